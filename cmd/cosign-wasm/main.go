@@ -0,0 +1,160 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command cosign-wasm verifies cosign signatures with pkg/verify in WASM
+// sandboxes. Built for GOOS=js, it registers a global cosignVerify function
+// taking and returning JSON; built for GOOS=wasip1, or natively, it reads the
+// request from stdin and writes the result to stdout.
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/verify"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// request is a signature to verify and what to trust, with PEM keys and
+// certificates.
+type request struct {
+	// Payload is the signed payload, or the DSSE envelope of an attestation,
+	// base64 encoded in JSON.
+	Payload     []byte              `json:"payload"`
+	Signature   string              `json:"signature,omitempty"`
+	Certificate string              `json:"certificate,omitempty"`
+	Chain       string              `json:"chain,omitempty"`
+	Bundle      *bundle.RekorBundle `json:"bundle,omitempty"`
+
+	PublicKey       string            `json:"publicKey,omitempty"`
+	Roots           string            `json:"roots,omitempty"`
+	Intermediates   string            `json:"intermediates,omitempty"`
+	Identities      []verify.Identity `json:"identities,omitempty"`
+	RekorPublicKeys string            `json:"rekorPublicKeys,omitempty"`
+	IgnoreTlog      bool              `json:"ignoreTlog,omitempty"`
+}
+
+type response struct {
+	Verified       bool       `json:"verified"`
+	Error          string     `json:"error,omitempty"`
+	Payload        []byte     `json:"payload,omitempty"`
+	Subjects       []string   `json:"subjects,omitempty"`
+	Issuer         string     `json:"issuer,omitempty"`
+	LogIndex       int64      `json:"logIndex,omitempty"`
+	IntegratedTime *time.Time `json:"integratedTime,omitempty"`
+}
+
+// handle verifies the JSON request raw, and returns the JSON response and
+// whether the signature verified.
+func handle(ctx context.Context, raw []byte) ([]byte, bool) {
+	resp := response{}
+	if res, err := verifyRequest(ctx, raw); err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Verified = true
+		resp.Payload = res.Payload
+		resp.Subjects = res.Subjects
+		resp.Issuer = res.Issuer
+		if !res.IntegratedTime.IsZero() {
+			resp.LogIndex = res.LogIndex
+			resp.IntegratedTime = &res.IntegratedTime
+		}
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		// Only the strings of resp could fail to marshal.
+		return []byte(`{"verified":false,"error":"marshaling the response"}`), false
+	}
+	return b, resp.Verified
+}
+
+func verifyRequest(ctx context.Context, raw []byte) (*verify.Result, error) {
+	r := request{}
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, fmt.Errorf("parsing request: %w", err)
+	}
+	o := verify.Options{Identities: r.Identities, IgnoreTlog: r.IgnoreTlog}
+	var err error
+	if r.PublicKey != "" {
+		if o.PublicKey, err = cryptoutils.UnmarshalPEMToPublicKey([]byte(r.PublicKey)); err != nil {
+			return nil, fmt.Errorf("parsing publicKey: %w", err)
+		}
+	}
+	if o.Roots, err = certPool(r.Roots); err != nil {
+		return nil, fmt.Errorf("parsing roots: %w", err)
+	}
+	if o.Intermediates, err = certPool(r.Intermediates); err != nil {
+		return nil, fmt.Errorf("parsing intermediates: %w", err)
+	}
+	if o.RekorPubKeys, err = rekorPubKeys(r.RekorPublicKeys); err != nil {
+		return nil, fmt.Errorf("parsing rekorPublicKeys: %w", err)
+	}
+	return verify.Verify(ctx, verify.Signature{
+		Payload:         r.Payload,
+		Base64Signature: r.Signature,
+		Certificate:     []byte(r.Certificate),
+		Chain:           []byte(r.Chain),
+		Bundle:          r.Bundle,
+	}, o)
+}
+
+// certPool returns the pool of the PEM certificates, or nil if there are
+// none.
+func certPool(certs string) (*x509.CertPool, error) {
+	if certs == "" {
+		return nil, nil
+	}
+	parsed, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(certs))
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, c := range parsed {
+		pool.AddCert(c)
+	}
+	return pool, nil
+}
+
+// rekorPubKeys returns the PEM ECDSA public keys by log ID.
+func rekorPubKeys(keys string) (map[string]*ecdsa.PublicKey, error) {
+	pubs := map[string]*ecdsa.PublicKey{}
+	for rest := []byte(keys); ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		ecPub, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, errors.New("rekor public keys must be ECDSA keys")
+		}
+		id, err := verify.LogID(ecPub)
+		if err != nil {
+			return nil, err
+		}
+		pubs[id] = ecPub
+	}
+	return pubs, nil
+}
@@ -0,0 +1,35 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+
+package main
+
+import (
+	"context"
+	"syscall/js"
+)
+
+func main() {
+	js.Global().Set("cosignVerify", js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+		if len(args) != 1 || args[0].Type() != js.TypeString {
+			return `{"verified":false,"error":"cosignVerify takes the JSON request"}`
+		}
+		out, _ := handle(context.Background(), []byte(args[0].String()))
+		return string(out)
+	}))
+	// Keep the exported function around for the page or runtime.
+	select {}
+}
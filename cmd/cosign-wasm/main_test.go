@@ -0,0 +1,81 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestHandle(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte("payload")
+	raw, err := sv.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := func(sig string, ignoreTlog bool) []byte {
+		b, err := json.Marshal(request{Payload: payload, Signature: sig, PublicKey: string(pub), IgnoreTlog: ignoreTlog})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	out, verified := handle(context.Background(), req(base64.StdEncoding.EncodeToString(raw), true))
+	resp := response{}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("handle() = %s: %v", out, err)
+	}
+	if !verified || !resp.Verified || !bytes.Equal(resp.Payload, payload) {
+		t.Errorf("handle() = %s, want the verified payload", out)
+	}
+
+	for name, raw := range map[string][]byte{
+		"another signature": req(base64.StdEncoding.EncodeToString([]byte("signature")), true),
+		"no bundle":         req(base64.StdEncoding.EncodeToString(raw), false),
+		"not json":          []byte("signature"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, verified := handle(context.Background(), raw)
+			if verified || !strings.Contains(string(out), `"verified":false,"error":`) {
+				t.Errorf("handle() = %s, want an error", out)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 
+	ocitypes "github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attach"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/spf13/cobra"
@@ -43,12 +44,18 @@ func attachSignature() *cobra.Command {
 	o := &options.AttachSignatureOptions{}
 
 	cmd := &cobra.Command{
-		Use:              "signature",
-		Short:            "Attach signatures to the supplied container image",
-		Example:          "  cosign attach signature <image uri>",
+		Use:   "signature",
+		Short: "Attach signatures to the supplied container image",
+		Example: `  cosign attach signature <image uri>
+
+  # attach a signature to an image in an OCI layout, storing it in the layout
+  cosign attach signature --signature <signature file path> --local-image <path>`,
 		PersistentPreRun: options.BindViper,
 		Args:             cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.LocalImage.Enabled {
+				return attach.LocalSignatureCmd(cmd.Context(), o.LocalImage, o.Signature, o.Payload, o.Cert, o.CertChain, args[0])
+			}
 			return attach.SignatureCmd(cmd.Context(), o.Registry, o.Signature, o.Payload, o.Cert, o.CertChain, args[0])
 		},
 	}
@@ -62,17 +69,42 @@ func attachSBOM() *cobra.Command {
 	o := &options.AttachSBOMOptions{}
 
 	cmd := &cobra.Command{
-		Use:              "sbom",
-		Short:            "Attach sbom to the supplied container image",
-		Example:          "  cosign attach sbom <image uri>",
+		Use:   "sbom",
+		Short: "Attach sbom to the supplied container image",
+		Long: `Attach sbom to the supplied container image.
+
+The format of the SBOM (SPDX JSON or tag-value, CycloneDX JSON or XML, or syft
+JSON) is detected from its content and determines its media type. --type and
+--input-format are only used for SBOMs of no known format; if they disagree
+with the content, a warning is printed and the detected media type is used.
+The format version and the tools that generated the SBOM are recorded in the
+dev.sigstore.cosign/sbom-format-version and dev.sigstore.cosign/sbom-tool
+annotations.
+
+Attached SBOMs are not signed and are deprecated in favor of SBOM attestations
+made with 'cosign attest --type spdxjson|cyclonedx'. SBOMs already attached are
+converted to attestations with 'cosign convert sbom-to-attestation'.`,
+		Example: `  # attach an SBOM, detecting its format
+  cosign attach sbom --sbom sbom.cdx.json <image uri>
+
+  # attach an SBOM of no known format as SPDX tag-value
+  cosign attach sbom --sbom sbom.txt --type spdx --input-format text <image uri>`,
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mediaType, err := o.MediaType()
-			if err != nil {
+			if err := o.RegistryExperimental.CheckFeatureGates(); err != nil {
 				return err
 			}
-			fmt.Fprintf(os.Stderr, "WARNING: Attaching SBOMs this way does not sign them. If you want to sign them, use 'cosign attest --predicate %s --key <key path>' or 'cosign sign --key <key path> --attachment sbom <image uri>'.\n", o.SBOM)
+			var mediaType ocitypes.MediaType
+			if cmd.Flags().Changed("type") || cmd.Flags().Changed("input-format") {
+				mt, err := o.MediaType()
+				if err != nil {
+					return err
+				}
+				mediaType = mt
+			}
+			fmt.Fprintf(os.Stderr, "WARNING: Attaching SBOMs this way does not sign them and is deprecated. Use 'cosign attest --predicate %s --type <spdxjson|cyclonedx> --key <key path> <image uri>' instead, "+
+				"and 'cosign convert sbom-to-attestation --key <key path> <image uri>' to convert SBOMs already attached.\n", o.SBOM)
 			return attach.SBOMCmd(cmd.Context(), o.Registry, o.RegistryExperimental, o.SBOM, mediaType, args[0])
 		},
 	}
@@ -96,11 +128,17 @@ func attachAttestation() *cobra.Command {
   # attach attestation from bundle files in form of JSONLines to a container image
   # https://github.com/in-toto/attestation/blob/main/spec/v1.0-draft/bundle.md
   cosign attach attestation --attestation <attestation bundle file path> <image uri>
+
+  # attach an attestation to an image in an OCI layout, storing it in the layout
+  cosign attach attestation --attestation <attestation file path> --local-image <path>
 `,
 
 		Args:             cobra.MinimumNArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.LocalImage.Enabled {
+				return attach.LocalAttestationCmd(cmd.Context(), o.LocalImage, o.Attestations, args[0])
+			}
 			return attach.AttestationCmd(cmd.Context(), o.Registry, o.Attestations, args[0])
 		},
 	}
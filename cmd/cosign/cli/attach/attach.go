@@ -24,6 +24,7 @@ import (
 	ssldsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
@@ -46,39 +47,18 @@ func AttestationCmd(ctx context.Context, regOpts options.RegistryOptions, signed
 }
 
 func attachAttestation(ctx context.Context, remoteOpts []ociremote.Option, signedPayload, imageRef string, nameOpts []name.Option) error {
-	fmt.Fprintf(os.Stderr, "Using payload from: %s", signedPayload)
-	attestationFile, err := os.Open(signedPayload)
+	atts, err := readAttestations(signedPayload)
 	if err != nil {
 		return err
 	}
 
-	env := ssldsse.Envelope{}
-	decoder := json.NewDecoder(attestationFile)
-	for decoder.More() {
-		if err := decoder.Decode(&env); err != nil {
-			return err
-		}
-
-		payload, err := json.Marshal(env)
-		if err != nil {
-			return err
-		}
-
-		if env.PayloadType != types.IntotoPayloadType {
-			return fmt.Errorf("invalid payloadType %s on envelope. Expected %s", env.PayloadType, types.IntotoPayloadType)
-		}
-
-		if len(env.Signatures) == 0 {
-			return fmt.Errorf("could not attach attestation without having signatures")
-		}
-
-		ref, err := name.ParseReference(imageRef, nameOpts...)
+	for _, att := range atts {
+		ref, err := oci.ParseReference(imageRef, nameOpts...)
 		if err != nil {
 			return err
 		}
 		if _, ok := ref.(name.Digest); !ok {
-			msg := fmt.Sprintf(ui.TagReferenceMessage, imageRef)
-			ui.Warnf(ctx, msg)
+			ui.Warnf(ctx, ui.TagReferenceMessage, imageRef)
 		}
 		digest, err := ociremote.ResolveDigest(ref, remoteOpts...)
 		if err != nil {
@@ -89,12 +69,6 @@ func attachAttestation(ctx context.Context, remoteOpts []ociremote.Option, signe
 		// each access.
 		ref = digest // nolint
 
-		opts := []static.Option{static.WithLayerMediaType(types.DssePayloadType)}
-		att, err := static.NewAttestation(payload, opts...)
-		if err != nil {
-			return err
-		}
-
 		se, err := ociremote.SignedEntity(digest, remoteOpts...)
 		if err != nil {
 			return err
@@ -113,3 +87,44 @@ func attachAttestation(ctx context.Context, remoteOpts []ociremote.Option, signe
 	}
 	return nil
 }
+
+// readAttestations returns the attestations of the DSSE envelopes in the
+// file signedPayload.
+func readAttestations(signedPayload string) ([]oci.Signature, error) {
+	fmt.Fprintf(os.Stderr, "Using payload from: %s", signedPayload)
+	attestationFile, err := os.Open(signedPayload)
+	if err != nil {
+		return nil, err
+	}
+	defer attestationFile.Close()
+
+	var atts []oci.Signature
+	env := ssldsse.Envelope{}
+	decoder := json.NewDecoder(attestationFile)
+	for decoder.More() {
+		if err := decoder.Decode(&env); err != nil {
+			return nil, err
+		}
+
+		payload, err := json.Marshal(env)
+		if err != nil {
+			return nil, err
+		}
+
+		if env.PayloadType != types.IntotoPayloadType {
+			return nil, fmt.Errorf("invalid payloadType %s on envelope. Expected %s", env.PayloadType, types.IntotoPayloadType)
+		}
+
+		if len(env.Signatures) == 0 {
+			return nil, fmt.Errorf("could not attach attestation without having signatures")
+		}
+
+		opts := []static.Option{static.WithLayerMediaType(types.DssePayloadType)}
+		att, err := static.NewAttestation(payload, opts...)
+		if err != nil {
+			return nil, err
+		}
+		atts = append(atts, att)
+	}
+	return atts, nil
+}
@@ -0,0 +1,94 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci/layout"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+)
+
+// LocalSignatureCmd attaches a signature to the image on disk at path, an OCI
+// layout or a docker tarball, storing it in the OCI layout.
+func LocalSignatureCmd(ctx context.Context, o options.LocalImageOptions, sigRef, payloadRef, certRef, certChainRef, path string) error {
+	b64SigBytes, err := signatureBytes(sigRef)
+	if err != nil {
+		return err
+	} else if len(b64SigBytes) == 0 {
+		return errors.New("empty signature")
+	}
+
+	l, dst, err := loadLocal(o, path)
+	if err != nil {
+		return err
+	}
+	digest, err := l.Reference()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	newSig, err := attachedSignature(ctx, digest, b64SigBytes, payloadRef, certRef, certChainRef)
+	if err != nil {
+		return err
+	}
+
+	newSE, err := mutate.AttachSignatureToEntity(l.Entity, newSig)
+	if err != nil {
+		return err
+	}
+	ui.Infof(ctx, "Writing signature to the OCI layout %s", dst)
+	return l.Write(dst, newSE)
+}
+
+// LocalAttestationCmd attaches the attestations in the files signedPayloads
+// to the image on disk at path, storing them in the OCI layout.
+func LocalAttestationCmd(ctx context.Context, o options.LocalImageOptions, signedPayloads []string, path string) error {
+	l, dst, err := loadLocal(o, path)
+	if err != nil {
+		return err
+	}
+
+	se := l.Entity
+	for _, payload := range signedPayloads {
+		atts, err := readAttestations(payload)
+		if err != nil {
+			return fmt.Errorf("attaching payload from %s: %w", payload, err)
+		}
+		for _, att := range atts {
+			if se, err = mutate.AttachAttestationToEntity(se, att); err != nil {
+				return err
+			}
+		}
+	}
+	ui.Infof(ctx, "Writing attestations to the OCI layout %s", dst)
+	return l.Write(dst, se)
+}
+
+func loadLocal(o options.LocalImageOptions, path string) (*layout.Local, string, error) {
+	l, err := layout.LoadLocal(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("loading local image %s: %w", path, err)
+	}
+	dst, err := o.Destination(path, l.Tarball)
+	if err != nil {
+		return nil, "", err
+	}
+	return l, dst, nil
+}
@@ -36,21 +36,35 @@ import (
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	ociexperimental "github.com/sigstore/cosign/v2/internal/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	ctypes "github.com/sigstore/cosign/v2/pkg/types"
 )
 
+// SBOMCmd attaches the SBOM at sbomRef to imageRef. The media type is
+// detected from the content of the SBOM; sbomType is used if the SBOM is of
+// no known format, and a mismatch with the detected one is reported.
+// sbomType may be empty to rely on detection alone.
 func SBOMCmd(ctx context.Context, regOpts options.RegistryOptions, regExpOpts options.RegistryExperimentalOptions, sbomRef string, sbomType ocitypes.MediaType, imageRef string) error {
-	if regExpOpts.RegistryReferrersMode == options.RegistryReferrersModeOCI11 {
-		return sbomCmdOCIExperimental(ctx, regOpts, sbomRef, sbomType, imageRef)
-	}
-
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+	// The SBOM is read once, as it may come from standard input, for both
+	// the referrer and the tag.
+	b, err := sbomBytes(sbomRef)
 	if err != nil {
 		return err
 	}
+	sbomType, annotations := sbomMediaType(ctx, b, sbomType)
 
-	b, err := sbomBytes(sbomRef)
+	if mode := regExpOpts.RegistryReferrersMode; mode.Referrers() {
+		if err := sbomCmdOCIExperimental(ctx, regOpts, b, sbomType, annotations, imageRef); err != nil {
+			return err
+		}
+		if !mode.Tags() {
+			return nil
+		}
+	}
+
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
 	if err != nil {
 		return err
 	}
@@ -66,16 +80,16 @@ func SBOMCmd(ctx context.Context, regOpts options.RegistryOptions, regExpOpts op
 	}
 
 	ui.Infof(ctx, "Uploading SBOM file for [%s] to [%s] with mediaType [%s].\n", ref.Name(), dstRef.Name(), sbomType)
-	img, err := static.NewFile(b, static.WithLayerMediaType(sbomType))
+	img, err := static.NewFile(b, static.WithLayerMediaType(sbomType), static.WithAnnotations(annotations))
 	if err != nil {
 		return err
 	}
-	return remote.Write(dstRef, img, regOpts.GetRegistryClientOpts(ctx)...)
+	return ociremote.WriteImage(dstRef, img, regOpts.GetRegistryClientOpts(ctx)...)
 }
 
-func sbomCmdOCIExperimental(ctx context.Context, regOpts options.RegistryOptions, sbomRef string, sbomType ocitypes.MediaType, imageRef string) error {
+func sbomCmdOCIExperimental(ctx context.Context, regOpts options.RegistryOptions, b []byte, sbomType ocitypes.MediaType, annotations map[string]string, imageRef string) error {
 	var dig name.Digest
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
 	if err != nil {
 		return err
 	}
@@ -94,7 +108,7 @@ func sbomCmdOCIExperimental(ctx context.Context, regOpts options.RegistryOptions
 	desc, err := remote.Head(dig, regOpts.GetRegistryClientOpts(ctx)...)
 	var terr *transport.Error
 	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
-		h, err := v1.NewHash(dig.DigestStr())
+		h, err := oci.ParseDigest(dig.DigestStr())
 		if err != nil {
 			return err
 		}
@@ -110,11 +124,6 @@ func sbomCmdOCIExperimental(ctx context.Context, regOpts options.RegistryOptions
 		return err
 	}
 
-	b, err := sbomBytes(sbomRef)
-	if err != nil {
-		return err
-	}
-
 	empty := mutate.MediaType(
 		mutate.ConfigMediaType(empty.Image, ocitypes.MediaType(artifactType)),
 		ocitypes.OCIManifestSchema1)
@@ -123,6 +132,7 @@ func sbomCmdOCIExperimental(ctx context.Context, regOpts options.RegistryOptions
 		return err
 	}
 	att = mutate.Subject(att, *desc).(v1.Image)
+	att = mutate.Annotations(att, annotations).(v1.Image)
 	attdig, err := att.Digest()
 	if err != nil {
 		return err
@@ -131,7 +141,7 @@ func sbomCmdOCIExperimental(ctx context.Context, regOpts options.RegistryOptions
 
 	fmt.Fprintf(os.Stderr, "Uploading SBOM file for [%s] to [%s] with config.mediaType [%s] layers[0].mediaType [%s].\n",
 		ref.Name(), dstRef.String(), artifactType, sbomType)
-	return remote.Write(dstRef, att, regOpts.GetRegistryClientOpts(ctx)...)
+	return ociremote.WriteImage(dstRef, att, regOpts.GetRegistryClientOpts(ctx)...)
 }
 
 func sbomBytes(sbomRef string) ([]byte, error) {
@@ -147,3 +157,22 @@ func sbomBytes(sbomRef string) ([]byte, error) {
 		return nil, errors.New("unknown SBOM arg type")
 	}
 }
+
+// sbomMediaType returns the media type and annotations of the SBOM b. The
+// detected media type wins over the declared one, since consumers pick their
+// parser by media type and break on mislabeled SBOMs.
+func sbomMediaType(ctx context.Context, b []byte, declared ocitypes.MediaType) (ocitypes.MediaType, map[string]string) {
+	f := detectSBOMFormat(b)
+	if f == nil {
+		if declared == "" {
+			ui.Warnf(ctx, "SBOM is of no known format, uploading it with mediaType [%s]; set --type to override", ctypes.SPDXMediaType)
+			return ctypes.SPDXMediaType, nil
+		}
+		return declared, nil
+	}
+	detected := f.MediaType()
+	if declared != "" && declared != detected {
+		ui.Warnf(ctx, "SBOM was declared as [%s] but its content is %s %s, uploading it with mediaType [%s]", declared, f.Type, f.InputFormat, detected)
+	}
+	return detected, f.Annotations()
+}
@@ -0,0 +1,234 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attach
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+
+	ocitypes "github.com/google/go-containerregistry/pkg/v1/types"
+	ctypes "github.com/sigstore/cosign/v2/pkg/types"
+)
+
+const (
+	// SBOMFormatVersionAnnotationKey is the annotation recording the version
+	// of the SBOM format, e.g. SPDX-2.3 or 1.5 for CycloneDX.
+	SBOMFormatVersionAnnotationKey = "dev.sigstore.cosign/sbom-format-version"
+	// SBOMToolAnnotationKey is the annotation recording the tools that
+	// generated the SBOM.
+	SBOMToolAnnotationKey = "dev.sigstore.cosign/sbom-tool"
+
+	cyclonedxXMLNamespace = "http://cyclonedx.org/schema/bom/"
+)
+
+// sbomFormat is the format of an SBOM as detected from its content.
+type sbomFormat struct {
+	// Type is spdx, cyclonedx or syft.
+	Type string
+	// InputFormat is json, xml or text.
+	InputFormat string
+	Version     string
+	Tool        string
+}
+
+// MediaType returns the media type of SBOMs in format f.
+func (f *sbomFormat) MediaType() ocitypes.MediaType {
+	switch {
+	case f.Type == "spdx" && f.InputFormat == ctypes.JSONInputFormat:
+		return ctypes.SPDXJSONMediaType
+	case f.Type == "spdx":
+		return ctypes.SPDXMediaType
+	case f.Type == "cyclonedx" && f.InputFormat == ctypes.XMLInputFormat:
+		return ctypes.CycloneDXXMLMediaType
+	case f.Type == "cyclonedx":
+		return ctypes.CycloneDXJSONMediaType
+	default:
+		return ctypes.SyftMediaType
+	}
+}
+
+// Annotations returns the annotations recording the format version and
+// tools of f.
+func (f *sbomFormat) Annotations() map[string]string {
+	ann := map[string]string{}
+	if f.Version != "" {
+		ann[SBOMFormatVersionAnnotationKey] = f.Version
+	}
+	if f.Tool != "" {
+		ann[SBOMToolAnnotationKey] = f.Tool
+	}
+	return ann
+}
+
+// detectSBOMFormat detects SPDX (JSON and tag-value), CycloneDX (JSON and
+// XML) and syft JSON SBOMs. It returns nil if b is none of them.
+func detectSBOMFormat(b []byte) *sbomFormat {
+	trimmed := bytes.TrimSpace(b)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return detectJSONSBOM(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return detectXMLSBOM(trimmed)
+	default:
+		return detectSPDXTagValue(trimmed)
+	}
+}
+
+type sbomTool struct {
+	Vendor  string `json:"vendor" xml:"vendor"`
+	Name    string `json:"name" xml:"name"`
+	Version string `json:"version" xml:"version"`
+}
+
+func toolNames(tools []sbomTool) string {
+	names := make([]string, 0, len(tools))
+	for _, t := range tools {
+		if t.Name == "" {
+			continue
+		}
+		if t.Version != "" {
+			names = append(names, t.Name+"-"+t.Version)
+		} else {
+			names = append(names, t.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func detectJSONSBOM(b []byte) *sbomFormat {
+	var doc struct {
+		// SPDX
+		SPDXVersion  string `json:"spdxVersion"`
+		CreationInfo struct {
+			Creators []string `json:"creators"`
+		} `json:"creationInfo"`
+		// CycloneDX
+		BOMFormat   string `json:"bomFormat"`
+		SpecVersion string `json:"specVersion"`
+		Metadata    struct {
+			Tools json.RawMessage `json:"tools"`
+		} `json:"metadata"`
+		// syft
+		Schema struct {
+			Version string `json:"version"`
+			URL     string `json:"url"`
+		} `json:"schema"`
+		Descriptor sbomTool `json:"descriptor"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(doc.SPDXVersion, "SPDX-"):
+		return &sbomFormat{
+			Type:        "spdx",
+			InputFormat: ctypes.JSONInputFormat,
+			Version:     doc.SPDXVersion,
+			Tool:        spdxTools(doc.CreationInfo.Creators),
+		}
+	case doc.BOMFormat == "CycloneDX":
+		// CycloneDX 1.5 replaced the list of tools with an object listing
+		// components and services.
+		var tools []sbomTool
+		if err := json.Unmarshal(doc.Metadata.Tools, &tools); err != nil {
+			var v15 struct {
+				Components []sbomTool `json:"components"`
+				Services   []sbomTool `json:"services"`
+			}
+			if err := json.Unmarshal(doc.Metadata.Tools, &v15); err == nil {
+				tools = append(v15.Components, v15.Services...)
+			}
+		}
+		return &sbomFormat{
+			Type:        "cyclonedx",
+			InputFormat: ctypes.JSONInputFormat,
+			Version:     doc.SpecVersion,
+			Tool:        toolNames(tools),
+		}
+	case strings.Contains(doc.Schema.URL, "anchore/syft") || doc.Descriptor.Name == "syft":
+		return &sbomFormat{
+			Type:        "syft",
+			InputFormat: ctypes.JSONInputFormat,
+			Version:     doc.Schema.Version,
+			Tool:        toolNames([]sbomTool{doc.Descriptor}),
+		}
+	default:
+		return nil
+	}
+}
+
+func detectXMLSBOM(b []byte) *sbomFormat {
+	var doc struct {
+		XMLName    xml.Name
+		Tools      []sbomTool `xml:"metadata>tools>tool"`
+		Components []sbomTool `xml:"metadata>tools>components>component"`
+	}
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil
+	}
+	if doc.XMLName.Local != "bom" || !strings.HasPrefix(doc.XMLName.Space, cyclonedxXMLNamespace) {
+		return nil
+	}
+	return &sbomFormat{
+		Type:        "cyclonedx",
+		InputFormat: ctypes.XMLInputFormat,
+		Version:     strings.TrimPrefix(doc.XMLName.Space, cyclonedxXMLNamespace),
+		Tool:        toolNames(append(doc.Tools, doc.Components...)),
+	}
+}
+
+func detectSPDXTagValue(b []byte) *sbomFormat {
+	var f *sbomFormat
+	var creators []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(nil, len(b)+1)
+	for scanner.Scan() {
+		tag, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(tag) {
+		case "SPDXVersion":
+			if f == nil && strings.HasPrefix(value, "SPDX-") {
+				f = &sbomFormat{Type: "spdx", InputFormat: ctypes.TextInputFormat, Version: value}
+			}
+		case "Creator":
+			creators = append(creators, value)
+		}
+	}
+	if f == nil {
+		return nil
+	}
+	f.Tool = spdxTools(creators)
+	return f
+}
+
+// spdxTools returns the tools among SPDX creators, which are of the form
+// "Tool: syft-0.84.0".
+func spdxTools(creators []string) string {
+	var tools []string
+	for _, c := range creators {
+		if strings.HasPrefix(c, "Tool:") {
+			tools = append(tools, strings.TrimSpace(strings.TrimPrefix(c, "Tool:")))
+		}
+	}
+	return strings.Join(tools, ", ")
+}
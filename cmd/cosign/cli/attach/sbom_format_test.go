@@ -0,0 +1,118 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attach
+
+import (
+	"context"
+	"testing"
+
+	ocitypes "github.com/google/go-containerregistry/pkg/v1/types"
+	ctypes "github.com/sigstore/cosign/v2/pkg/types"
+)
+
+func TestDetectSBOMFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		sbom string
+		want *sbomFormat
+	}{{
+		name: "spdx json",
+		sbom: `{"spdxVersion": "SPDX-2.3", "creationInfo": {"creators": ["Organization: Anchore, Inc", "Tool: syft-0.84.0"]}}`,
+		want: &sbomFormat{Type: "spdx", InputFormat: ctypes.JSONInputFormat, Version: "SPDX-2.3", Tool: "syft-0.84.0"},
+	}, {
+		name: "spdx tag-value",
+		sbom: "SPDXVersion: SPDX-2.2\nDataLicense: CC0-1.0\nCreator: Tool: bom-v0.2.1\n",
+		want: &sbomFormat{Type: "spdx", InputFormat: ctypes.TextInputFormat, Version: "SPDX-2.2", Tool: "bom-v0.2.1"},
+	}, {
+		name: "cyclonedx 1.4 json",
+		sbom: `{"bomFormat": "CycloneDX", "specVersion": "1.4", "metadata": {"tools": [{"vendor": "anchore", "name": "syft", "version": "0.84.0"}]}}`,
+		want: &sbomFormat{Type: "cyclonedx", InputFormat: ctypes.JSONInputFormat, Version: "1.4", Tool: "syft-0.84.0"},
+	}, {
+		name: "cyclonedx 1.5 json",
+		sbom: `{"bomFormat": "CycloneDX", "specVersion": "1.5", "metadata": {"tools": {"components": [{"name": "trivy", "version": "0.45.0"}]}}}`,
+		want: &sbomFormat{Type: "cyclonedx", InputFormat: ctypes.JSONInputFormat, Version: "1.5", Tool: "trivy-0.45.0"},
+	}, {
+		name: "cyclonedx xml",
+		sbom: `<?xml version="1.0"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <metadata><tools><tool><vendor>anchore</vendor><name>syft</name><version>0.84.0</version></tool></tools></metadata>
+</bom>`,
+		want: &sbomFormat{Type: "cyclonedx", InputFormat: ctypes.XMLInputFormat, Version: "1.4", Tool: "syft-0.84.0"},
+	}, {
+		name: "syft json",
+		sbom: `{"artifacts": [], "descriptor": {"name": "syft", "version": "0.84.0"}, "schema": {"version": "9.0.0", "url": "https://raw.githubusercontent.com/anchore/syft/main/schema/json/schema-9.0.0.json"}}`,
+		want: &sbomFormat{Type: "syft", InputFormat: ctypes.JSONInputFormat, Version: "9.0.0", Tool: "syft-0.84.0"},
+	}, {
+		name: "unknown json",
+		sbom: `{"foo": "bar"}`,
+	}, {
+		name: "unknown xml",
+		sbom: `<bom xmlns="http://example.com/bom"/>`,
+	}, {
+		name: "unknown text",
+		sbom: "hello world",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectSBOMFormat([]byte(tt.sbom))
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("detectSBOMFormat() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("detectSBOMFormat() = nil")
+			}
+			if *got != *tt.want {
+				t.Errorf("detectSBOMFormat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSBOMMediaType(t *testing.T) {
+	ctx := context.Background()
+	cdx := []byte(`{"bomFormat": "CycloneDX", "specVersion": "1.4"}`)
+
+	tests := []struct {
+		name     string
+		sbom     []byte
+		declared ocitypes.MediaType
+		want     ocitypes.MediaType
+	}{
+		{"detected", cdx, "", ctypes.CycloneDXJSONMediaType},
+		{"corrected", cdx, ctypes.SPDXMediaType, ctypes.CycloneDXJSONMediaType},
+		{"unknown declared", []byte("hello"), ctypes.SyftMediaType, ctypes.SyftMediaType},
+		{"unknown", []byte("hello"), "", ctypes.SPDXMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := sbomMediaType(ctx, tt.sbom, tt.declared)
+			if got != tt.want {
+				t.Errorf("sbomMediaType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	_, ann := sbomMediaType(ctx, cdx, "")
+	if ann[SBOMFormatVersionAnnotationKey] != "1.4" {
+		t.Errorf("annotations = %v, want format version 1.4", ann)
+	}
+	if _, ok := ann[SBOMToolAnnotationKey]; ok {
+		t.Errorf("annotations = %v, want no tool", ann)
+	}
+}
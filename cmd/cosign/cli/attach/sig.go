@@ -26,6 +26,7 @@ import (
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
@@ -39,7 +40,7 @@ func SignatureCmd(ctx context.Context, regOpts options.RegistryOptions, sigRef,
 		return errors.New("empty signature")
 	}
 
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
 	if err != nil {
 		return err
 	}
@@ -56,6 +57,31 @@ func SignatureCmd(ctx context.Context, regOpts options.RegistryOptions, sigRef,
 	// each access.
 	ref = digest // nolint
 
+	newSig, err := attachedSignature(ctx, digest, b64SigBytes, payloadRef, certRef, certChainRef)
+	if err != nil {
+		return err
+	}
+
+	se, err := ociremote.SignedEntity(digest, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+
+	// Attach the signature to the entity.
+	newSE, err := mutate.AttachSignatureToEntity(se, newSig)
+	if err != nil {
+		return err
+	}
+
+	// Publish the signatures associated with this entity
+	return ociremote.WriteSignatures(digest.Repository, newSE, ociremoteOpts...)
+}
+
+// attachedSignature returns the signature b64SigBytes of the payload at
+// payloadRef, or of the payload generated for digest if empty, with the
+// certificate and chain at certRef and certChainRef, if any.
+func attachedSignature(ctx context.Context, digest name.Digest, b64SigBytes []byte, payloadRef, certRef, certChainRef string) (oci.Signature, error) {
+	var err error
 	var payload []byte
 	if payloadRef == "" {
 		payload, err = cosign.ObsoletePayload(ctx, digest)
@@ -63,12 +89,12 @@ func SignatureCmd(ctx context.Context, regOpts options.RegistryOptions, sigRef,
 		payload, err = os.ReadFile(filepath.Clean(payloadRef))
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	sig, err := static.NewSignature(payload, string(b64SigBytes))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var cert []byte
@@ -77,35 +103,18 @@ func SignatureCmd(ctx context.Context, regOpts options.RegistryOptions, sigRef,
 	if certRef != "" {
 		cert, err = os.ReadFile(filepath.Clean(certRef))
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if certChainRef != "" {
 		certChain, err = os.ReadFile(filepath.Clean(certChainRef))
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	newSig, err := mutate.Signature(sig, mutate.WithCertChain(cert, certChain))
-	if err != nil {
-		return err
-	}
-
-	se, err := ociremote.SignedEntity(digest, ociremoteOpts...)
-	if err != nil {
-		return err
-	}
-
-	// Attach the signature to the entity.
-	newSE, err := mutate.AttachSignatureToEntity(se, newSig)
-	if err != nil {
-		return err
-	}
-
-	// Publish the signatures associated with this entity
-	return ociremote.WriteSignatures(digest.Repository, newSE, ociremoteOpts...)
+	return mutate.Signature(sig, mutate.WithCertChain(cert, certChain))
 }
 
 type SignatureArgType uint8
@@ -23,6 +23,7 @@ import (
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
 )
 
 func Attest() *cobra.Command {
@@ -54,12 +55,43 @@ func Attest() *cobra.Command {
   # attach an attestation to a container image with a local key pair file, including a certificate and certificate chain
   cosign attest --predicate <FILE> --type <TYPE> --key cosign.key --cert cosign.crt --cert-chain chain.crt <IMAGE>
 
+  # generate an SBOM of a container image with the cosign-sbom-syft plugin and attest it
+  cosign attest --sbom-from-image --sbom-generator syft --type cyclonedx --key cosign.key <IMAGE>
+
+  # attest the base image, layer commands and labels recorded in the config and history of a container image
+  cosign attest --build-metadata-from-image --key cosign.key <IMAGE>
+
+  # attest the SBOM of a multi-arch image and of each of its platform images, storing the envelope once
+  cosign attest --recursive --predicate sbom.spdx.json --type spdxjson --key cosign.key <IMAGE INDEX>
+
+  # attest an image in an OCI layout, storing the attestation in the layout
+  cosign attest --predicate <FILE> --type <TYPE> --key cosign.key --local-image <PATH>
+
+  # validate a custom predicate against the schema registered for its type before signing
+  cosign attest --predicate <FILE> --type https://example.com/build/v1 --predicate-schemas schemas.json --key cosign.key <IMAGE>
+
+  # validate a custom predicate against a JSON schema file
+  cosign attest --predicate <FILE> --type https://example.com/build/v1 --predicate-schema build.schema.json --key cosign.key <IMAGE>
+
+  # attest a predicate of a type registered by a plug-in in ~/.cosign/predicates, validated against its schema
+  cosign attest --predicate <FILE> --type build --key cosign.key <IMAGE>
+
+  # retry a failed transparency log upload or registry push up to 5 times; attesting the same statement again
+  # after it still fails resumes its publication from the journal, with the same envelope and log entry
+  cosign attest --publish-retries 5 --predicate <FILE> --type <TYPE> --key cosign.key <IMAGE>
+
+  # check the configuration without pushing the attestation or uploading it to the transparency log
+  cosign attest --dry-run --predicate <FILE> --type <TYPE> <IMAGE>
+
   # attach an attestation to a container image which does not fully support OCI media types
   COSIGN_DOCKER_MEDIA_TYPES=1 cosign attest --predicate <FILE> --type <TYPE> --key cosign.key legacy-registry.example.com/my/image`,
 
 		Args:             cobra.MinimumNArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.RegistryExperimental.CheckFeatureGates(); err != nil {
+				return err
+			}
 			oidcClientSecret, err := o.OIDC.ClientSecret()
 			if err != nil {
 				return err
@@ -72,26 +104,50 @@ func Attest() *cobra.Command {
 				FulcioURL:                o.Fulcio.URL,
 				IDToken:                  o.Fulcio.IdentityToken,
 				InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                     o.Fulcio.ACME,
 				RekorURL:                 o.Rekor.URL,
 				OIDCIssuer:               o.OIDC.Issuer,
 				OIDCClientID:             o.OIDC.ClientID,
 				OIDCClientSecret:         oidcClientSecret,
 				OIDCRedirectURL:          o.OIDC.RedirectURL,
 				OIDCProvider:             o.OIDC.Provider,
+				OIDCTokenFile:            o.OIDC.TokenFile,
+				OIDCAudience:             o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
 				SkipConfirmation:         o.SkipConfirmation,
 				TSAServerURL:             o.TSAServerURL,
+				SkipCertificate:          o.DryRun.Enabled && !o.DryRun.RequestCertificate,
 			}
 			attestCommand := attest.AttestCommand{
-				KeyOpts:         ko,
-				RegistryOptions: o.Registry,
-				CertPath:        o.Cert,
-				CertChainPath:   o.CertChain,
-				NoUpload:        o.NoUpload,
-				PredicatePath:   o.Predicate.Path,
-				PredicateType:   o.Predicate.Type,
-				Replace:         o.Replace,
-				Timeout:         ro.Timeout,
-				TlogUpload:      o.TlogUpload,
+				KeyOpts:          ko,
+				RegistryOptions:  o.Registry,
+				CertPath:         o.Cert,
+				CertChainPath:    o.CertChain,
+				NoUpload:         o.NoUpload,
+				PredicatePath:    o.Predicate.Path,
+				PredicateType:    o.Predicate.Type,
+				PredicateSchemas: o.Predicate.Schemas,
+				PredicateSchema:  o.Predicate.Schema,
+				Replace:          o.Replace,
+				Timeout:          ro.Timeout,
+				TlogUpload:       o.TlogUpload,
+				SBOMFromImage:    o.SBOMFromImage,
+				SBOMGenerator:    o.SBOMGenerator,
+				BuildFromImage:   o.BuildFromImage,
+				DryRun:           o.DryRun.Enabled,
+				LocalImage:       o.LocalImage,
+				Recursive:        o.Recursive,
+				JournalDir:       o.Journal,
+				PublishRetries:   o.PublishRetries,
+
+				RegistryReferrersMode: o.RegistryExperimental.RegistryReferrersMode,
+			}
+			if attestCommand.JournalDir == "" {
+				dir, err := attest.DefaultJournalDir()
+				if err != nil {
+					ui.Warnf(cmd.Context(), "Attestations aren't journaled: %v", err)
+				}
+				attestCommand.JournalDir = dir
 			}
 
 			for _, img := range args {
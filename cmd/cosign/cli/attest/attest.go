@@ -19,31 +19,41 @@ import (
 	"bytes"
 	"context"
 	_ "crypto/sha256" // for `crypto.SHA256`
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/in-toto/in-toto-golang/in_toto"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
 	tsaclient "github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa/client"
+	"github.com/sigstore/cosign/v2/internal/pkg/profile"
+	"github.com/sigstore/cosign/v2/internal/pkg/store"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
 	cbundle "github.com/sigstore/cosign/v2/pkg/cosign/bundle"
 	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/layout"
 	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/oci/walk"
 	"github.com/sigstore/cosign/v2/pkg/types"
 	"github.com/sigstore/rekor/pkg/generated/client"
 	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature/dsse"
 	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
 )
@@ -68,6 +78,13 @@ func uploadToTlog(ctx context.Context, sv *sign.SignerVerifier, rekorURL string,
 	return cbundle.EntryToBundle(entry), nil
 }
 
+// PredicateGenerator generates the predicate for the image at digest.
+type PredicateGenerator func(ctx context.Context, digest name.Digest) ([]byte, error)
+
+// StatementGenerator generates the whole in-toto statement for the image at
+// digest.
+type StatementGenerator func(ctx context.Context, digest name.Digest) (interface{}, error)
+
 // nolint
 type AttestCommand struct {
 	options.KeyOpts
@@ -77,10 +94,51 @@ type AttestCommand struct {
 	NoUpload      bool
 	PredicatePath string
 	PredicateType string
-	Replace       bool
-	Timeout       time.Duration
-	TlogUpload    bool
-	TSAServerURL  string
+	// PredicateSchema is the path of a JSON schema that predicates of
+	// PredicateType are validated against, in place of the registered one.
+	PredicateSchema string
+	// PredicateSchemas is the path or reference of a predicate schema
+	// registry that custom predicates are validated against before signing.
+	PredicateSchemas string
+	Replace          bool
+	Timeout          time.Duration
+	TlogUpload       bool
+	TSAServerURL     string
+	SBOMFromImage    bool
+	SBOMGenerator    string
+	// BuildFromImage generates a buildmetadata predicate from the config and
+	// history of the image.
+	BuildFromImage bool
+	// GeneratePredicate, if set, is used instead of reading PredicatePath.
+	GeneratePredicate PredicateGenerator
+	// GenerateStatement, if set, is used instead of generating the statement
+	// from the predicate. PredicateType then only annotates the attestation.
+	GenerateStatement StatementGenerator
+	// SignerVerifier, if set, signs the attestation instead of a signer
+	// created from KeyOpts, so that callers attesting many images create it
+	// only once.
+	SignerVerifier *sign.SignerVerifier
+	// DryRun prints the attestation that would be pushed and the
+	// transparency log entry that would be uploaded, instead of uploading
+	// them.
+	DryRun bool
+	// LocalImage attests the image on disk at the image reference, storing
+	// the attestation in its OCI layout.
+	LocalImage options.LocalImageOptions
+	// RegistryReferrersMode selects whether the attestations are written as
+	// OCI 1.1 referrers of the image, to the legacy attestation tag, or both.
+	RegistryReferrersMode options.RegistryReferrersMode
+	// Recursive attests each image of a multi-arch image too, with one
+	// statement whose subjects are all of them, so that the envelope is
+	// signed once and the registry stores it as a single blob.
+	Recursive bool
+	// JournalDir, if set, is the directory that the attestations whose
+	// publication doesn't complete are journaled in, so that attesting the
+	// same statement again resumes it with the same envelope.
+	JournalDir string
+	// PublishRetries is how many times a failed transparency log upload or
+	// registry push of the attestation is retried.
+	PublishRetries int
 }
 
 // nolint
@@ -90,19 +148,40 @@ func (c *AttestCommand) Exec(ctx context.Context, imageRef string) error {
 		return &options.KeyParseError{}
 	}
 
+	if c.SBOMFromImage {
+		if c.PredicatePath != "" {
+			return errors.New("only one of --predicate and --sbom-from-image may be provided")
+		}
+		if c.PredicateType == options.PredicateCustom {
+			c.PredicateType = options.PredicateSPDXJSON
+		}
+		c.GeneratePredicate = func(ctx context.Context, digest name.Digest) ([]byte, error) {
+			return generateSBOM(ctx, c.SBOMGenerator, c.PredicateType, digest)
+		}
+	}
+	if c.BuildFromImage {
+		if c.PredicatePath != "" || c.SBOMFromImage {
+			return errors.New("only one of --predicate, --sbom-from-image and --build-metadata-from-image may be provided")
+		}
+		if c.PredicateType == options.PredicateCustom {
+			c.PredicateType = options.PredicateBuild
+		}
+		if c.PredicateType != options.PredicateBuild {
+			return fmt.Errorf("--build-metadata-from-image requires --type %s, got %s", options.PredicateBuild, c.PredicateType)
+		}
+		c.GeneratePredicate = func(ctx context.Context, digest name.Digest) ([]byte, error) {
+			ociremoteOpts, err := c.RegistryOptions.ClientOpts(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return generateBuildMetadata(ctx, digest, ociremoteOpts...)
+		}
+	}
+
 	predicateURI, err := options.ParsePredicateType(c.PredicateType)
 	if err != nil {
 		return err
 	}
-	ref, err := name.ParseReference(imageRef, c.NameOptions()...)
-	if err != nil {
-		return fmt.Errorf("parsing reference: %w", err)
-	}
-	if _, ok := ref.(name.Digest); !ok {
-		msg := fmt.Sprintf(ui.TagReferenceMessage, imageRef)
-		ui.Warnf(ctx, msg)
-	}
-
 	if c.Timeout != 0 {
 		var cancelFn context.CancelFunc
 		ctx, cancelFn = context.WithTimeout(ctx, c.Timeout)
@@ -113,43 +192,80 @@ func (c *AttestCommand) Exec(ctx context.Context, imageRef string) error {
 	if err != nil {
 		return err
 	}
-	digest, err := ociremote.ResolveDigest(ref, ociremoteOpts...)
+	var digest name.Digest
+	var local *layout.Local
+	var dst string
+	if c.LocalImage.Enabled {
+		if c.SBOMFromImage || c.BuildFromImage {
+			return errors.New("--local-image can't be used with --sbom-from-image or --build-metadata-from-image")
+		}
+		if c.RegistryReferrersMode.Referrers() {
+			return errors.New("--local-image can't be used with --registry-referrers-mode")
+		}
+		if c.Recursive {
+			return errors.New("--local-image can't be used with --recursive")
+		}
+		if local, err = layout.LoadLocal(imageRef); err != nil {
+			return fmt.Errorf("loading local image %s: %w", imageRef, err)
+		}
+		if dst, err = c.LocalImage.Destination(imageRef, local.Tarball); err != nil {
+			return err
+		}
+		if digest, err = local.Reference(); err != nil {
+			return fmt.Errorf("%s: %w", imageRef, err)
+		}
+	} else {
+		ref, err := oci.ParseReference(imageRef, c.NameOptions()...)
+		if err != nil {
+			return fmt.Errorf("parsing reference: %w", err)
+		}
+		if err := profile.CheckImage(ref); err != nil {
+			return err
+		}
+		if _, ok := ref.(name.Digest); !ok {
+			ui.Warnf(ctx, ui.TagReferenceMessage, imageRef)
+		}
+		// Resolve "ref" to a digest to avoid a race where we use a tag
+		// multiple times, and it potentially points to different things at
+		// each access.
+		if digest, err = ociremote.ResolveDigest(ref, ociremoteOpts...); err != nil {
+			return err
+		}
+	}
+	schemas, err := cosign.LoadPredicateSchemas(c.PredicateSchemas, c.NameOptions(), ociremoteOpts...)
 	if err != nil {
 		return err
 	}
-	h, _ := v1.NewHash(digest.Identifier())
-	// Overwrite "ref" with a digest to avoid a race where we use a tag
-	// multiple times, and it potentially points to different things at
-	// each access.
-	ref = digest // nolint
-
-	sv, err := sign.SignerFromKeyOpts(ctx, c.CertPath, c.CertChainPath, c.KeyOpts)
+	if c.PredicateSchema != "" {
+		if schemas, err = cosign.AddPredicateSchema(schemas, predicateURI, c.PredicateSchema); err != nil {
+			return err
+		}
+	}
+	h, err := oci.ParseDigest(digest.Identifier())
 	if err != nil {
-		return fmt.Errorf("getting signer: %w", err)
+		return err
 	}
-	defer sv.Close()
-	wrapped := dsse.WrapSigner(sv, types.IntotoPayloadType)
-	dd := cremote.NewDupeDetector(sv)
-
-	var predicate io.ReadCloser
-	if c.PredicatePath == "-" {
-		fmt.Fprintln(os.Stderr, "Using payload from: standard input")
-		predicate = os.Stdin
-	} else {
-		fmt.Fprintln(os.Stderr, "Using payload from:", c.PredicatePath)
-		predicate, err = os.Open(c.PredicatePath)
-		if err != nil {
-			return err
+	var se oci.SignedEntity
+	if local != nil {
+		se = local.Entity
+	} else if se, err = ociremote.SignedEntity(digest, ociremoteOpts...); err != nil {
+		return err
+	}
+	entities, err := c.entities(ctx, se)
+	if err != nil {
+		return err
+	}
+	sv := c.SignerVerifier
+	if sv == nil {
+		if sv, err = sign.SignerFromKeyOpts(ctx, c.CertPath, c.CertChainPath, c.KeyOpts); err != nil {
+			return fmt.Errorf("getting signer: %w", err)
 		}
-		defer predicate.Close()
+		defer sv.Close()
 	}
+	wrapped := dsse.WrapSigner(sv, types.IntotoPayloadType)
+	dd := cremote.NewDupeDetector(sv)
 
-	sh, err := attestation.GenerateStatement(attestation.GenerateOpts{
-		Predicate: predicate,
-		Type:      c.PredicateType,
-		Digest:    h.Hex,
-		Repo:      digest.Repository.String(),
-	})
+	sh, err := c.statement(ctx, digest, h, predicateURI)
 	if err != nil {
 		return err
 	}
@@ -158,9 +274,73 @@ func (c *AttestCommand) Exec(ctx context.Context, imageRef string) error {
 	if err != nil {
 		return err
 	}
-	signedPayload, err := wrapped.SignMessage(bytes.NewReader(payload), signatureoptions.WithContext(ctx))
+	if len(entities) > 1 {
+		if payload, err = addSubjects(payload, digest.Repository.String(), entities[1:]); err != nil {
+			return err
+		}
+	}
+	if err := schemas.ValidateStatement(payload); err != nil {
+		return err
+	}
+	// The transparency log upload and the registry pushes of an attestation
+	// whose publication fails are resumed with the same envelope, which
+	// makes them idempotent: the log has one entry of the envelope, and the
+	// registry one attestation.
+	var j *journal
+	if c.JournalDir != "" && !c.DryRun && !c.NoUpload {
+		j = &journal{dir: c.JournalDir}
+	}
+	if !c.NoUpload {
+		// Attesting the same content again, e.g. for each tag of the
+		// digest, reuses the attestations that the signer already made.
+		if entities, err = c.unattested(ctx, entities, payload, sv); err != nil {
+			return err
+		}
+		if len(entities) == 0 {
+			j.remove(ctx, digest, payload)
+			return nil
+		}
+	}
+	je, err := j.resume(digest, payload, sv)
 	if err != nil {
-		return fmt.Errorf("signing: %w", err)
+		return err
+	}
+	var signedPayload []byte
+	cert, chain := sv.Cert, sv.Chain
+	if je != nil {
+		ui.Infof(ctx, "Resuming the publication of the attestation of %s signed at %s", digest, je.CreatedAt.Format(time.RFC3339))
+		signedPayload, cert, chain = je.Envelope, []byte(je.Certificate), []byte(je.Chain)
+		// The images it was pushed to have it, even if signed by another
+		// key of the signer than sv.
+		if entities, err = je.unpushed(entities); err != nil {
+			return err
+		}
+		if len(entities) == 0 {
+			j.remove(ctx, digest, payload)
+			return nil
+		}
+	} else {
+		if signedPayload, err = wrapped.SignMessage(bytes.NewReader(payload), signatureoptions.WithContext(ctx)); err != nil {
+			return fmt.Errorf("signing: %w", err)
+		}
+		if j != nil {
+			if je, err = newEntry(digest, payload, signedPayload, sv); err != nil {
+				return err
+			}
+			j.save(ctx, digest, payload, je)
+		}
+	}
+	if !c.DryRun {
+		// Archived again below with its transparency log bundle, if any.
+		store.Archive(ctx, &store.Entry{
+			Kind:        store.KindAttestation,
+			Subject:     digest.String(),
+			Digest:      digest.DigestStr(),
+			CreatedAt:   time.Now().UTC(),
+			Payload:     signedPayload,
+			Certificate: string(cert),
+			Chain:       string(chain),
+		})
 	}
 
 	if c.NoUpload {
@@ -169,16 +349,26 @@ func (c *AttestCommand) Exec(ctx context.Context, imageRef string) error {
 	}
 
 	opts := []static.Option{static.WithLayerMediaType(types.DssePayloadType)}
-	if sv.Cert != nil {
-		opts = append(opts, static.WithCertChain(sv.Cert, sv.Chain))
+	if len(cert) > 0 {
+		opts = append(opts, static.WithCertChain(cert, chain))
 	}
 	if c.KeyOpts.TSAServerURL != "" {
-		// Here we get the response from the timestamped authority server
-		responseBytes, err := tsa.GetTimestampedSignature(signedPayload, tsaclient.NewTSAClient(c.KeyOpts.TSAServerURL))
-		if err != nil {
-			return err
+		var bundle *cbundle.RFC3161Timestamp
+		if je != nil {
+			bundle = je.RFC3161Timestamp
+		}
+		if bundle == nil {
+			// Here we get the response from the timestamped authority server
+			responseBytes, err := tsa.GetTimestampedSignature(signedPayload, tsaclient.NewTSAClient(c.KeyOpts.TSAServerURL))
+			if err != nil {
+				return err
+			}
+			bundle = cbundle.TimestampToRFC3161Timestamp(responseBytes)
+			if je != nil {
+				je.RFC3161Timestamp = bundle
+				j.save(ctx, digest, payload, je)
+			}
 		}
-		bundle := cbundle.TimestampToRFC3161Timestamp(responseBytes)
 
 		opts = append(opts, static.WithRFC3161Timestamp(bundle))
 	}
@@ -195,16 +385,35 @@ func (c *AttestCommand) Exec(ctx context.Context, imageRef string) error {
 	opts = append(opts, static.WithAnnotations(predicateTypeAnnotation))
 
 	// Check whether we should be uploading to the transparency log
-	shouldUpload, err := sign.ShouldUploadToTlog(ctx, c.KeyOpts, digest, c.TlogUpload)
-	if err != nil {
+	shouldUpload := false
+	if c.DryRun {
+		sign.DryRunTlogUpload(ctx, c.KeyOpts, digest, c.TlogUpload, "intoto")
+	} else if shouldUpload, err = sign.ShouldUploadToTlog(ctx, c.KeyOpts, digest, c.TlogUpload); err != nil {
 		return fmt.Errorf("should upload to tlog: %w", err)
 	}
+	var bundle *cbundle.RekorBundle
 	if shouldUpload {
-		bundle, err := uploadToTlog(ctx, sv, c.RekorURL, func(r *client.Rekor, b []byte) (*models.LogEntryAnon, error) {
-			return cosign.TLogUploadInTotoAttestation(ctx, r, signedPayload, b)
-		})
-		if err != nil {
-			return err
+		if je != nil {
+			bundle = je.Bundle
+		}
+		if bundle == nil {
+			if err := c.retry(ctx, "Uploading to the transparency log", func() (err error) {
+				bundle, err = uploadToTlog(ctx, sv, c.RekorURL, func(r *client.Rekor, b []byte) (*models.LogEntryAnon, error) {
+					// A resumed envelope is signed by the journaled
+					// signer, which for keyless signing isn't sv.
+					if je != nil {
+						b = je.signer()
+					}
+					return cosign.TLogUploadInTotoAttestation(ctx, r, signedPayload, b)
+				})
+				return err
+			}); err != nil {
+				return publicationError(nil, nil, entities, je != nil, err)
+			}
+			if je != nil {
+				je.Bundle = bundle
+				j.save(ctx, digest, payload, je)
+			}
 		}
 		opts = append(opts, static.WithBundle(bundle))
 	}
@@ -213,10 +422,8 @@ func (c *AttestCommand) Exec(ctx context.Context, imageRef string) error {
 	if err != nil {
 		return err
 	}
-
-	se, err := ociremote.SignedEntity(digest, ociremoteOpts...)
-	if err != nil {
-		return err
+	if !c.DryRun {
+		store.ArchiveSignature(ctx, store.KindAttestation, digest.String(), digest.DigestStr(), sig)
 	}
 
 	signOpts := []mutate.SignOption{
@@ -228,12 +435,212 @@ func (c *AttestCommand) Exec(ctx context.Context, imageRef string) error {
 		signOpts = append(signOpts, mutate.WithReplaceOp(ro))
 	}
 
-	// Attach the attestation to the entity.
-	newSE, err := mutate.AttachAttestationToEntity(se, sig, signOpts...)
+	// Attach the attestation to each entity. Its layer is the same blob for
+	// all of them, which the registry already has after the first write.
+	var pushed []string
+	if je != nil {
+		pushed = je.Pushed
+	}
+	for i, se := range entities {
+		newSE, err := mutate.AttachAttestationToEntity(se, sig, signOpts...)
+		if err != nil {
+			return err
+		}
+		if err := c.retry(ctx, "Pushing the attestation", func() error {
+			return c.push(ctx, digest.Repository, newSE, local, dst, len(signedPayload), ociremoteOpts)
+		}); err != nil {
+			return publicationError(bundle, pushed, entities[i:], je != nil, err)
+		}
+		h, err := se.Digest()
+		if err != nil {
+			return err
+		}
+		pushed = append(pushed, h.String())
+		if je != nil {
+			je.Pushed = pushed
+			j.save(ctx, digest, payload, je)
+		}
+	}
+	j.remove(ctx, digest, payload)
+	return nil
+}
+
+// push writes the attestations of se, an image of repo, to the registry or,
+// if local is set, to the OCI layout dst.
+func (c *AttestCommand) push(ctx context.Context, repo name.Repository, se oci.SignedEntity, local *layout.Local, dst string, layerSize int, ociremoteOpts []ociremote.Option) error {
+	h, err := se.Digest()
 	if err != nil {
 		return err
 	}
+	digest := repo.Digest(h.String())
+
+	if c.DryRun {
+		atts, err := se.Attestations()
+		if err != nil {
+			return err
+		}
+		if local != nil {
+			return sign.DryRunPush(ctx, "attestation", "the OCI layout "+dst, atts, layerSize)
+		}
+		var targets []string
+		if c.RegistryReferrersMode.Referrers() {
+			targets = append(targets, "a referrer of "+digest.String())
+		}
+		if c.RegistryReferrersMode.Tags() {
+			tag, err := ociremote.AttestationTag(digest, ociremoteOpts...)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, tag.String())
+		}
+		return sign.DryRunPush(ctx, "attestation", strings.Join(targets, " and "), atts, layerSize)
+	}
+
+	if local != nil {
+		ui.Infof(ctx, "Writing attestation of %s to the OCI layout %s", digest.DigestStr(), dst)
+		return local.Write(dst, se)
+	}
+
+	// Publish the attestations associated with this entity (using OCI 1.1+ behavior)
+	if c.RegistryReferrersMode.Referrers() {
+		if err := ociremote.WriteAttestationsExperimentalOCI(digest, se, ociremoteOpts...); err != nil {
+			return err
+		}
+		if !c.RegistryReferrersMode.Tags() {
+			return nil
+		}
+	}
 
 	// Publish the attestations associated with this entity
-	return ociremote.WriteAttestations(digest.Repository, newSE, ociremoteOpts...)
+	return ociremote.WriteAttestations(digest.Repository, se, ociremoteOpts...)
+}
+
+// entities returns se and, with Recursive, each image and index that se
+// transitively has, once each.
+func (c *AttestCommand) entities(ctx context.Context, se oci.SignedEntity) ([]oci.SignedEntity, error) {
+	if !c.Recursive {
+		return []oci.SignedEntity{se}, nil
+	}
+	var entities []oci.SignedEntity
+	seen := map[v1.Hash]bool{}
+	err := walk.SignedEntity(ctx, se, func(_ context.Context, se oci.SignedEntity) error {
+		h, err := se.Digest()
+		if err != nil {
+			return err
+		}
+		if !seen[h] {
+			seen[h] = true
+			entities = append(entities, se)
+		}
+		return nil
+	})
+	return entities, err
+}
+
+// addSubjects adds the entities, images of repo, to the subjects of the
+// in-toto statement.
+func addSubjects(statement []byte, repo string, entities []oci.SignedEntity) ([]byte, error) {
+	st := map[string]json.RawMessage{}
+	if err := json.Unmarshal(statement, &st); err != nil {
+		return nil, err
+	}
+	var subjects []in_toto.Subject
+	if err := json.Unmarshal(st["subject"], &subjects); err != nil {
+		return nil, fmt.Errorf("parsing the subjects of the statement: %w", err)
+	}
+	for _, se := range entities {
+		h, err := se.Digest()
+		if err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, in_toto.Subject{Name: repo, Digest: map[string]string{h.Algorithm: h.Hex}})
+	}
+	b, err := json.Marshal(subjects)
+	if err != nil {
+		return nil, err
+	}
+	st["subject"] = b
+	return json.Marshal(st)
+}
+
+// unattested returns the entities that don't have an attestation of the
+// statement by the signer of sv yet, and prints those that have.
+func (c *AttestCommand) unattested(ctx context.Context, entities []oci.SignedEntity, statement []byte, sv *sign.SignerVerifier) ([]oci.SignedEntity, error) {
+	var cert *x509.Certificate
+	if sv.Cert != nil {
+		certs, err := cryptoutils.UnmarshalCertificatesFromPEM(sv.Cert)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the signing certificate: %w", err)
+		}
+		if len(certs) > 0 {
+			cert = certs[0]
+		}
+	}
+	pending := make([]oci.SignedEntity, 0, len(entities))
+	for _, se := range entities {
+		atts, err := se.Attestations()
+		if err != nil {
+			return nil, err
+		}
+		existing, err := cremote.FindStatement(atts, statement, sv, cert)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			pending = append(pending, se)
+			continue
+		}
+		h, err := se.Digest()
+		if err != nil {
+			return nil, err
+		}
+		ui.Infof(ctx, "%s already has an attestation of this statement by the signer, reusing it", h)
+	}
+	return pending, nil
+}
+
+// statementType returns the type GenerateStatement generates the statement of
+// the --type t with: t for the predicates cosign knows, or else uri, the
+// predicate type t parsed to, e.g. that of a predicate plug-in.
+// statement generates the in-toto statement of the attestation of the image
+// at digest, whose hash is h.
+func (c *AttestCommand) statement(ctx context.Context, digest name.Digest, h v1.Hash, predicateURI string) (interface{}, error) {
+	if c.GenerateStatement != nil {
+		return c.GenerateStatement(ctx, digest)
+	}
+	var predicate io.ReadCloser
+	switch {
+	case c.GeneratePredicate != nil:
+		generated, err := c.GeneratePredicate(ctx, digest)
+		if err != nil {
+			return nil, err
+		}
+		predicate = io.NopCloser(bytes.NewReader(generated))
+	case c.PredicatePath == "-":
+		fmt.Fprintln(os.Stderr, "Using payload from: standard input")
+		predicate = os.Stdin
+	default:
+		fmt.Fprintln(os.Stderr, "Using payload from:", c.PredicatePath)
+		var err error
+		predicate, err = os.Open(c.PredicatePath)
+		if err != nil {
+			return nil, err
+		}
+		defer predicate.Close()
+	}
+
+	return attestation.GenerateStatement(attestation.GenerateOpts{
+		Predicate:       predicate,
+		Type:            statementType(c.PredicateType, predicateURI),
+		Digest:          h.Hex,
+		DigestAlgorithm: h.Algorithm,
+		Repo:            digest.Repository.String(),
+	})
+}
+
+func statementType(t, uri string) string {
+	if _, ok := options.PredicateTypeMap[t]; ok {
+		return t
+	}
+	return uri
 }
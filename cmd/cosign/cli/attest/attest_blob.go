@@ -17,7 +17,7 @@ package attest
 import (
 	"bytes"
 	"context"
-	"crypto"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -35,12 +35,13 @@ import (
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa/client"
+	"github.com/sigstore/cosign/v2/internal/pkg/store"
+	"github.com/sigstore/cosign/v2/pkg/blob"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
 	cbundle "github.com/sigstore/cosign/v2/pkg/cosign/bundle"
 	"github.com/sigstore/cosign/v2/pkg/types"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
-	"github.com/sigstore/sigstore/pkg/signature"
 	"github.com/sigstore/sigstore/pkg/signature/dsse"
 	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
 )
@@ -52,9 +53,19 @@ type AttestBlobCommand struct {
 	CertChainPath string
 
 	ArtifactHash string
+	// DigestAlgorithm is the name of the digest algorithm of the subject of
+	// the statement, see blob.LookupDigestAlgorithm. ArtifactHash is a digest
+	// of this algorithm.
+	DigestAlgorithm string
 
 	PredicatePath string
 	PredicateType string
+	// PredicateSchemas is the path or reference of a predicate schema
+	// registry that custom predicates are validated against before signing.
+	PredicateSchemas string
+	// PredicateSchema is the path of a JSON schema that predicates of
+	// PredicateType are validated against, in place of the registered one.
+	PredicateSchema string
 
 	TlogUpload bool
 	Timeout    time.Duration
@@ -62,6 +73,11 @@ type AttestBlobCommand struct {
 	OutputSignature   string
 	OutputAttestation string
 	OutputCertificate string
+
+	// OutputDir and OutputName are the directory and the name template of
+	// the files written for each blob by ExecBlobs.
+	OutputDir  string
+	OutputName string
 }
 
 // nolint
@@ -81,11 +97,14 @@ func (c *AttestBlobCommand) Exec(ctx context.Context, artifactPath string) error
 		return errors.New("expected an rfc3161-timestamp path when using a TSA server")
 	}
 
-	var artifact []byte
+	alg, err := blob.LookupDigestAlgorithm(c.DigestAlgorithm)
+	if err != nil {
+		return err
+	}
 	var hexDigest string
-	var err error
-
 	if c.ArtifactHash == "" {
+		var artifact []byte
+		var err error
 		if artifactPath == "-" {
 			artifact, err = io.ReadAll(os.Stdin)
 		} else {
@@ -95,39 +114,194 @@ func (c *AttestBlobCommand) Exec(ctx context.Context, artifactPath string) error
 		if err != nil {
 			return err
 		}
-	}
-
-	if c.ArtifactHash == "" {
-		digest, _, err := signature.ComputeDigestForSigning(bytes.NewReader(artifact), crypto.SHA256, []crypto.Hash{crypto.SHA256, crypto.SHA384})
-		if err != nil {
+		if hexDigest, err = artifactDigest(alg, artifact); err != nil {
 			return err
 		}
-		hexDigest = strings.ToLower(hex.EncodeToString(digest))
 	} else {
 		hexDigest = c.ArtifactHash
 	}
 
-	fmt.Fprintln(os.Stderr, "Using predicate from:", c.PredicatePath)
-	predicate, err := os.Open(c.PredicatePath)
+	predicateType, schemas, err := c.predicateSchemas()
 	if err != nil {
 		return err
 	}
-	defer predicate.Close()
 
 	sv, err := sign.SignerFromKeyOpts(ctx, c.CertPath, c.CertChainPath, c.KeyOpts)
 	if err != nil {
 		return fmt.Errorf("getting signer: %w", err)
 	}
 	defer sv.Close()
+
+	return c.attest(ctx, sv, predicateType, schemas, artifactPath, alg, hexDigest, attestOutputs{
+		Signature:   c.OutputSignature,
+		Attestation: c.OutputAttestation,
+		Certificate: c.OutputCertificate,
+		Bundle:      c.BundlePath,
+		Timestamp:   c.RFC3161TimestampPath,
+	})
+}
+
+// ExecBlobs attests the blobs, paths or globs, with one signer, so that
+// keyless signing gets a single certificate from Fulcio, and writes the
+// DSSE envelope and bundle of each blob, and its RFC3161 timestamp if
+// c.TSAServerURL is set, to c.OutputDir, named with the c.OutputName template,
+// and their index.
+func (c *AttestBlobCommand) ExecBlobs(ctx context.Context, blobs []string) error {
+	if options.NOf(c.KeyRef, c.Sk) > 1 {
+		return &options.KeyParseError{}
+	}
+	if c.ArtifactHash != "" || c.OutputSignature != "" || c.OutputAttestation != "" || c.BundlePath != "" || c.RFC3161TimestampPath != "" {
+		return errors.New("--hash, --output-signature, --output-attestation, --bundle and --rfc3161-timestamp-bundle can't be used with --output-dir, the files of each blob are named after it")
+	}
+	alg, err := blob.LookupDigestAlgorithm(c.DigestAlgorithm)
+	if err != nil {
+		return err
+	}
+	paths, err := sign.ExpandBlobs(blobs)
+	if err != nil {
+		return err
+	}
+	out, err := sign.NewOutputDir("attest-blob", c.OutputDir, c.OutputName)
+	if err != nil {
+		return err
+	}
+	kinds := []string{sign.OutputSignature, sign.OutputBundle}
+	if c.TSAServerURL != "" {
+		kinds = append(kinds, sign.OutputTimestamp)
+	}
+	digests := make([]string, 0, len(paths))
+	entries := make([]*sign.OutputIndexEntry, 0, len(paths))
+	for _, p := range paths {
+		artifact, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		digest, err := artifactDigest(alg, artifact)
+		if err != nil {
+			return err
+		}
+		// The index lists the SHA-256 digests of the blobs.
+		indexDigest := digest
+		if !alg.IsDefault() {
+			h := sha256.Sum256(artifact)
+			indexDigest = hex.EncodeToString(h[:])
+		}
+		e, err := out.Entry(p, indexDigest, kinds...)
+		if err != nil {
+			return err
+		}
+		digests = append(digests, digest)
+		entries = append(entries, e)
+	}
+	predicateType, schemas, err := c.predicateSchemas()
+	if err != nil {
+		return err
+	}
+
+	signerCtx := ctx
+	if c.Timeout != 0 {
+		var cancelFn context.CancelFunc
+		signerCtx, cancelFn = context.WithTimeout(ctx, c.Timeout)
+		defer cancelFn()
+	}
+	sv, err := sign.SignerFromKeyOpts(signerCtx, c.CertPath, c.CertChainPath, c.KeyOpts)
+	if err != nil {
+		return fmt.Errorf("getting signer: %w", err)
+	}
+	defer sv.Close()
+
+	for i, e := range entries {
+		blobCtx := ctx
+		cancelFn := func() {}
+		if c.Timeout != 0 {
+			blobCtx, cancelFn = context.WithTimeout(ctx, c.Timeout)
+		}
+		fmt.Fprintln(os.Stderr, "Using payload from:", e.Path)
+		err := c.attest(blobCtx, sv, predicateType, schemas, e.Path, alg, digests[i], attestOutputs{
+			Signature: out.Path(e, sign.OutputSignature),
+			Bundle:    out.Path(e, sign.OutputBundle),
+			Timestamp: out.Path(e, sign.OutputTimestamp),
+		})
+		cancelFn()
+		if err != nil {
+			return fmt.Errorf("attesting %s: %w", e.Path, err)
+		}
+		out.Add(e)
+	}
+
+	certificate := ""
+	if c.OutputCertificate != "" {
+		if err := writeCertificate(ctx, sv, c.OutputCertificate); err != nil {
+			return err
+		}
+		if _, err := os.Stat(c.OutputCertificate); err == nil {
+			certificate = c.OutputCertificate
+		}
+	}
+	if err := out.WriteIndex(certificate); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Attested %d blobs, listed in %s\n", len(entries), filepath.Join(c.OutputDir, sign.OutputIndexFile))
+	return nil
+}
+
+// attestOutputs are the files written for an attested blob, if set.
+type attestOutputs struct {
+	Signature   string
+	Attestation string
+	Certificate string
+	Bundle      string
+	Timestamp   string
+}
+
+func artifactDigest(alg blob.DigestAlgorithm, artifact []byte) (string, error) {
+	digest, err := alg.Digest(bytes.NewReader(artifact))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(hex.EncodeToString(digest)), nil
+}
+
+// predicateSchemas returns the predicate type of c and the schemas its
+// predicates are validated against.
+func (c *AttestBlobCommand) predicateSchemas() (string, *attestation.PredicateSchemas, error) {
+	predicateType, err := options.ParsePredicateType(c.PredicateType)
+	if err != nil {
+		return "", nil, err
+	}
+	schemas, err := cosign.LoadPredicateSchemas(c.PredicateSchemas, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.PredicateSchema != "" {
+		if schemas, err = cosign.AddPredicateSchema(schemas, predicateType, c.PredicateSchema); err != nil {
+			return "", nil, err
+		}
+	}
+	return predicateType, schemas, nil
+}
+
+// attest signs the statement of the predicate of c for the artifact at
+// artifactPath with the hex digest of alg, and writes the outputs.
+// nolint
+func (c *AttestBlobCommand) attest(ctx context.Context, sv *sign.SignerVerifier, predicateType string, schemas *attestation.PredicateSchemas, artifactPath string, alg blob.DigestAlgorithm, hexDigest string, outputs attestOutputs) error {
+	fmt.Fprintln(os.Stderr, "Using predicate from:", c.PredicatePath)
+	predicate, err := os.Open(c.PredicatePath)
+	if err != nil {
+		return err
+	}
+	defer predicate.Close()
+
 	wrapped := dsse.WrapSigner(sv, types.IntotoPayloadType)
 
 	base := path.Base(artifactPath)
 
 	sh, err := attestation.GenerateStatement(attestation.GenerateOpts{
-		Predicate: predicate,
-		Type:      c.PredicateType,
-		Digest:    hexDigest,
-		Repo:      base,
+		Predicate:       predicate,
+		Type:            statementType(c.PredicateType, predicateType),
+		Digest:          hexDigest,
+		DigestAlgorithm: alg.InTotoName,
+		Repo:            base,
 	})
 	if err != nil {
 		return err
@@ -137,6 +311,9 @@ func (c *AttestBlobCommand) Exec(ctx context.Context, artifactPath string) error
 	if err != nil {
 		return err
 	}
+	if err := schemas.ValidateStatement(payload); err != nil {
+		return err
+	}
 
 	sig, err := wrapped.SignMessage(bytes.NewReader(payload), signatureoptions.WithContext(ctx))
 	if err != nil {
@@ -159,11 +336,23 @@ func (c *AttestBlobCommand) Exec(ctx context.Context, artifactPath string) error
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(c.RFC3161TimestampPath, ts, 0600); err != nil {
+		if err := os.WriteFile(outputs.Timestamp, ts, 0600); err != nil {
 			return fmt.Errorf("create RFC3161 timestamp file: %w", err)
 		}
-		fmt.Fprintln(os.Stderr, "RFC3161 timestamp bundle written to file ", c.RFC3161TimestampPath)
+		fmt.Fprintln(os.Stderr, "RFC3161 timestamp bundle written to file ", outputs.Timestamp)
+	}
+	// Archived again below with its transparency log bundle, if any.
+	archived := &store.Entry{
+		Kind:             store.KindAttestation,
+		Subject:          artifactPath,
+		Digest:           alg.Name + ":" + hexDigest,
+		CreatedAt:        time.Now().UTC(),
+		Payload:          sig,
+		Certificate:      string(sv.Cert),
+		Chain:            string(sv.Chain),
+		RFC3161Timestamp: rfc3161Timestamp,
 	}
+	store.Archive(ctx, archived)
 
 	rekorBytes, err := sv.Bytes(ctx)
 	if err != nil {
@@ -185,9 +374,11 @@ func (c *AttestBlobCommand) Exec(ctx context.Context, artifactPath string) error
 		}
 		fmt.Fprintln(os.Stderr, "tlog entry created with index:", *entry.LogIndex)
 		signedPayload.Bundle = cbundle.EntryToBundle(entry)
+		archived.Bundle = signedPayload.Bundle
+		store.Archive(ctx, archived)
 	}
 
-	if c.BundlePath != "" {
+	if outputs.Bundle != "" {
 		signedPayload.Base64Signature = base64.StdEncoding.EncodeToString(sig)
 		signedPayload.Cert = base64.StdEncoding.EncodeToString(rekorBytes)
 
@@ -195,50 +386,57 @@ func (c *AttestBlobCommand) Exec(ctx context.Context, artifactPath string) error
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(c.BundlePath, contents, 0600); err != nil {
+		if err := os.WriteFile(outputs.Bundle, contents, 0600); err != nil {
 			return fmt.Errorf("create bundle file: %w", err)
 		}
-		fmt.Fprintln(os.Stderr, "Bundle wrote in the file ", c.BundlePath)
+		fmt.Fprintln(os.Stderr, "Bundle wrote in the file ", outputs.Bundle)
 	}
 
-	if c.OutputSignature != "" {
-		if err := os.WriteFile(c.OutputSignature, sig, 0600); err != nil {
+	if outputs.Signature != "" {
+		if err := os.WriteFile(outputs.Signature, sig, 0600); err != nil {
 			return fmt.Errorf("create signature file: %w", err)
 		}
-		fmt.Fprintf(os.Stderr, "Signature written in %s\n", c.OutputSignature)
+		fmt.Fprintf(os.Stderr, "Signature written in %s\n", outputs.Signature)
 	} else {
 		fmt.Fprintln(os.Stdout, string(sig))
 	}
 
-	if c.OutputAttestation != "" {
-		if err := os.WriteFile(c.OutputAttestation, payload, 0600); err != nil {
+	if outputs.Attestation != "" {
+		if err := os.WriteFile(outputs.Attestation, payload, 0600); err != nil {
 			return fmt.Errorf("create signature file: %w", err)
 		}
-		fmt.Fprintf(os.Stderr, "Attestation written in %s\n", c.OutputAttestation)
+		fmt.Fprintf(os.Stderr, "Attestation written in %s\n", outputs.Attestation)
 	}
 
-	if c.OutputCertificate != "" {
-		signer, err := sv.Bytes(ctx)
-		if err != nil {
-			return fmt.Errorf("error getting signer: %w", err)
-		}
-		cert, err := cryptoutils.UnmarshalCertificatesFromPEM(signer)
-		// signer is a certificate
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Could not output signer certificate. Was a certificate used? ", err)
-			return nil
+	if outputs.Certificate != "" {
+		return writeCertificate(ctx, sv, outputs.Certificate)
+	}
 
-		}
-		if len(cert) != 1 {
-			fmt.Fprintln(os.Stderr, "Could not output signer certificate. Expected a single certificate")
-			return nil
-		}
-		bts := signer
-		if err := os.WriteFile(c.OutputCertificate, bts, 0600); err != nil {
-			return fmt.Errorf("create certificate file: %w", err)
-		}
-		fmt.Fprintln(os.Stderr, "Certificate written to file ", c.OutputCertificate)
+	return nil
+}
+
+// writeCertificate writes the certificate of sv to outputCertificate, or
+// warns that it has none.
+func writeCertificate(ctx context.Context, sv *sign.SignerVerifier, outputCertificate string) error {
+	signer, err := sv.Bytes(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting signer: %w", err)
 	}
+	cert, err := cryptoutils.UnmarshalCertificatesFromPEM(signer)
+	// signer is a certificate
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Could not output signer certificate. Was a certificate used? ", err)
+		return nil
 
+	}
+	if len(cert) != 1 {
+		fmt.Fprintln(os.Stderr, "Could not output signer certificate. Expected a single certificate")
+		return nil
+	}
+	bts := signer
+	if err := os.WriteFile(outputCertificate, bts, 0600); err != nil {
+		return fmt.Errorf("create certificate file: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, "Certificate written to file ", outputCertificate)
 	return nil
 }
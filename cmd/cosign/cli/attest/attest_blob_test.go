@@ -33,7 +33,10 @@ import (
 	ssldsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/blob"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 	"github.com/sigstore/cosign/v2/test"
 	"github.com/sigstore/sigstore/pkg/signature"
 	"github.com/sigstore/sigstore/pkg/signature/dsse"
@@ -173,6 +176,52 @@ func TestAttestBlobCmdLocalKeyAndCert(t *testing.T) {
 
 // TestAttestBlob tests the main functionality -- does the command produce
 // a validly signed DSSE envelope? (Using an on disk key)
+func TestAttestBlobDigestAlgorithm(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+
+	keys, _ := cosign.GenerateKeyPair(nil)
+	keyRef := writeFile(t, td, string(keys.PrivateBytes), "key.pem")
+	blobPath := writeFile(t, td, "foo", "foo.txt")
+	predicatePath := writeFile(t, td, `{ "buildType": "x", "builder": { "id": "2" }, "recipe": {} }`, "predicate.json")
+
+	for _, name := range []string{"sha3-256", "blake3"} {
+		alg, err := blob.LookupDigestAlgorithm(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest, _ := alg.Digest(strings.NewReader("foo"))
+		dssePath := filepath.Join(td, name+".intoto.jsonl")
+		at := AttestBlobCommand{
+			KeyOpts:         options.KeyOpts{KeyRef: keyRef},
+			PredicatePath:   predicatePath,
+			PredicateType:   "slsaprovenance",
+			OutputSignature: dssePath,
+			DigestAlgorithm: name,
+		}
+		if err := at.Exec(ctx, blobPath); err != nil {
+			t.Fatal(err)
+		}
+
+		dsseBytes, _ := os.ReadFile(dssePath)
+		env := &ssldsse.Envelope{}
+		if err := json.Unmarshal(dsseBytes, env); err != nil {
+			t.Fatal(err)
+		}
+		decodedPredicate, err := base64.StdEncoding.DecodeString(env.Payload)
+		if err != nil {
+			t.Fatalf("decoding dsse payload: %v", err)
+		}
+		var statement in_toto.Statement
+		if err := json.Unmarshal(decodedPredicate, &statement); err != nil {
+			t.Fatalf("decoding predicate: %v", err)
+		}
+		if len(statement.Subject) != 1 || len(statement.Subject[0].Digest) != 1 || statement.Subject[0].Digest[alg.InTotoName] != hex.EncodeToString(digest) {
+			t.Errorf("%s: subjects = %+v, want the %s digest of the blob", name, statement.Subject, alg.InTotoName)
+		}
+	}
+}
+
 func TestAttestBlob(t *testing.T) {
 	ctx := context.Background()
 	td := t.TempDir()
@@ -241,3 +290,137 @@ func TestAttestBlob(t *testing.T) {
 		t.Fatalf("dsse verify: %v", err)
 	}
 }
+
+func TestAttestBlobPredicatePlugin(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+	plugins := filepath.Join(td, "predicates")
+	if err := os.Mkdir(plugins, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, plugins, `{"name": "build", "predicateType": "https://example.com/build/v1", "schema": {"type": "object", "required": ["builder"]}}`, "build.json")
+	t.Setenv(env.VariablePredicatesDir.String(), plugins)
+
+	keys, _ := cosign.GenerateKeyPair(nil)
+	keyRef := writeFile(t, td, string(keys.PrivateBytes), "key.pem")
+	blobPath := writeFile(t, td, "foo", "foo.txt")
+	strictSchema := writeFile(t, td, `{"type": "object", "required": ["builder", "steps"]}`, "strict.json")
+
+	for name, tc := range map[string]struct {
+		predicate string
+		schema    string
+		wantErr   string
+	}{
+		"valid":            {predicate: `{"builder": "ci"}`},
+		"invalid":          {predicate: `{"steps": 3}`, wantErr: "does not match its schema"},
+		"predicate schema": {predicate: `{"builder": "ci"}`, schema: strictSchema, wantErr: "does not match its schema"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dssePath := filepath.Join(t.TempDir(), "dsse.intoto.jsonl")
+			at := AttestBlobCommand{
+				KeyOpts:         options.KeyOpts{KeyRef: keyRef},
+				PredicatePath:   writeFile(t, t.TempDir(), tc.predicate, "predicate.json"),
+				PredicateType:   "build",
+				PredicateSchema: tc.schema,
+				OutputSignature: dssePath,
+			}
+			err := at.Exec(ctx, blobPath)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Exec() = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			dsseBytes, _ := os.ReadFile(dssePath)
+			envelope := &ssldsse.Envelope{}
+			if err := json.Unmarshal(dsseBytes, envelope); err != nil {
+				t.Fatal(err)
+			}
+			payload, _ := base64.StdEncoding.DecodeString(envelope.Payload)
+			var statement in_toto.Statement
+			if err := json.Unmarshal(payload, &statement); err != nil {
+				t.Fatal(err)
+			}
+			if statement.PredicateType != "https://example.com/build/v1" {
+				t.Errorf("predicate type = %s, want that of the plug-in", statement.PredicateType)
+			}
+		})
+	}
+}
+
+func TestAttestBlobExecBlobs(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+
+	keys, _ := cosign.GenerateKeyPair(nil)
+	keyRef := writeFile(t, td, string(keys.PrivateBytes), "key.pem")
+	pubKeyRef := writeFile(t, td, string(keys.PublicBytes), "key.pub")
+	writeFile(t, td, "linux", "app-linux.tar.gz")
+	writeFile(t, td, "darwin", "app-darwin.tar.gz")
+	predicatePath := writeFile(t, td, `{ "buildType": "x", "builder": { "id": "2" }, "recipe": {} }`, "predicate.json")
+
+	out := filepath.Join(td, "attestations")
+	at := AttestBlobCommand{
+		KeyOpts:       options.KeyOpts{KeyRef: keyRef},
+		PredicatePath: predicatePath,
+		PredicateType: "slsaprovenance",
+		OutputDir:     out,
+		OutputName:    "{name}.{alg}.{kind}",
+	}
+	if err := at.ExecBlobs(ctx, []string{filepath.Join(td, "*.tar.gz")}); err != nil {
+		t.Fatalf("ExecBlobs() = %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(out, sign.OutputIndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := sign.OutputIndex{}
+	if err := json.Unmarshal(b, &index); err != nil {
+		t.Fatal(err)
+	}
+	if index.Command != "attest-blob" || len(index.Artifacts) != 2 {
+		t.Fatalf("index = %s, want the 2 attested blobs", b)
+	}
+	verifier, _ := signature.LoadVerifierFromPEMFile(pubKeyRef, crypto.SHA256)
+	dssev, err := ssldsse.NewEnvelopeVerifier(&dsse.VerifierAdapter{SignatureVerifier: verifier})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range index.Artifacts {
+		if want := e.Name + ".sha256.sig"; e.Files[sign.OutputSignature] != want {
+			t.Errorf("signature of %s = %s, want %s", e.Name, e.Files[sign.OutputSignature], want)
+		}
+		if _, err := os.Stat(filepath.Join(out, e.Name+".sha256.bundle")); err != nil {
+			t.Errorf("bundle of %s: %v", e.Name, err)
+		}
+		dsseBytes, err := os.ReadFile(filepath.Join(out, e.Files[sign.OutputSignature]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		env := &ssldsse.Envelope{}
+		if err := json.Unmarshal(dsseBytes, env); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dssev.Verify(ctx, env); err != nil {
+			t.Errorf("dsse verify of %s: %v", e.Name, err)
+		}
+		decoded, _ := base64.StdEncoding.DecodeString(env.Payload)
+		var statement in_toto.Statement
+		if err := json.Unmarshal(decoded, &statement); err != nil {
+			t.Fatal(err)
+		}
+		if len(statement.Subject) != 1 || "sha256:"+statement.Subject[0].Digest["sha256"] != e.Digest {
+			t.Errorf("subject of %s = %+v, want digest %s", e.Name, statement.Subject, e.Digest)
+		}
+	}
+
+	at.OutputDir = t.TempDir()
+	at.BundlePath = filepath.Join(td, "bundle.json")
+	if err := at.ExecBlobs(ctx, []string{filepath.Join(td, "*.tar.gz")}); err == nil || !strings.Contains(err.Error(), "can't be used with --output-dir") {
+		t.Errorf("ExecBlobs() with --bundle = %v, want an error", err)
+	}
+}
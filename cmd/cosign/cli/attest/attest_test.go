@@ -0,0 +1,301 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+func TestAttestCmdDryRun(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := writeFile(t, td, string(keys.PrivateBytes), "cosign.key")
+	predicate := writeFile(t, td, `{"builder":"ci"}`, "predicate.json")
+
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	ref, err := name.ParseReference(strings.TrimPrefix(s.URL, "http://") + "/app:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := AttestCommand{
+		KeyOpts: options.KeyOpts{
+			KeyRef:           keyRef,
+			RekorURL:         "https://rekor.example.com",
+			SkipConfirmation: true,
+		},
+		PredicatePath: predicate,
+		PredicateType: options.PredicateCustom,
+		TlogUpload:    true,
+		DryRun:        true,
+	}
+	var execErr error
+	out := ui.RunWithTestCtx(func(ctx context.Context, _ ui.WriteFunc) {
+		execErr = c.Exec(ctx, ref.String())
+	})
+	if execErr != nil {
+		t.Fatalf("Exec() unexpected error: %v", execErr)
+	}
+
+	digest := ref.Context().Digest(h.String())
+	tag, err := ociremote.AttestationTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"would upload the intoto entry for " + digest.String() + " to the transparency log at https://rekor.example.com",
+		"would push attestation to " + tag.String(),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q doesn't contain %q", out, want)
+		}
+	}
+	if _, err := remote.Head(tag); err == nil {
+		t.Errorf("dry run pushed %s", tag)
+	}
+}
+
+func TestAttestCmdRecursive(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := writeFile(t, td, string(keys.PrivateBytes), "cosign.key")
+	predicate := writeFile(t, td, `{"packages":["a","b"]}`, "sbom.json")
+
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := random.Index(100, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{"latest", "v1"} {
+		if err := remote.WriteIndex(repo.Tag(tag), idx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	im, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := idx.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digests := []v1.Hash{h}
+	for _, m := range im.Manifests {
+		digests = append(digests, m.Digest)
+	}
+
+	c := AttestCommand{
+		KeyOpts:       options.KeyOpts{KeyRef: keyRef},
+		PredicatePath: predicate,
+		PredicateType: options.PredicateCustom,
+		Recursive:     true,
+	}
+	// Attesting the same content through another tag of the digest reuses
+	// the attestations.
+	for _, tag := range []string{"latest", "v1"} {
+		if err := c.Exec(context.Background(), repo.Tag(tag).String()); err != nil {
+			t.Fatalf("Exec(%s) = %v", tag, err)
+		}
+	}
+
+	var layer v1.Hash
+	for i, h := range digests {
+		se, err := ociremote.SignedEntity(repo.Digest(h.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		atts, err := se.Attestations()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs, err := atts.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sigs) != 1 {
+			t.Fatalf("%s has %d attestations, want 1", h, len(sigs))
+		}
+		d, err := sigs[0].Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			layer = d
+		} else if d != layer {
+			t.Errorf("the attestation of %s is the blob %s, want %s of the index", h, d, layer)
+		}
+		p, err := sigs[0].Payload()
+		if err != nil {
+			t.Fatal(err)
+		}
+		env := struct{ Payload []byte }{}
+		if err := json.Unmarshal(p, &env); err != nil {
+			t.Fatal(err)
+		}
+		for _, h := range digests {
+			if !strings.Contains(string(env.Payload), h.Hex) {
+				t.Errorf("attestation doesn't have the subject %s", h)
+			}
+		}
+	}
+}
+
+func TestAttestCmdResume(t *testing.T) {
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = time.Second })
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := writeFile(t, td, string(keys.PrivateBytes), "cosign.key")
+	predicate := writeFile(t, td, `{"builder":"ci"}`, "predicate.json")
+
+	// The registry fails the manifest pushes while failures is positive, with
+	// a status that go-containerregistry doesn't retry itself.
+	var failures atomic.Int32
+	reg := registry.New()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") && failures.Add(-1) >= 0 {
+			http.Error(w, "insufficient storage", http.StatusInsufficientStorage)
+			return
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	ref, err := name.ParseReference(strings.TrimPrefix(s.URL, "http://") + "/app:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := ref.Context().Digest(h.String())
+
+	journalDir := filepath.Join(td, "journal")
+	c := AttestCommand{
+		KeyOpts:        options.KeyOpts{KeyRef: keyRef},
+		PredicatePath:  predicate,
+		PredicateType:  options.PredicateCustom,
+		JournalDir:     journalDir,
+		PublishRetries: 1,
+	}
+	// The push fails even when retried, and the attestation stays journaled.
+	failures.Store(100)
+	err = c.Exec(context.Background(), digest.String())
+	if err == nil || !strings.Contains(err.Error(), "nothing was published, attesting the same statement again resumes the publication") {
+		t.Fatalf("Exec() = %v, want the failed publication reported", err)
+	}
+	files, err := filepath.Glob(filepath.Join(journalDir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("journal = %v, %v, want one entry", files, err)
+	}
+	b, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e journalEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		t.Fatal(err)
+	}
+
+	// Attesting again, with a push that succeeds when retried, publishes
+	// the journaled envelope.
+	failures.Store(1)
+	if err := c.Exec(context.Background(), digest.String()); err != nil {
+		t.Fatalf("Exec() = %v", err)
+	}
+	se, err := ociremote.SignedEntity(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := atts.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sigs) != 1 {
+		t.Fatalf("%d attestations, want 1", len(sigs))
+	}
+	p, err := sigs[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p, e.Envelope) {
+		t.Errorf("attestation = %s, want the journaled envelope %s", p, e.Envelope)
+	}
+	if files, _ := filepath.Glob(filepath.Join(journalDir, "*.json")); len(files) != 0 {
+		t.Errorf("journal = %v, want it empty once published", files)
+	}
+}
@@ -0,0 +1,109 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// The OCI annotations of the base image of an image, which some builders
+// set as labels instead.
+const (
+	annotationBaseImageDigest = "org.opencontainers.image.base.digest"
+	annotationBaseImageName   = "org.opencontainers.image.base.name"
+)
+
+// generateBuildMetadata returns the buildmetadata predicate of the image at
+// digest.
+func generateBuildMetadata(ctx context.Context, digest name.Digest, opts ...ociremote.Option) ([]byte, error) {
+	se, err := ociremote.SignedEntity(digest, opts...)
+	if err != nil {
+		return nil, err
+	}
+	img, ok := se.(oci.SignedImage)
+	if !ok {
+		return nil, fmt.Errorf("--build-metadata-from-image needs an image, %s is an image index; attest the image of each platform instead", digest)
+	}
+	ui.Infof(ctx, "Generating the build metadata of %s from its config and history", digest)
+	p, err := buildMetadata(img)
+	if err != nil {
+		return nil, fmt.Errorf("generating build metadata: %w", err)
+	}
+	return json.Marshal(p)
+}
+
+// buildMetadata returns the build metadata recorded in the manifest, config
+// and history of img.
+func buildMetadata(img v1.Image) (*attestation.BuildMetadataPredicate, error) {
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	cf, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &attestation.BuildMetadataPredicate{
+		Architecture: cf.Architecture,
+		OS:           cf.OS,
+		Labels:       cf.Config.Labels,
+		Layers:       []attestation.BuildMetadataLayer{},
+	}
+	if !cf.Created.IsZero() {
+		p.Created = cf.Created.UTC().Format(time.RFC3339)
+	}
+	for _, annotations := range []map[string]string{m.Annotations, cf.Config.Labels} {
+		if d := annotations[annotationBaseImageDigest]; d != "" {
+			if _, err := oci.ParseDigest(d); err != nil {
+				return nil, fmt.Errorf("base image digest %q: %w", d, err)
+			}
+			p.BaseImage = &attestation.BuildMetadataBaseImage{Name: annotations[annotationBaseImageName], Digest: d}
+			break
+		}
+	}
+
+	// Each step of the history that isn't an empty layer created the next
+	// layer. Layers without a step, if the builder recorded none, are listed
+	// after the steps.
+	layers := m.Layers
+	for _, h := range cf.History {
+		l := attestation.BuildMetadataLayer{Command: h.CreatedBy, Comment: h.Comment}
+		if !h.Created.IsZero() {
+			l.Created = h.Created.UTC().Format(time.RFC3339)
+		}
+		if !h.EmptyLayer && len(layers) > 0 {
+			l.Digest = layers[0].Digest.String()
+			layers = layers[1:]
+		}
+		p.Layers = append(p.Layers, l)
+	}
+	for _, layer := range layers {
+		p.Layers = append(p.Layers, attestation.BuildMetadataLayer{Digest: layer.Digest.String()})
+	}
+	return p, nil
+}
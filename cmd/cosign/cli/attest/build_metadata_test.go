@@ -0,0 +1,211 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// builtImage returns an image of two layers built in three steps, the second
+// of which created no layer.
+func builtImage(t *testing.T) (v1.Image, []v1.Hash) {
+	t.Helper()
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	img := empty.Image
+	var digests []v1.Hash
+	for _, step := range []struct {
+		command string
+		empty   bool
+	}{{"ADD rootfs.tar /", false}, {"ENV PATH=/bin", true}, {"RUN make install", false}} {
+		add := mutate.Addendum{History: v1.History{CreatedBy: step.command, Created: v1.Time{Time: created}, EmptyLayer: step.empty}}
+		if !step.empty {
+			layer, err := random.Layer(100, "application/vnd.oci.image.layer.v1.tar+gzip")
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := layer.Digest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			add.Layer = layer
+			digests = append(digests, d)
+		}
+		var err error
+		if img, err = mutate.Append(img, add); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cf, err := img.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf = cf.DeepCopy()
+	cf.Architecture, cf.OS, cf.Created = "amd64", "linux", v1.Time{Time: created}
+	cf.Config.Labels = map[string]string{
+		"org.opencontainers.image.source":      "https://github.com/example/app",
+		"org.opencontainers.image.base.name":   "docker.io/library/alpine:3",
+		"org.opencontainers.image.base.digest": "sha256:" + strings.Repeat("b", 64),
+	}
+	if img, err = mutate.ConfigFile(img, cf); err != nil {
+		t.Fatal(err)
+	}
+	return img, digests
+}
+
+func TestBuildMetadata(t *testing.T) {
+	img, digests := builtImage(t)
+	got, err := buildMetadata(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []attestation.BuildMetadataLayer{
+		{Digest: digests[0].String(), Command: "ADD rootfs.tar /", Created: "2026-01-02T03:04:05Z"},
+		{Command: "ENV PATH=/bin", Created: "2026-01-02T03:04:05Z"},
+		{Digest: digests[1].String(), Command: "RUN make install", Created: "2026-01-02T03:04:05Z"},
+	}
+	if !reflect.DeepEqual(got.Layers, want) {
+		t.Errorf("buildMetadata() layers = %+v, want %+v", got.Layers, want)
+	}
+	if got.BaseImage == nil || got.BaseImage.Name != "docker.io/library/alpine:3" || got.BaseImage.Digest != "sha256:"+strings.Repeat("b", 64) {
+		t.Errorf("buildMetadata() base image = %+v, want the labeled one", got.BaseImage)
+	}
+	if got.Architecture != "amd64" || got.OS != "linux" || got.Created != "2026-01-02T03:04:05Z" || got.Labels["org.opencontainers.image.source"] == "" {
+		t.Errorf("buildMetadata() = %+v", got)
+	}
+
+	// The annotations of the manifest take precedence, and layers without
+	// history are listed after it.
+	base := "sha256:" + strings.Repeat("c", 64)
+	cf, err := img.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf = cf.DeepCopy()
+	cf.History = cf.History[:1]
+	truncated, err := mutate.ConfigFile(img, cf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotated := mutate.Annotations(truncated, map[string]string{"org.opencontainers.image.base.digest": base}).(v1.Image)
+	got, err = buildMetadata(annotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.BaseImage == nil || got.BaseImage.Digest != base || got.BaseImage.Name != "" {
+		t.Errorf("buildMetadata() of the annotated image has base image %+v, want %s", got.BaseImage, base)
+	}
+	want = []attestation.BuildMetadataLayer{want[0], {Digest: digests[1].String()}}
+	if !reflect.DeepEqual(got.Layers, want) {
+		t.Errorf("buildMetadata() of the image without history has layers %+v, want %+v", got.Layers, want)
+	}
+}
+
+func TestAttestCmdBuildMetadataFromImage(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := writeFile(t, td, string(keys.PrivateBytes), "cosign.key")
+
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	ref, err := name.ParseReference(strings.TrimPrefix(s.URL, "http://") + "/app:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _ := builtImage(t)
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c := AttestCommand{
+		KeyOpts:        options.KeyOpts{KeyRef: keyRef},
+		PredicateType:  options.PredicateSLSA,
+		BuildFromImage: true,
+	}
+	if err := c.Exec(ctx, ref.String()); err == nil || !strings.Contains(err.Error(), "requires --type buildmetadata") {
+		t.Fatalf("Exec() with --type slsaprovenance error = %v", err)
+	}
+	c.PredicateType = options.PredicateCustom
+	if err := c.Exec(ctx, ref.String()); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := atts.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("got %d attestations, want 1", len(all))
+	}
+	payload, err := all[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st struct {
+		in_toto.StatementHeader
+		Predicate attestation.BuildMetadataPredicate `json:"predicate"`
+	}
+	if err := json.Unmarshal(decoded, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.PredicateType != attestation.CosignBuildMetadataV01 || len(st.Predicate.Layers) != 3 || st.Predicate.BaseImage == nil {
+		t.Errorf("statement = %+v, want the build metadata of the image", st)
+	}
+}
@@ -0,0 +1,256 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	cbundle "github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/verify"
+)
+
+// DefaultJournalDir returns .cosign/journal in the user's home directory.
+func DefaultJournalDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding the journal in the home directory: %w", err)
+	}
+	return filepath.Join(home, ".cosign", "journal"), nil
+}
+
+// journalEntry is an attestation whose publication to the transparency log
+// and the registry hasn't completed. Publishing the same statement again
+// resumes it with the same envelope, which the transparency log already has
+// an entry of if it was uploaded, instead of signing it again.
+type journalEntry struct {
+	// Subject is the image attested, by digest, and PayloadSHA256 the hex
+	// digest of the statement that was signed.
+	Subject       string    `json:"subject"`
+	PayloadSHA256 string    `json:"payloadSHA256"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// Envelope is the signed DSSE envelope, and the signer its public key
+	// or certificate.
+	Envelope         []byte                    `json:"envelope"`
+	PublicKey        string                    `json:"publicKey,omitempty"`
+	Certificate      string                    `json:"certificate,omitempty"`
+	Chain            string                    `json:"chain,omitempty"`
+	RFC3161Timestamp *cbundle.RFC3161Timestamp `json:"rfc3161Timestamp,omitempty"`
+	// Bundle is the transparency log entry of the envelope, once uploaded.
+	Bundle *cbundle.RekorBundle `json:"bundle,omitempty"`
+	// Pushed are the images the attestation was pushed to.
+	Pushed []string `json:"pushed,omitempty"`
+}
+
+// signer returns the certificate of the signer of e, or its public key if
+// it has none, as uploaded to the transparency log.
+func (e *journalEntry) signer() []byte {
+	if e.Certificate != "" {
+		return []byte(e.Certificate)
+	}
+	return []byte(e.PublicKey)
+}
+
+// unpushed returns the entities that e wasn't pushed to.
+func (e *journalEntry) unpushed(entities []oci.SignedEntity) ([]oci.SignedEntity, error) {
+	var left []oci.SignedEntity
+	for _, se := range entities {
+		h, err := se.Digest()
+		if err != nil {
+			return nil, err
+		}
+		pushed := false
+		for _, p := range e.Pushed {
+			pushed = pushed || p == h.String()
+		}
+		if !pushed {
+			left = append(left, se)
+		}
+	}
+	return left, nil
+}
+
+// journal is a directory of journal entries, named by the digest of their
+// subject and statement.
+type journal struct {
+	dir string
+}
+
+func (j *journal) path(digest name.Digest, payload []byte) string {
+	h := sha256.New()
+	h.Write([]byte(digest.String()))
+	h.Write([]byte{0})
+	h.Write(withoutTimestamp(payload))
+	return filepath.Join(j.dir, hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+// withoutTimestamp returns the statement payload without the time it was
+// generated at, that predicates of the custom type have, so that attesting
+// the same predicate again finds the entry of the first attempt.
+func withoutTimestamp(payload []byte) []byte {
+	var statement map[string]interface{}
+	if err := json.Unmarshal(payload, &statement); err != nil || statement["predicateType"] != attestation.CosignCustomProvenanceV01 {
+		return payload
+	}
+	predicate, ok := statement["predicate"].(map[string]interface{})
+	if !ok {
+		return payload
+	}
+	delete(predicate, "Timestamp")
+	b, err := json.Marshal(statement)
+	if err != nil {
+		return payload
+	}
+	return b
+}
+
+// resume returns the entry of the statement payload about digest signed by
+// sv, or nil if its publication isn't pending or it was signed by another
+// signer.
+func (j *journal) resume(digest name.Digest, payload []byte, sv *sign.SignerVerifier) (*journalEntry, error) {
+	if j == nil {
+		return nil, nil
+	}
+	b, err := os.ReadFile(j.path(digest, payload))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading the journal: %w", err)
+	}
+	e := &journalEntry{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, fmt.Errorf("parsing the journal entry of %s: %w", digest, err)
+	}
+	same, err := sameSigner(e, sv)
+	if err != nil || !same {
+		return nil, err
+	}
+	return e, nil
+}
+
+// sameSigner reports whether e was signed with the key of sv or, for
+// short-lived certificates, by the same identity.
+func sameSigner(e *journalEntry, sv *sign.SignerVerifier) (bool, error) {
+	if e.Certificate != "" && sv.Cert != nil {
+		journaled, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(e.Certificate))
+		if err != nil || len(journaled) == 0 {
+			return false, fmt.Errorf("parsing the journaled certificate: %w", err)
+		}
+		current, err := cryptoutils.UnmarshalCertificatesFromPEM(sv.Cert)
+		if err != nil || len(current) == 0 {
+			return false, fmt.Errorf("parsing the signing certificate: %w", err)
+		}
+		return reflect.DeepEqual(verify.SubjectAlternativeNames(journaled[0]), verify.SubjectAlternativeNames(current[0])) &&
+			verify.OIDCIssuer(journaled[0]) == verify.OIDCIssuer(current[0]), nil
+	}
+	pub, err := publicKeyPEM(sv)
+	if err != nil {
+		return false, err
+	}
+	return e.PublicKey != "" && bytes.Equal([]byte(e.PublicKey), pub), nil
+}
+
+func publicKeyPEM(sv *sign.SignerVerifier) ([]byte, error) {
+	pub, err := sv.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return cryptoutils.MarshalPublicKeyToPEM(pub)
+}
+
+// newEntry returns the entry of the envelope of the statement payload about
+// digest, signed by sv.
+func newEntry(digest name.Digest, payload, envelope []byte, sv *sign.SignerVerifier) (*journalEntry, error) {
+	pub, err := publicKeyPEM(sv)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(payload)
+	return &journalEntry{
+		Subject:       digest.String(),
+		PayloadSHA256: hex.EncodeToString(h[:]),
+		CreatedAt:     time.Now().UTC(),
+		Envelope:      envelope,
+		PublicKey:     string(pub),
+		Certificate:   string(sv.Cert),
+		Chain:         string(sv.Chain),
+	}, nil
+}
+
+// save writes e, the entry of the statement payload about digest. Failing
+// to write it only warns, since it is only needed to resume a publication
+// that fails.
+func (j *journal) save(ctx context.Context, digest name.Digest, payload []byte, e *journalEntry) {
+	if j == nil {
+		return
+	}
+	if err := j.write(j.path(digest, payload), e); err != nil {
+		ui.Warnf(ctx, "Journaling the attestation of %s: %v", digest, err)
+	}
+}
+
+func (j *journal) write(p string, e *journalEntry) error {
+	if err := os.MkdirAll(j.dir, 0o700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The entry is written to a temporary file first so that an
+	// interrupted write doesn't corrupt the entry it replaces.
+	tmp, err := os.CreateTemp(j.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// remove removes the entry of the statement payload about digest, once its
+// publication completed.
+func (j *journal) remove(ctx context.Context, digest name.Digest, payload []byte) {
+	if j == nil {
+		return
+	}
+	if err := os.Remove(j.path(digest, payload)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		ui.Warnf(ctx, "Removing the journal entry of the attestation of %s: %v", digest, err)
+	}
+}
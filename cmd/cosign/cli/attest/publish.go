@@ -0,0 +1,80 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	cbundle "github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// retryBackoff is how long a failed publication step waits before it is
+// first retried, doubled for each retry after.
+var retryBackoff = time.Second
+
+// retry runs step, the named step of the publication, retrying it up to
+// c.PublishRetries times while it fails.
+func (c *AttestCommand) retry(ctx context.Context, name string, step func() error) error {
+	wait := retryBackoff
+	for i := 0; ; i++ {
+		err := step()
+		if err == nil || i >= c.PublishRetries {
+			return err
+		}
+		ui.Warnf(ctx, "%s failed, retrying in %s: %v", name, wait, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// publicationError returns err of a publication that failed, reporting what
+// of it completed: the transparency log entry if bundle is set, the images
+// pushed, and those left, which attesting the statement again resumes if
+// the attestation is journaled.
+func publicationError(bundle *cbundle.RekorBundle, pushed []string, left []oci.SignedEntity, journaled bool, err error) error {
+	var state []string
+	if bundle != nil {
+		state = append(state, fmt.Sprintf("the attestation is in the transparency log at index %d", bundle.Payload.LogIndex))
+	}
+	if len(pushed) > 0 {
+		state = append(state, "pushed to "+strings.Join(pushed, ", "))
+	}
+	if len(state) == 0 {
+		state = append(state, "nothing was published")
+	}
+	var digests []string
+	for _, se := range left {
+		if h, err := se.Digest(); err == nil {
+			digests = append(digests, h.String())
+		}
+	}
+	if len(digests) > 0 && (bundle != nil || len(pushed) > 0) {
+		state = append(state, "not pushed to "+strings.Join(digests, ", "))
+	}
+	if journaled {
+		state = append(state, "attesting the same statement again resumes the publication")
+	}
+	return fmt.Errorf("%w; %s", err, strings.Join(state, ", "))
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/pkg/plugin"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// sbomGeneratorPrefix is the prefix of the executables that implement SBOM
+// generator plugins, e.g. cosign-sbom-syft.
+const sbomGeneratorPrefix = "cosign-sbom-"
+
+// generateSBOM runs the SBOM generator plugin against digest and returns the
+// SBOM it wrote to standard output.
+//
+// Plugins are invoked as `cosign-sbom-<name> <format> <image@digest>`, where
+// format is spdxjson or cyclonedx. A plugin pulls the image itself, and must
+// exit non-zero if it can't produce an SBOM in the requested format.
+func generateSBOM(ctx context.Context, generator, predicateType string, digest name.Digest) ([]byte, error) {
+	var format string
+	switch predicateType {
+	case options.PredicateSPDX, options.PredicateSPDXJSON:
+		format = options.PredicateSPDXJSON
+	case options.PredicateCycloneDX:
+		format = options.PredicateCycloneDX
+	default:
+		return nil, fmt.Errorf("--sbom-from-image requires --type spdx, spdxjson or cyclonedx, got %s", predicateType)
+	}
+
+	if generator == "" {
+		generator = env.Getenv(env.VariableSBOMGenerator)
+	}
+	if generator == "" {
+		return nil, fmt.Errorf("--sbom-from-image requires an SBOM generator, set --sbom-generator or %s", env.VariableSBOMGenerator)
+	}
+	path, err := plugin.Find(sbomGeneratorPrefix, generator)
+	if err != nil {
+		return nil, err
+	}
+	ui.Infof(ctx, "Generating %s SBOM of %s with %s", format, digest, path)
+
+	sbom, err := plugin.Output(ctx, path, format, digest.String())
+	if err != nil {
+		return nil, fmt.Errorf("generating SBOM: %w", err)
+	}
+	return sbom, nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// writePlugin installs a fake cosign-sbom-fake plugin on the PATH that runs
+// script, and returns the directory holding it.
+func writePlugin(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("SBOM generator plugin script requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "cosign-sbom-fake"), []byte("#!/bin/sh\n"+script+"\n"), 0o700); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return bin
+}
+
+func TestGenerateSBOM(t *testing.T) {
+	bin := writePlugin(t, `if [ "$1" = "fail" ]; then exit 1; fi
+echo "{\"format\":\"$1\",\"image\":\"$2\"}"`)
+	if err := os.WriteFile(filepath.Join(bin, "cosign-sbom-silent"), []byte("#!/bin/sh\n"), 0o700); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	digest, err := name.NewDigest("example.com/app@sha256:" + strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		generator     string
+		envGenerator  string
+		predicateType string
+		wantFormat    string
+		wantErr       string
+	}{{
+		name:          "spdx",
+		generator:     "fake",
+		predicateType: options.PredicateSPDX,
+		wantFormat:    options.PredicateSPDXJSON,
+	}, {
+		name:          "cyclonedx",
+		generator:     "fake",
+		predicateType: options.PredicateCycloneDX,
+		wantFormat:    options.PredicateCycloneDX,
+	}, {
+		name:          "generator from environment",
+		envGenerator:  "fake",
+		predicateType: options.PredicateSPDXJSON,
+		wantFormat:    options.PredicateSPDXJSON,
+	}, {
+		name:          "generator path",
+		generator:     filepath.Join(bin, "cosign-sbom-fake"),
+		predicateType: options.PredicateSPDXJSON,
+		wantFormat:    options.PredicateSPDXJSON,
+	}, {
+		name:          "not an SBOM type",
+		generator:     "fake",
+		predicateType: options.PredicateSLSA,
+		wantErr:       "requires --type spdx, spdxjson or cyclonedx",
+	}, {
+		name:          "no generator",
+		predicateType: options.PredicateSPDXJSON,
+		wantErr:       "requires an SBOM generator",
+	}, {
+		name:          "plugin not found",
+		generator:     "missing",
+		predicateType: options.PredicateSPDXJSON,
+		wantErr:       "plugin cosign-sbom-missing not found",
+	}, {
+		name:          "no output",
+		generator:     "silent",
+		predicateType: options.PredicateSPDXJSON,
+		wantErr:       "produced no output",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(env.VariableSBOMGenerator.String(), tt.envGenerator)
+			got, err := generateSBOM(context.Background(), tt.generator, tt.predicateType, digest)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("generateSBOM() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateSBOM() unexpected error: %v", err)
+			}
+			want := fmt.Sprintf(`{"format":"%s","image":"%s"}`, tt.wantFormat, digest)
+			if strings.TrimSpace(string(got)) != want {
+				t.Errorf("generateSBOM() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestAttestCmdSBOMFromImage(t *testing.T) {
+	writePlugin(t, `echo '{"bomFormat":"CycloneDX","specVersion":"1.4"}'`)
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := writeFile(t, td, string(keys.PrivateBytes), "cosign.key")
+
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	ref, err := name.ParseReference(strings.TrimPrefix(s.URL, "http://") + "/app:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c := AttestCommand{
+		KeyOpts:       options.KeyOpts{KeyRef: keyRef},
+		PredicatePath: "predicate.json",
+		PredicateType: options.PredicateCycloneDX,
+		SBOMFromImage: true,
+		SBOMGenerator: "fake",
+	}
+	if err := c.Exec(ctx, ref.String()); err == nil || !strings.Contains(err.Error(), "only one of --predicate and --sbom-from-image") {
+		t.Fatalf("Exec() with --predicate error = %v", err)
+	}
+
+	c.PredicatePath = ""
+	if err := c.Exec(ctx, ref.String()); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := atts.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("got %d attestations, want 1", len(all))
+	}
+	payload, err := all[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st in_toto.Statement
+	if err := json.Unmarshal(decoded, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.PredicateType != in_toto.PredicateCycloneDX {
+		t.Errorf("predicate type = %s, want %s", st.PredicateType, in_toto.PredicateCycloneDX)
+	}
+	if p, _ := st.Predicate.(map[string]interface{}); p["bomFormat"] != "CycloneDX" {
+		t.Errorf("predicate = %v, want the generated SBOM", st.Predicate)
+	}
+}
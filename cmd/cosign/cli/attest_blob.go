@@ -15,6 +15,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
@@ -42,10 +44,23 @@ func AttestBlob() *cobra.Command {
   cosign attest-blob --predicate <FILE> --type <TYPE> --key gcpkms://projects/[PROJECT]/locations/global/keyRings/[KEYRING]/cryptoKeys/[KEY]/versions/[VERSION] <BLOB>
 
   # attach an attestation to a blob with a key pair stored in Hashicorp Vault
-  cosign attest-blob --predicate <FILE> --type <TYPE> --key hashivault://[KEY] <BLOB>`,
+  cosign attest-blob --predicate <FILE> --type <TYPE> --key hashivault://[KEY] <BLOB>
+
+  # attest the binaries of a release keyless with one certificate, writing dist/attestations/<name>.sig and <name>.bundle,
+  # and their index dist/attestations/index.json
+  cosign attest-blob --yes --predicate <FILE> --type <TYPE> --output-dir dist/attestations --output-certificate dist/attestations/release.pem 'dist/*.tar.gz'
+
+  # attest a blob, naming the subject of the statement by its BLAKE3 digest
+  cosign attest-blob --predicate <FILE> --type <TYPE> --key cosign.key --digest-algorithm blake3 <BLOB>`,
 
-		Args:             cobra.ExactArgs(1),
+		Args:             cobra.MinimumNArgs(1),
 		PersistentPreRun: options.BindViper,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if o.OutputDir.Dir == "" && len(args) != 1 {
+				return fmt.Errorf("accepts 1 arg, received %d, attesting several blobs requires --output-dir", len(args))
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			oidcClientSecret, err := o.OIDC.ClientSecret()
 			if err != nil {
@@ -59,12 +74,16 @@ func AttestBlob() *cobra.Command {
 				FulcioURL:                o.Fulcio.URL,
 				IDToken:                  o.Fulcio.IdentityToken,
 				InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                     o.Fulcio.ACME,
 				RekorURL:                 o.Rekor.URL,
 				OIDCIssuer:               o.OIDC.Issuer,
 				OIDCClientID:             o.OIDC.ClientID,
 				OIDCClientSecret:         oidcClientSecret,
 				OIDCRedirectURL:          o.OIDC.RedirectURL,
 				OIDCProvider:             o.OIDC.Provider,
+				OIDCTokenFile:            o.OIDC.TokenFile,
+				OIDCAudience:             o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
 				SkipConfirmation:         o.SkipConfirmation,
 				TSAServerURL:             o.TSAServerURL,
 				RFC3161TimestampPath:     o.RFC3161TimestampPath,
@@ -75,14 +94,22 @@ func AttestBlob() *cobra.Command {
 				CertPath:          o.Cert,
 				CertChainPath:     o.CertChain,
 				ArtifactHash:      o.Hash,
+				DigestAlgorithm:   o.Digest.Algorithm,
 				TlogUpload:        o.TlogUpload,
 				PredicateType:     o.Predicate.Type,
+				PredicateSchemas:  o.Predicate.Schemas,
+				PredicateSchema:   o.Predicate.Schema,
 				PredicatePath:     o.Predicate.Path,
 				OutputSignature:   o.OutputSignature,
 				OutputAttestation: o.OutputAttestation,
 				OutputCertificate: o.OutputCertificate,
+				OutputDir:         o.OutputDir.Dir,
+				OutputName:        o.OutputDir.Name,
 				Timeout:           ro.Timeout,
 			}
+			if o.OutputDir.Dir != "" {
+				return v.ExecBlobs(cmd.Context(), args)
+			}
 			return v.Exec(cmd.Context(), args[0])
 		},
 	}
@@ -0,0 +1,256 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/kubernetes"
+)
+
+func Audit() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Provides utilities for auditing the signing coverage of deployed images",
+	}
+
+	cmd.AddCommand(auditCluster())
+	cmd.AddCommand(auditRegistry())
+
+	return cmd
+}
+
+func auditCluster() *cobra.Command {
+	o := &options.AuditClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Verify the images running in a Kubernetes cluster against a policy and report their compliance",
+		Long: `List the images that the containers of the pods of a Kubernetes cluster
+currently run, by the digests their container runtimes report, verify each of
+them in parallel with the key or certificate identity of the entry of the
+policy that matches its repository, and write a compliance report.
+
+The policy is read as by cosign proxy --policy and cosign verify
+--image-policy. Images that no entry matches, that fail to verify or whose
+digest their container runtime doesn't report aren't compliant, and the
+command fails if there are any, after writing the report.
+
+The cluster is the current context of the kubeconfig, or the cluster cosign
+runs in. Listing pods across namespaces needs the permission to list the pods
+of each audited namespace.`,
+		Example: `  cosign audit cluster --policy policy.json
+
+  # audit two namespaces and write a Markdown report, e.g. for a GitHub Actions job summary
+  cosign audit cluster --policy policy.json --namespace prod --namespace payments --output markdown >> "$GITHUB_STEP_SUMMARY"`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return AuditClusterCmd(cmd.Context(), *o, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// AuditClusterCmd verifies the images running in the cluster with the
+// policy of o and writes the compliance report to o.OutputFile, or out.
+func AuditClusterCmd(ctx context.Context, o options.AuditClusterOptions, out io.Writer) error {
+	if o.Output != "json" && o.Output != "markdown" {
+		return fmt.Errorf("unsupported output format %q, expected json or markdown", o.Output)
+	}
+	p, err := proxy.ReadPolicy(o.Policy)
+	if err != nil {
+		return err
+	}
+	images, err := kubernetes.ClusterImages(ctx, o.Namespaces, o.Registry.NameOptions()...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Auditing %d images running in the cluster\n", len(images))
+
+	report := auditImages(ctx, o, p, images)
+	return writeAuditReport(report, o, out)
+}
+
+func auditRegistry() *cobra.Command {
+	o := &options.AuditRegistryOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Verify the images pushed to registries against a policy as their push notifications arrive",
+		Long: `Serve a webhook for the push notifications of registries, verify each newly
+pushed image with the key or certificate identity of the entry of the policy
+that matches its repository, and record the results in a database, so that the
+signing coverage of large registries is kept current without verifying every
+image again.
+
+The notifications of Distribution, the Docker registry, and the
+PUSH_ARTIFACT events of Harbor webhook policies are accepted. The policy is
+read as by cosign proxy --policy and cosign verify --image-policy.
+
+The results database is a file of JSON lines, one for each verification of an
+image, and the last line of an image is its current result. Images it records
+are not verified again when they are pushed with another tag, but images that
+didn't pass are verified again when a signature, attestation or SBOM is pushed
+for them by cosign, since images are usually signed after they are pushed.
+
+Notifications are answered once their images are queued, and refused, for the
+registry to retry them, while too many images are waiting to be verified.`,
+		Example: `  cosign audit registry --policy policy.json --results results.jsonl
+
+  # require the Authorization header configured in the registry
+  cosign audit registry --policy policy.json --results results.jsonl --address :8080 --authorization-file webhook-auth
+
+  # notify the webhook of the pushes to Distribution, in its config.yml
+  notifications:
+    endpoints:
+      - name: cosign
+        url: http://cosign-audit:8080/
+        headers:
+          Authorization: [<AUTHORIZATION>]
+
+  # list the current results of the images that aren't compliant
+  jq -s 'group_by(.name) | map(last) | map(select(.status != "passed"))' results.jsonl`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return AuditRegistryCmd(cmd.Context(), *o)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// AuditRegistryCmd serves the webhook of the registry notifications on
+// o.Address, and verifies the images they push, until ctx is done or cosign
+// is interrupted.
+func AuditRegistryCmd(ctx context.Context, o options.AuditRegistryOptions) error {
+	p, err := proxy.ReadPolicy(o.Policy)
+	if err != nil {
+		return err
+	}
+	var authorization string
+	if o.AuthorizationFile != "" {
+		b, err := os.ReadFile(o.AuthorizationFile)
+		if err != nil {
+			return fmt.Errorf("reading authorization file: %w", err)
+		}
+		authorization = strings.TrimSpace(string(b))
+	}
+	results, err := verify.OpenAuditResults(o.Results)
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	v := &verify.VerifyCommand{
+		RegistryOptions: o.Registry,
+		CheckClaims:     true,
+		RekorURL:        o.Rekor.URL,
+		NameOptions:     o.Registry.NameOptions(),
+	}
+	v.Route = routeImagePolicy(p, v)
+	auditor := verify.NewRegistryAuditor(v, results)
+	auditor.Authorization = authorization
+	auditor.NameOptions = o.Registry.NameOptions()
+
+	l, err := net.Listen("tcp", o.Address)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           auditor,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		auditor.Run(ctx, budget.Workers(o.Workers, runtime.GOMAXPROCS(0)))
+	}()
+
+	ui.Infof(ctx, "Receiving registry notifications on %s", l.Addr())
+	err = srv.Serve(l)
+	// The workers stop with the server, after the images they are verifying.
+	stop()
+	<-done
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// auditImages verifies images with the entries of p that match them.
+func auditImages(ctx context.Context, o options.AuditClusterOptions, p *proxy.Policy, images []kubernetes.RunningImage) *verify.ClusterAuditReport {
+	v := &verify.VerifyCommand{
+		RegistryOptions: o.Registry,
+		CheckClaims:     true,
+		RekorURL:        o.Rekor.URL,
+		NameOptions:     o.Registry.NameOptions(),
+	}
+	v.Route = routeImagePolicy(p, v)
+	return v.Audit(ctx, images, o.Namespaces, o.Workers)
+}
+
+func writeAuditReport(report *verify.ClusterAuditReport, o options.AuditClusterOptions, out io.Writer) error {
+	var b bytes.Buffer
+	if err := report.Write(&b, o.Output); err != nil {
+		return err
+	}
+	if o.OutputFile == "" {
+		if _, err := out.Write(b.Bytes()); err != nil {
+			return err
+		}
+	} else {
+		if err := os.WriteFile(o.OutputFile, b.Bytes(), 0600); err != nil {
+			return fmt.Errorf("writing audit report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Audit report written to %s\n", o.OutputFile)
+	}
+	if n := report.Failed(); n > 0 {
+		return fmt.Errorf("%d of %d images running in the cluster aren't compliant", n, report.Images)
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/internal/pkg/batch"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+func Backfill() *cobra.Command {
+	o := &options.BackfillOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Sign the existing images of repositories or registries",
+		Long: `Sign the images that exist in repositories, or in every repository of
+registries with --all-repositories, such as those pushed before signing was
+adopted.
+
+Each tag is resolved to the digest it points to, and each digest is signed
+once, with the key or keyless identity given. Images that already have a
+signature are skipped unless --include-signed is given, so an interrupted
+backfill can be re-run, or resumed without listing the repositories again
+with --state-file and --resume. The signed payload of each signature records
+when it was backfilled in the dev.sigstore.cosign/backfilled annotation, and
+--note in the dev.sigstore.cosign/backfill-note annotation.
+
+Images that fail to sign are reported and the others are still signed. With
+--report, the images found and what was done with each are written to a JSON
+file.`,
+		Example: `  # sign the unsigned images of a repository with a key
+  cosign backfill --key cosign.key --note "signed on adoption of cosign" example.com/app
+
+  # list what would be signed in every repository of a registry
+  cosign backfill --all-repositories --dry-run --report backfill.json example.com
+
+  # sign the release tags of the team repositories keyless, resumably
+  cosign backfill --all-repositories --repository-regexp '^team/' --tag-regexp '^v[0-9]' \
+    --state-file backfill.state --resume --report backfill.json example.com`,
+		Args:             cobra.MinimumNArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oidcClientSecret, err := o.OIDC.ClientSecret()
+			if err != nil {
+				return err
+			}
+			ko := options.KeyOpts{
+				KeyRef:                   o.Key,
+				PassFunc:                 generate.GetPass,
+				Sk:                       o.SecurityKey.Use,
+				Slot:                     o.SecurityKey.Slot,
+				FulcioURL:                o.Fulcio.URL,
+				IDToken:                  o.Fulcio.IdentityToken,
+				InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                     o.Fulcio.ACME,
+				RekorURL:                 o.Rekor.URL,
+				OIDCIssuer:               o.OIDC.Issuer,
+				OIDCClientID:             o.OIDC.ClientID,
+				OIDCClientSecret:         oidcClientSecret,
+				OIDCRedirectURL:          o.OIDC.RedirectURL,
+				OIDCProvider:             o.OIDC.Provider,
+				OIDCTokenFile:            o.OIDC.TokenFile,
+				OIDCAudience:             o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
+				SkipConfirmation:         o.SkipConfirmation,
+				TSAServerURL:             o.TSAServerURL,
+			}
+			return BackfillCmd(cmd.Context(), ko, *o, args, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// The statuses of the images of a BackfillReport.
+const (
+	BackfillSigned        = "signed"
+	BackfillWouldSign     = "would-sign"
+	BackfillAlreadySigned = "already-signed"
+	BackfillPreviousRun   = "previous-run"
+	BackfillFailed        = "failed"
+)
+
+// BackfillReport is the report of a `cosign backfill`, written with --report.
+type BackfillReport struct {
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt time.Time       `json:"finishedAt"`
+	DryRun     bool            `json:"dryRun,omitempty"`
+	Note       string          `json:"note,omitempty"`
+	Counts     map[string]int  `json:"counts"`
+	Images     []BackfillImage `json:"images"`
+}
+
+// BackfillImage is an image found by a `cosign backfill`, and what was done
+// with it.
+type BackfillImage struct {
+	Image  string   `json:"image"`
+	Tags   []string `json:"tags"`
+	Status string   `json:"status"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// BackfillCmd signs the images of the repositories, or of the registries
+// with o.AllRepositories, named by targets, with the signer of ko. It writes
+// a summary to out and the report to o.Report. It returns an error if any
+// image failed to sign, once the others are signed.
+func BackfillCmd(ctx context.Context, ko options.KeyOpts, o options.BackfillOptions, targets []string, out io.Writer) (err error) {
+	if options.NOf(ko.KeyRef, ko.Sk) > 1 {
+		return &options.KeyParseError{}
+	}
+	var repoRe, tagRe *regexp.Regexp
+	if o.RepositoryRegexp != "" {
+		if !o.AllRepositories {
+			return errors.New("--repository-regexp requires --all-repositories")
+		}
+		if repoRe, err = regexp.Compile(o.RepositoryRegexp); err != nil {
+			return fmt.Errorf("invalid --repository-regexp: %w", err)
+		}
+	}
+	if o.TagRegexp != "" {
+		if tagRe, err = regexp.Compile(o.TagRegexp); err != nil {
+			return fmt.Errorf("invalid --tag-regexp: %w", err)
+		}
+	}
+
+	ociremoteOpts, err := o.Registry.ClientOpts(ctx)
+	if err != nil {
+		return err
+	}
+	remoteOpts := o.Registry.GetRegistryClientOpts(ctx)
+	report := &BackfillReport{StartedAt: time.Now().UTC(), DryRun: o.DryRun, Note: o.Note, Counts: map[string]int{}}
+	defer func() {
+		report.FinishedAt = time.Now().UTC()
+		if werr := report.write(o.Report); werr != nil && err == nil {
+			err = werr
+		}
+	}()
+
+	var repos []name.Repository
+	for _, t := range targets {
+		if !o.AllRepositories {
+			repo, err := name.NewRepository(t, o.Registry.NameOptions()...)
+			if err != nil {
+				return err
+			}
+			repos = append(repos, repo)
+			continue
+		}
+		reg, err := name.NewRegistry(t, o.Registry.NameOptions()...)
+		if err != nil {
+			return err
+		}
+		catalog, err := remote.Catalog(ctx, reg, remoteOpts...)
+		if err != nil {
+			return fmt.Errorf("listing the repositories of %s: %w", reg, err)
+		}
+		for _, r := range catalog {
+			if repoRe == nil || repoRe.MatchString(r) {
+				repos = append(repos, reg.Repo(r))
+			}
+		}
+	}
+
+	images, err := backfillImages(ctx, repos, tagRe, o.Registry.RefOpts.TagPrefix, remoteOpts)
+	if err != nil {
+		return err
+	}
+
+	progress := batch.Idempotent()
+	if err := progress.UseStateFile(o.Batch.StateFile, o.Batch.Resume); err != nil {
+		return err
+	}
+	defer func() {
+		err = progress.Finish(ctx, err)
+	}()
+
+	annotations := map[string]interface{}{cosign.BackfilledAnnotation: report.StartedAt.Format(time.RFC3339)}
+	if o.Note != "" {
+		annotations[cosign.BackfillNoteAnnotation] = o.Note
+	}
+	signOpts := options.SignOptions{Upload: true, TlogUpload: o.TlogUpload, Registry: o.Registry}
+	var sv *sign.SignerVerifier
+	defer func() {
+		if sv != nil {
+			sv.Close()
+		}
+	}()
+
+	for i, img := range images {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ui.Infof(ctx, "[%d/%d] %s", i+1, len(images), img.Image)
+		digest, err := oci.ParseDigestReference(img.Image, o.Registry.NameOptions()...)
+		if err != nil {
+			return err
+		}
+		if progress.Skip(img.Image) {
+			img.Status = BackfillPreviousRun
+			report.add(img)
+			continue
+		}
+		if !o.IncludeSigned {
+			signed, err := hasSignatures(digest, ociremoteOpts)
+			if err != nil {
+				return err
+			}
+			if signed {
+				img.Status = BackfillAlreadySigned
+				report.add(img)
+				if err := progress.Unchanged(img.Image); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if o.DryRun {
+			img.Status = BackfillWouldSign
+			report.add(img)
+			continue
+		}
+
+		// The signer is only created once there is an image to sign, so
+		// that a backfill with nothing to do doesn't ask for an identity.
+		if sv == nil {
+			if sv, err = sign.SignerFromKeyOpts(ctx, o.Cert, o.CertChain, ko); err != nil {
+				return fmt.Errorf("getting signer: %w", err)
+			}
+		}
+		progress.Start(img.Image)
+		if err := sign.SignDigest(ctx, digest, ko, signOpts, annotations, sv); err != nil {
+			ui.Warnf(ctx, "signing %s: %v", img.Image, err)
+			img.Status, img.Error = BackfillFailed, err.Error()
+			report.add(img)
+			continue
+		}
+		img.Status = BackfillSigned
+		report.add(img)
+		if err := progress.Done(img.Image); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Found %d images: %d signed, %d to sign, %d already signed, %d signed by a previous run, %d failed\n",
+		len(images), report.Counts[BackfillSigned], report.Counts[BackfillWouldSign], report.Counts[BackfillAlreadySigned],
+		report.Counts[BackfillPreviousRun], report.Counts[BackfillFailed])
+	if n := report.Counts[BackfillFailed]; n > 0 {
+		return fmt.Errorf("failed to sign %d of %d images", n, len(images))
+	}
+	return nil
+}
+
+// backfillImages returns the images that the tags of repos matching tagRe
+// point to, each once with all of its tags, skipping the tags of signatures,
+// attestations and SBOMs.
+func backfillImages(ctx context.Context, repos []name.Repository, tagRe *regexp.Regexp, prefix string, remoteOpts []remote.Option) ([]BackfillImage, error) {
+	var images []BackfillImage
+	for _, repo := range repos {
+		tags, err := remote.List(repo, remoteOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("listing the tags of %s: %w", repo, err)
+		}
+		found := map[string]int{}
+		for _, tag := range tags {
+			if _, _, ok := parseAttachmentTag(tag, prefix); ok || isSBOMTag(tag, prefix) {
+				continue
+			}
+			if tagRe != nil && !tagRe.MatchString(tag) {
+				continue
+			}
+			desc, err := remote.Head(repo.Tag(tag), remoteOpts...)
+			if err != nil {
+				return nil, fmt.Errorf("resolving %s: %w", repo.Tag(tag), err)
+			}
+			image := repo.Digest(desc.Digest.String()).String()
+			if i, ok := found[image]; ok {
+				images[i].Tags = append(images[i].Tags, tag)
+				continue
+			}
+			found[image] = len(images)
+			images = append(images, BackfillImage{Image: image, Tags: []string{tag}})
+		}
+		if len(tags) == 0 {
+			ui.Infof(ctx, "%s has no tags", repo)
+		}
+	}
+	return images, nil
+}
+
+// isSBOMTag reports whether tag is the [prefix]sha256-<hex>.sbom tag of an
+// attached SBOM.
+func isSBOMTag(tag, prefix string) bool {
+	base := strings.TrimPrefix(tag, prefix)
+	return strings.HasPrefix(base, "sha256-") && strings.HasSuffix(base, "."+ociremote.SBOMTagSuffix)
+}
+
+// hasSignatures reports whether digest has a signature tag.
+func hasSignatures(digest name.Digest, ociremoteOpts []ociremote.Option) (bool, error) {
+	tag, err := ociremote.SignatureTag(digest, ociremoteOpts...)
+	if err != nil {
+		return false, err
+	}
+	return ociremote.NewDeleter(ociremoteOpts...).TagExists(tag)
+}
+
+func (r *BackfillReport) add(img BackfillImage) {
+	r.Images = append(r.Images, img)
+	r.Counts[img.Status]++
+}
+
+// write writes the report to path, unless it is empty.
+func (r *BackfillReport) write(path string) error {
+	if path == "" {
+		return nil
+	}
+	if r.Images == nil {
+		r.Images = []BackfillImage{}
+	}
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Clean(path), append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+func TestBackfillCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := filepath.Join(td, "cosign.key")
+	if err := os.WriteFile(keyRef, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ko := options.KeyOpts{KeyRef: keyRef, SkipConfirmation: true}
+
+	// push pushes a random image to the tags of repo, and returns its
+	// digest.
+	push := func(repo string, tags ...string) name.Digest {
+		t.Helper()
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tag := range tags {
+			ref, err := name.NewTag(host + "/" + repo + ":" + tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := remote.Write(ref, img); err != nil {
+				t.Fatal(err)
+			}
+		}
+		d, err := name.NewDigest(host + "/" + repo + "@" + h.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	release := push("team/app", "v1", "latest")
+	dev := push("team/app", "dev")
+	signed := push("team/app", "v0")
+	other := push("other/app", "v1")
+
+	sign := func(o options.BackfillOptions, targets ...string) (BackfillReport, error) {
+		t.Helper()
+		o.Report = filepath.Join(t.TempDir(), "report.json")
+		err := BackfillCmd(ctx, ko, o, targets, io.Discard)
+		b, rerr := os.ReadFile(o.Report)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		r := BackfillReport{}
+		if jerr := json.Unmarshal(b, &r); jerr != nil {
+			t.Fatalf("report %q: %v", b, jerr)
+		}
+		return r, err
+	}
+	statuses := func(r BackfillReport) map[string]string {
+		got := map[string]string{}
+		for _, img := range r.Images {
+			got[img.Image] = img.Status
+		}
+		return got
+	}
+
+	// Sign one image, so that it is skipped by the backfill.
+	if r, err := sign(options.BackfillOptions{TagRegexp: "^v0$", Note: "earlier"}, host+"/team/app"); err != nil || r.Counts[BackfillSigned] != 1 {
+		t.Fatalf("BackfillCmd() of v0 = %v, %+v", err, r)
+	}
+
+	r, err := sign(options.BackfillOptions{AllRepositories: true, RepositoryRegexp: "^team/", DryRun: true}, host)
+	if err != nil {
+		t.Fatalf("BackfillCmd() dry run = %v", err)
+	}
+	want := map[string]string{release.String(): BackfillWouldSign, dev.String(): BackfillWouldSign, signed.String(): BackfillAlreadySigned}
+	if got := statuses(r); !mapsEqual(got, want) {
+		t.Errorf("dry run statuses = %v, want %v", got, want)
+	}
+	for _, img := range r.Images {
+		if img.Image == release.String() && strings.Join(img.Tags, ",") != "latest,v1" {
+			t.Errorf("tags of %s = %v, want latest and v1", img.Image, img.Tags)
+		}
+	}
+	if has, err := hasSignatures(release, nil); err != nil || has {
+		t.Errorf("hasSignatures() after a dry run = %v, %v", has, err)
+	}
+
+	stateFile := filepath.Join(td, "backfill.state")
+	r, err = sign(options.BackfillOptions{AllRepositories: true, Note: "adoption", Batch: options.BatchOptions{StateFile: stateFile}}, host)
+	if err != nil {
+		t.Fatalf("BackfillCmd() = %v", err)
+	}
+	want = map[string]string{release.String(): BackfillSigned, dev.String(): BackfillSigned, signed.String(): BackfillAlreadySigned, other.String(): BackfillSigned}
+	if got := statuses(r); !mapsEqual(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+
+	// The signed payloads record the backfill.
+	sigTag, err := ociremote.SignatureTag(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := ociremote.Signatures(sigTag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := sigs.Get()
+	if err != nil || len(l) != 1 {
+		t.Fatalf("signatures of %s = %d, %v", release, len(l), err)
+	}
+	p, err := l[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sci := payload.SimpleContainerImage{}
+	if err := json.Unmarshal(p, &sci); err != nil {
+		t.Fatal(err)
+	}
+	if sci.Optional[cosign.BackfillNoteAnnotation] != "adoption" || sci.Optional[cosign.BackfilledAnnotation] == nil {
+		t.Errorf("signed payload annotations = %v, want the backfill", sci.Optional)
+	}
+
+	// Resuming skips the images of the state file without looking them up.
+	r, err = sign(options.BackfillOptions{AllRepositories: true, Batch: options.BatchOptions{StateFile: stateFile, Resume: true}}, host)
+	if err != nil {
+		t.Fatalf("BackfillCmd() resumed = %v", err)
+	}
+	if r.Counts[BackfillPreviousRun] != 4 {
+		t.Errorf("resumed counts = %v, want 4 images of the previous run", r.Counts)
+	}
+
+	var out bytes.Buffer
+	if err := BackfillCmd(ctx, ko, options.BackfillOptions{RepositoryRegexp: "^team/"}, []string{host + "/team/app"}, &out); err == nil || !strings.Contains(err.Error(), "requires --all-repositories") {
+		t.Errorf("BackfillCmd() with --repository-regexp = %v, want an error", err)
+	}
+}
+
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
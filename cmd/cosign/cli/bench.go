@@ -0,0 +1,147 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/bench"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+func Bench() *cobra.Command {
+	o := &options.BenchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure the throughput and latency of signing and verifying against the configured services",
+		Long: `Measure how many signatures per second cosign signs and verifies, and how long
+each takes, with every combination of --parallelism and --payload-size, and
+print the measurements as JSON.
+
+Signing signs a random payload, timestamps the signature with
+--timestamp-server-url and uploads it to the transparency log with
+--tlog-upload, as sign-blob does. Verifying verifies these signatures and looks
+them up in the transparency log, as verify-blob does without a bundle. Without
+--key the signer is certified by Fulcio once, before measuring.
+
+The report has the cosign version, to detect regressions between versions. The
+command fails if any operation failed, after printing the report.`,
+		Example: `  # measure signing and verifying with a key, locally
+  cosign bench --key cosign.key
+
+  # measure a private Sigstore deployment, writing the report to a file
+  cosign bench --yes --fulcio-url https://fulcio.example.com --rekor-url https://rekor.example.com \
+    --tlog-upload --timestamp-server-url https://tsa.example.com/api/v1/timestamp --output-file bench.json
+
+  # measure signing 1000 64KiB payloads, 32 at a time
+  cosign bench --key cosign.key --operation sign --parallelism 32 --payload-size 65536 --count 1000`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if options.NOf(o.Key, o.SecurityKey.Use) > 1 {
+				return &options.KeyParseError{}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oidcClientSecret, err := o.OIDC.ClientSecret()
+			if err != nil {
+				return err
+			}
+			ko := options.KeyOpts{
+				KeyRef:                   o.Key,
+				PassFunc:                 generate.GetPass,
+				Sk:                       o.SecurityKey.Use,
+				Slot:                     o.SecurityKey.Slot,
+				FulcioURL:                o.Fulcio.URL,
+				IDToken:                  o.Fulcio.IdentityToken,
+				InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                     o.Fulcio.ACME,
+				RekorURL:                 o.Rekor.URL,
+				OIDCIssuer:               o.OIDC.Issuer,
+				OIDCClientID:             o.OIDC.ClientID,
+				OIDCClientSecret:         oidcClientSecret,
+				OIDCRedirectURL:          o.OIDC.RedirectURL,
+				OIDCDisableProviders:     o.OIDC.DisableAmbientProviders,
+				OIDCTokenFile:            o.OIDC.TokenFile,
+				OIDCAudience:             o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
+				SkipConfirmation:         o.SkipConfirmation,
+				TSAServerURL:             o.TSAServerURL,
+			}
+			c := bench.Config{
+				Operations:   o.Operations,
+				Parallelism:  o.Parallelism,
+				PayloadSizes: o.PayloadSizes,
+				Count:        o.Count,
+				TSAServerURL: o.TSAServerURL,
+				FulcioURL:    o.Fulcio.URL,
+			}
+			if o.TlogUpload {
+				c.RekorURL = o.Rekor.URL
+			}
+			if err := c.Validate(); err != nil {
+				return err
+			}
+			return BenchCmd(cmd, ko, c, o.Output)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// BenchCmd measures c with the signer of ko, and writes the report to
+// output, or stdout if empty.
+func BenchCmd(cmd *cobra.Command, ko options.KeyOpts, c bench.Config, output string) error {
+	ctx := cmd.Context()
+	sv, err := sign.SignerFromKeyOpts(ctx, "", "", ko)
+	if err != nil {
+		return fmt.Errorf("getting signer: %w", err)
+	}
+	defer sv.Close()
+
+	report, err := bench.Measure(ctx, sv, c)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if output != "" {
+		if err := os.WriteFile(output, b, 0o600); err != nil {
+			return fmt.Errorf("writing the report: %w", err)
+		}
+		ui.Infof(ctx, "Wrote the report to %s", output)
+	} else if _, err := cmd.OutOrStdout().Write(b); err != nil {
+		return err
+	}
+
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d operations failed", failed)
+	}
+	return nil
+}
@@ -0,0 +1,429 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bench measures the throughput and latency of signing and verifying
+// against the configured Sigstore services, for capacity planning and for
+// comparing cosign versions.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/digitorus/timestamp"
+	"github.com/sigstore/rekor/pkg/generated/client"
+	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
+	tsaclient "github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa/client"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// The operations that can be measured.
+const (
+	OperationSign   = "sign"
+	OperationVerify = "verify"
+)
+
+// Config is what to measure.
+type Config struct {
+	// Operations are OperationSign, OperationVerify or both.
+	Operations []string
+	// Every operation is measured with every Parallelism and PayloadSize,
+	// Count times for each.
+	Parallelism  []int
+	PayloadSizes []int
+	Count        int
+	// RekorURL is the transparency log that signatures are uploaded to and
+	// looked up in, none if empty, and TSAServerURL the timestamp authority
+	// that they are timestamped by, none if empty.
+	RekorURL     string
+	TSAServerURL string
+	// FulcioURL is the certificate authority that certified the signer, if
+	// it has a certificate, only reported.
+	FulcioURL string
+}
+
+// Validate returns an error if c measures nothing.
+func (c Config) Validate() error {
+	if len(c.Operations) == 0 {
+		return errors.New("no operation to measure")
+	}
+	for _, op := range c.Operations {
+		if op != OperationSign && op != OperationVerify {
+			return fmt.Errorf("unsupported operation %q, must be %s or %s", op, OperationSign, OperationVerify)
+		}
+	}
+	if len(c.Parallelism) == 0 || len(c.PayloadSizes) == 0 {
+		return errors.New("no parallelism or payload size to measure with")
+	}
+	for _, p := range c.Parallelism {
+		if p < 1 {
+			return fmt.Errorf("parallelism %d must be at least 1", p)
+		}
+	}
+	for _, s := range c.PayloadSizes {
+		if s < 1 {
+			return fmt.Errorf("payload size %d must be at least 1 byte", s)
+		}
+	}
+	if c.Count < 1 {
+		return fmt.Errorf("count %d must be at least 1", c.Count)
+	}
+	return nil
+}
+
+func (c Config) measures(op string) bool {
+	for _, o := range c.Operations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Report is the result of a benchmark.
+type Report struct {
+	// CosignVersion is the version of cosign that measured, to compare
+	// reports between versions.
+	CosignVersion string    `json:"cosignVersion"`
+	StartedAt     time.Time `json:"startedAt"`
+	// Signer is "key", or "certificate" for signers certified by Fulcio.
+	Signer    string    `json:"signer"`
+	Endpoints Endpoints `json:"endpoints"`
+	Runs      []Run     `json:"runs"`
+}
+
+// Endpoints are the services that the operations used.
+type Endpoints struct {
+	Fulcio string `json:"fulcio,omitempty"`
+	Rekor  string `json:"rekor,omitempty"`
+	TSA    string `json:"tsa,omitempty"`
+}
+
+// Run is the measurement of Count operations, Parallelism at a time, over
+// random payloads of PayloadSize bytes.
+type Run struct {
+	Operation   string `json:"operation"`
+	Parallelism int    `json:"parallelism"`
+	PayloadSize int    `json:"payloadSize"`
+	Count       int    `json:"count"`
+	Errors      int    `json:"errors"`
+	// FirstError is the error of the first failed operation, if any.
+	FirstError string `json:"firstError,omitempty"`
+	// Seconds is the time all the operations took, and Throughput the
+	// number of successful operations per second.
+	Seconds    float64 `json:"seconds"`
+	Throughput float64 `json:"throughput"`
+	// Latency is that of the successful operations.
+	Latency Latency `json:"latency"`
+}
+
+// Latency are statistics of the durations of operations, in milliseconds.
+type Latency struct {
+	Min  float64 `json:"minMs"`
+	Mean float64 `json:"meanMs"`
+	P50  float64 `json:"p50Ms"`
+	P90  float64 `json:"p90Ms"`
+	P99  float64 `json:"p99Ms"`
+	Max  float64 `json:"maxMs"`
+}
+
+// Failed returns the number of failed operations of r.
+func (r *Report) Failed() int {
+	failed := 0
+	for _, run := range r.Runs {
+		failed += run.Errors
+	}
+	return failed
+}
+
+// signed is a payload and its signature.
+type signed struct {
+	payload   []byte
+	signature []byte
+}
+
+type bench struct {
+	c      Config
+	sv     *sign.SignerVerifier
+	rekor  *client.Rekor
+	tsa    *tsaClient
+	signer []byte
+}
+
+// tsaClient requests timestamps like the TSA client of sign-blob, but
+// parses the responses one at a time: the BER parser of digitorus/pkcs7
+// that parses them isn't safe for concurrent use. The requests themselves
+// are still concurrent.
+type tsaClient struct {
+	url    string
+	client http.Client
+	mu     sync.Mutex
+}
+
+var _ tsaclient.TimestampAuthorityClient = (*tsaClient)(nil)
+
+// GetTimestampResponse implements tsaclient.TimestampAuthorityClient.
+func (c *tsaClient) GetTimestampResponse(tsq []byte) ([]byte, error) {
+	resp, err := c.client.Post(c.url, "application/timestamp-query", bytes.NewReader(tsq))
+	if err != nil {
+		return nil, fmt.Errorf("requesting a timestamp: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("request to timestamp authority failed with status code %d", resp.StatusCode)
+	}
+	tsr, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading the timestamp response: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := timestamp.ParseResponse(tsr); err != nil {
+		return nil, fmt.Errorf("parsing the timestamp response: %w", err)
+	}
+	return tsr, nil
+}
+
+// Measure measures the operations of c with sv. Failed operations are
+// counted in the runs of the report rather than returned.
+func Measure(ctx context.Context, sv *sign.SignerVerifier, c Config) (*Report, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	b := &bench{c: c, sv: sv}
+	var err error
+	if b.signer, err = sv.Bytes(ctx); err != nil {
+		return nil, err
+	}
+	if c.RekorURL != "" {
+		if b.rekor, err = rekor.NewClient(c.RekorURL); err != nil {
+			return nil, err
+		}
+	}
+	if c.TSAServerURL != "" {
+		b.tsa = &tsaClient{url: c.TSAServerURL, client: http.Client{Timeout: 10 * time.Second}}
+	}
+	report := &Report{
+		CosignVersion: version.GetVersionInfo().GitVersion,
+		StartedAt:     time.Now().UTC(),
+		Signer:        "key",
+		Endpoints:     Endpoints{Rekor: c.RekorURL, TSA: c.TSAServerURL},
+	}
+	if sv.Cert != nil {
+		report.Signer = "certificate"
+		report.Endpoints.Fulcio = c.FulcioURL
+	}
+
+	for _, size := range c.PayloadSizes {
+		for _, p := range c.Parallelism {
+			payloads, err := newPayloads(size, c.Count)
+			if err != nil {
+				return nil, err
+			}
+			// Verifying needs signatures, which are only measured if
+			// signing is.
+			signOp := OperationSign
+			if !c.measures(OperationSign) {
+				signOp = ""
+			}
+			sigs, run := b.run(ctx, signOp, p, size, len(payloads), func(i int) (signed, error) {
+				return b.sign(ctx, payloads[i])
+			})
+			if run != nil {
+				report.Runs = append(report.Runs, *run)
+			}
+			if !c.measures(OperationVerify) {
+				continue
+			}
+			var ok []signed
+			for _, s := range sigs {
+				if s.signature != nil {
+					ok = append(ok, s)
+				}
+			}
+			if len(ok) == 0 {
+				return nil, fmt.Errorf("no signature of %d bytes to verify, all failed", size)
+			}
+			_, run = b.run(ctx, OperationVerify, p, size, len(ok), func(i int) (signed, error) {
+				return ok[i], b.verify(ctx, ok[i])
+			})
+			report.Runs = append(report.Runs, *run)
+		}
+	}
+	return report, nil
+}
+
+// run runs op count times, parallelism at a time, and returns the results
+// of the successful operations by index, and the measurement of them,
+// unless op is empty.
+func (b *bench) run(ctx context.Context, op string, parallelism, size, count int, do func(i int) (signed, error)) ([]signed, *Run) {
+	if op != "" {
+		ui.Infof(ctx, "Measuring %d %s operations of %d bytes, %d at a time", count, op, size, parallelism)
+	}
+	results := make([]signed, count)
+	durations := make([]time.Duration, count)
+	errs := make([]error, count)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				t := time.Now()
+				results[i], errs[i] = do(i)
+				durations[i] = time.Since(t)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	elapsed := time.Since(start)
+	if op == "" {
+		return results, nil
+	}
+
+	run := &Run{Operation: op, Parallelism: parallelism, PayloadSize: size, Count: count, Seconds: elapsed.Seconds()}
+	var ok []time.Duration
+	for i, err := range errs {
+		if err != nil {
+			if run.Errors == 0 {
+				run.FirstError = err.Error()
+			}
+			run.Errors++
+			results[i] = signed{}
+			continue
+		}
+		ok = append(ok, durations[i])
+	}
+	if elapsed > 0 {
+		run.Throughput = float64(len(ok)) / elapsed.Seconds()
+	}
+	run.Latency = latency(ok)
+	return results, run
+}
+
+// sign signs payload, timestamps the signature and uploads it to the
+// transparency log, as sign-blob does.
+func (b *bench) sign(ctx context.Context, payload []byte) (signed, error) {
+	sig, err := b.sv.SignMessage(bytes.NewReader(payload), signatureoptions.WithContext(ctx))
+	if err != nil {
+		return signed{}, fmt.Errorf("signing: %w", err)
+	}
+	if b.tsa != nil {
+		if _, err := tsa.GetTimestampedSignature(sig, b.tsa); err != nil {
+			return signed{}, fmt.Errorf("timestamping: %w", err)
+		}
+	}
+	if b.rekor != nil {
+		h := sha256.New()
+		h.Write(payload)
+		if _, err := cosign.TLogUpload(ctx, b.rekor, sig, h, b.signer); err != nil {
+			return signed{}, fmt.Errorf("uploading to the transparency log: %w", err)
+		}
+	}
+	return signed{payload: payload, signature: sig}, nil
+}
+
+// verify verifies the signature of s and, if it was uploaded, looks it up
+// in the transparency log, as verify-blob does without a bundle.
+func (b *bench) verify(ctx context.Context, s signed) error {
+	if err := b.sv.VerifySignature(bytes.NewReader(s.signature), bytes.NewReader(s.payload), signatureoptions.WithContext(ctx)); err != nil {
+		return fmt.Errorf("verifying the signature: %w", err)
+	}
+	if b.rekor != nil {
+		if _, err := cosign.FindTlogEntry(ctx, b.rekor, base64.StdEncoding.EncodeToString(s.signature), s.payload, b.signer); err != nil {
+			return fmt.Errorf("finding the signature in the transparency log: %w", err)
+		}
+	}
+	return nil
+}
+
+// newPayloads returns count random payloads of size bytes. They differ in
+// their first bytes, since the transparency log has one entry of each.
+func newPayloads(size, count int) ([][]byte, error) {
+	base := make([]byte, size)
+	if _, err := rand.Read(base); err != nil {
+		return nil, err
+	}
+	var nonce [8]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	prefix := binary.BigEndian.Uint64(nonce[:])
+	payloads := make([][]byte, count)
+	for i := range payloads {
+		p := append([]byte(nil), base...)
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], prefix+uint64(i))
+		copy(p, n[:])
+		payloads[i] = p
+	}
+	return payloads, nil
+}
+
+// latency returns the statistics of durations.
+func latency(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	percentile := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return ms(sorted[i])
+	}
+	return Latency{
+		Min:  ms(sorted[0]),
+		Mean: ms(total / time.Duration(len(sorted))),
+		P50:  percentile(0.5),
+		P90:  percentile(0.9),
+		P99:  percentile(0.99),
+		Max:  ms(sorted[len(sorted)-1]),
+	}
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
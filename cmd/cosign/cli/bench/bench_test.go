@@ -0,0 +1,127 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/tsa"
+)
+
+func TestMeasure(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := tsa.NewEphemeralServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(ts)
+	t.Cleanup(srv.Close)
+
+	report, err := Measure(context.Background(), &sign.SignerVerifier{SignerVerifier: sv}, Config{
+		Operations:   []string{OperationSign, OperationVerify},
+		Parallelism:  []int{1, 4},
+		PayloadSizes: []int{16, 4096},
+		Count:        10,
+		TSAServerURL: srv.URL + tsa.TimestampPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Signer != "key" || report.Endpoints.TSA != srv.URL+tsa.TimestampPath {
+		t.Errorf("report signer %q with endpoints %+v", report.Signer, report.Endpoints)
+	}
+	if len(report.Runs) != 8 {
+		t.Fatalf("%d runs, want 8", len(report.Runs))
+	}
+	for _, run := range report.Runs {
+		if run.Errors != 0 || run.Count != 10 {
+			t.Errorf("%s run of %d bytes, %d at a time: %d of %d operations failed, first: %s",
+				run.Operation, run.PayloadSize, run.Parallelism, run.Errors, run.Count, run.FirstError)
+		}
+		if run.Throughput <= 0 || run.Latency.Min > run.Latency.P50 || run.Latency.P50 > run.Latency.Max {
+			t.Errorf("%s run of %d bytes, %d at a time: throughput %f, latency %+v", run.Operation, run.PayloadSize, run.Parallelism, run.Throughput, run.Latency)
+		}
+	}
+	if report.Failed() != 0 {
+		t.Errorf("Failed() = %d", report.Failed())
+	}
+
+	// Verifying alone signs the payloads without measuring it.
+	report, err = Measure(context.Background(), &sign.SignerVerifier{SignerVerifier: sv}, Config{
+		Operations:   []string{OperationVerify},
+		Parallelism:  []int{2},
+		PayloadSizes: []int{32},
+		Count:        3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Runs) != 1 || report.Runs[0].Operation != OperationVerify {
+		t.Errorf("runs = %+v, want one verify run", report.Runs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Config{Operations: []string{OperationSign}, Parallelism: []int{1}, PayloadSizes: []int{1}, Count: 1}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	for name, mutate := range map[string]func(*Config){
+		"no operation":      func(c *Config) { c.Operations = nil },
+		"unknown operation": func(c *Config) { c.Operations = []string{"attest"} },
+		"no parallelism":    func(c *Config) { c.Parallelism = nil },
+		"zero parallelism":  func(c *Config) { c.Parallelism = []int{0} },
+		"empty payload":     func(c *Config) { c.PayloadSizes = []int{0} },
+		"zero count":        func(c *Config) { c.Count = 0 },
+	} {
+		c := valid
+		mutate(&c)
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate() with %s: expected an error", name)
+		}
+	}
+}
+
+func TestLatency(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	want := Latency{Min: 1, Mean: 50.5, P50: 50, P90: 90, P99: 99, Max: 100}
+	if got := latency(durations); got != want {
+		t.Errorf("latency() = %+v, want %+v", got, want)
+	}
+	if got := latency(nil); got != (Latency{}) {
+		t.Errorf("latency(nil) = %+v", got)
+	}
+}
@@ -0,0 +1,69 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/bundle"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func Bundle() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Provides utilities for offline bundles of the signatures of images",
+	}
+
+	cmd.AddCommand(
+		bundleExport(),
+	)
+
+	return cmd
+}
+
+func bundleExport() *cobra.Command {
+	o := &options.BundleExportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the signatures and attestations of an image to an offline bundle",
+		Long: `Export the signatures and attestations of an image, attached with the tag
+schema or as OCI 1.1 referrers, to a JSON bundle for verifying the image
+without network access, e.g. in an air-gapped cluster.
+
+The bundle holds the certificate chains, with their embedded SCTs, the Rekor
+bundles, the RFC3161 timestamps and the Rekor inclusion proofs of the
+signatures. It is verified with cosign verify --bundle-file and cosign
+verify-attestation --bundle-file, against a pinned --trusted-root.`,
+		Example: `  cosign bundle export --output bundle.json <IMAGE>
+
+  # verify the image offline, against the trusted root of the Sigstore TUF repository
+  cosign verify --bundle-file bundle.json --trusted-root trusted_root.json \
+    --certificate-identity foo@example.com --certificate-oidc-issuer https://issuer.example.com
+
+  # export without the inclusion proofs, for signatures that aren't in Rekor
+  cosign bundle export --tlog-proofs=false --output bundle.json <IMAGE>`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return bundle.ExportCmd(cmd.Context(), *o, args[0], cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
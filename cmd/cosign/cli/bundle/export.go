@@ -0,0 +1,102 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/rekor/pkg/generated/client"
+)
+
+// ExportCmd writes the offline bundle of imageRef to o.Output, or to out if
+// it is empty.
+func ExportCmd(ctx context.Context, o options.BundleExportOptions, imageRef string, out io.Writer) error {
+	ref, err := oci.ParseReference(imageRef, o.Registry.NameOptions()...)
+	if err != nil {
+		return err
+	}
+	ociremoteOpts, err := o.Registry.ClientOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("constructing client options: %w", err)
+	}
+	digest, err := ociremote.ResolveDigest(ref, ociremoteOpts...)
+	if err != nil {
+		return fmt.Errorf("resolving digest: %w", err)
+	}
+
+	var rekorClient *client.Rekor
+	if o.TlogProofs && o.Rekor.URL != "" {
+		rekorClient, err = rekor.NewClient(o.Rekor.URL)
+		if err != nil {
+			return fmt.Errorf("creating Rekor client: %w", err)
+		}
+	}
+	b, err := cosign.ExportOfflineBundle(ctx, digest, rekorClient, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	if o.CTProofs {
+		clients, err := ctLogClients(ctx, o.CTLogURL)
+		if err != nil {
+			return err
+		}
+		if err := b.AddCTInclusionProofs(ctx, clients); err != nil {
+			return fmt.Errorf("fetching certificate transparency log inclusion proofs: %w", err)
+		}
+	}
+
+	raw, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	raw = append(raw, '\n')
+	if o.Output == "" {
+		if _, err := out.Write(raw); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(o.Output, raw, 0o600); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	ui.Infof(ctx, "Exported %d signatures and %d attestations of %s", len(b.Signatures), len(b.Attestations), digest)
+	return nil
+}
+
+// ctLogClients returns the client of the certificate transparency log at url
+// for every log, or the clients of the logs of the TUF root if it is empty.
+func ctLogClients(ctx context.Context, url string) (func(logID string) (cosign.CTLogClient, error), error) {
+	if url == "" {
+		pubKeys, err := cosign.GetCTLogPubs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting CTLog public keys: %w", err)
+		}
+		return cosign.CTLogClientsOfKeys(pubKeys), nil
+	}
+	lc, err := cosign.NewCTLogClient(url)
+	if err != nil {
+		return nil, fmt.Errorf("creating certificate transparency log client: %w", err)
+	}
+	return func(string) (cosign.CTLogClient, error) { return lc, nil }, nil
+}
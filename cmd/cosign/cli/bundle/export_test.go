@@ -0,0 +1,218 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+func writeSignatures(t *testing.T, tag name.Tag, sigs ...oci.Signature) {
+	t.Helper()
+	s, err := mutate.AppendSignatures(empty.Signatures(), sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(tag, s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref.Context().Tag("latest"), img); err != nil {
+		t.Fatal(err)
+	}
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := t.TempDir()
+	keyPath := filepath.Join(td, "key.pub")
+	if err := os.WriteFile(keyPath, pub, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, err := cryptoutils.MarshalPublicKeyToPEM(other.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKeyPath := filepath.Join(td, "other.pub")
+	if err := os.WriteFile(otherKeyPath, otherPub, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A signature and an attestation of the image.
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + ref.Context().String() + `"},"image":{"docker-manifest-digest":"` + h.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	raw, err := sv.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(payload, base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSignatures(t, sigTag, sig)
+
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://cosign.sigstore.dev/attestation/v1","subject":[{"name":"` +
+		ref.Context().String() + `","digest":{"sha256":"` + h.Hex + `"}}],"predicate":{"Data":"tested","Timestamp":""}}`)
+	envelope, err := dsse.WrapSigner(sv, types.IntotoPayloadType).SignMessage(bytes.NewReader(statement))
+	if err != nil {
+		t.Fatal(err)
+	}
+	att, err := static.NewAttestation(envelope, static.WithLayerMediaType(types.DssePayloadType))
+	if err != nil {
+		t.Fatal(err)
+	}
+	attTag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSignatures(t, attTag, att)
+
+	bundlePath := filepath.Join(td, "bundle.json")
+	o := options.BundleExportOptions{Output: bundlePath}
+	if err := ExportCmd(ctx, o, host+"/app", io.Discard); err != nil {
+		t.Fatalf("ExportCmd() = %v", err)
+	}
+	b, err := cosign.LoadOfflineBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Image != ref.String() || len(b.Signatures) != 1 || len(b.Attestations) != 1 {
+		t.Fatalf("bundle of %s has %d signatures and %d attestations, want %s, 1 and 1", b.Image, len(b.Signatures), len(b.Attestations), ref)
+	}
+
+	var stdout bytes.Buffer
+	if err := ExportCmd(ctx, options.BundleExportOptions{}, ref.String(), &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &cosign.OfflineBundle{}); err != nil {
+		t.Errorf("ExportCmd() to stdout wrote %q: %v", stdout.String(), err)
+	}
+
+	// The bundle verifies without the registry.
+	s.Close()
+	bo := options.OfflineBundleOptions{BundleFile: bundlePath}
+	v := &verify.VerifyCommand{KeyRef: keyPath, CheckClaims: true, IgnoreSCT: true, IgnoreTlog: true, OfflineBundle: bo}
+	if err := v.Exec(ctx, nil); err != nil {
+		t.Errorf("VerifyCommand.Exec() with the bundle = %v", err)
+	}
+	va := &verify.VerifyAttestationCommand{KeyRef: keyPath, CheckClaims: true, IgnoreSCT: true, IgnoreTlog: true, PredicateType: "custom", OfflineBundle: bo}
+	if err := va.Exec(ctx, []string{"mirror.example.com/app@" + h.String()}); err != nil {
+		t.Errorf("VerifyAttestationCommand.Exec() with the bundle = %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		images []string
+		v      verify.VerifyCommand
+		want   string
+	}{
+		"another image": {
+			images: []string{host + "/app@sha256:" + strings.Repeat("0", 64)},
+			v:      verify.VerifyCommand{KeyRef: keyPath, IgnoreTlog: true},
+			want:   "isn't the image of the bundle",
+		},
+		"tag": {
+			images: []string{host + "/app:latest"},
+			v:      verify.VerifyCommand{KeyRef: keyPath, IgnoreTlog: true},
+			want:   "isn't the image of the bundle",
+		},
+		"no trusted root": {
+			v:    verify.VerifyCommand{KeyRef: keyPath},
+			want: "--trusted-root is required",
+		},
+		"local image": {
+			v:    verify.VerifyCommand{KeyRef: keyPath, IgnoreTlog: true, LocalImage: true},
+			want: "can't be used with --local-image",
+		},
+		"another key": {
+			v:    verify.VerifyCommand{KeyRef: otherKeyPath, IgnoreTlog: true},
+			want: "no matching signatures",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tc.v.IgnoreSCT = true
+			tc.v.OfflineBundle = bo
+			if err := tc.v.Exec(ctx, tc.images); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Exec() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
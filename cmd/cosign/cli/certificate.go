@@ -0,0 +1,66 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/certificate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func Certificate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certificate",
+		Short: "Provides utilities for the certificates of signatures",
+	}
+
+	cmd.AddCommand(certificateInspect())
+
+	return cmd
+}
+
+func certificateInspect() *cobra.Command {
+	o := &options.CertificateInspectOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "inspect <FILE> | <IMAGE>",
+		Short: "Decode certificates, with the names of their Sigstore extensions",
+		Long: `Decode the certificates of a PEM or DER certificate file, of a cosign or
+Sigstore bundle, or of the signatures and attestations of an image, with
+their chains.
+
+The Fulcio extensions (OIDs 1.3.6.1.4.1.57264.1.*), e.g. the OIDC issuer and
+the source repository and build of a CI workflow, are shown with their names
+and decoded values, and so are the subject alternative names, key usages and
+key identifiers. The certificates are shown without being verified.`,
+		Example: `  cosign certificate inspect signing-cert.pem
+
+  # the certificate of a blob signed with sign-blob --bundle
+  cosign certificate inspect artifact.bundle
+
+  # the certificates of the signatures and attestations of an image, as JSON
+  cosign certificate inspect --output json <IMAGE>`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return certificate.InspectCmd(cmd.Context(), *o, args[0], cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,469 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	fulciocert "github.com/sigstore/fulcio/pkg/certificate"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/templates/term"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// Certificate is the decoded form of a certificate, as printed by
+// InspectCmd.
+type Certificate struct {
+	// Source is where the certificate was found, e.g. the signature of an
+	// image.
+	Source                  string      `json:"source"`
+	Subject                 string      `json:"subject"`
+	Issuer                  string      `json:"issuer"`
+	SerialNumber            string      `json:"serialNumber"`
+	NotBefore               time.Time   `json:"notBefore"`
+	NotAfter                time.Time   `json:"notAfter"`
+	PublicKey               string      `json:"publicKey"`
+	SignatureAlgorithm      string      `json:"signatureAlgorithm"`
+	IsCA                    bool        `json:"isCA"`
+	KeyUsage                []string    `json:"keyUsage,omitempty"`
+	ExtKeyUsage             []string    `json:"extKeyUsage,omitempty"`
+	SubjectAlternativeNames []string    `json:"subjectAlternativeNames,omitempty"`
+	Extensions              []Extension `json:"extensions,omitempty"`
+}
+
+// Extension is an extension of a certificate. Value is decoded for the
+// Sigstore extensions and hex-encoded for those with no field in
+// Certificate.
+type Extension struct {
+	OID      string `json:"oid"`
+	Name     string `json:"name,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Critical bool   `json:"critical,omitempty"`
+	Sigstore bool   `json:"sigstore,omitempty"`
+}
+
+// sigstoreExtensions names the extensions that Fulcio adds to certificates,
+// see https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md. The
+// first ones hold the raw value rather than a DER string.
+var sigstoreExtensions = map[string]struct {
+	name string
+	raw  bool
+}{
+	fulciocert.OIDIssuer.String():                          {name: "Issuer (deprecated)", raw: true},
+	fulciocert.OIDGitHubWorkflowTrigger.String():           {name: "GitHub Workflow Trigger (deprecated)", raw: true},
+	fulciocert.OIDGitHubWorkflowSHA.String():               {name: "GitHub Workflow SHA (deprecated)", raw: true},
+	fulciocert.OIDGitHubWorkflowName.String():              {name: "GitHub Workflow Name (deprecated)", raw: true},
+	fulciocert.OIDGitHubWorkflowRepository.String():        {name: "GitHub Workflow Repository (deprecated)", raw: true},
+	fulciocert.OIDGitHubWorkflowRef.String():               {name: "GitHub Workflow Ref (deprecated)", raw: true},
+	fulciocert.OIDIssuerV2.String():                        {name: "Issuer"},
+	fulciocert.OIDBuildSignerURI.String():                  {name: "Build Signer URI"},
+	fulciocert.OIDBuildSignerDigest.String():               {name: "Build Signer Digest"},
+	fulciocert.OIDRunnerEnvironment.String():               {name: "Runner Environment"},
+	fulciocert.OIDSourceRepositoryURI.String():             {name: "Source Repository URI"},
+	fulciocert.OIDSourceRepositoryDigest.String():          {name: "Source Repository Digest"},
+	fulciocert.OIDSourceRepositoryRef.String():             {name: "Source Repository Ref"},
+	fulciocert.OIDSourceRepositoryIdentifier.String():      {name: "Source Repository Identifier"},
+	fulciocert.OIDSourceRepositoryOwnerURI.String():        {name: "Source Repository Owner URI"},
+	fulciocert.OIDSourceRepositoryOwnerIdentifier.String(): {name: "Source Repository Owner Identifier"},
+	fulciocert.OIDBuildConfigURI.String():                  {name: "Build Config URI"},
+	fulciocert.OIDBuildConfigDigest.String():               {name: "Build Config Digest"},
+	fulciocert.OIDBuildTrigger.String():                    {name: "Build Trigger"},
+	fulciocert.OIDRunInvocationURI.String():                {name: "Run Invocation URI"},
+	"1.3.6.1.4.1.57264.1.22":                               {name: "Source Repository Visibility At Signing"},
+}
+
+// standardExtensions names the common extensions whose values are fields of
+// Certificate or are printed hex-encoded.
+var standardExtensions = map[string]string{
+	"2.5.29.14":               "Subject Key Identifier",
+	"2.5.29.15":               "Key Usage",
+	"2.5.29.17":               "Subject Alternative Name",
+	"2.5.29.19":               "Basic Constraints",
+	"2.5.29.30":               "Name Constraints",
+	"2.5.29.31":               "CRL Distribution Points",
+	"2.5.29.32":               "Certificate Policies",
+	"2.5.29.35":               "Authority Key Identifier",
+	"2.5.29.37":               "Extended Key Usage",
+	"1.3.6.1.5.5.7.1.1":       "Authority Information Access",
+	"1.3.6.1.4.1.11129.2.4.2": "CT Precertificate SCTs",
+	"1.3.6.1.4.1.11129.2.4.3": "CT Precertificate Poison",
+}
+
+var keyUsages = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "Digital Signature"},
+	{x509.KeyUsageContentCommitment, "Content Commitment"},
+	{x509.KeyUsageKeyEncipherment, "Key Encipherment"},
+	{x509.KeyUsageDataEncipherment, "Data Encipherment"},
+	{x509.KeyUsageKeyAgreement, "Key Agreement"},
+	{x509.KeyUsageCertSign, "Certificate Sign"},
+	{x509.KeyUsageCRLSign, "CRL Sign"},
+	{x509.KeyUsageEncipherOnly, "Encipher Only"},
+	{x509.KeyUsageDecipherOnly, "Decipher Only"},
+}
+
+var extKeyUsages = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:             "Any",
+	x509.ExtKeyUsageServerAuth:      "Server Authentication",
+	x509.ExtKeyUsageClientAuth:      "Client Authentication",
+	x509.ExtKeyUsageCodeSigning:     "Code Signing",
+	x509.ExtKeyUsageEmailProtection: "Email Protection",
+	x509.ExtKeyUsageTimeStamping:    "Time Stamping",
+	x509.ExtKeyUsageOCSPSigning:     "OCSP Signing",
+}
+
+// InspectCmd decodes the certificates of ref, a PEM or DER certificate file,
+// a bundle, or else an image whose signatures and attestations have
+// certificates, and writes them to out as text or JSON.
+func InspectCmd(ctx context.Context, o options.CertificateInspectOptions, ref string, out io.Writer) error {
+	if o.Output != "text" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, expected text or json", o.Output)
+	}
+	var certs []Certificate
+	var err error
+	if _, serr := os.Stat(ref); serr == nil {
+		certs, err = fileCertificates(ref)
+	} else {
+		certs, err = imageCertificates(ctx, o.Registry, ref)
+	}
+	if err != nil {
+		return err
+	}
+	if len(certs) == 0 {
+		return fmt.Errorf("no certificates found in %s", ref)
+	}
+
+	if o.Output == "json" {
+		b, err := json.MarshalIndent(certs, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+	var buf bytes.Buffer
+	for i, c := range certs {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		writeText(&buf, c)
+	}
+	_, err = term.NewResponsiveWriter(out).Write(buf.Bytes())
+	return err
+}
+
+// fileCertificates returns the certificates of the PEM or DER certificate
+// file, or the bundle, at path.
+func fileCertificates(path string) ([]Certificate, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := parseCertificates(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return describeAll(certs, path), nil
+}
+
+// bundle has the certificates of a cosign bundle, as written by sign-blob
+// --bundle, or of a Sigstore bundle.
+type bundle struct {
+	Cert                 string `json:"cert"`
+	VerificationMaterial *struct {
+		Certificate *struct {
+			RawBytes []byte `json:"rawBytes"`
+		} `json:"certificate"`
+		X509CertificateChain *struct {
+			Certificates []struct {
+				RawBytes []byte `json:"rawBytes"`
+			} `json:"certificates"`
+		} `json:"x509CertificateChain"`
+	} `json:"verificationMaterial"`
+}
+
+func parseCertificates(b []byte) ([]*x509.Certificate, error) {
+	trimmed := bytes.TrimSpace(b)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("-----BEGIN")):
+		return cryptoutils.UnmarshalCertificatesFromPEM(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var bu bundle
+		if err := json.Unmarshal(trimmed, &bu); err != nil {
+			return nil, fmt.Errorf("parsing bundle: %w", err)
+		}
+		if bu.Cert != "" {
+			// The certificate of a cosign bundle is PEM, base64-encoded
+			// by older versions.
+			pem := []byte(bu.Cert)
+			if decoded, err := base64.StdEncoding.DecodeString(bu.Cert); err == nil {
+				pem = decoded
+			}
+			return cryptoutils.UnmarshalCertificatesFromPEM(pem)
+		}
+		var der [][]byte
+		if vm := bu.VerificationMaterial; vm != nil {
+			if vm.Certificate != nil {
+				der = append(der, vm.Certificate.RawBytes)
+			}
+			if vm.X509CertificateChain != nil {
+				for _, c := range vm.X509CertificateChain.Certificates {
+					der = append(der, c.RawBytes)
+				}
+			}
+		}
+		if len(der) == 0 {
+			return nil, fmt.Errorf("the bundle has no certificate, it may be signed with a key")
+		}
+		return x509.ParseCertificates(bytes.Join(der, nil))
+	}
+	return x509.ParseCertificates(b)
+}
+
+// imageCertificates returns the certificates, and their chains, of the
+// signatures and attestations of the image at ref.
+func imageCertificates(ctx context.Context, regOpts options.RegistryOptions, ref string) ([]Certificate, error) {
+	r, err := oci.ParseReference(ref, regOpts.NameOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a file nor an image: %w", ref, err)
+	}
+	remoteOpts, err := regOpts.ClientOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := ociremote.ResolveDigest(r, remoteOpts...)
+	if err != nil {
+		return nil, err
+	}
+	se, err := ociremote.SignedEntity(digest, remoteOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []Certificate
+	for _, kind := range []string{"signature", "attestation"} {
+		var sigs oci.Signatures
+		if kind == "signature" {
+			sigs, err = se.Signatures()
+		} else {
+			sigs, err = se.Attestations()
+		}
+		if err != nil {
+			continue
+		}
+		list, err := sigs.Get()
+		if err != nil {
+			return nil, fmt.Errorf("fetching %ss: %w", kind, err)
+		}
+		for _, sig := range list {
+			cert, err := sig.Cert()
+			if err != nil || cert == nil {
+				continue
+			}
+			chain, err := sig.Chain()
+			if err != nil {
+				return nil, err
+			}
+			d, err := sig.Digest()
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, describeAll(append([]*x509.Certificate{cert}, chain...), kind+" "+d.String())...)
+		}
+	}
+	return certs, nil
+}
+
+func describeAll(certs []*x509.Certificate, source string) []Certificate {
+	described := make([]Certificate, 0, len(certs))
+	for i, c := range certs {
+		s := source
+		if i > 0 {
+			s = fmt.Sprintf("%s, chain %d", source, i)
+		}
+		described = append(described, describe(c, s))
+	}
+	return described
+}
+
+// describe decodes cert, with the friendly names of its extensions.
+func describe(cert *x509.Certificate, source string) Certificate {
+	c := Certificate{
+		Source:             source,
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SerialNumber:       cert.SerialNumber.String(),
+		NotBefore:          cert.NotBefore.UTC(),
+		NotAfter:           cert.NotAfter.UTC(),
+		PublicKey:          publicKey(cert),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		IsCA:               cert.IsCA,
+	}
+	for _, ku := range keyUsages {
+		if cert.KeyUsage&ku.usage != 0 {
+			c.KeyUsage = append(c.KeyUsage, ku.name)
+		}
+	}
+	for _, eku := range cert.ExtKeyUsage {
+		n, ok := extKeyUsages[eku]
+		if !ok {
+			n = fmt.Sprintf("Unknown (%d)", eku)
+		}
+		c.ExtKeyUsage = append(c.ExtKeyUsage, n)
+	}
+	for _, oid := range cert.UnknownExtKeyUsage {
+		c.ExtKeyUsage = append(c.ExtKeyUsage, oid.String())
+	}
+	for _, e := range cert.EmailAddresses {
+		c.SubjectAlternativeNames = append(c.SubjectAlternativeNames, "email:"+e)
+	}
+	for _, u := range cert.URIs {
+		c.SubjectAlternativeNames = append(c.SubjectAlternativeNames, "URI:"+u.String())
+	}
+	for _, d := range cert.DNSNames {
+		c.SubjectAlternativeNames = append(c.SubjectAlternativeNames, "DNS:"+d)
+	}
+	for _, ip := range cert.IPAddresses {
+		c.SubjectAlternativeNames = append(c.SubjectAlternativeNames, "IP:"+ip.String())
+	}
+	if on, err := cryptoutils.UnmarshalOtherNameSAN(cert.Extensions); err == nil {
+		c.SubjectAlternativeNames = append(c.SubjectAlternativeNames, "otherName:"+on)
+	}
+
+	for _, ext := range cert.Extensions {
+		e := Extension{OID: ext.Id.String(), Critical: ext.Critical}
+		if s, ok := sigstoreExtensions[e.OID]; ok {
+			e.Name, e.Sigstore = s.name, true
+			e.Value = string(ext.Value)
+			if !s.raw {
+				if err := fulciocert.ParseDERString(ext.Value, &e.Value); err != nil {
+					e.Value = hex.EncodeToString(ext.Value)
+				}
+			}
+		} else {
+			e.Name = standardExtensions[e.OID]
+			switch e.OID {
+			case "2.5.29.14":
+				e.Value = colonHex(cert.SubjectKeyId)
+			case "2.5.29.35":
+				e.Value = colonHex(cert.AuthorityKeyId)
+			case "2.5.29.15", "2.5.29.17", "2.5.29.19", "2.5.29.37":
+				// These are fields of Certificate.
+			default:
+				e.Value = hex.EncodeToString(ext.Value)
+			}
+		}
+		c.Extensions = append(c.Extensions, e)
+	}
+	return c
+}
+
+func publicKey(cert *x509.Certificate) string {
+	switch k := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		return "ECDSA " + k.Curve.Params().Name
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", k.N.BitLen())
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
+
+func colonHex(b []byte) string {
+	parts := make([]string, len(b))
+	for i, c := range b {
+		parts[i] = fmt.Sprintf("%02X", c)
+	}
+	return strings.Join(parts, ":")
+}
+
+// writeText writes c as aligned fields, with the Sigstore extensions by
+// their friendly names.
+func writeText(w io.Writer, c Certificate) {
+	fmt.Fprintf(w, "Certificate (%s)\n", c.Source)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(tw, "  %s:\t%s\n", label, value)
+		}
+	}
+	field("Subject", c.Subject)
+	field("Issuer", c.Issuer)
+	field("Serial number", c.SerialNumber)
+	field("Not before", c.NotBefore.Format(time.RFC3339))
+	field("Not after", c.NotAfter.Format(time.RFC3339))
+	field("Public key", c.PublicKey)
+	field("Signature algorithm", c.SignatureAlgorithm)
+	field("CA", fmt.Sprint(c.IsCA))
+	field("Key usage", strings.Join(c.KeyUsage, ", "))
+	field("Extended key usage", strings.Join(c.ExtKeyUsage, ", "))
+	for i, san := range c.SubjectAlternativeNames {
+		label := ""
+		if i == 0 {
+			label = "Subject alternative names:"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", label, san)
+	}
+	tw.Flush()
+
+	for _, sigstore := range []bool{true, false} {
+		var exts []Extension
+		for _, e := range c.Extensions {
+			if e.Sigstore == sigstore && (sigstore || e.Value != "") {
+				exts = append(exts, e)
+			}
+		}
+		if len(exts) == 0 {
+			continue
+		}
+		if sigstore {
+			fmt.Fprintln(w, "  Sigstore extensions:")
+		} else {
+			fmt.Fprintln(w, "  Other extensions:")
+		}
+		for _, e := range exts {
+			name := e.OID
+			if e.Name != "" {
+				name = fmt.Sprintf("%s (%s)", e.Name, e.OID)
+			}
+			if e.Critical {
+				name += " [critical]"
+			}
+			fmt.Fprintf(tw, "    %s:\t%s\n", name, e.Value)
+		}
+		tw.Flush()
+	}
+}
@@ -0,0 +1,154 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	fulciocert "github.com/sigstore/fulcio/pkg/certificate"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func fulcioCert(t *testing.T) []byte {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exts, err := fulciocert.Extensions{
+		Issuer:              "https://token.actions.githubusercontent.com",
+		SourceRepositoryURI: "https://github.com/example/app",
+		BuildTrigger:        "push",
+	}.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	workflow, _ := url.Parse("https://github.com/example/app/.github/workflows/release.yml@refs/heads/main")
+	tmpl := &x509.Certificate{
+		SerialNumber:    big.NewInt(42),
+		NotBefore:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		NotAfter:        time.Date(2026, 1, 2, 3, 14, 5, 0, time.UTC),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{workflow},
+		ExtraExtensions: exts,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, priv.Public(), priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestInspectCmd(t *testing.T) {
+	td := t.TempDir()
+	der := fulcioCert(t)
+	pem, err := cryptoutils.MarshalCertificateToPEM(mustParse(t, der))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigstoreBundle, _ := json.Marshal(map[string]any{
+		"verificationMaterial": map[string]any{"certificate": map[string]any{"rawBytes": der}},
+	})
+	files := map[string][]byte{
+		"cert.pem":      pem,
+		"cert.der":      der,
+		"cosign.bundle": []byte(`{"base64Signature": "c2ln", "cert": "` + base64.StdEncoding.EncodeToString(pem) + `"}`),
+		"sigstore.json": sigstoreBundle,
+	}
+	for name, b := range files {
+		p := filepath.Join(td, name)
+		if err := os.WriteFile(p, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if err := InspectCmd(context.Background(), options.CertificateInspectOptions{Output: "json"}, p, &out); err != nil {
+			t.Fatalf("InspectCmd(%s) = %v", name, err)
+		}
+		var certs []Certificate
+		if err := json.Unmarshal(out.Bytes(), &certs); err != nil {
+			t.Fatal(err)
+		}
+		if len(certs) != 1 || certs[0].SerialNumber != "42" || certs[0].PublicKey != "ECDSA P-256" {
+			t.Fatalf("InspectCmd(%s) = %+v", name, certs)
+		}
+		sigstore := map[string]string{}
+		for _, e := range certs[0].Extensions {
+			if e.Sigstore {
+				sigstore[e.Name] = e.Value
+			}
+		}
+		if sigstore["Issuer"] != "https://token.actions.githubusercontent.com" ||
+			sigstore["Issuer (deprecated)"] != "https://token.actions.githubusercontent.com" ||
+			sigstore["Source Repository URI"] != "https://github.com/example/app" ||
+			sigstore["Build Trigger"] != "push" {
+			t.Errorf("InspectCmd(%s) Sigstore extensions = %v", name, sigstore)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := InspectCmd(context.Background(), options.CertificateInspectOptions{Output: "text"}, filepath.Join(td, "cert.pem"), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Certificate (" + filepath.Join(td, "cert.pem") + ")\n",
+		"  Not before:                 2026-01-02T03:04:05Z\n",
+		"  Extended key usage:         Code Signing\n",
+		"  Subject alternative names:  URI:https://github.com/example/app/.github/workflows/release.yml@refs/heads/main\n",
+		"  Sigstore extensions:\n",
+		"    Source Repository URI (1.3.6.1.4.1.57264.1.12):  https://github.com/example/app\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("text output = %s, want it to contain %q", out.String(), want)
+		}
+	}
+
+	if err := InspectCmd(context.Background(), options.CertificateInspectOptions{Output: "yaml"}, filepath.Join(td, "cert.pem"), &out); err == nil {
+		t.Error("InspectCmd() with an unsupported output format: expected an error")
+	}
+	keyBundle := filepath.Join(td, "key.bundle")
+	if err := os.WriteFile(keyBundle, []byte(`{"verificationMaterial": {"publicKey": {"hint": "abc"}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := InspectCmd(context.Background(), options.CertificateInspectOptions{Output: "text"}, keyBundle, &out); err == nil {
+		t.Error("InspectCmd() of a bundle signed with a key: expected an error")
+	}
+}
+
+func mustParse(t *testing.T, der []byte) *x509.Certificate {
+	t.Helper()
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
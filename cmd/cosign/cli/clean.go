@@ -16,33 +16,62 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
-	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/spf13/cobra"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	ociexperimental "github.com/sigstore/cosign/v2/internal/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/walk"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
 )
 
 func Clean() *cobra.Command {
 	c := &options.CleanOptions{}
 
 	cmd := &cobra.Command{
-		Use:              "clean",
-		Short:            "Remove all signatures from an image.",
-		Example:          "  cosign clean <IMAGE>",
+		Use:   "clean",
+		Short: "Remove all signatures from an image.",
+		Example: `  cosign clean <IMAGE>
+
+  # list what would be removed, without removing anything
+  cosign clean --dry-run <IMAGE>
+
+  # remove only the signatures and attestations made with a compromised key
+  cosign clean --signed-by-key cosign.pub <IMAGE>
+
+  # remove only the signatures made by a keyless identity, from a multi-arch
+  # image and each of its platform images
+  cosign clean --recursive --type signature --signed-by-identity user@example.com --signed-by-oidc-issuer https://accounts.example.com <IMAGE>`,
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return CleanCmd(cmd.Context(), c.Registry, c.CleanType, args[0], c.Force)
+			o := CleanOpts{Type: c.CleanType, DryRun: c.DryRun, Recursive: c.Recursive, Force: c.Force}
+			if c.SignedByKey != "" || c.SignedByIdentity != "" || c.SignedByOIDCIssuer != "" {
+				m, err := NewSignerMatcher(cmd.Context(), c.SignedByKey, c.SignedByIdentity, c.SignedByOIDCIssuer)
+				if err != nil {
+					return err
+				}
+				o.SignedBy = m
+			}
+			return CleanImageCmd(cmd.Context(), c.Registry, args[0], o, cmd.OutOrStdout())
 		},
 	}
 
@@ -50,67 +79,236 @@ func Clean() *cobra.Command {
 	return cmd
 }
 
+// CleanOpts selects what CleanImageCmd removes from an image.
+type CleanOpts struct {
+	// Type is the kind of attachments to remove.
+	Type options.CleanType
+	// SignedBy, if set, removes only the signatures and attestations made
+	// by its signer.
+	SignedBy *SignerMatcher
+	// DryRun only lists what would be removed.
+	DryRun bool
+	// Recursive also cleans each image of a multi-arch image.
+	Recursive bool
+	// Force removes without prompting for confirmation.
+	Force bool
+}
+
 func CleanCmd(ctx context.Context, regOpts options.RegistryOptions, cleanType options.CleanType, imageRef string, force bool) error {
-	if !force {
-		ui.Warnf(ctx, prompt(cleanType))
+	return CleanImageCmd(ctx, regOpts, imageRef, CleanOpts{Type: cleanType, Force: force}, os.Stdout)
+}
+
+// CleanSignedByCmd removes the signatures and attestations made by the
+// signer m selects, and keeps those made by anyone else.
+func CleanSignedByCmd(ctx context.Context, regOpts options.RegistryOptions, cleanType options.CleanType, imageRef string, m *SignerMatcher, force bool) error {
+	return CleanImageCmd(ctx, regOpts, imageRef, CleanOpts{Type: cleanType, SignedBy: m, Force: force}, os.Stdout)
+}
+
+// CleanImageCmd removes the attachments o selects from imageRef. With
+// o.DryRun, it writes what it would remove to out instead.
+func CleanImageCmd(ctx context.Context, regOpts options.RegistryOptions, imageRef string, o CleanOpts, out io.Writer) error {
+	if o.SignedBy != nil && o.Type == options.CleanTypeSbom {
+		return errors.New("SBOMs are not signed, so they can't be removed by signer")
+	}
+	if !o.Force && !o.DryRun {
+		msg := prompt(o.Type)
+		if o.SignedBy != nil {
+			msg = fmt.Sprintf("this will remove the %s made by %s from the image", signedByKind(o.Type), o.SignedBy)
+		}
+		if o.Recursive {
+			msg += " and from each image of its index"
+		}
+		ui.Warnf(ctx, "%s", msg)
 		if err := ui.ConfirmContinue(ctx); err != nil {
 			return err
 		}
 	}
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
+	if err != nil {
+		return err
+	}
+
+	c, err := newCleaner(ctx, regOpts, o, out)
 	if err != nil {
 		return err
 	}
 
-	remoteOpts := regOpts.GetRegistryClientOpts(ctx)
+	refs := []name.Reference{ref}
+	if o.Recursive {
+		se, err := ociremote.SignedEntity(ref, c.ociremoteOpts...)
+		if err != nil {
+			return err
+		}
+		refs = nil
+		if err := walk.SignedEntity(ctx, se, func(ctx context.Context, se oci.SignedEntity) error {
+			h, err := se.Digest()
+			if err != nil {
+				return err
+			}
+			refs = append(refs, ref.Context().Digest(h.String()))
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range refs {
+		if o.SignedBy != nil {
+			err = c.cleanSignedBy(ctx, r)
+		} else {
+			err = c.clean(r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleaner removes the attachments of the images of a CleanImageCmd.
+type cleaner struct {
+	opts          CleanOpts
+	out           io.Writer
+	ociremoteOpts []ociremote.Option
+	remoteOpts    []remote.Option
+	deleter       *ociremote.Deleter
+}
+
+func newCleaner(ctx context.Context, regOpts options.RegistryOptions, o CleanOpts, out io.Writer) (*cleaner, error) {
+	// Honor the attachment tag prefix and COSIGN_REPOSITORY so the same tags
+	// are cleaned as sign and attest wrote.
+	ociremoteOpts, err := regOpts.ClientOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &cleaner{
+		opts:          o,
+		out:           out,
+		ociremoteOpts: ociremoteOpts,
+		remoteOpts:    regOpts.GetRegistryClientOpts(ctx),
+		deleter:       ociremote.NewDeleter(ociremoteOpts...),
+	}, nil
+}
 
-	sigRef, err := ociremote.SignatureTag(ref, ociremote.WithRemoteOptions(remoteOpts...))
+// clean removes the attachment tags and referrers of the type of c from ref.
+func (c *cleaner) clean(ref name.Reference) error {
+	sigRef, err := ociremote.SignatureTag(ref, c.ociremoteOpts...)
 	if err != nil {
 		return err
 	}
 
-	attRef, err := ociremote.AttestationTag(ref, ociremote.WithRemoteOptions(remoteOpts...))
+	attRef, err := ociremote.AttestationTag(ref, c.ociremoteOpts...)
 	if err != nil {
 		return err
 	}
 
-	sbomRef, err := ociremote.SBOMTag(ref, ociremote.WithRemoteOptions(remoteOpts...))
+	sbomRef, err := ociremote.SBOMTag(ref, c.ociremoteOpts...)
 	if err != nil {
 		return err
 	}
 
 	var cleanTags []name.Tag
-	switch cleanType {
+	var artifactTypes []string
+	switch c.opts.Type {
 	case options.CleanTypeSignature:
 		cleanTags = []name.Tag{sigRef}
+		artifactTypes = []string{ociexperimental.ArtifactType("sig")}
 	case options.CleanTypeSbom:
 		cleanTags = []name.Tag{sbomRef}
+		artifactTypes = []string{ociexperimental.ArtifactType("sbom")}
 	case options.CleanTypeAttestation:
 		cleanTags = []name.Tag{attRef}
+		artifactTypes = []string{ociexperimental.ArtifactType("att")}
 	case options.CleanTypeAll:
 		cleanTags = []name.Tag{sigRef, attRef, sbomRef}
+		artifactTypes = []string{ociexperimental.ArtifactType("sig"), ociexperimental.ArtifactType("att"), ociexperimental.ArtifactType("sbom")}
 	default:
 		panic("invalid CleanType value")
 	}
 
 	for _, t := range cleanTags {
-		if err := remote.Delete(t, remoteOpts...); err != nil {
-			var te *transport.Error
-			if errors.As(err, &te) && te.StatusCode == http.StatusNotFound { //nolint: revive
-				// If the tag doesn't exist, some registries may
-				// respond with a 404, which shouldn't be considered an
-				// error.
-			} else {
-				fmt.Fprintf(os.Stderr, "could not delete %s from %s\n: %v\n", t, imageRef, err)
+		if c.opts.DryRun {
+			exists, err := c.deleter.TagExists(t)
+			if err != nil {
+				return fmt.Errorf("checking %s: %w", t, err)
 			}
-		} else {
-			fmt.Fprintf(os.Stderr, "Removed %s from %s\n", t, imageRef)
+			if exists {
+				fmt.Fprintf(c.out, "Would remove %s from %s\n", t, ref)
+			}
+			continue
+		}
+		deleted, err := c.deleter.DeleteTag(t)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "could not delete %s from %s\n: %v\n", t, ref, err)
+		case deleted:
+			fmt.Fprintf(os.Stderr, "Removed %s from %s\n", t, ref)
+		}
+	}
+
+	// Signatures, attestations and SBOMs attached with
+	// --registry-referrers-mode=oci-1-1
+	// are referrers of the image rather than tags.
+	digest, err := ociremote.ResolveDigest(ref, c.ociremoteOpts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not resolve %s to remove its referrers\n: %v\n", ref, err)
+		return nil
+	}
+	for _, artifactType := range artifactTypes {
+		if c.opts.DryRun {
+			referrers, err := c.deleter.Referrers(digest, artifactType)
+			if err != nil {
+				return fmt.Errorf("listing the %s referrers of %s: %w", artifactType, ref, err)
+			}
+			for _, h := range referrers {
+				fmt.Fprintf(c.out, "Would remove %s referrer %s from %s\n", artifactType, h, ref)
+			}
+			continue
+		}
+		n, err := c.deleter.DeleteReferrers(digest, artifactType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not delete the %s referrers of %s\n: %v\n", artifactType, ref, err)
+			continue
+		}
+		if n > 0 {
+			fmt.Fprintf(os.Stderr, "Removed %d %s referrer(s) from %s\n", n, artifactType, ref)
 		}
 	}
 
 	return nil
 }
 
+// cleanSignedBy removes the signatures and attestations of the type of c
+// made by its signer from ref.
+func (c *cleaner) cleanSignedBy(ctx context.Context, ref name.Reference) error {
+	type target struct {
+		tag         name.Tag
+		attestation bool
+	}
+	var targets []target
+	if c.opts.Type == options.CleanTypeSignature || c.opts.Type == options.CleanTypeAll {
+		sigRef, err := ociremote.SignatureTag(ref, c.ociremoteOpts...)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target{tag: sigRef})
+	}
+	if c.opts.Type == options.CleanTypeAttestation || c.opts.Type == options.CleanTypeAll {
+		attRef, err := ociremote.AttestationTag(ref, c.ociremoteOpts...)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target{tag: attRef, attestation: true})
+	}
+
+	for _, t := range targets {
+		if _, _, err := c.removeSignedBy(ctx, t.tag, t.attestation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func prompt(cleanType options.CleanType) string {
 	switch cleanType {
 	case options.CleanTypeSignature:
@@ -124,3 +322,187 @@ func prompt(cleanType options.CleanType) string {
 	}
 	panic("invalid CleanType value")
 }
+
+// SignerMatcher selects the signatures and attestations made by one signer.
+type SignerMatcher struct {
+	verifier    signature.Verifier
+	fingerprint string
+	identity    cosign.Identity
+}
+
+// NewSignerMatcher returns a SignerMatcher for the signer identified by key,
+// a public key reference or the sha256:<hex> fingerprint of its DER
+// encoding, and by the certificate identity and OIDC issuer. Every criterion
+// that is set must match.
+func NewSignerMatcher(ctx context.Context, key, identity, issuer string) (*SignerMatcher, error) {
+	m := &SignerMatcher{identity: cosign.Identity{Subject: identity, Issuer: issuer}}
+	switch {
+	case strings.HasPrefix(key, "sha256:"):
+		m.fingerprint = strings.ToLower(key)
+	case key != "":
+		v, err := sigs.PublicKeyFromKeyRef(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("loading public key: %w", err)
+		}
+		pub, err := v.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if m.fingerprint, err = cosign.KeyFingerprint(pub); err != nil {
+			return nil, err
+		}
+		m.verifier = v
+	case identity == "" && issuer == "":
+		return nil, errors.New("a signing key or certificate identity is required")
+	}
+	return m, nil
+}
+
+func (m *SignerMatcher) String() string {
+	var parts []string
+	if m.fingerprint != "" {
+		parts = append(parts, "key "+m.fingerprint)
+	}
+	if m.identity.Subject != "" {
+		parts = append(parts, "identity "+m.identity.Subject)
+	}
+	if m.identity.Issuer != "" {
+		parts = append(parts, "OIDC issuer "+m.identity.Issuer)
+	}
+	return strings.Join(parts, " and ")
+}
+
+// Match reports whether sig was made by the signer. attestation selects how
+// a key that is given as a reference verifies sig.
+func (m *SignerMatcher) Match(ctx context.Context, sig oci.Signature, attestation bool) (bool, error) {
+	cert, err := sig.Cert()
+	if err != nil {
+		return false, err
+	}
+
+	if m.fingerprint != "" {
+		switch {
+		case cert != nil:
+			fp, err := cosign.KeyFingerprint(cert.PublicKey)
+			if err != nil || fp != m.fingerprint {
+				return false, nil //nolint: nilerr
+			}
+		case m.verifier != nil:
+			if verifySignature(ctx, m.verifier, sig, attestation) != nil {
+				return false, nil
+			}
+		default:
+			// Without the key, a signature without a certificate can't be
+			// attributed to a fingerprint.
+			return false, nil
+		}
+	}
+
+	if m.identity != (cosign.Identity{}) {
+		if cert == nil {
+			return false, nil
+		}
+		if err := cosign.CheckCertificatePolicy(cert, &cosign.CheckOpts{Identities: []cosign.Identity{m.identity}}); err != nil {
+			return false, nil //nolint: nilerr
+		}
+	}
+	return true, nil
+}
+
+func verifySignature(ctx context.Context, verifier signature.Verifier, sig oci.Signature, attestation bool) error {
+	payload, err := sig.Payload()
+	if err != nil {
+		return err
+	}
+	if attestation {
+		return dsse.WrapVerifier(verifier).VerifySignature(bytes.NewReader(payload), nil)
+	}
+	b64sig, err := sig.Base64Signature()
+	if err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(b64sig)
+	if err != nil {
+		return err
+	}
+	return verifier.VerifySignature(bytes.NewReader(raw), bytes.NewReader(payload))
+}
+
+// removeSignedBy rewrites the signatures or attestations stored in tag
+// without the entries that the signer of c matches, deleting tag once
+// nothing is left. It returns the number of entries removed, or that would
+// be in a dry run, and the number found.
+func (c *cleaner) removeSignedBy(ctx context.Context, tag name.Tag, attestation bool) (int, int, error) {
+	m := c.opts.SignedBy
+	sigList, err := ociremote.Signatures(tag, c.ociremoteOpts...)
+	if err != nil {
+		return 0, 0, err
+	}
+	all, err := sigList.Get()
+	if err != nil {
+		return 0, 0, err
+	}
+	var kept, matched []oci.Signature
+	for _, sig := range all {
+		match, err := m.Match(ctx, sig, attestation)
+		if err != nil {
+			return 0, 0, err
+		}
+		if match {
+			matched = append(matched, sig)
+		} else {
+			kept = append(kept, sig)
+		}
+	}
+
+	removed := len(matched)
+	switch {
+	case removed == 0:
+		fmt.Fprintf(os.Stderr, "Nothing made by %s in %s\n", m, tag)
+		return 0, len(all), nil
+	case c.opts.DryRun:
+		fmt.Fprintf(c.out, "Would remove %d of %d entries from %s, made by %s:\n", removed, len(all), tag, m)
+		for _, sig := range matched {
+			h, err := sig.Digest()
+			if err != nil {
+				return 0, 0, err
+			}
+			fmt.Fprintf(c.out, "  %s\n", h)
+		}
+		return removed, len(all), nil
+	}
+	if err := c.replaceEntries(tag, kept); err != nil {
+		return 0, 0, err
+	}
+	fmt.Fprintf(os.Stderr, "Removed %d of %d entries from %s, made by %s\n", removed, len(all), tag, m)
+	return removed, len(all), nil
+}
+
+// replaceEntries rewrites the signatures or attestations stored in tag with
+// kept only, deleting tag if kept is empty.
+func (c *cleaner) replaceEntries(tag name.Tag, kept []oci.Signature) error {
+	if len(kept) == 0 {
+		if _, err := c.deleter.DeleteTag(tag); err != nil {
+			return fmt.Errorf("deleting %s: %w", tag, err)
+		}
+		return nil
+	}
+	pruned, err := mutate.AppendSignatures(empty.Signatures(), kept...)
+	if err != nil {
+		return err
+	}
+	if err := remote.Write(tag, pruned, c.remoteOpts...); err != nil {
+		return fmt.Errorf("writing %s: %w", tag, err)
+	}
+	return nil
+}
+
+func signedByKind(cleanType options.CleanType) string {
+	switch cleanType {
+	case options.CleanTypeSignature:
+		return "signatures"
+	case options.CleanTypeAttestation:
+		return "attestations"
+	}
+	return "signatures and attestations"
+}
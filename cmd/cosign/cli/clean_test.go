@@ -0,0 +1,354 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestCleanSignedByCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two signatures made with keys, and one made by a keyless identity.
+	payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + h.String() + `"}}}`)
+	compromisedSigner, compromisedKey := newTestKey(t)
+	otherSigner, otherKey := newTestKey(t)
+	compromised := signPayload(t, compromisedSigner, payload)
+	other := signPayload(t, otherSigner, payload)
+
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	leafCert, _, _ := test.GenerateLeafCert("user@example.com", "https://issuer.example.com", rootCert, rootKey)
+	leafPEM, _ := cryptoutils.MarshalCertificateToPEM(leafCert)
+	keyless, err := static.NewSignature(payload, "c2lnbmF0dXJl", static.WithCertChain(leafPEM, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sigs, err := mutate.AppendSignatures(empty.Signatures(), compromised, other, keyless)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sigTag, sigs); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining := func() int {
+		t.Helper()
+		got, err := ociremote.Signatures(sigTag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l, err := got.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(l)
+	}
+
+	for _, tt := range []struct {
+		name                  string
+		key, identity, issuer string
+		want                  int
+	}{
+		{name: "compromised key", key: compromisedKey, want: 2},
+		{name: "identity from another issuer", identity: "user@example.com", issuer: "https://other.example.com", want: 2},
+		{name: "keyless identity", identity: "user@example.com", issuer: "https://issuer.example.com", want: 1},
+		{name: "last signature", key: otherKey, want: 0},
+	} {
+		m, err := NewSignerMatcher(ctx, tt.key, tt.identity, tt.issuer)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if err := CleanSignedByCmd(ctx, options.RegistryOptions{}, options.CleanTypeAll, ref.String(), m, true); err != nil {
+			t.Fatalf("%s: CleanSignedByCmd() = %v", tt.name, err)
+		}
+		if got := remaining(); got != tt.want {
+			t.Errorf("%s: %d signatures remaining, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := NewSignerMatcher(ctx, "", "", ""); err == nil {
+		t.Error("expected an error without a key or identity")
+	}
+	m, _ := NewSignerMatcher(ctx, "sha256:abc", "", "")
+	if err := CleanSignedByCmd(ctx, options.RegistryOptions{}, options.CleanTypeSbom, ref.String(), m, true); err == nil {
+		t.Error("expected an error when removing SBOMs by signer")
+	}
+}
+
+func TestCleanImageCmdDryRunRecursive(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	idx, err := random.Index(100, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := idx.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := name.NewRepository(host + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.WriteIndex(repo.Tag("latest"), idx); err != nil {
+		t.Fatal(err)
+	}
+	im, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The index and each of its images are signed with a retired key and
+	// another key.
+	retiredSigner, retiredKey := newTestKey(t)
+	otherSigner, _ := newTestKey(t)
+	var sigTags []name.Tag
+	for _, d := range append([]v1.Hash{h}, im.Manifests[0].Digest, im.Manifests[1].Digest) {
+		payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + d.String() + `"}}}`)
+		sigs, err := mutate.AppendSignatures(empty.Signatures(), signPayload(t, retiredSigner, payload), signPayload(t, otherSigner, payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigTag, err := ociremote.SignatureTag(repo.Digest(d.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(sigTag, sigs); err != nil {
+			t.Fatal(err)
+		}
+		sigTags = append(sigTags, sigTag)
+	}
+	remaining := func() []int {
+		t.Helper()
+		var counts []int
+		for _, tag := range sigTags {
+			got, err := ociremote.Signatures(tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l, err := got.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			counts = append(counts, len(l))
+		}
+		return counts
+	}
+
+	m, err := NewSignerMatcher(ctx, retiredKey, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	o := CleanOpts{Type: options.CleanTypeSignature, SignedBy: m, DryRun: true, Recursive: true}
+	if err := CleanImageCmd(ctx, options.RegistryOptions{}, repo.Tag("latest").String(), o, &out); err != nil {
+		t.Fatalf("CleanImageCmd() = %v", err)
+	}
+	if got := strings.Count(out.String(), "Would remove 1 of 2 entries"); got != 3 {
+		t.Errorf("CleanImageCmd() dry run wrote %q, want the signatures of the index and its 2 images", out.String())
+	}
+	if got := remaining(); got[0] != 2 || got[1] != 2 || got[2] != 2 {
+		t.Errorf("dry run left %v signatures, want all of them", got)
+	}
+
+	o.DryRun, o.Force = false, true
+	if err := CleanImageCmd(ctx, options.RegistryOptions{}, repo.Tag("latest").String(), o, &out); err != nil {
+		t.Fatalf("CleanImageCmd() = %v", err)
+	}
+	if got := remaining(); got[0] != 1 || got[1] != 1 || got[2] != 1 {
+		t.Errorf("%v signatures remaining, want those made with the other key", got)
+	}
+
+	out.Reset()
+	o = CleanOpts{Type: options.CleanTypeAll, DryRun: true, Recursive: true}
+	if err := CleanImageCmd(ctx, options.RegistryOptions{}, repo.Tag("latest").String(), o, &out); err != nil {
+		t.Fatalf("CleanImageCmd() = %v", err)
+	}
+	for _, tag := range sigTags {
+		if !strings.Contains(out.String(), "Would remove "+tag.String()) {
+			t.Errorf("CleanImageCmd() dry run wrote %q, want %s", out.String(), tag)
+		}
+	}
+	if strings.Contains(out.String(), ".att") {
+		t.Errorf("CleanImageCmd() dry run wrote %q, want only the existing tags", out.String())
+	}
+
+	o.DryRun, o.Force = false, true
+	if err := CleanImageCmd(ctx, options.RegistryOptions{}, repo.Tag("latest").String(), o, io.Discard); err != nil {
+		t.Fatalf("CleanImageCmd() = %v", err)
+	}
+	if got := remaining(); got[0] != 0 || got[1] != 0 || got[2] != 0 {
+		t.Errorf("%v signatures remaining, want none", got)
+	}
+}
+
+func TestCleanImageCmdDigest(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	// The image is pushed by digest only, as in tag-less promotion pipelines.
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, _ := newTestKey(t)
+	payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + h.String() + `"}}}`)
+	sigs, err := mutate.AppendSignatures(empty.Signatures(), signPayload(t, signer, payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attTag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []name.Tag{sigTag, attTag} {
+		if err := remote.Write(tag, sigs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	o := CleanOpts{Type: options.CleanTypeAll, DryRun: true}
+	if err := CleanImageCmd(ctx, options.RegistryOptions{}, ref.String(), o, &out); err != nil {
+		t.Fatalf("CleanImageCmd() = %v", err)
+	}
+	for _, tag := range []name.Tag{sigTag, attTag} {
+		if want := "Would remove " + tag.String() + " from " + ref.String(); !strings.Contains(out.String(), want) {
+			t.Errorf("CleanImageCmd() dry run wrote %q, want %q", out.String(), want)
+		}
+	}
+
+	o = CleanOpts{Type: options.CleanTypeSignature, Force: true}
+	if err := CleanImageCmd(ctx, options.RegistryOptions{}, ref.String(), o, io.Discard); err != nil {
+		t.Fatalf("CleanImageCmd() = %v", err)
+	}
+	if _, err := remote.Head(sigTag); err == nil {
+		t.Errorf("%s still exists", sigTag)
+	}
+	if _, err := remote.Head(attTag); err != nil {
+		t.Errorf("cleaning signatures removed %s: %v", attTag, err)
+	}
+	if _, err := remote.Head(ref); err != nil {
+		t.Errorf("cleaning signatures removed the image: %v", err)
+	}
+}
+
+// newTestKey returns a new signer and the path to its public key.
+func newTestKey(t *testing.T) (signature.SignerVerifier, string) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pub")
+	if err := os.WriteFile(path, pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return sv, path
+}
+
+func signPayload(t *testing.T, signer signature.Signer, payload []byte) oci.Signature {
+	t.Helper()
+	raw, err := signer.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(payload, base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
@@ -18,15 +18,22 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/logs"
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
-	"sigs.k8s.io/release-utils/version"
 
-	cranecmd "github.com/google/go-containerregistry/cmd/crane/cmd"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/templates"
+	"github.com/sigstore/cosign/v2/internal/pkg/batch"
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tufcache"
+	"github.com/sigstore/cosign/v2/internal/pkg/events"
+	"github.com/sigstore/cosign/v2/internal/pkg/gcpauth"
+	"github.com/sigstore/cosign/v2/internal/pkg/profile"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 	cobracompletefig "github.com/withfig/autocomplete-tools/integrations/cobra"
 )
 
@@ -34,23 +41,9 @@ var (
 	ro = &options.RootOptions{}
 )
 
-func normalizeCertificateFlags(_ *pflag.FlagSet, name string) pflag.NormalizedName {
-	switch name {
-	case "cert":
-		name = "certificate"
-	case "cert-email":
-		name = "certificate-email"
-	case "cert-chain":
-		name = "certificate-chain"
-	case "cert-oidc-issuer":
-		name = "certificate-oidc-issuer"
-	case "output-cert":
-		name = "output-certificate"
-	case "cert-identity":
-		name = "certificate-identity"
-	}
-	return pflag.NormalizedName(name)
-}
+// tufRefreshWait is how long a command waits on exit for the background
+// refresh of the TUF snapshot.
+const tufRefreshWait = 30 * time.Second
 
 func New() *cobra.Command {
 	var (
@@ -78,9 +71,47 @@ func New() *cobra.Command {
 				logs.Debug.SetOutput(os.Stderr)
 			}
 
+			// A misspelled variable silently leaves its setting, which may be
+			// a security check, at its default.
+			if cmd.Name() != cobra.ShellCompRequestCmd {
+				for _, w := range env.Check(os.Environ(), options.FlagEnvVars(cmd.Root())) {
+					ui.Warnf(cmd.Context(), "%s", w)
+				}
+			}
+
+			if ro.EventsFD >= 0 {
+				f, err := events.OpenFD(ro.EventsFD)
+				if err != nil {
+					return fmt.Errorf("--events-fd: %w", err)
+				}
+				events.SetOutput(f)
+			}
+			batch.SetSummaryFile(ro.SummaryFile)
+			if err := tufcache.SetRefresh(ro.TUFRefresh); err != nil {
+				return fmt.Errorf("--tuf-refresh: %w", err)
+			}
+			if err := tufcache.SetCache(ro.TUFCache); err != nil {
+				return fmt.Errorf("--tuf-cache: %w", err)
+			}
+			if err := gcpauth.SetServiceAccount(ro.ImpersonateServiceAccount); err != nil {
+				return fmt.Errorf("--impersonate-service-account: %w", err)
+			}
+
+			command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+			if err := budget.Load(command); err != nil {
+				return err
+			}
+			if err := profile.Load(ro.Profile, command, cmd.Flags()); err != nil {
+				return err
+			}
+
 			return nil
 		},
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			// Let the background refresh of the TUF snapshot finish, so the
+			// next run reads it.
+			tufcache.Wait(tufRefreshWait)
+			tufcache.Close()
 			if out != nil {
 				_ = out.Close()
 			}
@@ -95,36 +126,67 @@ func New() *cobra.Command {
 	cmd.AddCommand(Attach())
 	cmd.AddCommand(Attest())
 	cmd.AddCommand(AttestBlob())
+	cmd.AddCommand(Audit())
+	cmd.AddCommand(Backfill())
+	cmd.AddCommand(Bench())
+	cmd.AddCommand(Bundle())
+	cmd.AddCommand(Certificate())
 	cmd.AddCommand(Clean())
 	cmd.AddCommand(Tree())
 	cmd.AddCommand(Completion())
+	cmd.AddCommand(Conformance())
+	cmd.AddCommand(Convert())
 	cmd.AddCommand(Copy())
+	cmd.AddCommand(Countersign())
+	cmd.AddCommand(Daemon())
+	cmd.AddCommand(Dev())
 	cmd.AddCommand(Dockerfile())
+	cmd.AddCommand(Embed())
+	cmd.AddCommand(Doctor())
 	cmd.AddCommand(Download())
+	cmd.AddCommand(ExportPolicy())
+	cmd.AddCommand(Find())
 	cmd.AddCommand(Generate())
 	cmd.AddCommand(GenerateKeyPair())
 	cmd.AddCommand(ImportKeyPair())
+	cmd.AddCommand(Inspect())
 	cmd.AddCommand(Initialize())
+	cmd.AddCommand(LintPipeline())
+	cmd.AddCommand(LintPolicy())
 	cmd.AddCommand(Load())
+	cmd.AddCommand(Login())
 	cmd.AddCommand(Manifest())
+	cmd.AddCommand(MigrateFlags())
+	cmd.AddCommand(Mutate())
 	cmd.AddCommand(PIVTool())
 	cmd.AddCommand(PKCS11Tool())
+	cmd.AddCommand(Policy())
+	cmd.AddCommand(Prefetch())
+	cmd.AddCommand(Promote())
+	cmd.AddCommand(Proxy())
 	cmd.AddCommand(PublicKey())
+	cmd.AddCommand(Retention())
+	cmd.AddCommand(RevokeKey())
 	cmd.AddCommand(Save())
+	cmd.AddCommand(SBOM())
+	cmd.AddCommand(Scan())
+	cmd.AddCommand(Server())
 	cmd.AddCommand(Sign())
 	cmd.AddCommand(SignBlob())
+	cmd.AddCommand(Store())
 	cmd.AddCommand(Upload())
 	cmd.AddCommand(Verify())
 	cmd.AddCommand(VerifyAttestation())
 	cmd.AddCommand(VerifyBlob())
 	cmd.AddCommand(VerifyBlobAttestation())
+	cmd.AddCommand(VerifyChecksums())
 	cmd.AddCommand(Triangulate())
+	cmd.AddCommand(TrustedRoot())
+	cmd.AddCommand(TSA())
 	cmd.AddCommand(Env())
-	cmd.AddCommand(version.WithFont("starwars"))
-
-	cmd.AddCommand(cranecmd.NewCmdAuthLogin("cosign"))
+	cmd.AddCommand(Version())
 
-	cmd.SetGlobalNormalizationFunc(normalizeCertificateFlags)
+	options.AddFlagMigrations(cmd)
 	cmd.AddCommand(cobracompletefig.CreateCompletionSpecCommand())
 
 	return cmd
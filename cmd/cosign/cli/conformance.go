@@ -0,0 +1,407 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attach"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	ociexperimental "github.com/sigstore/cosign/v2/internal/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	ctypes "github.com/sigstore/cosign/v2/pkg/types"
+)
+
+func Conformance() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conformance",
+		Short: "Provides utilities for checking which cosign features work with a service",
+	}
+
+	cmd.AddCommand(
+		conformanceRegistry(),
+	)
+
+	return cmd
+}
+
+func conformanceRegistry() *cobra.Command {
+	o := &options.ConformanceRegistryOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Check which cosign features work with a registry",
+		Long: `Run the sign, attach, discover and delete operations of cosign against a
+repository, with test images and a throwaway key, and report which cosign
+features work with its registry: signatures and attestations stored with the
+cosign tag schema, attached SBOMs, signatures stored as OCI 1.1 referrers, and
+cosign clean.
+
+The test images are pushed to the repository, and deleted with everything
+pushed for them where the registry allows it. Nothing is uploaded to the
+transparency log. The command fails if a feature doesn't work.`,
+		Example: `  cosign conformance registry example.com/cosign-conformance
+
+  # report the results in JSON
+  cosign conformance registry --output json example.com/cosign-conformance`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ConformanceRegistryCmd(cmd.Context(), *o, args[0])
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// The statuses of a ConformanceResult.
+const (
+	ConformanceSupported   = "supported"
+	ConformanceUnsupported = "unsupported"
+	ConformanceSkipped     = "skipped"
+)
+
+// ConformanceResult is whether a cosign feature works with a registry.
+type ConformanceResult struct {
+	Feature string `json:"feature"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail"`
+}
+
+// ConformanceRegistryCmd runs the registry conformance suite against
+// repository, prints its results, and returns an error if a feature doesn't
+// work.
+func ConformanceRegistryCmd(ctx context.Context, o options.ConformanceRegistryOptions, repository string) error {
+	if o.Output != "text" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, must be text or json", o.Output)
+	}
+	results, err := RunRegistryConformance(ctx, o, repository)
+	if err != nil {
+		return err
+	}
+
+	if o.Output == "json" {
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		for _, r := range results {
+			fmt.Printf("%-11s %s: %s\n", strings.ToUpper(r.Status), r.Feature, r.Detail)
+		}
+	}
+
+	unsupported := 0
+	for _, r := range results {
+		if r.Status == ConformanceUnsupported {
+			unsupported++
+		}
+	}
+	if unsupported > 0 {
+		return fmt.Errorf("%d of %d features don't work with the registry of %s", unsupported, len(results), repository)
+	}
+	return nil
+}
+
+// RunRegistryConformance runs the registry conformance suite against
+// repository and returns whether each feature works. It only returns an
+// error if the suite can't be run.
+func RunRegistryConformance(ctx context.Context, o options.ConformanceRegistryOptions, repository string) ([]ConformanceResult, error) {
+	repo, err := name.NewRepository(repository, o.Registry.NameOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	ropts, err := o.Registry.ClientOpts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("constructing client options: %w", err)
+	}
+	c := &conformance{o: o, repo: repo, ropts: ropts}
+	if err := c.generateKey(ctx); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(c.dir)
+
+	// Signatures are tried with the cosign tag schema on one image and as
+	// OCI 1.1 referrers on another, as verification finds either.
+	tagged, referred := c.push(ctx, "images"), c.push(ctx, "")
+	if tagged == nil || referred == nil {
+		for _, f := range []string{"signatures", "attestations", "sbom", "referrers", "clean"} {
+			c.skip(f, "the test images couldn't be pushed")
+		}
+		return c.results, nil
+	}
+	c.signatures(ctx, tagged)
+	c.attestations(ctx, tagged)
+	c.sbom(ctx, tagged)
+	c.referrers(ctx, referred)
+	c.clean(tagged, referred)
+	return c.results, nil
+}
+
+// conformance is the state of a run of the registry conformance suite.
+type conformance struct {
+	o     options.ConformanceRegistryOptions
+	repo  name.Repository
+	ropts []ociremote.Option
+	// dir holds the throwaway key pair.
+	dir     string
+	ko      options.KeyOpts
+	co      *cosign.CheckOpts
+	results []ConformanceResult
+}
+
+// testImage is an image pushed by the suite.
+type testImage struct {
+	tag    name.Tag
+	digest name.Digest
+}
+
+func (c *conformance) report(feature string, err error, detail string) {
+	if err != nil {
+		c.results = append(c.results, ConformanceResult{Feature: feature, Status: ConformanceUnsupported, Detail: err.Error()})
+		return
+	}
+	c.results = append(c.results, ConformanceResult{Feature: feature, Status: ConformanceSupported, Detail: detail})
+}
+
+func (c *conformance) skip(feature, detail string) {
+	c.results = append(c.results, ConformanceResult{Feature: feature, Status: ConformanceSkipped, Detail: detail})
+}
+
+// generateKey writes a throwaway key pair without a password, and sets up
+// the key options to sign and the check options to verify with it.
+func (c *conformance) generateKey(ctx context.Context) error {
+	dir, err := os.MkdirTemp("", "cosign-conformance")
+	if err != nil {
+		return err
+	}
+	c.dir = dir
+	noPassword := func(bool) ([]byte, error) { return []byte{}, nil }
+	keys, err := cosign.GenerateKeyPair(noPassword)
+	if err != nil {
+		return err
+	}
+	priv, pub := filepath.Join(dir, "cosign.key"), filepath.Join(dir, "cosign.pub")
+	if err := os.WriteFile(priv, keys.PrivateBytes, 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(pub, keys.PublicBytes, 0o600); err != nil {
+		return err
+	}
+	verifier, err := sigs.PublicKeyFromKeyRef(ctx, pub)
+	if err != nil {
+		return err
+	}
+	c.ko = options.KeyOpts{KeyRef: priv, PassFunc: noPassword, SkipConfirmation: true}
+	c.co = &cosign.CheckOpts{
+		RegistryClientOpts: c.ropts,
+		SigVerifier:        verifier,
+		IgnoreTlog:         true,
+		IgnoreSCT:          true,
+	}
+	return nil
+}
+
+// push pushes a random test image, reporting the outcome as the images
+// feature if feature is set.
+func (c *conformance) push(ctx context.Context, feature string) *testImage {
+	img, err := random.Image(256, 1)
+	if err != nil {
+		c.report(feature, err, "")
+		return nil
+	}
+	h, err := img.Digest()
+	if err != nil {
+		c.report(feature, err, "")
+		return nil
+	}
+	// Tag the image so that registries don't garbage collect it as untagged.
+	tag := c.repo.Tag("cosign-conformance-" + h.Hex[:12])
+	err = remote.Write(tag, img, c.o.Registry.GetRegistryClientOpts(ctx)...)
+	if feature != "" {
+		c.report(feature, err, fmt.Sprintf("pushed the test image %s", tag))
+	}
+	if err != nil {
+		return nil
+	}
+	return &testImage{tag: tag, digest: c.repo.Digest(h.String())}
+}
+
+func (c *conformance) signOptions(mode options.RegistryReferrersMode) options.SignOptions {
+	return options.SignOptions{
+		Upload:               true,
+		SkipConfirmation:     true,
+		Registry:             c.o.Registry,
+		RegistryExperimental: options.RegistryExperimentalOptions{RegistryReferrersMode: mode},
+	}
+}
+
+func (c *conformance) sign(ctx context.Context, img *testImage, mode options.RegistryReferrersMode) error {
+	if err := sign.SignCmd(ctx, &options.RootOptions{Timeout: options.DefaultTimeout}, c.ko, c.signOptions(mode), []string{img.digest.String()}); err != nil {
+		return fmt.Errorf("signing: %w", err)
+	}
+	return nil
+}
+
+// signatures signs the image with the cosign tag schema and verifies it.
+func (c *conformance) signatures(ctx context.Context, img *testImage) {
+	err := c.sign(ctx, img, options.RegistryReferrersModeLegacy)
+	if err == nil {
+		co := *c.co
+		co.ClaimVerifier = cosign.SimpleClaimVerifier
+		if _, _, err = cosign.VerifyImageSignatures(ctx, img.digest, &co); err != nil {
+			err = fmt.Errorf("verifying: %w", err)
+		}
+	}
+	tag, _ := ociremote.SignatureTag(img.digest, c.ropts...)
+	c.report("signatures", err, fmt.Sprintf("signed and verified with the signature tag %s", tag))
+}
+
+// attestations attests the image with the cosign tag schema and verifies it.
+func (c *conformance) attestations(ctx context.Context, img *testImage) {
+	predicate := filepath.Join(c.dir, "predicate.json")
+	err := os.WriteFile(predicate, []byte(`{"conformance":true}`), 0o600)
+	if err == nil {
+		a := &attest.AttestCommand{
+			KeyOpts:         c.ko,
+			RegistryOptions: c.o.Registry,
+			PredicatePath:   predicate,
+			PredicateType:   options.PredicateCustom,
+			Timeout:         options.DefaultTimeout,
+		}
+		if err = a.Exec(ctx, img.digest.String()); err != nil {
+			err = fmt.Errorf("attesting: %w", err)
+		}
+	}
+	if err == nil {
+		co := *c.co
+		co.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
+		if _, _, err = cosign.VerifyImageAttestations(ctx, img.digest, &co); err != nil {
+			err = fmt.Errorf("verifying: %w", err)
+		}
+	}
+	tag, _ := ociremote.AttestationTag(img.digest, c.ropts...)
+	c.report("attestations", err, fmt.Sprintf("attested and verified with the attestation tag %s", tag))
+}
+
+// sbom attaches an SBOM to the image and downloads it.
+func (c *conformance) sbom(ctx context.Context, img *testImage) {
+	want := []byte(`{"spdxVersion":"SPDX-2.3","name":"cosign-conformance"}`)
+	path := filepath.Join(c.dir, "sbom.spdx.json")
+	err := os.WriteFile(path, want, 0o600)
+	if err == nil {
+		if err = attach.SBOMCmd(ctx, c.o.Registry, options.RegistryExperimentalOptions{}, path, ctypes.SPDXJSONMediaType, img.digest.String()); err != nil {
+			err = fmt.Errorf("attaching: %w", err)
+		}
+	}
+	if err == nil {
+		err = func() error {
+			se, err := ociremote.SignedEntity(img.digest, c.ropts...)
+			if err != nil {
+				return err
+			}
+			f, err := se.Attachment("sbom")
+			if err != nil {
+				return err
+			}
+			got, err := f.Payload()
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(got, want) {
+				return errors.New("the attached SBOM differs from the downloaded one")
+			}
+			return nil
+		}()
+		if err != nil {
+			err = fmt.Errorf("downloading: %w", err)
+		}
+	}
+	tag, _ := ociremote.SBOMTag(img.digest, c.ropts...)
+	c.report("sbom", err, fmt.Sprintf("attached and downloaded with the SBOM tag %s", tag))
+}
+
+// referrers signs the image with an OCI 1.1 referrer and verifies it.
+func (c *conformance) referrers(ctx context.Context, img *testImage) {
+	err := c.sign(ctx, img, options.RegistryReferrersModeOCI11)
+	if err == nil {
+		co := *c.co
+		co.ClaimVerifier = cosign.SimpleClaimVerifier
+		if _, _, err = cosign.VerifyImageSignatures(ctx, img.digest, &co); err != nil {
+			err = fmt.Errorf("verifying: %w", err)
+		}
+	}
+	detail := "signed and verified with the referrers API"
+	if !ociremote.RegistryCapabilities(c.repo.RegistryStr()).Referrers {
+		detail = "signed and verified with the referrers tag schema, as the registry doesn't serve the referrers API"
+	}
+	c.report("referrers", err, detail)
+}
+
+// clean deletes everything the suite pushed, as cosign clean does.
+func (c *conformance) clean(tagged, referred *testImage) {
+	d := ociremote.NewDeleter(c.ropts...)
+	err := func() error {
+		var tags []name.Tag
+		for _, f := range []func(name.Reference, ...ociremote.Option) (name.Tag, error){ociremote.SignatureTag, ociremote.AttestationTag, ociremote.SBOMTag} {
+			tag, err := f(tagged.digest, c.ropts...)
+			if err != nil {
+				return err
+			}
+			tags = append(tags, tag)
+		}
+		for _, tag := range tags {
+			if _, err := d.DeleteTag(tag); err != nil {
+				return fmt.Errorf("deleting %s: %w", tag, err)
+			}
+		}
+		if _, err := d.DeleteReferrers(referred.digest, ociexperimental.ArtifactType("sig")); err != nil {
+			return fmt.Errorf("deleting the referrers of %s: %w", referred.digest, err)
+		}
+		for _, img := range []*testImage{tagged, referred} {
+			if _, err := d.DeleteTag(img.tag); err != nil {
+				return fmt.Errorf("deleting the test image %s: %w", img.tag, err)
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		err = fmt.Errorf("%w, the test images and what was pushed for them may remain in %s", err, c.repo)
+	}
+	detail := "deleted the test images and what was pushed for them by digest"
+	if !ociremote.RegistryCapabilities(c.repo.RegistryStr()).DeleteByDigest {
+		detail = "deleted the test images and what was pushed for them by tag, as the registry rejects deleting manifests by digest"
+	}
+	c.report("clean", err, detail)
+}
@@ -0,0 +1,89 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func TestRunRegistryConformance(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		referrersAPI  bool
+		wantReferrers string
+	}{{
+		name:          "referrers API",
+		referrersAPI:  true,
+		wantReferrers: "with the referrers API",
+	}, {
+		name:          "referrers tag schema",
+		wantReferrers: "with the referrers tag schema",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(registry.New(registry.WithReferrersSupport(tt.referrersAPI), registry.Logger(log.New(io.Discard, "", 0))))
+			t.Cleanup(s.Close)
+			repository := strings.TrimPrefix(s.URL, "http://") + "/conformance"
+
+			results, err := RunRegistryConformance(context.Background(), options.ConformanceRegistryOptions{Output: "text"}, repository)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []string{"images", "signatures", "attestations", "sbom", "referrers", "clean"}
+			if len(results) != len(want) {
+				t.Fatalf("results = %+v, want %v", results, want)
+			}
+			for i, r := range results {
+				if r.Feature != want[i] || r.Status != ConformanceSupported {
+					t.Errorf("result %d = %+v, want %s supported", i, r, want[i])
+				}
+				if r.Feature == "referrers" && !strings.Contains(r.Detail, tt.wantReferrers) {
+					t.Errorf("referrers detail = %q, want %q", r.Detail, tt.wantReferrers)
+				}
+			}
+
+			repo, err := name.NewRepository(repository)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tags, err := remote.List(repo); err != nil || len(tags) > 0 {
+				t.Errorf("tags left after the suite = %v, %v", tags, err)
+			}
+		})
+	}
+}
+
+func TestConformanceRegistryCmdFails(t *testing.T) {
+	// Nothing listens on port 1.
+	o := options.ConformanceRegistryOptions{Output: "json"}
+	if err := ConformanceRegistryCmd(context.Background(), o, "127.0.0.1:1/conformance"); err == nil || !strings.Contains(err.Error(), "1 of 6 features") {
+		t.Errorf("ConformanceRegistryCmd() error = %v, want 1 unsupported feature", err)
+	}
+	o.Output = "yaml"
+	if err := ConformanceRegistryCmd(context.Background(), o, "127.0.0.1:1/conformance"); err == nil {
+		t.Error("ConformanceRegistryCmd() with an unsupported output format succeeded")
+	}
+}
@@ -0,0 +1,119 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/convert"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func Convert() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Provides utilities for converting the artifacts attached to images",
+	}
+
+	cmd.AddCommand(
+		convertSBOMToAttestation(),
+	)
+
+	return cmd
+}
+
+func convertSBOMToAttestation() *cobra.Command {
+	o := &options.ConvertSBOMOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sbom-to-attestation",
+		Short: "Convert the SBOMs attached to images to signed attestations",
+		Long: `Convert the SBOMs attached to images with 'cosign attach sbom' to signed
+in-toto attestations of the images.
+
+The SPDX or CycloneDX JSON SBOM attached to each image is attested with the
+spdx, spdxjson or cyclonedx predicate type of its format, signed with the key
+or keyless identity, and pushed as an OCI 1.1 referrer of the image unless
+--registry-referrers-mode says otherwise. With --delete-sbom, the legacy SBOM
+tag of the image is deleted once its SBOM is attested.`,
+		Example: `  # convert the SBOM of an image, signing it with a key
+  cosign convert sbom-to-attestation --key cosign.key <IMAGE>
+
+  # convert the SBOM keyless and delete the legacy SBOM tag
+  cosign convert sbom-to-attestation --delete-sbom <IMAGE>
+
+  # convert the SBOM to an attestation on the legacy attestation tag
+  cosign convert sbom-to-attestation --key cosign.key --registry-referrers-mode legacy <IMAGE>`,
+		Args:             cobra.MinimumNArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.RegistryExperimental.CheckFeatureGates(); err != nil {
+				return err
+			}
+			oidcClientSecret, err := o.OIDC.ClientSecret()
+			if err != nil {
+				return err
+			}
+			ko := options.KeyOpts{
+				KeyRef:                   o.Key,
+				PassFunc:                 generate.GetPass,
+				Sk:                       o.SecurityKey.Use,
+				Slot:                     o.SecurityKey.Slot,
+				FulcioURL:                o.Fulcio.URL,
+				IDToken:                  o.Fulcio.IdentityToken,
+				InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                     o.Fulcio.ACME,
+				RekorURL:                 o.Rekor.URL,
+				OIDCIssuer:               o.OIDC.Issuer,
+				OIDCClientID:             o.OIDC.ClientID,
+				OIDCClientSecret:         oidcClientSecret,
+				OIDCRedirectURL:          o.OIDC.RedirectURL,
+				OIDCProvider:             o.OIDC.Provider,
+				OIDCTokenFile:            o.OIDC.TokenFile,
+				OIDCAudience:             o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
+				SkipConfirmation:         o.SkipConfirmation,
+				TSAServerURL:             o.TSAServerURL,
+			}
+			c := convert.SBOMToAttestationCommand{
+				AttestCommand: attest.AttestCommand{
+					KeyOpts:         ko,
+					RegistryOptions: o.Registry,
+					CertPath:        o.Cert,
+					CertChainPath:   o.CertChain,
+					Timeout:         ro.Timeout,
+					TlogUpload:      o.TlogUpload,
+
+					RegistryReferrersMode: o.RegistryExperimental.RegistryReferrersMode,
+				},
+				DeleteSBOM: o.DeleteSBOM,
+			}
+			for _, img := range args {
+				if err := c.Exec(cmd.Context(), img); err != nil {
+					return fmt.Errorf("converting the SBOM of %s: %w", img, err)
+				}
+			}
+			return nil
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
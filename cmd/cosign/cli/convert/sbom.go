@@ -0,0 +1,118 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	ctypes "github.com/sigstore/cosign/v2/pkg/types"
+)
+
+// SBOMToAttestationCommand converts the SBOM attached to an image with
+// `cosign attach sbom` to an in-toto attestation of the image, signed with
+// the key or identity of the AttestCommand.
+type SBOMToAttestationCommand struct {
+	attest.AttestCommand
+	// DeleteSBOM deletes the legacy SBOM tag of the image once the SBOM is
+	// attested.
+	DeleteSBOM bool
+}
+
+// sbomPredicateType returns the predicate type of the attestation of an
+// SBOM of mediaType.
+func sbomPredicateType(mediaType string) (string, error) {
+	switch mediaType {
+	case ctypes.SPDXJSONMediaType:
+		return options.PredicateSPDXJSON, nil
+	case ctypes.SPDXMediaType:
+		return options.PredicateSPDX, nil
+	case ctypes.CycloneDXJSONMediaType:
+		return options.PredicateCycloneDX, nil
+	default:
+		return "", fmt.Errorf("SBOMs of media type %q can't be converted to attestations, only SPDX and CycloneDX JSON SBOMs can", mediaType)
+	}
+}
+
+// Exec attests the SBOM attached to imageRef.
+func (c *SBOMToAttestationCommand) Exec(ctx context.Context, imageRef string) error {
+	if c.LocalImage.Enabled {
+		return errors.New("attached SBOMs of images on disk can't be converted")
+	}
+	ref, err := oci.ParseReference(imageRef, c.NameOptions()...)
+	if err != nil {
+		return fmt.Errorf("parsing reference: %w", err)
+	}
+	ociremoteOpts, err := c.RegistryOptions.ClientOpts(ctx)
+	if err != nil {
+		return err
+	}
+	digest, err := ociremote.ResolveDigest(ref, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	se, err := ociremote.SignedEntity(digest, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	f, err := se.Attachment("sbom")
+	if err != nil {
+		return fmt.Errorf("getting the SBOM attached to %s: %w", imageRef, err)
+	}
+	mediaType, err := f.FileMediaType()
+	if err != nil {
+		return err
+	}
+	if c.PredicateType, err = sbomPredicateType(string(mediaType)); err != nil {
+		return err
+	}
+	sbom, err := f.Payload()
+	if err != nil {
+		return err
+	}
+
+	ui.Infof(ctx, "Attesting the %s SBOM attached to %s", mediaType, digest)
+	c.GeneratePredicate = func(context.Context, name.Digest) ([]byte, error) {
+		return sbom, nil
+	}
+	if err := c.AttestCommand.Exec(ctx, digest.String()); err != nil {
+		return err
+	}
+	if !c.DeleteSBOM || c.DryRun {
+		return nil
+	}
+
+	sbomTag, err := ociremote.SBOMTag(digest, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	deleted, err := ociremote.NewDeleter(ociremoteOpts...).DeleteTag(sbomTag)
+	if err != nil {
+		return fmt.Errorf("deleting %s: %w", sbomTag, err)
+	}
+	if deleted {
+		ui.Infof(ctx, "Removed the legacy SBOM tag %s", sbomTag)
+	}
+	return nil
+}
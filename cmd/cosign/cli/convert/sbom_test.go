@@ -0,0 +1,206 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	ocitypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/in-toto/in-toto-golang/in_toto"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	ctypes "github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestSBOMToAttestationCommand(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := filepath.Join(t.TempDir(), "cosign.key")
+	if err := os.WriteFile(keyRef, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.UnmarshalPEMToPublicKey(keys.PublicBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := signature.LoadVerifier(pub, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	// pushImage pushes an image with an SBOM of mediaType on its legacy
+	// SBOM tag.
+	pushImage := func(t *testing.T, repo, sbom string, mediaType string) name.Digest {
+		t.Helper()
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest, err := name.NewDigest(host + "/" + repo + "@" + h.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(digest.Context().Tag("latest"), img); err != nil {
+			t.Fatal(err)
+		}
+		f, err := static.NewFile([]byte(sbom), static.WithLayerMediaType(ocitypes.MediaType(mediaType)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sbomTag, err := ociremote.SBOMTag(digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(sbomTag, f); err != nil {
+			t.Fatal(err)
+		}
+		return digest
+	}
+	convert := func(mode options.RegistryReferrersMode, deleteSBOM bool) *SBOMToAttestationCommand {
+		return &SBOMToAttestationCommand{
+			AttestCommand: attest.AttestCommand{
+				KeyOpts:               options.KeyOpts{KeyRef: keyRef},
+				RegistryReferrersMode: mode,
+			},
+			DeleteSBOM: deleteSBOM,
+		}
+	}
+
+	digest := pushImage(t, "app", `{"bomFormat":"CycloneDX","specVersion":"1.4"}`, ctypes.CycloneDXJSONMediaType)
+	if err := convert(options.RegistryReferrersModeOCI11, true).Exec(ctx, digest.Context().Tag("latest").String()); err != nil {
+		t.Fatalf("Exec() = %v", err)
+	}
+
+	// The attestation is a referrer of the image, so there's none on the
+	// legacy attestation tag, and the SBOM tag is deleted.
+	attTag, err := ociremote.AttestationTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(attTag); err == nil {
+		t.Errorf("%s exists, want the attestation only as a referrer", attTag)
+	}
+	sbomTag, err := ociremote.SBOMTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(sbomTag); err == nil {
+		t.Errorf("%s exists, want it deleted with DeleteSBOM", sbomTag)
+	}
+
+	co := &cosign.CheckOpts{SigVerifier: verifier, IgnoreTlog: true, ClaimVerifier: cosign.IntotoSubjectClaimVerifier}
+	atts, _, err := cosign.VerifyImageAttestations(ctx, digest, co)
+	if err != nil {
+		t.Fatalf("VerifyImageAttestations() = %v", err)
+	}
+	if len(atts) != 1 {
+		t.Fatalf("got %d attestations, want 1", len(atts))
+	}
+	payload, err := atts[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st in_toto.Statement
+	if err := json.Unmarshal(decoded, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.PredicateType != in_toto.PredicateCycloneDX {
+		t.Errorf("predicate type = %s, want %s", st.PredicateType, in_toto.PredicateCycloneDX)
+	}
+	if p, _ := st.Predicate.(map[string]interface{}); p["bomFormat"] != "CycloneDX" {
+		t.Errorf("predicate = %v, want the attached SBOM", st.Predicate)
+	}
+
+	// In legacy mode, the attestation is written to the attestation tag and
+	// the SBOM is kept.
+	digest = pushImage(t, "legacy", `{"spdxVersion":"SPDX-2.3"}`, ctypes.SPDXJSONMediaType)
+	if err := convert(options.RegistryReferrersModeLegacy, false).Exec(ctx, digest.String()); err != nil {
+		t.Fatalf("Exec() in legacy mode = %v", err)
+	}
+	if attTag, err = ociremote.AttestationTag(digest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(attTag); err != nil {
+		t.Errorf("legacy attestation tag: %v", err)
+	}
+	if sbomTag, err = ociremote.SBOMTag(digest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(sbomTag); err != nil {
+		t.Errorf("SBOM tag: %v, want it kept without DeleteSBOM", err)
+	}
+
+	digest = pushImage(t, "xml", `<bom xmlns="http://cyclonedx.org/schema/bom/1.4"/>`, ctypes.CycloneDXXMLMediaType)
+	if err := convert(options.RegistryReferrersModeOCI11, true).Exec(ctx, digest.String()); err == nil || !strings.Contains(err.Error(), "can't be converted") {
+		t.Errorf("Exec() with a CycloneDX XML SBOM = %v, want an error", err)
+	}
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noSBOM, err := name.ParseReference(host + "/none:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(noSBOM, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := convert(options.RegistryReferrersModeOCI11, false).Exec(ctx, noSBOM.String()); err == nil || !strings.Contains(err.Error(), "getting the SBOM attached") {
+		t.Errorf("Exec() without an SBOM = %v, want an error", err)
+	}
+}
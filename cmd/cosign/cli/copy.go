@@ -37,12 +37,27 @@ func Copy() *cobra.Command {
   cosign copy --sig-only example.com/src example.com/dest
 
   # overwrite destination image and signatures
-  cosign copy -f example.com/src example.com/dest`,
+  cosign copy -f example.com/src example.com/dest
+
+  # copy an image addressed by digest, without tagging the destination
+  cosign copy example.com/src@sha256:<DIGEST> example.com/dest
+
+  # copy a repository with many signatures four at a time, resuming an earlier copy
+  cosign copy --jobs 4 --state-file copy.state --resume example.com/src example.com/dest
+
+  # push an image in an OCI layout and the signatures stored with it
+  cosign copy --local-image <PATH> example.com/dest:latest
+
+  # only copy the image if its signatures are verified with cosign.pub, e.g. {"key": "cosign.pub"}
+  cosign copy --verify-policy policy.json example.com/src:latest example.com/dest:latest`,
 
 		Args:             cobra.ExactArgs(2),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return copy.CopyCmd(cmd.Context(), o.Registry, args[0], args[1], o.SignatureOnly, o.Force)
+			if o.LocalImage {
+				return copy.LocalCmd(cmd.Context(), o.Registry, args[0], args[1], o.SignatureOnly, o.Force, o.VerifyPolicy)
+			}
+			return copy.CopyCmd(cmd.Context(), o.Registry, o.Batch, args[0], args[1], o.SignatureOnly, o.Force, o.Jobs, o.VerifyPolicy)
 		},
 	}
 
@@ -27,6 +27,11 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/internal/pkg/batch"
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/internal/pkg/profile"
+	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/oci/walk"
@@ -34,19 +39,32 @@ import (
 )
 
 // CopyCmd implements the logic to copy the supplied container image and signatures.
+// With verifyPolicy, nothing is copied unless the signatures of the source
+// image are verified with the signer policy at that path.
 // nolint
-func CopyCmd(ctx context.Context, regOpts options.RegistryOptions, srcImg, dstImg string, sigOnly, force bool) error {
+func CopyCmd(ctx context.Context, regOpts options.RegistryOptions, batchOpts options.BatchOptions, srcImg, dstImg string, sigOnly, force bool, jobs int, verifyPolicy string) (err error) {
 	no := regOpts.NameOptions()
-	srcRef, err := name.ParseReference(srcImg, no...)
+	srcRef, err := oci.ParseReference(srcImg, no...)
 	if err != nil {
 		return err
 	}
 	srcRepoRef := srcRef.Context()
 
-	dstRef, err := name.ParseReference(dstImg, no...)
+	dstRef, err := oci.ParseReference(dstImg, no...)
 	if err != nil {
 		return err
 	}
+	if srcDigest, ok := srcRef.(name.Digest); ok {
+		dstRef, err = digestDestination(srcDigest, dstImg, dstRef, no...)
+		if err != nil {
+			return err
+		}
+	}
+	// Images may be copied from anywhere into the registries of the
+	// --profile.
+	if err := profile.CheckImage(dstRef); err != nil {
+		return err
+	}
 	dstRepoRef := dstRef.Context()
 
 	ociRemoteOpts, err := regOpts.ClientOpts(ctx)
@@ -63,13 +81,38 @@ func CopyCmd(ctx context.Context, regOpts options.RegistryOptions, srcImg, dstIm
 
 	ociRemoteOpts = append(ociRemoteOpts, ociremote.WithRemoteOptions(remoteOpts...))
 
+	// Report what was copied if interrupted, or failed with a state file. The destination tag is only
+	// updated once everything else is copied, so it never points at a
+	// partial copy, and a re-run skips what is already there.
+	progress := batch.Idempotent()
+	if err := progress.UseStateFile(batchOpts.StateFile, batchOpts.Resume); err != nil {
+		return err
+	}
+	cp := newCopyProgress(os.Stderr)
+	defer func() {
+		cp.close()
+		err = progress.Finish(ctx, err)
+	}()
+
+	jobs = budget.Workers(jobs, runtime.GOMAXPROCS(0))
 	g, gctx := errgroup.WithContext(ctx)
-	g.SetLimit(runtime.GOMAXPROCS(0))
+	g.SetLimit(jobs)
 
 	root, err := ociremote.SignedEntity(srcRef, ociRemoteOpts...)
 	if err != nil {
 		return err
 	}
+	if verifyPolicy != "" {
+		// Verify the digest that is copied, which a source tag may no
+		// longer point at.
+		h, err := root.Digest()
+		if err != nil {
+			return err
+		}
+		if err := verifySource(ctx, regOpts, verifyPolicy, srcRepoRef.Digest(h.String()).String(), false); err != nil {
+			return err
+		}
+	}
 
 	if err := walk.SignedEntity(gctx, root, func(ctx context.Context, se oci.SignedEntity) error {
 		// Both of the SignedEntity types implement Digest()
@@ -86,8 +129,9 @@ func CopyCmd(ctx context.Context, regOpts options.RegistryOptions, srcImg, dstIm
 			}
 
 			dst := dstRepoRef.Tag(src.Identifier())
+			cp.queue()
 			g.Go(func() error {
-				return remoteCopy(ctx, pusher, src, dst, force, remoteOpts...)
+				return remoteCopy(ctx, progress, cp, pusher, src, dst, force, remoteOpts...)
 			})
 
 			return nil
@@ -107,31 +151,74 @@ func CopyCmd(ctx context.Context, regOpts options.RegistryOptions, srcImg, dstIm
 		}
 
 		// Copy the entity itself.
+		cp.queue()
 		g.Go(func() error {
 			dst := dstRepoRef.Tag(srcDigest.Identifier())
 			dst = dst.Tag(fmt.Sprint(regOpts.RefOpts.TagPrefix, h.Algorithm, "-", h.Hex))
-			return remoteCopy(ctx, pusher, srcDigest, dst, force, remoteOpts...)
+			return remoteCopy(ctx, progress, cp, pusher, srcDigest, dst, force, remoteOpts...)
 		})
 
 		return nil
 	}); err != nil {
+		// Let the copies in flight stop before reporting them.
+		_ = g.Wait()
 		return err
 	}
-	if sigOnly {
-		return nil
-	}
 
 	// Wait for everything to be copied over.
 	if err := g.Wait(); err != nil {
 		return err
 	}
+	if sigOnly {
+		return nil
+	}
 
 	// Now that everything has been copied over, update the tag.
 	h, err := root.Digest()
 	if err != nil {
 		return err
 	}
-	return remoteCopy(ctx, pusher, srcRepoRef.Digest(h.String()), dstRef, force, remoteOpts...)
+	cp.queue()
+	return remoteCopy(ctx, progress, cp, pusher, srcRepoRef.Digest(h.String()), dstRef, force, remoteOpts...)
+}
+
+// verifySource verifies the signatures of src, an image in a registry or the
+// OCI layout at that path if local, with the signer policy at policyPath.
+// Sources may be outside the registries of the --profile.
+func verifySource(ctx context.Context, regOpts options.RegistryOptions, policyPath, src string, local bool) error {
+	p, err := verify.ReadSignerPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	v := p.Command()
+	v.RegistryOptions = regOpts
+	v.NameOptions = regOpts.NameOptions()
+	v.LocalImage = local
+	v.AnyRegistry = true
+	if !local {
+		v.OnVerified = func(ctx context.Context, ref name.Reference, verified []oci.Signature) error {
+			ui.Infof(ctx, "Verified %d signatures of %s", len(verified), ref)
+			return nil
+		}
+	}
+	if err := v.Exec(ctx, []string{src}); err != nil {
+		return fmt.Errorf("verifying the signatures of %s: %w", src, err)
+	}
+	return nil
+}
+
+// digestDestination returns where to copy a source addressed by digest to.
+// A destination without a tag or digest is addressed by the same digest,
+// rather than the default "latest" tag, so that tag-less promotion pipelines
+// don't end up creating tags.
+func digestDestination(src name.Digest, dstImg string, dstRef name.Reference, opts ...name.Option) (name.Reference, error) {
+	if repo, err := name.NewRepository(dstImg, opts...); err == nil {
+		return repo.Digest(src.DigestStr()), nil
+	}
+	if dst, ok := dstRef.(name.Digest); ok && dst.DigestStr() != src.DigestStr() {
+		return nil, fmt.Errorf("destination digest %s does not match source digest %s", dst.DigestStr(), src.DigestStr())
+	}
+	return dstRef, nil
 }
 
 func descriptorsEqual(a, b *v1.Descriptor) bool {
@@ -143,7 +230,14 @@ func descriptorsEqual(a, b *v1.Descriptor) bool {
 
 type tagMap func(name.Reference, ...ociremote.Option) (name.Tag, error)
 
-func remoteCopy(ctx context.Context, pusher *remote.Pusher, src, dest name.Reference, overwrite bool, opts ...remote.Option) error {
+// remoteCopy copies src to dest, unless dest was copied by a previous run or
+// src is missing. Sources that need no copy are recorded as unchanged, so
+// that a resumed copy doesn't look them up again.
+func remoteCopy(ctx context.Context, progress *batch.Progress, cp *copyProgress, pusher *remote.Pusher, src, dest name.Reference, overwrite bool, opts ...remote.Option) error {
+	if progress.Skip(dest.Name()) {
+		cp.finish(false, true)
+		return nil
+	}
 	got, err := remote.Get(src, opts...)
 	if err != nil {
 		var te *transport.Error
@@ -152,7 +246,8 @@ func remoteCopy(ctx context.Context, pusher *remote.Pusher, src, dest name.Refer
 			// trying many flavors of tag (sig, sbom, att) and only a subset of
 			// these are likely to exist, especially when we're talking about a
 			// multi-arch image.
-			return nil
+			cp.finish(false, false)
+			return progress.Unchanged(dest.Name())
 		}
 		return err
 	}
@@ -160,12 +255,18 @@ func remoteCopy(ctx context.Context, pusher *remote.Pusher, src, dest name.Refer
 	if !overwrite {
 		if dstDesc, err := remote.Head(dest, opts...); err == nil {
 			if descriptorsEqual(&got.Descriptor, dstDesc) {
-				return nil
+				cp.finish(false, true)
+				return progress.Unchanged(dest.Name())
 			}
 			return fmt.Errorf("image %q already exists. Use `-f` to overwrite", dest.Name())
 		}
 	}
 
-	fmt.Fprintf(os.Stderr, "Copying %s to %s...\n", src, dest)
-	return pusher.Push(ctx, dest, got)
+	cp.copying(src, dest)
+	progress.Start(dest.Name())
+	if err := pusher.Push(ctx, dest, got); err != nil {
+		return err
+	}
+	cp.finish(true, false)
+	return progress.Done(dest.Name())
 }
@@ -15,10 +15,31 @@
 package copy
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci/layout"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/signed"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
 )
 
 func TestCopyAttachmentTagPrefix(t *testing.T) {
@@ -33,8 +54,323 @@ func TestCopyAttachmentTagPrefix(t *testing.T) {
 
 	err := CopyCmd(ctx, options.RegistryOptions{
 		RefOpts: refOpts,
-	}, srcImg, destImg, false, true)
+	}, options.BatchOptions{}, srcImg, destImg, false, true, 0, "")
 	if err == nil {
 		t.Fatal("failed to copy with attachment-tag-prefix")
 	}
 }
+
+func TestCopyByDigest(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	// An image that is only addressed by digest, with a signature.
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := name.NewDigest(host + "/src@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(src, img); err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sigTag, sig); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CopyCmd(ctx, options.RegistryOptions{}, options.BatchOptions{}, src.String(), host+"/dst", false, false, 0, ""); err != nil {
+		t.Fatalf("CopyCmd() unexpected error: %v", err)
+	}
+
+	dst, err := name.NewRepository(host + "/dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(dst.Digest(h.String())); err != nil {
+		t.Errorf("image was not copied by digest: %v", err)
+	}
+	tags, err := remote.List(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range tags {
+		if tag == "latest" {
+			t.Error("copying by digest created the latest tag")
+		}
+	}
+	dstSigTag, err := ociremote.SignatureTag(dst.Digest(h.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(dstSigTag); err != nil {
+		t.Errorf("signature was not copied: %v", err)
+	}
+
+	other := "sha256:" + strings.Repeat("0", 64)
+	if err := CopyCmd(ctx, options.RegistryOptions{}, options.BatchOptions{}, src.String(), host+"/dst@"+other, false, true, 0, ""); err == nil {
+		t.Error("expected an error copying to a different digest")
+	}
+}
+
+func TestCopyResume(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	// A tagged image with a signature, but no attestations or SBOM.
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := name.NewTag(host + "/src:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(src, img); err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(src.Context().Digest(h.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sigTag, sig); err != nil {
+		t.Fatal(err)
+	}
+
+	td := t.TempDir()
+	stderr := os.Stderr
+	t.Cleanup(func() { os.Stderr = stderr })
+	f, err := os.Create(filepath.Join(td, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stderr = f
+
+	statePath := filepath.Join(td, "state")
+	batchOpts := options.BatchOptions{StateFile: statePath}
+	if err := CopyCmd(ctx, options.RegistryOptions{}, batchOpts, src.String(), host+"/dst:latest", false, false, 2, ""); err != nil {
+		t.Fatalf("CopyCmd() unexpected error: %v", err)
+	}
+	os.Stderr = stderr
+	f.Close()
+	out, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(out), "] Copying "); got != 3 {
+		t.Errorf("CopyCmd() reported %d copies, want the image, its signature and the tag:\n%s", got, out)
+	}
+
+	// Everything is recorded, including the missing attestations and SBOM.
+	state, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(state), "\n"); got != 5 {
+		t.Errorf("state file records %d items, want 5:\n%s", got, state)
+	}
+
+	if err := CopyCmd(ctx, options.RegistryOptions{}, batchOpts, src.String(), host+"/dst:latest", false, false, 0, ""); err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Errorf("CopyCmd() of an existing state file without --resume = %v", err)
+	}
+	batchOpts.Resume = true
+	if err := CopyCmd(ctx, options.RegistryOptions{}, batchOpts, src.String(), host+"/dst:latest", false, false, 0, ""); err != nil {
+		t.Fatalf("CopyCmd() with --resume = %v", err)
+	}
+	resumed, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resumed) != string(state) {
+		t.Errorf("resumed copy recorded more items:\n%s", resumed)
+	}
+}
+
+func TestCopyLocalImage(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	// An OCI layout of a signed image.
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature([]byte("payload"), "c2ln")
+	if err != nil {
+		t.Fatal(err)
+	}
+	si, err := mutate.AttachSignatureToImage(signed.Image(img), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "oci")
+	if err := layout.WriteSignedImage(path, si); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, err := name.NewTag(host + "/dst:1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LocalCmd(ctx, options.RegistryOptions{}, path, dst.String(), false, false, ""); err != nil {
+		t.Fatalf("LocalCmd() = %v", err)
+	}
+	desc, err := remote.Head(dst)
+	if err != nil {
+		t.Fatalf("image was not copied: %v", err)
+	}
+	if desc.Digest != h {
+		t.Errorf("copied digest %s, want %s", desc.Digest, h)
+	}
+	se, err := ociremote.SignedEntity(dst.Context().Digest(h.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := se.Signatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, err := sigs.Get(); err != nil || len(l) != 1 {
+		t.Errorf("copied %d signatures, %v, want 1", len(l), err)
+	}
+
+	// Another image isn't overwritten without --force.
+	other, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDst := dst.Context().Tag("other")
+	if err := remote.Write(otherDst, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := LocalCmd(ctx, options.RegistryOptions{}, path, otherDst.String(), false, false, ""); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("LocalCmd() over another image = %v", err)
+	}
+	if err := LocalCmd(ctx, options.RegistryOptions{}, path, otherDst.String(), false, true, ""); err != nil {
+		t.Errorf("LocalCmd() with --force = %v", err)
+	}
+}
+
+func TestCopyCmdVerifyPolicy(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := name.NewTag(host + "/src:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(src, img); err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + src.Context().String() + `"},"image":{"docker-manifest-digest":"` + h.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	key, signer := writeTestKey(t)
+	otherKey, _ := writeTestKey(t)
+	raw, err := signer.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(payload, base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	si, err := mutate.AttachSignatureToImage(signed.Image(img), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ociremote.WriteSignatures(src.Context(), si); err != nil {
+		t.Fatal(err)
+	}
+
+	writePolicy := func(t *testing.T, key string) string {
+		path := filepath.Join(t.TempDir(), "policy.json")
+		if err := os.WriteFile(path, []byte(`{"key": "`+key+`", "ignoreTlog": true, "ignoreSCT": true}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	other, err := name.NewTag(host + "/other:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = CopyCmd(ctx, options.RegistryOptions{}, options.BatchOptions{}, src.String(), other.String(), false, false, 0, writePolicy(t, otherKey))
+	if err == nil || !strings.Contains(err.Error(), "verifying the signatures of "+src.Context().Digest(h.String()).String()) {
+		t.Fatalf("CopyCmd() with another key = %v, want a verification error", err)
+	}
+	if _, err := remote.Head(other); err == nil {
+		t.Error("image was copied despite failing the policy")
+	}
+
+	dst, err := name.NewTag(host + "/dst:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyCmd(ctx, options.RegistryOptions{}, options.BatchOptions{}, src.String(), dst.String(), false, false, 0, writePolicy(t, key)); err != nil {
+		t.Fatalf("CopyCmd() with the signing key = %v", err)
+	}
+	if desc, err := remote.Head(dst); err != nil || desc.Digest != h {
+		t.Errorf("image was not copied: %v", err)
+	}
+}
+
+func writeTestKey(t *testing.T) (string, signature.SignerVerifier) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pub")
+	if err := os.WriteFile(path, pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path, sv
+}
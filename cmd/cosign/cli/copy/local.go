@@ -0,0 +1,116 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package copy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/layout"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// LocalCmd pushes the image in the OCI layout at path, e.g. signed with
+// cosign sign --local-image, and the signatures and attestations stored with
+// it to dstImg. The destination tag is only updated once the signatures and
+// attestations are pushed. With verifyPolicy, nothing is pushed unless the
+// signatures stored with the image are verified with the signer policy at
+// that path.
+func LocalCmd(ctx context.Context, regOpts options.RegistryOptions, path, dstImg string, sigOnly, force bool, verifyPolicy string) error {
+	l, err := layout.LoadLocal(path)
+	if err != nil {
+		return fmt.Errorf("loading local image %s: %w", path, err)
+	}
+	if l.Tarball {
+		return fmt.Errorf("%s is a docker tarball, which can't hold signatures; copy the OCI layout it was signed to with --local-image-output", path)
+	}
+	// Reject tampered content before anything is written to the registry.
+	if err := layout.VerifyDigests(path); err != nil {
+		return fmt.Errorf("verifying %s: %w", path, err)
+	}
+	if verifyPolicy != "" {
+		if err := verifySource(ctx, regOpts, verifyPolicy, path, true); err != nil {
+			return err
+		}
+	}
+	h, err := l.Entity.Digest()
+	if err != nil {
+		return err
+	}
+
+	dstRef, err := oci.ParseReference(dstImg, regOpts.NameOptions()...)
+	if err != nil {
+		return err
+	}
+	if repo, err := name.NewRepository(dstImg, regOpts.NameOptions()...); err == nil {
+		dstRef = repo.Digest(h.String())
+	} else if d, ok := dstRef.(name.Digest); ok && d.DigestStr() != h.String() {
+		return fmt.Errorf("destination digest %s does not match the digest %s of %s", d.DigestStr(), h, path)
+	}
+	remoteOpts := regOpts.GetRegistryClientOpts(ctx)
+	if !force {
+		if desc, err := remote.Head(dstRef, remoteOpts...); err == nil && desc.Digest != h {
+			return fmt.Errorf("image %q already exists. Use `-f` to overwrite", dstRef.Name())
+		}
+	}
+	ociRemoteOpts, err := regOpts.ClientOpts(ctx)
+	if err != nil {
+		return err
+	}
+	ociRemoteOpts = append(ociRemoteOpts, ociremote.WithRemoteOptions(remoteOpts...))
+
+	sigs, err := l.Entity.Signatures()
+	if err != nil {
+		return err
+	}
+	if ss, err := sigs.Get(); err != nil {
+		return err
+	} else if len(ss) > 0 {
+		ui.Infof(ctx, "Copying %d signatures of %s to %s", len(ss), path, dstRef.Context())
+		if err := ociremote.WriteSignatures(dstRef.Context(), l.Entity, ociRemoteOpts...); err != nil {
+			return err
+		}
+	}
+	if sigOnly {
+		return nil
+	}
+	atts, err := l.Entity.Attestations()
+	if err != nil {
+		return err
+	}
+	if as, err := atts.Get(); err != nil {
+		return err
+	} else if len(as) > 0 {
+		ui.Infof(ctx, "Copying %d attestations of %s to %s", len(as), path, dstRef.Context())
+		if err := ociremote.WriteAttestations(dstRef.Context(), l.Entity, ociRemoteOpts...); err != nil {
+			return err
+		}
+	}
+
+	ui.Infof(ctx, "Copying %s to %s", path, dstRef)
+	switch e := l.Entity.(type) {
+	case oci.SignedImageIndex:
+		return remote.WriteIndex(dstRef, e, remoteOpts...)
+	case oci.SignedImage:
+		return remote.Write(dstRef, e, remoteOpts...)
+	}
+	return fmt.Errorf("%s holds neither an image nor an image index", path)
+}
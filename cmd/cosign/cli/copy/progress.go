@@ -0,0 +1,117 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package copy
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/templates/term"
+)
+
+// copyProgress reports the progress of a copy on stderr: on a terminal, as a
+// status line that is rewritten as the copies complete, otherwise as a line
+// per copy with the counts so far.
+type copyProgress struct {
+	mu sync.Mutex
+	w  io.Writer
+	// width is that of the terminal, or 0 if w isn't one.
+	width int
+	// queued counts the source tags and digests to copy, and copied,
+	// unchanged and missing those that were copied, were already at the
+	// destination or missing from the source.
+	queued, inFlight, copied, unchanged, missing int
+}
+
+func newCopyProgress(w *os.File) *copyProgress {
+	p := &copyProgress{w: term.NewResponsiveWriter(w)}
+	if size := term.GetSize(w.Fd()); size != nil {
+		p.w, p.width = w, int(size.Width)
+	}
+	return p
+}
+
+// queue counts a source to copy.
+func (p *copyProgress) queue() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queued++
+	p.status()
+}
+
+// copying reports that src is being copied to dest.
+func (p *copyProgress) copying(src, dest fmt.Stringer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight++
+	if p.width > 0 {
+		p.line(fmt.Sprintf("Copying %s to %s...", src, dest))
+		return
+	}
+	fmt.Fprintf(p.w, "[%d/%d] Copying %s to %s...\n", p.done()+1, p.queued, src, dest)
+}
+
+// finish counts a source that was copied if copied, or else needed no copy
+// because it is unchanged or missing.
+func (p *copyProgress) finish(copied, unchanged bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case copied:
+		p.inFlight--
+		p.copied++
+	case unchanged:
+		p.unchanged++
+	default:
+		p.missing++
+	}
+	p.status()
+}
+
+// close ends the status line.
+func (p *copyProgress) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.width > 0 && p.queued > 0 {
+		fmt.Fprintln(p.w)
+	}
+}
+
+func (p *copyProgress) done() int {
+	return p.copied + p.unchanged + p.missing
+}
+
+// status rewrites the status line on a terminal.
+func (p *copyProgress) status() {
+	if p.width == 0 {
+		return
+	}
+	s := fmt.Sprintf("%d of %d done: %d copied, %d up to date, %d not in the source, %d copying",
+		p.done(), p.queued, p.copied, p.unchanged, p.missing, p.inFlight)
+	if len(s) >= p.width {
+		s = s[:p.width-1]
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s", s)
+}
+
+// line writes s above the status line on a terminal.
+func (p *copyProgress) line(s string) {
+	fmt.Fprintf(p.w, "\r\033[K%s\n", strings.TrimSuffix(s, "\n"))
+	p.status()
+}
@@ -0,0 +1,200 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+func Countersign() *cobra.Command {
+	o := &options.CountersignOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "countersign",
+		Short: "Verify the signatures on the supplied container image and countersign them",
+		Long: `Verify the signatures on the supplied container image, like cosign verify, and
+add a countersignature by another party over the payload of each verified
+signature.
+
+The original signer is identified with the cosign verify flags. The
+countersigner signs with --signing-key, or with a certificate issued by Fulcio.
+Verification policies can require countersignatures with
+cosign verify --countersigner-key or --countersigner-identity.`,
+		Example: `  cosign countersign --key <key path>|<key url>|<kms uri> --signing-key <key path>|<kms uri> <image uri>
+
+  # countersign the signatures made by the release pipeline with the QA team's key
+  cosign countersign --certificate-identity release@example.com --certificate-oidc-issuer https://accounts.example.com \
+    --signing-key qa.key <IMAGE>
+
+  # require the QA countersignature when verifying
+  cosign verify --certificate-identity release@example.com --certificate-oidc-issuer https://accounts.example.com \
+    --countersigner-key qa.pub <IMAGE>`,
+
+		Args:             cobra.MinimumNArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vo := o.Verify
+			annotations, err := vo.AnnotationsMap()
+			if err != nil {
+				return err
+			}
+			hashAlgorithm, err := vo.SignatureDigest.HashAlgorithm()
+			if err != nil {
+				return err
+			}
+			v := &verify.VerifyCommand{
+				RegistryOptions:              vo.Registry,
+				CertVerifyOptions:            vo.CertVerify,
+				CheckClaims:                  vo.CheckClaims,
+				KeyRef:                       vo.Key,
+				CertRef:                      vo.CertVerify.Cert,
+				CertGithubWorkflowTrigger:    vo.CertVerify.CertGithubWorkflowTrigger,
+				CertGithubWorkflowSha:        vo.CertVerify.CertGithubWorkflowSha,
+				CertGithubWorkflowName:       vo.CertVerify.CertGithubWorkflowName,
+				CertGithubWorkflowRepository: vo.CertVerify.CertGithubWorkflowRepository,
+				CertGithubWorkflowRef:        vo.CertVerify.CertGithubWorkflowRef,
+				CertChain:                    vo.CertVerify.CertChain,
+				IgnoreSCT:                    vo.CertVerify.IgnoreSCT,
+				SCTRef:                       vo.CertVerify.SCT,
+				Sk:                           vo.SecurityKey.Use,
+				Slot:                         vo.SecurityKey.Slot,
+				RekorURL:                     vo.Rekor.URL,
+				Attachment:                   vo.Attachment,
+				Annotations:                  annotations,
+				HashAlgorithm:                hashAlgorithm,
+				LocalImage:                   vo.LocalImage,
+				Offline:                      vo.CommonVerifyOptions.Offline,
+				TSACertChainPaths:            vo.CommonVerifyOptions.TSACertChainPaths,
+				IgnoreTlog:                   vo.CommonVerifyOptions.IgnoreTlog,
+				Denylist:                     vo.CommonVerifyOptions.Denylist,
+				KeyUsagePolicy:               vo.CommonVerifyOptions.KeyUsagePolicy,
+				Witnesses:                    vo.CommonVerifyOptions.Witnesses,
+				WarningsAsErrors:             vo.WarningsAsErrors,
+				SourceRepositories:           vo.SourceRepositories,
+				Batch:                        vo.Batch,
+				AllowConverted:               vo.AllowConverted,
+				Recursive:                    vo.Recursive,
+				LayerPolicy:                  vo.LayerPolicy,
+				Quota:                        vo.Quota,
+				EnforceExpiry:                vo.EnforceExpiry,
+				Encryption:                   vo.Encryption,
+				Cache:                        vo.Cache,
+				Evaluation:                   vo.Evaluation,
+			}
+			if vo.Registry.AllowInsecure {
+				v.NameOptions = append(v.NameOptions, name.Insecure)
+			}
+			if err := setImagePolicy(v, &vo); err != nil {
+				return err
+			}
+
+			oidcClientSecret, err := o.Verify.OIDC.ClientSecret()
+			if err != nil {
+				return err
+			}
+			ko := options.KeyOpts{
+				KeyRef:                   o.SigningKey,
+				PassFunc:                 generate.GetPass,
+				FulcioURL:                o.Verify.Fulcio.URL,
+				IDToken:                  o.Verify.Fulcio.IdentityToken,
+				InsecureSkipFulcioVerify: o.Verify.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                     o.Verify.Fulcio.ACME,
+				RekorURL:                 vo.Rekor.URL,
+				OIDCIssuer:               o.Verify.OIDC.Issuer,
+				OIDCClientID:             o.Verify.OIDC.ClientID,
+				OIDCClientSecret:         oidcClientSecret,
+				OIDCRedirectURL:          o.Verify.OIDC.RedirectURL,
+				OIDCProvider:             o.Verify.OIDC.Provider,
+				OIDCTokenFile:            o.Verify.OIDC.TokenFile,
+				OIDCAudience:             o.Verify.OIDC.Audience,
+				OIDCTokenExchangeIssuer:  o.Verify.OIDC.TokenExchangeIssuer,
+				SkipConfirmation:         o.SkipConfirmation,
+				TSAServerURL:             o.TSAServerURL,
+			}
+			signOpts := options.SignOptions{
+				Cert:       o.SigningCert,
+				CertChain:  o.SigningCertChain,
+				Upload:     true,
+				TlogUpload: o.TlogUpload,
+				Registry:   vo.Registry,
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), ro.Timeout)
+			defer cancel()
+			return withFailureReport(cmd, args, vo.CommonVerifyOptions.FailureReport, func() error {
+				return CountersignCmd(ctx, v, ko, signOpts, args)
+			})
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// CountersignCmd verifies the signatures of images with v, and adds a
+// countersignature made with ko over the payload of each verified signature.
+func CountersignCmd(ctx context.Context, v *verify.VerifyCommand, ko options.KeyOpts, signOpts options.SignOptions, images []string) error {
+	if v.LocalImage {
+		return errors.New("local images can't be countersigned")
+	}
+	sv, err := sign.SignerFromKeyOpts(ctx, signOpts.Cert, signOpts.CertChain, ko)
+	if err != nil {
+		return fmt.Errorf("getting signer: %w", err)
+	}
+	defer sv.Close()
+	ociremoteOpts, err := signOpts.Registry.ClientOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	v.OnVerified = func(ctx context.Context, ref name.Reference, verified []oci.Signature) error {
+		digest, err := ociremote.ResolveDigest(ref, ociremoteOpts...)
+		if err != nil {
+			return err
+		}
+		// Signatures over the same payload need a single countersignature.
+		seen := map[string]struct{}{}
+		for _, sig := range verified {
+			payload, err := sig.Payload()
+			if err != nil {
+				return err
+			}
+			if _, ok := seen[string(payload)]; ok {
+				continue
+			}
+			seen[string(payload)] = struct{}{}
+			if err := sign.CountersignDigest(ctx, digest, payload, ko, signOpts, sv); err != nil {
+				return fmt.Errorf("countersigning %s: %w", digest, err)
+			}
+		}
+		ui.Infof(ctx, "Countersigned %d verified payload(s) of %s", len(seen), digest)
+		return nil
+	}
+	return v.Exec(ctx, images)
+}
@@ -0,0 +1,142 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+func TestCountersignCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// The image is signed by the release key.
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + ref.Context().String() + `"},"image":{"docker-manifest-digest":"` + h.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	releaseSigner, releaseKey := newTestKey(t)
+	sigs, err := mutate.AppendSignatures(empty.Signatures(), signPayload(t, releaseSigner, payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sigTag, sigs); err != nil {
+		t.Fatal(err)
+	}
+
+	// The QA key countersigns.
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := t.TempDir()
+	qaKey := filepath.Join(td, "qa.key")
+	qaPub := filepath.Join(td, "qa.pub")
+	if err := os.WriteFile(qaKey, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(qaPub, keys.PublicBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey := newTestKey(t)
+
+	newVerifyCommand := func(countersigners options.CountersignerOptions) *verify.VerifyCommand {
+		return &verify.VerifyCommand{
+			KeyRef:         releaseKey,
+			CheckClaims:    true,
+			IgnoreSCT:      true,
+			IgnoreTlog:     true,
+			Countersigners: countersigners,
+		}
+	}
+	requireQA := options.CountersignerOptions{Keys: []string{qaPub}}
+
+	if err := newVerifyCommand(requireQA).Exec(ctx, []string{ref.String()}); err == nil || !strings.Contains(err.Error(), "countersignature by "+qaPub) {
+		t.Fatalf("Exec() before countersigning error = %v, want a missing countersignature", err)
+	}
+
+	ko := options.KeyOpts{KeyRef: qaKey, PassFunc: func(bool) ([]byte, error) { return nil, nil }}
+	signOpts := options.SignOptions{Upload: true}
+	if err := CountersignCmd(ctx, newVerifyCommand(options.CountersignerOptions{}), ko, signOpts, []string{ref.String()}); err != nil {
+		t.Fatalf("CountersignCmd() unexpected error: %v", err)
+	}
+	// Countersigning again doesn't add a duplicate.
+	if err := CountersignCmd(ctx, newVerifyCommand(options.CountersignerOptions{}), ko, signOpts, []string{ref.String()}); err != nil {
+		t.Fatalf("CountersignCmd() unexpected error: %v", err)
+	}
+	got, err := ociremote.Signatures(sigTag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := got.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("got %d signatures, want the signature and its countersignature", len(all))
+	}
+	if p, _ := all[1].Payload(); string(p) != string(payload) {
+		t.Errorf("countersignature payload = %s, want %s", p, payload)
+	}
+
+	if err := newVerifyCommand(requireQA).Exec(ctx, []string{ref.String()}); err != nil {
+		t.Errorf("Exec() requiring the QA countersignature: unexpected error %v", err)
+	}
+	if err := newVerifyCommand(options.CountersignerOptions{Keys: []string{qaPub, otherKey}}).Exec(ctx, []string{ref.String()}); err == nil {
+		t.Error("Exec() requiring a missing countersignature: expected an error")
+	}
+	if err := newVerifyCommand(options.CountersignerOptions{Keys: []string{releaseKey}}).Exec(ctx, []string{ref.String()}); err == nil {
+		t.Error("Exec() requiring a countersignature by the signer itself: expected an error")
+	}
+}
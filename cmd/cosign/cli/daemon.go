@@ -0,0 +1,119 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/pkg/daemon"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func Daemon() *cobra.Command {
+	o := &options.DaemonOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Keep the KMS clients, OIDC tokens and registry credentials of cosign clients warm in a long-running process",
+		Long: `Run a daemon that keeps warm, for the cosign clients connecting to its Unix
+socket, what they would load again for each command, so that busy build farms
+don't pay the startup cost of each invocation. Clients with
+$COSIGN_DAEMON_SOCKET set run their commands themselves, with their standard
+streams, working directory and environment, and ask the daemon at that path
+for:
+
+  - the clients of KMS keys, which sign in a worker of the daemon
+  - the ambient OIDC tokens, until shortly before they expire
+  - the registry credentials, for 5 minutes
+
+They load them themselves if the daemon isn't listening. The TUF metadata stays
+warm in the TUF cache of the clients, see --tuf-refresh.
+
+KMS clients, OIDC tokens and registry credentials are only shared by clients
+with the same environment, where their credentials come from. The KMS clients
+are kept by a worker process per environment and impersonated service account,
+started in that environment. The socket is only accessible to the user running
+the daemon, whose connections are the only ones accepted.`,
+		Example: `  cosign daemon --socket <path>
+
+  # run a daemon that exits after an hour without requests
+  cosign daemon --socket /run/user/1000/cosign.sock --idle-timeout 1h
+
+  # sign with the daemon
+  COSIGN_DAEMON_SOCKET=/run/user/1000/cosign.sock cosign sign --key gcpkms://projects/<PROJECT>/locations/global/keyRings/<KEYRING>/cryptoKeys/<KEY> <IMAGE>`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DaemonCmd(cmd.Context(), *o)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// DaemonCmd serves the clients of the socket of o until ctx is done, cosign is
+// interrupted, or no client sent a request for o.IdleTimeout. With o.Worker,
+// it serves the KMS keys of a daemon as one of its workers.
+func DaemonCmd(ctx context.Context, o options.DaemonOptions) error {
+	if o.Worker {
+		return daemon.ServeWorker(ctx, func(ctx context.Context, keyRef string) (signature.SignerVerifier, error) {
+			return sigs.SignerVerifierFromKeyRef(ctx, keyRef, nil)
+		})
+	}
+	socket := o.Socket
+	if socket == "" {
+		socket = env.Getenv(env.VariableDaemonSocket)
+	}
+	if socket == "" {
+		return fmt.Errorf("either --socket or $%s must be set", env.VariableDaemonSocket)
+	}
+	l, err := daemon.Listen(socket)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	ui.Infof(ctx, "Serving the clients of %s", socket)
+	return daemon.Serve(ctx, l, daemon.Options{IdleTimeout: o.IdleTimeout, Worker: daemonWorkerCmd})
+}
+
+// daemonWorkerCmd returns the command of a worker of the daemon that
+// impersonates serviceAccount.
+func daemonWorkerCmd(serviceAccount string) *exec.Cmd {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = os.Args[0]
+	}
+	args := []string{"daemon", "--worker"}
+	if serviceAccount != "" {
+		args = append([]string{"--impersonate-service-account", serviceAccount}, args...)
+	}
+	return exec.Command(exe, args...)
+}
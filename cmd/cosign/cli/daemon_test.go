@@ -0,0 +1,36 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDaemonWorkerCmd(t *testing.T) {
+	for _, tc := range []struct {
+		serviceAccount string
+		want           []string
+	}{
+		{want: []string{"daemon", "--worker"}},
+		{serviceAccount: "ci@example.iam.gserviceaccount.com", want: []string{"--impersonate-service-account", "ci@example.iam.gserviceaccount.com", "daemon", "--worker"}},
+	} {
+		if got := daemonWorkerCmd(tc.serviceAccount).Args[1:]; !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("daemonWorkerCmd(%q) args = %v, want %v", tc.serviceAccount, got, tc.want)
+		}
+	}
+
+}
@@ -0,0 +1,154 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/devstack"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+func Dev() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Provides utilities for experimenting with signing locally",
+	}
+
+	cmd.AddCommand(
+		devSign(),
+		devStack(),
+	)
+
+	return cmd
+}
+
+func devSign() *cobra.Command {
+	o := &options.DevSignOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign images with an ephemeral, untrusted development key",
+		Long: `Sign images with a keypair generated in memory, for experimenting with signing
+and verification locally. No key files are written, and neither Fulcio nor the
+transparency log is used.
+
+The signatures are annotated with dev.sigstore.cosign/untrusted and expire
+after --ttl. The public key and the cosign verify command for the signatures
+are printed to standard output, as shell commands. The key is lost when the
+command exits, so the signatures are UNTRUSTED and must not be relied on
+outside of local testing.`,
+		Example: `  cosign dev sign <IMAGE>
+
+  # sign, then verify the image in the same shell
+  eval "$(cosign dev sign localhost:5000/app:dev)"
+
+  # sign for a 10 minute test
+  cosign dev sign --ttl 10m <IMAGE>`,
+		Args:             cobra.MinimumNArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sign.DevSignCmd(cmd.Context(), ro, *o, args, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func devStack() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stack",
+		Short: "Provides an ephemeral environment for testing keyless signing locally",
+	}
+
+	cmd.AddCommand(devStackUp())
+
+	return cmd
+}
+
+func devStackUp() *cobra.Command {
+	o := &options.DevStackUpOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Run an ephemeral registry, timestamp authority and certificate authority for testing keyless flows",
+		Long: `Run an ephemeral test environment of in-process fakes, so that users and CI can
+test keyless signing and verification end to end without network access:
+
+  * an OCI registry
+  * an RFC 3161 timestamp authority, as run by cosign tsa serve --ephemeral
+  * a certificate authority standing in for Fulcio, served with the local CA
+    protocol of --fulcio-url unix:<path>, which certifies every signer as
+    --identity from --issuer, without an OIDC token
+
+There is no transparency log in the environment: signatures aren't uploaded
+to Rekor, and are timestamped by the timestamp authority instead.
+
+The certificate chains, a trusted root of the certificate and timestamp
+authorities for --trusted-root, and an env file of the COSIGN_ variables that
+configure cosign to sign and verify with the environment are written to --dir.
+The keys of the authorities are generated in memory and lost when the command
+exits, so the signatures are only for testing.`,
+		Example: `  cosign dev stack up --dir /tmp/stack &
+
+  # sign and verify an image keylessly against the environment
+  source /tmp/stack/env
+  cosign upload blob -f artifact $DEV_STACK_REGISTRY/artifact:v1
+  cosign sign --yes $DEV_STACK_REGISTRY/artifact:v1
+  cosign verify $DEV_STACK_REGISTRY/artifact:v1
+
+  # sign and verify a blob, with its timestamp
+  cosign sign-blob --yes --bundle artifact.bundle --rfc3161-timestamp artifact.tsr artifact
+  cosign verify-blob --bundle artifact.bundle --rfc3161-timestamp artifact.tsr artifact`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DevStackUpCmd(cmd.Context(), *o, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// DevStackUpCmd runs a test environment until ctx is done or cosign is
+// interrupted, and prints its environment to out.
+func DevStackUpCmd(ctx context.Context, o options.DevStackUpOptions, out io.Writer) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	s, err := devstack.Start(ctx, o)
+	if err != nil {
+		return err
+	}
+	ui.Warnf(ctx, "The authorities of the stack sign with ephemeral keys, its signatures are only for testing")
+	ui.Infof(ctx, "Registry:                %s", s.Registry)
+	ui.Infof(ctx, "Timestamp authority:     %s", s.TSAURL)
+	ui.Infof(ctx, "Certificate authority:   unix:%s", filepath.Join(s.Dir, devstack.CASocket))
+	ui.Infof(ctx, "Trusted root:            %s", filepath.Join(s.Dir, devstack.TrustedRootFile))
+	fmt.Fprintf(out, "source %s\n", filepath.Join(s.Dir, devstack.EnvFile))
+	return s.Wait()
+}
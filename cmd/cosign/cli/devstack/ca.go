@@ -0,0 +1,202 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devstack
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	fulciocert "github.com/sigstore/fulcio/pkg/certificate"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/localca"
+)
+
+// certValidity is how long the certificates of the CA are valid, as long as
+// those of Fulcio.
+const certValidity = 10 * time.Minute
+
+// CA is a certificate authority standing in for Fulcio, served with the
+// local CA protocol of --fulcio-url unix:<path>. It certifies every signer
+// as the same identity, without an OIDC token.
+type CA struct {
+	Identity string
+	Issuer   string
+
+	key crypto.Signer
+	// chain is the intermediate and root certificates of the CA.
+	chain []*x509.Certificate
+}
+
+// NewCA returns a CA with a new root and intermediate, that certifies
+// signers as identity, an email address or a URI, from the OIDC issuer.
+func NewCA(identity, issuer string) (*CA, error) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	root, err := createCert(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "cosign dev stack root", Organization: []string{"local"}},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, nil, rootKey.Public(), rootKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating the root certificate: %w", err)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	intermediate, err := createCert(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "cosign dev stack intermediate", Organization: []string{"local"}},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		MaxPathLenZero:        true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}, root, key.Public(), rootKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating the intermediate certificate: %w", err)
+	}
+	return &CA{Identity: identity, Issuer: issuer, key: key, chain: []*x509.Certificate{intermediate, root}}, nil
+}
+
+// Chain returns the intermediate and root certificates of ca.
+func (ca *CA) Chain() []*x509.Certificate {
+	return ca.chain
+}
+
+// Serve serves the local CA requests of the connections accepted on l
+// until ctx is done.
+func (ca *CA) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go ca.serveConn(conn)
+	}
+}
+
+func (ca *CA) serveConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+	var req localca.Request
+	resp := localca.Response{APIVersion: localca.APIVersion}
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		resp.Error = "decoding request: " + err.Error()
+	} else {
+		resp = ca.Issue(req)
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// Issue returns the certificate of the key of the CSR of req, followed by
+// the chain of ca.
+func (ca *CA) Issue(req localca.Request) localca.Response {
+	resp := localca.Response{APIVersion: localca.APIVersion}
+	cert, err := ca.issue(req)
+	if err != nil {
+		resp.Error = err.Error()
+		return resp
+	}
+	chain, err := cryptoutils.MarshalCertificatesToPEM(append([]*x509.Certificate{cert}, ca.chain...))
+	if err != nil {
+		resp.Error = err.Error()
+		return resp
+	}
+	resp.CertificateChain = string(chain)
+	return resp
+}
+
+func (ca *CA) issue(req localca.Request) (*x509.Certificate, error) {
+	if req.APIVersion != localca.APIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %q, want %q", req.APIVersion, localca.APIVersion)
+	}
+	block, _ := pem.Decode([]byte(req.CertificateSigningRequest))
+	if block == nil {
+		return nil, errors.New("no PEM-encoded certificate signing request")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate signing request: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("the certificate signing request isn't signed with its key: %w", err)
+	}
+	exts, err := fulciocert.Extensions{Issuer: ca.Issuer}.Render()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		NotBefore:       now,
+		NotAfter:        now.Add(certValidity),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: exts,
+	}
+	if u, err := url.Parse(ca.Identity); err == nil && u.Scheme != "" {
+		tmpl.URIs = []*url.URL{u}
+	} else {
+		tmpl.EmailAddresses = []string{ca.Identity}
+	}
+	return createCert(tmpl, ca.chain[0], csr.PublicKey, ca.key)
+}
+
+// createCert returns the certificate of pub made from tmpl, with a random
+// serial number, signed by parent, or self-signed if nil, with key.
+func createCert(tmpl, parent *x509.Certificate, pub crypto.PublicKey, key crypto.Signer) (*x509.Certificate, error) {
+	sn, err := cryptoutils.GenerateSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.SerialNumber = sn
+	if parent == nil {
+		parent = tmpl
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
@@ -0,0 +1,216 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package devstack runs an ephemeral environment of in-process fakes of a
+// registry, a timestamp authority and a certificate authority standing in
+// for Fulcio, for testing keyless signing and verification hermetically.
+package devstack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/trustedroot"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/tsa"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// The files that Start writes to the directory of the stack.
+const (
+	CASocket        = "fulcio.sock"
+	CAChainFile     = "fulcio_chain.pem"
+	TSAChainFile    = "tsa_chain.pem"
+	TrustedRootFile = "trusted_root.json"
+	EnvFile         = "env"
+)
+
+// Stack is a running environment.
+type Stack struct {
+	// Dir has the socket of the CA and the files of the environment.
+	Dir string
+	// Registry is the host of the registry, and TSAURL the URL that
+	// timestamps are requested from.
+	Registry string
+	TSAURL   string
+	CA       *CA
+	// Env is the environment that configures cosign to use the stack,
+	// written to the EnvFile of Dir as shell exports.
+	Env [][2]string
+
+	servers []*http.Server
+	done    chan error
+}
+
+// Start starts the registry, timestamp authority and CA of a stack in
+// o.Dir, or in a new temporary directory, and writes the trust material and
+// environment of the stack there. The stack runs until ctx is done.
+func Start(ctx context.Context, o options.DevStackUpOptions) (*Stack, error) {
+	dir := o.Dir
+	if dir == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "cosign-dev-stack-*"); err != nil {
+			return nil, err
+		}
+	} else if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	ca, err := NewCA(o.Identity, o.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := tsa.NewEphemeralServer()
+	if err != nil {
+		return nil, err
+	}
+	s := &Stack{Dir: dir, CA: ca, done: make(chan error, 3)}
+
+	regListener, err := net.Listen("tcp", o.RegistryAddress)
+	if err != nil {
+		return nil, fmt.Errorf("registry: %w", err)
+	}
+	tsaListener, err := net.Listen("tcp", o.TSAAddress)
+	if err != nil {
+		regListener.Close()
+		return nil, fmt.Errorf("timestamp authority: %w", err)
+	}
+	socket := filepath.Join(dir, CASocket)
+	_ = os.Remove(socket)
+	caListener, err := net.Listen("unix", socket)
+	if err != nil {
+		regListener.Close()
+		tsaListener.Close()
+		return nil, fmt.Errorf("certificate authority: %w", err)
+	}
+	s.Registry = regListener.Addr().String()
+	s.TSAURL = "http://" + tsaListener.Addr().String() + tsa.TimestampPath
+
+	if err := s.writeFiles(ts); err != nil {
+		regListener.Close()
+		tsaListener.Close()
+		caListener.Close()
+		return nil, err
+	}
+
+	for _, srv := range []struct {
+		l net.Listener
+		h http.Handler
+	}{
+		{regListener, registry.New(registry.Logger(log.New(io.Discard, "", 0)))},
+		{tsaListener, ts},
+	} {
+		hs := &http.Server{Handler: srv.h, ReadHeaderTimeout: 10 * time.Second}
+		s.servers = append(s.servers, hs)
+		go func(l net.Listener) {
+			if err := hs.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+				s.done <- err
+				return
+			}
+			s.done <- nil
+		}(srv.l)
+	}
+	go func() { s.done <- ca.Serve(ctx, caListener) }()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		for _, hs := range s.servers {
+			_ = hs.Shutdown(shutdownCtx)
+		}
+	}()
+	return s, nil
+}
+
+// Wait waits for the servers of s to stop, and returns the first error of
+// one of them.
+func (s *Stack) Wait() error {
+	var first error
+	for i := 0; i < cap(s.done); i++ {
+		if err := <-s.done; err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// writeFiles writes the certificate chains, the trusted root and the
+// environment of s to its directory.
+func (s *Stack) writeFiles(ts *tsa.Server) error {
+	caChain, err := cryptoutils.MarshalCertificatesToPEM(s.CA.Chain())
+	if err != nil {
+		return err
+	}
+	tsaChain, err := ts.CertChainPEM()
+	if err != nil {
+		return err
+	}
+	tsaCerts, err := cryptoutils.UnmarshalCertificatesFromPEM(tsaChain)
+	if err != nil {
+		return err
+	}
+	root, err := trustedroot.Marshal(
+		[]trustedroot.Authority{{URI: "unix:" + filepath.Join(s.Dir, CASocket), Chain: s.CA.Chain()}},
+		[]trustedroot.Authority{{URI: s.TSAURL, Chain: tsaCerts}},
+	)
+	if err != nil {
+		return err
+	}
+
+	s.Env = [][2]string{
+		{"COSIGN_FULCIO_URL", "unix:" + filepath.Join(s.Dir, CASocket)},
+		{"COSIGN_TIMESTAMP_SERVER_URL", s.TSAURL},
+		{"COSIGN_TLOG_UPLOAD", "false"},
+		{env.VariableSigstoreRootFile.String(), filepath.Join(s.Dir, CAChainFile)},
+		{"COSIGN_TIMESTAMP_CERTIFICATE_CHAIN", filepath.Join(s.Dir, TSAChainFile)},
+		{"COSIGN_INSECURE_IGNORE_TLOG", "true"},
+		{"COSIGN_INSECURE_IGNORE_SCT", "true"},
+		{"COSIGN_CERTIFICATE_IDENTITY", s.CA.Identity},
+		{"COSIGN_CERTIFICATE_OIDC_ISSUER", s.CA.Issuer},
+		{"DEV_STACK_REGISTRY", s.Registry},
+	}
+	var sb strings.Builder
+	for _, kv := range s.Env {
+		fmt.Fprintf(&sb, "export %s=%s\n", kv[0], shellQuote(kv[1]))
+	}
+
+	for name, b := range map[string][]byte{
+		CAChainFile:     caChain,
+		TSAChainFile:    tsaChain,
+		TrustedRootFile: append(root, '\n'),
+		EnvFile:         []byte(sb.String()),
+	} {
+		if err := os.WriteFile(filepath.Join(s.Dir, name), b, 0o600); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
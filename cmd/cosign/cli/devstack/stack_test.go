@@ -0,0 +1,144 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devstack
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/localca"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+func TestStart(t *testing.T) {
+	// Unix socket paths are short, so the stack isn't in t.TempDir().
+	dir, err := os.MkdirTemp("", "stack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := Start(ctx, options.DevStackUpOptions{
+		Dir:             dir,
+		RegistryAddress: "localhost:0",
+		TSAAddress:      "localhost:0",
+		Identity:        "dev@example.com",
+		Issuer:          "https://oidc.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cosign.LoadTrustedRoot(filepath.Join(dir, TrustedRootFile)); err != nil {
+		t.Errorf("LoadTrustedRoot() = %v", err)
+	}
+	env, err := os.ReadFile(filepath.Join(dir, EnvFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"export COSIGN_FULCIO_URL='unix:" + filepath.Join(dir, CASocket) + "'\n",
+		"export COSIGN_TIMESTAMP_SERVER_URL='" + s.TSAURL + "'\n",
+		"export DEV_STACK_REGISTRY='" + s.Registry + "'\n",
+	} {
+		if !strings.Contains(string(env), want) {
+			t.Errorf("env = %s, want it to contain %q", env, want)
+		}
+	}
+
+	resp, err := http.Get("http://" + s.Registry + "/v2/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /v2/ of the registry = %d", resp.StatusCode)
+	}
+
+	// The CA certifies signers as the identity of the stack, with the chain
+	// of its root file.
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := localca.New("unix:" + filepath.Join(dir, CASocket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, _, err := c.SigningCert(ctx, sv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootPEM, err := os.ReadFile(filepath.Join(dir, CAChainFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := cryptoutils.UnmarshalCertificatesFromPEM(rootPEM)
+	if err != nil || len(chain) != 2 {
+		t.Fatalf("CA chain = %d certificates, %v", len(chain), err)
+	}
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	intermediates.AddCert(chain[0])
+	roots.AddCert(chain[1])
+	if _, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}}); err != nil {
+		t.Errorf("verifying the certificate = %v", err)
+	}
+	ce := cosign.CertExtensions{Cert: certs[0]}
+	if len(certs[0].EmailAddresses) != 1 || certs[0].EmailAddresses[0] != "dev@example.com" || ce.GetIssuer() != "https://oidc.example.com" {
+		t.Errorf("certificate of %v from %s, want dev@example.com from https://oidc.example.com", certs[0].EmailAddresses, ce.GetIssuer())
+	}
+
+	cancel()
+	if err := s.Wait(); err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+}
+
+func TestIssue(t *testing.T) {
+	ca, err := NewCA("https://github.com/example/app/.github/workflows/release.yml@refs/heads/main", "https://token.actions.githubusercontent.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, req := range map[string]localca.Request{
+		"apiVersion": {APIVersion: "v0"},
+		"no CSR":     {APIVersion: localca.APIVersion, CertificateSigningRequest: "not a CSR"},
+	} {
+		if resp := ca.Issue(req); resp.Error == "" {
+			t.Errorf("Issue() with %s: expected an error", name)
+		}
+	}
+}
@@ -81,6 +81,10 @@ Shell-like variables in the Dockerfile's FROM lines will be substituted with val
 			if err != nil {
 				return err
 			}
+			signReportKeyOpts, err := o.SignReportKeyOpts()
+			if err != nil {
+				return err
+			}
 			v := &dockerfile.VerifyDockerfileCommand{
 				VerifyCommand: verify.VerifyCommand{
 					RegistryOptions:              o.Registry,
@@ -99,17 +103,42 @@ Shell-like variables in the Dockerfile's FROM lines will be substituted with val
 					Sk:                           o.SecurityKey.Use,
 					Slot:                         o.SecurityKey.Slot,
 					Output:                       o.Output,
+					OutputTemplate:               o.OutputTemplate.Template,
+					GitHubSummary:                o.GitHubSummary,
 					RekorURL:                     o.Rekor.URL,
 					Attachment:                   o.Attachment,
 					Annotations:                  annotations,
 					LocalImage:                   o.LocalImage,
 					Offline:                      o.CommonVerifyOptions.Offline,
-					TSACertChainPath:             o.CommonVerifyOptions.TSACertChainPath,
+					TSACertChainPaths:            o.CommonVerifyOptions.TSACertChainPaths,
 					IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+					Denylist:                     o.CommonVerifyOptions.Denylist,
+					KeyUsagePolicy:               o.CommonVerifyOptions.KeyUsagePolicy,
+					Witnesses:                    o.CommonVerifyOptions.Witnesses,
+					WarningsAsErrors:             o.WarningsAsErrors,
+					SignReport:                   o.SignReport,
+					SignReportKeyOpts:            signReportKeyOpts,
+					SignReportCertificate:        o.SignReportCertificate,
+					SourceRepositories:           o.SourceRepositories,
+					Batch:                        o.Batch,
+					AllowConverted:               o.AllowConverted,
+					Recursive:                    o.Recursive,
+					LayerPolicy:                  o.LayerPolicy,
+					Quota:                        o.Quota,
+					EnforceExpiry:                o.EnforceExpiry,
+					Encryption:                   o.Encryption,
+					Countersigners:               o.Countersigners,
+					Cache:                        o.Cache,
+					Evaluation:                   o.Evaluation,
 				},
 				BaseOnly: o.BaseImageOnly,
 			}
-			return v.Exec(cmd.Context(), args)
+			if err := setImagePolicy(&v.VerifyCommand, &o.VerifyOptions); err != nil {
+				return err
+			}
+			return withFailureReport(cmd, args, o.CommonVerifyOptions.FailureReport, func() error {
+				return v.Exec(cmd.Context(), args)
+			})
 		},
 	}
 
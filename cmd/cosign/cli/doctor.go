@@ -0,0 +1,337 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/sigstore/sigstore/pkg/tuf"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func Doctor() *cobra.Command {
+	o := &options.DoctorOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the connectivity to registries and Sigstore services, and the local clock",
+		Long: `Check the environment cosign runs in before signing or verifying: the
+connectivity to the Fulcio, Rekor and timestamp servers, the TUF trust root and
+its mirror, the pull and push access to a repository, and the skew between the
+local clock and the clocks of these servers.
+
+Each check prints what to do if it fails. The command fails if any check fails.`,
+		Example: `  cosign doctor
+
+  # also check the access to the repository signatures are pushed to
+  cosign doctor --repository example.com/app
+
+  # check a private Sigstore deployment
+  cosign doctor --fulcio-url https://fulcio.example.com --rekor-url https://rekor.example.com --timestamp-server-url https://tsa.example.com/api/v1/timestamp`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DoctorCmd(cmd.Context(), *o)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// The statuses of a DoctorCheck.
+const (
+	DoctorOK      = "ok"
+	DoctorWarning = "warning"
+	DoctorFailed  = "failed"
+	DoctorSkipped = "skipped"
+)
+
+// DoctorCheck is the result of one of the checks of cosign doctor.
+type DoctorCheck struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Detail      string `json:"detail"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// DoctorCmd runs the checks configured in o, prints their results, and
+// returns an error if any of them failed.
+func DoctorCmd(ctx context.Context, o options.DoctorOptions) error {
+	if o.Output != "text" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, must be text or json", o.Output)
+	}
+	checks := RunDoctorChecks(ctx, o)
+
+	if o.Output == "json" {
+		b, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		for _, c := range checks {
+			fmt.Printf("%-8s %s: %s\n", strings.ToUpper(c.Status), c.Name, c.Detail)
+			if c.Remediation != "" {
+				fmt.Printf("%-8s -> %s\n", "", c.Remediation)
+			}
+		}
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == DoctorFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// RunDoctorChecks runs the checks configured in o and returns their results.
+func RunDoctorChecks(ctx context.Context, o options.DoctorOptions) []DoctorCheck {
+	d := &doctor{client: &http.Client{Timeout: 30 * time.Second}}
+	if o.Repository != "" {
+		d.checkRegistry(ctx, o.Repository, o.Registry)
+	}
+	if o.FulcioURL != "" {
+		d.checkService(ctx, "fulcio", o.FulcioURL, "/api/v2/trustBundle",
+			"check --fulcio-url, and that proxies and firewalls allow HTTPS connections to Fulcio")
+	}
+	if o.RekorURL != "" {
+		d.checkService(ctx, "rekor", o.RekorURL, "/api/v1/log",
+			"check --rekor-url, and that proxies and firewalls allow HTTPS connections to Rekor")
+	}
+	if o.TSAServerURL != "" {
+		d.checkTSA(ctx, o.TSAServerURL)
+	}
+	if o.TUF {
+		d.checkTUF(ctx)
+	}
+	d.checkClock(o.MaxClockSkew)
+	return d.checks
+}
+
+type doctor struct {
+	client *http.Client
+	checks []DoctorCheck
+	// skews are the differences between the clocks of the contacted servers
+	// and the local clock, by server.
+	skews map[string]time.Duration
+}
+
+func (d *doctor) add(name, status, remediation, detail string, a ...interface{}) {
+	d.checks = append(d.checks, DoctorCheck{
+		Name:        name,
+		Status:      status,
+		Detail:      fmt.Sprintf(detail, a...),
+		Remediation: remediation,
+	})
+}
+
+// get fetches url with client, and records the skew of the server's clock
+// from its Date header.
+func (d *doctor) get(ctx context.Context, client *http.Client, url string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("User-Agent", options.UserAgent())
+	sent := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	received := time.Now()
+
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		if d.skews == nil {
+			d.skews = map[string]time.Duration{}
+		}
+		// The server set the date somewhere in between, and truncated it
+		// to the second.
+		local := sent.Add(received.Sub(sent) / 2).Truncate(time.Second)
+		d.skews[req.URL.Host] = date.Sub(local)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return resp.StatusCode, body, err
+}
+
+// checkService checks that base+path of a Sigstore service responds.
+func (d *doctor) checkService(ctx context.Context, service, base, path, remediation string) {
+	status, body, err := d.get(ctx, d.client, strings.TrimSuffix(base, "/")+path)
+	switch {
+	case err != nil:
+		d.add(service, DoctorFailed, remediation, "%s is unreachable: %v", base, err)
+	case status != http.StatusOK:
+		d.add(service, DoctorFailed, remediation, "%s responded with HTTP %d", base, status)
+	case service == "rekor":
+		var info struct {
+			TreeSize int64 `json:"treeSize"`
+		}
+		if err := json.Unmarshal(body, &info); err != nil {
+			d.add(service, DoctorFailed, remediation, "%s didn't respond with the log info: %v", base, err)
+			return
+		}
+		d.add(service, DoctorOK, "", "%s is reachable, the log has %d entries", base, info.TreeSize)
+	default:
+		d.add(service, DoctorOK, "", "%s is reachable", base)
+	}
+}
+
+// checkTSA checks that the timestamp server responds. Timestamps are
+// requested with POST, so any response to a GET short of a server error
+// means it is reachable.
+func (d *doctor) checkTSA(ctx context.Context, url string) {
+	const remediation = "check --timestamp-server-url, which must be the path of the timestamp API, e.g. https://freetsa.org/tsr"
+	status, _, err := d.get(ctx, d.client, url)
+	switch {
+	case err != nil:
+		d.add("tsa", DoctorFailed, remediation, "%s is unreachable: %v", url, err)
+	case status >= http.StatusInternalServerError:
+		d.add("tsa", DoctorFailed, remediation, "%s responded with HTTP %d", url, status)
+	default:
+		d.add("tsa", DoctorOK, "", "%s is reachable", url)
+	}
+}
+
+// checkTUF checks that the TUF trust root can be loaded and updated, and
+// that its mirror is reachable.
+func (d *doctor) checkTUF(ctx context.Context) {
+	const remediation = "run cosign initialize, with --mirror and --root for a private TUF repository"
+	status, err := tuf.GetRootStatus(ctx)
+	if err != nil {
+		d.add("tuf", DoctorFailed, remediation, "loading the trust root: %v", err)
+		return
+	}
+	if ts, ok := status.Metadata["timestamp.json"]; ok {
+		if expires, err := time.Parse(time.RFC822, ts.Expiration); err == nil && expires.Before(time.Now()) {
+			d.add("tuf", DoctorFailed, remediation, "the trust root from %s expired on %s", status.Remote, ts.Expiration)
+			return
+		}
+	}
+	if !strings.HasPrefix(status.Remote, "http://") && !strings.HasPrefix(status.Remote, "https://") {
+		d.add("tuf", DoctorOK, "", "the trust root from %s is cached in %s", status.Remote, status.Local)
+		return
+	}
+	code, _, err := d.get(ctx, d.client, strings.TrimSuffix(status.Remote, "/")+"/timestamp.json")
+	switch {
+	case err != nil:
+		d.add("tuf", DoctorWarning, remediation, "the trust root is cached in %s, but the mirror %s is unreachable: %v", status.Local, status.Remote, err)
+	case code != http.StatusOK:
+		d.add("tuf", DoctorWarning, remediation, "the trust root is cached in %s, but the mirror %s responded with HTTP %d", status.Local, status.Remote, code)
+	default:
+		d.add("tuf", DoctorOK, "", "the trust root from %s is cached in %s", status.Remote, status.Local)
+	}
+}
+
+// checkRegistry checks that the registry of repo is reachable, and that the
+// credentials in regOpts allow pulling from and pushing to repo.
+func (d *doctor) checkRegistry(ctx context.Context, repo string, regOpts options.RegistryOptions) {
+	r, err := name.NewRepository(repo, regOpts.NameOptions()...)
+	if err != nil {
+		d.add("registry", DoctorFailed, "pass a repository such as example.com/app to --repository", "parsing %s: %v", repo, err)
+		return
+	}
+	reg := r.Registry
+	login := fmt.Sprintf("log in with cosign login %s, or make sure the credentials of docker login or a credential helper are available", reg)
+
+	client := &http.Client{Timeout: d.client.Timeout, Transport: regOpts.Transport()}
+	status, _, err := d.get(ctx, client, fmt.Sprintf("%s://%s/v2/", reg.Scheme(), reg.RegistryStr()))
+	switch {
+	case err != nil:
+		d.add("registry", DoctorFailed, "check the repository, and that proxies and firewalls allow connections to the registry. Use --allow-http-registry for registries without TLS",
+			"%s is unreachable: %v", reg, err)
+		return
+	case status != http.StatusOK && status != http.StatusUnauthorized:
+		d.add("registry", DoctorFailed, "check that the repository is hosted on an OCI registry", "%s responded with HTTP %d", reg, status)
+		return
+	}
+	d.add("registry", DoctorOK, "", "%s is reachable", reg)
+
+	_, err = remote.List(r, regOpts.GetRegistryClientOpts(ctx)...)
+	var terr *transport.Error
+	switch {
+	case err == nil:
+		d.add("registry pull", DoctorOK, "", "the tags of %s can be listed", r)
+	case errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound:
+		d.add("registry pull", DoctorWarning, "check the repository name", "%s doesn't exist yet", r)
+	default:
+		d.add("registry pull", DoctorFailed, login, "no pull access to %s: %v", r, err)
+	}
+
+	if err := remote.CheckPushPermission(r.Tag("cosign-doctor"), regOpts.AuthKeychain(), regOpts.Transport()); err != nil {
+		d.add("registry push", DoctorFailed, login+". To push signatures to another repository, set COSIGN_REPOSITORY",
+			"no push access to %s: %v", r, err)
+		return
+	}
+	d.add("registry push", DoctorOK, "", "signatures and attestations can be pushed to %s", r)
+}
+
+// checkClock checks the skew between the local clock and the clocks of the
+// servers contacted by the other checks.
+func (d *doctor) checkClock(maxSkew time.Duration) {
+	if len(d.skews) == 0 {
+		d.add("clock", DoctorSkipped, "", "no server reported its time")
+		return
+	}
+	hosts := make([]string, 0, len(d.skews))
+	for host := range d.skews {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	var worstHost string
+	var worst time.Duration
+	for _, host := range hosts {
+		if skew := d.skews[host]; worstHost == "" || absDuration(skew) > absDuration(worst) {
+			worstHost, worst = host, skew
+		}
+	}
+	// The server dates are truncated to the second.
+	if absDuration(worst) > maxSkew+time.Second {
+		direction := "behind"
+		if worst < 0 {
+			direction = "ahead of"
+		}
+		d.add("clock", DoctorFailed, "synchronize the local clock, e.g. with NTP. Signing certificates are only valid for 10 minutes and are checked against the transparency log and timestamp times",
+			"the local clock is %s %s the clock of %s", absDuration(worst), direction, worstHost)
+		return
+	}
+	d.add("clock", DoctorOK, "", "the local clock is within %s of the clocks of %d server(s)", maxSkew, len(d.skews))
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
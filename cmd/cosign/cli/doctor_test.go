@@ -0,0 +1,122 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func doctorServer(t *testing.T, skew time.Duration, status int, body string) string {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s.URL
+}
+
+func TestRunDoctorChecks(t *testing.T) {
+	reg := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(reg.Close)
+	repo := strings.TrimPrefix(reg.URL, "http://") + "/app"
+
+	for _, tt := range []struct {
+		name string
+		o    options.DoctorOptions
+		want map[string]string
+	}{{
+		name: "healthy",
+		o: options.DoctorOptions{
+			Repository:   repo,
+			FulcioURL:    doctorServer(t, 0, http.StatusOK, "{}"),
+			RekorURL:     doctorServer(t, 0, http.StatusOK, `{"treeSize":42}`),
+			TSAServerURL: doctorServer(t, 0, http.StatusMethodNotAllowed, ""),
+			MaxClockSkew: time.Minute,
+		},
+		want: map[string]string{
+			"registry":      DoctorOK,
+			"registry pull": DoctorWarning, // nothing was pushed to the repository yet
+			"registry push": DoctorOK,
+			"fulcio":        DoctorOK,
+			"rekor":         DoctorOK,
+			"tsa":           DoctorOK,
+			"clock":         DoctorOK,
+		},
+	}, {
+		name: "unhealthy",
+		o: options.DoctorOptions{
+			FulcioURL:    doctorServer(t, 0, http.StatusBadGateway, ""),
+			RekorURL:     doctorServer(t, -10*time.Minute, http.StatusOK, `{"treeSize":42}`),
+			MaxClockSkew: time.Minute,
+		},
+		want: map[string]string{
+			"fulcio": DoctorFailed,
+			"rekor":  DoctorOK,
+			"clock":  DoctorFailed,
+		},
+	}, {
+		name: "nothing to check",
+		want: map[string]string{
+			"clock": DoctorSkipped,
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := RunDoctorChecks(context.Background(), tt.o)
+			got := map[string]string{}
+			for _, c := range checks {
+				got[c.Name] = c.Status
+				if c.Status == DoctorFailed && c.Remediation == "" {
+					t.Errorf("failed check %s has no remediation", c.Name)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("checks = %+v, want %v", checks, tt.want)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s check = %q, want %q (checks: %+v)", name, got[name], want, checks)
+				}
+			}
+		})
+	}
+}
+
+func TestDoctorCmdFails(t *testing.T) {
+	o := options.DoctorOptions{
+		FulcioURL: doctorServer(t, 0, http.StatusInternalServerError, ""),
+		Output:    "json",
+	}
+	if err := DoctorCmd(context.Background(), o); err == nil || !strings.Contains(err.Error(), "1 of 2 checks failed") {
+		t.Errorf("DoctorCmd() error = %v, want 1 failed check", err)
+	}
+	o.Output = "yaml"
+	if err := DoctorCmd(context.Background(), o); err == nil {
+		t.Error("DoctorCmd() with an unsupported output format succeeded")
+	}
+}
@@ -42,19 +42,24 @@ func Download() *cobra.Command {
 
 func downloadSignature() *cobra.Command {
 	o := &options.RegistryOptions{}
+	so := &options.SignatureDownloadOptions{}
 
 	cmd := &cobra.Command{
-		Use:              "signature",
-		Short:            "Download signatures from the supplied container image",
-		Example:          "  cosign download signature <image uri>",
+		Use:   "signature",
+		Short: "Download signatures from the supplied container image",
+		Example: `  cosign download signature <image uri>
+
+  # list the certificate identities of the signatures
+  cosign download signature --output-template '{{join "," .Cert.EmailAddresses}}{{"\n"}}' <image uri>`,
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return download.SignatureCmd(cmd.Context(), *o, args[0])
+			return download.SignatureCmd(cmd.Context(), *o, *so, args[0])
 		},
 	}
 
 	o.AddFlags(cmd)
+	so.AddFlags(cmd)
 
 	return cmd
 }
@@ -87,9 +92,12 @@ func downloadAttestation() *cobra.Command {
 	ao := &options.AttestationDownloadOptions{}
 
 	cmd := &cobra.Command{
-		Use:              "attestation",
-		Short:            "Download in-toto attestations from the supplied container image",
-		Example:          "  cosign download attestation <image uri> [--predicate-type]",
+		Use:   "attestation",
+		Short: "Download in-toto attestations from the supplied container image",
+		Example: `  cosign download attestation <image uri> [--predicate-type] [--signed-by <identity or fingerprint>]
+
+  # write the in-toto statements of the attestations
+  cosign download attestation --output-template '{{.payload | base64decode}}{{"\n"}}' <image uri>`,
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -19,17 +19,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
-	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/templates"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 )
 
 func AttestationCmd(ctx context.Context, regOpts options.RegistryOptions, attOptions options.AttestationDownloadOptions, imageRef string) error {
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+	tmpl, err := templates.ParseOutput(attOptions.OutputTemplate.Template)
+	if err != nil {
+		return err
+	}
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
 	if err != nil {
 		return err
 	}
@@ -89,12 +95,25 @@ func AttestationCmd(ctx context.Context, regOpts options.RegistryOptions, attOpt
 		se = nse
 	}
 
-	attestations, err := cosign.FetchAttestations(se, predicateType)
+	var match func(oci.Signature) (bool, error)
+	if len(attOptions.SignedBy) > 0 {
+		match = signedBy(attOptions.SignedBy).match
+	}
+	attestations, err := cosign.FetchAttestationsMatching(se, predicateType, match)
 	if err != nil {
 		return err
 	}
+	if len(attestations) == 0 {
+		return fmt.Errorf("no attestations signed by %s found", strings.Join(attOptions.SignedBy, " or "))
+	}
 
 	for _, att := range attestations {
+		if tmpl != nil {
+			if err := templates.WriteOutput(os.Stdout, tmpl, att); err != nil {
+				return err
+			}
+			continue
+		}
 		b, err := json.Marshal(att)
 		if err != nil {
 			return err
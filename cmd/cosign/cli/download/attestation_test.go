@@ -0,0 +1,216 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+func TestAttestationCmdSignedBy(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(strings.TrimPrefix(s.URL, "http://") + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// Attestations of a build and a scanning team, and one that claims to be
+	// of the build team with a copy of its certificate.
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	attest := func(identity, predicate string, certOf *oci.Signature) oci.Signature {
+		t.Helper()
+		cert, key, err := test.GenerateLeafCert(identity, "https://issuer.example.com", rootCert, rootKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sv, err := signature.LoadECDSASignerVerifier(key, crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://example.com/` + predicate + `","predicate":{}}`
+		envelope, err := dsse.WrapSigner(sv, types.IntotoPayloadType).SignMessage(strings.NewReader(statement))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if certOf != nil {
+			if cert, err = (*certOf).Cert(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		certPEM, err := cryptoutils.MarshalCertificateToPEM(cert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		att, err := static.NewAttestation(envelope, static.WithCertChain(certPEM, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return att
+	}
+	build := attest("build@example.com", "provenance", nil)
+	scan := attest("scan@example.com", "vuln", nil)
+	impostor := attest("impostor@example.com", "forged", &build)
+	atts, err := mutate.AppendSignatures(empty.Signatures(), build, scan, impostor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attTag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(attTag, atts); err != nil {
+		t.Fatal(err)
+	}
+	scanCert, err := scan.Cert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanFingerprint, err := cosign.KeyFingerprint(scanCert.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	download := func(t *testing.T, signedBy ...string) ([]string, error) {
+		t.Helper()
+		f, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdout := os.Stdout
+		os.Stdout = f
+		cmdErr := AttestationCmd(ctx, options.RegistryOptions{}, options.AttestationDownloadOptions{SignedBy: signedBy}, ref.String())
+		os.Stdout = stdout
+		f.Close()
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var predicateTypes []string
+		for _, line := range bytes.Split(bytes.TrimSpace(b), []byte("\n")) {
+			if len(line) == 0 {
+				continue
+			}
+			a := cosign.AttestationPayload{}
+			if err := json.Unmarshal(line, &a); err != nil {
+				t.Fatal(err)
+			}
+			statement, err := base64.StdEncoding.DecodeString(a.PayLoad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			st := struct{ PredicateType string }{}
+			if err := json.Unmarshal(statement, &st); err != nil {
+				t.Fatal(err)
+			}
+			predicateTypes = append(predicateTypes, strings.TrimPrefix(st.PredicateType, "https://example.com/"))
+		}
+		return predicateTypes, cmdErr
+	}
+
+	for _, tt := range []struct {
+		name     string
+		signedBy []string
+		want     string
+	}{
+		{name: "all"},
+		{name: "identity", signedBy: []string{"build@example.com"}, want: "provenance"},
+		{name: "fingerprint", signedBy: []string{strings.ToUpper(scanFingerprint)}, want: "vuln"},
+		{name: "either", signedBy: []string{"build@example.com", scanFingerprint}, want: "provenance vuln"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := download(t, tt.signedBy...)
+			if err != nil {
+				t.Fatalf("AttestationCmd() = %v", err)
+			}
+			if tt.want == "" {
+				if len(got) != 3 {
+					t.Errorf("AttestationCmd() downloaded %v, want all 3 attestations", got)
+				}
+				return
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("AttestationCmd() downloaded %v, want %s", got, tt.want)
+			}
+		})
+	}
+	if _, err := download(t, "impostor@example.com"); err == nil || !strings.Contains(err.Error(), "no attestations signed by impostor@example.com") {
+		t.Errorf("AttestationCmd() of an unknown signer = %v, want none found", err)
+	}
+
+	// Each attestation is written with the template.
+	f, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = f
+	attOpts := options.AttestationDownloadOptions{SignedBy: []string{"scan@example.com"},
+		OutputTemplate: options.OutputTemplateOptions{Template: `{{.payloadType}} {{base64decode .payload}}{{"\n"}}`}}
+	cmdErr := AttestationCmd(ctx, options.RegistryOptions{}, attOpts, ref.String())
+	os.Stdout = stdout
+	f.Close()
+	if cmdErr != nil {
+		t.Fatalf("AttestationCmd() with a template = %v", cmdErr)
+	}
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); !strings.HasPrefix(got, "application/vnd.in-toto+json {") || !strings.Contains(got, "https://example.com/vuln") || strings.Count(got, "\n") != 1 {
+		t.Errorf("AttestationCmd() with a template wrote %q", got)
+	}
+}
@@ -23,7 +23,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/pkg/oci"
@@ -47,7 +46,7 @@ func SBOMCmd(
 	ctx context.Context, regOpts options.RegistryOptions,
 	dnOpts options.SBOMDownloadOptions, imageRef string, out io.Writer,
 ) ([]string, error) {
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,79 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestSBOMCmdDigest(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+
+	// The image is pushed by digest only, as in tag-less promotion pipelines.
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(strings.TrimPrefix(s.URL, "http://") + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	const sbom = `{"spdxVersion":"SPDX-2.3"}`
+	f, err := static.NewFile([]byte(sbom), static.WithLayerMediaType("text/spdx+json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sbomTag, err := ociremote.SBOMTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sbomTag, f); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	got, err := SBOMCmd(ctx, options.RegistryOptions{}, options.SBOMDownloadOptions{}, ref.String(), &out)
+	if err != nil {
+		t.Fatalf("SBOMCmd() = %v", err)
+	}
+	if len(got) != 1 || got[0] != sbom || out.String() != sbom {
+		t.Errorf("SBOMCmd() = %v, wrote %q, want %s", got, out.String(), sbom)
+	}
+}
@@ -19,14 +19,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 
-	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/templates"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 )
 
-func SignatureCmd(ctx context.Context, regOpts options.RegistryOptions, imageRef string) error {
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+func SignatureCmd(ctx context.Context, regOpts options.RegistryOptions, sigOptions options.SignatureDownloadOptions, imageRef string) error {
+	tmpl, err := templates.ParseOutput(sigOptions.OutputTemplate.Template)
+	if err != nil {
+		return err
+	}
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
 	if err != nil {
 		return err
 	}
@@ -39,6 +45,12 @@ func SignatureCmd(ctx context.Context, regOpts options.RegistryOptions, imageRef
 		return err
 	}
 	for _, sig := range signatures {
+		if tmpl != nil {
+			if err := templates.WriteOutput(os.Stdout, tmpl, sig); err != nil {
+				return err
+			}
+			continue
+		}
 		b, err := json.Marshal(sig)
 		if err != nil {
 			return err
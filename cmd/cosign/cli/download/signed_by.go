@@ -0,0 +1,72 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"bytes"
+	"crypto"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+// signedBy matches the attestations signed by one of the signers of
+// --signed-by, by the identity or key fingerprint of their certificate.
+type signedBy []string
+
+// match reports whether att was signed by one of the signers. The
+// certificate of att must have signed it, so that an attestation can't be
+// attributed to a signer by attaching its certificate, but isn't verified.
+func (s signedBy) match(att oci.Signature) (bool, error) {
+	cert, err := att.Cert()
+	if err != nil {
+		return false, err
+	}
+	if cert == nil {
+		// Attestations signed with a key can't be attributed to a signer.
+		return false, nil
+	}
+	fp, err := cosign.KeyFingerprint(cert.PublicKey)
+	if err != nil {
+		return false, nil //nolint: nilerr
+	}
+	matched := false
+	for _, signer := range s {
+		if fingerprint := strings.ToLower(signer); strings.HasPrefix(fingerprint, "sha256:") {
+			matched = fingerprint == fp
+		} else {
+			matched = cosign.CheckCertificatePolicy(cert, &cosign.CheckOpts{Identities: []cosign.Identity{{Subject: signer}}}) == nil
+		}
+		if matched {
+			break
+		}
+	}
+	if !matched {
+		return false, nil
+	}
+	verifier, err := signature.LoadVerifier(cert.PublicKey, crypto.SHA256)
+	if err != nil {
+		return false, nil //nolint: nilerr
+	}
+	payload, err := att.Payload()
+	if err != nil {
+		return false, err
+	}
+	return dsse.WrapVerifier(verifier).VerifySignature(bytes.NewReader(payload), nil) == nil, nil
+}
@@ -0,0 +1,73 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/embed"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func Embed() *cobra.Command {
+	o := &options.EmbedOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "embed",
+		Short: "Embed the bundle of a signed executable or archive in it",
+		Long: `Embed the bundle of a signed executable or archive in it, so that it can be
+verified with cosign verify-blob --embedded, for distribution channels without
+sidecar bundle files.
+
+The bundle is written to the location of the format of the file:
+
+  - the .sigstore section of an ELF executable, the .sigstor section of a PE
+    executable or the __SIGSTORE,__bundle section of a Mach-O executable,
+    which must be zero-filled when the executable is signed
+  - the comment of a zip archive, which must have none
+  - a subfield of the extra field of the header of a gzip file, e.g. a
+    .tar.gz archive
+  - a .sigstore.bundle.json member at the end of a tar archive
+
+Executables without a reserved section, and files of other formats, have the
+bundle appended to them. Their contents as signed are restored for
+verification, so that the file is signed as usual before its bundle is
+embedded.`,
+		Example: `  # reserve 16KiB for the bundle of an ELF executable, sign it, and embed its bundle
+  head -c 16384 /dev/zero > sigstore.bin
+  objcopy --add-section .sigstore=sigstore.bin app
+  cosign sign-blob --yes --bundle app.bundle app
+  cosign embed --bundle app.bundle app
+
+  # reserve the section of a Mach-O executable when linking it with go build
+  go build -ldflags "-extldflags '-sectcreate __SIGSTORE __bundle sigstore.bin'" -o app .
+
+  # embed the bundle of a release archive in it
+  cosign sign-blob --yes --bundle app.tar.gz.bundle app.tar.gz
+  cosign embed --bundle app.tar.gz.bundle app.tar.gz
+
+  # verify the executable with its embedded bundle
+  cosign verify-blob --embedded --certificate-identity foo@example.com --certificate-oidc-issuer https://issuer.example.com app`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return embed.EmbedCmd(cmd.Context(), *o, args[0])
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
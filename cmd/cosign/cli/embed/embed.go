@@ -0,0 +1,75 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/blob"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// EmbedCmd embeds the bundle of o in the file, an executable or archive, in
+// place or to o.Output, so that it can be verified with cosign verify-blob
+// --embedded.
+func EmbedCmd(ctx context.Context, o options.EmbedOptions, file string) error {
+	bundle, err := os.ReadFile(filepath.Clean(o.BundlePath))
+	if err != nil {
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+	var b cosign.LocalSignedPayload
+	if err := json.Unmarshal(bundle, &b); err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	if b.Base64Signature == "" {
+		return errors.New("the bundle has no signature")
+	}
+	// The bundle is compacted, so that it fits in small reserved sections.
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, bundle); err != nil {
+		return err
+	}
+
+	fi, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	contents, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return err
+	}
+	embedded, err := blob.EmbedBundle(contents, compact.Bytes())
+	if err != nil {
+		return fmt.Errorf("embedding bundle in %s: %w", file, err)
+	}
+	output := o.Output
+	if output == "" {
+		output = file
+	}
+	if err := os.WriteFile(output, embedded, fi.Mode().Perm()); err != nil {
+		return err
+	}
+	ui.Infof(ctx, "Embedded the bundle of %s in %s", file, output)
+	return nil
+}
@@ -0,0 +1,126 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestEmbedCmd(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+	write := func(name string, b []byte, mode os.FileMode) string {
+		p := filepath.Join(td, name)
+		if err := os.WriteFile(p, b, mode); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := write("cosign.pub", pub, 0o600)
+
+	executable := []byte("#!/bin/sh\necho hello\n")
+	app := write("app", executable, 0o755)
+	sig, err := sv.SignMessage(bytes.NewReader(executable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.MarshalIndent(cosign.LocalSignedPayload{Base64Signature: base64.StdEncoding.EncodeToString(sig)}, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundlePath := write("app.bundle", b, 0o600)
+
+	if err := EmbedCmd(ctx, options.EmbedOptions{BundlePath: bundlePath}, app); err != nil {
+		t.Fatalf("EmbedCmd() = %v", err)
+	}
+	if fi, err := os.Stat(app); err != nil || fi.Mode().Perm() != 0o755 {
+		t.Errorf("embedded executable = %v, %v, want mode 0755", fi, err)
+	}
+	v := &verify.VerifyBlobCmd{KeyOpts: options.KeyOpts{KeyRef: keyPath}, IgnoreSCT: true, IgnoreTlog: true, Embedded: true}
+	if err := v.Exec(ctx, app); err != nil {
+		t.Errorf("VerifyBlobCmd.Exec() of the embedded executable = %v", err)
+	}
+	if err := EmbedCmd(ctx, options.EmbedOptions{BundlePath: bundlePath}, app); err == nil || !strings.Contains(err.Error(), "already has an embedded bundle") {
+		t.Errorf("EmbedCmd() of the embedded executable = %v, want an error", err)
+	}
+
+	other := write("other", []byte("#!/bin/sh\necho bye\n"), 0o755)
+	tampered := filepath.Join(td, "tampered")
+	if err := EmbedCmd(ctx, options.EmbedOptions{BundlePath: bundlePath, Output: tampered}, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Exec(ctx, tampered); err == nil {
+		t.Error("VerifyBlobCmd.Exec() of another executable with the bundle: expected an error")
+	}
+	if err := v.Exec(ctx, other); err == nil || !strings.Contains(err.Error(), "no embedded bundle") {
+		t.Errorf("VerifyBlobCmd.Exec() of an executable without a bundle = %v, want an error", err)
+	}
+
+	// A release archive.
+	var archive bytes.Buffer
+	zw := gzip.NewWriter(&archive)
+	if _, err := zw.Write(executable); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tarball := write("app.tar.gz", archive.Bytes(), 0o644)
+	sig, err = sv.SignMessage(bytes.NewReader(archive.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(cosign.LocalSignedPayload{Base64Signature: base64.StdEncoding.EncodeToString(sig)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := EmbedCmd(ctx, options.EmbedOptions{BundlePath: write("app.tar.gz.bundle", b, 0o600)}, tarball); err != nil {
+		t.Fatalf("EmbedCmd() of the archive = %v", err)
+	}
+	if err := v.Exec(ctx, tarball); err != nil {
+		t.Errorf("VerifyBlobCmd.Exec() of the embedded archive = %v", err)
+	}
+}
@@ -17,14 +17,17 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/cosign/featuregates"
 )
 
 func Env() *cobra.Command {
@@ -33,11 +36,32 @@ func Env() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "env",
 		Short: "Prints Cosign environment variables",
-		Args:  cobra.NoArgs,
+		Long: `Prints Cosign environment variables, and the state of the feature gates.
+
+With --list-vars, the registered variables are listed as a table of their
+names, the types of their values, their defaults and their current values.
+Every command warns about the registered variables whose values aren't of
+their type, and about the COSIGN_ variables that are neither registered nor
+set flags, which are most likely misspelled.`,
+		Example: `  # print the environment variables and feature gates
+  cosign env
+
+  # list the types and defaults of the registered environment variables
+  cosign env --list-vars`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			envVars := env.EnvironmentVariables()
+			if o.ListVars {
+				return listEnv(cmd.OutOrStdout(), envVars, getEnv(), o.ShowSensitiveValues)
+			}
 			printEnv(envVars, getEnv(), getEnviron(), o.ShowDescriptions, o.ShowSensitiveValues)
 
+			gates, err := featuregates.Gates()
+			if err != nil {
+				return err
+			}
+			printFeatureGates(gates, o.ShowDescriptions)
+
 			return nil
 		},
 	}
@@ -132,6 +156,41 @@ func printEnv(envVars map[env.Variable]env.VariableOpts,
 	}
 }
 
+// listEnv writes envVars to w as a table of their names, types, defaults
+// and values, masking the values of sensitive variables unless showSensitive.
+func listEnv(w io.Writer, envVars map[env.Variable]env.VariableOpts, envGet envGetter, showSensitive bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tDEFAULT\tVALUE")
+	for _, e := range sortEnvKeys(envVars) {
+		opts := envVars[e]
+		val := envGet(e)
+		if opts.Sensitive && !showSensitive && val != "" {
+			val = "******"
+		}
+		name := e.String()
+		if opts.Deprecated != "" {
+			name += " (deprecated)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, opts.ValueType(), opts.Default, val)
+	}
+	return tw.Flush()
+}
+
+// printFeatureGates prints the state of the feature gates as <feature>=<bool>
+// lines, as they would be given to --feature-gates.
+func printFeatureGates(gates []featuregates.Gate, showDescription bool) {
+	if showDescription {
+		fmt.Printf("# Feature gates below are set with --feature-gates, %s or %s.\n", env.VariableFeatureGates, featuregates.ConfigPath())
+	}
+	for _, g := range gates {
+		if showDescription {
+			fmt.Printf("# %s %s\n", g.Feature, g.Description)
+			fmt.Printf("# Stage: %s, set by: %s\n", g.Stage, g.Source)
+		}
+		fmt.Printf("%s=%t\n", g.Feature, g.Enabled)
+	}
+}
+
 func sortEnvKeys(envVars map[env.Variable]env.VariableOpts) []env.Variable {
 	keys := []env.Variable{}
 	for k := range envVars {
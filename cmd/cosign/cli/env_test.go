@@ -19,9 +19,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/cosign/featuregates"
 )
 
 const (
@@ -302,3 +305,59 @@ func TestPrintEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintFeatureGates(t *testing.T) {
+	orgStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printFeatureGates([]featuregates.Gate{{
+		Feature: "TestGate",
+		Spec:    featuregates.Spec{Description: "is a test gate", Stage: featuregates.Alpha},
+		Enabled: true,
+		Source:  featuregates.SourceFlag,
+	}}, false)
+
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = orgStdout
+
+	if want := "TestGate=true\n"; string(out) != want {
+		t.Errorf("Expected to get %q\n, but got %q", want, string(out))
+	}
+}
+
+func TestListEnv(t *testing.T) {
+	vars := map[env.Variable]env.VariableOpts{
+		VariableTest1: {Type: env.TypeInt, Default: "10"},
+		VariableTest2: {Sensitive: true, Deprecated: "use COSIGN_TEST1"},
+	}
+	get := func(v env.Variable) string {
+		return map[env.Variable]string{VariableTest1: "5", VariableTest2: "secret"}[v]
+	}
+	var out strings.Builder
+	if err := listEnv(&out, vars, get, false); err != nil {
+		t.Fatal(err)
+	}
+	want := `NAME                       TYPE    DEFAULT  VALUE
+COSIGN_TEST1               int     10       5
+COSIGN_TEST2 (deprecated)  string           ******
+`
+	if out.String() != want {
+		t.Errorf("listEnv() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestFlagEnvVarsKnown(t *testing.T) {
+	// The variables of flags, such as those the dev stack exports, aren't
+	// warned about as unknown.
+	known := options.FlagEnvVars(New())
+	for _, v := range []string{"COSIGN_FULCIO_URL", "COSIGN_TLOG_UPLOAD", "COSIGN_CERTIFICATE_IDENTITY", "COSIGN_OUTPUT_FILE"} {
+		if !known[v] {
+			t.Errorf("FlagEnvVars() doesn't have %s", v)
+		}
+	}
+	if w := env.Check([]string{"COSIGN_FULCIO_URL=https://fulcio.example.com"}, known); len(w) != 0 {
+		t.Errorf("Check() = %v", w)
+	}
+}
@@ -0,0 +1,210 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package environments evaluates which of the environments of a promotion
+// pipeline, each with its own verification policy, images qualify for.
+package environments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+)
+
+// Policy is the --environment-policy of cosign verify: the environments that
+// images are evaluated for, in order.
+type Policy struct {
+	Environments []Environment `json:"environments"`
+}
+
+// Environment is the policy of an environment: a policy entry, as in the
+// --policy of cosign proxy, whose glob, labels and annotations select the
+// images that may be deployed to it and whose key or certificate identity
+// must have signed them, with SignatureAnnotations in the signature, e.g.
+// the env=prod of cosign sign -a env=prod.
+type Environment struct {
+	Name string `json:"name"`
+	proxy.PolicyEntry
+	SignatureAnnotations map[string]string `json:"signatureAnnotations,omitempty"`
+}
+
+// ReadPolicy reads and validates the policy in file.
+func ReadPolicy(file string) (*Policy, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policy{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing environment policy %s: %w", file, err)
+	}
+	if len(p.Environments) == 0 {
+		return nil, fmt.Errorf("environment policy %s has no environments", file)
+	}
+	seen := map[string]bool{}
+	for i, e := range p.Environments {
+		if e.Name == "" {
+			return nil, fmt.Errorf("environment policy %s: environment %d has no name", file, i)
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("environment policy %s: environment %s is listed twice", file, e.Name)
+		}
+		seen[e.Name] = true
+		if err := e.Validate(); err != nil {
+			return nil, fmt.Errorf("environment policy %s: environment %s: %w", file, e.Name, err)
+		}
+	}
+	return p, nil
+}
+
+// Matrix is the result of the evaluation of images for the environments of
+// a policy.
+type Matrix struct {
+	Images []ImageResult `json:"images"`
+}
+
+// ImageResult is the result of an image, by digest, for each environment.
+type ImageResult struct {
+	Image        string              `json:"image"`
+	Environments []EnvironmentResult `json:"environments"`
+}
+
+// EnvironmentResult is whether an image qualifies for the environment Name,
+// or the Reason it doesn't.
+type EnvironmentResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Qualified returns the environments that r qualifies for.
+func (r ImageResult) Qualified() []string {
+	var names []string
+	for _, e := range r.Environments {
+		if e.Passed {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// Evaluate verifies each image, once resolved to its digest so that every
+// environment sees the same image, as v does with the policy of each
+// environment of p.
+func Evaluate(ctx context.Context, p *Policy, v *verify.VerifyCommand, images []string) (*Matrix, error) {
+	if v.LocalImage || v.BatchVerify.File != "" || v.OfflineBundle.BundleFile != "" || v.SignReport != "" {
+		return nil, errors.New("an environment policy can't be used with --local-image, --batch-file, --bundle-file or --sign-report")
+	}
+	if len(images) == 0 {
+		return nil, errors.New("no images to evaluate")
+	}
+	opts, err := v.RegistryOptions.ClientOpts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("constructing client options: %w", err)
+	}
+	m := &Matrix{Images: []ImageResult{}}
+	for _, img := range images {
+		ref, err := oci.ParseReference(img, v.NameOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("parsing reference: %w", err)
+		}
+		digest, err := ociremote.ResolveDigest(ref, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", img, err)
+		}
+		r := ImageResult{Image: digest.String()}
+		for i := range p.Environments {
+			r.Environments = append(r.Environments, p.Environments[i].evaluate(ctx, v, digest))
+		}
+		m.Images = append(m.Images, r)
+	}
+	return m, nil
+}
+
+func (e *Environment) evaluate(ctx context.Context, v *verify.VerifyCommand, ref name.Digest) EnvironmentResult {
+	r := EnvironmentResult{Name: e.Name}
+	match, err := (&proxy.Policy{Images: []proxy.PolicyEntry{e.PolicyEntry}}).Match(ctx, ref, v.GetRegistryClientOpts(ctx)...)
+	if err != nil {
+		r.Reason = err.Error()
+		return r
+	}
+	if match == nil {
+		r.Reason = "the image isn't in the repositories, or doesn't have the labels and annotations, of the environment"
+		return r
+	}
+	ev := *v
+	e.Apply(&ev)
+	// Only the matrix is written to stdout.
+	ev.OnVerified = func(context.Context, name.Reference, []oci.Signature) error {
+		return nil
+	}
+	if len(e.SignatureAnnotations) > 0 {
+		annotations := map[string]interface{}{}
+		for k, val := range v.Annotations.Annotations {
+			annotations[k] = val
+		}
+		for k, val := range e.SignatureAnnotations {
+			annotations[k] = val
+		}
+		ev.Annotations = sigs.AnnotationsMap{Annotations: annotations}
+	}
+	if err := ev.Exec(ctx, []string{ref.String()}); err != nil {
+		r.Reason = err.Error()
+		return r
+	}
+	r.Passed = true
+	return r
+}
+
+// Write writes m to w, as JSON or, with output text, as a table.
+func (m *Matrix) Write(w io.Writer, output string) error {
+	if output != "text" {
+		b, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "IMAGE\tENVIRONMENT\tRESULT\tREASON")
+	for _, img := range m.Images {
+		for _, e := range img.Environments {
+			result := "pass"
+			if !e.Passed {
+				result = "fail"
+			}
+			// Each result is a row, even for reasons of several lines.
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", img.Image, e.Name, result, strings.Join(strings.Fields(e.Reason), " "))
+		}
+	}
+	return tw.Flush()
+}
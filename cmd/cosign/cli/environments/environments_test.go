@@ -0,0 +1,187 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package environments
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+// newTestKey returns a signer and the path of its public key.
+func newTestKey(t *testing.T) (signature.SignerVerifier, string) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pub")
+	if err := os.WriteFile(path, pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return sv, path
+}
+
+func writePolicy(t *testing.T, policy string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "environments.json")
+	if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEvaluate(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := name.NewTag(host + "/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(tag, img); err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := tag.Context().Digest(h.String())
+
+	// The image is signed by CI, after its tests passed, and not released.
+	ciSigner, ciKey := newTestKey(t)
+	_, releaseKey := newTestKey(t)
+	p, err := (&payload.Cosign{Image: digest, Annotations: map[string]interface{}{"tests": "passed"}}).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := ciSigner.SignMessage(bytes.NewReader(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(p, base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := mutate.AppendSignatures(empty.Signatures(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sigTag, sigs); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := ReadPolicy(writePolicy(t, `{"environments": [
+		{"name": "dev", "key": "`+ciKey+`", "ignoreTlog": true, "ignoreSCT": true},
+		{"name": "staging", "key": "`+ciKey+`", "signatureAnnotations": {"tests": "passed"}, "ignoreTlog": true, "ignoreSCT": true},
+		{"name": "canary", "key": "`+ciKey+`", "signatureAnnotations": {"env": "canary"}, "ignoreTlog": true, "ignoreSCT": true},
+		{"name": "prod", "key": "`+releaseKey+`", "ignoreTlog": true, "ignoreSCT": true},
+		{"name": "partner", "glob": "registry.example.com/*", "key": "`+ciKey+`", "ignoreTlog": true, "ignoreSCT": true}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Evaluate(context.Background(), policy, &verify.VerifyCommand{CheckClaims: true}, []string{tag.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Images) != 1 || m.Images[0].Image != digest.String() {
+		t.Fatalf("images = %+v, want %s", m.Images, digest)
+	}
+	want := map[string]string{
+		"dev":     "",
+		"staging": "",
+		"canary":  "missing or incorrect annotation",
+		"prod":    "no matching signatures",
+		"partner": "isn't in the repositories",
+	}
+	for _, r := range m.Images[0].Environments {
+		reason, ok := want[r.Name]
+		if !ok {
+			t.Errorf("unexpected environment %s", r.Name)
+			continue
+		}
+		if r.Passed != (reason == "") || !strings.Contains(r.Reason, reason) {
+			t.Errorf("%s: passed = %t, reason %q, want it to contain %q", r.Name, r.Passed, r.Reason, reason)
+		}
+	}
+	if got := strings.Join(m.Images[0].Qualified(), ","); got != "dev,staging" {
+		t.Errorf("Qualified() = %s, want dev,staging", got)
+	}
+
+	var out bytes.Buffer
+	if err := m.Write(&out, "text"); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 6 || !strings.HasPrefix(lines[1], digest.String()) || !strings.Contains(lines[1], "dev") || !strings.Contains(lines[1], "pass") {
+		t.Errorf("text matrix = %s", out.String())
+	}
+}
+
+func TestReadPolicy(t *testing.T) {
+	for name, tc := range map[string]struct {
+		policy, want string
+	}{
+		"no environments": {policy: `{"environments": []}`, want: "has no environments"},
+		"no name":         {policy: `{"environments": [{"key": "k.pub"}]}`, want: "has no name"},
+		"twice":           {policy: `{"environments": [{"name": "dev", "key": "k.pub"}, {"name": "dev", "key": "k.pub"}]}`, want: "listed twice"},
+		"no signer":       {policy: `{"environments": [{"name": "dev"}]}`, want: "must set a key or a certificate identity"},
+		"unknown field":   {policy: `{"environments": [{"name": "dev", "key": "k.pub", "env": "dev"}]}`, want: "unknown field"},
+	} {
+		if _, err := ReadPolicy(writePolicy(t, tc.policy)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("ReadPolicy() with %s = %v, want an error containing %q", name, err, tc.want)
+		}
+	}
+}
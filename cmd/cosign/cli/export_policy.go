@@ -0,0 +1,66 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/exportpolicy"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+)
+
+func ExportPolicy() *cobra.Command {
+	o := &options.ExportPolicyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export-policy",
+		Short: "Export the policy of cosign proxy to the policies of admission controllers",
+		Long: `Export the verification policy of cosign proxy, with the roots of trust cosign
+verifies against, to the policies of other admission systems, so that what
+"verified" means is defined in one place.
+
+The formats are:
+  kyverno    a Kyverno ClusterPolicy with a verifyImages rule for each entry.
+             Each rule skips the images of the entries before it, so that an
+             image is verified by its first matching entry, as by the proxy.
+  openshift  an OpenShift ClusterImagePolicy for each entry. Entries need a
+             glob with a trailing /* or a *. registry subdomain as their only
+             wildcard, and keyless entries an email identity and an issuer
+             without regular expressions.
+
+The Fulcio roots, the Rekor public keys and the CT log public keys are those of
+the TUF root of cosign, or of SIGSTORE_ROOT_FILE, SIGSTORE_REKOR_PUBLIC_KEY and
+SIGSTORE_CT_LOG_PUBLIC_KEY_FILE. The resources are written to standard output
+as YAML.`,
+		Example: `  cosign export-policy --policy policy.json | kubectl apply -f -
+
+  # export to OpenShift ClusterImagePolicy resources named prod-0, prod-1, ...
+  cosign export-policy --policy policy.json --format openshift --name prod`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := proxy.ReadPolicy(o.Policy)
+			if err != nil {
+				return err
+			}
+			return exportpolicy.Export(cmd.Context(), cmd.OutOrStdout(), p, *o)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
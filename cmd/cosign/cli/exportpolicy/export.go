@@ -0,0 +1,195 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exportpolicy exports the verification policy of cosign proxy, with
+// the roots of trust cosign verifies against, to the policies of admission
+// controllers, so that what "verified" means is defined in one place.
+package exportpolicy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/fulcio/fulcioroots"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+// Export writes the policy p in format to w, as YAML Kubernetes resources.
+func Export(ctx context.Context, w io.Writer, p *proxy.Policy, o options.ExportPolicyOptions) error {
+	for i, e := range p.Images {
+		if len(e.Labels) > 0 || len(e.Annotations) > 0 {
+			return fmt.Errorf("entry %d: labels and annotations can't be exported", i)
+		}
+	}
+	t := &trust{}
+	var resources []any
+	switch o.Format {
+	case "kyverno":
+		r, err := kyverno(ctx, p, o, t)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, r)
+	case "openshift":
+		r, err := openshift(ctx, p, o, t)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, r...)
+	default:
+		return fmt.Errorf("unsupported format %q, must be kyverno or openshift", o.Format)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for _, r := range resources {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+type metadata struct {
+	Name        string            `yaml:"name"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// trust loads the roots of trust of cosign, from TUF or the SIGSTORE_*
+// environment variables, once they are needed by an entry.
+type trust struct {
+	fulcio, rekor, ctlog []byte
+	rekorKeys            []string
+}
+
+func (t *trust) fulcioPEM(ctx context.Context) ([]byte, error) {
+	if t.fulcio == nil {
+		b, err := fulcioroots.GetPEM(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting Fulcio roots: %w", err)
+		}
+		t.fulcio = b
+	}
+	return t.fulcio, nil
+}
+
+// rekorPEM returns every trusted Rekor public key, in the order of their
+// log IDs.
+func (t *trust) rekorPEM(ctx context.Context) ([]byte, error) {
+	if t.rekor == nil {
+		keys, err := cosign.GetRekorPubs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting Rekor public keys: %w", err)
+		}
+		b, active, err := keysPEM(keys)
+		if err != nil {
+			return nil, err
+		}
+		t.rekor, t.rekorKeys = b, active
+	}
+	return t.rekor, nil
+}
+
+// activeRekorPEM returns the Rekor public key that log entries are signed
+// with now, for formats that take a single key.
+func (t *trust) activeRekorPEM(ctx context.Context) ([]byte, error) {
+	if _, err := t.rekorPEM(ctx); err != nil {
+		return nil, err
+	}
+	switch len(t.rekorKeys) {
+	case 0:
+		return nil, errors.New("none of the Rekor public keys is active")
+	case 1:
+		return []byte(t.rekorKeys[0]), nil
+	default:
+		return nil, fmt.Errorf("%d Rekor public keys are active, set %s to the one to export", len(t.rekorKeys), env.VariableSigstoreRekorPublicKey)
+	}
+}
+
+func (t *trust) ctlogPEM(ctx context.Context) ([]byte, error) {
+	if t.ctlog == nil {
+		keys, err := cosign.GetCTLogPubs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting CT log public keys: %w", err)
+		}
+		b, _, err := keysPEM(keys)
+		if err != nil {
+			return nil, err
+		}
+		t.ctlog = b
+	}
+	return t.ctlog, nil
+}
+
+// keysPEM returns the PEM of all keys, and of each key that is in use.
+func keysPEM(keys *cosign.TrustedTransparencyLogPubKeys) ([]byte, []string, error) {
+	ids := make([]string, 0, len(keys.Keys))
+	for id := range keys.Keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var all bytes.Buffer
+	var active []string
+	for _, id := range ids {
+		k := keys.Keys[id]
+		b, err := cryptoutils.MarshalPublicKeyToPEM(k.PubKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("marshalling public key of log %s: %w", id, err)
+		}
+		all.Write(b)
+		if k.Status == tuf.Active && k.ValidFor.End.IsZero() {
+			active = append(active, string(b))
+		}
+	}
+	return all.Bytes(), active, nil
+}
+
+// publicKeyPEM returns the PEM of the public key of an entry, which may be
+// a path, a KMS URI or a Kubernetes secret.
+func publicKeyPEM(ctx context.Context, keyRef string) ([]byte, error) {
+	pub, err := sigs.PublicKeyFromKeyRef(ctx, keyRef)
+	if err != nil {
+		return nil, fmt.Errorf("loading public key %s: %w", keyRef, err)
+	}
+	k, err := pub.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return cryptoutils.MarshalPublicKeyToPEM(k)
+}
+
+// dockerHub returns repo with the registry of Docker Hub spelled as
+// docker.io, which is how admission controllers normalize image references,
+// rather than as index.docker.io.
+func dockerHub(repo string) string {
+	if strings.HasPrefix(repo, "index.docker.io/") {
+		return "docker.io/" + strings.TrimPrefix(repo, "index.docker.io/")
+	}
+	return repo
+}
@@ -0,0 +1,159 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exportpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+func writeKey(t *testing.T) (string, []byte) {
+	t.Helper()
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return nil, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cosign.pub")
+	if err := os.WriteFile(path, keys.PublicBytes, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, keys.PublicBytes
+}
+
+func TestExportKyverno(t *testing.T) {
+	key, pub := writeKey(t)
+	p := &proxy.Policy{Images: []proxy.PolicyEntry{
+		{Glob: "index.docker.io/library/*", Key: key, IgnoreTlog: true},
+		{Key: key, IgnoreTlog: true},
+	}}
+
+	var out bytes.Buffer
+	o := options.ExportPolicyOptions{Policy: "policy.json", Format: "kyverno", Name: "prod"}
+	if err := Export(context.Background(), &out, p, o); err != nil {
+		t.Fatal(err)
+	}
+	got := &kyvernoPolicy{}
+	if err := yaml.Unmarshal(out.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Kind != "ClusterPolicy" || got.Metadata.Name != "prod" {
+		t.Errorf("got %s %s, want ClusterPolicy prod", got.Kind, got.Metadata.Name)
+	}
+	if len(got.Spec.Rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(got.Spec.Rules))
+	}
+	first, second := got.Spec.Rules[0].VerifyImages[0], got.Spec.Rules[1].VerifyImages[0]
+	want := []string{"docker.io/library/*:*", "docker.io/library/*@*"}
+	if strings.Join(first.ImageReferences, ",") != strings.Join(want, ",") {
+		t.Errorf("first rule image references = %v, want %v", first.ImageReferences, want)
+	}
+	if len(first.SkipImageReferences) != 0 {
+		t.Errorf("first rule skips %v, want nothing", first.SkipImageReferences)
+	}
+	if strings.Join(second.SkipImageReferences, ",") != strings.Join(want, ",") {
+		t.Errorf("second rule skips %v, want %v", second.SkipImageReferences, want)
+	}
+	keys := second.Attestors[0].Entries[0].Keys
+	if keys == nil || keys.PublicKeys != string(pub) || !keys.Rekor.IgnoreTlog {
+		t.Errorf("second rule attestor = %+v, want the public key ignoring the tlog", second.Attestors[0].Entries[0])
+	}
+}
+
+func TestExportOpenShift(t *testing.T) {
+	key, pub := writeKey(t)
+	p := &proxy.Policy{Images: []proxy.PolicyEntry{
+		{Glob: "registry.example.com/team/*", Key: key, IgnoreTlog: true},
+	}}
+
+	var out bytes.Buffer
+	o := options.ExportPolicyOptions{Format: "openshift", Name: "prod"}
+	if err := Export(context.Background(), &out, p, o); err != nil {
+		t.Fatal(err)
+	}
+	got := &openshiftPolicy{}
+	if err := yaml.Unmarshal(out.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Metadata.Name != "prod-0" {
+		t.Errorf("name = %s, want prod-0", got.Metadata.Name)
+	}
+	if len(got.Spec.Scopes) != 1 || got.Spec.Scopes[0] != "registry.example.com/team" {
+		t.Errorf("scopes = %v, want [registry.example.com/team]", got.Spec.Scopes)
+	}
+	root := got.Spec.Policy.RootOfTrust
+	if root.PolicyType != "PublicKey" || root.PublicKey == nil || root.PublicKey.KeyData != base64.StdEncoding.EncodeToString(pub) {
+		t.Errorf("root of trust = %+v, want the public key", root)
+	}
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	p := &proxy.Policy{Images: []proxy.PolicyEntry{{Key: "cosign.pub"}}}
+	if err := Export(context.Background(), &bytes.Buffer{}, p, options.ExportPolicyOptions{Format: "gatekeeper"}); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestExportRejectsMetadataSelectors(t *testing.T) {
+	p := &proxy.Policy{Images: []proxy.PolicyEntry{{Key: "cosign.pub", Labels: map[string]string{"env": "prod"}}}}
+	if err := Export(context.Background(), &bytes.Buffer{}, p, options.ExportPolicyOptions{Format: "kyverno"}); err == nil {
+		t.Error("expected an error for an entry with labels")
+	}
+}
+
+func TestOpenShiftScope(t *testing.T) {
+	tests := []struct {
+		glob    string
+		want    string
+		wantErr bool
+	}{
+		{glob: "registry.example.com/app", want: "registry.example.com/app"},
+		{glob: "registry.example.com/team/*", want: "registry.example.com/team"},
+		{glob: "index.docker.io/library/*", want: "docker.io/library"},
+		{glob: "*.example.com", want: "*.example.com"},
+		{glob: "", wantErr: true},
+		{glob: "registry.example.com/app-*", wantErr: true},
+		{glob: "*.example.com/app", wantErr: true},
+		{glob: "registry.example.com/[ab]pp", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := openshiftScope(tt.glob)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("openshiftScope(%q) error = %v, wantErr %v", tt.glob, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("openshiftScope(%q) = %q, want %q", tt.glob, got, tt.want)
+		}
+	}
+}
+
+func TestKyvernoImageReferencesRejectsClasses(t *testing.T) {
+	if _, err := kyvernoImageReferences("registry.example.com/[ab]pp"); err == nil {
+		t.Error("expected an error for a character class")
+	}
+}
@@ -0,0 +1,204 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exportpolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+// These are the parts of the kyverno.io/v1 ClusterPolicy that verify images,
+// see https://kyverno.io/docs/writing-policies/verify-images/sigstore/.
+type kyvernoPolicy struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   metadata          `yaml:"metadata"`
+	Spec       kyvernoPolicySpec `yaml:"spec"`
+}
+
+type kyvernoPolicySpec struct {
+	ValidationFailureAction string        `yaml:"validationFailureAction"`
+	Background              bool          `yaml:"background"`
+	WebhookTimeoutSeconds   int           `yaml:"webhookTimeoutSeconds"`
+	Rules                   []kyvernoRule `yaml:"rules"`
+}
+
+type kyvernoRule struct {
+	Name         string               `yaml:"name"`
+	Match        kyvernoMatch         `yaml:"match"`
+	VerifyImages []kyvernoVerifyImage `yaml:"verifyImages"`
+}
+
+type kyvernoMatch struct {
+	Any []kyvernoResourceFilter `yaml:"any"`
+}
+
+type kyvernoResourceFilter struct {
+	Resources struct {
+		Kinds []string `yaml:"kinds"`
+	} `yaml:"resources"`
+}
+
+type kyvernoVerifyImage struct {
+	ImageReferences     []string             `yaml:"imageReferences"`
+	SkipImageReferences []string             `yaml:"skipImageReferences,omitempty"`
+	Attestors           []kyvernoAttestorSet `yaml:"attestors"`
+}
+
+type kyvernoAttestorSet struct {
+	Entries []kyvernoAttestor `yaml:"entries"`
+}
+
+type kyvernoAttestor struct {
+	Keys    *kyvernoKeys    `yaml:"keys,omitempty"`
+	Keyless *kyvernoKeyless `yaml:"keyless,omitempty"`
+}
+
+type kyvernoKeys struct {
+	PublicKeys string       `yaml:"publicKeys"`
+	Rekor      kyvernoRekor `yaml:"rekor"`
+}
+
+type kyvernoKeyless struct {
+	Subject       string       `yaml:"subject,omitempty"`
+	SubjectRegExp string       `yaml:"subjectRegExp,omitempty"`
+	Issuer        string       `yaml:"issuer,omitempty"`
+	IssuerRegExp  string       `yaml:"issuerRegExp,omitempty"`
+	Roots         string       `yaml:"roots"`
+	Rekor         kyvernoRekor `yaml:"rekor"`
+	CTLog         kyvernoCTLog `yaml:"ctlog"`
+}
+
+type kyvernoRekor struct {
+	URL        string `yaml:"url,omitempty"`
+	PubKey     string `yaml:"pubkey,omitempty"`
+	IgnoreTlog bool   `yaml:"ignoreTlog,omitempty"`
+}
+
+type kyvernoCTLog struct {
+	PubKey    string `yaml:"pubkey,omitempty"`
+	IgnoreSCT bool   `yaml:"ignoreSCT,omitempty"`
+}
+
+// kyverno exports p as a ClusterPolicy with a rule for each entry. Kyverno
+// verifies an image with every rule that matches it, so each rule skips the
+// images of the entries before it, as the proxy uses the first entry that
+// matches.
+func kyverno(ctx context.Context, p *proxy.Policy, o options.ExportPolicyOptions, t *trust) (*kyvernoPolicy, error) {
+	pods := kyvernoResourceFilter{}
+	pods.Resources.Kinds = []string{"Pod"}
+
+	policy := &kyvernoPolicy{
+		APIVersion: "kyverno.io/v1",
+		Kind:       "ClusterPolicy",
+		Metadata: metadata{
+			Name:        o.Name,
+			Annotations: map[string]string{"policies.kyverno.io/description": "Exported from " + o.Policy + " by cosign export-policy."},
+		},
+		Spec: kyvernoPolicySpec{
+			ValidationFailureAction: "Enforce",
+			// Images are only verified on admission.
+			Background:            false,
+			WebhookTimeoutSeconds: 30,
+		},
+	}
+
+	var previous []string
+	for i, e := range p.Images {
+		refs, err := kyvernoImageReferences(e.Glob)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		attestor, err := kyvernoEntryAttestor(ctx, e, o, t)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		policy.Spec.Rules = append(policy.Spec.Rules, kyvernoRule{
+			Name:  fmt.Sprintf("verify-%d", i),
+			Match: kyvernoMatch{Any: []kyvernoResourceFilter{pods}},
+			VerifyImages: []kyvernoVerifyImage{{
+				ImageReferences:     refs,
+				SkipImageReferences: append([]string(nil), previous...),
+				Attestors:           []kyvernoAttestorSet{{Entries: []kyvernoAttestor{attestor}}},
+			}},
+		})
+		previous = append(previous, refs...)
+	}
+	if last := p.Images[len(p.Images)-1]; last.Glob != "" {
+		ui.Warnf(ctx, "Kyverno admits the images that match no entry, which the proxy doesn't serve; end the policy with an entry without a glob to verify them")
+	}
+	return policy, nil
+}
+
+// kyvernoImageReferences returns the Kyverno image references that match the
+// images of the repositories matching glob. Kyverno's * also matches /, so
+// they may match more nested repositories than glob.
+func kyvernoImageReferences(glob string) ([]string, error) {
+	if glob == "" {
+		return []string{"*"}, nil
+	}
+	if strings.ContainsAny(glob, `[\`) {
+		return nil, fmt.Errorf("glob %q has a character class or an escape, which Kyverno image references don't support", glob)
+	}
+	repo := dockerHub(glob)
+	return []string{repo + ":*", repo + "@*"}, nil
+}
+
+func kyvernoEntryAttestor(ctx context.Context, e proxy.PolicyEntry, o options.ExportPolicyOptions, t *trust) (kyvernoAttestor, error) {
+	rekor := kyvernoRekor{IgnoreTlog: e.IgnoreTlog}
+	if !e.IgnoreTlog {
+		pub, err := t.rekorPEM(ctx)
+		if err != nil {
+			return kyvernoAttestor{}, err
+		}
+		rekor.URL, rekor.PubKey = o.Rekor.URL, string(pub)
+	}
+
+	if e.Key != "" {
+		pub, err := publicKeyPEM(ctx, e.Key)
+		if err != nil {
+			return kyvernoAttestor{}, err
+		}
+		return kyvernoAttestor{Keys: &kyvernoKeys{PublicKeys: string(pub), Rekor: rekor}}, nil
+	}
+
+	roots, err := t.fulcioPEM(ctx)
+	if err != nil {
+		return kyvernoAttestor{}, err
+	}
+	ctlog := kyvernoCTLog{IgnoreSCT: e.IgnoreSCT}
+	if !e.IgnoreSCT {
+		pub, err := t.ctlogPEM(ctx)
+		if err != nil {
+			return kyvernoAttestor{}, err
+		}
+		ctlog.PubKey = string(pub)
+	}
+	return kyvernoAttestor{Keyless: &kyvernoKeyless{
+		Subject:       e.CertificateIdentity,
+		SubjectRegExp: e.CertificateIdentityRegexp,
+		Issuer:        e.CertificateOIDCIssuer,
+		IssuerRegExp:  e.CertificateOIDCIssuerRegexp,
+		Roots:         string(roots),
+		Rekor:         rekor,
+		CTLog:         ctlog,
+	}}, nil
+}
@@ -0,0 +1,167 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exportpolicy
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+)
+
+// These are the parts of the config.openshift.io/v1 ClusterImagePolicy, see
+// https://docs.openshift.com/container-platform/latest/nodes/nodes-sigstore-using.html.
+type openshiftPolicy struct {
+	APIVersion string              `yaml:"apiVersion"`
+	Kind       string              `yaml:"kind"`
+	Metadata   metadata            `yaml:"metadata"`
+	Spec       openshiftPolicySpec `yaml:"spec"`
+}
+
+type openshiftPolicySpec struct {
+	Scopes []string             `yaml:"scopes"`
+	Policy openshiftImagePolicy `yaml:"policy"`
+}
+
+type openshiftImagePolicy struct {
+	RootOfTrust    openshiftRootOfTrust    `yaml:"rootOfTrust"`
+	SignedIdentity openshiftSignedIdentity `yaml:"signedIdentity"`
+}
+
+type openshiftRootOfTrust struct {
+	PolicyType        string                    `yaml:"policyType"`
+	PublicKey         *openshiftPublicKey       `yaml:"publicKey,omitempty"`
+	FulcioCAWithRekor *openshiftFulcioWithRekor `yaml:"fulcioCAWithRekor,omitempty"`
+}
+
+type openshiftPublicKey struct {
+	KeyData      string `yaml:"keyData"`
+	RekorKeyData string `yaml:"rekorKeyData,omitempty"`
+}
+
+type openshiftFulcioWithRekor struct {
+	FulcioCAData  string                 `yaml:"fulcioCAData"`
+	RekorKeyData  string                 `yaml:"rekorKeyData"`
+	FulcioSubject openshiftFulcioSubject `yaml:"fulcioSubject"`
+}
+
+type openshiftFulcioSubject struct {
+	OIDCIssuer  string `yaml:"oidcIssuer"`
+	SignedEmail string `yaml:"signedEmail"`
+}
+
+type openshiftSignedIdentity struct {
+	MatchPolicy string `yaml:"matchPolicy"`
+}
+
+// openshift exports each entry of p as a ClusterImagePolicy. CRI-O uses the
+// most specific scope that matches an image, which is the first matching
+// entry of policies that list the more specific globs first.
+func openshift(ctx context.Context, p *proxy.Policy, o options.ExportPolicyOptions, t *trust) ([]any, error) {
+	policies := make([]any, 0, len(p.Images))
+	for i, e := range p.Images {
+		scope, err := openshiftScope(e.Glob)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		root, err := openshiftRoot(ctx, e, t)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		policies = append(policies, &openshiftPolicy{
+			APIVersion: "config.openshift.io/v1",
+			Kind:       "ClusterImagePolicy",
+			Metadata:   metadata{Name: fmt.Sprintf("%s-%d", o.Name, i)},
+			Spec: openshiftPolicySpec{
+				Scopes: []string{scope},
+				Policy: openshiftImagePolicy{
+					RootOfTrust:    root,
+					SignedIdentity: openshiftSignedIdentity{MatchPolicy: "MatchRepoDigestOrExact"},
+				},
+			},
+		})
+	}
+	return policies, nil
+}
+
+// openshiftScope returns the ClusterImagePolicy scope of the repositories
+// matching glob. Scopes match a repository and the ones nested in it, and
+// support no wildcards other than *. for subdomains of a registry.
+func openshiftScope(glob string) (string, error) {
+	if glob == "" {
+		return "", errors.New("ClusterImagePolicy needs a scope, an entry without a glob can't be exported")
+	}
+	scope := strings.TrimSuffix(glob, "/*")
+	if strings.ContainsAny(scope, `*?[\`) {
+		host, rest, _ := strings.Cut(scope, "/")
+		if rest != "" || !strings.HasPrefix(host, "*.") || strings.ContainsAny(host[2:], `*?[\`) {
+			return "", fmt.Errorf("glob %q can't be a ClusterImagePolicy scope, which only supports a trailing /* or a *. registry subdomain", glob)
+		}
+	}
+	return dockerHub(scope), nil
+}
+
+func openshiftRoot(ctx context.Context, e proxy.PolicyEntry, t *trust) (openshiftRootOfTrust, error) {
+	if e.Key != "" {
+		pub, err := publicKeyPEM(ctx, e.Key)
+		if err != nil {
+			return openshiftRootOfTrust{}, err
+		}
+		key := &openshiftPublicKey{KeyData: base64.StdEncoding.EncodeToString(pub)}
+		if !e.IgnoreTlog {
+			rekor, err := t.activeRekorPEM(ctx)
+			if err != nil {
+				return openshiftRootOfTrust{}, err
+			}
+			key.RekorKeyData = base64.StdEncoding.EncodeToString(rekor)
+		}
+		return openshiftRootOfTrust{PolicyType: "PublicKey", PublicKey: key}, nil
+	}
+
+	switch {
+	case e.CertificateIdentityRegexp != "" || e.CertificateOIDCIssuerRegexp != "":
+		return openshiftRootOfTrust{}, errors.New("ClusterImagePolicy doesn't support certificate identity or issuer regular expressions")
+	case !strings.Contains(e.CertificateIdentity, "@") || strings.Contains(e.CertificateIdentity, "/"):
+		return openshiftRootOfTrust{}, fmt.Errorf("ClusterImagePolicy only supports email certificate identities, not %q", e.CertificateIdentity)
+	case e.CertificateOIDCIssuer == "":
+		return openshiftRootOfTrust{}, errors.New("ClusterImagePolicy needs a certificate OIDC issuer")
+	case e.IgnoreTlog:
+		return openshiftRootOfTrust{}, errors.New("ClusterImagePolicy always verifies certificates against Rekor, ignoreTlog can't be exported")
+	}
+	roots, err := t.fulcioPEM(ctx)
+	if err != nil {
+		return openshiftRootOfTrust{}, err
+	}
+	rekor, err := t.activeRekorPEM(ctx)
+	if err != nil {
+		return openshiftRootOfTrust{}, err
+	}
+	return openshiftRootOfTrust{
+		PolicyType: "FulcioCAWithRekor",
+		FulcioCAWithRekor: &openshiftFulcioWithRekor{
+			FulcioCAData: base64.StdEncoding.EncodeToString(roots),
+			RekorKeyData: base64.StdEncoding.EncodeToString(rekor),
+			FulcioSubject: openshiftFulcioSubject{
+				OIDCIssuer:  e.CertificateOIDCIssuer,
+				SignedEmail: e.CertificateIdentity,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,221 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+)
+
+func Find() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Provides utilities for finding signed artifacts",
+	}
+
+	cmd.AddCommand(findRepo())
+
+	return cmd
+}
+
+func findRepo() *cobra.Command {
+	o := &options.FindRepoOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "repo",
+		Short: "Find the artifacts in a repository signed by a given signer",
+		Long: `Scan the signatures and attestations stored in a repository and list the
+artifacts they were made for, optionally filtered by signer and signing time.
+
+The signatures are matched by their keys and certificates only. They are not
+verified, so use cosign verify to establish trust in the artifacts found.`,
+		Example: `  cosign find repo --identity foo@example.com --since 2024-01-01 <REPOSITORY>
+
+  # list the artifacts signed with a key in JSON
+  cosign find repo --key cosign.pub --output json <REPOSITORY>`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.Output != "text" && o.Output != "json" {
+				return fmt.Errorf("unsupported output format %q, expected text or json", o.Output)
+			}
+			found, err := FindRepoCmd(cmd.Context(), *o, args[0])
+			if err != nil {
+				return err
+			}
+			return printFound(found, o.Output)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// FoundSignature is a signature or attestation found by cosign find repo.
+type FoundSignature struct {
+	Image       string     `json:"image"`
+	Attestation bool       `json:"attestation,omitempty"`
+	Subject     string     `json:"subject,omitempty"`
+	Issuer      string     `json:"issuer,omitempty"`
+	SignedAt    *time.Time `json:"signedAt,omitempty"`
+}
+
+// FindRepoCmd returns the signatures and attestations in repo that match the
+// signer and signing time criteria in o.
+func FindRepoCmd(ctx context.Context, o options.FindRepoOptions, repo string) ([]FoundSignature, error) {
+	since, until, err := o.TimeRange()
+	if err != nil {
+		return nil, err
+	}
+	var m *SignerMatcher
+	if o.Key != "" || o.Identity != "" || o.OIDCIssuer != "" {
+		if m, err = NewSignerMatcher(ctx, o.Key, o.Identity, o.OIDCIssuer); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := name.NewRepository(repo, o.Registry.NameOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	ociremoteOpts, err := o.Registry.ClientOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list, err := remote.List(r, o.Registry.GetRegistryClientOpts(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags in %s: %w", r, err)
+	}
+	sort.Strings(list)
+
+	var found []FoundSignature
+	for _, t := range list {
+		h, attestation, ok := parseAttachmentTag(t, o.Registry.RefOpts.TagPrefix)
+		if !ok {
+			continue
+		}
+		tag := r.Tag(t)
+		sigList, err := ociremote.Signatures(tag, ociremoteOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", tag, err)
+		}
+		all, err := sigList.Get()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", tag, err)
+		}
+		for _, sig := range all {
+			if m != nil {
+				match, err := m.Match(ctx, sig, attestation)
+				if err != nil {
+					return nil, err
+				}
+				if !match {
+					continue
+				}
+			}
+			signedAt, err := signingTime(sig)
+			if err != nil {
+				return nil, err
+			}
+			if !since.IsZero() || !until.IsZero() {
+				if signedAt == nil || signedAt.Before(since) || (!until.IsZero() && !signedAt.Before(until)) {
+					continue
+				}
+			}
+
+			f := FoundSignature{
+				Image:       r.Digest(h.String()).String(),
+				Attestation: attestation,
+				SignedAt:    signedAt,
+			}
+			if cert, err := sig.Cert(); err == nil && cert != nil {
+				ce := cosign.CertExtensions{Cert: cert}
+				f.Subject = sigs.CertSubject(cert)
+				f.Issuer = ce.GetIssuer()
+			}
+			found = append(found, f)
+		}
+	}
+	return found, nil
+}
+
+// signingTime returns the time sig was integrated in the transparency log,
+// or else the start of the validity of its certificate. It returns nil if
+// sig has neither.
+func signingTime(sig oci.Signature) (*time.Time, error) {
+	bundle, err := sig.Bundle()
+	if err != nil {
+		return nil, err
+	}
+	if bundle != nil {
+		t := time.Unix(bundle.Payload.IntegratedTime, 0).UTC()
+		return &t, nil
+	}
+	cert, err := sig.Cert()
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		t := cert.NotBefore.UTC()
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func printFound(found []FoundSignature, output string) error {
+	switch output {
+	case "json":
+		if found == nil {
+			found = []FoundSignature{}
+		}
+		b, err := json.Marshal(found)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	default:
+		for _, f := range found {
+			kind := "signature"
+			if f.Attestation {
+				kind = "attestation"
+			}
+			signer, signedAt := "-", "-"
+			if f.Subject != "" {
+				signer = f.Subject
+			}
+			if f.SignedAt != nil {
+				signedAt = f.SignedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%s\t%s\t%s\t%s\n", f.Image, kind, signer, signedAt)
+		}
+		fmt.Fprintf(os.Stderr, "Found %d matching signature(s) and attestation(s)\n", len(found))
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+func TestFindRepoCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	keySigner, keyPath := newTestKey(t)
+
+	// Two images signed by user@example.com, one of them also with a key.
+	var keylessImage string
+	for i, signers := range []int{1, 2} {
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := name.NewDigest(host + "/app@" + h.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(ref, img); err != nil {
+			t.Fatal(err)
+		}
+		payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + h.String() + `"}}}`)
+
+		leafCert, _, _ := test.GenerateLeafCert("user@example.com", "https://issuer.example.com", rootCert, rootKey)
+		leafPEM, _ := cryptoutils.MarshalCertificateToPEM(leafCert)
+		keyless, err := static.NewSignature(payload, "c2lnbmF0dXJl", static.WithCertChain(leafPEM, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		signatures := []oci.Signature{keyless}
+		if signers == 2 {
+			signatures = append(signatures, signPayload(t, keySigner, payload))
+		}
+		sigs, err := mutate.AppendSignatures(empty.Signatures(), signatures...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigTag, err := ociremote.SignatureTag(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(sigTag, sigs); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			keylessImage = ref.String()
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		opts options.FindRepoOptions
+		want int
+	}{
+		{name: "all", want: 3},
+		{name: "identity", opts: options.FindRepoOptions{Identity: "user@example.com"}, want: 2},
+		{name: "other identity", opts: options.FindRepoOptions{Identity: "other@example.com"}, want: 0},
+		{name: "key", opts: options.FindRepoOptions{Key: keyPath}, want: 1},
+		{name: "since", opts: options.FindRepoOptions{Identity: "user@example.com", Since: "2020-01-01"}, want: 2},
+		{name: "until", opts: options.FindRepoOptions{Identity: "user@example.com", Until: "2020-01-01"}, want: 0},
+		// Key signatures without a bundle have no signing time.
+		{name: "key since", opts: options.FindRepoOptions{Key: keyPath, Since: "2020-01-01T00:00:00Z"}, want: 0},
+	} {
+		found, err := FindRepoCmd(ctx, tt.opts, host+"/app")
+		if err != nil {
+			t.Fatalf("%s: FindRepoCmd() = %v", tt.name, err)
+		}
+		if len(found) != tt.want {
+			t.Errorf("%s: found %d signatures, want %d: %+v", tt.name, len(found), tt.want, found)
+		}
+	}
+
+	found, err := FindRepoCmd(ctx, options.FindRepoOptions{Identity: "user@example.com"}, host+"/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var images []string
+	for _, f := range found {
+		if f.Subject != "user@example.com" || f.Issuer != "https://issuer.example.com" || f.SignedAt == nil {
+			t.Errorf("unexpected signer details %+v", f)
+		}
+		images = append(images, f.Image)
+	}
+	if !strings.Contains(strings.Join(images, " "), keylessImage) {
+		t.Errorf("found %v, want %s among them", images, keylessImage)
+	}
+
+	if _, err := FindRepoCmd(ctx, options.FindRepoOptions{Since: "yesterday"}, host+"/app"); err == nil {
+		t.Error("expected an error for an invalid --since")
+	}
+}
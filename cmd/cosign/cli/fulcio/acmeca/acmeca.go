@@ -0,0 +1,750 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package acmeca requests short-lived code signing certificates from an
+// ACME CA (RFC 8555) instead of Fulcio, for organizations that issue their
+// internal certificates with ACME.
+//
+// The CA is named with acme:<directory URL>. The certificate is ordered for
+// the configured identifiers, in the profile of the CA if one is set, and
+// the authorizations of the order are either already valid, e.g. because
+// the CA authorizes the account by its external account binding, or are
+// answered with http-01 challenges.
+package acmeca
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"go.step.sm/crypto/jose"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/localca"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+// Scheme prefixes the directory URL of ACME CAs, e.g. in --fulcio-url.
+const Scheme = "acme:"
+
+const (
+	// maxResponseSize bounds the responses read from ACME CAs.
+	maxResponseSize = 1 << 20
+	// maxPolls bounds how often an authorization or order is polled for
+	// its status to change.
+	maxPolls = 60
+	// maxBadNonceRetries bounds how often a request rejected for its nonce
+	// is retried with a fresh one.
+	maxBadNonceRetries = 3
+
+	joseContentType  = "application/jose+json"
+	chainContentType = "application/pem-certificate-chain"
+	problemBadNonce  = "urn:ietf:params:acme:error:badNonce"
+	challengeHTTP01  = "http-01"
+	http01PathPrefix = "/.well-known/acme-challenge/"
+)
+
+// pollInterval is how long to wait between polls of an authorization or
+// order when the CA doesn't ask for a time with Retry-After.
+var pollInterval = time.Second
+
+// IsACME reports whether the address of a CA, e.g. of --fulcio-url, names an
+// ACME CA.
+func IsACME(address string) bool {
+	return strings.HasPrefix(address, Scheme)
+}
+
+// Identifier is an identifier of an order, e.g. {"dns", "build.example.com"}.
+type Identifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ParseIdentifier parses an identifier of --acme-identifier, as dns:<name>,
+// ip:<address> or email:<address>, or a DNS name or IP address.
+func ParseIdentifier(s string) (Identifier, error) {
+	typ, value, ok := strings.Cut(s, ":")
+	switch {
+	case net.ParseIP(s) != nil:
+		typ, value = "ip", s
+	case !ok:
+		typ, value = "dns", s
+	}
+	switch typ {
+	case "dns":
+	case "ip":
+		if net.ParseIP(value) == nil {
+			return Identifier{}, fmt.Errorf("%s isn't an IP address", value)
+		}
+	case "email":
+		if !strings.Contains(value, "@") {
+			return Identifier{}, fmt.Errorf("%s isn't an email address", value)
+		}
+	default:
+		return Identifier{}, fmt.Errorf("unsupported identifier type %q of %s, want dns, ip or email", typ, s)
+	}
+	if value == "" {
+		return Identifier{}, fmt.Errorf("identifier %s has no value", s)
+	}
+	return Identifier{Type: typ, Value: value}, nil
+}
+
+// Client requests certificates from an ACME CA.
+type Client struct {
+	directoryURL string
+	opts         options.ACMEOptions
+	identifiers  []Identifier
+	key          crypto.Signer
+	alg          jose.SignatureAlgorithm
+	http         *http.Client
+
+	dir directory
+	// kid is the URL of the account, once registered.
+	kid    string
+	nonces []string
+
+	// http01 serves the key authorizations of the http-01 challenges, once
+	// started.
+	http01     *http.Server
+	http01Addr string
+	mu         sync.Mutex
+	keyAuths   map[string]string
+}
+
+// directory is the directory of the resources of an ACME CA.
+type directory struct {
+	NewNonce   string `json:"newNonce"`
+	NewAccount string `json:"newAccount"`
+	NewOrder   string `json:"newOrder"`
+	Meta       struct {
+		// Profiles are the names and descriptions of the profiles of the
+		// CA, see https://datatracker.ietf.org/doc/draft-aaron-acme-profiles/.
+		Profiles                map[string]string `json:"profiles"`
+		ExternalAccountRequired bool              `json:"externalAccountRequired"`
+	} `json:"meta"`
+}
+
+type order struct {
+	Status         string      `json:"status"`
+	Authorizations []string    `json:"authorizations"`
+	Finalize       string      `json:"finalize"`
+	Certificate    string      `json:"certificate"`
+	Error          *problemDoc `json:"error"`
+}
+
+type authorization struct {
+	Status     string      `json:"status"`
+	Identifier Identifier  `json:"identifier"`
+	Challenges []challenge `json:"challenges"`
+}
+
+type challenge struct {
+	Type   string      `json:"type"`
+	URL    string      `json:"url"`
+	Token  string      `json:"token"`
+	Status string      `json:"status"`
+	Error  *problemDoc `json:"error"`
+}
+
+// problemDoc is an error of an ACME CA (RFC 7807).
+type problemDoc struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+func (p *problemDoc) Error() string {
+	if p.Detail == "" {
+		return p.Type
+	}
+	return fmt.Sprintf("%s (%s)", p.Detail, p.Type)
+}
+
+// New returns the client of the ACME CA at address, acme:<directory URL>,
+// that orders certificates as configured by o.
+func New(address string, o options.ACMEOptions) (*Client, error) {
+	if !IsACME(address) || strings.TrimPrefix(address, Scheme) == "" {
+		return nil, fmt.Errorf("%s isn't an ACME CA, acme:<directory URL>", address)
+	}
+	if len(o.Identifiers) == 0 {
+		return nil, errors.New("--acme-identifier is required to order a certificate from an ACME CA")
+	}
+	if (o.EABKeyID == "") != (o.EABHMACKey == "") {
+		return nil, errors.New("--acme-eab-kid and --acme-eab-hmac-key must be set together")
+	}
+	c := &Client{
+		directoryURL: strings.TrimPrefix(address, Scheme),
+		opts:         o,
+		http:         &http.Client{Timeout: 30 * time.Second},
+		keyAuths:     map[string]string{},
+	}
+	for _, s := range o.Identifiers {
+		id, err := ParseIdentifier(s)
+		if err != nil {
+			return nil, fmt.Errorf("--acme-identifier: %w", err)
+		}
+		c.identifiers = append(c.identifiers, id)
+	}
+	var err error
+	if o.AccountKey != "" {
+		c.key, err = loadAccountKey(o.AccountKey)
+	} else {
+		c.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.alg, err = signatureAlgorithm(c.key); err != nil {
+		return nil, fmt.Errorf("--acme-account-key: %w", err)
+	}
+	return c, nil
+}
+
+func loadAccountKey(path string) (crypto.Signer, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading --acme-account-key: %w", err)
+	}
+	priv, err := cryptoutils.UnmarshalPEMToPrivateKey(b, cryptoutils.SkipPassword)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --acme-account-key: %w", err)
+	}
+	s, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("--acme-account-key %s isn't a signing key", path)
+	}
+	return s, nil
+}
+
+// signatureAlgorithm returns the JWS algorithm of the account key.
+func signatureAlgorithm(key crypto.Signer) (jose.SignatureAlgorithm, error) {
+	switch k := key.Public().(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jose.ES256, nil
+		case elliptic.P384():
+			return jose.ES384, nil
+		}
+		return "", fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+	case *rsa.PublicKey:
+		return jose.RS256, nil
+	case ed25519.PublicKey:
+		return jose.EdDSA, nil
+	}
+	return "", fmt.Errorf("unsupported key type %T", key.Public())
+}
+
+// SigningCert returns the PEM-encoded certificate of the key of sv, issued
+// by the CA for the identifiers of c, and the PEM-encoded chain of the CA.
+func (c *Client) SigningCert(ctx context.Context, sv signature.SignerVerifier) (cert, chain []byte, err error) {
+	defer c.stopHTTP01()
+	if err := c.get(ctx, c.directoryURL, &c.dir); err != nil {
+		return nil, nil, fmt.Errorf("fetching the directory of ACME CA %s: %w", c.directoryURL, err)
+	}
+	if err := c.checkProfile(); err != nil {
+		return nil, nil, err
+	}
+	if err := c.register(ctx); err != nil {
+		return nil, nil, fmt.Errorf("registering the ACME account: %w", err)
+	}
+
+	req := struct {
+		Identifiers []Identifier `json:"identifiers"`
+		Profile     string       `json:"profile,omitempty"`
+	}{c.identifiers, c.opts.Profile}
+	var o order
+	resp, err := c.post(ctx, c.dir.NewOrder, req, &o)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ordering the certificate: %w", err)
+	}
+	orderURL := resp.Header.Get("Location")
+	for _, u := range o.Authorizations {
+		if err := c.authorize(ctx, u); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := c.poll(ctx, orderURL, &o, func() (bool, error) {
+		if o.Status == "pending" {
+			return false, nil
+		}
+		return true, orderError(o, "ready", "valid")
+	}); err != nil {
+		return nil, nil, fmt.Errorf("waiting for the order to be ready: %w", err)
+	}
+
+	if o.Status == "ready" {
+		csr, err := c.csr(ctx, sv)
+		if err != nil {
+			return nil, nil, fmt.Errorf("creating certificate signing request: %w", err)
+		}
+		if _, err := c.post(ctx, o.Finalize, struct {
+			CSR string `json:"csr"`
+		}{base64.RawURLEncoding.EncodeToString(csr)}, &o); err != nil {
+			return nil, nil, fmt.Errorf("finalizing the order: %w", err)
+		}
+		if err := c.poll(ctx, orderURL, &o, func() (bool, error) {
+			if o.Status == "processing" || o.Status == "ready" {
+				return false, nil
+			}
+			return true, orderError(o, "valid")
+		}); err != nil {
+			return nil, nil, fmt.Errorf("waiting for the certificate to be issued: %w", err)
+		}
+	}
+	if o.Certificate == "" {
+		return nil, nil, fmt.Errorf("the order of ACME CA %s is valid without a certificate", c.directoryURL)
+	}
+
+	b, err := c.postAsGet(ctx, o.Certificate, chainContentType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("downloading the certificate: %w", err)
+	}
+	return c.splitChain(b, sv)
+}
+
+// checkProfile checks that the CA has the profile of c, if it lists its
+// profiles.
+func (c *Client) checkProfile() error {
+	if c.opts.Profile == "" || len(c.dir.Meta.Profiles) == 0 {
+		return nil
+	}
+	if _, ok := c.dir.Meta.Profiles[c.opts.Profile]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(c.dir.Meta.Profiles))
+	for name := range c.dir.Meta.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("--acme-profile %s isn't a profile of ACME CA %s, one of %s", c.opts.Profile, c.directoryURL, strings.Join(names, ", "))
+}
+
+// register registers the account of the key of c, or finds it if the CA
+// already has it.
+func (c *Client) register(ctx context.Context) error {
+	if c.dir.Meta.ExternalAccountRequired && c.opts.EABKeyID == "" {
+		return fmt.Errorf("ACME CA %s requires an external account binding, set --acme-eab-kid and --acme-eab-hmac-key", c.directoryURL)
+	}
+	req := struct {
+		TermsOfServiceAgreed   bool            `json:"termsOfServiceAgreed"`
+		ExternalAccountBinding json.RawMessage `json:"externalAccountBinding,omitempty"`
+	}{TermsOfServiceAgreed: true}
+	if c.opts.EABKeyID != "" {
+		eab, err := c.externalAccountBinding()
+		if err != nil {
+			return err
+		}
+		req.ExternalAccountBinding = eab
+	}
+	resp, err := c.post(ctx, c.dir.NewAccount, req, nil)
+	if err != nil {
+		return err
+	}
+	if c.kid = resp.Header.Get("Location"); c.kid == "" {
+		return errors.New("the ACME CA responded without the URL of the account")
+	}
+	return nil
+}
+
+// externalAccountBinding returns the JWS binding the account key of c to the
+// external account of --acme-eab-kid (RFC 8555, section 7.3.4).
+func (c *Client) externalAccountBinding() (json.RawMessage, error) {
+	hmacKey, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(c.opts.EABHMACKey, "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding --acme-eab-hmac-key: %w", err)
+	}
+	jwk, err := json.Marshal(jose.JSONWebKey{Key: c.key.Public()})
+	if err != nil {
+		return nil, err
+	}
+	s, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: hmacKey},
+		(&jose.SignerOptions{}).WithHeader("kid", c.opts.EABKeyID).WithHeader("url", c.dir.NewAccount))
+	if err != nil {
+		return nil, err
+	}
+	jws, err := s.Sign(jwk)
+	if err != nil {
+		return nil, err
+	}
+	return flatten(jws)
+}
+
+// authorize waits for the authorization at url to be valid, answering its
+// http-01 challenge if it is pending.
+func (c *Client) authorize(ctx context.Context, url string) error {
+	var a authorization
+	if _, err := c.post(ctx, url, nil, &a); err != nil {
+		return fmt.Errorf("fetching authorization: %w", err)
+	}
+	id := a.Identifier.Type + ":" + a.Identifier.Value
+	switch a.Status {
+	case "valid":
+		return nil
+	case "pending":
+	default:
+		return fmt.Errorf("the authorization of %s is %s", id, a.Status)
+	}
+
+	var types []string
+	var ch *challenge
+	for i := range a.Challenges {
+		types = append(types, a.Challenges[i].Type)
+		if a.Challenges[i].Type == challengeHTTP01 {
+			ch = &a.Challenges[i]
+		}
+	}
+	if ch == nil || c.opts.HTTP01Listen == "" {
+		return fmt.Errorf("ACME CA %s hasn't authorized the account for %s, and its %s challenges aren't answered without --acme-http01-address",
+			c.directoryURL, id, strings.Join(types, ", "))
+	}
+	if err := c.serveHTTP01(ch.Token); err != nil {
+		return err
+	}
+	if _, err := c.post(ctx, ch.URL, struct{}{}, nil); err != nil {
+		return fmt.Errorf("answering the %s challenge of %s: %w", ch.Type, id, err)
+	}
+	return c.poll(ctx, url, &a, func() (bool, error) {
+		switch a.Status {
+		case "pending":
+			return false, nil
+		case "valid":
+			return true, nil
+		}
+		for _, ch := range a.Challenges {
+			if ch.Error != nil {
+				return true, fmt.Errorf("the authorization of %s is %s: %w", id, a.Status, ch.Error)
+			}
+		}
+		return true, fmt.Errorf("the authorization of %s is %s", id, a.Status)
+	})
+}
+
+// serveHTTP01 serves the key authorization of the http-01 challenge of
+// token, starting the server of --acme-http01-address if needed.
+func (c *Client) serveHTTP01(token string) error {
+	thumbprint, err := (&jose.JSONWebKey{Key: c.key.Public()}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.keyAuths[token] = token + "." + base64.RawURLEncoding.EncodeToString(thumbprint)
+	c.mu.Unlock()
+	if c.http01 != nil {
+		return nil
+	}
+
+	l, err := net.Listen("tcp", c.opts.HTTP01Listen)
+	if err != nil {
+		return fmt.Errorf("--acme-http01-address: %w", err)
+	}
+	c.http01Addr = l.Addr().String()
+	c.http01 = &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.mu.Lock()
+			keyAuth, ok := c.keyAuths[strings.TrimPrefix(r.URL.Path, http01PathPrefix)]
+			c.mu.Unlock()
+			if !ok || !strings.HasPrefix(r.URL.Path, http01PathPrefix) {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = io.WriteString(w, keyAuth)
+		}),
+	}
+	go func() { _ = c.http01.Serve(l) }()
+	return nil
+}
+
+func (c *Client) stopHTTP01() {
+	if c.http01 != nil {
+		_ = c.http01.Close()
+	}
+}
+
+// csr returns the DER-encoded CSR of the key of sv for the identifiers of c.
+func (c *Client) csr(ctx context.Context, sv signature.SignerVerifier) ([]byte, error) {
+	tmpl := &x509.CertificateRequest{}
+	for _, id := range c.identifiers {
+		switch id.Type {
+		case "dns":
+			tmpl.DNSNames = append(tmpl.DNSNames, id.Value)
+		case "ip":
+			tmpl.IPAddresses = append(tmpl.IPAddresses, net.ParseIP(id.Value))
+		case "email":
+			tmpl.EmailAddresses = append(tmpl.EmailAddresses, id.Value)
+		}
+	}
+	b, err := localca.NewCertificateSigningRequest(ctx, sv, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	return block.Bytes, nil
+}
+
+// splitChain returns the certificate of the chain, which must certify the
+// key of sv, and the rest of the chain.
+func (c *Client) splitChain(chain []byte, sv signature.SignerVerifier) ([]byte, []byte, error) {
+	block, rest := pem.Decode(chain)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, nil, fmt.Errorf("ACME CA %s responded without a certificate", c.directoryURL)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing the certificate of ACME CA %s: %w", c.directoryURL, err)
+	}
+	pub, err := sv.PublicKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	if k, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(pub) {
+		return nil, nil, fmt.Errorf("the certificate of ACME CA %s doesn't certify the signing key", c.directoryURL)
+	}
+	return pem.EncodeToMemory(block), bytes.TrimLeft(rest, "\n"), nil
+}
+
+// orderError returns the error of an order whose status isn't one of want.
+func orderError(o order, want ...string) error {
+	for _, s := range want {
+		if o.Status == s {
+			return nil
+		}
+	}
+	if o.Error != nil {
+		return fmt.Errorf("the order is %s: %w", o.Status, o.Error)
+	}
+	return fmt.Errorf("the order is %s", o.Status)
+}
+
+// poll fetches the resource at url into v until done reports true, waiting
+// between fetches as long as the CA asks with Retry-After.
+func (c *Client) poll(ctx context.Context, url string, v interface{}, done func() (bool, error)) error {
+	var wait time.Duration
+	for i := 0; ; i++ {
+		if ok, err := done(); ok || err != nil {
+			return err
+		}
+		if i == maxPolls {
+			return errors.New("timed out")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		resp, err := c.post(ctx, url, nil, v)
+		if err != nil {
+			return err
+		}
+		wait = pollInterval
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+			wait = time.Duration(s) * time.Second
+		}
+	}
+}
+
+// get fetches the JSON resource at url, without authentication, into v.
+func (c *Client) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, b)
+	}
+	return json.Unmarshal(b, v)
+}
+
+// postAsGet fetches the resource at url with an empty signed request,
+// accepting the content type accept.
+func (c *Client) postAsGet(ctx context.Context, url, accept string) ([]byte, error) {
+	_, b, err := c.do(ctx, url, nil, accept)
+	return b, err
+}
+
+// post posts payload, or an empty payload to fetch the resource if nil,
+// signed with the account key to url, and decodes the response into v if
+// not nil.
+func (c *Client) post(ctx context.Context, url string, payload, v interface{}) (*http.Response, error) {
+	resp, b, err := c.do(ctx, url, payload, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	if v != nil && len(b) > 0 {
+		if err := json.Unmarshal(b, v); err != nil {
+			return nil, fmt.Errorf("decoding the response of %s: %w", url, err)
+		}
+	}
+	return resp, nil
+}
+
+func (c *Client) do(ctx context.Context, url string, payload interface{}, accept string) (*http.Response, []byte, error) {
+	body := []byte{}
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return nil, nil, err
+		}
+	}
+	for retry := 0; ; retry++ {
+		jws, err := c.sign(ctx, url, body)
+		if err != nil {
+			return nil, nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jws))
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Content-Type", joseContentType)
+		req.Header.Set("Accept", accept)
+		req.Header.Set("User-Agent", options.UserAgent())
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		c.saveNonce(resp)
+		if resp.StatusCode < 300 {
+			return resp, b, nil
+		}
+		err = responseError(resp, b)
+		var p *problemDoc
+		if errors.As(err, &p) && p.Type == problemBadNonce && retry < maxBadNonceRetries {
+			continue
+		}
+		return nil, nil, err
+	}
+}
+
+// sign returns the flattened JWS of payload for url, signed with the account
+// key and identified by the account URL, or the key if not registered yet.
+func (c *Client) sign(ctx context.Context, url string, payload []byte) ([]byte, error) {
+	if len(c.nonces) == 0 {
+		if err := c.fetchNonce(ctx); err != nil {
+			return nil, fmt.Errorf("fetching a nonce: %w", err)
+		}
+	}
+	opts := (&jose.SignerOptions{NonceSource: c}).WithHeader("url", url)
+	if c.kid != "" {
+		opts.WithHeader("kid", c.kid)
+	} else {
+		opts.EmbedJWK = true
+	}
+	s, err := jose.NewSigner(jose.SigningKey{Algorithm: c.alg, Key: c.key}, opts)
+	if err != nil {
+		return nil, err
+	}
+	jws, err := s.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+	return flatten(jws)
+}
+
+// Nonce implements jose.NonceSource with the nonces of the responses of the
+// CA.
+func (c *Client) Nonce() (string, error) {
+	if len(c.nonces) == 0 {
+		return "", errors.New("no nonce")
+	}
+	n := c.nonces[len(c.nonces)-1]
+	c.nonces = c.nonces[:len(c.nonces)-1]
+	return n, nil
+}
+
+func (c *Client) fetchNonce(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.dir.NewNonce, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if !c.saveNonce(resp) {
+		return fmt.Errorf("%s responded without a nonce", c.dir.NewNonce)
+	}
+	return nil
+}
+
+func (c *Client) saveNonce(resp *http.Response) bool {
+	n := resp.Header.Get("Replay-Nonce")
+	if n != "" {
+		c.nonces = append(c.nonces, n)
+	}
+	return n != ""
+}
+
+// flatten returns the flattened JSON serialization of jws, which ACME
+// requires, with the payload even if it is empty.
+func flatten(jws *jose.JSONWebSignature) ([]byte, error) {
+	compact, err := jws.CompactSerialize()
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(compact, ".")
+	return json.Marshal(struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+		Signature string `json:"signature"`
+	}{parts[0], parts[1], parts[2]})
+}
+
+// responseError returns the error of the response of the CA, its problem
+// document if it has one.
+func responseError(resp *http.Response, b []byte) error {
+	p := &problemDoc{}
+	if err := json.Unmarshal(b, p); err == nil && p.Type != "" {
+		return p
+	}
+	return fmt.Errorf("%s %s: %s", resp.Request.Method, resp.Request.URL, resp.Status)
+}
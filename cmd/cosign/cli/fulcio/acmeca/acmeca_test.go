@@ -0,0 +1,482 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acmeca
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"go.step.sm/crypto/jose"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+// fakeCA is an ACME CA with a single account and order, that issues
+// certificates for the identifiers of the order.
+type fakeCA struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	profiles   map[string]string
+	eabKeyID   string
+	eabHMACKey []byte
+	// pending makes the authorization of the order pending until its
+	// http-01 challenge is answered by client.
+	pending bool
+	client  *Client
+	// badNonce rejects the first request with a nonce.
+	badNonce bool
+
+	mu          sync.Mutex
+	nonce       int
+	accountKey  *jose.JSONWebKey
+	identifiers []Identifier
+	profile     string
+	authzStatus string
+	orderStatus string
+	// chain is the certificate chain of the order, once issued.
+	chain []byte
+
+	caKey  *ecdsa.PrivateKey
+	caCert *x509.Certificate
+}
+
+func newFakeCA(t *testing.T) *fakeCA {
+	ca := &fakeCA{t: t}
+	var err error
+	if ca.caKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ACME root"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, ca.caKey.Public(), ca.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ca.caCert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	ca.srv = httptest.NewServer(http.HandlerFunc(ca.serve))
+	t.Cleanup(ca.srv.Close)
+	return ca
+}
+
+func (ca *fakeCA) address() string {
+	return Scheme + ca.srv.URL + "/directory"
+}
+
+func (ca *fakeCA) serve(w http.ResponseWriter, r *http.Request) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	ca.nonce++
+	w.Header().Set("Replay-Nonce", fmt.Sprint("nonce-", ca.nonce))
+	switch r.URL.Path {
+	case "/directory":
+		dir := map[string]interface{}{
+			"newNonce":   ca.srv.URL + "/new-nonce",
+			"newAccount": ca.srv.URL + "/new-account",
+			"newOrder":   ca.srv.URL + "/new-order",
+			"meta":       map[string]interface{}{"profiles": ca.profiles, "externalAccountRequired": ca.eabKeyID != ""},
+		}
+		_ = json.NewEncoder(w).Encode(dir)
+		return
+	case "/new-nonce":
+		return
+	}
+
+	payload, err := ca.verify(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		typ := "urn:ietf:params:acme:error:malformed"
+		if strings.Contains(err.Error(), "nonce") {
+			typ = problemBadNonce
+		}
+		_ = json.NewEncoder(w).Encode(problemDoc{Type: typ, Detail: err.Error()})
+		return
+	}
+	switch r.URL.Path {
+	case "/new-account":
+		w.Header().Set("Location", ca.srv.URL+"/account/1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"status": "valid"}`)
+	case "/new-order":
+		var req struct {
+			Identifiers []Identifier `json:"identifiers"`
+			Profile     string       `json:"profile"`
+		}
+		if err := json.Unmarshal(payload, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ca.identifiers, ca.profile = req.Identifiers, req.Profile
+		ca.authzStatus, ca.orderStatus = "valid", "ready"
+		if ca.pending {
+			ca.authzStatus, ca.orderStatus = "pending", "pending"
+		}
+		w.Header().Set("Location", ca.srv.URL+"/order/1")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(ca.order())
+	case "/order/1":
+		_ = json.NewEncoder(w).Encode(ca.order())
+	case "/authz/1":
+		_ = json.NewEncoder(w).Encode(authorization{
+			Status:     ca.authzStatus,
+			Identifier: ca.identifiers[0],
+			Challenges: []challenge{{Type: "dns-01", URL: ca.srv.URL + "/chall/2", Token: "dns-token"}, {Type: challengeHTTP01, URL: ca.srv.URL + "/chall/1", Token: "token"}},
+		})
+	case "/chall/1":
+		if err := ca.validateHTTP01(); err != nil {
+			ca.authzStatus, ca.orderStatus = "invalid", "invalid"
+		} else {
+			ca.authzStatus, ca.orderStatus = "valid", "ready"
+		}
+		_, _ = io.WriteString(w, `{"status": "processing"}`)
+	case "/finalize/1":
+		var req struct {
+			CSR string `json:"csr"`
+		}
+		if err := json.Unmarshal(payload, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := ca.issue(req.CSR); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(problemDoc{Type: "urn:ietf:params:acme:error:badCSR", Detail: err.Error()})
+			return
+		}
+		ca.orderStatus = "valid"
+		_ = json.NewEncoder(w).Encode(ca.order())
+	case "/cert/1":
+		if r.Header.Get("Accept") != chainContentType {
+			http.Error(w, "not acceptable", http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", chainContentType)
+		_, _ = w.Write(ca.chain)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (ca *fakeCA) order() order {
+	o := order{
+		Status:         ca.orderStatus,
+		Authorizations: []string{ca.srv.URL + "/authz/1"},
+		Finalize:       ca.srv.URL + "/finalize/1",
+	}
+	if ca.orderStatus == "valid" {
+		o.Certificate = ca.srv.URL + "/cert/1"
+	}
+	return o
+}
+
+// verify returns the payload of the JWS of r, checking its URL, nonce and
+// signature by the account key, and the external account binding of new
+// accounts.
+func (ca *fakeCA) verify(r *http.Request) ([]byte, error) {
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != joseContentType {
+		return nil, fmt.Errorf("%s %s isn't a JWS", r.Method, r.Header.Get("Content-Type"))
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	jws, err := jose.ParseJWS(string(b))
+	if err != nil {
+		return nil, err
+	}
+	h := jws.Signatures[0].Protected
+	if h.ExtraHeaders["url"] != ca.srv.URL+r.URL.Path {
+		return nil, fmt.Errorf("url %v, want %s", h.ExtraHeaders["url"], r.URL.Path)
+	}
+	if h.Nonce == "" {
+		return nil, fmt.Errorf("no nonce")
+	}
+	if ca.badNonce {
+		ca.badNonce = false
+		return nil, fmt.Errorf("bad nonce %s", h.Nonce)
+	}
+	if r.URL.Path == "/new-account" {
+		if h.JSONWebKey == nil {
+			return nil, fmt.Errorf("new account without jwk")
+		}
+		ca.accountKey = h.JSONWebKey
+	} else if h.KeyID != ca.srv.URL+"/account/1" {
+		return nil, fmt.Errorf("kid %s isn't the account", h.KeyID)
+	}
+	payload, err := jws.Verify(ca.accountKey.Key)
+	if err != nil {
+		return nil, err
+	}
+	if r.URL.Path == "/new-account" && ca.eabKeyID != "" {
+		var req struct {
+			ExternalAccountBinding json.RawMessage `json:"externalAccountBinding"`
+		}
+		if err := json.Unmarshal(payload, &req); err != nil || req.ExternalAccountBinding == nil {
+			return nil, fmt.Errorf("no external account binding")
+		}
+		eab, err := jose.ParseJWS(string(req.ExternalAccountBinding))
+		if err != nil {
+			return nil, err
+		}
+		if eab.Signatures[0].Protected.KeyID != ca.eabKeyID {
+			return nil, fmt.Errorf("external account binding of %s", eab.Signatures[0].Protected.KeyID)
+		}
+		bound, err := eab.Verify(ca.eabHMACKey)
+		if err != nil {
+			return nil, err
+		}
+		var jwk jose.JSONWebKey
+		if err := json.Unmarshal(bound, &jwk); err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(jwk.Key, ca.accountKey.Key) {
+			return nil, fmt.Errorf("external account binding of another key")
+		}
+	}
+	return payload, nil
+}
+
+func (ca *fakeCA) validateHTTP01() error {
+	resp, err := http.Get("http://" + ca.client.http01Addr + http01PathPrefix + "token")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	thumbprint, err := ca.accountKey.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	if want := "token." + base64.RawURLEncoding.EncodeToString(thumbprint); string(b) != want {
+		return fmt.Errorf("key authorization %s, want %s", b, want)
+	}
+	return nil
+}
+
+// issue issues the certificate of the CSR, which must be for the
+// identifiers of the order.
+func (ca *fakeCA) issue(b64 string) error {
+	der, err := base64.RawURLEncoding.DecodeString(b64)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return err
+	}
+	var ids []Identifier
+	for _, n := range csr.DNSNames {
+		ids = append(ids, Identifier{"dns", n})
+	}
+	for _, e := range csr.EmailAddresses {
+		ids = append(ids, Identifier{"email", e})
+	}
+	if !reflect.DeepEqual(ids, ca.identifiers) {
+		return fmt.Errorf("CSR for %v, want %v", ids, ca.identifiers)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(2),
+		NotBefore:      time.Now(),
+		NotAfter:       time.Now().Add(10 * time.Minute),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		DNSNames:       csr.DNSNames,
+		EmailAddresses: csr.EmailAddresses,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, ca.caCert, csr.PublicKey, ca.caKey)
+	if err != nil {
+		return err
+	}
+	c, err := x509.ParseCertificate(cert)
+	if err != nil {
+		return err
+	}
+	ca.chain, err = cryptoutils.MarshalCertificatesToPEM([]*x509.Certificate{c, ca.caCert})
+	return err
+}
+
+func newSignerVerifier(t *testing.T) signature.SignerVerifier {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sv
+}
+
+func TestSigningCert(t *testing.T) {
+	ca := newFakeCA(t)
+	ca.profiles = map[string]string{"codesigning": "short-lived code signing certificates", "tls": "server certificates"}
+	ca.eabKeyID, ca.eabHMACKey = "kid-1", []byte("0123456789abcdef0123456789abcdef")
+	ca.badNonce = true
+	c, err := New(ca.address(), options.ACMEOptions{
+		Profile:     "codesigning",
+		Identifiers: []string{"build.example.com", "email:release@example.com"},
+		EABKeyID:    "kid-1",
+		EABHMACKey:  base64.RawURLEncoding.EncodeToString(ca.eabHMACKey),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv := newSignerVerifier(t)
+	certPEM, chainPEM, err := c.SigningCert(context.Background(), sv)
+	if err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	if ca.profile != "codesigning" {
+		t.Errorf("ordered in profile %q, want codesigning", ca.profile)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(certPEM)
+	if err != nil || len(certs) != 1 {
+		t.Fatalf("certificate = %d certificates, %v", len(certs), err)
+	}
+	if !reflect.DeepEqual(certs[0].DNSNames, []string{"build.example.com"}) || !reflect.DeepEqual(certs[0].EmailAddresses, []string{"release@example.com"}) {
+		t.Errorf("certificate for %v and %v", certs[0].DNSNames, certs[0].EmailAddresses)
+	}
+	chain, err := cryptoutils.UnmarshalCertificatesFromPEM(chainPEM)
+	if err != nil || len(chain) != 1 || !chain[0].Equal(ca.caCert) {
+		t.Errorf("chain = %d certificates, %v, want the root", len(chain), err)
+	}
+}
+
+func TestSigningCertHTTP01(t *testing.T) {
+	pollInterval = time.Millisecond
+	t.Cleanup(func() { pollInterval = time.Second })
+	ca := newFakeCA(t)
+	ca.pending = true
+	c, err := New(ca.address(), options.ACMEOptions{Identifiers: []string{"build.example.com"}, HTTP01Listen: "127.0.0.1:0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca.client = c
+	if _, _, err := c.SigningCert(context.Background(), newSignerVerifier(t)); err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	if ca.authzStatus != "valid" {
+		t.Errorf("authorization is %s, want valid", ca.authzStatus)
+	}
+}
+
+func TestSigningCertErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		ca      func(*fakeCA)
+		opts    options.ACMEOptions
+		wantErr string
+	}{{
+		name:    "unknown profile",
+		ca:      func(ca *fakeCA) { ca.profiles = map[string]string{"tls": "", "codesigning": ""} },
+		opts:    options.ACMEOptions{Profile: "smime"},
+		wantErr: "--acme-profile smime isn't a profile of ACME CA",
+	}, {
+		name:    "external account required",
+		ca:      func(ca *fakeCA) { ca.eabKeyID = "kid-1" },
+		wantErr: "requires an external account binding",
+	}, {
+		name:    "pending authorization",
+		ca:      func(ca *fakeCA) { ca.pending = true },
+		wantErr: "hasn't authorized the account for dns:build.example.com, and its dns-01, http-01 challenges aren't answered",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ca := newFakeCA(t)
+			tc.ca(ca)
+			tc.opts.Identifiers = []string{"build.example.com"}
+			c, err := New(ca.address(), tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _, err = c.SigningCert(context.Background(), newSignerVerifier(t))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("SigningCert() = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		address string
+		opts    options.ACMEOptions
+		wantErr string
+	}{
+		{"https://ca.example.com", options.ACMEOptions{Identifiers: []string{"a.example.com"}}, "isn't an ACME CA"},
+		{"acme:https://ca.example.com", options.ACMEOptions{}, "--acme-identifier is required"},
+		{"acme:https://ca.example.com", options.ACMEOptions{Identifiers: []string{"uri:https://example.com"}}, `unsupported identifier type "uri"`},
+		{"acme:https://ca.example.com", options.ACMEOptions{Identifiers: []string{"a.example.com"}, EABKeyID: "kid"}, "must be set together"},
+	} {
+		if _, err := New(tc.address, tc.opts); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("New(%s, %+v) = %v, want %q", tc.address, tc.opts, err, tc.wantErr)
+		}
+	}
+}
+
+func TestParseIdentifier(t *testing.T) {
+	for in, want := range map[string]Identifier{
+		"build.example.com":         {"dns", "build.example.com"},
+		"dns:build.example.com":     {"dns", "build.example.com"},
+		"ip:10.0.0.1":               {"ip", "10.0.0.1"},
+		"2001:db8::1":               {"ip", "2001:db8::1"},
+		"email:release@example.com": {"email", "release@example.com"},
+	} {
+		got, err := ParseIdentifier(in)
+		if err != nil || got != want {
+			t.Errorf("ParseIdentifier(%s) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"ip:build.example.com", "email:example.com", "dns:"} {
+		if _, err := ParseIdentifier(in); err == nil {
+			t.Errorf("ParseIdentifier(%s) = nil error", in)
+		}
+	}
+}
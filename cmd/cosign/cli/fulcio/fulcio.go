@@ -19,14 +19,20 @@ import (
 	"context"
 	"crypto"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/acmeca"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/localca"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/tokenexchange"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign/privacy"
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/fulcio/fulcioroots"
+	"github.com/sigstore/cosign/v2/internal/pkg/daemon"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/providers"
 	"github.com/sigstore/fulcio/pkg/api"
@@ -110,30 +116,20 @@ type Signer struct {
 }
 
 func NewSigner(ctx context.Context, ko options.KeyOpts, signer signature.SignerVerifier) (*Signer, error) {
+	if localca.IsLocal(ko.FulcioURL) {
+		return newLocalCASigner(ctx, ko, signer)
+	}
+	if acmeca.IsACME(ko.FulcioURL) {
+		return newACMESigner(ctx, ko, signer)
+	}
 	fClient, err := NewClient(ko.FulcioURL)
 	if err != nil {
 		return nil, fmt.Errorf("creating Fulcio client: %w", err)
 	}
 
-	idToken, err := idToken(ko.IDToken)
+	idToken, err := oidcToken(ctx, ko)
 	if err != nil {
-		return nil, fmt.Errorf("getting id token: %w", err)
-	}
-	var provider providers.Interface
-	// If token is not set in the options, get one from the provders
-	if idToken == "" && providers.Enabled(ctx) && !ko.OIDCDisableProviders {
-		if ko.OIDCProvider != "" {
-			provider, err = providers.ProvideFrom(ctx, ko.OIDCProvider)
-			if err != nil {
-				return nil, fmt.Errorf("getting provider: %w", err)
-			}
-			idToken, err = provider.Provide(ctx, "sigstore")
-		} else {
-			idToken, err = providers.Provide(ctx, "sigstore")
-		}
-		if err != nil {
-			return nil, fmt.Errorf("fetching ambient OIDC credentials: %w", err)
-		}
+		return nil, err
 	}
 
 	fmt.Fprintln(os.Stderr, "Retrieving signed certificate...")
@@ -145,6 +141,10 @@ func NewSigner(ctx context.Context, ko options.KeyOpts, signer signature.SignerV
 		flow = ko.FulcioAuthFlow
 	case idToken != "":
 		flow = flowToken
+	case ko.SkipConfirmation || inCI():
+		// Never open a browser or wait for a device code nobody will enter.
+		return nil, errors.New("no OIDC token to request a certificate with non-interactively: " +
+			"pass one with --identity-token or --oidc-token-file, run with an ambient OIDC provider, or run without --yes outside of CI")
 	case !term.IsTerminal(0):
 		fmt.Fprintln(os.Stderr, "Non-interactive mode detected, using device flow.")
 		flow = flowDevice
@@ -153,11 +153,7 @@ func NewSigner(ctx context.Context, ko options.KeyOpts, signer signature.SignerV
 		privacy.StatementOnce.Do(func() {
 			ui.Infof(ctx, privacy.Statement)
 			ui.Infof(ctx, privacy.StatementConfirmation)
-			if !ko.SkipConfirmation {
-				if err := ui.ConfirmContinue(ctx); err != nil {
-					statementErr = err
-				}
-			}
+			statementErr = ui.ConfirmContinue(ctx)
 		})
 		if statementErr != nil {
 			return nil, statementErr
@@ -179,6 +175,155 @@ func NewSigner(ctx context.Context, ko options.KeyOpts, signer signature.SignerV
 	return f, nil
 }
 
+// newLocalCASigner returns the signer with the certificate of the local CA
+// of ko.FulcioURL, requested with the OIDC token of ko if there is one, as
+// local CAs may authenticate signers otherwise.
+func newLocalCASigner(ctx context.Context, ko options.KeyOpts, signer signature.SignerVerifier) (*Signer, error) {
+	c, err := localca.New(ko.FulcioURL)
+	if err != nil {
+		return nil, err
+	}
+	idToken, err := oidcToken(ctx, ko)
+	if err != nil {
+		return nil, err
+	}
+	ui.Infof(ctx, "Retrieving signed certificate from local CA %s...", ko.FulcioURL)
+	cert, chain, err := c.SigningCert(ctx, signer, idToken)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving cert: %w", err)
+	}
+	return &Signer{SignerVerifier: signer, Cert: cert, Chain: chain}, nil
+}
+
+// newACMESigner returns the signer with the certificate ordered from the ACME
+// CA of ko.FulcioURL for the identifiers of ko.ACME. The account of the
+// order authenticates the signer, not an OIDC token.
+func newACMESigner(ctx context.Context, ko options.KeyOpts, signer signature.SignerVerifier) (*Signer, error) {
+	c, err := acmeca.New(ko.FulcioURL, ko.ACME)
+	if err != nil {
+		return nil, err
+	}
+	ui.Infof(ctx, "Ordering signed certificate from ACME CA %s...", strings.TrimPrefix(ko.FulcioURL, acmeca.Scheme))
+	cert, chain, err := c.SigningCert(ctx, signer)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving cert: %w", err)
+	}
+	return &Signer{SignerVerifier: signer, Cert: cert, Chain: chain}, nil
+}
+
+// oidcToken returns the OIDC token of ko, from --identity-token,
+// --oidc-token-file or the ambient providers, after exchanging it at
+// ko.OIDCTokenExchangeIssuer if set, or "" if there is none.
+func oidcToken(ctx context.Context, ko options.KeyOpts) (string, error) {
+	idToken, err := idToken(ko.IDToken)
+	if err != nil {
+		return "", fmt.Errorf("getting id token: %w", err)
+	}
+	if idToken == "" && ko.OIDCTokenFile != "" {
+		b, err := os.ReadFile(ko.OIDCTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("reading OIDC token: %w", err)
+		}
+		idToken = strings.TrimSpace(string(b))
+	}
+	audience := ko.OIDCAudience
+	if audience == "" {
+		audience = "sigstore"
+	}
+	// If token is not set in the options, get one from the provders
+	if idToken == "" && !ko.OIDCDisableProviders {
+		if idToken, err = ambientToken(ctx, ko.OIDCProvider, audience); err != nil {
+			return "", err
+		}
+	}
+	if idToken == "" {
+		return "", nil
+	}
+
+	if ko.OIDCTokenExchangeIssuer != "" {
+		e := &tokenexchange.Exchanger{
+			Issuer:       ko.OIDCTokenExchangeIssuer,
+			Audience:     audience,
+			ClientID:     ko.OIDCClientID,
+			ClientSecret: ko.OIDCClientSecret,
+		}
+		if idToken, err = e.Exchange(ctx, idToken); err != nil {
+			return "", err
+		}
+	}
+	if ko.OIDCAudience != "" {
+		if err := tokenexchange.CheckAudience(idToken, ko.OIDCAudience); err != nil {
+			return "", err
+		}
+	}
+	return idToken, nil
+}
+
+// ambientTokenMargin is how long before their expiry the cosign daemon stops
+// reusing ambient OIDC tokens.
+const ambientTokenMargin = time.Minute
+
+// ambientToken returns a token for audience from the OIDC provider named
+// provider, or from the enabled providers if empty, or "" if there is none.
+// The cosign daemon reuses the token until shortly before it expires.
+func ambientToken(ctx context.Context, provider, audience string) (string, error) {
+	v, err := daemon.Warm(ctx, "oidc/"+provider+"/"+audience, func() ([]byte, time.Time, error) {
+		idToken, err := provideToken(ctx, provider, audience)
+		if err != nil || idToken == "" {
+			return []byte(idToken), time.Now(), err
+		}
+		expires, err := tokenexchange.Expiry(idToken)
+		if err != nil {
+			// Tokens that can't be told to be valid aren't reused.
+			return []byte(idToken), time.Now(), nil
+		}
+		return []byte(idToken), expires.Add(-ambientTokenMargin), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(v), nil
+}
+
+func provideToken(ctx context.Context, provider, audience string) (string, error) {
+	p := providers.Interface(nil)
+	if provider != "" {
+		var err error
+		if p, err = providers.ProvideFrom(ctx, provider); err != nil {
+			return "", fmt.Errorf("getting provider: %w", err)
+		}
+	}
+	var (
+		idToken string
+		err     error
+	)
+	switch {
+	// Providers named by their configuration, e.g. exec:<path>, aren't
+	// among the registered ones.
+	case p != nil && (p.Enabled(ctx) || providers.Enabled(ctx)):
+		idToken, err = p.Provide(ctx, audience)
+	case p == nil && providers.Enabled(ctx):
+		idToken, err = providers.Provide(ctx, audience)
+	}
+	if err != nil {
+		return "", fmt.Errorf("fetching ambient OIDC credentials: %w", err)
+	}
+	return idToken, nil
+}
+
+// ciEnvironment are the variables set by CI systems, whose jobs can't
+// complete an interactive OIDC flow.
+var ciEnvironment = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "JENKINS_URL", "TF_BUILD"}
+
+func inCI() bool {
+	for _, v := range ciEnvironment {
+		if s := os.Getenv(v); s != "" && s != "false" && s != "0" {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *Signer) PublicKey(opts ...signature.PublicKeyOption) (crypto.PublicKey, error) { //nolint: revive
 	return f.SignerVerifier.PublicKey()
 }
@@ -193,6 +338,11 @@ func GetIntermediates() (*x509.CertPool, error) {
 	return fulcioroots.GetIntermediates()
 }
 
+// GetCertificates returns the certificates of GetRoots and GetIntermediates.
+func GetCertificates() (roots, intermediates []*x509.Certificate, err error) {
+	return fulcioroots.GetCertificates()
+}
+
 func NewClient(fulcioURL string) (api.LegacyClient, error) {
 	fulcioServer, err := url.Parse(fulcioURL)
 	if err != nil {
@@ -22,10 +22,15 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
@@ -227,3 +232,73 @@ func TestNewSigner(t *testing.T) {
 		t.Fatalf("missing signer/verifier")
 	}
 }
+
+func TestOIDCToken(t *testing.T) {
+	ctx := context.Background()
+	enc := base64.RawURLEncoding
+	jwt := func(aud string) string {
+		return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(`{"aud":"`+aud+`"}`)) + ".sig"
+	}
+	ciToken, exchanged := jwt("https://ci.example.com"), jwt("sigstore")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte(ciToken+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	issuer := httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": issuer.URL + "/token"})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("subject_token") != ciToken || r.FormValue("audience") != "sigstore" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": exchanged})
+	})
+
+	ko := options.KeyOpts{OIDCDisableProviders: true, OIDCTokenFile: tokenFile}
+	if got, err := oidcToken(ctx, ko); err != nil || got != ciToken {
+		t.Errorf("oidcToken() of the token file = %q, %v, want %q", got, err, ciToken)
+	}
+	ko.OIDCAudience = "sigstore"
+	if _, err := oidcToken(ctx, ko); err == nil || !strings.Contains(err.Error(), "not sigstore") {
+		t.Errorf("oidcToken() for another audience = %v, want an error", err)
+	}
+	ko.OIDCTokenExchangeIssuer = issuer.URL
+	if got, err := oidcToken(ctx, ko); err != nil || got != exchanged {
+		t.Errorf("oidcToken() with the exchange = %q, %v, want %q", got, err, exchanged)
+	}
+	ko.OIDCAudience = ""
+	if got, err := oidcToken(ctx, ko); err != nil || got != exchanged {
+		t.Errorf("oidcToken() with the exchange for the default audience = %q, %v, want %q", got, err, exchanged)
+	}
+}
+
+func TestNewSignerNonInteractive(t *testing.T) {
+	for _, v := range ciEnvironment {
+		t.Setenv(v, "")
+	}
+	if inCI() {
+		t.Fatal("inCI() = true without CI variables")
+	}
+	t.Setenv("CI", "true")
+	if !inCI() {
+		t.Error("inCI() = false with CI=true")
+	}
+
+	privKey, err := cosign.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(privKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ko := options.KeyOpts{OIDCDisableProviders: true, FulcioURL: "http://127.0.0.1:0"}
+	if _, err := NewSigner(context.Background(), ko, sv); err == nil || !strings.Contains(err.Error(), "non-interactively") {
+		t.Errorf("NewSigner() in CI without a token = %v, want a non-interactive error", err)
+	}
+}
@@ -0,0 +1,233 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localca requests short-lived signing certificates from a local CA
+// instead of Fulcio, for disconnected environments.
+//
+// The CA is a helper executable, named with exec:<path>, or a Unix socket,
+// named with unix:<path>. A Request is written to the standard input of the
+// helper, or to a connection to the socket, and a Response is read from its
+// standard output, or from the connection.
+package localca
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	osexec "os/exec"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+// APIVersion is the version of the requests and responses of local CAs.
+const APIVersion = "cosign.sigstore.dev/local-ca/v1"
+
+// maxResponseSize bounds the responses read from local CAs.
+const maxResponseSize = 1 << 20
+
+// Request asks a local CA for the certificate of the key of the CSR.
+type Request struct {
+	APIVersion string `json:"apiVersion"`
+	// CertificateSigningRequest is the PEM-encoded CSR, signed with the key
+	// to certify.
+	CertificateSigningRequest string `json:"certificateSigningRequest"`
+	// IdentityToken is the OIDC token of the signer, if any, for local CAs
+	// that certify the identity of its subject.
+	IdentityToken string `json:"identityToken,omitempty"`
+}
+
+// Response is the certificate issued by a local CA, or its error.
+type Response struct {
+	APIVersion string `json:"apiVersion"`
+	// CertificateChain is the PEM-encoded certificate, followed by the
+	// certificates of the CA up to its root.
+	CertificateChain string `json:"certificateChain,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// IsLocal reports whether the address of a CA, e.g. of --fulcio-url, names a
+// local CA.
+func IsLocal(address string) bool {
+	return strings.HasPrefix(address, "exec:") || strings.HasPrefix(address, "unix:")
+}
+
+// Client requests certificates from a local CA.
+type Client struct {
+	address string
+	exec    string
+	socket  string
+}
+
+// New returns the client of the local CA at address, exec:<path> of a helper
+// executable, which is looked up in PATH if it has no separator, or
+// unix:<path> of a socket.
+func New(address string) (*Client, error) {
+	scheme, path, _ := strings.Cut(address, ":")
+	switch scheme {
+	case "exec":
+		if path == "" {
+			return nil, errors.New("local CA helpers are named as exec:<path>")
+		}
+		p, err := osexec.LookPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("finding local CA helper: %w", err)
+		}
+		return &Client{address: address, exec: p}, nil
+	case "unix":
+		path = strings.TrimPrefix(path, "//")
+		if path == "" {
+			return nil, errors.New("local CA sockets are named as unix:<path>")
+		}
+		return &Client{address: address, socket: path}, nil
+	}
+	return nil, fmt.Errorf("%s isn't a local CA, exec:<path> or unix:<path>", address)
+}
+
+// SigningCert returns the PEM-encoded certificate of the key of sv, with the
+// identity token if not empty, and the PEM-encoded chain of the local CA.
+func (c *Client) SigningCert(ctx context.Context, sv signature.SignerVerifier, token string) (cert, chain []byte, err error) {
+	csr, err := CertificateSigningRequest(ctx, sv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating certificate signing request: %w", err)
+	}
+	req, err := json.Marshal(Request{APIVersion: APIVersion, CertificateSigningRequest: string(csr), IdentityToken: token})
+	if err != nil {
+		return nil, nil, err
+	}
+	var b []byte
+	if c.exec != "" {
+		b, err = c.run(ctx, req)
+	} else {
+		b, err = c.dial(ctx, req)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := Response{}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, nil, fmt.Errorf("decoding the response of local CA %s: %w", c.address, err)
+	}
+	if resp.APIVersion != APIVersion {
+		return nil, nil, fmt.Errorf("local CA %s responded with apiVersion %q, want %q", c.address, resp.APIVersion, APIVersion)
+	}
+	if resp.Error != "" {
+		return nil, nil, fmt.Errorf("local CA %s: %s", c.address, resp.Error)
+	}
+	return splitChain(c.address, []byte(resp.CertificateChain), sv)
+}
+
+func (c *Client) run(ctx context.Context, req []byte) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd := osexec.CommandContext(ctx, c.exec)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running local CA helper %s: %w", c.exec, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func (c *Client) dial(ctx context.Context, req []byte) ([]byte, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", c.socket)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to local CA: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := conn.Write(append(req, '\n')); err != nil {
+		return nil, fmt.Errorf("writing to local CA %s: %w", c.address, err)
+	}
+	var resp json.RawMessage
+	if err := json.NewDecoder(io.LimitReader(conn, maxResponseSize)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading the response of local CA %s: %w", c.address, err)
+	}
+	return resp, nil
+}
+
+// splitChain returns the certificate of the chain of the local CA at address,
+// which must certify the key of sv, and the rest of the chain.
+func splitChain(address string, chain []byte, sv signature.SignerVerifier) ([]byte, []byte, error) {
+	block, rest := pem.Decode(chain)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, nil, fmt.Errorf("local CA %s responded without a certificate", address)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing the certificate of local CA %s: %w", address, err)
+	}
+	pub, err := sv.PublicKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	if k, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(pub) {
+		return nil, nil, fmt.Errorf("the certificate of local CA %s doesn't certify the signing key", address)
+	}
+	return pem.EncodeToMemory(block), bytes.TrimLeft(rest, "\n"), nil
+}
+
+// CertificateSigningRequest returns the PEM-encoded CSR of the key of sv,
+// signed with it.
+func CertificateSigningRequest(ctx context.Context, sv signature.SignerVerifier) ([]byte, error) {
+	return NewCertificateSigningRequest(ctx, sv, &x509.CertificateRequest{})
+}
+
+// NewCertificateSigningRequest returns the PEM-encoded CSR of the key of sv
+// made from tmpl, e.g. with the names to certify, signed with it.
+func NewCertificateSigningRequest(ctx context.Context, sv signature.SignerVerifier, tmpl *x509.CertificateRequest) ([]byte, error) {
+	pub, err := sv.PublicKey(signatureoptions.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, &cryptoSigner{ctx: ctx, sv: sv, pub: pub})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// cryptoSigner signs the digests of x509.CreateCertificateRequest with a
+// signature.SignerVerifier, e.g. of a KMS key.
+type cryptoSigner struct {
+	ctx context.Context
+	sv  signature.SignerVerifier
+	pub crypto.PublicKey
+}
+
+func (s *cryptoSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+func (s *cryptoSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.sv.SignMessage(bytes.NewReader(nil), signatureoptions.WithContext(s.ctx),
+		signatureoptions.WithDigest(digest), signatureoptions.WithCryptoSignerOpts(opts))
+}
@@ -0,0 +1,214 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localca
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// issue returns the response of a local CA with the root and its key to the
+// request.
+func issue(t *testing.T, root *x509.Certificate, rootKey crypto.Signer, req Request) Response {
+	t.Helper()
+	block, _ := pem.Decode([]byte(req.CertificateSigningRequest))
+	if block == nil {
+		return Response{APIVersion: APIVersion, Error: "no CSR"}
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil || csr.CheckSignature() != nil {
+		return Response{APIVersion: APIVersion, Error: "invalid CSR"}
+	}
+	if req.IdentityToken != "token" {
+		return Response{APIVersion: APIVersion, Error: "unauthenticated"}
+	}
+	pub := csr.PublicKey.(*ecdsa.PublicKey)
+	cert, err := test.GenerateLeafCertWithExpiration("foo@example.com", "https://local-ca.example.com", time.Now().Add(-time.Minute),
+		&ecdsa.PrivateKey{PublicKey: *pub}, root, rootKey)
+	if err != nil {
+		t.Error(err)
+		return Response{APIVersion: APIVersion, Error: err.Error()}
+	}
+	chain, err := cryptoutils.MarshalCertificatesToPEM([]*x509.Certificate{cert, root})
+	if err != nil {
+		t.Error(err)
+	}
+	return Response{APIVersion: APIVersion, CertificateChain: string(chain)}
+}
+
+func newSigner(t *testing.T) signature.SignerVerifier {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sv
+}
+
+func TestSigningCertSocket(t *testing.T) {
+	ctx := context.Background()
+	root, rootKey, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unix socket paths are short, unlike those of t.TempDir.
+	dir, err := os.MkdirTemp("", "localca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "ca.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			req := Request{}
+			if err := json.NewDecoder(conn).Decode(&req); err == nil {
+				_ = json.NewEncoder(conn).Encode(issue(t, root, rootKey, req))
+			}
+			conn.Close()
+		}
+	}()
+
+	c, err := New("unix://" + socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv := newSigner(t)
+	cert, chain, err := c.SigningCert(ctx, sv, "token")
+	if err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(cert)
+	if err != nil || len(certs) != 1 {
+		t.Fatalf("SigningCert() certificate = %s, %v", cert, err)
+	}
+	pub, _ := sv.PublicKey()
+	if err := cryptoutils.EqualKeys(certs[0].PublicKey, pub); err != nil {
+		t.Errorf("SigningCert() certificate: %v", err)
+	}
+	if roots, err := cryptoutils.UnmarshalCertificatesFromPEM(chain); err != nil || len(roots) != 1 || !roots[0].Equal(root) {
+		t.Errorf("SigningCert() chain = %s, %v, want the root", chain, err)
+	}
+
+	if _, _, err := c.SigningCert(ctx, sv, ""); err == nil || !strings.Contains(err.Error(), "unauthenticated") {
+		t.Errorf("SigningCert() without a token = %v, want the error of the CA", err)
+	}
+}
+
+func writeHelper(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "helper")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSigningCertExec(t *testing.T) {
+	ctx := context.Background()
+	root, rootKey, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv := newSigner(t)
+	csr, err := CertificateSigningRequest(ctx, sv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The helper responds with the certificate of the key of sv. printf keeps
+	// the escaped newlines of the PEM, unlike the echo of some shells.
+	resp, err := json.Marshal(issue(t, root, rootKey, Request{CertificateSigningRequest: string(csr), IdentityToken: "token"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New("exec:" + writeHelper(t, "cat >/dev/null\nprintf '%s\\n' '"+string(resp)+"'"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.SigningCert(ctx, sv, ""); err != nil {
+		t.Errorf("SigningCert() = %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		script string
+		want   string
+	}{
+		"failed": {
+			script: "echo denied >&2; exit 3",
+			want:   "exit status 3",
+		},
+		"another version": {
+			script: `echo '{"apiVersion":"v0"}'`,
+			want:   "apiVersion",
+		},
+		"error": {
+			script: `echo '{"apiVersion":"` + APIVersion + `","error":"denied"}'`,
+			want:   "denied",
+		},
+		"no certificate": {
+			script: `echo '{"apiVersion":"` + APIVersion + `"}'`,
+			want:   "without a certificate",
+		},
+		"another key": {
+			script: "printf '%s\\n' '" + string(resp) + "'",
+			want:   "doesn't certify the signing key",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := New("exec:" + writeHelper(t, tc.script))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := c.SigningCert(ctx, newSigner(t), ""); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("SigningCert() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	for _, address := range []string{"exec:", "unix:", "https://fulcio.sigstore.dev"} {
+		if _, err := New(address); err == nil {
+			t.Errorf("New(%q): expected an error", address)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokenexchange exchanges the OIDC tokens of CI systems, whose
+// issuers or audiences Fulcio doesn't accept, for ID tokens Fulcio accepts,
+// with the OAuth 2.0 token exchange of RFC 8693.
+package tokenexchange
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	grantType      = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeJWT   = "urn:ietf:params:oauth:token-type:jwt"
+	tokenTypeIDJWT = "urn:ietf:params:oauth:token-type:id_token"
+)
+
+// Exchanger exchanges tokens at the token endpoint of an OIDC issuer.
+type Exchanger struct {
+	// Issuer is the URL of the issuer, whose token endpoint is discovered
+	// from its OpenID configuration.
+	Issuer string
+	// Audience, if set, is the audience of the exchanged token.
+	Audience string
+	// ClientID and ClientSecret, if set, authenticate the exchange.
+	ClientID     string
+	ClientSecret string
+	// HTTPClient sends the requests, http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// Exchange returns the ID token the issuer exchanges subjectToken for.
+func (e *Exchanger) Exchange(ctx context.Context, subjectToken string) (string, error) {
+	endpoint, err := e.tokenEndpoint(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":           {grantType},
+		"subject_token":        {subjectToken},
+		"subject_token_type":   {tokenTypeJWT},
+		"requested_token_type": {tokenTypeIDJWT},
+	}
+	if e.Audience != "" {
+		form.Set("audience", e.Audience)
+	}
+	if e.ClientID != "" && e.ClientSecret == "" {
+		form.Set("client_id", e.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if e.ClientID != "" && e.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(e.ClientID), url.QueryEscape(e.ClientSecret))
+	}
+
+	var resp struct {
+		AccessToken      string `json:"access_token"`
+		IssuedTokenType  string `json:"issued_token_type"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := e.do(req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("exchanging the OIDC token at %s: %w", endpoint, err)
+	}
+	if status != http.StatusOK {
+		msg := http.StatusText(status)
+		for _, s := range []string{resp.Error, resp.ErrorDescription} {
+			if s != "" {
+				msg += ": " + s
+			}
+		}
+		return "", fmt.Errorf("exchanging the OIDC token at %s: %s", endpoint, msg)
+	}
+	if resp.AccessToken == "" {
+		return "", fmt.Errorf("exchanging the OIDC token at %s: the response has no token", endpoint)
+	}
+	if resp.IssuedTokenType != "" && resp.IssuedTokenType != tokenTypeIDJWT && resp.IssuedTokenType != tokenTypeJWT {
+		return "", fmt.Errorf("exchanging the OIDC token at %s: issued a %s instead of an ID token", endpoint, resp.IssuedTokenType)
+	}
+	return resp.AccessToken, nil
+}
+
+func (e *Exchanger) tokenEndpoint(ctx context.Context) (string, error) {
+	u := strings.TrimSuffix(e.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	var config struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	status, err := e.do(req, &config)
+	if err != nil {
+		return "", fmt.Errorf("fetching the OpenID configuration of %s: %w", e.Issuer, err)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("fetching the OpenID configuration of %s: %s", e.Issuer, http.StatusText(status))
+	}
+	if config.TokenEndpoint == "" {
+		return "", fmt.Errorf("the OpenID configuration of %s has no token endpoint", e.Issuer)
+	}
+	return config.TokenEndpoint, nil
+}
+
+// do sends req and decodes the JSON response into v, unless the response
+// isn't JSON.
+func (e *Exchanger) do(req *http.Request, v interface{}) (int, error) {
+	client := e.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(b, v); err != nil && resp.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("parsing the response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// CheckAudience returns an error if the JWT token isn't issued for audience.
+// The signature of the token isn't verified, which Fulcio does.
+func CheckAudience(token, audience string) error {
+	var claims struct {
+		Audience json.RawMessage `json:"aud"`
+	}
+	if err := decodeClaims(token, &claims); err != nil {
+		return err
+	}
+	var audiences []string
+	if err := json.Unmarshal(claims.Audience, &audiences); err != nil {
+		var aud string
+		if err := json.Unmarshal(claims.Audience, &aud); err != nil {
+			return errors.New("the OIDC token has no audience")
+		}
+		audiences = []string{aud}
+	}
+	for _, aud := range audiences {
+		if aud == audience {
+			return nil
+		}
+	}
+	return fmt.Errorf("the OIDC token is issued for %s, not %s", strings.Join(audiences, ", "), audience)
+}
+
+// Expiry returns the expiry time of the JWT token, which is unverified like
+// with CheckAudience.
+func Expiry(token string) (time.Time, error) {
+	var claims struct {
+		Expiry int64 `json:"exp"`
+	}
+	if err := decodeClaims(token, &claims); err != nil {
+		return time.Time{}, err
+	}
+	if claims.Expiry == 0 {
+		return time.Time{}, errors.New("the OIDC token has no expiry")
+	}
+	return time.Unix(claims.Expiry, 0), nil
+}
+
+// decodeClaims decodes the claims of the JWT token into v.
+func decodeClaims(token string, v interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("the OIDC token isn't a JWT")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return fmt.Errorf("decoding the claims of the OIDC token: %w", err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("parsing the claims of the OIDC token: %w", err)
+	}
+	return nil
+}
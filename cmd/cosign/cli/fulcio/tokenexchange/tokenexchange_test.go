@@ -0,0 +1,126 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenexchange
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jwt returns an unsigned JWT with the claims.
+func jwt(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(claims)) + ".sig"
+}
+
+func newIssuer(t *testing.T, exchange http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": s.URL, "token_endpoint": s.URL + "/token"})
+	})
+	mux.HandleFunc("/token", exchange)
+	return s
+}
+
+func TestExchange(t *testing.T) {
+	ctx := context.Background()
+	ciToken := jwt(`{"aud":"https://ci.example.com"}`)
+	exchanged := jwt(`{"aud":"sigstore"}`)
+	s := newIssuer(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		id, secret, _ := r.BasicAuth()
+		if r.Form.Get("grant_type") != grantType || r.Form.Get("subject_token") != ciToken || r.Form.Get("audience") != "sigstore" ||
+			id != "cosign" || secret != "s3cr3t" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_request", "error_description": "unexpected request"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": exchanged, "issued_token_type": tokenTypeIDJWT, "token_type": "N_A"})
+	})
+
+	e := &Exchanger{Issuer: s.URL + "/", Audience: "sigstore", ClientID: "cosign", ClientSecret: "s3cr3t"}
+	got, err := e.Exchange(ctx, ciToken)
+	if err != nil {
+		t.Fatalf("Exchange() = %v", err)
+	}
+	if got != exchanged {
+		t.Errorf("Exchange() = %q, want %q", got, exchanged)
+	}
+
+	e.ClientSecret = "guess"
+	if _, err := e.Exchange(ctx, ciToken); err == nil || !strings.Contains(err.Error(), "invalid_request: unexpected request") {
+		t.Errorf("Exchange() with another secret = %v, want the error of the issuer", err)
+	}
+
+	other := newIssuer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "opaque", "issued_token_type": "urn:ietf:params:oauth:token-type:access_token"})
+	})
+	if _, err := (&Exchanger{Issuer: other.URL}).Exchange(ctx, ciToken); err == nil || !strings.Contains(err.Error(), "instead of an ID token") {
+		t.Errorf("Exchange() for an access token = %v, want an error", err)
+	}
+	if _, err := (&Exchanger{Issuer: other.URL + "/missing"}).Exchange(ctx, ciToken); err == nil || !strings.Contains(err.Error(), "OpenID configuration") {
+		t.Errorf("Exchange() without an OpenID configuration = %v, want an error", err)
+	}
+}
+
+func TestCheckAudience(t *testing.T) {
+	for name, tc := range map[string]struct {
+		token string
+		want  string
+	}{
+		"audience":          {token: jwt(`{"aud":"sigstore"}`)},
+		"one of audiences":  {token: jwt(`{"aud":["ci","sigstore"]}`)},
+		"another audience":  {token: jwt(`{"aud":["ci"]}`), want: "issued for ci, not sigstore"},
+		"no audience":       {token: jwt(`{"sub":"job"}`), want: "has no audience"},
+		"not a jwt":         {token: "opaque", want: "isn't a JWT"},
+		"invalid claims":    {token: "a.b.c", want: "claims"},
+		"padded base64 url": {token: strings.Replace(jwt(`{"aud":"sigstore"}`), ".sig", "", 1) + "==.sig"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := CheckAudience(tc.token, "sigstore")
+			if tc.want == "" && err != nil {
+				t.Errorf("CheckAudience() = %v", err)
+			}
+			if tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)) {
+				t.Errorf("CheckAudience() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	got, err := Expiry(jwt(`{"aud":"sigstore","exp":1700000000}`))
+	if err != nil || !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expiry() = %v, %v, want the exp claim", got, err)
+	}
+	if _, err := Expiry(jwt(`{"aud":"sigstore"}`)); err == nil || !strings.Contains(err.Error(), "no expiry") {
+		t.Errorf("Expiry() without exp = %v", err)
+	}
+	if _, err := Expiry("opaque"); err == nil {
+		t.Error("Expiry() of an opaque token: expected an error")
+	}
+}
@@ -40,7 +40,15 @@ to sign payloads with your own tooling or algorithms.`,
   cosign generate -a foo=bar <IMAGE>
 
   # Use this payload in another tool
-  gpg --output image.sig --detach-sig <(cosign generate <IMAGE>)`,
+  gpg --output image.sig --detach-sig <(cosign generate <IMAGE>)
+
+  # Generate a payload for an image digest without looking it up in the registry
+  cosign generate --offline --digest sha256:<DIGEST> <IMAGE>
+
+  # Record the digests of images on an online machine, then generate their
+  # payloads on an offline machine with a copy of the file
+  cosign generate --digest-cache digests.json <IMAGE>
+  cosign generate --offline --digest-cache digests.json <IMAGE>`,
 
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
@@ -49,7 +57,7 @@ to sign payloads with your own tooling or algorithms.`,
 			if err != nil {
 				return err
 			}
-			return generate.GenerateCmd(cmd.Context(), o.Registry, args[0], annotationMap.Annotations, cmd.OutOrStdout())
+			return generate.GenerateCmd(cmd.Context(), *o, args[0], annotationMap.Annotations, cmd.OutOrStdout())
 		},
 	}
 
@@ -0,0 +1,63 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// digestCache is the --digest-cache file of `cosign generate`: the digests
+// of image references, by their fully qualified names, looked up on an online
+// machine so that payloads can be generated offline.
+type digestCache struct {
+	Digests map[string]string `json:"digests"`
+}
+
+// loadDigestCache reads the digest cache at path, which is empty if there is
+// no file yet.
+func loadDigestCache(path string) (*digestCache, error) {
+	c := &digestCache{}
+	b, err := os.ReadFile(filepath.Clean(path))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading digest cache: %w", err)
+	default:
+		if err := json.Unmarshal(b, c); err != nil {
+			return nil, fmt.Errorf("parsing digest cache %s: %w", path, err)
+		}
+	}
+	if c.Digests == nil {
+		c.Digests = map[string]string{}
+	}
+	return c, nil
+}
+
+func (c *digestCache) save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing digest cache: %w", err)
+	}
+	return nil
+}
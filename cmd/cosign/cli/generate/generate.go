@@ -22,21 +22,18 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/sigstore/pkg/signature/payload"
 )
 
 // nolint
-func GenerateCmd(ctx context.Context, regOpts options.RegistryOptions, imageRef string, annotations map[string]interface{}, w io.Writer) error {
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+func GenerateCmd(ctx context.Context, o options.GenerateOptions, imageRef string, annotations map[string]interface{}, w io.Writer) error {
+	ref, err := oci.ParseReference(imageRef, o.Registry.NameOptions()...)
 	if err != nil {
 		return err
 	}
-	ociremoteOpts, err := regOpts.ClientOpts(ctx)
-	if err != nil {
-		return err
-	}
-	digest, err := ociremote.ResolveDigest(ref, ociremoteOpts...)
+	digest, err := resolveDigest(ctx, o, ref)
 	if err != nil {
 		return err
 	}
@@ -52,3 +49,66 @@ func GenerateCmd(ctx context.Context, regOpts options.RegistryOptions, imageRef
 	fmt.Fprintln(w, string(json))
 	return nil
 }
+
+// resolveDigest returns the digest of ref: ref itself if it is a digest, the
+// --digest of o, the entry of ref in the --digest-cache of o, or, unless o is
+// offline, the digest looked up in the registry, which is then added to the
+// cache.
+func resolveDigest(ctx context.Context, o options.GenerateOptions, ref name.Reference) (name.Digest, error) {
+	if o.Digest != "" {
+		digest, err := withDigest(ref, o.Digest)
+		if err != nil {
+			return name.Digest{}, fmt.Errorf("invalid --digest: %w", err)
+		}
+		if d, ok := ref.(name.Digest); ok && d.DigestStr() != digest.DigestStr() {
+			return name.Digest{}, fmt.Errorf("--digest %s isn't the digest of %s", o.Digest, ref)
+		}
+		return digest, nil
+	}
+	if d, ok := ref.(name.Digest); ok {
+		return d, nil
+	}
+
+	var cache *digestCache
+	if o.DigestCache != "" {
+		var err error
+		if cache, err = loadDigestCache(o.DigestCache); err != nil {
+			return name.Digest{}, err
+		}
+		if cached, ok := cache.Digests[ref.Name()]; ok {
+			digest, err := withDigest(ref, cached)
+			if err != nil {
+				return name.Digest{}, fmt.Errorf("invalid digest of %s in %s: %w", ref.Name(), o.DigestCache, err)
+			}
+			return digest, nil
+		}
+	}
+	if o.Offline {
+		return name.Digest{}, fmt.Errorf("the digest of %s is needed offline: use a digest, --digest or a --digest-cache with %s", ref, ref.Name())
+	}
+
+	ociremoteOpts, err := o.Registry.ClientOpts(ctx)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	digest, err := ociremote.ResolveDigest(ref, ociremoteOpts...)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	if cache != nil {
+		cache.Digests[ref.Name()] = digest.DigestStr()
+		if err := cache.save(o.DigestCache); err != nil {
+			return name.Digest{}, err
+		}
+	}
+	return digest, nil
+}
+
+// withDigest returns the digest of the repository of ref.
+func withDigest(ref name.Reference, digest string) (name.Digest, error) {
+	h, err := oci.ParseDigest(digest)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	return ref.Context().Digest(h.String()), nil
+}
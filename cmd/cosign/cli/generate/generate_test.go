@@ -0,0 +1,129 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+func TestGenerateCmdDigests(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := name.NewTag(host + "/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(tag, img); err != nil {
+		t.Fatal(err)
+	}
+
+	generated := func(t *testing.T, o options.GenerateOptions, image string) (string, error) {
+		t.Helper()
+		var b bytes.Buffer
+		if err := GenerateCmd(ctx, o, image, nil, &b); err != nil {
+			return "", err
+		}
+		sci := payload.SimpleContainerImage{}
+		if err := json.Unmarshal(b.Bytes(), &sci); err != nil {
+			t.Fatal(err)
+		}
+		return sci.Critical.Image.DockerManifestDigest, nil
+	}
+
+	// The digest is looked up online and recorded in the cache.
+	cachePath := filepath.Join(t.TempDir(), "digests.json")
+	if got, err := generated(t, options.GenerateOptions{DigestCache: cachePath}, tag.String()); err != nil || got != h.String() {
+		t.Fatalf("GenerateCmd() = %s, %v, want %s", got, err, h)
+	}
+	cache, err := loadDigestCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.Digests[tag.Name()]; got != h.String() {
+		t.Errorf("cached digest of %s = %q, want %s", tag.Name(), got, h)
+	}
+
+	// Offline, the registry isn't needed.
+	s.Close()
+	for name, o := range map[string]options.GenerateOptions{
+		"cache":  {DigestCache: cachePath, Offline: true},
+		"digest": {Digest: h.String(), Offline: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got, err := generated(t, o, tag.String()); err != nil || got != h.String() {
+				t.Errorf("GenerateCmd() = %s, %v, want %s", got, err, h)
+			}
+		})
+	}
+	if got, err := generated(t, options.GenerateOptions{Offline: true}, tag.Context().Digest(h.String()).String()); err != nil || got != h.String() {
+		t.Errorf("GenerateCmd() of a digest = %s, %v, want %s", got, err, h)
+	}
+
+	other := "sha256:" + strings.Repeat("0", 64)
+	for name, tc := range map[string]struct {
+		o     options.GenerateOptions
+		image string
+		want  string
+	}{
+		"not cached": {
+			o:     options.GenerateOptions{DigestCache: cachePath, Offline: true},
+			image: host + "/app:v2",
+			want:  "is needed offline",
+		},
+		"invalid digest": {
+			o:     options.GenerateOptions{Digest: "sha256:abc", Offline: true},
+			image: tag.String(),
+			want:  "invalid --digest",
+		},
+		"another digest": {
+			o:     options.GenerateOptions{Digest: other, Offline: true},
+			image: tag.Context().Digest(h.String()).String(),
+			want:  "isn't the digest of",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := generated(t, tc.o, tc.image); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("GenerateCmd() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	gcpkms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+// remoteKeyProvider creates the keys of a KMS provider for --create-remote,
+// and describes the access that signing and verifying with them need.
+type remoteKeyProvider struct {
+	// create creates the key of ref, missing from k, with the recommended
+	// algorithm and protection level of the provider.
+	create func(ctx context.Context, k kms.SignerVerifier, ref string) (crypto.PublicKey, error)
+	// policy returns the minimal IAM policies, or the commands granting
+	// them, that sign with the key of ref and verify with it.
+	policy func(ref string) (string, error)
+}
+
+var remoteKeyProviders = map[string]remoteKeyProvider{
+	// Asymmetric AWS KMS keys are always protected by its HSMs.
+	"awskms":     {create: createDefaultKey, policy: awsPolicy},
+	"gcpkms":     {create: createGCPKey, policy: gcpPolicy},
+	"azurekms":   {create: createDefaultKey, policy: azurePolicy},
+	"hashivault": {create: createDefaultKey, policy: hashivaultPolicy},
+}
+
+// CreateRemoteKeyCmd creates the key of kmsVal, unless it exists, with the
+// recommended algorithm and protection level of its KMS, writes its public
+// key to <outputKeyPrefix>.pub and writes the minimal IAM policies that
+// signing with it and verifying with it need to out.
+func CreateRemoteKeyCmd(ctx context.Context, out io.Writer, kmsVal, outputKeyPrefix string) error {
+	scheme, _, _ := strings.Cut(kmsVal, "://")
+	p, ok := remoteKeyProviders[scheme]
+	if !ok {
+		return fmt.Errorf("--create-remote supports awskms://, gcpkms://, azurekms:// and hashivault:// keys, not %q", kmsVal)
+	}
+	policy, err := p.policy(kmsVal)
+	if err != nil {
+		return err
+	}
+	k, err := kms.Get(ctx, kmsVal, crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	pubKey, err := k.PublicKey(options.WithContext(ctx))
+	if err == nil {
+		ui.Infof(ctx, "Key %s already exists, it isn't created", kmsVal)
+	} else {
+		ui.Infof(ctx, "Creating key %s", kmsVal)
+		if pubKey, err = p.create(ctx, k, kmsVal); err != nil {
+			return fmt.Errorf("creating key: %w", err)
+		}
+	}
+	pemBytes, err := cryptoutils.MarshalPublicKeyToPEM(pubKey)
+	if err != nil {
+		return err
+	}
+	publicKeyFileName := outputKeyPrefix + ".pub"
+	if err := os.WriteFile(publicKeyFileName, pemBytes, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Public key written to", publicKeyFileName)
+	_, err = io.WriteString(out, policy)
+	return err
+}
+
+// createDefaultKey creates a key with the default algorithm of k, ECDSA
+// P-256.
+func createDefaultKey(ctx context.Context, k kms.SignerVerifier, _ string) (crypto.PublicKey, error) {
+	return k.CreateKey(ctx, k.DefaultAlgorithm())
+}
+
+var gcpKeyRE = regexp.MustCompile(`^gcpkms://projects/([^/]+)/locations/([^/]+)/keyRings/([^/]+)/cryptoKeys/([^/]+)(?:/(?:cryptoKeyVersions|versions)/([^/]+))?$`)
+
+// gcpKey is the key of a gcpkms:// reference.
+type gcpKey struct {
+	Project, Location, KeyRing, Key string
+}
+
+func parseGCPKey(ref string) (*gcpKey, error) {
+	m := gcpKeyRE.FindStringSubmatch(ref)
+	if m == nil {
+		return nil, fmt.Errorf("invalid gcpkms reference %q, expected gcpkms://projects/<PROJECT>/locations/<LOCATION>/keyRings/<KEYRING>/cryptoKeys/<KEY>", ref)
+	}
+	return &gcpKey{Project: m[1], Location: m[2], KeyRing: m[3], Key: m[4]}, nil
+}
+
+func (g *gcpKey) keyRingName() string {
+	return fmt.Sprintf("projects/%s/locations/%s/keyRings/%s", g.Project, g.Location, g.KeyRing)
+}
+
+// createGCPKey creates an ECDSA P-256 key protected by Cloud HSM, and its
+// key ring if needed, and waits for its first version to be generated.
+func createGCPKey(ctx context.Context, k kms.SignerVerifier, ref string) (crypto.PublicKey, error) {
+	g, err := parseGCPKey(ref)
+	if err != nil {
+		return nil, err
+	}
+	client, err := gcpkms.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if _, err := client.GetKeyRing(ctx, &kmspb.GetKeyRingRequest{Name: g.keyRingName()}); status.Code(err) == codes.NotFound {
+		if _, err := client.CreateKeyRing(ctx, &kmspb.CreateKeyRingRequest{
+			Parent:    fmt.Sprintf("projects/%s/locations/%s", g.Project, g.Location),
+			KeyRingId: g.KeyRing,
+		}); err != nil {
+			return nil, fmt.Errorf("creating key ring: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("looking up key ring: %w", err)
+	}
+	key, err := client.CreateCryptoKey(ctx, &kmspb.CreateCryptoKeyRequest{
+		Parent:      g.keyRingName(),
+		CryptoKeyId: g.Key,
+		CryptoKey: &kmspb.CryptoKey{
+			Purpose: kmspb.CryptoKey_ASYMMETRIC_SIGN,
+			VersionTemplate: &kmspb.CryptoKeyVersionTemplate{
+				Algorithm:       kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256,
+				ProtectionLevel: kmspb.ProtectionLevel_HSM,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// HSM keys are generated asynchronously.
+	for {
+		v, err := client.GetCryptoKeyVersion(ctx, &kmspb.GetCryptoKeyVersionRequest{Name: key.GetName() + "/cryptoKeyVersions/1"})
+		if err != nil {
+			return nil, err
+		}
+		if v.GetState() != kmspb.CryptoKeyVersion_PENDING_GENERATION {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+	return k.PublicKey(options.WithContext(ctx))
+}
+
+var gcpPolicyTemplate = template.Must(template.New("gcp").Parse(`# Signing: grant the identity that signs, e.g. serviceAccount:<EMAIL>
+gcloud kms keys add-iam-policy-binding {{.Key}} --project {{.Project}} --location {{.Location}} --keyring {{.KeyRing}} \
+  --member <MEMBER> --role roles/cloudkms.signerVerifier
+gcloud kms keys add-iam-policy-binding {{.Key}} --project {{.Project}} --location {{.Location}} --keyring {{.KeyRing}} \
+  --member <MEMBER> --role roles/cloudkms.viewer
+
+# Verification: grant the identity that verifies
+gcloud kms keys add-iam-policy-binding {{.Key}} --project {{.Project}} --location {{.Location}} --keyring {{.KeyRing}} \
+  --member <MEMBER> --role roles/cloudkms.publicKeyViewer
+gcloud kms keys add-iam-policy-binding {{.Key}} --project {{.Project}} --location {{.Location}} --keyring {{.KeyRing}} \
+  --member <MEMBER> --role roles/cloudkms.viewer
+`))
+
+func gcpPolicy(ref string) (string, error) {
+	g, err := parseGCPKey(ref)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := gcpPolicyTemplate.Execute(&sb, g); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+var awsAliasRE = regexp.MustCompile(`^awskms://[^/]*/(?:arn:(aws|aws-us-gov):kms:([a-z0-9-]+):(\d{12}):)?(alias/.+)$`)
+
+var awsPolicyTemplate = template.Must(template.New("aws").Parse(`# Signing: attach to the IAM role or user that signs
+{
+  "Version": "2012-10-17",
+  "Statement": [{
+    "Effect": "Allow",
+    "Action": ["kms:Sign", "kms:GetPublicKey", "kms:DescribeKey"],
+    "Resource": "{{.Resource}}",
+    "Condition": {"ForAnyValue:StringEquals": {"kms:ResourceAliases": "{{.Alias}}"}}
+  }]
+}
+
+# Verification: attach to the IAM role or user that verifies
+{
+  "Version": "2012-10-17",
+  "Statement": [{
+    "Effect": "Allow",
+    "Action": ["kms:GetPublicKey", "kms:DescribeKey"],
+    "Resource": "{{.Resource}}",
+    "Condition": {"ForAnyValue:StringEquals": {"kms:ResourceAliases": "{{.Alias}}"}}
+  }]
+}
+`))
+
+// awsPolicy returns the IAM policies of the key of an alias, which the key
+// is created with, allowing the key that the alias names.
+func awsPolicy(ref string) (string, error) {
+	m := awsAliasRE.FindStringSubmatch(ref)
+	if m == nil {
+		return "", fmt.Errorf("--create-remote needs an alias, awskms:///alias/<NAME> or awskms:///arn:aws:kms:<REGION>:<ACCOUNT>:alias/<NAME>, not %q", ref)
+	}
+	partition, region, account := m[1], m[2], m[3]
+	if partition == "" {
+		partition, region, account = "aws", "*", "*"
+	}
+	var sb strings.Builder
+	err := awsPolicyTemplate.Execute(&sb, map[string]string{
+		"Resource": fmt.Sprintf("arn:%s:kms:%s:%s:key/*", partition, region, account),
+		"Alias":    m[4],
+	})
+	return sb.String(), err
+}
+
+var azureKeyRE = regexp.MustCompile(`^azurekms://([^/.]+)[^/]*/([^/]+)$`)
+
+func azurePolicy(ref string) (string, error) {
+	m := azureKeyRE.FindStringSubmatch(ref)
+	if m == nil {
+		return "", fmt.Errorf("invalid azurekms reference %q, expected azurekms://<VAULT_NAME>.vault.azure.net/<KEY>", ref)
+	}
+	return fmt.Sprintf(`# Signing: grant the identity that signs, with the access policies of the vault
+az keyvault set-policy --name %[1]s --object-id <OBJECT_ID> --key-permissions get sign
+
+# Verification: grant the identity that verifies
+az keyvault set-policy --name %[1]s --object-id <OBJECT_ID> --key-permissions get verify
+`, m[1]), nil
+}
+
+var hashivaultKeyRE = regexp.MustCompile(`^hashivault://([\w.-]+)$`)
+
+// hashivaultPolicy returns the Vault policies of the key, in the transit
+// secrets engine at $TRANSIT_SECRET_ENGINE_PATH, as the KMS reads it.
+func hashivaultPolicy(ref string) (string, error) {
+	m := hashivaultKeyRE.FindStringSubmatch(ref)
+	if m == nil {
+		return "", errors.New("invalid hashivault reference, expected hashivault://<KEY>")
+	}
+	path := os.Getenv("TRANSIT_SECRET_ENGINE_PATH")
+	if path == "" {
+		path = "transit"
+	}
+	return fmt.Sprintf(`# Signing: the policy of the token that signs
+path "%[1]s/keys/%[2]s" {
+  capabilities = ["read"]
+}
+path "%[1]s/sign/%[2]s/*" {
+  capabilities = ["update"]
+}
+
+# Verification: the policy of the token that verifies
+path "%[1]s/keys/%[2]s" {
+  capabilities = ["read"]
+}
+path "%[1]s/verify/%[2]s/*" {
+  capabilities = ["update"]
+}
+`, path, m[1]), nil
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRemoteKeyPolicies(t *testing.T) {
+	t.Setenv("TRANSIT_SECRET_ENGINE_PATH", "cosign-transit")
+	for _, tc := range []struct {
+		ref  string
+		want []string
+	}{{
+		ref: "gcpkms://projects/p/locations/global/keyRings/r/cryptoKeys/k",
+		want: []string{
+			"gcloud kms keys add-iam-policy-binding k --project p --location global --keyring r \\\n  --member <MEMBER> --role roles/cloudkms.signerVerifier",
+			"--role roles/cloudkms.publicKeyViewer",
+		},
+	}, {
+		ref:  "awskms:///alias/release",
+		want: []string{`"Action": ["kms:Sign", "kms:GetPublicKey", "kms:DescribeKey"]`, `"Resource": "arn:aws:kms:*:*:key/*"`, `"kms:ResourceAliases": "alias/release"`},
+	}, {
+		ref:  "awskms://kms.us-east-1.amazonaws.com/arn:aws:kms:us-east-1:123456789012:alias/release",
+		want: []string{`"Resource": "arn:aws:kms:us-east-1:123456789012:key/*"`},
+	}, {
+		ref:  "azurekms://release.vault.azure.net/signing",
+		want: []string{"az keyvault set-policy --name release --object-id <OBJECT_ID> --key-permissions get sign", "--key-permissions get verify"},
+	}, {
+		ref:  "hashivault://release",
+		want: []string{`path "cosign-transit/sign/release/*"`, `path "cosign-transit/verify/release/*"`},
+	}} {
+		scheme, _, _ := strings.Cut(tc.ref, "://")
+		got, err := remoteKeyProviders[scheme].policy(tc.ref)
+		if err != nil {
+			t.Errorf("policy(%q) = %v", tc.ref, err)
+			continue
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("policy(%q) = %s, want it to contain %s", tc.ref, got, want)
+			}
+		}
+	}
+}
+
+func TestCreateRemoteKeyCmdErrors(t *testing.T) {
+	for _, ref := range []string{
+		"k8s://default/cosign",
+		"awskms:///1234abcd-12ab-34cd-56ef-1234567890ab",
+		"gcpkms://projects/p/keyRings/r",
+		"azurekms://release.vault.azure.net",
+		"hashivault://release/key",
+	} {
+		if err := CreateRemoteKeyCmd(context.Background(), io.Discard, ref, "cosign"); err == nil {
+			t.Errorf("CreateRemoteKeyCmd(%q): expected an error", ref)
+		}
+	}
+}
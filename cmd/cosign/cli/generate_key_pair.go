@@ -16,6 +16,8 @@
 package cli
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
@@ -28,7 +30,12 @@ func GenerateKeyPair() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "generate-key-pair",
 		Short: "Generates a key-pair.",
-		Long:  "Generates a key-pair for signing.",
+		Long: `Generates a key-pair for signing.
+
+With --create-remote, the --kms key is created unless it exists, with ECDSA
+P-256 and, in Google Cloud KMS, Cloud HSM protection, its public key is
+written, and the minimal IAM policies, or the commands granting them, that
+signing and verifying with it need are printed.`,
 		Example: `  cosign generate-key-pair [--kms KMSPATH]
 
   # generate key-pair and write to cosign.key and cosign.pub files
@@ -46,6 +53,9 @@ func GenerateKeyPair() *cobra.Command {
   # generate a key-pair in Google Cloud KMS
   cosign generate-key-pair --kms gcpkms://projects/[PROJECT]/locations/global/keyRings/[KEYRING]/cryptoKeys/[KEY]
 
+  # create a key in Google Cloud KMS protected by Cloud HSM, unless it exists, and print the IAM bindings to sign and verify with it
+  cosign generate-key-pair --create-remote --kms gcpkms://projects/[PROJECT]/locations/global/keyRings/[KEYRING]/cryptoKeys/[KEY]
+
   # generate a key-pair in Hashicorp Vault
   cosign generate-key-pair --kms hashivault://[KEY]
 
@@ -67,6 +77,12 @@ CAVEATS:
 
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.CreateRemote {
+				if o.KMS == "" {
+					return errors.New("--create-remote requires --kms")
+				}
+				return generate.CreateRemoteKeyCmd(cmd.Context(), cmd.OutOrStdout(), o.KMS, o.OutputKeyPrefix)
+			}
 			return generate.GenerateKeyPairCmd(cmd.Context(), o.KMS, o.OutputKeyPrefix, args)
 		},
 	}
@@ -28,7 +28,13 @@ func ImportKeyPair() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import-key-pair",
 		Short: "Imports a PEM-encoded RSA or EC private key.",
-		Long:  "Imports a PEM-encoded RSA or EC private key for signing.",
+		Long: `Imports a PEM-encoded RSA or EC private key for signing.
+
+The key may also be a PKCS #12 bundle, the JSON output of aws kms
+generate-data-key-pair, or key material wrapped with the RSA AES key wrap
+scheme of the AWS and GCP KMS key imports, which --wrapping-key unwraps. The
+fingerprint of the key is shown, and confirmed unless --yes is set, before it
+is encrypted with the password of the cosign key pair.`,
 		Example: `  cosign import-key-pair  --key openssl.key --output-key-prefix my-key
 
   # import PEM-encoded RSA or EC private key and write to import-cosign.key and import-cosign.pub files
@@ -37,12 +43,23 @@ func ImportKeyPair() *cobra.Command {
   # import PEM-encoded RSA or EC private key and write to my-key.key and my-key.pub files
   cosign import-key-pair --key <key path> --output-key-prefix my-key
 
+  # import the private key of a PKCS #12 bundle, whose password is read from COSIGN_PKCS12_PASSWORD or the terminal
+  cosign import-key-pair --key bundle.p12
+
+  # import a data key pair generated by AWS KMS
+  aws kms generate-data-key-pair --key-id <key id> --key-pair-spec ECC_NIST_P256 > data-key-pair.json
+  cosign import-key-pair --key data-key-pair.json
+
+  # import key material wrapped for a GCP KMS import job with RSA_OAEP_3072_SHA1_AES_256
+  cosign import-key-pair --key wrapped.key --format rsa-aes-key-wrap --wrapping-key wrapping.pem --wrapping-hash sha1
+
 CAVEATS:
   This command interactively prompts for a password. You can use
-  the COSIGN_PASSWORD environment variable to provide one.`,
+  the COSIGN_PASSWORD environment variable to provide one.
+  Confirmations are skipped if standard input isn't a terminal.`,
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return importkeypair.ImportKeyPairCmd(cmd.Context(), o.Key, o.OutputKeyPrefix, args)
+			return importkeypair.ImportKeyPairCmd(cmd.Context(), *o, args)
 		},
 	}
 
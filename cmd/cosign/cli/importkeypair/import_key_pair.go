@@ -17,14 +17,21 @@ package importkeypair
 
 import (
 	"context"
+	"crypto"
+	"crypto/rsa"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"syscall"
 
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	icos "github.com/sigstore/cosign/v2/internal/pkg/cosign"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"golang.org/x/term"
 )
 
 var (
@@ -33,14 +40,35 @@ var (
 )
 
 // nolint
-func ImportKeyPairCmd(ctx context.Context, keyVal string, outputKeyPrefixVal string, args []string) error {
-	keys, err := cosign.ImportKeyPair(keyVal, GetPass)
+func ImportKeyPairCmd(ctx context.Context, o options.ImportKeyPairOptions, args []string) error {
+	opts, err := importOpts(o)
+	if err != nil {
+		return err
+	}
+	pk, err := cosign.ImportPrivateKey(o.Key, opts)
+	if err != nil {
+		return err
+	}
+
+	// Show what is imported before asking for the password of the key pair.
+	fingerprint, err := cosign.KeyFingerprint(pk.Public())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Importing %s private key with fingerprint %s\n", cosign.KeyDescription(pk.Public()), fingerprint)
+	if !o.SkipConfirmation && cosign.IsTerminal() {
+		if err := ui.ConfirmContinue(ctx); err != nil {
+			return err
+		}
+	}
+
+	keys, err := cosign.EncryptImportedKey(pk, GetPass)
 	if err != nil {
 		return err
 	}
 
-	privateKeyFileName := outputKeyPrefixVal + ".key"
-	publicKeyFileName := outputKeyPrefixVal + ".pub"
+	privateKeyFileName := o.OutputKeyPrefix + ".key"
+	publicKeyFileName := o.OutputKeyPrefix + ".pub"
 
 	fileExists, err := icos.FileExists(privateKeyFileName)
 	if err != nil {
@@ -66,6 +94,53 @@ func ImportKeyPairCmd(ctx context.Context, keyVal string, outputKeyPrefixVal str
 	return nil
 }
 
+// importOpts loads the wrapping key of o.
+func importOpts(o options.ImportKeyPairOptions) (cosign.ImportOpts, error) {
+	opts := cosign.ImportOpts{Format: o.Format, PKCS12Password: getPKCS12Pass}
+	switch o.WrappingHash {
+	case "", "sha256":
+		opts.WrappingHash = crypto.SHA256
+	case "sha1":
+		opts.WrappingHash = crypto.SHA1
+	default:
+		return opts, fmt.Errorf("unsupported --wrapping-hash %s, expected sha1 or sha256", o.WrappingHash)
+	}
+	if o.WrappingKey == "" {
+		return opts, nil
+	}
+	raw, err := os.ReadFile(filepath.Clean(o.WrappingKey))
+	if err != nil {
+		return opts, fmt.Errorf("reading wrapping key: %w", err)
+	}
+	pk, err := cryptoutils.UnmarshalPEMToPrivateKey(raw, cryptoutils.SkipPassword)
+	if err != nil {
+		return opts, fmt.Errorf("parsing wrapping key: %w", err)
+	}
+	rsaPk, ok := pk.(*rsa.PrivateKey)
+	if !ok {
+		return opts, fmt.Errorf("the wrapping key is a %T, not an RSA private key", pk)
+	}
+	opts.WrappingKey = rsaPk
+	return opts, nil
+}
+
+// getPKCS12Pass returns the password of a PKCS #12 bundle, from
+// COSIGN_PKCS12_PASSWORD or else the terminal.
+func getPKCS12Pass(_ bool) ([]byte, error) {
+	if pw, ok := env.LookupEnv(env.VariablePKCS12Password); ok {
+		return []byte(pw), nil
+	}
+	if !cosign.IsTerminal() {
+		return nil, fmt.Errorf("the PKCS #12 bundle is encrypted, set %s to its password", env.VariablePKCS12Password)
+	}
+	fmt.Fprint(os.Stderr, "Enter password for PKCS #12 bundle: ")
+	// Unnecessary convert of syscall.Stdin on *nix, but Windows is a uintptr
+	// nolint:unconvert
+	pw, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintln(os.Stderr)
+	return pw, err
+}
+
 func GetPass(confirm bool) ([]byte, error) {
 	read := Read(confirm)
 	return read()
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tufcache"
 	"github.com/sigstore/cosign/v2/pkg/blob"
 	"github.com/sigstore/sigstore/pkg/tuf"
 )
@@ -36,6 +37,11 @@ func DoInitialize(ctx context.Context, root, mirror string) error {
 		}
 	}
 
+	unlock, err := tufcache.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	if err := tuf.Initialize(ctx, mirror, rootFileBytes); err != nil {
 		return err
 	}
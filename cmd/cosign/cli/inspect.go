@@ -0,0 +1,51 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/inspect"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func Inspect() *cobra.Command {
+	o := &options.InspectOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Show the signatures, attestations, certificates and transparency log entries of an image",
+		Long: `Show the signatures, attestations, certificates and transparency log entries of an image.
+
+The contents are shown without being verified. Use 'cosign verify' and
+'cosign verify-attestation' to verify them.`,
+		Example: `  cosign inspect <IMAGE>
+
+  # browse the image's artifacts in a terminal UI
+  cosign inspect --interactive <IMAGE>
+
+  # also write the references and digests found to refs.json, e.g. to verify exactly what was inspected
+  cosign inspect --export-refs refs.json <IMAGE>
+  cosign verify --key cosign.pub $(jq -r .reference refs.json)`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return inspect.InspectCmd(cmd.Context(), o.Registry, args[0], o.Interactive, o.ExportRefs)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+)
+
+// ExportedRefs are the references and digests written by inspect
+// --export-refs, so that follow-up commands can operate on exactly what was
+// inspected.
+type ExportedRefs struct {
+	// Image is the reference as given, and Reference the same image by
+	// digest.
+	Image          string          `json:"image"`
+	Digest         string          `json:"digest"`
+	Reference      string          `json:"reference"`
+	SignatureTag   string          `json:"signatureTag"`
+	AttestationTag string          `json:"attestationTag"`
+	Signatures     []ExportedEntry `json:"signatures"`
+	Attestations   []ExportedEntry `json:"attestations"`
+}
+
+// ExportedEntry is a signature or attestation layer.
+type ExportedEntry struct {
+	Digest             string   `json:"digest"`
+	PredicateType      string   `json:"predicateType,omitempty"`
+	Subjects           []string `json:"subjects,omitempty"`
+	CertificateSubject string   `json:"certificateSubject,omitempty"`
+	RekorLogIndex      *int64   `json:"rekorLogIndex,omitempty"`
+}
+
+func newExportedRefs(image string, digest name.Digest, signatures, attestations []oci.Signature, opts ...ociremote.Option) (*ExportedRefs, error) {
+	sigTag, err := ociremote.SignatureTag(digest, opts...)
+	if err != nil {
+		return nil, err
+	}
+	attTag, err := ociremote.AttestationTag(digest, opts...)
+	if err != nil {
+		return nil, err
+	}
+	refs := &ExportedRefs{
+		Image:          image,
+		Digest:         digest.DigestStr(),
+		Reference:      digest.String(),
+		SignatureTag:   sigTag.String(),
+		AttestationTag: attTag.String(),
+		Signatures:     []ExportedEntry{},
+		Attestations:   []ExportedEntry{},
+	}
+	for _, sig := range signatures {
+		e, err := exportedEntry(sig)
+		if err != nil {
+			return nil, err
+		}
+		refs.Signatures = append(refs.Signatures, e)
+	}
+	for _, att := range attestations {
+		e, err := exportedEntry(att)
+		if err != nil {
+			return nil, err
+		}
+		p, err := att.Payload()
+		if err != nil {
+			return nil, err
+		}
+		var env struct {
+			Payload string `json:"payload"`
+		}
+		if json.Unmarshal(p, &env) == nil {
+			if st, ok := statementHeader(env.Payload); ok {
+				e.PredicateType = st.PredicateType
+				for _, s := range st.Subject {
+					for alg, hex := range s.Digest {
+						e.Subjects = append(e.Subjects, s.Name+"@"+alg+":"+hex)
+					}
+				}
+			}
+		}
+		refs.Attestations = append(refs.Attestations, e)
+	}
+	return refs, nil
+}
+
+func exportedEntry(sig oci.Signature) (ExportedEntry, error) {
+	d, err := sig.Digest()
+	if err != nil {
+		return ExportedEntry{}, err
+	}
+	e := ExportedEntry{Digest: d.String()}
+	cert, err := sig.Cert()
+	if err != nil {
+		return ExportedEntry{}, err
+	}
+	if cert != nil {
+		e.CertificateSubject = sigs.CertSubject(cert)
+	}
+	b, err := sig.Bundle()
+	if err != nil {
+		return ExportedEntry{}, err
+	}
+	if b != nil {
+		logIndex := b.Payload.LogIndex
+		e.RekorLogIndex = &logIndex
+	}
+	return e, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+func TestNewExportedRefs(t *testing.T) {
+	sig, att := testArtifacts(t)
+	digest, err := name.NewDigest("example.com/app@" + imageDigest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := newExportedRefs("example.com/app:latest", digest, []oci.Signature{sig}, []oci.Signature{att})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagPrefix := "example.com/app:" + strings.Replace(imageDigest, ":", "-", 1)
+	if refs.Reference != "example.com/app@"+imageDigest || refs.Digest != imageDigest || refs.Image != "example.com/app:latest" {
+		t.Errorf("unexpected image references %+v", refs)
+	}
+	if refs.SignatureTag != tagPrefix+".sig" || refs.AttestationTag != tagPrefix+".att" {
+		t.Errorf("tags = %s, %s, want %s.sig and .att", refs.SignatureTag, refs.AttestationTag, tagPrefix)
+	}
+
+	if len(refs.Signatures) != 1 || len(refs.Attestations) != 1 {
+		t.Fatalf("got %d signatures and %d attestations, want 1 each", len(refs.Signatures), len(refs.Attestations))
+	}
+	s := refs.Signatures[0]
+	if d, _ := sig.Digest(); s.Digest != d.String() {
+		t.Errorf("signature digest = %s, want %s", s.Digest, d)
+	}
+	if s.CertificateSubject != "subject@example.com" || s.RekorLogIndex == nil || *s.RekorLogIndex != 42 {
+		t.Errorf("unexpected signature entry %+v", s)
+	}
+	a := refs.Attestations[0]
+	if a.PredicateType != "https://slsa.dev/provenance/v0.2" || len(a.Subjects) != 1 || a.Subjects[0] != "example.com/app@sha256:abc" {
+		t.Errorf("unexpected attestation entry %+v", a)
+	}
+}
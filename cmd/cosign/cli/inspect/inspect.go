@@ -0,0 +1,319 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+// Node is an entry in the inspection tree. Value, if set, is the part of the
+// entry that can be copied (e.g. a digest).
+type Node struct {
+	Label    string
+	Value    string
+	Children []*Node
+
+	expanded bool
+}
+
+func (n *Node) add(label, value string) *Node {
+	c := &Node{Label: label, Value: value}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// String renders the node as it is displayed in the tree.
+func (n *Node) String() string {
+	if n.Value == "" {
+		return n.Label
+	}
+	return fmt.Sprintf("%s: %s", n.Label, n.Value)
+}
+
+// InspectCmd shows the signatures, attestations, certificates and
+// transparency log entries of imageRef, either as a tree printed to stdout,
+// or as a browsable terminal UI if interactive is set. If exportRefs is set,
+// the references and digests found are also written to that file as JSON.
+func InspectCmd(ctx context.Context, regOpts options.RegistryOptions, imageRef string, interactive bool, exportRefs string) error {
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
+	if err != nil {
+		return err
+	}
+	remoteOpts, err := regOpts.ClientOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	digest, signatures, attestations, err := load(ref, remoteOpts...)
+	if err != nil {
+		return err
+	}
+	root, err := buildTree(ref.String(), digest.DigestStr(), signatures, attestations)
+	if err != nil {
+		return err
+	}
+
+	if exportRefs != "" {
+		refs, err := newExportedRefs(ref.String(), digest, signatures, attestations, remoteOpts...)
+		if err != nil {
+			return err
+		}
+		b, err := json.MarshalIndent(refs, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(exportRefs, append(b, '\n'), 0o600); err != nil {
+			return fmt.Errorf("writing references: %w", err)
+		}
+	}
+
+	if interactive {
+		return browse(root)
+	}
+	printTree(os.Stdout, root)
+	return nil
+}
+
+func load(ref name.Reference, opts ...ociremote.Option) (name.Digest, []oci.Signature, []oci.Signature, error) {
+	digest, err := ociremote.ResolveDigest(ref, opts...)
+	if err != nil {
+		return name.Digest{}, nil, nil, err
+	}
+	se, err := ociremote.SignedEntity(digest, opts...)
+	if err != nil {
+		return name.Digest{}, nil, nil, err
+	}
+
+	var signatures, attestations []oci.Signature
+	if s, err := se.Signatures(); err == nil {
+		if signatures, err = s.Get(); err != nil {
+			return name.Digest{}, nil, nil, fmt.Errorf("fetching signatures: %w", err)
+		}
+	}
+	if s, err := se.Attestations(); err == nil {
+		if attestations, err = s.Get(); err != nil {
+			return name.Digest{}, nil, nil, fmt.Errorf("fetching attestations: %w", err)
+		}
+	}
+	return digest, signatures, attestations, nil
+}
+
+func buildTree(image, digest string, signatures, attestations []oci.Signature) (*Node, error) {
+	root := &Node{Label: image, Value: digest, expanded: true}
+
+	sn := root.add(fmt.Sprintf("Signatures (%d)", len(signatures)), "")
+	sn.expanded = true
+	for _, sig := range signatures {
+		n, err := signatureNode(sig)
+		if err != nil {
+			return nil, err
+		}
+		sn.Children = append(sn.Children, n)
+	}
+
+	an := root.add(fmt.Sprintf("Attestations (%d)", len(attestations)), "")
+	an.expanded = true
+	for _, att := range attestations {
+		n, err := attestationNode(att)
+		if err != nil {
+			return nil, err
+		}
+		an.Children = append(an.Children, n)
+	}
+	return root, nil
+}
+
+func signatureNode(sig oci.Signature) (*Node, error) {
+	d, err := sig.Digest()
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{Label: "Signature", Value: d.String()}
+
+	b64sig, err := sig.Base64Signature()
+	if err != nil {
+		return nil, err
+	}
+	n.add("Base64 signature", b64sig)
+
+	p, err := sig.Payload()
+	if err != nil {
+		return nil, err
+	}
+	sci := payload.SimpleContainerImage{}
+	if err := json.Unmarshal(p, &sci); err == nil {
+		pn := n.add("Payload", "")
+		pn.add("Signed digest", sci.Critical.Image.DockerManifestDigest)
+		pn.add("Identity", sci.Critical.Identity.DockerReference)
+		keys := make([]string, 0, len(sci.Optional))
+		for k := range sci.Optional {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			pn.add(k, fmt.Sprint(sci.Optional[k]))
+		}
+	}
+
+	if err := addSignatureMetadata(n, sig); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func attestationNode(att oci.Signature) (*Node, error) {
+	d, err := att.Digest()
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{Label: "Attestation", Value: d.String()}
+
+	p, err := att.Payload()
+	if err != nil {
+		return nil, err
+	}
+	var env struct {
+		PayloadType string `json:"payloadType"`
+		Payload     string `json:"payload"`
+	}
+	if err := json.Unmarshal(p, &env); err != nil {
+		return nil, fmt.Errorf("decoding attestation envelope: %w", err)
+	}
+	n.add("Payload type", env.PayloadType)
+	if st, ok := statementHeader(env.Payload); ok {
+		n.add("Predicate type", st.PredicateType)
+		subjects := n.add("Subjects", "")
+		for _, s := range st.Subject {
+			for alg, hex := range s.Digest {
+				subjects.add(s.Name, alg+":"+hex)
+			}
+		}
+	}
+
+	if err := addSignatureMetadata(n, att); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// statementHeader decodes the header of the in-toto statement in the base64
+// encoded payload of an attestation envelope.
+func statementHeader(b64payload string) (in_toto.StatementHeader, bool) {
+	st := in_toto.StatementHeader{}
+	decoded, err := base64.StdEncoding.DecodeString(b64payload)
+	if err != nil {
+		return st, false
+	}
+	return st, json.Unmarshal(decoded, &st) == nil
+}
+
+// addSignatureMetadata adds the certificate and transparency log entry of
+// sig, if any, to n.
+func addSignatureMetadata(n *Node, sig oci.Signature) error {
+	cert, err := sig.Cert()
+	if err != nil {
+		return err
+	}
+	if cert != nil {
+		chain, err := sig.Chain()
+		if err != nil {
+			return err
+		}
+		n.Children = append(n.Children, certificateNode(cert, chain))
+	}
+
+	b, err := sig.Bundle()
+	if err != nil {
+		return err
+	}
+	if b != nil {
+		rn := n.add("Rekor entry", "")
+		rn.add("Log index", fmt.Sprint(b.Payload.LogIndex))
+		rn.add("Log ID", b.Payload.LogID)
+		rn.add("Integrated time", time.Unix(b.Payload.IntegratedTime, 0).UTC().Format(time.RFC3339))
+		rn.add("Signed entry timestamp", base64.StdEncoding.EncodeToString(b.SignedEntryTimestamp))
+	}
+	return nil
+}
+
+func certificateNode(cert *x509.Certificate, chain []*x509.Certificate) *Node {
+	ce := cosign.CertExtensions{Cert: cert}
+	n := &Node{Label: "Certificate", Value: sigs.CertSubject(cert)}
+	if issuer := ce.GetIssuer(); issuer != "" {
+		n.add("OIDC issuer", issuer)
+	}
+	n.add("Issued by", cert.Issuer.String())
+	n.add("Serial number", cert.SerialNumber.String())
+	n.add("Not before", cert.NotBefore.UTC().Format(time.RFC3339))
+	n.add("Not after", cert.NotAfter.UTC().Format(time.RFC3339))
+	if len(chain) > 0 {
+		cn := n.add(fmt.Sprintf("Chain (%d)", len(chain)), "")
+		for _, c := range chain {
+			cn.add("Certificate", c.Subject.String())
+		}
+	}
+	return n
+}
+
+// printTree writes the fully expanded tree to w.
+func printTree(w io.Writer, root *Node) {
+	var walk func(n *Node, prefix string, last bool, top bool)
+	walk = func(n *Node, prefix string, last, top bool) {
+		switch {
+		case top:
+			fmt.Fprintln(w, n.String())
+		case last:
+			fmt.Fprintf(w, "%s└── %s\n", prefix, n.String())
+			prefix += "    "
+		default:
+			fmt.Fprintf(w, "%s├── %s\n", prefix, n.String())
+			prefix += "│   "
+		}
+		for i, c := range n.Children {
+			walk(c, prefix, i == len(n.Children)-1, false)
+		}
+	}
+	walk(root, "", true, true)
+}
+
+// truncate shortens s to at most width runes.
+func truncate(s string, width int) string {
+	r := []rune(strings.ReplaceAll(s, "\n", " "))
+	if width <= 0 || len(r) <= width {
+		return string(r)
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(r[:width-1]) + "…"
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+const imageDigest = "sha256:9f3b5a3a0d2ab4cbdeb1d0e9e1cc3e03f0f0f1b0db6e2b5e4c6d8d7a3e1f2a3b"
+
+// testArtifacts returns a keyless signature with a Rekor bundle and an
+// attestation.
+func testArtifacts(t *testing.T) (oci.Signature, oci.Signature) {
+	t.Helper()
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	leafCert, _, _ := test.GenerateLeafCert("subject@example.com", "https://issuer.example.com", rootCert, rootKey)
+	leafPEM, _ := cryptoutils.MarshalCertificateToPEM(leafCert)
+	rootPEM, _ := cryptoutils.MarshalCertificateToPEM(rootCert)
+
+	sig, err := static.NewSignature(
+		[]byte(`{"critical":{"identity":{"docker-reference":"example.com/app"},"image":{"docker-manifest-digest":"`+imageDigest+`"},"type":"cosign container image signature"},"optional":{"b":"2","a":"1"}}`),
+		"c2lnbmF0dXJl",
+		static.WithCertChain(leafPEM, rootPEM),
+		static.WithBundle(&bundle.RekorBundle{
+			SignedEntryTimestamp: []byte("set"),
+			Payload:              bundle.RekorPayload{LogIndex: 42, LogID: "log-id", IntegratedTime: 0},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement := `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2","subject":[{"name":"example.com/app","digest":{"sha256":"abc"}}],"predicate":{}}`
+	envelope := `{"payloadType":"application/vnd.in-toto+json","payload":"` + base64.StdEncoding.EncodeToString([]byte(statement)) + `","signatures":[]}`
+	att, err := static.NewAttestation([]byte(envelope))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sig, att
+}
+
+func testTree(t *testing.T) *Node {
+	t.Helper()
+	sig, att := testArtifacts(t)
+	root, err := buildTree("example.com/app:latest", imageDigest, []oci.Signature{sig}, []oci.Signature{att})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestPrintTree(t *testing.T) {
+	var out bytes.Buffer
+	printTree(&out, testTree(t))
+	got := out.String()
+
+	for _, want := range []string{
+		"example.com/app:latest: " + imageDigest + "\n",
+		"├── Signatures (1)\n",
+		"│       ├── Base64 signature: c2lnbmF0dXJl\n",
+		"│       │   ├── Signed digest: " + imageDigest + "\n",
+		"│       │   ├── a: 1\n│       │   └── b: 2\n",
+		"│       ├── Certificate: subject@example.com\n",
+		"OIDC issuer: https://issuer.example.com\n",
+		"│       └── Rekor entry\n",
+		"Log index: 42\n",
+		"Integrated time: 1970-01-01T00:00:00Z\n",
+		"└── Attestations (1)\n",
+		"Predicate type: https://slsa.dev/provenance/v0.2\n",
+		"example.com/app: sha256:abc\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("printTree() output is missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestBrowser(t *testing.T) {
+	b := &browser{root: testTree(t), height: 24}
+
+	// Root, Signatures and Attestations are expanded, the entries are not.
+	if got := len(b.rows()); got != 5 {
+		t.Fatalf("initial rows = %d, want 5", got)
+	}
+
+	b.handle(keyDown)
+	b.handle(keyDown)
+	if got := b.selected().Label; got != "Signature" {
+		t.Fatalf("selected = %q, want Signature", got)
+	}
+	b.handle(keyExpand)
+	b.handle(keyDown)
+	if got := b.selected().Label; got != "Base64 signature" {
+		t.Fatalf("selected = %q, want Base64 signature", got)
+	}
+	if _, clip := b.handle(keyCopy); clip != "c2lnbmF0dXJl" {
+		t.Errorf("copied %q, want the signature", clip)
+	}
+
+	// Collapsing a leaf moves to its parent, collapsing again folds it.
+	b.handle(keyCollapse)
+	if got := b.selected().Label; got != "Signature" {
+		t.Fatalf("selected = %q, want Signature", got)
+	}
+	b.handle(keyCollapse)
+	if got := len(b.rows()); got != 5 {
+		t.Fatalf("rows after collapse = %d, want 5", got)
+	}
+
+	// The cursor is clamped to the visible rows.
+	for i := 0; i < 10; i++ {
+		b.handle(keyDown)
+	}
+	if got := b.selected().Label; got != "Attestation" {
+		t.Errorf("selected = %q, want Attestation", got)
+	}
+	b.handle(keyPageUp)
+	if b.cursor != 0 {
+		t.Errorf("cursor = %d after page up, want 0", b.cursor)
+	}
+
+	if quit, _ := b.handle(keyQuit); !quit {
+		t.Error("expected quit")
+	}
+}
+
+func TestBrowserRender(t *testing.T) {
+	b := &browser{root: testTree(t)}
+	b.cursor = 4
+	var out bytes.Buffer
+	// Only two tree lines fit, so the view scrolls to the cursor.
+	b.render(&out, 40, chromeLines+2)
+	got := out.String()
+	if strings.Contains(got, "Signatures (1)") {
+		t.Errorf("expected Signatures to be scrolled out of view:\n%s", got)
+	}
+	if !strings.Contains(got, "\x1b[7m    ▸ Attestation: sha256:") {
+		t.Errorf("expected the selected row to be highlighted:\n%s", got)
+	}
+	for _, l := range strings.Split(got, "\r\n") {
+		if n := len([]rune(strings.TrimPrefix(strings.TrimSuffix(l, "\x1b[0m"), "\x1b[7m"))); n > 40 && !strings.HasPrefix(l, "\x1b[H") {
+			t.Errorf("line %q is wider than the screen", l)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	for in, want := range map[string]key{
+		"\x1b[A": keyUp, "j": keyDown, "\x1b[C": keyExpand, "h": keyCollapse,
+		"\r": keyToggle, "\x1b[6~": keyPageDown, "c": keyCopy, "q": keyQuit, "x": keyUnknown,
+	} {
+		if got := parseKey([]byte(in)); got != want {
+			t.Errorf("parseKey(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestCopySequence(t *testing.T) {
+	if got, want := copySequence("sha256:abc"), "\x1b]52;c;c2hhMjU2OmFiYw==\a"; got != want {
+		t.Errorf("copySequence() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/moby/term"
+	cosignterm "github.com/sigstore/cosign/v2/cmd/cosign/cli/templates/term"
+)
+
+type key int
+
+const (
+	keyUnknown key = iota
+	keyUp
+	keyDown
+	keyExpand
+	keyCollapse
+	keyToggle
+	keyPageUp
+	keyPageDown
+	keyCopy
+	keyQuit
+)
+
+const (
+	// Lines used by the title, the detail pane and the help line.
+	chromeLines = 4
+	helpLine    = "↑/↓ move  →/← expand/collapse  enter toggle  c copy  q quit"
+)
+
+type row struct {
+	node  *Node
+	depth int
+}
+
+// browser holds the state of the interactive inspection UI. It is kept
+// separate from the terminal handling so it can be tested.
+type browser struct {
+	root   *Node
+	cursor int
+	offset int
+	height int
+	status string
+}
+
+// rows returns the currently visible (i.e. expanded) nodes.
+func (b *browser) rows() []row {
+	var rows []row
+	var walk func(n *Node, depth int)
+	walk = func(n *Node, depth int) {
+		rows = append(rows, row{node: n, depth: depth})
+		if !n.expanded {
+			return
+		}
+		for _, c := range n.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(b.root, 0)
+	return rows
+}
+
+func (b *browser) selected() *Node {
+	rows := b.rows()
+	return rows[b.cursor].node
+}
+
+// handle applies k to the browser state. It returns true if the UI should
+// exit, and the value to copy to the clipboard, if any.
+func (b *browser) handle(k key) (bool, string) {
+	b.status = ""
+	rows := b.rows()
+	page := b.height - chromeLines
+	if page < 1 {
+		page = 1
+	}
+	switch k {
+	case keyUp:
+		b.cursor--
+	case keyDown:
+		b.cursor++
+	case keyPageUp:
+		b.cursor -= page
+	case keyPageDown:
+		b.cursor += page
+	case keyExpand:
+		if n := rows[b.cursor].node; len(n.Children) > 0 {
+			n.expanded = true
+		}
+	case keyCollapse:
+		r := rows[b.cursor]
+		if r.node.expanded && len(r.node.Children) > 0 {
+			r.node.expanded = false
+			break
+		}
+		// Move to the parent.
+		for i := b.cursor - 1; i >= 0; i-- {
+			if rows[i].depth < r.depth {
+				b.cursor = i
+				break
+			}
+		}
+	case keyToggle:
+		if n := rows[b.cursor].node; len(n.Children) > 0 {
+			n.expanded = !n.expanded
+		}
+	case keyCopy:
+		n := rows[b.cursor].node
+		if n.Value == "" {
+			b.status = "Nothing to copy"
+			return false, ""
+		}
+		b.status = "Copied to clipboard"
+		return false, n.Value
+	case keyQuit:
+		return true, ""
+	}
+
+	if rows = b.rows(); b.cursor >= len(rows) {
+		b.cursor = len(rows) - 1
+	}
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+	return false, ""
+}
+
+// render draws the browser into a width x height screen.
+func (b *browser) render(w io.Writer, width, height int) {
+	b.height = height
+	page := height - chromeLines
+	if page < 1 {
+		page = 1
+	}
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+page {
+		b.offset = b.cursor - page + 1
+	}
+
+	var buf bytes.Buffer
+	// Move home and clear the screen.
+	buf.WriteString("\x1b[H\x1b[2J")
+	line := func(s string) {
+		buf.WriteString(truncate(s, width))
+		buf.WriteString("\r\n")
+	}
+	line(fmt.Sprintf("cosign inspect %s", b.root.Label))
+
+	rows := b.rows()
+	for i := b.offset; i < b.offset+page; i++ {
+		if i >= len(rows) {
+			line("")
+			continue
+		}
+		r := rows[i]
+		marker := " "
+		switch {
+		case len(r.node.Children) > 0 && r.node.expanded:
+			marker = "▾"
+		case len(r.node.Children) > 0:
+			marker = "▸"
+		}
+		s := fmt.Sprintf("%s%s %s", strings.Repeat("  ", r.depth), marker, r.node.String())
+		if i == b.cursor {
+			// Reverse video for the selected line.
+			buf.WriteString("\x1b[7m")
+			buf.WriteString(truncate(s, width))
+			buf.WriteString("\x1b[0m\r\n")
+			continue
+		}
+		line(s)
+	}
+
+	line(strings.Repeat("─", width))
+	detail := b.selected().String()
+	if b.status != "" {
+		detail = b.status
+	}
+	line(detail)
+	buf.WriteString(truncate(helpLine, width))
+	_, _ = w.Write(buf.Bytes())
+}
+
+// parseKey decodes a single key press read from a terminal in raw mode.
+func parseKey(p []byte) key {
+	switch string(p) {
+	case "\x1b[A", "k":
+		return keyUp
+	case "\x1b[B", "j":
+		return keyDown
+	case "\x1b[C", "l":
+		return keyExpand
+	case "\x1b[D", "h":
+		return keyCollapse
+	case "\r", "\n", " ":
+		return keyToggle
+	case "\x1b[5~":
+		return keyPageUp
+	case "\x1b[6~":
+		return keyPageDown
+	case "c", "y":
+		return keyCopy
+	case "q", "\x1b", "\x03":
+		return keyQuit
+	}
+	return keyUnknown
+}
+
+// copySequence returns the OSC 52 escape sequence asking the terminal to put
+// s on the system clipboard. It also works over SSH, unlike shelling out to a
+// platform specific clipboard tool.
+func copySequence(s string) string {
+	return fmt.Sprintf("\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(s)))
+}
+
+// browse runs the interactive UI on the current terminal until the user quits.
+func browse(root *Node) error {
+	inFd, _ := term.GetFdInfo(os.Stdin)
+	outFd, isTerminal := term.GetFdInfo(os.Stdout)
+	if !isTerminal || !term.IsTerminal(inFd) {
+		return errors.New("--interactive requires a terminal")
+	}
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return fmt.Errorf("setting terminal to raw mode: %w", err)
+	}
+	// Switch to the alternate screen and hide the cursor, and undo both on exit.
+	fmt.Fprint(os.Stdout, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(os.Stdout, "\x1b[?25h\x1b[?1049l")
+		_ = term.RestoreTerminal(inFd, state)
+	}()
+
+	b := &browser{root: root}
+	in := make([]byte, 8)
+	for {
+		width, height := 80, 24
+		if size := cosignterm.GetSize(outFd); size != nil && size.Width > 0 && size.Height > 0 {
+			width, height = int(size.Width), int(size.Height)
+		}
+		b.render(os.Stdout, width, height)
+
+		n, err := os.Stdin.Read(in)
+		if err != nil {
+			return err
+		}
+		quit, clip := b.handle(parseKey(in[:n]))
+		if quit {
+			return nil
+		}
+		if clip != "" {
+			fmt.Fprint(os.Stdout, copySequence(clip))
+		}
+	}
+}
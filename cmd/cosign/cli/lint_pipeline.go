@@ -0,0 +1,143 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/lintpipeline"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func LintPipeline() *cobra.Command {
+	o := &options.LintPipelineOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "lint-pipeline [FILE]...",
+		Short: "Check the cosign invocations of CI configurations",
+		Long: `Check the cosign invocations of GitHub Actions workflows, GitLab CI
+configurations and other CI configurations with shell scripts under run,
+script, before_script or after_script keys, against the commands and flags of
+this version of cosign.
+
+Errors are invocations that fail, such as unknown commands and flags, missing
+arguments, and keyless verifications without the certificate identity and
+issuer to verify. Warnings are deprecated flags, flags that disable security
+checks, literal passwords, signing commands without --yes, which ask for
+confirmation, and identity or issuer regular expressions that accept any
+value or any domain or organization, aren't anchored with ^ and $ or have an
+unescaped dot.
+
+Without FILE, the GitHub Actions workflows in .github/workflows and
+.gitlab-ci.yml of the current directory are checked. The command fails if
+there are errors, or warnings with --strict.`,
+		Example: `  cosign lint-pipeline
+
+  # check a GitLab CI configuration, failing on warnings too
+  cosign lint-pipeline --strict .gitlab-ci.yml`,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return LintPipelineCmd(cmd.Root(), *o, args)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// LintPipelineCmd checks the cosign invocations of the CI configurations in
+// files, or of the default ones if files is empty, against the commands of
+// root, and prints the findings.
+func LintPipelineCmd(root *cobra.Command, o options.LintPipelineOptions, files []string) error {
+	if o.Output != "text" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, must be text or json", o.Output)
+	}
+	if len(files) == 0 {
+		var err error
+		if files, err = defaultPipelineFiles(); err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no CI configurations found in .github/workflows or .gitlab-ci.yml")
+		}
+	}
+
+	findings := []lintpipeline.Finding{}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		fs, err := lintpipeline.Lint(root, f, data)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, fs...)
+	}
+
+	return printFindings(findings, o.Output, o.Strict, "the cosign invocations")
+}
+
+// printFindings prints findings in the format output, and returns an error
+// if there are errors, or warnings and strict is set, in what.
+func printFindings(findings []lintpipeline.Finding, output string, strict bool, what string) error {
+	if output == "json" {
+		b, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		for _, f := range findings {
+			fmt.Printf("%s:%d: %s: %s: %s\n", f.File, f.Line, f.Severity, f.Command, f.Message)
+		}
+	}
+
+	errs, warnings := 0, 0
+	for _, f := range findings {
+		if f.Severity == lintpipeline.SeverityError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	if errs > 0 || (strict && warnings > 0) {
+		return fmt.Errorf("found %d errors and %d warnings in %s", errs, warnings, what)
+	}
+	return nil
+}
+
+// defaultPipelineFiles returns the GitHub Actions workflows and GitLab CI
+// configuration of the current directory.
+func defaultPipelineFiles() ([]string, error) {
+	var files []string
+	for _, pattern := range []string{".github/workflows/*.yml", ".github/workflows/*.yaml"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	if _, err := os.Stat(".gitlab-ci.yml"); err == nil {
+		files = append(files, ".gitlab-ci.yml")
+	}
+	return files, nil
+}
@@ -0,0 +1,46 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func TestLintPipelineCmd(t *testing.T) {
+	dir := t.TempDir()
+	workflow := filepath.Join(dir, "release.yml")
+	if err := os.WriteFile(workflow, []byte(`jobs:
+  release:
+    steps:
+      - run: cosign sign --yes --key env://COSIGN_KEY ghcr.io/example/app@${{ steps.build.outputs.digest }}
+      - run: cosign verify --cert-identity https://github.com/example/app/.github/workflows/release.yml@refs/heads/main --certificate-oidc-issuer https://token.actions.githubusercontent.com ghcr.io/example/app
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LintPipelineCmd(New(), options.LintPipelineOptions{Output: "text"}, []string{workflow}); err != nil {
+		t.Errorf("LintPipelineCmd() = %v", err)
+	}
+	err := LintPipelineCmd(New(), options.LintPipelineOptions{Output: "json", Strict: true}, []string{workflow})
+	if err == nil || !strings.Contains(err.Error(), "0 errors and 1 warnings") {
+		t.Errorf("LintPipelineCmd() with --strict = %v, want the deprecated --cert-identity warning", err)
+	}
+}
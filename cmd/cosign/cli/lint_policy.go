@@ -0,0 +1,81 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/lintpipeline"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func LintPolicy() *cobra.Command {
+	o := &options.LintPipelineOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "lint-policy FILE...",
+		Short: "Check verification policies for dangerous certificate identities",
+		Long: `Check the entries of verification policies, as read by cosign proxy --policy,
+cosign verify --image-policy and cosign verify-attestation --base-image-policy.
+
+Errors are keyless entries without a certificate identity or OIDC issuer.
+Warnings are identity and issuer regular expressions that accept any value,
+or the identities of any domain or organization such as ^.*@.*$ or
+^https://.*$, that aren't anchored with ^ and $, so that they also match any value that
+contains a match, or that have an unescaped . matching any character, e.g.
+^https://github.com/example/ that also matches https://githubXcom/example/.
+These are the expressions that cosign verify --certificate-identity-strict
+rejects.
+
+The command fails if there are errors, or warnings with --strict.`,
+		Example: `  cosign lint-policy policy.json
+
+  # fail on warnings too, e.g. in the CI of the policy repository
+  cosign lint-policy --strict policy.json base-images.json`,
+		Args:             cobra.MinimumNArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return LintPolicyCmd(*o, args)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// LintPolicyCmd checks the verification policies in files and prints the
+// findings.
+func LintPolicyCmd(o options.LintPipelineOptions, files []string) error {
+	if o.Output != "text" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, must be text or json", o.Output)
+	}
+	findings := []lintpipeline.Finding{}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		fs, err := lintpipeline.LintPolicy(f, data)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, fs...)
+	}
+	return printFindings(findings, o.Output, o.Strict, "the policies")
+}
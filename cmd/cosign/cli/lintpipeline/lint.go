@@ -0,0 +1,350 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lintpipeline finds the cosign invocations of CI configurations and
+// checks them against the commands and flags of this version of cosign.
+package lintpipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// The severities of a Finding.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Finding is a problem with a cosign invocation of a CI configuration, or
+// with an entry of a verification policy.
+type Finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Command  string `json:"command"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// scriptKeys are the keys of the shell scripts of GitHub Actions steps (run),
+// GitLab CI jobs (script, before_script and after_script) and Tekton steps
+// (script).
+var scriptKeys = map[string]bool{
+	"run":           true,
+	"script":        true,
+	"before_script": true,
+	"after_script":  true,
+}
+
+// Lint checks the cosign invocations of the shell scripts of the CI
+// configuration in data against the commands of root.
+func Lint(root *cobra.Command, file string, data []byte) ([]Finding, error) {
+	var findings []Finding
+	d := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := d.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("parsing %s: %w", file, err)
+		}
+		for _, s := range scripts(&doc, false) {
+			// The content of block scalars starts on the line after their
+			// indicator.
+			first := s.Line
+			if s.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+				first++
+			}
+			for _, c := range splitCommands(s.Value) {
+				env, args, ok := cosignInvocation(c)
+				if !ok {
+					continue
+				}
+				line := first
+				if s.Style&yaml.LiteralStyle != 0 {
+					line += c.line
+				}
+				for _, f := range LintInvocation(root, env, c.words[args:]) {
+					f.File, f.Line = file, line
+					findings = append(findings, f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// scripts returns the string scalars of n that are shell scripts.
+func scripts(n *yaml.Node, isScript bool) []*yaml.Node {
+	var found []*yaml.Node
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if isScript && n.Tag == "!!str" {
+			found = append(found, n)
+		}
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			found = append(found, scripts(c, false)...)
+		}
+	case yaml.SequenceNode:
+		for _, c := range n.Content {
+			found = append(found, scripts(c, isScript)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			found = append(found, scripts(n.Content[i+1], scriptKeys[n.Content[i].Value])...)
+		}
+	}
+	return found
+}
+
+// LintInvocation checks a cosign invocation with the environment assignments
+// env and the arguments args against the commands of root. The findings have
+// no file and line.
+func LintInvocation(root *cobra.Command, env map[string]string, args []string) []Finding {
+	cmd, rest := findCommand(root, args)
+	l := &linter{cmd: cmd, command: cmd.CommandPath()}
+
+	flags, positional := map[string]string{}, []string(nil)
+	// Positional arguments can't be counted after flags of unknown arity.
+	unknownFlags := false
+	for i := 0; i < len(rest); i++ {
+		a := rest[i]
+		if a == "--" {
+			positional = append(positional, rest[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			positional = append(positional, a)
+			continue
+		}
+		name, value, hasValue := "", "", false
+		var f *pflag.Flag
+		if strings.HasPrefix(a, "--") {
+			name, value, hasValue = strings.Cut(a[2:], "=")
+			f = lookupFlag(cmd, name)
+		} else {
+			name, value = a[1:2], strings.TrimPrefix(a[2:], "=")
+			hasValue = len(a) > 2
+			f = lookupShorthand(cmd, name)
+			if f != nil && f.ShorthandDeprecated != "" {
+				l.warn("-%s is deprecated: %s", name, f.ShorthandDeprecated)
+			}
+		}
+		if name == "help" || name == "h" {
+			return nil
+		}
+		if m, ok := options.FlagMigrationFor(name); ok && f == nil && len(name) > 1 {
+			l.error("unknown flag %s, it was renamed to --%s. cosign migrate-flags rewrites it", a, m.New)
+			unknownFlags = true
+			continue
+		}
+		if f == nil {
+			l.error("unknown flag %s", a)
+			unknownFlags = true
+			continue
+		}
+		if f.Deprecated != "" {
+			l.warn("--%s is deprecated: %s", f.Name, f.Deprecated)
+		}
+		if !hasValue {
+			switch {
+			case f.NoOptDefVal != "":
+				value = f.NoOptDefVal
+			case i+1 < len(rest):
+				i++
+				value = rest[i]
+			default:
+				l.error("--%s needs a value", f.Name)
+			}
+		}
+		// The checks below look for the successors of renamed flags.
+		if m, ok := options.FlagMigrationFor(f.Name); ok {
+			flags[m.New] = value
+		} else {
+			flags[f.Name] = value
+		}
+	}
+
+	if cmd.Deprecated != "" {
+		l.warn("the command is deprecated: %s", cmd.Deprecated)
+	}
+	if !cmd.Runnable() {
+		if len(positional) > 0 {
+			l.error("unknown command %q", positional[0])
+		} else {
+			l.error("missing subcommand")
+		}
+		return l.findings
+	}
+	if cmd.Args != nil && !unknownFlags {
+		if err := cmd.Args(cmd, positional); err != nil {
+			l.error("%v", err)
+		}
+	}
+	l.checkIdentity(flags)
+	l.checkInsecure(env, flags)
+	if cmd.Flags().Lookup("yes") != nil {
+		if _, ok := flags["yes"]; !ok {
+			l.warn("without --yes, the command asks for confirmation, which fails without a terminal")
+		}
+	}
+	return l.findings
+}
+
+// findCommand returns the subcommand of root that args run, and the arguments
+// after its name.
+func findCommand(root *cobra.Command, args []string) (*cobra.Command, []string) {
+	cmd, rest := root, []string(nil)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if strings.HasPrefix(a, "-") {
+			rest = append(rest, a)
+			// Flags before the name of the subcommand may take the next
+			// argument as their value.
+			if f := lookupFlag(cmd, strings.TrimPrefix(a, "--")); f != nil && !strings.Contains(a, "=") && f.NoOptDefVal == "" && i+1 < len(args) {
+				i++
+				rest = append(rest, args[i])
+			}
+			continue
+		}
+		sub := subcommand(cmd, a)
+		if sub == nil {
+			return cmd, append(rest, args[i:]...)
+		}
+		cmd = sub
+	}
+	return cmd, rest
+}
+
+func subcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// lookupFlag returns the flag of cmd or its parents named name.
+func lookupFlag(cmd *cobra.Command, name string) *pflag.Flag {
+	for _, fs := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags(), cmd.InheritedFlags()} {
+		if f := fs.Lookup(name); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func lookupShorthand(cmd *cobra.Command, name string) *pflag.Flag {
+	for _, fs := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags(), cmd.InheritedFlags()} {
+		if f := fs.ShorthandLookup(name); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+type linter struct {
+	cmd *cobra.Command
+	// command is the Command of the findings.
+	command  string
+	findings []Finding
+}
+
+func (l *linter) add(severity, format string, a ...interface{}) {
+	l.findings = append(l.findings, Finding{
+		Command:  l.command,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+func (l *linter) error(format string, a ...interface{}) { l.add(SeverityError, format, a...) }
+
+func (l *linter) warn(format string, a ...interface{}) { l.add(SeverityWarning, format, a...) }
+
+// checkIdentity checks that keyless verifications constrain the identity
+// and issuer of the signing certificate.
+func (l *linter) checkIdentity(flags map[string]string) {
+	if l.cmd.Flags().Lookup("certificate-identity") == nil {
+		return
+	}
+	if _, ok := flags["key"]; ok {
+		return
+	}
+	if _, ok := flags["sk"]; ok {
+		return
+	}
+	for _, c := range []struct{ exact, regexp string }{
+		{"certificate-identity", "certificate-identity-regexp"},
+		{"certificate-oidc-issuer", "certificate-oidc-issuer-regexp"},
+	} {
+		_, exact := flags[c.exact]
+		re, isRegexp := flags[c.regexp]
+		if !exact && !isRegexp {
+			l.error("keyless verification needs --%s or --%s", c.exact, c.regexp)
+		}
+		if isRegexp {
+			l.checkIdentityRegexp("--"+c.regexp+"="+re, re)
+		}
+	}
+}
+
+// checkIdentityRegexp warns of the problems of the identity or issuer
+// regular expression re, named name.
+func (l *linter) checkIdentityRegexp(name, re string) {
+	problems, err := cosign.IdentityRegexpProblems(re)
+	if err != nil {
+		l.error("%s: %v", name, err)
+		return
+	}
+	for _, p := range problems {
+		l.warn("%s %s", name, p)
+	}
+}
+
+// checkInsecure warns of the flags and variables that disable security
+// checks or leak secrets.
+func (l *linter) checkInsecure(env map[string]string, flags map[string]string) {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if (strings.Contains(name, "insecure") || name == "allow-http-registry") && flags[name] != "false" {
+			l.warn("--%s disables a security check", name)
+		}
+	}
+	if v, ok := flags["tlog-upload"]; ok && v == "false" {
+		l.warn("--tlog-upload=false keeps the signature out of the transparency log, so it can only be verified with --insecure-ignore-tlog")
+	}
+	if v := env["COSIGN_PASSWORD"]; v != "" && !strings.HasPrefix(v, "$") {
+		l.warn("COSIGN_PASSWORD is set to a literal password, use a CI secret instead")
+	}
+}
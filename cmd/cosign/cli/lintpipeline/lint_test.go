@@ -0,0 +1,192 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lintpipeline
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSplitCommands(t *testing.T) {
+	script := `echo "a b" # comment
+cosign sign --yes \
+  --key 'k y' ${{ steps.build.outputs.image }}; FOO=1 cosign verify $(cat ref) && true
+if cosign triangulate x; then exit 1; fi
+`
+	var got [][]string
+	var lines []int
+	for _, c := range splitCommands(script) {
+		got = append(got, c.words)
+		lines = append(lines, c.line)
+	}
+	want := [][]string{
+		{"echo", "a b"},
+		{"cosign", "sign", "--yes", "--key", "k y", "${{ steps.build.outputs.image }}"},
+		{"FOO=1", "cosign", "verify", "$(cat ref)"},
+		{"true"},
+		{"if", "cosign", "triangulate", "x"},
+		{"then", "exit", "1"},
+		{"fi"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitCommands() = %q, want %q", got, want)
+	}
+	if wantLines := []int{0, 1, 2, 2, 3, 3, 3}; !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("lines = %v, want %v", lines, wantLines)
+	}
+
+	env, first, ok := cosignInvocation(shellCommand{words: want[2]})
+	if !ok || env["FOO"] != "1" || first != 2 {
+		t.Errorf("cosignInvocation() = %v, %d, %t", env, first, ok)
+	}
+	if _, _, ok := cosignInvocation(shellCommand{words: want[0]}); ok {
+		t.Error("cosignInvocation() found cosign in echo")
+	}
+}
+
+// testRoot is a cosign command tree with a signing and a verification
+// command.
+func testRoot() *cobra.Command {
+	root := &cobra.Command{Use: "cosign"}
+	root.PersistentFlags().String("output-file", "", "")
+	run := func(*cobra.Command, []string) {}
+
+	sign := &cobra.Command{Use: "sign", Run: run, Args: cobra.MinimumNArgs(1)}
+	sign.Flags().String("key", "", "")
+	sign.Flags().BoolP("yes", "y", false, "")
+	sign.Flags().Bool("allow-insecure-registry", false, "")
+	sign.Flags().Bool("tlog-upload", true, "")
+	sign.Flags().String("old", "", "")
+	_ = sign.Flags().MarkDeprecated("old", "use --key")
+
+	verify := &cobra.Command{Use: "verify", Run: run, Args: cobra.MinimumNArgs(1)}
+	for _, f := range []string{"key", "certificate-identity", "certificate-identity-regexp", "certificate-oidc-issuer", "certificate-oidc-issuer-regexp"} {
+		verify.Flags().String(f, "", "")
+	}
+	verify.Flags().Bool("insecure-ignore-tlog", false, "")
+
+	root.AddCommand(sign, verify, &cobra.Command{Use: "attach"})
+	return root
+}
+
+func TestLintInvocation(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cmd  string
+		env  map[string]string
+		want []string
+	}{{
+		name: "clean signing",
+		cmd:  "sign --yes --key cosign.key img",
+	}, {
+		name: "clean keyless verification",
+		cmd:  "verify --certificate-identity a@b --certificate-oidc-issuer=https://b img",
+	}, {
+		name: "flags before the subcommand",
+		cmd:  "--output-file out sign -y img",
+	}, {
+		name: "help",
+		cmd:  "sign --help --bogus",
+	}, {
+		name: "unknown subcommand",
+		cmd:  "sing img",
+		want: []string{`error: unknown command "sing"`},
+	}, {
+		name: "missing subcommand",
+		cmd:  "attach",
+		want: []string{"error: missing subcommand"},
+	}, {
+		name: "unknown flag",
+		cmd:  "sign -y --bogus x img",
+		want: []string{"error: unknown flag --bogus"},
+	}, {
+		name: "missing argument",
+		cmd:  "sign -y --key",
+		want: []string{"error: --key needs a value", "error: requires at least 1 arg(s), only received 0"},
+	}, {
+		name: "deprecated flag and prompt",
+		cmd:  "sign --old k img",
+		want: []string{"warning: --old is deprecated: use --key", "warning: without --yes"},
+	}, {
+		name: "insecure",
+		cmd:  "sign -y --allow-insecure-registry --tlog-upload=false img",
+		env:  map[string]string{"COSIGN_PASSWORD": "hunter2"},
+		want: []string{"warning: --allow-insecure-registry", "warning: --tlog-upload=false", "warning: COSIGN_PASSWORD"},
+	}, {
+		name: "password from a secret",
+		cmd:  "sign -y img",
+		env:  map[string]string{"COSIGN_PASSWORD": "$PASSWORD"},
+	}, {
+		name: "insecure flag disabled",
+		cmd:  "verify --key k --insecure-ignore-tlog=false img",
+	}, {
+		name: "missing identity",
+		cmd:  "verify --certificate-oidc-issuer-regexp .* img",
+		want: []string{"error: keyless verification needs --certificate-identity", "warning: --certificate-oidc-issuer-regexp=.* accepts any value"},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := LintInvocation(testRoot(), tt.env, strings.Fields(tt.cmd))
+			if len(findings) != len(tt.want) {
+				t.Fatalf("LintInvocation() = %+v, want %q", findings, tt.want)
+			}
+			for i, f := range findings {
+				if got := f.Severity + ": " + f.Message; !strings.HasPrefix(got, tt.want[i]) {
+					t.Errorf("finding %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLint(t *testing.T) {
+	config := `jobs:
+  sign:
+    steps:
+      - name: cosign sign
+        run: |
+          make
+          cosign sign --yes \
+            --bogus img
+      - run: cosign verify --key k img
+---
+sign:
+  script:
+    - cosign sing img
+`
+	findings, err := Lint(testRoot(), "ci.yml", []byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, fmt.Sprintf("%s:%d: %s: %s", f.File, f.Line, f.Command, f.Message))
+	}
+	want := []string{
+		"ci.yml:7: cosign sign: unknown flag --bogus",
+		`ci.yml:13: cosign: unknown command "sing"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lint() = %q, want %q", got, want)
+	}
+
+	if _, err := Lint(testRoot(), "ci.yml", []byte("run: [")); err == nil {
+		t.Error("Lint() parsed malformed YAML")
+	}
+}
@@ -0,0 +1,103 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lintpipeline
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+// Change is a flag of a cosign invocation that Migrate renamed.
+type Change struct {
+	// Line is the line of the flag, from 1.
+	Line    int
+	Command string
+	Old     string
+	New     string
+}
+
+// Migrate renames the flags of the cosign invocations of script that were
+// renamed in options.FlagMigrations, if the invoked commands of root have
+// their successors. script is a shell script, or a CI configuration with
+// shell scripts in plain or block YAML scalars.
+func Migrate(root *cobra.Command, script string) (string, []Change) {
+	type rename struct {
+		offset int
+		m      options.FlagMigration
+	}
+	var (
+		renames []rename
+		changes []Change
+	)
+	for _, c := range splitCommands(script) {
+		skip := yamlPrefix(c.words)
+		_, first, ok := cosignInvocation(shellCommand{words: c.words[skip:]})
+		if !ok {
+			continue
+		}
+		first += skip
+		cmd, _ := findCommand(root, c.words[first:])
+		for i := first; i < len(c.words); i++ {
+			w := c.words[i]
+			if w == "--" {
+				break
+			}
+			if !strings.HasPrefix(w, "--") {
+				continue
+			}
+			name, _, _ := strings.Cut(w[2:], "=")
+			m, ok := options.FlagMigrationFor(name)
+			// Quoted flags aren't renamed, so that the offset of the
+			// word is the offset of the flag.
+			if !ok || lookupFlag(cmd, m.New) == nil || !strings.HasPrefix(script[c.starts[i]:], "--"+name) {
+				continue
+			}
+			renames = append(renames, rename{offset: c.starts[i] + 2, m: m})
+			changes = append(changes, Change{
+				Line:    strings.Count(script[:c.starts[i]], "\n") + 1,
+				Command: cmd.CommandPath(),
+				Old:     m.Old,
+				New:     m.New,
+			})
+		}
+	}
+
+	sort.Slice(renames, func(i, j int) bool { return renames[i].offset < renames[j].offset })
+	var b strings.Builder
+	last := 0
+	for _, r := range renames {
+		b.WriteString(script[last:r.offset])
+		b.WriteString(r.m.New)
+		last = r.offset + len(r.m.Old)
+	}
+	b.WriteString(script[last:])
+	return b.String(), changes
+}
+
+// yamlPrefix returns the number of leading words of words that are YAML
+// keys and sequence entry indicators, such as "- run:".
+func yamlPrefix(words []string) int {
+	for i, w := range words {
+		if w != "-" && !strings.HasSuffix(w, ":") {
+			return i
+		}
+	}
+	return len(words)
+}
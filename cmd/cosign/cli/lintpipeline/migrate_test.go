@@ -0,0 +1,66 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lintpipeline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	root := testRoot()
+	verify, _, err := root.Find([]string{"verify"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Flags().String("certificate", "", "")
+
+	script := `jobs:
+  sign:
+    steps:
+      - run: cosign verify --cert-identity me@example.com \
+          --cert-oidc-issuer=https://accounts.google.com --cert c.pem img
+      - run: |
+          git push --force
+          cosign sign --force --key k "--cert" img
+`
+	want := `jobs:
+  sign:
+    steps:
+      - run: cosign verify --certificate-identity me@example.com \
+          --certificate-oidc-issuer=https://accounts.google.com --certificate c.pem img
+      - run: |
+          git push --force
+          cosign sign --yes --key k "--cert" img
+`
+	got, changes := Migrate(root, script)
+	if got != want {
+		t.Errorf("Migrate() = %s, want %s", got, want)
+	}
+	wantChanges := []Change{
+		{Line: 4, Command: "cosign verify", Old: "cert-identity", New: "certificate-identity"},
+		{Line: 5, Command: "cosign verify", Old: "cert-oidc-issuer", New: "certificate-oidc-issuer"},
+		{Line: 5, Command: "cosign verify", Old: "cert", New: "certificate"},
+		{Line: 8, Command: "cosign sign", Old: "force", New: "yes"},
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("Migrate() changes = %+v, want %+v", changes, wantChanges)
+	}
+
+	if got, changes := Migrate(root, want); got != want || len(changes) != 0 {
+		t.Errorf("Migrate() rewrote migrated flags: %+v", changes)
+	}
+}
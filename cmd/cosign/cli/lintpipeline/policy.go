@@ -0,0 +1,88 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lintpipeline
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintPolicy checks the entries of the verification policy in data, a policy
+// of cosign proxy --policy, verify --image-policy or verify-attestation
+// --base-image-policy. Errors are keyless entries without a certificate
+// identity or issuer, and warnings identity and issuer regular expressions
+// that accept any value, aren't anchored or have an unescaped dot. The
+// Command of the findings is the entry, e.g. images[0].
+func LintPolicy(file string, data []byte) ([]Finding, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s isn't a verification policy", file)
+	}
+	images := mappingValue(doc.Content[0], "images")
+	if images == nil || images.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s has no images", file)
+	}
+
+	var findings []Finding
+	for i, e := range images.Content {
+		if e.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s: images[%d] isn't an object", file, i)
+		}
+		entry := fmt.Sprintf("images[%d]", i)
+		l := &linter{command: entry}
+		if mappingValue(e, "key") == nil {
+			for _, c := range []struct{ exact, regexp string }{
+				{"certificateIdentity", "certificateIdentityRegexp"},
+				{"certificateOidcIssuer", "certificateOidcIssuerRegexp"},
+			} {
+				if mappingValue(e, c.exact) == nil && mappingValue(e, c.regexp) == nil {
+					l.error("keyless entry needs %s or %s", c.exact, c.regexp)
+				}
+			}
+		}
+		findings = append(findings, l.at(file, e.Line)...)
+		for _, name := range []string{"certificateIdentityRegexp", "certificateOidcIssuerRegexp"} {
+			if v := mappingValue(e, name); v != nil {
+				l := &linter{command: entry}
+				l.checkIdentityRegexp(name+"="+v.Value, v.Value)
+				findings = append(findings, l.at(file, v.Line)...)
+			}
+		}
+	}
+	return findings, nil
+}
+
+// at returns the findings of l, in file at line.
+func (l *linter) at(file string, line int) []Finding {
+	for i := range l.findings {
+		l.findings[i].File, l.findings[i].Line = file, line
+	}
+	return l.findings
+}
+
+// mappingValue returns the value of key in the mapping n, or nil.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
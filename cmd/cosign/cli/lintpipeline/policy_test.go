@@ -0,0 +1,56 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lintpipeline
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLintPolicy(t *testing.T) {
+	policy := `{"images": [
+  {"glob": "ghcr.io/example/*", "certificateIdentityRegexp": "^https://github\\.com/example/.*$", "certificateOidcIssuer": "https://token.actions.githubusercontent.com"},
+  {"glob": "registry.example.com/*",
+   "certificateIdentityRegexp": "^https://github.com/example/.*$",
+   "certificateOidcIssuerRegexp": ".*"},
+  {"glob": "*", "certificateIdentity": "release@example.com"},
+  {"key": "cosign.pub"}
+]}`
+	findings, err := LintPolicy("policy.json", []byte(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"policy.json:4: warning: images[1]: certificateIdentityRegexp=^https://github.com/example/.*$ has an unescaped .",
+		"policy.json:5: warning: images[1]: certificateOidcIssuerRegexp=.* accepts any value",
+		"policy.json:6: error: images[2]: keyless entry needs certificateOidcIssuer",
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("LintPolicy() = %+v, want %q", findings, want)
+	}
+	for i, f := range findings {
+		if got := fmt.Sprintf("%s:%d: %s: %s: %s", f.File, f.Line, f.Severity, f.Command, f.Message); !strings.HasPrefix(got, want[i]) {
+			t.Errorf("finding %d = %q, want %q", i, got, want[i])
+		}
+	}
+
+	for _, s := range []string{`[]`, `{"images": {}}`, `{"images": ["key"]}`, `{`} {
+		if _, err := LintPolicy("policy.json", []byte(s)); err == nil {
+			t.Errorf("LintPolicy(%s): expected an error", s)
+		}
+	}
+}
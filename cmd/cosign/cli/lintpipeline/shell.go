@@ -0,0 +1,196 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lintpipeline
+
+import (
+	"strings"
+)
+
+// shellCommand is a simple command of a shell script, without its redirections
+// and control operators.
+type shellCommand struct {
+	words []string
+	// starts are the offsets of the words in the script.
+	starts []int
+	// line is the line of the script the command starts on, from 0.
+	line int
+}
+
+// splitCommands splits a shell script into its simple commands. It
+// understands quotes, escapes, line continuations, comments and the command
+// separators, which is all it takes to find the arguments of the commands of
+// CI scripts. Expansions such as $VAR, $(...) and GitHub Actions ${{ ... }}
+// expressions are kept as written.
+func splitCommands(script string) []shellCommand {
+	var (
+		commands []shellCommand
+		cur      shellCommand
+		word     strings.Builder
+		inWord   bool
+		start    int
+		line     int
+	)
+	// begin starts a word at offset i unless one is started.
+	begin := func(i int) {
+		if !inWord {
+			start = i
+			inWord = true
+		}
+	}
+	flushWord := func() {
+		if inWord {
+			if len(cur.words) == 0 {
+				cur.line = line
+			}
+			cur.words = append(cur.words, word.String())
+			cur.starts = append(cur.starts, start)
+		}
+		word.Reset()
+		inWord = false
+	}
+	flushCommand := func() {
+		flushWord()
+		if len(cur.words) > 0 {
+			commands = append(commands, cur)
+		}
+		cur = shellCommand{}
+	}
+	// readUntil appends script[i:] up to and including end to the word, and
+	// returns the index after it.
+	readUntil := func(i int, end string, keep bool) int {
+		begin(i)
+		j := strings.Index(script[i:], end)
+		if j < 0 {
+			j = len(script) - i
+		} else {
+			j += len(end)
+		}
+		s := script[i : i+j]
+		line += strings.Count(s, "\n")
+		if !keep {
+			s = strings.TrimSuffix(s, end)
+		}
+		word.WriteString(s)
+		return i + j
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == '\\' && i+1 < len(script):
+			if script[i+1] == '\n' {
+				line++
+			} else {
+				begin(i)
+				word.WriteByte(script[i+1])
+			}
+			i += 2
+		case c == '\'':
+			begin(i)
+			i = readUntil(i+1, "'", false)
+		case c == '"':
+			begin(i)
+			i++
+			for ; i < len(script) && script[i] != '"'; i++ {
+				if script[i] == '\\' && i+1 < len(script) && strings.IndexByte("\"\\$`", script[i+1]) >= 0 {
+					i++
+				}
+				if script[i] == '\n' {
+					line++
+				}
+				word.WriteByte(script[i])
+			}
+			i++
+		case strings.HasPrefix(script[i:], "${{"):
+			begin(i)
+			word.WriteString("${{")
+			i = readUntil(i+3, "}}", true)
+		case strings.HasPrefix(script[i:], "$("):
+			begin(i)
+			depth := 0
+			for ; i < len(script); i++ {
+				word.WriteByte(script[i])
+				if script[i] == '(' {
+					depth++
+				} else if script[i] == ')' {
+					if depth--; depth == 0 {
+						i++
+						break
+					}
+				}
+			}
+		case c == '#' && !inWord:
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+		case c == ' ' || c == '\t' || c == '\r':
+			flushWord()
+			i++
+		case strings.IndexByte("\n;&|(){}", c) >= 0:
+			flushCommand()
+			if c == '\n' {
+				line++
+			}
+			i++
+		default:
+			begin(i)
+			word.WriteByte(c)
+			i++
+		}
+	}
+	flushCommand()
+	return commands
+}
+
+// commandPrefixes are the words that can precede the name of the command of a
+// simple command.
+var commandPrefixes = map[string]bool{
+	"!": true, "command": true, "do": true, "else": true, "elif": true, "env": true, "exec": true,
+	"if": true, "nohup": true, "sudo": true, "then": true, "time": true, "until": true, "while": true,
+}
+
+// cosignInvocation returns the environment assignments and the arguments of c
+// if it runs cosign. The arguments are the words of c from the index first.
+func cosignInvocation(c shellCommand) (env map[string]string, first int, ok bool) {
+	env = map[string]string{}
+	for i, w := range c.words {
+		if name, value, ok := strings.Cut(w, "="); ok && isIdentifier(name) {
+			env[name] = value
+			continue
+		}
+		if commandPrefixes[w] || (i > 0 && strings.HasPrefix(w, "-") && commandPrefixes[c.words[i-1]]) {
+			continue
+		}
+		base := w[strings.LastIndexAny(w, `/\`)+1:]
+		if base == "cosign" || base == "cosign.exe" {
+			return env, i + 1, true
+		}
+		return nil, 0, false
+	}
+	return nil, 0, false
+}
+
+func isIdentifier(s string) bool {
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+	for _, c := range s {
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -19,9 +19,9 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/google/go-containerregistry/pkg/name"
-
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/layout"
 	"github.com/sigstore/cosign/v2/pkg/oci/remote"
 
@@ -32,10 +32,23 @@ func Load() *cobra.Command {
 	o := &options.LoadOptions{}
 
 	cmd := &cobra.Command{
-		Use:              "load",
-		Short:            "Load a signed image on disk to a remote registry",
-		Long:             "Load a signed image on disk to a remote registry",
-		Example:          `  cosign load --dir <path to directory> <IMAGE>`,
+		Use:   "load",
+		Short: "Load a signed image on disk to a remote registry",
+		Long: `Load a signed image on disk to a remote registry.
+
+The digests of all the manifests and blobs on disk are verified before anything
+is written. With --verify-policy, the image's signatures must also be valid for
+the key or certificate identity in the policy, e.g.
+
+  {"key": "cosign.pub"}
+  {"certificateIdentity": "release@example.com", "certificateOidcIssuer": "https://accounts.google.com"}
+
+The policy also accepts certificateIdentityRegexp, certificateOidcIssuerRegexp,
+ignoreTlog and ignoreSCT.`,
+		Example: `  cosign load --dir <path to directory> <IMAGE>
+
+  # only load the image if it is signed with cosign.pub
+  cosign load --dir <path to directory> --verify-policy policy.json <IMAGE>`,
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -47,12 +60,33 @@ func Load() *cobra.Command {
 	return cmd
 }
 
+// LoadPolicy is the --verify-policy of cosign load: the key or certificate
+// identity the signatures of the image on disk must be verified with.
+type LoadPolicy = verify.SignerPolicy
+
 func LoadCmd(ctx context.Context, opts options.LoadOptions, imageRef string) error {
-	ref, err := name.ParseReference(imageRef)
+	ref, err := oci.ParseReference(imageRef)
 	if err != nil {
 		return fmt.Errorf("parsing image name %s: %w", imageRef, err)
 	}
 
+	// Reject tampered content before anything is written to the registry.
+	if err := layout.VerifyDigests(opts.Directory); err != nil {
+		return fmt.Errorf("verifying %s: %w", opts.Directory, err)
+	}
+
+	if opts.VerifyPolicy != "" {
+		p, err := verify.ReadSignerPolicy(opts.VerifyPolicy)
+		if err != nil {
+			return err
+		}
+		v := p.Command()
+		v.LocalImage = true
+		if err := v.Exec(ctx, []string{opts.Directory}); err != nil {
+			return fmt.Errorf("verifying the signatures in %s: %w", opts.Directory, err)
+		}
+	}
+
 	// get the signed image from disk
 	sii, err := layout.SignedImageIndex(opts.Directory)
 	if err != nil {
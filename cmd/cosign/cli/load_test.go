@@ -0,0 +1,140 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci/layout"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	"github.com/sigstore/cosign/v2/pkg/oci/signed"
+)
+
+func TestLoadCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"example.com/app"},"image":{"docker-manifest-digest":"` + h.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	signer, key := newTestKey(t)
+	_, otherKey := newTestKey(t)
+	si, err := mutate.AttachSignatureToImage(signed.Image(img), signPayload(t, signer, payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writePolicy := func(t *testing.T, policy string) string {
+		path := filepath.Join(t.TempDir(), "policy.json")
+		if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name    string
+		policy  string
+		tamper  bool
+		wantErr string
+	}{{
+		name: "no policy",
+	}, {
+		name:   "signed with the policy key",
+		policy: `{"key": "` + key + `", "ignoreTlog": true, "ignoreSCT": true}`,
+	}, {
+		name:    "signed with another key",
+		policy:  `{"key": "` + otherKey + `", "ignoreTlog": true, "ignoreSCT": true}`,
+		wantErr: "verifying the signatures",
+	}, {
+		name:    "tampered layer",
+		tamper:  true,
+		wantErr: "has digest",
+	}, {
+		name:    "policy without a signer",
+		policy:  `{"ignoreTlog": true}`,
+		wantErr: "must set a key or a certificate identity",
+	}, {
+		name:    "unknown policy field",
+		policy:  `{"key": "` + key + `", "keys": []}`,
+		wantErr: "parsing verification policy",
+	}}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := layout.WriteSignedImage(dir, si); err != nil {
+				t.Fatal(err)
+			}
+			if tt.tamper {
+				layers, err := img.Layers()
+				if err != nil {
+					t.Fatal(err)
+				}
+				l, err := layers[0].Digest()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "blobs", l.Algorithm, l.Hex), []byte("tampered"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			opts := options.LoadOptions{Directory: dir}
+			if tt.policy != "" {
+				opts.VerifyPolicy = writePolicy(t, tt.policy)
+			}
+			ref, err := name.ParseReference(host + "/app" + string(rune('a'+i)) + ":latest")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = LoadCmd(ctx, opts, ref.String())
+			_, headErr := remote.Head(ref)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadCmd() unexpected error: %v", err)
+				}
+				if headErr != nil {
+					t.Errorf("image was not loaded: %v", headErr)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadCmd() error = %v, want %q", err, tt.wantErr)
+			}
+			if headErr == nil {
+				t.Error("image was loaded despite the error")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/login"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func Login() *cobra.Command {
+	o := &options.LoginOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "login [OPTIONS] [SERVER]",
+		Short: "Log in to a registry",
+		Long: `Log in to a registry.
+
+The credentials are validated against the registry before they are stored.
+They are stored through the credential helper given with --credential-helper,
+the one configured in the Docker client configuration, or the platform default
+helper, and only fall back to the configuration file if none is available.`,
+		Example: `  # Log in to reg.example.com
+  cosign login reg.example.com -u AzureDiamond -p hunter2
+
+  # Log in with an identity token, and check access to a repository
+  cosign login reg.example.com --identity-token <TOKEN> --probe-repository my-org/my-image
+
+  # Store the credentials in a specific credential helper
+  cosign login reg.example.com -u AzureDiamond --password-stdin --credential-helper pass`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return login.LoginCmd(cmd.Context(), *o, args[0])
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/credentials"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// LoginCmd validates the credentials for server and stores them in the
+// Docker client configuration, through a credential helper if one is
+// configured or available.
+func LoginCmd(ctx context.Context, opts options.LoginOptions, server string) error {
+	var nameOpts []name.Option
+	if opts.AllowHTTPRegistry {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+	reg, err := name.NewRegistry(server, nameOpts...)
+	if err != nil {
+		return err
+	}
+
+	if opts.PasswordStdin {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		opts.Password = strings.TrimSuffix(string(contents), "\n")
+		opts.Password = strings.TrimSuffix(opts.Password, "\r")
+	}
+
+	authConfig, err := newAuthConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Probe {
+		var t http.RoundTripper = remote.DefaultTransport
+		if opts.AllowInsecure {
+			t = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}} // #nosec G402
+		}
+		auth := authn.FromConfig(authn.AuthConfig{
+			Username:      authConfig.Username,
+			Password:      authConfig.Password,
+			IdentityToken: authConfig.IdentityToken,
+		})
+		if err := probe(ctx, reg, auth, t, opts.ProbeRepository); err != nil {
+			return err
+		}
+	}
+
+	location, err := store(ctx, reg, authConfig, opts.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	ui.Infof(ctx, "logged in to %s via %s", reg.Name(), location)
+	return nil
+}
+
+func newAuthConfig(opts options.LoginOptions) (types.AuthConfig, error) {
+	switch {
+	case opts.IdentityToken != "" && opts.Password != "":
+		return types.AuthConfig{}, errors.New("only one of password and identity token may be provided")
+	case opts.IdentityToken != "":
+		return types.AuthConfig{Username: opts.Username, IdentityToken: opts.IdentityToken}, nil
+	case opts.Username == "" || opts.Password == "":
+		return types.AuthConfig{}, errors.New("username and password, or an identity token, are required")
+	}
+	return types.AuthConfig{Username: opts.Username, Password: opts.Password}, nil
+}
+
+// staticKeychain resolves every resource to the same authenticator.
+type staticKeychain struct {
+	auth authn.Authenticator
+}
+
+func (k staticKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return k.auth, nil
+}
+
+// probe checks that reg accepts auth and, if repo is set, reports whether it
+// grants pull and push access to that repository.
+func probe(ctx context.Context, reg name.Registry, auth authn.Authenticator, t http.RoundTripper, repo string) error {
+	rt, err := transport.NewWithContext(ctx, reg, auth, t, nil)
+	if err != nil {
+		return fmt.Errorf("authenticating to %s: %w", reg.Name(), err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/v2/", reg.Scheme(), reg.RegistryStr()), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		return fmt.Errorf("probing %s: %w", reg.Name(), err)
+	}
+	defer resp.Body.Close()
+	if err := transport.CheckError(resp, http.StatusOK); err != nil {
+		return fmt.Errorf("%s rejected the credentials: %w", reg.Name(), err)
+	}
+
+	if repo == "" {
+		return nil
+	}
+	r := reg.Repo(repo)
+	var denied []string
+
+	_, err = remote.List(r, remote.WithContext(ctx), remote.WithAuth(auth), remote.WithTransport(t))
+	var terr *transport.Error
+	switch {
+	case err == nil:
+		ui.Infof(ctx, "pull access to %s: ok", r)
+	case errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound:
+		ui.Infof(ctx, "pull access to %s: ok (repository does not exist yet)", r)
+	default:
+		ui.Infof(ctx, "pull access to %s: denied (%v)", r, err)
+		denied = append(denied, "pull")
+	}
+
+	if err := remote.CheckPushPermission(r.Tag("latest"), staticKeychain{auth}, t); err != nil {
+		ui.Infof(ctx, "push access to %s: denied (%v)", r, err)
+		denied = append(denied, "push")
+	} else {
+		ui.Infof(ctx, "push access to %s: ok", r)
+	}
+
+	if len(denied) > 0 {
+		return fmt.Errorf("credentials do not grant %s access to %s", strings.Join(denied, " and "), r)
+	}
+	return nil
+}
+
+// store saves authConfig for reg and returns a description of where it was
+// stored. Credentials are kept in a credential helper whenever possible:
+// helper if set, otherwise the configured one, otherwise the platform default.
+func store(ctx context.Context, reg name.Registry, authConfig types.AuthConfig, helper string) (string, error) {
+	cf, err := config.Load(env.Getenv(env.VariableDockerConfig))
+	if err != nil {
+		return "", err
+	}
+
+	serverAddress := reg.Name()
+	if serverAddress == name.DefaultRegistry {
+		serverAddress = authn.DefaultAuthKey
+	}
+	authConfig.ServerAddress = serverAddress
+
+	if helper == "" && cf.CredentialsStore == "" && cf.CredentialHelpers[serverAddress] == "" {
+		helper = credentials.DetectDefaultStore("")
+	}
+	if helper != "" {
+		if cf.CredentialHelpers == nil {
+			cf.CredentialHelpers = map[string]string{}
+		}
+		cf.CredentialHelpers[serverAddress] = helper
+	}
+
+	if err := cf.GetCredentialsStore(serverAddress).Store(authConfig); err != nil {
+		return "", fmt.Errorf("storing credentials: %w", err)
+	}
+	if err := cf.Save(); err != nil {
+		return "", err
+	}
+
+	if h := cf.CredentialHelpers[serverAddress]; h != "" {
+		return fmt.Sprintf("credential helper docker-credential-%s", h), nil
+	}
+	if cf.CredentialsStore != "" {
+		return fmt.Sprintf("credential helper docker-credential-%s", cf.CredentialsStore), nil
+	}
+	ui.Warnf(ctx, "credentials are stored unencrypted in %s. Use --credential-helper to store them in a credential helper instead.", cf.Filename)
+	return cf.Filename, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+// newRegistry returns an in-memory registry that requires basic auth with
+// user/pass, and only grants push access to repositories under "writable/".
+func newRegistry(t *testing.T) string {
+	t.Helper()
+	reg := registry.New()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && !strings.HasPrefix(r.URL.Path, "/v2/writable/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+func readConfig(t *testing.T, dir string) map[string]interface{} {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		t.Fatal(err)
+	}
+	cfg := map[string]interface{}{}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestLoginCmd(t *testing.T) {
+	server := newRegistry(t)
+
+	tests := []struct {
+		name       string
+		opts       options.LoginOptions
+		wantErr    string
+		wantStored bool
+	}{{
+		name:       "valid credentials",
+		opts:       options.LoginOptions{Username: "user", Password: "pass", Probe: true},
+		wantStored: true,
+	}, {
+		name:    "rejected credentials",
+		opts:    options.LoginOptions{Username: "user", Password: "wrong", Probe: true},
+		wantErr: "rejected the credentials",
+	}, {
+		name:       "rejected credentials without probing",
+		opts:       options.LoginOptions{Username: "user", Password: "wrong"},
+		wantStored: true,
+	}, {
+		name:       "pull and push access",
+		opts:       options.LoginOptions{Username: "user", Password: "pass", Probe: true, ProbeRepository: "writable/app"},
+		wantStored: true,
+	}, {
+		name:    "no push access",
+		opts:    options.LoginOptions{Username: "user", Password: "pass", Probe: true, ProbeRepository: "readonly/app"},
+		wantErr: "do not grant push access",
+	}, {
+		name:    "missing password",
+		opts:    options.LoginOptions{Username: "user", Probe: true},
+		wantErr: "username and password, or an identity token, are required",
+	}, {
+		name:    "password and identity token",
+		opts:    options.LoginOptions{Password: "pass", IdentityToken: "token"},
+		wantErr: "only one of password and identity token",
+	}, {
+		name:       "identity token",
+		opts:       options.LoginOptions{IdentityToken: "token"},
+		wantStored: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("DOCKER_CONFIG", dir)
+			// Make sure no platform credential helper is picked up.
+			t.Setenv("PATH", "")
+
+			err := LoginCmd(context.Background(), tt.opts, server)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoginCmd() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("LoginCmd() unexpected error: %v", err)
+			}
+
+			auths, _ := readConfig(t, dir)["auths"].(map[string]interface{})
+			if _, stored := auths[server]; stored != tt.wantStored {
+				t.Errorf("credentials stored = %t, want %t", stored, tt.wantStored)
+			}
+		})
+	}
+}
+
+func TestLoginCmdCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper script requires a POSIX shell")
+	}
+	server := newRegistry(t)
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	// A fake helper that records what it was asked to store.
+	bin := t.TempDir()
+	stored := filepath.Join(bin, "stored")
+	script := "#!/bin/sh\ncat > " + stored + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "docker-credential-fake"), []byte(script), 0o700); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	opts := options.LoginOptions{Username: "user", Password: "pass", Probe: true, CredentialHelper: "fake"}
+	if err := LoginCmd(context.Background(), opts, server); err != nil {
+		t.Fatalf("LoginCmd() unexpected error: %v", err)
+	}
+
+	if b, _ := os.ReadFile(filepath.Join(dir, "config.json")); strings.Contains(string(b), `"pass"`) {
+		t.Error("password was written to the configuration file")
+	}
+	cfg := readConfig(t, dir)
+	if helpers, _ := cfg["credHelpers"].(map[string]interface{}); helpers[server] != "fake" {
+		t.Errorf("credHelpers = %v, want %s configured to use fake", cfg["credHelpers"], server)
+	}
+	b, err := os.ReadFile(stored)
+	if err != nil {
+		t.Fatalf("credential helper was not called: %v", err)
+	}
+	if !strings.Contains(string(b), `"Secret":"pass"`) {
+		t.Errorf("credential helper got %s, want the password", b)
+	}
+}
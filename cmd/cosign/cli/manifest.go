@@ -37,13 +37,19 @@ func Manifest() *cobra.Command {
 }
 
 func manifestVerify() *cobra.Command {
-	o := &options.VerifyOptions{}
+	o := &options.VerifyManifestOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "verify",
 		Short: "Verify all signatures of images specified in the manifest",
 		Long: `Verify all signature of images in a Kubernetes resource manifest by checking claims
-against the transparency log.`,
+against the transparency log.
+
+With --admission-review, the object of a Kubernetes AdmissionReview read from stdin
+is verified instead, and the AdmissionReview is written to stdout with the response,
+which denies the object if one of its images isn't verified. This lets cosign run
+behind a generic webhook shim, or be tested against recorded admission payloads.
+The exit status is 0 whenever a response is written.`,
 		Example: `  cosign manifest verify --key <key path>|<key url>|<kms uri> <path/to/manifest>
 
   # verify cosign claims and signing certificates on images in the manifest
@@ -68,14 +74,21 @@ against the transparency log.`,
   cosign manifest verify --key gcpkms://projects/[PROJECT]/locations/global/keyRings/[KEYRING]/cryptoKeys/[KEY] <path/to/my-deployment.yaml>
 
   # verify images with public key stored in Hashicorp Vault
-  cosign manifest verify --key hashivault://[KEY] <path/to/my-deployment.yaml>`,
-		Args:             cobra.ExactArgs(1),
+  cosign manifest verify --key hashivault://[KEY] <path/to/my-deployment.yaml>
+
+  # answer a recorded admission request
+  cosign manifest verify --key cosign.pub --admission-review < admission-review.json`,
+		Args:             cobra.MaximumNArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			annotations, err := o.AnnotationsMap()
 			if err != nil {
 				return err
 			}
+			signReportKeyOpts, err := o.SignReportKeyOpts()
+			if err != nil {
+				return err
+			}
 			v := &manifest.VerifyManifestCommand{
 				VerifyCommand: verify.VerifyCommand{
 					RegistryOptions:              o.Registry,
@@ -94,16 +107,42 @@ against the transparency log.`,
 					Sk:                           o.SecurityKey.Use,
 					Slot:                         o.SecurityKey.Slot,
 					Output:                       o.Output,
+					OutputTemplate:               o.OutputTemplate.Template,
+					GitHubSummary:                o.GitHubSummary,
 					RekorURL:                     o.Rekor.URL,
 					Attachment:                   o.Attachment,
 					Annotations:                  annotations,
 					LocalImage:                   o.LocalImage,
 					Offline:                      o.CommonVerifyOptions.Offline,
-					TSACertChainPath:             o.CommonVerifyOptions.TSACertChainPath,
+					TSACertChainPaths:            o.CommonVerifyOptions.TSACertChainPaths,
 					IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+					Denylist:                     o.CommonVerifyOptions.Denylist,
+					KeyUsagePolicy:               o.CommonVerifyOptions.KeyUsagePolicy,
+					Witnesses:                    o.CommonVerifyOptions.Witnesses,
+					WarningsAsErrors:             o.WarningsAsErrors,
+					SignReport:                   o.SignReport,
+					SignReportKeyOpts:            signReportKeyOpts,
+					SignReportCertificate:        o.SignReportCertificate,
+					SourceRepositories:           o.SourceRepositories,
+					Batch:                        o.Batch,
+					AllowConverted:               o.AllowConverted,
+					Recursive:                    o.Recursive,
+					LayerPolicy:                  o.LayerPolicy,
+					Quota:                        o.Quota,
+					EnforceExpiry:                o.EnforceExpiry,
+					Encryption:                   o.Encryption,
+					Countersigners:               o.Countersigners,
+					Cache:                        o.Cache,
+					Evaluation:                   o.Evaluation,
 				},
+				AdmissionReview: o.AdmissionReview,
+			}
+			if err := setImagePolicy(&v.VerifyCommand, &o.VerifyOptions); err != nil {
+				return err
 			}
-			return v.Exec(cmd.Context(), args)
+			return withFailureReport(cmd, args, o.CommonVerifyOptions.FailureReport, func() error {
+				return v.Exec(cmd.Context(), args)
+			})
 		},
 	}
 
@@ -0,0 +1,93 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// execAdmissionReview reads an AdmissionReview from in, verifies the images
+// of the object it admits and writes it to out with the response, which
+// allows the object only if all of them are verified.
+func (c *VerifyManifestCommand) execAdmissionReview(ctx context.Context, in io.Reader, out io.Writer) error {
+	if c.Output == verify.OutputJSONv1 || c.Output == verify.OutputSARIF || c.OutputTemplate != "" {
+		return errors.New("--admission-review writes the AdmissionReview to stdout, so it can't be used with --output json-v1, sarif or --output-template")
+	}
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(in).Decode(&review); err != nil {
+		return fmt.Errorf("decoding the AdmissionReview: %w", err)
+	}
+	if review.Request == nil {
+		return errors.New("the AdmissionReview has no request")
+	}
+	review.Response = c.review(ctx, review.Request)
+	review.Request = nil
+	return json.NewEncoder(out).Encode(&review)
+}
+
+// review returns the response to req, denying the object if one of its
+// images isn't verified.
+func (c *VerifyManifestCommand) review(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	resp := &admissionv1.AdmissionResponse{UID: req.UID, Allowed: true}
+	// Objects that are deleted have no object to admit.
+	if len(req.Object.Raw) == 0 {
+		return resp
+	}
+	images, err := getImagesFromYamlManifest(req.Object.Raw)
+	if err != nil {
+		resp.Allowed = false
+		resp.Result = &metav1.Status{Code: http.StatusBadRequest, Message: fmt.Sprintf("extracting the images of %s: %v", req.Kind.Kind, err)}
+		return resp
+	}
+	if len(images) == 0 {
+		return resp
+	}
+	fmt.Fprintf(os.Stderr, "Extracted image(s): %s\n", strings.Join(images, ", "))
+
+	// Only the response is written to stdout, including by the commands
+	// that --image-policy routes images to, which are copied from c.
+	c.OnVerified = func(context.Context, name.Reference, []oci.Signature) error {
+		return nil
+	}
+	var denied []string
+	for _, img := range images {
+		if err := c.VerifyCommand.Exec(ctx, []string{img}); err != nil {
+			denied = append(denied, fmt.Sprintf("%s: %v", img, err))
+		}
+	}
+	if len(denied) > 0 {
+		resp.Allowed = false
+		resp.Result = &metav1.Status{
+			Code:    http.StatusForbidden,
+			Message: "image signature verification failed for " + strings.Join(denied, "; "),
+		}
+	}
+	return resp
+}
@@ -0,0 +1,131 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	admissionv1 "k8s.io/api/admission/v1"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+func admissionReview(t *testing.T, object string) string {
+	t.Helper()
+	return `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview","request":{"uid":"705ab4f5-6393-11e8-b7cc-42010a800002",` +
+		`"kind":{"group":"","version":"v1","kind":"Pod"},"resource":{"group":"","version":"v1","resource":"pods"},` +
+		`"operation":"CREATE","userInfo":{},"object":` + object + `}}`
+}
+
+func TestExecAdmissionReview(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	img, err := random.Image(512, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.ParseReference(strings.TrimPrefix(srv.URL, "http://") + "/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return nil, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := filepath.Join(t.TempDir(), "cosign.pub")
+	if err := os.WriteFile(pub, keys.PublicBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		object  string
+		allowed bool
+		message string
+	}{{
+		name:    "unsigned image",
+		object:  `{"apiVersion":"v1","kind":"Pod","spec":{"containers":[{"name":"app","image":"` + ref.String() + `"}]}}`,
+		message: ref.String() + ": no signatures found",
+	}, {
+		name:    "no images",
+		object:  `{"apiVersion":"v1","kind":"ConfigMap","data":{"a":"b"}}`,
+		allowed: true,
+	}, {
+		name:    "deleted",
+		object:  `null`,
+		allowed: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &VerifyManifestCommand{VerifyCommand: verify.VerifyCommand{KeyRef: pub, CheckClaims: true, IgnoreTlog: true, IgnoreSCT: true}}
+			var out bytes.Buffer
+			if err := c.execAdmissionReview(context.Background(), strings.NewReader(admissionReview(t, tc.object)), &out); err != nil {
+				t.Fatal(err)
+			}
+			var review admissionv1.AdmissionReview
+			if err := json.Unmarshal(out.Bytes(), &review); err != nil {
+				t.Fatal(err)
+			}
+			if review.APIVersion != "admission.k8s.io/v1" || review.Kind != "AdmissionReview" || review.Request != nil {
+				t.Errorf("AdmissionReview = %s", out.Bytes())
+			}
+			resp := review.Response
+			if resp == nil || resp.UID != "705ab4f5-6393-11e8-b7cc-42010a800002" {
+				t.Fatalf("response = %+v, want the UID of the request", resp)
+			}
+			if resp.Allowed != tc.allowed {
+				t.Errorf("allowed = %t, want %t", resp.Allowed, tc.allowed)
+			}
+			if !tc.allowed && (resp.Result == nil || resp.Result.Code != http.StatusForbidden || !strings.Contains(resp.Result.Message, tc.message)) {
+				t.Errorf("result = %+v, want it to deny %s", resp.Result, tc.message)
+			}
+		})
+	}
+}
+
+func TestExecAdmissionReviewErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		c     VerifyManifestCommand
+		input string
+	}{
+		"output":     {c: VerifyManifestCommand{VerifyCommand: verify.VerifyCommand{Output: verify.OutputJSONv1}}, input: admissionReview(t, `{}`)},
+		"not JSON":   {input: "apiVersion: admission.k8s.io/v1"},
+		"no request": {input: `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview"}`},
+	} {
+		var out bytes.Buffer
+		if err := tc.c.execAdmissionReview(context.Background(), strings.NewReader(tc.input), &out); err == nil {
+			t.Errorf("execAdmissionReview() with %s: expected an error", name)
+		}
+		if out.Len() != 0 {
+			t.Errorf("execAdmissionReview() with %s wrote %s", name, out.Bytes())
+		}
+	}
+}
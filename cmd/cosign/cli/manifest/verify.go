@@ -33,10 +33,19 @@ import (
 // VerifyManifestCommand verifies all image signatures on a supplied k8s resource
 type VerifyManifestCommand struct {
 	verify.VerifyCommand
+	// AdmissionReview reads a Kubernetes AdmissionReview from stdin instead
+	// of a manifest, and writes it back with the response to stdout.
+	AdmissionReview bool
 }
 
 // Exec runs the verification command
 func (c *VerifyManifestCommand) Exec(ctx context.Context, args []string) error {
+	if c.AdmissionReview {
+		if len(args) != 0 {
+			return errors.New("--admission-review reads the AdmissionReview from stdin, not a manifest")
+		}
+		return c.execAdmissionReview(ctx, os.Stdin, os.Stdout)
+	}
 	if len(args) != 1 {
 		return flag.ErrHelp
 	}
@@ -0,0 +1,105 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/lintpipeline"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func MigrateFlags() *cobra.Command {
+	o := &options.MigrateFlagsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate-flags [FILE]...",
+		Short: "Rewrite the renamed flags of cosign invocations to their successors",
+		Long: `Rewrite the flags of the cosign invocations of shell scripts and CI
+configurations that were renamed, such as --cert to --certificate, to their
+successors. Renamed flags are accepted with a deprecation warning until the
+release they are removed in.
+
+Without FILE, a script is read from standard input. The rewritten scripts are
+printed to standard output, unless --write rewrites the files in place, and
+the renamed flags are listed on standard error.`,
+		Example: `  echo 'cosign verify --cert-identity me@example.com --cert-oidc-issuer https://accounts.google.com <IMAGE>' | cosign migrate-flags
+
+  # rewrite the GitHub Actions workflows in place
+  cosign migrate-flags --write .github/workflows/*.yml`,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MigrateFlagsCmd(cmd.Root(), *o, args, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// MigrateFlagsCmd rewrites the renamed flags of the cosign invocations of
+// files, or of stdin if files is empty, to the flags of root.
+func MigrateFlagsCmd(root *cobra.Command, o options.MigrateFlagsOptions, files []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(files) == 0 {
+		if o.Write {
+			return fmt.Errorf("--write needs the files to rewrite")
+		}
+		script, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		migrated := migrateFlags(root, "<stdin>", string(script), stderr)
+		_, err = io.WriteString(stdout, migrated)
+		return err
+	}
+
+	for _, f := range files {
+		script, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		migrated := migrateFlags(root, f, string(script), stderr)
+		if !o.Write {
+			if _, err := io.WriteString(stdout, migrated); err != nil {
+				return err
+			}
+			continue
+		}
+		if migrated == string(script) {
+			continue
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(f, []byte(migrated), info.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func migrateFlags(root *cobra.Command, name, script string, stderr io.Writer) string {
+	migrated, changes := lintpipeline.Migrate(root, script)
+	for _, c := range changes {
+		fmt.Fprintf(stderr, "%s:%d: %s: --%s -> --%s\n", name, c.Line, c.Command, c.Old, c.New)
+	}
+	return migrated
+}
@@ -0,0 +1,398 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+func Mutate() *cobra.Command {
+	o := &options.MutateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "mutate",
+		Short: "Set the annotations or labels of a signed image, and re-sign it",
+		Long: `Set the manifest annotations or config labels of a signed image, and sign
+the image that results with the key or keyless identity given.
+
+Mutating an image changes its digest, which leaves its signatures and
+attestations behind on the digest it had. cosign mutate pushes the mutated
+image, signs it, and carries forward the attestations of the image made by the
+same signer, re-signed for the new digest, before moving the tag of the image
+(or --tag) to it. The signed payload of the new signature records the digest
+of the image it was mutated from in the dev.sigstore.cosign/mutated-from
+annotation.
+
+The image must have a signature of the signer, so that mutating it doesn't
+re-sign an image that was never verified, unless --force is given. When
+signing keyless, --signed-by-identity and --signed-by-oidc-issuer identify the
+signatures and attestations of the signer.`,
+		Example: `  # set an annotation of an image signed with a key and re-sign it
+  cosign mutate --key cosign.key --annotation org.opencontainers.image.source=https://github.com/example/app example.com/app:v1
+
+  # set a label and push the result to another tag
+  cosign mutate --key cosign.key --label release=stable --tag example.com/app:stable example.com/app:v1
+
+  # set an annotation keyless, carrying forward the attestations of the CI identity
+  cosign mutate --annotation reviewed=true \
+    --signed-by-identity https://github.com/example/app/.github/workflows/release.yml@refs/heads/main \
+    --signed-by-oidc-issuer https://token.actions.githubusercontent.com example.com/app:v1`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oidcClientSecret, err := o.OIDC.ClientSecret()
+			if err != nil {
+				return err
+			}
+			ko := options.KeyOpts{
+				KeyRef:                   o.Key,
+				PassFunc:                 generate.GetPass,
+				Sk:                       o.SecurityKey.Use,
+				Slot:                     o.SecurityKey.Slot,
+				FulcioURL:                o.Fulcio.URL,
+				IDToken:                  o.Fulcio.IdentityToken,
+				InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                     o.Fulcio.ACME,
+				RekorURL:                 o.Rekor.URL,
+				OIDCIssuer:               o.OIDC.Issuer,
+				OIDCClientID:             o.OIDC.ClientID,
+				OIDCClientSecret:         oidcClientSecret,
+				OIDCRedirectURL:          o.OIDC.RedirectURL,
+				OIDCProvider:             o.OIDC.Provider,
+				OIDCTokenFile:            o.OIDC.TokenFile,
+				OIDCAudience:             o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
+				SkipConfirmation:         o.SkipConfirmation,
+				TSAServerURL:             o.TSAServerURL,
+			}
+			return MutateCmd(cmd.Context(), ko, *o, args[0], cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// MutateCmd sets the annotations and labels of o on the image imageRef,
+// pushes the mutated image and signs it with the signer of ko, carrying
+// forward the attestations of imageRef made by the same signer. It then
+// points the tag of imageRef, or o.Tag, at the mutated image and writes a
+// summary to out.
+func MutateCmd(ctx context.Context, ko options.KeyOpts, o options.MutateOptions, imageRef string, out io.Writer) error {
+	if len(o.Annotations) == 0 && len(o.Labels) == 0 {
+		return errors.New("nothing to mutate: give --annotation or --label")
+	}
+	if ko.KeyRef == "" && !ko.Sk && o.SignedByIdentity == "" && o.SignedByOIDCIssuer == "" {
+		return errors.New("--signed-by-identity or --signed-by-oidc-issuer is required when signing keyless, to verify the signatures of the image")
+	}
+	no := o.Registry.NameOptions()
+	ref, err := oci.ParseReference(imageRef, no...)
+	if err != nil {
+		return fmt.Errorf("parsing reference: %w", err)
+	}
+	var tag *name.Tag
+	if t, ok := ref.(name.Tag); ok {
+		tag = &t
+	}
+	if o.Tag != "" {
+		t, err := name.NewTag(o.Tag, no...)
+		if err != nil {
+			return fmt.Errorf("parsing --tag: %w", err)
+		}
+		tag = &t
+	}
+	ociremoteOpts, err := o.Registry.ClientOpts(ctx)
+	if err != nil {
+		return err
+	}
+	remoteOpts := o.Registry.GetRegistryClientOpts(ctx)
+	digest, err := ociremote.ResolveDigest(ref, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+
+	sv, err := sign.SignerFromKeyOpts(ctx, o.Cert, o.CertChain, ko)
+	if err != nil {
+		return fmt.Errorf("getting signer: %w", err)
+	}
+	defer sv.Close()
+	m, err := mutateSigner(sv, o)
+	if err != nil {
+		return err
+	}
+
+	// Check that the image is the signer's before re-signing it, and select
+	// its attestations to carry forward.
+	se, err := ociremote.SignedEntity(digest, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	signed, err := matchingSignatures(ctx, se.Signatures, m, false)
+	if err != nil {
+		return err
+	}
+	if len(signed) == 0 {
+		if !o.Force {
+			return fmt.Errorf("%s has no signatures made by %s, so mutating it would sign an image that wasn't verified. Use `-f` to mutate it anyway", digest, m)
+		}
+		ui.Warnf(ctx, "mutating %s, which has no signatures made by %s", digest, m)
+	}
+	atts, err := matchingSignatures(ctx, se.Attestations, m, true)
+	if err != nil {
+		return err
+	}
+
+	mutated, err := mutateImage(ref, digest, o, remoteOpts...)
+	if err != nil {
+		return err
+	}
+	h, err := mutated.Digest()
+	if err != nil {
+		return err
+	}
+	if h.String() == digest.DigestStr() {
+		fmt.Fprintf(out, "%s already has the annotations and labels given\n", digest)
+		return nil
+	}
+	newDigest := digest.Context().Digest(h.String())
+	if err := mutated.write(newDigest, remoteOpts...); err != nil {
+		return fmt.Errorf("pushing %s: %w", newDigest, err)
+	}
+
+	signOpts := options.SignOptions{Upload: true, TlogUpload: o.TlogUpload, Registry: o.Registry}
+	annotations := map[string]interface{}{cosign.MutatedFromAnnotation: digest.DigestStr()}
+	if err := sign.SignDigest(ctx, newDigest, ko, signOpts, annotations, sv); err != nil {
+		return fmt.Errorf("signing %s: %w", newDigest, err)
+	}
+	for _, att := range atts {
+		st, err := carryStatement(att, digest, newDigest)
+		if err != nil {
+			return err
+		}
+		c := attest.AttestCommand{
+			KeyOpts:         ko,
+			RegistryOptions: o.Registry,
+			PredicateType:   st.PredicateType,
+			TlogUpload:      o.TlogUpload,
+			SignerVerifier:  sv,
+			GenerateStatement: func(context.Context, name.Digest) (interface{}, error) {
+				return st, nil
+			},
+		}
+		if err := c.Exec(ctx, newDigest.String()); err != nil {
+			return fmt.Errorf("attesting %s of %s: %w", st.PredicateType, newDigest, err)
+		}
+	}
+
+	// The tag is moved last, so that it never points at an unsigned image.
+	if tag != nil {
+		if err := remote.Tag(*tag, mutated.taggable, remoteOpts...); err != nil {
+			return fmt.Errorf("tagging %s: %w", tag, err)
+		}
+		newDigest = tag.Context().Digest(h.String())
+		fmt.Fprintf(out, "Mutated %s into %s, now tagged %s, signed by %s with %d attestations carried forward\n", digest, newDigest, tag, m, len(atts))
+		return nil
+	}
+	fmt.Fprintf(out, "Mutated %s into %s, signed by %s with %d attestations carried forward\n", digest, newDigest, m, len(atts))
+	return nil
+}
+
+// mutateSigner returns the SignerMatcher of the signatures of sv: the key of
+// sv, or the identity of o if sv signs with a certificate.
+func mutateSigner(sv *sign.SignerVerifier, o options.MutateOptions) (*SignerMatcher, error) {
+	if sv.Cert != nil {
+		if o.SignedByIdentity == "" && o.SignedByOIDCIssuer == "" {
+			return nil, errors.New("--signed-by-identity or --signed-by-oidc-issuer is required when signing with a certificate")
+		}
+		return &SignerMatcher{identity: cosign.Identity{Subject: o.SignedByIdentity, Issuer: o.SignedByOIDCIssuer}}, nil
+	}
+	pub, err := sv.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	fp, err := cosign.KeyFingerprint(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &SignerMatcher{verifier: sv, fingerprint: fp}, nil
+}
+
+// matchingSignatures returns the signatures, or attestations, of list made
+// by the signer of m.
+func matchingSignatures(ctx context.Context, list func() (oci.Signatures, error), m *SignerMatcher, attestation bool) ([]oci.Signature, error) {
+	s, err := list()
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.Get()
+	if err != nil {
+		return nil, err
+	}
+	var matched []oci.Signature
+	for _, sig := range all {
+		ok, err := m.Match(ctx, sig, attestation)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, sig)
+		}
+	}
+	return matched, nil
+}
+
+// mutatedImage is an image or index mutated by mutateImage.
+type mutatedImage struct {
+	taggable remote.Taggable
+	image    v1.Image
+	index    v1.ImageIndex
+}
+
+func (m *mutatedImage) Digest() (v1.Hash, error) {
+	if m.index != nil {
+		return m.index.Digest()
+	}
+	return m.image.Digest()
+}
+
+func (m *mutatedImage) write(ref name.Reference, opts ...remote.Option) error {
+	if m.index != nil {
+		return remote.WriteIndex(ref, m.index, opts...)
+	}
+	return remote.Write(ref, m.image, opts...)
+}
+
+// mutateImage sets the annotations and labels of o on the image or index at
+// digest, which ref names.
+func mutateImage(ref name.Reference, digest name.Digest, o options.MutateOptions, opts ...remote.Option) (*mutatedImage, error) {
+	desc, err := remote.Get(digest, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if desc.MediaType.IsIndex() {
+		if len(o.Labels) > 0 {
+			return nil, fmt.Errorf("%s is an image index, whose labels can't be set", ref)
+		}
+		ii, err := desc.ImageIndex()
+		if err != nil {
+			return nil, err
+		}
+		if len(o.Annotations) > 0 {
+			ii = mutate.Annotations(ii, o.Annotations).(v1.ImageIndex)
+		}
+		return &mutatedImage{taggable: ii, index: ii}, nil
+	}
+
+	img, err := desc.Image()
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Labels) > 0 {
+		cf, err := img.ConfigFile()
+		if err != nil {
+			return nil, err
+		}
+		cfg := cf.Config.DeepCopy()
+		if cfg.Labels == nil {
+			cfg.Labels = map[string]string{}
+		}
+		for k, v := range o.Labels {
+			cfg.Labels[k] = v
+		}
+		if img, err = mutate.Config(img, *cfg); err != nil {
+			return nil, err
+		}
+	}
+	if len(o.Annotations) > 0 {
+		img = mutate.Annotations(img, o.Annotations).(v1.Image)
+	}
+	return &mutatedImage{taggable: img, image: img}, nil
+}
+
+// carriedStatement is the in-toto statement of an attestation carried
+// forward by MutateCmd, whose predicate is kept as it was signed.
+type carriedStatement struct {
+	Type          string            `json:"_type"`
+	PredicateType string            `json:"predicateType"`
+	Subject       []in_toto.Subject `json:"subject"`
+	Predicate     json.RawMessage   `json:"predicate"`
+}
+
+// carryStatement returns the statement of att, an attestation of from,
+// with the subjects of from replaced by to.
+func carryStatement(att oci.Signature, from, to name.Digest) (*carriedStatement, error) {
+	p, err := att.Payload()
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(p, &envelope); err != nil {
+		return nil, fmt.Errorf("decoding attestation: %w", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decoding attestation payload: %w", err)
+	}
+	st := &carriedStatement{}
+	if err := json.Unmarshal(raw, st); err != nil {
+		return nil, fmt.Errorf("decoding attestation statement: %w", err)
+	}
+	fromHash, err := oci.ParseDigest(from.DigestStr())
+	if err != nil {
+		return nil, err
+	}
+	toHash, err := oci.ParseDigest(to.DigestStr())
+	if err != nil {
+		return nil, err
+	}
+	replaced := false
+	for i, s := range st.Subject {
+		if s.Digest[fromHash.Algorithm] == fromHash.Hex {
+			st.Subject[i] = in_toto.Subject{
+				Name:   to.Repository.String(),
+				Digest: map[string]string{toHash.Algorithm: toHash.Hex},
+			}
+			replaced = true
+		}
+	}
+	if !replaced {
+		return nil, fmt.Errorf("the %s attestation of %s has no subject of its digest", st.PredicateType, from)
+	}
+	return st, nil
+}
@@ -0,0 +1,194 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	slsa "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+func TestMutateCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := filepath.Join(td, "cosign.key")
+	if err := os.WriteFile(keyRef, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pubRef := filepath.Join(td, "cosign.pub")
+	if err := os.WriteFile(pubRef, keys.PublicBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ko := options.KeyOpts{KeyRef: keyRef, SkipConfirmation: true}
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := name.NewTag(host + "/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(tag, img); err != nil {
+		t.Fatal(err)
+	}
+	digest := tag.Context().Digest(h.String())
+
+	o := options.MutateOptions{Annotations: map[string]string{"reviewed": "true"}, Labels: map[string]string{"release": "stable"}}
+	if err := MutateCmd(ctx, ko, options.MutateOptions{}, tag.String(), io.Discard); err == nil || !strings.Contains(err.Error(), "nothing to mutate") {
+		t.Errorf("MutateCmd() without mutations = %v", err)
+	}
+	if err := MutateCmd(ctx, ko, o, tag.String(), io.Discard); err == nil || !strings.Contains(err.Error(), "no signatures made by") {
+		t.Errorf("MutateCmd() of an unsigned image = %v", err)
+	}
+
+	// Sign the image, and attest it with the key and with another key.
+	sv, err := sign.SignerFromKeyOpts(ctx, "", "", ko)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sign.SignDigest(ctx, digest, ko, options.SignOptions{Upload: true}, nil, sv); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := newTestKey(t)
+	atts, err := mutate.AppendSignatures(empty.Signatures(),
+		attestPayload(t, sv, digest, slsa.PredicateSLSAProvenance),
+		attestPayload(t, other, digest, "https://example.com/review/v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	attTag, err := ociremote.AttestationTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(attTag, atts); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MutateCmd(ctx, ko, o, tag.String(), io.Discard); err != nil {
+		t.Fatalf("MutateCmd() = %v", err)
+	}
+	desc, err := remote.Get(tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Digest == h {
+		t.Fatalf("%s still points at the image it was mutated from", tag)
+	}
+	mutated, err := desc.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := mutated.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf, err := mutated.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Annotations["reviewed"] != "true" || cf.Config.Labels["release"] != "stable" {
+		t.Errorf("mutated image has annotations %v and labels %v", m.Annotations, cf.Config.Labels)
+	}
+
+	newDigest := tag.Context().Digest(desc.Digest.String())
+	se, err := ociremote.SignedEntity(newDigest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matcher, err := NewSignerMatcher(ctx, pubRef, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := matchingSignatures(ctx, se.Signatures, matcher, false)
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("mutated image has signatures %v, %v, want 1 of the key", sigs, err)
+	}
+	p, err := sigs[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sci := payload.SimpleContainerImage{}
+	if err := json.Unmarshal(p, &sci); err != nil {
+		t.Fatal(err)
+	}
+	if sci.Critical.Image.DockerManifestDigest != desc.Digest.String() || sci.Optional[cosign.MutatedFromAnnotation] != h.String() {
+		t.Errorf("signed payload = %s, want the mutated digest and the digest it was mutated from", p)
+	}
+
+	carried, err := matchingSignatures(ctx, se.Attestations, matcher, true)
+	if err != nil || len(carried) != 1 {
+		t.Fatalf("mutated image has attestations %v, %v, want the 1 of the key", carried, err)
+	}
+	if got := predicateType(carried[0]); got != slsa.PredicateSLSAProvenance {
+		t.Errorf("carried attestation has predicate type %q", got)
+	}
+	p, err = carried[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(p, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := in_toto.Statement{}
+	if err := json.Unmarshal(raw, &st); err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Subject) != 1 || st.Subject[0].Digest["sha256"] != desc.Digest.Hex {
+		t.Errorf("carried attestation has subjects %v, want the mutated image", st.Subject)
+	}
+}
@@ -0,0 +1,58 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ACMEOptions configures the requests of signing certificates from an ACME
+// CA, named with --fulcio-url acme:<directory URL>.
+type ACMEOptions struct {
+	Profile      string
+	Identifiers  []string
+	AccountKey   string
+	EABKeyID     string
+	EABHMACKey   string
+	HTTP01Listen string
+}
+
+var _ Interface = (*ACMEOptions)(nil)
+
+// AddFlags implements Interface
+func (o *ACMEOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Profile, "acme-profile", "",
+		"profile of the ACME CA of --fulcio-url acme:<directory URL> to order the certificate with, e.g. one of short-lived code signing certificates. "+
+			"Must be one of the profiles the directory of the CA lists, if it lists any")
+
+	cmd.Flags().StringSliceVar(&o.Identifiers, "acme-identifier", nil,
+		"identifier to order the certificate from the ACME CA for, and that verifiers match with --certificate-identity, "+
+			"as dns:<name>, ip:<address> or email:<address>, or a DNS name (can be repeated)")
+
+	cmd.Flags().StringVar(&o.AccountKey, "acme-account-key", "",
+		"path to the PEM-encoded private key of the ACME account, which is registered if new. Without one, an account is registered with a new key for each certificate")
+	_ = cmd.Flags().SetAnnotation("acme-account-key", cobra.BashCompFilenameExt, []string{"key"})
+
+	cmd.Flags().StringVar(&o.EABKeyID, "acme-eab-kid", "",
+		"key identifier of the external account binding to register the ACME account with, for CAs that require one")
+
+	cmd.Flags().StringVar(&o.EABHMACKey, "acme-eab-hmac-key", "",
+		"base64url-encoded HMAC key of the external account binding of --acme-eab-kid")
+
+	cmd.Flags().StringVar(&o.HTTP01Listen, "acme-http01-address", "",
+		"address to serve the http-01 challenges of the ACME CA on, e.g. :80, for identifiers that the CA hasn't authorized the account for. "+
+			"Without one, the CA must have authorized them already")
+}
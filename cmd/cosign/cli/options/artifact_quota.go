@@ -0,0 +1,52 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ArtifactQuotaOptions is the wrapper for the quotas of the signatures and
+// attestations attached to an image, and the predicate types that it must
+// have verified attestations of.
+type ArtifactQuotaOptions struct {
+	MaxSignatures         int
+	MaxAttestations       int
+	MaxArtifactSize       int64
+	RequirePredicateTypes []string
+}
+
+var _ Interface = (*ArtifactQuotaOptions)(nil)
+
+// AddFlags implements Interface
+func (o *ArtifactQuotaOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&o.MaxSignatures, "max-signatures", 0,
+		"fail if more signatures than this, verified or not, are attached to an image. 0 allows any number")
+
+	cmd.Flags().IntVar(&o.MaxAttestations, "max-attestations", 0,
+		"fail if more attestations than this, verified or not, are attached to an image. 0 allows any number")
+
+	cmd.Flags().Int64Var(&o.MaxArtifactSize, "max-artifact-size", 0,
+		"fail if a signature or attestation attached to an image is larger than this many bytes. 0 allows any size")
+
+	cmd.Flags().StringSliceVar(&o.RequirePredicateTypes, "require-predicate-type", nil,
+		"fail unless an image has an attestation of this predicate type, by name or URI, verified like its signatures (can be repeated)")
+}
+
+// Enabled reports whether a quota or required predicate type is set.
+func (o *ArtifactQuotaOptions) Enabled() bool {
+	return o.MaxSignatures > 0 || o.MaxAttestations > 0 || o.MaxArtifactSize > 0 || len(o.RequirePredicateTypes) > 0
+}
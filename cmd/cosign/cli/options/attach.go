@@ -27,11 +27,12 @@ import (
 
 // AttachSignatureOptions is the top level wrapper for the attach signature command.
 type AttachSignatureOptions struct {
-	Signature string
-	Payload   string
-	Cert      string
-	CertChain string
-	Registry  RegistryOptions
+	Signature  string
+	Payload    string
+	Cert       string
+	CertChain  string
+	Registry   RegistryOptions
+	LocalImage LocalImageOptions
 }
 
 var _ Interface = (*AttachSignatureOptions)(nil)
@@ -39,6 +40,7 @@ var _ Interface = (*AttachSignatureOptions)(nil)
 // AddFlags implements Interface
 func (o *AttachSignatureOptions) AddFlags(cmd *cobra.Command) {
 	o.Registry.AddFlags(cmd)
+	o.LocalImage.AddFlags(cmd)
 
 	cmd.Flags().StringVar(&o.Signature, "signature", "",
 		"path to the signature, or {-} for stdin")
@@ -76,8 +78,8 @@ func (o *AttachSBOMOptions) AddFlags(cmd *cobra.Command) {
 		"path to the sbom, or {-} for stdin")
 	_ = cmd.Flags().SetAnnotation("sbom", cobra.BashCompFilenameExt, []string{})
 
-	cmd.Flags().StringVar(&o.SBOMType, "type", "spdx",
-		"type of sbom (spdx|cyclonedx|syft)")
+	cmd.Flags().StringVar(&o.SBOMType, "type", "",
+		"type of sbom (spdx|cyclonedx|syft), detected from the content if not set")
 
 	cmd.Flags().StringVar(&o.SBOMInputFormat, "input-format", "",
 		"type of sbom input format (json|xml|text)")
@@ -98,7 +100,7 @@ func (o *AttachSBOMOptions) MediaType() (types.MediaType, error) {
 		}
 		return ctypes.CycloneDXXMLMediaType, nil
 
-	case "spdx":
+	case "spdx", "":
 		if o.SBOMInputFormat != "" && o.SBOMInputFormat != ctypes.TextInputFormat && o.SBOMInputFormat != ctypes.JSONInputFormat {
 			return "invalid", fmt.Errorf("invalid SBOM input format: %q, expected (json|text)", o.SBOMInputFormat)
 		}
@@ -120,11 +122,13 @@ func (o *AttachSBOMOptions) MediaType() (types.MediaType, error) {
 type AttachAttestationOptions struct {
 	Attestations []string
 	Registry     RegistryOptions
+	LocalImage   LocalImageOptions
 }
 
 // AddFlags implements Interface
 func (o *AttachAttestationOptions) AddFlags(cmd *cobra.Command) {
 	o.Registry.AddFlags(cmd)
+	o.LocalImage.AddFlags(cmd)
 
 	cmd.Flags().StringArrayVarP(&o.Attestations, "attestation", "", nil,
 		"path to the attestation envelope")
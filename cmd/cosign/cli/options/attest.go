@@ -30,6 +30,11 @@ type AttestOptions struct {
 	SkipConfirmation bool
 	TlogUpload       bool
 	TSAServerURL     string
+	SBOMFromImage    bool
+	SBOMGenerator    string
+	BuildFromImage   bool
+	Journal          string
+	PublishRetries   int
 
 	Rekor       RekorOptions
 	Fulcio      FulcioOptions
@@ -37,6 +42,10 @@ type AttestOptions struct {
 	SecurityKey SecurityKeyOptions
 	Predicate   PredicateLocalOptions
 	Registry    RegistryOptions
+	DryRun      DryRunOptions
+	LocalImage  LocalImageOptions
+
+	RegistryExperimental RegistryExperimentalOptions
 }
 
 var _ Interface = (*AttestOptions)(nil)
@@ -49,6 +58,9 @@ func (o *AttestOptions) AddFlags(cmd *cobra.Command) {
 	o.OIDC.AddFlags(cmd)
 	o.Rekor.AddFlags(cmd)
 	o.Registry.AddFlags(cmd)
+	o.DryRun.AddFlags(cmd)
+	o.LocalImage.AddFlags(cmd)
+	o.RegistryExperimental.AddFlags(cmd)
 
 	cmd.Flags().StringVar(&o.Key, "key", "",
 		"path to the private key file, KMS URI or Kubernetes Secret")
@@ -69,7 +81,7 @@ func (o *AttestOptions) AddFlags(cmd *cobra.Command) {
 		"do not upload the generated attestation")
 
 	cmd.Flags().BoolVarP(&o.Recursive, "recursive", "r", false,
-		"if a multi-arch image is specified, additionally sign each discrete image")
+		"if a multi-arch image is specified, additionally attest each discrete image, with one attestation whose subjects are all of them")
 
 	cmd.Flags().BoolVarP(&o.Replace, "replace", "", false,
 		"")
@@ -82,4 +94,22 @@ func (o *AttestOptions) AddFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringVar(&o.TSAServerURL, "timestamp-server-url", "",
 		"url to the Timestamp RFC3161 server, default none. Must be the path to the API to request timestamp responses, e.g. https://freetsa.org/tsr")
+
+	cmd.Flags().BoolVar(&o.SBOMFromImage, "sbom-from-image", false,
+		"generate an SBOM of the image with the SBOM generator plugin and attest it, instead of reading --predicate")
+
+	cmd.Flags().StringVar(&o.SBOMGenerator, "sbom-generator", "",
+		"SBOM generator plugin used with --sbom-from-image: the name of a cosign-sbom-<name> executable on the PATH, or the path to an executable. "+
+			"Defaults to $COSIGN_SBOM_GENERATOR")
+
+	cmd.Flags().BoolVar(&o.BuildFromImage, "build-metadata-from-image", false,
+		"generate a buildmetadata predicate of the base image, the commands of the layers and the labels recorded in the config and history of the image "+
+			"and attest it, instead of reading --predicate, for images built by tools that emit no provenance")
+
+	cmd.Flags().StringVar(&o.Journal, "journal", "",
+		"directory to journal the attestations whose transparency log upload or registry push fails in, so that attesting the same statement again "+
+			"resumes their publication with the same envelope instead of leaving them half-published. Defaults to .cosign/journal in the home directory")
+
+	cmd.Flags().IntVar(&o.PublishRetries, "publish-retries", 3,
+		"number of times a failed transparency log upload or registry push of the attestation is retried, with exponential backoff")
 }
@@ -30,12 +30,14 @@ type AttestBlobOptions struct {
 	RFC3161TimestampPath string
 
 	Hash      string
+	Digest    BlobDigestOptions
 	Predicate PredicateLocalOptions
 
 	OutputSignature   string
 	OutputAttestation string
 	OutputCertificate string
 	BundlePath        string
+	OutputDir         OutputDirOptions
 
 	Rekor       RekorOptions
 	Fulcio      FulcioOptions
@@ -52,6 +54,8 @@ func (o *AttestBlobOptions) AddFlags(cmd *cobra.Command) {
 	o.Fulcio.AddFlags(cmd)
 	o.OIDC.AddFlags(cmd)
 	o.SecurityKey.AddFlags(cmd)
+	o.OutputDir.AddFlags(cmd)
+	o.Digest.AddFlags(cmd)
 
 	cmd.Flags().StringVar(&o.Key, "key", "",
 		"path to the private key file, KMS URI or Kubernetes Secret")
@@ -84,7 +88,7 @@ func (o *AttestBlobOptions) AddFlags(cmd *cobra.Command) {
 	_ = cmd.Flags().SetAnnotation("bundle", cobra.BashCompFilenameExt, []string{})
 
 	cmd.Flags().StringVar(&o.Hash, "hash", "",
-		"hash of blob in hexadecimal (base16). Used if you want to sign an artifact stored elsewhere and have the hash")
+		"hash of blob in hexadecimal (base16), of the --digest-algorithm. Used if you want to sign an artifact stored elsewhere and have the hash")
 
 	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
 		"skip confirmation prompts for non-destructive operations")
@@ -0,0 +1,96 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// AuditClusterOptions is the top level wrapper for the audit cluster command.
+type AuditClusterOptions struct {
+	Policy     string
+	Namespaces []string
+	Workers    int
+	Output     string
+	OutputFile string
+	Rekor      RekorOptions
+	Registry   RegistryOptions
+}
+
+var _ Interface = (*AuditClusterOptions)(nil)
+
+// AddFlags implements Interface
+func (o *AuditClusterOptions) AddFlags(cmd *cobra.Command) {
+	o.Rekor.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Policy, "policy", "",
+		"path to a JSON policy, as read by cosign proxy --policy, with the key or certificate identity the images of each repository must be verified with")
+	_ = cmd.Flags().SetAnnotation("policy", cobra.BashCompFilenameExt, []string{"json"})
+	_ = cmd.MarkFlagRequired("policy")
+
+	cmd.Flags().StringSliceVarP(&o.Namespaces, "namespace", "n", nil,
+		"only audit the pods of this namespace. May be repeated, or comma-separated. Defaults to all namespaces")
+
+	cmd.Flags().IntVar(&o.Workers, "workers", 0,
+		"maximum number of images verified in parallel. Defaults to the number of CPUs")
+
+	cmd.Flags().StringVar(&o.Output, "output", "json",
+		"format of the compliance report: json or markdown")
+
+	cmd.Flags().StringVar(&o.OutputFile, "output-file", "",
+		"write the compliance report to this file instead of stdout")
+	_ = cmd.Flags().SetAnnotation("output-file", cobra.BashCompFilenameExt, []string{})
+}
+
+// AuditRegistryOptions is the top level wrapper for the audit registry
+// command.
+type AuditRegistryOptions struct {
+	Policy            string
+	Address           string
+	Results           string
+	Workers           int
+	AuthorizationFile string
+	Rekor             RekorOptions
+	Registry          RegistryOptions
+}
+
+var _ Interface = (*AuditRegistryOptions)(nil)
+
+// AddFlags implements Interface
+func (o *AuditRegistryOptions) AddFlags(cmd *cobra.Command) {
+	o.Rekor.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Policy, "policy", "",
+		"path to a JSON policy, as read by cosign proxy --policy, with the key or certificate identity the images of each repository must be verified with")
+	_ = cmd.Flags().SetAnnotation("policy", cobra.BashCompFilenameExt, []string{"json"})
+	_ = cmd.MarkFlagRequired("policy")
+
+	cmd.Flags().StringVar(&o.Address, "address", "localhost:8080",
+		"address the webhook of the registry notifications listens on")
+
+	cmd.Flags().StringVar(&o.Results, "results", "",
+		"path to the results database, a file of JSON lines that the result of each verification is appended to. Images it records are not verified again")
+	_ = cmd.Flags().SetAnnotation("results", cobra.BashCompFilenameExt, []string{})
+	_ = cmd.MarkFlagRequired("results")
+
+	cmd.Flags().IntVar(&o.Workers, "workers", 0,
+		"maximum number of images verified in parallel. Defaults to the number of CPUs")
+
+	cmd.Flags().StringVar(&o.AuthorizationFile, "authorization-file", "",
+		"path to a file with the value of the Authorization header that registry notifications must have, as configured in the registry")
+	_ = cmd.Flags().SetAnnotation("authorization-file", cobra.BashCompFilenameExt, []string{})
+}
@@ -0,0 +1,102 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// BackfillOptions is the top level wrapper for the backfill command.
+type BackfillOptions struct {
+	Key              string
+	Cert             string
+	CertChain        string
+	SkipConfirmation bool
+	TlogUpload       bool
+	TSAServerURL     string
+	AllRepositories  bool
+	RepositoryRegexp string
+	TagRegexp        string
+	IncludeSigned    bool
+	Note             string
+	Report           string
+	DryRun           bool
+
+	Rekor       RekorOptions
+	Fulcio      FulcioOptions
+	OIDC        OIDCOptions
+	SecurityKey SecurityKeyOptions
+	Registry    RegistryOptions
+	Batch       BatchOptions
+}
+
+var _ Interface = (*BackfillOptions)(nil)
+
+// AddFlags implements Interface
+func (o *BackfillOptions) AddFlags(cmd *cobra.Command) {
+	o.Rekor.AddFlags(cmd)
+	o.Fulcio.AddFlags(cmd)
+	o.OIDC.AddFlags(cmd)
+	o.SecurityKey.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+	o.Batch.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"path to the private key file, KMS URI or Kubernetes Secret")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{"key"})
+
+	cmd.Flags().StringVar(&o.Cert, "certificate", "",
+		"path to the X.509 certificate in PEM format to include in the OCI Signature")
+	_ = cmd.Flags().SetAnnotation("certificate", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().StringVar(&o.CertChain, "certificate-chain", "",
+		"path to a list of CA X.509 certificates in PEM format which will be needed "+
+			"when building the certificate chain for the signing certificate. "+
+			"Must start with the parent intermediate CA certificate of the "+
+			"signing certificate and end with the root certificate. Included in the OCI Signature")
+	_ = cmd.Flags().SetAnnotation("certificate-chain", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
+		"skip confirmation prompts for non-destructive operations")
+
+	cmd.Flags().BoolVar(&o.TlogUpload, "tlog-upload", true,
+		"whether or not to upload to the tlog")
+
+	cmd.Flags().StringVar(&o.TSAServerURL, "timestamp-server-url", "",
+		"url to the Timestamp RFC3161 server, default none. Must be the path to the API to request timestamp responses, e.g. https://freetsa.org/tsr")
+
+	cmd.Flags().BoolVar(&o.AllRepositories, "all-repositories", false,
+		"the arguments are registries, whose repositories are listed from their catalog and backfilled")
+
+	cmd.Flags().StringVar(&o.RepositoryRegexp, "repository-regexp", "",
+		"with --all-repositories, only backfill the repositories whose name matches this regular expression")
+
+	cmd.Flags().StringVar(&o.TagRegexp, "tag-regexp", "",
+		"only sign the images of the tags matching this regular expression")
+
+	cmd.Flags().BoolVar(&o.IncludeSigned, "include-signed", false,
+		"also sign the images that already have signatures")
+
+	cmd.Flags().StringVar(&o.Note, "note", "",
+		"note recorded in the signed payload of each signature, e.g. why the images are backfilled")
+
+	cmd.Flags().StringVar(&o.Report, "report", "",
+		"write a JSON report of each image found, and whether it was signed, to FILE")
+	_ = cmd.Flags().SetAnnotation("report", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false,
+		"only report the images that would be signed, without signing them")
+}
@@ -0,0 +1,59 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// BatchOptions is the wrapper for the state file of commands that operate on
+// many images.
+type BatchOptions struct {
+	StateFile string
+	Resume    bool
+}
+
+var _ Interface = (*BatchOptions)(nil)
+
+// AddFlags implements Interface
+func (o *BatchOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.StateFile, "state-file", "",
+		"record the completed images in FILE, one per line, so that a failed or interrupted run can be continued with --resume")
+	_ = cmd.Flags().SetAnnotation("state-file", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().BoolVar(&o.Resume, "resume", false,
+		"skip the images recorded in --state-file by a previous run, and keep recording there")
+}
+
+// VerifyBatchOptions is the wrapper for the images that `verify` reads from a
+// batch file and verifies in parallel.
+type VerifyBatchOptions struct {
+	File    string
+	Workers int
+}
+
+var _ Interface = (*VerifyBatchOptions)(nil)
+
+// AddFlags implements Interface
+func (o *VerifyBatchOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.File, "batch-file", "",
+		"also verify the images listed in FILE, or - to read them from standard input, one per line, "+
+			"verifying up to --batch-workers of them in parallel; blank lines and lines starting with # are skipped")
+	_ = cmd.Flags().SetAnnotation("batch-file", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().IntVar(&o.Workers, "batch-workers", 0,
+		"the number of images of --batch-file verified in parallel, the number of CPUs if 0, at most the workers of the budget config file")
+}
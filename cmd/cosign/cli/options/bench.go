@@ -0,0 +1,77 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// BenchOptions is the top level wrapper for the bench command.
+type BenchOptions struct {
+	Key              string
+	Operations       []string
+	Parallelism      []int
+	PayloadSizes     []int
+	Count            int
+	TlogUpload       bool
+	TSAServerURL     string
+	Output           string
+	SkipConfirmation bool
+	SecurityKey      SecurityKeyOptions
+	Fulcio           FulcioOptions
+	Rekor            RekorOptions
+	OIDC             OIDCOptions
+}
+
+var _ Interface = (*BenchOptions)(nil)
+
+// AddFlags implements Interface
+func (o *BenchOptions) AddFlags(cmd *cobra.Command) {
+	o.SecurityKey.AddFlags(cmd)
+	o.Fulcio.AddFlags(cmd)
+	o.Rekor.AddFlags(cmd)
+	o.OIDC.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"path to the private key file, KMS URI or Kubernetes Secret to sign with, instead of a certificate from Fulcio")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringSliceVar(&o.Operations, "operation", []string{"sign", "verify"},
+		"operations to measure, sign or verify (can be repeated)")
+
+	cmd.Flags().IntSliceVar(&o.Parallelism, "parallelism", []int{1, 4, 16},
+		"numbers of operations run concurrently to measure each operation with (can be repeated)")
+
+	cmd.Flags().IntSliceVar(&o.PayloadSizes, "payload-size", []int{1 << 10, 1 << 20},
+		"sizes in bytes of the random payloads to measure each operation with (can be repeated)")
+
+	cmd.Flags().IntVar(&o.Count, "count", 100,
+		"number of operations of each run, for each operation, parallelism and payload size")
+
+	cmd.Flags().BoolVar(&o.TlogUpload, "tlog-upload", false,
+		"upload every signature to the transparency log, and look it up there when verifying. "+
+			"This adds --count entries to the log for every run, so only do it against your own Rekor")
+
+	cmd.Flags().StringVar(&o.TSAServerURL, "timestamp-server-url", "",
+		"url to the Timestamp RFC3161 server to timestamp every signature with, default none")
+
+	cmd.Flags().StringVar(&o.Output, "output-file", "",
+		"write the JSON report to FILE instead of stdout")
+	_ = cmd.Flags().SetAnnotation("output-file", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
+		"skip confirmation prompts for non-destructive operations")
+}
@@ -0,0 +1,40 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/pkg/blob"
+)
+
+// BlobDigestOptions selects the digest algorithm blobs are signed, verified
+// and attested with.
+type BlobDigestOptions struct {
+	Algorithm string
+}
+
+var _ Interface = (*BlobDigestOptions)(nil)
+
+// AddFlags implements Interface
+func (o *BlobDigestOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Algorithm, "digest-algorithm", blob.DefaultDigestAlgorithm,
+		"digest algorithm of the blob ("+strings.Join(blob.DigestAlgorithmNames(), "|")+"). "+
+			"Blob signatures with another algorithm than sha256 are over the digest, which requires an ECDSA key and --tlog-upload=false or --insecure-ignore-tlog. "+
+			"Attestations name the digest in the subject of their statement")
+}
@@ -0,0 +1,81 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// BundleExportOptions is the top level wrapper for the `bundle export` command.
+type BundleExportOptions struct {
+	Output     string
+	TlogProofs bool
+	CTProofs   bool
+	CTLogURL   string
+	Rekor      RekorOptions
+	Registry   RegistryOptions
+}
+
+var _ Interface = (*BundleExportOptions)(nil)
+
+// AddFlags implements Interface
+func (o *BundleExportOptions) AddFlags(cmd *cobra.Command) {
+	o.Rekor.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Output, "output", "",
+		"write the bundle to FILE instead of standard output")
+	_ = cmd.Flags().SetAnnotation("output", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().BoolVar(&o.TlogProofs, "tlog-proofs", true,
+		"include the inclusion proof of the Rekor entry of each signature, fetched from --rekor-url")
+
+	cmd.Flags().BoolVar(&o.CTProofs, "ct-proofs", false,
+		"include the inclusion proof of the certificate of each keyless signature in the certificate transparency log of its SCT, "+
+			"for verifying with --require-ct-inclusion")
+
+	cmd.Flags().StringVar(&o.CTLogURL, "ct-log-url", "",
+		"URL of the certificate transparency log to fetch the inclusion proofs of --ct-proofs from, instead of the URL of the log in the TUF root")
+}
+
+// OfflineBundleOptions is the wrapper for verifying an image with the bundle
+// of `cosign bundle export` and a pinned trusted root, or with pre-fetched
+// Rekor entries.
+type OfflineBundleOptions struct {
+	BundleFile     string
+	TrustedRoot    string
+	RekorBundleDir string
+}
+
+var _ Interface = (*OfflineBundleOptions)(nil)
+
+// AddFlags implements Interface
+func (o *OfflineBundleOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.BundleFile, "bundle-file", "",
+		"verify the image of the offline bundle FILE of 'cosign bundle export', with the signatures in the bundle and without network access. "+
+			"The image may be omitted, or must be the digest of the bundle")
+	_ = cmd.Flags().SetAnnotation("bundle-file", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().StringVar(&o.TrustedRoot, "trusted-root", "",
+		"path to a Sigstore trusted root (trusted_root.json) with the Fulcio, Rekor, CT log and timestamp authority roots to verify against, "+
+			"instead of those of the TUF root. Required with --bundle-file, unless verifying with --key and --insecure-ignore-tlog")
+	_ = cmd.Flags().SetAnnotation("trusted-root", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().StringVar(&o.RekorBundleDir, "rekor-offline-bundle-dir", "",
+		"directory of Rekor entries fetched ahead of verification, that signatures without a Rekor bundle are checked against instead of looking them up online. "+
+			"The entries of a signature are in sha256-<hex>.json, named after the SHA-256 of its payload, as returned by the Rekor API")
+	_ = cmd.Flags().SetAnnotation("rekor-offline-bundle-dir", cobra.BashCompSubdirsInDir, []string{})
+}
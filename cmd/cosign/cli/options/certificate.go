@@ -16,8 +16,12 @@ package options
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/providers/github"
 	"github.com/spf13/cobra"
 )
 
@@ -28,14 +32,31 @@ type CertVerifyOptions struct {
 	CertIdentityRegexp           string
 	CertOidcIssuer               string
 	CertOidcIssuerRegexp         string
+	StrictIdentity               bool
 	CertGithubWorkflowTrigger    string
 	CertGithubWorkflowSha        string
 	CertGithubWorkflowName       string
 	CertGithubWorkflowRepository string
 	CertGithubWorkflowRef        string
+	CertClaims                   []string
 	CertChain                    string
+	LocalCARoots                 string
+	ACMECARoots                  string
 	SCT                          string
 	IgnoreSCT                    bool
+	RequireCTInclusion           bool
+	CTLogURL                     string
+	ClockSkew                    time.Duration
+	IgnoreKeyUsage               bool
+	AllowAnyEKU                  bool
+	RequireNameConstraints       bool
+	IssuerSPKIHashes             []string
+	MaxChainDepth                int
+
+	// identityFlags are the values of the identity and issuer flags in the
+	// order they were set, which pairs each identity with its issuer when
+	// the flags are repeated.
+	identityFlags []identityFlagValue
 }
 
 var _ Interface = (*RekorOptions)(nil)
@@ -46,17 +67,28 @@ func (o *CertVerifyOptions) AddFlags(cmd *cobra.Command) {
 		"path to the public certificate. The certificate will be verified against the Fulcio roots if the --certificate-chain option is not passed.")
 	_ = cmd.Flags().SetAnnotation("certificate", cobra.BashCompFilenameExt, []string{"cert"})
 
-	cmd.Flags().StringVar(&o.CertIdentity, "certificate-identity", "",
-		"The identity expected in a valid Fulcio certificate. Valid values include email address, DNS names, IP addresses, and URIs. Either --certificate-identity or --certificate-identity-regexp must be set for keyless flows.")
+	cmd.Flags().Var(o.identityFlag(&o.CertIdentity, "certificate-identity"), "certificate-identity",
+		"The identity expected in a valid Fulcio certificate. Valid values include email address, DNS names, IP addresses, and URIs. Either --certificate-identity or --certificate-identity-regexp must be set for keyless flows. "+
+			"May be repeated, with --certificate-identity-regexp too, each with its --certificate-oidc-issuer or --certificate-oidc-issuer-regexp right before or after it, "+
+			"to accept the signatures of any of the identities")
+
+	cmd.Flags().Var(o.identityFlag(&o.CertIdentityRegexp, "certificate-identity-regexp"), "certificate-identity-regexp",
+		"A regular expression alternative to --certificate-identity. Accepts the Go regular expression syntax described at https://golang.org/s/re2syntax. Either --certificate-identity or --certificate-identity-regexp must be set for keyless flows. "+
+			"May be repeated like --certificate-identity")
 
-	cmd.Flags().StringVar(&o.CertIdentityRegexp, "certificate-identity-regexp", "",
-		"A regular expression alternative to --certificate-identity. Accepts the Go regular expression syntax described at https://golang.org/s/re2syntax. Either --certificate-identity or --certificate-identity-regexp must be set for keyless flows.")
+	cmd.Flags().Var(o.identityFlag(&o.CertOidcIssuer, "certificate-oidc-issuer"), "certificate-oidc-issuer",
+		"The OIDC issuer expected in a valid Fulcio certificate, e.g. https://token.actions.githubusercontent.com or https://oauth2.sigstore.dev/auth. "+
+			"github-actions:<host> is the issuer of GitHub Actions on the GitHub Enterprise Server instance at host, or on github.com, "+
+			"and github-actions that of the GitHub server of the workflow run, or of $GITHUB_HOST, when verifying in GitHub Actions. Either --certificate-oidc-issuer or --certificate-oidc-issuer-regexp must be set for keyless flows. "+
+			"May be repeated, once for each --certificate-identity or --certificate-identity-regexp")
 
-	cmd.Flags().StringVar(&o.CertOidcIssuer, "certificate-oidc-issuer", "",
-		"The OIDC issuer expected in a valid Fulcio certificate, e.g. https://token.actions.githubusercontent.com or https://oauth2.sigstore.dev/auth. Either --certificate-oidc-issuer or --certificate-oidc-issuer-regexp must be set for keyless flows.")
+	cmd.Flags().Var(o.identityFlag(&o.CertOidcIssuerRegexp, "certificate-oidc-issuer-regexp"), "certificate-oidc-issuer-regexp",
+		"A regular expression alternative to --certificate-oidc-issuer. Accepts the Go regular expression syntax described at https://golang.org/s/re2syntax. Either --certificate-oidc-issuer or --certificate-oidc-issuer-regexp must be set for keyless flows. "+
+			"May be repeated like --certificate-oidc-issuer")
 
-	cmd.Flags().StringVar(&o.CertOidcIssuerRegexp, "certificate-oidc-issuer-regexp", "",
-		"A regular expression alternative to --certificate-oidc-issuer. Accepts the Go regular expression syntax described at https://golang.org/s/re2syntax. Either --certificate-oidc-issuer or --certificate-oidc-issuer-regexp must be set for keyless flows.")
+	cmd.Flags().BoolVar(&o.StrictIdentity, "certificate-identity-strict", false,
+		"reject --certificate-identity-regexp and --certificate-oidc-issuer-regexp values that aren't anchored with ^ and $, "+
+			"that accept any value or any domain or organization, or that have an unescaped . matching any character, so that only exact or narrow identities are verified")
 
 	// -- Cert extensions begin --
 	// Source: https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md
@@ -74,28 +106,297 @@ func (o *CertVerifyOptions) AddFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringVar(&o.CertGithubWorkflowRef, "certificate-github-workflow-ref", "",
 		"contains the ref claim from the GitHub OIDC Identity token that contains the git ref that the workflow run was based upon.")
+
+	cmd.Flags().StringArrayVar(&o.CertClaims, "certificate-claim", nil,
+		"constrain an OIDC token claim embedded in the Fulcio certificate, as claim=value, claim!=value, claim^=prefix or claim~=regexp, "+
+			"e.g. sourceRepositoryOwnerURI=https://github.com/example or runnerEnvironment=github-hosted. "+
+			"Claims are named as in https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md, or by the OID of their extension. "+
+			"May be repeated; every claim must match")
 	// -- Cert extensions end --
 	cmd.Flags().StringVar(&o.CertChain, "certificate-chain", "",
 		"path to a list of CA certificates in PEM format which will be needed "+
 			"when building the certificate chain for the signing certificate. "+
 			"Must start with the parent intermediate CA certificate of the "+
-			"signing certificate and end with the root certificate")
+			"signing certificate and end with the root certificate. "+
+			"Can also be the PKCS11 URI of a CA certificate in an HSM, or the KMS URI of a CA key that is trusted as the root, "+
+			"so that the roots are never stored as files")
 	_ = cmd.Flags().SetAnnotation("certificate-chain", cobra.BashCompFilenameExt, []string{"cert"})
 
+	cmd.Flags().StringVar(&o.LocalCARoots, "local-ca-roots", "",
+		"path to the PEM certificates of a local CA that issues the signing certificates instead of Fulcio, e.g. with --fulcio-url exec:<path>, "+
+			"to verify them against instead of the Fulcio roots. The self-signed certificates are the roots and the others intermediates. "+
+			"The certificates of local CAs have no SCTs, which aren't required")
+	_ = cmd.Flags().SetAnnotation("local-ca-roots", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().StringVar(&o.ACMECARoots, "acme-ca-roots", "",
+		"path to the PEM certificates of an ACME CA that issues the signing certificates instead of Fulcio, with --fulcio-url acme:<directory URL>, "+
+			"to verify them against like --local-ca-roots. The certificates of ACME CAs have no SCTs or OIDC issuer, so neither is required, "+
+			"and --certificate-identity matches the identifiers they were ordered for")
+	_ = cmd.Flags().SetAnnotation("acme-ca-roots", cobra.BashCompFilenameExt, []string{"cert"})
+	cmd.MarkFlagsMutuallyExclusive("local-ca-roots", "acme-ca-roots")
+
 	cmd.Flags().StringVar(&o.SCT, "sct", "",
 		"path to a detached Signed Certificate Timestamp, formatted as a RFC6962 AddChainResponse struct. "+
 			"If a certificate contains an SCT, verification will check both the detached and embedded SCTs.")
 	cmd.Flags().BoolVar(&o.IgnoreSCT, "insecure-ignore-sct", false,
 		"when set, verification will not check that a certificate contains an embedded SCT, a proof of "+
 			"inclusion in a certificate transparency log")
+	cmd.Flags().BoolVar(&o.RequireCTInclusion, "require-ct-inclusion", false,
+		"require, beyond the signature of the SCT, an inclusion proof of the certificate in the certificate transparency log of the SCT, "+
+			"to a tree head signed by the log. The proof is fetched from the log, or read from the offline bundle with --bundle-file")
+	cmd.Flags().StringVar(&o.CTLogURL, "ct-log-url", "",
+		"URL of the certificate transparency log to fetch inclusion proofs from with --require-ct-inclusion, "+
+			"instead of the URL of the log in the trusted root")
+	cmd.Flags().DurationVar(&o.ClockSkew, "certificate-clock-skew", 0,
+		"how far outside the validity period of a short-lived signing certificate the transparency log, "+
+			"timestamp or current time may be, to tolerate clock drift between the signer and the servers, e.g. 30s. At most 5m")
+	cmd.Flags().BoolVar(&o.IgnoreKeyUsage, "insecure-ignore-key-usage", false,
+		"when set, verification will not check that the signing certificate isn't a CA and has the digital signature key usage, "+
+			"and that the CAs of its chain have the CA basic constraint and the certificate signing key usage, for legacy CAs")
+	cmd.Flags().BoolVar(&o.AllowAnyEKU, "insecure-allow-any-eku", false,
+		"accept signing certificates without the extended key usage extension or with the any extended key usage, "+
+			"instead of requiring the code signing extended key usage, for legacy CAs")
+	cmd.Flags().BoolVar(&o.RequireNameConstraints, "certificate-require-name-constraints", false,
+		"require a CA of the certificate chain to have name constraints, limiting the identities it may issue certificates for")
+	cmd.Flags().StringSliceVar(&o.IssuerSPKIHashes, "certificate-issuer-spki-hash", nil,
+		"pin the CA that issues signing certificates by the SHA-256 hash of its subject public key info, as sha256:<hex> or base64, "+
+			"e.g. from openssl x509 -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64, "+
+			"so that the certificates of other intermediates of the same root, e.g. a compromised one, fail verification. May be repeated")
+	cmd.Flags().IntVar(&o.MaxChainDepth, "certificate-chain-max-depth", 0,
+		"the most CA certificates, intermediates and root, that the chain of a signing certificate may have, "+
+			"e.g. 2 for a single intermediate. 0 for no limit")
+}
+
+// ApplyChainPinning sets the issuer pins and certificate chain depth of
+// --certificate-issuer-spki-hash and --certificate-chain-max-depth on co.
+func (o *CertVerifyOptions) ApplyChainPinning(co *cosign.CheckOpts) error {
+	if o.MaxChainDepth < 0 {
+		return fmt.Errorf("--certificate-chain-max-depth must not be negative, got %d", o.MaxChainDepth)
+	}
+	co.MaxChainDepth = o.MaxChainDepth
+	co.IssuerSPKIHashes = nil
+	for _, s := range o.IssuerSPKIHashes {
+		h, err := cosign.ParseSPKIHash(s)
+		if err != nil {
+			return fmt.Errorf("--certificate-issuer-spki-hash: %w", err)
+		}
+		co.IssuerSPKIHashes = append(co.IssuerSPKIHashes, h)
+	}
+	return nil
+}
+
+// MaxCertificateClockSkew is the most --certificate-clock-skew may be.
+// Signing certificates are valid for minutes, so a larger skew would accept
+// them long after they expired.
+const MaxCertificateClockSkew = 5 * time.Minute
+
+// ApplyClockSkew sets the --certificate-clock-skew on co.
+func (o *CertVerifyOptions) ApplyClockSkew(co *cosign.CheckOpts) error {
+	if o.ClockSkew < 0 || o.ClockSkew > MaxCertificateClockSkew {
+		return fmt.Errorf("--certificate-clock-skew must be between 0 and %s, got %s", MaxCertificateClockSkew, o.ClockSkew)
+	}
+	co.CertClockSkew = o.ClockSkew
+	return nil
 }
 
+// ClaimMatchers returns the parsed --certificate-claim values.
+func (o *CertVerifyOptions) ClaimMatchers() ([]cosign.ClaimMatcher, error) {
+	m, err := cosign.ParseClaimMatchers(o.CertClaims)
+	if err != nil {
+		return nil, fmt.Errorf("--certificate-claim: %w", err)
+	}
+	return m, nil
+}
+
+// ApplyCTInclusion sets the certificate transparency log inclusion checks of
+// --require-ct-inclusion and --ct-log-url on co.
+func (o *CertVerifyOptions) ApplyCTInclusion(co *cosign.CheckOpts) error {
+	if !o.RequireCTInclusion {
+		if o.CTLogURL != "" {
+			return errors.New("--ct-log-url requires --require-ct-inclusion")
+		}
+		return nil
+	}
+	if co.IgnoreSCT {
+		return errors.New("--require-ct-inclusion can't be used with --insecure-ignore-sct")
+	}
+	co.RequireCTInclusion = true
+	if o.CTLogURL != "" {
+		lc, err := cosign.NewCTLogClient(o.CTLogURL)
+		if err != nil {
+			return fmt.Errorf("creating certificate transparency log client: %w", err)
+		}
+		co.CTLogClients = func(string) (cosign.CTLogClient, error) { return lc, nil }
+	}
+	return nil
+}
+
+// CARoots returns the path of the roots of the local or ACME CA that issues
+// the signing certificates instead of Fulcio, of --local-ca-roots or
+// --acme-ca-roots, or "" if neither is set.
+func (o *CertVerifyOptions) CARoots() string {
+	if o.ACMECARoots != "" {
+		return o.ACMECARoots
+	}
+	return o.LocalCARoots
+}
+
+// Identities returns the identities that the certificates of signatures must
+// match one of: that of the identity and issuer flags, or, if some of them
+// are repeated, each identity flag paired with the issuer flag right before
+// or after it.
 func (o *CertVerifyOptions) Identities() ([]cosign.Identity, error) {
-	if o.CertIdentity == "" && o.CertIdentityRegexp == "" {
-		return nil, errors.New("--certificate-identity or --certificate-identity-regexp is required for verification in keyless mode")
+	var ids []cosign.Identity
+	if o.repeatedIdentityFlags() {
+		var err error
+		if ids, err = o.pairedIdentities(); err != nil {
+			return nil, err
+		}
+	} else {
+		if o.CertIdentity == "" && o.CertIdentityRegexp == "" {
+			return nil, errors.New("--certificate-identity or --certificate-identity-regexp is required for verification in keyless mode")
+		}
+		if o.CertOidcIssuer == "" && o.CertOidcIssuerRegexp == "" && o.ACMECARoots == "" {
+			return nil, errors.New("--certificate-oidc-issuer or --certificate-oidc-issuer-regexp is required for verification in keyless mode")
+		}
+		ids = []cosign.Identity{{IssuerRegExp: o.CertOidcIssuerRegexp, Issuer: o.CertOidcIssuer, SubjectRegExp: o.CertIdentityRegexp, Subject: o.CertIdentity}}
+	}
+	for i := range ids {
+		issuer, err := expandIssuer(ids[i].Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("--certificate-oidc-issuer: %w", err)
+		}
+		ids[i].Issuer = issuer
+		if o.StrictIdentity {
+			if err := cosign.CheckIdentityStrict(ids[i]); err != nil {
+				return nil, fmt.Errorf("--certificate-identity-strict: %w", err)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// identityFlagValue is a value of an identity or issuer flag.
+type identityFlagValue struct {
+	flag  string
+	value string
+}
+
+// issuer reports whether v is the value of an issuer flag rather than of an
+// identity flag.
+func (v identityFlagValue) issuer() bool {
+	return strings.HasPrefix(v.flag, "certificate-oidc-issuer")
+}
+
+// identityFlag is the value of the identity or issuer flag name, which sets
+// value to the last of its values and records them all in order.
+type identityFlag struct {
+	name   string
+	value  *string
+	values *[]identityFlagValue
+}
+
+func (o *CertVerifyOptions) identityFlag(value *string, name string) *identityFlag {
+	return &identityFlag{name: name, value: value, values: &o.identityFlags}
+}
+
+func (f *identityFlag) String() string {
+	return *f.value
+}
+
+func (f *identityFlag) Set(v string) error {
+	*f.value = v
+	*f.values = append(*f.values, identityFlagValue{flag: f.name, value: v})
+	return nil
+}
+
+func (f *identityFlag) Type() string {
+	return "string"
+}
+
+// repeatedIdentityFlags reports whether one of the identity and issuer flags
+// was set more than once.
+func (o *CertVerifyOptions) repeatedIdentityFlags() bool {
+	seen := map[string]bool{}
+	for _, v := range o.identityFlags {
+		if seen[v.flag] {
+			return true
+		}
+		seen[v.flag] = true
+	}
+	return false
+}
+
+// pairedIdentities returns the identities of the repeated identity and issuer
+// flags, each identity paired with the issuer right before or after it.
+func (o *CertVerifyOptions) pairedIdentities() ([]cosign.Identity, error) {
+	var ids []cosign.Identity
+	if o.ACMECARoots != "" && o.CertOidcIssuer == "" && o.CertOidcIssuerRegexp == "" {
+		// The certificates of ACME CAs have no issuer to pair identities with.
+		for _, v := range o.identityFlags {
+			if v.flag == "certificate-identity-regexp" {
+				ids = append(ids, cosign.Identity{SubjectRegExp: v.value})
+			} else {
+				ids = append(ids, cosign.Identity{Subject: v.value})
+			}
+		}
+		return ids, nil
+	}
+	var identity, issuer *identityFlagValue
+	for i := range o.identityFlags {
+		v := &o.identityFlags[i]
+		switch {
+		case v.issuer() && issuer != nil:
+			return nil, fmt.Errorf("--%s %s has no --certificate-identity or --certificate-identity-regexp paired with it", issuer.flag, issuer.value)
+		case v.issuer():
+			issuer = v
+		case identity != nil:
+			return nil, fmt.Errorf("--%s %s has no --certificate-oidc-issuer or --certificate-oidc-issuer-regexp paired with it", identity.flag, identity.value)
+		default:
+			identity = v
+		}
+		if identity == nil || issuer == nil {
+			continue
+		}
+		id := cosign.Identity{}
+		if identity.flag == "certificate-identity-regexp" {
+			id.SubjectRegExp = identity.value
+		} else {
+			id.Subject = identity.value
+		}
+		if issuer.flag == "certificate-oidc-issuer-regexp" {
+			id.IssuerRegExp = issuer.value
+		} else {
+			id.Issuer = issuer.value
+		}
+		ids = append(ids, id)
+		identity, issuer = nil, nil
+	}
+	switch {
+	case identity != nil:
+		return nil, fmt.Errorf("--%s %s has no --certificate-oidc-issuer or --certificate-oidc-issuer-regexp paired with it", identity.flag, identity.value)
+	case issuer != nil:
+		return nil, fmt.Errorf("--%s %s has no --certificate-identity or --certificate-identity-regexp paired with it", issuer.flag, issuer.value)
+	}
+	return ids, nil
+}
+
+// githubActionsIssuer is the template of --certificate-oidc-issuer for the
+// issuer of GitHub Actions, followed by :<host> for that of a given server.
+const githubActionsIssuer = "github-actions"
+
+// expandIssuer returns the OIDC issuer of the --certificate-oidc-issuer
+// value issuer, which may be a template.
+func expandIssuer(issuer string) (string, error) {
+	if issuer == githubActionsIssuer {
+		return github.AmbientIssuer()
 	}
-	if o.CertOidcIssuer == "" && o.CertOidcIssuerRegexp == "" {
-		return nil, errors.New("--certificate-oidc-issuer or --certificate-oidc-issuer-regexp is required for verification in keyless mode")
+	if strings.HasPrefix(issuer, githubActionsIssuer+":") {
+		host := strings.TrimPrefix(issuer, githubActionsIssuer+":")
+		if host == "" {
+			return "", errors.New("github-actions: requires the host of the GitHub server")
+		}
+		return github.Issuer(host)
 	}
-	return []cosign.Identity{{IssuerRegExp: o.CertOidcIssuerRegexp, Issuer: o.CertOidcIssuer, SubjectRegExp: o.CertIdentityRegexp, Subject: o.CertIdentity}}, nil
+	return issuer, nil
 }
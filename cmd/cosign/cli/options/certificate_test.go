@@ -0,0 +1,133 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/spf13/cobra"
+)
+
+func TestCertVerifyOptionsIdentitiesIssuerTemplate(t *testing.T) {
+	t.Setenv("GITHUB_SERVER_URL", "https://ghes.example.com")
+	for issuer, want := range map[string]string{
+		"https://oauth2.sigstore.dev/auth": "https://oauth2.sigstore.dev/auth",
+		"github-actions":                   "https://ghes.example.com/_services/token",
+		"github-actions:github.com":        "https://token.actions.githubusercontent.com",
+		"github-actions:ghes.corp.example": "https://ghes.corp.example/_services/token",
+	} {
+		o := CertVerifyOptions{CertIdentity: "https://ghes.example.com/org/repo/.github/workflows/release.yml@refs/heads/main", CertOidcIssuer: issuer}
+		ids, err := o.Identities()
+		if err != nil || len(ids) != 1 || ids[0].Issuer != want {
+			t.Errorf("Identities() with --certificate-oidc-issuer %s = %+v, %v, want the issuer %s", issuer, ids, err, want)
+		}
+	}
+	o := CertVerifyOptions{CertIdentity: "someone@example.com", CertOidcIssuer: "github-actions:"}
+	if _, err := o.Identities(); err == nil || !strings.Contains(err.Error(), "requires the host") {
+		t.Errorf("Identities() without a host = %v", err)
+	}
+}
+
+func TestCertVerifyOptionsRepeatedIdentities(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		want []cosign.Identity
+		err  string
+	}{{
+		name: "single",
+		args: []string{"--certificate-oidc-issuer", "https://accounts.google.com", "--certificate-identity", "someone@example.com"},
+		want: []cosign.Identity{{Subject: "someone@example.com", Issuer: "https://accounts.google.com"}},
+	}, {
+		name: "exact and regexp together",
+		args: []string{"--certificate-identity", "someone@example.com", "--certificate-identity-regexp", "@example.com$", "--certificate-oidc-issuer", "https://accounts.google.com"},
+		want: []cosign.Identity{{Subject: "someone@example.com", SubjectRegExp: "@example.com$", Issuer: "https://accounts.google.com"}},
+	}, {
+		name: "pairs",
+		args: []string{
+			"--certificate-identity", "https://github.com/org/repo/.github/workflows/release.yml@refs/heads/main",
+			"--certificate-oidc-issuer", "https://token.actions.githubusercontent.com",
+			"--certificate-oidc-issuer-regexp", "^https://gitlab\\.example\\.com$",
+			"--certificate-identity-regexp", "^https://gitlab\\.example\\.com/org/",
+			"--certificate-identity", "someone@example.com",
+			"--certificate-oidc-issuer", "https://accounts.google.com",
+		},
+		want: []cosign.Identity{
+			{Subject: "https://github.com/org/repo/.github/workflows/release.yml@refs/heads/main", Issuer: "https://token.actions.githubusercontent.com"},
+			{SubjectRegExp: "^https://gitlab\\.example\\.com/org/", IssuerRegExp: "^https://gitlab\\.example\\.com$"},
+			{Subject: "someone@example.com", Issuer: "https://accounts.google.com"},
+		},
+	}, {
+		name: "identity without issuer",
+		args: []string{"--certificate-identity", "a@example.com", "--certificate-identity", "b@example.com", "--certificate-oidc-issuer", "https://accounts.google.com"},
+		err:  "--certificate-identity a@example.com has no --certificate-oidc-issuer",
+	}, {
+		name: "issuer without identity",
+		args: []string{"--certificate-identity", "a@example.com", "--certificate-oidc-issuer", "https://accounts.google.com", "--certificate-oidc-issuer", "https://github.com/login/oauth"},
+		err:  "--certificate-oidc-issuer https://github.com/login/oauth has no --certificate-identity",
+	}, {
+		name: "ACME CA",
+		args: []string{"--acme-ca-roots", "roots.pem", "--certificate-identity", "build.example.com"},
+		want: []cosign.Identity{{Subject: "build.example.com"}},
+	}, {
+		name: "repeated with ACME CA",
+		args: []string{"--acme-ca-roots", "roots.pem", "--certificate-identity", "build.example.com", "--certificate-identity", "release@example.com"},
+		want: []cosign.Identity{{Subject: "build.example.com"}, {Subject: "release@example.com"}},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			o := CertVerifyOptions{}
+			cmd := &cobra.Command{}
+			o.AddFlags(cmd)
+			if err := cmd.ParseFlags(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			ids, err := o.Identities()
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Identities() = %v, want an error containing %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(ids, tc.want) {
+				t.Errorf("Identities() = %+v, %v, want %+v", ids, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestCertVerifyOptionsApplyClockSkew(t *testing.T) {
+	for skew, wantErr := range map[time.Duration]bool{
+		0:                       false,
+		30 * time.Second:        false,
+		MaxCertificateClockSkew: false,
+		time.Hour:               true,
+		-time.Second:            true,
+	} {
+		o := CertVerifyOptions{ClockSkew: skew}
+		co := &cosign.CheckOpts{}
+		err := o.ApplyClockSkew(co)
+		if (err != nil) != wantErr {
+			t.Errorf("ApplyClockSkew() with --certificate-clock-skew %s = %v, wantErr %t", skew, err, wantErr)
+		}
+		if err == nil && co.CertClockSkew != skew {
+			t.Errorf("ApplyClockSkew() set the skew %s, want %s", co.CertClockSkew, skew)
+		}
+	}
+}
@@ -55,9 +55,14 @@ func (c *CleanType) Type() string {
 }
 
 type CleanOptions struct {
-	Registry  RegistryOptions
-	CleanType CleanType
-	Force     bool
+	Registry           RegistryOptions
+	CleanType          CleanType
+	Force              bool
+	DryRun             bool
+	Recursive          bool
+	SignedByKey        string
+	SignedByIdentity   string
+	SignedByOIDCIssuer string
 }
 
 var _ Interface = (*CleanOptions)(nil)
@@ -68,4 +73,21 @@ func (c *CleanOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().Var(&c.CleanType, "type", "a type of clean: <signature|attestation|sbom|all>")
 	// TODO(#2044): Rename to --skip-confirmation for consistency?
 	cmd.Flags().BoolVarP(&c.Force, "force", "f", false, "do not prompt for confirmation")
+
+	cmd.Flags().BoolVar(&c.DryRun, "dry-run", false,
+		"only list the signatures, attestations and SBOMs that would be removed, without removing anything")
+
+	cmd.Flags().BoolVarP(&c.Recursive, "recursive", "r", false,
+		"if a multi-arch image is specified, additionally clean each discrete image")
+
+	cmd.Flags().StringVar(&c.SignedByKey, "signed-by-key", "",
+		"only remove signatures and attestations made with this key: a path, URL or KMS URI of the public key, "+
+			"or the sha256:<hex> fingerprint of its DER encoding (which only matches signatures carrying a certificate)")
+	_ = cmd.Flags().SetAnnotation("signed-by-key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&c.SignedByIdentity, "signed-by-identity", "",
+		"only remove signatures and attestations whose certificate identity (email or URI SAN) is this value")
+
+	cmd.Flags().StringVar(&c.SignedByOIDCIssuer, "signed-by-oidc-issuer", "",
+		"only remove signatures and attestations whose certificate was issued for this OIDC issuer")
 }
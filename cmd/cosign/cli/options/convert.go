@@ -0,0 +1,81 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ConvertSBOMOptions is the top level wrapper for the convert
+// sbom-to-attestation command.
+type ConvertSBOMOptions struct {
+	Key              string
+	Cert             string
+	CertChain        string
+	SkipConfirmation bool
+	TlogUpload       bool
+	TSAServerURL     string
+	DeleteSBOM       bool
+
+	Rekor                RekorOptions
+	Fulcio               FulcioOptions
+	OIDC                 OIDCOptions
+	SecurityKey          SecurityKeyOptions
+	Registry             RegistryOptions
+	RegistryExperimental RegistryExperimentalOptions
+}
+
+var _ Interface = (*ConvertSBOMOptions)(nil)
+
+// AddFlags implements Interface
+func (o *ConvertSBOMOptions) AddFlags(cmd *cobra.Command) {
+	o.SecurityKey.AddFlags(cmd)
+	o.Fulcio.AddFlags(cmd)
+	o.OIDC.AddFlags(cmd)
+	o.Rekor.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+	// The attestations are pushed as OCI 1.1 referrers unless asked
+	// otherwise, as the SBOMs are converted to move off the legacy tags.
+	o.RegistryExperimental.RegistryReferrersMode = RegistryReferrersModeOCI11
+	o.RegistryExperimental.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"path to the private key file, KMS URI or Kubernetes Secret")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{"key"})
+
+	cmd.Flags().StringVar(&o.Cert, "certificate", "",
+		"path to the X.509 certificate in PEM format to include in the OCI Signature")
+	_ = cmd.Flags().SetAnnotation("certificate", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().StringVar(&o.CertChain, "certificate-chain", "",
+		"path to a list of CA X.509 certificates in PEM format which will be needed "+
+			"when building the certificate chain for the signing certificate. "+
+			"Must start with the parent intermediate CA certificate of the "+
+			"signing certificate and end with the root certificate. Included in the OCI Signature")
+	_ = cmd.Flags().SetAnnotation("certificate-chain", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
+		"skip confirmation prompts for non-destructive operations")
+
+	cmd.Flags().BoolVar(&o.TlogUpload, "tlog-upload", true,
+		"whether or not to upload to the tlog")
+
+	cmd.Flags().StringVar(&o.TSAServerURL, "timestamp-server-url", "",
+		"url to the Timestamp RFC3161 server, default none. Must be the path to the API to request timestamp responses, e.g. https://freetsa.org/tsr")
+
+	cmd.Flags().BoolVar(&o.DeleteSBOM, "delete-sbom", false,
+		"delete the legacy SBOM tag of the image once its SBOM is attested")
+}
@@ -23,7 +23,11 @@ import (
 type CopyOptions struct {
 	SignatureOnly bool
 	Force         bool
+	LocalImage    bool
+	Jobs          int
+	VerifyPolicy  string
 	Registry      RegistryOptions
+	Batch         BatchOptions
 }
 
 var _ Interface = (*CopyOptions)(nil)
@@ -31,10 +35,22 @@ var _ Interface = (*CopyOptions)(nil)
 // AddFlags implements Interface
 func (o *CopyOptions) AddFlags(cmd *cobra.Command) {
 	o.Registry.AddFlags(cmd)
+	o.Batch.AddFlags(cmd)
 
 	cmd.Flags().BoolVar(&o.SignatureOnly, "sig-only", false,
 		"only copy the image signature")
 
 	cmd.Flags().BoolVarP(&o.Force, "force", "f", false,
 		"overwrite destination image(s), if necessary")
+
+	cmd.Flags().BoolVar(&o.LocalImage, "local-image", false,
+		"whether the source is a path to an OCI layout, e.g. signed with 'cosign sign --local-image', "+
+			"whose signatures and attestations are copied with the image")
+
+	cmd.Flags().IntVar(&o.Jobs, "jobs", 0,
+		"number of images and signatures to copy in parallel, or the number of CPUs if 0, at most the workers of the budget config file")
+
+	cmd.Flags().StringVar(&o.VerifyPolicy, "verify-policy", "",
+		"path to a JSON policy with the key or certificate identity the source image's signatures must be verified with before anything is copied")
+	_ = cmd.Flags().SetAnnotation("verify-policy", cobra.BashCompFilenameExt, []string{"json"})
 }
@@ -0,0 +1,95 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// CountersignerOptions is the wrapper for the countersigners required by a
+// verification policy.
+type CountersignerOptions struct {
+	Keys       []string
+	Identities []string
+	OIDCIssuer string
+}
+
+var _ Interface = (*CountersignerOptions)(nil)
+
+// AddFlags implements Interface
+func (o *CountersignerOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&o.Keys, "countersigner-key", nil,
+		"require a countersignature of the verified payload made with this public key file, KMS URI or Kubernetes Secret (can be repeated)")
+
+	cmd.Flags().StringSliceVar(&o.Identities, "countersigner-identity", nil,
+		"require a countersignature of the verified payload with a certificate for this identity (can be repeated)")
+
+	cmd.Flags().StringVar(&o.OIDCIssuer, "countersigner-oidc-issuer", "",
+		"the OIDC issuer of the --countersigner-identity certificates")
+}
+
+// Enabled reports whether any countersigners are required.
+func (o *CountersignerOptions) Enabled() bool {
+	return len(o.Keys) > 0 || len(o.Identities) > 0
+}
+
+// CountersignOptions is the top level wrapper for the countersign command.
+type CountersignOptions struct {
+	// Verify identifies the signatures to countersign.
+	Verify VerifyOptions
+
+	SigningKey       string
+	SigningCert      string
+	SigningCertChain string
+	SkipConfirmation bool
+	TlogUpload       bool
+	TSAServerURL     string
+}
+
+var _ Interface = (*CountersignOptions)(nil)
+
+// AddFlags implements Interface
+func (o *CountersignOptions) AddFlags(cmd *cobra.Command) {
+	// The --fulcio-url, --identity-token and --oidc-* flags of Verify issue
+	// the certificate to countersign with.
+	o.Verify.AddFlags(cmd)
+
+	// The countersignature is what those flags require, not a precondition.
+	for _, f := range []string{"countersigner-key", "countersigner-identity", "countersigner-oidc-issuer"} {
+		_ = cmd.Flags().MarkHidden(f)
+	}
+
+	cmd.Flags().StringVar(&o.SigningKey, "signing-key", "",
+		"path to the private key file, KMS URI or Kubernetes Secret to countersign with. Without one, a certificate is issued by Fulcio")
+	_ = cmd.Flags().SetAnnotation("signing-key", cobra.BashCompFilenameExt, []string{"key"})
+
+	cmd.Flags().StringVar(&o.SigningCert, "signing-certificate", "",
+		"path to the X.509 certificate in PEM format of --signing-key to include in the countersignature")
+	_ = cmd.Flags().SetAnnotation("signing-certificate", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().StringVar(&o.SigningCertChain, "signing-certificate-chain", "",
+		"path to a list of CA X.509 certificates in PEM format which will be needed "+
+			"when building the certificate chain for --signing-certificate. Included in the countersignature")
+	_ = cmd.Flags().SetAnnotation("signing-certificate-chain", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
+		"skip confirmation prompts for non-destructive operations")
+
+	cmd.Flags().BoolVar(&o.TlogUpload, "tlog-upload", true,
+		"whether or not to upload the countersignature to the tlog")
+
+	cmd.Flags().StringVar(&o.TSAServerURL, "timestamp-server-url", "",
+		"url to the Timestamp RFC3161 server, default none. Must be the path to the API to request timestamp responses, e.g. https://freetsa.org/tsr")
+}
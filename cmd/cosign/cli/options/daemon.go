@@ -0,0 +1,46 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// DaemonOptions is the top level wrapper for the daemon command.
+type DaemonOptions struct {
+	Socket      string
+	IdleTimeout time.Duration
+	Worker      bool
+}
+
+var _ Interface = (*DaemonOptions)(nil)
+
+// AddFlags implements Interface
+func (o *DaemonOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Socket, "socket", "",
+		"path of the Unix socket the daemon listens on, which clients name with $COSIGN_DAEMON_SOCKET. Defaults to $COSIGN_DAEMON_SOCKET")
+	_ = cmd.Flags().SetAnnotation("socket", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().DurationVar(&o.IdleTimeout, "idle-timeout", 0,
+		"exit once no client sent a request for this long, e.g. 1h. Default never")
+
+	// The daemon runs itself with --worker to keep KMS clients.
+	cmd.Flags().BoolVar(&o.Worker, "worker", false,
+		"serve the KMS keys of a daemon as its worker")
+	_ = cmd.Flags().MarkHidden("worker")
+}
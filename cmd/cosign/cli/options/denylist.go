@@ -0,0 +1,42 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "github.com/spf13/cobra"
+
+// DenylistOptions is the wrapper for the denylist consulted during verification.
+type DenylistOptions struct {
+	Path      string
+	Key       string
+	Signature string
+}
+
+var _ Interface = (*DenylistOptions)(nil)
+
+// AddFlags implements Interface
+func (o *DenylistOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Path, "denylist", "",
+		"path, OCI reference or tuf://<target> of a signed denylist of revoked key fingerprints, certificate identities and artifact digests "+
+			"to reject. Targets in the TUF repository set up with 'cosign initialize' don't need a denylist key. Defaults to $COSIGN_DENYLIST")
+	_ = cmd.Flags().SetAnnotation("denylist", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.Key, "denylist-key", "",
+		"path to the public key file, KMS URI or Kubernetes Secret that signed the denylist. Defaults to $COSIGN_DENYLIST_KEY")
+	_ = cmd.Flags().SetAnnotation("denylist-key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.Signature, "denylist-signature", "",
+		"path or tuf://<target> of the base64 encoded signature of a denylist file. Defaults to the denylist path with a .sig suffix")
+	_ = cmd.Flags().SetAnnotation("denylist-signature", cobra.BashCompFilenameExt, []string{})
+}
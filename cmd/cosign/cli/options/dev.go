@@ -0,0 +1,73 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// DevSignOptions is the top level wrapper for the `dev sign` command.
+type DevSignOptions struct {
+	TTL string
+
+	AnnotationOptions
+	Registry             RegistryOptions
+	RegistryExperimental RegistryExperimentalOptions
+}
+
+var _ Interface = (*DevSignOptions)(nil)
+
+// AddFlags implements Interface
+func (o *DevSignOptions) AddFlags(cmd *cobra.Command) {
+	o.AnnotationOptions.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+	o.RegistryExperimental.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.TTL, "ttl", "1h",
+		"expire the signature after this duration, at most 24h, e.g. 30m. The expiry time is signed as the "+
+			"dev.sigstore.cosign/expires annotation, and enforced by the printed verify command")
+}
+
+// DevStackUpOptions is the top level wrapper for the `dev stack up` command.
+type DevStackUpOptions struct {
+	Dir             string
+	RegistryAddress string
+	TSAAddress      string
+	Identity        string
+	Issuer          string
+}
+
+var _ Interface = (*DevStackUpOptions)(nil)
+
+// AddFlags implements Interface
+func (o *DevStackUpOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Dir, "dir", "",
+		"directory to write the trust material and environment of the stack to, and to create the socket of its certificate authority in. "+
+			"Defaults to a new temporary directory")
+	_ = cmd.Flags().SetAnnotation("dir", cobra.BashCompSubdirsInDir, []string{})
+
+	cmd.Flags().StringVar(&o.RegistryAddress, "registry-address", "localhost:0",
+		"address the registry listens on, by default on a free port")
+
+	cmd.Flags().StringVar(&o.TSAAddress, "timestamp-authority-address", "localhost:0",
+		"address the timestamp authority listens on, by default on a free port")
+
+	cmd.Flags().StringVar(&o.Identity, "identity", "dev@example.com",
+		"identity, an email address or a URI, that the certificate authority certifies every signer as")
+
+	cmd.Flags().StringVar(&o.Issuer, "issuer", "https://oidc.example.com",
+		"OIDC issuer that the certificate authority records in the certificates")
+}
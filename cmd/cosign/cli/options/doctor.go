@@ -0,0 +1,62 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// DoctorOptions is the top level wrapper for the doctor command.
+type DoctorOptions struct {
+	Repository   string
+	FulcioURL    string
+	RekorURL     string
+	TSAServerURL string
+	TUF          bool
+	MaxClockSkew time.Duration
+	Output       string
+	Registry     RegistryOptions
+}
+
+var _ Interface = (*DoctorOptions)(nil)
+
+// AddFlags implements Interface
+func (o *DoctorOptions) AddFlags(cmd *cobra.Command) {
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Repository, "repository", "",
+		"repository to check the connectivity and pull and push access to, e.g. the one signatures are pushed to")
+
+	cmd.Flags().StringVar(&o.FulcioURL, "fulcio-url", DefaultFulcioURL,
+		"address of the Fulcio server to check, empty to skip")
+
+	cmd.Flags().StringVar(&o.RekorURL, "rekor-url", DefaultRekorURL,
+		"address of the Rekor server to check, empty to skip")
+
+	cmd.Flags().StringVar(&o.TSAServerURL, "timestamp-server-url", "",
+		"url of the Timestamp RFC3161 server to check, default none")
+
+	cmd.Flags().BoolVar(&o.TUF, "tuf", true,
+		"check the TUF trust root and mirror configured with cosign initialize")
+
+	cmd.Flags().DurationVar(&o.MaxClockSkew, "max-clock-skew", time.Minute,
+		"maximum difference between the local clock and the clocks of the checked servers")
+
+	cmd.Flags().StringVar(&o.Output, "output", "text",
+		"output format for the results, text or json")
+}
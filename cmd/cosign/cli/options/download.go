@@ -23,14 +23,22 @@ type SBOMDownloadOptions struct {
 }
 
 type AttestationDownloadOptions struct {
-	PredicateType string // Predicate type of attestation to retrieve
-	Platform      string // Platform to download attestations
+	PredicateType  string                // Predicate type of attestation to retrieve
+	Platform       string                // Platform to download attestations
+	SignedBy       []string              // Identities or key fingerprints of the signers of attestations to retrieve
+	OutputTemplate OutputTemplateOptions // Template that each attestation is written with
+}
+
+type SignatureDownloadOptions struct {
+	OutputTemplate OutputTemplateOptions // Template that each signature is written with
 }
 
 var _ Interface = (*SBOMDownloadOptions)(nil)
 
 var _ Interface = (*AttestationDownloadOptions)(nil)
 
+var _ Interface = (*SignatureDownloadOptions)(nil)
+
 // AddFlags implements Interface
 func (o *SBOMDownloadOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.Platform, "platform", "",
@@ -43,4 +51,14 @@ func (o *AttestationDownloadOptions) AddFlags(cmd *cobra.Command) {
 		"download attestation with matching predicateType annotation")
 	cmd.Flags().StringVar(&o.Platform, "platform", "",
 		"download attestation for a specific platform image")
+	cmd.Flags().StringSliceVar(&o.SignedBy, "signed-by", nil,
+		"only download attestations signed by this signer: the certificate identity (email or URI SAN), or the sha256:<hex> fingerprint "+
+			"of the DER encoding of the public key of the certificate (can be repeated, any may match). "+
+			"The certificates aren't verified, use 'cosign verify-attestation' for that")
+	o.OutputTemplate.AddFlags(cmd)
+}
+
+// AddFlags implements Interface
+func (o *SignatureDownloadOptions) AddFlags(cmd *cobra.Command) {
+	o.OutputTemplate.AddFlags(cmd)
 }
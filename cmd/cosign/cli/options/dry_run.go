@@ -0,0 +1,38 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// DryRunOptions is the wrapper for the dry run flags of sign and attest.
+type DryRunOptions struct {
+	Enabled            bool
+	RequestCertificate bool
+}
+
+var _ Interface = (*DryRunOptions)(nil)
+
+// AddFlags implements Interface
+func (o *DryRunOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.Enabled, "dry-run", false,
+		"generate and sign the payload, but only print what would be pushed to the registry and uploaded to the transparency log")
+
+	cmd.Flags().BoolVar(&o.RequestCertificate, "dry-run-certificate", false,
+		"in a dry run, still request the signing certificate from Fulcio to check the OIDC configuration. "+
+			"Fulcio records the certificate in its certificate transparency log")
+}
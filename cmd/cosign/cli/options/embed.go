@@ -0,0 +1,40 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// EmbedOptions is the top level wrapper for the embed command.
+type EmbedOptions struct {
+	BundlePath string
+	Output     string
+}
+
+var _ Interface = (*EmbedOptions)(nil)
+
+// AddFlags implements Interface
+func (o *EmbedOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.BundlePath, "bundle", "",
+		"path to the bundle FILE of the executable or archive, written by cosign sign-blob --bundle")
+	_ = cmd.Flags().SetAnnotation("bundle", cobra.BashCompFilenameExt, []string{})
+	_ = cmd.MarkFlagRequired("bundle")
+
+	cmd.Flags().StringVar(&o.Output, "output", "",
+		"write the file with the embedded bundle to FILE instead of updating it in place")
+	_ = cmd.Flags().SetAnnotation("output", cobra.BashCompFilenameExt, []string{})
+}
@@ -0,0 +1,38 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "github.com/spf13/cobra"
+
+// EncryptionOptions is the wrapper for the verification policy of images
+// encrypted with OCIcrypt.
+type EncryptionOptions struct {
+	RequireEncrypted bool
+	Recipients       []string
+}
+
+var _ Interface = (*EncryptionOptions)(nil)
+
+// AddFlags implements Interface
+func (o *EncryptionOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.RequireEncrypted, "require-encrypted", false,
+		"reject images whose layers aren't all encrypted with OCIcrypt. The layers are checked without decrypting them")
+
+	cmd.Flags().StringSliceVar(&o.Recipients, "encryption-recipient", nil,
+		"require the encrypted image to be signed with this recipient, a public key or certificate file or sha256:<fingerprint> of its key, "+
+			"in the dev.sigstore.cosign/encryption-recipients annotation set by cosign sign --encryption-recipient (can be repeated). Implies --require-encrypted")
+	_ = cmd.Flags().SetAnnotation("encryption-recipient", cobra.BashCompFilenameExt, []string{})
+}
@@ -23,6 +23,7 @@ import (
 type EnvOptions struct {
 	ShowDescriptions    bool
 	ShowSensitiveValues bool
+	ListVars            bool
 }
 
 var _ Interface = (*EnvOptions)(nil)
@@ -34,4 +35,7 @@ func (o *EnvOptions) AddFlags(cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&o.ShowSensitiveValues, "show-sensitive-values", false,
 		"show values of sensitive environment variables")
+
+	cmd.Flags().BoolVar(&o.ListVars, "list-vars", false,
+		"list the registered environment variables as a table of their names, types, defaults and current values instead")
 }
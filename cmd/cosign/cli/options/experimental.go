@@ -16,13 +16,54 @@ package options
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/cosign/featuregates"
 )
 
+// EnableExperimental reports whether COSIGN_EXPERIMENTAL is set, which enables
+// all alpha and beta feature gates.
 func EnableExperimental() bool {
 	if b, err := strconv.ParseBool(env.Getenv(env.VariableExperimental)); err == nil {
 		return b
 	}
 	return false
 }
+
+// ExperimentalFeatures returns the enabled alpha and beta feature gates.
+func ExperimentalFeatures() []string {
+	features := []string{}
+	gates, err := featuregates.Gates()
+	if err != nil {
+		return features
+	}
+	for _, g := range gates {
+		if g.Enabled && g.Stage != featuregates.GA {
+			features = append(features, string(g.Feature))
+		}
+	}
+	return features
+}
+
+// featureGatesValue is the value of the --feature-gates flag, which sets the
+// feature gates as it's parsed.
+type featureGatesValue struct {
+	values []string
+}
+
+func (v *featureGatesValue) String() string {
+	return strings.Join(v.values, ",")
+}
+
+func (v *featureGatesValue) Set(s string) error {
+	if err := featuregates.SetFromFlag(s); err != nil {
+		return err
+	}
+	v.values = append(v.values, s)
+	return nil
+}
+
+func (v *featureGatesValue) Type() string {
+	return "mapStringBool"
+}
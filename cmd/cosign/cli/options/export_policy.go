@@ -0,0 +1,46 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ExportPolicyOptions is the top level wrapper for the export-policy command.
+type ExportPolicyOptions struct {
+	Policy string
+	Format string
+	Name   string
+	Rekor  RekorOptions
+}
+
+var _ Interface = (*ExportPolicyOptions)(nil)
+
+// AddFlags implements Interface
+func (o *ExportPolicyOptions) AddFlags(cmd *cobra.Command) {
+	o.Rekor.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Policy, "policy", "",
+		"path to the JSON policy of cosign proxy to export")
+	_ = cmd.Flags().SetAnnotation("policy", cobra.BashCompFilenameExt, []string{"json"})
+	_ = cmd.MarkFlagRequired("policy")
+
+	cmd.Flags().StringVar(&o.Format, "format", "kyverno",
+		"admission policy format to export to (kyverno|openshift)")
+
+	cmd.Flags().StringVar(&o.Name, "name", "cosign",
+		"name of the exported policy resources")
+}
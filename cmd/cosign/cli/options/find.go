@@ -0,0 +1,84 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// FindRepoOptions is the top level wrapper for the find repo command.
+type FindRepoOptions struct {
+	Key        string
+	Identity   string
+	OIDCIssuer string
+	Since      string
+	Until      string
+	Output     string
+	Registry   RegistryOptions
+}
+
+var _ Interface = (*FindRepoOptions)(nil)
+
+// AddFlags implements Interface
+func (o *FindRepoOptions) AddFlags(cmd *cobra.Command) {
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"only find signatures and attestations made with this key: a path, URL or KMS URI of the public key, "+
+			"or the sha256:<hex> fingerprint of its DER encoding (which only matches signatures carrying a certificate)")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.Identity, "identity", "",
+		"only find signatures and attestations whose certificate identity (email or URI SAN) is this value")
+
+	cmd.Flags().StringVar(&o.OIDCIssuer, "oidc-issuer", "",
+		"only find signatures and attestations whose certificate was issued for this OIDC issuer")
+
+	cmd.Flags().StringVar(&o.Since, "since", "",
+		"only find entries signed at or after this time, as a date (2006-01-02) or an RFC 3339 timestamp. "+
+			"The signing time is the transparency log integration time, or else the start of the certificate's validity; "+
+			"entries with neither are skipped")
+
+	cmd.Flags().StringVar(&o.Until, "until", "",
+		"only find entries signed before this time, as a date (2006-01-02) or an RFC 3339 timestamp")
+
+	cmd.Flags().StringVar(&o.Output, "output", "text",
+		"output format: text or json")
+}
+
+// TimeRange returns the parsed --since and --until times. Unset times are
+// zero.
+func (o *FindRepoOptions) TimeRange() (since, until time.Time, err error) {
+	if since, err = parseFindTime(o.Since); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("parsing --since: %w", err)
+	}
+	if until, err = parseFindTime(o.Until); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("parsing --until: %w", err)
+	}
+	return since, until, nil
+}
+
+func parseFindTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
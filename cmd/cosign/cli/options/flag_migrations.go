@@ -0,0 +1,105 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/mod/semver"
+	"sigs.k8s.io/release-utils/version"
+)
+
+// FlagMigration is a flag that was renamed, and is still accepted as an alias
+// of its successor until the Until release.
+type FlagMigration struct {
+	Old   string
+	New   string
+	Until string
+}
+
+// FlagMigrations are the renamed flags, which cosign migrate-flags rewrites
+// to their successors.
+var FlagMigrations = []FlagMigration{
+	{Old: "cert", New: "certificate", Until: "v3.0.0"},
+	{Old: "cert-chain", New: "certificate-chain", Until: "v3.0.0"},
+	{Old: "cert-identity", New: "certificate-identity", Until: "v3.0.0"},
+	{Old: "cert-oidc-issuer", New: "certificate-oidc-issuer", Until: "v3.0.0"},
+	{Old: "output-cert", New: "output-certificate", Until: "v3.0.0"},
+	// cosign 1 verified the email of Fulcio certificates, which is their
+	// identity for email accounts.
+	{Old: "cert-email", New: "certificate-identity", Until: "v3.0.0"},
+	{Old: "certificate-email", New: "certificate-identity", Until: "v3.0.0"},
+	{Old: "force", New: "yes", Until: "v3.0.0"},
+}
+
+// FlagMigrationFor returns the migration of the flag named old.
+func FlagMigrationFor(old string) (FlagMigration, bool) {
+	for _, m := range FlagMigrations {
+		if m.Old == old {
+			return m, true
+		}
+	}
+	return FlagMigration{}, false
+}
+
+// removed reports whether this release of cosign no longer accepts m.Old.
+// Development builds accept all of them.
+func (m FlagMigration) removed() bool {
+	v := version.GetVersionInfo().GitVersion
+	return semver.IsValid(v) && semver.Compare(semver.Canonical(v), m.Until) >= 0
+}
+
+// AddFlagMigrations adds the old flags of FlagMigrations to cmd and its
+// subcommands that have their successors, as hidden aliases that warn that
+// they are deprecated.
+func AddFlagMigrations(cmd *cobra.Command) {
+	for _, fs := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()} {
+		for _, m := range FlagMigrations {
+			f := fs.Lookup(m.New)
+			if f == nil || fs.Lookup(m.Old) != nil || m.removed() {
+				continue
+			}
+			fs.AddFlag(&pflag.Flag{
+				Name:        m.Old,
+				Usage:       f.Usage,
+				Value:       &flagAlias{fs: fs, name: m.New},
+				DefValue:    f.DefValue,
+				NoOptDefVal: f.NoOptDefVal,
+				Hidden:      true,
+				Deprecated:  fmt.Sprintf("use --%s instead, --%s will be removed in cosign %s. cosign migrate-flags rewrites scripts to the new flags", m.New, m.Old, m.Until),
+			})
+		}
+	}
+	for _, c := range cmd.Commands() {
+		AddFlagMigrations(c)
+	}
+}
+
+// flagAlias sets the flag it's an alias of.
+type flagAlias struct {
+	fs   *pflag.FlagSet
+	name string
+}
+
+var _ pflag.Value = (*flagAlias)(nil)
+
+func (a *flagAlias) Set(s string) error { return a.fs.Set(a.name, s) }
+
+func (a *flagAlias) String() string { return a.fs.Lookup(a.name).Value.String() }
+
+func (a *flagAlias) Type() string { return a.fs.Lookup(a.name).Value.Type() }
@@ -0,0 +1,66 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAddFlagMigrations(t *testing.T) {
+	var certificate string
+	var yes bool
+	root := &cobra.Command{Use: "cosign"}
+	sub := &cobra.Command{Use: "verify", Run: func(*cobra.Command, []string) {}}
+	sub.Flags().StringVar(&certificate, "certificate", "", "")
+	sub.Flags().BoolVar(&yes, "yes", false, "")
+	root.AddCommand(sub)
+	AddFlagMigrations(root)
+
+	if root.Flags().Lookup("cert") != nil {
+		t.Error("AddFlagMigrations() added --cert to a command without --certificate")
+	}
+	f := sub.Flags().Lookup("cert")
+	if f == nil || !f.Hidden || f.Deprecated == "" {
+		t.Fatalf("--cert = %+v, want a hidden deprecated flag", f)
+	}
+
+	sub.Flags().SetOutput(io.Discard)
+	if err := sub.ParseFlags([]string{"--cert", "c.pem", "--force"}); err != nil {
+		t.Fatal(err)
+	}
+	if certificate != "c.pem" || !yes {
+		t.Errorf("--cert and --force set certificate = %q and yes = %t", certificate, yes)
+	}
+	if !sub.Flags().Changed("certificate") {
+		t.Error("--cert didn't mark --certificate changed")
+	}
+}
+
+func TestFlagMigrations(t *testing.T) {
+	seen := map[string]bool{}
+	for _, m := range FlagMigrations {
+		if seen[m.Old] {
+			t.Errorf("--%s is migrated twice", m.Old)
+		}
+		seen[m.Old] = true
+		if _, ok := FlagMigrationFor(m.New); ok {
+			t.Errorf("--%s is migrated to the migrated --%s", m.Old, m.New)
+		}
+	}
+}
@@ -26,6 +26,7 @@ type FulcioOptions struct {
 	URL                      string
 	IdentityToken            string
 	InsecureSkipFulcioVerify bool
+	ACME                     ACMEOptions
 }
 
 var _ Interface = (*FulcioOptions)(nil)
@@ -34,11 +35,16 @@ var _ Interface = (*FulcioOptions)(nil)
 func (o *FulcioOptions) AddFlags(cmd *cobra.Command) {
 	// TODO: change this back to api.SigstorePublicServerURL after the v1 migration is complete.
 	cmd.Flags().StringVar(&o.URL, "fulcio-url", DefaultFulcioURL,
-		"address of sigstore PKI server")
+		"address of sigstore PKI server, or of a local CA to request short-lived certificates from in disconnected environments, "+
+			"exec:<path> of a helper executable or unix:<path> of a socket speaking the cosign.sigstore.dev/local-ca/v1 protocol, "+
+			"or acme:<directory URL> of an ACME CA, see --acme-profile and --acme-identifier. "+
+			"Their certificates have no SCTs, and are verified with --local-ca-roots, or --acme-ca-roots for ACME CAs")
 
 	cmd.Flags().StringVar(&o.IdentityToken, "identity-token", "",
 		"identity token to use for certificate from fulcio. the token or a path to a file containing the token is accepted.")
 
 	cmd.Flags().BoolVar(&o.InsecureSkipFulcioVerify, "insecure-skip-verify", false,
 		"skip verifying fulcio published to the SCT (this should only be used for testing).")
+
+	o.ACME.AddFlags(cmd)
 }
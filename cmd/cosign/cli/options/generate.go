@@ -22,7 +22,10 @@ import (
 // GenerateOptions is the top level wrapper for the generate command.
 type GenerateOptions struct {
 	AnnotationOptions
-	Registry RegistryOptions
+	Registry    RegistryOptions
+	Digest      string
+	DigestCache string
+	Offline     bool
 }
 
 var _ Interface = (*GenerateOptions)(nil)
@@ -31,4 +34,15 @@ var _ Interface = (*GenerateOptions)(nil)
 func (o *GenerateOptions) AddFlags(cmd *cobra.Command) {
 	o.AnnotationOptions.AddFlags(cmd)
 	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Digest, "digest", "",
+		"the digest of the image, so that it isn't looked up in the registry")
+
+	cmd.Flags().StringVar(&o.DigestCache, "digest-cache", "",
+		"path to a JSON file of the digests of image references: the digest of the image is read from the file if it has it, "+
+			"otherwise it is looked up in the registry and added to the file, so that the file can be copied to an offline machine")
+	_ = cmd.Flags().SetAnnotation("digest-cache", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().BoolVar(&o.Offline, "offline", false,
+		"never look the digest of the image up in the registry: the image must be a digest, or have --digest or an entry in --digest-cache")
 }
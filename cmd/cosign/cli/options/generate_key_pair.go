@@ -24,6 +24,9 @@ type GenerateKeyPairOptions struct {
 	// KMS Key Management Service
 	KMS             string
 	OutputKeyPrefix string
+	// CreateRemote creates the KMS key, if missing, with the recommended
+	// settings of its provider and prints the IAM policies it needs.
+	CreateRemote bool
 }
 
 var _ Interface = (*GenerateKeyPairOptions)(nil)
@@ -34,4 +37,7 @@ func (o *GenerateKeyPairOptions) AddFlags(cmd *cobra.Command) {
 		"create key pair in KMS service to use for signing")
 	cmd.Flags().StringVar(&o.OutputKeyPrefix, "output-key-prefix", "cosign",
 		"name used for generated .pub and .key files (defaults to `cosign`)")
+	cmd.Flags().BoolVar(&o.CreateRemote, "create-remote", false,
+		"with --kms, create the key if it doesn't exist with the recommended algorithm and protection level of the provider, "+
+			"and print the minimal IAM policies that signing and verifying with it need")
 }
@@ -26,6 +26,15 @@ type ImportKeyPairOptions struct {
 
 	// Filename used for outputted keys
 	OutputKeyPrefix string
+
+	// Format of Key, detected if empty
+	Format string
+
+	// RSA private key and hash that unwrap rsa-aes-key-wrap key material
+	WrappingKey  string
+	WrappingHash string
+
+	SkipConfirmation bool
 }
 
 var _ Interface = (*ImportKeyPairOptions)(nil)
@@ -39,4 +48,18 @@ func (o *ImportKeyPairOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.OutputKeyPrefix, "output-key-prefix", "o", "import-cosign",
 		"name used for outputted key pairs")
 	_ = cmd.Flags().SetAnnotation("output-key-prefix", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.Format, "format", "",
+		"format of the imported key: pem, pkcs12 (a .p12 or .pfx bundle), aws-data-key-pair (the JSON output of aws kms generate-data-key-pair) "+
+			"or rsa-aes-key-wrap (key material wrapped for AWS or GCP KMS key import, unwrapped with --wrapping-key). Detected if empty")
+
+	cmd.Flags().StringVar(&o.WrappingKey, "wrapping-key", "",
+		"path to the PEM encoded RSA private key that unwraps rsa-aes-key-wrap key material")
+	_ = cmd.Flags().SetAnnotation("wrapping-key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.WrappingHash, "wrapping-hash", "sha256",
+		"hash of the RSA-OAEP encryption of rsa-aes-key-wrap key material (sha1|sha256)")
+
+	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
+		"skip confirmation of the fingerprint of the imported key")
 }
@@ -0,0 +1,39 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "github.com/spf13/cobra"
+
+// InspectOptions is the top level wrapper for the inspect command.
+type InspectOptions struct {
+	Registry    RegistryOptions
+	Interactive bool
+	ExportRefs  string
+}
+
+var _ Interface = (*InspectOptions)(nil)
+
+// AddFlags implements Interface
+func (o *InspectOptions) AddFlags(cmd *cobra.Command) {
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().BoolVar(&o.Interactive, "interactive", false,
+		"browse the signatures, attestations, certificates and transparency log entries in a terminal UI")
+
+	cmd.Flags().StringVar(&o.ExportRefs, "export-refs", "",
+		"write the image reference by digest, its signature and attestation tags, and the digests of the "+
+			"signatures and attestations found to this file as JSON")
+	_ = cmd.Flags().SetAnnotation("export-refs", cobra.BashCompFilenameExt, []string{"json"})
+}
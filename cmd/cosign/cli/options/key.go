@@ -35,10 +35,26 @@ type KeyOpts struct {
 	SkipConfirmation     bool
 	TSAServerURL         string
 	RFC3161TimestampPath string
-	TSACertChainPath     string
+	TSACertChainPaths    []string
 	// IssueCertificate controls whether to issue a certificate when a key is
 	// provided.
 	IssueCertificateForExistingKey bool
+	// SkipCertificate signs with an ephemeral key instead of requesting a
+	// certificate from Fulcio, for dry runs of keyless signing.
+	SkipCertificate bool
+	// SigningServerURL, if set, signs with the key of the delegated signing
+	// service at this URL, see the signingserver package.
+	SigningServerURL string
+
+	// OIDCTokenFile, if set, is the path of the OIDC token to request the
+	// certificate with, read when the certificate is requested.
+	OIDCTokenFile string
+	// OIDCAudience, if set, is the audience ambient providers request tokens
+	// for, which the tokens sent to Fulcio must have.
+	OIDCAudience string
+	// OIDCTokenExchangeIssuer, if set, is the issuer the OIDC token is
+	// exchanged at before it is sent to Fulcio.
+	OIDCTokenExchangeIssuer string
 
 	// FulcioAuthFlow is the auth flow to use when authenticating against
 	// Fulcio. See https://pkg.go.dev/github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio#pkg-constants
@@ -48,4 +64,20 @@ type KeyOpts struct {
 	// Modeled after InsecureSkipVerify in tls.Config, this disables
 	// verifying the SCT.
 	InsecureSkipFulcioVerify bool
+
+	// ACME configures the requests of certificates from the ACME CA of
+	// FulcioURL, if it is acme:<directory URL>.
+	ACME ACMEOptions
+
+	// SigningConfig selects the transparency log to upload to in place of
+	// RekorURL, if set.
+	SigningConfig *cosign.SigningConfig
+
+	// BlobDigestAlgorithm is the name of the digest algorithm blobs are
+	// signed with, see blob.LookupDigestAlgorithm.
+	BlobDigestAlgorithm string
+
+	// Deprecated: TSACertChainPath is added to TSACertChainPaths. Use
+	// TSACertChainPaths.
+	TSACertChainPath string
 }
@@ -0,0 +1,45 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// LicensePolicyOptions is the wrapper for the license policy that the SPDX
+// and CycloneDX attestations of an image are checked against.
+type LicensePolicyOptions struct {
+	Deny      []string
+	Allowlist string
+}
+
+var _ Interface = (*LicensePolicyOptions)(nil)
+
+// AddFlags implements Interface
+func (o *LicensePolicyOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&o.Deny, "deny-license", nil,
+		"fail if a package of a verified SPDX or CycloneDX attestation can only be used under this SPDX license ID, e.g. GPL-3.0-only (can be repeated)")
+
+	cmd.Flags().StringVar(&o.Allowlist, "license-allowlist", "",
+		"path to a file of the SPDX license IDs that the packages of the verified SPDX and CycloneDX attestations may be used under, one per line; "+
+			"packages without a license are NOASSERTION")
+	_ = cmd.Flags().SetAnnotation("license-allowlist", cobra.BashCompFilenameExt, []string{})
+}
+
+// Enabled reports whether a license policy is set.
+func (o *LicensePolicyOptions) Enabled() bool {
+	return len(o.Deny) > 0 || o.Allowlist != ""
+}
@@ -0,0 +1,37 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// LintPipelineOptions is the top level wrapper for the lint-pipeline command.
+type LintPipelineOptions struct {
+	Output string
+	Strict bool
+}
+
+var _ Interface = (*LintPipelineOptions)(nil)
+
+// AddFlags implements Interface
+func (o *LintPipelineOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Output, "output", "text",
+		"output format for the findings, text or json")
+
+	cmd.Flags().BoolVar(&o.Strict, "strict", false,
+		"fail on warnings as well as errors")
+}
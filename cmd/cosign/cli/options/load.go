@@ -21,8 +21,9 @@ import (
 
 // LoadOptions is the top level wrapper for the load command.
 type LoadOptions struct {
-	Directory string
-	Registry  RegistryOptions
+	Directory    string
+	VerifyPolicy string
+	Registry     RegistryOptions
 }
 
 var _ Interface = (*LoadOptions)(nil)
@@ -34,4 +35,8 @@ func (o *LoadOptions) AddFlags(cmd *cobra.Command) {
 		"path to directory where the signed image is stored on disk")
 	_ = cmd.Flags().SetAnnotation("dir", cobra.BashCompSubdirsInDir, []string{})
 	_ = cmd.MarkFlagRequired("dir")
+
+	cmd.Flags().StringVar(&o.VerifyPolicy, "verify-policy", "",
+		"path to a JSON policy with the key or certificate identity the image's signatures must be verified with before it is loaded")
+	_ = cmd.Flags().SetAnnotation("verify-policy", cobra.BashCompFilenameExt, []string{"json"})
 }
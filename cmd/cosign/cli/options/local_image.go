@@ -0,0 +1,54 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// LocalImageOptions is the wrapper for the flags of sign, attest and attach
+// that store signatures with an image on disk rather than in a registry.
+type LocalImageOptions struct {
+	Enabled bool
+	Output  string
+}
+
+var _ Interface = (*LocalImageOptions)(nil)
+
+// AddFlags implements Interface
+func (o *LocalImageOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.Enabled, "local-image", false,
+		"whether the specified image is a path to an OCI layout or a docker tarball, "+
+			"whose signatures are stored in the OCI layout rather than pushed to a registry")
+
+	cmd.Flags().StringVar(&o.Output, "local-image-output", "",
+		"the OCI layout to write the signed local image to, by default the OCI layout itself. "+
+			"Required for docker tarballs, which can't hold signatures")
+}
+
+// Destination returns the OCI layout to write the signed local image at
+// path to, which is a docker tarball if tarball is true.
+func (o *LocalImageOptions) Destination(path string, tarball bool) (string, error) {
+	if o.Output != "" {
+		return o.Output, nil
+	}
+	if tarball {
+		return "", fmt.Errorf("%s is a docker tarball, which can't hold signatures; write the signed image to an OCI layout with --local-image-output", path)
+	}
+	return path, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "github.com/spf13/cobra"
+
+// LoginOptions is the top level wrapper for the login command.
+type LoginOptions struct {
+	Username          string
+	Password          string
+	PasswordStdin     bool
+	IdentityToken     string
+	CredentialHelper  string
+	Probe             bool
+	ProbeRepository   string
+	AllowInsecure     bool
+	AllowHTTPRegistry bool
+}
+
+var _ Interface = (*LoginOptions)(nil)
+
+// AddFlags implements Interface
+func (o *LoginOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Username, "username", "u", "", "Username")
+
+	cmd.Flags().StringVarP(&o.Password, "password", "p", "", "Password")
+
+	cmd.Flags().BoolVar(&o.PasswordStdin, "password-stdin", false, "Take the password from stdin")
+
+	cmd.Flags().StringVar(&o.IdentityToken, "identity-token", "",
+		"identity (refresh) token to store instead of a password, for registries that exchange it for access tokens")
+
+	cmd.Flags().StringVar(&o.CredentialHelper, "credential-helper", "",
+		"name of the docker-credential-<helper> used to store the credentials for this registry. "+
+			"If unset, the configured or platform default helper is used")
+
+	cmd.Flags().BoolVar(&o.Probe, "probe", true,
+		"validate the credentials against the registry before storing them")
+
+	cmd.Flags().StringVar(&o.ProbeRepository, "probe-repository", "",
+		"repository on the registry to check pull and push access to, e.g. my-org/my-image")
+
+	cmd.Flags().BoolVar(&o.AllowInsecure, "allow-insecure-registry", false,
+		"whether to allow insecure connections to registries (e.g., with expired or self-signed TLS certificates). Don't use this for anything but testing")
+
+	cmd.Flags().BoolVar(&o.AllowHTTPRegistry, "allow-http-registry", false,
+		"whether to allow using HTTP protocol while connecting to registries. Don't use this for anything but testing")
+}
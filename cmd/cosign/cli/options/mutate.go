@@ -0,0 +1,95 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// MutateOptions is the top level wrapper for the mutate command.
+type MutateOptions struct {
+	Annotations        map[string]string
+	Labels             map[string]string
+	Tag                string
+	Key                string
+	Cert               string
+	CertChain          string
+	SkipConfirmation   bool
+	TlogUpload         bool
+	TSAServerURL       string
+	SignedByIdentity   string
+	SignedByOIDCIssuer string
+	Force              bool
+
+	Rekor       RekorOptions
+	Fulcio      FulcioOptions
+	OIDC        OIDCOptions
+	SecurityKey SecurityKeyOptions
+	Registry    RegistryOptions
+}
+
+var _ Interface = (*MutateOptions)(nil)
+
+// AddFlags implements Interface
+func (o *MutateOptions) AddFlags(cmd *cobra.Command) {
+	o.Rekor.AddFlags(cmd)
+	o.Fulcio.AddFlags(cmd)
+	o.OIDC.AddFlags(cmd)
+	o.SecurityKey.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringToStringVarP(&o.Annotations, "annotation", "a", nil,
+		"manifest annotations to set, as key=value pairs; may be repeated")
+
+	cmd.Flags().StringToStringVarP(&o.Labels, "label", "l", nil,
+		"config labels to set, as key=value pairs; may be repeated, and not supported for image indexes")
+
+	cmd.Flags().StringVar(&o.Tag, "tag", "",
+		"tag to point at the mutated image, instead of the tag of the image given")
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"path to the private key file, KMS URI or Kubernetes Secret")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{"key"})
+
+	cmd.Flags().StringVar(&o.Cert, "certificate", "",
+		"path to the X.509 certificate in PEM format to include in the OCI Signature")
+	_ = cmd.Flags().SetAnnotation("certificate", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().StringVar(&o.CertChain, "certificate-chain", "",
+		"path to a list of CA X.509 certificates in PEM format which will be needed "+
+			"when building the certificate chain for the signing certificate. "+
+			"Must start with the parent intermediate CA certificate of the "+
+			"signing certificate and end with the root certificate. Included in the OCI Signature")
+	_ = cmd.Flags().SetAnnotation("certificate-chain", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
+		"skip confirmation prompts for non-destructive operations")
+
+	cmd.Flags().BoolVar(&o.TlogUpload, "tlog-upload", true,
+		"whether or not to upload to the tlog")
+
+	cmd.Flags().StringVar(&o.TSAServerURL, "timestamp-server-url", "",
+		"url to the Timestamp RFC3161 server, default none. Must be the path to the API to request timestamp responses, e.g. https://freetsa.org/tsr")
+
+	cmd.Flags().StringVar(&o.SignedByIdentity, "signed-by-identity", "",
+		"when signing keyless, the certificate identity (email or URI SAN) of the signatures and attestations to carry forward")
+
+	cmd.Flags().StringVar(&o.SignedByOIDCIssuer, "signed-by-oidc-issuer", "",
+		"when signing keyless, the OIDC issuer of the certificates of the signatures and attestations to carry forward")
+
+	cmd.Flags().BoolVarP(&o.Force, "force", "f", false,
+		"mutate the image even if it has no signature of the signer")
+}
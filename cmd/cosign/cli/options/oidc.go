@@ -34,6 +34,9 @@ type OIDCOptions struct {
 	RedirectURL             string
 	Provider                string
 	DisableAmbientProviders bool
+	TokenFile               string
+	Audience                string
+	TokenExchangeIssuer     string
 }
 
 func (o *OIDCOptions) ClientSecret() (string, error) {
@@ -70,8 +73,23 @@ func (o *OIDCOptions) AddFlags(cmd *cobra.Command) {
 		"OIDC redirect URL (Optional). The default oidc-redirect-url is 'http://localhost:0/auth/callback'.")
 
 	cmd.Flags().StringVar(&o.Provider, "oidc-provider", "",
-		"Specify the provider to get the OIDC token from (Optional). If unset, all options will be tried. Options include: [spiffe, google, github, filesystem, buildkite-agent]")
+		"Specify the provider to get the OIDC token from (Optional). If unset, all options will be tried. Options include: [spiffe, google, github, filesystem, buildkite-agent], "+
+			"or exec:<path> for a helper executable that writes an identity token, e.g. of a site-specific SSO system, "+
+			"as described at https://pkg.go.dev/github.com/sigstore/cosign/v2/pkg/providers/exec")
 
 	cmd.Flags().BoolVar(&o.DisableAmbientProviders, "oidc-disable-ambient-providers", false,
 		"Disable ambient OIDC providers. When true, ambient credentials will not be read")
+
+	cmd.Flags().StringVar(&o.TokenFile, "oidc-token-file", "",
+		"Path to a file containing the OIDC token of a CI job to request the certificate with, read when the certificate is requested. "+
+			"The token is exchanged first with --oidc-token-exchange-issuer if set")
+	_ = cmd.Flags().SetAnnotation("oidc-token-file", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.Audience, "oidc-audience", "",
+		"Audience of the OIDC token sent to Fulcio (Optional). When set, ambient providers request tokens for it, "+
+			"and tokens issued for another audience are rejected before requesting the certificate. The default audience is 'sigstore'.")
+
+	cmd.Flags().StringVar(&o.TokenExchangeIssuer, "oidc-token-exchange-issuer", "",
+		"OIDC issuer whose token endpoint exchanges the OIDC token for one Fulcio accepts (Optional), with the RFC 8693 token exchange. "+
+			"Exchanges are authenticated with --oidc-client-id and --oidc-client-secret-file")
 }
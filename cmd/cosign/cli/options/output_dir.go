@@ -0,0 +1,46 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// DefaultOutputName is the default --output-name template.
+const DefaultOutputName = "{name}.{kind}"
+
+// OutputDirOptions is the wrapper for the output directory of the files
+// written for each of several blobs.
+type OutputDirOptions struct {
+	Dir  string
+	Name string
+}
+
+var _ Interface = (*OutputDirOptions)(nil)
+
+// AddFlags implements Interface
+func (o *OutputDirOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Dir, "output-dir", "",
+		"write the files of each of several blobs, given as paths or globs and signed with one key and certificate, "+
+			"with a single Fulcio request for keyless signing, to DIR: its signature and bundle, and its RFC3161 timestamp "+
+			"with --timestamp-server-url, named with --output-name, and the index of the files to DIR/index.json")
+	_ = cmd.Flags().SetAnnotation("output-dir", cobra.BashCompSubdirsInDir, []string{})
+
+	cmd.Flags().StringVar(&o.Name, "output-name", DefaultOutputName,
+		"template of the names of the files written to --output-dir, with the placeholders {name}, the file name of the blob, "+
+			"{alg} and {digest}, the algorithm and hex digest of the blob, and {kind}, sig, bundle or timestamp.json, "+
+			"e.g. {name}.{alg}.{kind} for app.tar.gz.sha256.bundle")
+}
@@ -0,0 +1,37 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// OutputTemplateOptions is the wrapper for the Go template that formats the
+// output of a command.
+type OutputTemplateOptions struct {
+	Template string
+}
+
+var _ Interface = (*OutputTemplateOptions)(nil)
+
+// AddFlags implements Interface
+func (o *OutputTemplateOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Template, "output-template", "",
+		"format the output with a Go template, inline if it contains {{ or else the path to the template file, applied to "+
+			"the objects of the JSON output with their JSON field names, e.g. '{{range .subjects}}{{.name}}: {{.status}}{{\"\\n\"}}{{end}}'. "+
+			"The functions json, join, upper, lower and base64decode are available")
+	_ = cmd.Flags().SetAnnotation("output-template", cobra.BashCompFilenameExt, []string{"tmpl"})
+}
@@ -55,3 +55,29 @@ func (o *PKCS11ToolListKeysUrisOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.Pin, "pin", "",
 		"pin of the PKCS11 slot, uses environment variable COSIGN_PKCS11_PIN if empty")
 }
+
+// PKCS11ToolListKeysOptions is the wrapper for `pkcs11-tool list-keys` related options.
+type PKCS11ToolListKeysOptions struct {
+	ModulePath string
+	SlotID     uint
+	Pin        string
+	JSON       bool
+}
+
+var _ Interface = (*PKCS11ToolListKeysOptions)(nil)
+
+// AddFlags implements Interface
+func (o *PKCS11ToolListKeysOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.ModulePath, "module-path", env.Getenv(env.VariablePKCS11ModulePath),
+		"absolute path to the PKCS11 module")
+	_ = cmd.Flags().SetAnnotation("module-path", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().UintVar(&o.SlotID, "slot-id", 0,
+		"id of the PKCS11 slot, uses 0 if empty")
+
+	cmd.Flags().StringVar(&o.Pin, "pin", "",
+		"pin of the PKCS11 slot, uses environment variable COSIGN_PKCS11_PIN if empty")
+
+	cmd.Flags().BoolVar(&o.JSON, "json", false,
+		"print the keys as a JSON array")
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/in-toto/in-toto-golang/in_toto"
 	"github.com/spf13/cobra"
 
+	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
 )
 
@@ -35,6 +36,8 @@ const (
 	PredicateCycloneDX = "cyclonedx"
 	PredicateLink      = "link"
 	PredicateVuln      = "vuln"
+	PredicateBaseImage = "baseimage"
+	PredicateBuild     = "buildmetadata"
 )
 
 // PredicateTypeMap is the mapping between the predicate `type` option to predicate URI.
@@ -46,11 +49,15 @@ var PredicateTypeMap = map[string]string{
 	PredicateCycloneDX: in_toto.PredicateCycloneDX,
 	PredicateLink:      in_toto.PredicateLinkV1,
 	PredicateVuln:      attestation.CosignVulnProvenanceV01,
+	PredicateBaseImage: attestation.CosignBaseImageV01,
+	PredicateBuild:     attestation.CosignBuildMetadataV01,
 }
 
 // PredicateOptions is the wrapper for predicate related options.
 type PredicateOptions struct {
-	Type string
+	Type    string
+	Schemas string
+	Schema  string
 }
 
 var _ Interface = (*PredicateOptions)(nil)
@@ -58,15 +65,36 @@ var _ Interface = (*PredicateOptions)(nil)
 // AddFlags implements Interface
 func (o *PredicateOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.Type, "type", "custom",
-		"specify a predicate type (slsaprovenance|link|spdx|spdxjson|cyclonedx|vuln|custom) or an URI")
+		"specify a predicate type (slsaprovenance|link|spdx|spdxjson|cyclonedx|vuln|baseimage|buildmetadata|custom), an URI "+
+			"or the name of a predicate plug-in in ~/.cosign/predicates")
+	o.addSchemasFlag(cmd)
+}
+
+func (o *PredicateOptions) addSchemasFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Schemas, "predicate-schemas", "",
+		"path to a registry of JSON schemas for custom predicate types, of the form {\"predicateTypes\": {\"<type URI>\": \"<schema file or OCI reference>\"}}. "+
+			"Predicates of registered types must match their schema. Defaults to $COSIGN_PREDICATE_SCHEMAS")
+	_ = cmd.Flags().SetAnnotation("predicate-schemas", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().StringVar(&o.Schema, "predicate-schema", "",
+		"path to a JSON schema that predicates of the --type predicate type must match, in place of its registered schema")
+	_ = cmd.Flags().SetAnnotation("predicate-schema", cobra.BashCompFilenameExt, []string{"json"})
 }
 
 // ParsePredicateType parses the predicate `type` flag passed into a predicate URI, or validates `type` is a valid URI.
+// Names of predicate plug-ins resolve to their predicate type.
 func ParsePredicateType(t string) (string, error) {
 	uri, ok := PredicateTypeMap[t]
 	if !ok {
 		if _, err := url.ParseRequestURI(t); err != nil {
-			return "", fmt.Errorf("invalid predicate type: %s", t)
+			plugin, perr := cosign.FindPredicatePlugin(t)
+			if perr != nil {
+				return "", perr
+			}
+			if plugin == nil {
+				return "", fmt.Errorf("invalid predicate type: %s", t)
+			}
+			return plugin.PredicateType, nil
 		}
 		uri = t
 	}
@@ -90,13 +118,18 @@ func (o *PredicateLocalOptions) AddFlags(cmd *cobra.Command) {
 }
 
 // PredicateRemoteOptions is the wrapper for remote predicate related options.
+// Types holds the values of the repeatable --type flag, in place of Type.
 type PredicateRemoteOptions struct {
 	PredicateOptions
+	Types []string
 }
 
 var _ Interface = (*PredicateRemoteOptions)(nil)
 
 // AddFlags implements Interface
 func (o *PredicateRemoteOptions) AddFlags(cmd *cobra.Command) {
-	o.PredicateOptions.AddFlags(cmd)
+	cmd.Flags().StringSliceVar(&o.Types, "type", []string{"custom"},
+		"specify a predicate type (slsaprovenance|link|spdx|spdxjson|cyclonedx|vuln|baseimage|buildmetadata|custom), an URI "+
+			"or the name of a predicate plug-in in ~/.cosign/predicates, may be repeated to verify several predicate types from a single fetch of the attestations")
+	o.addSchemasFlag(cmd)
 }
@@ -0,0 +1,38 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// PrefetchOptions is the top level wrapper for the `prefetch` command: the
+// policy of the verifications that it caches, and how many images it
+// verifies in parallel.
+type PrefetchOptions struct {
+	VerifyOptions
+	Workers int
+}
+
+var _ Interface = (*PrefetchOptions)(nil)
+
+// AddFlags implements Interface
+func (o *PrefetchOptions) AddFlags(cmd *cobra.Command) {
+	o.VerifyOptions.AddFlags(cmd)
+
+	cmd.Flags().IntVar(&o.Workers, "workers", 0,
+		"the number of images verified in parallel, the number of CPUs if 0, at most the workers of the budget config file")
+}
@@ -0,0 +1,59 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// PromoteOptions is the top level wrapper for the promote command.
+type PromoteOptions struct {
+	SignedByKey        string
+	SignedByIdentity   string
+	SignedByOIDCIssuer string
+	PredicateTypes     []string
+	SigOnly            bool
+	Force              bool
+	Registry           RegistryOptions
+}
+
+var _ Interface = (*PromoteOptions)(nil)
+
+// AddFlags implements Interface
+func (o *PromoteOptions) AddFlags(cmd *cobra.Command) {
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.SignedByKey, "signed-by-key", "",
+		"promote the signatures and attestations made with this key: a path, URL or KMS URI of the public key, "+
+			"or the sha256:<hex> fingerprint of its DER encoding (which only matches signatures carrying a certificate)")
+	_ = cmd.Flags().SetAnnotation("signed-by-key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.SignedByIdentity, "signed-by-identity", "",
+		"promote the signatures and attestations whose certificate identity (email or URI SAN) is this value")
+
+	cmd.Flags().StringVar(&o.SignedByOIDCIssuer, "signed-by-oidc-issuer", "",
+		"promote the signatures and attestations whose certificate was issued for this OIDC issuer")
+
+	cmd.Flags().StringSliceVar(&o.PredicateTypes, "predicate-type", nil,
+		"only promote the attestations of these predicate types, given as URIs or as the names accepted by "+
+			"'cosign attest --type'; may be repeated, and defaults to attestations of any type")
+
+	cmd.Flags().BoolVar(&o.SigOnly, "sig-only", false,
+		"only promote the image and its signatures, without its attestations")
+
+	cmd.Flags().BoolVarP(&o.Force, "force", "f", false,
+		"overwrite the signatures and attestations of the destination image, if necessary")
+}
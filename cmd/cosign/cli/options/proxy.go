@@ -0,0 +1,48 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ProxyOptions is the top level wrapper for the proxy command.
+type ProxyOptions struct {
+	Address         string
+	Policy          string
+	DefaultRegistry string
+	Rekor           RekorOptions
+	Registry        RegistryOptions
+}
+
+var _ Interface = (*ProxyOptions)(nil)
+
+// AddFlags implements Interface
+func (o *ProxyOptions) AddFlags(cmd *cobra.Command) {
+	o.Rekor.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Address, "address", "localhost:5000",
+		"address the registry proxy listens on")
+
+	cmd.Flags().StringVar(&o.Policy, "policy", "",
+		"path to a JSON policy with the key or certificate identity the images of each repository must be verified with before they are served")
+	_ = cmd.Flags().SetAnnotation("policy", cobra.BashCompFilenameExt, []string{"json"})
+	_ = cmd.MarkFlagRequired("policy")
+
+	cmd.Flags().StringVar(&o.DefaultRegistry, "default-registry", "index.docker.io",
+		"registry to pull the repositories from that don't start with a registry host")
+}
@@ -19,17 +19,20 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 
-	ecr "github.com/awslabs/amazon-ecr-credential-helper/ecr-login"
-	"github.com/chrismellard/docker-credential-acr-env/pkg/credhelper"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/authn/github"
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/google/go-containerregistry/pkg/v1/google"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
-	alibabaacr "github.com/mozillazg/docker-credential-acr-helper/pkg/credhelper"
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/internal/pkg/daemon"
+	"github.com/sigstore/cosign/v2/internal/pkg/forensics"
+	"github.com/sigstore/cosign/v2/internal/pkg/gcpauth"
+	"github.com/sigstore/cosign/v2/internal/pkg/telemetry"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/cosign/featuregates"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/spf13/cobra"
 )
@@ -37,11 +40,26 @@ import (
 // Keychain is an alias of authn.Keychain to expose this configuration option to consumers of this lib
 type Keychain = authn.Keychain
 
+// RegisterKeychain adds kc, named name, to the keychains that --k8s-keychain
+// reads registry credentials from, after the default keychain, and that
+// --registry-credential-helper can name. The keychains of cloud registries
+// register themselves unless cosign is built with the nocloud tag.
+func RegisterKeychain(name string, kc Keychain) {
+	ociremote.RegisterKeychain(name, kc)
+}
+
+// RegisteredKeychains returns the names of the keychains added with
+// RegisterKeychain, in the order they are read from.
+func RegisteredKeychains() []string {
+	return ociremote.RegisteredKeychains()
+}
+
 // RegistryOptions is the wrapper for the registry options.
 type RegistryOptions struct {
 	AllowInsecure      bool
 	AllowHTTPRegistry  bool
 	KubernetesKeychain bool
+	CredentialHelpers  []string
 	RefOpts            ReferenceOptions
 	Keychain           Keychain
 }
@@ -59,6 +77,12 @@ func (o *RegistryOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.KubernetesKeychain, "k8s-keychain", false,
 		"whether to use the kubernetes keychain instead of the default keychain (supports workload identity).")
 
+	cmd.Flags().StringSliceVar(&o.CredentialHelpers, "registry-credential-helper", nil,
+		"[REGISTRY=]HELPER of a credential helper asked for registry credentials before the docker config, so that the ambient credentials "+
+			"of cloud platforms work without 'docker login': a built-in keychain ("+strings.Join(RegisteredKeychains(), ", ")+
+			"), or a docker-credential-HELPER program on the PATH. With REGISTRY, only for that registry (can be repeated). "+
+			"Defaults to the comma-separated $COSIGN_REGISTRY_CREDENTIAL_HELPERS")
+
 	o.RefOpts.AddFlags(cmd)
 }
 
@@ -91,26 +115,13 @@ func (o *RegistryOptions) GetRegistryClientOpts(ctx context.Context) []remote.Op
 		remote.WithUserAgent(UserAgent()),
 	}
 
-	switch {
-	case o.Keychain != nil:
-		opts = append(opts, remote.WithAuthFromKeychain(o.Keychain))
-	case o.KubernetesKeychain:
-		kc := authn.NewMultiKeychain(
-			authn.DefaultKeychain,
-			google.Keychain,
-			authn.NewKeychainFromHelper(ecr.NewECRHelper(ecr.WithLogger(io.Discard))),
-			authn.NewKeychainFromHelper(credhelper.NewACRCredentialsHelper()),
-			authn.NewKeychainFromHelper(alibabaacr.NewACRHelper().WithLoggerOut(io.Discard)),
-			github.Keychain,
-		)
-		opts = append(opts, remote.WithAuthFromKeychain(kc))
-	default:
-		opts = append(opts, remote.WithAuthFromKeychain(authn.DefaultKeychain))
-	}
-
-	if o.AllowInsecure {
-		opts = append(opts, remote.WithTransport(&http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}})) // #nosec G402
-	}
+	opts = append(opts, remote.WithAuthFromKeychain(o.AuthKeychain()))
+	// Work around the referrers quirks of ECR and of registries without the
+	// referrers API, ask for filtered referrers, and write signature tags
+	// with conditional requests. The requests are counted for the summary of
+	// operations on many images, and recorded for failure reports.
+	tr := ociremote.CapabilitiesTransport(ociremote.ECRReferrersTransport(ociremote.ConditionalTransport(budget.Transport(telemetry.Transport(forensics.Transport(o.Transport(), telemetry.Registry), telemetry.Registry), telemetry.Registry))))
+	opts = append(opts, remote.WithTransport(ociremote.ReferrersFilterTransport(tr)))
 
 	// Reuse a remote.Pusher and a remote.Puller for all operations that use these opts.
 	// This allows us to avoid re-authenticating for everying remote.Function we call,
@@ -127,30 +138,116 @@ func (o *RegistryOptions) GetRegistryClientOpts(ctx context.Context) []remote.Op
 	return opts
 }
 
+// AuthKeychain returns the keychain the registry credentials are read from:
+// the impersonated service account of --impersonate-service-account for
+// Google registries, the keychains of the credential helpers, then the
+// default keychain. The cosign daemon keeps the credentials it resolves warm.
+func (o *RegistryOptions) AuthKeychain() authn.Keychain {
+	if o.Keychain != nil {
+		return o.Keychain
+	}
+	kcs, err := o.credentialHelperKeychains()
+	if err != nil {
+		return errorKeychain{err: err}
+	}
+	account := gcpauth.ServiceAccount()
+	if account != "" {
+		kcs = append([]authn.Keychain{gcpauth.Keychain}, kcs...)
+	}
+	return daemon.Keychain(o.defaultKeychain(kcs), fmt.Sprintf("%v/%t/%s", o.CredentialHelpers, o.KubernetesKeychain, account))
+}
+
+// defaultKeychain returns the keychain of kcs, the keychains of the
+// credential helpers, followed by the default keychains.
+func (o *RegistryOptions) defaultKeychain(kcs []authn.Keychain) authn.Keychain {
+	if o.KubernetesKeychain {
+		kcs = append(kcs, authn.DefaultKeychain)
+		for _, name := range RegisteredKeychains() {
+			kc, _ := ociremote.CredentialHelperKeychain(name)
+			kcs = append(kcs, kc)
+		}
+		kcs = append(kcs, github.Keychain)
+	} else {
+		kcs = append(kcs, authn.DefaultKeychain)
+	}
+	if len(kcs) == 1 {
+		return kcs[0]
+	}
+	return authn.NewMultiKeychain(kcs...)
+}
+
+// credentialHelperKeychains returns the keychains of --registry-credential-helper,
+// or else of $COSIGN_REGISTRY_CREDENTIAL_HELPERS.
+func (o *RegistryOptions) credentialHelperKeychains() ([]authn.Keychain, error) {
+	helpers := o.CredentialHelpers
+	if len(helpers) == 0 {
+		if v := env.Getenv(env.VariableCredentialHelpers); v != "" {
+			helpers = strings.Split(v, ",")
+		}
+	}
+	kcs := make([]authn.Keychain, 0, len(helpers)+1)
+	for _, helper := range helpers {
+		kc, err := ociremote.CredentialHelperKeychain(strings.TrimSpace(helper))
+		if err != nil {
+			return nil, err
+		}
+		kcs = append(kcs, kc)
+	}
+	return kcs, nil
+}
+
+// errorKeychain fails to resolve any credentials, so that an invalid
+// credential helper fails the registry operations rather than make them
+// anonymous.
+type errorKeychain struct {
+	err error
+}
+
+// Resolve implements authn.Keychain
+func (k errorKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return nil, k.err
+}
+
+// Transport returns the HTTP transport used to connect to registries.
+func (o *RegistryOptions) Transport() http.RoundTripper {
+	if o.AllowInsecure {
+		return &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}} // #nosec G402
+	}
+	return remote.DefaultTransport
+}
+
 type RegistryReferrersMode string
 
 const (
 	RegistryReferrersModeLegacy RegistryReferrersMode = "legacy"
 	RegistryReferrersModeOCI11  RegistryReferrersMode = "oci-1-1"
+	// RegistryReferrersModeBoth writes OCI 1.1 referrers and the legacy tags,
+	// so that verifiers that only know one of them find the signatures while
+	// clients migrate.
+	RegistryReferrersModeBoth RegistryReferrersMode = "both"
 )
 
+// Referrers reports whether the mode writes OCI 1.1 referrers.
+func (e RegistryReferrersMode) Referrers() bool {
+	return e == RegistryReferrersModeOCI11 || e == RegistryReferrersModeBoth
+}
+
+// Tags reports whether the mode writes the legacy cosign tags.
+func (e RegistryReferrersMode) Tags() bool {
+	return e != RegistryReferrersModeOCI11
+}
+
 func (e *RegistryReferrersMode) String() string {
 	return string(*e)
 }
 
 func (e *RegistryReferrersMode) Set(v string) error {
 	switch v {
-	case "legacy":
-		*e = RegistryReferrersMode(v)
-		return nil
-	case "oci-1-1":
-		if !EnableExperimental() {
-			return fmt.Errorf(`in order to use  mode "%s", you must set COSIGN_EXPERIMENTAL=1`, v)
-		}
+	case "legacy", "oci-1-1", "both":
 		*e = RegistryReferrersMode(v)
 		return nil
 	default:
-		return errors.New(`must be one of "legacy", "oci-1-1"`)
+		return errors.New(`must be one of "legacy", "oci-1-1", "both"`)
 	}
 }
 
@@ -168,5 +265,15 @@ var _ Interface = (*RegistryExperimentalOptions)(nil)
 // AddFlags implements Interface
 func (o *RegistryExperimentalOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().Var(&o.RegistryReferrersMode, "registry-referrers-mode",
-		"mode for fetching references from the registry. allowed: legacy, oci-1-1")
+		"mode for fetching references from the registry. allowed: legacy, oci-1-1, "+
+			"both to write OCI 1.1 referrers and legacy tags for mixed old and new verifiers (oci-1-1 and both require the OCI11Referrers feature gate)")
+}
+
+// CheckFeatureGates returns an error if the options need a disabled feature
+// gate.
+func (o *RegistryExperimentalOptions) CheckFeatureGates() error {
+	if o.RegistryReferrersMode.Referrers() {
+		return featuregates.Require(featuregates.OCI11Referrers)
+	}
+	return nil
 }
@@ -0,0 +1,49 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestAuthKeychainCredentialHelpers(t *testing.T) {
+	reg, err := name.NewRegistry("registry.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	o := RegistryOptions{}
+	if auth, err := o.AuthKeychain().Resolve(reg); err != nil || auth != authn.Anonymous {
+		t.Errorf("Resolve() without credential helpers = %v, %v, want anonymous", auth, err)
+	}
+
+	t.Setenv(env.VariableCredentialHelpers.String(), "registry.example.com=missing")
+	if _, err := o.AuthKeychain().Resolve(reg); err == nil || !strings.Contains(err.Error(), "credential helper missing") {
+		t.Errorf("Resolve() with the credential helper of $%s = %v, want an error", env.VariableCredentialHelpers, err)
+	}
+	// The flag takes precedence.
+	RegisterKeychain("test", authn.DefaultKeychain)
+	o.CredentialHelpers = []string{"other.example.com=test"}
+	if auth, err := o.AuthKeychain().Resolve(reg); err != nil || auth != authn.Anonymous {
+		t.Errorf("Resolve() with the credential helper of another registry = %v, %v, want anonymous", auth, err)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "github.com/spf13/cobra"
+
+// RetentionApplyOptions is the top level wrapper for the retention apply command.
+type RetentionApplyOptions struct {
+	Policy   string
+	DryRun   bool
+	Report   string
+	Force    bool
+	Registry RegistryOptions
+}
+
+var _ Interface = (*RetentionApplyOptions)(nil)
+
+// AddFlags implements Interface
+func (o *RetentionApplyOptions) AddFlags(cmd *cobra.Command) {
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Policy, "policy", "",
+		"path to the retention policy file, which sets what is kept of the signatures and attestations of the repository")
+	_ = cmd.Flags().SetAnnotation("policy", cobra.BashCompFilenameExt, []string{"json"})
+	_ = cmd.MarkFlagRequired("policy")
+
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false,
+		"only list the signatures and attestations that the policy doesn't keep, without removing anything")
+
+	cmd.Flags().StringVar(&o.Report, "report", "",
+		"write a JSON report of the signatures and attestations removed, or that would be in a dry run, to this file")
+	_ = cmd.Flags().SetAnnotation("report", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().BoolVarP(&o.Force, "force", "f", false, "do not prompt for confirmation before removing")
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "github.com/spf13/cobra"
+
+// RevokeKeyOptions is the top level wrapper for the revoke-key command.
+type RevokeKeyOptions struct {
+	Key          string
+	Repositories []string
+	Reason       string
+	SigningKey   string
+	OutputFile   string
+	RekorSearch  bool
+	Remove       bool
+	Force        bool
+	Rekor        RekorOptions
+	Registry     RegistryOptions
+}
+
+var _ Interface = (*RevokeKeyOptions)(nil)
+
+// AddFlags implements Interface
+func (o *RevokeKeyOptions) AddFlags(cmd *cobra.Command) {
+	o.Rekor.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"path to the compromised public key file, KMS URI or Kubernetes Secret")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{})
+	_ = cmd.MarkFlagRequired("key")
+
+	cmd.Flags().StringSliceVar(&o.Repositories, "repository", nil,
+		"repository to search for signatures and attestations made with the key. May be repeated, or comma-separated")
+	_ = cmd.MarkFlagRequired("repository")
+
+	cmd.Flags().StringVar(&o.Reason, "reason", "",
+		"reason for the revocation, recorded in the revocation attestation")
+
+	cmd.Flags().StringVar(&o.SigningKey, "signing-key", "",
+		"path to the private key file, KMS URI or Kubernetes Secret used to sign the revocation attestation. "+
+			"If unset, the attestation statement is written unsigned")
+	_ = cmd.Flags().SetAnnotation("signing-key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.OutputFile, "output-file", "",
+		"write the revocation attestation to this file instead of stdout")
+	_ = cmd.Flags().SetAnnotation("output-file", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().BoolVar(&o.RekorSearch, "rekor-search", true,
+		"search the transparency log for entries made with the key")
+
+	cmd.Flags().BoolVar(&o.Remove, "remove", false,
+		"remove the signatures and attestations made with the key from the registry")
+
+	cmd.Flags().BoolVarP(&o.Force, "force", "f", false, "do not prompt for confirmation before removing")
+}
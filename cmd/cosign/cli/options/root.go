@@ -30,9 +30,16 @@ const EnvPrefix = "COSIGN"
 
 // RootOptions define flags and options for the root cosign cli.
 type RootOptions struct {
-	OutputFile string
-	Verbose    bool
-	Timeout    time.Duration
+	OutputFile                string
+	Verbose                   bool
+	Timeout                   time.Duration
+	EventsFD                  int
+	SummaryFile               string
+	TUFRefresh                string
+	TUFCache                  string
+	Profile                   string
+	ImpersonateServiceAccount string
+	featureGates              featureGatesValue
 }
 
 // DefaultTimeout specifies the default timeout for commands.
@@ -51,6 +58,38 @@ func (o *RootOptions) AddFlags(cmd *cobra.Command) {
 
 	cmd.PersistentFlags().DurationVarP(&o.Timeout, "timeout", "t", DefaultTimeout,
 		"timeout for commands")
+
+	cmd.PersistentFlags().IntVar(&o.EventsFD, "events-fd", -1,
+		"write the progress and result events of operations on several images as JSON lines to this open file descriptor, "+
+			"e.g. 3 with 3>events.ndjson. Default none")
+
+	cmd.PersistentFlags().StringVar(&o.SummaryFile, "summary-file", "",
+		"write the summary of operations on several images, printed when they finish, as JSON to this file: "+
+			"the images by outcome, the slowest images and the requests made to registries and Rekor")
+	_ = cmd.PersistentFlags().SetAnnotation("summary-file", cobra.BashCompFilenameExt, []string{})
+
+	cmd.PersistentFlags().StringVar(&o.TUFRefresh, "tuf-refresh", "auto",
+		"when to refresh the TUF metadata that the trusted keys and certificates are read from: never, serving them from "+
+			"the snapshot in the TUF cache until it expires, auto, refreshing it in the background once half its validity passed, "+
+			"or always, refreshing it before verifying")
+
+	cmd.PersistentFlags().StringVar(&o.TUFCache, "tuf-cache", "user",
+		"where the TUF metadata and the snapshot of its targets are kept: user, in $TUF_ROOT or ~/.sigstore/root, system, "+
+			"reading the shared TUF cache of $COSIGN_TUF_SYSTEM_ROOT without writing to it and refreshing a temporary copy of it "+
+			"once needed, or memory, refreshing them on every run. Runs sharing a cache directory hold its lock while they write it")
+
+	cmd.PersistentFlags().StringVar(&o.Profile, "profile", "",
+		"name of the profile of the profiles config file ($COSIGN_PROFILES_FILE) whose keys, endpoints, identity constraints, "+
+			"registries and annotations the command uses. Flags set to other values than the profile's are an error. Defaults to $COSIGN_PROFILE")
+
+	cmd.PersistentFlags().StringVar(&o.ImpersonateServiceAccount, "impersonate-service-account", "",
+		"email of the GCP service account to impersonate, with the application default credentials, to sign with GCP KMS keys "+
+			"and authenticate to Google Container Registry and Artifact Registry, or a comma separated delegation chain whose last "+
+			"account is impersonated through the others. Requires the Service Account Token Creator role on the account")
+
+	cmd.PersistentFlags().Var(&o.featureGates, "feature-gates",
+		"comma separated list of <feature>=<bool> pairs that enable or disable gated features, overriding $COSIGN_FEATURE_GATES "+
+			"and the feature gates config file. cosign env lists the feature gates")
 }
 
 func BindViper(cmd *cobra.Command, args []string) {
@@ -62,20 +101,20 @@ func BindViper(cmd *cobra.Command, args []string) {
 }
 
 // callPersistentPreRun calls parent commands. PersistentPreRun
-// does not call parents PersistentPreRun functions
+// does not call parents PersistentPreRun functions. Like cobra, it passes
+// them cmd, the command that runs.
 func callPersistentPreRun(cmd *cobra.Command, args []string) {
-	if parent := cmd.Parent(); parent != nil {
+	for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
 		if parent.PersistentPreRun != nil {
-			parent.PersistentPreRun(parent, args)
+			parent.PersistentPreRun(cmd, args)
 		}
 		if parent.PersistentPreRunE != nil {
-			err := parent.PersistentPreRunE(parent, args)
+			err := parent.PersistentPreRunE(cmd, args)
 			if err != nil {
 				cmd.PrintErrln("Error:", err.Error())
 				os.Exit(1)
 			}
 		}
-		callPersistentPreRun(parent, args)
 	}
 }
 
@@ -91,6 +130,23 @@ func bindFlags(cmd *cobra.Command, v *viper.Viper) {
 	})
 }
 
+// FlagEnvVars returns the environment variables that set the flags of cmd
+// and its subcommands, as BindViper reads them.
+func FlagEnvVars(cmd *cobra.Command) map[string]bool {
+	vars := map[string]bool{}
+	add := func(f *pflag.Flag) {
+		vars[flagToEnvVar(f.Name)] = true
+	}
+	cmd.Flags().VisitAll(add)
+	cmd.PersistentFlags().VisitAll(add)
+	for _, c := range cmd.Commands() {
+		for k := range FlagEnvVars(c) {
+			vars[k] = true
+		}
+	}
+	return vars
+}
+
 func flagToEnvVar(f string) string {
 	f = strings.ToUpper(f)
 	return fmt.Sprintf("%s_%s", EnvPrefix, strings.ReplaceAll(f, "-", "_"))
@@ -0,0 +1,73 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ServerSignOptions is the top level wrapper for the server sign command.
+type ServerSignOptions struct {
+	Address     string
+	Key         string
+	Cert        string
+	CertChain   string
+	SecurityKey SecurityKeyOptions
+	Policy      string
+	AuditLog    string
+	TLSCert     string
+	TLSKey      string
+}
+
+var _ Interface = (*ServerSignOptions)(nil)
+
+// AddFlags implements Interface
+func (o *ServerSignOptions) AddFlags(cmd *cobra.Command) {
+	o.SecurityKey.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Address, "address", "localhost:8080",
+		"address the signing server listens on")
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"path to the private key file, KMS URI or Kubernetes Secret to sign with")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.Cert, "certificate", "",
+		"path to the X.509 certificate in PEM format of the key, returned to the clients")
+	_ = cmd.Flags().SetAnnotation("certificate", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().StringVar(&o.CertChain, "certificate-chain", "",
+		"path to a list of CA X.509 certificates in PEM format that issued --certificate, "+
+			"starting with its parent and ending with the root certificate, returned to the clients")
+	_ = cmd.Flags().SetAnnotation("certificate-chain", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().StringVar(&o.Policy, "policy", "",
+		"path to a JSON policy with the callers allowed to sign, by the SHA-256 digest of their bearer token, and what each may sign. "+
+			"Without a policy, the callers are authenticated with the token in $COSIGN_SIGNING_SERVER_TOKEN")
+	_ = cmd.Flags().SetAnnotation("policy", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().StringVar(&o.AuditLog, "audit-log", "",
+		"append a JSON line for each signed or rejected request to FILE, or write them to stdout with -")
+	_ = cmd.Flags().SetAnnotation("audit-log", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.TLSCert, "tls-cert", "",
+		"path to the PEM-encoded certificate to serve HTTPS with, along with --tls-key")
+	_ = cmd.Flags().SetAnnotation("tls-cert", cobra.BashCompFilenameExt, []string{"cert"})
+
+	cmd.Flags().StringVar(&o.TLSKey, "tls-key", "",
+		"path to the PEM-encoded private key of --tls-cert")
+	_ = cmd.Flags().SetAnnotation("tls-key", cobra.BashCompFilenameExt, []string{"key"})
+}
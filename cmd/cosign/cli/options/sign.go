@@ -31,11 +31,19 @@ type SignOptions struct {
 	OutputCertificate string
 	PayloadPath       string
 	Recursive         bool
+	ResumeFrom        string
+	SignConverted     bool
 	Attachment        string
+	Layers            []string
 	SkipConfirmation  bool
 	TlogUpload        bool
 	TSAServerURL      string
 	IssueCertificate  bool
+	Expires           string
+	SigningServer     string
+	// EncryptionRecipients are the recipients of an encrypted image, listed
+	// in the EncryptionRecipientsAnnotation of its signature.
+	EncryptionRecipients []string
 
 	Rekor       RekorOptions
 	Fulcio      FulcioOptions
@@ -44,6 +52,9 @@ type SignOptions struct {
 	AnnotationOptions
 	Registry             RegistryOptions
 	RegistryExperimental RegistryExperimentalOptions
+	DryRun               DryRunOptions
+	LocalImage           LocalImageOptions
+	Batch                BatchOptions
 }
 
 var _ Interface = (*SignOptions)(nil)
@@ -57,11 +68,18 @@ func (o *SignOptions) AddFlags(cmd *cobra.Command) {
 	o.AnnotationOptions.AddFlags(cmd)
 	o.Registry.AddFlags(cmd)
 	o.RegistryExperimental.AddFlags(cmd)
+	o.DryRun.AddFlags(cmd)
+	o.LocalImage.AddFlags(cmd)
+	o.Batch.AddFlags(cmd)
 
 	cmd.Flags().StringVar(&o.Key, "key", "",
 		"path to the private key file, KMS URI or Kubernetes Secret")
 	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{})
 
+	cmd.Flags().StringVar(&o.SigningServer, "signing-server", "",
+		"URL of a delegated signing service, e.g. cosign server sign, that signs the payloads with a key it keeps, and returns its certificates if it has any. "+
+			"Requests are authenticated with the bearer token in $COSIGN_SIGNING_SERVER_TOKEN")
+
 	cmd.Flags().StringVar(&o.Cert, "certificate", "",
 		"path to the X.509 certificate in PEM format to include in the OCI Signature")
 	_ = cmd.Flags().SetAnnotation("certificate", cobra.BashCompFilenameExt, []string{"cert"})
@@ -92,11 +110,22 @@ func (o *SignOptions) AddFlags(cmd *cobra.Command) {
 	_ = cmd.Flags().SetAnnotation("payload", cobra.BashCompFilenameExt, []string{})
 
 	cmd.Flags().BoolVarP(&o.Recursive, "recursive", "r", false,
-		"if a multi-arch image is specified, additionally sign each discrete image")
+		"if a multi-arch image is specified, additionally sign each discrete image, or artifact of an index of OCI artifacts")
+
+	cmd.Flags().StringVar(&o.ResumeFrom, "resume-from", "",
+		"skip the images before this one, as printed by an interrupted run, to resume signing several or --recursive images")
+
+	cmd.Flags().BoolVar(&o.SignConverted, "sign-converted", false,
+		"additionally sign the images converted from the signed image to a lazy-pulling layer format (eStargz, zstd:chunked, Nydus) "+
+			"that name it as their subject, so that they verify like the original")
 
 	cmd.Flags().StringVar(&o.Attachment, "attachment", "",
 		"related image attachment to sign (sbom), default none")
 
+	cmd.Flags().StringSliceVar(&o.Layers, "layers", nil,
+		"sign the layers or config of the image selected by index, from the base layer at 0 or the top layer at -1, by digest, or as config, "+
+			"instead of the image. Requires the LayerSignatures feature gate")
+
 	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
 		"skip confirmation prompts for non-destructive operations")
 
@@ -108,4 +137,14 @@ func (o *SignOptions) AddFlags(cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&o.IssueCertificate, "issue-certificate", false,
 		"issue a code signing certificate from Fulcio, even if a key is provided")
+
+	cmd.Flags().StringVar(&o.Expires, "expires", "",
+		"expire the signature after this duration, e.g. 90d or 12h. The expiry time is signed as the "+
+			"dev.sigstore.cosign/expires annotation, and enforced by cosign verify --enforce-expiry")
+
+	cmd.Flags().StringSliceVar(&o.EncryptionRecipients, "encryption-recipient", nil,
+		"attest that this recipient, a public key or certificate file or sha256:<fingerprint> of its key, can decrypt the image, "+
+			"whose layers must be encrypted with OCIcrypt. The recipients are signed as the dev.sigstore.cosign/encryption-recipients annotation, "+
+			"and required by cosign verify --encryption-recipient (can be repeated)")
+	_ = cmd.Flags().SetAnnotation("encryption-recipient", cobra.BashCompFilenameExt, []string{})
 }
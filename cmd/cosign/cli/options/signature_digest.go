@@ -34,7 +34,9 @@ var supportedSignatureAlgorithms = map[string]crypto.Hash{
 	"sha512": crypto.SHA512,
 }
 
-func supportedSignatureAlgorithmNames() []string {
+// SupportedSignatureAlgorithmNames returns the sorted names of the digest
+// algorithms signatures can be made and verified with.
+func SupportedSignatureAlgorithmNames() []string {
 	names := make([]string, 0, len(supportedSignatureAlgorithms))
 
 	for name := range supportedSignatureAlgorithms {
@@ -56,7 +58,7 @@ var _ Interface = (*SignatureDigestOptions)(nil)
 
 // AddFlags implements Interface
 func (o *SignatureDigestOptions) AddFlags(cmd *cobra.Command) {
-	validSignatureDigestAlgorithms := strings.Join(supportedSignatureAlgorithmNames(), "|")
+	validSignatureDigestAlgorithms := strings.Join(SupportedSignatureAlgorithmNames(), "|")
 
 	cmd.Flags().StringVar(&o.AlgorithmName, "signature-digest-algorithm", "sha256",
 		fmt.Sprintf("digest algorithm to use when processing a signature (%s)", validSignatureDigestAlgorithms))
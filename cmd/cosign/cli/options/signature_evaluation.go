@@ -0,0 +1,50 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// SignatureEvaluationOptions is the wrapper for how the signatures attached
+// to an image are verified.
+type SignatureEvaluationOptions struct {
+	Workers   int
+	StopAfter int
+}
+
+var _ Interface = (*SignatureEvaluationOptions)(nil)
+
+// AddFlags implements Interface
+func (o *SignatureEvaluationOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&o.Workers, "signature-workers", 0,
+		"the number of signatures of an image verified in parallel, the number of CPUs if 0, at most the workers of the budget config file")
+
+	cmd.Flags().IntVar(&o.StopAfter, "stop-after-verified", 0,
+		"stop verifying the signatures of an image once this many have verified, instead of checking all of them; "+
+			"only those verified are printed and have their countersignatures checked. 0 verifies all the signatures")
+}
+
+// Apply sets the signature workers and early exit of co.
+func (o *SignatureEvaluationOptions) Apply(co *cosign.CheckOpts) {
+	co.SignatureWorkers = budget.Workers(o.Workers, runtime.NumCPU())
+	co.StopAfterVerified = o.StopAfter
+}
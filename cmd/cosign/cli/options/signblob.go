@@ -38,6 +38,10 @@ type SignBlobOptions struct {
 	TSAServerURL         string
 	RFC3161TimestampPath string
 	IssueCertificate     bool
+	SigningConfig        string
+	OCI                  bool
+	OutputDir            OutputDirOptions
+	Digest               BlobDigestOptions
 }
 
 var _ Interface = (*SignBlobOptions)(nil)
@@ -48,6 +52,9 @@ func (o *SignBlobOptions) AddFlags(cmd *cobra.Command) {
 	o.Fulcio.AddFlags(cmd)
 	o.Rekor.AddFlags(cmd)
 	o.OIDC.AddFlags(cmd)
+	o.Registry.AddFlags(cmd)
+	o.OutputDir.AddFlags(cmd)
+	o.Digest.AddFlags(cmd)
 
 	cmd.Flags().StringVar(&o.Key, "key", "",
 		"path to the private key file, KMS URI or Kubernetes Secret")
@@ -86,4 +93,14 @@ func (o *SignBlobOptions) AddFlags(cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&o.IssueCertificate, "issue-certificate", false,
 		"issue a code signing certificate from Fulcio, even if a key is provided")
+
+	cmd.Flags().StringVar(&o.SigningConfig, "signing-config", "",
+		"path to a Sigstore signing config that selects the transparency log to upload to in place of --rekor-url. "+
+			"Rekor v2 logs require the RekorV2 feature gate")
+	_ = cmd.Flags().SetAnnotation("signing-config", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().BoolVar(&o.OCI, "oci", false,
+		"sign OCI artifacts, such as Helm charts or WASM modules, that are already pushed to a registry: "+
+			"each argument is an artifact reference instead of a file, and the signature is attached to it as an OCI 1.1 referrer, "+
+			"to be verified with cosign verify (requires the OCI11Referrers feature gate)")
 }
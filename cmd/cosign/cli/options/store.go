@@ -0,0 +1,42 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// StoreQueryOptions is the top level wrapper for the store query command.
+type StoreQueryOptions struct {
+	Dir    string
+	Kind   string
+	Output string
+}
+
+var _ Interface = (*StoreQueryOptions)(nil)
+
+// AddFlags implements Interface
+func (o *StoreQueryOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Dir, "dir", "",
+		"directory of the local store. Defaults to $COSIGN_STORE, or ~/.cosign/store")
+	_ = cmd.Flags().SetAnnotation("dir", cobra.BashCompSubdirsInDir, []string{})
+
+	cmd.Flags().StringVar(&o.Kind, "kind", "",
+		"only list the entries of this kind: signature or attestation")
+
+	cmd.Flags().StringVar(&o.Output, "output", "text",
+		"output format: text, or json for the archived entries")
+}
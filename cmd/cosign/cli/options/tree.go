@@ -18,11 +18,66 @@ import "github.com/spf13/cobra"
 
 type TreeOptions struct {
 	Registry  RegistryOptions
+	Rekor     RekorOptions
 	CleanType string
+	Output    string
+	Color     string
+	// OutputTemplate formats the JSON graph instead of --output.
+	OutputTemplate OutputTemplateOptions
+
+	// Verify annotates each artifact as verified or unverified under the
+	// policy of the flags below.
+	Verify               bool
+	Key                  string
+	CertIdentity         string
+	CertIdentityRegexp   string
+	CertOidcIssuer       string
+	CertOidcIssuerRegexp string
+	ImagePolicy          string
+	IgnoreTlog           bool
+	IgnoreSCT            bool
 }
 
 var _ Interface = (*TreeOptions)(nil)
 
 func (c *TreeOptions) AddFlags(cmd *cobra.Command) {
 	c.Registry.AddFlags(cmd)
+	c.Rekor.AddFlags(cmd)
+	c.OutputTemplate.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&c.Output, "output", "text",
+		"output format: text, json or dot")
+
+	cmd.Flags().StringVar(&c.Color, "color", "auto",
+		"colorize the text output: auto, when writing to a terminal and $NO_COLOR is unset, always or never")
+
+	cmd.Flags().BoolVar(&c.Verify, "verify", false,
+		"verify the signatures, attestations and SBOMs of the image, and annotate each as verified or unverified")
+
+	cmd.Flags().StringVar(&c.Key, "key", "",
+		"with --verify, path to the public key file, KMS URI or Kubernetes Secret that the artifacts must be signed with")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&c.CertIdentity, "certificate-identity", "",
+		"with --verify, the identity expected in the certificates of keyless signatures")
+
+	cmd.Flags().StringVar(&c.CertIdentityRegexp, "certificate-identity-regexp", "",
+		"with --verify, a regular expression alternative to --certificate-identity")
+
+	cmd.Flags().StringVar(&c.CertOidcIssuer, "certificate-oidc-issuer", "",
+		"with --verify, the OIDC issuer expected in the certificates of keyless signatures")
+
+	cmd.Flags().StringVar(&c.CertOidcIssuerRegexp, "certificate-oidc-issuer-regexp", "",
+		"with --verify, a regular expression alternative to --certificate-oidc-issuer")
+
+	cmd.Flags().StringVar(&c.ImagePolicy, "image-policy", "",
+		"with --verify, path to a policy, in the format of cosign proxy --policy, that selects the key or certificate identity "+
+			"of the image by its repository and its labels or annotations, instead of --key and --certificate-identity")
+	_ = cmd.Flags().SetAnnotation("image-policy", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().BoolVar(&c.IgnoreTlog, "insecure-ignore-tlog", false,
+		"with --verify, ignore transparency log verification, to be used when an artifact signature has not been uploaded to the transparency log.")
+
+	cmd.Flags().BoolVar(&c.IgnoreSCT, "insecure-ignore-sct", false,
+		"with --verify, don't check for embedded Signed Certificate Timestamps in the certificates of keyless signatures")
 }
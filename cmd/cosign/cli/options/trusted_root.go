@@ -0,0 +1,53 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// TrustedRootSyncOptions is the top level wrapper for the `trusted-root sync`
+// command.
+type TrustedRootSyncOptions struct {
+	Output          string
+	Mirror          string
+	Root            string
+	SnapshotVersion int
+	DryRun          bool
+}
+
+var _ Interface = (*TrustedRootSyncOptions)(nil)
+
+// AddFlags implements Interface
+func (o *TrustedRootSyncOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Output, "output", "trusted_root.json",
+		"the trusted root to write, whose previous contents the changes are listed against")
+	_ = cmd.Flags().SetAnnotation("output", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().StringVar(&o.Mirror, "mirror", "",
+		"GCS bucket to a SigStore TUF repository, or HTTP(S) base URL, or file:/// for local filestore remote (air-gap), "+
+			"the mirror configured with cosign initialize if empty")
+
+	cmd.Flags().StringVar(&o.Root, "root", "",
+		"path to trusted initial root. defaults to the root configured with cosign initialize, or the embedded root")
+	_ = cmd.Flags().SetAnnotation("root", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().IntVar(&o.SnapshotVersion, "snapshot-version", 0,
+		"fail unless the refreshed TUF repository is at this snapshot version, so that the trusted root is the one that was reviewed")
+
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false,
+		"only list the changes to --output, without writing it")
+}
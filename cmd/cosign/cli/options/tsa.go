@@ -0,0 +1,44 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// TSAServeOptions is the top level wrapper for the tsa serve command.
+type TSAServeOptions struct {
+	Address   string
+	Ephemeral bool
+	CertChain string
+}
+
+var _ Interface = (*TSAServeOptions)(nil)
+
+// AddFlags implements Interface
+func (o *TSAServeOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Address, "address", "localhost:3000",
+		"address the timestamp authority listens on")
+
+	cmd.Flags().BoolVar(&o.Ephemeral, "ephemeral", false,
+		"sign the timestamps with a key and certificate chain generated in memory, which are lost when the command exits. "+
+			"Required, as the timestamp authority is only meant for testing")
+
+	cmd.Flags().StringVar(&o.CertChain, "cert-chain", "",
+		"write the PEM-encoded certificate chain of the timestamp authority to this file, "+
+			"for --timestamp-certificate-chain when verifying")
+	_ = cmd.Flags().SetAnnotation("cert-chain", cobra.BashCompFilenameExt, []string{"pem"})
+}
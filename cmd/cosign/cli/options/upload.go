@@ -16,15 +16,26 @@
 package options
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/spf13/cobra"
+
+	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
 )
 
 // UploadBlobOptions is the top level wrapper for the `upload blob` command.
 type UploadBlobOptions struct {
-	ContentType string
-	Files       FilesOptions
-	Registry    RegistryOptions
-	Annotations map[string]string
+	ContentType      string
+	Files            FilesOptions
+	Registry         RegistryOptions
+	Annotations      map[string]string
+	ArtifactType     string
+	LayerMediaTypes  map[string]string
+	LayerAnnotations []string
+	Publish          UploadPublishOptions
 }
 
 var _ Interface = (*UploadBlobOptions)(nil)
@@ -33,17 +44,73 @@ var _ Interface = (*UploadBlobOptions)(nil)
 func (o *UploadBlobOptions) AddFlags(cmd *cobra.Command) {
 	o.Registry.AddFlags(cmd)
 	o.Files.AddFlags(cmd)
+	o.Publish.AddFlags(cmd)
 
 	cmd.Flags().StringVar(&o.ContentType, "ct", "",
 		"content type to set")
 	cmd.Flags().StringToStringVarP(&o.Annotations, "annotation", "a", nil,
 		"annotations to set")
+	cmd.Flags().StringVar(&o.ArtifactType, "artifact-type", "",
+		"upload the files as the layers of a single OCI 1.1 artifact with this artifactType, instead of one image per platform")
+	cmd.Flags().StringToStringVar(&o.LayerMediaTypes, "layer-media-type", nil,
+		"<filepath>=<media type> of a layer of an artifact uploaded with --artifact-type. Defaults to --ct or the detected content type")
+	cmd.Flags().StringSliceVar(&o.LayerAnnotations, "layer-annotation", nil,
+		"<filepath>:<key>=<value> annotation to set on a layer of an artifact uploaded with --artifact-type")
+}
+
+// ArtifactFiles returns the files to upload as the layers of an artifact,
+// with their media types and annotations.
+func (o *UploadBlobOptions) ArtifactFiles() ([]cremote.ArtifactFile, error) {
+	if o.ArtifactType == "" {
+		if len(o.LayerMediaTypes) > 0 || len(o.LayerAnnotations) > 0 {
+			return nil, errors.New("--layer-media-type and --layer-annotation require --artifact-type")
+		}
+		return nil, nil
+	}
+
+	files := make([]cremote.ArtifactFile, len(o.Files.Files))
+	index := map[string]int{}
+	for i, path := range o.Files.Files {
+		if _, dup := index[path]; dup {
+			return nil, fmt.Errorf("%s is given more than once", path)
+		}
+		index[path] = i
+		files[i] = cremote.ArtifactFile{Path: path, MediaType: types.MediaType(o.ContentType)}
+	}
+	for path, mt := range o.LayerMediaTypes {
+		i, ok := index[path]
+		if !ok {
+			return nil, fmt.Errorf("--layer-media-type for %s, which is not one of the files", path)
+		}
+		files[i].MediaType = types.MediaType(mt)
+	}
+	for _, a := range o.LayerAnnotations {
+		// Values may contain colons, e.g. URLs, so split at the first '='.
+		left, value, ok := strings.Cut(a, "=")
+		sep := strings.LastIndex(left, ":")
+		if !ok || sep < 1 || sep == len(left)-1 {
+			return nil, fmt.Errorf("invalid --layer-annotation %q, expected <filepath>:<key>=<value>", a)
+		}
+		path, key := left[:sep], left[sep+1:]
+		i, ok := index[path]
+		if !ok {
+			return nil, fmt.Errorf("--layer-annotation for %s, which is not one of the files", path)
+		}
+		if files[i].Annotations == nil {
+			files[i].Annotations = map[string]string{}
+		}
+		files[i].Annotations[key] = value
+	}
+	return files, nil
 }
 
 // UploadWASMOptions is the top level wrapper for the `upload wasm` command.
 type UploadWASMOptions struct {
-	File     string
-	Registry RegistryOptions
+	File         string
+	Annotations  map[string]string
+	ArtifactType string
+	Registry     RegistryOptions
+	Publish      UploadPublishOptions
 }
 
 var _ Interface = (*UploadWASMOptions)(nil)
@@ -51,9 +118,58 @@ var _ Interface = (*UploadWASMOptions)(nil)
 // AddFlags implements Interface
 func (o *UploadWASMOptions) AddFlags(cmd *cobra.Command) {
 	o.Registry.AddFlags(cmd)
+	o.Publish.AddFlags(cmd)
+
+	cmd.Flags().StringToStringVarP(&o.Annotations, "annotation", "a", nil,
+		"annotations to set on the manifest of the wasm module")
+	cmd.Flags().StringVar(&o.ArtifactType, "artifact-type", "",
+		"OCI 1.1 artifactType to set on the manifest of the wasm module")
 
 	cmd.Flags().StringVarP(&o.File, "file", "f", "",
 		"path to the wasm file to upload")
 	_ = cmd.Flags().SetAnnotation("file", cobra.BashCompFilenameExt, []string{})
 	_ = cmd.MarkFlagRequired("file")
 }
+
+// UploadPublishOptions signs and attests the artifact an upload command
+// uploaded, in the same step.
+type UploadPublishOptions struct {
+	Sign             bool
+	Attest           string
+	Key              string
+	SkipConfirmation bool
+	TlogUpload       bool
+
+	Predicate   PredicateOptions
+	Rekor       RekorOptions
+	Fulcio      FulcioOptions
+	OIDC        OIDCOptions
+	SecurityKey SecurityKeyOptions
+}
+
+var _ Interface = (*UploadPublishOptions)(nil)
+
+// AddFlags implements Interface
+func (o *UploadPublishOptions) AddFlags(cmd *cobra.Command) {
+	o.Predicate.AddFlags(cmd)
+	o.Rekor.AddFlags(cmd)
+	o.Fulcio.AddFlags(cmd)
+	o.OIDC.AddFlags(cmd)
+	o.SecurityKey.AddFlags(cmd)
+
+	cmd.Flags().BoolVar(&o.Sign, "sign", false,
+		"sign the uploaded artifact. If signing fails, the upload is rolled back")
+	cmd.Flags().StringVar(&o.Attest, "attest", "",
+		"path to a predicate file to attest the uploaded artifact with, of the --type predicate type. If attesting fails, the upload and its signature are rolled back")
+	_ = cmd.Flags().SetAnnotation("attest", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"path to the private key file, KMS URI or Kubernetes Secret to sign and attest with")
+	_ = cmd.Flags().SetAnnotation("key", cobra.BashCompFilenameExt, []string{"key"})
+
+	cmd.Flags().BoolVarP(&o.SkipConfirmation, "yes", "y", false,
+		"skip confirmation prompts for non-destructive operations")
+
+	cmd.Flags().BoolVar(&o.TlogUpload, "tlog-upload", true,
+		"whether or not to upload the signature and attestation to the tlog")
+}
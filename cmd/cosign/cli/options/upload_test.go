@@ -0,0 +1,81 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+)
+
+func TestUploadBlobOptions_ArtifactFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    UploadBlobOptions
+		want    []cremote.ArtifactFile
+		wantErr bool
+	}{{
+		name: "not an artifact",
+		opts: UploadBlobOptions{Files: FilesOptions{Files: []string{"foo"}}},
+	}, {
+		name:    "layer media type without artifact type",
+		opts:    UploadBlobOptions{Files: FilesOptions{Files: []string{"foo"}}, LayerMediaTypes: map[string]string{"foo": "text/plain"}},
+		wantErr: true,
+	}, {
+		name: "media types and annotations",
+		opts: UploadBlobOptions{
+			ArtifactType:     "application/vnd.example",
+			ContentType:      "application/octet-stream",
+			Files:            FilesOptions{Files: []string{"foo", "dir/bar"}},
+			LayerMediaTypes:  map[string]string{"dir/bar": "text/plain"},
+			LayerAnnotations: []string{"foo:org.example.url=https://example.com/a=b", "dir/bar:empty="},
+		},
+		want: []cremote.ArtifactFile{{
+			Path:        "foo",
+			MediaType:   "application/octet-stream",
+			Annotations: map[string]string{"org.example.url": "https://example.com/a=b"},
+		}, {
+			Path:        "dir/bar",
+			MediaType:   "text/plain",
+			Annotations: map[string]string{"empty": ""},
+		}},
+	}, {
+		name:    "annotation for another file",
+		opts:    UploadBlobOptions{ArtifactType: "application/vnd.example", Files: FilesOptions{Files: []string{"foo"}}, LayerAnnotations: []string{"bar:key=value"}},
+		wantErr: true,
+	}, {
+		name:    "annotation without a key",
+		opts:    UploadBlobOptions{ArtifactType: "application/vnd.example", Files: FilesOptions{Files: []string{"foo"}}, LayerAnnotations: []string{"foo=value"}},
+		wantErr: true,
+	}, {
+		name:    "duplicate file",
+		opts:    UploadBlobOptions{ArtifactType: "application/vnd.example", Files: FilesOptions{Files: []string{"foo", "foo"}}},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.ArtifactFiles()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ArtifactFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ArtifactFiles() diff: %s", diff)
+			}
+		})
+	}
+}
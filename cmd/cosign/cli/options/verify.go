@@ -16,22 +16,45 @@
 package options
 
 import (
+	"github.com/sigstore/cosign/v2/pkg/types"
 	"github.com/spf13/cobra"
 )
 
 type CommonVerifyOptions struct {
-	Offline          bool // Force offline verification
-	TSACertChainPath string
-	IgnoreTlog       bool
+	Offline           bool // Force offline verification
+	TSACertChainPaths []string
+	IgnoreTlog        bool
+	Denylist          DenylistOptions
+	KeyUsagePolicy    string
+	Witnesses         WitnessOptions
+	// FailureReport is the directory that the evidence of a failed
+	// verification is written to.
+	FailureReport string
 }
 
 func (o *CommonVerifyOptions) AddFlags(cmd *cobra.Command) {
+	o.Denylist.AddFlags(cmd)
+	o.Witnesses.AddFlags(cmd)
+
 	cmd.Flags().BoolVar(&o.Offline, "offline", false,
 		"only allow offline verification")
 
-	cmd.Flags().StringVar(&o.TSACertChainPath, "timestamp-certificate-chain", "",
+	cmd.Flags().StringVar(&o.KeyUsagePolicy, "key-usage-policy", "",
+		"path to a policy of the purposes of signers, of the form {\"signers\": [{\"key\": \"sha256:<fingerprint>\", \"usages\": [\"sign\"]}]}, "+
+			"e.g. that a key may only sign images, or that a certificate identity may only attest predicates of the types in its \"predicateTypes\". "+
+			"Signatures and attestations that a signer listed in the policy isn't allowed to make fail verification")
+	_ = cmd.Flags().SetAnnotation("key-usage-policy", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().StringArrayVar(&o.TSACertChainPaths, "timestamp-certificate-chain", nil,
 		"path to PEM-encoded certificate chain file for the RFC3161 timestamp authority. Must contain the root CA certificate. "+
-			"Optionally may contain intermediate CA certificates, and may contain the leaf TSA certificate if not present in the timestamp")
+			"Optionally may contain intermediate CA certificates, and may contain the leaf TSA certificate if not present in the timestamp. "+
+			"May be repeated, e.g. with the chains of the intermediates of a TSA before and after it rotated them, "+
+			"to verify each timestamp with the chain that issued it")
+
+	cmd.Flags().StringVar(&o.FailureReport, "failure-report", "",
+		"if verification fails, write the evidence it fetched, the manifests, envelopes, certificates and transparency log responses, "+
+			"and its inputs, the flags and the files they name, with an index.json to this directory, e.g. to reproduce a failure seen in CI")
+	_ = cmd.Flags().SetAnnotation("failure-report", cobra.BashCompSubdirsInDir, []string{})
 
 	cmd.Flags().BoolVar(&o.IgnoreTlog, "insecure-ignore-tlog", false,
 		"ignore transparency log verification, to be used when an artifact signature has not been uploaded to the transparency log. Artifacts "+
@@ -48,12 +71,41 @@ type VerifyOptions struct {
 	PayloadRef   string
 	LocalImage   bool
 
+	WarningsAsErrors   bool
+	SignReport         string
+	SignReportKey      string
+	SourceRepositories []string
+	EnforceExpiry      bool
+	Encryption         EncryptionOptions
+	AllowConverted     bool
+	Recursive          bool
+	ImagePolicy        string
+	EnvironmentPolicy  string
+	LayerPolicy        string
+	Countersigners     CountersignerOptions
+	Batch              BatchOptions
+	Cache              VerifyCacheOptions
+	Evaluation         SignatureEvaluationOptions
+	Quota              ArtifactQuotaOptions
+	// OutputTemplate formats the --output json-v1 results instead.
+	OutputTemplate OutputTemplateOptions
+	// GitHubSummary appends the verification results to the GitHub Actions
+	// job summary.
+	GitHubSummary bool
+	// SignReportCertificate is the file the certificate of a --sign-report
+	// signed keylessly is written to.
+	SignReportCertificate string
+
 	CommonVerifyOptions CommonVerifyOptions
 	SecurityKey         SecurityKeyOptions
 	CertVerify          CertVerifyOptions
 	Rekor               RekorOptions
 	Registry            RegistryOptions
 	SignatureDigest     SignatureDigestOptions
+	// Fulcio and OIDC issue the certificate a --sign-report is signed with
+	// without --sign-report-key.
+	Fulcio FulcioOptions
+	OIDC   OIDCOptions
 
 	AnnotationOptions
 }
@@ -69,6 +121,16 @@ func (o *VerifyOptions) AddFlags(cmd *cobra.Command) {
 	o.SignatureDigest.AddFlags(cmd)
 	o.AnnotationOptions.AddFlags(cmd)
 	o.CommonVerifyOptions.AddFlags(cmd)
+	o.Countersigners.AddFlags(cmd)
+	o.Batch.AddFlags(cmd)
+	o.Cache.AddFlags(cmd)
+	o.Evaluation.AddFlags(cmd)
+	o.Quota.AddFlags(cmd)
+	o.Encryption.AddFlags(cmd)
+	o.OutputTemplate.AddFlags(cmd)
+	o.Fulcio.AddFlags(cmd)
+	o.OIDC.AddFlags(cmd)
+	addGitHubSummaryFlag(cmd, &o.GitHubSummary)
 
 	cmd.Flags().StringVar(&o.Key, "key", "",
 		"path to the public key file, KMS URI or Kubernetes Secret")
@@ -81,7 +143,8 @@ func (o *VerifyOptions) AddFlags(cmd *cobra.Command) {
 		"related image attachment to verify (sbom), default none")
 
 	cmd.Flags().StringVarP(&o.Output, "output", "o", "json",
-		"output format for the signing image information (json|text)")
+		"output format for the signing image information (json|text), or for the verification results "+
+			"of each image and signature in a versioned schema (json-v1|sarif)")
 
 	cmd.Flags().StringVar(&o.SignatureRef, "signature", "",
 		"signature content or path or remote URL")
@@ -90,14 +153,96 @@ func (o *VerifyOptions) AddFlags(cmd *cobra.Command) {
 		"payload path or remote URL")
 
 	cmd.Flags().BoolVar(&o.LocalImage, "local-image", false,
-		"whether the specified image is a path to an image saved locally via 'cosign save'")
+		"whether the specified image is a path to an OCI layout saved locally via 'cosign save', or signed with 'cosign sign --local-image'")
+
+	cmd.Flags().BoolVar(&o.WarningsAsErrors, "warnings-as-errors", false,
+		"fail verification if any soft policy warnings (e.g. certificate close to expiry, deprecated algorithm) are raised")
+
+	cmd.Flags().StringVar(&o.SignReport, "sign-report", "",
+		"write a DSSE-signed in-toto verification report, recording what was verified, when and against which policy, to this FILE")
+	_ = cmd.Flags().SetAnnotation("sign-report", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.SignReportKey, "sign-report-key", "",
+		"path to the private key file or KMS URI used to sign the --sign-report verification report. "+
+			"Without it, the report is signed keylessly with a certificate from --fulcio-url for the identity of the --identity-token or --oidc-* flags")
+	_ = cmd.Flags().SetAnnotation("sign-report-key", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringVar(&o.SignReportCertificate, "sign-report-certificate", "",
+		"write the certificate and chain the --sign-report verification report is signed with keylessly to this FILE, to verify the report with")
+	_ = cmd.Flags().SetAnnotation("sign-report-certificate", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().StringSliceVar(&o.SourceRepositories, "source-repository", nil,
+		"for images promoted by digest from another registry, also check this repository for signatures of the same digest (can be repeated)")
+
+	cmd.Flags().BoolVar(&o.EnforceExpiry, "enforce-expiry", false,
+		"reject signatures whose dev.sigstore.cosign/expires annotation, set with cosign sign --expires, is in the past")
+
+	cmd.Flags().BoolVar(&o.AllowConverted, "allow-converted", false,
+		"for images with eStargz or zstd:chunked layers and no signatures of their own, verify the signatures of the image they were converted from, "+
+			"recorded as their subject, after checking that both have the same configuration and files")
+
+	cmd.Flags().BoolVarP(&o.Recursive, "recursive", "r", false,
+		"if a multi-arch image is specified, additionally verify each discrete image or artifact, as signed by cosign sign --recursive, "+
+			"and fail listing every platform that isn't verified")
+
+	cmd.Flags().StringVar(&o.ImagePolicy, "image-policy", "",
+		"path to a policy, in the format of cosign proxy --policy, that selects the key or certificate identity of each image "+
+			"by its repository and its labels or annotations, instead of --key and --certificate-identity")
+	_ = cmd.Flags().SetAnnotation("image-policy", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().StringVar(&o.EnvironmentPolicy, "environment-policy", "",
+		"path to a policy of named environments, each an --image-policy entry with the annotations its signatures must have, "+
+			"to write which environments each image qualifies for instead, failing only if it qualifies for none")
+	_ = cmd.Flags().SetAnnotation("environment-policy", cobra.BashCompFilenameExt, []string{"json"})
+
+	cmd.Flags().StringVar(&o.LayerPolicy, "layer-policy", "",
+		"path to a policy of rules that also require the layers or config of each image, selected as with cosign sign --layers, "+
+			"to be signed by a key or certificate identity. Requires the LayerSignatures feature gate")
+	_ = cmd.Flags().SetAnnotation("layer-policy", cobra.BashCompFilenameExt, []string{"json"})
 }
 
+// SignReportKeyOpts returns the KeyOpts to sign the --sign-report
+// verification report with: the --sign-report-key, or an ephemeral key
+// certified by Fulcio without it.
+func (o *VerifyOptions) SignReportKeyOpts() (KeyOpts, error) {
+	oidcClientSecret, err := o.OIDC.ClientSecret()
+	if err != nil {
+		return KeyOpts{}, err
+	}
+	return KeyOpts{
+		KeyRef:                   o.SignReportKey,
+		FulcioURL:                o.Fulcio.URL,
+		IDToken:                  o.Fulcio.IdentityToken,
+		InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+		ACME:                     o.Fulcio.ACME,
+		OIDCIssuer:               o.OIDC.Issuer,
+		OIDCClientID:             o.OIDC.ClientID,
+		OIDCClientSecret:         oidcClientSecret,
+		OIDCRedirectURL:          o.OIDC.RedirectURL,
+		OIDCDisableProviders:     o.OIDC.DisableAmbientProviders,
+		OIDCProvider:             o.OIDC.Provider,
+		OIDCTokenFile:            o.OIDC.TokenFile,
+		OIDCAudience:             o.OIDC.Audience,
+		OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
+	}, nil
+}
+
+// The values of --policy-evaluation.
+const (
+	PolicyEvaluationEach  = "each"
+	PolicyEvaluationJoint = "joint"
+)
+
 // VerifyAttestationOptions is the top level wrapper for the `verify attestation` command.
 type VerifyAttestationOptions struct {
 	Key         string
 	CheckClaims bool
 	Output      string
+	// OutputTemplate formats the --output json-v1 results instead.
+	OutputTemplate OutputTemplateOptions
+	// GitHubSummary appends the verification results to the GitHub Actions
+	// job summary.
+	GitHubSummary bool
 
 	CommonVerifyOptions CommonVerifyOptions
 	SecurityKey         SecurityKeyOptions
@@ -106,7 +251,14 @@ type VerifyAttestationOptions struct {
 	Registry            RegistryOptions
 	Predicate           PredicateRemoteOptions
 	Policies            []string
+	PolicyEvaluation    string
 	LocalImage          bool
+	WarningsAsErrors    bool
+	SourceRepositories  []string
+	AllowConverted      bool
+	BaseImagePolicy     string
+	PayloadTypes        []string
+	LicensePolicy       LicensePolicyOptions
 }
 
 var _ Interface = (*VerifyAttestationOptions)(nil)
@@ -119,6 +271,9 @@ func (o *VerifyAttestationOptions) AddFlags(cmd *cobra.Command) {
 	o.Registry.AddFlags(cmd)
 	o.Predicate.AddFlags(cmd)
 	o.CommonVerifyOptions.AddFlags(cmd)
+	o.OutputTemplate.AddFlags(cmd)
+	o.LicensePolicy.AddFlags(cmd)
+	addGitHubSummaryFlag(cmd, &o.GitHubSummary)
 
 	cmd.Flags().StringVar(&o.Key, "key", "",
 		"path to the public key file, KMS URI or Kubernetes Secret")
@@ -126,21 +281,103 @@ func (o *VerifyAttestationOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.CheckClaims, "check-claims", true,
 		"whether to check the claims found")
 
+	addPayloadTypeFlag(cmd, &o.PayloadTypes)
+
 	cmd.Flags().StringSliceVar(&o.Policies, "policy", nil,
-		"specify CUE or Rego files will be using for validation")
+		"specify CUE or Rego files will be using for validation, either as paths or as tuf://<target> in the TUF repository set up with 'cosign initialize'")
+
+	cmd.Flags().StringVar(&o.PolicyEvaluation, "policy-evaluation", PolicyEvaluationEach,
+		"how the --policy files are evaluated: against each attestation (each), or once against a document of all the verified attestations "+
+			"of the --type predicate types (joint), so that a policy can assert on their counts, relationships and freshness. "+
+			"The document is of the form {\"now\", \"count\", \"attestations\": [{\"predicateType\", \"signedAt\", \"statement\"}], "+
+			"\"predicateTypes\": {\"<--type>\": [<attestations>]}}")
 
 	cmd.Flags().StringVarP(&o.Output, "output", "o", "json",
-		"output format for the signing image information (json|text)")
+		"output format for the signing image information (json|text), or for the verification results "+
+			"of each image and signature in a versioned schema (json-v1|sarif)")
 
 	cmd.Flags().BoolVar(&o.LocalImage, "local-image", false,
-		"whether the specified image is a path to an image saved locally via 'cosign save'")
+		"whether the specified image is a path to an OCI layout saved locally via 'cosign save', or signed with 'cosign sign --local-image'")
+
+	cmd.Flags().BoolVar(&o.WarningsAsErrors, "warnings-as-errors", false,
+		"fail verification if any soft policy warnings (e.g. certificate close to expiry, deprecated algorithm) are raised")
+
+	cmd.Flags().StringSliceVar(&o.SourceRepositories, "source-repository", nil,
+		"for images promoted by digest from another registry, also check this repository for attestations of the same digest (can be repeated)")
+
+	cmd.Flags().BoolVar(&o.AllowConverted, "allow-converted", false,
+		"for images with eStargz or zstd:chunked layers and no attestations of their own, verify the attestations of the image they were converted from, "+
+			"recorded as their subject, after checking that both have the same configuration and files")
+
+	cmd.Flags().StringVar(&o.BaseImagePolicy, "base-image-policy", "",
+		"path to a policy for verifying the chain of base images named by verified baseimage attestations. "+
+			"Each base image is verified with the first policy entry whose glob matches its repository")
+	_ = cmd.Flags().SetAnnotation("base-image-policy", cobra.BashCompFilenameExt, []string{"json"})
 }
 
 // VerifyBlobOptions is the top level wrapper for the `verify blob` command.
 type VerifyBlobOptions struct {
+	Key          string
+	Signature    string
+	BundlePath   string
+	Embedded     bool
+	AutoDiscover bool
+	Output       string
+	// OutputTemplate formats the --output json-v1 results instead.
+	OutputTemplate OutputTemplateOptions
+	// GitHubSummary appends the verification results to the GitHub Actions
+	// job summary.
+	GitHubSummary bool
+
+	SecurityKey         SecurityKeyOptions
+	CertVerify          CertVerifyOptions
+	Rekor               RekorOptions
+	CommonVerifyOptions CommonVerifyOptions
+	Digest              BlobDigestOptions
+
+	RFC3161TimestampPath string
+}
+
+var _ Interface = (*VerifyBlobOptions)(nil)
+
+// AddFlags implements Interface
+func (o *VerifyBlobOptions) AddFlags(cmd *cobra.Command) {
+	o.SecurityKey.AddFlags(cmd)
+	o.Rekor.AddFlags(cmd)
+	o.CertVerify.AddFlags(cmd)
+	o.CommonVerifyOptions.AddFlags(cmd)
+	o.OutputTemplate.AddFlags(cmd)
+	addGitHubSummaryFlag(cmd, &o.GitHubSummary)
+	o.Digest.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.Key, "key", "",
+		"path to the public key file, KMS URI or Kubernetes Secret")
+
+	cmd.Flags().StringVar(&o.Signature, "signature", "",
+		"signature content or path or remote URL, or - to read it from standard input")
+
+	cmd.Flags().StringVar(&o.BundlePath, "bundle", "",
+		"path to bundle FILE, or - to read it from standard input")
+
+	cmd.Flags().BoolVar(&o.Embedded, "embedded", false,
+		"verify the blob, an executable or archive, with the bundle embedded in it by cosign embed")
+
+	cmd.Flags().BoolVar(&o.AutoDiscover, "auto-discover", false,
+		"verify the blob with the <blob>.sig, <blob>.pem or <blob>.bundle next to it, or else a signed checksum file in its directory that lists it")
+
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "",
+		"output format for the verification results of the blob and its signature in a versioned schema (json-v1|sarif), default none")
+
+	cmd.Flags().StringVar(&o.RFC3161TimestampPath, "rfc3161-timestamp", "",
+		"path to RFC3161 timestamp FILE")
+}
+
+// VerifyChecksumsOptions is the top level wrapper for the `verify-checksums` command.
+type VerifyChecksumsOptions struct {
 	Key        string
 	Signature  string
 	BundlePath string
+	Artifacts  []string
 
 	SecurityKey         SecurityKeyOptions
 	CertVerify          CertVerifyOptions
@@ -150,10 +387,10 @@ type VerifyBlobOptions struct {
 	RFC3161TimestampPath string
 }
 
-var _ Interface = (*VerifyBlobOptions)(nil)
+var _ Interface = (*VerifyChecksumsOptions)(nil)
 
 // AddFlags implements Interface
-func (o *VerifyBlobOptions) AddFlags(cmd *cobra.Command) {
+func (o *VerifyChecksumsOptions) AddFlags(cmd *cobra.Command) {
 	o.SecurityKey.AddFlags(cmd)
 	o.Rekor.AddFlags(cmd)
 	o.CertVerify.AddFlags(cmd)
@@ -163,10 +400,13 @@ func (o *VerifyBlobOptions) AddFlags(cmd *cobra.Command) {
 		"path to the public key file, KMS URI or Kubernetes Secret")
 
 	cmd.Flags().StringVar(&o.Signature, "signature", "",
-		"signature content or path or remote URL")
+		"signature of the checksum file, content or path or remote URL, or - to read it from standard input")
 
 	cmd.Flags().StringVar(&o.BundlePath, "bundle", "",
-		"path to bundle FILE")
+		"path to the bundle FILE of the checksum file, or - to read it from standard input")
+
+	cmd.Flags().StringArrayVar(&o.Artifacts, "artifact", nil,
+		"path to an artifact to verify against its checksum in the checksum file, by its path or else its file name. May be repeated")
 
 	cmd.Flags().StringVar(&o.RFC3161TimestampPath, "rfc3161-timestamp", "",
 		"path to RFC3161 timestamp FILE")
@@ -188,6 +428,23 @@ func (o *VerifyDockerfileOptions) AddFlags(cmd *cobra.Command) {
 		"only verify the base image (the last FROM image in the Dockerfile)")
 }
 
+// VerifyManifestOptions is the top level wrapper for the `manifest verify` command.
+type VerifyManifestOptions struct {
+	VerifyOptions
+	AdmissionReview bool
+}
+
+var _ Interface = (*VerifyManifestOptions)(nil)
+
+// AddFlags implements Interface
+func (o *VerifyManifestOptions) AddFlags(cmd *cobra.Command) {
+	o.VerifyOptions.AddFlags(cmd)
+
+	cmd.Flags().BoolVar(&o.AdmissionReview, "admission-review", false,
+		"read a Kubernetes AdmissionReview from stdin instead of a manifest, and write it to stdout with the response, "+
+			"which only allows the object if all its images are verified")
+}
+
 // VerifyBlobAttestationOptions is the top level wrapper for the `verify-blob-attestation` command.
 type VerifyBlobAttestationOptions struct {
 	Key           string
@@ -201,8 +458,11 @@ type VerifyBlobAttestationOptions struct {
 	CertVerify          CertVerifyOptions
 	Rekor               RekorOptions
 	CommonVerifyOptions CommonVerifyOptions
+	Digest              BlobDigestOptions
 
 	RFC3161TimestampPath string
+	LowMemory            bool
+	PayloadTypes         []string
 }
 
 var _ Interface = (*VerifyBlobOptions)(nil)
@@ -213,20 +473,44 @@ func (o *VerifyBlobAttestationOptions) AddFlags(cmd *cobra.Command) {
 	o.SecurityKey.AddFlags(cmd)
 	o.Rekor.AddFlags(cmd)
 	o.CertVerify.AddFlags(cmd)
+	o.Digest.AddFlags(cmd)
 	o.CommonVerifyOptions.AddFlags(cmd)
 
 	cmd.Flags().StringVar(&o.Key, "key", "",
 		"path to the public key file, KMS URI or Kubernetes Secret")
 
 	cmd.Flags().StringVar(&o.SignaturePath, "signature", "",
-		"path to base64-encoded signature over attestation in DSSE format")
+		"path to base64-encoded signature over attestation in DSSE format, or - to read it from standard input")
 
 	cmd.Flags().StringVar(&o.BundlePath, "bundle", "",
-		"path to bundle FILE")
+		"path to bundle FILE, or - to read it from standard input")
 
 	cmd.Flags().BoolVar(&o.CheckClaims, "check-claims", true,
 		"if true, verifies the provided blob's sha256 digest exists as an in-toto subject within the attestation. If false, only the DSSE envelope is verified.")
 
+	addPayloadTypeFlag(cmd, &o.PayloadTypes)
+
 	cmd.Flags().StringVar(&o.RFC3161TimestampPath, "rfc3161-timestamp", "",
 		"path to RFC3161 timestamp FILE")
+
+	cmd.Flags().BoolVar(&o.LowMemory, "low-memory", false,
+		"verify with a bounded amount of memory, hashing the DSSE payload as it is read instead of buffering the attestation. "+
+			"Needs --signature FILE, --key or --sk with an ECDSA or RSA key, and --insecure-ignore-tlog; "+
+			"--bundle, --certificate, --rfc3161-timestamp and predicate schemas aren't supported")
+}
+
+// addPayloadTypeFlag adds the --payload-type flag of the attestation
+// verification commands.
+func addPayloadTypeFlag(cmd *cobra.Command, payloadTypes *[]string) {
+	cmd.Flags().StringSliceVar(payloadTypes, "payload-type", nil,
+		"payloadType that the DSSE envelope of an attestation must have, matched exactly (can be repeated). "+
+			"Defaults to the in-toto payload type, "+types.IntotoPayloadType)
+}
+
+// addGitHubSummaryFlag adds the --github-summary flag of the verification
+// commands with results.
+func addGitHubSummaryFlag(cmd *cobra.Command, summary *bool) {
+	cmd.Flags().BoolVar(summary, "github-summary", false,
+		"append a Markdown report of the verification, a table of the verified subjects, the identities that signed them and their outcomes, "+
+			"to the job summary of the GitHub Actions step ($GITHUB_STEP_SUMMARY), also if it fails")
 }
@@ -0,0 +1,44 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// VerifyCacheOptions is the wrapper for the cache of the verifications of
+// image digests.
+type VerifyCacheOptions struct {
+	Dir string
+	TTL time.Duration
+}
+
+var _ Interface = (*VerifyCacheOptions)(nil)
+
+// AddFlags implements Interface
+func (o *VerifyCacheOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Dir, "cache-dir", "",
+		"cache the signatures that verified each image digest in DIR, so that verifying the digest again with the same policy "+
+			"skips the registry and Rekor, until --cache-ttl")
+	_ = cmd.Flags().SetAnnotation("cache-dir", cobra.BashCompSubdirsInDir, []string{})
+
+	cmd.Flags().DurationVar(&o.TTL, "cache-ttl", cosign.DefaultVerificationCacheTTL,
+		"how long the verifications in --cache-dir are used")
+}
@@ -0,0 +1,37 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "github.com/spf13/cobra"
+
+// WitnessOptions is the wrapper for the witnesses that must cosign the
+// transparency log checkpoints during verification.
+type WitnessOptions struct {
+	Keys string
+	Min  int
+}
+
+var _ Interface = (*WitnessOptions)(nil)
+
+// AddFlags implements Interface
+func (o *WitnessOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Keys, "witness-keys", "",
+		"path to a file of witness note verifier keys, one per line, of which --min-witnesses must cosign the transparency log checkpoint")
+	_ = cmd.Flags().SetAnnotation("witness-keys", cobra.BashCompFilenameExt, []string{})
+
+	cmd.Flags().IntVar(&o.Min, "min-witnesses", 1,
+		"minimum number of the witnesses in --witness-keys that must cosign the transparency log checkpoint")
+}
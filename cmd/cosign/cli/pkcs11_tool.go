@@ -34,6 +34,7 @@ func PKCS11Tool() *cobra.Command {
 
 	cmd.AddCommand(
 		pkcs11ToolListTokens(),
+		pkcs11ToolListKeys(),
 		PKCS11ToolListKeysUrisOptions(),
 	)
 
@@ -62,6 +63,29 @@ func pkcs11ToolListTokens() *cobra.Command {
 	return cmd
 }
 
+func pkcs11ToolListKeys() *cobra.Command {
+	o := &options.PKCS11ToolListKeysOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list-keys",
+		Short: "list-keys lists the keys of a PKCS11 token, with their type, certificate and URI",
+		Long: `List the key pairs of a PKCS11 token with their label, id (CKA_ID), type,
+certificate and the URI that selects them, which doesn't include the PIN.`,
+		Example: `  cosign pkcs11-tool list-keys --module-path /usr/lib/softhsm/libsofthsm2.so --slot-id 0
+
+  # print the keys as JSON
+  cosign pkcs11-tool list-keys --module-path /usr/lib/softhsm/libsofthsm2.so --slot-id 0 --json`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pkcs11cli.ListKeysCmd(cmd.Context(), o.ModulePath, o.SlotID, o.Pin, o.JSON, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+
+	return cmd
+}
+
 func PKCS11ToolListKeysUrisOptions() *cobra.Command {
 	o := &options.PKCS11ToolListKeysUrisOptions{}
 
@@ -20,16 +20,20 @@ package pkcs11cli
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"syscall"
 
 	"github.com/miekg/pkcs11"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
 	"golang.org/x/term"
 )
 
@@ -248,3 +252,110 @@ func ListKeysUrisCmd(ctx context.Context, modulePath string, slotID uint, pin st
 
 	return nil
 }
+
+// ListedKey is a key of a PKCS11 token printed by `pkcs11-tool list-keys`.
+type ListedKey struct {
+	Slot  uint   `json:"slot"`
+	Token string `json:"token"`
+	Label string `json:"label,omitempty"`
+	// ID is the hex encoded CKA_ID of the key.
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type"`
+	// Certificate is the subject of the certificate of the key, if the token
+	// has one.
+	Certificate string `json:"certificate,omitempty"`
+	// URI selects the key, without its PIN.
+	URI string `json:"uri"`
+}
+
+// GetKeys returns the key pairs of the token in slotID, logging in once.
+func GetKeys(ctx context.Context, modulePath string, slotID uint, pin string) ([]ListedKey, error) {
+	if modulePath == "" || !filepath.IsAbs(modulePath) {
+		return nil, flag.ErrHelp
+	}
+
+	// The token label selects the key in the URIs along with the slot.
+	tokens, err := GetTokens(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	var tokenLabel string
+	found := false
+	for _, token := range tokens {
+		if token.Slot == slotID {
+			tokenLabel, found = token.TokenInfo.Label, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no token in slot %d of PKCS11 module '%s'", slotID, modulePath)
+	}
+
+	slotIDInt := int(slotID)
+	config := pkcs11key.NewPkcs11UriConfigFromInput(modulePath, &slotIDInt, tokenLabel, nil, nil, pin)
+	keyPairs, err := pkcs11key.ListKeys(config, true)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]ListedKey, 0, len(keyPairs))
+	for _, kp := range keyPairs {
+		uri, err := pkcs11key.NewPkcs11UriConfigFromInput(modulePath, &slotIDInt, tokenLabel, kp.Label, kp.ID, "").Construct()
+		if err != nil {
+			return nil, fmt.Errorf("construct pkcs11 uri: %w", err)
+		}
+		key := ListedKey{
+			Slot:  slotID,
+			Token: tokenLabel,
+			Label: string(kp.Label),
+			ID:    hex.EncodeToString(kp.ID),
+			Type:  cosign.KeyDescription(kp.PublicKey),
+			URI:   uri,
+		}
+		if kp.Certificate != nil {
+			key.Certificate = sigs.CertSubject(kp.Certificate)
+			if key.Certificate == "" {
+				key.Certificate = kp.Certificate.Subject.String()
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func ListKeysCmd(ctx context.Context, modulePath string, slotID uint, pin string, asJSON bool, out io.Writer) error {
+	if modulePath == "" {
+		return fmt.Errorf("please specify --module-path or set COSIGN_PKCS11_MODULE_PATH")
+	}
+	keys, err := GetKeys(ctx, modulePath, slotID, pin)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		b, err := json.MarshalIndent(keys, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+
+	fmt.Fprintf(out, "\nListing keys in slot '%d' of PKCS11 module '%s'\n", slotID, modulePath)
+	for i, key := range keys {
+		fmt.Fprintf(out, "Key %d\n", i)
+		if key.Label != "" {
+			fmt.Fprintf(out, "\tLabel: %s\n", key.Label)
+		}
+		if key.ID != "" {
+			fmt.Fprintf(out, "\tID: %s\n", key.ID)
+		}
+		fmt.Fprintf(out, "\tType: %s\n", key.Type)
+		if key.Certificate != "" {
+			fmt.Fprintf(out, "\tCertificate: %s\n", key.Certificate)
+		}
+		fmt.Fprintf(out, "\tURI: %s\n", key.URI)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+)
+
+func Policy() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Provides utilities for the CUE and Rego policies of attestations",
+	}
+
+	cmd.AddCommand(
+		policyTest(),
+	)
+
+	return cmd
+}
+
+func policyTest() *cobra.Command {
+	o := &options.PolicyTestOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "test SPEC...",
+		Short: "Test CUE and Rego policies against fixture attestations",
+		Long: `Evaluate CUE and Rego policies against fixture attestations, as
+cosign verify-attestation --policy evaluates them against the verified
+attestations of an image, and check that each test of the specs allows or
+denies them as expected, e.g. in the CI of a policy repository before the
+policies gate production.
+
+A spec is a YAML or JSON file of tests, with paths relative to it:
+
+  tests:
+  - name: release provenance is allowed
+    policies: [provenance.rego]
+    attestations: [testdata/release.intoto.json]
+    types: [slsaprovenance]
+    expect: allow
+  - name: provenance and a vulnerability scan are required
+    policies: [release.cue]
+    attestations: [testdata/release.intoto.json]
+    types: [slsaprovenance, vuln]
+    evaluation: joint
+    expect: deny
+
+The attestations are DSSE envelopes of in-toto statements, as attached to
+images, or in-toto statements. types are the --type predicate types, custom
+if none, and evaluation is the --policy-evaluation, each or joint. Signatures
+aren't verified.
+
+The command fails if a test fails.`,
+		Example: `  cosign policy test policies/tests.yaml
+
+  # run only the tests of the provenance policy
+  cosign policy test --run provenance policies/tests.yaml`,
+		Args:             cobra.MinimumNArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return verify.PolicyTestCmd(cmd.Context(), *o, args, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
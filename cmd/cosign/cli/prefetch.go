@@ -0,0 +1,66 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/prefetch"
+)
+
+func Prefetch() *cobra.Command {
+	o := &options.PrefetchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "prefetch <images file>",
+		Short: "Warm the caches that cosign verify reads, ahead of a window without network access",
+		Long: `Warm the caches that cosign verify reads, ahead of a window without network
+access such as a maintenance window.
+
+The TUF metadata is refreshed, and with it the snapshot of the Rekor and CT log
+public keys and Fulcio certificates kept in the TUF cache, and each image of the
+file, one per line, is verified with the flags of cosign verify and its result
+cached in --cache-dir. Images are verified again even if their verification is
+cached, so verifying them during the window with the same flags and a
+--cache-ttl spanning it reads neither the registry nor Rekor.
+
+Registry tokens expire within minutes, so they aren't kept, and cosign
+verify-attestation doesn't read the cache: to verify images and their
+attestations without any network access, save them with cosign save and verify
+them with --local-image.`,
+		Example: `  # before the window, verify the images of the release and cache the results
+  cosign prefetch --key cosign.pub --cache-dir /var/cache/cosign images.txt
+
+  # during the window, verify them from the cache
+  cosign verify --key cosign.pub --cache-dir /var/cache/cosign --cache-ttl 12h --batch-file images.txt`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := newVerifyCommand(&o.VerifyOptions, options.VerifyBatchOptions{Workers: o.Workers}, options.OfflineBundleOptions{})
+			if err != nil {
+				return err
+			}
+			if err := setImagePolicy(v, &o.VerifyOptions); err != nil {
+				return err
+			}
+			return prefetch.PrefetchCmd(cmd.Context(), v, args[0])
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,69 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prefetch warms the caches that verification reads from ahead of a
+// window without network access, such as a maintenance window.
+package prefetch
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tufcache"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// PrefetchCmd refreshes the TUF metadata, and with it the snapshot of the
+// Rekor and CT log public keys and Fulcio certificates that v verifies with,
+// and verifies the images listed in file, one per line, as v does, caching
+// the results in the --cache-dir of v. Verifying them again with the same
+// policy, within the --cache-ttl of that verification, then reads neither the
+// registry nor Rekor.
+func PrefetchCmd(ctx context.Context, v *verify.VerifyCommand, file string) error {
+	switch {
+	case v.Cache.Dir == "":
+		return errors.New("cosign prefetch needs --cache-dir, the verification cache to warm")
+	case v.LocalImage:
+		return errors.New("--local-image can't be used with cosign prefetch, as local images are verified without network access")
+	case v.SignReport != "":
+		return errors.New("--sign-report can't be used with cosign prefetch, which writes no verification results")
+	case v.Output == verify.OutputJSONv1 || v.Output == verify.OutputSARIF || v.OutputTemplate != "" || v.GitHubSummary:
+		return errors.New("--output json-v1, sarif, --output-template and --github-summary can't be used with cosign prefetch, " +
+			"which writes no verification results")
+	}
+	if err := tufcache.SetRefresh(tufcache.RefreshAlways); err != nil {
+		return err
+	}
+
+	c := *v
+	c.BatchVerify.File = file
+	// Cached verifications are never used, so that each image is verified
+	// again and its entry's --cache-ttl runs from now.
+	c.Cache.TTL = 0
+	var n int
+	c.OnVerified = func(context.Context, name.Reference, []oci.Signature) error {
+		n++
+		return nil
+	}
+	if err := c.Exec(ctx, nil); err != nil {
+		return err
+	}
+	ui.Infof(ctx, "Prefetched the verifications of %d images into %s", n, v.Cache.Dir)
+	return nil
+}
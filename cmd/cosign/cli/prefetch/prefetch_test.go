@@ -0,0 +1,153 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prefetch
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestPrefetchCmd(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	host := strings.TrimPrefix(s.URL, "http://")
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := name.NewTag(host + "/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(tag, img); err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := tag.Context().Digest(h.String())
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	key := filepath.Join(dir, "key.pub")
+	if err := os.WriteFile(key, pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := (&payload.Cosign{Image: digest}).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := sv.SignMessage(bytes.NewReader(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(p, base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := mutate.AppendSignatures(empty.Signatures(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sigTag, sigs); err != nil {
+		t.Fatal(err)
+	}
+	images := filepath.Join(dir, "images.txt")
+	if err := os.WriteFile(images, []byte("# release\n"+digest.String()+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	v := verify.VerifyCommand{
+		KeyRef:      key,
+		CheckClaims: true,
+		IgnoreTlog:  true,
+		IgnoreSCT:   true,
+		Cache:       options.VerifyCacheOptions{Dir: filepath.Join(dir, "cache"), TTL: time.Hour},
+	}
+	if err := PrefetchCmd(context.Background(), &v, images); err != nil {
+		t.Fatalf("PrefetchCmd() = %v", err)
+	}
+
+	// With the registry gone, the image verifies from the cache.
+	s.Close()
+	var verified bool
+	v.OnVerified = func(_ context.Context, ref name.Reference, _ []oci.Signature) error {
+		verified = ref.String() == digest.String()
+		return nil
+	}
+	if err := v.Exec(context.Background(), []string{digest.String()}); err != nil {
+		t.Fatalf("Exec() after prefetching = %v", err)
+	}
+	if !verified {
+		t.Errorf("%s wasn't verified", digest)
+	}
+}
+
+func TestPrefetchCmdErrors(t *testing.T) {
+	cache := options.VerifyCacheOptions{Dir: t.TempDir(), TTL: time.Hour}
+	for name, v := range map[string]verify.VerifyCommand{
+		"no cache":    {},
+		"local image": {Cache: cache, LocalImage: true},
+		"sign report": {Cache: cache, SignReport: "report.json"},
+		"json-v1":     {Cache: cache, Output: verify.OutputJSONv1},
+	} {
+		if err := PrefetchCmd(context.Background(), &v, "images.txt"); err == nil {
+			t.Errorf("PrefetchCmd() with %s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/walk"
+)
+
+func Promote() *cobra.Command {
+	o := &options.PromoteOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "Copy an image by digest to another repository with the signatures and attestations of a signer.",
+		Long: `Copy an image by digest to another repository with the signatures and
+attestations made by a signer, and check the promoted image once copied.
+
+Only the signatures and attestations that the --signed-by-* flags select are
+copied, and of the attestations only those of the --predicate-type types if
+any are given; those of other signers, and the SBOMs attached to the image,
+are left behind. Once everything is copied, the signatures and attestations
+of the destination are read back and must be exactly those selected. A
+multi-arch image is promoted with each of its platform images.
+
+The destination is a repository, into which the image is copied by digest,
+or a tag of a repository, which is pointed at the image once it and its
+signatures and attestations are copied.`,
+		Example: `  # promote an image signed with a key to the production repository
+  cosign promote --signed-by-key release.pub example.com/staging/app@sha256:<DIGEST> example.com/prod/app
+
+  # promote an image with the SLSA provenance and SBOM attestations of a CI identity, and tag it
+  cosign promote --signed-by-identity https://github.com/org/repo/.github/workflows/release.yml@refs/heads/main \
+    --signed-by-oidc-issuer https://token.actions.githubusercontent.com \
+    --predicate-type slsaprovenance --predicate-type spdxjson \
+    example.com/staging/app@sha256:<DIGEST> example.com/prod/app:v1.2.3`,
+
+		Args:             cobra.ExactArgs(2),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := NewSignerMatcher(cmd.Context(), o.SignedByKey, o.SignedByIdentity, o.SignedByOIDCIssuer)
+			if err != nil {
+				return err
+			}
+			po := PromoteOpts{SignedBy: m, SigOnly: o.SigOnly, Force: o.Force}
+			for _, t := range o.PredicateTypes {
+				uri, err := options.ParsePredicateType(t)
+				if err != nil {
+					return err
+				}
+				po.PredicateTypes = append(po.PredicateTypes, uri)
+			}
+			return PromoteCmd(cmd.Context(), o.Registry, args[0], args[1], po, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// PromoteOpts selects what PromoteCmd copies with an image.
+type PromoteOpts struct {
+	// SignedBy selects the signatures and attestations to copy.
+	SignedBy *SignerMatcher
+	// PredicateTypes, if set, are the predicate type URIs of the attestations
+	// to copy.
+	PredicateTypes []string
+	// SigOnly copies no attestations.
+	SigOnly bool
+	// Force overwrites the signatures and attestations of the destination.
+	Force bool
+}
+
+// PromoteCmd copies the image srcImg by digest to dstImg, a repository or a
+// tag, with the signatures and attestations that o selects, and checks that
+// the destination has exactly those once copied. It writes a summary of the
+// promotion to out.
+func PromoteCmd(ctx context.Context, regOpts options.RegistryOptions, srcImg, dstImg string, o PromoteOpts, out io.Writer) error {
+	if o.SignedBy == nil {
+		return errors.New("a signer is required to select the signatures and attestations to promote")
+	}
+	no := regOpts.NameOptions()
+	srcRef, err := oci.ParseReference(srcImg, no...)
+	if err != nil {
+		return err
+	}
+	ociremoteOpts, err := regOpts.ClientOpts(ctx)
+	if err != nil {
+		return err
+	}
+	srcDigest, err := ociremote.ResolveDigest(srcRef, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	if _, ok := srcRef.(name.Digest); !ok {
+		ui.Warnf(ctx, "promoting %s, which %s points to now", srcDigest, srcImg)
+	}
+	dstRepo, dstTag, err := promoteDestination(srcDigest, dstImg, no...)
+	if err != nil {
+		return err
+	}
+
+	remoteOpts := regOpts.GetRegistryClientOpts(ctx)
+	pusher, err := remote.NewPusher(remoteOpts...)
+	if err != nil {
+		return err
+	}
+	p := &promoter{
+		opts:          o,
+		srcRepo:       srcDigest.Context(),
+		dstRepo:       dstRepo,
+		ociremoteOpts: ociremoteOpts,
+		remoteOpts:    remoteOpts,
+		pusher:        pusher,
+	}
+	root, err := ociremote.SignedEntity(srcDigest, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	if err := walk.SignedEntity(ctx, root, p.promote); err != nil {
+		return err
+	}
+
+	for _, e := range p.promoted {
+		if err := p.check(ctx, e); err != nil {
+			return fmt.Errorf("checking the promoted image %s: %w", e.digest, err)
+		}
+	}
+	dst := name.Reference(dstRepo.Digest(srcDigest.DigestStr()))
+	if dstTag != nil {
+		desc, err := remote.Get(dst, p.remoteOpts...)
+		if err != nil {
+			return err
+		}
+		if err := remote.Tag(*dstTag, desc, p.remoteOpts...); err != nil {
+			return fmt.Errorf("tagging %s: %w", dstTag, err)
+		}
+		dst = *dstTag
+	}
+
+	var sigs, atts int
+	for _, e := range p.promoted {
+		sigs += e.signatures
+		atts += e.attestations
+	}
+	fmt.Fprintf(out, "Promoted %s to %s with %d signatures and %d attestations made by %s\n", srcDigest, dst, sigs, atts, o.SignedBy)
+	return nil
+}
+
+// promoteDestination returns the repository that the image src is promoted
+// to, and the tag to point at it if dstImg names one.
+func promoteDestination(src name.Digest, dstImg string, opts ...name.Option) (name.Repository, *name.Tag, error) {
+	if repo, err := name.NewRepository(dstImg, opts...); err == nil {
+		return repo, nil, nil
+	}
+	ref, err := oci.ParseReference(dstImg, opts...)
+	if err != nil {
+		return name.Repository{}, nil, err
+	}
+	switch r := ref.(type) {
+	case name.Tag:
+		return r.Context(), &r, nil
+	case name.Digest:
+		if r.DigestStr() != src.DigestStr() {
+			return name.Repository{}, nil, fmt.Errorf("destination digest %s does not match source digest %s", r.DigestStr(), src.DigestStr())
+		}
+		return r.Context(), nil, nil
+	}
+	return name.Repository{}, nil, fmt.Errorf("invalid destination %s", dstImg)
+}
+
+// promoter copies the images of a PromoteCmd.
+type promoter struct {
+	opts          PromoteOpts
+	srcRepo       name.Repository
+	dstRepo       name.Repository
+	ociremoteOpts []ociremote.Option
+	remoteOpts    []remote.Option
+	pusher        *remote.Pusher
+	promoted      []promotedImage
+}
+
+// promotedImage is an image copied by a promoter and the signatures and
+// attestations copied with it.
+type promotedImage struct {
+	digest       name.Digest
+	signatures   int
+	attestations int
+	tags         map[name.Tag]promotedTag
+}
+
+// promotedTag holds the digests of the signatures or attestations copied to
+// a tag.
+type promotedTag struct {
+	digests     []v1.Hash
+	attestation bool
+}
+
+// promote copies se and its selected signatures and attestations to the
+// destination repository. Nothing is copied if the signer made no signature
+// of the image promoted.
+func (p *promoter) promote(ctx context.Context, se oci.SignedEntity) error {
+	h, err := se.Digest()
+	if err != nil {
+		return err
+	}
+	e := promotedImage{digest: p.dstRepo.Digest(h.String()), tags: map[name.Tag]promotedTag{}}
+
+	sigs, err := p.selectFrom(ctx, se.Signatures, false)
+	if err != nil {
+		return err
+	}
+	if len(sigs) == 0 && len(p.promoted) == 0 {
+		return fmt.Errorf("no signatures of %s are made by %s", p.srcRepo.Digest(h.String()), p.opts.SignedBy)
+	}
+	var atts []oci.Signature
+	if !p.opts.SigOnly {
+		if atts, err = p.selectFrom(ctx, se.Attestations, true); err != nil {
+			return err
+		}
+	}
+
+	desc, err := remote.Get(p.srcRepo.Digest(h.String()), p.remoteOpts...)
+	if err != nil {
+		return err
+	}
+	if err := p.pusher.Push(ctx, e.digest, desc); err != nil {
+		return fmt.Errorf("copying %s: %w", e.digest, err)
+	}
+	if err := p.write(e, sigs, ociremote.SignatureTag, false); err != nil {
+		return err
+	}
+	if err := p.write(e, atts, ociremote.AttestationTag, true); err != nil {
+		return err
+	}
+	e.signatures, e.attestations = len(sigs), len(atts)
+	p.promoted = append(p.promoted, e)
+	return nil
+}
+
+// selected reports whether the signature or attestation sig is promoted.
+func (p *promoter) selected(ctx context.Context, sig oci.Signature, attestation bool) (bool, error) {
+	match, err := p.opts.SignedBy.Match(ctx, sig, attestation)
+	if err != nil || !match || !attestation || len(p.opts.PredicateTypes) == 0 {
+		return match, err
+	}
+	pt := predicateType(sig)
+	for _, t := range p.opts.PredicateTypes {
+		if pt == t {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// selectFrom returns the signatures or attestations that get returns and
+// that are promoted.
+func (p *promoter) selectFrom(ctx context.Context, get func() (oci.Signatures, error), attestation bool) ([]oci.Signature, error) {
+	sigs, err := get()
+	if err != nil {
+		return nil, err
+	}
+	all, err := sigs.Get()
+	if err != nil {
+		return nil, err
+	}
+	var selected []oci.Signature
+	for _, sig := range all {
+		ok, err := p.selected(ctx, sig, attestation)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			selected = append(selected, sig)
+		}
+	}
+	return selected, nil
+}
+
+// write writes sigs to the tag of tm for the image e, unless there are none.
+func (p *promoter) write(e promotedImage, sigs []oci.Signature, tm func(name.Reference, ...ociremote.Option) (name.Tag, error), attestation bool) error {
+	if len(sigs) == 0 {
+		return nil
+	}
+	digests := make([]v1.Hash, 0, len(sigs))
+	for _, sig := range sigs {
+		h, err := sig.Digest()
+		if err != nil {
+			return err
+		}
+		digests = append(digests, h)
+	}
+
+	src, err := tm(e.digest, p.ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	tag := p.dstRepo.Tag(src.Identifier())
+	img, err := mutate.AppendSignatures(empty.Signatures(), sigs...)
+	if err != nil {
+		return err
+	}
+	if !p.opts.Force {
+		if existing, err := remote.Head(tag, p.remoteOpts...); err == nil {
+			want, err := img.Digest()
+			if err != nil {
+				return err
+			}
+			if existing.Digest != want {
+				return fmt.Errorf("%s already exists. Use `-f` to overwrite", tag)
+			}
+		}
+	}
+	if err := remote.Write(tag, img, p.remoteOpts...); err != nil {
+		return fmt.Errorf("writing %s: %w", tag, err)
+	}
+	e.tags[tag] = promotedTag{digests: digests, attestation: attestation}
+	return nil
+}
+
+// check reads back the signatures and attestations of the promoted image e
+// and verifies that they are those selected, and still made by the signer.
+func (p *promoter) check(ctx context.Context, e promotedImage) error {
+	for tag, want := range e.tags {
+		sigs, err := ociremote.Signatures(tag, ociremote.WithRemoteOptions(p.remoteOpts...))
+		if err != nil {
+			return err
+		}
+		got, err := sigs.Get()
+		if err != nil {
+			return err
+		}
+		if len(got) != len(want.digests) {
+			return fmt.Errorf("%s has %d entries, want the %d promoted", tag, len(got), len(want.digests))
+		}
+		for i, sig := range got {
+			h, err := sig.Digest()
+			if err != nil {
+				return err
+			}
+			if h != want.digests[i] {
+				return fmt.Errorf("entry %d of %s is %s, want %s", i, tag, h, want.digests[i])
+			}
+			ok, err := p.selected(ctx, sig, want.attestation)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("entry %s of %s isn't made by %s", h, tag, p.opts.SignedBy)
+			}
+		}
+	}
+	return nil
+}
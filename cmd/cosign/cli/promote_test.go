@@ -0,0 +1,197 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	slsa "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+func attestPayload(t *testing.T, signer signature.Signer, subject name.Digest, predicateType string) oci.Signature {
+	t.Helper()
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"` + predicateType + `","subject":[{"name":"` +
+		subject.Context().String() + `","digest":{"sha256":"` + strings.TrimPrefix(subject.DigestStr(), "sha256:") + `"}}],"predicate":{}}`)
+	envelope, err := dsse.WrapSigner(signer, types.IntotoPayloadType).SignMessage(bytes.NewReader(statement))
+	if err != nil {
+		t.Fatal(err)
+	}
+	att, err := static.NewAttestation(envelope, static.WithLayerMediaType(types.DssePayloadType))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return att
+}
+
+func TestPromoteCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := name.NewDigest(host + "/staging/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(src, img); err != nil {
+		t.Fatal(err)
+	}
+	write := func(tm func(name.Reference, ...ociremote.Option) (name.Tag, error), ref name.Reference, sigs ...oci.Signature) {
+		t.Helper()
+		tag, err := tm(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := mutate.AppendSignatures(empty.Signatures(), sigs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(tag, img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := func(tm func(name.Reference, ...ociremote.Option) (name.Tag, error), ref name.Reference) []oci.Signature {
+		t.Helper()
+		tag, err := tm(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs, err := ociremote.Signatures(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l, err := sigs.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return l
+	}
+
+	// Signatures and attestations of the release key and of another key,
+	// and an SBOM.
+	payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + h.String() + `"}}}`)
+	releaseSigner, releaseKey := newTestKey(t)
+	otherSigner, _ := newTestKey(t)
+	_, unusedKey := newTestKey(t)
+	write(ociremote.SignatureTag, src, signPayload(t, releaseSigner, payload), signPayload(t, otherSigner, payload))
+	write(ociremote.AttestationTag, src,
+		attestPayload(t, releaseSigner, src, slsa.PredicateSLSAProvenance),
+		attestPayload(t, releaseSigner, src, in_toto.PredicateSPDX),
+		attestPayload(t, otherSigner, src, slsa.PredicateSLSAProvenance))
+	sbomTag, err := ociremote.SBOMTag(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sbom, err := static.NewFile([]byte(`{}`), static.WithLayerMediaType(types.SPDXJSONMediaType))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sbomTag, sbom); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := NewSignerMatcher(ctx, releaseKey, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	o := PromoteOpts{SignedBy: release, PredicateTypes: []string{slsa.PredicateSLSAProvenance}}
+	if err := PromoteCmd(ctx, options.RegistryOptions{}, src.String(), host+"/prod/app:v1", o, &out); err != nil {
+		t.Fatalf("PromoteCmd() = %v", err)
+	}
+	if !strings.Contains(out.String(), "with 1 signatures and 1 attestations") {
+		t.Errorf("PromoteCmd() wrote %q", out.String())
+	}
+
+	dst, err := name.NewDigest(host + "/prod/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc, err := remote.Head(dst.Context().Tag("v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Digest != h {
+		t.Errorf("the destination tag points to %s, want %s", desc.Digest, h)
+	}
+	if sigs := entries(ociremote.SignatureTag, dst); len(sigs) != 1 {
+		t.Errorf("promoted %d signatures, want the one of the release key", len(sigs))
+	}
+	if atts := entries(ociremote.AttestationTag, dst); len(atts) != 1 || predicateType(atts[0]) != slsa.PredicateSLSAProvenance {
+		t.Errorf("promoted %d attestations, want the SLSA provenance of the release key", len(atts))
+	}
+	if dstSBOM, err := ociremote.SBOMTag(dst); err != nil {
+		t.Fatal(err)
+	} else if _, err := remote.Head(dstSBOM); err == nil {
+		t.Errorf("%s was promoted, want the SBOM left behind", dstSBOM)
+	}
+
+	// Promoting again with another selection conflicts with the promoted
+	// attestations, unless forced.
+	o.PredicateTypes = nil
+	if err := PromoteCmd(ctx, options.RegistryOptions{}, src.String(), host+"/prod/app", o, io.Discard); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("PromoteCmd() over other attestations = %v, want an error", err)
+	}
+	o.Force = true
+	if err := PromoteCmd(ctx, options.RegistryOptions{}, src.String(), host+"/prod/app", o, io.Discard); err != nil {
+		t.Fatalf("PromoteCmd() with Force = %v", err)
+	}
+	if atts := entries(ociremote.AttestationTag, dst); len(atts) != 2 {
+		t.Errorf("promoted %d attestations, want both of the release key", len(atts))
+	}
+
+	unused, err := NewSignerMatcher(ctx, unusedKey, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PromoteCmd(ctx, options.RegistryOptions{}, src.String(), host+"/other/app", PromoteOpts{SignedBy: unused}, io.Discard); err == nil || !strings.Contains(err.Error(), "no signatures") {
+		t.Errorf("PromoteCmd() without signatures of the signer = %v, want an error", err)
+	}
+	if _, err := remote.Head(dst.Context().Registry.Repo("other", "app").Digest(h.String())); err == nil {
+		t.Error("the image was copied without signatures of the signer")
+	}
+
+	if err := PromoteCmd(ctx, options.RegistryOptions{}, src.String(), host+"/prod/app@sha256:"+strings.Repeat("0", 64), o, io.Discard); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("PromoteCmd() to another digest = %v, want an error", err)
+	}
+}
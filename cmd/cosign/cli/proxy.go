@@ -0,0 +1,125 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+func Proxy() *cobra.Command {
+	o := &options.ProxyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Run a local registry proxy that only serves verified images",
+		Long: `Run a local registry proxy that pulls images through from their registries and
+only serves the ones whose signatures are valid for the policy, so that Docker
+and containerd on a workstation can only run verified images.
+
+The policy lists the key or certificate identity the images of the repositories
+matching each glob must be verified with. The first matching entry is used, and
+images of repositories that match no entry are not served, e.g.
+
+  {"images": [
+    {"glob": "ghcr.io/example/*", "certificateIdentityRegexp": "^https://github.com/example/", "certificateOidcIssuer": "https://token.actions.githubusercontent.com"},
+    {"glob": "index.docker.io/library/*", "key": "cosign.pub"}
+  ]}
+
+The entries also accept certificateIdentity, certificateOidcIssuerRegexp,
+certificateClaims, the --certificate-claim constraints of cosign verify, e.g.
+["runnerEnvironment=github-hosted"], ignoreTlog and ignoreSCT, and labels and
+annotations, which restrict an entry to the images whose config labels or
+manifest annotations have their values, so that e.g. images labeled env=prod
+need a stricter identity:
+
+  {"glob": "ghcr.io/example/*", "labels": {"env": "prod"}, "certificateIdentity": "https://github.com/example/app/.github/workflows/release.yml@refs/heads/main", ...}
+
+Images are pulled as localhost:5000/<registry>/<repository>. Repositories that
+don't start with a registry host are pulled from --default-registry, so the
+proxy can be a Docker registry mirror. containerd names the mirrored registry in
+the ns query parameter of its requests, so the proxy can be configured as the
+mirror of any registry in its hosts.toml.`,
+		Example: `  cosign proxy --policy policy.json [--address localhost:5000]
+
+  # pull an image through the proxy
+  docker pull localhost:5000/ghcr.io/example/app:latest
+
+  # use the proxy as the Docker Hub mirror, in /etc/docker/daemon.json
+  {"registry-mirrors": ["http://localhost:5000"]}
+
+  # use the proxy as a containerd mirror, in /etc/containerd/certs.d/ghcr.io/hosts.toml
+  [host."http://localhost:5000"]
+    capabilities = ["pull", "resolve"]`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ProxyCmd(cmd.Context(), *o)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// ProxyCmd serves the registry proxy on o.Address until ctx is done or
+// cosign is interrupted.
+func ProxyCmd(ctx context.Context, o options.ProxyOptions) error {
+	p, err := proxy.ReadPolicy(o.Policy)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	l, err := net.Listen("tcp", o.Address)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler: &proxy.Server{
+			DefaultRegistry: o.DefaultRegistry,
+			Verify:          p.Verifier(o),
+			NameOptions:     o.Registry.NameOptions(),
+			RemoteOptions:   o.Registry.GetRegistryClientOpts(ctx),
+		},
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	ui.Infof(ctx, "Serving verified images on %s", l.Addr())
+	if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// Policy is the --policy of cosign proxy: how the images of each repository
+// are verified before they are served.
+type Policy struct {
+	// Images are tried in order, and the first whose glob matches the
+	// repository of an image, and whose labels and annotations, if any, match
+	// its metadata, verifies it. Images that match no entry are not served.
+	Images []PolicyEntry `json:"images"`
+}
+
+// PolicyEntry is the verification policy for the images in the repositories
+// matching Glob, a path.Match pattern against the full repository name, e.g.
+// index.docker.io/library/*. An empty Glob matches every repository.
+//
+// With Labels or Annotations, the entry only matches the images whose config
+// labels, or manifest annotations, have all of their values, e.g. env=prod.
+// An index has no labels, so multi-platform images are matched by their
+// annotations.
+type PolicyEntry struct {
+	Glob                        string            `json:"glob,omitempty"`
+	Labels                      map[string]string `json:"labels,omitempty"`
+	Annotations                 map[string]string `json:"annotations,omitempty"`
+	Key                         string            `json:"key,omitempty"`
+	CertificateIdentity         string            `json:"certificateIdentity,omitempty"`
+	CertificateIdentityRegexp   string            `json:"certificateIdentityRegexp,omitempty"`
+	CertificateOIDCIssuer       string            `json:"certificateOidcIssuer,omitempty"`
+	CertificateOIDCIssuerRegexp string            `json:"certificateOidcIssuerRegexp,omitempty"`
+	CertificateClaims           []string          `json:"certificateClaims,omitempty"`
+	IgnoreTlog                  bool              `json:"ignoreTlog,omitempty"`
+	IgnoreSCT                   bool              `json:"ignoreSCT,omitempty"`
+}
+
+// ReadPolicy reads and validates the policy in file.
+func ReadPolicy(file string) (*Policy, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policy{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing proxy policy %s: %w", file, err)
+	}
+	if len(p.Images) == 0 {
+		return nil, fmt.Errorf("proxy policy %s has no images", file)
+	}
+	for i, e := range p.Images {
+		if err := e.Validate(); err != nil {
+			return nil, fmt.Errorf("proxy policy %s: entry %d: %w", file, i, err)
+		}
+	}
+	return p, nil
+}
+
+// Validate returns why e isn't a valid entry: it has no key or certificate
+// identity, or an invalid glob or certificate claim.
+func (e *PolicyEntry) Validate() error {
+	if e.Key == "" && e.CertificateIdentity == "" && e.CertificateIdentityRegexp == "" {
+		return errors.New("must set a key or a certificate identity")
+	}
+	if _, err := path.Match(e.Glob, ""); err != nil {
+		return fmt.Errorf("invalid glob %q: %w", e.Glob, err)
+	}
+	if _, err := cosign.ParseClaimMatchers(e.CertificateClaims); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Match returns the first entry that matches the image ref, or nil if none
+// does. The labels and annotations of the image are only fetched, with opts,
+// if an entry with a matching glob selects them.
+func (p *Policy) Match(ctx context.Context, ref name.Reference, opts ...remote.Option) (*PolicyEntry, error) {
+	opts = append([]remote.Option{remote.WithContext(ctx)}, opts...)
+	return p.matchMetadata(ref.Context().Name(), func() (*imageMetadata, error) {
+		return fetchMetadata(ref, opts...)
+	})
+}
+
+func (p *Policy) matchMetadata(repo string, fetch func() (*imageMetadata, error)) (*PolicyEntry, error) {
+	var md *imageMetadata
+	for i, e := range p.Images {
+		if e.Glob != "" {
+			if ok, _ := path.Match(e.Glob, repo); !ok {
+				continue
+			}
+		}
+		if len(e.Labels) > 0 || len(e.Annotations) > 0 {
+			if md == nil {
+				var err error
+				if md, err = fetch(); err != nil {
+					return nil, err
+				}
+			}
+			if !contains(md.labels, e.Labels) || !contains(md.annotations, e.Annotations) {
+				continue
+			}
+		}
+		return &p.Images[i], nil
+	}
+	return nil, nil
+}
+
+// contains reports whether m has every value of want.
+func contains(m, want map[string]string) bool {
+	for k, v := range want {
+		if got, ok := m[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// imageMetadata is what the labels and annotations of policy entries are
+// matched against.
+type imageMetadata struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+func fetchMetadata(ref name.Reference, opts ...remote.Option) (*imageMetadata, error) {
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("getting the manifest of %s: %w", ref, err)
+	}
+	// Image manifests and indexes both keep their annotations at the top.
+	var m struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.Unmarshal(desc.Manifest, &m); err != nil {
+		return nil, fmt.Errorf("parsing the manifest of %s: %w", ref, err)
+	}
+	md := &imageMetadata{annotations: m.Annotations}
+	if desc.MediaType.IsImage() {
+		img, err := desc.Image()
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := img.ConfigFile()
+		if err != nil {
+			return nil, fmt.Errorf("getting the config of %s: %w", ref, err)
+		}
+		md.labels = cfg.Config.Labels
+	}
+	return md, nil
+}
+
+// Apply sets the key, the certificate identity and the transparency log and
+// SCT checks of v to those of e.
+func (e *PolicyEntry) Apply(v *verify.VerifyCommand) {
+	v.KeyRef = e.Key
+	v.CertIdentity = e.CertificateIdentity
+	v.CertIdentityRegexp = e.CertificateIdentityRegexp
+	v.CertOidcIssuer = e.CertificateOIDCIssuer
+	v.CertOidcIssuerRegexp = e.CertificateOIDCIssuerRegexp
+	v.CertClaims = e.CertificateClaims
+	v.IgnoreTlog = v.IgnoreTlog || e.IgnoreTlog
+	v.IgnoreSCT = v.IgnoreSCT || e.IgnoreSCT
+}
+
+// Verifier returns a VerifyFunc that verifies the signatures of images with
+// the entry of p that matches them.
+func (p *Policy) Verifier(o options.ProxyOptions) VerifyFunc {
+	return func(ctx context.Context, ref name.Digest) error {
+		e, err := p.Match(ctx, ref, o.Registry.GetRegistryClientOpts(ctx)...)
+		if err != nil {
+			return err
+		}
+		if e == nil {
+			return fmt.Errorf("no proxy policy entry matches %s", ref)
+		}
+		v := &verify.VerifyCommand{
+			RegistryOptions: o.Registry,
+			CheckClaims:     true,
+			RekorURL:        o.Rekor.URL,
+			NameOptions:     o.Registry.NameOptions(),
+			OnVerified: func(context.Context, name.Reference, []oci.Signature) error {
+				return nil
+			},
+		}
+		e.Apply(v)
+		return v.Exec(ctx, []string{ref.String()})
+	}
+}
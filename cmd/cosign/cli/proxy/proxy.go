@@ -0,0 +1,262 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proxy implements a read-only registry that pulls images through
+// from their registries and only serves the ones that pass verification.
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// VerifyFunc verifies the image ref before it is served.
+type VerifyFunc func(ctx context.Context, ref name.Digest) error
+
+// Server is an http.Handler serving the pull side of the registry API. The
+// manifests of an image are only served once the image passed Verify, and
+// blobs only if they belong to such a manifest.
+type Server struct {
+	// DefaultRegistry is the registry of the repositories whose name doesn't
+	// start with a registry host, like Docker Hub for registry mirrors.
+	DefaultRegistry string
+	Verify          VerifyFunc
+	NameOptions     []name.Option
+	RemoteOptions   []remote.Option
+
+	group singleflight.Group
+
+	mu sync.Mutex
+	// allowed are the manifests and blobs that may be served, by
+	// repository and digest.
+	allowed map[string]bool
+}
+
+var _ http.Handler = (*Server)(nil)
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the cosign registry proxy is read-only")
+		return
+	}
+	if r.URL.Path == "/v2/" || r.URL.Path == "/v2" {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, "{}")
+		return
+	}
+
+	p := strings.TrimPrefix(r.URL.Path, "/v2/")
+	for _, kind := range []string{"manifests", "blobs"} {
+		i := strings.LastIndex(p, "/"+kind+"/")
+		if i <= 0 || p == r.URL.Path {
+			continue
+		}
+		repo, err := s.repository(p[:i], r.URL.Query().Get("ns"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "NAME_INVALID", err.Error())
+			return
+		}
+		ref := p[i+len(kind)+2:]
+		if kind == "manifests" {
+			s.serveManifest(w, r, repo, ref)
+		} else {
+			s.serveBlob(w, r, repo, ref)
+		}
+		return
+	}
+	writeError(w, http.StatusNotFound, "UNSUPPORTED", fmt.Sprintf("%s is not part of the pull API", r.URL.Path))
+}
+
+// repository returns the upstream repository of the repository path of a
+// request. containerd names the registry it mirrors in the ns query
+// parameter, other clients in the first path component.
+func (s *Server) repository(p, ns string) (name.Repository, error) {
+	if ns == "" {
+		ns = s.DefaultRegistry
+		if host, rest, ok := strings.Cut(p, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+			ns, p = host, rest
+		}
+	}
+	return name.NewRepository(ns+"/"+p, s.NameOptions...)
+}
+
+func (s *Server) serveManifest(w http.ResponseWriter, r *http.Request, repo name.Repository, ref string) {
+	var upstream name.Reference
+	var err error
+	if strings.Contains(ref, ":") {
+		upstream, err = oci.ParseDigestReference(repo.Name()+"@"+ref, s.NameOptions...)
+	} else {
+		upstream, err = name.NewTag(repo.Name()+":"+ref, s.NameOptions...)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	desc, err := ociremote.Descriptor(upstream, ociremote.WithRemoteOptions(append(s.RemoteOptions, remote.WithContext(ctx))...))
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	digest := repo.Digest(desc.Digest.String())
+	if !s.isAllowed(repo, desc.Digest) {
+		// Pulls of the same image are verified once, however many clients
+		// ask for it at the same time.
+		_, err, _ := s.group.Do(digest.String(), func() (interface{}, error) {
+			return nil, s.Verify(ctx, digest)
+		})
+		if err != nil {
+			ui.Warnf(ctx, "Denied %s: %v", upstream, err)
+			writeError(w, http.StatusForbidden, "DENIED", fmt.Sprintf("%s failed verification: %v", upstream, err))
+			return
+		}
+		ui.Infof(ctx, "Verified %s", digest)
+	}
+	if err := s.allowReferences(repo, desc); err != nil {
+		writeError(w, http.StatusBadGateway, "MANIFEST_INVALID", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", string(desc.MediaType))
+	w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(desc.Manifest)))
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(desc.Manifest)
+	}
+}
+
+func (s *Server) serveBlob(w http.ResponseWriter, r *http.Request, repo name.Repository, ref string) {
+	h, err := oci.ParseDigest(ref)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", err.Error())
+		return
+	}
+	if !s.isAllowed(repo, h) {
+		writeError(w, http.StatusForbidden, "DENIED", fmt.Sprintf("blob %s is not part of a verified image", h))
+		return
+	}
+
+	layer, err := remote.Layer(repo.Digest(h.String()), append(s.RemoteOptions, remote.WithContext(r.Context()))...)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	size, err := layer.Size()
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", h.String())
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	if r.Method == http.MethodHead {
+		return
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	defer rc.Close()
+	_, _ = io.Copy(w, rc)
+}
+
+func (s *Server) isAllowed(repo name.Repository, h v1.Hash) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.allowed[repo.Name()+"@"+h.String()]
+}
+
+// allowReferences allows the manifest of desc, which passed verification,
+// and everything it references: the manifests of an index, and the config
+// and layers of an image.
+func (s *Server) allowReferences(repo name.Repository, desc *remote.Descriptor) error {
+	digests := []v1.Hash{desc.Digest}
+	switch {
+	case desc.MediaType.IsIndex():
+		idx, err := v1.ParseIndexManifest(bytes.NewReader(desc.Manifest))
+		if err != nil {
+			return fmt.Errorf("parsing index %s: %w", desc.Digest, err)
+		}
+		for _, m := range idx.Manifests {
+			digests = append(digests, m.Digest)
+		}
+	case desc.MediaType.IsImage():
+		m, err := v1.ParseManifest(bytes.NewReader(desc.Manifest))
+		if err != nil {
+			return fmt.Errorf("parsing manifest %s: %w", desc.Digest, err)
+		}
+		digests = append(digests, m.Config.Digest)
+		for _, l := range m.Layers {
+			digests = append(digests, l.Digest)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.allowed == nil {
+		s.allowed = map[string]bool{}
+	}
+	for _, h := range digests {
+		s.allowed[repo.Name()+"@"+h.String()] = true
+	}
+	return nil
+}
+
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode != 0 {
+		code := "UNKNOWN"
+		if len(terr.Errors) > 0 {
+			code = string(terr.Errors[0].Code)
+		}
+		writeError(w, terr.StatusCode, code, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadGateway, "UNKNOWN", err.Error())
+}
+
+// writeError writes an error response of the registry API.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	type registryError struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Errors []registryError `json:"errors"`
+	}{Errors: []registryError{{Code: code, Message: message}}})
+}
@@ -0,0 +1,306 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+func TestServer(t *testing.T) {
+	upstream := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer upstream.Close()
+	upstreamHost := strings.TrimPrefix(upstream.URL, "http://")
+
+	idx, err := random.Index(100, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := name.NewTag(upstreamHost + "/signed:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := name.NewTag(upstreamHost + "/unsigned:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.WriteIndex(signed, idx); err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(unsigned, img); err != nil {
+		t.Fatal(err)
+	}
+	idxDigest, err := idx.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var verifications int32
+	srv := &Server{
+		DefaultRegistry: upstreamHost,
+		Verify: func(_ context.Context, ref name.Digest) error {
+			atomic.AddInt32(&verifications, 1)
+			if ref.Context().Name() == signed.Context().Name() && ref.DigestStr() == idxDigest.String() {
+				return nil
+			}
+			return errors.New("no matching signatures")
+		},
+	}
+	proxy := httptest.NewServer(srv)
+	defer proxy.Close()
+	proxyHost := strings.TrimPrefix(proxy.URL, "http://")
+
+	t.Run("verified index", func(t *testing.T) {
+		pulled, err := remote.Index(mustRef(t, proxyHost+"/signed:latest"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := pulled.IndexManifest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, desc := range m.Manifests {
+			child, err := pulled.Image(desc.Digest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			layers, err := child.Layers()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, l := range layers {
+				rc, err := l.Compressed()
+				if err != nil {
+					t.Fatal(err)
+				}
+				rc.Close()
+			}
+			if _, err := child.RawConfigFile(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := atomic.SwapInt32(&verifications, 0); got != 1 {
+			t.Errorf("verified %d times, want only the index to be verified", got)
+		}
+	})
+
+	t.Run("default registry", func(t *testing.T) {
+		if _, err := remote.Head(mustRef(t, proxyHost+"/signed:latest")); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("containerd ns", func(t *testing.T) {
+		srv := &Server{DefaultRegistry: "index.docker.io", Verify: srv.Verify}
+		proxy := httptest.NewServer(srv)
+		defer proxy.Close()
+		resp, err := http.Get(proxy.URL + "/v2/signed/manifests/latest?ns=" + url.QueryEscape(upstreamHost))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Docker-Content-Digest") != idxDigest.String() {
+			t.Errorf("got status %d and digest %q", resp.StatusCode, resp.Header.Get("Docker-Content-Digest"))
+		}
+	})
+
+	t.Run("denied image", func(t *testing.T) {
+		_, err := remote.Image(mustRef(t, proxyHost+"/unsigned:latest"))
+		var terr *transport.Error
+		if !errors.As(err, &terr) || terr.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected the image to be denied, got %v", err)
+		}
+	})
+
+	t.Run("denied blob", func(t *testing.T) {
+		layers, err := img.Layers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := layers[0].Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.Get(proxy.URL + "/v2/unsigned/blobs/" + h.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusForbidden)
+		}
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		err := remote.Write(mustRef(t, proxyHost+"/pushed:latest"), img)
+		if err == nil {
+			t.Fatal("expected the push to fail")
+		}
+	})
+}
+
+func TestReadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(s string) string {
+		p := filepath.Join(dir, "policy.json")
+		if err := os.WriteFile(p, []byte(s), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	p, err := ReadPolicy(write(`{"images": [
+		{"glob": "ghcr.io/example/*", "certificateIdentity": "release@example.com"},
+		{"key": "cosign.pub"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if e, err := p.Match(ctx, mustRef(t, "ghcr.io/example/app")); err != nil || e == nil || e.CertificateIdentity != "release@example.com" {
+		t.Errorf("got entry %+v, %v for ghcr.io/example/app", e, err)
+	}
+	if e, err := p.Match(ctx, mustRef(t, "busybox")); err != nil || e == nil || e.Key != "cosign.pub" {
+		t.Errorf("got entry %+v, %v for index.docker.io/library/busybox", e, err)
+	}
+
+	for _, s := range []string{
+		`{"images": []}`,
+		`{"images": [{"glob": "ghcr.io/*"}]}`,
+		`{"images": [{"glob": "[", "key": "cosign.pub"}]}`,
+		`{"images": [{"key": "cosign.pub", "allow": true}]}`,
+		`{"images": [{"certificateIdentity": "release@example.com", "certificateClaims": ["runner=github-hosted"]}]}`,
+	} {
+		if _, err := ReadPolicy(write(s)); err == nil {
+			t.Errorf("expected an error for %s", s)
+		}
+	}
+}
+
+func TestPolicyMatchMetadata(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer s.Close()
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod, err := mutate.Config(img, v1.Config{Labels: map[string]string{"env": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(mustRef(t, host+"/app:prod"), prod); err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(mustRef(t, host+"/app:dev"), img); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := random.Index(100, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotated := mutate.Annotations(idx, map[string]string{"env": "prod"}).(v1.ImageIndex)
+	if err := remote.WriteIndex(mustRef(t, host+"/app:multi"), annotated); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Policy{Images: []PolicyEntry{
+		{Glob: host + "/*", Labels: map[string]string{"env": "prod"}, Key: "prod.pub"},
+		{Glob: host + "/*", Annotations: map[string]string{"env": "prod"}, Key: "multi.pub"},
+		{Glob: host + "/*", Key: "dev.pub"},
+	}}
+	for tag, want := range map[string]string{
+		"prod":  "prod.pub",
+		"multi": "multi.pub",
+		"dev":   "dev.pub",
+	} {
+		e, err := p.Match(ctx, mustRef(t, host+"/app:"+tag))
+		if err != nil || e == nil || e.Key != want {
+			t.Errorf("Match(%s) = %+v, %v, want the entry with %s", tag, e, err, want)
+		}
+	}
+
+	// The metadata is only fetched for the entries that select it.
+	if e, err := p.Match(ctx, mustRef(t, "ghcr.io/example/app")); err != nil || e != nil {
+		t.Errorf("Match() for another registry = %+v, %v, want no entry", e, err)
+	}
+	if _, err := p.Match(ctx, mustRef(t, host+"/app:missing")); err == nil {
+		t.Error("Match() of a missing image: expected an error")
+	}
+}
+
+func TestServerVerifiesOnce(t *testing.T) {
+	upstream := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer upstream.Close()
+	upstreamHost := strings.TrimPrefix(upstream.URL, "http://")
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(mustRef(t, upstreamHost+"/app:latest"), img); err != nil {
+		t.Fatal(err)
+	}
+
+	var verifications int32
+	srv := &Server{DefaultRegistry: upstreamHost, Verify: func(context.Context, name.Digest) error {
+		atomic.AddInt32(&verifications, 1)
+		return nil
+	}}
+	proxy := httptest.NewServer(srv)
+	defer proxy.Close()
+	ref := mustRef(t, strings.TrimPrefix(proxy.URL, "http://")+"/app:latest")
+	for i := 0; i < 3; i++ {
+		if _, err := remote.Get(ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := atomic.LoadInt32(&verifications); got != 1 {
+		t.Errorf("verified %d times, want 1", got)
+	}
+}
+
+func mustRef(t *testing.T, s string) name.Reference {
+	t.Helper()
+	ref, err := name.ParseReference(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
@@ -15,16 +15,76 @@
 package rekor
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-retryablehttp"
 	rekor "github.com/sigstore/rekor/pkg/client"
 	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/util"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/internal/pkg/forensics"
+	"github.com/sigstore/cosign/v2/internal/pkg/telemetry"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
 )
 
+// NewClient returns a client of the Rekor log at rekorURL. It is set up like
+// rekor.GetRekorClient, but identical lookups are coalesced and cached, and
+// requests are held back while the rate limit of the log or the request
+// budget is exhausted, so that verifying many artifacts against a public
+// instance is not throttled.
 func NewClient(rekorURL string) (*client.Rekor, error) {
-	rekorClient, err := rekor.GetRekorClient(rekorURL, rekor.WithUserAgent(options.UserAgent()))
+	if IsTileLog(rekorURL) {
+		return nil, fmt.Errorf("%s is read as tiles, which only verifies the Rekor v2 entries of bundles", rekorURL)
+	}
+	u, err := url.Parse(rekorURL)
 	if err != nil {
 		return nil, err
 	}
-	return rekorClient, nil
+	if u.Path == "" {
+		u.Path = client.DefaultBasePath
+	}
+
+	retryableClient := retryablehttp.NewClient()
+	retryableClient.HTTPClient = &http.Client{
+		Transport: newRateLimitTransport(&userAgentTransport{
+			next:      budget.Transport(telemetry.Transport(forensics.Transport(cleanhttp.DefaultTransport(), telemetry.Rekor), telemetry.Rekor), telemetry.Rekor),
+			userAgent: options.UserAgent(),
+		}),
+	}
+	retryableClient.RetryMax = rekor.DefaultRetryCount
+	retryableClient.Logger = nil
+	httpClient := &http.Client{
+		Transport: newLookupTransport(&retryablehttp.RoundTripper{Client: retryableClient}),
+	}
+
+	rt := httptransport.NewWithClient(u.Host, u.Path, []string{u.Scheme}, httpClient)
+	rt.Consumers["application/json"] = runtime.JSONConsumer()
+	rt.Consumers["application/x-pem-file"] = runtime.TextConsumer()
+	rt.Producers["application/json"] = runtime.JSONProducer()
+
+	registry := strfmt.Default
+	registry.Add("signedCheckpoint", &util.SignedNote{}, util.SignedCheckpointValidator)
+	return client.New(rt, registry), nil
+}
+
+// IsTileLog reports whether rekorURL has the tiles+ scheme prefix, which
+// selects reading the log as tiles rather than through the Rekor v1 API.
+func IsTileLog(rekorURL string) bool {
+	return strings.HasPrefix(rekorURL, rekorv2.TileScheme)
+}
+
+// NewV2Client returns a client of the Rekor v2 log at rekorURL, which may
+// have the tiles+ scheme prefix.
+func NewV2Client(rekorURL string) (*rekorv2.Client, error) {
+	return rekorv2.NewClient(rekorURL, rekorv2.WithUserAgent(options.UserAgent()),
+		rekorv2.WithHTTPClient(&http.Client{Transport: budget.Transport(telemetry.Transport(forensics.Transport(http.DefaultTransport, telemetry.Rekor), telemetry.Rekor), telemetry.Rekor)}))
 }
@@ -50,3 +50,16 @@ func TestNewClient(t *testing.T) {
 		t.Fatal("no requests were received")
 	}
 }
+
+func TestTileLogURL(t *testing.T) {
+	const u = "tiles+https://log.example.com"
+	if !IsTileLog(u) || IsTileLog("https://rekor.sigstore.dev") {
+		t.Error("IsTileLog() doesn't tell tile logs by their tiles+ scheme prefix")
+	}
+	if _, err := NewClient(u); err == nil {
+		t.Error("NewClient() of a tile log: expected an error")
+	}
+	if _, err := NewV2Client(u); err != nil {
+		t.Errorf("NewV2Client() of a tile log = %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekor
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+const (
+	// lookupCacheSize is the number of Rekor lookups that are kept in memory.
+	lookupCacheSize = 1024
+	// maxRateLimitWait bounds how long a request waits for a rate limit to
+	// reset, so that a bogus reset header cannot stall cosign.
+	maxRateLimitWait = time.Minute
+)
+
+// cachedResponse is a successful Rekor lookup shared by all of its callers.
+type cachedResponse struct {
+	key        string
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func (c *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(c.statusCode),
+		StatusCode:    c.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}
+}
+
+// lookupTransport coalesces identical concurrent Rekor lookups into a single
+// request and caches the entries that were found, so that verifying many
+// artifacts does not fetch the same entry over and over.
+type lookupTransport struct {
+	next  http.RoundTripper
+	group singleflight.Group
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+func newLookupTransport(next http.RoundTripper) *lookupTransport {
+	return &lookupTransport{
+		next:    next,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// isLookup reports whether req reads entries from the log. The responses of
+// these never change, unlike the log info and consistency proofs.
+func isLookup(req *http.Request) bool {
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	switch req.Method {
+	case http.MethodGet:
+		return strings.Contains(path, "/log/entries") || strings.HasSuffix(path, "/log/publicKey")
+	case http.MethodPost:
+		return strings.HasSuffix(path, "/log/entries/retrieve") || strings.HasSuffix(path, "/index/retrieve")
+	}
+	return false
+}
+
+func (t *lookupTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isLookup(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	key := req.Method + " " + req.URL.String() + " " + hex.EncodeToString(sum[:])
+	if c := t.get(key); c != nil {
+		return c.response(req), nil
+	}
+
+	v, err, _ := t.group.Do(key, func() (interface{}, error) {
+		r := req.Clone(req.Context())
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		resp, err := t.next.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		c := &cachedResponse{key: key, statusCode: resp.StatusCode, header: resp.Header, body: b}
+		if resp.StatusCode == http.StatusOK {
+			t.add(c)
+		}
+		return c, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*cachedResponse).response(req), nil
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func (t *lookupTransport) get(key string) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.lru.MoveToFront(e)
+	return e.Value.(*cachedResponse)
+}
+
+func (t *lookupTransport) add(c *cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[c.key]; ok {
+		return
+	}
+	t.entries[c.key] = t.lru.PushFront(c)
+	if t.lru.Len() > lookupCacheSize {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// rateLimitTransport holds back requests while the Rekor rate limit is
+// exhausted, as announced by the Retry-After and RateLimit headers of its
+// responses, instead of letting every concurrent request be throttled.
+type rateLimitTransport struct {
+	next http.RoundTripper
+	now  func() time.Time
+
+	mu        sync.Mutex
+	notBefore time.Time
+}
+
+func newRateLimitTransport(next http.RoundTripper) *rateLimitTransport {
+	return &rateLimitTransport{next: next, now: time.Now}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if reset, ok := t.reset(resp); ok {
+		t.mu.Lock()
+		if reset.After(t.notBefore) {
+			t.notBefore = reset
+		}
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+func (t *rateLimitTransport) wait(ctx context.Context) error {
+	t.mu.Lock()
+	d := t.notBefore.Sub(t.now())
+	t.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	if d > maxRateLimitWait {
+		d = maxRateLimitWait
+	}
+	ui.Warnf(ctx, "Rekor rate limit reached, waiting %s before the next request", d.Round(time.Second))
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reset returns when the rate limit that resp reports as exhausted resets.
+func (t *rateLimitTransport) reset(resp *http.Response) (time.Time, bool) {
+	now := t.now()
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if v := resp.Header.Get("Retry-After"); v != "" {
+			if s, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return now.Add(time.Duration(s) * time.Second), true
+			}
+			if at, err := http.ParseTime(v); err == nil {
+				return at, true
+			}
+		}
+	}
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		if resp.Header.Get(prefix+"Remaining") != "0" {
+			continue
+		}
+		s, err := strconv.ParseInt(resp.Header.Get(prefix+"Reset"), 10, 64)
+		if err != nil {
+			continue
+		}
+		// The IETF draft sends the seconds until the reset, older servers
+		// send the reset as a Unix time.
+		if s > now.Unix()/2 {
+			return time.Unix(s, 0), true
+		}
+		return now.Add(time.Duration(s) * time.Second), true
+	}
+	return time.Time{}, false
+}
+
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
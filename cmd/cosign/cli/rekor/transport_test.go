@@ -0,0 +1,230 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLookupTransport(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if strings.HasSuffix(r.URL.Path, "/slow") {
+				<-release
+			}
+			if strings.HasSuffix(r.URL.Path, "/missing") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			b, _ := io.ReadAll(r.Body)
+			_, _ = w.Write(append([]byte(r.URL.Path+":"), b...))
+		}))
+	defer testServer.Close()
+
+	hc := &http.Client{Transport: newLookupTransport(http.DefaultTransport)}
+	get := func(t *testing.T, method, path, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, testServer.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := hc.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(b)
+	}
+	count := func(t *testing.T, want int32) {
+		t.Helper()
+		if got := atomic.SwapInt32(&requests, 0); got != want {
+			t.Errorf("server got %d requests, want %d", got, want)
+		}
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if _, body := get(t, http.MethodGet, "/api/v1/log/entries/abc", ""); body != "/api/v1/log/entries/abc:" {
+				t.Errorf("got body %q", body)
+			}
+		}
+		count(t, 1)
+	})
+
+	t.Run("keyed by body", func(t *testing.T) {
+		for _, body := range []string{`{"hash":"a"}`, `{"hash":"b"}`, `{"hash":"a"}`} {
+			if _, got := get(t, http.MethodPost, "/api/v1/index/retrieve", body); got != "/api/v1/index/retrieve:"+body {
+				t.Errorf("got body %q", got)
+			}
+		}
+		count(t, 2)
+	})
+
+	t.Run("not a lookup", func(t *testing.T) {
+		get(t, http.MethodGet, "/api/v1/log", "")
+		get(t, http.MethodGet, "/api/v1/log", "")
+		get(t, http.MethodPost, "/api/v1/log/entries", "{}")
+		get(t, http.MethodPost, "/api/v1/log/entries", "{}")
+		count(t, 4)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if code, _ := get(t, http.MethodGet, "/api/v1/log/entries/missing", ""); code != http.StatusNotFound {
+				t.Errorf("got status %d", code)
+			}
+		}
+		count(t, 2)
+	})
+
+	t.Run("coalesced", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, body := get(t, http.MethodGet, "/api/v1/log/entries/slow", ""); body != "/api/v1/log/entries/slow:" {
+					t.Errorf("got body %q", body)
+				}
+			}()
+		}
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		count(t, 1)
+	})
+}
+
+func TestLookupTransportEvicts(t *testing.T) {
+	tr := newLookupTransport(nil)
+	for i := 0; i <= lookupCacheSize; i++ {
+		tr.add(&cachedResponse{key: strconv.Itoa(i)})
+	}
+	if tr.get("0") != nil {
+		t.Error("oldest entry was not evicted")
+	}
+	if tr.get("1") == nil || tr.get(strconv.Itoa(lookupCacheSize)) == nil {
+		t.Error("newer entries were evicted")
+	}
+	if len(tr.entries) != lookupCacheSize {
+		t.Errorf("got %d entries, want %d", len(tr.entries), lookupCacheSize)
+	}
+}
+
+func TestRateLimitReset(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tr := &rateLimitTransport{now: func() time.Time { return now }}
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		want   time.Time
+	}{{
+		name:   "retry after seconds",
+		status: http.StatusTooManyRequests,
+		header: map[string]string{"Retry-After": "7"},
+		want:   now.Add(7 * time.Second),
+	}, {
+		name:   "retry after date",
+		status: http.StatusServiceUnavailable,
+		header: map[string]string{"Retry-After": now.Add(time.Minute).UTC().Format(http.TimeFormat)},
+		want:   now.Add(time.Minute),
+	}, {
+		name:   "retry after on success",
+		status: http.StatusOK,
+		header: map[string]string{"Retry-After": "7"},
+	}, {
+		name:   "remaining seconds",
+		status: http.StatusOK,
+		header: map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "30"},
+		want:   now.Add(30 * time.Second),
+	}, {
+		name:   "remaining unix time",
+		status: http.StatusOK,
+		header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000042"},
+		want:   now.Add(42 * time.Second),
+	}, {
+		name:   "not exhausted",
+		status: http.StatusOK,
+		header: map[string]string{"RateLimit-Remaining": "3", "RateLimit-Reset": "30"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+			got, ok := tr.reset(resp)
+			if ok != !tt.want.IsZero() || !got.Equal(tt.want) {
+				t.Errorf("reset() = %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	testServer := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			times = append(times, time.Now())
+			first := len(times) == 1
+			mu.Unlock()
+			if first {
+				w.Header().Set("RateLimit-Remaining", "0")
+				w.Header().Set("RateLimit-Reset", "1")
+			}
+		}))
+	defer testServer.Close()
+
+	hc := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport)}
+	for i := 0; i < 2; i++ {
+		resp, err := hc.Get(testServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if d := times[1].Sub(times[0]); d < 900*time.Millisecond {
+		t.Errorf("second request was sent after %s, want it to wait for the reset", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tr := newRateLimitTransport(http.DefaultTransport)
+	tr.notBefore = time.Now().Add(time.Hour)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, testServer.URL, nil)
+	if _, err := tr.RoundTrip(req); err == nil {
+		t.Error("expected the canceled request to fail")
+	}
+}
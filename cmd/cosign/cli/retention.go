@@ -0,0 +1,240 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/pkg/now"
+	"github.com/sigstore/cosign/v2/internal/pkg/retention"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// RetentionReport is the report of the signatures and attestations that
+// cosign retention apply removed from a repository, or would have in a dry
+// run.
+type RetentionReport struct {
+	Repository string             `json:"repository"`
+	AppliedAt  time.Time          `json:"appliedAt"`
+	DryRun     bool               `json:"dryRun"`
+	Removed    []RetentionRemoval `json:"removed"`
+	Kept       int                `json:"kept"`
+}
+
+// RetentionRemoval is a signature or attestation that the retention policy
+// doesn't keep.
+type RetentionRemoval struct {
+	Image       string     `json:"image"`
+	Tag         string     `json:"tag"`
+	Attestation bool       `json:"attestation,omitempty"`
+	Entry       string     `json:"entry"`
+	SignedAt    *time.Time `json:"signedAt,omitempty"`
+	Reason      string     `json:"reason"`
+}
+
+func Retention() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Provides utilities for applying retention policies to signatures and attestations",
+	}
+
+	cmd.AddCommand(retentionApply())
+
+	return cmd
+}
+
+func retentionApply() *cobra.Command {
+	o := &options.RetentionApplyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Remove the signatures and attestations of a repository that a retention policy doesn't keep",
+		Long: `Remove the signatures and attestations stored in the tags of a repository that
+the retention policy file doesn't keep. The policy sets, for the signatures
+and for the attestations, how many of the most recently signed tags keep
+theirs and how long entries are kept after they were signed:
+
+  {
+    "signatures": {"keepSignedTags": 10},
+    "attestations": {"maxAge": "365d"}
+  }
+
+A tag is signed when its digest has a signature tag, and was signed at the
+newest signing time of its signatures: the transparency log integration time,
+or else the start of the certificate's validity. Entries without a signing
+time are never removed for their age, and tags without one are the least
+recently signed. Signatures and attestations attached as referrers are not
+considered.`,
+		Example: `  cosign retention apply --policy retention.json <REPOSITORY>
+
+  # list what the policy would remove and write it to a report, without removing anything
+  cosign retention apply --policy retention.json --dry-run --report retention-report.json <REPOSITORY>`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RetentionApplyCmd(cmd.Context(), *o, args[0], cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// RetentionApplyCmd removes the signatures and attestations of repo that the
+// retention policy of o doesn't keep. With o.DryRun, it writes what it would
+// remove to out instead.
+func RetentionApplyCmd(ctx context.Context, o options.RetentionApplyOptions, repo string, out io.Writer) error {
+	policy, err := retention.Load(o.Policy)
+	if err != nil {
+		return err
+	}
+	r, err := name.NewRepository(repo, o.Registry.NameOptions()...)
+	if err != nil {
+		return err
+	}
+	appliedAt, err := now.Now()
+	if err != nil {
+		return err
+	}
+	c, err := newCleaner(ctx, o.Registry, CleanOpts{DryRun: o.DryRun, Force: o.Force}, out)
+	if err != nil {
+		return err
+	}
+
+	list, err := remote.List(r, c.remoteOpts...)
+	if err != nil {
+		return fmt.Errorf("listing tags in %s: %w", r, err)
+	}
+	sort.Strings(list)
+
+	tags := map[string]string{}
+	var attachments []retention.Attachment
+	var entries [][]oci.Signature
+	for _, t := range list {
+		h, attestation, ok := parseAttachmentTag(t, o.Registry.RefOpts.TagPrefix)
+		if !ok {
+			if policy.KeepsSignedTags() {
+				d, err := ociremote.ResolveDigest(r.Tag(t), c.ociremoteOpts...)
+				if err != nil {
+					return fmt.Errorf("resolving %s: %w", r.Tag(t), err)
+				}
+				tags[t] = d.DigestStr()
+			}
+			continue
+		}
+		sigList, err := ociremote.Signatures(r.Tag(t), c.ociremoteOpts...)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", r.Tag(t), err)
+		}
+		all, err := sigList.Get()
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", r.Tag(t), err)
+		}
+		a := retention.Attachment{Tag: t, Digest: h.String(), Attestation: attestation}
+		for _, sig := range all {
+			d, err := sig.Digest()
+			if err != nil {
+				return err
+			}
+			signedAt, err := signingTime(sig)
+			if err != nil {
+				return err
+			}
+			a.Entries = append(a.Entries, retention.Entry{Digest: d.String(), SignedAt: signedAt})
+		}
+		attachments = append(attachments, a)
+		entries = append(entries, all)
+	}
+
+	report := RetentionReport{Repository: r.String(), AppliedAt: appliedAt.UTC(), DryRun: o.DryRun, Removed: []RetentionRemoval{}}
+	removals := policy.Select(appliedAt, tags, attachments)
+	for i, a := range attachments {
+		for _, rm := range removals[i] {
+			e := a.Entries[rm.Index]
+			report.Removed = append(report.Removed, RetentionRemoval{
+				Image:       r.Digest(a.Digest).String(),
+				Tag:         r.Tag(a.Tag).String(),
+				Attestation: a.Attestation,
+				Entry:       e.Digest,
+				SignedAt:    e.SignedAt,
+				Reason:      rm.Reason,
+			})
+		}
+		report.Kept += len(a.Entries) - len(removals[i])
+	}
+
+	if len(report.Removed) == 0 {
+		fmt.Fprintf(os.Stderr, "Nothing to remove from %s\n", r)
+	} else if !o.DryRun && !o.Force {
+		ui.Warnf(ctx, "this will remove %d signatures and attestations from %s", len(report.Removed), r)
+		if err := ui.ConfirmContinue(ctx); err != nil {
+			return err
+		}
+	}
+
+	for i, a := range attachments {
+		if len(removals[i]) == 0 {
+			continue
+		}
+		tag := r.Tag(a.Tag)
+		if o.DryRun {
+			fmt.Fprintf(out, "Would remove %d of %d entries from %s:\n", len(removals[i]), len(a.Entries), tag)
+			for _, rm := range removals[i] {
+				fmt.Fprintf(out, "  %s (%s)\n", a.Entries[rm.Index].Digest, rm.Reason)
+			}
+			continue
+		}
+		removed := map[int]bool{}
+		for _, rm := range removals[i] {
+			removed[rm.Index] = true
+		}
+		var kept []oci.Signature
+		for j, sig := range entries[i] {
+			if !removed[j] {
+				kept = append(kept, sig)
+			}
+		}
+		if err := c.replaceEntries(tag, kept); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Removed %d of %d entries from %s\n", len(removals[i]), len(a.Entries), tag)
+	}
+
+	if o.Report == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(o.Report, b, 0600); err != nil {
+		return fmt.Errorf("writing retention report: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Retention report written to %s\n", o.Report)
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestRetentionApplyCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	repo := host + "/app"
+
+	signedAt := func(days int) static.Option {
+		return static.WithBundle(&bundle.RekorBundle{Payload: bundle.RekorPayload{
+			IntegratedTime: time.Now().AddDate(0, 0, -days).Unix(),
+		}})
+	}
+	write := func(tag name.Tag, entries ...oci.Signature) {
+		t.Helper()
+		sigs, err := mutate.AppendSignatures(empty.Signatures(), entries...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(tag, sigs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// push pushes an image as tag, signed days ago, and returns its
+	// signature and attestation tags.
+	push := func(tag string, days int) (name.Tag, name.Tag) {
+		t.Helper()
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := name.NewTag(repo + ":" + tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(ref, img); err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := static.NewSignature([]byte(tag), "c2lnbmF0dXJl", signedAt(days))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigTag, err := ociremote.SignatureTag(ref.Context().Digest(h.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		write(sigTag, sig)
+		attTag, err := ociremote.AttestationTag(ref.Context().Digest(h.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sigTag, attTag
+	}
+	oldSig, _ := push("v1", 400)
+	newSig, attTag := push("v2", 10)
+	var atts []oci.Signature
+	for _, days := range []int{400, 10} {
+		att, err := static.NewAttestation([]byte(`{"payloadType":"application/vnd.in-toto+json"}`), signedAt(days))
+		if err != nil {
+			t.Fatal(err)
+		}
+		atts = append(atts, att)
+	}
+	write(attTag, atts...)
+
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "retention.json")
+	if err := os.WriteFile(policy, []byte(`{"signatures": {"keepSignedTags": 1}, "attestations": {"maxAge": "365d"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "report.json")
+	o := options.RetentionApplyOptions{Policy: policy, DryRun: true, Report: report}
+
+	var out bytes.Buffer
+	if err := RetentionApplyCmd(ctx, o, repo, &out); err != nil {
+		t.Fatalf("RetentionApplyCmd() dry run = %v", err)
+	}
+	for _, want := range []string{
+		"Would remove 1 of 1 entries from " + oldSig.String() + ":\n",
+		"not attached to any of the 1 most recently signed tags",
+		"Would remove 1 of 2 entries from " + attTag.String() + ":\n",
+		"more than 365d ago",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry run output = %q, want it to contain %q", out.String(), want)
+		}
+	}
+	if _, err := remote.Head(oldSig); err != nil {
+		t.Errorf("dry run removed %s: %v", oldSig, err)
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r RetentionReport
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.DryRun || r.Repository != repo || len(r.Removed) != 2 || r.Kept != 2 {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	for _, rm := range r.Removed {
+		if rm.Attestation && (rm.Tag != attTag.String() || rm.SignedAt == nil) {
+			t.Errorf("attestation removal = %+v, want the old attestation of %s", rm, attTag)
+		}
+	}
+
+	o.DryRun = false
+	o.Force = true
+	if err := RetentionApplyCmd(ctx, o, repo, &out); err != nil {
+		t.Fatalf("RetentionApplyCmd() = %v", err)
+	}
+	if _, err := remote.Head(oldSig); err == nil {
+		t.Errorf("expected %s to be deleted", oldSig)
+	}
+	if _, err := remote.Head(newSig); err != nil {
+		t.Errorf("expected %s to be kept: %v", newSig, err)
+	}
+	remaining, err := ociremote.Signatures(attTag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, _ := remaining.Get(); len(l) != 1 {
+		t.Errorf("%d attestations remaining, want 1", len(l))
+	}
+}
@@ -0,0 +1,333 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/rekor/pkg/generated/client/index"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
+	"github.com/sigstore/cosign/v2/internal/pkg/now"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/cosign/v2/pkg/types"
+)
+
+// KeyRevocationPredicateType is the predicate type of the attestations
+// generated by cosign revoke-key.
+const KeyRevocationPredicateType = "https://sigstore.dev/cosign/key-revocation/v1"
+
+// KeyRevocation is the predicate of a key revocation attestation. Its
+// subjects are the artifacts that were signed with the revoked key.
+type KeyRevocation struct {
+	KeyFingerprint string            `json:"keyFingerprint"`
+	Reason         string            `json:"reason,omitempty"`
+	RevokedAt      time.Time         `json:"revokedAt"`
+	Artifacts      []RevokedArtifact `json:"artifacts"`
+	RekorEntries   []string          `json:"rekorEntries,omitempty"`
+	Removed        bool              `json:"removed"`
+}
+
+// RevokedArtifact is an artifact with signatures or attestations made with
+// the revoked key.
+type RevokedArtifact struct {
+	Image        string `json:"image"`
+	Signatures   int    `json:"signatures,omitempty"`
+	Attestations int    `json:"attestations,omitempty"`
+
+	// repo and digest are those of Image, as parsed from its attachment tags.
+	repo   name.Repository
+	digest v1.Hash
+}
+
+func RevokeKey() *cobra.Command {
+	o := &options.RevokeKeyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "revoke-key",
+		Short: "Find and revoke the signatures made with a compromised key",
+		Long: `Find the signatures and attestations made with a compromised key in the given
+repositories and the transparency log entries made with it, and generate a
+revocation attestation listing them.
+
+With --remove, the signatures and attestations made with the key are also
+removed from the registry. Those made by other signers are kept.`,
+		Example: `  cosign revoke-key --key compromised.pub --repository example.com/app --repository example.com/tools
+
+  # remove the signatures and sign the revocation attestation with a new key
+  cosign revoke-key --key compromised.pub --repository example.com/app --remove --signing-key cosign.key --output-file revocation.json`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RevokeKeyCmd(cmd.Context(), *o)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// attachmentTag is a signature or attestation tag holding entries made with
+// the revoked key.
+type attachmentTag struct {
+	tag         name.Tag
+	attestation bool
+}
+
+// RevokeKeyCmd finds the artifacts and transparency log entries made with
+// the key in o, optionally removes the signatures and attestations from the
+// registry, and writes a revocation attestation.
+func RevokeKeyCmd(ctx context.Context, o options.RevokeKeyOptions) error {
+	m, err := NewSignerMatcher(ctx, o.Key, "", "")
+	if err != nil {
+		return err
+	}
+	if m.verifier == nil {
+		return errors.New("--key must reference the public key, not its fingerprint")
+	}
+
+	artifacts, tags, err := findSignedBy(ctx, o.Registry, o.Repositories, m)
+	if err != nil {
+		return err
+	}
+
+	var entries []string
+	if o.RekorSearch {
+		rekorClient, err := rekor.NewClient(o.Rekor.URL)
+		if err != nil {
+			return fmt.Errorf("creating Rekor client: %w", err)
+		}
+		pub, err := m.verifier.PublicKey()
+		if err != nil {
+			return err
+		}
+		pemBytes, err := cryptoutils.MarshalPublicKeyToPEM(pub)
+		if err != nil {
+			return err
+		}
+		params := index.NewSearchIndexParamsWithContext(ctx)
+		params.Query = &models.SearchIndex{
+			PublicKey: &models.SearchIndexPublicKey{
+				Format:  swag.String(models.SearchIndexPublicKeyFormatX509),
+				Content: strfmt.Base64(pemBytes),
+			},
+		}
+		resp, err := rekorClient.Index.SearchIndex(params)
+		if err != nil {
+			return fmt.Errorf("searching %s for entries made with the key: %w", o.Rekor.URL, err)
+		}
+		entries = resp.Payload
+		sort.Strings(entries)
+		fmt.Fprintf(os.Stderr, "Found %d transparency log entries made with %s in %s\n", len(entries), m, o.Rekor.URL)
+	}
+
+	removed := false
+	if o.Remove && len(tags) > 0 {
+		if !o.Force {
+			ui.Warnf(ctx, "this will remove the signatures and attestations made by %s from %d artifacts", m, len(artifacts))
+			if err := ui.ConfirmContinue(ctx); err != nil {
+				return err
+			}
+		}
+		c, err := newCleaner(ctx, o.Registry, CleanOpts{SignedBy: m}, os.Stdout)
+		if err != nil {
+			return err
+		}
+		for _, t := range tags {
+			if _, _, err := c.removeSignedBy(ctx, t.tag, t.attestation); err != nil {
+				return err
+			}
+		}
+		removed = true
+	}
+
+	revokedAt, err := now.Now()
+	if err != nil {
+		return err
+	}
+	st := in_toto.Statement{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          in_toto.StatementInTotoV01,
+			PredicateType: KeyRevocationPredicateType,
+		},
+		Predicate: KeyRevocation{
+			KeyFingerprint: m.fingerprint,
+			Reason:         o.Reason,
+			RevokedAt:      revokedAt.UTC(),
+			Artifacts:      artifacts,
+			RekorEntries:   entries,
+			Removed:        removed,
+		},
+	}
+	for _, a := range artifacts {
+		st.Subject = append(st.Subject, in_toto.Subject{
+			Name:   a.repo.String(),
+			Digest: map[string]string{a.digest.Algorithm: a.digest.Hex},
+		})
+	}
+	out, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	if o.SigningKey != "" {
+		sv, err := sigs.SignerVerifierFromKeyRef(ctx, o.SigningKey, generate.GetPass)
+		if err != nil {
+			return fmt.Errorf("loading signing key: %w", err)
+		}
+		wrapped := dsse.WrapSigner(sv, types.IntotoPayloadType)
+		if out, err = wrapped.SignMessage(bytes.NewReader(out), signatureoptions.WithContext(ctx)); err != nil {
+			return fmt.Errorf("signing revocation attestation: %w", err)
+		}
+	}
+
+	if o.OutputFile == "" {
+		fmt.Println(string(out))
+		return nil
+	}
+	if err := os.WriteFile(o.OutputFile, out, 0600); err != nil {
+		return fmt.Errorf("writing revocation attestation: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Revocation attestation written to %s\n", o.OutputFile)
+	return nil
+}
+
+// findSignedBy lists the signature and attestation tags in repos and returns
+// the artifacts with entries that m matches, along with the tags holding them.
+func findSignedBy(ctx context.Context, regOpts options.RegistryOptions, repos []string, m *SignerMatcher) ([]RevokedArtifact, []attachmentTag, error) {
+	remoteOpts := regOpts.GetRegistryClientOpts(ctx)
+	ociremoteOpts, err := regOpts.ClientOpts(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var artifacts []RevokedArtifact
+	var tags []attachmentTag
+	for _, repo := range repos {
+		r, err := name.NewRepository(repo, regOpts.NameOptions()...)
+		if err != nil {
+			return nil, nil, err
+		}
+		list, err := remote.List(r, remoteOpts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing tags in %s: %w", r, err)
+		}
+		sort.Strings(list)
+
+		found := map[string]*RevokedArtifact{}
+		var order []string
+		for _, t := range list {
+			h, attestation, ok := parseAttachmentTag(t, regOpts.RefOpts.TagPrefix)
+			if !ok {
+				continue
+			}
+			tag := r.Tag(t)
+			matched, err := countSignedBy(ctx, tag, attestation, m, ociremoteOpts)
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading %s: %w", tag, err)
+			}
+			if matched == 0 {
+				continue
+			}
+			a, ok := found[h.String()]
+			if !ok {
+				a = &RevokedArtifact{Image: r.Digest(h.String()).String(), repo: r, digest: h}
+				found[h.String()] = a
+				order = append(order, h.String())
+			}
+			if attestation {
+				a.Attestations += matched
+			} else {
+				a.Signatures += matched
+			}
+			tags = append(tags, attachmentTag{tag: tag, attestation: attestation})
+		}
+		for _, h := range order {
+			a := found[h]
+			fmt.Fprintf(os.Stderr, "Found %d signature(s) and %d attestation(s) made with %s on %s\n", a.Signatures, a.Attestations, m, a.Image)
+			artifacts = append(artifacts, *a)
+		}
+	}
+	return artifacts, tags, nil
+}
+
+// parseAttachmentTag returns the digest of the artifact a signature or
+// attestation tag such as [prefix]sha256-<hex>.sig is attached to.
+func parseAttachmentTag(tag, prefix string) (v1.Hash, bool, bool) {
+	if !strings.HasPrefix(tag, prefix) {
+		return v1.Hash{}, false, false
+	}
+	base, suffix, ok := strings.Cut(strings.TrimPrefix(tag, prefix), ".")
+	if !ok || (suffix != ociremote.SignatureTagSuffix && suffix != ociremote.AttestationTagSuffix) {
+		return v1.Hash{}, false, false
+	}
+	alg, hex, ok := strings.Cut(base, "-")
+	if !ok {
+		return v1.Hash{}, false, false
+	}
+	h, err := oci.ParseDigest(alg + ":" + hex)
+	if err != nil {
+		return v1.Hash{}, false, false
+	}
+	return h, suffix == ociremote.AttestationTagSuffix, true
+}
+
+// countSignedBy returns the number of entries in tag that m matches.
+func countSignedBy(ctx context.Context, tag name.Tag, attestation bool, m *SignerMatcher, ociremoteOpts []ociremote.Option) (int, error) {
+	sigList, err := ociremote.Signatures(tag, ociremoteOpts...)
+	if err != nil {
+		return 0, err
+	}
+	all, err := sigList.Get()
+	if err != nil {
+		return 0, err
+	}
+	matched := 0
+	for _, sig := range all {
+		match, err := m.Match(ctx, sig, attestation)
+		if err != nil {
+			return 0, err
+		}
+		if match {
+			matched++
+		}
+	}
+	return matched, nil
+}
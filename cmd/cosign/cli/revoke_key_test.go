@@ -0,0 +1,227 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+func TestRevokeKeyCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	compromisedSigner, compromisedKey := newTestKey(t)
+	otherSigner, _ := newTestKey(t)
+	payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + h.String() + `"}}}`)
+
+	write := func(tag name.Tag, entries ...oci.Signature) {
+		t.Helper()
+		sigs, err := mutate.AppendSignatures(empty.Signatures(), entries...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(tag, sigs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sigTag, err := ociremote.SignatureTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(sigTag, signPayload(t, compromisedSigner, payload), signPayload(t, otherSigner, payload))
+
+	envelope, err := dsse.WrapSigner(compromisedSigner, types.IntotoPayloadType).SignMessage(bytes.NewReader([]byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	att, err := static.NewAttestation(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attTag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(attTag, att)
+
+	// Another image is signed under its sha512 digest.
+	img512, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := img512.RawManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h512, err := oci.ComputeDigest("sha512", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref512, err := oci.ParseDigestReference(host + "/app@" + h512.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref512, img512); err != nil {
+		t.Fatal(err)
+	}
+	sigTag512, err := ociremote.SignatureTag(ref512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(sigTag512, signPayload(t, compromisedSigner, []byte(`{"critical":{"image":{"docker-manifest-digest":"`+h512.String()+`"}}}`)))
+
+	// A key to sign the revocation attestation with.
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte("password"), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	signingKey := filepath.Join(dir, "cosign.key")
+	if err := os.WriteFile(signingKey, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("COSIGN_PASSWORD", "password")
+
+	output := filepath.Join(dir, "revocation.json")
+	err = RevokeKeyCmd(ctx, options.RevokeKeyOptions{
+		Key:          compromisedKey,
+		Repositories: []string{host + "/app"},
+		Reason:       "key leaked",
+		SigningKey:   signingKey,
+		OutputFile:   output,
+		Remove:       true,
+		Force:        true,
+	})
+	if err != nil {
+		t.Fatalf("RevokeKeyCmd() = %v", err)
+	}
+
+	// Only the other signer's signature is left, and the attestation tag
+	// is gone.
+	remaining, err := ociremote.Signatures(sigTag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, _ := remaining.Get(); len(l) != 1 {
+		t.Errorf("%d signatures remaining, want 1", len(l))
+	}
+	if _, err := remote.Head(attTag); err == nil {
+		t.Error("expected the attestation tag to be deleted")
+	}
+
+	b, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env struct {
+		PayloadType string `json:"payloadType"`
+		Payload     string `json:"payload"`
+	}
+	if err := json.Unmarshal(b, &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.PayloadType != types.IntotoPayloadType {
+		t.Errorf("payloadType = %q", env.PayloadType)
+	}
+	st, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statement struct {
+		PredicateType string `json:"predicateType"`
+		Subject       []struct {
+			Name   string            `json:"name"`
+			Digest map[string]string `json:"digest"`
+		} `json:"subject"`
+		Predicate KeyRevocation `json:"predicate"`
+	}
+	if err := json.Unmarshal(st, &statement); err != nil {
+		t.Fatal(err)
+	}
+	if statement.PredicateType != KeyRevocationPredicateType {
+		t.Errorf("predicateType = %q", statement.PredicateType)
+	}
+	if len(statement.Subject) != 2 || statement.Subject[0].Digest["sha256"] != h.Hex || statement.Subject[1].Digest["sha512"] != h512.Hex ||
+		statement.Subject[1].Name != host+"/app" {
+		t.Errorf("subjects = %+v, want %s and %s", statement.Subject, h, h512)
+	}
+	want := []RevokedArtifact{{Image: ref.String(), Signatures: 1, Attestations: 1}, {Image: ref512.String(), Signatures: 1}}
+	if got := statement.Predicate.Artifacts; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("artifacts = %+v, want %+v", got, want)
+	}
+	if !statement.Predicate.Removed || statement.Predicate.Reason != "key leaked" || !strings.HasPrefix(statement.Predicate.KeyFingerprint, "sha256:") {
+		t.Errorf("unexpected predicate: %+v", statement.Predicate)
+	}
+}
+
+func TestParseAttachmentTag(t *testing.T) {
+	hex := strings.Repeat("a", 64)
+	for tag, want := range map[string]bool{
+		"sha256-" + hex + ".sig":        true,
+		"sha256-" + hex + ".att":        true,
+		"prefix-sha256-" + hex + ".sig": false,
+		"sha256-" + hex + ".sbom":       false,
+		"latest":                        false,
+		"sha256-abc.sig":                false,
+	} {
+		if _, _, ok := parseAttachmentTag(tag, ""); ok != want {
+			t.Errorf("parseAttachmentTag(%q) = %t, want %t", tag, ok, want)
+		}
+	}
+	if _, att, ok := parseAttachmentTag("prefix-sha256-"+hex+".att", "prefix-"); !ok || !att {
+		t.Error("expected a prefixed attestation tag to be parsed")
+	}
+}
@@ -20,7 +20,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/layout"
@@ -48,7 +47,7 @@ func Save() *cobra.Command {
 }
 
 func SaveCmd(_ context.Context, opts options.SaveOptions, imageRef string) error {
-	ref, err := name.ParseReference(imageRef)
+	ref, err := oci.ParseReference(imageRef)
 	if err != nil {
 		return fmt.Errorf("parsing image name %s: %w", imageRef, err)
 	}
@@ -58,20 +57,31 @@ func SaveCmd(_ context.Context, opts options.SaveOptions, imageRef string) error
 		return fmt.Errorf("signed entity: %w", err)
 	}
 
-	if _, ok := se.(oci.SignedImage); ok {
+	switch se.(type) {
+	case oci.SignedImage:
 		si, err := ociremote.SignedImage(ref)
 		if err != nil {
 			return fmt.Errorf("getting signed image: %w", err)
 		}
-		return layout.WriteSignedImage(opts.Directory, si)
-	}
-
-	if _, ok := se.(oci.SignedImageIndex); ok {
+		if err := layout.WriteSignedImage(opts.Directory, si); err != nil {
+			return err
+		}
+	case oci.SignedImageIndex:
 		sii, err := ociremote.SignedImageIndex(ref)
 		if err != nil {
 			return fmt.Errorf("getting signed image index: %w", err)
 		}
-		return layout.WriteSignedImageIndex(opts.Directory, sii)
+		if err := layout.WriteSignedImageIndex(opts.Directory, sii); err != nil {
+			return err
+		}
+	default:
+		return errors.New("unknown signed entity")
+	}
+
+	// Check what was written, so a corrupted transfer fails the save rather
+	// than a later load.
+	if err := layout.VerifyDigests(opts.Directory); err != nil {
+		return fmt.Errorf("verifying %s: %w", opts.Directory, err)
 	}
-	return errors.New("unknown signed entity")
+	return nil
 }
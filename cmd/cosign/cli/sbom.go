@@ -0,0 +1,141 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sbom"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func SBOM() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom",
+		Short: "Provides utilities for discovering images in and performing operations on SBOMs",
+	}
+
+	cmd.AddCommand(
+		sbomVerify(),
+	)
+
+	return cmd
+}
+
+func sbomVerify() *cobra.Command {
+	o := &options.VerifyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify all signatures of container images referenced in the SBOM",
+		Long: `Verify all signatures of container images referenced in an SPDX or CycloneDX
+JSON SBOM by checking claims against the transparency log.
+
+Images are discovered from pkg:oci and pkg:docker package URLs (purls) in the
+SBOM's packages or components; all other package types are ignored.`,
+		Example: `  cosign sbom verify --key <key path>|<key url>|<kms uri> <path/to/sbom.json>
+
+  # verify cosign claims and signing certificates on images in the SBOM
+  cosign sbom verify --certificate-identity=name@example.com \
+    --certificate-oidc-issuer=https://accounts.example.com <path/to/sbom.spdx.json>
+
+  # additionally verify specified annotations
+  cosign sbom verify -a key1=val1 -a key2=val2 <path/to/sbom.cdx.json>
+
+  # verify images with public key
+  cosign sbom verify --key cosign.pub <path/to/sbom.spdx.json>
+
+  # verify images with public key stored in Google Cloud KMS
+  cosign sbom verify --key gcpkms://projects/[PROJECT]/locations/global/keyRings/[KEYRING]/cryptoKeys/[KEY] <path/to/sbom.spdx.json>`,
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			annotations, err := o.AnnotationsMap()
+			if err != nil {
+				return err
+			}
+			signReportKeyOpts, err := o.SignReportKeyOpts()
+			if err != nil {
+				return err
+			}
+			hashAlgorithm, err := o.SignatureDigest.HashAlgorithm()
+			if err != nil {
+				return err
+			}
+			v := &sbom.VerifySBOMCommand{
+				VerifyCommand: verify.VerifyCommand{
+					RegistryOptions:              o.Registry,
+					CertVerifyOptions:            o.CertVerify,
+					CheckClaims:                  o.CheckClaims,
+					KeyRef:                       o.Key,
+					CertRef:                      o.CertVerify.Cert,
+					CertGithubWorkflowTrigger:    o.CertVerify.CertGithubWorkflowTrigger,
+					CertGithubWorkflowSha:        o.CertVerify.CertGithubWorkflowSha,
+					CertGithubWorkflowName:       o.CertVerify.CertGithubWorkflowName,
+					CertGithubWorkflowRepository: o.CertVerify.CertGithubWorkflowRepository,
+					CertGithubWorkflowRef:        o.CertVerify.CertGithubWorkflowRef,
+					CertChain:                    o.CertVerify.CertChain,
+					IgnoreSCT:                    o.CertVerify.IgnoreSCT,
+					SCTRef:                       o.CertVerify.SCT,
+					Sk:                           o.SecurityKey.Use,
+					Slot:                         o.SecurityKey.Slot,
+					Output:                       o.Output,
+					OutputTemplate:               o.OutputTemplate.Template,
+					GitHubSummary:                o.GitHubSummary,
+					RekorURL:                     o.Rekor.URL,
+					Attachment:                   o.Attachment,
+					Annotations:                  annotations,
+					HashAlgorithm:                hashAlgorithm,
+					Offline:                      o.CommonVerifyOptions.Offline,
+					TSACertChainPaths:            o.CommonVerifyOptions.TSACertChainPaths,
+					IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+					Denylist:                     o.CommonVerifyOptions.Denylist,
+					KeyUsagePolicy:               o.CommonVerifyOptions.KeyUsagePolicy,
+					Witnesses:                    o.CommonVerifyOptions.Witnesses,
+					WarningsAsErrors:             o.WarningsAsErrors,
+					SignReport:                   o.SignReport,
+					SignReportKeyOpts:            signReportKeyOpts,
+					SignReportCertificate:        o.SignReportCertificate,
+					SourceRepositories:           o.SourceRepositories,
+					Batch:                        o.Batch,
+					AllowConverted:               o.AllowConverted,
+					Recursive:                    o.Recursive,
+					LayerPolicy:                  o.LayerPolicy,
+					Quota:                        o.Quota,
+					EnforceExpiry:                o.EnforceExpiry,
+					Encryption:                   o.Encryption,
+					Countersigners:               o.Countersigners,
+					Cache:                        o.Cache,
+					Evaluation:                   o.Evaluation,
+				},
+			}
+			if o.Registry.AllowInsecure {
+				v.NameOptions = append(v.NameOptions, name.Insecure)
+			}
+			if err := setImagePolicy(&v.VerifyCommand, o); err != nil {
+				return err
+			}
+			return withFailureReport(cmd, args, o.CommonVerifyOptions.FailureReport, func() error {
+				return v.Exec(cmd.Context(), args)
+			})
+		},
+	}
+
+	o.AddFlags(cmd)
+
+	return cmd
+}
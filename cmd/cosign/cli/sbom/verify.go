@@ -0,0 +1,226 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sbom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+// VerifySBOMCommand verifies all image signatures referenced by a supplied SBOM
+type VerifySBOMCommand struct {
+	verify.VerifyCommand
+}
+
+// Exec runs the verification command
+func (c *VerifySBOMCommand) Exec(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	sbom, err := os.ReadFile(filepath.Clean(args[0]))
+	if err != nil {
+		return fmt.Errorf("could not read SBOM: %w", err)
+	}
+
+	images, err := getImagesFromSBOM(ctx, sbom)
+	if err != nil {
+		return fmt.Errorf("unable to extract the container image references in the SBOM: %w", err)
+	}
+	if len(images) == 0 {
+		return errors.New("no container images found in SBOM")
+	}
+	fmt.Fprintf(os.Stderr, "Extracted image(s): %s\n", strings.Join(images, ", "))
+
+	return c.VerifyCommand.Exec(ctx, images)
+}
+
+// spdxDocument filters an SPDX JSON document down to the package purls.
+type spdxDocument struct {
+	SPDXVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		ExternalRefs []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+// cyclonedxComponent is the subset of a CycloneDX component we care about.
+// Components may nest arbitrarily.
+type cyclonedxComponent struct {
+	Purl       string               `json:"purl"`
+	Components []cyclonedxComponent `json:"components"`
+}
+
+// cyclonedxDocument filters a CycloneDX JSON document down to the component purls.
+type cyclonedxDocument struct {
+	BOMFormat string `json:"bomFormat"`
+	Metadata  struct {
+		Component *cyclonedxComponent `json:"component"`
+	} `json:"metadata"`
+	Components []cyclonedxComponent `json:"components"`
+}
+
+func getImagesFromSBOM(ctx context.Context, sbom []byte) ([]string, error) {
+	purls, err := getPurlsFromSBOM(sbom)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+	var images []string
+	for _, p := range purls {
+		image, ok, err := imageFromPurl(p)
+		if err != nil {
+			ui.Warnf(ctx, "skipping malformed purl %q: %v", p, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if _, dup := seen[image]; dup {
+			continue
+		}
+		seen[image] = struct{}{}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+func getPurlsFromSBOM(sbom []byte) ([]string, error) {
+	var probe struct {
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+	}
+	if err := json.Unmarshal(sbom, &probe); err != nil {
+		return nil, fmt.Errorf("only SPDX and CycloneDX JSON documents are supported at this time: %w", err)
+	}
+
+	var purls []string
+	switch {
+	case probe.SPDXVersion != "":
+		doc := spdxDocument{}
+		if err := json.Unmarshal(sbom, &doc); err != nil {
+			return nil, fmt.Errorf("decoding SPDX document: %w", err)
+		}
+		for _, pkg := range doc.Packages {
+			for _, ref := range pkg.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					purls = append(purls, ref.ReferenceLocator)
+				}
+			}
+		}
+	case strings.EqualFold(probe.BOMFormat, "CycloneDX"):
+		doc := cyclonedxDocument{}
+		if err := json.Unmarshal(sbom, &doc); err != nil {
+			return nil, fmt.Errorf("decoding CycloneDX document: %w", err)
+		}
+		var walk func(cs []cyclonedxComponent)
+		walk = func(cs []cyclonedxComponent) {
+			for _, c := range cs {
+				if c.Purl != "" {
+					purls = append(purls, c.Purl)
+				}
+				walk(c.Components)
+			}
+		}
+		if doc.Metadata.Component != nil {
+			walk([]cyclonedxComponent{*doc.Metadata.Component})
+		}
+		walk(doc.Components)
+	default:
+		return nil, errors.New("only SPDX and CycloneDX JSON documents are supported at this time")
+	}
+	return purls, nil
+}
+
+// imageFromPurl converts a pkg:oci or pkg:docker package URL into an image
+// reference. It returns false if the purl does not describe a container image.
+//
+// See https://github.com/package-url/purl-spec/blob/master/PURL-TYPES.rst
+func imageFromPurl(purl string) (string, bool, error) {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return "", false, errors.New("missing pkg: scheme")
+	}
+	rest := strings.TrimPrefix(purl, "pkg:")
+	// Subpaths are never meaningful for container images.
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, rawQuery, _ := strings.Cut(rest, "?")
+
+	typ, path, ok := strings.Cut(rest, "/")
+	if !ok {
+		return "", false, errors.New("missing package name")
+	}
+	typ = strings.ToLower(typ)
+	if typ != "oci" && typ != "docker" {
+		return "", false, nil
+	}
+
+	path, version, _ := strings.Cut(path, "@")
+	path, err := url.PathUnescape(path)
+	if err != nil {
+		return "", false, err
+	}
+	version, err = url.PathUnescape(version)
+	if err != nil {
+		return "", false, err
+	}
+	qualifiers, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", false, err
+	}
+	repositoryURL := qualifiers.Get("repository_url")
+
+	var repo string
+	switch typ {
+	case "oci":
+		// The oci type has no namespace, and repository_url carries the
+		// full repository including the artifact name.
+		repo = path
+		if repositoryURL != "" {
+			repo = repositoryURL
+		}
+	case "docker":
+		// The docker type carries the namespace in the path and the
+		// registry (only) in repository_url.
+		repo = path
+		if repositoryURL != "" {
+			repo = strings.TrimSuffix(repositoryURL, "/") + "/" + path
+		}
+	}
+	repo = strings.TrimPrefix(strings.TrimPrefix(repo, "https://"), "http://")
+
+	switch {
+	case strings.Contains(version, ":"):
+		return repo + "@" + version, true, nil
+	case qualifiers.Get("tag") != "":
+		return repo + ":" + qualifiers.Get("tag"), true, nil
+	case version != "":
+		return repo + ":" + version, true, nil
+	default:
+		return repo, true, nil
+	}
+}
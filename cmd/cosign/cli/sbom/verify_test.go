@@ -0,0 +1,152 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sbom
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const spdxSBOM = `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {
+      "name": "debian",
+      "externalRefs": [
+        {
+          "referenceCategory": "PACKAGE-MANAGER",
+          "referenceType": "purl",
+          "referenceLocator": "pkg:oci/debian@sha256%3A244fd47e07d1004f0aed9c156aa09083c0aba8a0b90b0dcaa3d8b3c8e9d3d7d2?repository_url=docker.io/library/debian&tag=latest"
+        }
+      ]
+    },
+    {
+      "name": "libc",
+      "externalRefs": [
+        {
+          "referenceCategory": "PACKAGE-MANAGER",
+          "referenceType": "purl",
+          "referenceLocator": "pkg:deb/debian/libc6@2.31"
+        }
+      ]
+    }
+  ]
+}`
+
+const cyclonedxSBOM = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "metadata": {
+    "component": {
+      "type": "application",
+      "purl": "pkg:docker/example/app@v1.0.0?repository_url=ghcr.io"
+    }
+  },
+  "components": [
+    {
+      "type": "container",
+      "purl": "pkg:docker/library/nginx@1.21.1",
+      "components": [
+        {
+          "type": "container",
+          "purl": "pkg:oci/sidecar?repository_url=gcr.io/project/sidecar&tag=v2"
+        }
+      ]
+    },
+    {
+      "type": "library",
+      "purl": "pkg:golang/github.com/sigstore/cosign@v2.0.0"
+    },
+    {
+      "type": "container",
+      "purl": "pkg:docker/library/nginx@1.21.1"
+    }
+  ]
+}`
+
+func TestGetImagesFromSBOM(t *testing.T) {
+	testCases := []struct {
+		name           string
+		sbom           string
+		expected       []string
+		expectedErrors bool
+	}{{
+		name:     "spdx",
+		sbom:     spdxSBOM,
+		expected: []string{"docker.io/library/debian@sha256:244fd47e07d1004f0aed9c156aa09083c0aba8a0b90b0dcaa3d8b3c8e9d3d7d2"},
+	}, {
+		name:     "cyclonedx",
+		sbom:     cyclonedxSBOM,
+		expected: []string{"ghcr.io/example/app:v1.0.0", "library/nginx:1.21.1", "gcr.io/project/sidecar:v2"},
+	}, {
+		name:           "unknown format",
+		sbom:           `{"foo": "bar"}`,
+		expectedErrors: true,
+	}, {
+		name:           "not json",
+		sbom:           `SPDXVersion: SPDX-2.3`,
+		expectedErrors: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := getImagesFromSBOM(context.Background(), []byte(tc.sbom))
+			if tc.expectedErrors {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getImagesFromSBOM() returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("getImagesFromSBOM() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestImageFromPurl(t *testing.T) {
+	testCases := []struct {
+		purl     string
+		expected string
+		ok       bool
+		err      bool
+	}{
+		{purl: "pkg:oci/app@sha256%3Aabc?repository_url=registry.example.com/team/app", expected: "registry.example.com/team/app@sha256:abc", ok: true},
+		{purl: "pkg:oci/app?tag=latest", expected: "app:latest", ok: true},
+		{purl: "pkg:docker/team/app@sha256:abc?repository_url=https://registry.example.com", expected: "registry.example.com/team/app@sha256:abc", ok: true},
+		{purl: "pkg:docker/app", expected: "app", ok: true},
+		{purl: "pkg:npm/left-pad@1.0.0", ok: false},
+		{purl: "oci/app", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.purl, func(t *testing.T) {
+			got, ok, err := imageFromPurl(tc.purl)
+			if (err != nil) != tc.err {
+				t.Fatalf("imageFromPurl() err = %v, wanted error: %t", err, tc.err)
+			}
+			if ok != tc.ok {
+				t.Fatalf("imageFromPurl() ok = %t, want %t", ok, tc.ok)
+			}
+			if got != tc.expected {
+				t.Errorf("imageFromPurl() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/scan"
+)
+
+func Scan() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Provides utilities for vulnerability scanning container images",
+	}
+
+	cmd.AddCommand(
+		scanAttach(),
+	)
+
+	return cmd
+}
+
+func scanAttach() *cobra.Command {
+	o := &options.ScanAttachOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "attach",
+		Short: "Scan the supplied container image and attach the result as a signed vulnerability attestation",
+		Long: `Scan the supplied container image with a scanner plugin, and attach the scan
+result to the image as a signed vulnerability scan attestation of type
+` + "`vuln`" + `.
+
+Scanner plugins are cosign-scan-<name> executables that implement two commands:
+
+  cosign-scan-<name> info
+      prints a JSON object describing the scanner and its vulnerability
+      database: {"uri": "...", "version": "...", "db": {"uri": "...", "version": "..."}}
+
+  cosign-scan-<name> scan <image@digest>
+      scans the image, pulling it with the Docker client configuration, and
+      prints the scanner's JSON report
+
+Plugins exit non-zero on failure, and may log to standard error.`,
+		Example: `  cosign scan attach --scanner <name>|<path> --key <key path>|<kms uri> <image uri>
+
+  # scan a container image with the cosign-scan-trivy plugin and attest the result with a local key pair file
+  cosign scan attach --scanner trivy --key cosign.key <IMAGE>
+
+  # scan a container image with the scanner configured in the environment, replacing the previous scan results
+  COSIGN_SCANNER=grype cosign scan attach --replace <IMAGE>`,
+
+		Args:             cobra.MinimumNArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oidcClientSecret, err := o.OIDC.ClientSecret()
+			if err != nil {
+				return err
+			}
+			ko := options.KeyOpts{
+				KeyRef:                   o.Key,
+				PassFunc:                 generate.GetPass,
+				Sk:                       o.SecurityKey.Use,
+				Slot:                     o.SecurityKey.Slot,
+				FulcioURL:                o.Fulcio.URL,
+				IDToken:                  o.Fulcio.IdentityToken,
+				InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                     o.Fulcio.ACME,
+				RekorURL:                 o.Rekor.URL,
+				OIDCIssuer:               o.OIDC.Issuer,
+				OIDCClientID:             o.OIDC.ClientID,
+				OIDCClientSecret:         oidcClientSecret,
+				OIDCRedirectURL:          o.OIDC.RedirectURL,
+				OIDCProvider:             o.OIDC.Provider,
+				OIDCTokenFile:            o.OIDC.TokenFile,
+				OIDCAudience:             o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
+				SkipConfirmation:         o.SkipConfirmation,
+				TSAServerURL:             o.TSAServerURL,
+			}
+			attachCommand := scan.AttachCommand{
+				AttestCommand: attest.AttestCommand{
+					KeyOpts:         ko,
+					RegistryOptions: o.Registry,
+					CertPath:        o.Cert,
+					CertChainPath:   o.CertChain,
+					Replace:         o.Replace,
+					Timeout:         ro.Timeout,
+					TlogUpload:      o.TlogUpload,
+				},
+				Scanner: o.Scanner,
+			}
+
+			for _, img := range args {
+				if err := attachCommand.Exec(cmd.Context(), img); err != nil {
+					return fmt.Errorf("scanning %s: %w", img, err)
+				}
+			}
+			return nil
+		},
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
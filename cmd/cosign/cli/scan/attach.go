@@ -0,0 +1,98 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/pkg/plugin"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// scannerPrefix is the prefix of the executables that implement scanner
+// plugins, e.g. cosign-scan-trivy.
+const scannerPrefix = "cosign-scan-"
+
+// AttachCommand scans an image with a scanner plugin and attaches the result
+// to it as a vulnerability scan attestation.
+type AttachCommand struct {
+	attest.AttestCommand
+	Scanner string
+}
+
+// Exec scans imageRef and attests the result.
+func (c *AttachCommand) Exec(ctx context.Context, imageRef string) error {
+	scanner := c.Scanner
+	if scanner == "" {
+		scanner = env.Getenv(env.VariableScanner)
+	}
+	if scanner == "" {
+		return fmt.Errorf("a scanner is required, set --scanner or %s", env.VariableScanner)
+	}
+	path, err := plugin.Find(scannerPrefix, scanner)
+	if err != nil {
+		return err
+	}
+
+	c.PredicateType = options.PredicateVuln
+	c.GeneratePredicate = func(ctx context.Context, digest name.Digest) ([]byte, error) {
+		return scan(ctx, path, digest)
+	}
+	return c.AttestCommand.Exec(ctx, imageRef)
+}
+
+// scan runs the scanner plugin at path against digest and returns its report
+// wrapped in a vulnerability scan predicate.
+func scan(ctx context.Context, path string, digest name.Digest) ([]byte, error) {
+	info, err := plugin.Output(ctx, path, "info")
+	if err != nil {
+		return nil, fmt.Errorf("getting scanner info: %w", err)
+	}
+	var scanner attestation.Scanner
+	if err := json.Unmarshal(info, &scanner); err != nil {
+		return nil, fmt.Errorf("parsing scanner info from %s: %w", path, err)
+	}
+	if scanner.URI == "" {
+		return nil, fmt.Errorf("scanner info from %s has no uri", path)
+	}
+
+	ui.Infof(ctx, "Scanning %s with %s %s", digest, scanner.URI, scanner.Version)
+	started := time.Now().UTC()
+	report, err := plugin.Output(ctx, path, "scan", digest.String())
+	if err != nil {
+		return nil, fmt.Errorf("scanning: %w", err)
+	}
+	finished := time.Now().UTC()
+	if err := json.Unmarshal(report, &scanner.Result); err != nil {
+		return nil, fmt.Errorf("parsing scan report from %s: %w", path, err)
+	}
+
+	return json.Marshal(attestation.CosignVulnPredicate{
+		Scanner: scanner,
+		Metadata: attestation.Metadata{
+			ScanStartedOn:  started,
+			ScanFinishedOn: finished,
+		},
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// fakeScanner reports one vulnerability in the image it is asked to scan.
+const fakeScanner = `case "$1" in
+info) echo '{"uri":"pkg:github/example/scanner","version":"1.2.3","db":{"uri":"https://example.com/db","version":"42"}}' ;;
+scan) echo "{\"image\":\"$2\",\"vulnerabilities\":[\"CVE-2023-0001\"]}" ;;
+*) exit 1 ;;
+esac`
+
+func TestAttachCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("scanner plugin scripts require a POSIX shell")
+	}
+	bin := t.TempDir()
+	for name, script := range map[string]string{
+		"cosign-scan-fake":   fakeScanner,
+		"cosign-scan-noinfo": `echo '{"version":"1"}'`,
+	} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script+"\n"), 0o700); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(env.VariablePassword.String(), "")
+	t.Setenv(env.VariableScanner.String(), "")
+
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRef := filepath.Join(t.TempDir(), "cosign.key")
+	if err := os.WriteFile(keyRef, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	ref, err := name.ParseReference(strings.TrimPrefix(s.URL, "http://") + "/app:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	newCommand := func(scanner string) *AttachCommand {
+		return &AttachCommand{
+			AttestCommand: attest.AttestCommand{KeyOpts: options.KeyOpts{KeyRef: keyRef}},
+			Scanner:       scanner,
+		}
+	}
+	for scanner, wantErr := range map[string]string{
+		"":        "a scanner is required",
+		"missing": "plugin cosign-scan-missing not found",
+		"noinfo":  "has no uri",
+	} {
+		if err := newCommand(scanner).Exec(ctx, ref.String()); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("Exec() with scanner %q error = %v, want %q", scanner, err, wantErr)
+		}
+	}
+
+	if err := newCommand("fake").Exec(ctx, ref.String()); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := atts.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("got %d attestations, want 1", len(all))
+	}
+	payload, err := all[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st attestation.CosignVulnStatement
+	if err := json.Unmarshal(decoded, &st); err != nil {
+		t.Fatal(err)
+	}
+
+	if st.PredicateType != attestation.CosignVulnProvenanceV01 {
+		t.Errorf("predicate type = %s, want %s", st.PredicateType, attestation.CosignVulnProvenanceV01)
+	}
+	scanner := st.Predicate.Scanner
+	if scanner.URI != "pkg:github/example/scanner" || scanner.Version != "1.2.3" || scanner.DB.Version != "42" {
+		t.Errorf("scanner = %+v, want the scanner info", scanner)
+	}
+	result, _ := scanner.Result.(map[string]interface{})
+	if want := ref.Context().Digest(h.String()).String(); result["image"] != want {
+		t.Errorf("scanned image = %v, want %s", result["image"], want)
+	}
+	if st.Predicate.Metadata.ScanFinishedOn.Before(st.Predicate.Metadata.ScanStartedOn) {
+		t.Errorf("scan metadata = %+v, want the scan to finish after it started", st.Predicate.Metadata)
+	}
+}
@@ -0,0 +1,199 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/cosign/signingserver"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+func Server() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "Provides services that cosign clients delegate to",
+	}
+
+	cmd.AddCommand(
+		serverSign(),
+	)
+
+	return cmd
+}
+
+func serverSign() *cobra.Command {
+	o := &options.ServerSignOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Run a signing service that signs for cosign sign --signing-server",
+		Long: `Run a minimal self-hosted signing service, which signs with a key in a KMS, an
+HSM or a file for the clients of cosign sign --signing-server, so that only the
+service has access to the key.
+
+The callers are authenticated with bearer tokens. With --policy, each caller
+has a token, listed by its SHA-256 digest, and may be restricted to images of
+some repositories and to some hash algorithms, e.g.
+
+  {"callers": [
+    {"name": "release", "tokenSha256": "<sha256sum of the token>", "repositories": ["ghcr.io/example/*"]},
+    {"name": "nightly", "tokenSha256": "<sha256sum of the token>", "hashAlgorithms": ["sha256"]}
+  ]}
+
+Callers restricted to repositories must send the payloads they sign, which
+cosign sign does, and can only sign image signatures and attestations. Without
+--policy, every caller has the token in $COSIGN_SIGNING_SERVER_TOKEN.
+
+With --audit-log, each sign request and each request that fails authentication
+is recorded as a line of JSON, with the caller, the signed digest and
+repositories, and why the request was rejected.`,
+		Example: `  cosign server sign --key <key path>|<kms uri> [--certificate <cert>] [--policy <policy.json>] [--audit-log <file>]
+
+  # sign with a KMS key for the callers of a policy, logging to stdout
+  cosign server sign --key gcpkms://projects/<PROJECT>/locations/global/keyRings/<KEYRING>/cryptoKeys/<KEY> --policy callers.json --audit-log -
+
+  # serve HTTPS, with a single token for every caller
+  COSIGN_SIGNING_SERVER_TOKEN=<token> cosign server sign --key cosign.key --tls-cert server.crt --tls-key server.key --address :8443
+
+  # sign an image with the service
+  COSIGN_SIGNING_SERVER_TOKEN=<token> cosign sign --signing-server https://signer.example.com:8443 <IMAGE>`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ServerSignCmd(cmd.Context(), *o)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// ServerSignCmd serves the signing service on o.Address until ctx is done or
+// cosign is interrupted.
+func ServerSignCmd(ctx context.Context, o options.ServerSignOptions) error {
+	if (o.TLSCert == "") != (o.TLSKey == "") {
+		return errors.New("--tls-cert and --tls-key must be set together")
+	}
+	var audit io.Writer
+	switch o.AuditLog {
+	case "":
+	case "-":
+		audit = os.Stdout
+	default:
+		f, err := os.OpenFile(o.AuditLog, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("opening audit log: %w", err)
+		}
+		defer f.Close()
+		audit = f
+	}
+	h, closeSigner, err := signingHandler(ctx, o, audit)
+	if err != nil {
+		return err
+	}
+	defer closeSigner()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	l, err := net.Listen("tcp", o.Address)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	ui.Infof(ctx, "Serving the signing service on %s", l.Addr())
+	if o.TLSCert != "" {
+		err = srv.ServeTLS(l, o.TLSCert, o.TLSKey)
+	} else {
+		err = srv.Serve(l)
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// signingHandler returns the handler of the signing service of o, writing
+// its audit log to audit if set, and the func that releases its key.
+func signingHandler(ctx context.Context, o options.ServerSignOptions, audit io.Writer) (*signingserver.Handler, func(), error) {
+	if options.NOf(o.Key, o.SecurityKey.Use) != 1 {
+		return nil, nil, errors.New("exactly one of --key and --sk must be set")
+	}
+	var opts []signingserver.HandlerOption
+	var token string
+	if o.Policy != "" {
+		p, err := signingserver.ReadPolicy(o.Policy)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, signingserver.WithPolicy(p))
+	} else if token = env.Getenv(env.VariableSigningToken); token == "" {
+		return nil, nil, fmt.Errorf("either --policy or $%s must be set to authenticate the callers", env.VariableSigningToken)
+	}
+	if audit != nil {
+		opts = append(opts, signingserver.WithAuditLog(audit))
+	}
+
+	ko := options.KeyOpts{
+		KeyRef:   o.Key,
+		PassFunc: generate.GetPass,
+		Sk:       o.SecurityKey.Use,
+		Slot:     o.SecurityKey.Slot,
+	}
+	sv, err := sign.SignerFromKeyOpts(ctx, o.Cert, o.CertChain, ko)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := sv.PublicKey()
+	if err != nil {
+		sv.Close()
+		return nil, nil, err
+	}
+	pem, err := cryptoutils.MarshalPublicKeyToPEM(pub)
+	if err != nil {
+		sv.Close()
+		return nil, nil, err
+	}
+	signer := signingserver.Signer{PublicKey: string(pem), Certificate: string(sv.Cert), CertificateChain: string(sv.Chain)}
+	return signingserver.NewHandler(sv, signer, token, opts...), sv.Close, nil
+}
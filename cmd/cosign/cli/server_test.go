@@ -0,0 +1,79 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/signingserver"
+)
+
+func TestSigningHandler(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte("password"), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(td, "cosign.key")
+	if err := os.WriteFile(keyPath, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("COSIGN_PASSWORD", "password")
+	t.Setenv("COSIGN_SIGNING_SERVER_TOKEN", "")
+
+	if _, _, err := signingHandler(ctx, options.ServerSignOptions{Key: keyPath}, nil); err == nil || !strings.Contains(err.Error(), "--policy") {
+		t.Errorf("signingHandler() without authentication = %v, want an error", err)
+	}
+	if _, _, err := signingHandler(ctx, options.ServerSignOptions{}, nil); err == nil || !strings.Contains(err.Error(), "--key") {
+		t.Errorf("signingHandler() without a key = %v, want an error", err)
+	}
+	if err := ServerSignCmd(ctx, options.ServerSignOptions{Key: keyPath, TLSCert: "server.crt"}); err == nil || !strings.Contains(err.Error(), "--tls-key") {
+		t.Errorf("ServerSignCmd() without --tls-key = %v, want an error", err)
+	}
+
+	t.Setenv("COSIGN_SIGNING_SERVER_TOKEN", "s3cr3t")
+	var audit bytes.Buffer
+	h, closeSigner, err := signingHandler(ctx, options.ServerSignOptions{Key: keyPath}, &audit)
+	if err != nil {
+		t.Fatalf("signingHandler() = %v", err)
+	}
+	t.Cleanup(closeSigner)
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+
+	c, err := signingserver.NewClient(ctx, s.URL, "s3cr3t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Signer().PublicKey != string(keys.PublicBytes) {
+		t.Errorf("public key of the server = %q, want %q", c.Signer().PublicKey, keys.PublicBytes)
+	}
+	if _, err := c.SignMessage(bytes.NewReader([]byte("payload"))); err != nil {
+		t.Errorf("SignMessage() = %v", err)
+	}
+	if !strings.Contains(audit.String(), `"decision":"signed"`) {
+		t.Errorf("audit log = %q, want the signed request", audit.String())
+	}
+}
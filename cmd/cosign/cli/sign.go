@@ -48,6 +48,13 @@ race conditions or (worse) malicious tampering.
   # sign a multi-arch container image AND all referenced, discrete images
   cosign sign --key cosign.key --recursive <MULTI-ARCH IMAGE DIGEST>
 
+  # sign the two base layers of a base image, so that the images built on it
+  # can require them with cosign verify --layer-policy (experimental)
+  COSIGN_EXPERIMENTAL=1 cosign sign --key platform.key --layers 0,1 <BASE IMAGE DIGEST>
+
+  # resume an interrupted recursive signing at the image it reported as pending
+  cosign sign --key cosign.key --recursive --resume-from <IMAGE DIGEST> <MULTI-ARCH IMAGE DIGEST>
+
   # sign a container image and add annotations
   cosign sign --key cosign.key -a key1=value1 -a key2=value2 <IMAGE DIGEST>
 
@@ -79,7 +86,36 @@ race conditions or (worse) malicious tampering.
   cosign sign --key cosign.key <IMAGE DIGEST>
 
   # sign a container image and skip uploading to the transparency log
-  cosign sign --key cosign.key --tlog-upload=false <IMAGE DIGEST>`,
+  cosign sign --key cosign.key --tlog-upload=false <IMAGE DIGEST>
+
+  # check the signing configuration without pushing the signature or uploading it to the transparency log
+  cosign sign --dry-run <IMAGE DIGEST>
+
+  # sign an image in an OCI layout built in CI, storing the signature in the layout, and push it later
+  cosign sign --key cosign.key --local-image <PATH>
+  cosign copy --local-image <PATH> <IMAGE>
+
+  # sign an image in a docker tarball, writing the signed image to an OCI layout
+  cosign sign --key cosign.key --local-image --local-image-output <PATH> image.tar
+
+  # sign with the key of a central signing service, which keeps custody of the key
+  COSIGN_SIGNING_SERVER_TOKEN=<TOKEN> cosign sign --signing-server https://signer.example.com <IMAGE DIGEST>
+
+  # sign an encrypted (OCIcrypt) image without decrypting it, attesting which keys can decrypt it
+  cosign sign --key cosign.key --encryption-recipient recipient.pub <IMAGE DIGEST>
+
+  # sign keyless in a self-hosted CI job, exchanging its OIDC token for one Fulcio accepts
+  cosign sign --yes --oidc-token-file $CI_JOB_JWT_FILE --oidc-token-exchange-issuer https://sts.example.com --oidc-audience sigstore <IMAGE DIGEST>
+
+  # sign a container image with the identity token of a site-specific SSO system, written by a helper executable
+  cosign sign --yes --oidc-provider exec:/usr/local/bin/sso-oidc-helper <IMAGE DIGEST>
+
+  # sign keyless in an air-gapped network, with a short-lived certificate of a local CA instead of Fulcio
+  cosign sign --yes --fulcio-url exec:/usr/local/bin/local-ca --tlog-upload=false <IMAGE DIGEST>
+
+  # sign keyless with a short-lived code signing certificate of an internal ACME CA instead of Fulcio
+  cosign sign --yes --fulcio-url acme:https://ca.example.com/acme/directory --acme-profile codesigning \
+    --acme-identifier build.example.com --acme-eab-kid $EAB_KID --acme-eab-hmac-key $EAB_HMAC_KEY <IMAGE DIGEST>`,
 
 		Args:             cobra.MinimumNArgs(1),
 		PersistentPreRun: options.BindViper,
@@ -90,6 +126,9 @@ race conditions or (worse) malicious tampering.
 			default:
 				return fmt.Errorf("specified image attachment %s not specified. Can be 'sbom'", o.Attachment)
 			}
+			if err := o.RegistryExperimental.CheckFeatureGates(); err != nil {
+				return err
+			}
 			oidcClientSecret, err := o.OIDC.ClientSecret()
 			if err != nil {
 				return err
@@ -102,6 +141,7 @@ race conditions or (worse) malicious tampering.
 				FulcioURL:                      o.Fulcio.URL,
 				IDToken:                        o.Fulcio.IdentityToken,
 				InsecureSkipFulcioVerify:       o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                           o.Fulcio.ACME,
 				RekorURL:                       o.Rekor.URL,
 				OIDCIssuer:                     o.OIDC.Issuer,
 				OIDCClientID:                   o.OIDC.ClientID,
@@ -109,11 +149,15 @@ race conditions or (worse) malicious tampering.
 				OIDCRedirectURL:                o.OIDC.RedirectURL,
 				OIDCDisableProviders:           o.OIDC.DisableAmbientProviders,
 				OIDCProvider:                   o.OIDC.Provider,
+				OIDCTokenFile:                  o.OIDC.TokenFile,
+				OIDCAudience:                   o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:        o.OIDC.TokenExchangeIssuer,
 				SkipConfirmation:               o.SkipConfirmation,
 				TSAServerURL:                   o.TSAServerURL,
 				IssueCertificateForExistingKey: o.IssueCertificate,
+				SigningServerURL:               o.SigningServer,
 			}
-			if err := sign.SignCmd(ro, ko, *o, args); err != nil {
+			if err := sign.SignCmd(cmd.Context(), ro, ko, *o, args); err != nil {
 				if o.Attachment == "" {
 					return fmt.Errorf("signing %v: %w", args, err)
 				}
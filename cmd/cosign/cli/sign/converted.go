@@ -0,0 +1,97 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/conversion"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// signDigestAndConverted signs digest and, with --sign-converted, the images
+// converted from it.
+func signDigestAndConverted(ctx context.Context, digest name.Digest, payload []byte, ko options.KeyOpts, signOpts options.SignOptions,
+	annotations map[string]interface{},
+	dd mutate.DupeDetector, sv *SignerVerifier, se oci.SignedEntity) error {
+	if err := signDigest(ctx, digest, payload, ko, signOpts, annotations, dd, sv, se); err != nil {
+		return err
+	}
+	if !signOpts.SignConverted {
+		return nil
+	}
+
+	converted, err := convertedImages(digest, signOpts.Registry.GetRegistryClientOpts(ctx)...)
+	if err != nil {
+		return err
+	}
+	opts, err := signOpts.Registry.ClientOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("constructing client options: %w", err)
+	}
+	for _, ref := range converted {
+		ui.Infof(ctx, "Signing %s, which was converted from %s", ref.DigestStr(), digest.DigestStr())
+		cse, err := ociremote.SignedEntity(ref, opts...)
+		if err != nil {
+			return fmt.Errorf("accessing image: %w", err)
+		}
+		if err := signDigest(ctx, ref, payload, ko, signOpts, annotations, dd, sv, cse); err != nil {
+			return fmt.Errorf("signing %s: %w", ref, err)
+		}
+	}
+	return nil
+}
+
+// convertedImages returns the images that were converted from digest to a
+// lazy-pulling layer format, found as referrers that name digest as their
+// subject.
+func convertedImages(digest name.Digest, opts ...remote.Option) ([]name.Digest, error) {
+	idx, err := remote.Referrers(digest, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("listing referrers of %s: %w", digest, err)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	var converted []name.Digest
+	for _, desc := range m.Manifests {
+		if !desc.MediaType.IsImage() {
+			continue
+		}
+		ref := digest.Context().Digest(desc.Digest.String())
+		img, err := remote.Image(ref, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("fetching referrer %s: %w", ref, err)
+		}
+		im, err := img.Manifest()
+		if err != nil {
+			return nil, err
+		}
+		if h, ok := conversion.Source(im); ok && h.String() == digest.DigestStr() {
+			converted = append(converted, ref)
+		}
+	}
+	return converted, nil
+}
@@ -0,0 +1,88 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+func TestConvertedImages(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer s.Close()
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	push := func(img v1.Image) name.Digest {
+		t.Helper()
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := repo.Digest(h.String())
+		if err := remote.Write(d, img); err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	newImage := func(annotations map[string]string) v1.Image {
+		t.Helper()
+		l, err := random.Layer(100, "application/vnd.oci.image.layer.v1.tar+gzip")
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := mutate.Append(empty.Image, mutate.Addendum{Layer: l, Annotations: annotations})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img
+	}
+	withSubject := func(img, subject v1.Image) v1.Image {
+		t.Helper()
+		desc, err := partial.Descriptor(subject)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mutate.Subject(img, *desc).(v1.Image)
+	}
+
+	source := newImage(nil)
+	sourceRef := push(source)
+	converted := push(withSubject(newImage(map[string]string{"containerd.io/snapshot/nydus-bootstrap": "true"}), source))
+	// A referrer that isn't a converted image, like an SBOM, is not signed.
+	push(withSubject(newImage(nil), source))
+
+	got, err := convertedImages(sourceRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != converted {
+		t.Errorf("convertedImages() = %v, want [%s]", got, converted)
+	}
+}
@@ -0,0 +1,120 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+const (
+	// UntrustedAnnotation marks the signatures of `cosign dev sign`, whose
+	// ephemeral keys aren't bound to an identity.
+	UntrustedAnnotation = "dev.sigstore.cosign/untrusted"
+
+	// maxDevTTL bounds the lifetime of development signatures.
+	maxDevTTL = 24 * time.Hour
+
+	devPublicKeyEnv = string(env.VariableDevPublicKey)
+)
+
+// DevSignCmd signs imgs with a keypair generated in memory, without Fulcio
+// or the transparency log, and writes its public key and the command to
+// verify the signatures to out. The signatures are annotated as untrusted
+// and expire after o.TTL.
+func DevSignCmd(ctx context.Context, ro *options.RootOptions, o options.DevSignOptions, imgs []string, out io.Writer) error {
+	d, err := cosign.ParseExpiryDuration(o.TTL)
+	if err != nil {
+		return err
+	}
+	if d > maxDevTTL {
+		return fmt.Errorf("--ttl %s is longer than %s, development signatures must be short-lived", o.TTL, maxDevTTL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ro.Timeout)
+	defer cancel()
+
+	am, err := o.AnnotationsMap()
+	if err != nil {
+		return fmt.Errorf("getting annotations: %w", err)
+	}
+	annotations, err := withExpiry(am.Annotations, o.TTL, false)
+	if err != nil {
+		return err
+	}
+	annotations[UntrustedAnnotation] = "ephemeral development key"
+
+	sv, err := signerFromNewKey()
+	if err != nil {
+		return fmt.Errorf("getting signer: %w", err)
+	}
+	defer sv.Close()
+	pub, err := sv.Bytes(ctx)
+	if err != nil {
+		return fmt.Errorf("getting public key: %w", err)
+	}
+	dd := cremote.NewDupeDetector(sv)
+
+	opts, err := o.Registry.ClientOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("constructing client options: %w", err)
+	}
+	signOpts := options.SignOptions{
+		Upload:               true,
+		Registry:             o.Registry,
+		RegistryExperimental: o.RegistryExperimental,
+	}
+
+	ui.Warnf(ctx, "Signing with an ephemeral development key, the signatures are UNTRUSTED and expire in %s", o.TTL)
+	digests := make([]name.Digest, 0, len(imgs))
+	for _, img := range imgs {
+		ref, err := ParseOCIReference(ctx, img, o.Registry.NameOptions()...)
+		if err != nil {
+			return err
+		}
+		se, err := ociremote.SignedEntity(ref, opts...)
+		if err != nil {
+			return fmt.Errorf("accessing image: %w", err)
+		}
+		h, err := se.(interface{ Digest() (v1.Hash, error) }).Digest()
+		if err != nil {
+			return fmt.Errorf("computing digest: %w", err)
+		}
+		digest := ref.Context().Digest(h.String())
+		if err := signDigest(ctx, digest, nil, options.KeyOpts{}, signOpts, annotations, dd, sv, se); err != nil {
+			return fmt.Errorf("signing digest: %w", err)
+		}
+		digests = append(digests, digest)
+	}
+
+	fmt.Fprintf(out, "# UNTRUSTED: signed with an ephemeral development key, for local testing only.\n")
+	fmt.Fprintf(out, "export %s='%s'\n", devPublicKeyEnv, pub)
+	for _, digest := range digests {
+		fmt.Fprintf(out, "cosign verify --key env://%s --insecure-ignore-tlog --enforce-expiry %s\n", devPublicKeyEnv, digest)
+	}
+	return nil
+}
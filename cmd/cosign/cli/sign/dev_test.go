@@ -0,0 +1,93 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+)
+
+func TestDevSignCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer s.Close()
+	ref, err := name.NewTag(strings.TrimPrefix(s.URL, "http://") + "/app:dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := ref.Context().Digest(h.String())
+
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	var out bytes.Buffer
+	if err := DevSignCmd(ctx, ro, options.DevSignOptions{TTL: "10m"}, []string{ref.String()}, &out); err != nil {
+		t.Fatalf("DevSignCmd() = %v", err)
+	}
+
+	// The output exports the public key and verifies the digest with it.
+	pub, verifyCmd, ok := strings.Cut(strings.SplitN(out.String(), "export "+devPublicKeyEnv+"='", 2)[1], "'\n")
+	if !ok || !strings.HasPrefix(pub, "-----BEGIN PUBLIC KEY-----") {
+		t.Fatalf("DevSignCmd() wrote %q, want the public key", out.String())
+	}
+	if want := "cosign verify --key env://" + devPublicKeyEnv + " --insecure-ignore-tlog --enforce-expiry " + digest.String() + "\n"; verifyCmd != want {
+		t.Errorf("DevSignCmd() verify command = %q, want %q", verifyCmd, want)
+	}
+	t.Setenv(devPublicKeyEnv, pub)
+	verifier, err := sigs.PublicKeyFromKeyRef(ctx, "env://"+devPublicKeyEnv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	co := &cosign.CheckOpts{
+		SigVerifier:   verifier,
+		IgnoreTlog:    true,
+		EnforceExpiry: true,
+		ClaimVerifier: cosign.SimpleClaimVerifier,
+		Annotations:   map[string]interface{}{UntrustedAnnotation: "ephemeral development key"},
+	}
+	if _, _, err := cosign.VerifyImageSignatures(ctx, digest, co); err != nil {
+		t.Errorf("VerifyImageSignatures() of the development signature = %v", err)
+	}
+
+	for _, ttl := range []string{"48h", "2d", "soon"} {
+		if err := DevSignCmd(ctx, ro, options.DevSignOptions{TTL: ttl}, []string{ref.String()}, io.Discard); err == nil {
+			t.Errorf("DevSignCmd() with --ttl %s: expected an error", ttl)
+		}
+	}
+}
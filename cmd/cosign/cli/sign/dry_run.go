@@ -0,0 +1,62 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// DryRunTlogUpload prints the transparency log entry of entryType for ref
+// that a dry run would have uploaded. Unlike ShouldUploadToTlog it never asks
+// for confirmation; the confirmations that would be asked are printed.
+func DryRunTlogUpload(ctx context.Context, ko options.KeyOpts, ref name.Reference, tlogUpload bool, entryType string) {
+	if !tlogUpload {
+		ui.Infof(ctx, "Dry run: would not upload to the transparency log")
+		return
+	}
+	if !ko.SkipConfirmation {
+		ui.Infof(ctx, "Dry run: would ask to accept the transparency log privacy statement, skip with --yes")
+		if _, err := remote.Get(ref, remote.WithContext(ctx)); err != nil {
+			ui.Warnf(ctx, "Dry run: %q appears to be a private repository, would ask to confirm uploading to the transparency log", ref.Context().String())
+		}
+	}
+	ui.Infof(ctx, "Dry run: would upload the %s entry for %s to the transparency log at %s", entryType, ref, ko.RekorURL)
+}
+
+// DryRunPush prints the target a dry run would have pushed the signatures or
+// attestations image sigs to, with an estimate of the upload: the new layer
+// of layerSize bytes, the config and the manifest. The other layers are
+// already in the registry.
+func DryRunPush(ctx context.Context, kind, target string, sigs oci.Signatures, layerSize int) error {
+	config, err := sigs.RawConfigFile()
+	if err != nil {
+		return err
+	}
+	manifest, err := sigs.RawManifest()
+	if err != nil {
+		return err
+	}
+	ui.Infof(ctx, "Dry run: would push %s to %s (about %d bytes: the %d byte %s, a %d byte config and a %d byte manifest)",
+		kind, target, layerSize+len(config)+len(manifest), layerSize, kind, len(config), len(manifest))
+	return nil
+}
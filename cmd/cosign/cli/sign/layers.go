@@ -0,0 +1,80 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/featuregates"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// checkLayerOptions returns an error if the options of signOpts can't be
+// used to sign --layers.
+func checkLayerOptions(signOpts options.SignOptions) error {
+	if err := featuregates.Require(featuregates.LayerSignatures); err != nil {
+		return err
+	}
+	switch {
+	case signOpts.Recursive || signOpts.SignConverted || signOpts.Attachment != "" || signOpts.LocalImage.Enabled:
+		return errors.New("--layers can't be used with --recursive, --sign-converted, --attachment or --local-image")
+	case signOpts.PayloadPath != "":
+		return errors.New("--layers can't be used with --payload, the payload names the digest of a single blob")
+	case len(signOpts.EncryptionRecipients) > 0:
+		return errors.New("--layers can't be used with --encryption-recipient, the recipients are those of the image")
+	case signOpts.RegistryExperimental.RegistryReferrersMode.Referrers():
+		return errors.New("--layers can't be used with --registry-referrers-mode=oci-1-1, only manifests may have referrers")
+	}
+	return nil
+}
+
+// signLayers signs the blobs of the image ref selected by --layers with
+// signEntity, each by its digest in the repository of ref, so that the
+// layers an image shares with its base image are signed once for both.
+func signLayers(ctx context.Context, ref name.Reference, signOpts options.SignOptions, signEntity func(name.Digest, oci.SignedEntity) error, opts ...ociremote.Option) error {
+	desc, err := remote.Get(ref, signOpts.Registry.GetRegistryClientOpts(ctx)...)
+	if err != nil {
+		return fmt.Errorf("accessing image: %w", err)
+	}
+	if desc.MediaType.IsIndex() {
+		return fmt.Errorf("%s is an index, --layers signs the layers of the image of a platform", ref)
+	}
+	img, err := desc.Image()
+	if err != nil {
+		return fmt.Errorf("accessing image: %w", err)
+	}
+	hashes, err := cosign.SelectBlobs(img, signOpts.Layers)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ref, err)
+	}
+	for _, h := range hashes {
+		digest := ref.Context().Digest(h.String())
+		ui.Infof(ctx, "Signing blob %s of %s", h, ref)
+		if err := signEntity(digest, ociremote.SignedBlob(digest, opts...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+const (
+	// OutputIndexSchema identifies the version of the schema of OutputIndex.
+	OutputIndexSchema = "https://sigstore.dev/cosign/output-index/v1"
+	// OutputIndexFile is the name of the index of an output directory.
+	OutputIndexFile = "index.json"
+
+	// The kinds of the files written for a blob.
+	OutputSignature = "sig"
+	OutputBundle    = "bundle"
+	OutputTimestamp = "timestamp.json"
+)
+
+// OutputIndex is the index of the files written to an output directory,
+// written to its OutputIndexFile.
+type OutputIndex struct {
+	Schema      string             `json:"schema"`
+	Command     string             `json:"command"`
+	Certificate string             `json:"certificate,omitempty"`
+	Artifacts   []OutputIndexEntry `json:"artifacts"`
+}
+
+// OutputIndexEntry is a blob of an OutputIndex and the files written for it,
+// by kind, relative to the output directory.
+type OutputIndexEntry struct {
+	Name   string            `json:"name"`
+	Path   string            `json:"path"`
+	Digest string            `json:"digest"`
+	Files  map[string]string `json:"files"`
+}
+
+// OutputDir names the files written for each blob to an output directory
+// with a template, and records them in its index.
+type OutputDir struct {
+	dir      string
+	template string
+	index    OutputIndex
+	// written maps the names of the files to their blobs.
+	written map[string]string
+}
+
+// NewOutputDir creates the output directory dir of command, whose files are
+// named with template. Its placeholders are {name}, the file name of the
+// blob, {alg} and {digest}, the algorithm and hex digest of the blob, and
+// {kind}, the kind of the file, e.g. sig or bundle.
+func NewOutputDir(command, dir, template string) (*OutputDir, error) {
+	if template == "" {
+		template = options.DefaultOutputName
+	}
+	if !strings.Contains(template, "{kind}") {
+		return nil, fmt.Errorf("--output-name %q must have a {kind} placeholder, so that the files of a blob have distinct names", template)
+	}
+	if strings.ContainsAny(template, `/\`) || strings.Contains(template, "..") {
+		return nil, fmt.Errorf("--output-name %q must name a file in --output-dir", template)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	return &OutputDir{
+		dir:      dir,
+		template: template,
+		index:    OutputIndex{Schema: OutputIndexSchema, Command: command, Artifacts: []OutputIndexEntry{}},
+		written:  map[string]string{},
+	}, nil
+}
+
+// Entry returns the entry of the blob at path with the sha256 hex digest,
+// with the paths of the files of kinds.
+func (d *OutputDir) Entry(path, digest string, kinds ...string) (*OutputIndexEntry, error) {
+	e := &OutputIndexEntry{
+		Name:   filepath.Base(path),
+		Path:   path,
+		Digest: "sha256:" + digest,
+		Files:  map[string]string{},
+	}
+	for _, kind := range kinds {
+		name := strings.NewReplacer("{name}", e.Name, "{alg}", "sha256", "{digest}", digest, "{kind}", kind).Replace(d.template)
+		if name == OutputIndexFile {
+			return nil, fmt.Errorf("the %s of %s would overwrite the index %s", kind, path, OutputIndexFile)
+		}
+		if prev, ok := d.written[name]; ok {
+			return nil, fmt.Errorf("the files of %s and %s would both be named %s, add {digest} to --output-name", prev, path, name)
+		}
+		d.written[name] = path
+		e.Files[kind] = name
+	}
+	return e, nil
+}
+
+// Path returns the path of the file of kind of e.
+func (d *OutputDir) Path(e *OutputIndexEntry, kind string) string {
+	if name, ok := e.Files[kind]; ok {
+		return filepath.Join(d.dir, name)
+	}
+	return ""
+}
+
+// Add records the signed blob of e in the index.
+func (d *OutputDir) Add(e *OutputIndexEntry) {
+	d.index.Artifacts = append(d.index.Artifacts, *e)
+}
+
+// WriteIndex writes the index, with the certificate written, if any, to the
+// output directory.
+func (d *OutputDir) WriteIndex(certificate string) error {
+	if len(d.index.Artifacts) == 0 {
+		return errors.New("no blobs were signed")
+	}
+	if certificate != "" {
+		if rel, err := filepath.Rel(d.dir, certificate); err == nil && !strings.HasPrefix(rel, "..") {
+			certificate = rel
+		}
+		d.index.Certificate = certificate
+	}
+	b, err := json.MarshalIndent(d.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(d.dir, OutputIndexFile), append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing output index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/featuregates"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
+)
+
+// RekorLog returns the URL and major API version of the transparency log to
+// upload to: the log selected by the signing config if there is one, or else
+// the Rekor v1 log at ko.RekorURL.
+func RekorLog(ko options.KeyOpts) (string, uint32, error) {
+	if ko.SigningConfig == nil {
+		return ko.RekorURL, 1, nil
+	}
+	log, err := ko.SigningConfig.RekorLog(time.Now())
+	if err != nil {
+		return "", 0, err
+	}
+	if log.MajorAPIVersion == rekorv2.APIVersion {
+		if err := featuregates.Require(featuregates.RekorV2); err != nil {
+			return "", 0, fmt.Errorf("uploading to the Rekor v2 log %s: %w", log.URL, err)
+		}
+	}
+	return log.URL, log.MajorAPIVersion, nil
+}
+
+// uploadToRekorV2 adds a hashedrekord entry of the signature over the
+// SHA-256 digest to the Rekor v2 log at ko.RekorURL. rekorBytes is the PEM
+// encoded certificate or public key of the signer.
+func uploadToRekorV2(ctx context.Context, ko options.KeyOpts, sig, digest, rekorBytes []byte) (*rekorv2.Entry, error) {
+	verifier, err := rekorv2.VerifierFromPEM(rekorBytes)
+	if err != nil {
+		return nil, err
+	}
+	if verifier.X509Certificate != nil && ko.TSAServerURL == "" {
+		ui.Warnf(ctx, "Rekor v2 entries have no integrated time to prove that the certificate was valid when signing, use --timestamp-server-url to get a signed timestamp")
+	}
+	client, err := rekor.NewV2Client(ko.RekorURL)
+	if err != nil {
+		return nil, err
+	}
+	return client.AddHashedRekord(ctx, digest, sig, verifier)
+}
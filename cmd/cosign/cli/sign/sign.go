@@ -26,27 +26,37 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/acmeca"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/fulcioverifier"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/localca"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign/privacy"
+	"github.com/sigstore/cosign/v2/internal/pkg/batch"
 	icos "github.com/sigstore/cosign/v2/internal/pkg/cosign"
 	ifulcio "github.com/sigstore/cosign/v2/internal/pkg/cosign/fulcio"
 	ipayload "github.com/sigstore/cosign/v2/internal/pkg/cosign/payload"
 	irekor "github.com/sigstore/cosign/v2/internal/pkg/cosign/rekor"
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa/client"
+	"github.com/sigstore/cosign/v2/internal/pkg/daemon"
+	"github.com/sigstore/cosign/v2/internal/pkg/gcpauth"
+	"github.com/sigstore/cosign/v2/internal/pkg/profile"
+	"github.com/sigstore/cosign/v2/internal/pkg/store"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pivkey"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
 	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign/signingserver"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
@@ -54,6 +64,7 @@ import (
 	sigs "github.com/sigstore/cosign/v2/pkg/signature"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
 	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
 	sigPayload "github.com/sigstore/sigstore/pkg/signature/payload"
 
@@ -115,27 +126,39 @@ func GetAttachedImageRef(ref name.Reference, attachment string, opts ...ociremot
 }
 
 // ParseOCIReference parses a string reference to an OCI image into a reference, warning if the reference did not include a digest.
+// The image must be in the registries of the --profile.
 func ParseOCIReference(ctx context.Context, refStr string, opts ...name.Option) (name.Reference, error) {
-	ref, err := name.ParseReference(refStr, opts...)
+	ref, err := oci.ParseReference(refStr, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("parsing reference: %w", err)
 	}
+	if err := profile.CheckImage(ref); err != nil {
+		return nil, err
+	}
 	if _, ok := ref.(name.Digest); !ok {
-		msg := fmt.Sprintf(ui.TagReferenceMessage, refStr)
-		ui.Warnf(ctx, msg)
+		ui.Warnf(ctx, ui.TagReferenceMessage, refStr)
 	}
 	return ref, nil
 }
 
 // nolint
-func SignCmd(ro *options.RootOptions, ko options.KeyOpts, signOpts options.SignOptions, imgs []string) error {
+func SignCmd(ctx context.Context, ro *options.RootOptions, ko options.KeyOpts, signOpts options.SignOptions, imgs []string) (err error) {
 	if options.NOf(ko.KeyRef, ko.Sk) > 1 {
 		return &options.KeyParseError{}
 	}
+	if ko.SigningServerURL != "" && (options.NOf(ko.KeyRef, ko.Sk) > 0 || signOpts.Cert != "" || signOpts.CertChain != "") {
+		return errors.New("--signing-server can't be used with --key, --sk, --certificate or --certificate-chain")
+	}
+	if len(signOpts.Layers) > 0 {
+		if err := checkLayerOptions(signOpts); err != nil {
+			return err
+		}
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), ro.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, ro.Timeout)
 	defer cancel()
 
+	ko.SkipCertificate = signOpts.DryRun.Enabled && !signOpts.DryRun.RequestCertificate
 	sv, err := SignerFromKeyOpts(ctx, signOpts.Cert, signOpts.CertChain, ko)
 	if err != nil {
 		return fmt.Errorf("getting signer: %w", err)
@@ -143,6 +166,10 @@ func SignCmd(ro *options.RootOptions, ko options.KeyOpts, signOpts options.SignO
 	defer sv.Close()
 	dd := cremote.NewDupeDetector(sv)
 
+	if signOpts.SignConverted && signOpts.PayloadPath != "" {
+		return errors.New("--sign-converted can't be used with --payload, the payload names the digest of a single image")
+	}
+
 	var staticPayload []byte
 	if signOpts.PayloadPath != "" {
 		ui.Infof(ctx, "Using payload from: %s", signOpts.PayloadPath)
@@ -167,7 +194,49 @@ func SignCmd(ro *options.RootOptions, ko options.KeyOpts, signOpts options.SignO
 		return fmt.Errorf("getting annotations: %w", err)
 	}
 	annotations := am.Annotations
-	for _, inputImg := range imgs {
+	if signOpts.Expires != "" {
+		if annotations, err = withExpiry(annotations, signOpts.Expires, len(staticPayload) > 0); err != nil {
+			return err
+		}
+	}
+	if len(signOpts.EncryptionRecipients) > 0 {
+		if annotations, err = withEncryptionRecipients(annotations, signOpts.EncryptionRecipients, len(staticPayload) > 0); err != nil {
+			return err
+		}
+	}
+
+	if signOpts.LocalImage.Enabled {
+		return signLocalImages(ctx, ko, signOpts, staticPayload, annotations, dd, sv, imgs)
+	}
+
+	// Report the signed and pending images if interrupted, so that the
+	// command can be re-run with --resume-from, and record them in the state
+	// file for --resume.
+	progress := batch.Resumable(signOpts.ResumeFrom)
+	if err := progress.UseStateFile(signOpts.Batch.StateFile, signOpts.Batch.Resume); err != nil {
+		return err
+	}
+	var pending, next []string
+	defer func() {
+		err = progress.Finish(ctx, err, pending...)
+	}()
+	signEntity := func(digest name.Digest, se oci.SignedEntity) error {
+		progress.Start(digest.String())
+		pending = next
+		if len(signOpts.EncryptionRecipients) > 0 {
+			if err := cosign.CheckEncryptedImage(se); err != nil {
+				return fmt.Errorf("%s can't list encryption recipients: %w", digest, err)
+			}
+		}
+		if err := signDigestAndConverted(ctx, digest, staticPayload, ko, signOpts, annotations, dd, sv, se); err != nil {
+			return fmt.Errorf("signing digest: %w", err)
+		}
+		return progress.Done(digest.String())
+	}
+
+	for i, inputImg := range imgs {
+		// An image is pending until one of its digests is started.
+		pending, next = imgs[i:], imgs[i+1:]
 		ref, err := ParseOCIReference(ctx, inputImg, regOpts.NameOptions()...)
 		if err != nil {
 			return err
@@ -177,14 +246,23 @@ func SignCmd(ro *options.RootOptions, ko options.KeyOpts, signOpts options.SignO
 			return fmt.Errorf("unable to resolve attachment %s for image %s", signOpts.Attachment, inputImg)
 		}
 
+		if len(signOpts.Layers) > 0 {
+			if err := signLayers(ctx, ref, signOpts, signEntity, opts...); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if digest, ok := ref.(name.Digest); ok && !signOpts.Recursive {
+			if progress.Skip(digest.String(), inputImg) {
+				continue
+			}
 			se, err := ociremote.SignedEntity(ref, opts...)
 			if err != nil {
 				return fmt.Errorf("accessing image: %w", err)
 			}
-			err = signDigest(ctx, digest, staticPayload, ko, signOpts, annotations, dd, sv, se)
-			if err != nil {
-				return fmt.Errorf("signing digest: %w", err)
+			if err := signEntity(digest, se); err != nil {
+				return err
 			}
 			continue
 		}
@@ -201,9 +279,11 @@ func SignCmd(ro *options.RootOptions, ko options.KeyOpts, signOpts options.SignO
 				return fmt.Errorf("computing digest: %w", err)
 			}
 			digest := ref.Context().Digest(d.String())
-			err = signDigest(ctx, digest, staticPayload, ko, signOpts, annotations, dd, sv, se)
-			if err != nil {
-				return fmt.Errorf("signing digest: %w", err)
+			if progress.Skip(digest.String(), inputImg) {
+				return ErrDone
+			}
+			if err := signEntity(digest, se); err != nil {
+				return err
 			}
 			return ErrDone
 		}); err != nil {
@@ -214,9 +294,149 @@ func SignCmd(ro *options.RootOptions, ko options.KeyOpts, signOpts options.SignO
 	return nil
 }
 
+// withExpiry returns annotations with the ExpiresAnnotation set to expires
+// from now.
+func withExpiry(annotations map[string]interface{}, expires string, staticPayload bool) (map[string]interface{}, error) {
+	if staticPayload {
+		return nil, errors.New("--expires can't be used with --payload, the expiry must be part of the signed payload")
+	}
+	if _, ok := annotations[cosign.ExpiresAnnotation]; ok {
+		return nil, fmt.Errorf("only one of --expires and the %s annotation may be provided", cosign.ExpiresAnnotation)
+	}
+	d, err := cosign.ParseExpiryDuration(expires)
+	if err != nil {
+		return nil, err
+	}
+	if annotations == nil {
+		annotations = map[string]interface{}{}
+	}
+	annotations[cosign.ExpiresAnnotation] = time.Now().Add(d).UTC().Format(time.RFC3339)
+	return annotations, nil
+}
+
+// withEncryptionRecipients returns annotations with the
+// EncryptionRecipientsAnnotation listing the fingerprints of recipients.
+func withEncryptionRecipients(annotations map[string]interface{}, recipients []string, staticPayload bool) (map[string]interface{}, error) {
+	if staticPayload {
+		return nil, errors.New("--encryption-recipient can't be used with --payload, the recipients must be part of the signed payload")
+	}
+	if _, ok := annotations[cosign.EncryptionRecipientsAnnotation]; ok {
+		return nil, fmt.Errorf("only one of --encryption-recipient and the %s annotation may be provided", cosign.EncryptionRecipientsAnnotation)
+	}
+	fps := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		fp, err := cosign.EncryptionRecipient(r)
+		if err != nil {
+			return nil, err
+		}
+		fps = append(fps, fp)
+	}
+	if annotations == nil {
+		annotations = map[string]interface{}{}
+	}
+	annotations[cosign.EncryptionRecipientsAnnotation] = strings.Join(fps, ",")
+	return annotations, nil
+}
+
+// CountersignDigest signs payload, the payload of an existing signature of
+// digest, with sv, and attaches the countersignature to digest.
+func CountersignDigest(ctx context.Context, digest name.Digest, payload []byte, ko options.KeyOpts, signOpts options.SignOptions, sv *SignerVerifier) error {
+	opts, err := signOpts.Registry.ClientOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("constructing client options: %w", err)
+	}
+	se, err := ociremote.SignedEntity(digest, opts...)
+	if err != nil {
+		return fmt.Errorf("accessing image: %w", err)
+	}
+	return signDigest(ctx, digest, payload, ko, signOpts, nil, cremote.NewDupeDetector(sv), sv, se)
+}
+
+// SignDigest signs digest with sv, with annotations in the generated
+// payload, and attaches the signature to digest, as SignCmd does for each
+// image. It lets callers signing many images create sv only once.
+func SignDigest(ctx context.Context, digest name.Digest, ko options.KeyOpts, signOpts options.SignOptions, annotations map[string]interface{}, sv *SignerVerifier) error {
+	opts, err := signOpts.Registry.ClientOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("constructing client options: %w", err)
+	}
+	se, err := ociremote.SignedEntity(digest, opts...)
+	if err != nil {
+		return fmt.Errorf("accessing image: %w", err)
+	}
+	return signDigest(ctx, digest, nil, ko, signOpts, annotations, cremote.NewDupeDetector(sv), sv, se)
+}
+
 func signDigest(ctx context.Context, digest name.Digest, payload []byte, ko options.KeyOpts, signOpts options.SignOptions,
 	annotations map[string]interface{},
 	dd mutate.DupeDetector, sv *SignerVerifier, se oci.SignedEntity) error {
+	ociSig, payload, err := newSignature(ctx, digest, payload, ko, signOpts, annotations, sv)
+	if err != nil {
+		return err
+	}
+	if !signOpts.Upload {
+		return nil
+	}
+
+	// Attach the signature to the entity.
+	newSE, err := mutate.AttachSignatureToEntity(se, ociSig, mutate.WithDupeDetector(dd))
+	if err != nil {
+		return err
+	}
+
+	// Publish the signatures associated with this entity
+	mode := signOpts.RegistryExperimental.RegistryReferrersMode
+	walkOpts, err := signOpts.Registry.ClientOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("constructing client options: %w", err)
+	}
+
+	if signOpts.DryRun.Enabled {
+		sigs, err := newSE.Signatures()
+		if err != nil {
+			return err
+		}
+		var targets []string
+		if mode.Referrers() {
+			targets = append(targets, "a referrer of "+digest.String())
+		}
+		if mode.Tags() {
+			tag, err := ociremote.SignatureTag(digest, walkOpts...)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, tag.String())
+		}
+		return DryRunPush(ctx, "signature", strings.Join(targets, " and "), sigs, len(payload))
+	}
+
+	// Check if we are overriding the signatures repository location
+	repo, _ := ociremote.GetEnvTargetRepository()
+	if repo.RepositoryStr() == "" {
+		ui.Infof(ctx, "Pushing signature to: %s", digest.Repository)
+	} else {
+		ui.Infof(ctx, "Pushing signature to: %s", repo.RepositoryStr())
+	}
+
+	// Publish the signatures associated with this entity (using OCI 1.1+ behavior)
+	if mode.Referrers() {
+		if err := ociremote.WriteSignaturesExperimentalOCI(digest, newSE, walkOpts...); err != nil {
+			return err
+		}
+		if !mode.Tags() {
+			return nil
+		}
+	}
+
+	// Publish the signatures associated with this entity
+	return ociremote.WriteSignatures(digest.Repository, newSE, walkOpts...)
+}
+
+// newSignature signs payload, or the payload generated for digest and
+// annotations if empty, with sv, and writes the signature, payload and
+// certificate to the output files of signOpts.
+func newSignature(ctx context.Context, digest name.Digest, payload []byte, ko options.KeyOpts, signOpts options.SignOptions,
+	annotations map[string]interface{}, sv *SignerVerifier) (oci.Signature, []byte, error) {
 	var err error
 	// The payload can be passed to skip generation.
 	if len(payload) == 0 {
@@ -225,7 +445,7 @@ func signDigest(ctx context.Context, digest name.Digest, payload []byte, ko opti
 			Annotations: annotations,
 		}).MarshalJSON()
 		if err != nil {
-			return fmt.Errorf("payload: %w", err)
+			return nil, nil, fmt.Errorf("payload: %w", err)
 		}
 	}
 
@@ -238,26 +458,28 @@ func signDigest(ctx context.Context, digest name.Digest, payload []byte, ko opti
 	if ko.TSAServerURL != "" {
 		s = tsa.NewSigner(s, client.NewTSAClient(ko.TSAServerURL))
 	}
-	shouldUpload, err := ShouldUploadToTlog(ctx, ko, digest, signOpts.TlogUpload)
-	if err != nil {
-		return fmt.Errorf("should upload to tlog: %w", err)
+	shouldUpload := false
+	if signOpts.DryRun.Enabled {
+		DryRunTlogUpload(ctx, ko, digest, signOpts.TlogUpload, "hashedrekord")
+	} else if shouldUpload, err = ShouldUploadToTlog(ctx, ko, digest, signOpts.TlogUpload); err != nil {
+		return nil, nil, fmt.Errorf("should upload to tlog: %w", err)
 	}
 	if shouldUpload {
 		rClient, err := rekor.NewClient(ko.RekorURL)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		s = irekor.NewSigner(s, rClient)
 	}
 
 	ociSig, _, err := s.Sign(ctx, bytes.NewReader(payload))
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	b64sig, err := ociSig.Base64Signature()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	outputSignature := signOpts.OutputSignature
@@ -267,7 +489,7 @@ func signDigest(ctx context.Context, digest name.Digest, payload []byte, ko opti
 			outputSignature = fmt.Sprintf("%s-%s", outputSignature, strings.Replace(digest.DigestStr(), ":", "-", 1))
 		}
 		if err := os.WriteFile(outputSignature, []byte(b64sig), 0600); err != nil {
-			return fmt.Errorf("create signature file: %w", err)
+			return nil, nil, fmt.Errorf("create signature file: %w", err)
 		}
 	}
 	outputPayload := signOpts.OutputPayload
@@ -277,54 +499,27 @@ func signDigest(ctx context.Context, digest name.Digest, payload []byte, ko opti
 			outputPayload = fmt.Sprintf("%s-%s", outputPayload, strings.Replace(digest.DigestStr(), ":", "-", 1))
 		}
 		if err := os.WriteFile(outputPayload, payload, 0600); err != nil {
-			return fmt.Errorf("create payload file: %w", err)
+			return nil, nil, fmt.Errorf("create payload file: %w", err)
 		}
 	}
 
 	if signOpts.OutputCertificate != "" {
 		rekorBytes, err := sv.Bytes(ctx)
 		if err != nil {
-			return fmt.Errorf("create certificate file: %w", err)
+			return nil, nil, fmt.Errorf("create certificate file: %w", err)
 		}
 
 		if err := os.WriteFile(signOpts.OutputCertificate, rekorBytes, 0600); err != nil {
-			return fmt.Errorf("create certificate file: %w", err)
+			return nil, nil, fmt.Errorf("create certificate file: %w", err)
 		}
 		// TODO: maybe accept a --b64 flag as well?
 		ui.Infof(ctx, "Certificate wrote in the file %s", signOpts.OutputCertificate)
 	}
-
-	if !signOpts.Upload {
-		return nil
-	}
-
-	// Attach the signature to the entity.
-	newSE, err := mutate.AttachSignatureToEntity(se, ociSig, mutate.WithDupeDetector(dd))
-	if err != nil {
-		return err
-	}
-
-	// Publish the signatures associated with this entity
-	walkOpts, err := signOpts.Registry.ClientOpts(ctx)
-	if err != nil {
-		return fmt.Errorf("constructing client options: %w", err)
-	}
-
-	// Check if we are overriding the signatures repository location
-	repo, _ := ociremote.GetEnvTargetRepository()
-	if repo.RepositoryStr() == "" {
-		ui.Infof(ctx, "Pushing signature to: %s", digest.Repository)
-	} else {
-		ui.Infof(ctx, "Pushing signature to: %s", repo.RepositoryStr())
+	if !signOpts.DryRun.Enabled {
+		store.ArchiveSignature(ctx, store.KindSignature, digest.String(), digest.DigestStr(), ociSig)
 	}
 
-	// Publish the signatures associated with this entity (using OCI 1.1+ behavior)
-	if signOpts.RegistryExperimental.RegistryReferrersMode == options.RegistryReferrersModeOCI11 {
-		return ociremote.WriteSignaturesExperimentalOCI(digest, newSE, walkOpts...)
-	}
-
-	// Publish the signatures associated with this entity
-	return ociremote.WriteSignatures(digest.Repository, newSE, walkOpts...)
+	return ociSig, payload, nil
 }
 
 func signerFromSecurityKey(ctx context.Context, keySlot string) (*SignerVerifier, error) {
@@ -361,8 +556,46 @@ func signerFromSecurityKey(ctx context.Context, keySlot string) (*SignerVerifier
 	}, nil
 }
 
+// signerFromSigningServer returns the signer of the signing service at
+// serverURL, with the certificates it returns.
+func signerFromSigningServer(ctx context.Context, serverURL string) (*SignerVerifier, error) {
+	c, err := signingserver.NewClient(ctx, serverURL, env.Getenv(env.VariableSigningToken))
+	if err != nil {
+		return nil, err
+	}
+	sv := &SignerVerifier{SignerVerifier: c}
+	if s := c.Signer(); s.Certificate != "" {
+		sv.Cert = []byte(s.Certificate)
+		if s.CertificateChain != "" {
+			sv.Chain = []byte(s.CertificateChain)
+		}
+	}
+	return sv, nil
+}
+
+// signerVerifierFromKeyRef reads the key of keyRef. The cosign daemon keeps
+// the clients of KMS keys warm in its workers, for each impersonated service
+// account.
+func signerVerifierFromKeyRef(ctx context.Context, keyRef string, passFunc cosign.PassFunc) (signature.SignerVerifier, error) {
+	if !isKMSRef(keyRef) {
+		return sigs.SignerVerifierFromKeyRef(ctx, keyRef, passFunc)
+	}
+	return daemon.SignerVerifier(ctx, keyRef, gcpauth.ServiceAccount(), func() (signature.SignerVerifier, error) {
+		return sigs.SignerVerifierFromKeyRef(ctx, keyRef, passFunc)
+	})
+}
+
+func isKMSRef(keyRef string) bool {
+	for _, p := range kms.SupportedProviders() {
+		if strings.HasPrefix(keyRef, p) {
+			return true
+		}
+	}
+	return false
+}
+
 func signerFromKeyRef(ctx context.Context, certPath, certChainPath, keyRef string, passFunc cosign.PassFunc) (*SignerVerifier, error) {
-	k, err := sigs.SignerVerifierFromKeyRef(ctx, keyRef, passFunc)
+	k, err := signerVerifierFromKeyRef(ctx, keyRef, passFunc)
 	if err != nil {
 		return nil, fmt.Errorf("reading key: %w", err)
 	}
@@ -512,7 +745,8 @@ func keylessSigner(ctx context.Context, ko options.KeyOpts, sv *SignerVerifier)
 		err error
 	)
 
-	if ko.InsecureSkipFulcioVerify {
+	// The certificates of local and ACME CAs have no SCTs to verify.
+	if ko.InsecureSkipFulcioVerify || localca.IsLocal(ko.FulcioURL) || acmeca.IsACME(ko.FulcioURL) {
 		if k, err = fulcio.NewSigner(ctx, ko, sv); err != nil {
 			return nil, fmt.Errorf("getting key from Fulcio: %w", err)
 		}
@@ -538,6 +772,8 @@ func SignerFromKeyOpts(ctx context.Context, certPath string, certChainPath strin
 		sv, err = signerFromSecurityKey(ctx, ko.Slot)
 	case ko.KeyRef != "":
 		sv, err = signerFromKeyRef(ctx, certPath, certChainPath, ko.KeyRef, ko.PassFunc)
+	case ko.SigningServerURL != "":
+		sv, err = signerFromSigningServer(ctx, ko.SigningServerURL)
 	default:
 		genKey = true
 		ui.Infof(ctx, "Generating ephemeral keys...")
@@ -548,6 +784,10 @@ func SignerFromKeyOpts(ctx context.Context, certPath string, certChainPath strin
 	}
 
 	if ko.IssueCertificateForExistingKey || genKey {
+		if ko.SkipCertificate {
+			ui.Infof(ctx, "Dry run: not requesting a signing certificate from %s, request it with --dry-run-certificate", ko.FulcioURL)
+			return sv, nil
+		}
 		return keylessSigner(ctx, ko, sv)
 	}
 
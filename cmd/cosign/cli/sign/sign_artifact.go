@@ -0,0 +1,80 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign/featuregates"
+	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// SignArtifactCmd signs the OCI artifacts named by refs, such as Helm charts
+// or WASM modules already pushed to a registry, and attaches the signatures
+// to them as OCI 1.1 referrers, so that they are verified like images with
+// cosign verify. The manifests of the artifacts may have any media type.
+func SignArtifactCmd(ctx context.Context, ro *options.RootOptions, ko options.KeyOpts, signOpts options.SignOptions, refs []string) error {
+	if options.NOf(ko.KeyRef, ko.Sk) > 1 {
+		return &options.KeyParseError{}
+	}
+	if err := featuregates.Require(featuregates.OCI11Referrers); err != nil {
+		return err
+	}
+	signOpts.RegistryExperimental.RegistryReferrersMode = options.RegistryReferrersModeOCI11
+
+	ctx, cancel := context.WithTimeout(ctx, ro.Timeout)
+	defer cancel()
+
+	ko.SkipCertificate = signOpts.DryRun.Enabled && !signOpts.DryRun.RequestCertificate
+	sv, err := SignerFromKeyOpts(ctx, signOpts.Cert, signOpts.CertChain, ko)
+	if err != nil {
+		return fmt.Errorf("getting signer: %w", err)
+	}
+	defer sv.Close()
+	dd := cremote.NewDupeDetector(sv)
+
+	opts, err := signOpts.Registry.ClientOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("constructing client options: %w", err)
+	}
+	am, err := signOpts.AnnotationsMap()
+	if err != nil {
+		return fmt.Errorf("getting annotations: %w", err)
+	}
+
+	for _, inputRef := range refs {
+		ref, err := ParseOCIReference(ctx, inputRef, signOpts.Registry.NameOptions()...)
+		if err != nil {
+			return err
+		}
+		se, err := ociremote.SignedArtifact(ref, opts...)
+		if err != nil {
+			return fmt.Errorf("accessing artifact: %w", err)
+		}
+		h, err := se.Digest()
+		if err != nil {
+			return fmt.Errorf("computing digest: %w", err)
+		}
+		digest := ref.Context().Digest(h.String())
+		if err := signDigest(ctx, digest, nil, ko, signOpts, am.Annotations, dd, sv, se); err != nil {
+			return fmt.Errorf("signing %s: %w", inputRef, err)
+		}
+	}
+	return nil
+}
@@ -16,44 +16,54 @@
 package sign
 
 import (
+	"bytes"
 	"context"
-	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa/client"
+	"github.com/sigstore/cosign/v2/internal/pkg/store"
 	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/blob"
 	cbundle "github.com/sigstore/cosign/v2/pkg/cosign/bundle"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
 	internal "github.com/sigstore/cosign/v2/internal/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
 )
 
 // nolint
-func SignBlobCmd(ro *options.RootOptions, ko options.KeyOpts, payloadPath string, b64 bool, outputSignature string, outputCertificate string, tlogUpload bool) ([]byte, error) {
+func SignBlobCmd(ctx context.Context, ro *options.RootOptions, ko options.KeyOpts, payloadPath string, b64 bool, outputSignature string, outputCertificate string, tlogUpload bool) ([]byte, error) {
 	var payload internal.HashReader
 	var err error
 
-	ctx, cancel := context.WithTimeout(context.Background(), ro.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, ro.Timeout)
 	defer cancel()
 
+	alg, err := blob.LookupDigestAlgorithm(ko.BlobDigestAlgorithm)
+	if err != nil {
+		return nil, err
+	}
 	if payloadPath == "-" {
-		payload = internal.NewHashReader(os.Stdin, sha256.New())
+		payload = internal.NewHashReader(os.Stdin, alg.New())
 	} else {
 		ui.Infof(ctx, "Using payload from: %s", payloadPath)
 		f, err := os.Open(filepath.Clean(payloadPath))
 		if err != nil {
 			return nil, err
 		}
-		payload = internal.NewHashReader(f, sha256.New())
+		payload = internal.NewHashReader(f, alg.New())
 	}
 	if err != nil {
 		return nil, err
@@ -65,7 +75,21 @@ func SignBlobCmd(ro *options.RootOptions, ko options.KeyOpts, payloadPath string
 	}
 	defer sv.Close()
 
-	sig, err := sv.SignMessage(&payload, signatureoptions.WithContext(ctx))
+	return signBlob(ctx, ko, sv, payloadPath, &payload, b64, outputSignature, outputCertificate, tlogUpload)
+}
+
+// signBlob signs payload, the blob at payloadPath, with sv and writes the
+// signature, certificate, bundle and RFC3161 timestamp as SignBlobCmd does.
+// nolint
+func signBlob(ctx context.Context, ko options.KeyOpts, sv *SignerVerifier, payloadPath string, payload *internal.HashReader, b64 bool, outputSignature string, outputCertificate string, tlogUpload bool) ([]byte, error) {
+	alg, err := blob.LookupDigestAlgorithm(ko.BlobDigestAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if tlogUpload && !alg.Tlog {
+		return nil, fmt.Errorf("the transparency log doesn't accept signatures over %s blob digests, sign with --tlog-upload=false", alg.Name)
+	}
+	sig, err := signPayload(ctx, sv, payload, alg)
 	if err != nil {
 		return nil, fmt.Errorf("signing blob: %w", err)
 	}
@@ -98,6 +122,24 @@ func SignBlobCmd(ro *options.RootOptions, ko options.KeyOpts, payloadPath string
 		}
 		ui.Infof(ctx, "RFC3161 timestamp written to file %s\n", ko.RFC3161TimestampPath)
 	}
+	// Archived again below with its transparency log bundle, if any.
+	archived := &store.Entry{
+		Kind:             store.KindSignature,
+		Subject:          payloadPath,
+		Digest:           alg.Name + ":" + hex.EncodeToString(payload.Sum(nil)),
+		CreatedAt:        time.Now().UTC(),
+		Signature:        base64.StdEncoding.EncodeToString(sig),
+		Certificate:      string(sv.Cert),
+		Chain:            string(sv.Chain),
+		RFC3161Timestamp: rfc3161Timestamp,
+	}
+	store.Archive(ctx, archived)
+	var rekorAPIVersion uint32
+	if tlogUpload {
+		if ko.RekorURL, rekorAPIVersion, err = RekorLog(ko); err != nil {
+			return nil, err
+		}
+	}
 	shouldUpload, err := ShouldUploadToTlog(ctx, ko, nil, tlogUpload)
 	if err != nil {
 		return nil, fmt.Errorf("upload to tlog: %w", err)
@@ -107,16 +149,27 @@ func SignBlobCmd(ro *options.RootOptions, ko options.KeyOpts, payloadPath string
 		if err != nil {
 			return nil, err
 		}
-		rekorClient, err := rekor.NewClient(ko.RekorURL)
-		if err != nil {
-			return nil, err
-		}
-		entry, err := cosign.TLogUpload(ctx, rekorClient, sig, &payload, rekorBytes)
-		if err != nil {
-			return nil, err
+		if rekorAPIVersion == rekorv2.APIVersion {
+			entry, err := uploadToRekorV2(ctx, ko, sig, payload.Sum(nil), rekorBytes)
+			if err != nil {
+				return nil, err
+			}
+			ui.Infof(ctx, "tlog entry created with index: %d", entry.LogIndex)
+			signedPayload.TransparencyLogEntry = entry
+		} else {
+			rekorClient, err := rekor.NewClient(ko.RekorURL)
+			if err != nil {
+				return nil, err
+			}
+			entry, err := cosign.TLogUpload(ctx, rekorClient, sig, payload, rekorBytes)
+			if err != nil {
+				return nil, err
+			}
+			ui.Infof(ctx, "tlog entry created with index: %d", *entry.LogIndex)
+			signedPayload.Bundle = cbundle.EntryToBundle(entry)
+			archived.Bundle = signedPayload.Bundle
+			store.Archive(ctx, archived)
 		}
-		ui.Infof(ctx, "tlog entry created with index: %d", *entry.LogIndex)
-		signedPayload.Bundle = cbundle.EntryToBundle(entry)
 	}
 
 	// if bundle is specified, just do that and ignore the rest
@@ -159,25 +212,54 @@ func SignBlobCmd(ro *options.RootOptions, ko options.KeyOpts, payloadPath string
 	}
 
 	if outputCertificate != "" {
-		certBytes, err := extractCertificate(ctx, sv)
-		if err != nil {
+		if err := writeCertificate(ctx, sv, outputCertificate, b64); err != nil {
 			return nil, err
 		}
-		if certBytes != nil {
-			bts := certBytes
-			if b64 {
-				bts = []byte(base64.StdEncoding.EncodeToString(certBytes))
-			}
-			if err := os.WriteFile(outputCertificate, bts, 0600); err != nil {
-				return nil, fmt.Errorf("create certificate file: %w", err)
-			}
-			ui.Infof(ctx, "Wrote certificate to file %s", outputCertificate)
-		}
 	}
 
 	return sig, nil
 }
 
+// signPayload signs payload with sv. Unless alg is the default, the digest of
+// payload with alg is signed instead of payload, which payload computes.
+func signPayload(ctx context.Context, sv *SignerVerifier, payload *internal.HashReader, alg blob.DigestAlgorithm) ([]byte, error) {
+	if alg.IsDefault() {
+		return sv.SignMessage(payload, signatureoptions.WithContext(ctx))
+	}
+	pub, err := sv.PublicKey(signatureoptions.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := alg.CheckKey(pub); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, payload); err != nil {
+		return nil, err
+	}
+	opts := append(alg.SignOptions(payload.Sum(nil)), signatureoptions.WithContext(ctx))
+	return sv.SignMessage(bytes.NewReader(nil), opts...)
+}
+
+// writeCertificate writes the certificate of sv, if it has one, to
+// outputCertificate.
+func writeCertificate(ctx context.Context, sv *SignerVerifier, outputCertificate string, b64 bool) error {
+	certBytes, err := extractCertificate(ctx, sv)
+	if err != nil {
+		return err
+	}
+	if certBytes != nil {
+		bts := certBytes
+		if b64 {
+			bts = []byte(base64.StdEncoding.EncodeToString(certBytes))
+		}
+		if err := os.WriteFile(outputCertificate, bts, 0600); err != nil {
+			return fmt.Errorf("create certificate file: %w", err)
+		}
+		ui.Infof(ctx, "Wrote certificate to file %s", outputCertificate)
+	}
+	return nil
+}
+
 // Extract an encoded certificate from the SignerVerifier. Returns (nil, nil) if verifier is not a certificate.
 func extractCertificate(ctx context.Context, sv *SignerVerifier) ([]byte, error) {
 	signer, err := sv.Bytes(ctx)
@@ -0,0 +1,169 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	internal "github.com/sigstore/cosign/v2/internal/pkg/cosign"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/blob"
+)
+
+// SignBlobsCmd signs the blobs, paths or globs, with one signer, so that
+// keyless signing gets a single certificate from Fulcio, and writes the
+// signature and bundle of each blob, and its RFC3161 timestamp if
+// ko.TSAServerURL is set, to outputDir, named with the outputName template,
+// and their OutputIndex.
+func SignBlobsCmd(ctx context.Context, ro *options.RootOptions, ko options.KeyOpts, blobs []string, b64 bool, outputDir, outputName, outputCertificate string, tlogUpload bool) error {
+	paths, err := ExpandBlobs(blobs)
+	if err != nil {
+		return err
+	}
+	out, err := NewOutputDir("sign-blob", outputDir, outputName)
+	if err != nil {
+		return err
+	}
+	kinds := []string{OutputSignature, OutputBundle}
+	if ko.TSAServerURL != "" {
+		kinds = append(kinds, OutputTimestamp)
+	}
+	entries := make([]*OutputIndexEntry, 0, len(paths))
+	for _, path := range paths {
+		digest, err := fileDigest(path)
+		if err != nil {
+			return err
+		}
+		e, err := out.Entry(path, digest, kinds...)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+	}
+
+	signerCtx, cancel := context.WithTimeout(ctx, ro.Timeout)
+	defer cancel()
+	sv, err := SignerFromKeyOpts(signerCtx, "", "", ko)
+	if err != nil {
+		return err
+	}
+	defer sv.Close()
+
+	for _, e := range entries {
+		if err := signBlobTo(ctx, ro, ko, sv, e, out, b64, tlogUpload); err != nil {
+			return fmt.Errorf("signing %s: %w", e.Path, err)
+		}
+		out.Add(e)
+	}
+
+	if outputCertificate != "" {
+		if err := writeCertificate(ctx, sv, outputCertificate, b64); err != nil {
+			return err
+		}
+		if _, err := os.Stat(outputCertificate); err != nil {
+			// The signer has no certificate.
+			outputCertificate = ""
+		}
+	}
+	if err := out.WriteIndex(outputCertificate); err != nil {
+		return err
+	}
+	ui.Infof(ctx, "Signed %d blobs, listed in %s", len(entries), filepath.Join(outputDir, OutputIndexFile))
+	return nil
+}
+
+// signBlobTo signs the blob of e with sv, writing its files to out.
+func signBlobTo(ctx context.Context, ro *options.RootOptions, ko options.KeyOpts, sv *SignerVerifier, e *OutputIndexEntry, out *OutputDir, b64 bool, tlogUpload bool) error {
+	ctx, cancel := context.WithTimeout(ctx, ro.Timeout)
+	defer cancel()
+
+	ui.Infof(ctx, "Using payload from: %s", e.Path)
+	f, err := os.Open(filepath.Clean(e.Path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	alg, err := blob.LookupDigestAlgorithm(ko.BlobDigestAlgorithm)
+	if err != nil {
+		return err
+	}
+	payload := internal.NewHashReader(f, alg.New())
+
+	ko.BundlePath = out.Path(e, OutputBundle)
+	ko.RFC3161TimestampPath = out.Path(e, OutputTimestamp)
+	_, err = signBlob(ctx, ko, sv, e.Path, &payload, b64, out.Path(e, OutputSignature), "", tlogUpload)
+	return err
+}
+
+// fileDigest returns the hex sha256 digest of the file at path.
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ExpandBlobs returns the paths of blobs, expanding the globs, which must
+// each match a file.
+func ExpandBlobs(blobs []string) ([]string, error) {
+	var paths []string
+	seen := map[string]bool{}
+	for _, b := range blobs {
+		if b == "-" {
+			return nil, errors.New("blobs can't be read from standard input with --output-dir")
+		}
+		matches := []string{b}
+		if strings.ContainsAny(b, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(b); err != nil {
+				return nil, fmt.Errorf("invalid glob %s: %w", b, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no blobs match %s", b)
+			}
+		}
+		for _, m := range matches {
+			if fi, err := os.Stat(m); err != nil {
+				return nil, err
+			} else if fi.IsDir() {
+				continue
+			}
+			if m = filepath.Clean(m); !seen[m] {
+				seen[m] = true
+				paths = append(paths, m)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no blobs to sign")
+	}
+	return paths, nil
+}
@@ -0,0 +1,143 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestSignBlobsCmd(t *testing.T) {
+	td := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(td, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	write("dist/app-linux.tar.gz", "linux")
+	write("dist/app-darwin.tar.gz", "darwin")
+	sums := write("dist/SHA256SUMS", "sums")
+	write("other/SHA256SUMS", "other sums")
+
+	keyFile, _, _, privKey, _, _ := generateCertificateFiles(t, t.TempDir(), pass("foo"))
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	ko := options.KeyOpts{KeyRef: keyFile, PassFunc: pass("foo"), SkipConfirmation: true}
+	verifier, err := signature.LoadVerifier(privKey.Public(), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(td, "signatures")
+	if err := SignBlobsCmd(context.Background(), ro, ko, []string{filepath.Join(td, "dist", "*.tar.gz"), sums}, true, out, "", "", false); err != nil {
+		t.Fatalf("SignBlobsCmd() = %v", err)
+	}
+	for name, content := range map[string]string{"app-linux.tar.gz": "linux", "app-darwin.tar.gz": "darwin", "SHA256SUMS": "sums"} {
+		b64, err := os.ReadFile(filepath.Join(out, name+".sig"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := base64.StdEncoding.DecodeString(string(b64))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := verifier.VerifySignature(bytes.NewReader(sig), strings.NewReader(content)); err != nil {
+			t.Errorf("signature of %s: %v", name, err)
+		}
+		b, err := os.ReadFile(filepath.Join(out, name+".bundle"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bundle := cosign.LocalSignedPayload{}
+		if err := json.Unmarshal(b, &bundle); err != nil || bundle.Base64Signature != string(b64) {
+			t.Errorf("bundle of %s = %s, %v, want its signature", name, b, err)
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(out, OutputIndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := OutputIndex{}
+	if err := json.Unmarshal(b, &index); err != nil {
+		t.Fatal(err)
+	}
+	if index.Schema != OutputIndexSchema || index.Command != "sign-blob" || len(index.Artifacts) != 3 {
+		t.Fatalf("index = %s, want the 3 signed blobs", b)
+	}
+	if e := index.Artifacts[2]; e.Name != "SHA256SUMS" || e.Path != sums || !strings.HasPrefix(e.Digest, "sha256:") ||
+		e.Files[OutputSignature] != "SHA256SUMS.sig" || e.Files[OutputBundle] != "SHA256SUMS.bundle" {
+		t.Errorf("index entry = %+v, want the files of SHA256SUMS", e)
+	}
+
+	out = filepath.Join(td, "named")
+	if err := SignBlobsCmd(context.Background(), ro, ko, []string{sums, filepath.Join(td, "other", "SHA256SUMS")}, true, out, "{name}.{digest}.{kind}", "", false); err != nil {
+		t.Fatalf("SignBlobsCmd() with {digest} = %v", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(out, "SHA256SUMS.*.bundle"))
+	if err != nil || len(matches) != 2 {
+		t.Errorf("bundles named with {digest} = %v, %v, want 2", matches, err)
+	}
+
+	for name, tc := range map[string]struct {
+		blobs []string
+		name  string
+		want  string
+	}{
+		"same name": {
+			blobs: []string{sums, filepath.Join(td, "other", "SHA256SUMS")},
+			want:  "would both be named SHA256SUMS.sig",
+		},
+		"no kind": {
+			blobs: []string{sums},
+			name:  "{name}.{alg}",
+			want:  "must have a {kind} placeholder",
+		},
+		"another directory": {
+			blobs: []string{sums},
+			name:  "../{name}.{kind}",
+			want:  "must name a file in --output-dir",
+		},
+		"no match": {
+			blobs: []string{filepath.Join(td, "dist", "*.zip")},
+			want:  "no blobs match",
+		},
+		"stdin": {
+			blobs: []string{"-"},
+			want:  "standard input",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := SignBlobsCmd(context.Background(), ro, ko, tc.blobs, true, t.TempDir(), tc.name, "", false); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("SignBlobsCmd() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci/layout"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+)
+
+// signLocalImages signs the images on disk at paths, OCI layouts or docker
+// tarballs, and stores the signatures in their OCI layouts.
+func signLocalImages(ctx context.Context, ko options.KeyOpts, signOpts options.SignOptions, payload []byte,
+	annotations map[string]interface{}, dd mutate.DupeDetector, sv *SignerVerifier, paths []string) error {
+	if signOpts.Recursive || signOpts.Attachment != "" || signOpts.SignConverted || signOpts.RegistryExperimental.RegistryReferrersMode.Referrers() {
+		return errors.New("--local-image can't be used with --recursive, --attachment, --sign-converted or --registry-referrers-mode")
+	}
+	if signOpts.LocalImage.Output != "" && len(paths) > 1 {
+		return errors.New("--local-image-output can only be used with a single image")
+	}
+
+	for _, path := range paths {
+		l, err := layout.LoadLocal(path)
+		if err != nil {
+			return fmt.Errorf("loading local image %s: %w", path, err)
+		}
+		dst, err := signOpts.LocalImage.Destination(path, l.Tarball)
+		if err != nil {
+			return err
+		}
+		digest, err := l.Reference()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if len(signOpts.EncryptionRecipients) > 0 {
+			if err := cosign.CheckEncryptedImage(l.Entity); err != nil {
+				return fmt.Errorf("%s can't list encryption recipients: %w", path, err)
+			}
+		}
+
+		ociSig, signed, err := newSignature(ctx, digest, payload, ko, signOpts, annotations, sv)
+		if err != nil {
+			return fmt.Errorf("signing %s: %w", path, err)
+		}
+		if !signOpts.Upload {
+			continue
+		}
+		newSE, err := mutate.AttachSignatureToEntity(l.Entity, ociSig, mutate.WithDupeDetector(dd))
+		if err != nil {
+			return err
+		}
+		if signOpts.DryRun.Enabled {
+			sigs, err := newSE.Signatures()
+			if err != nil {
+				return err
+			}
+			if err := DryRunPush(ctx, "signature", "the OCI layout "+dst, sigs, len(signed)); err != nil {
+				return err
+			}
+			continue
+		}
+		ui.Infof(ctx, "Writing signature of %s to the OCI layout %s", digest.DigestStr(), dst)
+		if err := l.Write(dst, newSE); err != nil {
+			return fmt.Errorf("writing %s: %w", dst, err)
+		}
+	}
+	return nil
+}
@@ -16,24 +16,47 @@
 package sign
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
+	"io"
+	"log"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/cosign/signingserver"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/test"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
 	"github.com/theupdateframework/go-tuf/encrypted"
 )
 
@@ -122,7 +145,7 @@ func TestSignCmdLocalKeyAndSk(t *testing.T) {
 		},
 	} {
 		so := options.SignOptions{}
-		err := SignCmd(ro, ko, so, nil)
+		err := SignCmd(context.Background(), ro, ko, so, nil)
 		if (errors.Is(err, &options.KeyParseError{}) == false) {
 			t.Fatal("expected KeyParseError")
 		}
@@ -229,3 +252,538 @@ func Test_ParseOCIReference(t *testing.T) {
 		}
 	}
 }
+
+func Test_withExpiry(t *testing.T) {
+	before := time.Now()
+	got, err := withExpiry(map[string]interface{}{"env": "prod"}, "90d", false)
+	if err != nil {
+		t.Fatalf("withExpiry() unexpected error: %v", err)
+	}
+	if got["env"] != "prod" {
+		t.Errorf("withExpiry() dropped the existing annotations: %v", got)
+	}
+	expires, err := time.Parse(time.RFC3339, got[cosign.ExpiresAnnotation].(string))
+	if err != nil {
+		t.Fatalf("invalid expiry annotation: %v", err)
+	}
+	if want := before.Add(90 * 24 * time.Hour).Truncate(time.Second); expires.Before(want) || expires.After(want.Add(time.Minute)) {
+		t.Errorf("expiry = %s, want about %s", expires, want)
+	}
+
+	if _, err := withExpiry(nil, "12h", true); err == nil {
+		t.Error("withExpiry() with a static payload: expected an error")
+	}
+	if _, err := withExpiry(map[string]interface{}{cosign.ExpiresAnnotation: "x"}, "12h", false); err == nil {
+		t.Error("withExpiry() with an expiry annotation: expected an error")
+	}
+	if _, err := withExpiry(nil, "soon", false); err == nil {
+		t.Error("withExpiry() with an invalid duration: expected an error")
+	}
+}
+
+func Test_withEncryptionRecipients(t *testing.T) {
+	alice, bob := "sha256:"+strings.Repeat("a", 64), "SHA256:"+strings.Repeat("B", 64)
+	got, err := withEncryptionRecipients(map[string]interface{}{"env": "prod"}, []string{alice, bob}, false)
+	if err != nil {
+		t.Fatalf("withEncryptionRecipients() unexpected error: %v", err)
+	}
+	if got["env"] != "prod" {
+		t.Errorf("withEncryptionRecipients() dropped the existing annotations: %v", got)
+	}
+	if want := alice + "," + strings.ToLower(bob); got[cosign.EncryptionRecipientsAnnotation] != want {
+		t.Errorf("recipients annotation = %v, want %s", got[cosign.EncryptionRecipientsAnnotation], want)
+	}
+
+	if _, err := withEncryptionRecipients(nil, []string{alice}, true); err == nil {
+		t.Error("withEncryptionRecipients() with a static payload: expected an error")
+	}
+	if _, err := withEncryptionRecipients(map[string]interface{}{cosign.EncryptionRecipientsAnnotation: "x"}, []string{alice}, false); err == nil {
+		t.Error("withEncryptionRecipients() with a recipients annotation: expected an error")
+	}
+	if _, err := withEncryptionRecipients(nil, []string{filepath.Join(t.TempDir(), "missing.pub")}, false); err == nil {
+		t.Error("withEncryptionRecipients() with a missing key: expected an error")
+	}
+}
+
+// TestSignCmdDryRun verifies that a dry run of keyless signing neither
+// requests a certificate nor pushes the signature.
+func TestSignCmdDryRun(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := repo.Digest(h.String())
+	if err := remote.Write(digest, img); err != nil {
+		t.Fatal(err)
+	}
+
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	// The Fulcio URL is unreachable, so requesting a certificate would fail.
+	ko := options.KeyOpts{FulcioURL: "http://127.0.0.1:0", SkipConfirmation: true}
+	so := options.SignOptions{Upload: true, DryRun: options.DryRunOptions{Enabled: true}}
+	if err := SignCmd(context.Background(), ro, ko, so, []string{digest.String()}); err != nil {
+		t.Fatalf("SignCmd() unexpected error: %v", err)
+	}
+
+	tag, err := ociremote.SignatureTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(tag); err == nil {
+		t.Errorf("dry run pushed %s", tag)
+	}
+}
+
+// TestSignCmdSigningServer verifies that images are signed with the key of a
+// signing server, which is sent the token.
+func TestSignCmdSigningServer(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := repo.Digest(h.String())
+	if err := remote.Write(digest, img); err != nil {
+		t.Fatal(err)
+	}
+
+	priv, err := cosign.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(signingserver.NewHandler(sv, signingserver.Signer{PublicKey: string(pub)}, "s3cr3t"))
+	t.Cleanup(server.Close)
+	t.Setenv(env.VariableSigningToken.String(), "s3cr3t")
+
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	ko := options.KeyOpts{SigningServerURL: server.URL, SkipConfirmation: true}
+	so := options.SignOptions{Upload: true}
+	if err := SignCmd(context.Background(), ro, ko, so, []string{digest.String()}); err != nil {
+		t.Fatalf("SignCmd() unexpected error: %v", err)
+	}
+	co := &cosign.CheckOpts{SigVerifier: sv, IgnoreTlog: true, ClaimVerifier: cosign.SimpleClaimVerifier}
+	if _, _, err := cosign.VerifyImageSignatures(context.Background(), digest, co); err != nil {
+		t.Errorf("the signature of the signing server doesn't verify: %v", err)
+	}
+
+	ko.KeyRef = "cosign.key"
+	if err := SignCmd(context.Background(), ro, ko, so, []string{digest.String()}); err == nil || !strings.Contains(err.Error(), "--signing-server") {
+		t.Errorf("SignCmd() with --signing-server and --key = %v", err)
+	}
+}
+
+// TestSignCmdResumeFrom verifies that --resume-from skips the images before
+// it, and that an interrupted run reports where to resume.
+func TestSignCmdResumeFrom(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var digests, hexes []string
+	for i := 0; i < 2; i++ {
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := repo.Digest(h.String())
+		if err := remote.Write(digest, img); err != nil {
+			t.Fatal(err)
+		}
+		digests = append(digests, digest.String())
+		hexes = append(hexes, h.Hex)
+	}
+
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	ko := options.KeyOpts{FulcioURL: "http://127.0.0.1:0", SkipConfirmation: true}
+	so := options.SignOptions{Upload: true, DryRun: options.DryRunOptions{Enabled: true}, ResumeFrom: digests[1]}
+	out := ui.RunWithTestCtx(func(ctx context.Context, _ ui.WriteFunc) {
+		if err := SignCmd(ctx, ro, ko, so, digests); err != nil {
+			t.Fatalf("SignCmd() unexpected error: %v", err)
+		}
+	})
+	if strings.Contains(out, hexes[0]) || !strings.Contains(out, hexes[1]) {
+		t.Errorf("SignCmd() with --resume-from signed the wrong images:\n%s", out)
+	}
+
+	so.ResumeFrom = "unknown"
+	if err := SignCmd(context.Background(), ro, ko, so, digests); err == nil || !strings.Contains(err.Error(), "--resume-from") {
+		t.Errorf("SignCmd() = %v, want an error for an unknown --resume-from", err)
+	}
+
+	so.ResumeFrom = ""
+	out = ui.RunWithTestCtx(func(ctx context.Context, _ ui.WriteFunc) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if err := SignCmd(ctx, ro, ko, so, digests); err == nil {
+			t.Error("SignCmd() with a cancelled context succeeded")
+		}
+	})
+	if !strings.Contains(out, "Interrupted") || !strings.Contains(out, "--resume-from") || !strings.Contains(out, digests[1]) {
+		t.Errorf("SignCmd() interrupted did not report the pending images:\n%s", out)
+	}
+}
+
+func TestRekorLog(t *testing.T) {
+	now := time.Now()
+	sc := &cosign.SigningConfig{RekorTlogURLs: []cosign.Service{
+		{URL: "https://rekor.example.com", MajorAPIVersion: 1},
+		{URL: "https://log.example.com", MajorAPIVersion: 2, ValidFor: cosign.ValidityPeriod{Start: now.Add(-time.Hour)}},
+	}}
+
+	url, version, err := RekorLog(options.KeyOpts{RekorURL: options.DefaultRekorURL})
+	if err != nil || url != options.DefaultRekorURL || version != 1 {
+		t.Errorf("RekorLog() without a signing config = %s, %d, %v", url, version, err)
+	}
+
+	t.Setenv(env.VariableFeatureGates.String(), "RekorV2=false")
+	if _, _, err := RekorLog(options.KeyOpts{SigningConfig: sc}); err == nil || !strings.Contains(err.Error(), "RekorV2") {
+		t.Errorf("RekorLog() = %v, want the disabled feature gate", err)
+	}
+
+	t.Setenv(env.VariableFeatureGates.String(), "RekorV2=true")
+	url, version, err = RekorLog(options.KeyOpts{RekorURL: options.DefaultRekorURL, SigningConfig: sc})
+	if err != nil || url != "https://log.example.com" || version != 2 {
+		t.Errorf("RekorLog() = %s, %d, %v", url, version, err)
+	}
+}
+
+// TestSignArtifactCmd verifies that a Helm chart pushed as an OCI artifact is
+// signed with an OCI 1.1 referrer that verifies like an image signature.
+func TestSignArtifactCmd(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true), registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/charts/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart := mutate.ConfigMediaType(empty.Image, "application/vnd.cncf.helm.config.v1+json")
+	h, err := chart.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := repo.Digest(h.String())
+	if err := remote.Write(digest, chart); err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile, _, _, privKey, _, _ := generateCertificateFiles(t, t.TempDir(), pass("foo"))
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	ko := options.KeyOpts{KeyRef: keyFile, PassFunc: pass("foo"), SkipConfirmation: true}
+	so := options.SignOptions{Upload: true}
+
+	t.Setenv(env.VariableFeatureGates.String(), "OCI11Referrers=false")
+	if err := SignArtifactCmd(context.Background(), ro, ko, so, []string{digest.String()}); err == nil || !strings.Contains(err.Error(), "OCI11Referrers") {
+		t.Fatalf("SignArtifactCmd() = %v, want the disabled feature gate", err)
+	}
+
+	t.Setenv(env.VariableFeatureGates.String(), "OCI11Referrers=true")
+	if err := SignArtifactCmd(context.Background(), ro, ko, so, []string{digest.String()}); err != nil {
+		t.Fatalf("SignArtifactCmd() = %v", err)
+	}
+
+	tag, err := ociremote.SignatureTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(tag); err == nil {
+		t.Errorf("signing the artifact pushed the legacy signature tag %s", tag)
+	}
+	verifier, err := signature.LoadECDSAVerifier(&privKey.PublicKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	co := &cosign.CheckOpts{SigVerifier: verifier, IgnoreTlog: true, ClaimVerifier: cosign.SimpleClaimVerifier}
+	sigs, _, err := cosign.VerifyImageSignatures(context.Background(), digest, co)
+	if err != nil {
+		t.Fatalf("VerifyImageSignatures() = %v", err)
+	}
+	if len(sigs) != 1 {
+		t.Errorf("verified %d signatures, want 1", len(sigs))
+	}
+}
+
+// TestSignCmdSHA512 verifies that an image addressed by a sha512 digest is
+// signed under that digest, and that the signature verifies.
+func TestSignCmdSHA512(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := img.RawManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := oci.ComputeDigest("sha512", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := repo.Digest(h.String())
+	if err := remote.Write(digest, img); err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile, _, _, privKey, _, _ := generateCertificateFiles(t, t.TempDir(), pass("foo"))
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	ko := options.KeyOpts{KeyRef: keyFile, PassFunc: pass("foo"), SkipConfirmation: true}
+	so := options.SignOptions{Upload: true}
+	if err := SignCmd(context.Background(), ro, ko, so, []string{digest.String()}); err != nil {
+		t.Fatalf("SignCmd() = %v", err)
+	}
+
+	tag, err := ociremote.SignatureTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "sha512-" + h.Hex + ".sig"; tag.TagStr() != want {
+		t.Errorf("SignatureTag() = %s, want %s", tag.TagStr(), want)
+	}
+	verifier, err := signature.LoadECDSAVerifier(&privKey.PublicKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	co := &cosign.CheckOpts{SigVerifier: verifier, IgnoreTlog: true, ClaimVerifier: cosign.SimpleClaimVerifier}
+	sigs, _, err := cosign.VerifyImageSignatures(context.Background(), digest, co)
+	if err != nil {
+		t.Fatalf("VerifyImageSignatures() = %v", err)
+	}
+	if len(sigs) != 1 {
+		t.Fatalf("verified %d signatures, want 1", len(sigs))
+	}
+	p, err := sigs[0].Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(p), h.String()) {
+		t.Errorf("signed payload %s does not name %s", p, h)
+	}
+
+	// A manifest that doesn't match the sha512 digest is rejected.
+	other := repo.Digest("sha512:" + strings.Repeat("0", 128))
+	if err := remote.Write(other, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignCmd(context.Background(), ro, ko, so, []string{other.String()}); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("SignCmd() = %v, want a digest mismatch", err)
+	}
+}
+
+// TestSignCmdReferrersModeBoth verifies that --registry-referrers-mode=both
+// writes the signature as a referrer and to the legacy signature tag.
+func TestSignCmdReferrersModeBoth(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true), registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := repo.Digest(h.String())
+	if err := remote.Write(digest, img); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(env.VariableFeatureGates.String(), "OCI11Referrers=true")
+	keyFile, _, _, _, _, _ := generateCertificateFiles(t, t.TempDir(), pass("foo"))
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	ko := options.KeyOpts{KeyRef: keyFile, PassFunc: pass("foo"), SkipConfirmation: true}
+	so := options.SignOptions{
+		Upload:               true,
+		RegistryExperimental: options.RegistryExperimentalOptions{RegistryReferrersMode: options.RegistryReferrersModeBoth},
+	}
+	if err := SignCmd(context.Background(), ro, ko, so, []string{digest.String()}); err != nil {
+		t.Fatalf("SignCmd() = %v", err)
+	}
+
+	tag, err := ociremote.SignatureTag(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := ociremote.Signatures(tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := sigs.Get(); err != nil || len(got) != 1 {
+		t.Errorf("the signature tag has %d signatures, %v, want 1", len(got), err)
+	}
+	index, err := ociremote.Referrers(digest, "application/vnd.dev.cosign.artifact.sig.v1+json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Manifests) != 1 {
+		t.Errorf("got %d signature referrers, want 1", len(index.Manifests))
+	}
+}
+
+// TestSignCmdLocalImage verifies that the signatures of images on disk are
+// stored in their OCI layout.
+func TestSignCmdLocalImage(t *testing.T) {
+	td := t.TempDir()
+	keyFile, _, _, privKey, _, _ := generateCertificateFiles(t, td, pass("foo"))
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(td, "oci")
+	p, err := layout.Write(path, empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AppendImage(img, layout.WithAnnotations(map[string]string{"io.containerd.image.name": "example.com/app:1.0"})); err != nil {
+		t.Fatal(err)
+	}
+	tarPath := filepath.Join(td, "image.tar")
+	if err := tarball.WriteToFile(tarPath, name.MustParseReference("example.com/app:1.0"), img); err != nil {
+		t.Fatal(err)
+	}
+
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	ko := options.KeyOpts{KeyRef: keyFile, PassFunc: pass("foo"), SkipConfirmation: true}
+	so := options.SignOptions{Upload: true, LocalImage: options.LocalImageOptions{Enabled: true}}
+	verifier, err := signature.LoadECDSAVerifier(&privKey.PublicKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	co := &cosign.CheckOpts{SigVerifier: verifier, IgnoreTlog: true, ClaimVerifier: cosign.SimpleClaimVerifier}
+	// Signing again with the same key doesn't add a duplicate signature.
+	for i := 0; i < 2; i++ {
+		if err := SignCmd(context.Background(), ro, ko, so, []string{path}); err != nil {
+			t.Fatalf("SignCmd() = %v", err)
+		}
+		sigs, _, err := cosign.VerifyLocalImageSignatures(context.Background(), path, co)
+		if err != nil {
+			t.Fatalf("VerifyLocalImageSignatures() = %v", err)
+		}
+		if len(sigs) != 1 {
+			t.Errorf("verified %d signatures, want 1", len(sigs))
+		}
+	}
+
+	if err := SignCmd(context.Background(), ro, ko, so, []string{tarPath}); err == nil || !strings.Contains(err.Error(), "--local-image-output") {
+		t.Errorf("SignCmd() of a tarball = %v, want an error for the missing --local-image-output", err)
+	}
+	so.LocalImage.Output = filepath.Join(td, "signed")
+	if err := SignCmd(context.Background(), ro, ko, so, []string{tarPath}); err != nil {
+		t.Fatalf("SignCmd() of a tarball = %v", err)
+	}
+	if _, _, err := cosign.VerifyLocalImageSignatures(context.Background(), so.LocalImage.Output, co); err != nil {
+		t.Errorf("VerifyLocalImageSignatures() of the signed tarball = %v", err)
+	}
+	if _, _, err := cosign.VerifyLocalImageSignatures(context.Background(), tarPath, co); err == nil || !strings.Contains(err.Error(), "docker tarball") {
+		t.Errorf("VerifyLocalImageSignatures() of a tarball = %v", err)
+	}
+}
+
+// rawManifest is a manifest of any media type, pushed with remote.Put.
+type rawManifest struct {
+	mediaType types.MediaType
+	b         []byte
+}
+
+func (m rawManifest) RawManifest() ([]byte, error)        { return m.b, nil }
+func (m rawManifest) MediaType() (types.MediaType, error) { return m.mediaType, nil }
+func (m rawManifest) descriptor(p *v1.Platform) v1.Descriptor {
+	h, size, _ := v1.SHA256(bytes.NewReader(m.b))
+	return v1.Descriptor{MediaType: m.mediaType, Size: size, Digest: h, Platform: p, ArtifactType: "application/vnd.example.plugin.v1"}
+}
+
+// TestSignCmdRecursiveArtifacts verifies that --recursive signs the artifacts
+// of an index that are neither images nor indexes, such as the platforms of
+// a Helm plugin, and that each of them verifies.
+func TestSignCmdRecursiveArtifacts(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/plugins/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := v1.IndexManifest{SchemaVersion: 2, MediaType: types.OCIImageIndex}
+	var digests []name.Digest
+	for _, arch := range []string{"amd64", "arm64"} {
+		m := rawManifest{
+			mediaType: "application/vnd.example.plugin.manifest.v1+json",
+			b:         []byte(`{"mediaType":"application/vnd.example.plugin.manifest.v1+json","arch":"` + arch + `"}`),
+		}
+		desc := m.descriptor(&v1.Platform{OS: "linux", Architecture: arch})
+		digest := repo.Digest(desc.Digest.String())
+		if err := remote.Put(digest, m); err != nil {
+			t.Fatal(err)
+		}
+		idx.Manifests = append(idx.Manifests, desc)
+		digests = append(digests, digest)
+	}
+	b, err := json.Marshal(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := rawManifest{mediaType: types.OCIImageIndex, b: b}
+	digest := repo.Digest(index.descriptor(nil).Digest.String())
+	if err := remote.Put(digest, index); err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile, _, _, privKey, _, _ := generateCertificateFiles(t, t.TempDir(), pass("foo"))
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	ko := options.KeyOpts{KeyRef: keyFile, PassFunc: pass("foo"), SkipConfirmation: true}
+	so := options.SignOptions{Upload: true, Recursive: true}
+	if err := SignCmd(context.Background(), ro, ko, so, []string{digest.String()}); err != nil {
+		t.Fatalf("SignCmd() = %v", err)
+	}
+
+	verifier, err := signature.LoadECDSAVerifier(&privKey.PublicKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	co := &cosign.CheckOpts{SigVerifier: verifier, IgnoreTlog: true, ClaimVerifier: cosign.SimpleClaimVerifier}
+	for _, d := range append(digests, digest) {
+		if _, _, err := cosign.VerifyImageSignatures(context.Background(), d, co); err != nil {
+			t.Errorf("VerifyImageSignatures(%s) = %v", d, err)
+		}
+	}
+}
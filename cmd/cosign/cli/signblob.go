@@ -16,12 +16,15 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/blob"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -51,13 +54,37 @@ func SignBlob() *cobra.Command {
   cosign sign-blob --key gcpkms://projects/[PROJECT]/locations/global/keyRings/[KEYRING]/cryptoKeys/[KEY] <FILE>
 
   # sign a blob with a key pair stored in Hashicorp Vault
-  cosign sign-blob --key hashivault://[KEY] <FILE>`,
+  cosign sign-blob --key hashivault://[KEY] <FILE>
+
+  # sign a Helm chart or WASM module pushed as an OCI artifact, attaching the
+  # signature as an OCI 1.1 referrer to be verified with cosign verify
+  cosign sign-blob --oci --key cosign.key <ARTIFACT DIGEST>
+
+  # sign the binaries of a release keyless with one certificate, writing dist/signatures/<name>.sig and <name>.bundle,
+  # and their index dist/signatures/index.json
+  cosign sign-blob --yes --output-dir dist/signatures --output-certificate dist/signatures/release.pem 'dist/*.tar.gz' dist/SHA256SUMS
+
+  # sign the BLAKE3 digest of a blob with an ECDSA key, without the transparency log
+  cosign sign-blob --key cosign.key --digest-algorithm blake3 --tlog-upload=false <FILE>`,
 		Args:             cobra.MinimumNArgs(1),
 		PersistentPreRun: options.BindViper,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if options.NOf(o.Key, o.SecurityKey.Use) > 1 {
 				return &options.KeyParseError{}
 			}
+			if o.OCI && (o.BundlePath != "" || o.RFC3161TimestampPath != "" || o.Output != "") {
+				return errors.New("--bundle, --rfc3161-timestamp and --output can't be used with --oci, the signature is attached to the artifact")
+			}
+			alg, err := blob.LookupDigestAlgorithm(o.Digest.Algorithm)
+			if err != nil {
+				return err
+			}
+			if o.OCI && !alg.IsDefault() {
+				return errors.New("--digest-algorithm can't be used with --oci, the artifact is signed by its manifest digest")
+			}
+			if o.OutputDir.Dir != "" && (o.OCI || o.BundlePath != "" || o.RFC3161TimestampPath != "" || o.Output != "" || o.OutputSignature != "") {
+				return errors.New("--oci, --bundle, --rfc3161-timestamp, --output and --output-signature can't be used with --output-dir, the files of each blob are named after it")
+			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -73,17 +100,45 @@ func SignBlob() *cobra.Command {
 				FulcioURL:                      o.Fulcio.URL,
 				IDToken:                        o.Fulcio.IdentityToken,
 				InsecureSkipFulcioVerify:       o.Fulcio.InsecureSkipFulcioVerify,
+				ACME:                           o.Fulcio.ACME,
 				RekorURL:                       o.Rekor.URL,
 				OIDCIssuer:                     o.OIDC.Issuer,
 				OIDCClientID:                   o.OIDC.ClientID,
 				OIDCClientSecret:               oidcClientSecret,
 				OIDCRedirectURL:                o.OIDC.RedirectURL,
 				OIDCDisableProviders:           o.OIDC.DisableAmbientProviders,
+				OIDCTokenFile:                  o.OIDC.TokenFile,
+				OIDCAudience:                   o.OIDC.Audience,
+				OIDCTokenExchangeIssuer:        o.OIDC.TokenExchangeIssuer,
 				BundlePath:                     o.BundlePath,
 				SkipConfirmation:               o.SkipConfirmation,
 				TSAServerURL:                   o.TSAServerURL,
 				RFC3161TimestampPath:           o.RFC3161TimestampPath,
 				IssueCertificateForExistingKey: o.IssueCertificate,
+				BlobDigestAlgorithm:            o.Digest.Algorithm,
+			}
+			if o.SigningConfig != "" {
+				if ko.SigningConfig, err = cosign.LoadSigningConfig(o.SigningConfig); err != nil {
+					return err
+				}
+			}
+
+			if o.OCI {
+				signOpts := options.SignOptions{
+					Upload:            true,
+					OutputSignature:   o.OutputSignature,
+					OutputCertificate: o.OutputCertificate,
+					SkipConfirmation:  o.SkipConfirmation,
+					TlogUpload:        o.TlogUpload,
+					TSAServerURL:      o.TSAServerURL,
+					IssueCertificate:  o.IssueCertificate,
+					Registry:          o.Registry,
+				}
+				return sign.SignArtifactCmd(cmd.Context(), ro, ko, signOpts, args)
+			}
+
+			if o.OutputDir.Dir != "" {
+				return sign.SignBlobsCmd(cmd.Context(), ro, ko, args, o.Base64Output, o.OutputDir.Dir, o.OutputDir.Name, o.OutputCertificate, o.TlogUpload)
 			}
 
 			for _, blob := range args {
@@ -93,7 +148,7 @@ func SignBlob() *cobra.Command {
 					o.OutputSignature = o.Output
 				}
 
-				if _, err := sign.SignBlobCmd(ro, ko, blob, o.Base64Output, o.OutputSignature, o.OutputCertificate, o.TlogUpload); err != nil {
+				if _, err := sign.SignBlobCmd(cmd.Context(), ro, ko, blob, o.Base64Output, o.OutputSignature, o.OutputCertificate, o.TlogUpload); err != nil {
 					return fmt.Errorf("signing %s: %w", blob, err)
 				}
 			}
@@ -0,0 +1,167 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/pkg/store"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+func Store() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "Provides utilities for the local store of the signatures and attestations cosign produces",
+	}
+
+	cmd.AddCommand(storeQuery())
+
+	return cmd
+}
+
+func storeQuery() *cobra.Command {
+	o := &options.StoreQueryOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "query [<DIGEST> | <IMAGE@DIGEST> | <FILE>]",
+		Short: "List the signatures and attestations archived in the local store for a subject",
+		Long: `List the signatures and attestations archived in the local store for the
+subject with a digest, an image by digest or a file, by its SHA-256 digest, or
+for every subject without one.
+
+With $COSIGN_STORE set, to a directory or to 1 for ~/.cosign/store, cosign sign,
+attest, sign-blob and attest-blob archive each signature and attestation they
+produce in the store, by the digest of their subject, before pushing it or
+uploading it to the transparency log, so that artifacts signed on laptops or
+ephemeral runners aren't lost if that fails. An entry is archived again once
+it has a transparency log bundle. The entries are JSON files at
+<algorithm>/<hex digest>/<id>.json in the store, and --output json prints
+them, e.g. to attach them again.`,
+		Example: `  # archive the signatures of a runner, then find the signature of an image
+  export COSIGN_STORE=1
+  cosign sign --key cosign.key <IMAGE>
+  cosign store query <IMAGE@DIGEST>
+
+  # list the attestations archived for a blob as JSON
+  cosign store query --kind attestation --output json artifact.tar.gz`,
+		Args:             cobra.MaximumNArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subject := ""
+			if len(args) == 1 {
+				subject = args[0]
+			}
+			return StoreQueryCmd(*o, subject, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// StoreQueryCmd writes the entries of the local store for subject, or all
+// of them if empty, to out.
+func StoreQueryCmd(o options.StoreQueryOptions, subject string, out io.Writer) error {
+	if o.Kind != "" && o.Kind != store.KindSignature && o.Kind != store.KindAttestation {
+		return fmt.Errorf("unsupported kind %q, expected signature or attestation", o.Kind)
+	}
+	if o.Output != "text" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, expected text or json", o.Output)
+	}
+	dir := o.Dir
+	var err error
+	if dir == "" {
+		dir, err = store.Dir()
+		if err == nil && dir == "" {
+			dir, err = store.DefaultDir()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	digest, err := subjectDigest(subject)
+	if err != nil {
+		return err
+	}
+	all, err := store.New(dir).Query(digest)
+	if err != nil {
+		return err
+	}
+	entries := make([]store.Entry, 0, len(all))
+	for _, e := range all {
+		if o.Kind == "" || e.Kind == o.Kind {
+			entries = append(entries, e)
+		}
+	}
+
+	if o.Output == "json" {
+		b, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+	for _, e := range entries {
+		bundled := "-"
+		if e.Bundle != nil {
+			bundled = fmt.Sprintf("tlog:%d", e.Bundle.Payload.LogIndex)
+		}
+		fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%s\n", e.CreatedAt.Format(time.RFC3339), e.Kind, e.Subject, e.Digest, bundled)
+	}
+	fmt.Fprintf(os.Stderr, "Found %d archived signature(s) and attestation(s) in %s\n", len(entries), dir)
+	return nil
+}
+
+// subjectDigest returns the digest of subject: a digest, an image by digest
+// or a file, whose SHA-256 digest is computed.
+func subjectDigest(subject string) (string, error) {
+	if subject == "" {
+		return "", nil
+	}
+	if alg, hexDigest, ok := strings.Cut(subject, ":"); ok && !strings.ContainsAny(alg, "/.@") && hexDigest != "" && !strings.ContainsAny(hexDigest, "/@") {
+		return subject, nil
+	}
+	if strings.Contains(subject, "@") {
+		ref, err := oci.ParseDigestReference(subject)
+		if err != nil {
+			return "", err
+		}
+		return ref.DigestStr(), nil
+	}
+	f, err := os.Open(filepath.Clean(subject))
+	if err != nil {
+		return "", fmt.Errorf("%s is neither a digest, an image by digest nor a file: %w", subject, err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
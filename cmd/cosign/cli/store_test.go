@@ -0,0 +1,127 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/internal/pkg/store"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestStoreQueryCmd(t *testing.T) {
+	ctx := context.Background()
+	// The registry refuses the signatures, as if the upload step failed.
+	reg := registry.New()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, ".sig") {
+			http.Error(w, "denied", http.StatusForbidden)
+			return
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	td := t.TempDir()
+	dir := filepath.Join(td, "store")
+	t.Setenv(env.VariablePassword.String(), "")
+	t.Setenv(env.VariableStore.String(), dir)
+
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv := filepath.Join(td, "cosign.key")
+	if err := os.WriteFile(priv, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	ko := options.KeyOpts{KeyRef: priv, PassFunc: func(bool) ([]byte, error) { return nil, nil }}
+	ro := &options.RootOptions{Timeout: options.DefaultTimeout}
+	if err := sign.SignCmd(ctx, ro, ko, options.SignOptions{Upload: true}, []string{ref.String()}); err == nil {
+		t.Fatal("SignCmd() to a registry refusing signatures: expected an error")
+	}
+
+	var out bytes.Buffer
+	if err := StoreQueryCmd(options.StoreQueryOptions{Output: "json"}, ref.String(), &out); err != nil {
+		t.Fatal(err)
+	}
+	var entries []store.Entry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Kind != store.KindSignature || entries[0].Subject != ref.String() || entries[0].Digest != h.String() || entries[0].Signature == "" {
+		t.Fatalf("archived entries of %s = %+v, want its signature", ref, entries)
+	}
+
+	out.Reset()
+	if err := StoreQueryCmd(options.StoreQueryOptions{Dir: dir, Output: "text"}, h.String(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := "\tsignature\t" + ref.String() + "\t" + h.String() + "\t-\n"; !strings.HasSuffix(out.String(), want) {
+		t.Errorf("text output = %q, want it to end with %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := StoreQueryCmd(options.StoreQueryOptions{Kind: store.KindAttestation, Output: "text"}, "", &out); err != nil || out.Len() != 0 {
+		t.Errorf("attestations = %q, %v, want none", out.String(), err)
+	}
+
+	// A file is queried by its SHA-256 digest.
+	blob := filepath.Join(td, "artifact.txt")
+	if err := os.WriteFile(blob, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := subjectDigest(blob); err != nil || d != "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("subjectDigest(%s) = %s, %v", blob, d, err)
+	}
+	for _, o := range []options.StoreQueryOptions{{Kind: "sbom", Output: "text"}, {Output: "yaml"}} {
+		if err := StoreQueryCmd(o, "", &out); err == nil {
+			t.Errorf("StoreQueryCmd(%+v): expected an error", o)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// outputFuncs are the functions of the --output-template templates.
+var outputFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": func(sep string, elems []interface{}) string {
+		s := make([]string, 0, len(elems))
+		for _, e := range elems {
+			s = append(s, fmt.Sprint(e))
+		}
+		return strings.Join(s, sep)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"base64decode": func(s string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(s)
+		return string(b), err
+	},
+}
+
+// ParseOutput parses the --output-template value, an inline template if it
+// contains "{{", or else the path to the template file. It returns nil if
+// value is empty.
+func ParseOutput(value string) (*template.Template, error) {
+	if value == "" {
+		return nil, nil
+	}
+	name, text := "output-template", value
+	if !strings.Contains(value, "{{") {
+		b, err := os.ReadFile(filepath.Clean(value))
+		if err != nil {
+			return nil, fmt.Errorf("reading output template: %w", err)
+		}
+		name, text = filepath.Base(value), string(b)
+	}
+	t, err := template.New(name).Funcs(outputFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing output template: %w", err)
+	}
+	return t, nil
+}
+
+// WriteOutput writes v, as it's marshaled to JSON, formatted with t to w, so
+// that the template refers to the fields by their JSON names and sees the
+// same values as the JSON output.
+func WriteOutput(w io.Writer, t *template.Template, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	// Log indexes and other integers are kept as they are written.
+	d.UseNumber()
+	var data interface{}
+	if err := d.Decode(&data); err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return fmt.Errorf("executing output template: %w", err)
+	}
+	_, err = w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,88 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseOutput(t *testing.T) {
+	if tmpl, err := ParseOutput(""); tmpl != nil || err != nil {
+		t.Errorf("ParseOutput(\"\") = %v, %v, want nil", tmpl, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(path, []byte("{{.name}}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"{{.name}}", path} {
+		tmpl, err := ParseOutput(value)
+		if err != nil {
+			t.Fatalf("ParseOutput(%q) = %v", value, err)
+		}
+		var out bytes.Buffer
+		if err := WriteOutput(&out, tmpl, map[string]string{"name": "app"}); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != "app" {
+			t.Errorf("template %q wrote %q, want app", value, out.String())
+		}
+	}
+
+	for value, want := range map[string]string{
+		filepath.Join(t.TempDir(), "missing.tmpl"): "reading output template",
+		"{{.name":              "parsing output template",
+		"{{nosuchfunc .name}}": "parsing output template",
+	} {
+		if _, err := ParseOutput(value); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseOutput(%q) = %v, want %q", value, err, want)
+		}
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	v := struct {
+		Name     string   `json:"name"`
+		LogIndex int64    `json:"logIndex"`
+		Tags     []string `json:"tags"`
+		Payload  string   `json:"payload"`
+	}{Name: "app", LogIndex: 123456789012, Tags: []string{"v1", "latest"}, Payload: "eyJhIjoxfQ=="}
+	tmpl, err := ParseOutput(`{{upper .name}} {{.logIndex}} {{join "," .tags}} {{json .tags}} {{base64decode .payload}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := WriteOutput(&out, tmpl, v); err != nil {
+		t.Fatal(err)
+	}
+	if want := `APP 123456789012 v1,latest ["v1","latest"] {"a":1}`; out.String() != want {
+		t.Errorf("WriteOutput() = %q, want %q", out.String(), want)
+	}
+
+	// Nothing is written if the template fails.
+	tmpl, err = ParseOutput(`{{.name}}{{base64decode .name}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := WriteOutput(&out, tmpl, v); err == nil || out.Len() != 0 {
+		t.Errorf("WriteOutput() = %v, wrote %q, want an error and nothing written", err, out.String())
+	}
+}
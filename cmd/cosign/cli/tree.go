@@ -17,29 +17,71 @@ package cli
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-
-	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/spf13/cobra"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/templates"
+	cosignterm "github.com/sigstore/cosign/v2/cmd/cosign/cli/templates/term"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
 )
 
 func Tree() *cobra.Command {
 	c := &options.TreeOptions{}
 
 	cmd := &cobra.Command{
-		Use:              "tree",
-		Short:            "Display supply chain security related artifacts for an image such as signatures, SBOMs and attestations",
-		Example:          "  cosign tree <IMAGE>",
+		Use:   "tree",
+		Short: "Display supply chain security related artifacts for an image such as signatures, SBOMs and attestations",
+		Long: `Display supply chain security related artifacts for an image such as signatures,
+SBOMs and attestations.
+
+With --output json, the artifacts are written as a JSON graph, with the digest,
+type and media type of each artifact, the predicate type of attestations, and
+the certificate identity and Rekor log index of signatures and attestations
+that have them. --output dot writes the graph in the Graphviz DOT language.
+--output-template formats the JSON graph with a Go template instead.
+
+With --verify, each signature and attestation is verified with --key or the
+certificate identity, or with the entry of --image-policy that matches the
+image, and annotated as verified or unverified, followed by a count of the
+verified artifacts. The SBOMs are verified by the signatures of their
+attachment. On a terminal, the statuses are in color, unless --color never or
+$NO_COLOR is set, and the lines fit its width. The JSON graph has the status of
+each artifact, and the error of the unverified ones.`,
+		Example: `  cosign tree <IMAGE>
+
+  # write the artifacts as JSON
+  cosign tree --output json <IMAGE>
+
+  # render the artifacts with Graphviz
+  cosign tree --output dot <IMAGE> | dot -Tsvg > tree.svg
+
+  # list the predicate types of the attestations
+  cosign tree --output-template '{{range .artifacts}}{{if .predicateType}}{{.predicateType}}{{"\n"}}{{end}}{{end}}' <IMAGE>
+
+  # show which artifacts are signed by the release workflow
+  cosign tree --verify --certificate-identity-regexp '^https://github.com/org/repo/' \
+    --certificate-oidc-issuer https://token.actions.githubusercontent.com <IMAGE>
+
+  # verify the artifacts with the key of their repository in an image policy
+  cosign tree --verify --image-policy policy.json <IMAGE>`,
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return TreeCmd(cmd.Context(), c.Registry, args[0])
+			return TreeCmd(cmd.Context(), *c, args[0], os.Stdout)
 		},
 	}
 
@@ -47,110 +89,350 @@ func Tree() *cobra.Command {
 	return cmd
 }
 
-func TreeCmd(ctx context.Context, regOpts options.RegistryOptions, imageRef string) error {
-	scsaMap := map[name.Tag][]v1.Layer{}
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+// Types of the artifacts of a TreeGraph.
+const (
+	TreeSignature   = "signature"
+	TreeAttestation = "attestation"
+	TreeSBOM        = "sbom"
+)
+
+// TreeGraph is the graph of the supply chain security related artifacts of an
+// image, written by cosign tree --output json.
+type TreeGraph struct {
+	Image     string         `json:"image"`
+	Digest    string         `json:"digest"`
+	Artifacts []TreeArtifact `json:"artifacts"`
+}
+
+// TreeArtifact is a signature, attestation or SBOM of the image of a
+// TreeGraph, stored as a layer of the image Tag.
+type TreeArtifact struct {
+	Type          string        `json:"type"`
+	Tag           string        `json:"tag"`
+	Digest        string        `json:"digest"`
+	MediaType     string        `json:"mediaType,omitempty"`
+	PredicateType string        `json:"predicateType,omitempty"`
+	Identity      *TreeIdentity `json:"identity,omitempty"`
+	LogIndex      *int64        `json:"logIndex,omitempty"`
+	// Verification is TreeVerified or TreeUnverified with --verify, and
+	// VerificationError why an unverified artifact failed, if known.
+	Verification      string `json:"verification,omitempty"`
+	VerificationError string `json:"verificationError,omitempty"`
+	// signature is the base64 signature of a signature or attestation, which
+	// tells apart those of the same payload.
+	signature string
+}
+
+// TreeIdentity is the certificate identity of a signature or attestation.
+type TreeIdentity struct {
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer,omitempty"`
+}
+
+func TreeCmd(ctx context.Context, o options.TreeOptions, imageRef string, w io.Writer) error {
+	switch o.Output {
+	case "text", "json", "dot":
+	default:
+		return fmt.Errorf("unsupported output format %q, expected text, json or dot", o.Output)
+	}
+	switch o.Color {
+	case "", "auto", "always", "never":
+	default:
+		return fmt.Errorf("unsupported --color %q, expected auto, always or never", o.Color)
+	}
+	tmpl, err := templates.ParseOutput(o.OutputTemplate.Template)
+	if err != nil {
+		return err
+	}
+	var policy *proxy.Policy
+	if o.Verify {
+		if policy, err = treePolicy(o); err != nil {
+			return err
+		}
+	}
+	ref, err := oci.ParseReference(imageRef, o.Registry.NameOptions()...)
 	if err != nil {
 		return err
 	}
 
-	remoteOpts, err := regOpts.ClientOpts(ctx)
+	remoteOpts, err := o.Registry.ClientOpts(ctx)
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(os.Stdout, "📦 Supply Chain Security Related artifacts for an image: %s\n", ref.String())
+	if o.Output == "text" && tmpl == nil {
+		fmt.Fprintf(w, "📦 Supply Chain Security Related artifacts for an image: %s\n", ref.String())
+	}
 
 	simg, err := ociremote.SignedEntity(ref, remoteOpts...)
 	if err != nil {
 		return err
 	}
+	digest, err := simg.Digest()
+	if err != nil {
+		return err
+	}
+	g := &TreeGraph{Image: ref.String(), Digest: digest.String(), Artifacts: []TreeArtifact{}}
 
 	attRef, err := ociremote.AttestationTag(ref, remoteOpts...)
 	if err != nil {
 		return err
 	}
-
 	atts, err := simg.Attestations()
 	if err == nil {
-		layers, err := atts.Layers()
-		if err != nil {
+		if err := g.addSignatures(TreeAttestation, attRef, atts); err != nil {
 			return err
 		}
-		if len(layers) > 0 {
-			scsaMap[attRef] = layers
-		}
 	}
 
 	sigRef, err := ociremote.SignatureTag(ref, remoteOpts...)
 	if err != nil {
 		return err
 	}
-
 	sigs, err := simg.Signatures()
 	if err == nil {
-		layers, err := sigs.Layers()
-		if err != nil {
+		if err := g.addSignatures(TreeSignature, sigRef, sigs); err != nil {
 			return err
 		}
-		if len(layers) > 0 {
-			scsaMap[sigRef] = layers
-		}
 	}
 
 	sbomRef, err := ociremote.SBOMTag(ref, remoteOpts...)
 	if err != nil {
 		return err
 	}
-
 	sbombs, err := simg.Attachment(ociremote.SBOMTagSuffix)
 	if err == nil {
 		layers, err := sbombs.Layers()
 		if err != nil {
 			return err
 		}
-		if len(layers) > 0 {
-			scsaMap[sbomRef] = layers
+		for _, l := range layers {
+			d, err := l.Digest()
+			if err != nil {
+				return err
+			}
+			mt, err := l.MediaType()
+			if err != nil {
+				return err
+			}
+			g.Artifacts = append(g.Artifacts, TreeArtifact{Type: TreeSBOM, Tag: sbomRef.String(), Digest: d.String(), MediaType: string(mt)})
 		}
 	}
 
-	if len(scsaMap) == 0 {
-		fmt.Fprintf(os.Stdout, "No Supply Chain Security Related Artifacts artifacts found for image %s\n, start creating one with simply running"+
-			"$ cosign sign <img>", ref.String())
-		return nil
+	if policy != nil {
+		if err := g.verify(ctx, o, policy, ref); err != nil {
+			return err
+		}
 	}
 
-	for t, k := range scsaMap {
-		switch t {
-		case sigRef:
-			fmt.Fprintf(os.Stdout, "└── 🔐 Signatures for an image tag: %s\n", t.String())
-		case sbomRef:
-			fmt.Fprintf(os.Stdout, "└── 📦 SBOMs for an image tag: %s\n", t.String())
-		case attRef:
-			fmt.Fprintf(os.Stdout, "└── 💾 Attestations for an image tag: %s\n", t.String())
+	if tmpl != nil {
+		return templates.WriteOutput(w, tmpl, g)
+	}
+	switch o.Output {
+	case "json":
+		b, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return err
 		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case "dot":
+		return g.writeDOT(w)
+	}
+	return g.writeText(w, newTreeStyle(w, o.Color))
+}
 
-		if err := printLayers(k); err != nil {
+// addSignatures adds the signatures or attestations of set, the image tag t,
+// to g.
+func (g *TreeGraph) addSignatures(typ string, t name.Tag, set oci.Signatures) error {
+	list, err := set.Get()
+	if err != nil {
+		return err
+	}
+	for _, sig := range list {
+		d, err := sig.Digest()
+		if err != nil {
 			return err
 		}
+		mt, err := sig.MediaType()
+		if err != nil {
+			return err
+		}
+		a := TreeArtifact{Type: typ, Tag: t.String(), Digest: d.String(), MediaType: string(mt)}
+		a.signature, _ = sig.Base64Signature()
+		if typ == TreeAttestation {
+			a.PredicateType = predicateType(sig)
+		}
+		if cert, err := sig.Cert(); err == nil && cert != nil {
+			ce := cosign.CertExtensions{Cert: cert}
+			a.Identity = &TreeIdentity{Subject: sigs.CertSubject(cert), Issuer: ce.GetIssuer()}
+		}
+		if b, err := sig.Bundle(); err == nil && b != nil {
+			logIndex := b.Payload.LogIndex
+			a.LogIndex = &logIndex
+		}
+		g.Artifacts = append(g.Artifacts, a)
 	}
-
 	return nil
 }
 
-func printLayers(layers []v1.Layer) error {
-	for i, l := range layers {
-		last := i == len(layers)-1
-		var sym string
-		if last {
+// predicateType returns the predicate type of the in-toto statement of the
+// attestation att, or "" if it has none.
+func predicateType(att oci.Signature) string {
+	p, err := att.Payload()
+	if err != nil {
+		return ""
+	}
+	var envelope struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(p, &envelope); err != nil {
+		return ""
+	}
+	raw, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return ""
+	}
+	var st struct {
+		PredicateType string `json:"predicateType"`
+	}
+	if err := json.Unmarshal(raw, &st); err != nil {
+		return ""
+	}
+	return st.PredicateType
+}
+
+func (g *TreeGraph) writeText(w io.Writer, style treeStyle) error {
+	if len(g.Artifacts) == 0 {
+		fmt.Fprintf(w, "No Supply Chain Security Related Artifacts artifacts found for image %s\n, start creating one with simply running"+
+			"$ cosign sign <img>", g.Image)
+		return nil
+	}
+
+	for i, a := range g.Artifacts {
+		if i == 0 || g.Artifacts[i-1].Tag != a.Tag {
+			switch a.Type {
+			case TreeSignature:
+				style.line(w, "└── 🔐 Signatures for an image tag: "+a.Tag, "")
+			case TreeSBOM:
+				style.line(w, "└── 📦 SBOMs for an image tag: "+a.Tag, "")
+			case TreeAttestation:
+				style.line(w, "└── 💾 Attestations for an image tag: "+a.Tag, "")
+			}
+		}
+		sym := "   ├──"
+		if i == len(g.Artifacts)-1 || g.Artifacts[i+1].Tag != a.Tag {
 			sym = "   └──"
-		} else {
-			sym = "   ├──"
 		}
-		digest, err := l.Digest()
-		if err != nil {
-			return err
+		style.line(w, sym+" 🍒 "+a.Digest, a.Verification)
+	}
+	if g.Artifacts[0].Verification != "" {
+		n := g.verifiedCount()
+		status := TreeVerified
+		if n < len(g.Artifacts) {
+			status = TreeUnverified
 		}
-		fmt.Printf("%s 🍒 %s\n", sym, digest)
+		summary := fmt.Sprintf("🛡️  %d of %d artifacts verified", n, len(g.Artifacts))
+		fmt.Fprintln(w, style.paint(status, style.truncate(summary, 0)))
 	}
 	return nil
 }
+
+// treeStyle is how the text output of cosign tree is written: with the
+// verification statuses in color, and lines truncated to the width of the
+// terminal, if any.
+type treeStyle struct {
+	color bool
+	// width is that of the terminal, or 0 if w isn't one.
+	width int
+}
+
+// newTreeStyle returns the style of the text output to w. With color auto,
+// the output is colored on a terminal unless $NO_COLOR is set.
+func newTreeStyle(w io.Writer, color string) treeStyle {
+	s := treeStyle{}
+	if f, ok := w.(*os.File); ok {
+		if size := cosignterm.GetSize(f.Fd()); size != nil {
+			s.width = int(size.Width)
+		}
+	}
+	switch color {
+	case "always":
+		s.color = true
+	case "", "auto":
+		s.color = s.width > 0 && os.Getenv("NO_COLOR") == ""
+	}
+	return s
+}
+
+// line writes text, truncated to leave room for the verification status
+// that follows it, if any.
+func (s treeStyle) line(w io.Writer, text, status string) {
+	label := ""
+	switch status {
+	case TreeVerified:
+		label = " ✔ verified"
+	case TreeUnverified:
+		label = " ✘ unverified"
+	}
+	fmt.Fprintln(w, s.truncate(text, utf8.RuneCountInString(label))+s.paint(status, label))
+}
+
+// paint returns text in the color of the verification status.
+func (s treeStyle) paint(status, text string) string {
+	if !s.color || text == "" {
+		return text
+	}
+	switch status {
+	case TreeVerified:
+		return "\x1b[32m" + text + "\x1b[0m"
+	case TreeUnverified:
+		return "\x1b[31m" + text + "\x1b[0m"
+	}
+	return text
+}
+
+// truncate shortens text to the width of the terminal, less reserved
+// columns, ending it with an ellipsis.
+func (s treeStyle) truncate(text string, reserved int) string {
+	limit := s.width - reserved
+	r := []rune(text)
+	if s.width <= 0 || limit < 1 || len(r) <= limit {
+		return text
+	}
+	return string(r[:limit-1]) + "…"
+}
+
+// writeDOT writes g as a Graphviz digraph, with an edge from each artifact to
+// the image. Verified artifacts are green, and unverified ones red.
+func (g *TreeGraph) writeDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph cosign_tree {\n")
+	fmt.Fprintf(&b, "  %s [shape=box, label=%s];\n", strconv.Quote(g.Digest), strconv.Quote(g.Image))
+	for _, a := range g.Artifacts {
+		label := []string{a.Type, a.Digest}
+		if a.PredicateType != "" {
+			label = append(label, a.PredicateType)
+		}
+		if a.Identity != nil {
+			label = append(label, a.Identity.Subject)
+		}
+		if a.LogIndex != nil {
+			label = append(label, fmt.Sprintf("log index %d", *a.LogIndex))
+		}
+		attrs := ""
+		switch a.Verification {
+		case TreeVerified:
+			label = append(label, TreeVerified)
+			attrs = ", color=green"
+		case TreeUnverified:
+			label = append(label, TreeUnverified)
+			attrs = ", color=red"
+		}
+		id := strconv.Quote(a.Type + " " + a.Digest)
+		fmt.Fprintf(&b, "  %s [label=%s%s];\n", id, strconv.Quote(strings.Join(label, "\n")), attrs)
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", id, strconv.Quote(g.Digest), strconv.Quote(a.Type))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
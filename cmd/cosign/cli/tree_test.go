@@ -0,0 +1,295 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	slsa "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+func TestTreeCmd(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The image has no tag, as in tag-less promotion pipelines.
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	leafCert, _, _ := test.GenerateLeafCert("signer@example.com", "https://issuer.example.com", rootCert, rootKey)
+	leafPEM, err := cryptoutils.MarshalCertificateToPEM(leafCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature([]byte("payload"), "c2lnbmF0dXJl", static.WithCertChain(leafPEM, nil),
+		static.WithBundle(&bundle.RekorBundle{Payload: bundle.RekorPayload{LogIndex: 42}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := base64.StdEncoding.EncodeToString([]byte(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2","subject":[]}`))
+	att, err := static.NewAttestation([]byte(`{"payloadType":"application/vnd.in-toto+json","payload":"` + statement + `","signatures":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(tag name.Tag, sigs ...oci.Signature) {
+		s, err := mutate.AppendSignatures(empty.Signatures(), sigs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(tag, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sigTag, _ := ociremote.SignatureTag(ref)
+	attTag, _ := ociremote.AttestationTag(ref)
+	write(sigTag, sig)
+	write(attTag, att)
+
+	var out bytes.Buffer
+	if err := TreeCmd(ctx, options.TreeOptions{Output: "json"}, ref.String(), &out); err != nil {
+		t.Fatalf("TreeCmd() = %v", err)
+	}
+	g := TreeGraph{}
+	if err := json.Unmarshal(out.Bytes(), &g); err != nil {
+		t.Fatalf("TreeCmd() wrote %q: %v", out.String(), err)
+	}
+	if g.Digest != h.String() || len(g.Artifacts) != 2 {
+		t.Fatalf("TreeCmd() = %+v, want the 2 artifacts of %s", g, h)
+	}
+	gotAtt, gotSig := g.Artifacts[0], g.Artifacts[1]
+	if gotAtt.Type != TreeAttestation || gotAtt.Tag != attTag.String() || gotAtt.PredicateType != "https://slsa.dev/provenance/v0.2" || gotAtt.Identity != nil {
+		t.Errorf("attestation = %+v", gotAtt)
+	}
+	if gotSig.Type != TreeSignature || gotSig.Identity == nil || gotSig.Identity.Subject != "signer@example.com" ||
+		gotSig.Identity.Issuer != "https://issuer.example.com" || gotSig.LogIndex == nil || *gotSig.LogIndex != 42 {
+		t.Errorf("signature = %+v", gotSig)
+	}
+
+	out.Reset()
+	if err := TreeCmd(ctx, options.TreeOptions{Output: "dot"}, ref.String(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if dot := out.String(); !strings.HasPrefix(dot, "digraph") || !strings.Contains(dot, `-> "`+h.String()+`" [label="signature"]`) || !strings.Contains(dot, "log index 42") {
+		t.Errorf("TreeCmd() DOT = %q", dot)
+	}
+
+	out.Reset()
+	if err := TreeCmd(ctx, options.TreeOptions{Output: "text"}, ref.String(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if text := out.String(); !strings.Contains(text, "Signatures for an image tag: "+sigTag.String()) || strings.Count(text, "└── 🍒") != 2 {
+		t.Errorf("TreeCmd() text = %q", text)
+	}
+
+	// The template sees the JSON graph, and replaces the text output.
+	out.Reset()
+	tmpl := options.OutputTemplateOptions{Template: `{{range .artifacts}}{{.type}} {{.logIndex}}{{with .identity}} {{.subject}}{{end}}{{"\n"}}{{end}}`}
+	if err := TreeCmd(ctx, options.TreeOptions{Output: "text", OutputTemplate: tmpl}, ref.String(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "attestation <no value>\nsignature 42 signer@example.com\n"; got != want {
+		t.Errorf("TreeCmd() with a template = %q, want %q", got, want)
+	}
+
+	if err := TreeCmd(ctx, options.TreeOptions{Output: "yaml"}, ref.String(), &out); err == nil {
+		t.Error("TreeCmd() with --output yaml: expected an error")
+	}
+	if err := TreeCmd(ctx, options.TreeOptions{Output: "text", OutputTemplate: options.OutputTemplateOptions{Template: "{{.image"}}, ref.String(), &out); err == nil {
+		t.Error("TreeCmd() with an invalid template: expected an error")
+	}
+}
+
+func TestTreeCmdVerify(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	write := func(tm func(name.Reference, ...ociremote.Option) (name.Tag, error), ref name.Reference, sigs ...oci.Signature) {
+		t.Helper()
+		tag, err := tm(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := mutate.AppendSignatures(empty.Signatures(), sigs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(tag, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	imagePayload := func(d name.Digest) []byte {
+		p, err := (&payload.Cosign{Image: d}).MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// A signature and an attestation of the release key, a signature of the
+	// same payload, so with the same digest, by another key, and an SBOM
+	// signed by the release key.
+	releaseSigner, releaseKey := newTestKey(t)
+	otherSigner, otherKey := newTestKey(t)
+	write(ociremote.SignatureTag, ref, signPayload(t, releaseSigner, imagePayload(ref)), signPayload(t, otherSigner, imagePayload(ref)))
+	write(ociremote.AttestationTag, ref, attestPayload(t, releaseSigner, ref, slsa.PredicateSLSAProvenance))
+	sbomTag, err := ociremote.SBOMTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sbom, err := static.NewFile([]byte(`{}`), static.WithLayerMediaType(types.SPDXJSONMediaType))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sbomTag, sbom); err != nil {
+		t.Fatal(err)
+	}
+	sbomDigest, err := sbom.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sbomRef := ref.Context().Digest(sbomDigest.String())
+	write(ociremote.SignatureTag, sbomRef, signPayload(t, releaseSigner, imagePayload(sbomRef)))
+
+	tree := func(t *testing.T, o options.TreeOptions) TreeGraph {
+		t.Helper()
+		o.Verify, o.IgnoreTlog, o.IgnoreSCT, o.Output = true, true, true, "json"
+		var out bytes.Buffer
+		if err := TreeCmd(ctx, o, ref.String(), &out); err != nil {
+			t.Fatalf("TreeCmd() = %v", err)
+		}
+		g := TreeGraph{}
+		if err := json.Unmarshal(out.Bytes(), &g); err != nil {
+			t.Fatalf("TreeCmd() wrote %q: %v", out.String(), err)
+		}
+		return g
+	}
+	statuses := func(g TreeGraph) string {
+		var s []string
+		for _, a := range g.Artifacts {
+			status := a.Verification
+			s = append(s, a.Type+" "+status)
+		}
+		return strings.Join(s, ", ")
+	}
+
+	g := tree(t, options.TreeOptions{Key: releaseKey})
+	if got, want := statuses(g), "attestation verified, signature verified, signature unverified, sbom verified"; got != want {
+		t.Errorf("TreeCmd() with the release key = %s, want %s", got, want)
+	}
+	g = tree(t, options.TreeOptions{Key: otherKey})
+	if got, want := statuses(g), "attestation unverified, signature unverified, signature verified, sbom unverified"; got != want {
+		t.Errorf("TreeCmd() with another key = %s, want %s", got, want)
+	}
+	if a := g.Artifacts[0]; a.VerificationError == "" {
+		t.Errorf("unverified attestation = %+v, want its error", a)
+	}
+
+	policy := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(policy, []byte(`{"images": [{"glob": "`+host+`/other", "key": "`+otherKey+`"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	g = tree(t, options.TreeOptions{ImagePolicy: policy})
+	if g.verifiedCount() != 0 || !strings.Contains(g.Artifacts[1].VerificationError, "no entry of the image policy") {
+		t.Errorf("TreeCmd() without a matching policy entry = %+v", g)
+	}
+
+	var out bytes.Buffer
+	o := options.TreeOptions{Verify: true, Key: releaseKey, IgnoreTlog: true, IgnoreSCT: true, Output: "text", Color: "always"}
+	if err := TreeCmd(ctx, o, ref.String(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if text := out.String(); strings.Count(text, "\x1b[32m ✔ verified\x1b[0m") != 3 || strings.Count(text, "\x1b[31m ✘ unverified\x1b[0m") != 1 ||
+		!strings.Contains(text, "3 of 4 artifacts verified") {
+		t.Errorf("TreeCmd() text = %q", text)
+	}
+
+	if err := TreeCmd(ctx, options.TreeOptions{Verify: true, Output: "text"}, ref.String(), &out); err == nil {
+		t.Error("TreeCmd() --verify without a key or identity: expected an error")
+	}
+}
+
+func TestTreeStyleTruncate(t *testing.T) {
+	s := treeStyle{width: 20}
+	if got := s.truncate("   └── 🍒 sha256:0123456789abcdef", utf8.RuneCountInString(" ✔ verified")); got != "   └── 🍒…" {
+		t.Errorf("truncate() = %q", got)
+	}
+	if got := s.truncate("short", 0); got != "short" {
+		t.Errorf("truncate() = %q, want the text", got)
+	}
+	if got := (treeStyle{}).truncate("   └── 🍒 sha256:0123456789abcdef", 0); got != "   └── 🍒 sha256:0123456789abcdef" {
+		t.Errorf("truncate() without a terminal = %q, want the text", got)
+	}
+}
@@ -0,0 +1,179 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// Verification statuses of the artifacts of a TreeGraph, with --verify.
+const (
+	TreeVerified   = "verified"
+	TreeUnverified = "unverified"
+)
+
+// treePolicy returns the policy that cosign tree --verify verifies the
+// artifacts of an image with: the --image-policy of o, or else one entry with
+// its key or certificate identity.
+func treePolicy(o options.TreeOptions) (*proxy.Policy, error) {
+	if o.ImagePolicy != "" {
+		if o.Key != "" || o.CertIdentity != "" || o.CertIdentityRegexp != "" {
+			return nil, errors.New("--image-policy can't be used with --key or --certificate-identity")
+		}
+		return proxy.ReadPolicy(o.ImagePolicy)
+	}
+	if o.Key == "" && o.CertIdentity == "" && o.CertIdentityRegexp == "" {
+		return nil, errors.New("--verify requires --key, --certificate-identity, --certificate-identity-regexp or --image-policy")
+	}
+	return &proxy.Policy{Images: []proxy.PolicyEntry{{
+		Key:                         o.Key,
+		CertificateIdentity:         o.CertIdentity,
+		CertificateIdentityRegexp:   o.CertIdentityRegexp,
+		CertificateOIDCIssuer:       o.CertOidcIssuer,
+		CertificateOIDCIssuerRegexp: o.CertOidcIssuerRegexp,
+	}}}, nil
+}
+
+// verify annotates the artifacts of g, the graph of the image ref, as
+// verified or unverified with the entry of p that matches ref. Signatures and
+// attestations are verified one by one, and the SBOMs are verified if the
+// signatures of their attachment are. The artifacts that fail have the error
+// of their type.
+func (g *TreeGraph) verify(ctx context.Context, o options.TreeOptions, p *proxy.Policy, ref name.Reference) error {
+	e, err := p.Match(ctx, ref, o.Registry.GetRegistryClientOpts(ctx)...)
+	if err != nil {
+		return err
+	}
+	if e == nil {
+		g.annotate(nil, map[string]error{"": fmt.Errorf("no entry of the image policy matches %s", ref)})
+		return nil
+	}
+
+	v := verify.VerifyCommand{
+		RegistryOptions: o.Registry,
+		CheckClaims:     true,
+		RekorURL:        o.Rekor.URL,
+		IgnoreTlog:      o.IgnoreTlog,
+		IgnoreSCT:       o.IgnoreSCT,
+		NameOptions:     o.Registry.NameOptions(),
+	}
+	e.Apply(&v)
+	verified := map[string]bool{}
+	collect := func(typ string) func(context.Context, name.Reference, []oci.Signature) error {
+		return func(_ context.Context, _ name.Reference, sigs []oci.Signature) error {
+			for _, sig := range sigs {
+				d, err := sig.Digest()
+				if err != nil {
+					return err
+				}
+				b64sig, err := sig.Base64Signature()
+				if err != nil {
+					return err
+				}
+				verified[typ+" "+d.String()+" "+b64sig] = true
+			}
+			return nil
+		}
+	}
+	// The artifacts are annotated rather than printed, so the verification
+	// messages are dropped.
+	ctx = ui.WithEnv(ctx, &ui.Env{Stderr: io.Discard, Stdin: os.Stdin})
+	img := ref.Context().Digest(g.Digest).String()
+	errs := map[string]error{}
+	ofType := map[string]bool{}
+	for _, a := range g.Artifacts {
+		ofType[a.Type] = true
+	}
+
+	if ofType[TreeSignature] {
+		sv := v
+		sv.OnVerified = collect(TreeSignature)
+		errs[TreeSignature] = sv.Exec(ctx, []string{img})
+	}
+	if ofType[TreeAttestation] {
+		av := &verify.VerifyAttestationCommand{
+			RegistryOptions:   v.RegistryOptions,
+			CertVerifyOptions: v.CertVerifyOptions,
+			CheckClaims:       true,
+			KeyRef:            v.KeyRef,
+			RekorURL:          v.RekorURL,
+			IgnoreTlog:        v.IgnoreTlog,
+			IgnoreSCT:         v.IgnoreSCT,
+			NameOptions:       v.NameOptions,
+			OnVerified:        collect(TreeAttestation),
+		}
+		errs[TreeAttestation] = av.Exec(ctx, []string{img})
+	}
+	if ofType[TreeSBOM] {
+		sv := v
+		sv.Attachment = TreeSBOM
+		sv.OnVerified = func(_ context.Context, _ name.Reference, sigs []oci.Signature) error {
+			verified[TreeSBOM] = len(sigs) > 0
+			return nil
+		}
+		errs[TreeSBOM] = sv.Exec(ctx, []string{img})
+	}
+	g.annotate(verified, errs)
+	return nil
+}
+
+// annotate sets the verification status of the artifacts of g: verified if
+// their type, digest and signature, or the type of SBOMs, is in verified. Unverified
+// artifacts get the error of their type, or that of "" for every type.
+func (g *TreeGraph) annotate(verified map[string]bool, errs map[string]error) {
+	for i := range g.Artifacts {
+		a := &g.Artifacts[i]
+		key := a.Type + " " + a.Digest + " " + a.signature
+		if a.Type == TreeSBOM {
+			key = TreeSBOM
+		}
+		if verified[key] {
+			a.Verification = TreeVerified
+			continue
+		}
+		a.Verification = TreeUnverified
+		err := errs[a.Type]
+		if err == nil {
+			err = errs[""]
+		}
+		if err != nil {
+			a.VerificationError = err.Error()
+		}
+	}
+}
+
+// verifiedCount returns the number of verified artifacts of g.
+func (g *TreeGraph) verifiedCount() int {
+	n := 0
+	for _, a := range g.Artifacts {
+		if a.Verification == TreeVerified {
+			n++
+		}
+	}
+	return n
+}
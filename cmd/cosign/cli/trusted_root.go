@@ -0,0 +1,71 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/trustedroot"
+)
+
+func TrustedRoot() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trusted-root",
+		Short: "Provides utilities for Sigstore trusted roots",
+	}
+
+	cmd.AddCommand(
+		trustedRootSync(),
+	)
+
+	return cmd
+}
+
+func trustedRootSync() *cobra.Command {
+	o := &options.TrustedRootSyncOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Refresh the TUF repository and write its trusted root",
+		Long: `Refresh the Sigstore TUF repository, verifying the signed metadata, and write
+the Fulcio, Rekor, CT log and timestamp authority material it distributes to a
+single trusted root, for verifying with --trusted-root.
+
+The trusted_root.json target of the repository is written as it is. For
+repositories without one, the trusted root is assembled from the targets of
+each usage. The changes to the previous trusted root are listed, so that they
+can be reviewed before the trusted root is distributed.
+
+With --snapshot-version, the command fails unless the repository is at the
+given snapshot version, so that the written trusted root is the reviewed one.`,
+		Example: `  cosign trusted-root sync --output trusted_root.json
+
+  # list the changes of the latest metadata without writing the trusted root
+  cosign trusted-root sync --dry-run
+
+  # write the trusted root of a private deployment, pinned to a reviewed snapshot
+  cosign trusted-root sync --mirror https://tuf.example.com --root root.json --snapshot-version 42`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return trustedroot.SyncCmd(cmd.Context(), *o, cmd.OutOrStdout())
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,103 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustedroot
+
+import (
+	"fmt"
+	"sort"
+)
+
+// diff returns a line for each log and authority that next adds to,
+// removes from or changes in previous, which is nil if there was none.
+func diff(previous, next *trustedRoot) []string {
+	if previous == nil {
+		previous = &trustedRoot{}
+	}
+	var lines []string
+	lines = append(lines, diffLogs("Rekor log", previous.Tlogs, next.Tlogs)...)
+	lines = append(lines, diffLogs("CT log", previous.Ctlogs, next.Ctlogs)...)
+	lines = append(lines, diffAuthorities("certificate authority", previous.CertificateAuthorities, next.CertificateAuthorities)...)
+	lines = append(lines, diffAuthorities("timestamp authority", previous.TimestampAuthorities, next.TimestampAuthorities)...)
+	return lines
+}
+
+func diffLogs(kind string, previous, next []transparencyLog) []string {
+	old := map[string]transparencyLog{}
+	for _, l := range previous {
+		old[l.id()] = l
+	}
+	describe := func(l transparencyLog) string {
+		if l.BaseURL == "" {
+			return fmt.Sprintf("%s with key %s", kind, l.id())
+		}
+		return fmt.Sprintf("%s %s with key %s", kind, l.BaseURL, l.id())
+	}
+	var lines []string
+	for _, l := range next {
+		o, ok := old[l.id()]
+		delete(old, l.id())
+		switch {
+		case !ok:
+			lines = append(lines, fmt.Sprintf("+ %s, %s", describe(l), l.PublicKey.ValidFor))
+		case o.BaseURL != l.BaseURL:
+			lines = append(lines, fmt.Sprintf("~ %s, moved from %s", describe(l), o.BaseURL))
+		}
+		if ok && o.PublicKey.ValidFor.String() != l.PublicKey.ValidFor.String() {
+			lines = append(lines, fmt.Sprintf("~ %s, %s, was %s", describe(l), l.PublicKey.ValidFor, o.PublicKey.ValidFor))
+		}
+	}
+	gone := make([]string, 0, len(old))
+	for _, l := range old {
+		gone = append(gone, describe(l))
+	}
+	return append(lines, removed(gone)...)
+}
+
+func diffAuthorities(kind string, previous, next []authority) []string {
+	old := map[string]authority{}
+	for _, a := range previous {
+		old[a.id()] = a
+	}
+	describe := func(a authority) string {
+		return fmt.Sprintf("%s %s (%s)", kind, a.name(), a.id())
+	}
+	var lines []string
+	for _, a := range next {
+		o, ok := old[a.id()]
+		delete(old, a.id())
+		switch {
+		case !ok:
+			lines = append(lines, fmt.Sprintf("+ %s, %s", describe(a), a.ValidFor))
+		case o.ValidFor.String() != a.ValidFor.String():
+			lines = append(lines, fmt.Sprintf("~ %s, %s, was %s", describe(a), a.ValidFor, o.ValidFor))
+		}
+	}
+	gone := make([]string, 0, len(old))
+	for _, a := range old {
+		gone = append(gone, describe(a))
+	}
+	return append(lines, removed(gone)...)
+}
+
+// removed returns the lines of the removed entries, sorted.
+func removed(entries []string) []string {
+	sort.Strings(entries)
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, "- "+e)
+	}
+	return lines
+}
@@ -0,0 +1,331 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustedroot
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+// mediaType is the media type of the trusted roots assembled from the
+// targets of TUF repositories without a trusted_root.json target.
+const mediaType = "application/vnd.dev.sigstore.trustedroot+json;version=0.1"
+
+// trustedRoot is a Sigstore trusted root, in the JSON encoding of the
+// TrustedRoot message of https://github.com/sigstore/protobuf-specs.
+type trustedRoot struct {
+	MediaType              string            `json:"mediaType"`
+	Tlogs                  []transparencyLog `json:"tlogs"`
+	CertificateAuthorities []authority       `json:"certificateAuthorities"`
+	Ctlogs                 []transparencyLog `json:"ctlogs"`
+	TimestampAuthorities   []authority       `json:"timestampAuthorities,omitempty"`
+}
+
+type transparencyLog struct {
+	BaseURL       string    `json:"baseUrl"`
+	HashAlgorithm string    `json:"hashAlgorithm"`
+	PublicKey     publicKey `json:"publicKey"`
+	LogID         logID     `json:"logId"`
+}
+
+type publicKey struct {
+	RawBytes   []byte   `json:"rawBytes"`
+	KeyDetails string   `json:"keyDetails"`
+	ValidFor   validFor `json:"validFor"`
+}
+
+type logID struct {
+	KeyID []byte `json:"keyId"`
+}
+
+// id returns the hex encoded SHA-256 digest of the DER encoded key of the
+// log, which identifies it whether or not the log ID is set.
+func (l transparencyLog) id() string {
+	digest := sha256.Sum256(l.PublicKey.RawBytes)
+	return hex.EncodeToString(digest[:])
+}
+
+type authority struct {
+	Subject   subject   `json:"subject"`
+	URI       string    `json:"uri"`
+	CertChain certChain `json:"certChain"`
+	ValidFor  validFor  `json:"validFor"`
+}
+
+type subject struct {
+	Organization string `json:"organization,omitempty"`
+	CommonName   string `json:"commonName,omitempty"`
+}
+
+type certChain struct {
+	Certificates []rawBytes `json:"certificates"`
+}
+
+type rawBytes struct {
+	RawBytes []byte `json:"rawBytes"`
+}
+
+// id returns the SHA-256 fingerprint of the first certificate of the chain
+// of the authority.
+func (a authority) id() string {
+	if len(a.CertChain.Certificates) == 0 {
+		return ""
+	}
+	digest := sha256.Sum256(a.CertChain.Certificates[0].RawBytes)
+	return "sha256:" + hex.EncodeToString(digest[:])
+}
+
+// name returns the subject of the first certificate of the chain of the
+// authority, or its fingerprint if it can't be parsed.
+func (a authority) name() string {
+	if len(a.CertChain.Certificates) > 0 {
+		if cert, err := x509.ParseCertificate(a.CertChain.Certificates[0].RawBytes); err == nil {
+			return cert.Subject.String()
+		}
+	}
+	return a.id()
+}
+
+type validFor struct {
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+func (v validFor) String() string {
+	switch {
+	case v.Start != nil && v.End != nil:
+		return fmt.Sprintf("valid from %s until %s", v.Start.UTC().Format(time.RFC3339), v.End.UTC().Format(time.RFC3339))
+	case v.Start != nil:
+		return fmt.Sprintf("valid from %s", v.Start.UTC().Format(time.RFC3339))
+	case v.End != nil:
+		return fmt.Sprintf("valid until %s", v.End.UTC().Format(time.RFC3339))
+	}
+	return "valid indefinitely"
+}
+
+// source is the TUF repository that trust material is read from.
+type source interface {
+	GetTarget(name string) ([]byte, error)
+	GetTargetsByMeta(usage tuf.UsageKind, fallbacks []string) ([]tuf.TargetFile, error)
+}
+
+// The targets of the trust material of repositories without a
+// trusted_root.json target.
+var (
+	fulcioTargets = []string{"fulcio.crt.pem", "fulcio_v1.crt.pem", "fulcio_intermediate_v1.crt.pem"}
+	rekorTargets  = []string{"rekor.pub"}
+	ctlogTargets  = []string{"ctfe.pub"}
+)
+
+// assemble returns the trusted root of the Fulcio, Rekor and CT log targets
+// of src, for repositories without a trusted_root.json target. These targets
+// don't record when keys and certificates were rotated out, so expired ones
+// are valid until the end recorded in previous, if any, or else until now.
+func assemble(src source, previous *trustedRoot, now time.Time) (*trustedRoot, error) {
+	ends := map[string]*time.Time{}
+	if previous != nil {
+		for _, l := range append(previous.Tlogs[:len(previous.Tlogs):len(previous.Tlogs)], previous.Ctlogs...) {
+			ends[l.id()] = l.PublicKey.ValidFor.End
+		}
+		for _, a := range previous.CertificateAuthorities {
+			ends[a.id()] = a.ValidFor.End
+		}
+	}
+	end := func(id string, status tuf.StatusKind) *time.Time {
+		if status == tuf.Active {
+			return nil
+		}
+		if e := ends[id]; e != nil {
+			return e
+		}
+		return &now
+	}
+
+	root := &trustedRoot{MediaType: mediaType}
+	for _, t := range []struct {
+		usage     tuf.UsageKind
+		fallbacks []string
+		logs      *[]transparencyLog
+	}{
+		{tuf.Rekor, rekorTargets, &root.Tlogs},
+		{tuf.CTFE, ctlogTargets, &root.Ctlogs},
+	} {
+		targets, err := src.GetTargetsByMeta(t.usage, t.fallbacks)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s targets: %w", t.usage, err)
+		}
+		for _, target := range targets {
+			l, err := logFromPEM(target.Target)
+			if err != nil {
+				return nil, fmt.Errorf("%s target: %w", t.usage, err)
+			}
+			l.PublicKey.ValidFor.End = end(l.id(), target.Status)
+			*t.logs = append(*t.logs, l)
+		}
+	}
+
+	targets, err := src.GetTargetsByMeta(tuf.Fulcio, fulcioTargets)
+	if err != nil {
+		return nil, fmt.Errorf("reading Fulcio targets: %w", err)
+	}
+	cas, err := authorities(targets)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cas {
+		cas[i].ValidFor.End = end(cas[i].id(), targets[i].Status)
+	}
+	root.CertificateAuthorities = cas
+	return root, nil
+}
+
+// logFromPEM returns the transparency log of the PEM encoded public key.
+func logFromPEM(pemBytes []byte) (transparencyLog, error) {
+	pub, err := cryptoutils.UnmarshalPEMToPublicKey(pemBytes)
+	if err != nil {
+		return transparencyLog{}, err
+	}
+	der, err := cryptoutils.MarshalPublicKeyToDER(pub)
+	if err != nil {
+		return transparencyLog{}, err
+	}
+	details, err := keyDetails(pub)
+	if err != nil {
+		return transparencyLog{}, err
+	}
+	digest := sha256.Sum256(der)
+	return transparencyLog{
+		HashAlgorithm: "SHA2_256",
+		PublicKey:     publicKey{RawBytes: der, KeyDetails: details},
+		LogID:         logID{KeyID: digest[:]},
+	}, nil
+}
+
+// keyDetails returns the PublicKeyDetails name of the algorithm of a log key.
+func keyDetails(pub crypto.PublicKey) (string, error) {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return "PKIX_ECDSA_P256_SHA_256", nil
+		case elliptic.P384():
+			return "PKIX_ECDSA_P384_SHA_384", nil
+		case elliptic.P521():
+			return "PKIX_ECDSA_P521_SHA_512", nil
+		}
+	case *rsa.PublicKey:
+		return fmt.Sprintf("PKIX_RSA_PKCS1V15_%d_SHA256", k.Size()*8), nil
+	case ed25519.PublicKey:
+		return "PKIX_ED25519", nil
+	}
+	return "", fmt.Errorf("unsupported log key type %T", pub)
+}
+
+// authorities returns a certificate authority for each of the Fulcio
+// targets. A target of intermediates is completed with the root that issued
+// them, from another target, so that each chain ends with its root.
+func authorities(targets []tuf.TargetFile) ([]authority, error) {
+	chains := make([][]*x509.Certificate, 0, len(targets))
+	var roots []*x509.Certificate
+	for _, t := range targets {
+		certs, err := cryptoutils.UnmarshalCertificatesFromPEM(t.Target)
+		if err != nil {
+			return nil, fmt.Errorf("parsing Fulcio target: %w", err)
+		}
+		if len(certs) == 0 {
+			return nil, errors.New("a Fulcio target has no certificates")
+		}
+		chains = append(chains, certs)
+		if last := certs[len(certs)-1]; bytes.Equal(last.RawSubject, last.RawIssuer) {
+			roots = append(roots, last)
+		}
+	}
+
+	cas := make([]authority, 0, len(chains))
+	for _, certs := range chains {
+		if last := certs[len(certs)-1]; !bytes.Equal(last.RawSubject, last.RawIssuer) {
+			for _, root := range roots {
+				if last.CheckSignatureFrom(root) == nil {
+					certs = append(certs, root)
+					break
+				}
+			}
+		}
+		cas = append(cas, newAuthority(certs, ""))
+	}
+	return cas, nil
+}
+
+// newAuthority returns the authority at uri with the chain certs, from its
+// certificate to its root.
+func newAuthority(certs []*x509.Certificate, uri string) authority {
+	a := authority{URI: uri}
+	if len(certs[0].Subject.Organization) > 0 {
+		a.Subject.Organization = certs[0].Subject.Organization[0]
+	}
+	a.Subject.CommonName = certs[0].Subject.CommonName
+	start := certs[0].NotBefore.UTC()
+	a.ValidFor.Start = &start
+	for _, cert := range certs {
+		a.CertChain.Certificates = append(a.CertChain.Certificates, rawBytes{RawBytes: cert.Raw})
+	}
+	return a
+}
+
+// Authority is a certificate or timestamp authority of a trusted root made
+// with Marshal.
+type Authority struct {
+	URI string
+	// Chain is the chain of the authority, from its certificate to its
+	// root.
+	Chain []*x509.Certificate
+}
+
+// Marshal returns the JSON encoded trusted root, with no transparency logs,
+// of the certificate authorities cas and the timestamp authorities tsas,
+// e.g. of a test environment.
+func Marshal(cas, tsas []Authority) ([]byte, error) {
+	root := &trustedRoot{MediaType: mediaType, Tlogs: []transparencyLog{}, Ctlogs: []transparencyLog{}}
+	for _, t := range []struct {
+		authorities []Authority
+		to          *[]authority
+	}{
+		{cas, &root.CertificateAuthorities},
+		{tsas, &root.TimestampAuthorities},
+	} {
+		for _, a := range t.authorities {
+			if len(a.Chain) == 0 {
+				return nil, fmt.Errorf("the authority %s has no certificates", a.URI)
+			}
+			*t.to = append(*t.to, newAuthority(a.Chain, a.URI))
+		}
+	}
+	return json.MarshalIndent(root, "", "  ")
+}
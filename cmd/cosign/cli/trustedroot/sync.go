@@ -0,0 +1,151 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trustedroot maintains Sigstore trusted roots, the trust material
+// that verification against a pinned --trusted-root uses in place of TUF.
+package trustedroot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tufcache"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/blob"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+// trustedRootTarget is the target of the trusted root of a TUF repository.
+const trustedRootTarget = "trusted_root.json"
+
+// SyncCmd refreshes the TUF repository, writes its trusted root to o.Output
+// and lists the changes to the previous contents of o.Output to out.
+func SyncCmd(ctx context.Context, o options.TrustedRootSyncOptions, out io.Writer) error {
+	unlock, err := tufcache.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	mirror := o.Mirror
+	var root []byte
+	if mirror == "" {
+		if o.Root != "" {
+			return errors.New("--root requires --mirror")
+		}
+		t, err := tuf.NewFromEnv(ctx)
+		if err != nil {
+			return err
+		}
+		mirror = t.Mirror()
+	} else if o.Root != "" {
+		var err error
+		if root, err = blob.LoadFileOrURL(o.Root); err != nil {
+			return err
+		}
+	}
+	// Initializing forces a refresh of the metadata, rather than only once
+	// the timestamp expires.
+	if err := tuf.Initialize(ctx, mirror, root); err != nil {
+		return fmt.Errorf("refreshing TUF repository %s: %w", mirror, err)
+	}
+	client, err := tuf.NewFromEnv(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := tuf.GetRootStatus(ctx)
+	if err != nil {
+		return err
+	}
+	return syncTrustedRoot(ctx, client, status, o, out, time.Now())
+}
+
+func syncTrustedRoot(ctx context.Context, src source, status *tuf.RootStatus, o options.TrustedRootSyncOptions, out io.Writer, now time.Time) error {
+	snapshot := status.Metadata["snapshot.json"].Version
+	if o.SnapshotVersion != 0 && snapshot != o.SnapshotVersion {
+		return fmt.Errorf("the TUF repository is at snapshot version %d, not the pinned version %d", snapshot, o.SnapshotVersion)
+	}
+
+	var previous *trustedRoot
+	path := filepath.Clean(o.Output)
+	if b, err := os.ReadFile(path); err == nil {
+		previous = &trustedRoot{}
+		if err := json.Unmarshal(b, previous); err != nil {
+			return fmt.Errorf("parsing the previous trusted root %s: %w", o.Output, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading the previous trusted root: %w", err)
+	}
+
+	// The trusted root target is written as it is, so that it can be
+	// compared with that of the repository.
+	var raw []byte
+	next := &trustedRoot{}
+	if hasTarget(status, trustedRootTarget) {
+		var err error
+		if raw, err = src.GetTarget(trustedRootTarget); err != nil {
+			return fmt.Errorf("reading %s target: %w", trustedRootTarget, err)
+		}
+		if err := json.Unmarshal(raw, next); err != nil {
+			return fmt.Errorf("parsing %s target: %w", trustedRootTarget, err)
+		}
+	} else {
+		var err error
+		if next, err = assemble(src, previous, now); err != nil {
+			return err
+		}
+		if raw, err = json.MarshalIndent(next, "", "  "); err != nil {
+			return err
+		}
+		raw = append(raw, '\n')
+	}
+	if _, err := cosign.ParseTrustedRoot(raw, now); err != nil {
+		return fmt.Errorf("the trusted root of the TUF repository is invalid: %w", err)
+	}
+
+	changes := diff(previous, next)
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "%s is up to date with TUF snapshot version %d\n", o.Output, snapshot)
+	} else {
+		fmt.Fprintf(out, "The trusted root of TUF snapshot version %d changes %s:\n", snapshot, o.Output)
+		for _, c := range changes {
+			fmt.Fprintf(out, "  %s\n", c)
+		}
+	}
+	if o.DryRun || (len(changes) == 0 && previous != nil) {
+		return nil
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return fmt.Errorf("writing trusted root: %w", err)
+	}
+	ui.Infof(ctx, "Wrote the trusted root to %s", o.Output)
+	return nil
+}
+
+func hasTarget(status *tuf.RootStatus, name string) bool {
+	for _, t := range status.Targets {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustedroot
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+// fakeSource is a TUF repository of the targets by usage and by name.
+type fakeSource struct {
+	targets map[string][]byte
+	byUsage map[tuf.UsageKind][]tuf.TargetFile
+}
+
+func (s *fakeSource) GetTarget(name string) ([]byte, error) {
+	if t, ok := s.targets[name]; ok {
+		return t, nil
+	}
+	return nil, errors.New("no target " + name)
+}
+
+func (s *fakeSource) GetTargetsByMeta(usage tuf.UsageKind, _ []string) ([]tuf.TargetFile, error) {
+	return s.byUsage[usage], nil
+}
+
+func newKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub
+}
+
+func newStatus(snapshot int, targets ...string) *tuf.RootStatus {
+	return &tuf.RootStatus{
+		Metadata: map[string]tuf.MetadataStatus{"snapshot.json": {Version: snapshot}},
+		Targets:  targets,
+	}
+}
+
+func TestSyncTrustedRoot(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	rootCert, rootKey, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subCert, _, err := test.GenerateSubordinateCa(rootCert, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootPEM, err := cryptoutils.MarshalCertificateToPEM(rootCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subPEM, err := cryptoutils.MarshalCertificateToPEM(subCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rekorPEM, oldRekorPEM, ctlogPEM := newKeyPEM(t), newKeyPEM(t), newKeyPEM(t)
+	src := &fakeSource{byUsage: map[tuf.UsageKind][]tuf.TargetFile{
+		tuf.Fulcio: {{Target: rootPEM, Status: tuf.Active}, {Target: subPEM, Status: tuf.Active}},
+		tuf.Rekor:  {{Target: rekorPEM, Status: tuf.Active}},
+		tuf.CTFE:   {{Target: ctlogPEM, Status: tuf.Active}},
+	}}
+	output := filepath.Join(t.TempDir(), "trusted_root.json")
+	o := options.TrustedRootSyncOptions{Output: output}
+
+	// The trusted root is assembled from the targets of each usage.
+	var out bytes.Buffer
+	if err := syncTrustedRoot(ctx, src, newStatus(3), o, &out, now); err != nil {
+		t.Fatalf("syncTrustedRoot() = %v", err)
+	}
+	b, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := trustedRoot{}
+	if err := json.Unmarshal(b, &root); err != nil {
+		t.Fatal(err)
+	}
+	if len(root.Tlogs) != 1 || len(root.Ctlogs) != 1 || len(root.CertificateAuthorities) != 2 {
+		t.Fatalf("trusted root = %+v, want a Rekor log, a CT log and 2 certificate authorities", root)
+	}
+	if chain := root.CertificateAuthorities[1].CertChain.Certificates; len(chain) != 2 || !bytes.Equal(chain[1].RawBytes, rootCert.Raw) {
+		t.Errorf("intermediate chain has %d certificates, want it completed with the root", len(chain))
+	}
+	if got := out.String(); !strings.Contains(got, "snapshot version 3") || strings.Count(got, "\n  + ") != 4 {
+		t.Errorf("syncTrustedRoot() printed %q, want 4 additions", got)
+	}
+
+	// A rotated Rekor key is still trusted until it expires.
+	src.byUsage[tuf.Rekor] = []tuf.TargetFile{{Target: oldRekorPEM, Status: tuf.Active}, {Target: rekorPEM, Status: tuf.Expired}}
+	out.Reset()
+	if err := syncTrustedRoot(ctx, src, newStatus(4), o, &out, now); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); strings.Count(got, "\n  + Rekor log") != 1 || strings.Count(got, "\n  ~ Rekor log") != 1 || strings.Contains(got, "\n  - ") {
+		t.Errorf("syncTrustedRoot() printed %q, want an added and an expired Rekor log", got)
+	}
+
+	// The same metadata leaves the trusted root up to date.
+	out.Reset()
+	if err := syncTrustedRoot(ctx, src, newStatus(4), o, &out, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.Contains(got, "is up to date") {
+		t.Errorf("syncTrustedRoot() printed %q, want up to date", got)
+	}
+
+	// The trusted_root.json target is written as it is, unless --dry-run.
+	target := []byte(`{"mediaType":"` + mediaType + `","tlogs":[],"certificateAuthorities":[],"ctlogs":[]}`)
+	src.targets = map[string][]byte{trustedRootTarget: target}
+	before, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	dryRun := o
+	dryRun.DryRun = true
+	if err := syncTrustedRoot(ctx, src, newStatus(5, trustedRootTarget), dryRun, &out, now); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(output); !bytes.Equal(before, after) {
+		t.Error("syncTrustedRoot() with --dry-run wrote the trusted root")
+	}
+	if got := out.String(); strings.Count(got, "\n  - ") != 5 {
+		t.Errorf("syncTrustedRoot() printed %q, want 5 removals", got)
+	}
+	if err := syncTrustedRoot(ctx, src, newStatus(5, trustedRootTarget), o, &out, now); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(output); !bytes.Equal(after, target) {
+		t.Errorf("syncTrustedRoot() wrote %q, want the trusted_root.json target", after)
+	}
+
+	for name, tc := range map[string]struct {
+		o      options.TrustedRootSyncOptions
+		status *tuf.RootStatus
+		target []byte
+		want   string
+	}{
+		"another snapshot version": {
+			o:      options.TrustedRootSyncOptions{Output: output, SnapshotVersion: 4},
+			status: newStatus(5, trustedRootTarget),
+			target: target,
+			want:   "not the pinned version 4",
+		},
+		"invalid target": {
+			o:      o,
+			status: newStatus(5, trustedRootTarget),
+			target: []byte(`{"tlogs":[{"publicKey":{"rawBytes":"AAAA"}}]}`),
+			want:   "is invalid",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := &fakeSource{targets: map[string][]byte{trustedRootTarget: tc.target}}
+			if err := syncTrustedRoot(ctx, src, tc.status, tc.o, &out, now); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("syncTrustedRoot() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
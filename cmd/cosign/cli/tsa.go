@@ -0,0 +1,125 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/tsa"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+func TSA() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tsa",
+		Short: "Provides utilities for RFC 3161 timestamp authorities",
+	}
+
+	cmd.AddCommand(tsaServe())
+
+	return cmd
+}
+
+func tsaServe() *cobra.Command {
+	o := &options.TSAServeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a minimal RFC 3161 timestamp authority for testing",
+		Long: `Run a minimal RFC 3161 timestamp authority, for integration tests and
+air-gapped labs that exercise --timestamp-server-url without standing up a
+timestamp authority.
+
+It serves the API of the Sigstore timestamp authority: timestamp requests are
+POSTed to /api/v1/timestamp, and the certificate chain is served at
+/api/v1/timestamp/certchain. With --ephemeral, which is required, the
+timestamps are signed with a key and a certificate chain generated in memory,
+which are lost when the command exits, so the timestamps must not be relied on
+outside of testing. --cert-chain writes the chain for the
+--timestamp-certificate-chain flag of the verify commands.`,
+		Example: `  cosign tsa serve --ephemeral --cert-chain tsa-chain.pem
+
+  # timestamp a signature, then verify it with the chain of the timestamp authority
+  cosign sign-blob --key cosign.key --timestamp-server-url http://localhost:3000/api/v1/timestamp \
+    --rfc3161-timestamp artifact.tsr --tlog-upload=false --output-signature artifact.sig artifact
+  cosign verify-blob --key cosign.pub --signature artifact.sig --rfc3161-timestamp artifact.tsr \
+    --timestamp-certificate-chain tsa-chain.pem --insecure-ignore-tlog artifact`,
+		Args:             cobra.NoArgs,
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return TSAServeCmd(cmd.Context(), *o)
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// TSAServeCmd serves an ephemeral timestamp authority on o.Address until ctx
+// is done or cosign is interrupted.
+func TSAServeCmd(ctx context.Context, o options.TSAServeOptions) error {
+	if !o.Ephemeral {
+		return errors.New("only an ephemeral timestamp authority is supported, pass --ephemeral")
+	}
+	s, err := tsa.NewEphemeralServer()
+	if err != nil {
+		return err
+	}
+	if o.CertChain != "" {
+		b, err := s.CertChainPEM()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(o.CertChain, b, 0o600); err != nil {
+			return fmt.Errorf("writing certificate chain: %w", err)
+		}
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	l, err := net.Listen("tcp", o.Address)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	ui.Warnf(ctx, "The timestamp authority signs with an ephemeral key, its timestamps are only for testing")
+	ui.Infof(ctx, "Serving timestamps on http://%s%s", l.Addr(), tsa.TimestampPath)
+	if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
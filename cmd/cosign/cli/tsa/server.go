@@ -0,0 +1,147 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tsa is a minimal RFC 3161 timestamp authority, for testing
+// --timestamp-server-url flows without external infrastructure.
+package tsa
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/digitorus/timestamp"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/timestamp-authority/pkg/signer"
+)
+
+const (
+	// TimestampPath and CertChainPath are the paths of the API of the
+	// Sigstore timestamp authority that the server implements.
+	TimestampPath = "/api/v1/timestamp"
+	CertChainPath = "/api/v1/timestamp/certchain"
+
+	// maxRequestSize is the largest timestamp request served, as the
+	// Sigstore timestamp authority allows.
+	maxRequestSize = 10 << 10
+)
+
+// policy is the TSA policy of the timestamps, that of the Sigstore
+// timestamp authority.
+var policy = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 2}
+
+// Server is a timestamp authority serving RFC 3161 timestamps, signed by a
+// key and certificate chain generated in memory.
+type Server struct {
+	signer crypto.Signer
+	chain  []*x509.Certificate
+	// now returns the time of the timestamps, time.Now if nil.
+	now func() time.Time
+}
+
+// NewEphemeralServer returns a server with a new key and a certificate
+// chain, of a root, an intermediate and the timestamping certificate, that
+// are lost when it stops.
+func NewEphemeralServer() (*Server, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating the timestamping key: %w", err)
+	}
+	chain, err := signer.NewTimestampingCertWithChain(priv)
+	if err != nil {
+		return nil, fmt.Errorf("generating the timestamping certificate chain: %w", err)
+	}
+	return &Server{signer: priv, chain: chain}, nil
+}
+
+// CertChainPEM returns the certificate chain of s, the timestamping
+// certificate first, as passed to --timestamp-certificate-chain.
+func (s *Server) CertChainPEM() ([]byte, error) {
+	return cryptoutils.MarshalCertificatesToPEM(s.chain)
+}
+
+// ServeHTTP implements http.Handler. It timestamps the requests POSTed to
+// TimestampPath and serves the certificate chain at CertChainPath.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case TimestampPath:
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.serveTimestamp(w, r)
+	case CertChainPath:
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		b, err := s.CertChainPEM()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		_, _ = w.Write(b)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveTimestamp(w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, "reading the timestamp request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	req, err := timestamp.ParseRequest(b)
+	if err != nil {
+		http.Error(w, "parsing the timestamp request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := s.timestamp(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/timestamp-reply")
+	_, _ = w.Write(resp)
+}
+
+// timestamp returns the DER-encoded response to req.
+func (s *Server) timestamp(req *timestamp.Request) ([]byte, error) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	ts := timestamp.Timestamp{
+		HashAlgorithm:     req.HashAlgorithm,
+		HashedMessage:     req.HashedMessage,
+		Time:              now(),
+		Nonce:             req.Nonce,
+		Policy:            policy,
+		Accuracy:          time.Second,
+		AddTSACertificate: req.Certificates,
+	}
+	return ts.CreateResponse(s.chain[0], s.signer)
+}
@@ -0,0 +1,98 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsa
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/timestamp-authority/pkg/verification"
+
+	tsaclient "github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa/client"
+	"github.com/sigstore/cosign/v2/pkg/cosign/tsa"
+)
+
+func TestServer(t *testing.T) {
+	s, err := NewEphemeralServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.now = func() time.Time { return at }
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + CertChainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaves, intermediates, roots, err := tsaclient.SplitPEMCertificateChain(pem)
+	if err != nil || len(leaves) != 1 || len(intermediates) != 1 || len(roots) != 1 {
+		t.Fatalf("certificate chain = %d leaves, %d intermediates, %d roots, %v", len(leaves), len(intermediates), len(roots), err)
+	}
+
+	// A signature timestamped as cosign sign does verifies with the chain.
+	sig := []byte("signature")
+	tsr, err := tsaclient.GetTimestampedSignature(sig, client.NewTSAClient(srv.URL+TimestampPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := tsa.VerifyTimestampResponse(tsr, sig, []verification.VerifyOpts{{
+		TSACertificate: leaves[0],
+		Intermediates:  intermediates,
+		Roots:          roots,
+	}})
+	if err != nil {
+		t.Fatalf("VerifyTimestampResponse() = %v", err)
+	}
+	if !ts.Time.Equal(at) {
+		t.Errorf("timestamp time = %s, want %s", ts.Time, at)
+	}
+
+	for _, tc := range []struct {
+		method, path, body string
+		want               int
+	}{
+		{method: http.MethodGet, path: TimestampPath, want: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: CertChainPath, want: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: TimestampPath, body: "not a request", want: http.StatusBadRequest},
+		{method: http.MethodPost, path: TimestampPath, body: strings.Repeat("x", maxRequestSize+1), want: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/", want: http.StatusNotFound},
+	} {
+		req, err := http.NewRequest(tc.method, srv.URL+tc.path, strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s %s = %d, want %d", tc.method, tc.path, resp.StatusCode, tc.want)
+		}
+	}
+}
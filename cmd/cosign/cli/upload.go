@@ -20,6 +20,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/upload"
 )
@@ -62,7 +63,14 @@ func uploadBlob() *cobra.Command {
   cosign upload blob -a mykey=myvalue -f foo <IMAGE>
 
   # upload two blobs named foo-darwin and foo-linux to the location specified by <IMAGE>, setting annotations
-  cosign upload blob -a mykey=myvalue -a myotherkey="my other value" -f foo-darwin:darwin -f foo-linux:linux <IMAGE>`,
+  cosign upload blob -a mykey=myvalue -a myotherkey="my other value" -f foo-darwin:darwin -f foo-linux:linux <IMAGE>
+
+  # upload a release bundle as a single OCI artifact, with one layer per file
+  cosign upload blob --artifact-type application/vnd.example.release.v1 -f app.tar.gz -f checksums.txt \
+    --layer-media-type checksums.txt=text/plain --layer-annotation app.tar.gz:org.example.os=linux <IMAGE>
+
+  # upload a blob, then sign it and attest its provenance, rolling back the upload if either fails
+  cosign upload blob --sign --attest provenance.json --type slsaprovenance --key cosign.key -f foo <IMAGE>`,
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -72,12 +80,24 @@ func uploadBlob() *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			artifactFiles, err := o.ArtifactFiles()
+			if err != nil {
+				return err
+			}
+			p, err := uploadPublisher(o.Publish)
+			if err != nil {
+				return err
+			}
+			if o.ArtifactType != "" {
+				return upload.ArtifactCmd(cmd.Context(), o.Registry, o.ArtifactType, artifactFiles, o.Annotations, p, args[0])
+			}
+
 			files, err := o.Files.Parse()
 			if err != nil {
 				return err
 			}
 
-			return upload.BlobCmd(cmd.Context(), o.Registry, files, o.Annotations, o.ContentType, args[0])
+			return upload.BlobCmd(cmd.Context(), o.Registry, files, o.Annotations, o.ContentType, p, args[0])
 		},
 	}
 
@@ -90,13 +110,24 @@ func uploadWASM() *cobra.Command {
 	o := &options.UploadWASMOptions{}
 
 	cmd := &cobra.Command{
-		Use:              "wasm",
-		Short:            "Upload a wasm module to the supplied container image reference",
-		Example:          "  cosign upload wasm -f foo.wasm <image uri>",
+		Use:   "wasm",
+		Short: "Upload a wasm module to the supplied container image reference",
+		Example: `  cosign upload wasm -f foo.wasm <image uri>
+
+  # upload a wasm module with annotations and an artifactType
+  cosign upload wasm -f foo.wasm -a org.opencontainers.image.source=https://github.com/example/foo \
+    --artifact-type application/vnd.example.plugin.v1 <IMAGE>
+
+  # upload a wasm module, then sign it and attest its provenance, rolling back the upload if either fails
+  cosign upload wasm -f foo.wasm --sign --attest provenance.json --type slsaprovenance --yes <IMAGE>`,
 		Args:             cobra.ExactArgs(1),
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return upload.WasmCmd(cmd.Context(), o.Registry, o.File, args[0])
+			p, err := uploadPublisher(o.Publish)
+			if err != nil {
+				return err
+			}
+			return upload.WasmCmd(cmd.Context(), o.Registry, o.File, o.Annotations, o.ArtifactType, p, args[0])
 		},
 	}
 
@@ -104,3 +135,43 @@ func uploadWASM() *cobra.Command {
 
 	return cmd
 }
+
+// uploadPublisher returns the Publisher that signs and attests the artifact
+// an upload command uploads.
+func uploadPublisher(o options.UploadPublishOptions) (*upload.Publisher, error) {
+	oidcClientSecret, err := o.OIDC.ClientSecret()
+	if err != nil {
+		return nil, err
+	}
+	ko := options.KeyOpts{
+		KeyRef:                   o.Key,
+		PassFunc:                 generate.GetPass,
+		Sk:                       o.SecurityKey.Use,
+		Slot:                     o.SecurityKey.Slot,
+		FulcioURL:                o.Fulcio.URL,
+		IDToken:                  o.Fulcio.IdentityToken,
+		InsecureSkipFulcioVerify: o.Fulcio.InsecureSkipFulcioVerify,
+		ACME:                     o.Fulcio.ACME,
+		RekorURL:                 o.Rekor.URL,
+		OIDCIssuer:               o.OIDC.Issuer,
+		OIDCClientID:             o.OIDC.ClientID,
+		OIDCClientSecret:         oidcClientSecret,
+		OIDCRedirectURL:          o.OIDC.RedirectURL,
+		OIDCDisableProviders:     o.OIDC.DisableAmbientProviders,
+		OIDCProvider:             o.OIDC.Provider,
+		OIDCTokenFile:            o.OIDC.TokenFile,
+		OIDCAudience:             o.OIDC.Audience,
+		OIDCTokenExchangeIssuer:  o.OIDC.TokenExchangeIssuer,
+		SkipConfirmation:         o.SkipConfirmation,
+	}
+	return &upload.Publisher{
+		RootOptions:      ro,
+		KeyOpts:          ko,
+		Sign:             o.Sign,
+		TlogUpload:       o.TlogUpload,
+		PredicatePath:    o.Attest,
+		PredicateType:    o.Predicate.Type,
+		PredicateSchemas: o.Predicate.Schemas,
+		PredicateSchema:  o.Predicate.Schema,
+	}, nil
+}
@@ -26,10 +26,13 @@ import (
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 )
 
-func BlobCmd(ctx context.Context, regOpts options.RegistryOptions, files []cremote.File, annotations map[string]string, contentType, imageRef string) error {
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+// BlobCmd uploads files as one image per platform, in a multi-platform index
+// if there are several, then signs and attests the upload with p.
+func BlobCmd(ctx context.Context, regOpts options.RegistryOptions, files []cremote.File, annotations map[string]string, contentType string, p *Publisher, imageRef string) error {
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
 	if err != nil {
 		return err
 	}
@@ -43,7 +46,9 @@ func BlobCmd(ctx context.Context, regOpts options.RegistryOptions, files []cremo
 		}
 	}
 
-	dgstAddr, err := cremote.UploadFiles(ref, files, annotations, mt, regOpts.GetRegistryClientOpts(ctx)...)
+	dgstAddr, err := p.publish(ctx, regOpts, ref, func() (name.Digest, error) {
+		return cremote.UploadFiles(ref, files, annotations, mt, regOpts.GetRegistryClientOpts(ctx)...)
+	})
 	if err != nil {
 		return err
 	}
@@ -58,3 +63,25 @@ func BlobCmd(ctx context.Context, regOpts options.RegistryOptions, files []cremo
 	}
 	return nil
 }
+
+// ArtifactCmd uploads files as the layers of a single OCI 1.1 artifact with
+// the given artifactType, then signs and attests it with p.
+func ArtifactCmd(ctx context.Context, regOpts options.RegistryOptions, artifactType string, files []cremote.ArtifactFile, annotations map[string]string, p *Publisher, imageRef string) error {
+	if len(files) == 0 {
+		return errors.New("no files to upload")
+	}
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
+	if err != nil {
+		return err
+	}
+
+	dgstAddr, err := p.publish(ctx, regOpts, ref, func() (name.Digest, error) {
+		return cremote.UploadArtifact(ref, artifactType, files, annotations, cremote.DefaultMediaTypeGetter, regOpts.GetRegistryClientOpts(ctx)...)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Uploaded artifact to:")
+	fmt.Println(dgstAddr)
+	return nil
+}
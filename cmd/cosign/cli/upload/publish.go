@@ -0,0 +1,181 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// Publisher signs and attests an artifact right after it is uploaded. If the
+// upload, the signature or the attestation fails, the tags written for the
+// artifact are rolled back to the manifests they pointed to before, so the
+// artifact is published with its signature and attestation or not at all.
+// Entries already added to the transparency log can't be rolled back.
+type Publisher struct {
+	RootOptions *options.RootOptions
+	KeyOpts     options.KeyOpts
+	Sign        bool
+	TlogUpload  bool
+
+	PredicatePath    string
+	PredicateType    string
+	PredicateSchemas string
+	PredicateSchema  string
+}
+
+func (p *Publisher) enabled() bool {
+	return p != nil && (p.Sign || p.PredicatePath != "")
+}
+
+// publish runs upload, which writes the artifact to ref and returns its
+// digest, then signs and attests the artifact.
+func (p *Publisher) publish(ctx context.Context, regOpts options.RegistryOptions, ref name.Reference, upload func() (name.Digest, error)) (name.Digest, error) {
+	if !p.enabled() {
+		return upload()
+	}
+	ropts := regOpts.GetRegistryClientOpts(ctx)
+	var snapshots []tagSnapshot
+	rollback := func(err error) error {
+		for i := len(snapshots) - 1; i >= 0; i-- {
+			if rerr := snapshots[i].restore(ropts); rerr != nil {
+				return fmt.Errorf("%w; rolling back %s: %v", err, snapshots[i].tag, rerr)
+			}
+		}
+		return err
+	}
+	snapshot := func(tag name.Tag) error {
+		s, err := snapshotTag(tag, ropts)
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, s)
+		return nil
+	}
+
+	if tag, ok := ref.(name.Tag); ok {
+		if err := snapshot(tag); err != nil {
+			return name.Digest{}, err
+		}
+	}
+	digest, err := upload()
+	if err != nil {
+		return name.Digest{}, rollback(err)
+	}
+
+	ociremoteOpts, err := regOpts.ClientOpts(ctx)
+	if err != nil {
+		return name.Digest{}, rollback(err)
+	}
+	if p.Sign {
+		tag, err := ociremote.SignatureTag(digest, ociremoteOpts...)
+		if err == nil {
+			err = snapshot(tag)
+		}
+		if err != nil {
+			return name.Digest{}, rollback(err)
+		}
+		signOpts := options.SignOptions{
+			Upload:           true,
+			TlogUpload:       p.TlogUpload,
+			SkipConfirmation: p.KeyOpts.SkipConfirmation,
+			Registry:         regOpts,
+		}
+		if err := sign.SignCmd(ctx, p.RootOptions, p.KeyOpts, signOpts, []string{digest.String()}); err != nil {
+			return name.Digest{}, rollback(fmt.Errorf("signing %s: %w", digest, err))
+		}
+	}
+	if p.PredicatePath != "" {
+		tag, err := ociremote.AttestationTag(digest, ociremoteOpts...)
+		if err == nil {
+			err = snapshot(tag)
+		}
+		if err != nil {
+			return name.Digest{}, rollback(err)
+		}
+		a := &attest.AttestCommand{
+			KeyOpts:          p.KeyOpts,
+			RegistryOptions:  regOpts,
+			PredicatePath:    p.PredicatePath,
+			PredicateType:    p.PredicateType,
+			PredicateSchemas: p.PredicateSchemas,
+			PredicateSchema:  p.PredicateSchema,
+			TlogUpload:       p.TlogUpload,
+			Timeout:          p.RootOptions.Timeout,
+		}
+		if err := a.Exec(ctx, digest.String()); err != nil {
+			return name.Digest{}, rollback(fmt.Errorf("attesting %s: %w", digest, err))
+		}
+	}
+	return digest, nil
+}
+
+// tagSnapshot is the manifest a tag pointed to, or nil if the tag didn't
+// exist.
+type tagSnapshot struct {
+	tag  name.Tag
+	desc *remote.Descriptor
+}
+
+func snapshotTag(tag name.Tag, ropts []remote.Option) (tagSnapshot, error) {
+	desc, err := remote.Get(tag, ropts...)
+	if isNotFound(err) {
+		return tagSnapshot{tag: tag}, nil
+	}
+	if err != nil {
+		return tagSnapshot{}, err
+	}
+	return tagSnapshot{tag: tag, desc: desc}, nil
+}
+
+// restore points the tag to the manifest it pointed to before, or deletes it
+// if it didn't exist. Only the tag is deleted, never the manifest by digest,
+// because other tags may point to the same manifest.
+func (s tagSnapshot) restore(ropts []remote.Option) error {
+	current, err := remote.Head(s.tag, ropts...)
+	switch {
+	case isNotFound(err):
+		current = nil
+	case err != nil:
+		return err
+	}
+	if s.desc == nil {
+		if current == nil {
+			return nil
+		}
+		return remote.Delete(s.tag, ropts...)
+	}
+	if current != nil && current.Digest == s.desc.Digest {
+		return nil
+	}
+	return remote.Put(s.tag, s.desc, ropts...)
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
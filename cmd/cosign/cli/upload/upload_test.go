@@ -0,0 +1,170 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+	"github.com/sigstore/cosign/v2/pkg/types"
+)
+
+func testRepo(t *testing.T) name.Repository {
+	t.Helper()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func writeFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestWasmCmd(t *testing.T) {
+	repo := testRepo(t)
+	wasm := writeFile(t, t.TempDir(), "plugin.wasm", "\x00asm\x01\x00\x00\x00")
+	annotations := map[string]string{"org.example.name": "plugin"}
+	ref := repo.Tag("v1")
+	if err := WasmCmd(context.Background(), options.RegistryOptions{}, wasm, annotations, "application/vnd.example.plugin.v1", nil, ref.String()); err != nil {
+		t.Fatalf("WasmCmd() = %v", err)
+	}
+
+	desc, err := remote.Get(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := struct {
+		ArtifactType string            `json:"artifactType"`
+		Annotations  map[string]string `json:"annotations"`
+		Config       struct {
+			MediaType string `json:"mediaType"`
+		} `json:"config"`
+	}{}
+	if err := json.Unmarshal(desc.Manifest, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.ArtifactType != "application/vnd.example.plugin.v1" || m.Annotations["org.example.name"] != "plugin" || m.Config.MediaType != types.WasmConfigMediaType {
+		t.Errorf("manifest = %s, want the artifactType, annotations and wasm config", desc.Manifest)
+	}
+	// The wasm module is still readable as an image.
+	img, err := desc.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, err := img.Digest(); err != nil || h != desc.Digest {
+		t.Errorf("image digest = %v, %v, want %v", h, err, desc.Digest)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Publisher{
+		RootOptions: &options.RootOptions{Timeout: options.DefaultTimeout},
+		KeyOpts:     options.KeyOpts{KeyRef: writeFile(t, td, "cosign.key", string(keys.PrivateBytes)), SkipConfirmation: true},
+		Sign:        true,
+		// The attestation fails because the predicate doesn't exist.
+		PredicatePath: filepath.Join(td, "missing.json"),
+		PredicateType: options.PredicateCustom,
+	}
+
+	repo := testRepo(t)
+	ref := repo.Tag("latest")
+	prev, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, prev); err != nil {
+		t.Fatal(err)
+	}
+	prevDigest, err := prev.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := func() []string {
+		t.Helper()
+		tags, err := remote.List(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(tags)
+		return tags
+	}
+
+	files := []cremote.File{cremote.FileFromFlag(writeFile(t, td, "artifact", "release artifact"))}
+	err = BlobCmd(context.Background(), options.RegistryOptions{}, files, nil, "", p, ref.String())
+	if err == nil || !strings.Contains(err.Error(), "attesting") {
+		t.Fatalf("BlobCmd() with a missing predicate = %v, want an attesting error", err)
+	}
+	// The tag points to the previous image again, and the signature is gone.
+	if desc, err := remote.Head(ref); err != nil || desc.Digest != prevDigest {
+		t.Errorf("after the rollback, %s = %v, %v, want %s", ref, desc, err, prevDigest)
+	}
+	if got := tags(); len(got) != 1 || got[0] != "latest" {
+		t.Errorf("after the rollback, tags = %v, want only latest", got)
+	}
+
+	p.PredicatePath = writeFile(t, td, "predicate.json", `{"builder":"ci"}`)
+	if err := BlobCmd(context.Background(), options.RegistryOptions{}, files, nil, "", p, ref.String()); err != nil {
+		t.Fatalf("BlobCmd() = %v", err)
+	}
+	desc, err := remote.Head(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := "sha256-" + desc.Digest.Hex
+	if got, want := tags(), []string{"latest", sig + ".att", sig + ".sig"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+
+	// A new tag is deleted again when the upload can't be published.
+	p.PredicatePath = filepath.Join(td, "missing.json")
+	wasm := writeFile(t, td, "plugin.wasm", "\x00asm\x01\x00\x00\x00")
+	if err := WasmCmd(context.Background(), options.RegistryOptions{}, wasm, nil, "", p, repo.Tag("plugin").String()); err == nil {
+		t.Fatal("WasmCmd() with a missing predicate: expected an error")
+	}
+	if got := tags(); len(got) != 3 {
+		t.Errorf("after the rollback, tags = %v, want those of latest", got)
+	}
+}
@@ -16,31 +16,98 @@
 package upload
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
 	"github.com/sigstore/cosign/v2/pkg/types"
 )
 
-func WasmCmd(ctx context.Context, regOpts options.RegistryOptions, wasmPath, imageRef string) error {
+// WasmCmd uploads a wasm module with the given manifest annotations and, if
+// set, artifactType, then signs and attests it with p.
+func WasmCmd(ctx context.Context, regOpts options.RegistryOptions, wasmPath string, annotations map[string]string, artifactType string, p *Publisher, imageRef string) error {
 	b, err := os.ReadFile(wasmPath)
 	if err != nil {
 		return err
 	}
 
-	ref, err := name.ParseReference(imageRef, regOpts.NameOptions()...)
+	ref, err := oci.ParseReference(imageRef, regOpts.NameOptions()...)
 	if err != nil {
 		return err
 	}
 	fmt.Fprintf(os.Stderr, "Uploading wasm file from [%s] to [%s].\n", wasmPath, ref.Name())
-	img, err := static.NewFile(b, static.WithLayerMediaType(types.WasmLayerMediaType), static.WithConfigMediaType(types.WasmConfigMediaType))
+	opts := []static.Option{static.WithLayerMediaType(types.WasmLayerMediaType), static.WithConfigMediaType(types.WasmConfigMediaType)}
+	if annotations != nil {
+		opts = append(opts, static.WithAnnotations(annotations))
+	}
+	var img v1.Image
+	img, err = static.NewFile(b, opts...)
+	if err != nil {
+		return err
+	}
+	if artifactType != "" {
+		if img, err = withArtifactType(img, artifactType); err != nil {
+			return err
+		}
+	}
+
+	digest, err := p.publish(ctx, regOpts, ref, func() (name.Digest, error) {
+		h, err := img.Digest()
+		if err != nil {
+			return name.Digest{}, err
+		}
+		if err := remote.Write(ref, img, regOpts.GetRegistryClientOpts(ctx)...); err != nil {
+			return name.Digest{}, err
+		}
+		return ref.Context().Digest(h.String()), nil
+	})
 	if err != nil {
 		return err
 	}
-	return remote.Write(ref, img, regOpts.GetRegistryClientOpts(ctx)...)
+	fmt.Fprintln(os.Stderr, "Uploaded wasm module to:")
+	fmt.Println(digest)
+	return nil
+}
+
+// artifactImage is an image whose manifest has the artifactType field added
+// in OCI 1.1.
+type artifactImage struct {
+	v1.Image
+	raw []byte
+}
+
+func withArtifactType(img v1.Image, artifactType string) (v1.Image, error) {
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(struct {
+		*v1.Manifest
+		ArtifactType string `json:"artifactType"`
+	}{m, artifactType})
+	if err != nil {
+		return nil, err
+	}
+	return &artifactImage{Image: img, raw: raw}, nil
+}
+
+func (i *artifactImage) RawManifest() ([]byte, error) {
+	return i.raw, nil
+}
+
+func (i *artifactImage) Digest() (v1.Hash, error) {
+	h, _, err := v1.SHA256(bytes.NewReader(i.raw))
+	return h, err
+}
+
+func (i *artifactImage) Size() (int64, error) {
+	return int64(len(i.raw)), nil
 }
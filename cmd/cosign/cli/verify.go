@@ -16,14 +16,20 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/spf13/cobra"
 
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/environments"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/internal/pkg/forensics"
 	"github.com/sigstore/cosign/v2/internal/ui"
 )
 
@@ -31,12 +37,59 @@ const ignoreTLogMessage = "Skipping tlog verification is an insecure practice th
 
 func Verify() *cobra.Command {
 	o := &options.VerifyOptions{}
+	bo := &options.VerifyBatchOptions{}
+	ob := &options.OfflineBundleOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "verify",
 		Short: "Verify a signature on the supplied container image",
 		Long: `Verify signature and annotations on an image by checking the claims
-against the transparency log.`,
+against the transparency log.
+
+With --image-policy, each image is verified with the key or certificate
+identity of the first entry of the policy, as read by cosign proxy --policy,
+whose glob matches its repository and whose labels and annotations, if any,
+match those of the image, e.g.
+
+  {"images": [
+    {"glob": "registry.example.com/*", "labels": {"env": "prod"}, "key": "release.pub"},
+    {"glob": "registry.example.com/*", "certificateIdentityRegexp": "^https://github.com/example/",
+     "certificateOidcIssuer": "https://token.actions.githubusercontent.com"}
+  ]}
+
+With --environment-policy, each image is instead evaluated for every environment
+of the policy, whose entries are those of --image-policy with a name and the
+annotations that a signature must have to qualify the image for it, e.g.
+
+  {"environments": [
+    {"name": "dev", "key": "ci.pub"},
+    {"name": "staging", "key": "ci.pub", "signatureAnnotations": {"tests": "passed"}},
+    {"name": "prod", "glob": "registry.example.com/release/*", "key": "release.pub",
+     "signatureAnnotations": {"env": "prod"}}
+  ]}
+
+and the environments it passes or fails, with the reasons, are written as a
+matrix, as JSON or, with --output text, as a table. Verification only fails if
+an image qualifies for no environment.
+
+With --layer-policy, the layers or config of each image, selected as with
+cosign sign --layers, must also be signed with the key or certificate identity
+of each rule of the policy, e.g. the two base layers by the platform team that
+signed them in the repository of the base image:
+
+  {"rules": [
+    {"layers": ["0", "1"], "key": "platform.pub",
+     "sourceRepositories": ["registry.example.com/platform/base"]}
+  ]}
+
+Layer signatures are experimental and require the LayerSignatures feature gate.
+
+An image given as k8s-workload://<namespace>/<kind>/<name>, where kind is
+deployment, statefulset, daemonset, replicaset, job or pod, is replaced with
+the digests that the containers of the pods of the workload currently run,
+as their container runtimes report them, in the repositories of their specs.
+The cluster is the current context of the kubeconfig, or the cluster cosign
+runs in.`,
 		Example: `  cosign verify --key <key path>|<key url>|<kms uri> <image uri> [<image uri> ...]
 
   # verify cosign claims and signing certificates on the image with the transparency log
@@ -84,76 +137,297 @@ against the transparency log.`,
   cosign verify --key gitlab://[OWNER]/[PROJECT_NAME] <IMAGE>
 
   # verify image with public key stored in GitLab with project id
-  cosign verify --key gitlab://[PROJECT_ID] <IMAGE>`,
+  cosign verify --key gitlab://[PROJECT_ID] <IMAGE>
 
-		Args:             cobra.MinimumNArgs(1),
+  # verify image and write a verification report signed with the verifier's key
+  cosign verify --key cosign.pub --sign-report report.dsse.json --sign-report-key verifier.key <IMAGE>
+
+  # verify image and write a verification report signed keylessly with the verifier's OIDC identity
+  cosign verify --key cosign.pub --sign-report report.dsse.json --sign-report-certificate report.pem <IMAGE>
+
+  # verify a mirrored image using the signatures stored with the original image
+  cosign verify --key cosign.pub --source-repository registry.example.com/team/app mirror.example.com/app@sha256:<DIGEST>
+
+  # list the environments that an image may be promoted to
+  cosign verify --environment-policy environments.json --output text <IMAGE>
+
+  # also require the base layers of an image to be signed by the platform team
+  cosign verify --key cosign.pub --layer-policy layers.json <IMAGE>
+
+  # also fail images with more than 20 signatures, or without a verified SLSA provenance attestation
+  cosign verify --key cosign.pub --max-signatures 20 --require-predicate-type slsaprovenance <IMAGE>
+
+  # verify the signature of an image signed with a certificate of an internal ACME CA
+  cosign verify --acme-ca-roots acme-roots.pem --certificate-identity build.example.com --insecure-ignore-tlog <IMAGE>
+
+  # verify a multi-arch image and the image of each of its platforms
+  cosign verify --key cosign.pub --recursive <IMAGE>
+
+  # verify the images listed in a file, 8 at a time
+  cosign verify --key cosign.pub --batch-file images.txt --batch-workers 8
+
+  # verify the images read from stdin
+  kubectl get pods -o jsonpath='{.items[*].spec.containers[*].image}' | tr ' ' '\n' | cosign verify --key cosign.pub --batch-file -
+
+  # verify the images listed in a file for at most 10 minutes; those left are reported as not-evaluated
+  # in the results and the command exits with code 13
+  cosign verify --key cosign.pub --batch-file images.txt --timeout 10m --output json-v1
+
+  # verify an image offline with the bundle of 'cosign bundle export' and a pinned trusted root
+  cosign verify --bundle-file bundle.json --trusted-root trusted_root.json --certificate-identity foo@example.com --certificate-oidc-issuer https://issuer.example.com
+
+  # verify an image without network access to Rekor, with the Rekor entries of its signatures fetched ahead
+  cosign verify --key cosign.pub --offline --rekor-offline-bundle-dir rekor-entries/ <IMAGE>
+
+  # write the verification results as SARIF, e.g. for a code scanning dashboard
+  cosign verify --key cosign.pub --output sarif <IMAGE> > cosign.sarif
+
+  # write a report of the verification results with a Go template of the json-v1 results
+  cosign verify --key cosign.pub --batch-file images.txt --output-template report.tmpl
+
+  # cache the verified digests, so that verifying them again skips the registry and Rekor for an hour
+  cosign verify --key cosign.pub --cache-dir ~/.cache/cosign/verify --cache-ttl 1h <IMAGE>
+
+  # verify each image with the key or identity of its repository and labels, e.g. a stricter identity for env=prod
+  cosign verify --image-policy policy.json <IMAGE_1> <IMAGE_2> ...
+
+  # verify that an image is encrypted, and signed by the expected identity for the key that can decrypt it
+  cosign verify --certificate-identity=<IDENTITY> --certificate-oidc-issuer=<ISSUER> --encryption-recipient recipient.pub <IMAGE>
+
+  # verify an image signed from a tag by a GitHub-hosted runner of an organization
+  cosign verify --certificate-identity-regexp=^https://github.com/example/ --certificate-oidc-issuer=https://token.actions.githubusercontent.com \
+    --certificate-claim sourceRepositoryOwnerURI=https://github.com/example --certificate-claim sourceRepositoryRef^=refs/tags/ \
+    --certificate-claim runnerEnvironment=github-hosted <IMAGE>
+
+  # verify an image signed by a workflow of a GitHub Enterprise Server instance
+  cosign verify --certificate-identity-regexp=^https://ghes.example.com/example/ --certificate-oidc-issuer=github-actions:ghes.example.com <IMAGE>
+
+  # verify an image signed by either a release workflow or a maintainer, each with its issuer, printing which one matched
+  cosign verify --certificate-identity=https://github.com/example/app/.github/workflows/release.yml@refs/heads/main \
+    --certificate-oidc-issuer=https://token.actions.githubusercontent.com \
+    --certificate-identity-regexp='^[a-z]+@example\.com$' --certificate-oidc-issuer=https://accounts.google.com <IMAGE>
+
+  # write the manifests, envelopes, certificates and tlog responses fetched by a failed verification to a directory
+  cosign verify --failure-report failure/ --key cosign.pub <IMAGE>
+
+  # in a GitHub Actions step, append a table of the verified images, their signers and outcomes to the job summary
+  cosign verify --github-summary --batch-file images.txt --key cosign.pub
+
+  # verify the digests that the pods of a Deployment currently run, in the cluster of the kubeconfig
+  cosign verify --key cosign.pub k8s-workload://<NAMESPACE>/deployment/<NAME>`,
+
+		Args:             cobra.ArbitraryArgs,
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			annotations, err := o.AnnotationsMap()
+			v, err := newVerifyCommand(o, *bo, *ob)
 			if err != nil {
 				return err
 			}
 
-			hashAlgorithm, err := o.SignatureDigest.HashAlgorithm()
-			if err != nil {
-				return err
+			ctx := cmd.Context()
+			// Only an explicit --timeout limits verification, whose images
+			// left at the deadline are reported as not evaluated.
+			if f := cmd.Flag("timeout"); f != nil && f.Changed {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, ro.Timeout)
+				defer cancel()
 			}
 
-			v := &verify.VerifyCommand{
-				RegistryOptions:              o.Registry,
-				CertVerifyOptions:            o.CertVerify,
-				CheckClaims:                  o.CheckClaims,
-				KeyRef:                       o.Key,
-				CertRef:                      o.CertVerify.Cert,
-				CertGithubWorkflowTrigger:    o.CertVerify.CertGithubWorkflowTrigger,
-				CertGithubWorkflowSha:        o.CertVerify.CertGithubWorkflowSha,
-				CertGithubWorkflowName:       o.CertVerify.CertGithubWorkflowName,
-				CertGithubWorkflowRepository: o.CertVerify.CertGithubWorkflowRepository,
-				CertGithubWorkflowRef:        o.CertVerify.CertGithubWorkflowRef,
-				CertChain:                    o.CertVerify.CertChain,
-				IgnoreSCT:                    o.CertVerify.IgnoreSCT,
-				SCTRef:                       o.CertVerify.SCT,
-				Sk:                           o.SecurityKey.Use,
-				Slot:                         o.SecurityKey.Slot,
-				Output:                       o.Output,
-				RekorURL:                     o.Rekor.URL,
-				Attachment:                   o.Attachment,
-				Annotations:                  annotations,
-				HashAlgorithm:                hashAlgorithm,
-				SignatureRef:                 o.SignatureRef,
-				PayloadRef:                   o.PayloadRef,
-				LocalImage:                   o.LocalImage,
-				Offline:                      o.CommonVerifyOptions.Offline,
-				TSACertChainPath:             o.CommonVerifyOptions.TSACertChainPath,
-				IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+			if o.CommonVerifyOptions.IgnoreTlog {
+				ui.Warnf(ctx, fmt.Sprintf(ignoreTLogMessage, "signature"))
 			}
 
-			if o.Registry.AllowInsecure {
-				v.NameOptions = append(v.NameOptions, name.Insecure)
+			if o.EnvironmentPolicy != "" {
+				return verifyEnvironments(ctx, cmd.OutOrStdout(), v, o, args)
 			}
-
-			ctx := cmd.Context()
-
-			if o.CommonVerifyOptions.IgnoreTlog {
-				ui.Warnf(ctx, fmt.Sprintf(ignoreTLogMessage, "signature"))
+			if err := setImagePolicy(v, o); err != nil {
+				return err
 			}
 
-			return v.Exec(ctx, args)
+			return withFailureReport(cmd, args, o.CommonVerifyOptions.FailureReport, func() error {
+				return v.Exec(ctx, args)
+			})
 		},
 	}
 
 	o.AddFlags(cmd)
+	bo.AddFlags(cmd)
+	ob.AddFlags(cmd)
 	return cmd
 }
 
+// newVerifyCommand returns the VerifyCommand of the flags of cosign verify.
+func newVerifyCommand(o *options.VerifyOptions, bo options.VerifyBatchOptions, ob options.OfflineBundleOptions) (*verify.VerifyCommand, error) {
+	annotations, err := o.AnnotationsMap()
+	if err != nil {
+		return nil, err
+	}
+	signReportKeyOpts, err := o.SignReportKeyOpts()
+	if err != nil {
+		return nil, err
+	}
+
+	hashAlgorithm, err := o.SignatureDigest.HashAlgorithm()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &verify.VerifyCommand{
+		RegistryOptions:              o.Registry,
+		CertVerifyOptions:            o.CertVerify,
+		CheckClaims:                  o.CheckClaims,
+		KeyRef:                       o.Key,
+		CertRef:                      o.CertVerify.Cert,
+		CertGithubWorkflowTrigger:    o.CertVerify.CertGithubWorkflowTrigger,
+		CertGithubWorkflowSha:        o.CertVerify.CertGithubWorkflowSha,
+		CertGithubWorkflowName:       o.CertVerify.CertGithubWorkflowName,
+		CertGithubWorkflowRepository: o.CertVerify.CertGithubWorkflowRepository,
+		CertGithubWorkflowRef:        o.CertVerify.CertGithubWorkflowRef,
+		CertChain:                    o.CertVerify.CertChain,
+		IgnoreSCT:                    o.CertVerify.IgnoreSCT,
+		SCTRef:                       o.CertVerify.SCT,
+		Sk:                           o.SecurityKey.Use,
+		Slot:                         o.SecurityKey.Slot,
+		Output:                       o.Output,
+		OutputTemplate:               o.OutputTemplate.Template,
+		GitHubSummary:                o.GitHubSummary,
+		RekorURL:                     o.Rekor.URL,
+		Attachment:                   o.Attachment,
+		Annotations:                  annotations,
+		HashAlgorithm:                hashAlgorithm,
+		SignatureRef:                 o.SignatureRef,
+		PayloadRef:                   o.PayloadRef,
+		LocalImage:                   o.LocalImage,
+		Offline:                      o.CommonVerifyOptions.Offline,
+		TSACertChainPaths:            o.CommonVerifyOptions.TSACertChainPaths,
+		IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+		Denylist:                     o.CommonVerifyOptions.Denylist,
+		KeyUsagePolicy:               o.CommonVerifyOptions.KeyUsagePolicy,
+		Witnesses:                    o.CommonVerifyOptions.Witnesses,
+		WarningsAsErrors:             o.WarningsAsErrors,
+		SignReport:                   o.SignReport,
+		SignReportKeyOpts:            signReportKeyOpts,
+		SignReportCertificate:        o.SignReportCertificate,
+		SourceRepositories:           o.SourceRepositories,
+		Batch:                        o.Batch,
+		BatchVerify:                  bo,
+		OfflineBundle:                ob,
+		AllowConverted:               o.AllowConverted,
+		Recursive:                    o.Recursive,
+		LayerPolicy:                  o.LayerPolicy,
+		Quota:                        o.Quota,
+		EnforceExpiry:                o.EnforceExpiry,
+		Encryption:                   o.Encryption,
+		Countersigners:               o.Countersigners,
+		Cache:                        o.Cache,
+		Evaluation:                   o.Evaluation,
+	}
+
+	if o.Registry.AllowInsecure {
+		v.NameOptions = append(v.NameOptions, name.Insecure)
+	}
+	return v, nil
+}
+
+// setImagePolicy routes the images verified by v with the --image-policy of
+// o, if any.
+func setImagePolicy(v *verify.VerifyCommand, o *options.VerifyOptions) error {
+	if o.EnvironmentPolicy != "" {
+		return errors.New("--environment-policy is only supported by cosign verify")
+	}
+	if o.ImagePolicy == "" {
+		return nil
+	}
+	if o.Key != "" || o.SecurityKey.Use || o.CertVerify.Cert != "" || o.CertVerify.CertIdentity != "" || o.CertVerify.CertIdentityRegexp != "" {
+		return errors.New("--image-policy can't be used with --key, --sk, --certificate or --certificate-identity")
+	}
+	p, err := proxy.ReadPolicy(o.ImagePolicy)
+	if err != nil {
+		return err
+	}
+	v.Route = routeImagePolicy(p, v)
+	return nil
+}
+
+// verifyEnvironments writes the matrix of the environments of
+// --environment-policy that each image qualifies for, and fails if one
+// qualifies for none.
+func verifyEnvironments(ctx context.Context, out io.Writer, v *verify.VerifyCommand, o *options.VerifyOptions, images []string) error {
+	if o.ImagePolicy != "" || o.Key != "" || o.SecurityKey.Use || o.CertVerify.Cert != "" || o.CertVerify.CertIdentity != "" || o.CertVerify.CertIdentityRegexp != "" {
+		return errors.New("--environment-policy can't be used with --image-policy, --key, --sk, --certificate or --certificate-identity")
+	}
+	if o.Output == verify.OutputJSONv1 || o.Output == verify.OutputSARIF || o.OutputTemplate.Template != "" {
+		return errors.New("--environment-policy writes its matrix as JSON or text, so it can't be used with --output json-v1, sarif or --output-template")
+	}
+	p, err := environments.ReadPolicy(o.EnvironmentPolicy)
+	if err != nil {
+		return err
+	}
+	m, err := environments.Evaluate(ctx, p, v, images)
+	if err != nil {
+		return err
+	}
+	if err := m.Write(out, o.Output); err != nil {
+		return err
+	}
+	for _, img := range m.Images {
+		if len(img.Qualified()) == 0 {
+			return fmt.Errorf("%s qualifies for no environment", img.Image)
+		}
+	}
+	return nil
+}
+
+// routeImagePolicy returns a VerifyCommand.Route that verifies each image as v
+// does, with the key or certificate identity of the entry of p that matches
+// it.
+func routeImagePolicy(p *proxy.Policy, v *verify.VerifyCommand) func(context.Context, name.Reference) (*verify.VerifyCommand, error) {
+	return func(ctx context.Context, ref name.Reference) (*verify.VerifyCommand, error) {
+		e, err := p.Match(ctx, ref, v.GetRegistryClientOpts(ctx)...)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			return nil, fmt.Errorf("no entry of the image policy matches %s", ref)
+		}
+		routed := *v
+		e.Apply(&routed)
+		return &routed, nil
+	}
+}
+
 func VerifyAttestation() *cobra.Command {
 	o := &options.VerifyAttestationOptions{}
+	ob := &options.OfflineBundleOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "verify-attestation",
 		Short: "Verify an attestation on the supplied container image",
 		Long: `Verify an attestation on an image by checking the claims
-against the transparency log.`,
+against the transparency log.
+
+With --base-image-policy, the base image named by a verified baseimage
+attestation, made with cosign attest --type baseimage and a predicate such as
+{"image": "<IMAGE@DIGEST>"}, is verified too, and so on down the chain. Each base image is verified with
+the first entry of the policy whose glob matches its repository, e.g.
+
+  {"images": [
+    {"glob": "registry.example.com/base/*", "key": "base.pub", "policies": ["base.cue"]},
+    {"glob": "cgr.dev/chainguard/*", "certificateIdentityRegexp": "^https://github.com/chainguard-images/",
+     "certificateOidcIssuer": "https://token.actions.githubusercontent.com", "signaturesOnly": true}
+  ]}
+
+Entries also accept certificateIdentity, certificateOidcIssuerRegexp,
+predicateType, ignoreTlog and ignoreSCT, and the policy accepts maxDepth
+(default 5). With signaturesOnly, the signatures of the base image are
+verified instead of its attestations, which ends the chain.
+
+With --deny-license or --license-allowlist, the packages of the verified SPDX
+and CycloneDX attestations are checked against a license policy: each must be
+usable under a license that isn't denied and, with an allowlist, is in it,
+e.g. with one of the licenses of "MIT OR GPL-3.0-only" or all those of
+"MIT AND BSD-3-Clause". Packages without a license are NOASSERTION, and
+CycloneDX licenses without an SPDX ID are LicenseRef-<name>.`,
 		Example: `  cosign verify-attestation --key <key path>|<key url>|<kms uri> <image uri> [<image uri> ...]
 
   # verify cosign attestations on the image against the transparency log
@@ -168,6 +442,18 @@ against the transparency log.`,
   # verify image with public key
   cosign verify-attestation --key cosign.pub <IMAGE>
 
+  # verify the SLSA provenance and SPDX attestations of an image in one pass
+  cosign verify-attestation --key cosign.pub --type slsaprovenance --type spdxjson <IMAGE>
+
+  # verify the SBOM attestation of an image and that none of its packages is only licensed under the GPL 3.0
+  cosign verify-attestation --key cosign.pub --type spdxjson --deny-license GPL-3.0-only --deny-license GPL-3.0-or-later <IMAGE>
+
+  # verify that every package of the CycloneDX SBOM of an image is licensed under a license of an allowlist
+  cosign verify-attestation --key cosign.pub --type cyclonedx --license-allowlist licenses.txt <IMAGE>
+
+  # verify image attestations and the chain of base images they name
+  cosign verify-attestation --key cosign.pub --type baseimage --base-image-policy base-images.json <IMAGE>
+
   # verify image attestations with an on-disk signed image from 'cosign save'
   cosign verify-attestation --key cosign.pub --local-image <PATH>
 
@@ -190,9 +476,25 @@ against the transparency log.`,
   cosign verify-attestation --key cosign.pub --type <PREDICATE_TYPE> --policy <REGO_POLICY> <IMAGE>
 
   # verify image with public key and validate attestation based on CUE policy
-  cosign verify-attestation --key cosign.pub --type <PREDICATE_TYPE> --policy <CUE_POLICY> <IMAGE>`,
+  cosign verify-attestation --key cosign.pub --type <PREDICATE_TYPE> --policy <CUE_POLICY> <IMAGE>
+
+  # verify image with public key and validate its provenance and vulnerability attestations together
+  # against a policy, e.g. that both are newer than 7 days
+  cosign verify-attestation --key cosign.pub --type slsaprovenance --type vuln --policy <POLICY> --policy-evaluation joint <IMAGE>
+
+  # verify image attestations offline with the bundle of 'cosign bundle export' and a pinned trusted root
+  cosign verify-attestation --key cosign.pub --type slsaprovenance --bundle-file bundle.json --trusted-root trusted_root.json
 
-		Args:             cobra.MinimumNArgs(1),
+  # write the verification results in the versioned JSON schema
+  cosign verify-attestation --key cosign.pub --type slsaprovenance --output json-v1 <IMAGE>
+
+  # print the status of each image, formatted with a Go template of the json-v1 results
+  cosign verify-attestation --key cosign.pub --type slsaprovenance --output-template '{{range .subjects}}{{.name}} {{.status}}{{"\n"}}{{end}}' <IMAGE>
+
+  # verify the attestations of the digests that the pods of a StatefulSet currently run
+  cosign verify-attestation --key cosign.pub --type slsaprovenance k8s-workload://<NAMESPACE>/statefulset/<NAME>`,
+
+		Args:             cobra.ArbitraryArgs,
 		PersistentPreRun: options.BindViper,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			v := &verify.VerifyAttestationCommand{
@@ -212,14 +514,29 @@ against the transparency log.`,
 				Sk:                           o.SecurityKey.Use,
 				Slot:                         o.SecurityKey.Slot,
 				Output:                       o.Output,
+				OutputTemplate:               o.OutputTemplate.Template,
+				GitHubSummary:                o.GitHubSummary,
 				RekorURL:                     o.Rekor.URL,
-				PredicateType:                o.Predicate.Type,
+				PredicateTypes:               o.Predicate.Types,
+				PredicateSchemas:             o.Predicate.Schemas,
+				PredicateSchema:              o.Predicate.Schema,
+				PayloadTypes:                 o.PayloadTypes,
 				Policies:                     o.Policies,
+				PolicyEvaluation:             o.PolicyEvaluation,
 				LocalImage:                   o.LocalImage,
 				NameOptions:                  o.Registry.NameOptions(),
 				Offline:                      o.CommonVerifyOptions.Offline,
-				TSACertChainPath:             o.CommonVerifyOptions.TSACertChainPath,
+				TSACertChainPaths:            o.CommonVerifyOptions.TSACertChainPaths,
 				IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+				Denylist:                     o.CommonVerifyOptions.Denylist,
+				KeyUsagePolicy:               o.CommonVerifyOptions.KeyUsagePolicy,
+				Witnesses:                    o.CommonVerifyOptions.Witnesses,
+				WarningsAsErrors:             o.WarningsAsErrors,
+				SourceRepositories:           o.SourceRepositories,
+				AllowConverted:               o.AllowConverted,
+				BaseImagePolicy:              o.BaseImagePolicy,
+				OfflineBundle:                *ob,
+				LicensePolicy:                o.LicensePolicy,
 			}
 
 			ctx := cmd.Context()
@@ -228,11 +545,14 @@ against the transparency log.`,
 				ui.Warnf(ctx, fmt.Sprintf(ignoreTLogMessage, "attestation"))
 			}
 
-			return v.Exec(ctx, args)
+			return withFailureReport(cmd, args, o.CommonVerifyOptions.FailureReport, func() error {
+				return v.Exec(ctx, args)
+			})
 		},
 	}
 
 	o.AddFlags(cmd)
+	ob.AddFlags(cmd)
 	return cmd
 }
 
@@ -247,7 +567,24 @@ You may specify either a key, a certificate or a kms reference to verify against
 	If you use a key or a certificate, you must specify the path to them on disk.
 
 The signature may be specified as a path to a file or a base64 encoded string.
-The blob may be specified as a path to a file or - for stdin.`,
+The blob may be specified as a path to a file or - for stdin.
+
+One of the blob, --bundle, --certificate and --signature may be - to read it
+from stdin, so that verification can be part of a pipeline without temporary
+files. A bundle, certificate or signature read from stdin may be up to 10 MiB.
+
+With --auto-discover, the signature, certificate and bundle that aren't given
+are those published next to the blob, <blob>.sig, <blob>.pem and
+<blob>.bundle. If there are none, the blob is verified against the first
+checksum file in its directory that lists it, e.g. SHA256SUMS or
+checksums.txt, with the sidecar files of the checksum file.
+
+The Rekor v2 entry of a bundle is verified with its inclusion proof. If the
+proof doesn't verify, or its checkpoint isn't cosigned by --witness-keys, the
+entry is verified against the latest checkpoint of the log, with an inclusion
+proof computed from the tiles of the log (https://c2sp.org/tlog-tiles). The
+tiles are read from the URL of the log in the trusted root, or from --rekor-url
+if it has the tiles+ scheme prefix, e.g. tiles+https://log.example.com.`,
 		Example: ` cosign verify-blob (--key <key path>|<key url>|<kms uri>)|(--certificate <cert>) --signature <sig> <blob>
 
   # Verify a simple blob and message
@@ -282,6 +619,25 @@ The blob may be specified as a path to a file or - for stdin.`,
 
   # Verify a signature against a certificate
   cosign verify-blob --certificate <cert> --signature $sig <blob>
+
+  # Verify a blob with a bundle read from stdin
+  curl -sL https://example.com/<BUNDLE> | cosign verify-blob --bundle - --certificate-identity <identity> --certificate-oidc-issuer <issuer> <blob>
+
+  # Write the verification results in the versioned JSON schema
+  cosign verify-blob --key cosign.pub --signature $sig --output json-v1 <blob>
+
+  # Print the digest of a verified blob with a Go template of the json-v1 results
+  cosign verify-blob --key cosign.pub --signature $sig --output-template '{{range .subjects}}{{.digest}}{{"\n"}}{{end}}' <blob>
+
+  # Verify an executable or archive with the bundle embedded in it by cosign embed
+  cosign verify-blob --embedded --certificate-identity <identity> --certificate-oidc-issuer <issuer> <file>
+
+  # Verify a download with the app.tar.gz.sig and app.tar.gz.pem, or app.tar.gz.bundle, next to it,
+  # or else a signed checksum file in its directory, e.g. SHA256SUMS and SHA256SUMS.sig
+  cosign verify-blob --auto-discover --certificate-identity <identity> --certificate-oidc-issuer <issuer> app.tar.gz
+
+  # Verify a signature over the BLAKE3 digest of a blob, signed with --digest-algorithm blake3
+  cosign verify-blob --key cosign.pub --digest-algorithm blake3 --insecure-ignore-tlog --signature $sig <blob>
 `,
 
 		Args:             cobra.ExactArgs(1),
@@ -294,7 +650,7 @@ The blob may be specified as a path to a file or - for stdin.`,
 				RekorURL:             o.Rekor.URL,
 				BundlePath:           o.BundlePath,
 				RFC3161TimestampPath: o.RFC3161TimestampPath,
-				TSACertChainPath:     o.CommonVerifyOptions.TSACertChainPath,
+				TSACertChainPaths:    o.CommonVerifyOptions.TSACertChainPaths,
 			}
 			verifyBlobCmd := &verify.VerifyBlobCmd{
 				KeyOpts:                      ko,
@@ -311,6 +667,15 @@ The blob may be specified as a path to a file or - for stdin.`,
 				SCTRef:                       o.CertVerify.SCT,
 				Offline:                      o.CommonVerifyOptions.Offline,
 				IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+				Denylist:                     o.CommonVerifyOptions.Denylist,
+				KeyUsagePolicy:               o.CommonVerifyOptions.KeyUsagePolicy,
+				Witnesses:                    o.CommonVerifyOptions.Witnesses,
+				Output:                       o.Output,
+				OutputTemplate:               o.OutputTemplate.Template,
+				GitHubSummary:                o.GitHubSummary,
+				Embedded:                     o.Embedded,
+				AutoDiscover:                 o.AutoDiscover,
+				DigestAlgorithm:              o.Digest.Algorithm,
 			}
 
 			ctx := cmd.Context()
@@ -319,7 +684,86 @@ The blob may be specified as a path to a file or - for stdin.`,
 				ui.Warnf(ctx, fmt.Sprintf(ignoreTLogMessage, "blob"))
 			}
 
-			return verifyBlobCmd.Exec(ctx, args[0])
+			return withFailureReport(cmd, args, o.CommonVerifyOptions.FailureReport, func() error {
+				return verifyBlobCmd.Exec(ctx, args[0])
+			})
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func VerifyChecksums() *cobra.Command {
+	o := &options.VerifyChecksumsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "verify-checksums CHECKSUMS",
+		Short: "Verify a signed checksum file and the artifacts against it",
+		Long: `Verify the signature of a checksum file, e.g. the SHA256SUMS of a release, as
+cosign verify-blob would, and then the checksums of the --artifact files
+against it, as sha256sum --check would.
+
+The checksum file may be a path, a URL or - for stdin, and is read once, so
+that the checksums the artifacts are verified against are those signed. Its
+lines are those of sha256sum, sha384sum and sha512sum, "<checksum>  <name>",
+or of the BSD tools and --tag, "SHA256 (<name>) = <checksum>". An artifact is
+verified against the checksum of its path, or else of the only entry with its
+file name.
+
+The command fails if the signature doesn't verify, or if an artifact isn't in
+the checksum file or doesn't match its checksum.`,
+		Example: `  cosign verify-checksums --key cosign.pub --signature SHA256SUMS.sig --artifact app-linux-amd64.tar.gz SHA256SUMS
+
+  # verify the artifacts of a release signed keyless in GitHub Actions
+  cosign verify-checksums --bundle SHA256SUMS.bundle \
+    --certificate-identity-regexp ^https://github.com/example/app/ --certificate-oidc-issuer https://token.actions.githubusercontent.com \
+    --artifact dist/app-linux-amd64.tar.gz --artifact dist/app-darwin-arm64.tar.gz https://example.com/releases/v1.0.0/SHA256SUMS`,
+
+		Args:             cobra.ExactArgs(1),
+		PersistentPreRun: options.BindViper,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ko := options.KeyOpts{
+				KeyRef:               o.Key,
+				Sk:                   o.SecurityKey.Use,
+				Slot:                 o.SecurityKey.Slot,
+				RekorURL:             o.Rekor.URL,
+				BundlePath:           o.BundlePath,
+				RFC3161TimestampPath: o.RFC3161TimestampPath,
+				TSACertChainPaths:    o.CommonVerifyOptions.TSACertChainPaths,
+			}
+			v := &verify.VerifyChecksumsCmd{
+				VerifyBlobCmd: verify.VerifyBlobCmd{
+					KeyOpts:                      ko,
+					CertVerifyOptions:            o.CertVerify,
+					CertRef:                      o.CertVerify.Cert,
+					CertChain:                    o.CertVerify.CertChain,
+					SigRef:                       o.Signature,
+					CertGithubWorkflowTrigger:    o.CertVerify.CertGithubWorkflowTrigger,
+					CertGithubWorkflowSHA:        o.CertVerify.CertGithubWorkflowSha,
+					CertGithubWorkflowName:       o.CertVerify.CertGithubWorkflowName,
+					CertGithubWorkflowRepository: o.CertVerify.CertGithubWorkflowRepository,
+					CertGithubWorkflowRef:        o.CertVerify.CertGithubWorkflowRef,
+					IgnoreSCT:                    o.CertVerify.IgnoreSCT,
+					SCTRef:                       o.CertVerify.SCT,
+					Offline:                      o.CommonVerifyOptions.Offline,
+					IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+					Denylist:                     o.CommonVerifyOptions.Denylist,
+					KeyUsagePolicy:               o.CommonVerifyOptions.KeyUsagePolicy,
+					Witnesses:                    o.CommonVerifyOptions.Witnesses,
+				},
+				Artifacts: o.Artifacts,
+			}
+
+			ctx := cmd.Context()
+
+			if o.CommonVerifyOptions.IgnoreTlog {
+				ui.Warnf(ctx, fmt.Sprintf(ignoreTLogMessage, "checksum file"))
+			}
+
+			return withFailureReport(cmd, args, o.CommonVerifyOptions.FailureReport, func() error {
+				return v.Exec(ctx, args[0])
+			})
 		},
 	}
 
@@ -337,12 +781,31 @@ func VerifyBlobAttestation() *cobra.Command {
 You may specify either a key or a kms reference to verify against.
 
 The signature may be specified as a path to a file or a base64 encoded string.
-The blob may be specified as a path to a file.`,
+The blob may be specified as a path to a file or - for stdin.
+
+One of the blob, --bundle, --certificate and --signature may be - to read it
+from stdin, so that verification can be part of a pipeline without temporary
+files. A bundle, certificate or signature read from stdin may be up to 10 MiB.
+
+With --low-memory the attestation is verified with a bounded amount of memory,
+for embedded and edge devices: the DSSE payload is hashed and its statement
+parsed as they are read from the --signature file, which is never held in
+memory, and the Go runtime is given a soft memory limit unless GOMEMLIMIT sets
+one. It needs an ECDSA or RSA public key, as Ed25519 signs the whole envelope,
+and --insecure-ignore-tlog.`,
 		Example: ` cosign verify-blob-attestation (--key <key path>|<key url>|<kms uri>) --signature <sig> [path to BLOB]
 
   # Verify a simple blob attestation with a DSSE style signature
   cosign verify-blob-attestation --key cosign.pub (--signature <sig path>|<sig url>)[path to BLOB]
 
+  # Verify a blob attestation read from stdin
+  cat <attestation bundle> | cosign verify-blob-attestation --key cosign.pub --bundle - [path to BLOB]
+
+  # Verify a blob attestation with a bounded amount of memory
+  cosign verify-blob-attestation --low-memory --key cosign.pub --insecure-ignore-tlog --signature <sig path> [path to BLOB]
+
+  # Verify an attestation whose subject is named by its SHA3-256 digest, attested with --digest-algorithm sha3-256
+  cosign verify-blob-attestation --key cosign.pub --digest-algorithm sha3-256 --signature <sig path> [path to BLOB]
 `,
 
 		Args:             cobra.MaximumNArgs(1),
@@ -355,11 +818,13 @@ The blob may be specified as a path to a file.`,
 				RekorURL:             o.Rekor.URL,
 				BundlePath:           o.BundlePath,
 				RFC3161TimestampPath: o.RFC3161TimestampPath,
-				TSACertChainPath:     o.CommonVerifyOptions.TSACertChainPath,
+				TSACertChainPaths:    o.CommonVerifyOptions.TSACertChainPaths,
 			}
 			v := verify.VerifyBlobAttestationCommand{
 				KeyOpts:                      ko,
 				PredicateType:                o.PredicateOptions.Type,
+				PredicateSchemas:             o.PredicateOptions.Schemas,
+				PredicateSchema:              o.PredicateOptions.Schema,
 				CheckClaims:                  o.CheckClaims,
 				SignaturePath:                o.SignaturePath,
 				CertVerifyOptions:            o.CertVerify,
@@ -374,6 +839,12 @@ The blob may be specified as a path to a file.`,
 				SCTRef:                       o.CertVerify.SCT,
 				Offline:                      o.CommonVerifyOptions.Offline,
 				IgnoreTlog:                   o.CommonVerifyOptions.IgnoreTlog,
+				Denylist:                     o.CommonVerifyOptions.Denylist,
+				KeyUsagePolicy:               o.CommonVerifyOptions.KeyUsagePolicy,
+				Witnesses:                    o.CommonVerifyOptions.Witnesses,
+				LowMemory:                    o.LowMemory,
+				DigestAlgorithm:              o.Digest.Algorithm,
+				PayloadTypes:                 o.PayloadTypes,
 			}
 			// We only use the blob if we are checking claims.
 			if len(args) == 0 && o.CheckClaims {
@@ -390,10 +861,32 @@ The blob may be specified as a path to a file.`,
 				ui.Warnf(ctx, fmt.Sprintf(ignoreTLogMessage, "blob attestation"))
 			}
 
-			return v.Exec(ctx, path)
+			return withFailureReport(cmd, args, o.CommonVerifyOptions.FailureReport, func() error {
+				return v.Exec(ctx, path)
+			})
 		},
 	}
 
 	o.AddFlags(cmd)
 	return cmd
 }
+
+// withFailureReport runs the verification of cmd, and writes the evidence it
+// fetched and its inputs to the directory dir if it fails and dir is set.
+func withFailureReport(cmd *cobra.Command, args []string, dir string, verify func() error) error {
+	if dir == "" {
+		return verify()
+	}
+	forensics.Enable()
+	defer forensics.Reset()
+	err := verify()
+	if err == nil {
+		return nil
+	}
+	if werr := forensics.Write(dir, cmd.CommandPath(), args, cmd.Flags(), err); werr != nil {
+		ui.Warnf(cmd.Context(), "writing the failure report to %s: %v", dir, werr)
+	} else {
+		ui.Infof(cmd.Context(), "Wrote the evidence of the failed verification to %s", dir)
+	}
+	return err
+}
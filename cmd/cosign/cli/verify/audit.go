@@ -0,0 +1,175 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/kubernetes"
+)
+
+// ClusterAuditReport is the compliance report of cosign audit cluster: the
+// results of verifying each of the images running in a cluster.
+type ClusterAuditReport struct {
+	CosignVersion string    `json:"cosignVersion"`
+	AuditedAt     time.Time `json:"auditedAt"`
+	// Namespaces are the audited namespaces, or empty for all of them.
+	Namespaces []string            `json:"namespaces,omitempty"`
+	Images     int                 `json:"images"`
+	Compliant  int                 `json:"compliant"`
+	Results    []ClusterAuditImage `json:"results"`
+}
+
+// ClusterAuditImage is the result of verifying an image running in the
+// pods of a cluster.
+type ClusterAuditImage struct {
+	VerificationResultSubject
+	Pods []string `json:"pods"`
+}
+
+// Audit verifies each of images, up to workers at a time, as c does, and
+// returns the report of their results. Nothing is printed for each image:
+// its verification, or the routing of c.Route, failing makes it
+// non-compliant.
+func (c *VerifyCommand) Audit(ctx context.Context, images []kubernetes.RunningImage, namespaces []string, workers int) *ClusterAuditReport {
+	report := &ClusterAuditReport{
+		CosignVersion: cosignVersion(),
+		AuditedAt:     time.Now().UTC(),
+		Namespaces:    namespaces,
+		Images:        len(images),
+		Results:       make([]ClusterAuditImage, len(images)),
+	}
+	quiet := ui.WithEnv(ctx, &ui.Env{Stderr: io.Discard, Stdin: os.Stdin})
+	g := errgroup.Group{}
+	g.SetLimit(budget.Workers(workers, runtime.GOMAXPROCS(0)))
+	for i, img := range images {
+		i, img := i, img
+		g.Go(func() error {
+			report.Results[i] = ClusterAuditImage{VerificationResultSubject: c.auditImage(quiet, img.Image, img.Digest), Pods: img.Pods}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	for _, r := range report.Results {
+		if r.Status == VerificationPassed {
+			report.Compliant++
+		}
+	}
+	return report
+}
+
+// auditImage returns the result of verifying image, whose digest is digest,
+// or unknown if empty.
+func (c *VerifyCommand) auditImage(ctx context.Context, image, digest string) VerificationResultSubject {
+	if digest == "" {
+		return VerificationResultSubject{
+			Name:       image,
+			Status:     VerificationFailed,
+			Error:      "the container runtime doesn't report the digest of the image",
+			Signatures: []VerificationResultSignature{},
+		}
+	}
+	results := newVerificationResult("audit cluster")
+	v := *c
+	v.results = results
+	if err := v.Exec(ctx, []string{image}); err != nil {
+		results.fail(image, err)
+	} else if len(results.Subjects) == 0 {
+		results.fail(image, errors.New("no verification result"))
+	}
+	// A failure is recorded after the subjects that passed, e.g. of a
+	// routed verification.
+	s := results.Subjects[len(results.Subjects)-1]
+	s.Digest = digest
+	return s
+}
+
+// Failed returns the number of images of r that aren't compliant.
+func (r *ClusterAuditReport) Failed() int {
+	return r.Images - r.Compliant
+}
+
+// Write writes r to w in the format output, json or markdown.
+func (r *ClusterAuditReport) Write(w io.Writer, output string) error {
+	switch output {
+	case "json":
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling the audit report: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case "markdown":
+		return r.writeMarkdown(w)
+	}
+	return fmt.Errorf("unsupported output format %q, expected json or markdown", output)
+}
+
+func (r *ClusterAuditReport) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	scope := "all namespaces"
+	if len(r.Namespaces) > 0 {
+		scope = "namespaces " + strings.Join(r.Namespaces, ", ")
+	}
+	status := VerificationPassed
+	if r.Failed() > 0 {
+		status = VerificationFailed
+	}
+	fmt.Fprintf(&b, "### cosign audit cluster: %s\n\n", outcomes[status])
+	fmt.Fprintf(&b, "%d of %d images running in %s are signed as the policy requires.\n\n", r.Compliant, r.Images, markdownCell(scope))
+	if len(r.Results) > 0 {
+		b.WriteString("| Image | Pods | Identities | Outcome |\n| --- | --- | --- | --- |\n")
+		for _, res := range r.Results {
+			outcome := outcomes[res.Status]
+			if res.Error != "" {
+				outcome += ": " + markdownCell(res.Error)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", markdownCode(res.Name), podNamespaces(res.Pods), strings.Join(identities(res.VerificationResultSubject), "<br>"), outcome)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "<sub>Audited by cosign %s at %s</sub>\n", r.CosignVersion, r.AuditedAt.Format("2006-01-02 15:04:05 MST"))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// podNamespaces summarizes pods, as <namespace>/<name>, as their number and
+// namespaces.
+func podNamespaces(pods []string) string {
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, p := range pods {
+		ns, _, _ := strings.Cut(p, "/")
+		if !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, markdownCode(ns))
+		}
+	}
+	sort.Strings(namespaces)
+	return fmt.Sprintf("%d in %s", len(pods), strings.Join(namespaces, ", "))
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/cosign/kubernetes"
+)
+
+func TestAudit(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, pub := filepath.Join(td, "cosign.key"), filepath.Join(td, "cosign.pub")
+	if err := os.WriteFile(priv, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pub, keys.PublicBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	push := func(repo string) kubernetes.RunningImage {
+		t.Helper()
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := name.NewDigest(host + "/" + repo + "@" + h.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(ref, img); err != nil {
+			t.Fatal(err)
+		}
+		return kubernetes.RunningImage{Image: ref.String(), Digest: h.String(), Pods: []string{"prod/" + repo}}
+	}
+	app, other := push("app"), push("other")
+	ko := options.KeyOpts{KeyRef: priv, PassFunc: func(bool) ([]byte, error) { return nil, nil }}
+	if err := sign.SignCmd(ctx, &options.RootOptions{Timeout: options.DefaultTimeout}, ko, options.SignOptions{Upload: true}, []string{app.Image}); err != nil {
+		t.Fatal(err)
+	}
+	local := kubernetes.RunningImage{Image: "index.docker.io/local/tool:dev", Pods: []string{"dev/tool-1", "staging/tool-1"}}
+
+	c := &VerifyCommand{CheckClaims: true, IgnoreTlog: true, IgnoreSCT: true}
+	c.Route = func(_ context.Context, ref name.Reference) (*VerifyCommand, error) {
+		if ref.Context().RepositoryStr() != "app" {
+			return nil, fmt.Errorf("no entry of the image policy matches %s", ref)
+		}
+		routed := *c
+		routed.KeyRef = pub
+		return &routed, nil
+	}
+	report := c.Audit(ctx, []kubernetes.RunningImage{app, other, local}, nil, 2)
+
+	if report.Images != 3 || report.Compliant != 1 || report.Failed() != 2 {
+		t.Errorf("report of %d images with %d compliant, want 3 with 1", report.Images, report.Compliant)
+	}
+	for i, want := range []struct{ status, err string }{
+		{status: VerificationPassed},
+		{status: VerificationFailed, err: "no entry of the image policy matches"},
+		{status: VerificationFailed, err: "doesn't report the digest"},
+	} {
+		r := report.Results[i]
+		if r.Status != want.status || !strings.Contains(r.Error, want.err) {
+			t.Errorf("result %d = %s %q, want %s %q", i, r.Status, r.Error, want.status, want.err)
+		}
+	}
+	if r := report.Results[0]; r.Digest != app.Digest || len(r.Signatures) != 1 || len(r.Pods) != 1 {
+		t.Errorf("result of %s = %+v", app.Image, r)
+	}
+
+	var out bytes.Buffer
+	if err := report.Write(&out, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"### cosign audit cluster: ❌ failed\n\n1 of 3 images running in all namespaces are signed as the policy requires.\n",
+		"| `" + app.Image + "` | 1 in `prod` | key | ✅ passed |\n",
+		"| `" + local.Image + "` | 2 in `dev`, `staging` |  | ❌ failed: the container runtime doesn't report the digest of the image |\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("markdown report = %s, want it to contain %q", out.String(), want)
+		}
+	}
+	if err := report.Write(&out, "yaml"); err == nil {
+		t.Error("Write(yaml): expected an error")
+	}
+}
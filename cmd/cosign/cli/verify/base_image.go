@@ -0,0 +1,228 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/policy"
+)
+
+// defaultMaxBaseImageDepth bounds the length of a base image chain when the
+// policy doesn't set maxDepth.
+const defaultMaxBaseImageDepth = 5
+
+// BaseImagePolicy is the --base-image-policy of cosign verify-attestation:
+// how the base images named by baseimage attestations are verified.
+type BaseImagePolicy struct {
+	// MaxDepth is the maximum number of base images in the chain.
+	MaxDepth int `json:"maxDepth,omitempty"`
+	// Images are tried in order, and the first whose glob matches the
+	// repository of a base image verifies it.
+	Images []BaseImagePolicyEntry `json:"images"`
+}
+
+// BaseImagePolicyEntry is the verification policy for the base images in
+// the repositories matching Glob, a path.Match pattern. An empty Glob matches
+// every repository. The transparency log and SCT checks skipped for the
+// image itself are also skipped for its base images.
+type BaseImagePolicyEntry struct {
+	Glob                        string `json:"glob,omitempty"`
+	Key                         string `json:"key,omitempty"`
+	CertificateIdentity         string `json:"certificateIdentity,omitempty"`
+	CertificateIdentityRegexp   string `json:"certificateIdentityRegexp,omitempty"`
+	CertificateOIDCIssuer       string `json:"certificateOidcIssuer,omitempty"`
+	CertificateOIDCIssuerRegexp string `json:"certificateOidcIssuerRegexp,omitempty"`
+	IgnoreTlog                  bool   `json:"ignoreTlog,omitempty"`
+	IgnoreSCT                   bool   `json:"ignoreSCT,omitempty"`
+	// PredicateType and Policies select and validate the attestations of
+	// the base image, as --type and --policy do for the image itself. The
+	// predicate type defaults to the one being verified.
+	PredicateType string   `json:"predicateType,omitempty"`
+	Policies      []string `json:"policies,omitempty"`
+	// SignaturesOnly verifies the signatures of the base image instead of
+	// its attestations, which ends the chain.
+	SignaturesOnly bool `json:"signaturesOnly,omitempty"`
+}
+
+func readBaseImagePolicy(file string) (*BaseImagePolicy, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := &BaseImagePolicy{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing base image policy %s: %w", file, err)
+	}
+	for i, e := range p.Images {
+		if e.Key == "" && e.CertificateIdentity == "" && e.CertificateIdentityRegexp == "" {
+			return nil, fmt.Errorf("base image policy %s: entry %d must set a key or a certificate identity", file, i)
+		}
+		if _, err := path.Match(e.Glob, ""); err != nil {
+			return nil, fmt.Errorf("base image policy %s: entry %d: invalid glob %q: %w", file, i, e.Glob, err)
+		}
+	}
+	if p.MaxDepth == 0 {
+		p.MaxDepth = defaultMaxBaseImageDepth
+	}
+	return p, nil
+}
+
+// match returns the first entry whose glob matches repo.
+func (p *BaseImagePolicy) match(repo string) *BaseImagePolicyEntry {
+	for i, e := range p.Images {
+		if e.Glob == "" {
+			return &p.Images[i]
+		}
+		if ok, _ := path.Match(e.Glob, repo); ok {
+			return &p.Images[i]
+		}
+	}
+	return nil
+}
+
+// baseImageChain is the state shared by the verifications of the images in
+// a base image chain.
+type baseImageChain struct {
+	policy *BaseImagePolicy
+	// images are the verified images, starting with the one given to cosign
+	// verify-attestation.
+	images []string
+}
+
+// baseImage returns the base image named by the baseimage attestations in
+// verified, or "" if there are none.
+func baseImage(ctx context.Context, verified []oci.Signature) (string, error) {
+	base := ""
+	for _, att := range verified {
+		payload, _, err := policy.AttestationToPayloadJSON(ctx, options.PredicateBaseImage, att)
+		if err != nil {
+			return "", err
+		}
+		if payload == nil {
+			continue
+		}
+		st := struct {
+			Predicate attestation.BaseImagePredicate `json:"predicate"`
+		}{}
+		if err := json.Unmarshal(payload, &st); err != nil {
+			return "", fmt.Errorf("parsing base image attestation: %w", err)
+		}
+		if base != "" && base != st.Predicate.Image {
+			return "", fmt.Errorf("conflicting base image attestations for %s and %s", base, st.Predicate.Image)
+		}
+		base = st.Predicate.Image
+	}
+	return base, nil
+}
+
+// verifyBaseImage verifies the base image named by the baseimage
+// attestations in verified, and recursively its own base image, with the
+// policy of chain.
+func (c *VerifyAttestationCommand) verifyBaseImage(ctx context.Context, chain *baseImageChain, verified []oci.Signature) error {
+	base, err := baseImage(ctx, verified)
+	if err != nil || base == "" {
+		return err
+	}
+	ref, err := oci.ParseDigestReference(base, c.NameOptions...)
+	if err != nil {
+		return fmt.Errorf("base image %s must be a reference by digest: %w", base, err)
+	}
+	for _, img := range chain.images {
+		if img == ref.String() {
+			return fmt.Errorf("base image chain has a cycle at %s", ref)
+		}
+	}
+	if len(chain.images) > chain.policy.MaxDepth {
+		return fmt.Errorf("base image chain is longer than %d images", chain.policy.MaxDepth)
+	}
+	e := chain.policy.match(ref.Context().Name())
+	if e == nil {
+		return fmt.Errorf("no base image policy entry matches %s", ref.Context().Name())
+	}
+	chain.images = append(chain.images, ref.String())
+
+	certOpts := options.CertVerifyOptions{
+		CertIdentity:         e.CertificateIdentity,
+		CertIdentityRegexp:   e.CertificateIdentityRegexp,
+		CertOidcIssuer:       e.CertificateOIDCIssuer,
+		CertOidcIssuerRegexp: e.CertificateOIDCIssuerRegexp,
+		StrictIdentity:       c.StrictIdentity,
+		ClockSkew:            c.ClockSkew,
+	}
+	if e.SignaturesOnly {
+		v := &VerifyCommand{
+			RegistryOptions:   c.RegistryOptions,
+			CertVerifyOptions: certOpts,
+			KeyRef:            e.Key,
+			CheckClaims:       true,
+			Output:            c.Output,
+			RekorURL:          c.RekorURL,
+			NameOptions:       c.NameOptions,
+			Offline:           c.Offline,
+			IgnoreTlog:        c.IgnoreTlog || e.IgnoreTlog,
+			IgnoreSCT:         c.IgnoreSCT || e.IgnoreSCT,
+			Denylist:          c.Denylist,
+			KeyUsagePolicy:    c.KeyUsagePolicy,
+			Witnesses:         c.Witnesses,
+			results:           c.results,
+		}
+		if err := v.Exec(ctx, []string{ref.String()}); err != nil {
+			return fmt.Errorf("verifying the signatures of base image %s: %w", ref, err)
+		}
+		return nil
+	}
+
+	predicateType, predicateTypes := e.PredicateType, []string(nil)
+	if predicateType == "" {
+		predicateType, predicateTypes = c.PredicateType, c.PredicateTypes
+	}
+	v := &VerifyAttestationCommand{
+		RegistryOptions:   c.RegistryOptions,
+		CertVerifyOptions: certOpts,
+		KeyRef:            e.Key,
+		CheckClaims:       true,
+		Output:            c.Output,
+		RekorURL:          c.RekorURL,
+		PredicateType:     predicateType,
+		PredicateTypes:    predicateTypes,
+		PayloadTypes:      c.PayloadTypes,
+		Policies:          e.Policies,
+		NameOptions:       c.NameOptions,
+		Offline:           c.Offline,
+		IgnoreTlog:        c.IgnoreTlog || e.IgnoreTlog,
+		IgnoreSCT:         c.IgnoreSCT || e.IgnoreSCT,
+		Denylist:          c.Denylist,
+		KeyUsagePolicy:    c.KeyUsagePolicy,
+		Witnesses:         c.Witnesses,
+		BaseImagePolicy:   c.BaseImagePolicy,
+		baseImageChain:    chain,
+		results:           c.results,
+	}
+	if err := v.Exec(ctx, []string{ref.String()}); err != nil {
+		return fmt.Errorf("verifying the attestations of base image %s: %w", ref, err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestVerifyBaseImageChain(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+
+	newKey := func(n string) (string, string) {
+		t.Helper()
+		keys, err := cosign.GenerateKeyPair(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		priv, pub := filepath.Join(td, n+".key"), filepath.Join(td, n+".pub")
+		if err := os.WriteFile(priv, keys.PrivateBytes, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(pub, keys.PublicBytes, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return priv, pub
+	}
+	push := func(repo string) string {
+		t.Helper()
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := name.NewDigest(host + "/" + repo + "@" + h.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(ref, img); err != nil {
+			t.Fatal(err)
+		}
+		return ref.String()
+	}
+	keyOpts := func(key string) options.KeyOpts {
+		return options.KeyOpts{KeyRef: key, PassFunc: func(bool) ([]byte, error) { return nil, nil }}
+	}
+	attestBase := func(key, image, base string) {
+		t.Helper()
+		predicate := filepath.Join(td, "base.json")
+		b, _ := json.Marshal(map[string]string{"image": base})
+		if err := os.WriteFile(predicate, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		c := &attest.AttestCommand{KeyOpts: keyOpts(key), PredicatePath: predicate, PredicateType: options.PredicateBaseImage}
+		if err := c.Exec(ctx, image); err != nil {
+			t.Fatalf("attesting the base image of %s: %v", image, err)
+		}
+	}
+
+	// app is built on base, which is built on root. root is only signed.
+	appKey, appPub := newKey("app")
+	baseKey, basePub := newKey("base")
+	rootKey, rootPub := newKey("root")
+	app, base, root := push("app"), push("base"), push("root")
+	attestBase(appKey, app, base)
+	attestBase(baseKey, base, root)
+	if err := sign.SignCmd(ctx, &options.RootOptions{Timeout: options.DefaultTimeout}, keyOpts(rootKey), options.SignOptions{Upload: true}, []string{root}); err != nil {
+		t.Fatal(err)
+	}
+
+	writePolicy := func(p BaseImagePolicy) string {
+		t.Helper()
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(td, "policy.json")
+		if err := os.WriteFile(path, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	trusted := []BaseImagePolicyEntry{
+		{Glob: host + "/base", Key: basePub},
+		{Glob: host + "/root", Key: rootPub, SignaturesOnly: true},
+	}
+
+	for _, tt := range []struct {
+		name    string
+		policy  BaseImagePolicy
+		wantErr string
+	}{{
+		name:   "trusted chain",
+		policy: BaseImagePolicy{Images: trusted},
+	}, {
+		name:    "base signed with another key",
+		policy:  BaseImagePolicy{Images: []BaseImagePolicyEntry{{Glob: host + "/base", Key: rootPub}, trusted[1]}},
+		wantErr: "verifying the attestations of base image " + base,
+	}, {
+		name:    "root attestations required",
+		policy:  BaseImagePolicy{Images: []BaseImagePolicyEntry{trusted[0], {Glob: host + "/root", Key: rootPub}}},
+		wantErr: "verifying the attestations of base image " + root,
+	}, {
+		name:    "no policy for root",
+		policy:  BaseImagePolicy{Images: trusted[:1]},
+		wantErr: "no base image policy entry matches " + host + "/root",
+	}, {
+		name:    "chain too long",
+		policy:  BaseImagePolicy{MaxDepth: 1, Images: trusted},
+		wantErr: "longer than 1 images",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &VerifyAttestationCommand{
+				KeyRef:          appPub,
+				CheckClaims:     true,
+				IgnoreTlog:      true,
+				IgnoreSCT:       true,
+				PredicateType:   options.PredicateBaseImage,
+				Output:          "text",
+				BaseImagePolicy: writePolicy(tt.policy),
+			}
+			err := c.Exec(ctx, []string{app})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Exec() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Exec() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// readBatchFile returns the images listed in path, or in stdin if it is -,
+// one per line, skipping blank lines and comments.
+func readBatchFile(path string) ([]string, error) {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return scanBatch(r)
+}
+
+func scanBatch(r io.Reader) ([]string, error) {
+	var images []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		images = append(images, line)
+	}
+	return images, s.Err()
+}
+
+// parallelVerification is the verification of the images of --batch-file
+// with cosign.VerifyImagesParallel, whose results are read in the order of
+// the images so that they are printed and recorded as they are without it.
+type parallelVerification struct {
+	// indexes are the indexes of the verified references by image index.
+	indexes map[int]int
+	results <-chan cosign.VerifyResult
+	done    map[int]cosign.VerifyResult
+	cancel  context.CancelFunc
+}
+
+// verifyParallel starts verifying each of the images for which skip is false,
+// with verify, up to workers at a time.
+func verifyParallel(ctx context.Context, images []string, skip func(...string) bool, workers int, co *cosign.CheckOpts, resolve func(string) (name.Reference, error), verify verifyFunc) (*parallelVerification, error) {
+	p := &parallelVerification{indexes: map[int]int{}, done: map[int]cosign.VerifyResult{}}
+	var refs []name.Reference
+	for i, img := range images {
+		if skip(img) {
+			continue
+		}
+		ref, err := resolve(img)
+		if err != nil {
+			return nil, err
+		}
+		p.indexes[i] = len(refs)
+		refs = append(refs, ref)
+	}
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.results = cosign.VerifyImagesParallel(ctx, refs, co, cosign.BatchOpts{Workers: workers, Verify: verify})
+	return p, nil
+}
+
+// result waits for the result of the image at index i.
+func (p *parallelVerification) result(i int) (cosign.VerifyResult, error) {
+	want, ok := p.indexes[i]
+	if !ok {
+		return cosign.VerifyResult{}, fmt.Errorf("image %d was not verified", i)
+	}
+	for {
+		if r, ok := p.done[want]; ok {
+			delete(p.done, want)
+			return r, nil
+		}
+		r, ok := <-p.results
+		if !ok {
+			return cosign.VerifyResult{}, fmt.Errorf("image %d has no result", i)
+		}
+		p.done[r.Index] = r
+	}
+}
+
+// stop cancels the verification of the images whose results weren't read,
+// and drains them in the background.
+func (p *parallelVerification) stop() {
+	p.cancel()
+	go func() {
+		for range p.results {
+		}
+	}()
+}
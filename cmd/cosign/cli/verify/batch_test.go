@@ -0,0 +1,214 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	cosignError "github.com/sigstore/cosign/v2/cmd/cosign/errors"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestScanBatch(t *testing.T) {
+	got, err := scanBatch(strings.NewReader("# images\nregistry.example.com/a:1\n\n  registry.example.com/b@sha256:abc  \n#registry.example.com/c\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"registry.example.com/a:1", "registry.example.com/b@sha256:abc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scanBatch() = %v, want %v", got, want)
+	}
+}
+
+func TestVerifyParallel(t *testing.T) {
+	images := []string{"registry.example.com/app-a", "registry.example.com/app-b", "registry.example.com/skipped", "registry.example.com/app-c"}
+	skip := func(names ...string) bool { return names[0] == "registry.example.com/skipped" }
+	resolve := func(img string) (name.Reference, error) { return name.ParseReference(img) }
+	verify := func(_ context.Context, ref name.Reference, _ *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+		// Finish the first image last.
+		if ref.Context().RepositoryStr() == "app-a" {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if ref.Context().RepositoryStr() == "app-c" {
+			return nil, false, cosign.ErrNoSignaturesFound
+		}
+		return nil, true, nil
+	}
+
+	p, err := verifyParallel(context.Background(), images, skip, 3, &cosign.CheckOpts{}, resolve, verify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.stop()
+	for _, i := range []int{0, 1, 3} {
+		r, err := p.result(i)
+		if err != nil {
+			t.Fatalf("result(%d) = %v", i, err)
+		}
+		if r.Ref.Context().Name() != images[i] {
+			t.Errorf("result(%d) is for %s, want %s", i, r.Ref, images[i])
+		}
+		if wantErr := i == 3; errors.Is(r.Err, cosign.ErrNoSignaturesFound) != wantErr {
+			t.Errorf("result(%d) = %v, want error %v", i, r.Err, wantErr)
+		}
+	}
+	if _, err := p.result(2); err == nil {
+		t.Error("expected no result for the skipped image")
+	}
+}
+
+func TestVerifyBatchDeadline(t *testing.T) {
+	// Requests for app-b hang until the deadline.
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	release := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/app-b/") {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	t.Cleanup(func() { close(release) })
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := t.TempDir()
+	keyPath := filepath.Join(td, "cosign.pub")
+	if err := os.WriteFile(keyPath, pub, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// app-a is signed.
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app-a@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + ref.Context().String() + `"},"image":{"docker-manifest-digest":"` + h.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	raw, err := sv.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(payload, base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := mutate.AppendSignatures(empty.Signatures(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sigTag, sigs); err != nil {
+		t.Fatal(err)
+	}
+
+	batchFile := filepath.Join(td, "images")
+	if err := os.WriteFile(batchFile, []byte(host+"/app-b@"+h.String()+"\n"+host+"/app-c@"+h.String()+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(td, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = f
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	c := &VerifyCommand{KeyRef: keyPath, IgnoreTlog: true, IgnoreSCT: true, Output: OutputJSONv1}
+	c.BatchVerify.File = batchFile
+	c.BatchVerify.Workers = 1
+	verifyErr := c.Exec(ctx, []string{ref.String()})
+	os.Stdout = stdout
+	f.Close()
+
+	var ce *cosignError.CosignError
+	if !errors.As(verifyErr, &ce) || ce.ExitCode() != cosignError.NotEvaluated {
+		t.Fatalf("Exec() past the deadline = %v, want exit code %d", verifyErr, cosignError.NotEvaluated)
+	}
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := VerificationResult{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatalf("Exec() wrote %q: %v", b, err)
+	}
+	var got []string
+	for _, s := range r.Subjects {
+		got = append(got, s.Status)
+	}
+	want := []string{VerificationPassed, VerificationNotEvaluated, VerificationNotEvaluated}
+	if r.Status != VerificationNotEvaluated || !reflect.DeepEqual(got, want) {
+		t.Errorf("Exec() results have status %s and subjects %v, want %s and %v", r.Status, got, VerificationNotEvaluated, want)
+	}
+}
@@ -0,0 +1,69 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestVerifyBlobDigestAlgorithm(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := writeBlobFile(t, td, string(keys.PrivateBytes), "cosign.key")
+	pubPath := writeBlobFile(t, td, string(keys.PublicBytes), "cosign.pub")
+	blobPath := writeBlobFile(t, td, "release artifact", "blob")
+	ro := &options.RootOptions{Timeout: time.Minute}
+	ko := options.KeyOpts{KeyRef: keyPath, PassFunc: func(bool) ([]byte, error) { return nil, nil }, BlobDigestAlgorithm: "blake3"}
+
+	if _, err := sign.SignBlobCmd(ctx, ro, ko, blobPath, true, "", "", true); err == nil || !strings.Contains(err.Error(), "--tlog-upload=false") {
+		t.Fatalf("SignBlobCmd() with the transparency log = %v, want an error", err)
+	}
+	sig, err := sign.SignBlobCmd(ctx, ro, ko, blobPath, true, "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigPath := writeBlobFile(t, td, string(sig), "blob.sig")
+
+	verify := func(alg string, ignoreTlog bool) error {
+		c := &VerifyBlobCmd{KeyOpts: options.KeyOpts{KeyRef: pubPath}, SigRef: sigPath, IgnoreSCT: true, IgnoreTlog: ignoreTlog, DigestAlgorithm: alg}
+		return c.Exec(ctx, blobPath)
+	}
+	if err := verify("blake3", true); err != nil {
+		t.Errorf("Exec() with blake3 = %v", err)
+	}
+	if err := verify("sha3-256", true); err == nil {
+		t.Error("Exec() with sha3-256: expected an error")
+	}
+	if err := verify("", true); err == nil {
+		t.Error("Exec() with sha256: expected an error")
+	}
+	if err := verify("blake3", false); err == nil || !strings.Contains(err.Error(), "--insecure-ignore-tlog") {
+		t.Errorf("Exec() with blake3 and the transparency log = %v, want an error", err)
+	}
+}
@@ -0,0 +1,77 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/conversion"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// verifyConverted wraps verify for --allow-converted: an image whose layers
+// were converted to a lazy-pulling format, and that has no signatures of its
+// own, is verified with the signatures of the image it was converted from,
+// provided that both have the same configuration and files.
+func verifyConverted(verify verifyFunc) verifyFunc {
+	return func(ctx context.Context, ref name.Reference, co *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+		verified, bundleVerified, err := verify(ctx, ref, co)
+		if err == nil || !(errors.Is(err, cosign.ErrNoSignaturesFound) ||
+			errors.Is(err, cosign.ErrNoMatchingSignatures) ||
+			errors.Is(err, cosign.ErrNoMatchingAttestations)) {
+			return verified, bundleVerified, err
+		}
+
+		img, ierr := ociremote.SignedImage(ref, co.RegistryClientOpts...)
+		if ierr != nil {
+			return nil, false, err
+		}
+		m, ierr := img.Manifest()
+		if ierr != nil {
+			return nil, false, err
+		}
+		h, ok := conversion.Source(m)
+		if !ok {
+			return nil, false, err
+		}
+		format := conversion.Detect(m)
+		if !format.Comparable() {
+			return nil, false, fmt.Errorf("%w: %s has %s layers, which can't be checked against the image they were converted from, so it must be signed itself", err, ref, format)
+		}
+
+		source := ref.Context().Digest(h.String())
+		ui.Infof(ctx, "%s has %s layers converted from %s, verifying the image it was converted from", ref, format, source.DigestStr())
+		verified, bundleVerified, err = verify(ctx, source, co)
+		if err != nil {
+			return nil, false, fmt.Errorf("verifying %s, which %s was converted from: %w", source, ref, err)
+		}
+		sourceImg, err := ociremote.SignedImage(source, co.RegistryClientOpts...)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := conversion.Equivalent(img, sourceImg); err != nil {
+			return nil, false, fmt.Errorf("%s is not a conversion of %s: %w", ref, source.DigestStr(), err)
+		}
+		return verified, bundleVerified, nil
+	}
+}
@@ -0,0 +1,121 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+func convertedTestImage(t *testing.T, content string, level int, annotations map[string]string) v1.Image {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "bin/app", Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	l, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	}, tarball.WithCompressionLevel(level))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := mutate.Append(empty.Image, mutate.Addendum{Layer: l, Annotations: annotations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestVerifyConverted(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer s.Close()
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	push := func(img v1.Image) name.Digest {
+		t.Helper()
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := repo.Digest(h.String())
+		if err := remote.Write(d, img); err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	withSubject := func(img, subject v1.Image) v1.Image {
+		t.Helper()
+		desc, err := partial.Descriptor(subject)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mutate.Subject(img, *desc).(v1.Image)
+	}
+
+	toc := map[string]string{"containerd.io/snapshot/stargz/toc.digest": "sha256:0123"}
+	source := convertedTestImage(t, "hello", gzip.DefaultCompression, nil)
+	sourceRef := push(source)
+	converted := push(withSubject(convertedTestImage(t, "hello", gzip.BestSpeed, toc), source))
+	tampered := push(withSubject(convertedTestImage(t, "evil", gzip.BestSpeed, toc), source))
+	unrelated := push(convertedTestImage(t, "hello", gzip.BestSpeed, toc))
+
+	verify := verifyConverted(func(_ context.Context, ref name.Reference, _ *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+		if ref.String() == sourceRef.String() {
+			return nil, true, nil
+		}
+		return nil, false, fmt.Errorf("%s: %w", ref, cosign.ErrNoSignaturesFound)
+	})
+
+	if _, bundleVerified, err := verify(context.Background(), converted, &cosign.CheckOpts{}); err != nil || !bundleVerified {
+		t.Errorf("verifying the converted image: %v", err)
+	}
+	if _, _, err := verify(context.Background(), tampered, &cosign.CheckOpts{}); err == nil || !strings.Contains(err.Error(), "bin/app was changed") {
+		t.Errorf("expected the tampered image to fail verification, got %v", err)
+	}
+	if _, _, err := verify(context.Background(), unrelated, &cosign.CheckOpts{}); !errors.Is(err, cosign.ErrNoSignaturesFound) {
+		t.Errorf("expected the image without a subject to have no signatures, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+)
+
+// countersigner is a signer whose countersignature is required, with the
+// CheckOpts that verify its signatures.
+type countersigner struct {
+	name string
+	co   cosign.CheckOpts
+}
+
+// verifyCountersigners returns an error unless ref has, for each of the
+// countersigners in o, a valid signature by that countersigner over the
+// payload of one of the verified signatures. The verified signatures
+// themselves don't count as countersignatures.
+func verifyCountersigners(ctx context.Context, ref name.Reference, co *cosign.CheckOpts, o options.CountersignerOptions,
+	hashAlgorithm crypto.Hash, verified []oci.Signature, verify verifyFunc) error {
+	payloads := map[string]struct{}{}
+	signatures := map[string]struct{}{}
+	for _, sig := range verified {
+		p, err := sig.Payload()
+		if err != nil {
+			return err
+		}
+		payloads[string(p)] = struct{}{}
+		b64sig, err := sig.Base64Signature()
+		if err != nil {
+			return err
+		}
+		signatures[b64sig] = struct{}{}
+	}
+
+	var countersigners []countersigner
+	for _, key := range o.Keys {
+		v, err := sigs.PublicKeyFromKeyRefWithHashAlgo(ctx, key, hashAlgorithm)
+		if err != nil {
+			return fmt.Errorf("loading countersigner key %s: %w", key, err)
+		}
+		c := countersigner{name: key, co: *co}
+		c.co.SigVerifier = v
+		c.co.Identities = nil
+		countersigners = append(countersigners, c)
+	}
+	for _, id := range o.Identities {
+		c := countersigner{name: id, co: *co}
+		c.co.SigVerifier = nil
+		c.co.Identities = []cosign.Identity{{Subject: id, Issuer: o.OIDCIssuer}}
+		if c.co.RootCerts == nil {
+			var err error
+			if c.co.RootCerts, err = fulcio.GetRoots(); err != nil {
+				return fmt.Errorf("getting Fulcio roots: %w", err)
+			}
+			if c.co.IntermediateCerts, err = fulcio.GetIntermediates(); err != nil {
+				return fmt.Errorf("getting Fulcio intermediates: %w", err)
+			}
+		}
+		countersigners = append(countersigners, c)
+	}
+
+	for _, c := range countersigners {
+		c := c
+		countersigned, _, err := verify(ctx, ref, &c.co)
+		if err != nil {
+			return fmt.Errorf("verifying countersignature by %s: %w", c.name, err)
+		}
+		found := false
+		for _, sig := range countersigned {
+			p, err := sig.Payload()
+			if err != nil {
+				return err
+			}
+			b64sig, err := sig.Base64Signature()
+			if err != nil {
+				return err
+			}
+			_, samePayload := payloads[string(p)]
+			_, original := signatures[b64sig]
+			if samePayload && !original {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no countersignature by %s of the verified signatures", c.name)
+		}
+		ui.Infof(ctx, "Verified countersignature by %s", c.name)
+	}
+	return nil
+}
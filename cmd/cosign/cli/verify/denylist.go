@@ -0,0 +1,89 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+)
+
+// loadDenylist loads the denylist configured by o, falling back to
+// $COSIGN_DENYLIST and $COSIGN_DENYLIST_KEY. It returns nil if no denylist
+// is configured.
+//
+// A denylist is either a file with a detached signature, or an OCI artifact
+// signed with cosign, and must be signed by the denylist key. It can also be
+// a tuf://<target> in the TUF repository configured with cosign initialize.
+func loadDenylist(ctx context.Context, o options.DenylistOptions, regOpts []ociremote.Option, nameOpts []name.Option) (*cosign.Denylist, error) {
+	path, key := o.Path, o.Key
+	if path == "" {
+		path = env.Getenv(env.VariableDenylist)
+	}
+	if key == "" {
+		key = env.Getenv(env.VariableDenylistKey)
+	}
+	if path == "" {
+		return nil, nil
+	}
+	if target, ok := cosign.TUFTargetName(path); ok {
+		return loadTUFDenylist(ctx, target, key, o.Signature, cosign.GetTUFTarget)
+	}
+	if key == "" {
+		return nil, errors.New("a denylist key is required to verify the denylist, set --denylist-key or $COSIGN_DENYLIST_KEY")
+	}
+	verifier, err := sigs.PublicKeyFromKeyRef(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("loading denylist key: %w", err)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sigPath := o.Signature
+		if sigPath == "" {
+			sigPath = path + ".sig"
+		}
+		b64sig, err := os.ReadFile(sigPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading denylist signature: %w", err)
+		}
+		return cosign.ParseDenylist(raw, b64sig, verifier)
+	}
+
+	ref, err := oci.ParseReference(path, nameOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("denylist %s is neither a file nor an image reference: %w", path, err)
+	}
+	// The denylist is trusted because of the key that signed it, so its
+	// signatures don't need to be in the transparency log.
+	return cosign.FetchDenylist(ctx, ref, &cosign.CheckOpts{
+		RegistryClientOpts: regOpts,
+		SigVerifier:        verifier,
+		ClaimVerifier:      cosign.SimpleClaimVerifier,
+		IgnoreTlog:         true,
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestLoadDenylist(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := filepath.Join(dir, "denylist.pub")
+	if err := os.WriteFile(key, pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	raw := []byte(`{"digests":["sha256:abc"]}`)
+	sig, err := sv.SignMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "denylist.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".sig", []byte(base64.StdEncoding.EncodeToString(sig)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if d, err := loadDenylist(ctx, options.DenylistOptions{}, nil, nil); err != nil || d != nil {
+		t.Errorf("loadDenylist() without a denylist = %v, %v", d, err)
+	}
+	if _, err := loadDenylist(ctx, options.DenylistOptions{Path: path}, nil, nil); err == nil {
+		t.Error("expected an error without a denylist key")
+	}
+	d, err := loadDenylist(ctx, options.DenylistOptions{Path: path, Key: key}, nil, nil)
+	if err != nil {
+		t.Fatalf("loadDenylist() = %v", err)
+	}
+	if len(d.Digests) != 1 {
+		t.Errorf("unexpected denylist: %+v", d)
+	}
+
+	// The environment supplies the denylist when no flags are set.
+	t.Setenv("COSIGN_DENYLIST", path)
+	t.Setenv("COSIGN_DENYLIST_KEY", key)
+	if d, err := loadDenylist(ctx, options.DenylistOptions{}, nil, nil); err != nil || d == nil {
+		t.Errorf("loadDenylist() from the environment = %v, %v", d, err)
+	}
+	if _, err := loadDenylist(ctx, options.DenylistOptions{Signature: filepath.Join(dir, "missing.sig")}, nil, nil); err == nil {
+		t.Error("expected an error for a missing signature")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// outcomes are the outcomes of the statuses of the results in job summaries.
+var outcomes = map[string]string{
+	VerificationPassed:       "✅ passed",
+	VerificationFailed:       "❌ failed",
+	VerificationNotEvaluated: "⏸️ not evaluated",
+}
+
+// appendGitHubSummary appends the results, as a Markdown table of the
+// subjects, the identities of their signatures and their outcomes, to the
+// GitHub Actions job summary file path, which the steps of a job append to.
+func (r *VerificationResult) appendGitHubSummary(path string) error {
+	f, err := os.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening the job summary: %w", err)
+	}
+	if err := r.writeMarkdown(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing the job summary: %w", err)
+	}
+	return f.Close()
+}
+
+func (r *VerificationResult) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### cosign %s: %s\n\n", r.Command, outcomes[r.Status])
+	if r.Error != "" {
+		fmt.Fprintf(&b, "%s\n\n", markdownCell(r.Error))
+	}
+	if len(r.Subjects) > 0 {
+		b.WriteString("| Subject | Digest | Identities | Outcome |\n| --- | --- | --- | --- |\n")
+		for _, s := range r.Subjects {
+			outcome := outcomes[s.Status]
+			if s.Error != "" {
+				outcome += ": " + markdownCell(s.Error)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", markdownCode(s.Name), markdownCode(s.Digest), strings.Join(identities(s), "<br>"), outcome)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "<sub>Verified by cosign %s at %s</sub>\n\n", r.CosignVersion, r.VerifiedAt.Format("2006-01-02 15:04:05 MST"))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// identities returns the distinct identities of the signatures of s, in
+// order, as "subject (issuer)", or "key" for signatures without certificates.
+func identities(s VerificationResultSubject) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, sig := range s.Signatures {
+		id := "key"
+		if sig.Identity != nil {
+			id = markdownCode(sig.Identity.Subject)
+			if sig.Identity.Issuer != "" {
+				id += " (" + markdownCode(sig.Identity.Issuer) + ")"
+			}
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// markdownCell escapes s to be the text of a cell of a Markdown table.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// markdownCode returns s as inline code in a cell of a Markdown table, or ""
+// if it's empty.
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(markdownCell(s), "`", "'") + "`"
+}
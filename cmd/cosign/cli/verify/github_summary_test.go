@@ -0,0 +1,79 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestGitHubSummary(t *testing.T) {
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	if _, err := newCommandResult("verify", "json", "", true); err == nil {
+		t.Error("newCommandResult() outside GitHub Actions: expected an error")
+	}
+	if r, err := newCommandResult("verify", "json", "", false); r != nil || err != nil {
+		t.Errorf("newCommandResult() without structured output = %v, %v, want none", r, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.md")
+	if err := os.WriteFile(path, []byte("## Build\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+	r, err := newCommandResult("verify", "json", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.printsPayloads() {
+		t.Error("printsPayloads() of the results of the job summary only = false")
+	}
+
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"example.com/app"},"image":{"docker-manifest-digest":"sha256:abcd"},"type":"cosign container image signature"},"optional":null}`)
+	sig, err := static.NewSignature(payload, "c2lnbmF0dXJl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.pass("example.com/app:latest", "", []oci.Signature{sig, sig}, nil, nil)
+	var out bytes.Buffer
+	verifyErr := errors.New("no signatures | matched")
+	if err := r.finish(&out, "json", "example.com/other:latest", verifyErr); err != verifyErr {
+		t.Fatalf("finish() = %v, want the verification error", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("finish() wrote %q, want only the job summary", out.String())
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"## Build\n\n### cosign verify: ❌ failed\n",
+		"| `example.com/app:latest` | `sha256:abcd` | key | ✅ passed |\n",
+		"| `example.com/other:latest` |  |  | ❌ failed: no signatures \\| matched |\n",
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("job summary = %s, want it to contain %q", b, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/digitorus/timestamp"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/cue"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rego"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// jointPolicyInput is the document the policies are evaluated against with
+// --policy-evaluation joint: the verified attestations of all the predicate
+// types of an image, also grouped by the --type they matched.
+type jointPolicyInput struct {
+	// Now is the time of the evaluation, for policies on the freshness of
+	// the attestations.
+	Now            time.Time                           `json:"now"`
+	Count          int                                 `json:"count"`
+	Attestations   []jointPolicyAttestation            `json:"attestations"`
+	PredicateTypes map[string][]jointPolicyAttestation `json:"predicateTypes"`
+}
+
+// jointPolicyAttestation is a verified attestation of a jointPolicyInput.
+type jointPolicyAttestation struct {
+	PredicateType string `json:"predicateType"`
+	// SignedAt is the time of the RFC3161 timestamp of the attestation, or
+	// else of its transparency log entry, if it has either.
+	SignedAt  *time.Time      `json:"signedAt,omitempty"`
+	Statement json.RawMessage `json:"statement"`
+}
+
+// jointPolicyEvaluation reports whether the policies of c are evaluated
+// against all the verified attestations jointly.
+func (c *VerifyAttestationCommand) jointPolicyEvaluation() (bool, error) {
+	switch c.PolicyEvaluation {
+	case "", options.PolicyEvaluationEach:
+		return false, nil
+	case options.PolicyEvaluationJoint:
+		if len(c.Policies) == 0 {
+			return false, errors.New("--policy-evaluation joint requires --policy")
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid --policy-evaluation %q, expected %s or %s", c.PolicyEvaluation, options.PolicyEvaluationEach, options.PolicyEvaluationJoint)
+	}
+}
+
+func newJointPolicyInput(now time.Time, results []*predicateTypeResult) ([]byte, error) {
+	in := jointPolicyInput{
+		Now:            now.UTC(),
+		Attestations:   []jointPolicyAttestation{},
+		PredicateTypes: map[string][]jointPolicyAttestation{},
+	}
+	for _, r := range results {
+		atts := []jointPolicyAttestation{}
+		for i, sig := range r.checked {
+			st := struct {
+				PredicateType string `json:"predicateType"`
+			}{}
+			if err := json.Unmarshal(r.payloads[i], &st); err != nil {
+				return nil, fmt.Errorf("parsing the statement of an attestation: %w", err)
+			}
+			atts = append(atts, jointPolicyAttestation{
+				PredicateType: st.PredicateType,
+				SignedAt:      signedAt(sig),
+				Statement:     r.payloads[i],
+			})
+		}
+		in.Attestations = append(in.Attestations, atts...)
+		in.PredicateTypes[r.predicateType] = atts
+	}
+	in.Count = len(in.Attestations)
+	return json.Marshal(in)
+}
+
+// checkJointPolicies evaluates the CUE and Rego policies against the
+// jointPolicyInput of the verified attestations of results.
+func checkJointPolicies(ctx context.Context, results []*predicateTypeResult, cuePolicies, regoPolicies []string) error {
+	input, err := newJointPolicyInput(time.Now(), results)
+	if err != nil {
+		return err
+	}
+	var validationErrors []error
+	if len(cuePolicies) > 0 {
+		ui.Infof(ctx, "will be validating the attestations jointly against CUE policies: %v", cuePolicies)
+		if err := cue.ValidateJSON(input, cuePolicies); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+	if len(regoPolicies) > 0 {
+		ui.Infof(ctx, "will be validating the attestations jointly against Rego policies: %v", regoPolicies)
+		validationErrors = append(validationErrors, rego.ValidateJSON(input, regoPolicies)...)
+	}
+	if len(validationErrors) > 0 {
+		ui.Infof(ctx, "There are %d number of errors occurred during the joint validation:\n", len(validationErrors))
+		for _, v := range validationErrors {
+			ui.Infof(ctx, "- %v", v)
+		}
+		return fmt.Errorf("%d validation errors occurred in the joint evaluation of the policies", len(validationErrors))
+	}
+	return nil
+}
+
+// signedAt returns the time of the RFC3161 timestamp of sig, or else of its
+// transparency log entry, or nil if it has neither.
+func signedAt(sig oci.Signature) *time.Time {
+	if ts, err := sig.RFC3161Timestamp(); err == nil && ts != nil {
+		if t, err := timestamp.ParseResponse(ts.SignedRFC3161Timestamp); err == nil {
+			signed := t.Time.UTC()
+			return &signed
+		}
+	}
+	if bundle, err := sig.Bundle(); err == nil && bundle != nil {
+		signed := time.Unix(bundle.Payload.IntegratedTime, 0).UTC()
+		return &signed
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// loadKeyUsagePolicy reads the --key-usage-policy at path, if set. Its
+// predicate types may be given like --type, e.g. slsaprovenance.
+func loadKeyUsagePolicy(path string) (*cosign.KeyUsagePolicy, error) {
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key usage policy: %w", err)
+	}
+	p, err := cosign.ParseKeyUsagePolicy(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, r := range p.Signers {
+		for j, t := range r.PredicateTypes {
+			uri, err := options.ParsePredicateType(t)
+			if err != nil {
+				return nil, fmt.Errorf("%s: signer %d: %w", path, i, err)
+			}
+			p.Signers[i].PredicateTypes[j] = uri
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,54 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+	slsa "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+)
+
+func TestLoadKeyUsagePolicy(t *testing.T) {
+	if p, err := loadKeyUsagePolicy(""); p != nil || err != nil {
+		t.Errorf("loadKeyUsagePolicy(\"\") = %v, %v, want no policy", p, err)
+	}
+
+	write := func(policy string) string {
+		path := filepath.Join(t.TempDir(), "key-usage.json")
+		if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	p, err := loadKeyUsagePolicy(write(`{"signers": [{"key": "sha256:abc", "usages": ["attest"], "predicateTypes": ["slsaprovenance", "https://spdx.dev/Document"]}]}`))
+	if err != nil {
+		t.Fatalf("loadKeyUsagePolicy() = %v", err)
+	}
+	if got := p.Signers[0].PredicateTypes; len(got) != 2 || got[0] != slsa.PredicateSLSAProvenance || got[1] != in_toto.PredicateSPDX {
+		t.Errorf("predicate types = %v, want the URIs of slsaprovenance and spdx", got)
+	}
+
+	if _, err := loadKeyUsagePolicy(write(`{"signers": [{"key": "sha256:abc", "usages": ["attest"], "predicateTypes": ["not a type"]}]}`)); err == nil || !strings.Contains(err.Error(), "signer 0") {
+		t.Errorf("loadKeyUsagePolicy() with an invalid predicate type = %v", err)
+	}
+	if _, err := loadKeyUsagePolicy(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("loadKeyUsagePolicy() of a missing file: expected an error")
+	}
+}
@@ -0,0 +1,138 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// LayerPolicy is the --layer-policy of cosign verify: the layers and config
+// of images that must be signed, by other signers than the image itself,
+// e.g. the base layers by the platform team that built the base image.
+type LayerPolicy struct {
+	Rules []LayerPolicyRule `json:"rules"`
+}
+
+// LayerPolicyRule requires a signature of each of the Layers of an image,
+// selected as with cosign sign --layers, by Key or the certificate identity.
+// The signatures of a layer are looked up in the repository of the image,
+// then in SourceRepositories, such as the repository of the base image whose
+// layers were signed. The transparency log and SCT checks skipped for the
+// image itself are also skipped for its layers.
+type LayerPolicyRule struct {
+	Layers                      []string `json:"layers"`
+	Key                         string   `json:"key,omitempty"`
+	CertificateIdentity         string   `json:"certificateIdentity,omitempty"`
+	CertificateIdentityRegexp   string   `json:"certificateIdentityRegexp,omitempty"`
+	CertificateOIDCIssuer       string   `json:"certificateOidcIssuer,omitempty"`
+	CertificateOIDCIssuerRegexp string   `json:"certificateOidcIssuerRegexp,omitempty"`
+	IgnoreTlog                  bool     `json:"ignoreTlog,omitempty"`
+	IgnoreSCT                   bool     `json:"ignoreSCT,omitempty"`
+	SourceRepositories          []string `json:"sourceRepositories,omitempty"`
+}
+
+func readLayerPolicy(file string) (*LayerPolicy, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := &LayerPolicy{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing layer policy %s: %w", file, err)
+	}
+	if len(p.Rules) == 0 {
+		return nil, fmt.Errorf("layer policy %s has no rules", file)
+	}
+	for i, r := range p.Rules {
+		if len(r.Layers) == 0 {
+			return nil, fmt.Errorf("layer policy %s: rule %d selects no layers", file, i)
+		}
+		if r.Key == "" && r.CertificateIdentity == "" && r.CertificateIdentityRegexp == "" {
+			return nil, fmt.Errorf("layer policy %s: rule %d must set a key or a certificate identity", file, i)
+		}
+	}
+	return p, nil
+}
+
+// verifyLayers verifies the signatures of the layers of the image ref that
+// the rules of p select.
+func (c *VerifyCommand) verifyLayers(ctx context.Context, ref name.Reference, p *LayerPolicy) error {
+	desc, err := remote.Get(ref, c.GetRegistryClientOpts(ctx)...)
+	if err != nil {
+		return fmt.Errorf("accessing image: %w", err)
+	}
+	if desc.MediaType.IsIndex() {
+		return fmt.Errorf("%s is an index, --layer-policy verifies the layers of the image of a platform", ref)
+	}
+	img, err := desc.Image()
+	if err != nil {
+		return fmt.Errorf("accessing image: %w", err)
+	}
+	for i, r := range p.Rules {
+		hashes, err := cosign.SelectBlobs(img, r.Layers)
+		if err != nil {
+			return fmt.Errorf("layer policy rule %d: %w", i, err)
+		}
+		v := &VerifyCommand{
+			RegistryOptions: c.RegistryOptions,
+			CertVerifyOptions: options.CertVerifyOptions{
+				CertIdentity:         r.CertificateIdentity,
+				CertIdentityRegexp:   r.CertificateIdentityRegexp,
+				CertOidcIssuer:       r.CertificateOIDCIssuer,
+				CertOidcIssuerRegexp: r.CertificateOIDCIssuerRegexp,
+				StrictIdentity:       c.StrictIdentity,
+				ClockSkew:            c.ClockSkew,
+			},
+			KeyRef:             r.Key,
+			CheckClaims:        true,
+			RekorURL:           c.RekorURL,
+			NameOptions:        c.NameOptions,
+			Offline:            c.Offline,
+			IgnoreTlog:         c.IgnoreTlog || r.IgnoreTlog,
+			IgnoreSCT:          c.IgnoreSCT || r.IgnoreSCT,
+			Denylist:           c.Denylist,
+			KeyUsagePolicy:     c.KeyUsagePolicy,
+			Witnesses:          c.Witnesses,
+			SourceRepositories: r.SourceRepositories,
+			Cache:              c.Cache,
+			AnyRegistry:        c.AnyRegistry,
+			// Only the verification of the image itself is printed.
+			OnVerified: func(context.Context, name.Reference, []oci.Signature) error {
+				return nil
+			},
+		}
+		for _, h := range hashes {
+			layer := ref.Context().Digest(h.String())
+			if err := v.Exec(ctx, []string{layer.String()}); err != nil {
+				return fmt.Errorf("verifying the signatures of layer %s of %s with layer policy rule %d: %w", h, ref, i, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestVerifyLayerPolicy(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+	t.Setenv(env.VariableFeatureGates.String(), "LayerSignatures=true")
+
+	newKey := func(n string) (string, string) {
+		t.Helper()
+		keys, err := cosign.GenerateKeyPair(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		priv, pub := filepath.Join(td, n+".key"), filepath.Join(td, n+".pub")
+		if err := os.WriteFile(priv, keys.PrivateBytes, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(pub, keys.PublicBytes, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return priv, pub
+	}
+	push := func(repo string, img v1.Image) string {
+		t.Helper()
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := name.NewDigest(host + "/" + repo + "@" + h.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(ref, img); err != nil {
+			t.Fatal(err)
+		}
+		return ref.String()
+	}
+	signWith := func(key string, signOpts options.SignOptions, img string) error {
+		ko := options.KeyOpts{KeyRef: key, PassFunc: func(bool) ([]byte, error) { return nil, nil }}
+		signOpts.Upload = true
+		return sign.SignCmd(ctx, &options.RootOptions{Timeout: options.DefaultTimeout}, ko, signOpts, []string{img})
+	}
+
+	// app adds a layer to the two layers of base, which the platform team
+	// signed in the repository of base.
+	baseImg, err := random.Image(100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	top, err := random.Layer(100, "application/vnd.oci.image.layer.v1.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	appImg, err := mutate.AppendLayers(baseImg, top)
+	if err != nil {
+		t.Fatal(err)
+	}
+	platformKey, platformPub := newKey("platform")
+	appKey, appPub := newKey("app")
+	base, app := push("base", baseImg), push("app", appImg)
+	if err := signWith(platformKey, options.SignOptions{Layers: []string{"0", "1"}}, base); err != nil {
+		t.Fatal(err)
+	}
+	if err := signWith(appKey, options.SignOptions{}, app); err != nil {
+		t.Fatal(err)
+	}
+	if err := signWith(appKey, options.SignOptions{Layers: []string{"0"}, Recursive: true}, app); err == nil {
+		t.Error("signing --layers with --recursive: expected an error")
+	}
+
+	for _, tt := range []struct {
+		name    string
+		rules   []LayerPolicyRule
+		wantErr string
+	}{{
+		name:  "base layers signed by the platform team",
+		rules: []LayerPolicyRule{{Layers: []string{"0", "1"}, Key: platformPub, SourceRepositories: []string{host + "/base"}}},
+	}, {
+		name:    "layer signatures only in another repository",
+		rules:   []LayerPolicyRule{{Layers: []string{"0"}, Key: platformPub}},
+		wantErr: "verifying the signatures of layer",
+	}, {
+		name:    "top layer not signed",
+		rules:   []LayerPolicyRule{{Layers: []string{"-1"}, Key: platformPub, SourceRepositories: []string{host + "/base"}}},
+		wantErr: "verifying the signatures of layer",
+	}, {
+		name:    "signed by another key",
+		rules:   []LayerPolicyRule{{Layers: []string{"0"}, Key: appPub, SourceRepositories: []string{host + "/base"}}},
+		wantErr: "verifying the signatures of layer",
+	}, {
+		name:    "no such layer",
+		rules:   []LayerPolicyRule{{Layers: []string{"3"}, Key: platformPub}},
+		wantErr: "out of range",
+	}, {
+		name:    "no key",
+		rules:   []LayerPolicyRule{{Layers: []string{"0"}}},
+		wantErr: "must set a key or a certificate identity",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(LayerPolicy{Rules: tt.rules})
+			if err != nil {
+				t.Fatal(err)
+			}
+			policy := filepath.Join(t.TempDir(), "layers.json")
+			if err := os.WriteFile(policy, b, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			v := &VerifyCommand{
+				KeyRef:      appPub,
+				CheckClaims: true,
+				IgnoreTlog:  true,
+				IgnoreSCT:   true,
+				LayerPolicy: policy,
+			}
+			err = v.Exec(ctx, []string{app})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Exec() = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Exec() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Setenv(env.VariableFeatureGates.String(), "LayerSignatures=false")
+	if err := signWith(platformKey, options.SignOptions{Layers: []string{"0"}}, base); err == nil || !strings.Contains(err.Error(), "LayerSignatures") {
+		t.Errorf("signing --layers with the feature gate disabled = %v", err)
+	}
+}
@@ -0,0 +1,399 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/policy"
+)
+
+// noAssertion is the license of packages whose SBOM doesn't state one.
+const noAssertion = "NOASSERTION"
+
+// licensePolicy is the --deny-license and --license-allowlist of
+// verify-attestation, of lower case SPDX license IDs.
+type licensePolicy struct {
+	deny map[string]bool
+	// allow, if set, is the only licenses that packages may be used under.
+	allow map[string]bool
+}
+
+// newLicensePolicy returns the license policy of o, or nil if it sets none.
+func newLicensePolicy(o options.LicensePolicyOptions) (*licensePolicy, error) {
+	if !o.Enabled() {
+		return nil, nil
+	}
+	p := &licensePolicy{deny: map[string]bool{}}
+	for _, id := range o.Deny {
+		p.deny[strings.ToLower(strings.TrimSpace(id))] = true
+	}
+	if o.Allowlist != "" {
+		f, err := os.Open(o.Allowlist)
+		if err != nil {
+			return nil, fmt.Errorf("reading the license allowlist: %w", err)
+		}
+		defer f.Close()
+		p.allow = map[string]bool{}
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			line := strings.TrimSpace(s.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			p.allow[strings.ToLower(line)] = true
+		}
+		if err := s.Err(); err != nil {
+			return nil, fmt.Errorf("reading the license allowlist: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// allowed reports whether packages may be used under the license id.
+func (p *licensePolicy) allowed(id string) bool {
+	id = strings.ToLower(id)
+	return !p.deny[id] && (p.allow == nil || p.allow[id])
+}
+
+// check checks the packages of the SPDX and CycloneDX attestations of
+// verified against p, and fails listing those that can't be used under an
+// allowed license.
+func (p *licensePolicy) check(ctx context.Context, verified []oci.Signature) error {
+	var sboms int
+	var violations []string
+	for _, sig := range verified {
+		for _, predicateType := range []string{options.PredicateSPDXJSON, options.PredicateCycloneDX} {
+			payload, _, err := policy.AttestationToPayloadJSON(ctx, predicateType, sig)
+			if err != nil {
+				return err
+			}
+			if len(payload) == 0 {
+				continue
+			}
+			var statement struct {
+				Predicate json.RawMessage `json:"predicate"`
+			}
+			if err := json.Unmarshal(payload, &statement); err != nil {
+				return fmt.Errorf("parsing the statement of an attestation: %w", err)
+			}
+			pkgs, err := sbomPackages(predicateType, statement.Predicate)
+			if err != nil {
+				return err
+			}
+			sboms++
+			violations = append(violations, p.violations(pkgs)...)
+		}
+	}
+	if sboms == 0 {
+		return errors.New("none of the verified attestations is an SPDX or CycloneDX SBOM to check the license policy against")
+	}
+	if len(violations) > 0 {
+		for _, v := range violations {
+			ui.Infof(ctx, "- %s", v)
+		}
+		return fmt.Errorf("%d packages violate the license policy: %s", len(violations), strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// sbomPackage is a package of an SBOM, with its license expression.
+type sbomPackage struct {
+	name, version, license string
+}
+
+func (pkg sbomPackage) String() string {
+	if pkg.version == "" {
+		return pkg.name
+	}
+	return pkg.name + "@" + pkg.version
+}
+
+// violations returns a description of each of pkgs that can't be used under
+// an allowed license.
+func (p *licensePolicy) violations(pkgs []sbomPackage) []string {
+	var violations []string
+	for _, pkg := range pkgs {
+		license := pkg.license
+		if license == "" {
+			license = noAssertion
+		}
+		expr, err := parseLicenseExpression(license)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %v", pkg, err))
+			continue
+		}
+		if ok, failed := expr.eval(p); !ok {
+			violations = append(violations, fmt.Sprintf("%s: %s (%s not allowed)", pkg, license, strings.Join(failed, ", ")))
+		}
+	}
+	return violations
+}
+
+// sbomPackages returns the packages of the SPDX or CycloneDX predicate of
+// predicateType.
+func sbomPackages(predicateType string, predicate json.RawMessage) ([]sbomPackage, error) {
+	if predicateType == options.PredicateCycloneDX {
+		return cycloneDXPackages(predicate)
+	}
+	// cosign attest --type spdx attests the tag-value format as a string.
+	var tagValue string
+	if err := json.Unmarshal(predicate, &tagValue); err == nil {
+		return spdxTagValuePackages(tagValue), nil
+	}
+	var doc struct {
+		Packages []struct {
+			Name             string `json:"name"`
+			VersionInfo      string `json:"versionInfo"`
+			LicenseConcluded string `json:"licenseConcluded"`
+			LicenseDeclared  string `json:"licenseDeclared"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(predicate, &doc); err != nil {
+		return nil, fmt.Errorf("decoding SPDX document: %w", err)
+	}
+	pkgs := make([]sbomPackage, 0, len(doc.Packages))
+	for _, p := range doc.Packages {
+		pkgs = append(pkgs, sbomPackage{name: p.Name, version: p.VersionInfo, license: spdxLicense(p.LicenseConcluded, p.LicenseDeclared)})
+	}
+	return pkgs, nil
+}
+
+// spdxLicense returns the concluded license of an SPDX package, or else its
+// declared license.
+func spdxLicense(concluded, declared string) string {
+	if concluded != "" && concluded != noAssertion {
+		return concluded
+	}
+	return declared
+}
+
+// spdxTagValuePackages returns the packages of an SPDX document in the
+// tag-value format.
+func spdxTagValuePackages(doc string) []sbomPackage {
+	var pkgs []sbomPackage
+	var concluded, declared string
+	flush := func() {
+		if len(pkgs) > 0 {
+			pkgs[len(pkgs)-1].license = spdxLicense(concluded, declared)
+		}
+		concluded, declared = "", ""
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		tag, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(tag) {
+		case "PackageName":
+			flush()
+			pkgs = append(pkgs, sbomPackage{name: value})
+		case "PackageVersion":
+			if len(pkgs) > 0 {
+				pkgs[len(pkgs)-1].version = value
+			}
+		case "PackageLicenseConcluded":
+			concluded = value
+		case "PackageLicenseDeclared":
+			declared = value
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// cycloneDXComponent is the subset of a CycloneDX component that licenses
+// are checked in. Components may nest arbitrarily.
+type cycloneDXComponent struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Licenses []struct {
+		License *struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// license returns the license expression of c: its licenses, all of which
+// apply.
+func (c cycloneDXComponent) license() string {
+	var terms []string
+	for _, l := range c.Licenses {
+		switch {
+		case l.Expression != "":
+			terms = append(terms, "("+l.Expression+")")
+		case l.License != nil && l.License.ID != "":
+			terms = append(terms, l.License.ID)
+		case l.License != nil && l.License.Name != "":
+			// Licenses without an SPDX ID are only allowed by name.
+			terms = append(terms, "LicenseRef-"+strings.Join(strings.Fields(l.License.Name), "-"))
+		}
+	}
+	return strings.Join(terms, " AND ")
+}
+
+func cycloneDXPackages(predicate json.RawMessage) ([]sbomPackage, error) {
+	var doc struct {
+		Metadata struct {
+			Component *cycloneDXComponent `json:"component"`
+		} `json:"metadata"`
+		Components []cycloneDXComponent `json:"components"`
+	}
+	if err := json.Unmarshal(predicate, &doc); err != nil {
+		return nil, fmt.Errorf("decoding CycloneDX document: %w", err)
+	}
+	var pkgs []sbomPackage
+	var walk func(cs []cycloneDXComponent)
+	walk = func(cs []cycloneDXComponent) {
+		for _, c := range cs {
+			pkgs = append(pkgs, sbomPackage{name: c.Name, version: c.Version, license: c.license()})
+			walk(c.Components)
+		}
+	}
+	if doc.Metadata.Component != nil {
+		walk([]cycloneDXComponent{*doc.Metadata.Component})
+	}
+	walk(doc.Components)
+	return pkgs, nil
+}
+
+// licenseExpression is a parsed SPDX license expression: a license ID, with
+// an exception that doesn't change whether it's allowed, or the AND or OR of
+// its terms.
+type licenseExpression struct {
+	id    string
+	op    string
+	terms []*licenseExpression
+}
+
+// eval reports whether a package under e may be used with p: with all the
+// terms of an AND, or one of an OR. It also returns the IDs that aren't
+// allowed.
+func (e *licenseExpression) eval(p *licensePolicy) (bool, []string) {
+	if e.op == "" {
+		if p.allowed(e.id) {
+			return true, nil
+		}
+		return false, []string{e.id}
+	}
+	var failed []string
+	for _, t := range e.terms {
+		ok, f := t.eval(p)
+		if ok && e.op == "OR" {
+			return true, nil
+		}
+		failed = append(failed, f...)
+	}
+	return len(failed) == 0, failed
+}
+
+// parseLicenseExpression parses an SPDX license expression, such as
+// "MIT OR (GPL-2.0-only WITH Classpath-exception-2.0 AND BSD-3-Clause)".
+func parseLicenseExpression(s string) (*licenseExpression, error) {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s))
+	p := &licenseParser{tokens: tokens}
+	e, err := p.parse("OR")
+	if err == nil && p.pos < len(tokens) {
+		err = fmt.Errorf("unexpected %q", tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %w", s, err)
+	}
+	return e, nil
+}
+
+// licenseParser parses the tokens of a license expression, where AND binds
+// tighter than OR.
+type licenseParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *licenseParser) next() string {
+	if p.pos == len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// parse parses the terms joined by op, or by AND within those of OR.
+func (p *licenseParser) parse(op string) (*licenseExpression, error) {
+	term := p.term
+	if op == "OR" {
+		term = func() (*licenseExpression, error) { return p.parse("AND") }
+	}
+	e, err := term()
+	if err != nil {
+		return nil, err
+	}
+	terms := []*licenseExpression{e}
+	for strings.EqualFold(p.next(), op) {
+		p.pos++
+		e, err := term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, e)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &licenseExpression{op: op, terms: terms}, nil
+}
+
+// term parses a parenthesized expression or a license ID.
+func (p *licenseParser) term() (*licenseExpression, error) {
+	switch tok := p.next(); {
+	case tok == "":
+		return nil, errors.New("missing license")
+	case tok == "(":
+		p.pos++
+		e, err := p.parse("OR")
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return e, nil
+	case tok == ")" || strings.EqualFold(tok, "AND") || strings.EqualFold(tok, "OR") || strings.EqualFold(tok, "WITH"):
+		return nil, fmt.Errorf("unexpected %q", tok)
+	default:
+		p.pos++
+		if strings.EqualFold(p.next(), "WITH") {
+			p.pos++
+			if exception := p.next(); exception == "" || exception == "(" || exception == ")" {
+				return nil, errors.New("missing license exception")
+			}
+			p.pos++
+		}
+		return &licenseExpression{id: tok}, nil
+	}
+}
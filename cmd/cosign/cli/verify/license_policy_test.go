@@ -0,0 +1,172 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestLicenseExpression(t *testing.T) {
+	p := &licensePolicy{deny: map[string]bool{"gpl-3.0-only": true}}
+	allowlist := &licensePolicy{deny: map[string]bool{}, allow: map[string]bool{"mit": true, "apache-2.0": true}}
+	for _, tc := range []struct {
+		expr      string
+		policy    *licensePolicy
+		ok        bool
+		failed    string
+		wantError bool
+	}{
+		{expr: "MIT", policy: p, ok: true},
+		{expr: "gpl-3.0-ONLY", policy: p, failed: "gpl-3.0-ONLY"},
+		{expr: "MIT OR GPL-3.0-only", policy: p, ok: true},
+		{expr: "MIT AND GPL-3.0-only", policy: p, failed: "GPL-3.0-only"},
+		{expr: "MIT AND (GPL-3.0-only OR Apache-2.0)", policy: p, ok: true},
+		{expr: "GPL-3.0-only WITH GCC-exception-3.1 or BSD-3-Clause", policy: allowlist, failed: "GPL-3.0-only, BSD-3-Clause"},
+		{expr: "Apache-2.0 AND MIT OR BSD-3-Clause", policy: allowlist, ok: true},
+		{expr: "NOASSERTION", policy: allowlist, failed: "NOASSERTION"},
+		{expr: "MIT AND", wantError: true},
+		{expr: "(MIT OR Apache-2.0", wantError: true},
+		{expr: "MIT Apache-2.0", wantError: true},
+		{expr: "MIT WITH", wantError: true},
+	} {
+		e, err := parseLicenseExpression(tc.expr)
+		if (err != nil) != tc.wantError {
+			t.Errorf("parseLicenseExpression(%q) = %v, want error %t", tc.expr, err, tc.wantError)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		ok, failed := e.eval(tc.policy)
+		if ok != tc.ok || strings.Join(failed, ", ") != tc.failed {
+			t.Errorf("eval(%q) = %t, %v, want %t, %s", tc.expr, ok, failed, tc.ok, tc.failed)
+		}
+	}
+}
+
+func TestSBOMPackages(t *testing.T) {
+	for _, tc := range []struct {
+		name, predicateType, predicate string
+		want                           []sbomPackage
+	}{{
+		name:          "SPDX JSON",
+		predicateType: options.PredicateSPDXJSON,
+		predicate: `{"spdxVersion": "SPDX-2.3", "packages": [
+			{"name": "a", "versionInfo": "1.0", "licenseConcluded": "MIT", "licenseDeclared": "Apache-2.0"},
+			{"name": "b", "licenseConcluded": "NOASSERTION", "licenseDeclared": "GPL-3.0-only"},
+			{"name": "c"}]}`,
+		want: []sbomPackage{{"a", "1.0", "MIT"}, {"b", "", "GPL-3.0-only"}, {"c", "", ""}},
+	}, {
+		name:          "SPDX tag-value",
+		predicateType: options.PredicateSPDXJSON,
+		predicate:     `"SPDXVersion: SPDX-2.3\nPackageName: a\nPackageVersion: 1.0\nPackageLicenseConcluded: MIT OR Apache-2.0\nPackageName: b\nPackageLicenseDeclared: BSD-3-Clause\n"`,
+		want:          []sbomPackage{{"a", "1.0", "MIT OR Apache-2.0"}, {"b", "", "BSD-3-Clause"}},
+	}, {
+		name:          "CycloneDX",
+		predicateType: options.PredicateCycloneDX,
+		predicate: `{"bomFormat": "CycloneDX",
+			"metadata": {"component": {"name": "app", "version": "2.0", "licenses": [{"license": {"id": "Apache-2.0"}}]}},
+			"components": [
+				{"name": "a", "licenses": [{"license": {"id": "MIT"}}, {"expression": "BSD-3-Clause OR GPL-2.0-only"}],
+				 "components": [{"name": "b", "licenses": [{"license": {"name": "Acme Commercial"}}]}]},
+				{"name": "c"}]}`,
+		want: []sbomPackage{{"app", "2.0", "Apache-2.0"}, {"a", "", "MIT AND (BSD-3-Clause OR GPL-2.0-only)"}, {"b", "", "LicenseRef-Acme-Commercial"}, {"c", "", ""}},
+	}} {
+		got, err := sbomPackages(tc.predicateType, []byte(tc.predicate))
+		if err != nil {
+			t.Errorf("%s: sbomPackages() = %v", tc.name, err)
+			continue
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: sbomPackages() = %v, want %v", tc.name, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: package %d = %+v, want %+v", tc.name, i, got[i], tc.want[i])
+			}
+		}
+	}
+}
+
+func sbomAttestation(t *testing.T, predicateType, predicate string) oci.Signature {
+	t.Helper()
+	statement := `{"_type": "https://in-toto.io/Statement/v0.1", "predicateType": "` + predicateType + `", "subject": [], "predicate": ` + predicate + `}`
+	att, err := static.NewAttestation([]byte(`{"payloadType":"application/vnd.in-toto+json","payload":"` +
+		base64.StdEncoding.EncodeToString([]byte(statement)) + `","signatures":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return att
+}
+
+func TestLicensePolicyCheck(t *testing.T) {
+	ctx := context.Background()
+	allowlist := filepath.Join(t.TempDir(), "licenses.txt")
+	if err := os.WriteFile(allowlist, []byte("# permissive\nMIT\nApache-2.0\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	spdx := sbomAttestation(t, in_toto.PredicateSPDX, `{"spdxVersion": "SPDX-2.3", "packages": [
+		{"name": "a", "versionInfo": "1.0", "licenseConcluded": "MIT"},
+		{"name": "b", "licenseConcluded": "MIT OR GPL-3.0-only"}]}`)
+	cdx := sbomAttestation(t, in_toto.PredicateCycloneDX, `{"bomFormat": "CycloneDX", "components": [
+		{"name": "c", "version": "3", "licenses": [{"license": {"id": "GPL-3.0-only"}}]}, {"name": "d"}]}`)
+	provenance := sbomAttestation(t, "https://slsa.dev/provenance/v0.2", `{}`)
+
+	for _, tc := range []struct {
+		name     string
+		o        options.LicensePolicyOptions
+		verified []oci.Signature
+		want     string
+	}{
+		{name: "denied alternative", o: options.LicensePolicyOptions{Deny: []string{"GPL-3.0-only"}}, verified: []oci.Signature{spdx}},
+		{name: "denied", o: options.LicensePolicyOptions{Deny: []string{"GPL-3.0-only"}}, verified: []oci.Signature{spdx, cdx, provenance},
+			want: "1 packages violate the license policy: c@3: GPL-3.0-only (GPL-3.0-only not allowed)"},
+		{name: "allowlist", o: options.LicensePolicyOptions{Allowlist: allowlist}, verified: []oci.Signature{spdx, cdx},
+			want: "2 packages violate the license policy: c@3: GPL-3.0-only (GPL-3.0-only not allowed); d: NOASSERTION (NOASSERTION not allowed)"},
+		{name: "no SBOM", o: options.LicensePolicyOptions{Deny: []string{"GPL-3.0-only"}}, verified: []oci.Signature{provenance},
+			want: "none of the verified attestations is an SPDX or CycloneDX SBOM"},
+	} {
+		p, err := newLicensePolicy(tc.o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.check(ctx, tc.verified)
+		if tc.want == "" && err != nil {
+			t.Errorf("%s: check() = %v", tc.name, err)
+		} else if tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)) {
+			t.Errorf("%s: check() = %v, want %s", tc.name, err, tc.want)
+		}
+	}
+
+	if p, err := newLicensePolicy(options.LicensePolicyOptions{}); p != nil || err != nil {
+		t.Errorf("newLicensePolicy() without a policy = %v, %v", p, err)
+	}
+	if _, err := newLicensePolicy(options.LicensePolicyOptions{Allowlist: filepath.Join(t.TempDir(), "missing.txt")}); err == nil {
+		t.Error("newLicensePolicy() with a missing allowlist: expected an error")
+	}
+}
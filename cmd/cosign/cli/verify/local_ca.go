@@ -0,0 +1,57 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+// localCACerts returns the roots and intermediates of the local or ACME CA
+// in the PEM file of --local-ca-roots or --acme-ca-roots. The self-signed
+// certificates are the roots.
+func localCACerts(o *options.CertVerifyOptions) (roots, intermediates []*x509.Certificate, err error) {
+	flag, path := "--local-ca-roots", o.CARoots()
+	if o.ACMECARoots != "" {
+		flag = "--acme-ca-roots"
+	}
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", flag, err)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(b)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", flag, err)
+	}
+	for _, cert := range certs {
+		if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+			roots = append(roots, cert)
+			continue
+		}
+		intermediates = append(intermediates, cert)
+	}
+	if roots == nil {
+		return nil, nil, fmt.Errorf("%s %s has no root certificates", flag, path)
+	}
+	return roots, intermediates, nil
+}
@@ -0,0 +1,105 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestVerifyBlobLocalCA(t *testing.T) {
+	td := t.TempDir()
+	write := func(name string, b []byte) string {
+		p := filepath.Join(td, name)
+		if err := os.WriteFile(p, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	root, rootKey, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, subKey, err := test.GenerateSubordinateCa(root, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, priv, err := test.GenerateLeafCert("foo@example.com", "https://local-ca.example.com", sub, subKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := []byte("release artifact")
+	raw, err := sv.SignMessage(bytes.NewReader(blob))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemCerts := func(certs ...*x509.Certificate) []byte {
+		b, err := cryptoutils.MarshalCertificatesToPEM(certs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	blobPath := write("blob", blob)
+	sigPath := write("blob.sig", []byte(base64.StdEncoding.EncodeToString(raw)))
+	certPath := write("blob.pem", pemCerts(leaf))
+	rootsPath := write("roots.pem", pemCerts(sub, root))
+	otherRoot, _, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRootsPath := write("other.pem", pemCerts(otherRoot))
+	intermediatesPath := write("intermediates.pem", pemCerts(sub))
+
+	verify := func(roots string) error {
+		c := &VerifyBlobCmd{
+			CertVerifyOptions: options.CertVerifyOptions{
+				CertIdentity:   "foo@example.com",
+				CertOidcIssuer: "https://local-ca.example.com",
+				LocalCARoots:   roots,
+			},
+			CertRef:    certPath,
+			SigRef:     sigPath,
+			IgnoreTlog: true,
+		}
+		return c.Exec(context.Background(), blobPath)
+	}
+	// The certificates of a local CA have no SCT.
+	if err := verify(rootsPath); err != nil {
+		t.Errorf("Exec() with the local CA roots = %v", err)
+	}
+	if err := verify(otherRootsPath); err == nil {
+		t.Error("Exec() with the roots of another CA: expected an error")
+	}
+	if err := verify(intermediatesPath); err == nil {
+		t.Error("Exec() without a root: expected an error")
+	}
+}
@@ -0,0 +1,142 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// loadOfflineBundle reads the offline bundle and the trusted root of o. With
+// a bundle, the images to verify are its image, which images may name by
+// digest, and the trusted root, which is empty if needsTrustedRoot is false
+// and none was given, is never nil.
+func loadOfflineBundle(o options.OfflineBundleOptions, images []string, nameOpts []name.Option, needsTrustedRoot bool) (*cosign.OfflineBundle, *cosign.TrustedRoot, []string, error) {
+	var root *cosign.TrustedRoot
+	if o.TrustedRoot != "" {
+		var err error
+		root, err = cosign.LoadTrustedRoot(o.TrustedRoot)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if o.BundleFile == "" {
+		return nil, root, images, nil
+	}
+
+	b, err := cosign.LoadOfflineBundle(o.BundleFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	digest, err := b.Digest()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// The image may have been copied to another registry, so only the digest
+	// has to match.
+	switch len(images) {
+	case 0:
+		images = []string{digest.String()}
+	case 1:
+		ref, err := oci.ParseReference(images[0], nameOpts...)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("parsing reference: %w", err)
+		}
+		if d, ok := ref.(name.Digest); !ok || d.DigestStr() != digest.DigestStr() {
+			return nil, nil, nil, fmt.Errorf("%s isn't the image of the bundle, %s", images[0], digest)
+		}
+	default:
+		return nil, nil, nil, errors.New("--bundle-file verifies only the image of the bundle")
+	}
+	if root == nil {
+		if needsTrustedRoot {
+			return nil, nil, nil, errors.New("--trusted-root is required with --bundle-file, unless verifying with --key and --insecure-ignore-tlog")
+		}
+		root = &cosign.TrustedRoot{}
+	}
+	return b, root, images, nil
+}
+
+// rekorBundleDir returns the RekorBundleDir at dir, if set.
+func rekorBundleDir(dir string) (cosign.RekorBundleDir, error) {
+	if dir == "" {
+		return "", nil
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("--rekor-offline-bundle-dir: %w", err)
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("--rekor-offline-bundle-dir: %s isn't a directory", dir)
+	}
+	return cosign.RekorBundleDir(dir), nil
+}
+
+// rekorPubKeys returns the Rekor public keys of root, or of the TUF root if
+// root is nil.
+func rekorPubKeys(ctx context.Context, root *cosign.TrustedRoot) (*cosign.TrustedTransparencyLogPubKeys, error) {
+	if root != nil {
+		return root.RekorPubKeys, nil
+	}
+	return cosign.GetRekorPubs(ctx)
+}
+
+// ctLogPubKeys returns the CT log public keys of root, or of the TUF root if
+// root is nil.
+func ctLogPubKeys(ctx context.Context, root *cosign.TrustedRoot) (*cosign.TrustedTransparencyLogPubKeys, error) {
+	if root != nil {
+		return root.CTLogPubKeys, nil
+	}
+	return cosign.GetCTLogPubs(ctx)
+}
+
+// fulcioCerts returns the Fulcio roots and intermediates of root, or of the
+// TUF root if root is nil.
+func fulcioCerts(root *cosign.TrustedRoot) (roots, intermediates []*x509.Certificate, err error) {
+	if root != nil {
+		return root.FulcioRootCertificates, root.FulcioIntermediateCertificates, nil
+	}
+	roots, intermediates, err = fulcio.GetCertificates()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting Fulcio roots: %w", err)
+	}
+	return roots, intermediates, nil
+}
+
+// setTrustedRootTSA sets the timestamp authority certificates of co to those
+// of root, if it has any. Each timestamp is verified with the chain of the
+// authority that issued it if root has several.
+func setTrustedRootTSA(co *cosign.CheckOpts, root *cosign.TrustedRoot) {
+	if root == nil || len(root.TSARootCertificates) == 0 {
+		return
+	}
+	if len(root.TSACertificateChains) > 1 {
+		co.TSACertificateChains = root.TSACertificateChains
+		return
+	}
+	co.TSACertificate = root.TSACertificate
+	co.TSAIntermediateCertificates = root.TSAIntermediateCertificates
+	co.TSARootCertificates = root.TSARootCertificates
+}
@@ -0,0 +1,453 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/digitorus/timestamp"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/templates"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+	"sigs.k8s.io/release-utils/version"
+)
+
+const (
+	// OutputJSONv1 is the --output format of the verification results in the
+	// VerificationResultSchema.
+	OutputJSONv1 = "json-v1"
+	// OutputSARIF is the --output format of the verification results as a
+	// SARIF 2.1.0 log.
+	OutputSARIF = "sarif"
+
+	// VerificationResultSchema identifies the version of the schema of
+	// VerificationResult. Fields are only added within a version.
+	VerificationResultSchema = "https://sigstore.dev/cosign/verification-result/v1"
+
+	// VerificationPassed and VerificationFailed are the statuses of the
+	// subjects and signatures of a VerificationResult.
+	VerificationPassed = "pass"
+	VerificationFailed = "fail"
+	// VerificationNotEvaluated is the status of the subjects that weren't
+	// verified before the deadline of --timeout, and of the results with
+	// such subjects and no failed ones.
+	VerificationNotEvaluated = "not-evaluated"
+
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// structuredOutput reports whether output is a format of the verification
+// results, rather than of the verified payloads.
+func structuredOutput(output string) bool {
+	return output == OutputJSONv1 || output == OutputSARIF
+}
+
+// VerificationResult is the machine-readable result of `cosign verify`,
+// `cosign verify-attestation` or `cosign verify-blob`, written with
+// --output json-v1 or sarif.
+type VerificationResult struct {
+	Schema        string                      `json:"schema"`
+	Command       string                      `json:"command"`
+	CosignVersion string                      `json:"cosignVersion"`
+	VerifiedAt    time.Time                   `json:"verifiedAt"`
+	Status        string                      `json:"status"`
+	Error         string                      `json:"error,omitempty"`
+	Subjects      []VerificationResultSubject `json:"subjects"`
+	// template, if set, is the --output-template the results are written
+	// with instead.
+	template *template.Template
+	// githubSummary, if set, is the GitHub Actions job summary file the
+	// results are appended to as Markdown with --github-summary.
+	githubSummary string
+	// summaryOnly marks results collected only for githubSummary, which
+	// aren't written and with which the verified payloads are printed.
+	summaryOnly bool
+}
+
+// VerificationResultSubject is the result of verifying one image or blob.
+type VerificationResultSubject struct {
+	Name       string                        `json:"name"`
+	Digest     string                        `json:"digest,omitempty"`
+	Status     string                        `json:"status"`
+	Error      string                        `json:"error,omitempty"`
+	Signatures []VerificationResultSignature `json:"signatures"`
+}
+
+// VerificationResultSignature describes a signature or attestation checked
+// for a subject.
+type VerificationResultSignature struct {
+	Status           string                       `json:"status"`
+	Signature        string                       `json:"signature"`
+	Identity         *VerificationResultIdentity  `json:"identity,omitempty"`
+	Tlog             *VerificationResultTlogEntry `json:"tlog,omitempty"`
+	RFC3161Timestamp *time.Time                   `json:"rfc3161Timestamp,omitempty"`
+	Warnings         []cosign.VerificationWarning `json:"warnings,omitempty"`
+}
+
+// VerificationResultIdentity is the identity of the certificate of a
+// signature.
+type VerificationResultIdentity struct {
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer,omitempty"`
+	// Matched is the number, from 1, of the expected identity that the
+	// certificate matched, when verifying with several.
+	Matched int `json:"matched,omitempty"`
+}
+
+// VerificationResultTlogEntry is the transparency log entry of a signature.
+type VerificationResultTlogEntry struct {
+	LogID          string    `json:"logID"`
+	LogIndex       int64     `json:"logIndex"`
+	IntegratedTime time.Time `json:"integratedTime"`
+}
+
+// newTemplateResult returns the results of command, written with the
+// --output-template outputTemplate if set.
+func newTemplateResult(command, outputTemplate string) (*VerificationResult, error) {
+	t, err := templates.ParseOutput(outputTemplate)
+	if err != nil {
+		return nil, err
+	}
+	r := newVerificationResult(command)
+	r.template = t
+	return r, nil
+}
+
+// newCommandResult returns the results of command for --output output,
+// --output-template outputTemplate and --github-summary githubSummary, or
+// nil if none of them needs results.
+func newCommandResult(command, output, outputTemplate string, githubSummary bool) (*VerificationResult, error) {
+	if !structuredOutput(output) && outputTemplate == "" && !githubSummary {
+		return nil, nil
+	}
+	r, err := newTemplateResult(command, outputTemplate)
+	if err != nil {
+		return nil, err
+	}
+	r.summaryOnly = !structuredOutput(output) && outputTemplate == ""
+	if githubSummary {
+		if r.githubSummary = env.Getenv(env.VariableGitHubStepSummary); r.githubSummary == "" {
+			return nil, fmt.Errorf("--github-summary: $%s isn't set, the job summary is only written in GitHub Actions", env.VariableGitHubStepSummary)
+		}
+	}
+	return r, nil
+}
+
+var (
+	cosignVersionOnce sync.Once
+	cosignGitVersion  string
+)
+
+// cosignVersion returns the version of cosign in the results.
+// version.GetVersionInfo writes package variables without locking, so it's
+// only called once for the concurrent verifications of Audit.
+func cosignVersion() string {
+	cosignVersionOnce.Do(func() {
+		cosignGitVersion = version.GetVersionInfo().GitVersion
+	})
+	return cosignGitVersion
+}
+
+func newVerificationResult(command string) *VerificationResult {
+	return &VerificationResult{
+		Schema:        VerificationResultSchema,
+		Command:       command,
+		CosignVersion: cosignVersion(),
+		VerifiedAt:    time.Now().UTC(),
+		Subjects:      []VerificationResultSubject{},
+	}
+}
+
+// pass records the verified signatures of the subject name, accepted for one
+// of identities. The digest is that of the signed payloads if empty.
+func (r *VerificationResult) pass(name, digest string, verified []oci.Signature, identities []cosign.Identity, warnings warningCollector) {
+	s := VerificationResultSubject{
+		Name:       name,
+		Digest:     digest,
+		Status:     VerificationPassed,
+		Signatures: []VerificationResultSignature{},
+	}
+	for _, sig := range verified {
+		if s.Digest == "" {
+			s.Digest = signedDigest(sig)
+		}
+		s.Signatures = append(s.Signatures, resultSignature(sig, identities, warnings))
+	}
+	r.Subjects = append(r.Subjects, s)
+}
+
+// fail records the failed verification of the subject name.
+func (r *VerificationResult) fail(name string, err error) {
+	r.Subjects = append(r.Subjects, VerificationResultSubject{
+		Name:       name,
+		Status:     VerificationFailed,
+		Error:      err.Error(),
+		Signatures: []VerificationResultSignature{},
+	})
+}
+
+// notEvaluated records the subjects names that weren't verified.
+func (r *VerificationResult) notEvaluated(names ...string) {
+	for _, name := range names {
+		r.Subjects = append(r.Subjects, VerificationResultSubject{
+			Name:       name,
+			Status:     VerificationNotEvaluated,
+			Signatures: []VerificationResultSignature{},
+		})
+	}
+}
+
+// finish records err, the error of the verification of the subject current
+// if any, and writes the results to w in the format output. It returns err,
+// or the error of writing the results.
+func (r *VerificationResult) finish(w io.Writer, output, current string, err error) error {
+	r.Status = VerificationPassed
+	if err != nil {
+		r.Status = VerificationFailed
+		r.Error = err.Error()
+		if current != "" {
+			r.fail(current, err)
+		} else if r.partial() {
+			r.Status = VerificationNotEvaluated
+		}
+	}
+	var werr error
+	if !r.summaryOnly {
+		werr = r.write(w, output)
+	}
+	if r.githubSummary != "" {
+		if serr := r.appendGitHubSummary(r.githubSummary); serr != nil && werr == nil {
+			werr = serr
+		}
+	}
+	if werr != nil && err == nil {
+		return werr
+	}
+	return err
+}
+
+// printsPayloads reports whether the verified payloads are printed with the
+// results r, which replace them unless collected only for the job summary.
+func (r *VerificationResult) printsPayloads() bool {
+	return r == nil || r.summaryOnly
+}
+
+// partial reports whether some subjects weren't evaluated, and none failed.
+func (r *VerificationResult) partial() bool {
+	notEvaluated := false
+	for _, s := range r.Subjects {
+		switch s.Status {
+		case VerificationFailed:
+			return false
+		case VerificationNotEvaluated:
+			notEvaluated = true
+		}
+	}
+	return notEvaluated
+}
+
+func (r *VerificationResult) write(w io.Writer, output string) error {
+	if r.template != nil {
+		return templates.WriteOutput(w, r.template, r)
+	}
+	var v interface{} = r
+	if output == OutputSARIF {
+		v = r.sarif()
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling verification results: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+func resultSignature(sig oci.Signature, identities []cosign.Identity, warnings warningCollector) VerificationResultSignature {
+	rs := VerificationResultSignature{
+		Status:   VerificationPassed,
+		Warnings: warnings.forSignature(sig),
+	}
+	rs.Signature, _ = sig.Base64Signature()
+	if cert, err := sig.Cert(); err == nil && cert != nil {
+		ce := cosign.CertExtensions{Cert: cert}
+		rs.Identity = &VerificationResultIdentity{
+			Subject: sigs.CertSubject(cert),
+			Issuer:  ce.GetIssuer(),
+		}
+		if len(identities) > 1 {
+			rs.Identity.Matched = cosign.MatchedIdentity(cert, identities) + 1
+		}
+	}
+	if bundle, err := sig.Bundle(); err == nil && bundle != nil {
+		rs.Tlog = &VerificationResultTlogEntry{
+			LogID:          bundle.Payload.LogID,
+			LogIndex:       bundle.Payload.LogIndex,
+			IntegratedTime: time.Unix(bundle.Payload.IntegratedTime, 0).UTC(),
+		}
+	}
+	if ts, err := sig.RFC3161Timestamp(); err == nil && ts != nil {
+		if t, err := timestamp.ParseResponse(ts.SignedRFC3161Timestamp); err == nil {
+			signed := t.Time.UTC()
+			rs.RFC3161Timestamp = &signed
+		}
+	}
+	return rs
+}
+
+// signedDigest returns the image digest signed by the payload of sig, a
+// simple signing payload or the in-toto statement of an attestation, or ""
+// if it has none.
+func signedDigest(sig oci.Signature) string {
+	p, err := sig.Payload()
+	if err != nil {
+		return ""
+	}
+	var envelope struct {
+		PayloadType string `json:"payloadType"`
+		Payload     string `json:"payload"`
+	}
+	if err := json.Unmarshal(p, &envelope); err == nil && envelope.PayloadType != "" {
+		raw, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			return ""
+		}
+		st := in_toto.Statement{}
+		if err := json.Unmarshal(raw, &st); err != nil || len(st.Subject) == 0 {
+			return ""
+		}
+		algs := make([]string, 0, len(st.Subject[0].Digest))
+		for alg := range st.Subject[0].Digest {
+			algs = append(algs, alg)
+		}
+		sort.Strings(algs)
+		for _, alg := range algs {
+			if _, err := oci.DigestHash(alg); err == nil {
+				return alg + ":" + st.Subject[0].Digest[alg]
+			}
+		}
+		return ""
+	}
+	sci := payload.SimpleContainerImage{}
+	if err := json.Unmarshal(p, &sci); err != nil {
+		return ""
+	}
+	return sci.Critical.Image.DockerManifestDigest
+}
+
+// The subset of SARIF 2.1.0 written for the verification results, with a
+// result for each subject.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Kind       string                 `json:"kind"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+func (r *VerificationResult) sarif() sarifLog {
+	ruleID := "cosign/" + r.Command
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "cosign",
+			Version:        r.CosignVersion,
+			InformationURI: "https://github.com/sigstore/cosign",
+			Rules: []sarifRule{{
+				ID:               ruleID,
+				ShortDescription: sarifMessage{Text: fmt.Sprintf("cosign %s signature verification", r.Command)},
+			}},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, s := range r.Subjects {
+		res := sarifResult{
+			RuleID:    ruleID,
+			Kind:      VerificationPassed,
+			Level:     "none",
+			Message:   sarifMessage{Text: fmt.Sprintf("%d signatures of %s verified", len(s.Signatures), s.Name)},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{Name: s.Name, Kind: "resource"}}}},
+			Properties: map[string]interface{}{
+				"schema":     r.Schema,
+				"signatures": s.Signatures,
+			},
+		}
+		if s.Digest != "" {
+			res.Properties["digest"] = s.Digest
+		}
+		switch s.Status {
+		case VerificationFailed:
+			res.Kind, res.Level = VerificationFailed, "error"
+			res.Message.Text = fmt.Sprintf("verifying %s: %s", s.Name, s.Error)
+		case VerificationNotEvaluated:
+			// Open results are those the tool couldn't decide.
+			res.Kind = "open"
+			res.Message.Text = fmt.Sprintf("%s was not evaluated before the deadline", s.Name)
+		}
+		run.Results = append(run.Results, res)
+	}
+	return sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestVerificationResult(t *testing.T) {
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"example.com/app"},"image":{"docker-manifest-digest":"sha256:abcd"},"type":"cosign container image signature"},"optional":null}`)
+	sig, err := static.NewSignature(payload, "c2lnbmF0dXJl", static.WithBundle(&bundle.RekorBundle{
+		Payload: bundle.RekorPayload{LogIndex: 42, LogID: "log", IntegratedTime: 1700000000},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := base64.StdEncoding.EncodeToString([]byte(`{"_type":"https://in-toto.io/Statement/v0.1","subject":[{"name":"example.com/app","digest":{"sha512":"ef01"}}]}`))
+	att, err := static.NewAttestation([]byte(`{"payloadType":"application/vnd.in-toto+json","payload":"` + statement + `","signatures":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newVerificationResult("verify")
+	r.pass("example.com/app:latest", "", []oci.Signature{sig}, nil, nil)
+	r.pass("example.com/app:attested", "", []oci.Signature{att}, nil, nil)
+	var out bytes.Buffer
+	verifyErr := errors.New("no matching signatures")
+	if err := r.finish(&out, OutputJSONv1, "example.com/other:latest", verifyErr); err != verifyErr {
+		t.Fatalf("finish() = %v, want the verification error", err)
+	}
+
+	got := VerificationResult{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("finish() wrote %q: %v", out.String(), err)
+	}
+	if got.Schema != VerificationResultSchema || got.Command != "verify" || got.Status != VerificationFailed || len(got.Subjects) != 3 {
+		t.Fatalf("finish() = %+v, want the failed verify results of 3 subjects", got)
+	}
+	passed, attested, failed := got.Subjects[0], got.Subjects[1], got.Subjects[2]
+	if passed.Status != VerificationPassed || passed.Digest != "sha256:abcd" || len(passed.Signatures) != 1 {
+		t.Errorf("passed subject = %+v", passed)
+	} else if tlog := passed.Signatures[0].Tlog; tlog == nil || tlog.LogIndex != 42 || tlog.IntegratedTime.Unix() != 1700000000 {
+		t.Errorf("tlog entry = %+v, want log index 42", tlog)
+	}
+	if attested.Digest != "sha512:ef01" {
+		t.Errorf("attested subject digest = %q, want the digest of the statement", attested.Digest)
+	}
+	if failed.Status != VerificationFailed || failed.Error != verifyErr.Error() || failed.Signatures == nil {
+		t.Errorf("failed subject = %+v", failed)
+	}
+
+	out.Reset()
+	if err := r.write(&out, OutputSARIF); err != nil {
+		t.Fatal(err)
+	}
+	log := sarifLog{}
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != sarifVersion || len(log.Runs) != 1 || len(log.Runs[0].Results) != 3 {
+		t.Fatalf("SARIF log = %+v, want a run with 3 results", log)
+	}
+	for i, want := range []string{"none", "none", "error"} {
+		if res := log.Runs[0].Results[i]; res.Level != want || res.RuleID != "cosign/verify" {
+			t.Errorf("SARIF result %d = %+v, want level %s", i, res, want)
+		}
+	}
+}
+
+func TestVerifyBlobCmdOutput(t *testing.T) {
+	td := t.TempDir()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := []byte("release artifact")
+	raw, err := sv.SignMessage(bytes.NewReader(blob))
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(name string, b []byte) string {
+		p := filepath.Join(td, name)
+		if err := os.WriteFile(p, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	keyPath := write("cosign.pub", pub)
+	blobPath := write("blob", blob)
+	sigPath := write("blob.sig", []byte(base64.StdEncoding.EncodeToString(raw)))
+	otherSigPath := write("other.sig", []byte(base64.StdEncoding.EncodeToString([]byte("not a signature"))))
+
+	run := func(t *testing.T, sigRef string) (VerificationResult, error) {
+		t.Helper()
+		f, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdout := os.Stdout
+		os.Stdout = f
+		c := &VerifyBlobCmd{KeyOpts: options.KeyOpts{KeyRef: keyPath}, SigRef: sigRef, IgnoreSCT: true, IgnoreTlog: true, Output: OutputJSONv1}
+		verifyErr := c.Exec(context.Background(), blobPath)
+		os.Stdout = stdout
+		f.Close()
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := VerificationResult{}
+		if err := json.Unmarshal(b, &r); err != nil {
+			t.Fatalf("Exec() wrote %q: %v", b, err)
+		}
+		return r, verifyErr
+	}
+
+	r, err := run(t, sigPath)
+	if err != nil {
+		t.Fatalf("Exec() = %v", err)
+	}
+	digest := sha256.Sum256(blob)
+	if r.Command != "verify-blob" || r.Status != VerificationPassed || len(r.Subjects) != 1 ||
+		r.Subjects[0].Digest != "sha256:"+hex.EncodeToString(digest[:]) || len(r.Subjects[0].Signatures) != 1 {
+		t.Errorf("Exec() results = %+v, want the passed blob", r)
+	}
+
+	r, err = run(t, otherSigPath)
+	if err == nil {
+		t.Fatal("Exec() with another signature: expected an error")
+	}
+	if r.Status != VerificationFailed || len(r.Subjects) != 1 || r.Subjects[0].Name != blobPath || !strings.Contains(r.Subjects[0].Error, "invalid signature") {
+		t.Errorf("Exec() results = %+v, want the failed blob", r)
+	}
+
+	// The template formats the json-v1 results, even with the default
+	// --output.
+	f, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = f
+	c := &VerifyBlobCmd{KeyOpts: options.KeyOpts{KeyRef: keyPath}, SigRef: sigPath, IgnoreSCT: true, IgnoreTlog: true,
+		OutputTemplate: `{{.command}} {{range .subjects}}{{.status}} {{.digest}}{{end}}`}
+	verifyErr := c.Exec(context.Background(), blobPath)
+	os.Stdout = stdout
+	f.Close()
+	if verifyErr != nil {
+		t.Fatalf("Exec() with a template = %v", verifyErr)
+	}
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "verify-blob pass sha256:" + hex.EncodeToString(digest[:]); string(b) != want {
+		t.Errorf("Exec() with a template wrote %q, want %q", b, want)
+	}
+	c.OutputTemplate = "{{.subjects"
+	if err := c.Exec(context.Background(), blobPath); err == nil || !strings.Contains(err.Error(), "parsing output template") {
+		t.Errorf("Exec() with an invalid template = %v", err)
+	}
+}
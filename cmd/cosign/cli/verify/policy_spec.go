@@ -0,0 +1,194 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+)
+
+// PolicyTestSpec is a test spec of cosign policy test, in YAML or JSON.
+type PolicyTestSpec struct {
+	Tests []PolicyTestCase `yaml:"tests"`
+}
+
+// PolicyTestCase evaluates policies against fixture attestations, as cosign
+// verify-attestation --policy would against the verified attestations of an
+// image. The paths are relative to the spec.
+type PolicyTestCase struct {
+	Name string `yaml:"name"`
+	// Policies are the .cue and .rego policies.
+	Policies []string `yaml:"policies"`
+	// Attestations are DSSE envelopes of in-toto statements, as attached to
+	// images, or in-toto statements.
+	Attestations []string `yaml:"attestations"`
+	// Types are the --type predicate types, custom if empty.
+	Types []string `yaml:"types"`
+	// Evaluation is the --policy-evaluation, each or joint.
+	Evaluation string `yaml:"evaluation"`
+	// Expect is the expected result, allow or deny.
+	Expect string `yaml:"expect"`
+}
+
+const (
+	policyAllow = "allow"
+	policyDeny  = "deny"
+)
+
+// PolicyTestCmd runs the tests of the specs in files, or those whose name
+// matches o.Run, and writes their results to w. It fails if a test fails.
+func PolicyTestCmd(ctx context.Context, o options.PolicyTestOptions, files []string, w io.Writer) error {
+	var run *regexp.Regexp
+	if o.Run != "" {
+		var err error
+		if run, err = regexp.Compile(o.Run); err != nil {
+			return fmt.Errorf("--run: %w", err)
+		}
+	}
+	total, failed := 0, 0
+	for _, f := range files {
+		spec, err := readPolicyTestSpec(f)
+		if err != nil {
+			return err
+		}
+		for _, tc := range spec.Tests {
+			if run != nil && !run.MatchString(tc.Name) {
+				continue
+			}
+			total++
+			if err := tc.run(ctx, filepath.Dir(f)); err != nil {
+				failed++
+				fmt.Fprintf(w, "FAIL %s: %s: %v\n", f, tc.Name, err)
+				continue
+			}
+			fmt.Fprintf(w, "ok   %s: %s\n", f, tc.Name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d policy tests failed", failed, total)
+	}
+	if total == 0 {
+		return errors.New("no policy tests to run")
+	}
+	return nil
+}
+
+func readPolicyTestSpec(file string) (*PolicyTestSpec, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	spec := &PolicyTestSpec{}
+	if err := yaml.Unmarshal(b, spec); err != nil {
+		return nil, fmt.Errorf("parsing policy test spec %s: %w", file, err)
+	}
+	for i, tc := range spec.Tests {
+		switch {
+		case tc.Name == "":
+			return nil, fmt.Errorf("policy test spec %s: test %d has no name", file, i)
+		case len(tc.Policies) == 0:
+			return nil, fmt.Errorf("policy test spec %s: test %s has no policies", file, tc.Name)
+		case tc.Expect != policyAllow && tc.Expect != policyDeny:
+			return nil, fmt.Errorf("policy test spec %s: test %s expects %q, want %s or %s", file, tc.Name, tc.Expect, policyAllow, policyDeny)
+		}
+	}
+	return spec, nil
+}
+
+// run evaluates the policies of tc, with the paths relative to dir, and
+// reports whether the result is the expected one.
+func (tc PolicyTestCase) run(ctx context.Context, dir string) error {
+	joint := false
+	switch tc.Evaluation {
+	case "", options.PolicyEvaluationEach:
+	case options.PolicyEvaluationJoint:
+		joint = true
+	default:
+		return fmt.Errorf("invalid evaluation %q, expected %s or %s", tc.Evaluation, options.PolicyEvaluationEach, options.PolicyEvaluationJoint)
+	}
+	predicateTypes := tc.Types
+	if len(predicateTypes) == 0 {
+		predicateTypes = []string{options.PredicateCustom}
+	}
+	policies := make([]string, 0, len(tc.Policies))
+	for _, p := range tc.Policies {
+		policies = append(policies, relativeTo(dir, p))
+	}
+	atts := make([]oci.Signature, 0, len(tc.Attestations))
+	for _, a := range tc.Attestations {
+		att, err := loadAttestationFixture(relativeTo(dir, a))
+		if err != nil {
+			return err
+		}
+		atts = append(atts, att)
+	}
+
+	_, err := checkPolicies(ctx, predicateTypes, atts, nil, policies, joint)
+	switch {
+	case err != nil && tc.Expect == policyAllow:
+		return fmt.Errorf("expected allow, denied: %w", err)
+	case err == nil && tc.Expect == policyDeny:
+		return errors.New("expected deny, allowed")
+	}
+	return nil
+}
+
+func relativeTo(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// loadAttestationFixture reads the DSSE envelope or the in-toto statement in
+// file as an attestation.
+func loadAttestationFixture(file string) (oci.Signature, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	envelope := struct {
+		PayloadType string `json:"payloadType"`
+	}{}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return nil, fmt.Errorf("parsing attestation fixture %s: %w", file, err)
+	}
+	if envelope.PayloadType == "" {
+		b, err = json.Marshal(map[string]interface{}{
+			"payloadType": types.IntotoPayloadType,
+			"payload":     base64.StdEncoding.EncodeToString(b),
+			"signatures":  []interface{}{},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return static.NewAttestation(b)
+}
@@ -0,0 +1,115 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+func TestPolicyTestCmd(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(td, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	write("release.rego", `package signature
+
+default allow = false
+
+allow {
+	input.predicate.Data == "release"
+}
+`)
+	write("joint.cue", `count: 2
+`)
+	write("testdata/release.json", `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://cosign.sigstore.dev/attestation/v1",`+
+		`"subject":[{"name":"example.com/app","digest":{"sha256":"abcd"}}],"predicate":{"Data":"release","Timestamp":""}}`)
+	// A DSSE envelope of a statement of another predicate.
+	write("testdata/debug.json", `{"payloadType":"application/vnd.in-toto+json","signatures":[],"payload":"`+
+		`eyJfdHlwZSI6Imh0dHBzOi8vaW4tdG90by5pby9TdGF0ZW1lbnQvdjAuMSIsInByZWRpY2F0ZVR5cGUiOiJodHRwczovL2Nvc2lnbi5zaWdzdG9yZS5kZXYvYXR0ZXN0YXRpb24vdjEiLCJzdWJqZWN0IjpbXSwicHJlZGljYXRlIjp7IkRhdGEiOiJkZWJ1ZyIsIlRpbWVzdGFtcCI6IiJ9fQ=="}`)
+	spec := write("tests.yaml", `tests:
+- name: release is allowed
+  policies: [release.rego]
+  attestations: [testdata/release.json]
+  expect: allow
+- name: debug is denied
+  policies: [release.rego]
+  attestations: [testdata/debug.json]
+  expect: deny
+- name: joint count
+  policies: [joint.cue]
+  attestations: [testdata/release.json, testdata/debug.json]
+  evaluation: joint
+  expect: allow
+`)
+
+	var out bytes.Buffer
+	if err := PolicyTestCmd(ctx, options.PolicyTestOptions{}, []string{spec}, &out); err != nil {
+		t.Fatalf("PolicyTestCmd() = %v\n%s", err, out.String())
+	}
+	if got := strings.Count(out.String(), "ok   "); got != 3 {
+		t.Errorf("PolicyTestCmd() wrote %q, want 3 passed tests", out.String())
+	}
+
+	out.Reset()
+	if err := PolicyTestCmd(ctx, options.PolicyTestOptions{Run: "^debug"}, []string{spec}, &out); err != nil || strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("PolicyTestCmd() with --run = %v, wrote %q, want a passed test", err, out.String())
+	}
+
+	failing := write("failing.yaml", `tests:
+- name: debug is allowed
+  policies: [release.rego]
+  attestations: [testdata/debug.json]
+  expect: allow
+- name: release is denied
+  policies: [release.rego]
+  attestations: [testdata/release.json]
+  expect: deny
+`)
+	out.Reset()
+	if err := PolicyTestCmd(ctx, options.PolicyTestOptions{}, []string{failing}, &out); err == nil || !strings.Contains(err.Error(), "2 of 2 policy tests failed") {
+		t.Errorf("PolicyTestCmd() with failing tests = %v", err)
+	}
+	if !strings.Contains(out.String(), "expected allow, denied") || !strings.Contains(out.String(), "expected deny, allowed") {
+		t.Errorf("PolicyTestCmd() wrote %q, want the failed expectations", out.String())
+	}
+
+	for name, content := range map[string]string{
+		"no expectation": "tests:\n- name: a\n  policies: [release.rego]\n",
+		"no policies":    "tests:\n- name: a\n  expect: allow\n",
+		"cel":            "tests:\n- name: a\n  policies: [release.cel]\n  expect: allow\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := PolicyTestCmd(ctx, options.PolicyTestOptions{}, []string{write(name+".yaml", content)}, &out); err == nil {
+				t.Error("PolicyTestCmd(): expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/policy"
+)
+
+// checkArtifactQuota checks the signatures and attestations attached to ref
+// against the quotas of q, and that it has verified attestations, checked
+// with co, of the predicate types that q requires. All the violations are
+// reported together, so that an image both bloated and under-attested is
+// flagged as both.
+func checkArtifactQuota(ctx context.Context, ref name.Reference, co *cosign.CheckOpts, q options.ArtifactQuotaOptions) error {
+	se, err := ociremote.SignedEntity(ref, co.RegistryClientOpts...)
+	if err != nil {
+		return fmt.Errorf("accessing image: %w", err)
+	}
+	var violations []string
+	for _, kind := range []struct {
+		name  string
+		flag  string
+		max   int
+		fetch func() (oci.Signatures, error)
+	}{
+		{"signatures", "--max-signatures", q.MaxSignatures, se.Signatures},
+		{"attestations", "--max-attestations", q.MaxAttestations, se.Attestations},
+	} {
+		if kind.max == 0 && q.MaxArtifactSize == 0 {
+			continue
+		}
+		sigs, err := kind.fetch()
+		if err != nil {
+			return fmt.Errorf("fetching %s: %w", kind.name, err)
+		}
+		all, err := sigs.Get()
+		if err != nil {
+			return fmt.Errorf("fetching %s: %w", kind.name, err)
+		}
+		if kind.max > 0 && len(all) > kind.max {
+			violations = append(violations, fmt.Sprintf("%d %s are attached, more than the %d of %s", len(all), kind.name, kind.max, kind.flag))
+		}
+		if q.MaxArtifactSize == 0 {
+			continue
+		}
+		for _, sig := range all {
+			size, err := sig.Size()
+			if err != nil {
+				return err
+			}
+			if size > q.MaxArtifactSize {
+				d, err := sig.Digest()
+				if err != nil {
+					return err
+				}
+				violations = append(violations, fmt.Sprintf("%s %s is %d bytes, larger than the %d of --max-artifact-size", strings.TrimSuffix(kind.name, "s"), d, size, q.MaxArtifactSize))
+			}
+		}
+	}
+
+	if len(q.RequirePredicateTypes) > 0 {
+		missing, err := missingPredicateTypes(ctx, ref, co, q.RequirePredicateTypes)
+		if err != nil {
+			return err
+		}
+		for _, t := range missing {
+			violations = append(violations, fmt.Sprintf("no verified attestation of predicate type %s", t))
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%s violates the artifact quota: %s", ref, strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// missingPredicateTypes returns the predicate types, of required, that ref
+// has no attestation of verified with co.
+func missingPredicateTypes(ctx context.Context, ref name.Reference, co *cosign.CheckOpts, required []string) ([]string, error) {
+	aco := *co
+	aco.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
+	aco.StopAfterVerified = 0
+	// An image without verified attestations is missing every type.
+	verified, _, err := cosign.VerifyImageAttestations(ctx, ref, &aco)
+	if err != nil && !errors.Is(err, cosign.ErrNoMatchingAttestations) {
+		return nil, fmt.Errorf("verifying attestations: %w", err)
+	}
+
+	var missing []string
+	for _, t := range required {
+		uri, err := options.ParsePredicateType(t)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, att := range verified {
+			if _, got, _ := policy.AttestationToPayloadJSON(ctx, uri, att); got == uri {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, uri)
+		}
+	}
+	return missing, nil
+}
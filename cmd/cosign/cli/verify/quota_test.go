@@ -0,0 +1,130 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestVerifyArtifactQuota(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, pub := filepath.Join(td, "cosign.key"), filepath.Join(td, "cosign.pub")
+	if err := os.WriteFile(priv, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pub, keys.PublicBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// The image has two signatures and a custom attestation.
+	ko := options.KeyOpts{KeyRef: priv, PassFunc: func(bool) ([]byte, error) { return nil, nil }}
+	for _, a := range []string{"build=1", "build=2"} {
+		so := options.SignOptions{Upload: true, AnnotationOptions: options.AnnotationOptions{Annotations: []string{a}}}
+		if err := sign.SignCmd(ctx, &options.RootOptions{Timeout: options.DefaultTimeout}, ko, so, []string{ref.String()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	predicate := filepath.Join(td, "predicate.json")
+	if err := os.WriteFile(predicate, []byte(`{"tests": "passed"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&attest.AttestCommand{KeyOpts: ko, PredicatePath: predicate, PredicateType: options.PredicateCustom}).Exec(ctx, ref.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		quota   options.ArtifactQuotaOptions
+		wantErr []string
+	}{{
+		name:  "within the quota",
+		quota: options.ArtifactQuotaOptions{MaxSignatures: 2, MaxAttestations: 1, MaxArtifactSize: 1 << 20, RequirePredicateTypes: []string{"custom"}},
+	}, {
+		name:    "too many signatures",
+		quota:   options.ArtifactQuotaOptions{MaxSignatures: 1},
+		wantErr: []string{"2 signatures are attached, more than the 1 of --max-signatures"},
+	}, {
+		name:    "bloated and under-attested",
+		quota:   options.ArtifactQuotaOptions{MaxArtifactSize: 10, RequirePredicateTypes: []string{"custom", "slsaprovenance"}},
+		wantErr: []string{"larger than the 10 of --max-artifact-size", "no verified attestation of predicate type https://slsa.dev/provenance/v0.2"},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &VerifyCommand{
+				KeyRef:      pub,
+				CheckClaims: true,
+				IgnoreTlog:  true,
+				IgnoreSCT:   true,
+				Quota:       tt.quota,
+			}
+			err := v.Exec(ctx, []string{ref.String()})
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("Exec() = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Exec() = nil, want an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Exec() = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// indexManifest is a manifest that a multi-arch image index points to.
+type indexManifest struct {
+	ref      name.Digest
+	platform string
+}
+
+func (m indexManifest) String() string {
+	return fmt.Sprintf("%s (%s)", m.platform, m.ref.DigestStr())
+}
+
+// manifestErrors is the error of an index whose manifests failed
+// verification, one for each manifest. It unwraps to the first of them, so
+// that the exit code is that of its failure.
+type manifestErrors struct {
+	index  name.Reference
+	total  int
+	failed []indexManifest
+	errs   []error
+}
+
+func (e *manifestErrors) Error() string {
+	lines := make([]string, 0, len(e.failed))
+	for i, m := range e.failed {
+		lines = append(lines, fmt.Sprintf("  %s: %v", m, e.errs[i]))
+	}
+	return fmt.Sprintf("%d of %d manifests of %s failed verification:\n%s", len(e.failed), e.total, e.index, strings.Join(lines, "\n"))
+}
+
+func (e *manifestErrors) Unwrap() error {
+	return e.errs[0]
+}
+
+// verifyRecursive wraps verify for --recursive: the manifests of a
+// multi-arch image index, and of the indexes nested in it, are verified as
+// well as the index itself, as cosign sign --recursive signs them. Every
+// manifest is verified, and all that fail are reported together, so that an
+// index is only verified if each of its platforms is.
+func verifyRecursive(verify verifyFunc) verifyFunc {
+	return func(ctx context.Context, ref name.Reference, co *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+		verified, bundleVerified, err := verify(ctx, ref, co)
+		if err != nil {
+			return nil, false, err
+		}
+
+		se, err := ociremote.SignedEntity(ref, co.RegistryClientOpts...)
+		if err != nil {
+			return nil, false, fmt.Errorf("accessing %s: %w", ref, err)
+		}
+		idx, ok := se.(oci.SignedImageIndex)
+		if !ok {
+			return verified, bundleVerified, nil
+		}
+		manifests, err := indexManifests(ref.Context(), idx)
+		if err != nil {
+			return nil, false, fmt.Errorf("listing the manifests of %s: %w", ref, err)
+		}
+
+		merr := &manifestErrors{index: ref, total: len(manifests)}
+		for _, m := range manifests {
+			v, bv, err := verify(ctx, m.ref, co)
+			if err != nil {
+				merr.failed = append(merr.failed, m)
+				merr.errs = append(merr.errs, err)
+				continue
+			}
+			ui.Infof(ctx, "Verified %d signature(s) for %s of %s", len(v), m, ref)
+			verified = append(verified, v...)
+			bundleVerified = bundleVerified && bv
+		}
+		if len(merr.failed) > 0 {
+			return nil, false, merr
+		}
+		return verified, bundleVerified, nil
+	}
+}
+
+// indexManifests returns the manifests of idx, in repo, and of the indexes
+// nested in it.
+func indexManifests(repo name.Repository, idx oci.SignedImageIndex) ([]indexManifest, error) {
+	im, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	var manifests []indexManifest
+	for _, desc := range im.Manifests {
+		platform := "unknown"
+		switch {
+		case desc.Platform != nil:
+			platform = desc.Platform.String()
+		case desc.ArtifactType != "":
+			platform = desc.ArtifactType
+		}
+		manifests = append(manifests, indexManifest{ref: repo.Digest(desc.Digest.String()), platform: platform})
+		if !desc.MediaType.IsIndex() {
+			continue
+		}
+		child, err := idx.SignedImageIndex(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		nested, err := indexManifests(repo, child)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, nested...)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,108 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+func TestVerifyRecursive(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer s.Close()
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var idx v1.ImageIndex = empty.Index
+	digests := map[string]string{}
+	for _, arch := range []string{"amd64", "arm64"} {
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digests[arch] = h.String()
+		idx = mutate.AppendManifests(idx, mutate.IndexAddendum{
+			Add:        img,
+			Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: arch}},
+		})
+	}
+	h, err := idx.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := repo.Digest(h.String())
+	if err := remote.WriteIndex(ref, idx); err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedRefs := map[string]bool{}
+	signed := func(unsigned ...string) verifyFunc {
+		return func(_ context.Context, ref name.Reference, _ *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+			d := ref.(name.Digest).DigestStr()
+			for _, u := range unsigned {
+				if d == u {
+					return nil, false, fmt.Errorf("%s: %w", ref, cosign.ErrNoSignaturesFound)
+				}
+			}
+			verifiedRefs[d] = true
+			return nil, true, nil
+		}
+	}
+
+	if _, bundleVerified, err := verifyRecursive(signed())(context.Background(), ref, &cosign.CheckOpts{}); err != nil || !bundleVerified {
+		t.Fatalf("verifying the signed index: %v", err)
+	}
+	for arch, d := range digests {
+		if !verifiedRefs[d] {
+			t.Errorf("the linux/%s manifest wasn't verified", arch)
+		}
+	}
+
+	_, _, err = verifyRecursive(signed(digests["arm64"]))(context.Background(), ref, &cosign.CheckOpts{})
+	if !errors.Is(err, cosign.ErrNoSignaturesFound) {
+		t.Fatalf("expected the index with an unsigned platform to have no signatures, got %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "1 of 2 manifests") || !strings.Contains(msg, "linux/arm64 ("+digests["arm64"]+")") || strings.Contains(msg, "linux/amd64") {
+		t.Errorf("expected the error to only list linux/arm64, got %v", msg)
+	}
+
+	if _, _, err := verifyRecursive(signed(h.String()))(context.Background(), ref, &cosign.CheckOpts{}); !errors.Is(err, cosign.ErrNoSignaturesFound) {
+		t.Errorf("expected the unsigned index to fail verification, got %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/regevents"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+const (
+	// maxNotificationSize is the size of the largest registry notification
+	// that is read.
+	maxNotificationSize = 10 << 20
+	// registryAuditQueue is the number of images that may wait to be
+	// verified before notifications are refused, for the registry to retry.
+	registryAuditQueue = 1000
+)
+
+// RegistryAuditResult is a result of verifying an image pushed to a
+// registry, as recorded in the results database of cosign audit registry.
+type RegistryAuditResult struct {
+	VerificationResultSubject
+	// Tag is the tag the image was pushed with, if any.
+	Tag        string    `json:"tag,omitempty"`
+	VerifiedAt time.Time `json:"verifiedAt"`
+}
+
+// AuditResults is the results database of cosign audit registry, a file
+// that a JSON line is appended to for each verification of an image, so the
+// last line of an image is its current result. It is safe for concurrent
+// use.
+type AuditResults struct {
+	mu     sync.Mutex
+	f      *os.File
+	latest map[string]RegistryAuditResult
+}
+
+// OpenAuditResults opens the results database at path, which is created if
+// it doesn't exist.
+func OpenAuditResults(path string) (*AuditResults, error) {
+	path = filepath.Clean(path)
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading audit results: %w", err)
+	}
+	r := &AuditResults{latest: map[string]RegistryAuditResult{}}
+	// A result is only recorded once its line is complete, an interrupted
+	// write leaves a partial last line that is ignored and terminated.
+	lines := bytes.Split(b, []byte("\n"))
+	for i, line := range lines[:len(lines)-1] {
+		if len(line) == 0 {
+			continue
+		}
+		var res RegistryAuditResult
+		if err := json.Unmarshal(line, &res); err != nil {
+			return nil, fmt.Errorf("parsing line %d of audit results %s: %w", i+1, path, err)
+		}
+		r.latest[res.Name] = res
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit results: %w", err)
+	}
+	if last := lines[len(lines)-1]; len(last) > 0 {
+		if _, err := f.WriteString("\n"); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("writing audit results: %w", err)
+		}
+	}
+	r.f = f
+	return r, nil
+}
+
+// Lookup returns the current result of image, by digest, if it was
+// verified.
+func (r *AuditResults) Lookup(image string) (RegistryAuditResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.latest[image]
+	return res, ok
+}
+
+// Record appends res to r.
+func (r *AuditResults) Record(res RegistryAuditResult) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("marshaling audit result: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("writing audit results: %w", err)
+	}
+	r.latest[res.Name] = res
+	return nil
+}
+
+// Close closes the file of r.
+func (r *AuditResults) Close() error {
+	return r.f.Close()
+}
+
+// RegistryAuditor is the webhook of the push notifications of registries,
+// see regevents, which verifies each newly pushed image as Verify does and
+// records its result in Results. An image is only verified again if it
+// didn't pass and a signature, attestation or SBOM is pushed for it, since
+// images are usually pushed before they are signed.
+type RegistryAuditor struct {
+	Verify  *VerifyCommand
+	Results *AuditResults
+	// Authorization, if set, is the Authorization header that
+	// notifications must have.
+	Authorization string
+	NameOptions   []name.Option
+
+	queue chan pushedImage
+	mu    sync.Mutex
+	// pending are the images queued or being verified, and whether they
+	// must be verified again, because they were signed meanwhile.
+	pending map[string]bool
+}
+
+// pushedImage is an image that a RegistryAuditor verifies.
+type pushedImage struct {
+	image, digest, tag string
+}
+
+var _ http.Handler = (*RegistryAuditor)(nil)
+
+// NewRegistryAuditor returns a RegistryAuditor that verifies images as c
+// does and records their results in results.
+func NewRegistryAuditor(c *VerifyCommand, results *AuditResults) *RegistryAuditor {
+	return &RegistryAuditor{
+		Verify:  c,
+		Results: results,
+		queue:   make(chan pushedImage, registryAuditQueue),
+		pending: map[string]bool{},
+	}
+}
+
+func (a *RegistryAuditor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "registry notifications must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	if a.Authorization != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(a.Authorization)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxNotificationSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	pushes, err := regevents.Parse(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, p := range pushes {
+		img, signed := pushedImage{image: p.Image(), digest: p.Digest, tag: p.Tag}, false
+		if signs := p.Signs(); signs != "" {
+			img, signed = pushedImage{image: signs}, true
+		}
+		ref, err := oci.ParseDigestReference(img.image, a.NameOptions...)
+		if err != nil {
+			ui.Warnf(r.Context(), "Ignoring the push of %s: %v", img.image, err)
+			continue
+		}
+		img.image, img.digest = ref.Name(), ref.DigestStr()
+		if !a.enqueue(img, signed) {
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, "too many images are waiting to be verified", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// enqueue queues img to be verified, unless it already was, or is queued.
+// With signed, a signature, attestation or SBOM was pushed for img, which
+// is verified again unless it passed. It returns false if the queue is full.
+func (a *RegistryAuditor) enqueue(img pushedImage, signed bool) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.pending[img.image]; ok {
+		a.pending[img.image] = a.pending[img.image] || signed
+		return true
+	}
+	if res, ok := a.Results.Lookup(img.image); ok && (!signed || res.Status == VerificationPassed) {
+		return true
+	}
+	select {
+	case a.queue <- img:
+		a.pending[img.image] = false
+		return true
+	default:
+		return false
+	}
+}
+
+// Run verifies the queued images, up to workers at a time, until ctx is
+// done.
+func (a *RegistryAuditor) Run(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case img := <-a.queue:
+					a.audit(ctx, img)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// audit verifies img, and again as long as it was signed while it was
+// verified.
+func (a *RegistryAuditor) audit(ctx context.Context, img pushedImage) {
+	quiet := ui.WithEnv(ctx, &ui.Env{Stderr: io.Discard, Stdin: os.Stdin})
+	for {
+		s := a.Verify.auditImage(quiet, img.image, img.digest)
+		s.Name = img.image
+		if err := a.Results.Record(RegistryAuditResult{VerificationResultSubject: s, Tag: img.tag, VerifiedAt: time.Now().UTC()}); err != nil {
+			ui.Warnf(ctx, "Recording the result of %s: %v", img.image, err)
+		}
+		if s.Status == VerificationPassed {
+			ui.Infof(ctx, "%s: %s", img.image, outcomes[s.Status])
+		} else {
+			ui.Warnf(ctx, "%s: %s: %s", img.image, outcomes[s.Status], s.Error)
+		}
+
+		a.mu.Lock()
+		again := a.pending[img.image]
+		if !again {
+			delete(a.pending, img.image)
+		} else {
+			a.pending[img.image] = false
+		}
+		a.mu.Unlock()
+		if !again || ctx.Err() != nil {
+			return
+		}
+	}
+}
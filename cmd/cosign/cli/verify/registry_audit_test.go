@@ -0,0 +1,143 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestRegistryAuditor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, pub := filepath.Join(td, "cosign.key"), filepath.Join(td, "cosign.pub")
+	if err := os.WriteFile(priv, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pub, keys.PublicBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	db := filepath.Join(td, "results.jsonl")
+	results, err := OpenAuditResults(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewRegistryAuditor(&VerifyCommand{KeyRef: pub, CheckClaims: true, IgnoreTlog: true, IgnoreSCT: true}, results)
+	a.Authorization = "Bearer secret"
+	go a.Run(ctx, 2)
+
+	notify := func(tag, authorization string) int {
+		t.Helper()
+		body := fmt.Sprintf(`{"events": [{"action": "push", "target": {"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": %q, "repository": "app", "tag": %q}, "request": {"host": %q}}]}`, h, tag, host)
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		a.ServeHTTP(w, req)
+		return w.Code
+	}
+	// await waits for the result of the image to have status.
+	await := func(status string) RegistryAuditResult {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			if res, ok := results.Lookup(ref.String()); ok && res.Status == status {
+				return res
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatalf("the result of %s never was %s", ref, status)
+		return RegistryAuditResult{}
+	}
+
+	if code := notify("v1", "Bearer wrong"); code != http.StatusUnauthorized {
+		t.Errorf("notification with the wrong authorization = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := notify("v1", "Bearer secret"); code != http.StatusAccepted {
+		t.Fatalf("notification = %d, want %d", code, http.StatusAccepted)
+	}
+	if res := await(VerificationFailed); res.Tag != "v1" || res.Digest != h.String() {
+		t.Errorf("result of the unsigned image = %+v", res)
+	}
+
+	ko := options.KeyOpts{KeyRef: priv, PassFunc: func(bool) ([]byte, error) { return nil, nil }}
+	if err := sign.SignCmd(ctx, &options.RootOptions{Timeout: options.DefaultTimeout}, ko, options.SignOptions{Upload: true}, []string{ref.String()}); err != nil {
+		t.Fatal(err)
+	}
+	// The notification of the push of the signature verifies the image again.
+	if code := notify(strings.Replace(h.String(), ":", "-", 1)+".sig", "Bearer secret"); code != http.StatusAccepted {
+		t.Fatalf("notification of the signature = %d, want %d", code, http.StatusAccepted)
+	}
+	await(VerificationPassed)
+	cancel()
+	if err := results.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The results survive, and images that passed aren't queued again.
+	results, err = OpenAuditResults(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { results.Close() })
+	if res, ok := results.Lookup(ref.String()); !ok || res.Status != VerificationPassed || len(res.Signatures) != 1 {
+		t.Errorf("reopened result of %s = %+v", ref, res)
+	}
+	a = NewRegistryAuditor(a.Verify, results)
+	if code := notify("v2", ""); code != http.StatusAccepted || len(a.queue) != 0 {
+		t.Errorf("notification of a verified image = %d with %d queued, want %d with none", code, len(a.queue), http.StatusAccepted)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+// VerificationReportPredicateType is the in-toto predicate type of signed
+// verification reports produced by `cosign verify --sign-report`.
+const VerificationReportPredicateType = "https://sigstore.dev/cosign/verification-report/v1"
+
+// VerificationReport records who verified what, when, and against which policy.
+type VerificationReport struct {
+	VerifiedAt    time.Time                  `json:"verifiedAt"`
+	CosignVersion string                     `json:"cosignVersion"`
+	Policy        VerificationReportPolicy   `json:"policy"`
+	Results       []VerificationReportResult `json:"results"`
+}
+
+// VerificationReportPolicy describes the checks the verification was performed against.
+type VerificationReportPolicy struct {
+	Key         string                 `json:"key,omitempty"`
+	Identities  []cosign.Identity      `json:"identities,omitempty"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+	CheckClaims bool                   `json:"checkClaims"`
+	IgnoreTlog  bool                   `json:"ignoreTlog"`
+	IgnoreSCT   bool                   `json:"ignoreSCT"`
+	Offline     bool                   `json:"offline"`
+}
+
+// VerificationReportResult records the signatures accepted for a single image.
+type VerificationReportResult struct {
+	Image      string                        `json:"image"`
+	Digest     string                        `json:"digest"`
+	Signatures []VerificationReportSignature `json:"signatures"`
+}
+
+// VerificationReportSignature describes a single accepted signature.
+type VerificationReportSignature struct {
+	Signature string `json:"signature"`
+	Subject   string `json:"subject,omitempty"`
+	Issuer    string `json:"issuer,omitempty"`
+	LogIndex  *int64 `json:"logIndex,omitempty"`
+}
+
+func newVerificationReport(c *VerifyCommand, co *cosign.CheckOpts) *VerificationReport {
+	return &VerificationReport{
+		VerifiedAt:    time.Now().UTC(),
+		CosignVersion: cosignVersion(),
+		Policy: VerificationReportPolicy{
+			Key:         c.KeyRef,
+			Identities:  co.Identities,
+			Annotations: co.Annotations,
+			CheckClaims: c.CheckClaims,
+			IgnoreTlog:  c.IgnoreTlog,
+			IgnoreSCT:   c.IgnoreSCT,
+			Offline:     c.Offline,
+		},
+	}
+}
+
+// add records the verified signatures for img.
+func (r *VerificationReport) add(img string, verified []oci.Signature) error {
+	result := VerificationReportResult{Image: img}
+	for _, sig := range verified {
+		p, err := sig.Payload()
+		if err != nil {
+			return err
+		}
+		sci := payload.SimpleContainerImage{}
+		if err := json.Unmarshal(p, &sci); err != nil {
+			return fmt.Errorf("decoding the payload: %w", err)
+		}
+		result.Digest = sci.Critical.Image.DockerManifestDigest
+
+		b64sig, err := sig.Base64Signature()
+		if err != nil {
+			return err
+		}
+		rs := VerificationReportSignature{Signature: b64sig}
+		if cert, err := sig.Cert(); err == nil && cert != nil {
+			ce := cosign.CertExtensions{Cert: cert}
+			rs.Subject = sigs.CertSubject(cert)
+			rs.Issuer = ce.GetIssuer()
+		}
+		if bundle, err := sig.Bundle(); err == nil && bundle != nil {
+			logIndex := bundle.Payload.LogIndex
+			rs.LogIndex = &logIndex
+		}
+		result.Signatures = append(result.Signatures, rs)
+	}
+	r.Results = append(r.Results, result)
+	return nil
+}
+
+// statement wraps the report in an in-toto statement whose subjects are the
+// verified images.
+func (r *VerificationReport) statement() in_toto.Statement {
+	st := in_toto.Statement{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          in_toto.StatementInTotoV01,
+			PredicateType: VerificationReportPredicateType,
+		},
+		Predicate: r,
+	}
+	for _, res := range r.Results {
+		alg, hex, ok := strings.Cut(res.Digest, ":")
+		if !ok {
+			continue
+		}
+		st.Subject = append(st.Subject, in_toto.Subject{
+			Name:   res.Image,
+			Digest: map[string]string{alg: hex},
+		})
+	}
+	return st
+}
+
+// signVerificationReport signs the report as a DSSE envelope over an in-toto
+// statement with ko, and writes the envelope to outputPath. Without a key in
+// ko, the report is signed keylessly, and the certificate and chain of the
+// signature are written to certPath.
+func signVerificationReport(ctx context.Context, r *VerificationReport, ko options.KeyOpts, outputPath, certPath string) error {
+	ko.PassFunc = generate.GetPass
+	sv, err := sign.SignerFromKeyOpts(ctx, "", "", ko)
+	if err != nil {
+		return fmt.Errorf("getting signer: %w", err)
+	}
+	defer sv.Close()
+	wrapped := dsse.WrapSigner(sv, types.IntotoPayloadType)
+
+	st, err := json.Marshal(r.statement())
+	if err != nil {
+		return err
+	}
+	envelope, err := wrapped.SignMessage(bytes.NewReader(st), signatureoptions.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("signing verification report: %w", err)
+	}
+	if err := os.WriteFile(outputPath, envelope, 0600); err != nil {
+		return fmt.Errorf("writing signed verification report: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Signed verification report written to %s\n", outputPath)
+	if sv.Cert != nil {
+		if err := os.WriteFile(certPath, append(sv.Cert, sv.Chain...), 0600); err != nil {
+			return fmt.Errorf("writing verification report certificate: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Verification report certificate written to %s\n", certPath)
+	}
+	return nil
+}
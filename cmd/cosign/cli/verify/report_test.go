@@ -0,0 +1,215 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+	ssldsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio/localca"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+func TestSignVerificationReport(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("COSIGN_PASSWORD", "")
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return nil, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(td, "verifier.key")
+	if err := os.WriteFile(keyPath, keys.PrivateBytes, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"example.com/app"},"image":{"docker-manifest-digest":"sha256:abcd"},"type":"cosign container image signature"},"optional":null}`)
+	sig, err := static.NewSignature(payload, "c2lnbmF0dXJl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &VerifyCommand{KeyRef: "cosign.pub", CheckClaims: true}
+	r := newVerificationReport(c, &cosign.CheckOpts{})
+	if err := r.add("example.com/app:latest", []oci.Signature{sig}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(td, "report.dsse.json")
+	if err := signVerificationReport(context.Background(), r, options.KeyOpts{KeyRef: keyPath}, out, ""); err != nil {
+		t.Fatalf("signVerificationReport() = %v", err)
+	}
+
+	raw, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := ssldsse.Envelope{}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		t.Fatalf("report is not a DSSE envelope: %v", err)
+	}
+	if len(env.Signatures) != 1 {
+		t.Fatalf("expected 1 signature on report, got %d", len(env.Signatures))
+	}
+	decoded, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := in_toto.Statement{}
+	if err := json.Unmarshal(decoded, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.PredicateType != VerificationReportPredicateType {
+		t.Errorf("predicate type = %s, want %s", st.PredicateType, VerificationReportPredicateType)
+	}
+	if len(st.Subject) != 1 || st.Subject[0].Digest["sha256"] != "abcd" || st.Subject[0].Name != "example.com/app:latest" {
+		t.Errorf("unexpected subjects: %+v", st.Subject)
+	}
+}
+
+// serveLocalCA serves a local CA on a unix socket that issues certificates
+// for foo@example.com under root to the identity token "token".
+func serveLocalCA(t *testing.T, root *x509.Certificate, rootKey *ecdsa.PrivateKey) string {
+	t.Helper()
+	// Unix socket paths are short, unlike those of t.TempDir.
+	dir, err := os.MkdirTemp("", "localca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "ca.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			req := localca.Request{}
+			resp := localca.Response{APIVersion: localca.APIVersion}
+			if err := json.NewDecoder(conn).Decode(&req); err != nil || req.IdentityToken != "token" {
+				resp.Error = "unauthenticated"
+			} else if block, _ := pem.Decode([]byte(req.CertificateSigningRequest)); block == nil {
+				resp.Error = "no CSR"
+			} else if csr, err := x509.ParseCertificateRequest(block.Bytes); err != nil {
+				resp.Error = err.Error()
+			} else {
+				pub := csr.PublicKey.(*ecdsa.PublicKey)
+				cert, _ := test.GenerateLeafCertWithExpiration("foo@example.com", "https://local-ca.example.com", time.Now().Add(-time.Minute),
+					&ecdsa.PrivateKey{PublicKey: *pub}, root, rootKey)
+				chain, _ := cryptoutils.MarshalCertificatesToPEM([]*x509.Certificate{cert, root})
+				resp.CertificateChain = string(chain)
+			}
+			_ = json.NewEncoder(conn).Encode(resp)
+			conn.Close()
+		}
+	}()
+	return "unix://" + socket
+}
+
+func TestSignVerificationReportKeyless(t *testing.T) {
+	td := t.TempDir()
+	root, rootKey, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &VerifyCommand{KeyRef: "cosign.pub", CheckClaims: true}
+	r := newVerificationReport(c, &cosign.CheckOpts{})
+
+	out := filepath.Join(td, "report.dsse.json")
+	certPath := filepath.Join(td, "report.pem")
+	tokenPath := filepath.Join(td, "token")
+	if err := os.WriteFile(tokenPath, []byte("token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ko := options.KeyOpts{FulcioURL: serveLocalCA(t, root, rootKey), IDToken: tokenPath}
+	if err := signVerificationReport(context.Background(), r, ko, out, certPath); err != nil {
+		t.Fatalf("signVerificationReport() = %v", err)
+	}
+
+	chain, err := os.ReadFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(chain)
+	if err != nil || len(certs) != 2 || !certs[1].Equal(root) {
+		t.Fatalf("report certificate = %s, %v, want the certificate and its root", chain, err)
+	}
+	if len(certs[0].EmailAddresses) != 1 || certs[0].EmailAddresses[0] != "foo@example.com" {
+		t.Errorf("report certificate identity = %v, want foo@example.com", certs[0].EmailAddresses)
+	}
+	verifier, err := signature.LoadVerifier(certs[0].PublicKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dsse.WrapVerifier(verifier).VerifySignature(bytes.NewReader(envelope), nil); err != nil {
+		t.Errorf("report signature doesn't verify with its certificate: %v", err)
+	}
+	env := ssldsse.Envelope{}
+	if err := json.Unmarshal(envelope, &env); err != nil || env.PayloadType != types.IntotoPayloadType {
+		t.Errorf("report = %s, %v, want an in-toto DSSE envelope", envelope, err)
+	}
+}
+
+func TestSignReportCertificateRequired(t *testing.T) {
+	for name, tc := range map[string]struct {
+		c    VerifyCommand
+		want string
+	}{
+		"keyless without a certificate file": {
+			c:    VerifyCommand{SignReport: "report.dsse.json"},
+			want: "--sign-report-certificate is required",
+		},
+		"key with a certificate file": {
+			c:    VerifyCommand{SignReport: "report.dsse.json", SignReportKeyOpts: options.KeyOpts{KeyRef: "verifier.key"}, SignReportCertificate: "report.pem"},
+			want: "can't be used with --sign-report-key",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.c.Exec(context.Background(), []string{"example.com/app"}); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Exec() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// execRouted verifies each of images with the command c.Route returns for
+// it, recording their results in results, if any, and the image being
+// verified in current.
+func (c *VerifyCommand) execRouted(ctx context.Context, images []string, results *VerificationResult, current *string) error {
+	if c.LocalImage || c.BatchVerify.File != "" || c.OfflineBundle.BundleFile != "" || c.SignReport != "" {
+		return errors.New("a verification policy can't be used with --local-image, --batch-file, --bundle-file or --sign-report")
+	}
+	if len(images) == 0 {
+		return flag.ErrHelp
+	}
+	for _, img := range images {
+		*current = img
+		ref, err := oci.ParseReference(img, c.NameOptions...)
+		if err != nil {
+			return fmt.Errorf("parsing reference: %w", err)
+		}
+		v, err := c.Route(ctx, ref)
+		if err != nil {
+			return err
+		}
+		v.Route = nil
+		v.results = results
+		if err := v.Exec(ctx, []string{img}); err != nil {
+			return err
+		}
+	}
+	*current = ""
+	return nil
+}
@@ -0,0 +1,74 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+)
+
+// SignerPolicy is the --verify-policy of cosign load and cosign copy: the key
+// or certificate identity the signatures of an image must be verified with
+// before it is written to a registry.
+type SignerPolicy struct {
+	Key                         string `json:"key,omitempty"`
+	CertificateIdentity         string `json:"certificateIdentity,omitempty"`
+	CertificateIdentityRegexp   string `json:"certificateIdentityRegexp,omitempty"`
+	CertificateOIDCIssuer       string `json:"certificateOidcIssuer,omitempty"`
+	CertificateOIDCIssuerRegexp string `json:"certificateOidcIssuerRegexp,omitempty"`
+	IgnoreTlog                  bool   `json:"ignoreTlog,omitempty"`
+	IgnoreSCT                   bool   `json:"ignoreSCT,omitempty"`
+}
+
+// ReadSignerPolicy reads the signer policy at path, which must set a key or a
+// certificate identity.
+func ReadSignerPolicy(path string) (*SignerPolicy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &SignerPolicy{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing verification policy %s: %w", path, err)
+	}
+	if p.Key == "" && p.CertificateIdentity == "" && p.CertificateIdentityRegexp == "" {
+		return nil, fmt.Errorf("verification policy %s must set a key or a certificate identity", path)
+	}
+	return p, nil
+}
+
+// Command returns the command that verifies the signatures of images with p.
+func (p *SignerPolicy) Command() *VerifyCommand {
+	return &VerifyCommand{
+		CertVerifyOptions: options.CertVerifyOptions{
+			CertIdentity:         p.CertificateIdentity,
+			CertIdentityRegexp:   p.CertificateIdentityRegexp,
+			CertOidcIssuer:       p.CertificateOIDCIssuer,
+			CertOidcIssuerRegexp: p.CertificateOIDCIssuerRegexp,
+		},
+		KeyRef:      p.Key,
+		CheckClaims: true,
+		IgnoreTlog:  p.IgnoreTlog,
+		IgnoreSCT:   p.IgnoreSCT,
+		Output:      "text",
+	}
+}
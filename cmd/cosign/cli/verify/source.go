@@ -0,0 +1,90 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+type verifyFunc func(context.Context, name.Reference, *cosign.CheckOpts) ([]oci.Signature, bool, error)
+
+// verifyWithSourceRepositories runs verify for ref and, for images that were
+// promoted by digest from another registry, for the same digest in each of
+// sourceRepos. The verified signatures are merged, so verification succeeds
+// if any of the repositories holds a valid signature.
+//
+// If no repository holds a valid signature, the error for ref is returned.
+func verifyWithSourceRepositories(ctx context.Context, ref name.Reference, co *cosign.CheckOpts, sourceRepos []string, nameOpts []name.Option, verify verifyFunc) ([]oci.Signature, bool, error) {
+	verified, bundleVerified, err := verify(ctx, ref, co)
+	if len(sourceRepos) == 0 {
+		return verified, bundleVerified, err
+	}
+	firstErr := err
+	anyVerified := err == nil
+
+	digest, err := ociremote.ResolveDigest(ref, co.RegistryClientOpts...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	seen := map[string]struct{}{}
+	for _, sig := range verified {
+		if b64sig, err := sig.Base64Signature(); err == nil {
+			seen[b64sig] = struct{}{}
+		}
+	}
+
+	for _, repo := range sourceRepos {
+		sourceRepo, err := name.NewRepository(repo, nameOpts...)
+		if err != nil {
+			return nil, false, fmt.Errorf("parsing source repository %s: %w", repo, err)
+		}
+		sourceCo := *co
+		sourceCo.RegistryClientOpts = append(append([]ociremote.Option{}, co.RegistryClientOpts...), ociremote.WithTargetRepository(sourceRepo))
+
+		sourceVerified, sourceBundleVerified, err := verify(ctx, digest, &sourceCo)
+		if err != nil {
+			ui.Infof(ctx, "No valid signatures for %s found in source repository %s: %v", digest.DigestStr(), sourceRepo, err)
+			continue
+		}
+		ui.Infof(ctx, "Verified %d signature(s) for %s in source repository %s", len(sourceVerified), digest.DigestStr(), sourceRepo)
+
+		bundleVerified = sourceBundleVerified && (bundleVerified || !anyVerified)
+		anyVerified = true
+		for _, sig := range sourceVerified {
+			b64sig, err := sig.Base64Signature()
+			if err != nil {
+				return nil, false, err
+			}
+			if _, dup := seen[b64sig]; dup {
+				continue
+			}
+			seen[b64sig] = struct{}{}
+			verified = append(verified, sig)
+		}
+	}
+
+	if !anyVerified {
+		return nil, false, firstErr
+	}
+	return verified, bundleVerified, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestVerifyWithSourceRepositories(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	ref, err := name.NewDigest("mirror.example.com/app@" + digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSig := func(s string) oci.Signature {
+		sig, err := static.NewSignature([]byte("payload"), s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+	errNoSigs := errors.New("no signatures in mirror")
+
+	tests := []struct {
+		name    string
+		sources []string
+		// The fake tells source lookups apart from the mirror lookup by
+		// the target repository option added to their CheckOpts.
+		mirror, source []oci.Signature
+		mirrorErr      error
+		sourceErr      error
+		want           int
+		wantErr        error
+	}{{
+		name:      "mirror only",
+		mirrorErr: errNoSigs,
+		wantErr:   errNoSigs,
+	}, {
+		name:      "signatures only in source",
+		sources:   []string{"registry.example.com/team/app"},
+		mirrorErr: errNoSigs,
+		source:    []oci.Signature{newSig("c2ln")},
+		want:      1,
+	}, {
+		name:    "signatures merged without duplicates",
+		sources: []string{"registry.example.com/team/app"},
+		mirror:  []oci.Signature{newSig("c2ln")},
+		source:  []oci.Signature{newSig("c2ln"), newSig("b3RoZXI=")},
+		want:    2,
+	}, {
+		name:      "no signatures anywhere",
+		sources:   []string{"registry.example.com/team/app"},
+		mirrorErr: errNoSigs,
+		sourceErr: errors.New("no signatures in source"),
+		wantErr:   errNoSigs,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verify := func(_ context.Context, r name.Reference, co *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+				if r.String() != ref.String() {
+					t.Errorf("verified %s, want %s", r, ref)
+				}
+				if len(co.RegistryClientOpts) == 0 {
+					return tt.mirror, false, tt.mirrorErr
+				}
+				return tt.source, false, tt.sourceErr
+			}
+			got, _, err := verifyWithSourceRepositories(context.Background(), ref, &cosign.CheckOpts{}, tt.sources, nil, verify)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verifyWithSourceRepositories() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("got %d signatures, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	if _, _, err := verifyWithSourceRepositories(context.Background(), ref, &cosign.CheckOpts{}, []string{"Invalid Repo"}, nil,
+		func(context.Context, name.Reference, *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+			return nil, false, nil
+		}); err == nil {
+		t.Error("expected an error for an invalid source repository")
+	}
+}
@@ -0,0 +1,99 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sigstore/cosign/v2/pkg/blob"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// maxStdinInputSize bounds the bundle, certificate or signature read from
+// standard input, which is buffered in memory.
+const maxStdinInputSize = 10 << 20
+
+// stdin is replaced in tests.
+var stdin io.Reader = os.Stdin
+
+// blobInputs are the bundle, certificate and signature inputs of
+// verify-blob and verify-blob-attestation. At most one of them, or the blob
+// itself, may be given as - to read it from standard input. It is buffered,
+// since the bundle is read more than once.
+type blobInputs struct {
+	stdin []byte
+}
+
+// newBlobInputs reads standard input if one of refs is -. blobRef is only
+// checked, the blob itself is streamed from standard input.
+func newBlobInputs(blobRef string, refs ...string) (*blobInputs, error) {
+	fromStdin := 0
+	for _, ref := range append([]string{blobRef}, refs...) {
+		if ref == "-" {
+			fromStdin++
+		}
+	}
+	if fromStdin > 1 {
+		return nil, errors.New("only one of the blob, --bundle, --certificate and --signature can be read from standard input")
+	}
+
+	in := &blobInputs{}
+	if fromStdin == 0 || blobRef == "-" {
+		return in, nil
+	}
+	b, err := io.ReadAll(io.LimitReader(stdin, maxStdinInputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading standard input: %w", err)
+	}
+	if len(b) > maxStdinInputSize {
+		return nil, fmt.Errorf("standard input is larger than %d bytes", maxStdinInputSize)
+	}
+	in.stdin = b
+	return in, nil
+}
+
+// load returns the contents of ref, which is - for standard input, a path
+// or a URL.
+func (in *blobInputs) load(ref string) ([]byte, error) {
+	if ref == "-" {
+		return in.stdin, nil
+	}
+	return blob.LoadFileOrURL(ref)
+}
+
+func (in *blobInputs) loadBundle(ref string) (*cosign.LocalSignedPayload, error) {
+	if ref != "-" {
+		return cosign.FetchLocalSignedPayloadFromPath(ref)
+	}
+	var b *cosign.LocalSignedPayload
+	if err := json.Unmarshal(in.stdin, &b); err != nil {
+		return nil, fmt.Errorf("parsing bundle from standard input: %w", err)
+	}
+	return b, nil
+}
+
+func (in *blobInputs) loadCert(ref string) (*x509.Certificate, error) {
+	pems, err := in.load(ref)
+	if err != nil {
+		return nil, err
+	}
+	return loadCertFromPEM(pems)
+}
@@ -0,0 +1,134 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+func withStdin(t *testing.T, r io.Reader) {
+	t.Helper()
+	old := stdin
+	stdin = r
+	t.Cleanup(func() { stdin = old })
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	panic("standard input was read")
+}
+
+func TestNewBlobInputs(t *testing.T) {
+	withStdin(t, strings.NewReader("signature"))
+	in, err := newBlobInputs("blob", "", "", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := in.load("-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "signature" {
+		t.Errorf("load(-) = %q, want %q", got, "signature")
+	}
+}
+
+func TestNewBlobInputsConflict(t *testing.T) {
+	withStdin(t, failingReader{})
+	for _, refs := range [][]string{
+		{"-", "-", "", ""},
+		{"blob", "-", "-", ""},
+		{"-", "", "", "-"},
+	} {
+		if _, err := newBlobInputs(refs[0], refs[1:]...); err == nil || !strings.Contains(err.Error(), "only one of") {
+			t.Errorf("newBlobInputs(%q) = %v, want conflict error", refs, err)
+		}
+	}
+}
+
+func TestNewBlobInputsBlobFromStdin(t *testing.T) {
+	// The blob is streamed by the caller, so newBlobInputs must not read it.
+	withStdin(t, failingReader{})
+	if _, err := newBlobInputs("-", "bundle.json", "", ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewBlobInputsTooLarge(t *testing.T) {
+	withStdin(t, bytes.NewReader(make([]byte, maxStdinInputSize+1)))
+	if _, err := newBlobInputs("blob", "-"); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("newBlobInputs() = %v, want size error", err)
+	}
+
+	withStdin(t, bytes.NewReader(make([]byte, maxStdinInputSize)))
+	if _, err := newBlobInputs("blob", "-"); err != nil {
+		t.Errorf("newBlobInputs() = %v, want nil", err)
+	}
+}
+
+func TestBlobInputsLoadBundle(t *testing.T) {
+	withStdin(t, strings.NewReader(`{"base64Signature":"c2ln","cert":"Y2VydA=="}`))
+	in, err := newBlobInputs("blob", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := in.loadBundle("-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Base64Signature != "c2ln" || b.Cert != "Y2VydA==" {
+		t.Errorf("loadBundle(-) = %+v", b)
+	}
+
+	withStdin(t, strings.NewReader("not json"))
+	in, err = newBlobInputs("blob", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := in.loadBundle("-"); err == nil {
+		t.Error("loadBundle(-) succeeded on invalid JSON")
+	}
+}
+
+func TestBlobInputsLoadCert(t *testing.T) {
+	rootCert, _, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemBytes, err := cryptoutils.MarshalCertificateToPEM(rootCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withStdin(t, bytes.NewReader(pemBytes))
+	in, err := newBlobInputs("blob", "", "-", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := in.loadCert("-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cert.Equal(rootCert) {
+		t.Error("loadCert(-) returned a different certificate")
+	}
+}
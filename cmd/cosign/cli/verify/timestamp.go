@@ -0,0 +1,73 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// tsaCertChainPaths returns the paths of the TSA certificate chains, with the
+// deprecated single path, if set, appended.
+func tsaCertChainPaths(paths []string, path string) []string {
+	if path == "" {
+		return paths
+	}
+	for _, p := range paths {
+		if p == path {
+			return paths
+		}
+	}
+	return append(append([]string(nil), paths...), path)
+}
+
+// loadTSACertificateChains sets the timestamp authority certificates of co
+// to those of the PEM certificate chain files at paths. Each timestamp is
+// verified with the chain that issued it, so that the timestamps of a TSA
+// that rotated its intermediate verify with the chains from before and after
+// the rotation.
+func loadTSACertificateChains(co *cosign.CheckOpts, paths []string) error {
+	for i, path := range paths {
+		// TODO: Add support for TUF certificates.
+		pemBytes, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return fmt.Errorf("unable to open timestamp certificate chain file %s: %w", path, err)
+		}
+		leaves, intermediates, roots, err := tsa.SplitPEMCertificateChain(pemBytes)
+		if err != nil {
+			return fmt.Errorf("error splitting certificates of %s: %w", path, err)
+		}
+		if len(leaves) > 1 {
+			return fmt.Errorf("certificate chain %s must contain at most one TSA certificate", path)
+		}
+		chain := cosign.TSACertificateChain{Intermediates: intermediates, Roots: roots}
+		if len(leaves) == 1 {
+			chain.Certificate = leaves[0]
+		}
+		if i > 0 {
+			co.TSACertificateChains = append(co.TSACertificateChains, chain)
+			continue
+		}
+		co.TSACertificate = chain.Certificate
+		co.TSAIntermediateCertificates = chain.Intermediates
+		co.TSARootCertificates = chain.Roots
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+)
+
+// loadCertChain loads the --certificate-chain at ref, which is a file or URL
+// of PEM certificates, the PKCS11 URI of a CA certificate stored in an HSM, or
+// the URI of a CA key in a KMS. A CA key isn't a chain, and is returned as the
+// trust anchor key instead, so that the roots never need to be stored as files.
+func loadCertChain(ctx context.Context, ref string) ([]*x509.Certificate, crypto.PublicKey, error) {
+	if strings.HasPrefix(ref, pkcs11key.ReferenceScheme) {
+		config := pkcs11key.NewPkcs11UriConfig()
+		if err := config.Parse(ref); err != nil {
+			return nil, nil, fmt.Errorf("parsing pkcs11 uri: %w", err)
+		}
+		cert, err := pkcs11key.GetCertificateWithURIConfig(config, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading certificate chain from pkcs11 token: %w", err)
+		}
+		return []*x509.Certificate{cert}, nil, nil
+	}
+
+	sv, err := kms.Get(ctx, ref, crypto.SHA256)
+	if err == nil {
+		pub, err := sv.PublicKey()
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting the trust anchor key from KMS: %w", err)
+		}
+		return nil, pub, nil
+	}
+	var perr *kms.ProviderNotFoundError
+	if !errors.As(err, &perr) {
+		return nil, nil, fmt.Errorf("loading the trust anchor key from KMS: %w", err)
+	}
+	chain, err := loadCertChainFromFileOrURL(ref)
+	return chain, nil, err
+}
+
+// setTrustAnchorKey trusts the certificates issued with the CA key pub.
+func setTrustAnchorKey(co *cosign.CheckOpts, pub crypto.PublicKey) {
+	co.TrustAnchorKeys = []crypto.PublicKey{pub}
+	if co.RootCerts == nil {
+		co.RootCerts = x509.NewCertPool()
+	}
+}
@@ -0,0 +1,68 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature/kms/fake"
+)
+
+func TestLoadCertChain(t *testing.T) {
+	rootCert, rootKey, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The CA key is in KMS.
+	ctx := context.WithValue(context.Background(), fake.KmsCtxKey{}, rootKey)
+	chain, anchorKey, err := loadCertChain(ctx, fake.ReferenceScheme+"ca")
+	if err != nil {
+		t.Fatalf("loadCertChain() = %v", err)
+	}
+	if chain != nil || !rootKey.PublicKey.Equal(anchorKey.(*ecdsa.PublicKey)) {
+		t.Errorf("loadCertChain() = %v, %v, want the CA key", chain, anchorKey)
+	}
+	co := &cosign.CheckOpts{}
+	setTrustAnchorKey(co, anchorKey)
+	if co.RootCerts == nil || len(co.TrustAnchorKeys) != 1 {
+		t.Errorf("setTrustAnchorKey() set %+v", co)
+	}
+
+	// The chain is in a file.
+	pemBytes, err := cryptoutils.MarshalCertificateToPEM(rootCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "chain.pem")
+	if err := os.WriteFile(path, pemBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	chain, anchorKey, err = loadCertChain(context.Background(), path)
+	if err != nil {
+		t.Fatalf("loadCertChain() = %v", err)
+	}
+	if len(chain) != 1 || !chain[0].Equal(rootCert) || anchorKey != nil {
+		t.Errorf("loadCertChain() = %v, %v, want the root certificate", chain, anchorKey)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+)
+
+type tufTargetFunc func(ctx context.Context, name string) ([]byte, error)
+
+// fetchTUFPolicies returns policies with each tuf://<target> entry replaced
+// by a local copy of the target, keeping its file name so that the policy
+// format can still be told from the extension. The returned function removes
+// the copies.
+func fetchTUFPolicies(ctx context.Context, policies []string, getTarget tufTargetFunc) ([]string, func(), error) {
+	resolved := make([]string, 0, len(policies))
+	dir := ""
+	cleanup := func() {
+		if dir != "" {
+			os.RemoveAll(dir)
+		}
+	}
+	for i, p := range policies {
+		target, ok := cosign.TUFTargetName(p)
+		if !ok {
+			resolved = append(resolved, p)
+			continue
+		}
+		b, err := getTarget(ctx, target)
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("fetching policy %s: %w", p, err)
+		}
+		if dir == "" {
+			if dir, err = os.MkdirTemp("", "cosign-policies"); err != nil {
+				return nil, nil, err
+			}
+		}
+		// Targets in different directories may share a file name.
+		local := filepath.Join(dir, strconv.Itoa(i), path.Base(target))
+		if err := os.MkdirAll(filepath.Dir(local), 0o700); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		if err := os.WriteFile(local, b, 0o600); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		ui.Infof(ctx, "Using policy %s from TUF", target)
+		resolved = append(resolved, local)
+	}
+	return resolved, cleanup, nil
+}
+
+// loadTUFDenylist loads the denylist published as target in TUF. The TUF
+// metadata authenticates the denylist, so a denylist key is optional. If key
+// is set, the denylist must also have a detached signature by it, which
+// defaults to the <target>.sig target.
+func loadTUFDenylist(ctx context.Context, target, key, signature string, getTarget tufTargetFunc) (*cosign.Denylist, error) {
+	raw, err := getTarget(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("fetching denylist: %w", err)
+	}
+	if key == "" {
+		return cosign.ParseTUFDenylist(raw)
+	}
+
+	verifier, err := sigs.PublicKeyFromKeyRef(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("loading denylist key: %w", err)
+	}
+	var b64sig []byte
+	if sigTarget, ok := cosign.TUFTargetName(signature); ok || signature == "" {
+		if signature == "" {
+			sigTarget = target + ".sig"
+		}
+		b64sig, err = getTarget(ctx, sigTarget)
+	} else {
+		b64sig, err = os.ReadFile(signature)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading denylist signature: %w", err)
+	}
+	return cosign.ParseDenylist(raw, b64sig, verifier)
+}
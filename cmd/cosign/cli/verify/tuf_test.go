@@ -0,0 +1,167 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// fakeTUF serves targets from a map.
+func fakeTUF(targets map[string]string) tufTargetFunc {
+	return func(_ context.Context, name string) ([]byte, error) {
+		t, ok := targets[name]
+		if !ok {
+			return nil, fmt.Errorf("target %s not found", name)
+		}
+		return []byte(t), nil
+	}
+}
+
+func TestFetchTUFPolicies(t *testing.T) {
+	ctx := context.Background()
+	getTarget := fakeTUF(map[string]string{
+		"policies/release.rego": "package signature",
+		"release.rego":          "package other",
+	})
+
+	got, cleanup, err := fetchTUFPolicies(ctx, []string{"local.cue", "tuf://policies/release.rego", "tuf://release.rego"}, getTarget)
+	if err != nil {
+		t.Fatalf("fetchTUFPolicies() unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[0] != "local.cue" {
+		t.Fatalf("fetchTUFPolicies() = %v, want the local policy kept", got)
+	}
+	for i, want := range []string{"package signature", "package other"} {
+		local := got[i+1]
+		if filepath.Base(local) != "release.rego" {
+			t.Errorf("policy %d copied to %s, want the target file name kept", i+1, local)
+		}
+		if b, err := os.ReadFile(local); err != nil || string(b) != want {
+			t.Errorf("policy %d = %q, %v, want %q", i+1, b, err, want)
+		}
+	}
+	cleanup()
+	if _, err := os.Stat(got[1]); !os.IsNotExist(err) {
+		t.Errorf("cleanup() left %s behind", got[1])
+	}
+
+	if _, _, err := fetchTUFPolicies(ctx, []string{"tuf://missing.rego"}, getTarget); err == nil {
+		t.Error("fetchTUFPolicies() with a missing target: expected an error")
+	}
+}
+
+func TestLoadTUFDenylist(t *testing.T) {
+	ctx := context.Background()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := filepath.Join(t.TempDir(), "denylist.pub")
+	if err := os.WriteFile(key, pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	raw := `{"digests":["sha256:abc"]}`
+	sig, err := sv.SignMessage(bytes.NewReader([]byte(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64sig := base64.StdEncoding.EncodeToString(sig)
+	sigPath := filepath.Join(t.TempDir(), "denylist.sig")
+	if err := os.WriteFile(sigPath, []byte(b64sig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	getTarget := fakeTUF(map[string]string{
+		"denylist.json":     raw,
+		"denylist.json.sig": b64sig,
+		"other.sig":         b64sig,
+		"unsigned.json":     raw,
+	})
+	denied := v1.Hash{Algorithm: "sha256", Hex: "abc"}
+
+	tests := []struct {
+		name      string
+		target    string
+		key       string
+		signature string
+		wantErr   bool
+	}{{
+		name:   "trusted through TUF",
+		target: "denylist.json",
+	}, {
+		name:   "signature target",
+		target: "denylist.json",
+		key:    key,
+	}, {
+		name:      "named signature target",
+		target:    "denylist.json",
+		key:       key,
+		signature: "tuf://other.sig",
+	}, {
+		name:      "signature file",
+		target:    "denylist.json",
+		key:       key,
+		signature: sigPath,
+	}, {
+		name:    "missing signature",
+		target:  "unsigned.json",
+		key:     key,
+		wantErr: true,
+	}, {
+		name:    "missing target",
+		target:  "missing.json",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := loadTUFDenylist(ctx, tt.target, tt.key, tt.signature, getTarget)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("loadTUFDenylist() expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadTUFDenylist() unexpected error: %v", err)
+			}
+			if d.CheckDigest(denied) == nil {
+				t.Error("loaded denylist doesn't deny sha256:abc")
+			}
+		})
+	}
+}
@@ -29,15 +29,16 @@ import (
 	"path/filepath"
 
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
 	cosignError "github.com/sigstore/cosign/v2/cmd/cosign/errors"
-	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
+	"github.com/sigstore/cosign/v2/internal/pkg/batch"
+	"github.com/sigstore/cosign/v2/internal/pkg/profile"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/blob"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/featuregates"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pivkey"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
 	"github.com/sigstore/cosign/v2/pkg/oci"
@@ -67,6 +68,8 @@ type VerifyCommand struct {
 	Sk                           bool
 	Slot                         string
 	Output                       string
+	OutputTemplate               string
+	GitHubSummary                bool
 	RekorURL                     string
 	Attachment                   string
 	Annotations                  sigs.AnnotationsMap
@@ -76,12 +79,93 @@ type VerifyCommand struct {
 	LocalImage                   bool
 	NameOptions                  []name.Option
 	Offline                      bool
-	TSACertChainPath             string
+	TSACertChainPaths            []string
 	IgnoreTlog                   bool
+	Denylist                     options.DenylistOptions
+	KeyUsagePolicy               string
+	Witnesses                    options.WitnessOptions
+	WarningsAsErrors             bool
+	SignReport                   string
+	SignReportKeyOpts            options.KeyOpts
+	SignReportCertificate        string
+	SourceRepositories           []string
+	AllowConverted               bool
+	Recursive                    bool
+	EnforceExpiry                bool
+	Encryption                   options.EncryptionOptions
+	Countersigners               options.CountersignerOptions
+	Batch                        options.BatchOptions
+	BatchVerify                  options.VerifyBatchOptions
+	Cache                        options.VerifyCacheOptions
+	Evaluation                   options.SignatureEvaluationOptions
+	OfflineBundle                options.OfflineBundleOptions
+	// Quota caps the signatures and attestations attached to each image,
+	// and sets the predicate types it must have verified attestations of.
+	Quota options.ArtifactQuotaOptions
+	// LayerPolicy is the path of a LayerPolicy that the layers of each
+	// image must also satisfy.
+	LayerPolicy string
+	// OnVerified, if set, is called with the verified signatures of each
+	// image instead of printing them.
+	OnVerified func(ctx context.Context, ref name.Reference, verified []oci.Signature) error
+	// Route, if set, returns the command that verifies each image, e.g. with
+	// the policy entry for its repository and metadata, instead of c.
+	Route func(ctx context.Context, ref name.Reference) (*VerifyCommand, error)
+	// AnyRegistry verifies images outside the registries of the --profile
+	// too, such as the sources of cosign copy.
+	AnyRegistry bool
+	// Deprecated: TSACertChainPath is added to TSACertChainPaths. Use
+	// TSACertChainPaths.
+	TSACertChainPath string
+	// results, if set, collects the verification results of a parent
+	// command instead of writing them.
+	results *VerificationResult
 }
 
 // Exec runs the verification command
 func (c *VerifyCommand) Exec(ctx context.Context, images []string) (err error) {
+	// With --output json-v1 or sarif, --output-template or --github-summary,
+	// the results are written even if the verification fails, with the
+	// image that failed.
+	results, current := c.results, ""
+	if results == nil {
+		if results, err = newCommandResult("verify", c.Output, c.OutputTemplate, c.GitHubSummary); err != nil {
+			return err
+		}
+		if results != nil {
+			defer func() {
+				err = results.finish(os.Stdout, c.Output, current, err)
+			}()
+		}
+	}
+	if images, err = expandWorkloads(ctx, images, c.LocalImage, c.NameOptions); err != nil {
+		return err
+	}
+	if c.Route != nil {
+		return c.execRouted(ctx, images, results, &current)
+	}
+	if c.OfflineBundle.BundleFile != "" && (c.LocalImage || c.BatchVerify.File != "" || c.Recursive || c.Attachment != "" ||
+		c.SignatureRef != "" || c.AllowConverted || len(c.SourceRepositories) > 0 || c.Countersigners.Enabled() || c.OnVerified != nil) {
+		return errors.New("--bundle-file can't be used with --local-image, --batch-file, --recursive, --attachment, --signature, " +
+			"--allow-converted, --source-repository or countersignatures")
+	}
+	offlineBundle, trustedRoot, images, err := loadOfflineBundle(c.OfflineBundle, images, c.NameOptions, keylessVerification(c.KeyRef, c.Sk) || !c.IgnoreTlog)
+	if err != nil {
+		return err
+	}
+	if c.BatchVerify.File != "" {
+		if c.LocalImage {
+			return errors.New("--batch-file can't be used with --local-image")
+		}
+		batchImages, err := readBatchFile(c.BatchVerify.File)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", c.BatchVerify.File, err)
+		}
+		if batchImages, err = expandWorkloads(ctx, batchImages, false, c.NameOptions); err != nil {
+			return err
+		}
+		images = append(images, batchImages...)
+	}
 	if len(images) == 0 {
 		return flag.ErrHelp
 	}
@@ -93,6 +177,36 @@ func (c *VerifyCommand) Exec(ctx context.Context, images []string) (err error) {
 		return flag.ErrHelp
 	}
 
+	if c.SignReport != "" {
+		switch keyless := c.SignReportKeyOpts.KeyRef == ""; {
+		case keyless && c.SignReportCertificate == "":
+			return errors.New("--sign-report-certificate is required to sign the --sign-report keylessly, without --sign-report-key")
+		case !keyless && c.SignReportCertificate != "":
+			return errors.New("--sign-report-certificate can't be used with --sign-report-key")
+		}
+	}
+	if c.LocalImage && (c.Countersigners.Enabled() || c.OnVerified != nil) {
+		return errors.New("countersignatures can't be verified with --local-image")
+	}
+	if c.Recursive && (c.LocalImage || c.Attachment != "") {
+		return errors.New("--recursive can't be used with --local-image or --attachment")
+	}
+	if c.Quota.Enabled() && (c.LocalImage || offlineBundle != nil) {
+		return errors.New("--max-signatures, --max-attestations, --max-artifact-size and --require-predicate-type can't be used with --local-image or --bundle-file")
+	}
+	var layerPolicy *LayerPolicy
+	if c.LayerPolicy != "" {
+		if err := featuregates.Require(featuregates.LayerSignatures); err != nil {
+			return err
+		}
+		if c.LocalImage || offlineBundle != nil || c.Recursive || c.Attachment != "" {
+			return errors.New("--layer-policy can't be used with --local-image, --bundle-file, --recursive or --attachment")
+		}
+		if layerPolicy, err = readLayerPolicy(c.LayerPolicy); err != nil {
+			return err
+		}
+	}
+
 	// always default to sha256 if the algorithm hasn't been explicitly set
 	if c.HashAlgorithm == 0 {
 		c.HashAlgorithm = crypto.SHA256
@@ -119,89 +233,117 @@ func (c *VerifyCommand) Exec(ctx context.Context, images []string) (err error) {
 		CertGithubWorkflowName:       c.CertGithubWorkflowName,
 		CertGithubWorkflowRepository: c.CertGithubWorkflowRepository,
 		CertGithubWorkflowRef:        c.CertGithubWorkflowRef,
-		IgnoreSCT:                    c.IgnoreSCT,
+		IgnoreSCT:                    c.IgnoreSCT || c.CARoots() != "",
+		IgnoreKeyUsage:               c.IgnoreKeyUsage,
+		AllowAnyEKU:                  c.AllowAnyEKU,
+		RequireNameConstraints:       c.RequireNameConstraints,
 		SignatureRef:                 c.SignatureRef,
 		PayloadRef:                   c.PayloadRef,
 		Identities:                   identities,
-		Offline:                      c.Offline,
+		Offline:                      c.Offline || offlineBundle != nil,
 		IgnoreTlog:                   c.IgnoreTlog,
+		EnforceExpiry:                c.EnforceExpiry,
+		RequireEncrypted:             c.Encryption.RequireEncrypted || len(c.Encryption.Recipients) > 0,
 	}
-	if c.CheckClaims {
-		co.ClaimVerifier = cosign.SimpleClaimVerifier
+	if co.CertClaims, err = c.ClaimMatchers(); err != nil {
+		return err
 	}
-
-	if c.TSACertChainPath != "" {
-		_, err := os.Stat(c.TSACertChainPath)
-		if err != nil {
-			return fmt.Errorf("unable to open timestamp certificate chain file: %w", err)
-		}
-		// TODO: Add support for TUF certificates.
-		pemBytes, err := os.ReadFile(filepath.Clean(c.TSACertChainPath))
+	if co.RekorBundleDir, err = rekorBundleDir(c.OfflineBundle.RekorBundleDir); err != nil {
+		return err
+	}
+	if err := c.ApplyCTInclusion(co); err != nil {
+		return err
+	}
+	if err := c.ApplyChainPinning(co); err != nil {
+		return err
+	}
+	if err := c.ApplyClockSkew(co); err != nil {
+		return err
+	}
+	c.Evaluation.Apply(co)
+	for _, r := range c.Encryption.Recipients {
+		fp, err := cosign.EncryptionRecipient(r)
 		if err != nil {
-			return fmt.Errorf("error reading certification chain path file: %w", err)
+			return err
 		}
+		co.EncryptionRecipients = append(co.EncryptionRecipients, fp)
+	}
+	co.Denylist, err = loadDenylist(ctx, c.Denylist, ociremoteOpts, c.NameOptions)
+	if err != nil {
+		return err
+	}
+	co.KeyUsage, err = loadKeyUsagePolicy(c.KeyUsagePolicy)
+	if err != nil {
+		return err
+	}
+	co.Witnesses, err = loadWitnesses(c.Witnesses)
+	if err != nil {
+		return err
+	}
+	if co.Witnesses != nil && offlineBundle != nil {
+		return errors.New("--bundle-file can't be used with --witness-keys")
+	}
+	if c.CheckClaims {
+		co.ClaimVerifier = cosign.SimpleClaimVerifier
+	}
+	warnings := warningCollector{}
+	co.WarningHandler = warnings.handle
 
-		leaves, intermediates, roots, err := tsa.SplitPEMCertificateChain(pemBytes)
-		if err != nil {
-			return fmt.Errorf("error splitting certificates: %w", err)
-		}
-		if len(leaves) > 1 {
-			return fmt.Errorf("certificate chain must contain at most one TSA certificate")
-		}
-		if len(leaves) == 1 {
-			co.TSACertificate = leaves[0]
+	if paths := tsaCertChainPaths(c.TSACertChainPaths, c.TSACertChainPath); len(paths) > 0 {
+		if err := loadTSACertificateChains(co, paths); err != nil {
+			return err
 		}
-		co.TSAIntermediateCertificates = intermediates
-		co.TSARootCertificates = roots
+	} else {
+		setTrustedRootTSA(co, trustedRoot)
 	}
 
 	if !c.IgnoreTlog {
-		if c.RekorURL != "" {
+		if c.RekorURL != "" && offlineBundle == nil {
 			rekorClient, err := rekor.NewClient(c.RekorURL)
 			if err != nil {
 				return fmt.Errorf("creating Rekor client: %w", err)
 			}
 			co.RekorClient = rekorClient
 		}
-		// This performs an online fetch of the Rekor public keys, but this is needed
-		// for verifying tlog entries (both online and offline).
-		co.RekorPubKeys, err = cosign.GetRekorPubs(ctx)
+		// Without a trusted root, this performs an online fetch of the Rekor public keys,
+		// but this is needed for verifying tlog entries (both online and offline).
+		co.RekorPubKeys, err = rekorPubKeys(ctx, trustedRoot)
 		if err != nil {
 			return fmt.Errorf("getting Rekor public keys: %w", err)
 		}
 	}
 	if keylessVerification(c.KeyRef, c.Sk) {
 		if c.CertChain != "" {
-			chain, err := loadCertChainFromFileOrURL(c.CertChain)
+			chain, anchorKey, err := loadCertChain(ctx, c.CertChain)
 			if err != nil {
 				return err
 			}
-			co.RootCerts = x509.NewCertPool()
-			co.RootCerts.AddCert(chain[len(chain)-1])
-			if len(chain) > 1 {
-				co.IntermediateCerts = x509.NewCertPool()
-				for _, cert := range chain[:len(chain)-1] {
-					co.IntermediateCerts.AddCert(cert)
-				}
+			if anchorKey != nil {
+				setTrustAnchorKey(co, anchorKey)
+			} else {
+				co.SetCertificates(chain[len(chain)-1:], chain[:len(chain)-1])
 			}
-		} else {
-			// This performs an online fetch of the Fulcio roots. This is needed
-			// for verifying keyless certificates (both online and offline).
-			co.RootCerts, err = fulcio.GetRoots()
+		} else if c.CARoots() != "" {
+			roots, intermediates, err := localCACerts(&c.CertVerifyOptions)
 			if err != nil {
-				return fmt.Errorf("getting Fulcio roots: %w", err)
+				return err
 			}
-			co.IntermediateCerts, err = fulcio.GetIntermediates()
+			co.SetCertificates(roots, intermediates)
+		} else {
+			// Without a trusted root, this performs an online fetch of the Fulcio roots.
+			// This is needed for verifying keyless certificates (both online and offline).
+			roots, intermediates, err := fulcioCerts(trustedRoot)
 			if err != nil {
-				return fmt.Errorf("getting Fulcio intermediates: %w", err)
+				return err
 			}
+			co.SetCertificates(roots, intermediates)
 		}
 	}
 	keyRef := c.KeyRef
 	certRef := c.CertRef
 
-	if !c.IgnoreSCT {
-		co.CTLogPubKeys, err = cosign.GetCTLogPubs(ctx)
+	if !co.IgnoreSCT {
+		co.CTLogPubKeys, err = ctLogPubKeys(ctx, trustedRoot)
 		if err != nil {
 			return fmt.Errorf("getting ctlog public keys: %w", err)
 		}
@@ -236,25 +378,27 @@ func (c *VerifyCommand) Exec(ctx context.Context, images []string) (err error) {
 		}
 		if c.CertChain == "" {
 			// If no certChain is passed, the Fulcio root certificate will be used
-			co.RootCerts, err = fulcio.GetRoots()
+			roots, intermediates, err := fulcioCerts(trustedRoot)
 			if err != nil {
-				return fmt.Errorf("getting Fulcio roots: %w", err)
-			}
-			co.IntermediateCerts, err = fulcio.GetIntermediates()
-			if err != nil {
-				return fmt.Errorf("getting Fulcio intermediates: %w", err)
+				return err
 			}
-			pubKey, err = cosign.ValidateAndUnpackCert(cert, co)
+			co.SetCertificates(roots, intermediates)
+			pubKey, err = cosign.ValidateAndUnpackCertWithContext(ctx, cert, co)
 			if err != nil {
 				return err
 			}
 		} else {
 			// Verify certificate with chain
-			chain, err := loadCertChainFromFileOrURL(c.CertChain)
+			chain, anchorKey, err := loadCertChain(ctx, c.CertChain)
 			if err != nil {
 				return err
 			}
-			pubKey, err = cosign.ValidateAndUnpackCertWithChain(cert, chain, co)
+			if anchorKey != nil {
+				setTrustAnchorKey(co, anchorKey)
+				pubKey, err = cosign.ValidateAndUnpackCertWithContext(ctx, cert, co)
+			} else {
+				pubKey, err = cosign.ValidateAndUnpackCertWithChainContext(ctx, cert, chain, co)
+			}
 			if err != nil {
 				return err
 			}
@@ -277,37 +421,211 @@ func (c *VerifyCommand) Exec(ctx context.Context, images []string) (err error) {
 	// was performed so we don't need to use this fragile logic here.
 	fulcioVerified := (co.SigVerifier == nil)
 
-	for _, img := range images {
-		if c.LocalImage {
-			verified, bundleVerified, err := cosign.VerifyLocalImageSignatures(ctx, img, co)
+	var report *VerificationReport
+	if c.SignReport != "" {
+		report = newVerificationReport(c, co)
+	}
+
+	// Record the verified images in the state file, if any, so that a failed
+	// run over many images can be resumed.
+	progress := batch.New()
+	if err := progress.UseStateFile(c.Batch.StateFile, c.Batch.Resume); err != nil {
+		return err
+	}
+	var pending []string
+	defer func() {
+		err = progress.Finish(ctx, err, pending...)
+	}()
+	// The images left once the deadline of ctx, e.g. of --timeout, is
+	// reached are not evaluated rather than failed, so that CI can retry
+	// just them.
+	defer func() {
+		if err == nil || !deadlineExceeded(ctx, err) {
+			return
+		}
+		left := pending
+		if current != "" {
+			left = append([]string{current}, pending...)
+		}
+		if results != nil {
+			results.notEvaluated(left...)
+			current = ""
+		}
+		err = &cosignError.CosignError{
+			Message: fmt.Sprintf("%d images not evaluated before the deadline: %v", len(left), err),
+			Code:    cosignError.NotEvaluated,
+		}
+	}()
+
+	resolve := func(img string) (name.Reference, error) {
+		ref, err := oci.ParseReference(img, c.NameOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("parsing reference: %w", err)
+		}
+		if !c.AnyRegistry {
+			if err := profile.CheckImage(ref); err != nil {
+				return nil, err
+			}
+		}
+		ref, err = sign.GetAttachedImageRef(ref, c.Attachment, ociremoteOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("resolving attachment type %s for image %s: %w", c.Attachment, img, err)
+		}
+		return ref, nil
+	}
+	verifyImage := cosign.VerifyImageSignatures
+	if c.AllowConverted {
+		verifyImage = verifyConverted(verifyImage)
+	}
+	if c.Recursive {
+		verifyImage = verifyRecursive(verifyImage)
+	}
+	if c.Cache.Dir != "" {
+		cache, err := cosign.NewFileVerificationCache(c.Cache.Dir, c.Cache.TTL)
+		if err != nil {
+			return err
+		}
+		scope := fmt.Sprintf("verify attachment=%s recursive=%t allow-converted=%t warnings-as-errors=%t", c.Attachment, c.Recursive, c.AllowConverted, c.WarningsAsErrors)
+		verifyImage = cosign.CacheVerification(cache, scope, verifyImage)
+	}
+	verifyRef := func(ctx context.Context, ref name.Reference, co *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+		return verifyWithSourceRepositories(ctx, ref, co, c.SourceRepositories, c.NameOptions, verifyImage)
+	}
+	// The images of a batch file are verified in parallel, and their results
+	// read in order below.
+	var parallel *parallelVerification
+	if c.BatchVerify.File != "" {
+		parallel, err = verifyParallel(ctx, images, progress.Skip, c.BatchVerify.Workers, co, resolve, verifyRef)
+		if err != nil {
+			return err
+		}
+		defer parallel.stop()
+	}
+
+	for i, img := range images {
+		pending = images[i+1:]
+		if progress.Skip(img) {
+			continue
+		}
+		progress.Start(img)
+		current = img
+		if c.LocalImage || offlineBundle != nil {
+			var verified []oci.Signature
+			var bundleVerified bool
+			if offlineBundle != nil {
+				verified, bundleVerified, err = cosign.VerifyOfflineBundleSignatures(ctx, offlineBundle, co)
+			} else {
+				verified, bundleVerified, err = cosign.VerifyLocalImageSignatures(ctx, img, co)
+			}
 			if err != nil {
 				return err
 			}
 			PrintVerificationHeader(ctx, img, co, bundleVerified, fulcioVerified)
-			PrintVerification(ctx, verified, c.Output)
+			printMatchedIdentities(ctx, verified, co.Identities)
+			if results.printsPayloads() {
+				printVerification(ctx, verified, c.Output, warnings)
+			}
+			if err := warnings.report(ctx, img, verified, c.WarningsAsErrors); err != nil {
+				return err
+			}
+			if results != nil {
+				results.pass(img, "", verified, co.Identities, warnings)
+			}
+			if report != nil {
+				if err := report.add(img, verified); err != nil {
+					return fmt.Errorf("adding %s to verification report: %w", img, err)
+				}
+			}
 		} else {
-			ref, err := name.ParseReference(img, c.NameOptions...)
-			if err != nil {
-				return fmt.Errorf("parsing reference: %w", err)
+			var ref name.Reference
+			var verified []oci.Signature
+			var bundleVerified bool
+			if parallel != nil {
+				r, err := parallel.result(i)
+				if err != nil {
+					return err
+				}
+				ref, verified, bundleVerified, err = r.Ref, r.Signatures, r.BundleVerified, r.Err
+				progress.Took(img, r.Duration)
+				if err != nil {
+					return wrapVerifyError(ctx, err)
+				}
+			} else {
+				ref, err = resolve(img)
+				if err != nil {
+					return err
+				}
+				verified, bundleVerified, err = verifyRef(ctx, ref, co)
+				if err != nil {
+					return wrapVerifyError(ctx, err)
+				}
 			}
-			ref, err = sign.GetAttachedImageRef(ref, c.Attachment, ociremoteOpts...)
-			if err != nil {
-				return fmt.Errorf("resolving attachment type %s for image %s: %w", c.Attachment, img, err)
+			if c.Countersigners.Enabled() {
+				if err := verifyCountersigners(ctx, ref, co, c.Countersigners, c.HashAlgorithm, verified, cosign.VerifyImageSignatures); err != nil {
+					return err
+				}
 			}
-
-			verified, bundleVerified, err := cosign.VerifyImageSignatures(ctx, ref, co)
-			if err != nil {
-				return cosignError.WrapError(err)
+			if layerPolicy != nil {
+				if err := c.verifyLayers(ctx, ref, layerPolicy); err != nil {
+					return err
+				}
+			}
+			if c.Quota.Enabled() {
+				if err := checkArtifactQuota(ctx, ref, co, c.Quota); err != nil {
+					return err
+				}
 			}
 
 			PrintVerificationHeader(ctx, ref.Name(), co, bundleVerified, fulcioVerified)
-			PrintVerification(ctx, verified, c.Output)
+			printMatchedIdentities(ctx, verified, co.Identities)
+			if c.OnVerified != nil {
+				if err := c.OnVerified(ctx, ref, verified); err != nil {
+					return err
+				}
+			} else if results.printsPayloads() {
+				printVerification(ctx, verified, c.Output, warnings)
+			}
+			if err := warnings.report(ctx, ref.Name(), verified, c.WarningsAsErrors); err != nil {
+				return err
+			}
+			if results != nil {
+				results.pass(ref.Name(), "", verified, co.Identities, warnings)
+			}
+			if report != nil {
+				if err := report.add(ref.Name(), verified); err != nil {
+					return fmt.Errorf("adding %s to verification report: %w", ref.Name(), err)
+				}
+			}
+		}
+		current = ""
+		if err := progress.Done(img); err != nil {
+			return err
 		}
 	}
 
+	if report != nil {
+		return signVerificationReport(ctx, report, c.SignReportKeyOpts, c.SignReport, c.SignReportCertificate)
+	}
+
 	return nil
 }
 
+// deadlineExceeded reports whether err is the result of reaching the
+// deadline of ctx.
+func deadlineExceeded(ctx context.Context, err error) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded) && errors.Is(err, context.DeadlineExceeded)
+}
+
+// wrapVerifyError returns err with the exit code of its verification error,
+// unless it comes from reaching the deadline of ctx, so that Exec can report
+// the images left as not evaluated.
+func wrapVerifyError(ctx context.Context, err error) error {
+	if deadlineExceeded(ctx, err) {
+		return err
+	}
+	return cosignError.WrapError(err)
+}
+
 func PrintVerificationHeader(ctx context.Context, imgRef string, co *cosign.CheckOpts, bundleVerified, fulcioVerified bool) {
 	ui.Infof(ctx, "\nVerification for %s --", imgRef)
 	ui.Infof(ctx, "The following checks were performed on each of these signatures:")
@@ -331,8 +649,42 @@ func PrintVerificationHeader(ctx context.Context, imgRef string, co *cosign.Chec
 	}
 }
 
+// printMatchedIdentities logs, when verifying with several identities, which
+// of them the certificate of each of the verified signatures matched.
+func printMatchedIdentities(ctx context.Context, verified []oci.Signature, identities []cosign.Identity) {
+	if len(identities) < 2 {
+		return
+	}
+	for n, sig := range verified {
+		cert, err := sig.Cert()
+		if err != nil || cert == nil {
+			continue
+		}
+		if i := cosign.MatchedIdentity(cert, identities); i >= 0 {
+			ui.Infof(ctx, "  - The certificate of signature %d matched identity %d, %s", n+1, i+1, describeIdentity(identities[i]))
+		}
+	}
+}
+
+// describeIdentity returns the subject and issuer constraints of id.
+func describeIdentity(id cosign.Identity) string {
+	subject := "subject " + id.Subject
+	if id.SubjectRegExp != "" {
+		subject = "subject matching " + id.SubjectRegExp
+	}
+	issuer := "issuer " + id.Issuer
+	if id.IssuerRegExp != "" {
+		issuer = "issuer matching " + id.IssuerRegExp
+	}
+	return subject + " and " + issuer
+}
+
 // PrintVerification logs details about the verification to stdout
 func PrintVerification(ctx context.Context, verified []oci.Signature, output string) {
+	printVerification(ctx, verified, output, nil)
+}
+
+func printVerification(ctx context.Context, verified []oci.Signature, output string, warnings warningCollector) {
 	switch output {
 	case "text":
 		for _, sig := range verified {
@@ -432,6 +784,12 @@ func PrintVerification(ctx context.Context, verified []oci.Signature, output str
 				}
 				ss.Optional["RFC3161Timestamp"] = rfc3161Timestamp
 			}
+			if ws := warnings.forSignature(sig); len(ws) > 0 {
+				if ss.Optional == nil {
+					ss.Optional = make(map[string]interface{})
+				}
+				ss.Optional["Warnings"] = ws
+			}
 
 			outputKeys = append(outputKeys, ss)
 		}
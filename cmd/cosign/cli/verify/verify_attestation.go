@@ -17,6 +17,7 @@ package verify
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"flag"
 	"fmt"
@@ -25,12 +26,12 @@ import (
 	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
-	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
+	"github.com/sigstore/cosign/v2/internal/pkg/profile"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
 	"github.com/sigstore/cosign/v2/pkg/cosign/cue"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pivkey"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
@@ -59,18 +60,73 @@ type VerifyAttestationCommand struct {
 	Sk                           bool
 	Slot                         string
 	Output                       string
+	OutputTemplate               string
+	GitHubSummary                bool
 	RekorURL                     string
 	PredicateType                string
+	PredicateTypes               []string
+	PredicateSchemas             string
+	PredicateSchema              string
+	PayloadTypes                 []string
 	Policies                     []string
+	PolicyEvaluation             string
 	LocalImage                   bool
 	NameOptions                  []name.Option
 	Offline                      bool
-	TSACertChainPath             string
+	TSACertChainPaths            []string
 	IgnoreTlog                   bool
+	Denylist                     options.DenylistOptions
+	KeyUsagePolicy               string
+	Witnesses                    options.WitnessOptions
+	WarningsAsErrors             bool
+	SourceRepositories           []string
+	AllowConverted               bool
+	BaseImagePolicy              string
+	OfflineBundle                options.OfflineBundleOptions
+	LicensePolicy                options.LicensePolicyOptions
+	// OnVerified, if set, is called with the attestations of each image
+	// whose signatures are verified, instead of checking them against the
+	// predicate types and policies and printing them.
+	OnVerified func(ctx context.Context, ref name.Reference, verified []oci.Signature) error
+	// Deprecated: TSACertChainPath is added to TSACertChainPaths. Use
+	// TSACertChainPaths.
+	TSACertChainPath string
+	// baseImageChain is set when verifying a base image of the chain.
+	baseImageChain *baseImageChain
+	// results, if set, collects the verification results of a parent
+	// command instead of writing them.
+	results *VerificationResult
 }
 
 // Exec runs the verification command
 func (c *VerifyAttestationCommand) Exec(ctx context.Context, images []string) (err error) {
+	results, current := c.results, ""
+	if results == nil {
+		if results, err = newCommandResult("verify-attestation", c.Output, c.OutputTemplate, c.GitHubSummary); err != nil {
+			return err
+		}
+		if results != nil {
+			// Base images verified by nested commands are added to the results.
+			c.results = results
+			defer func() {
+				c.results = nil
+				err = results.finish(os.Stdout, c.Output, current, err)
+			}()
+		}
+	}
+	if c.OfflineBundle.BundleFile != "" && (c.LocalImage || c.AllowConverted || len(c.SourceRepositories) > 0 || c.BaseImagePolicy != "") {
+		return errors.New("--bundle-file can't be used with --local-image, --allow-converted, --source-repository or --base-image-policy")
+	}
+	if c.OnVerified != nil && (c.OfflineBundle.BundleFile != "" || c.LocalImage || c.BaseImagePolicy != "") {
+		return errors.New("the verified attestations of --bundle-file, --local-image and --base-image-policy can't be collected")
+	}
+	offlineBundle, trustedRoot, images, err := loadOfflineBundle(c.OfflineBundle, images, c.NameOptions, keylessVerification(c.KeyRef, c.Sk) || !c.IgnoreTlog)
+	if err != nil {
+		return err
+	}
+	if images, err = expandWorkloads(ctx, images, c.LocalImage, c.NameOptions); err != nil {
+		return err
+	}
 	if len(images) == 0 {
 		return flag.ErrHelp
 	}
@@ -100,71 +156,122 @@ func (c *VerifyAttestationCommand) Exec(ctx context.Context, images []string) (e
 		CertGithubWorkflowName:       c.CertGithubWorkflowName,
 		CertGithubWorkflowRepository: c.CertGithubWorkflowRepository,
 		CertGithubWorkflowRef:        c.CertGithubWorkflowRef,
-		IgnoreSCT:                    c.IgnoreSCT,
+		IgnoreSCT:                    c.IgnoreSCT || c.CARoots() != "",
+		IgnoreKeyUsage:               c.IgnoreKeyUsage,
+		AllowAnyEKU:                  c.AllowAnyEKU,
+		RequireNameConstraints:       c.RequireNameConstraints,
 		Identities:                   identities,
-		Offline:                      c.Offline,
+		Offline:                      c.Offline || offlineBundle != nil,
 		IgnoreTlog:                   c.IgnoreTlog,
+		PayloadTypes:                 c.PayloadTypes,
 	}
+	if co.CertClaims, err = c.ClaimMatchers(); err != nil {
+		return err
+	}
+	if co.RekorBundleDir, err = rekorBundleDir(c.OfflineBundle.RekorBundleDir); err != nil {
+		return err
+	}
+	if err := c.ApplyCTInclusion(co); err != nil {
+		return err
+	}
+	if err := c.ApplyChainPinning(co); err != nil {
+		return err
+	}
+	if err := c.ApplyClockSkew(co); err != nil {
+		return err
+	}
+	co.Denylist, err = loadDenylist(ctx, c.Denylist, ociremoteOpts, c.NameOptions)
+	if err != nil {
+		return err
+	}
+	co.KeyUsage, err = loadKeyUsagePolicy(c.KeyUsagePolicy)
+	if err != nil {
+		return err
+	}
+	co.Witnesses, err = loadWitnesses(c.Witnesses)
+	if err != nil {
+		return err
+	}
+	if co.Witnesses != nil && offlineBundle != nil {
+		return errors.New("--bundle-file can't be used with --witness-keys")
+	}
+	schemas, err := cosign.LoadPredicateSchemas(c.PredicateSchemas, c.NameOptions, ociremoteOpts...)
+	if err != nil {
+		return err
+	}
+	if c.PredicateSchema != "" {
+		predicateTypes := c.predicateTypes()
+		if len(predicateTypes) != 1 {
+			return errors.New("--predicate-schema applies to a single --type")
+		}
+		predicateType, err := options.ParsePredicateType(predicateTypes[0])
+		if err != nil {
+			return err
+		}
+		if schemas, err = cosign.AddPredicateSchema(schemas, predicateType, c.PredicateSchema); err != nil {
+			return err
+		}
+	}
+	joint, err := c.jointPolicyEvaluation()
+	if err != nil {
+		return err
+	}
+	licenses, err := newLicensePolicy(c.LicensePolicy)
+	if err != nil {
+		return err
+	}
+	policies, cleanup, err := fetchTUFPolicies(ctx, c.Policies, cosign.GetTUFTarget)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	if c.CheckClaims {
 		co.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
 	}
-	if !c.IgnoreSCT {
-		co.CTLogPubKeys, err = cosign.GetCTLogPubs(ctx)
+	warnings := warningCollector{}
+	co.WarningHandler = warnings.handle
+	if !co.IgnoreSCT {
+		co.CTLogPubKeys, err = ctLogPubKeys(ctx, trustedRoot)
 		if err != nil {
 			return fmt.Errorf("getting ctlog public keys: %w", err)
 		}
 	}
 
-	if c.TSACertChainPath != "" {
-		_, err := os.Stat(c.TSACertChainPath)
-		if err != nil {
-			return fmt.Errorf("unable to open timestamp certificate chain file '%s: %w", c.TSACertChainPath, err)
-		}
-		// TODO: Add support for TUF certificates.
-		pemBytes, err := os.ReadFile(filepath.Clean(c.TSACertChainPath))
-		if err != nil {
-			return fmt.Errorf("error reading certification chain path file: %w", err)
-		}
-
-		leaves, intermediates, roots, err := tsa.SplitPEMCertificateChain(pemBytes)
-		if err != nil {
-			return fmt.Errorf("error splitting certificates: %w", err)
-		}
-		if len(leaves) > 1 {
-			return fmt.Errorf("certificate chain must contain at most one TSA certificate")
-		}
-		if len(leaves) == 1 {
-			co.TSACertificate = leaves[0]
+	if paths := tsaCertChainPaths(c.TSACertChainPaths, c.TSACertChainPath); len(paths) > 0 {
+		if err := loadTSACertificateChains(co, paths); err != nil {
+			return err
 		}
-		co.TSAIntermediateCertificates = intermediates
-		co.TSARootCertificates = roots
+	} else {
+		setTrustedRootTSA(co, trustedRoot)
 	}
 	if !c.IgnoreTlog {
-		if c.RekorURL != "" {
+		if c.RekorURL != "" && offlineBundle == nil {
 			rekorClient, err := rekor.NewClient(c.RekorURL)
 			if err != nil {
 				return fmt.Errorf("creating Rekor client: %w", err)
 			}
 			co.RekorClient = rekorClient
 		}
-		// This performs an online fetch of the Rekor public keys, but this is needed
-		// for verifying tlog entries (both online and offline).
-		co.RekorPubKeys, err = cosign.GetRekorPubs(ctx)
+		// Without a trusted root, this performs an online fetch of the Rekor public keys,
+		// but this is needed for verifying tlog entries (both online and offline).
+		co.RekorPubKeys, err = rekorPubKeys(ctx, trustedRoot)
 		if err != nil {
 			return fmt.Errorf("getting Rekor public keys: %w", err)
 		}
 	}
 	if keylessVerification(c.KeyRef, c.Sk) {
-		// This performs an online fetch of the Fulcio roots. This is needed
-		// for verifying keyless certificates (both online and offline).
-		co.RootCerts, err = fulcio.GetRoots()
-		if err != nil {
-			return fmt.Errorf("getting Fulcio roots: %w", err)
+		var roots, intermediates []*x509.Certificate
+		if c.CARoots() != "" {
+			roots, intermediates, err = localCACerts(&c.CertVerifyOptions)
+		} else {
+			// Without a trusted root, this performs an online fetch of the Fulcio roots.
+			// This is needed for verifying keyless certificates (both online and offline).
+			roots, intermediates, err = fulcioCerts(trustedRoot)
 		}
-		co.IntermediateCerts, err = fulcio.GetIntermediates()
 		if err != nil {
-			return fmt.Errorf("getting Fulcio intermediates: %w", err)
+			return err
 		}
+		co.SetCertificates(roots, intermediates)
 	}
 	keyRef := c.KeyRef
 
@@ -196,25 +303,27 @@ func (c *VerifyAttestationCommand) Exec(ctx context.Context, images []string) (e
 		}
 		if c.CertChain == "" {
 			// If no certChain is passed, the Fulcio root certificate will be used
-			co.RootCerts, err = fulcio.GetRoots()
-			if err != nil {
-				return fmt.Errorf("getting Fulcio roots: %w", err)
-			}
-			co.IntermediateCerts, err = fulcio.GetIntermediates()
+			roots, intermediates, err := fulcioCerts(trustedRoot)
 			if err != nil {
-				return fmt.Errorf("getting Fulcio intermediates: %w", err)
+				return err
 			}
-			co.SigVerifier, err = cosign.ValidateAndUnpackCert(cert, co)
+			co.SetCertificates(roots, intermediates)
+			co.SigVerifier, err = cosign.ValidateAndUnpackCertWithContext(ctx, cert, co)
 			if err != nil {
 				return fmt.Errorf("creating certificate verifier: %w", err)
 			}
 		} else {
 			// Verify certificate with chain
-			chain, err := loadCertChainFromFileOrURL(c.CertChain)
+			chain, anchorKey, err := loadCertChain(ctx, c.CertChain)
 			if err != nil {
 				return err
 			}
-			co.SigVerifier, err = cosign.ValidateAndUnpackCertWithChain(cert, chain, co)
+			if anchorKey != nil {
+				setTrustAnchorKey(co, anchorKey)
+				co.SigVerifier, err = cosign.ValidateAndUnpackCertWithContext(ctx, cert, co)
+			} else {
+				co.SigVerifier, err = cosign.ValidateAndUnpackCertWithChainContext(ctx, cert, chain, co)
+			}
 			if err != nil {
 				return fmt.Errorf("creating certificate verifier: %w", err)
 			}
@@ -236,94 +345,249 @@ func (c *VerifyAttestationCommand) Exec(ctx context.Context, images []string) (e
 	fulcioVerified := (co.SigVerifier == nil)
 
 	for _, imageRef := range images {
+		current = imageRef
 		var verified []oci.Signature
 		var bundleVerified bool
 
-		if c.LocalImage {
-			verified, bundleVerified, err = cosign.VerifyLocalImageAttestations(ctx, imageRef, co)
+		switch {
+		case offlineBundle != nil:
+			verified, bundleVerified, err = cosign.VerifyOfflineBundleAttestations(ctx, offlineBundle, co)
 			if err != nil {
 				return err
 			}
-		} else {
-			ref, err := name.ParseReference(imageRef, c.NameOptions...)
+		case c.LocalImage:
+			verified, bundleVerified, err = cosign.VerifyLocalImageAttestations(ctx, imageRef, co)
 			if err != nil {
 				return err
 			}
-
-			verified, bundleVerified, err = cosign.VerifyImageAttestations(ctx, ref, co)
+		default:
+			ref, err := oci.ParseReference(imageRef, c.NameOptions...)
 			if err != nil {
 				return err
 			}
-		}
-
-		var cuePolicies, regoPolicies []string
-
-		for _, policy := range c.Policies {
-			switch filepath.Ext(policy) {
-			case ".rego":
-				regoPolicies = append(regoPolicies, policy)
-			case ".cue":
-				cuePolicies = append(cuePolicies, policy)
-			default:
-				return errors.New("invalid policy format, expected .cue or .rego")
+			if err := profile.CheckImage(ref); err != nil {
+				return err
 			}
-		}
 
-		var checked []oci.Signature
-		var validationErrors []error
-		// To aid in determining if there's a mismatch in what predicateType
-		// we're looking for and what we checked, keep track of them here so
-		// that we can help the user figure out if there's a typo, etc.
-		checkedPredicateTypes := []string{}
-		for _, vp := range verified {
-			payload, gotPredicateType, err := policy.AttestationToPayloadJSON(ctx, c.PredicateType, vp)
+			verifyImage := cosign.VerifyImageAttestations
+			if c.AllowConverted {
+				verifyImage = verifyConverted(verifyImage)
+			}
+			verified, bundleVerified, err = verifyWithSourceRepositories(ctx, ref, co, c.SourceRepositories, c.NameOptions, verifyImage)
 			if err != nil {
-				return fmt.Errorf("converting to consumable policy validation: %w", err)
+				return err
 			}
-			checkedPredicateTypes = append(checkedPredicateTypes, gotPredicateType)
-			if len(payload) == 0 {
-				// This is not the predicate type we're looking for.
+			if c.OnVerified != nil {
+				if err := c.OnVerified(ctx, ref, verified); err != nil {
+					return err
+				}
+				current = ""
 				continue
 			}
+		}
 
-			if len(cuePolicies) > 0 {
-				ui.Infof(ctx, "will be validating against CUE policies: %v", cuePolicies)
-				cueValidationErr := cue.ValidateJSON(payload, cuePolicies)
-				if cueValidationErr != nil {
-					validationErrors = append(validationErrors, cueValidationErr)
-					continue
-				}
+		checked, err := checkPolicies(ctx, c.predicateTypes(), verified, schemas, policies, joint)
+		if err != nil {
+			return err
+		}
+		if licenses != nil {
+			if err := licenses.check(ctx, checked); err != nil {
+				return err
 			}
+		}
+
+		// TODO: add CUE validation report to `PrintVerificationHeader`.
+		PrintVerificationHeader(ctx, imageRef, co, bundleVerified, fulcioVerified)
+		printMatchedIdentities(ctx, checked, co.Identities)
+		if results.printsPayloads() {
+			// The attestations are always JSON, so use the raw "text" mode for outputting them instead of conversion
+			PrintVerification(ctx, checked, "text")
+		}
+		if err := warnings.report(ctx, imageRef, checked, c.WarningsAsErrors); err != nil {
+			return err
+		}
+		if results != nil {
+			results.pass(imageRef, "", checked, co.Identities, warnings)
+		}
 
-			if len(regoPolicies) > 0 {
-				ui.Infof(ctx, "will be validating against Rego policies: %v", regoPolicies)
-				regoValidationErrs := rego.ValidateJSON(payload, regoPolicies)
-				if len(regoValidationErrs) > 0 {
-					validationErrors = append(validationErrors, regoValidationErrs...)
-					continue
+		if c.BaseImagePolicy != "" {
+			chain := c.baseImageChain
+			if chain == nil {
+				p, err := readBaseImagePolicy(c.BaseImagePolicy)
+				if err != nil {
+					return err
 				}
+				chain = &baseImageChain{policy: p, images: []string{imageRef}}
+			}
+			if err := c.verifyBaseImage(ctx, chain, verified); err != nil {
+				return err
+			}
+			if c.baseImageChain == nil {
+				ui.Infof(ctx, "Verified base image chain: %s", strings.Join(chain.images, " -> "))
 			}
+		}
+		current = ""
+	}
+
+	return nil
+}
 
-			checked = append(checked, vp)
+// predicateTypes returns the requested predicate types without duplicates:
+// PredicateTypes when set, which are all evaluated against one fetch of the
+// attestations, or else PredicateType.
+func (c *VerifyAttestationCommand) predicateTypes() []string {
+	if len(c.PredicateTypes) == 0 {
+		return []string{c.PredicateType}
+	}
+	seen := map[string]bool{}
+	var types []string
+	for _, t := range c.PredicateTypes {
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
 		}
+	}
+	return types
+}
 
-		if len(validationErrors) > 0 {
-			ui.Infof(ctx, "There are %d number of errors occurred during the validation:\n", len(validationErrors))
-			for _, v := range validationErrors {
-				ui.Infof(ctx, "- %v", v)
-			}
-			return fmt.Errorf("%d validation errors occurred", len(validationErrors))
+// checkPolicies validates the verified attestations of an image against the
+// predicate types, the predicate schemas and the CUE and Rego policies, for
+// each attestation or jointly, and returns the checked attestations.
+func checkPolicies(ctx context.Context, predicateTypes []string, verified []oci.Signature, schemas *attestation.PredicateSchemas, policies []string, joint bool) ([]oci.Signature, error) {
+	var cuePolicies, regoPolicies []string
+
+	for _, policy := range policies {
+		switch filepath.Ext(policy) {
+		case ".rego":
+			regoPolicies = append(regoPolicies, policy)
+		case ".cue":
+			cuePolicies = append(cuePolicies, policy)
+		default:
+			return nil, errors.New("invalid policy format, expected .cue or .rego")
 		}
+	}
 
+	var checked []oci.Signature
+	var failed []string
+	var jointResults []*predicateTypeResult
+	for _, predicateType := range predicateTypes {
+		var r *predicateTypeResult
+		var err error
+		if joint {
+			// The policies are evaluated below, with the attestations
+			// of every predicate type.
+			r, err = checkPredicateType(ctx, predicateType, verified, schemas, nil, nil)
+		} else {
+			r, err = checkPredicateType(ctx, predicateType, verified, schemas, cuePolicies, regoPolicies)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if joint && len(r.validationErrors) == 0 && len(r.checked) == 0 {
+			// Whether attestations of the predicate type are required
+			// is up to the policy.
+			ui.Infof(ctx, "Predicate type %s: no attestations", predicateType)
+			jointResults = append(jointResults, r)
+			continue
+		}
+		if err := r.err(ctx); err != nil {
+			if len(predicateTypes) == 1 {
+				return nil, err
+			}
+			ui.Warnf(ctx, "Predicate type %s: %v", predicateType, err)
+			failed = append(failed, predicateType)
+			continue
+		}
+		if len(predicateTypes) > 1 {
+			ui.Infof(ctx, "Predicate type %s: %d attestations verified", predicateType, len(r.checked))
+		}
+		checked = append(checked, r.checked...)
+		jointResults = append(jointResults, r)
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("%d of %d predicate types failed verification: %s", len(failed), len(predicateTypes), strings.Join(failed, ", "))
+	}
+	if joint {
 		if len(checked) == 0 {
-			return fmt.Errorf("none of the attestations matched the predicate type: %s, found: %s", c.PredicateType, strings.Join(checkedPredicateTypes, ","))
+			return nil, fmt.Errorf("none of the attestations matched the predicate types: %s", strings.Join(predicateTypes, ", "))
+		}
+		if err := checkJointPolicies(ctx, jointResults, cuePolicies, regoPolicies); err != nil {
+			return nil, err
 		}
-
-		// TODO: add CUE validation report to `PrintVerificationHeader`.
-		PrintVerificationHeader(ctx, imageRef, co, bundleVerified, fulcioVerified)
-		// The attestations are always JSON, so use the raw "text" mode for outputting them instead of conversion
-		PrintVerification(ctx, checked, "text")
 	}
+	return checked, nil
+}
+
+// predicateTypeResult is the outcome of evaluating the verified attestations
+// of an image against one predicate type.
+type predicateTypeResult struct {
+	predicateType string
+	checked       []oci.Signature
+	// payloads are the policy inputs of the checked attestations.
+	payloads         [][]byte
+	validationErrors []error
+	// To aid in determining if there's a mismatch in what predicateType
+	// we're looking for and what we checked, keep track of them here so
+	// that we can help the user figure out if there's a typo, etc.
+	checkedPredicateTypes []string
+}
 
+// err reports the validation errors of r, or an error if no attestation
+// matched its predicate type.
+func (r *predicateTypeResult) err(ctx context.Context) error {
+	if len(r.validationErrors) > 0 {
+		ui.Infof(ctx, "There are %d number of errors occurred during the validation:\n", len(r.validationErrors))
+		for _, v := range r.validationErrors {
+			ui.Infof(ctx, "- %v", v)
+		}
+		return fmt.Errorf("%d validation errors occurred", len(r.validationErrors))
+	}
+	if len(r.checked) == 0 {
+		return fmt.Errorf("none of the attestations matched the predicate type: %s, found: %s", r.predicateType, strings.Join(r.checkedPredicateTypes, ","))
+	}
 	return nil
 }
+
+// checkPredicateType validates the verified attestations of predicateType
+// against the predicate schemas and the CUE and Rego policies.
+func checkPredicateType(ctx context.Context, predicateType string, verified []oci.Signature, schemas *attestation.PredicateSchemas, cuePolicies, regoPolicies []string) (*predicateTypeResult, error) {
+	r := &predicateTypeResult{predicateType: predicateType, checkedPredicateTypes: []string{}}
+	for _, vp := range verified {
+		payload, gotPredicateType, err := policy.AttestationToPayloadJSON(ctx, predicateType, vp)
+		if err != nil {
+			return nil, fmt.Errorf("converting to consumable policy validation: %w", err)
+		}
+		r.checkedPredicateTypes = append(r.checkedPredicateTypes, gotPredicateType)
+		if len(payload) == 0 {
+			// This is not the predicate type we're looking for.
+			continue
+		}
+
+		if err := schemas.ValidateStatement(payload); err != nil {
+			r.validationErrors = append(r.validationErrors, err)
+			continue
+		}
+
+		if len(cuePolicies) > 0 {
+			ui.Infof(ctx, "will be validating against CUE policies: %v", cuePolicies)
+			cueValidationErr := cue.ValidateJSON(payload, cuePolicies)
+			if cueValidationErr != nil {
+				r.validationErrors = append(r.validationErrors, cueValidationErr)
+				continue
+			}
+		}
+
+		if len(regoPolicies) > 0 {
+			ui.Infof(ctx, "will be validating against Rego policies: %v", regoPolicies)
+			regoValidationErrs := rego.ValidateJSON(payload, regoPolicies)
+			if len(regoValidationErrs) > 0 {
+				r.validationErrors = append(r.validationErrors, regoValidationErrs...)
+				continue
+			}
+		}
+
+		r.checked = append(r.checked, vp)
+		r.payloads = append(r.payloads, payload)
+	}
+	return r, nil
+}
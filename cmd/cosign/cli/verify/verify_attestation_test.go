@@ -16,9 +16,23 @@ package verify
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 )
 
 func TestVerifyAttestationMissingSubject(t *testing.T) {
@@ -52,3 +66,170 @@ func TestVerifyAttestationMissingIssuer(t *testing.T) {
 		t.Fatal("verifyAttestation expected 'need --certificate-oidc-issuer'")
 	}
 }
+
+// attestedImage writes an image to a registry served with handler and
+// attests it with a new key for each of predicateTypes. It returns the image
+// and the path to the public key.
+func attestedImage(t *testing.T, handler http.Handler, predicateTypes ...string) (name.Digest, string) {
+	t.Helper()
+	ctx := context.Background()
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+	td := t.TempDir()
+	t.Setenv(env.VariablePassword.String(), "")
+
+	keys, err := cosign.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, pub := filepath.Join(td, "cosign.key"), filepath.Join(td, "cosign.pub")
+	if err := os.WriteFile(priv, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pub, keys.PublicBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(strings.TrimPrefix(s.URL, "http://") + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	predicate := filepath.Join(td, "predicate.json")
+	if err := os.WriteFile(predicate, []byte(`{"foo":"bar"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, predicateType := range predicateTypes {
+		c := &attest.AttestCommand{
+			KeyOpts:       options.KeyOpts{KeyRef: priv, PassFunc: func(bool) ([]byte, error) { return nil, nil }},
+			PredicatePath: predicate,
+			PredicateType: predicateType,
+		}
+		if err := c.Exec(ctx, ref.String()); err != nil {
+			t.Fatalf("attesting %s: %v", predicateType, err)
+		}
+	}
+	return ref, pub
+}
+
+func TestVerifyAttestationPredicateTypes(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.New()
+	var attFetches int32
+	ref, pub := attestedImage(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/manifests/") && strings.HasSuffix(r.URL.Path, ".att") {
+			atomic.AddInt32(&attFetches, 1)
+		}
+		reg.ServeHTTP(w, r)
+	}), options.PredicateCustom, "https://example.com/test/v1")
+
+	verify := func(types ...string) (int32, error) {
+		atomic.StoreInt32(&attFetches, 0)
+		c := &VerifyAttestationCommand{
+			KeyRef:         pub,
+			CheckClaims:    true,
+			IgnoreTlog:     true,
+			IgnoreSCT:      true,
+			PredicateTypes: types,
+			Output:         "text",
+		}
+		err := c.Exec(ctx, []string{ref.String()})
+		return atomic.LoadInt32(&attFetches), err
+	}
+	single, err := verify(options.PredicateCustom)
+	if err != nil {
+		t.Fatalf("verifying one predicate type: %v", err)
+	}
+	both, err := verify(options.PredicateCustom, "https://example.com/test/v1", options.PredicateCustom)
+	if err != nil {
+		t.Fatalf("verifying two predicate types: %v", err)
+	}
+	if single == 0 {
+		t.Fatal("no fetches of the attestations were counted")
+	}
+	if both != single {
+		t.Errorf("fetched the attestations %d times for two predicate types, want %d as for one", both, single)
+	}
+	if _, err := verify(options.PredicateCustom, options.PredicateSLSA); err == nil || !strings.Contains(err.Error(), "1 of 2 predicate types failed verification: slsaprovenance") {
+		t.Errorf("verifying a missing predicate type: error = %v, want 1 of 2 failed", err)
+	}
+}
+
+func TestVerifyAttestationJointPolicy(t *testing.T) {
+	ctx := context.Background()
+	ref, pub := attestedImage(t, registry.New(), options.PredicateCustom, "https://example.com/test/v1")
+	td := t.TempDir()
+	write := func(name, policy string) string {
+		p := filepath.Join(td, name)
+		if err := os.WriteFile(p, []byte(policy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	both := write("both.rego", `package signature
+
+default allow = false
+
+allow {
+	input.count == 2
+	count(input.predicateTypes.custom) == 1
+	input.predicateTypes.custom[0].statement.subject == input.predicateTypes["https://example.com/test/v1"][0].statement.subject
+	input.predicateTypes["https://example.com/test/v1"][0].statement.predicate.foo == "bar"
+	time.parse_rfc3339_ns(input.now) <= time.now_ns()
+}
+`)
+	counts := write("counts.cue", `count: 2
+now:   string
+attestations: [...{predicateType: string}]
+`)
+	vuln := write("vuln.rego", `package signature
+
+default allow = false
+
+allow {
+	count(input.predicateTypes.vuln) > 0
+	count(input.predicateTypes.custom) > 0
+}
+`)
+
+	verify := func(policy, evaluation string, types ...string) error {
+		c := &VerifyAttestationCommand{
+			KeyRef:           pub,
+			CheckClaims:      true,
+			IgnoreTlog:       true,
+			IgnoreSCT:        true,
+			PredicateTypes:   types,
+			Policies:         []string{policy},
+			PolicyEvaluation: evaluation,
+			Output:           "text",
+		}
+		return c.Exec(ctx, []string{ref.String()})
+	}
+	for _, policy := range []string{both, counts} {
+		if err := verify(policy, options.PolicyEvaluationJoint, options.PredicateCustom, "https://example.com/test/v1"); err != nil {
+			t.Errorf("verifying jointly with %s: %v", filepath.Base(policy), err)
+		}
+	}
+	if err := verify(both, options.PolicyEvaluationEach, options.PredicateCustom, "https://example.com/test/v1"); err == nil {
+		t.Error("verifying each attestation with a policy on their count succeeded")
+	}
+	// A missing predicate type is up to the policy.
+	if err := verify(vuln, options.PolicyEvaluationJoint, options.PredicateCustom, options.PredicateVuln); err == nil || !strings.Contains(err.Error(), "joint evaluation") {
+		t.Errorf("verifying jointly without a vuln attestation: error = %v, want a policy error", err)
+	}
+	if err := verify(vuln, options.PolicyEvaluationJoint, options.PredicateVuln); err == nil || !strings.Contains(err.Error(), "none of the attestations matched") {
+		t.Errorf("verifying jointly without attestations: error = %v, want none matched", err)
+	}
+	if err := verify(vuln, "all", options.PredicateCustom); err == nil || !strings.Contains(err.Error(), "invalid --policy-evaluation") {
+		t.Errorf("verifying with an invalid evaluation: error = %v", err)
+	}
+}
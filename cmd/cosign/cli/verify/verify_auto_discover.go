@@ -0,0 +1,127 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+// checksumFileName matches the names of the checksum files that releases are
+// published with, e.g. SHA256SUMS or app_1.0.0_checksums.txt.
+var checksumFileName = regexp.MustCompile(`(?i)(sha(256|384|512)sums|checksums)(\.txt)?$`)
+
+// execAutoDiscover verifies the file at blobRef with the signature,
+// certificate and bundle published next to it, <blob>.sig, <blob>.pem and
+// <blob>.bundle, or else those of a checksum file in its directory that
+// lists it.
+func (c *VerifyBlobCmd) execAutoDiscover(ctx context.Context, blobRef string) error {
+	if c.Embedded {
+		return errors.New("--auto-discover can't be used with --embedded")
+	}
+	if blobRef == "-" || !isFile(blobRef) {
+		return errors.New("--auto-discover needs the blob as a local file, next to its signature")
+	}
+
+	v := *c
+	v.AutoDiscover = false
+	if v.discoverSidecars(ctx, blobRef) {
+		return v.Exec(ctx, blobRef)
+	}
+
+	checksumsRef, err := discoverChecksums(blobRef)
+	if err != nil {
+		return err
+	}
+	if checksumsRef == "" {
+		return fmt.Errorf("no %s.sig or %s.bundle, nor a signed checksum file listing it, found", blobRef, blobRef)
+	}
+	ui.Infof(ctx, "Verifying %s against the checksum file %s", blobRef, checksumsRef)
+	v.discoverSidecars(ctx, checksumsRef)
+	cv := &VerifyChecksumsCmd{VerifyBlobCmd: v, Artifacts: []string{blobRef}}
+	return cv.Exec(ctx, checksumsRef)
+}
+
+// discoverSidecars sets the bundle, or else the signature and certificate, of
+// c that aren't set to the existing sidecar files of path, and returns whether
+// it has a bundle or signature.
+func (c *VerifyBlobCmd) discoverSidecars(ctx context.Context, path string) bool {
+	sidecar := func(ref *string, ext string) {
+		if *ref != "" || !isFile(path+ext) {
+			return
+		}
+		*ref = path + ext
+		ui.Infof(ctx, "Using %s", *ref)
+	}
+	if c.SigRef == "" && c.CertRef == "" {
+		sidecar(&c.BundlePath, ".bundle")
+	}
+	if c.BundlePath == "" {
+		sidecar(&c.SigRef, ".sig")
+		// A certificate can't be used with a key.
+		if c.KeyRef == "" && !c.Sk {
+			sidecar(&c.CertRef, ".pem")
+		}
+	}
+	return c.BundlePath != "" || c.SigRef != ""
+}
+
+// discoverChecksums returns the first checksum file, by name, in the
+// directory of path that is signed, with a <file>.sig or <file>.bundle, and
+// lists path, or an empty string if there is none.
+func discoverChecksums(path string) (string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && checksumFileName.MatchString(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := filepath.Join(filepath.Dir(path), name)
+		if !isFile(p+".sig") && !isFile(p+".bundle") {
+			continue
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return "", err
+		}
+		checksums, err := parseChecksums(b)
+		if err != nil {
+			continue
+		}
+		if _, err := checksums.lookup(path); err == nil {
+			return p, nil
+		}
+	}
+	return "", nil
+}
+
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode().IsRegular()
+}
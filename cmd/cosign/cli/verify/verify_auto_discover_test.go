@@ -0,0 +1,104 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestVerifyBlobAutoDiscover(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+	write := func(name string, b []byte) string {
+		p := filepath.Join(td, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := write("cosign.pub", pub)
+	sign := func(name string, b []byte) string {
+		p := write(name, b)
+		raw, err := sv.SignMessage(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		write(name+".sig", []byte(base64.StdEncoding.EncodeToString(raw)))
+		return p
+	}
+
+	// A blob with its own signature.
+	signed := sign("sidecar/app.tar.gz", []byte("app"))
+	// Blobs listed in a signed checksum file, one of them tampered with.
+	listed := write("release/app-linux.tar.gz", []byte("linux"))
+	tampered := write("release/app-darwin.tar.gz", []byte("tampered"))
+	unlisted := write("release/app-windows.zip", []byte("windows"))
+	linux, darwin := sha256.Sum256([]byte("linux")), sha256.Sum256([]byte("darwin"))
+	write("release/checksums.txt", []byte("not a checksum file\n"))
+	sign("release/SHA256SUMS", []byte(hex.EncodeToString(linux[:])+"  app-linux.tar.gz\n"+
+		hex.EncodeToString(darwin[:])+"  app-darwin.tar.gz\n"))
+
+	verify := func(blob string) error {
+		c := &VerifyBlobCmd{KeyOpts: options.KeyOpts{KeyRef: keyPath}, IgnoreSCT: true, IgnoreTlog: true, AutoDiscover: true}
+		return c.Exec(ctx, blob)
+	}
+	for _, blob := range []string{signed, listed} {
+		if err := verify(blob); err != nil {
+			t.Errorf("Exec(%s) = %v", blob, err)
+		}
+	}
+	for blob, want := range map[string]string{
+		tampered:                         "failed verification against the checksum file",
+		unlisted:                         "nor a signed checksum file listing it",
+		"-":                              "local file",
+		filepath.Join(td, "missing.txt"): "local file",
+	} {
+		if err := verify(blob); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Exec(%s) = %v, want %q", blob, err, want)
+		}
+	}
+}
@@ -16,10 +16,13 @@
 package verify
 
 import (
+	"bytes"
 	"context"
 	"crypto"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,16 +30,18 @@ import (
 	"os"
 	"path/filepath"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
-	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/blob"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pivkey"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
 	sigs "github.com/sigstore/cosign/v2/pkg/signature"
 
@@ -64,10 +69,53 @@ type VerifyBlobCmd struct {
 	SCTRef                       string
 	Offline                      bool
 	IgnoreTlog                   bool
+	Denylist                     options.DenylistOptions
+	KeyUsagePolicy               string
+	Witnesses                    options.WitnessOptions
+	Output                       string
+	OutputTemplate               string
+	GitHubSummary                bool
+	// Embedded verifies the blob, an executable or archive, with the bundle
+	// embedded in it by cosign embed.
+	Embedded bool
+	// AutoDiscover verifies the blob with the sidecar files next to it,
+	// <blob>.sig, <blob>.pem and <blob>.bundle, or a checksum file listing it.
+	AutoDiscover bool
+	// DigestAlgorithm is the name of the digest algorithm the blob was signed
+	// with, see blob.LookupDigestAlgorithm.
+	DigestAlgorithm string
+
+	// subject is the name of the blob in the results, if not blobRef.
+	subject string
 }
 
 // nolint
-func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
+func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) (err error) {
+	if c.AutoDiscover {
+		return c.execAutoDiscover(ctx, blobRef)
+	}
+	if c.Embedded {
+		return c.execEmbedded(ctx, blobRef)
+	}
+	subject := blobRef
+	if c.subject != "" {
+		subject = c.subject
+	}
+
+	results, err := newCommandResult("verify-blob", c.Output, c.OutputTemplate, c.GitHubSummary)
+	if err != nil {
+		return err
+	}
+	if results != nil {
+		defer func() {
+			failed := ""
+			if err != nil {
+				failed = subject
+			}
+			err = results.finish(os.Stdout, c.Output, failed, err)
+		}()
+	}
+
 	var cert *x509.Certificate
 	opts := make([]static.Option, 0)
 
@@ -82,7 +130,6 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 	}
 
 	var identities []cosign.Identity
-	var err error
 	if c.KeyRef == "" {
 		identities, err = c.Identities()
 		if err != nil {
@@ -90,7 +137,12 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 		}
 	}
 
-	sig, err := base64signature(c.SigRef, c.BundlePath)
+	in, err := newBlobInputs(blobRef, c.BundlePath, c.CertRef, c.SigRef)
+	if err != nil {
+		return err
+	}
+
+	sig, err := base64signature(in, c.SigRef, c.BundlePath)
 	if err != nil {
 		return err
 	}
@@ -99,6 +151,13 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 	if err != nil {
 		return err
 	}
+	alg, err := blob.LookupDigestAlgorithm(c.DigestAlgorithm)
+	if err != nil {
+		return err
+	}
+	if !alg.Tlog && !c.IgnoreTlog {
+		return fmt.Errorf("the transparency log doesn't accept signatures over %s blob digests, verify with --insecure-ignore-tlog", alg.Name)
+	}
 
 	co := &cosign.CheckOpts{
 		CertGithubWorkflowTrigger:    c.CertGithubWorkflowTrigger,
@@ -106,41 +165,57 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 		CertGithubWorkflowName:       c.CertGithubWorkflowName,
 		CertGithubWorkflowRepository: c.CertGithubWorkflowRepository,
 		CertGithubWorkflowRef:        c.CertGithubWorkflowRef,
-		IgnoreSCT:                    c.IgnoreSCT,
+		IgnoreSCT:                    c.IgnoreSCT || c.CARoots() != "",
+		IgnoreKeyUsage:               c.IgnoreKeyUsage,
+		AllowAnyEKU:                  c.AllowAnyEKU,
+		RequireNameConstraints:       c.RequireNameConstraints,
 		Identities:                   identities,
 		Offline:                      c.Offline,
 		IgnoreTlog:                   c.IgnoreTlog,
 	}
-	if c.RFC3161TimestampPath != "" && c.KeyOpts.TSACertChainPath == "" {
+	if co.CertClaims, err = c.ClaimMatchers(); err != nil {
+		return err
+	}
+	if err := c.ApplyCTInclusion(co); err != nil {
+		return err
+	}
+	if err := c.ApplyChainPinning(co); err != nil {
+		return err
+	}
+	if err := c.ApplyClockSkew(co); err != nil {
+		return err
+	}
+	co.Denylist, err = loadDenylist(ctx, c.Denylist, nil, nil)
+	if err != nil {
+		return err
+	}
+	co.KeyUsage, err = loadKeyUsagePolicy(c.KeyUsagePolicy)
+	if err != nil {
+		return err
+	}
+	co.Witnesses, err = loadWitnesses(c.Witnesses)
+	if err != nil {
+		return err
+	}
+	blobDigest := sha256.Sum256(blobBytes)
+	if err := co.Denylist.CheckDigest(v1.Hash{Algorithm: "sha256", Hex: hex.EncodeToString(blobDigest[:])}); err != nil {
+		return err
+	}
+	tsaChainPaths := tsaCertChainPaths(c.KeyOpts.TSACertChainPaths, c.KeyOpts.TSACertChainPath)
+	if c.RFC3161TimestampPath != "" && len(tsaChainPaths) == 0 {
 		return fmt.Errorf("timestamp-certificate-chain is required to validate a RFC3161 timestamp")
 	}
-	if c.KeyOpts.TSACertChainPath != "" {
-		_, err := os.Stat(c.KeyOpts.TSACertChainPath)
-		if err != nil {
-			return fmt.Errorf("unable to open timestamp certificate chain file '%s: %w", c.KeyOpts.TSACertChainPath, err)
-		}
-		// TODO: Add support for TUF certificates.
-		pemBytes, err := os.ReadFile(filepath.Clean(c.KeyOpts.TSACertChainPath))
-		if err != nil {
-			return fmt.Errorf("error reading certification chain path file: %w", err)
-		}
-
-		leaves, intermediates, roots, err := tsa.SplitPEMCertificateChain(pemBytes)
-		if err != nil {
-			return fmt.Errorf("error splitting certificates: %w", err)
-		}
-		if len(leaves) > 1 {
-			return fmt.Errorf("certificate chain must contain at most one TSA certificate")
-		}
-		if len(leaves) == 1 {
-			co.TSACertificate = leaves[0]
-		}
-		co.TSAIntermediateCertificates = intermediates
-		co.TSARootCertificates = roots
+	if err := loadTSACertificateChains(co, tsaChainPaths); err != nil {
+		return err
 	}
 
 	if !c.IgnoreTlog {
-		if c.RekorURL != "" {
+		switch {
+		case rekor.IsTileLog(c.RekorURL):
+			if co.RekorLog, err = rekor.NewV2Client(c.RekorURL); err != nil {
+				return fmt.Errorf("creating Rekor v2 client: %w", err)
+			}
+		case c.RekorURL != "":
 			rekorClient, err := rekor.NewClient(c.RekorURL)
 			if err != nil {
 				return fmt.Errorf("creating Rekor client: %w", err)
@@ -155,10 +230,18 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 		}
 	}
 	if keylessVerification(c.KeyRef, c.Sk) {
-		// Use default TUF roots if a cert chain is not provided.
+		// Use the roots of a local CA if set, or the default TUF roots if a
+		// cert chain is not provided.
 		// This performs an online fetch of the Fulcio roots. This is needed
 		// for verifying keyless certificates (both online and offline).
-		if c.CertChain == "" {
+		switch {
+		case c.CARoots() != "":
+			roots, intermediates, err := localCACerts(&c.CertVerifyOptions)
+			if err != nil {
+				return err
+			}
+			co.SetCertificates(roots, intermediates)
+		case c.CertChain == "":
 			co.RootCerts, err = fulcio.GetRoots()
 			if err != nil {
 				return fmt.Errorf("getting Fulcio roots: %w", err)
@@ -192,13 +275,13 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 			return fmt.Errorf("loading public key from token: %w", err)
 		}
 	case c.CertRef != "":
-		cert, err = loadCertFromFileOrURL(c.CertRef)
+		cert, err = in.loadCert(c.CertRef)
 		if err != nil {
 			return err
 		}
 	}
 	if c.BundlePath != "" {
-		b, err := cosign.FetchLocalSignedPayloadFromPath(c.BundlePath)
+		b, err := in.loadBundle(c.BundlePath)
 		if err != nil {
 			return err
 		}
@@ -230,6 +313,13 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 			cert = bundleCert
 		}
 		opts = append(opts, static.WithBundle(b.Bundle))
+		if b.TransparencyLogEntry != nil && !c.IgnoreTlog {
+			if err := verifyRekorV2Entry(ctx, b.TransparencyLogEntry, co, cert, blobDigest[:], sig); err != nil {
+				return err
+			}
+			// The signature is in the log, which has no bundle to verify.
+			co.IgnoreTlog = true
+		}
 	}
 	if c.RFC3161TimestampPath != "" {
 		var rfc3161Timestamp bundle.RFC3161Timestamp
@@ -253,11 +343,13 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 	// Set a cert chain if provided.
 	var chainPEM []byte
 	if c.CertChain != "" {
-		chain, err := loadCertChainFromFileOrURL(c.CertChain)
+		chain, anchorKey, err := loadCertChain(ctx, c.CertChain)
 		if err != nil {
 			return err
 		}
-		if chain == nil {
+		if anchorKey != nil {
+			setTrustAnchorKey(co, anchorKey)
+		} else if chain == nil {
 			return errors.New("expected certificate chain in --certificate-chain")
 		}
 		// Set the last one in the co.RootCerts. This is trusted, as its passed in
@@ -265,12 +357,14 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 		if co.RootCerts == nil {
 			co.RootCerts = x509.NewCertPool()
 		}
-		co.RootCerts.AddCert(chain[len(chain)-1])
-		// Use the whole as the cert chain in the signature object.
-		// The last one is omitted because it is considered the "root".
-		chainPEM, err = cryptoutils.MarshalCertificatesToPEM(chain)
-		if err != nil {
-			return err
+		if len(chain) > 0 {
+			co.RootCerts.AddCert(chain[len(chain)-1])
+			// Use the whole as the cert chain in the signature object.
+			// The last one is omitted because it is considered the "root".
+			chainPEM, err = cryptoutils.MarshalCertificatesToPEM(chain)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -285,7 +379,7 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 		opts = append(opts, static.WithCertChain(certPEM, chainPEM))
 	}
 
-	if !c.IgnoreSCT {
+	if !co.IgnoreSCT {
 		co.CTLogPubKeys, err = cosign.GetCTLogPubs(ctx)
 		if err != nil {
 			return fmt.Errorf("getting ctlog public keys: %w", err)
@@ -296,21 +390,62 @@ func (c *VerifyBlobCmd) Exec(ctx context.Context, blobRef string) error {
 	if err != nil {
 		return err
 	}
-	if _, err = cosign.VerifyBlobSignature(ctx, signature, co); err != nil {
+	subjectDigest := "sha256:" + hex.EncodeToString(blobDigest[:])
+	if alg.IsDefault() {
+		_, err = cosign.VerifyBlobSignature(ctx, signature, co)
+	} else {
+		var digest []byte
+		if digest, err = alg.Digest(bytes.NewReader(blobBytes)); err != nil {
+			return err
+		}
+		subjectDigest = alg.Name + ":" + hex.EncodeToString(digest)
+		_, err = cosign.VerifyBlobDigestSignature(ctx, signature, alg, digest, co)
+	}
+	if err != nil {
 		return err
 	}
 
 	ui.Infof(ctx, "Verified OK")
+	if results != nil {
+		results.pass(subject, subjectDigest, []oci.Signature{signature}, co.Identities, nil)
+	}
 	return nil
 }
 
+// verifyRekorV2Entry verifies that the Rekor v2 entry of a bundle is in a
+// trusted log and is for the signature sig over the blob digest, by cert or
+// the key of co.
+func verifyRekorV2Entry(ctx context.Context, e *rekorv2.Entry, co *cosign.CheckOpts, cert *x509.Certificate, digest []byte, sig string) error {
+	if err := cosign.VerifyRekorV2Entry(ctx, e, co); err != nil {
+		return err
+	}
+	rawSig, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	var verifier []byte
+	switch {
+	case cert != nil:
+		verifier = cert.Raw
+	case co.SigVerifier != nil:
+		pub, err := co.SigVerifier.PublicKey()
+		if err != nil {
+			return err
+		}
+		if verifier, err = cryptoutils.MarshalPublicKeyToDER(pub); err != nil {
+			return err
+		}
+	}
+	return e.MatchHashedRekord(digest, rawSig, verifier)
+}
+
 // base64signature returns the base64 encoded signature
-func base64signature(sigRef, bundlePath string) (string, error) {
+func base64signature(in *blobInputs, sigRef, bundlePath string) (string, error) {
 	var targetSig []byte
 	var err error
 	switch {
 	case sigRef != "":
-		targetSig, err = blob.LoadFileOrURL(sigRef)
+		targetSig, err = in.load(sigRef)
 		if err != nil {
 			if !os.IsNotExist(err) {
 				// ignore if file does not exist, it can be a base64 encoded string as well
@@ -319,7 +454,7 @@ func base64signature(sigRef, bundlePath string) (string, error) {
 			targetSig = []byte(sigRef)
 		}
 	case bundlePath != "":
-		b, err := cosign.FetchLocalSignedPayloadFromPath(bundlePath)
+		b, err := in.loadBundle(bundlePath)
 		if err != nil {
 			return "", err
 		}
@@ -338,7 +473,7 @@ func payloadBytes(blobRef string) ([]byte, error) {
 	var blobBytes []byte
 	var err error
 	if blobRef == "-" {
-		blobBytes, err = io.ReadAll(os.Stdin)
+		blobBytes, err = io.ReadAll(stdin)
 	} else {
 		blobBytes, err = blob.LoadFileOrURL(blobRef)
 	}
@@ -26,17 +26,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
-	internal "github.com/sigstore/cosign/v2/internal/pkg/cosign"
-	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
 	"github.com/sigstore/cosign/v2/pkg/blob"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
 	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pivkey"
 	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
@@ -61,18 +62,34 @@ type VerifyBlobAttestationCommand struct {
 	CertGithubWorkflowRepository string
 	CertGithubWorkflowRef        string
 
-	IgnoreSCT  bool
-	SCTRef     string
-	Offline    bool
-	IgnoreTlog bool
+	IgnoreSCT      bool
+	SCTRef         string
+	Offline        bool
+	IgnoreTlog     bool
+	Denylist       options.DenylistOptions
+	KeyUsagePolicy string
+	Witnesses      options.WitnessOptions
 
-	CheckClaims   bool
-	PredicateType string
+	CheckClaims      bool
+	PredicateType    string
+	PredicateSchemas string
+	PredicateSchema  string
+	PayloadTypes     []string
 	// TODO: Add policies
 
 	SignaturePath string // Path to the signature
+	// DigestAlgorithm is the name of the digest algorithm of the subject of
+	// the statement, see blob.LookupDigestAlgorithm.
+	DigestAlgorithm string
+
+	// LowMemory verifies the attestation with VerifyBlobAttestationStream.
+	LowMemory bool
 }
 
+// lowMemoryLimit is the soft memory limit of the Go runtime with LowMemory,
+// unless GOMEMLIMIT sets one.
+const lowMemoryLimit = 64 << 20
+
 // Exec runs the verification command
 func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath string) (err error) {
 	if options.NOf(c.SignaturePath, c.BundlePath) == 0 {
@@ -89,6 +106,15 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 		return &options.KeyParseError{}
 	}
 
+	if c.LowMemory {
+		return c.execLowMemory(ctx, artifactPath)
+	}
+
+	in, err := newBlobInputs(artifactPath, c.BundlePath, c.CertRef, c.SignaturePath)
+	if err != nil {
+		return err
+	}
+
 	var identities []cosign.Identity
 	if c.KeyRef == "" {
 		identities, err = c.Identities()
@@ -104,59 +130,60 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 		CertGithubWorkflowName:       c.CertGithubWorkflowName,
 		CertGithubWorkflowRepository: c.CertGithubWorkflowRepository,
 		CertGithubWorkflowRef:        c.CertGithubWorkflowRef,
-		IgnoreSCT:                    c.IgnoreSCT,
+		IgnoreSCT:                    c.IgnoreSCT || c.CARoots() != "",
+		IgnoreKeyUsage:               c.IgnoreKeyUsage,
+		AllowAnyEKU:                  c.AllowAnyEKU,
+		RequireNameConstraints:       c.RequireNameConstraints,
 		Offline:                      c.Offline,
 		IgnoreTlog:                   c.IgnoreTlog,
+		PayloadTypes:                 c.PayloadTypes,
+	}
+	if co.CertClaims, err = c.ClaimMatchers(); err != nil {
+		return err
+	}
+	if err := c.ApplyCTInclusion(co); err != nil {
+		return err
+	}
+	if err := c.ApplyChainPinning(co); err != nil {
+		return err
+	}
+	if err := c.ApplyClockSkew(co); err != nil {
+		return err
+	}
+	co.Denylist, err = loadDenylist(ctx, c.Denylist, nil, nil)
+	if err != nil {
+		return err
+	}
+	co.KeyUsage, err = loadKeyUsagePolicy(c.KeyUsagePolicy)
+	if err != nil {
+		return err
+	}
+	co.Witnesses, err = loadWitnesses(c.Witnesses)
+	if err != nil {
+		return err
+	}
+	schemas, err := c.loadPredicateSchemas()
+	if err != nil {
+		return err
 	}
 	var h v1.Hash
-	if c.CheckClaims {
+	if c.CheckClaims || co.Denylist != nil {
 		// Get the actual digest of the blob
-		var payload internal.HashReader
-		f, err := os.Open(filepath.Clean(artifactPath))
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-
-		payload = internal.NewHashReader(f, sha256.New())
-		if _, err := io.ReadAll(&payload); err != nil {
+		if h, err = c.artifactDigest(artifactPath, co); err != nil {
 			return err
 		}
-		digest := payload.Sum(nil)
-		h = v1.Hash{
-			Hex:       hex.EncodeToString(digest),
-			Algorithm: "sha256",
+		if c.CheckClaims {
+			co.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
 		}
-		co.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
 	}
 
 	// Set up TSA, Fulcio roots and tlog public keys and clients.
-	if c.RFC3161TimestampPath != "" && c.KeyOpts.TSACertChainPath == "" {
+	tsaChainPaths := tsaCertChainPaths(c.KeyOpts.TSACertChainPaths, c.KeyOpts.TSACertChainPath)
+	if c.RFC3161TimestampPath != "" && len(tsaChainPaths) == 0 {
 		return fmt.Errorf("timestamp-cert-chain is required to validate a rfc3161 timestamp bundle")
 	}
-	if c.KeyOpts.TSACertChainPath != "" {
-		_, err := os.Stat(c.TSACertChainPath)
-		if err != nil {
-			return fmt.Errorf("unable to open timestamp certificate chain file: %w", err)
-		}
-		// TODO: Add support for TUF certificates.
-		pemBytes, err := os.ReadFile(filepath.Clean(c.TSACertChainPath))
-		if err != nil {
-			return fmt.Errorf("error reading certification chain path file: %w", err)
-		}
-
-		leaves, intermediates, roots, err := tsa.SplitPEMCertificateChain(pemBytes)
-		if err != nil {
-			return fmt.Errorf("error splitting certificates: %w", err)
-		}
-		if len(leaves) > 1 {
-			return fmt.Errorf("certificate chain must contain at most one TSA certificate")
-		}
-		if len(leaves) == 1 {
-			co.TSACertificate = leaves[0]
-		}
-		co.TSAIntermediateCertificates = intermediates
-		co.TSARootCertificates = roots
+	if err := loadTSACertificateChains(co, tsaChainPaths); err != nil {
+		return err
 	}
 
 	if !c.IgnoreTlog {
@@ -175,10 +202,18 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 		}
 	}
 	if keylessVerification(c.KeyRef, c.Sk) {
-		// Use default TUF roots if a cert chain is not provided.
+		// Use the roots of a local CA if set, or the default TUF roots if a
+		// cert chain is not provided.
 		// This performs an online fetch of the Fulcio roots. This is needed
 		// for verifying keyless certificates (both online and offline).
-		if c.CertChain == "" {
+		switch {
+		case c.CARoots() != "":
+			roots, intermediates, err := localCACerts(&c.CertVerifyOptions)
+			if err != nil {
+				return err
+			}
+			co.SetCertificates(roots, intermediates)
+		case c.CertChain == "":
 			co.RootCerts, err = fulcio.GetRoots()
 			if err != nil {
 				return fmt.Errorf("getting Fulcio roots: %w", err)
@@ -189,7 +224,7 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 			}
 		}
 	}
-	if !c.IgnoreSCT {
+	if !co.IgnoreSCT {
 		co.CTLogPubKeys, err = cosign.GetCTLogPubs(ctx)
 		if err != nil {
 			return fmt.Errorf("getting ctlog public keys: %w", err)
@@ -197,7 +232,11 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 	}
 
 	var encodedSig []byte
-	if c.SignaturePath != "" {
+	switch c.SignaturePath {
+	case "":
+	case "-":
+		encodedSig = in.stdin
+	default:
 		encodedSig, err = os.ReadFile(filepath.Clean(c.SignaturePath))
 		if err != nil {
 			return fmt.Errorf("reading %s: %w", c.SignaturePath, err)
@@ -228,13 +267,13 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 			return fmt.Errorf("loading public key from token: %w", err)
 		}
 	case c.CertRef != "":
-		cert, err = loadCertFromFileOrURL(c.CertRef)
+		cert, err = in.loadCert(c.CertRef)
 		if err != nil {
 			return err
 		}
 	}
 	if c.BundlePath != "" {
-		b, err := cosign.FetchLocalSignedPayloadFromPath(c.BundlePath)
+		b, err := in.loadBundle(c.BundlePath)
 		if err != nil {
 			return err
 		}
@@ -294,11 +333,13 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 	// Set a cert chain if provided.
 	var chainPEM []byte
 	if c.CertChain != "" {
-		chain, err := loadCertChainFromFileOrURL(c.CertChain)
+		chain, anchorKey, err := loadCertChain(ctx, c.CertChain)
 		if err != nil {
 			return err
 		}
-		if chain == nil {
+		if anchorKey != nil {
+			setTrustAnchorKey(co, anchorKey)
+		} else if chain == nil {
 			return errors.New("expected certificate chain in --certificate-chain")
 		}
 		// Set the last one in the co.RootCerts. This is trusted, as its passed in
@@ -306,12 +347,14 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 		if co.RootCerts == nil {
 			co.RootCerts = x509.NewCertPool()
 		}
-		co.RootCerts.AddCert(chain[len(chain)-1])
-		// Use the whole as the cert chain in the signature object.
-		// The last one is omitted because it is considered the "root".
-		chainPEM, err = cryptoutils.MarshalCertificatesToPEM(chain)
-		if err != nil {
-			return err
+		if len(chain) > 0 {
+			co.RootCerts.AddCert(chain[len(chain)-1])
+			// Use the whole as the cert chain in the signature object.
+			// The last one is omitted because it is considered the "root".
+			chainPEM, err = cryptoutils.MarshalCertificatesToPEM(chain)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -340,10 +383,143 @@ func (c *VerifyBlobAttestationCommand) Exec(ctx context.Context, artifactPath st
 
 	// This checks the predicate type -- if no error is returned and no payload is, then
 	// the attestation is not of the given predicate type.
-	if b, gotPredicateType, err := policy.AttestationToPayloadJSON(ctx, c.PredicateType, signature); b == nil && err == nil {
-		return fmt.Errorf("invalid predicate type, expected %s got %s", c.PredicateType, gotPredicateType)
+	if b, gotPredicateType, err := policy.AttestationToPayloadJSON(ctx, c.PredicateType, signature); err == nil {
+		if b == nil {
+			return fmt.Errorf("invalid predicate type, expected %s got %s", c.PredicateType, gotPredicateType)
+		}
+		if err := schemas.ValidateStatement(b); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Verified OK")
+	return nil
+}
+
+// execLowMemory verifies the attestation without reading the envelope, or the
+// blob, into memory.
+func (c *VerifyBlobAttestationCommand) execLowMemory(ctx context.Context, artifactPath string) error {
+	switch {
+	case c.SignaturePath == "" || c.SignaturePath == "-":
+		return errors.New("--low-memory reads the DSSE envelope twice, from a --signature file")
+	case c.BundlePath != "" || c.CertRef != "":
+		return errors.New("--low-memory doesn't support --bundle or --certificate, provide a key with --key or --sk")
+	case c.KeyRef == "" && !c.Sk:
+		return errors.New("--low-memory needs a key, provide it with --key or --sk")
+	case !c.IgnoreTlog:
+		return errors.New("--low-memory doesn't support the transparency log, which needs the whole envelope, set --insecure-ignore-tlog")
+	case c.RFC3161TimestampPath != "":
+		return errors.New("--low-memory doesn't support --rfc3161-timestamp")
+	}
+	schemas, err := c.loadPredicateSchemas()
+	if err != nil {
+		return err
+	}
+	predicateType, err := options.ParsePredicateType(c.PredicateType)
+	if err != nil {
+		predicateType = c.PredicateType
+	}
+	if schemas.Has(predicateType) {
+		return errors.New("--low-memory doesn't validate predicate schemas, which needs the whole predicate")
+	}
+	// SetMemoryLimit(-1) only returns the limit, which is math.MaxInt64 if
+	// there is none.
+	if debug.SetMemoryLimit(-1) == math.MaxInt64 {
+		debug.SetMemoryLimit(lowMemoryLimit)
+	}
+
+	co := &cosign.CheckOpts{IgnoreTlog: true, PayloadTypes: c.PayloadTypes}
+	if co.Denylist, err = loadDenylist(ctx, c.Denylist, nil, nil); err != nil {
+		return err
+	}
+	if co.KeyUsage, err = loadKeyUsagePolicy(c.KeyUsagePolicy); err != nil {
+		return err
+	}
+	if c.KeyRef != "" {
+		co.SigVerifier, err = sigs.PublicKeyFromKeyRef(ctx, c.KeyRef)
+		if err != nil {
+			return fmt.Errorf("loading public key: %w", err)
+		}
+		if pkcs11Key, ok := co.SigVerifier.(*pkcs11key.Key); ok {
+			defer pkcs11Key.Close()
+		}
+	} else {
+		sk, err := pivkey.GetKeyWithSlot(c.Slot)
+		if err != nil {
+			return fmt.Errorf("opening piv token: %w", err)
+		}
+		defer sk.Close()
+		co.SigVerifier, err = sk.Verifier()
+		if err != nil {
+			return fmt.Errorf("loading public key from token: %w", err)
+		}
+	}
+
+	var h v1.Hash
+	if c.CheckClaims || co.Denylist != nil {
+		if h, err = c.artifactDigest(artifactPath, co); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(filepath.Clean(c.SignaturePath))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", c.SignaturePath, err)
+	}
+	defer f.Close()
+	so := cosign.StreamOpts{CheckClaims: c.CheckClaims, PredicateType: predicateType}
+	if err := cosign.VerifyBlobAttestationStream(ctx, f, h, so, co); err != nil {
+		return err
 	}
 
 	fmt.Fprintln(os.Stderr, "Verified OK")
 	return nil
 }
+
+// loadPredicateSchemas loads the predicate schemas, and the --predicate-schema
+// of the predicate type.
+// artifactDigest returns the digest of the artifact at artifactPath with the
+// digest algorithm of c, as named in the subjects of statements. With another
+// algorithm than the default, the SHA-256 digest of the artifact is checked
+// against the denylist of co too.
+func (c *VerifyBlobAttestationCommand) artifactDigest(artifactPath string, co *cosign.CheckOpts) (v1.Hash, error) {
+	alg, err := blob.LookupDigestAlgorithm(c.DigestAlgorithm)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+	var r io.Reader = stdin
+	if artifactPath != "-" {
+		f, err := os.Open(filepath.Clean(artifactPath))
+		if err != nil {
+			return v1.Hash{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+	h, sha := alg.New(), sha256.New()
+	var w io.Writer = h
+	if !alg.IsDefault() {
+		w = io.MultiWriter(h, sha)
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		return v1.Hash{}, err
+	}
+	if !alg.IsDefault() {
+		if err := co.Denylist.CheckDigest(v1.Hash{Algorithm: "sha256", Hex: hex.EncodeToString(sha.Sum(nil))}); err != nil {
+			return v1.Hash{}, err
+		}
+	}
+	return v1.Hash{Algorithm: alg.InTotoName, Hex: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+func (c *VerifyBlobAttestationCommand) loadPredicateSchemas() (*attestation.PredicateSchemas, error) {
+	schemas, err := cosign.LoadPredicateSchemas(c.PredicateSchemas, nil)
+	if err != nil || c.PredicateSchema == "" {
+		return schemas, err
+	}
+	predicateType, err := options.ParsePredicateType(c.PredicateType)
+	if err != nil {
+		return nil, err
+	}
+	return cosign.AddPredicateSchema(schemas, predicateType, c.PredicateSchema)
+}
@@ -179,3 +179,81 @@ func TestVerifyBlobAttestationNoCheckClaims(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyBlobAttestationLowMemory(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+
+	blobPath := writeBlobFile(t, td, blobContents, "blob")
+	anotherBlobPath := writeBlobFile(t, td, anotherBlobContents, "other-blob")
+	keyRef := writeBlobFile(t, td, pubkey, "cosign.pub")
+
+	tests := []struct {
+		description   string
+		blobPath      string
+		signature     string
+		predicateType string
+		ignoreTlog    bool
+		shouldErr     bool
+	}{
+		{
+			description:   "verify a slsaprovenance predicate",
+			predicateType: "slsaprovenance",
+			blobPath:      blobPath,
+			signature:     blobSLSAProvenanceSignature,
+			ignoreTlog:    true,
+		}, {
+			description:   "fail with incorrect predicate",
+			predicateType: "custom",
+			blobPath:      blobPath,
+			signature:     blobSLSAProvenanceSignature,
+			ignoreTlog:    true,
+			shouldErr:     true,
+		}, {
+			description: "fail with incorrect blob",
+			blobPath:    anotherBlobPath,
+			signature:   blobSLSAProvenanceSignature,
+			ignoreTlog:  true,
+			shouldErr:   true,
+		}, {
+			description:   "dsse envelope has multiple subjects, one is valid",
+			predicateType: "slsaprovenance",
+			blobPath:      blobPath,
+			signature:     dssePredicateMultipleSubjects,
+			ignoreTlog:    true,
+		}, {
+			description:   "dsse envelope has multiple subjects, none has correct sha256 digest",
+			predicateType: "slsaprovenance",
+			blobPath:      blobPath,
+			signature:     dssePredicateMultipleSubjectsInvalid,
+			ignoreTlog:    true,
+			shouldErr:     true,
+		}, {
+			description:   "fail without --insecure-ignore-tlog",
+			predicateType: "slsaprovenance",
+			blobPath:      blobPath,
+			signature:     blobSLSAProvenanceSignature,
+			shouldErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			decodedSig, err := base64.StdEncoding.DecodeString(test.signature)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cmd := VerifyBlobAttestationCommand{
+				KeyOpts:       options.KeyOpts{KeyRef: keyRef},
+				SignaturePath: writeBlobFile(t, td, string(decodedSig), "signature"),
+				IgnoreTlog:    test.ignoreTlog,
+				CheckClaims:   true,
+				PredicateType: test.predicateType,
+				LowMemory:     true,
+			}
+			if err := cmd.Exec(ctx, test.blobPath); (err != nil) != test.shouldErr {
+				t.Fatalf("verifyBlobAttestation()= %v, expected shouldErr=%t ", err, test.shouldErr)
+			}
+		})
+	}
+}
@@ -81,7 +81,7 @@ func TestSignaturesRef(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			gotSig, err := base64signature(test.sigRef, "")
+			gotSig, err := base64signature(&blobInputs{}, test.sigRef, "")
 			if test.shouldErr && err != nil {
 				return
 			}
@@ -113,7 +113,7 @@ func TestSignaturesBundle(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	gotSig, err := base64signature("", fp)
+	gotSig, err := base64signature(&blobInputs{}, "", fp)
 	if err != nil {
 		t.Fatal(err)
 	}
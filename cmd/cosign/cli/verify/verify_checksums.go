@@ -0,0 +1,198 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+// VerifyChecksumsCmd verifies the signature of a checksum file, e.g. the
+// SHA256SUMS of a release, and then the artifacts against its checksums.
+type VerifyChecksumsCmd struct {
+	VerifyBlobCmd
+	Artifacts []string
+}
+
+// Exec verifies the checksum file at checksumsRef, a path, URL or - for
+// standard input, and the artifacts of c.
+func (c *VerifyChecksumsCmd) Exec(ctx context.Context, checksumsRef string) error {
+	if len(c.Artifacts) == 0 {
+		return errors.New("provide the artifacts to verify with --artifact")
+	}
+	if checksumsRef == "-" {
+		for _, ref := range []string{c.BundlePath, c.CertRef, c.SigRef} {
+			if ref == "-" {
+				return errors.New("only one of the checksum file, --bundle, --certificate and --signature can be read from standard input")
+			}
+		}
+	}
+	// The checksums are read once, so that those parsed are those verified.
+	b, err := payloadBytes(checksumsRef)
+	if err != nil {
+		return fmt.Errorf("reading checksum file: %w", err)
+	}
+	checksumsPath, err := writeTemp("cosign-checksums-*", b)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(checksumsPath)
+	if err := c.VerifyBlobCmd.Exec(ctx, checksumsPath); err != nil {
+		return fmt.Errorf("verifying the signature of the checksum file: %w", err)
+	}
+
+	checksums, err := parseChecksums(b)
+	if err != nil {
+		return fmt.Errorf("parsing checksum file: %w", err)
+	}
+	failed := 0
+	for _, artifact := range c.Artifacts {
+		if err := checksums.verify(artifact); err != nil {
+			ui.Warnf(ctx, "%s: FAILED: %v", artifact, err)
+			failed++
+			continue
+		}
+		ui.Infof(ctx, "%s: OK", artifact)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d artifacts failed verification against the checksum file", failed, len(c.Artifacts))
+	}
+	return nil
+}
+
+// checksumFile maps the names of the entries of a checksum file to their
+// hex-encoded checksums.
+type checksumFile map[string]string
+
+// parseChecksums parses the lines of a checksum file, as written by
+// sha256sum and sha512sum, "<checksum>  <name>" or "<checksum> *<name>", or
+// by BSD tools and --tag, "SHA256 (<name>) = <checksum>".
+func parseChecksums(b []byte) (checksumFile, error) {
+	checksums := checksumFile{}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var name, sum string
+		if bsdChecksum(line) {
+			i, j := strings.Index(line, " ("), strings.LastIndex(line, ") = ")
+			name, sum = line[i+2:j], line[j+4:]
+		} else {
+			fields := strings.SplitN(line, " ", 2)
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d isn't a checksum and a name", n)
+			}
+			sum, name = fields[0], strings.TrimPrefix(strings.TrimPrefix(fields[1], " "), "*")
+		}
+		sum = strings.ToLower(sum)
+		if _, err := hex.DecodeString(sum); err != nil || checksumHash(sum) == nil {
+			return nil, fmt.Errorf("line %d has an invalid SHA-256, SHA-384 or SHA-512 checksum", n)
+		}
+		name = filepath.ToSlash(filepath.Clean(name))
+		if prev, ok := checksums[name]; ok && prev != sum {
+			return nil, fmt.Errorf("line %d has another checksum of %s", n, name)
+		}
+		checksums[name] = sum
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(checksums) == 0 {
+		return nil, errors.New("no checksums")
+	}
+	return checksums, nil
+}
+
+func bsdChecksum(line string) bool {
+	for _, alg := range []string{"SHA256", "SHA384", "SHA512"} {
+		if strings.HasPrefix(line, alg+" (") && strings.Contains(line, ") = ") {
+			return true
+		}
+	}
+	return false
+}
+
+// checksumHash returns the hash of the hex-encoded checksum sum, by its
+// length.
+func checksumHash(sum string) hash.Hash {
+	switch len(sum) {
+	case 2 * sha256.Size:
+		return sha256.New()
+	case 2 * sha512.Size384:
+		return sha512.New384()
+	case 2 * sha512.Size:
+		return sha512.New()
+	}
+	return nil
+}
+
+// lookup returns the checksum of the artifact at path, the entry of the path
+// or else the only entry of its base name.
+func (c checksumFile) lookup(path string) (string, error) {
+	name := filepath.ToSlash(filepath.Clean(path))
+	if sum, ok := c[name]; ok {
+		return sum, nil
+	}
+	base := filepath.Base(name)
+	var found []string
+	for n := range c {
+		if n == base || strings.HasSuffix(n, "/"+base) {
+			found = append(found, n)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", errors.New("not in the checksum file")
+	case 1:
+		return c[found[0]], nil
+	}
+	return "", fmt.Errorf("several entries of the checksum file are named %s, verify it by its path", base)
+}
+
+func (c checksumFile) verify(path string) error {
+	want, err := c.lookup(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := checksumHash(want)
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("checksum %s doesn't match %s", got, want)
+	}
+	return nil
+}
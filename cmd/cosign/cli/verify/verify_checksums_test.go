@@ -0,0 +1,155 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestVerifyChecksumsCmd(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+	write := func(name string, b []byte) string {
+		p := filepath.Join(td, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := write("cosign.pub", pub)
+
+	linux := write("dist/app-linux.tar.gz", []byte("linux"))
+	darwin := write("dist/app-darwin.tar.gz", []byte("darwin"))
+	tampered := write("tampered/app-linux.tar.gz", []byte("tampered"))
+	unlisted := write("dist/app-windows.zip", []byte("windows"))
+	sum := sha256.Sum256([]byte("linux"))
+	sum512 := sha512.Sum512([]byte("darwin"))
+	checksums := []byte("# release v1.0.0\n" +
+		hex.EncodeToString(sum[:]) + "  app-linux.tar.gz\n" +
+		"SHA512 (dist/app-darwin.tar.gz) = " + hex.EncodeToString(sum512[:]) + "\n")
+	checksumsPath := write("SHA256SUMS", checksums)
+	raw, err := sv.SignMessage(bytes.NewReader(checksums))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigPath := write("SHA256SUMS.sig", []byte(base64.StdEncoding.EncodeToString(raw)))
+	otherSigPath := write("other.sig", []byte(base64.StdEncoding.EncodeToString([]byte("not a signature"))))
+
+	verify := func(sigRef string, artifacts ...string) error {
+		c := &VerifyChecksumsCmd{
+			VerifyBlobCmd: VerifyBlobCmd{KeyOpts: options.KeyOpts{KeyRef: keyPath}, SigRef: sigRef, IgnoreSCT: true, IgnoreTlog: true},
+			Artifacts:     artifacts,
+		}
+		return c.Exec(ctx, checksumsPath)
+	}
+	if err := verify(sigPath, linux, darwin); err != nil {
+		t.Errorf("Exec() = %v", err)
+	}
+	for name, tc := range map[string]struct {
+		sig       string
+		artifacts []string
+		want      string
+	}{
+		"another signature": {
+			sig:       otherSigPath,
+			artifacts: []string{linux},
+			want:      "verifying the signature of the checksum file",
+		},
+		"tampered": {
+			sig:       sigPath,
+			artifacts: []string{linux, tampered},
+			want:      "1 of 2 artifacts failed",
+		},
+		"unlisted": {
+			sig:       sigPath,
+			artifacts: []string{unlisted},
+			want:      "1 of 1 artifacts failed",
+		},
+		"no artifacts": {
+			sig:  sigPath,
+			want: "--artifact",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := verify(tc.sig, tc.artifacts...); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Exec() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := strings.Repeat("ab", sha256.Size)
+	c, err := parseChecksums([]byte(sum + " *bin/app\n" + strings.ToUpper(sum) + "  ./dist/app\n" + sum + "  other/app.tar.gz\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.lookup("bin/app"); err != nil || got != sum {
+		t.Errorf("lookup() = %q, %v, want %s", got, err, sum)
+	}
+	if _, err := c.lookup("app"); err == nil || !strings.Contains(err.Error(), "several entries") {
+		t.Errorf("lookup() of an ambiguous name = %v", err)
+	}
+	if _, err := c.lookup("downloads/app.tar.gz"); err != nil {
+		t.Errorf("lookup() by the file name = %v", err)
+	}
+
+	for name, content := range map[string]string{
+		"empty":          "# no checksums\n",
+		"no name":        sum + "\n",
+		"not hex":        strings.Repeat("zz", sha256.Size) + "  app\n",
+		"short":          "abcd  app\n",
+		"conflicting":    sum + "  app\n" + strings.Repeat("cd", sha256.Size) + "  app\n",
+		"bsd not hex":    "SHA256 (app) = xyz\n",
+		"another length": strings.Repeat("ab", 20) + "  app\n",
+	} {
+		if _, err := parseChecksums([]byte(content)); err == nil {
+			t.Errorf("parseChecksums() of %s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sigstore/cosign/v2/pkg/blob"
+)
+
+// execEmbedded verifies the executable or archive at blobRef with the bundle
+// embedded in it, as its signed contents are verified with a --bundle.
+func (c *VerifyBlobCmd) execEmbedded(ctx context.Context, blobRef string) error {
+	if c.BundlePath != "" || c.SigRef != "" {
+		return errors.New("--bundle and --signature can't be used with --embedded, the bundle is embedded in the blob")
+	}
+	b, err := payloadBytes(blobRef)
+	if err != nil {
+		return err
+	}
+	signed, bundle, err := blob.ExtractBundle(b)
+	if err != nil {
+		return fmt.Errorf("extracting the embedded bundle of %s: %w", blobRef, err)
+	}
+	signedPath, err := writeTemp("cosign-embedded-*", signed)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(signedPath)
+	bundlePath, err := writeTemp("cosign-embedded-*.bundle", bundle)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(bundlePath)
+
+	v := *c
+	v.Embedded = false
+	v.BundlePath = bundlePath
+	v.subject = blobRef
+	return v.Exec(ctx, signedPath)
+}
+
+// writeTemp writes b to a new temporary file named after pattern and returns
+// its path.
+func writeTemp(pattern string, b []byte) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
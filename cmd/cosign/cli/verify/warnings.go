@@ -0,0 +1,63 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// warningCollector gathers the VerificationWarnings raised during
+// verification, indexed by the base64 signature they were raised for.
+type warningCollector map[string][]cosign.VerificationWarning
+
+func (wc warningCollector) handle(sig oci.Signature, w cosign.VerificationWarning) {
+	b64sig, err := sig.Base64Signature()
+	if err != nil {
+		return
+	}
+	wc[b64sig] = append(wc[b64sig], w)
+}
+
+func (wc warningCollector) forSignature(sig oci.Signature) []cosign.VerificationWarning {
+	if wc == nil {
+		return nil
+	}
+	b64sig, err := sig.Base64Signature()
+	if err != nil {
+		return nil
+	}
+	return wc[b64sig]
+}
+
+// report prints the warnings raised for the verified signatures and, if
+// asErrors is set, turns them into a verification failure.
+func (wc warningCollector) report(ctx context.Context, imgRef string, verified []oci.Signature, asErrors bool) error {
+	count := 0
+	for _, sig := range verified {
+		for _, w := range wc.forSignature(sig) {
+			ui.Warnf(ctx, "%s: %s", imgRef, w)
+			count++
+		}
+	}
+	if asErrors && count > 0 {
+		return fmt.Errorf("%d verification warnings for %s treated as errors (--warnings-as-errors)", count, imgRef)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
+)
+
+// loadWitnesses loads the witnesses configured by o that must cosign the
+// transparency log checkpoints. It returns nil if no witnesses are
+// configured.
+func loadWitnesses(o options.WitnessOptions) (*rekorv2.WitnessPolicy, error) {
+	if o.Keys == "" {
+		return nil, nil
+	}
+	f, err := os.Open(o.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("opening witness keys: %w", err)
+	}
+	defer f.Close()
+	witnesses, err := rekorv2.ParseWitnessKeys(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing witness keys from %s: %w", o.Keys, err)
+	}
+	if o.Min < 1 || o.Min > len(witnesses) {
+		return nil, fmt.Errorf("--min-witnesses must be between 1 and the %d witnesses in %s", len(witnesses), o.Keys)
+	}
+	return &rekorv2.WitnessPolicy{Witnesses: witnesses, Min: o.Min}, nil
+}
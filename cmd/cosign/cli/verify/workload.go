@@ -0,0 +1,50 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/kubernetes"
+)
+
+// expandWorkloads replaces the k8s-workload://<namespace>/<kind>/<name>
+// references in images with the images the pods of their workloads
+// currently run, by digest, so that exactly what is deployed is verified.
+func expandWorkloads(ctx context.Context, images []string, localImage bool, opts []name.Option) ([]string, error) {
+	var expanded []string
+	for _, img := range images {
+		if !strings.HasPrefix(img, kubernetes.WorkloadReference) {
+			expanded = append(expanded, img)
+			continue
+		}
+		if localImage {
+			return nil, errors.New("--local-image can't be used with " + kubernetes.WorkloadReference + " references")
+		}
+		deployed, err := kubernetes.WorkloadImages(ctx, img, opts...)
+		if err != nil {
+			return nil, err
+		}
+		ui.Infof(ctx, "%s runs %d images: %s", img, len(deployed), strings.Join(deployed, ", "))
+		expanded = append(expanded, deployed...)
+	}
+	return expanded, nil
+}
@@ -0,0 +1,190 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	v1mutate "github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/proxy"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/internal/pkg/forensics"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestVerifyImagePolicy(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	prodSigner, prodKey := newTestKey(t)
+	devSigner, devKey := newTestKey(t)
+
+	prod := pushSigned(t, host, "app", map[string]string{"env": "prod"}, prodSigner)
+	dev := pushSigned(t, host, "app", nil, devSigner)
+	misrouted := pushSigned(t, host, "app", map[string]string{"env": "prod"}, devSigner)
+	other := pushSigned(t, host, "other", nil, devSigner)
+
+	p := &proxy.Policy{Images: []proxy.PolicyEntry{
+		{Glob: host + "/app", Labels: map[string]string{"env": "prod"}, Key: prodKey},
+		{Glob: host + "/app", Key: devKey},
+	}}
+	verified := map[string]bool{}
+	v := verify.VerifyCommand{
+		CheckClaims: true,
+		IgnoreTlog:  true,
+		IgnoreSCT:   true,
+		OnVerified: func(_ context.Context, ref name.Reference, _ []oci.Signature) error {
+			verified[ref.String()] = true
+			return nil
+		},
+	}
+	v.Route = routeImagePolicy(p, &v)
+
+	if err := v.Exec(ctx, []string{prod, dev}); err != nil {
+		t.Fatalf("Exec() = %v", err)
+	}
+	if !verified[prod] || !verified[dev] {
+		t.Errorf("verified %v, want %s and %s", verified, prod, dev)
+	}
+	if err := v.Exec(ctx, []string{misrouted}); err == nil || !strings.Contains(err.Error(), "no matching signatures") {
+		t.Errorf("Exec() of a prod image signed with the dev key = %v, want no matching signatures", err)
+	}
+	if err := v.Exec(ctx, []string{other}); err == nil || !strings.Contains(err.Error(), "no entry of the image policy matches") {
+		t.Errorf("Exec() of an image without a policy = %v", err)
+	}
+}
+
+func TestVerifyCache(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(registry.New())
+	host := strings.TrimPrefix(s.URL, "http://")
+	signer, key := newTestKey(t)
+	img := pushSigned(t, host, "app", nil, signer)
+
+	v := &verify.VerifyCommand{
+		KeyRef:      key,
+		CheckClaims: true,
+		IgnoreTlog:  true,
+		IgnoreSCT:   true,
+		Cache:       options.VerifyCacheOptions{Dir: t.TempDir(), TTL: time.Hour},
+		OnVerified: func(context.Context, name.Reference, []oci.Signature) error {
+			return nil
+		},
+	}
+	if err := v.Exec(ctx, []string{img}); err != nil {
+		t.Fatalf("Exec() = %v", err)
+	}
+	// The cached verification needs no registry.
+	s.Close()
+	if err := v.Exec(ctx, []string{img}); err != nil {
+		t.Errorf("Exec() of the cached verification = %v", err)
+	}
+	v.Recursive = true
+	if err := v.Exec(ctx, []string{img}); err == nil {
+		t.Error("Exec() with another scope: expected the verification to reach the registry and fail")
+	}
+}
+
+// pushSigned writes an image with labels to repo in the registry host,
+// signed by signer, and returns its digest reference.
+func pushSigned(t *testing.T, host, repo string, labels map[string]string, signer signature.Signer) string {
+	t.Helper()
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err = v1mutate.Config(img, v1.Config{Labels: labels})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(host + "/" + repo + "@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + ref.Context().String() + `"},"image":{"docker-manifest-digest":"` +
+		h.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	sigs, err := mutate.AppendSignatures(empty.Signatures(), signPayload(t, signer, payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTag, err := ociremote.SignatureTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(sigTag, sigs); err != nil {
+		t.Fatal(err)
+	}
+	return ref.String()
+}
+
+func TestVerifyFailureReport(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	host := strings.TrimPrefix(s.URL, "http://")
+	signer, _ := newTestKey(t)
+	_, otherKey := newTestKey(t)
+	img := pushSigned(t, host, "app", nil, signer)
+
+	dir := filepath.Join(t.TempDir(), "report")
+	cmd := Verify()
+	cmd.SetArgs([]string{"--key", otherKey, "--insecure-ignore-tlog", "--insecure-ignore-sct", "--failure-report", dir, img})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if err := cmd.ExecuteContext(context.Background()); err == nil {
+		t.Fatal("Execute() with another key: expected an error")
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		t.Fatalf("reading the failure report: %v", err)
+	}
+	idx := forensics.Index{}
+	if err := json.Unmarshal(b, &idx); err != nil {
+		t.Fatal(err)
+	}
+	kinds := map[string]int{}
+	for _, f := range idx.Files {
+		kinds[f.Kind]++
+	}
+	if idx.Error == "" || idx.Flags["key"] != otherKey || kinds["response"] == 0 || kinds["payload"] != 1 || kinds["signature"] != 1 || kinds["input"] != 1 {
+		t.Errorf("failure report = %s", b)
+	}
+}
@@ -0,0 +1,144 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/providers"
+)
+
+// builder identifies the build system that built cosign, and is set with
+// -ldflags "-X github.com/sigstore/cosign/v2/cmd/cosign/cli.builder=...".
+var builder = "unknown"
+
+// tufModule is the module that embeds the default TUF root.
+const tufModule = "github.com/sigstore/sigstore"
+
+// VersionInfo is the output of cosign version --json.
+type VersionInfo struct {
+	version.Info
+	Builder   string   `json:"builder"`
+	BuildTags []string `json:"buildTags"`
+	// ExperimentalFeatures are the enabled alpha and beta feature gates.
+	ExperimentalFeatures []string `json:"experimentalFeatures"`
+	// EmbeddedTUFRoot is the version of the module that embeds the TUF root
+	// cosign is initialized with.
+	EmbeddedTUFRoot string              `json:"embeddedTUFRoot"`
+	Algorithms      VersionAlgorithms   `json:"algorithms"`
+	Capabilities    VersionCapabilities `json:"capabilities"`
+}
+
+// VersionAlgorithms are the algorithms cosign supports.
+type VersionAlgorithms struct {
+	KeyGeneration    string   `json:"keyGeneration"`
+	PublicKeys       []string `json:"publicKeys"`
+	SignatureDigests []string `json:"signatureDigests"`
+}
+
+// VersionCapabilities are the pluggable providers this cosign binary was
+// built with, which the nokms and nocloud build tags leave out.
+type VersionCapabilities struct {
+	// KMSProviders are the key reference schemes of the KMS providers.
+	KMSProviders []string `json:"kmsProviders"`
+	// Keychains are the registry keychains of --k8s-keychain besides the
+	// default and GitHub ones.
+	Keychains     []string `json:"keychains"`
+	OIDCProviders []string `json:"oidcProviders"`
+}
+
+func Version() *cobra.Command {
+	o := &options.VersionOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Prints the version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := version.GetVersionInfo()
+			v.Name = cmd.Root().Name()
+			v.Description = cmd.Root().Short
+			if v.CheckFontName("starwars") {
+				v.FontName = "starwars"
+			}
+			cmd.SetOut(cmd.OutOrStdout())
+
+			if !o.JSON {
+				cmd.Println(v.String())
+				return nil
+			}
+			out, err := json.MarshalIndent(GetVersionInfo(v), "", "  ")
+			if err != nil {
+				return fmt.Errorf("unable to generate JSON from version info: %w", err)
+			}
+			cmd.Println(string(out))
+			return nil
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// GetVersionInfo extends v with the build provenance and capabilities of
+// this cosign binary.
+func GetVersionInfo(v version.Info) VersionInfo {
+	info := VersionInfo{
+		Info:                 v,
+		Builder:              builder,
+		BuildTags:            []string{},
+		ExperimentalFeatures: options.ExperimentalFeatures(),
+		EmbeddedTUFRoot:      "unknown",
+		Algorithms: VersionAlgorithms{
+			KeyGeneration:    "ecdsa-p256",
+			PublicKeys:       []string{"ecdsa", "ed25519", "rsa"},
+			SignatureDigests: options.SupportedSignatureAlgorithmNames(),
+		},
+		Capabilities: VersionCapabilities{
+			KMSProviders:  kms.SupportedProviders(),
+			Keychains:     options.RegisteredKeychains(),
+			OIDCProviders: providers.Names(),
+		},
+	}
+	sort.Strings(info.Capabilities.KMSProviders)
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range bi.Settings {
+		if s.Key == "-tags" && s.Value != "" {
+			info.BuildTags = strings.Split(s.Value, ",")
+		}
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == tufModule {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			info.EmbeddedTUFRoot = dep.Path + "@" + dep.Version
+		}
+	}
+	return info
+}
@@ -0,0 +1,67 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestVersionJSON(t *testing.T) {
+	for _, tt := range []struct {
+		experimental string
+		want         []string
+	}{
+		{"0", []string{}},
+		{"1", []string{"LayerSignatures", "OCI11Referrers", "RekorV2"}},
+	} {
+		t.Setenv(env.VariableExperimental.String(), tt.experimental)
+		cmd := Version()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"--json"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		var got VersionInfo
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+		}
+		if got.GoVersion == "" || got.Builder == "" {
+			t.Errorf("missing build provenance: %+v", got)
+		}
+		if !reflect.DeepEqual(got.ExperimentalFeatures, tt.want) {
+			t.Errorf("experimentalFeatures = %v, want %v", got.ExperimentalFeatures, tt.want)
+		}
+		if !reflect.DeepEqual(got.Algorithms.SignatureDigests, []string{"sha224", "sha256", "sha384", "sha512"}) {
+			t.Errorf("signatureDigests = %v", got.Algorithms.SignatureDigests)
+		}
+		if !reflect.DeepEqual(got.Capabilities.Keychains, []string{"google", "ecr", "acr", "alibaba-acr"}) {
+			t.Errorf("keychains = %v", got.Capabilities.Keychains)
+		}
+		for _, key := range []string{`"gitVersion"`, `"builder"`, `"embeddedTUFRoot"`, `"algorithms"`, `"kmsProviders"`, `"oidcProviders"`} {
+			if !strings.Contains(out.String(), key) {
+				t.Errorf("output is missing %s", key)
+			}
+		}
+	}
+}
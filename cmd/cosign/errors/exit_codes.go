@@ -33,3 +33,6 @@ const NonExistentTag = 11
 
 // Error verifying image due to no matching signature
 const NoMatchingSignature = 12
+
+// Error verifying images due to the --timeout deadline, with those left not evaluated
+const NotEvaluated = 13
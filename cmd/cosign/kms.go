@@ -0,0 +1,48 @@
+//go:build !nokms
+// +build !nokms
+
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/gcpauth"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+	"github.com/sigstore/sigstore/pkg/signature/kms/gcp"
+
+	// Register the provider-specific plugins, which the nokms tag leaves out
+	// of builds for embedded verifiers.
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/aws"
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/azure"
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/hashivault"
+)
+
+func init() {
+	// Replace the gcpkms:// provider, which uses the application default
+	// credentials, to use GCP KMS keys as the service account of
+	// --impersonate-service-account.
+	kms.AddProvider(gcp.ReferenceScheme, func(ctx context.Context, keyResourceID string, _ crypto.Hash, _ ...signature.RPCOption) (kms.SignerVerifier, error) {
+		opts, err := gcpauth.ClientOptions(gcpauth.KMSScope)
+		if err != nil {
+			return nil, err
+		}
+		return gcp.LoadSignerVerifier(ctx, keyResourceID, opts...)
+	})
+}
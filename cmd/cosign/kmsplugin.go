@@ -0,0 +1,23 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	// Register the kms-plugin:// provider for out-of-tree KMS plugins. It
+	// only runs executables, so unlike the providers of kms.go it is kept in
+	// nokms builds.
+	_ "github.com/sigstore/cosign/v2/pkg/signature/kmsplugin"
+)
@@ -20,22 +20,28 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/templates/term"
 	cosignError "github.com/sigstore/cosign/v2/cmd/cosign/errors"
 	"github.com/sigstore/cosign/v2/internal/ui"
-
-	// Register the provider-specific plugins
-	_ "github.com/sigstore/sigstore/pkg/signature/kms/aws"
-	_ "github.com/sigstore/sigstore/pkg/signature/kms/azure"
-	_ "github.com/sigstore/sigstore/pkg/signature/kms/gcp"
-	_ "github.com/sigstore/sigstore/pkg/signature/kms/hashivault"
+	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 )
 
 func main() {
+	// Cancel the context on the first interrupt, so that commands stop
+	// cleanly and report what they completed, and exit on the second.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	// Fix up flags to POSIX standard flags.
-	ctx := context.Background()
 	for i, arg := range os.Args {
 		if (strings.HasPrefix(arg, "-") && len(arg) == 2) || (strings.HasPrefix(arg, "--") && len(arg) >= 4) {
 			continue
@@ -61,16 +67,38 @@ func main() {
 		}
 	}
 
-	if err := cli.New().Execute(); err != nil {
+	if err := execute(ctx); err != nil {
+		if ctx.Err() != nil {
+			log.Print(ui.Sprintf(ctx, "interrupted: %v", err))
+			os.Exit(130)
+		}
+
+		// Explain how to fit uploads that are too large for the registry.
+		var limitErr *ociremote.UploadLimitError
+		if errors.As(err, &limitErr) {
+			log.Print(ui.Sprintf(ctx, "error during command execution: %v", err))
+			limitErr.WriteGuidance(term.NewResponsiveWriter(os.Stderr))
+			os.Exit(1)
+		}
+
 		// if the error is a `CosignError` then we want to use the exit code that
 		// is related to the type of error that has occurred.
 		var cosignError *cosignError.CosignError
 		if errors.As(err, &cosignError) {
-			log.Printf("error during command execution: %v", err)
+			log.Print(ui.Sprintf(ctx, "error during command execution: %v", err))
 			os.Exit(cosignError.ExitCode())
 		}
 
 		// we don't call os.Exit as Fatalf does both PrintF and os.Exit(1)
-		log.Fatalf("error during command execution: %v", err)
+		log.Fatal(ui.Sprintf(ctx, "error during command execution: %v", err))
 	}
 }
+
+// execute runs the cosign command of os.Args, and closes the PKCS11 sessions
+// it opened whether it succeeds, fails or panics: PersistentPostRun only runs
+// after commands that succeed, and main exits with os.Exit, which skips
+// deferred calls.
+func execute(ctx context.Context) error {
+	defer pkcs11key.CloseSessions()
+	return cli.New().ExecuteContext(ctx)
+}
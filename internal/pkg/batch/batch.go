@@ -0,0 +1,319 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package batch tracks the progress of operations on many items, such as
+// signing several images or every image of an index, so that an interrupted
+// or failed operation can report what it completed and be resumed, either
+// with --resume-from or from a state file with --resume.
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/events"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+// Progress records the items of a batch operation as they start and
+// complete. It is safe for concurrent use.
+type Progress struct {
+	mu sync.Mutex
+	// resumable is whether the operation has a --resume-from flag, and
+	// idempotent whether it skips items that are already done.
+	resumable  bool
+	idempotent bool
+	resumeFrom string
+	resumed    bool
+	skipped    int
+	started    []string
+	completed  map[string]bool
+	// began is when the operation started, startedAt when each item was
+	// started and took how long it took to complete, for the summary.
+	began     time.Time
+	startedAt map[string]time.Time
+	took      map[string]time.Duration
+	now       func() time.Time
+	// state, if set, is the state file that completed items are appended to,
+	// and previous are the items it recorded before this run.
+	state    *os.File
+	previous map[string]bool
+}
+
+// New returns a Progress for an operation that can only be resumed with a
+// state file, see UseStateFile.
+func New() *Progress {
+	return &Progress{
+		completed: map[string]bool{},
+		began:     time.Now(),
+		startedAt: map[string]time.Time{},
+		took:      map[string]time.Duration{},
+		now:       time.Now,
+	}
+}
+
+// Idempotent returns a Progress for an operation that is resumed by
+// re-running it, because it skips the items that are already done.
+func Idempotent() *Progress {
+	p := New()
+	p.idempotent = true
+	return p
+}
+
+// Resumable returns a Progress for an operation with a --resume-from flag.
+// The items before resumeFrom are skipped, see Skip.
+func Resumable(resumeFrom string) *Progress {
+	p := New()
+	p.resumable = true
+	p.resumeFrom = resumeFrom
+	return p
+}
+
+// UseStateFile appends the completed items to the file at path, one per
+// line. With resume, the items recorded there by a previous run are skipped;
+// without it, the file must not exist, so that a forgotten --resume doesn't
+// discard the progress of a previous run.
+func (p *Progress) UseStateFile(path string, resume bool) error {
+	if path == "" {
+		if resume {
+			return errors.New("--resume requires --state-file")
+		}
+		return nil
+	}
+	path = filepath.Clean(path)
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_EXCL
+	var previous []byte
+	if resume {
+		b, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("reading state file: %w", err)
+		}
+		previous = b
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("state file %s exists, pass --resume to continue the run it records or remove it", path)
+	}
+	if err != nil {
+		return fmt.Errorf("opening state file: %w", err)
+	}
+
+	// An item is only recorded once its line is complete, an interrupted
+	// write leaves a partial last line that is ignored and terminated.
+	lines := bytes.Split(previous, []byte("\n"))
+	if last := len(lines) - 1; len(lines[last]) > 0 {
+		if _, err := f.WriteString("\n"); err != nil {
+			f.Close()
+			return fmt.Errorf("writing state file: %w", err)
+		}
+	}
+	p.previous = map[string]bool{}
+	for _, line := range lines[:len(lines)-1] {
+		if len(line) > 0 {
+			p.previous[string(line)] = true
+		}
+	}
+	p.state = f
+	return nil
+}
+
+// Skip reports whether the item known by names was completed by a previous
+// run, because it is recorded in the state file or comes before the
+// --resume-from item.
+func (p *Progress) Skip(names ...string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, name := range names {
+		if p.previous[name] {
+			p.skip(names[0])
+			return true
+		}
+	}
+	if p.resumeFrom == "" || p.resumed {
+		return false
+	}
+	for _, name := range names {
+		if name == p.resumeFrom {
+			p.resumed = true
+			return false
+		}
+	}
+	p.skip(names[0])
+	return true
+}
+
+func (p *Progress) skip(item string) {
+	p.skipped++
+	events.Emit(events.Event{Type: events.Skip, Item: item})
+}
+
+// Start records that item was started.
+func (p *Progress) Start(item string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.started = append(p.started, item)
+	p.startedAt[item] = p.now()
+	events.Emit(events.Event{Type: events.Start, Item: item})
+}
+
+// Done records that item was completed, in the state file if there is one.
+func (p *Progress) Done(item string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.completed[item] = true
+	if _, ok := p.took[item]; !ok {
+		p.took[item] = p.now().Sub(p.startedAt[item])
+	}
+	if p.state != nil {
+		if _, err := p.state.WriteString(item + "\n"); err != nil {
+			return fmt.Errorf("recording %s in state file: %w", item, err)
+		}
+	}
+	events.Emit(events.Event{Type: events.Done, Item: item})
+	return nil
+}
+
+// Unchanged records that item needed no work, e.g. because it is already up
+// to date, in the state file if there is one, so that a resumed run doesn't
+// check it again. Unlike a completed item, it isn't in the summary.
+func (p *Progress) Unchanged(item string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	events.Emit(events.Event{Type: events.Skip, Item: item})
+	if p.state != nil {
+		if _, err := p.state.WriteString(item + "\n"); err != nil {
+			return fmt.Errorf("recording %s in state file: %w", item, err)
+		}
+	}
+	return nil
+}
+
+// Took records that item took d to complete, for items that were worked on
+// before they were started, such as those verified in parallel. It must be
+// called before Done.
+func (p *Progress) Took(item string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.took[item] = d
+}
+
+// Finish closes the state file and returns err, the result of the
+// operation. If the operation was interrupted, or failed with a state file,
+// it first reports the completed and pending items, where pending are the
+// items that were not started yet. Finally it prints the summary of an
+// operation on several items, see Summary.
+func (p *Progress) Finish(ctx context.Context, err error, pending ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err = p.finish(ctx, err, pending)
+	if serr := p.summarize(ctx, err, len(pending)); serr != nil && err == nil {
+		err = serr
+	}
+	return err
+}
+
+func (p *Progress) finish(ctx context.Context, err error, pending []string) error {
+	if p.state != nil {
+		if cerr := p.state.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("closing state file: %w", cerr)
+		}
+		if p.skipped > 0 {
+			ui.Infof(ctx, "Skipped %d items completed by a previous run", p.skipped)
+		}
+	}
+	if err == nil {
+		if p.resumeFrom != "" && !p.resumed {
+			err = fmt.Errorf("--resume-from %s is none of the items of this operation", p.resumeFrom)
+		}
+		p.emitFinish(err)
+		return err
+	}
+	p.emitFinish(err)
+	if ctx.Err() == nil && p.state == nil {
+		return err
+	}
+
+	var completed, inFlight []string
+	for _, item := range p.started {
+		if p.completed[item] {
+			completed = append(completed, item)
+		} else {
+			inFlight = append(inFlight, item)
+		}
+	}
+	unfinished := append(inFlight[:len(inFlight):len(inFlight)], pending...)
+
+	done, total := len(completed)+p.skipped, len(completed)+len(unfinished)+p.skipped
+	if total <= 1 && p.state == nil {
+		// There is nothing to resume in an operation on a single item.
+		return err
+	}
+	if ctx.Err() != nil {
+		ui.Warnf(ctx, "Interrupted after completing %d of %d items", done, total)
+	} else {
+		ui.Warnf(ctx, "Failed after completing %d of %d items", done, total)
+	}
+	if p.state != nil {
+		// Batches with a state file may be large, so only the items that
+		// were being worked on are listed.
+		if len(inFlight) > 0 {
+			ui.Infof(ctx, "Unfinished:\n  %s", strings.Join(inFlight, "\n  "))
+		}
+		ui.Infof(ctx, "The completed items are recorded in %s, re-run with --resume to continue.", p.state.Name())
+		return err
+	}
+	if len(completed) > 0 {
+		ui.Infof(ctx, "Completed:\n  %s", strings.Join(completed, "\n  "))
+	}
+	if len(unfinished) > 0 {
+		ui.Infof(ctx, "Pending:\n  %s", strings.Join(unfinished, "\n  "))
+		switch {
+		case p.resumable:
+			ui.Infof(ctx, "Re-run with --resume-from %s to continue.", unfinished[0])
+		case p.idempotent:
+			ui.Infof(ctx, "Re-run the command to continue, completed items are skipped.")
+		}
+	}
+	return err
+}
+
+// emitFinish emits a Fail event for the items that were started but not
+// completed if err is set, and the Finish event of the operation.
+func (p *Progress) emitFinish(err error) {
+	if !events.Enabled() {
+		return
+	}
+	completed := len(p.completed)
+	skipped := p.skipped
+	finish := events.Event{Type: events.Finish, Completed: &completed, Skipped: &skipped}
+	if err != nil {
+		finish.Error = err.Error()
+		for _, item := range p.started {
+			if !p.completed[item] {
+				events.Emit(events.Event{Type: events.Fail, Item: item, Error: err.Error()})
+			}
+		}
+	}
+	events.Emit(finish)
+}
@@ -0,0 +1,227 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/events"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+func TestSkip(t *testing.T) {
+	p := Resumable("b")
+	var got []string
+	for _, item := range []string{"a", "b", "c"} {
+		if !p.Skip(item) {
+			got = append(got, item)
+		}
+	}
+	if strings.Join(got, ",") != "b,c" {
+		t.Errorf("not skipped = %v, want [b c]", got)
+	}
+
+	p = Resumable("")
+	if p.Skip("a") {
+		t.Error("Skip() without --resume-from = true")
+	}
+
+	p = Resumable("alias")
+	if p.Skip("name", "alias") {
+		t.Error("Skip() of an item known by the --resume-from alias = true")
+	}
+}
+
+func TestFinish(t *testing.T) {
+	ctx := context.Background()
+	if err := Resumable("x").Finish(ctx, nil); err == nil {
+		t.Error("Finish() with an unreached --resume-from succeeded")
+	}
+	p := Resumable("x")
+	p.Skip("x")
+	if err := p.Finish(ctx, nil); err != nil {
+		t.Errorf("Finish() = %v", err)
+	}
+
+	want := errors.New("boom")
+	out := ui.RunWithTestCtx(func(ctx context.Context, _ ui.WriteFunc) {
+		if err := New().Finish(ctx, want); !errors.Is(err, want) {
+			t.Errorf("Finish() = %v, want %v", err, want)
+		}
+	})
+	if out != "" {
+		t.Errorf("Finish() reported an error that is no interruption:\n%s", out)
+	}
+}
+
+func TestFinishInterrupted(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		progress *Progress
+		hint     string
+	}{
+		{"resumable", Resumable("a"), "Re-run with --resume-from b"},
+		{"rerun", Idempotent(), "Re-run the command"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.progress
+			out := ui.RunWithTestCtx(func(ctx context.Context, _ ui.WriteFunc) {
+				ctx, cancel := context.WithCancel(ctx)
+				for _, item := range []string{"a", "b"} {
+					if p.Skip(item) {
+						continue
+					}
+					p.Start(item)
+					if item == "a" {
+						if err := p.Done(item); err != nil {
+							t.Fatal(err)
+						}
+					}
+				}
+				cancel()
+				if err := p.Finish(ctx, ctx.Err(), "c"); !errors.Is(err, context.Canceled) {
+					t.Errorf("Finish() = %v, want %v", err, context.Canceled)
+				}
+			})
+			for _, want := range []string{"Interrupted after completing 1 of 3 items", "Completed:\n  a\n", "Pending:\n  b\n  c\n", tt.hint} {
+				if !strings.Contains(out, want) {
+					t.Errorf("Finish() output is missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestStateFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state")
+
+	if err := New().UseStateFile("", true); err == nil {
+		t.Error("UseStateFile() with --resume and no state file succeeded")
+	}
+
+	p := New()
+	if err := p.UseStateFile(path, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range []string{"a", "b"} {
+		p.Start(item)
+		if err := p.Done(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.Start("c")
+	failed := errors.New("failed")
+	out := ui.RunWithTestCtx(func(ctx context.Context, _ ui.WriteFunc) {
+		if err := p.Finish(ctx, failed, "d"); !errors.Is(err, failed) {
+			t.Errorf("Finish() = %v, want %v", err, failed)
+		}
+	})
+	for _, want := range []string{"Failed after completing 2 of 4 items", "Unfinished:\n  c\n", "re-run with --resume"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Finish() output is missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := New().UseStateFile(path, false); err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Errorf("UseStateFile() of an existing state file without --resume = %v", err)
+	}
+
+	// An interrupted write leaves a partial line, which is not an item.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("c"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	p = New()
+	if err := p.UseStateFile(path, true); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, item := range []string{"a", "b", "c", "d"} {
+		if p.Skip(item) {
+			continue
+		}
+		got = append(got, item)
+		p.Start(item)
+		if err := p.Done(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if strings.Join(got, ",") != "c,d" {
+		t.Errorf("resumed items = %v, want [c d]", got)
+	}
+	// An item that needed no work is recorded too.
+	if err := p.Unchanged("e"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Finish(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a\nb\nc\nc\nd\ne\n"; string(b) != want {
+		t.Errorf("state file = %q, want %q", b, want)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	var buf bytes.Buffer
+	events.SetOutput(&buf)
+	defer events.SetOutput(nil)
+
+	p := Resumable("b")
+	for _, item := range []string{"a", "b", "c"} {
+		if p.Skip(item) {
+			continue
+		}
+		p.Start(item)
+		if item == "b" {
+			if err := p.Done(item); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	_ = p.Finish(context.Background(), errors.New("boom"))
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		e := events.Event{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("event %q: %v", line, err)
+		}
+		got = append(got, e.Type+" "+e.Item)
+		if e.Type == events.Finish && (*e.Completed != 1 || *e.Skipped != 1 || e.Error != "boom") {
+			t.Errorf("finish event = %s, want 1 completed, 1 skipped and the error", line)
+		}
+	}
+	if want := "skip a,start b,done b,start c,fail c,finish "; strings.Join(got, ",") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+}
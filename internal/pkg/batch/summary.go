@@ -0,0 +1,185 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/telemetry"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+// SummarySchema identifies the version of the schema of Summary. Fields are
+// only added within a version.
+const SummarySchema = "https://sigstore.dev/cosign/batch-summary/v1"
+
+// slowest is the number of the slowest items a summary lists.
+const slowest = 5
+
+// Summary is the telemetry of an operation on several items, printed when it
+// finishes and written as JSON to the file of SetSummaryFile. The requests
+// are those of the whole process, which runs a single operation.
+type Summary struct {
+	Schema string `json:"schema"`
+	// Completed, Failed, Skipped and Pending count the items by outcome:
+	// Failed are the items that were started but not completed when the
+	// operation failed or was interrupted, Pending those not started.
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+	Skipped   int    `json:"skipped"`
+	Pending   int    `json:"pending"`
+	Error     string `json:"error,omitempty"`
+	// DurationSeconds is how long the operation took.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Slowest are the completed items that took longest, slowest first.
+	Slowest []ItemDuration `json:"slowest"`
+	// RegistryRequests and RekorQueries count the requests to registries and
+	// to Rekor logs by host.
+	RegistryRequests map[string]int `json:"registryRequests"`
+	RekorQueries     map[string]int `json:"rekorQueries"`
+}
+
+// ItemDuration is how long an item took to complete.
+type ItemDuration struct {
+	Item    string  `json:"item"`
+	Seconds float64 `json:"seconds"`
+}
+
+var (
+	summaryMu   sync.Mutex
+	summaryFile string
+)
+
+// SetSummaryFile sets the file that the summary of the operation is written
+// to as JSON, or disables writing it if path is empty.
+func SetSummaryFile(path string) {
+	summaryMu.Lock()
+	defer summaryMu.Unlock()
+	summaryFile = path
+}
+
+// summary returns the summary of the operation, which failed with err, if
+// not nil, with pending items that were not started.
+func (p *Progress) summary(err error, pending int) Summary {
+	s := Summary{
+		Schema:           SummarySchema,
+		Completed:        len(p.completed),
+		Skipped:          p.skipped,
+		Pending:          pending,
+		DurationSeconds:  p.now().Sub(p.began).Seconds(),
+		Slowest:          []ItemDuration{},
+		RegistryRequests: telemetry.Requests(telemetry.Registry),
+		RekorQueries:     telemetry.Requests(telemetry.Rekor),
+	}
+	for _, item := range p.started {
+		if !p.completed[item] {
+			if err != nil {
+				s.Failed++
+			} else {
+				s.Pending++
+			}
+		}
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	for item := range p.completed {
+		s.Slowest = append(s.Slowest, ItemDuration{Item: item, Seconds: p.took[item].Seconds()})
+	}
+	sort.Slice(s.Slowest, func(i, j int) bool {
+		if s.Slowest[i].Seconds != s.Slowest[j].Seconds {
+			return s.Slowest[i].Seconds > s.Slowest[j].Seconds
+		}
+		return s.Slowest[i].Item < s.Slowest[j].Item
+	})
+	if len(s.Slowest) > slowest {
+		s.Slowest = s.Slowest[:slowest]
+	}
+	return s
+}
+
+// summarize prints the summary of an operation on several items, and
+// writes it to the summary file if there is one.
+func (p *Progress) summarize(ctx context.Context, err error, pending int) error {
+	s := p.summary(err, pending)
+	if s.Completed+s.Failed+s.Skipped+s.Pending > 1 {
+		s.print(ctx)
+	}
+
+	summaryMu.Lock()
+	path := summaryFile
+	summaryMu.Unlock()
+	if path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Clean(path), append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+	return nil
+}
+
+func (s Summary) print(ctx context.Context) {
+	ui.Infof(ctx, "Summary: %d completed, %d failed, %d skipped and %d pending in %s",
+		s.Completed, s.Failed, s.Skipped, s.Pending, seconds(s.DurationSeconds))
+	if len(s.Slowest) > 0 {
+		lines := make([]string, 0, len(s.Slowest))
+		for _, d := range s.Slowest {
+			lines = append(lines, fmt.Sprintf("%s (%s)", d.Item, seconds(d.Seconds)))
+		}
+		ui.Infof(ctx, "Slowest:\n  %s", strings.Join(lines, "\n  "))
+	}
+	if len(s.RegistryRequests) > 0 {
+		ui.Infof(ctx, "Registry requests:\n  %s", strings.Join(byCount(s.RegistryRequests), "\n  "))
+	}
+	if len(s.RekorQueries) > 0 {
+		ui.Infof(ctx, "Rekor queries:\n  %s", strings.Join(byCount(s.RekorQueries), "\n  "))
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
+
+// byCount returns "host: count" lines of counts, the largest count first.
+func byCount(counts map[string]int) []string {
+	hosts := make([]string, 0, len(counts))
+	for host := range counts {
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if counts[hosts[i]] != counts[hosts[j]] {
+			return counts[hosts[i]] > counts[hosts[j]]
+		}
+		return hosts[i] < hosts[j]
+	})
+	lines := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		lines = append(lines, fmt.Sprintf("%s: %d", host, counts[host]))
+	}
+	return lines
+}
@@ -0,0 +1,121 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/telemetry"
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+func TestSummary(t *testing.T) {
+	telemetry.Reset()
+	defer telemetry.Reset()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+	client := &http.Client{Transport: telemetry.Transport(http.DefaultTransport, telemetry.Registry)}
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(s.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	SetSummaryFile(path)
+	defer SetSummaryFile("")
+
+	clock := time.Unix(0, 0)
+	p := Resumable("b")
+	p.began, p.now = clock, func() time.Time { return clock }
+	for i, item := range []string{"a", "b", "c", "d", "e"} {
+		if p.Skip(item) {
+			continue
+		}
+		p.Start(item)
+		clock = clock.Add(time.Duration(i) * time.Second)
+		if item == "d" {
+			// Verified in parallel, before it was started.
+			p.Took(item, 10*time.Second)
+		}
+		if item == "e" {
+			break
+		}
+		if err := p.Done(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	boom := errors.New("boom")
+	out := ui.RunWithTestCtx(func(ctx context.Context, _ ui.WriteFunc) {
+		if err := p.Finish(ctx, boom, "f", "g"); !errors.Is(err, boom) {
+			t.Errorf("Finish() = %v, want %v", err, boom)
+		}
+	})
+	host := strings.TrimPrefix(s.URL, "http://")
+	for _, want := range []string{
+		"Summary: 3 completed, 1 failed, 1 skipped and 2 pending in 10s",
+		"Slowest:\n  d (10s)\n  c (2s)\n  b (1s)\n",
+		"Registry requests:\n  " + host + ": 3\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Finish() output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Rekor queries") {
+		t.Errorf("Finish() output lists Rekor queries, none were made:\n%s", out)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Summary{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("summary file %q: %v", b, err)
+	}
+	if got.Schema != SummarySchema || got.Completed != 3 || got.Failed != 1 || got.Skipped != 1 || got.Pending != 2 ||
+		got.Error != "boom" || got.DurationSeconds != 10 || len(got.Slowest) != 3 || got.RegistryRequests[host] != 3 {
+		t.Errorf("summary file = %s", b)
+	}
+}
+
+func TestSummarySingleItem(t *testing.T) {
+	out := ui.RunWithTestCtx(func(ctx context.Context, _ ui.WriteFunc) {
+		p := New()
+		p.Start("a")
+		if err := p.Done("a"); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.Finish(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "" {
+		t.Errorf("Finish() of a single item printed a summary:\n%s", out)
+	}
+}
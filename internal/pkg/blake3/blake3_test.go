@@ -0,0 +1,74 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blake3
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// The vectors of the BLAKE3 test suite, whose inputs are the repeating
+// bytes 0, 1, ..., 250.
+var testVectors = []struct {
+	len  int
+	want string
+}{
+	{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+	{1, "2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"},
+	{1023, "10108970eeda3eb932baac1428c7a2163b0e924c9a9e25b35bba72b28f70bd11"},
+	{1024, "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+	{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+	{2048, "e776b6028c7cd22a4d0ba182a8bf62205d2ef576467e838ed6f2529b85fba24a"},
+	{2049, "5f4d72f40d7a5f82b15ca2b2e44b1de3c2ef86c426c95c1af0b6879522563030"},
+	{3072, "b98cb0ff3623be03326b373de6b9095218513e64f1ee2edd2525c7ad1e5cffd2"},
+	{3073, "7124b49501012f81cc7f11ca069ec9226cecb8a2c850cfe644e327d22d3e1cd3"},
+	{4096, "015094013f57a5277b59d8475c0501042c0b642e531b0a1c8f58d2163229e969"},
+	{31744, "62b6960e1a44bcc1eb1a611a8d6235b6b4b78f32e7abc4fb4c6cdcce94895c47"},
+	{102400, "bc3e3d41a1146b069abffad3c0d44860cf664390afce4d9661f7902e7943e085"},
+}
+
+func TestSum256(t *testing.T) {
+	for _, tv := range testVectors {
+		in := make([]byte, tv.len)
+		for i := range in {
+			in[i] = byte(i % 251)
+		}
+		if got := Sum256(in); hex.EncodeToString(got[:]) != tv.want {
+			t.Errorf("Sum256(%d bytes) = %x, want %s", tv.len, got, tv.want)
+		}
+
+		// Writing in odd-sized pieces, and summing in between, doesn't
+		// change the digest.
+		h := New()
+		for p := in; len(p) > 0; {
+			n := 333
+			if n > len(p) {
+				n = len(p)
+			}
+			h.Write(p[:n])
+			h.Sum(nil)
+			p = p[n:]
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != tv.want {
+			t.Errorf("Write(%d bytes) in pieces: Sum() = %s, want %s", tv.len, got, tv.want)
+		}
+		h.Reset()
+		h.Write(in)
+		if got := hex.EncodeToString(h.Sum(nil)); got != tv.want {
+			t.Errorf("after Reset(), Sum() of %d bytes = %s, want %s", tv.len, got, tv.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package budget caps the work cosign does in parallel and the rate of its
+// requests to registries and Rekor, as set in the budget config file, so that
+// operations on many images, such as verifying a --batch-file, copying an
+// index or fetching the signatures of an image, stay within the quotas of the
+// registries and Sigstore services they use. The budget is shared by every
+// parallel part of the command that runs.
+package budget
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/telemetry"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// ConfigFile is the format of the budget config file.
+type ConfigFile struct {
+	// Workers caps the number of operations any command runs in parallel.
+	Workers int `json:"workers,omitempty"`
+	// Commands caps it for the commands named, such as "verify" or
+	// "sbom attach", instead.
+	Commands map[string]int `json:"commands,omitempty"`
+	// Rate is the budget of all the requests to registries and Rekor.
+	Rate *Rate `json:"rate,omitempty"`
+	// Services are the budgets of the requests to each kind of service,
+	// "registry" or "rekor", on top of Rate.
+	Services map[string]Rate `json:"services,omitempty"`
+}
+
+// Rate is a budget of requests, of which up to Burst are sent at once and
+// RequestsPerSecond on average. Burst is RequestsPerSecond rounded up if it
+// is not positive.
+type Rate struct {
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Burst             int     `json:"burst,omitempty"`
+}
+
+func (r Rate) limiter() *rate.Limiter {
+	burst := r.Burst
+	if burst <= 0 {
+		burst = int(math.Ceil(r.RequestsPerSecond))
+	}
+	return rate.NewLimiter(rate.Limit(r.RequestsPerSecond), burst)
+}
+
+var (
+	mu       sync.Mutex
+	workers  int
+	limiters map[string]*rate.Limiter
+	total    *rate.Limiter
+)
+
+// ConfigPath returns the path of the budget config file, which is
+// $COSIGN_BUDGET_FILE or cosign/budget.json in the user's configuration
+// directory.
+func ConfigPath() string {
+	if path := env.Getenv(env.VariableBudgetFile); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cosign", "budget.json")
+}
+
+// Load reads the budget config file and applies its budget to command, the
+// path of the command that runs without the "cosign" prefix. A missing
+// config file at the default path sets no budget.
+func Load(command string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	set(config, command)
+	return nil
+}
+
+func loadConfig() (*ConfigFile, error) {
+	path := ConfigPath()
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && env.Getenv(env.VariableBudgetFile) == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading budget config: %w", err)
+	}
+	config := &ConfigFile{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(config); err != nil {
+		return nil, fmt.Errorf("parsing budget config %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("budget config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+func (c *ConfigFile) validate() error {
+	if c.Workers < 0 {
+		return fmt.Errorf("workers must not be negative, got %d", c.Workers)
+	}
+	for command, n := range c.Commands {
+		if n < 0 {
+			return fmt.Errorf("the workers of %q must not be negative, got %d", command, n)
+		}
+	}
+	if c.Rate != nil {
+		if err := c.Rate.validate(); err != nil {
+			return fmt.Errorf("rate: %w", err)
+		}
+	}
+	for kind, r := range c.Services {
+		if kind != telemetry.Registry && kind != telemetry.Rekor {
+			return fmt.Errorf("unknown service %q, want %q or %q", kind, telemetry.Registry, telemetry.Rekor)
+		}
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("%s rate: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+func (r Rate) validate() error {
+	if r.RequestsPerSecond <= 0 || math.IsInf(r.RequestsPerSecond, 0) || math.IsNaN(r.RequestsPerSecond) {
+		return fmt.Errorf("requestsPerSecond must be positive, got %v", r.RequestsPerSecond)
+	}
+	if r.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, got %d", r.Burst)
+	}
+	return nil
+}
+
+// set applies the budget of config to command, or removes the budget if
+// config is nil.
+func set(config *ConfigFile, command string) {
+	mu.Lock()
+	defer mu.Unlock()
+	workers, total, limiters = 0, nil, map[string]*rate.Limiter{}
+	if config == nil {
+		return
+	}
+	workers = config.Workers
+	if n, ok := config.Commands[command]; ok {
+		workers = n
+	}
+	if config.Rate != nil {
+		total = config.Rate.limiter()
+	}
+	for kind, r := range config.Services {
+		limiters[kind] = r.limiter()
+	}
+}
+
+// Workers returns the number of operations to run in parallel: requested, or
+// def if it is not positive, but at most the workers of the budget.
+func Workers(requested, def int) int {
+	n := requested
+	if n <= 0 {
+		n = def
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if workers > 0 && n > workers {
+		n = workers
+	}
+	return n
+}
+
+// Transport returns a transport that holds back the requests sent through
+// next, to a service of kind, until the budget of all the requests and of
+// the requests to kind allow them.
+func Transport(next http.RoundTripper, kind string) http.RoundTripper {
+	return &transport{next: next, kind: kind}
+}
+
+type transport struct {
+	next http.RoundTripper
+	kind string
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mu.Lock()
+	all, service := total, limiters[t.kind]
+	mu.Unlock()
+	for _, l := range []*rate.Limiter{all, service} {
+		if l == nil {
+			continue
+		}
+		if err := l.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,147 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package budget
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/telemetry"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func writeConfig(t *testing.T, config string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "budget.json")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(env.VariableBudgetFile.String(), path)
+	t.Cleanup(func() { set(nil, "") })
+}
+
+func TestWorkers(t *testing.T) {
+	writeConfig(t, `{"workers": 4, "commands": {"copy": 2, "verify": 0}}`)
+	for _, tc := range []struct {
+		command        string
+		requested, def int
+		want           int
+	}{
+		{command: "sign", requested: 0, def: 8, want: 4},
+		{command: "sign", requested: 3, def: 8, want: 3},
+		{command: "sign", requested: 16, def: 8, want: 4},
+		{command: "sign", requested: 0, def: 2, want: 2},
+		{command: "copy", requested: 0, def: 8, want: 2},
+		{command: "copy", requested: 16, def: 8, want: 2},
+		// 0 lifts the cap for the command.
+		{command: "verify", requested: 16, def: 8, want: 16},
+	} {
+		if err := Load(tc.command); err != nil {
+			t.Fatal(err)
+		}
+		if got := Workers(tc.requested, tc.def); got != tc.want {
+			t.Errorf("Workers(%d, %d) for %s = %d, want %d", tc.requested, tc.def, tc.command, got, tc.want)
+		}
+	}
+
+	set(nil, "")
+	if got := Workers(16, 8); got != 16 {
+		t.Errorf("Workers(16, 8) without a budget = %d, want 16", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv(env.VariableBudgetFile.String(), "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := Load("sign"); err != nil {
+		t.Errorf("Load() without a config file = %v", err)
+	}
+
+	t.Setenv(env.VariableBudgetFile.String(), filepath.Join(t.TempDir(), "missing.json"))
+	if err := Load("sign"); err == nil {
+		t.Error("Load() with a missing $COSIGN_BUDGET_FILE: expected an error")
+	}
+
+	for config, want := range map[string]string{
+		`{"workers": -1}`:                                                "workers must not be negative",
+		`{"commands": {"copy": -2}}`:                                     `the workers of "copy" must not be negative`,
+		`{"rate": {"requestsPerSecond": 0}}`:                             "requestsPerSecond must be positive",
+		`{"services": {"fulcio": {"requestsPerSecond": 1}}}`:             `unknown service "fulcio"`,
+		`{"services": {"rekor": {"requestsPerSecond": 1, "burst": -1}}}`: "rekor rate: burst must not be negative",
+		`{"parallelism": 2}`:                                             "unknown field",
+	} {
+		writeConfig(t, config)
+		if err := Load("sign"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load() with %s = %v, want %q", config, err, want)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+	get := func(kind string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := Transport(http.DefaultTransport, kind).RoundTrip(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// The budget allows a request a minute, so only the first of each is sent
+	// before the deadline.
+	writeConfig(t, `{"services": {"rekor": {"requestsPerSecond": 0.016}}}`)
+	if err := Load("verify"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := get(telemetry.Registry); err != nil {
+			t.Errorf("registry request %d = %v, want no budget", i, err)
+		}
+	}
+	if err := get(telemetry.Rekor); err != nil {
+		t.Errorf("first Rekor request = %v", err)
+	}
+	if err := get(telemetry.Rekor); err == nil {
+		t.Error("second Rekor request: expected it to exceed the budget")
+	}
+
+	writeConfig(t, `{"rate": {"requestsPerSecond": 0.016, "burst": 2}}`)
+	if err := Load("verify"); err != nil {
+		t.Fatal(err)
+	}
+	for i, kind := range []string{telemetry.Registry, telemetry.Rekor} {
+		if err := get(kind); err != nil {
+			t.Errorf("request %d = %v", i, err)
+		}
+	}
+	if err := get(telemetry.Registry); err == nil {
+		t.Error("third request: expected it to exceed the shared budget")
+	}
+}
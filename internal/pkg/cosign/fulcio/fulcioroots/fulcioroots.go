@@ -17,21 +17,26 @@ package fulcioroots
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
 
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tufcache"
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
-	"github.com/sigstore/sigstore/pkg/fulcioroots"
+	"github.com/sigstore/sigstore/pkg/tuf"
 )
 
 var (
-	rootsOnce        sync.Once
-	roots            *x509.CertPool
-	intermediates    *x509.CertPool
-	singletonRootErr error
+	rootsOnce         sync.Once
+	roots             *x509.CertPool
+	intermediates     *x509.CertPool
+	rootCerts         []*x509.Certificate
+	intermediateCerts []*x509.Certificate
+	singletonRootErr  error
 )
 
 // Get returns the Fulcio root certificate.
@@ -40,7 +45,7 @@ var (
 // there will be used instead of the normal Fulcio roots.
 func Get() (*x509.CertPool, error) {
 	rootsOnce.Do(func() {
-		roots, intermediates, singletonRootErr = initRoots()
+		roots, intermediates, rootCerts, intermediateCerts, singletonRootErr = initRoots()
 	})
 	return roots, singletonRootErr
 }
@@ -51,47 +56,78 @@ func Get() (*x509.CertPool, error) {
 // there will be used instead of the normal Fulcio intermediates.
 func GetIntermediates() (*x509.CertPool, error) {
 	rootsOnce.Do(func() {
-		roots, intermediates, singletonRootErr = initRoots()
+		roots, intermediates, rootCerts, intermediateCerts, singletonRootErr = initRoots()
 	})
 	return intermediates, singletonRootErr
 }
 
-func initRoots() (*x509.CertPool, *x509.CertPool, error) {
-	rootPool := x509.NewCertPool()
-	// intermediatePool should be nil if no intermediates are found
-	var intermediatePool *x509.CertPool
+// GetCertificates returns the Fulcio root and intermediate certificates,
+// those of the pools of Get and GetIntermediates.
+func GetCertificates() ([]*x509.Certificate, []*x509.Certificate, error) {
+	rootsOnce.Do(func() {
+		roots, intermediates, rootCerts, intermediateCerts, singletonRootErr = initRoots()
+	})
+	return rootCerts, intermediateCerts, singletonRootErr
+}
 
-	rootEnv := env.Getenv(env.VariableSigstoreRootFile)
-	if rootEnv != "" {
+// GetPEM returns the Fulcio root and intermediate certificates in PEM format,
+// for other verifiers to trust the same certificates as cosign.
+//
+// If the SIGSTORE_ROOT_FILE environment variable is set, the certificates
+// found there are returned instead of the normal Fulcio certificates.
+func GetPEM(ctx context.Context) ([]byte, error) {
+	if rootEnv := env.Getenv(env.VariableSigstoreRootFile); rootEnv != "" {
 		raw, err := os.ReadFile(rootEnv)
 		if err != nil {
-			return nil, nil, fmt.Errorf("error reading root PEM file: %w", err)
+			return nil, fmt.Errorf("error reading root PEM file: %w", err)
 		}
-		certs, err := cryptoutils.UnmarshalCertificatesFromPEM(raw)
-		if err != nil {
-			return nil, nil, fmt.Errorf("error unmarshalling certificates: %w", err)
-		}
-		for _, cert := range certs {
-			// root certificates are self-signed
-			if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
-				rootPool.AddCert(cert)
-			} else {
-				if intermediatePool == nil {
-					intermediatePool = x509.NewCertPool()
-				}
-				intermediatePool.AddCert(cert)
+		return raw, nil
+	}
+
+	// These are the targets that sigstore's fulcioroots reads.
+	targets, err := tufcache.GetTargetsByMeta(ctx, tuf.Fulcio, []string{"fulcio.crt.pem", "fulcio_v1.crt.pem", "fulcio_intermediate_v1.crt.pem"})
+	if err != nil {
+		return nil, fmt.Errorf("error getting targets: %w", err)
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("none of the Fulcio roots have been found")
+	}
+	var pems bytes.Buffer
+	for _, t := range targets {
+		pems.Write(bytes.TrimSpace(t.Target))
+		pems.WriteByte('\n')
+	}
+	return pems.Bytes(), nil
+}
+
+func initRoots() (*x509.CertPool, *x509.CertPool, []*x509.Certificate, []*x509.Certificate, error) {
+	rootPool := x509.NewCertPool()
+	// intermediatePool should be nil if no intermediates are found
+	var intermediatePool *x509.CertPool
+	var rootCerts, intermediateCerts []*x509.Certificate
+
+	// The certificates of SIGSTORE_ROOT_FILE, or of the TUF targets as
+	// sigstore's fulcioroots reads them but from the snapshot of the targets.
+	raw, err := GetPEM(context.Background())
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(raw)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("error unmarshalling certificates: %w", err)
+	}
+	for _, cert := range certs {
+		// root certificates are self-signed
+		if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+			rootPool.AddCert(cert)
+			rootCerts = append(rootCerts, cert)
+		} else {
+			if intermediatePool == nil {
+				intermediatePool = x509.NewCertPool()
 			}
-		}
-	} else {
-		var err error
-		rootPool, err = fulcioroots.Get()
-		if err != nil {
-			return nil, nil, err
-		}
-		intermediatePool, err = fulcioroots.GetIntermediates()
-		if err != nil {
-			return nil, nil, err
+			intermediatePool.AddCert(cert)
+			intermediateCerts = append(intermediateCerts, cert)
 		}
 	}
-	return rootPool, intermediatePool, nil
+	return rootPool, intermediatePool, rootCerts, intermediateCerts, nil
 }
@@ -61,6 +61,12 @@ func TestGetFulcioRoots(t *testing.T) {
 		// ignore deprecation error because certificates do not contain from SystemCertPool
 		t.Errorf("expected 1 intermediate certificate, got 0")
 	}
+
+	if roots, intermediates, err := GetCertificates(); err != nil {
+		t.Fatalf("failed to get certificates: %v", err)
+	} else if len(roots) != 1 || len(intermediates) != 1 {
+		t.Errorf("expected 1 root and 1 intermediate certificate, got %d and %d", len(roots), len(intermediates))
+	}
 }
 
 func TestGetFulcioRootsWithoutIntermediate(t *testing.T) {
@@ -0,0 +1,228 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tufcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sigstore/sigstore/pkg/tuf"
+	"github.com/syndtr/goleveldb/leveldb"
+	leveldberrors "github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/util"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// Backends of --tuf-cache, for where the TUF metadata and the snapshot of
+// its targets are kept.
+const (
+	// CacheUser keeps them in $TUF_ROOT or ~/.sigstore/root.
+	CacheUser = "user"
+	// CacheSystem serves them from the shared TUF cache of
+	// $COSIGN_TUF_SYSTEM_ROOT, which is only read. Once its snapshot doesn't
+	// have a target or expired, the cache is copied to a temporary directory
+	// and refreshed there, for the run.
+	CacheSystem = "system"
+	// CacheMemory keeps them in memory, refreshing them on every run.
+	CacheMemory = "memory"
+)
+
+// DefaultSystemRoot is the shared TUF cache of CacheSystem, if
+// $COSIGN_TUF_SYSTEM_ROOT isn't set.
+const DefaultSystemRoot = "/var/lib/sigstore/root"
+
+// corruptSuffix is appended to the names of the parts of the TUF cache
+// that recoverCache moves aside.
+const corruptSuffix = ".corrupt"
+
+var (
+	backend = CacheUser
+	// private is the copy of the shared TUF cache, once made.
+	private string
+	// checkOnce checks the TUF cache for corruption before it's first
+	// opened.
+	checkOnce = new(sync.Once)
+)
+
+// SetCache sets where the TUF metadata and the snapshot of its targets are
+// kept: CacheUser, the default, CacheSystem or CacheMemory.
+func SetCache(b string) error {
+	switch b {
+	case CacheUser, CacheSystem:
+	case CacheMemory:
+		// The TUF client keeps its metadata in memory with SIGSTORE_NO_CACHE.
+		if err := os.Setenv(tuf.SigstoreNoCache, "true"); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported TUF cache %q, expected user, system or memory", b)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	backend = b
+	return nil
+}
+
+// Close removes the copy of the shared TUF cache, if any.
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	if private != "" {
+		_ = os.RemoveAll(private)
+		private = ""
+	}
+}
+
+func systemRoot() string {
+	if dir := env.Getenv(env.VariableTUFSystemRoot); dir != "" {
+		return dir
+	}
+	return filepath.FromSlash(DefaultSystemRoot)
+}
+
+// copySystemCache copies the shared TUF cache, holding its lock shared so
+// that it isn't copied while it's written, to a temporary directory that
+// the TUF client reads and writes instead. It's called with mu held.
+func copySystemCache(ctx context.Context) error {
+	if private != "" {
+		return nil
+	}
+	src := systemRoot()
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("reading the shared TUF cache, written with TUF_ROOT=%s cosign initialize: %w", src, err)
+	}
+	unlock, err := lockDir(ctx, src, false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	dir, err := os.MkdirTemp("", "cosign-tuf-*")
+	if err != nil {
+		return err
+	}
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(dir, rel)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(dst, 0o700)
+		case rel == LockFile || !d.Type().IsRegular():
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dst, b, 0o600)
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return fmt.Errorf("copying the shared TUF cache %s: %w", src, err)
+	}
+	// The TUF client reads $TUF_ROOT whenever it opens the cache.
+	if err := os.Setenv(tuf.TufRootEnv, dir); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	private = dir
+	return nil
+}
+
+// recoverCache moves the TUF metadata and targets of the cache directory
+// aside if they're corrupt, such as when a run was killed while writing
+// them, so that the TUF client fetches them again rather than failing until
+// they're removed. It's called with the cache locked exclusively.
+func recoverCache(ctx context.Context, dir string) {
+	err := checkCache(dir)
+	if err == nil {
+		return
+	}
+	ui.Warnf(ctx, "The TUF cache %s is corrupt, %v. Moving it aside to fetch it again", dir, err)
+	for _, name := range []string{"tuf.db", "targets"} {
+		path := filepath.Join(dir, name)
+		_ = os.RemoveAll(path + corruptSuffix)
+		_ = os.Rename(path, path+corruptSuffix)
+	}
+}
+
+// checkCache returns why the TUF cache directory is corrupt: its metadata
+// can't be read, or a target doesn't match the length and hashes of its
+// metadata.
+func checkCache(dir string) error {
+	db, err := leveldb.OpenFile(filepath.Join(dir, "tuf.db"), &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		if leveldberrors.IsCorrupted(err) {
+			return fmt.Errorf("reading its metadata: %w", err)
+		}
+		// There is no cache yet, or it's open in a run of another version
+		// of cosign, which doesn't take the lock.
+		return nil
+	}
+	defer db.Close()
+
+	meta := map[string][]byte{}
+	it := db.NewIterator(nil, nil)
+	for it.Next() {
+		meta[string(it.Key())] = append([]byte(nil), it.Value()...)
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("reading its metadata: %w", err)
+	}
+	for name, b := range meta {
+		if !json.Valid(b) {
+			return fmt.Errorf("its metadata %s isn't JSON", name)
+		}
+	}
+	b, ok := meta["targets.json"]
+	if !ok {
+		return nil
+	}
+	var signed data.Signed
+	var targets data.Targets
+	if err := json.Unmarshal(b, &signed); err != nil {
+		return fmt.Errorf("parsing its metadata targets.json: %w", err)
+	}
+	if err := json.Unmarshal(signed.Signed, &targets); err != nil {
+		return fmt.Errorf("parsing its metadata targets.json: %w", err)
+	}
+	for name, tm := range targets.Targets {
+		// All the targets are written once the metadata is updated.
+		b, err := os.ReadFile(filepath.Join(dir, "targets", filepath.FromSlash(name)))
+		if err != nil {
+			return fmt.Errorf("reading its target %s: %w", name, err)
+		}
+		if err := util.BytesMatchLenAndHashes(b, tm.Length, tm.Hashes); err != nil {
+			return fmt.Errorf("its target %s doesn't match its metadata: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tufcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/tuf"
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestGetTargetWaitsForLock(t *testing.T) {
+	f := setup(t, RefreshAuto)
+	unlock, err := lockDir(context.Background(), rootCacheDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A run waiting for the lock times out with its context.
+	ctx, cancel := context.WithTimeout(context.Background(), 3*lockPoll)
+	defer cancel()
+	if _, err := GetTarget(ctx, "rekor.pub"); err == nil || !strings.Contains(err.Error(), "unlock the TUF cache") {
+		t.Errorf("GetTarget() with the cache locked = %v, want an error", err)
+	}
+
+	// Once the run holding the lock took the snapshot, it's served.
+	reset(RefreshAuto)
+	done := make(chan error)
+	go func() {
+		b, err := GetTarget(context.Background(), "rekor.pub")
+		if err == nil && string(b) != "old rekor key" {
+			t.Errorf("GetTarget() = %q, want the key of the snapshot taken by the other run", b)
+		}
+		done <- err
+	}()
+	time.Sleep(3 * lockPoll)
+	now := time.Now()
+	writeTestSnapshot(t, now, now.Add(time.Hour), map[string]string{"rekor.pub": "old rekor key"})
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if opened, _ := f.counts(); opened != 0 {
+		t.Errorf("TUF opened %d times, want the snapshot of the other run served", opened)
+	}
+}
+
+func TestGetTargetSystem(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t, RefreshAuto)
+	user := rootCacheDir()
+	system := t.TempDir()
+	t.Setenv(env.VariableTUFSystemRoot.String(), system)
+	if err := SetCache(CacheSystem); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	// Past half its validity, the shared snapshot isn't refreshed.
+	writeTestSnapshot(t, now.Add(-time.Hour), now.Add(time.Minute), map[string]string{"rekor.pub": "shared rekor key"})
+	if err := os.WriteFile(filepath.Join(system, "tuf.db"), []byte("metadata"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := GetTarget(ctx, "rekor.pub"); err != nil || string(b) != "shared rekor key" {
+		t.Errorf("GetTarget() = %q, %v, want the key of the shared snapshot", b, err)
+	}
+	Wait(time.Minute)
+	if opened, _ := f.counts(); opened != 0 {
+		t.Errorf("TUF opened %d times, want the shared snapshot served", opened)
+	}
+
+	// A target not in the shared snapshot is looked up in a copy of it.
+	f.set("ctfe.pub", "ctfe key")
+	if b, err := GetTarget(ctx, "ctfe.pub"); err != nil || string(b) != "ctfe key" {
+		t.Errorf("GetTarget() = %q, %v", b, err)
+	}
+	if private == "" || os.Getenv(tuf.TufRootEnv) != private {
+		t.Fatalf("%s = %s, want the copy %s", tuf.TufRootEnv, os.Getenv(tuf.TufRootEnv), private)
+	}
+	if b, err := os.ReadFile(filepath.Join(private, "tuf.db")); err != nil || string(b) != "metadata" {
+		t.Errorf("copy of the shared cache = %q, %v", b, err)
+	}
+	if s := readSnapshot(filepath.Join(system, SnapshotFile)); s == nil || len(s.Entries) != 1 {
+		t.Errorf("shared snapshot = %v, want it unchanged", s)
+	}
+	for _, dir := range []string{user, system} {
+		if _, err := os.Stat(filepath.Join(dir, LockFile)); !os.IsNotExist(err) {
+			t.Errorf("lock file written to %s: %v", dir, err)
+		}
+	}
+	copied := private
+	Close()
+	if _, err := os.Stat(copied); !os.IsNotExist(err) {
+		t.Errorf("copy of the shared cache not removed: %v", err)
+	}
+}
+
+func TestGetTargetSystemMissing(t *testing.T) {
+	setup(t, RefreshAuto)
+	t.Setenv(env.VariableTUFSystemRoot.String(), filepath.Join(t.TempDir(), "missing"))
+	if err := SetCache(CacheSystem); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetTarget(context.Background(), "rekor.pub"); err == nil || !strings.Contains(err.Error(), "cosign initialize") {
+		t.Errorf("GetTarget() without the shared cache = %v, want an error", err)
+	}
+}
+
+func TestSetCache(t *testing.T) {
+	t.Cleanup(func() { reset(RefreshAuto) })
+	t.Setenv(tuf.SigstoreNoCache, "")
+	for _, b := range []string{CacheUser, CacheSystem} {
+		if err := SetCache(b); err != nil {
+			t.Errorf("SetCache(%q) = %v", b, err)
+		}
+	}
+	if noCache() {
+		t.Errorf("%s set without the memory cache", tuf.SigstoreNoCache)
+	}
+	if err := SetCache(CacheMemory); err != nil || !noCache() {
+		t.Errorf("SetCache(memory) = %v, %s = %q", err, tuf.SigstoreNoCache, os.Getenv(tuf.SigstoreNoCache))
+	}
+	if err := SetCache("disk"); err == nil {
+		t.Error("SetCache(disk): expected an error")
+	}
+}
+
+// writeTestCache writes the TUF metadata of a target to the cache directory
+// dir, and the target.
+func writeTestCache(t *testing.T, dir, name, target string) {
+	t.Helper()
+	sum := sha256.Sum256([]byte(target))
+	targets, err := json.Marshal(map[string]any{"signed": map[string]any{
+		"_type":   "targets",
+		"targets": map[string]any{name: map[string]any{"length": len(target), "hashes": map[string]string{"sha256": hex.EncodeToString(sum[:])}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := leveldb.OpenFile(filepath.Join(dir, "tuf.db"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Put([]byte("targets.json"), targets, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "targets"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "targets", name), []byte(target), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCache(t *testing.T) {
+	dir := t.TempDir()
+	if err := checkCache(dir); err != nil {
+		t.Errorf("checkCache() without a cache = %v", err)
+	}
+	writeTestCache(t, dir, "rekor.pub", "rekor key")
+	if err := checkCache(dir); err != nil {
+		t.Errorf("checkCache() = %v", err)
+	}
+
+	// A target cut short by a killed run.
+	if err := os.WriteFile(filepath.Join(dir, "targets", "rekor.pub"), []byte("rekor"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkCache(dir); err == nil || !strings.Contains(err.Error(), "rekor.pub") {
+		t.Errorf("checkCache() with a truncated target = %v, want an error", err)
+	}
+	if err := os.Remove(filepath.Join(dir, "targets", "rekor.pub")); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkCache(dir); err == nil {
+		t.Error("checkCache() with a missing target: expected an error")
+	}
+
+	// Metadata that isn't JSON.
+	dir = t.TempDir()
+	db, err := leveldb.OpenFile(filepath.Join(dir, "tuf.db"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("root.json"), []byte("{\"signed\":"), nil); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if err := checkCache(dir); err == nil || !strings.Contains(err.Error(), "root.json") {
+		t.Errorf("checkCache() with truncated metadata = %v, want an error", err)
+	}
+}
+
+func TestRecoverCache(t *testing.T) {
+	dir := t.TempDir()
+	writeTestCache(t, dir, "rekor.pub", "rekor key")
+	if err := os.WriteFile(filepath.Join(dir, "remote.json"), []byte(`{"mirror":"https://tuf.example.com"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	recoverCache(context.Background(), dir)
+	if _, err := os.Stat(filepath.Join(dir, "tuf.db")); err != nil {
+		t.Errorf("a valid cache was moved aside: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "targets", "rekor.pub"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	recoverCache(context.Background(), dir)
+	for _, name := range []string{"tuf.db", "targets"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s of the corrupt cache not moved aside: %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, name+corruptSuffix)); err != nil {
+			t.Errorf("%s of the corrupt cache not kept: %v", name, err)
+		}
+	}
+	// The mirror set with cosign initialize is kept.
+	if _, err := os.Stat(filepath.Join(dir, "remote.json")); err != nil {
+		t.Errorf("remote.json removed: %v", err)
+	}
+}
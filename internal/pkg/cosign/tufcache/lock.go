@@ -0,0 +1,106 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tufcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockFile is the lock file in the TUF cache directory. Runs hold it
+// exclusively while they change the cache, and shared while they copy it.
+const LockFile = "cosign.lock"
+
+// lockPoll is how often a held lock is tried again.
+const lockPoll = 50 * time.Millisecond
+
+// errLocked is returned by tryLock if another process holds the lock.
+var errLocked = errors.New("locked by another process")
+
+// Lock locks the directory that the TUF client writes exclusively, waiting
+// for the other runs holding it until ctx is done, for commands that write
+// to it, as cosign initialize does. It returns the function that unlocks it.
+func Lock(ctx context.Context) (func(), error) {
+	if noCache() {
+		return func() {}, nil
+	}
+	return lockDir(ctx, userCacheDir(), true)
+}
+
+// lockDir locks dir, exclusively or shared, waiting until ctx is done. A
+// shared lock of a read-only directory without a lock file is a no-op.
+func lockDir(ctx context.Context, dir string, exclusive bool) (func(), error) {
+	f, err := openLockFile(dir, exclusive)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return func() {}, nil
+	}
+	for {
+		err := tryLock(f, exclusive)
+		if err == nil {
+			return func() { f.Close() }, nil
+		}
+		if !errors.Is(err, errLocked) {
+			f.Close()
+			return nil, fmt.Errorf("locking the TUF cache %s: %w", dir, err)
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, fmt.Errorf("waiting for another run to unlock the TUF cache %s: %w", dir, ctx.Err())
+		case <-time.After(lockPoll):
+		}
+	}
+}
+
+// tryLockDir locks dir exclusively if no other run holds its lock, and
+// returns false otherwise.
+func tryLockDir(dir string) (func(), bool) {
+	f, err := openLockFile(dir, true)
+	if err != nil || f == nil {
+		return nil, false
+	}
+	if err := tryLock(f, true); err != nil {
+		f.Close()
+		return nil, false
+	}
+	return func() { f.Close() }, true
+}
+
+func openLockFile(dir string, exclusive bool) (*os.File, error) {
+	path := filepath.Join(dir, LockFile)
+	if !exclusive {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return f, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating the TUF cache directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening the lock file of the TUF cache: %w", err)
+	}
+	return f, nil
+}
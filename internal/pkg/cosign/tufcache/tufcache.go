@@ -0,0 +1,439 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tufcache serves the TUF targets that cosign verifies with, such as
+// the Rekor and CT log public keys and the Fulcio certificates, from a
+// snapshot of them taken while the TUF metadata was valid. Verifying then
+// reads one file rather than waiting on the TUF repository, or on the checks
+// of its local metadata, on every run, and the snapshot is refreshed in the
+// background before it expires.
+//
+// The snapshot is written next to the TUF metadata, in $TUF_ROOT or
+// ~/.sigstore/root, and readable only by its owner, so it's as trusted as
+// the TUF cache itself. Runs sharing the cache, such as on a CI runner, hold
+// its LockFile while they write either, and a cache left corrupt is fetched
+// again.
+package tufcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+// Policies of --tuf-refresh, for when the TUF metadata is refreshed.
+const (
+	// RefreshNever serves the targets from the snapshot until it expires,
+	// without contacting the TUF repository.
+	RefreshNever = "never"
+	// RefreshAuto serves the targets from the snapshot while it's valid, and
+	// refreshes it in the background in the second half of its validity, or
+	// before serving them once it expired.
+	RefreshAuto = "auto"
+	// RefreshAlways refreshes the TUF metadata before the first target is
+	// served.
+	RefreshAlways = "always"
+)
+
+// SnapshotFile is the name of the snapshot in the TUF cache directory.
+const SnapshotFile = "cosign-targets.json"
+
+// snapshot is the SnapshotFile: the result of each lookup of the TUF targets
+// made so far, as of Refreshed, valid until the TUF metadata they were read
+// from expires.
+type snapshot struct {
+	Mirror    string           `json:"mirror"`
+	Refreshed time.Time        `json:"refreshed"`
+	Expires   time.Time        `json:"expires"`
+	Entries   map[string]entry `json:"entries"`
+}
+
+// refreshAt returns when the snapshot is refreshed in the background: half
+// way through its validity.
+func (s *snapshot) refreshAt() time.Time {
+	return s.Refreshed.Add(s.Expires.Sub(s.Refreshed) / 2)
+}
+
+// lookup is a lookup of targets: that called Name, or else those of Usage
+// or named Fallbacks.
+type lookup struct {
+	Name      string        `json:"name,omitempty"`
+	Usage     tuf.UsageKind `json:"usage,omitempty"`
+	Fallbacks []string      `json:"fallbacks,omitempty"`
+}
+
+func (l lookup) key() string {
+	if l.Name != "" {
+		return "target:" + l.Name
+	}
+	return "usage:" + l.Usage.String() + ":" + strings.Join(l.Fallbacks, ",")
+}
+
+// entry is the result of a lookup: its targets, or the error of the TUF
+// client, which only reads its local metadata once initialized, so that a
+// missing target isn't looked up again.
+type entry struct {
+	Lookup  lookup           `json:"lookup"`
+	Targets []tuf.TargetFile `json:"targets,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+func (e entry) result() ([]tuf.TargetFile, error) {
+	if e.Error != "" {
+		return nil, errors.New(e.Error)
+	}
+	return e.Targets, nil
+}
+
+// source is the subset of the TUF client that the targets are read from.
+type source interface {
+	GetTarget(name string) ([]byte, error)
+	GetTargetsByMeta(usage tuf.UsageKind, fallbacks []string) ([]tuf.TargetFile, error)
+}
+
+// openTUF returns the TUF client, refreshing its metadata if force is set or
+// it expired, its mirror and when its metadata expires.
+var openTUF = func(ctx context.Context, force bool) (source, string, time.Time, error) {
+	client, err := tuf.NewFromEnv(ctx)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	if force {
+		// Initializing forces a refresh of the metadata, rather than only
+		// once the timestamp expires.
+		if err := tuf.Initialize(ctx, client.Mirror(), nil); err != nil {
+			return nil, "", time.Time{}, fmt.Errorf("refreshing TUF repository %s: %w", client.Mirror(), err)
+		}
+	}
+	status, err := tuf.GetRootStatus(ctx)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	var expires time.Time
+	for role, md := range status.Metadata {
+		t, err := time.Parse(time.RFC822, md.Expiration)
+		if err != nil {
+			return nil, "", time.Time{}, fmt.Errorf("parsing the expiration of %s: %w", role, err)
+		}
+		if expires.IsZero() || t.Before(expires) {
+			expires = t
+		}
+	}
+	return client, client.Mirror(), expires, nil
+}
+
+var (
+	mu      sync.Mutex
+	refresh = RefreshAuto
+	// current is the snapshot, once read, or nil if there is none yet.
+	current *snapshot
+	read    bool
+	// refreshed is set once the metadata was refreshed, with RefreshAlways.
+	refreshed bool
+	// background is closed when the background refresh, if any, ends.
+	background chan struct{}
+)
+
+// SetRefresh sets when the TUF metadata is refreshed: RefreshNever,
+// RefreshAuto, the default, or RefreshAlways.
+func SetRefresh(policy string) error {
+	switch policy {
+	case RefreshNever, RefreshAuto, RefreshAlways:
+	default:
+		return fmt.Errorf("unsupported TUF refresh policy %q, expected never, auto or always", policy)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	refresh = policy
+	return nil
+}
+
+// GetTarget returns the target called name, as tuf.TUF.GetTarget does.
+func GetTarget(ctx context.Context, name string) ([]byte, error) {
+	targets, err := get(ctx, lookup{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return targets[0].Target, nil
+}
+
+// GetTargetsByMeta returns the targets of usage, or else those named
+// fallbacks, as tuf.TUF.GetTargetsByMeta does.
+func GetTargetsByMeta(ctx context.Context, usage tuf.UsageKind, fallbacks []string) ([]tuf.TargetFile, error) {
+	return get(ctx, lookup{Usage: usage, Fallbacks: fallbacks})
+}
+
+func get(ctx context.Context, l lookup) ([]tuf.TargetFile, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if noCache() {
+		// The TUF metadata is only kept in memory, and so is refreshed on
+		// every run anyway.
+		src, _, _, err := openTUF(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+		return l.fetch(src).result()
+	}
+
+	path := snapshotPath()
+	if !read {
+		current, read = readSnapshot(path), true
+	}
+	now := time.Now()
+	if refresh != RefreshAlways || refreshed {
+		if e, ok := current.entry(l); ok && now.Before(current.Expires) {
+			// The shared TUF cache is refreshed by the runs writing it.
+			if refresh == RefreshAuto && backend != CacheSystem && now.After(current.refreshAt()) {
+				refreshInBackground(path)
+			}
+			return e.result()
+		}
+	}
+	if refresh == RefreshNever {
+		switch {
+		case current == nil:
+			return nil, fmt.Errorf("there is no snapshot of the TUF targets in %s to use with --tuf-refresh never", path)
+		case !now.Before(current.Expires):
+			return nil, fmt.Errorf("the snapshot of the TUF targets in %s expired at %s, refresh it with --tuf-refresh auto", path, current.Expires.Format(time.RFC3339))
+		default:
+			return nil, fmt.Errorf("the snapshot of the TUF targets in %s doesn't have %s, refresh it with --tuf-refresh auto", path, l.key())
+		}
+	}
+
+	if backend == CacheSystem {
+		if err := copySystemCache(ctx); err != nil {
+			return nil, err
+		}
+		path = snapshotPath()
+	}
+	unlock, err := lockDir(ctx, rootCacheDir(), true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if refresh != RefreshAlways || refreshed {
+		// Another run may have taken the snapshot while this one waited for
+		// the lock.
+		s := readSnapshot(path)
+		if e, ok := s.entry(l); ok && now.Before(s.Expires) {
+			current = s
+			return e.result()
+		}
+	}
+	s, err := take(ctx, refresh == RefreshAlways, current, l)
+	if err != nil {
+		return nil, err
+	}
+	refreshed = true
+	current = s
+	if err := writeSnapshot(path, s); err != nil {
+		return nil, err
+	}
+	return s.Entries[l.key()].result()
+}
+
+// entry returns the result of l in s, if s is for the current mirror and has
+// it.
+func (s *snapshot) entry(l lookup) (entry, bool) {
+	if s == nil || s.Mirror != mirror() {
+		return entry{}, false
+	}
+	e, ok := s.Entries[l.key()]
+	return e, ok
+}
+
+func (l lookup) fetch(src source) entry {
+	e := entry{Lookup: l}
+	var err error
+	if l.Name != "" {
+		var b []byte
+		if b, err = src.GetTarget(l.Name); err == nil {
+			e.Targets = []tuf.TargetFile{{Target: b, Status: tuf.Active}}
+		}
+	} else {
+		e.Targets, err = src.GetTargetsByMeta(l.Usage, l.Fallbacks)
+	}
+	if err != nil {
+		e.Targets, e.Error = nil, err.Error()
+	}
+	return e
+}
+
+// take returns a new snapshot with the lookups of previous, if any, and
+// those of more. It's called with the TUF cache locked exclusively.
+func take(ctx context.Context, force bool, previous *snapshot, more ...lookup) (*snapshot, error) {
+	checkOnce.Do(func() { recoverCache(ctx, rootCacheDir()) })
+	src, m, expires, err := openTUF(ctx, force)
+	if err != nil {
+		return nil, err
+	}
+	s := &snapshot{Mirror: m, Refreshed: time.Now().UTC(), Expires: expires, Entries: map[string]entry{}}
+	if previous != nil && previous.Mirror == m {
+		for _, e := range previous.Entries {
+			more = append(more, e.Lookup)
+		}
+	}
+	for _, l := range more {
+		if _, ok := s.Entries[l.key()]; !ok {
+			s.Entries[l.key()] = l.fetch(src)
+		}
+	}
+	return s, nil
+}
+
+// refreshInBackground refreshes the snapshot at path, unless that has
+// already started. It's called with mu held.
+func refreshInBackground(path string) {
+	if background != nil {
+		return
+	}
+	done := make(chan struct{})
+	background = done
+	previous := current
+	go func() {
+		defer close(done)
+		// Another run holding the lock refreshes the snapshot itself.
+		unlock, ok := tryLockDir(filepath.Dir(path))
+		if !ok {
+			return
+		}
+		// The refresh outlives the lookup that started it, until Wait.
+		s, err := take(context.Background(), true, previous)
+		if err == nil {
+			_ = writeSnapshot(path, s)
+		}
+		// The lock is released first, as lookups wait for it holding mu.
+		unlock()
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		current = s
+	}()
+}
+
+// Wait waits up to timeout for the background refresh, if any, to end, so
+// that the next run uses the refreshed snapshot.
+func Wait(timeout time.Duration) {
+	mu.Lock()
+	done := background
+	mu.Unlock()
+	if done == nil {
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+func readSnapshot(path string) *snapshot {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	s := &snapshot{}
+	if err := json.Unmarshal(b, s); err != nil || s.Entries == nil {
+		// The snapshot is taken again.
+		return nil
+	}
+	return s
+}
+
+// writeSnapshot replaces the snapshot at path with s, so that concurrent
+// runs read either snapshot whole.
+func writeSnapshot(path string, s *snapshot) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating the TUF cache directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, SnapshotFile+".*")
+	if err != nil {
+		return fmt.Errorf("writing the snapshot of the TUF targets: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return fmt.Errorf("writing the snapshot of the TUF targets: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing the snapshot of the TUF targets: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("writing the snapshot of the TUF targets: %w", err)
+	}
+	return nil
+}
+
+// rootCacheDir returns the TUF cache directory, as the TUF client does, or
+// the shared one until it's copied.
+func rootCacheDir() string {
+	if backend == CacheSystem && private == "" {
+		return systemRoot()
+	}
+	return userCacheDir()
+}
+
+// userCacheDir returns the directory that the TUF client writes, $TUF_ROOT
+// or ~/.sigstore/root.
+func userCacheDir() string {
+	if dir := os.Getenv(tuf.TufRootEnv); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = ""
+	}
+	return filepath.Join(home, ".sigstore", "root")
+}
+
+func snapshotPath() string {
+	return filepath.Join(rootCacheDir(), SnapshotFile)
+}
+
+// mirror returns the TUF mirror set with cosign initialize, or the default
+// one, as the TUF client does.
+func mirror() string {
+	b, err := os.ReadFile(filepath.Join(rootCacheDir(), "remote.json"))
+	if err == nil {
+		var remote struct {
+			Mirror string `json:"mirror"`
+		}
+		if err := json.Unmarshal(b, &remote); err == nil && remote.Mirror != "" {
+			return remote.Mirror
+		}
+	}
+	return tuf.DefaultRemoteRoot
+}
+
+func noCache() bool {
+	b, err := strconv.ParseBool(os.Getenv(tuf.SigstoreNoCache))
+	return err == nil && b
+}
@@ -0,0 +1,297 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tufcache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+// fakeTUF is a TUF repository whose targets are the named ones, counting how
+// often it's opened.
+type fakeTUF struct {
+	mu      sync.Mutex
+	targets map[string]string
+	expires time.Time
+	opened  int
+	forced  int
+}
+
+func (f *fakeTUF) GetTarget(name string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.targets[name]
+	if !ok {
+		return nil, errors.New("target not found")
+	}
+	return []byte(t), nil
+}
+
+func (f *fakeTUF) GetTargetsByMeta(usage tuf.UsageKind, fallbacks []string) ([]tuf.TargetFile, error) {
+	var targets []tuf.TargetFile
+	for _, name := range fallbacks {
+		if b, err := f.GetTarget(name); err == nil {
+			targets = append(targets, tuf.TargetFile{Target: b, Status: tuf.Active})
+		}
+	}
+	return targets, nil
+}
+
+func (f *fakeTUF) set(name, target string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.targets[name] = target
+}
+
+func (f *fakeTUF) counts() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.opened, f.forced
+}
+
+// setup replaces the TUF client with a fake one, with an empty cache
+// directory and the package state reset.
+func setup(t *testing.T, policy string) *fakeTUF {
+	t.Helper()
+	t.Setenv(tuf.TufRootEnv, t.TempDir())
+	t.Setenv(tuf.SigstoreNoCache, "")
+	f := &fakeTUF{targets: map[string]string{"rekor.pub": "rekor key"}, expires: time.Now().Add(time.Hour)}
+	open := openTUF
+	openTUF = func(_ context.Context, force bool) (source, string, time.Time, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.opened++
+		if force {
+			f.forced++
+		}
+		return f, tuf.DefaultRemoteRoot, f.expires, nil
+	}
+	t.Cleanup(func() {
+		Wait(time.Minute)
+		openTUF = open
+		reset(RefreshAuto)
+	})
+	reset(policy)
+	return f
+}
+
+func reset(policy string) {
+	mu.Lock()
+	defer mu.Unlock()
+	refresh, current, read, refreshed, background = policy, nil, false, false, nil
+	backend, private, checkOnce = CacheUser, "", new(sync.Once)
+}
+
+// writeTestSnapshot writes a snapshot of the fake targets, refreshed at
+// refreshed and expiring at expires.
+func writeTestSnapshot(t *testing.T, refreshed, expires time.Time, targets map[string]string) {
+	t.Helper()
+	s := &snapshot{Mirror: tuf.DefaultRemoteRoot, Refreshed: refreshed, Expires: expires, Entries: map[string]entry{}}
+	for name, target := range targets {
+		l := lookup{Name: name}
+		s.Entries[l.key()] = entry{Lookup: l, Targets: []tuf.TargetFile{{Target: []byte(target), Status: tuf.Active}}}
+	}
+	if err := writeSnapshot(snapshotPath(), s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetTargetAuto(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t, RefreshAuto)
+
+	// Without a snapshot, it's taken.
+	b, err := GetTarget(ctx, "rekor.pub")
+	if err != nil || string(b) != "rekor key" {
+		t.Fatalf("GetTarget() = %q, %v", b, err)
+	}
+	if _, err := GetTargetsByMeta(ctx, tuf.Fulcio, []string{"rekor.pub"}); err != nil {
+		t.Fatal(err)
+	}
+	if opened, _ := f.counts(); opened != 2 {
+		t.Errorf("TUF opened %d times, want once for each lookup", opened)
+	}
+	fi, err := os.Stat(snapshotPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Errorf("snapshot mode = %v, want it readable only by its owner", fi.Mode().Perm())
+	}
+
+	// The next run serves both from the snapshot.
+	reset(RefreshAuto)
+	f.set("rekor.pub", "new rekor key")
+	if b, err := GetTarget(ctx, "rekor.pub"); err != nil || string(b) != "rekor key" {
+		t.Errorf("GetTarget() = %q, %v, want the key of the snapshot", b, err)
+	}
+	if targets, err := GetTargetsByMeta(ctx, tuf.Fulcio, []string{"rekor.pub"}); err != nil || len(targets) != 1 {
+		t.Errorf("GetTargetsByMeta() = %v, %v", targets, err)
+	}
+	if opened, _ := f.counts(); opened != 2 {
+		t.Errorf("TUF opened %d times, want the snapshot served", opened)
+	}
+
+	// A missing target isn't looked up again.
+	if _, err := GetTarget(ctx, "ctfe.pub"); err == nil {
+		t.Fatal("GetTarget() of a missing target: expected an error")
+	}
+	reset(RefreshAuto)
+	if _, err := GetTarget(ctx, "ctfe.pub"); err == nil || !strings.Contains(err.Error(), "target not found") {
+		t.Errorf("GetTarget() of a missing target = %v, want the error of the snapshot", err)
+	}
+	if opened, _ := f.counts(); opened != 3 {
+		t.Errorf("TUF opened %d times, want once for the missing target", opened)
+	}
+}
+
+func TestGetTargetBackgroundRefresh(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t, RefreshAuto)
+	now := time.Now()
+	writeTestSnapshot(t, now.Add(-time.Hour), now.Add(30*time.Minute), map[string]string{"rekor.pub": "old rekor key"})
+
+	// Past half its validity, the snapshot is served and refreshed.
+	b, err := GetTarget(ctx, "rekor.pub")
+	if err != nil || string(b) != "old rekor key" {
+		t.Fatalf("GetTarget() = %q, %v, want the key of the snapshot", b, err)
+	}
+	Wait(time.Minute)
+	if opened, forced := f.counts(); opened != 1 || forced != 1 {
+		t.Errorf("TUF opened %d times, %d forced, want one refresh", opened, forced)
+	}
+
+	reset(RefreshAuto)
+	if b, err := GetTarget(ctx, "rekor.pub"); err != nil || string(b) != "rekor key" {
+		t.Errorf("GetTarget() after the refresh = %q, %v, want the refreshed key", b, err)
+	}
+	if opened, _ := f.counts(); opened != 1 {
+		t.Errorf("TUF opened %d times, want the refreshed snapshot served", opened)
+	}
+}
+
+func TestGetTargetExpired(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t, RefreshAuto)
+	now := time.Now()
+	writeTestSnapshot(t, now.Add(-2*time.Hour), now.Add(-time.Hour), map[string]string{"rekor.pub": "old rekor key"})
+
+	if b, err := GetTarget(ctx, "rekor.pub"); err != nil || string(b) != "rekor key" {
+		t.Errorf("GetTarget() = %q, %v, want the refreshed key", b, err)
+	}
+	if opened, forced := f.counts(); opened != 1 || forced != 0 {
+		t.Errorf("TUF opened %d times, %d forced, want it opened once", opened, forced)
+	}
+}
+
+func TestGetTargetNever(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t, RefreshNever)
+
+	if _, err := GetTarget(ctx, "rekor.pub"); err == nil || !strings.Contains(err.Error(), "no snapshot") {
+		t.Errorf("GetTarget() without a snapshot = %v, want an error", err)
+	}
+
+	now := time.Now()
+	writeTestSnapshot(t, now.Add(-time.Hour), now.Add(time.Minute), map[string]string{"rekor.pub": "old rekor key"})
+	reset(RefreshNever)
+	if b, err := GetTarget(ctx, "rekor.pub"); err != nil || string(b) != "old rekor key" {
+		t.Errorf("GetTarget() = %q, %v, want the key of the snapshot", b, err)
+	}
+	if _, err := GetTarget(ctx, "ctfe.pub"); err == nil || !strings.Contains(err.Error(), "doesn't have") {
+		t.Errorf("GetTarget() of a target not in the snapshot = %v, want an error", err)
+	}
+
+	writeTestSnapshot(t, now.Add(-2*time.Hour), now.Add(-time.Hour), map[string]string{"rekor.pub": "old rekor key"})
+	reset(RefreshNever)
+	if _, err := GetTarget(ctx, "rekor.pub"); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("GetTarget() with an expired snapshot = %v, want an error", err)
+	}
+	if opened, _ := f.counts(); opened != 0 {
+		t.Errorf("TUF opened %d times, want never", opened)
+	}
+}
+
+func TestGetTargetAlways(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t, RefreshAlways)
+	now := time.Now()
+	writeTestSnapshot(t, now, now.Add(time.Hour), map[string]string{"rekor.pub": "old rekor key", "ctfe.pub": "old ctfe key"})
+	f.set("ctfe.pub", "ctfe key")
+
+	if b, err := GetTarget(ctx, "rekor.pub"); err != nil || string(b) != "rekor key" {
+		t.Errorf("GetTarget() = %q, %v, want the refreshed key", b, err)
+	}
+	// The refresh took the other lookups of the snapshot again.
+	if b, err := GetTarget(ctx, "ctfe.pub"); err != nil || string(b) != "ctfe key" {
+		t.Errorf("GetTarget() = %q, %v, want the refreshed key", b, err)
+	}
+	if opened, forced := f.counts(); opened != 1 || forced != 1 {
+		t.Errorf("TUF opened %d times, %d forced, want one refresh", opened, forced)
+	}
+}
+
+func TestGetTargetOtherMirror(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t, RefreshAuto)
+	now := time.Now()
+	writeTestSnapshot(t, now, now.Add(time.Hour), map[string]string{"rekor.pub": "old rekor key"})
+	if err := os.WriteFile(filepath.Join(rootCacheDir(), "remote.json"), []byte(`{"mirror":"https://tuf.example.com"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := GetTarget(ctx, "rekor.pub"); err != nil || string(b) != "rekor key" {
+		t.Errorf("GetTarget() = %q, %v, want the key of the other mirror", b, err)
+	}
+	if opened, _ := f.counts(); opened != 1 {
+		t.Errorf("TUF opened %d times, want the snapshot of the other mirror ignored", opened)
+	}
+}
+
+func TestGetTargetNoCache(t *testing.T) {
+	f := setup(t, RefreshNever)
+	t.Setenv(tuf.SigstoreNoCache, "true")
+
+	if b, err := GetTarget(context.Background(), "rekor.pub"); err != nil || string(b) != "rekor key" {
+		t.Errorf("GetTarget() = %q, %v", b, err)
+	}
+	if _, err := os.Stat(snapshotPath()); !os.IsNotExist(err) {
+		t.Errorf("snapshot written with %s: %v", tuf.SigstoreNoCache, err)
+	}
+	if opened, _ := f.counts(); opened != 1 {
+		t.Errorf("TUF opened %d times, want once", opened)
+	}
+}
+
+func TestSetRefresh(t *testing.T) {
+	t.Cleanup(func() { reset(RefreshAuto) })
+	for _, policy := range []string{RefreshNever, RefreshAuto, RefreshAlways} {
+		if err := SetRefresh(policy); err != nil {
+			t.Errorf("SetRefresh(%q) = %v", policy, err)
+		}
+	}
+	if err := SetRefresh("sometimes"); err == nil {
+		t.Error("SetRefresh(sometimes): expected an error")
+	}
+}
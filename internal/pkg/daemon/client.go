@@ -0,0 +1,82 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// ErrUnavailable is returned if no daemon listens on the socket of
+// $COSIGN_DAEMON_SOCKET.
+var ErrUnavailable = errors.New("the cosign daemon isn't running")
+
+// baseURL is the URL of the daemon, whichever socket it listens on.
+const baseURL = "http://cosign-daemon"
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[string]*http.Client{}
+
+	unavailableOnce sync.Once
+)
+
+// client returns the client of the daemon listening on $COSIGN_DAEMON_SOCKET,
+// or nil if it isn't set.
+func client() *http.Client {
+	socket := env.Getenv(env.VariableDaemonSocket)
+	if socket == "" {
+		return nil
+	}
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if c, ok := clients[socket]; ok {
+		return c
+	}
+	c := &http.Client{Transport: unixTransport(socket)}
+	clients[socket] = c
+	return c
+}
+
+// unixTransport sends requests to the Unix socket at path, whatever their
+// host.
+func unixTransport(path string) *http.Transport {
+	return &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			c, err := d.DialContext(ctx, "unix", path)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+			}
+			return c, nil
+		},
+	}
+}
+
+// warnUnavailable tells once that the command runs without the daemon,
+// because of err.
+func warnUnavailable(ctx context.Context, err error) {
+	unavailableOnce.Do(func() {
+		ui.Warnf(ctx, "running the command without the cosign daemon: %v", err)
+	})
+}
@@ -0,0 +1,210 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package daemon keeps the KMS clients, OIDC tokens and registry credentials
+// of short-lived cosign clients warm in a long-running cosign daemon, which
+// they reach over a local socket, so that the commands of a busy build farm
+// don't load them again.
+//
+// Clients run their commands themselves, with their own standard streams,
+// working directory and environment, and ask the daemon for what it keeps:
+//
+//	GET  /v1/warm/<key>  returns the value of key, or 404 if the daemon has none
+//	PUT  /v1/warm/<key>  keeps the value of key, until the time of its Expires header
+//	POST /v1/kms         loads the KMS key of a KMSRequest in a worker, returning a KMSResponse
+//	     /v1/kms/<id>/   serves the signingserver protocol for the keys of worker id
+//
+// Keys are prefixed with the digest of the environment of the client, and the
+// KMS keys are loaded by a worker process per environment and impersonated
+// service account, so clients only share what they'd load the same way.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+)
+
+// Options configures Serve.
+type Options struct {
+	// IdleTimeout, if positive, stops the daemon once no client sent a
+	// request for that long.
+	IdleTimeout time.Duration
+	// Worker returns the command of a process that serves KMS keys with
+	// ServeWorker, impersonating serviceAccount. The daemon runs it in the
+	// environment of the clients it serves.
+	Worker func(serviceAccount string) *exec.Cmd
+}
+
+// Listen listens on the Unix socket at path, which only the user may
+// connect to. A socket left at path by a daemon that exited is replaced.
+func Listen(path string) (net.Listener, error) {
+	if c, err := net.Dial("unix", path); err == nil {
+		c.Close()
+		return nil, fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	// The socket is created without permissions for the group and others,
+	// so there is no window in which they can connect.
+	return listenPrivate(path)
+}
+
+// Serve serves the clients that connect to l until ctx is done or, if
+// o.IdleTimeout is positive, no client sent a request for that long.
+// Connections of other users than the one running the daemon are rejected.
+func Serve(ctx context.Context, l net.Listener, o Options) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	dir, err := os.MkdirTemp("", "cosign-daemon-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	s := &server{
+		warm:    map[string]warmValue{},
+		workers: newWorkers(dir, o.Worker),
+	}
+	defer s.workers.stop()
+
+	t := newIdleTimer(o.IdleTimeout, cancel)
+	srv := &http.Server{
+		Handler:           t.handler(s.handler()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	err = srv.Serve(&peerListener{Listener: l, uid: os.Getuid(), reject: func(err error) {
+		ui.Warnf(ctx, "rejected a connection to the cosign daemon: %v", err)
+	}})
+	if ctx.Err() != nil || errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// peerListener only accepts the connections of the user uid.
+type peerListener struct {
+	net.Listener
+	uid    int
+	reject func(error)
+}
+
+// Accept implements net.Listener.
+func (l *peerListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		uid, err := peerUID(c)
+		switch {
+		case err != nil:
+			l.reject(fmt.Errorf("reading the credentials of the peer: %w", err))
+		case uid != l.uid:
+			l.reject(fmt.Errorf("the peer is user %d", uid))
+		default:
+			return c, nil
+		}
+		c.Close()
+	}
+}
+
+// server serves the requests of the clients of a daemon.
+type server struct {
+	warmMu sync.Mutex
+	warm   map[string]warmValue
+
+	workers *workers
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/warm/", s.handleWarm)
+	mux.HandleFunc("/v1/kms", s.handleKMS)
+	mux.HandleFunc("/v1/kms/", s.proxyKMS)
+	return mux
+}
+
+// idleTimer calls stop once no request was served for its duration.
+type idleTimer struct {
+	mu      sync.Mutex
+	idle    time.Duration
+	serving int
+	t       *time.Timer
+}
+
+func newIdleTimer(idle time.Duration, stop func()) *idleTimer {
+	t := &idleTimer{idle: idle}
+	if idle > 0 {
+		t.t = time.AfterFunc(idle, stop)
+	}
+	return t
+}
+
+func (t *idleTimer) handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.start()
+		defer t.done()
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (t *idleTimer) start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.serving++
+	if t.t != nil {
+		t.t.Stop()
+	}
+}
+
+func (t *idleTimer) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.serving--
+	if t.t != nil && t.serving == 0 {
+		t.t.Reset(t.idle)
+	}
+}
+
+// readError returns the error of the response resp, whose status isn't 2xx.
+func readError(resp *http.Response) error {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	msg := strings.TrimSpace(string(b))
+	if msg == "" {
+		msg = resp.Status
+	}
+	return errors.New(msg)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
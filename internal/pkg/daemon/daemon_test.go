@@ -0,0 +1,276 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestMain(m *testing.M) {
+	// The test binary is the KMS worker of the daemons of the tests.
+	if os.Getenv("COSIGN_DAEMON_TEST_WORKER") != "" {
+		if err := ServeWorker(context.Background(), func(ctx context.Context, keyRef string) (signature.SignerVerifier, error) {
+			priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				return nil, err
+			}
+			return signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+		}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// serve serves o on a socket in a temporary directory, which
+// $COSIGN_DAEMON_SOCKET names, until the test ends.
+func serve(t *testing.T, o Options) (string, <-chan error) {
+	t.Helper()
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("the daemon needs the peer credentials of Unix sockets")
+	}
+	// Unix socket paths are short, so they can't be in t.TempDir().
+	dir, err := os.MkdirTemp("", "daemon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "d.sock")
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("COSIGN_DAEMON_SOCKET", path)
+	ctx, cancel := context.WithCancel(context.Background())
+	done, stopped := make(chan error, 1), make(chan struct{})
+	go func() {
+		done <- Serve(ctx, l, o)
+		close(stopped)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	return path, done
+}
+
+func TestListen(t *testing.T) {
+	path, _ := serve(t, Options{})
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("the socket has permissions %v, want none for the group and others", perm)
+	}
+	if _, err := Listen(path); err == nil || !strings.Contains(err.Error(), "already listening") {
+		t.Errorf("Listen() on the socket of a daemon = %v", err)
+	}
+}
+
+func TestPeerListener(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("the daemon needs the peer credentials of Unix sockets")
+	}
+	dir, err := os.MkdirTemp("", "daemon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for _, tc := range []struct {
+		name   string
+		uid    int
+		accept bool
+	}{
+		{name: "same user", uid: os.Getuid(), accept: true},
+		{name: "other user", uid: os.Getuid() + 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := net.Listen("unix", filepath.Join(dir, tc.name+".sock"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rejected := make(chan error, 1)
+			pl := &peerListener{Listener: l, uid: tc.uid, reject: func(err error) { rejected <- err }}
+			accepted := make(chan net.Conn, 1)
+			go func() {
+				if c, err := pl.Accept(); err == nil {
+					accepted <- c
+				}
+			}()
+			c, err := net.Dial("unix", l.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			select {
+			case sc := <-accepted:
+				sc.Close()
+				if !tc.accept {
+					t.Error("Accept() accepted the connection of another user")
+				}
+			case err := <-rejected:
+				if tc.accept {
+					t.Errorf("Accept() rejected the connection of the user: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Accept() neither accepted nor rejected the connection")
+			}
+			l.Close()
+		})
+	}
+}
+
+func TestServeIdleTimeout(t *testing.T) {
+	_, done := serve(t, Options{IdleTimeout: 10 * time.Millisecond})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() didn't stop when idle")
+	}
+}
+
+func TestWarm(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	loads := 0
+	load := func() ([]byte, time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		loads++
+		return []byte(fmt.Sprint(loads)), time.Time{}, nil
+	}
+	if v, _ := Warm(ctx, "key", load); string(v) != "1" {
+		t.Errorf("Warm() without a daemon = %s", v)
+	}
+	if v, _ := Warm(ctx, "key", load); string(v) != "2" {
+		t.Errorf("Warm() without a daemon = %s, want another load", v)
+	}
+
+	serve(t, Options{})
+	t.Setenv("COSIGN_DAEMON_TEST", "a")
+	if v, _ := Warm(ctx, "key", load); string(v) != "3" {
+		t.Errorf("Warm() = %s, want a load", v)
+	}
+	// The daemon serves its clients at once.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := Warm(ctx, "key", load); err != nil || string(v) != "3" {
+				t.Errorf("Warm() = %s, %v, want the warm value", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	// Clients with other environments don't share values.
+	t.Setenv("COSIGN_DAEMON_TEST", "b")
+	if v, _ := Warm(ctx, "key", load); string(v) != "4" {
+		t.Errorf("Warm() in another environment = %s, want a load", v)
+	}
+
+	expired := func() ([]byte, time.Time, error) {
+		v, _, err := load()
+		return v, time.Now().Add(-time.Minute), err
+	}
+	first, _ := Warm(ctx, "expired", expired)
+	if second, _ := Warm(ctx, "expired", expired); bytes.Equal(first, second) {
+		t.Error("Warm() reused an expired value")
+	}
+	if _, err := Warm(ctx, "failed", func() ([]byte, time.Time, error) {
+		return nil, time.Time{}, errors.New("boom")
+	}); err == nil {
+		t.Error("Warm() of a failed load: expected an error")
+	}
+
+	// Values are loaded by the client if the daemon is gone.
+	t.Setenv("COSIGN_DAEMON_SOCKET", filepath.Join(t.TempDir(), "missing.sock"))
+	if v, err := Warm(ctx, "key", load); err != nil || string(v) != "7" {
+		t.Errorf("Warm() without a daemon = %s, %v, want a load", v, err)
+	}
+}
+
+func TestSignerVerifier(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("COSIGN_DAEMON_TEST_WORKER", "1")
+	serve(t, Options{Worker: func(serviceAccount string) *exec.Cmd {
+		return exec.Command(os.Args[0], "-test.run=^$", serviceAccount)
+	}})
+	noLoad := func() (signature.SignerVerifier, error) {
+		t.Error("the client loaded the key")
+		return nil, errors.New("no daemon")
+	}
+	publicKey := func(keyRef, serviceAccount string) crypto.PublicKey {
+		t.Helper()
+		sv, err := SignerVerifier(ctx, keyRef, serviceAccount, noLoad)
+		if err != nil {
+			t.Fatalf("SignerVerifier() = %v", err)
+		}
+		msg := []byte("payload")
+		sig, err := sv.SignMessage(bytes.NewReader(msg))
+		if err != nil {
+			t.Fatalf("SignMessage() = %v", err)
+		}
+		if err := sv.VerifySignature(bytes.NewReader(sig), bytes.NewReader(msg)); err != nil {
+			t.Errorf("VerifySignature() = %v", err)
+		}
+		pub, err := sv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pub
+	}
+
+	// The worker generates a key each time it loads one.
+	first := publicKey("awskms:///alias/a", "")
+	if pub := publicKey("awskms:///alias/a", ""); !first.(*ecdsa.PublicKey).Equal(pub) {
+		t.Error("the worker loaded a warm key again")
+	}
+	if pub := publicKey("awskms:///alias/b", ""); first.(*ecdsa.PublicKey).Equal(pub) {
+		t.Error("the worker served another key for a new key")
+	}
+	if pub := publicKey("awskms:///alias/a", "ci@example.iam.gserviceaccount.com"); first.(*ecdsa.PublicKey).Equal(pub) {
+		t.Error("clients impersonating another service account share a key")
+	}
+	t.Setenv("COSIGN_DAEMON_TEST", "other")
+	if pub := publicKey("awskms:///alias/a", ""); first.(*ecdsa.PublicKey).Equal(pub) {
+		t.Error("clients with another environment share a key")
+	}
+}
@@ -0,0 +1,70 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
+// keychainTTL is how long the daemon keeps registry credentials, which
+// don't tell when they expire.
+const keychainTTL = 5 * time.Minute
+
+// Keychain returns kc, whose credentials the daemon at $COSIGN_DAEMON_SOCKET
+// keeps for keychainTTL, if it is set. name tells apart the keychains of the
+// same registries, e.g. with different credential helpers.
+func Keychain(kc authn.Keychain, name string) authn.Keychain {
+	if client() == nil {
+		return kc
+	}
+	return warmKeychain{kc: kc, name: name}
+}
+
+type warmKeychain struct {
+	kc   authn.Keychain
+	name string
+}
+
+// Resolve implements authn.Keychain
+func (k warmKeychain) Resolve(r authn.Resource) (authn.Authenticator, error) {
+	b, err := Warm(context.Background(), "registry/"+k.name+"/"+r.RegistryStr(), func() ([]byte, time.Time, error) {
+		a, err := k.kc.Resolve(r)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		cfg, err := a.Authorization()
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		b, err := json.Marshal(cfg)
+		return b, time.Now().Add(keychainTTL), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	cfg := authn.AuthConfig{}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg == (authn.AuthConfig{}) {
+		return authn.Anonymous, nil
+	}
+	return authn.FromConfig(cfg), nil
+}
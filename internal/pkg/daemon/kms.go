@@ -0,0 +1,376 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/signingserver"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// KMSRequest asks the daemon for the KMS key KeyRef, impersonating
+// ServiceAccount, loaded in the environment Env of the client.
+type KMSRequest struct {
+	Env            []string `json:"env"`
+	ServiceAccount string   `json:"serviceAccount,omitempty"`
+	KeyRef         string   `json:"keyRef"`
+}
+
+// KMSResponse tells the path under which the daemon serves the
+// signingserver protocol for the key of a KMSRequest.
+type KMSResponse struct {
+	Path string `json:"path"`
+}
+
+// SignerVerifier returns the signer of the KMS key keyRef, impersonating
+// serviceAccount, that a worker of the daemon at $COSIGN_DAEMON_SOCKET keeps
+// for the environment of the process, or the signer loaded with load without
+// a daemon. Signatures are verified locally with the public key of the key.
+func SignerVerifier(ctx context.Context, keyRef, serviceAccount string, load func() (signature.SignerVerifier, error)) (signature.SignerVerifier, error) {
+	c := client()
+	if c == nil {
+		return load()
+	}
+	b, err := json.Marshal(KMSRequest{Env: os.Environ(), ServiceAccount: serviceAccount, KeyRef: keyRef})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/kms", bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrUnavailable) {
+			warnUnavailable(ctx, err)
+			return load()
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cosign daemon: %w", readError(resp))
+	}
+	kr := KMSResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&kr); err != nil {
+		return nil, fmt.Errorf("parsing the response of the cosign daemon: %w", err)
+	}
+	return signingserver.NewClient(ctx, baseURL+kr.Path, "", signingserver.WithHTTPClient(c))
+}
+
+func (s *server) handleKMS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	kr := KMSRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWarmSize)).Decode(&kr); err != nil {
+		http.Error(w, "invalid KMS request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	wk, err := s.workers.get(kr.Env, kr.ServiceAccount)
+	if err != nil {
+		http.Error(w, "starting a worker: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	path, err := wk.load(r.Context(), kr.KeyRef)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, KMSResponse{Path: "/v1/kms/" + wk.id + path})
+}
+
+func (s *server) proxyKMS(w http.ResponseWriter, r *http.Request) {
+	id, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/kms/"), "/")
+	wk := s.workers.byID(id)
+	if wk == nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.StripPrefix("/v1/kms/"+id, wk.proxy).ServeHTTP(w, r)
+}
+
+// workers are the worker processes of a daemon, one per environment and
+// impersonated service account, listening on sockets in dir.
+type workers struct {
+	dir   string
+	start func(serviceAccount string) *exec.Cmd
+
+	mu   sync.Mutex
+	n    int
+	m    map[string]*worker
+	done sync.WaitGroup
+}
+
+func newWorkers(dir string, start func(string) *exec.Cmd) *workers {
+	return &workers{dir: dir, start: start, m: map[string]*worker{}}
+}
+
+// worker is a process that serves KMS keys with ServeWorker.
+type worker struct {
+	id     string
+	client *http.Client
+	proxy  http.Handler
+	stdin  io.Closer
+}
+
+// get returns the worker of env and serviceAccount, started if it isn't
+// running.
+func (ws *workers) get(env []string, serviceAccount string) (*worker, error) {
+	key := digest(append([]string{"serviceAccount=" + serviceAccount}, env...))
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if wk, ok := ws.m[key]; ok {
+		return wk, nil
+	}
+	if ws.start == nil {
+		return nil, errors.New("the daemon has no KMS workers")
+	}
+	ws.n++
+	id := strconv.Itoa(ws.n)
+	socket := filepath.Join(ws.dir, id+".sock")
+	// The socket is in the private directory of the daemon, and passed to
+	// the worker, which can't be connected to before it serves.
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	f, err := l.(*net.UnixListener).File()
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	defer f.Close()
+	cmd := ws.start(serviceAccount)
+	cmd.Env = env
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	c := &http.Client{Transport: unixTransport(socket)}
+	proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: "cosign-daemon-worker"})
+	proxy.Transport = c.Transport
+	wk := &worker{id: id, client: c, proxy: proxy, stdin: stdin}
+	ws.m[key] = wk
+	ws.done.Add(1)
+	go func() {
+		defer ws.done.Done()
+		_ = cmd.Wait()
+		l.Close()
+		ws.mu.Lock()
+		defer ws.mu.Unlock()
+		if ws.m[key] == wk {
+			delete(ws.m, key)
+		}
+	}()
+	return wk, nil
+}
+
+func (ws *workers) byID(id string) *worker {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for _, wk := range ws.m {
+		if wk.id == id {
+			return wk
+		}
+	}
+	return nil
+}
+
+// stop stops the workers, which exit once their stdin is closed, and waits
+// for them.
+func (ws *workers) stop() {
+	ws.mu.Lock()
+	for _, wk := range ws.m {
+		wk.stdin.Close()
+	}
+	ws.mu.Unlock()
+	ws.done.Wait()
+}
+
+// workerKey asks a worker for a key.
+type workerKey struct {
+	KeyRef string `json:"keyRef"`
+}
+
+// load has the worker load keyRef, and returns the path under which it serves
+// the key.
+func (wk *worker) load(ctx context.Context, keyRef string) (string, error) {
+	b, err := json.Marshal(workerKey{KeyRef: keyRef})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://cosign-daemon-worker/v1/keys", bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	resp, err := wk.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", readError(resp)
+	}
+	kr := KMSResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&kr); err != nil {
+		return "", err
+	}
+	return kr.Path, nil
+}
+
+// LoadFunc loads the signer of the KMS key keyRef.
+type LoadFunc func(ctx context.Context, keyRef string) (signature.SignerVerifier, error)
+
+// ServeWorker serves the KMS keys loaded with load on the listener the daemon
+// passed to the process as its first extra file, until ctx is done or the
+// daemon closes the stdin of the process.
+func ServeWorker(ctx context.Context, load LoadFunc) error {
+	f := os.NewFile(3, "cosign-daemon-worker")
+	l, err := net.FileListener(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("the worker must be started by the cosign daemon: %w", err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		cancel()
+	}()
+	return serveWorker(ctx, l, load)
+}
+
+func serveWorker(ctx context.Context, l net.Listener, load LoadFunc) error {
+	s := &keyServer{load: load, keys: map[string]*loadedKey{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/keys", s.handleLoad)
+	mux.HandleFunc("/v1/keys/", s.handleKey)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	if err := srv.Serve(l); ctx.Err() == nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// keyServer serves the keys of a worker.
+type keyServer struct {
+	load LoadFunc
+
+	mu   sync.Mutex
+	n    int
+	keys map[string]*loadedKey
+	ids  sync.Map
+}
+
+// loadedKey is a key of a worker, loaded once.
+type loadedKey struct {
+	mu      sync.Mutex
+	id      string
+	handler http.Handler
+}
+
+func (s *keyServer) handleLoad(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	wk := workerKey{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWarmSize)).Decode(&wk); err != nil {
+		http.Error(w, "invalid key request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	k, ok := s.keys[wk.KeyRef]
+	if !ok {
+		s.n++
+		k = &loadedKey{id: strconv.Itoa(s.n)}
+		s.keys[wk.KeyRef] = k
+	}
+	s.mu.Unlock()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.handler == nil {
+		// The client of the key outlives the request that loads it.
+		h, err := s.newHandler(context.Background(), wk.KeyRef)
+		if err != nil {
+			http.Error(w, "loading "+wk.KeyRef+": "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		k.handler = http.StripPrefix("/v1/keys/"+k.id, h)
+		s.ids.Store(k.id, k.handler)
+	}
+	writeJSON(w, KMSResponse{Path: "/v1/keys/" + k.id})
+}
+
+func (s *keyServer) newHandler(ctx context.Context, keyRef string) (http.Handler, error) {
+	sv, err := s.load(ctx, keyRef)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := sv.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	pem, err := cryptoutils.MarshalPublicKeyToPEM(pub)
+	if err != nil {
+		return nil, err
+	}
+	return signingserver.NewHandler(sv, signingserver.Signer{PublicKey: string(pem)}, ""), nil
+}
+
+func (s *keyServer) handleKey(w http.ResponseWriter, r *http.Request) {
+	id, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/keys/"), "/")
+	h, ok := s.ids.Load(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	h.(http.Handler).ServeHTTP(w, r)
+}
@@ -0,0 +1,36 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux && !darwin
+
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// errUnsupported is returned on the systems whose Unix sockets don't tell
+// the credentials of their peers.
+var errUnsupported = fmt.Errorf("the cosign daemon isn't supported on %s", runtime.GOOS)
+
+func listenPrivate(string) (net.Listener, error) {
+	return nil, errUnsupported
+}
+
+func peerUID(net.Conn) (int, error) {
+	return 0, errUnsupported
+}
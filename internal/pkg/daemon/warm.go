@@ -0,0 +1,175 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxWarmSize is the largest value the daemon keeps.
+const maxWarmSize = 1 << 20
+
+// errNotWarm is returned if the daemon has no value for a key.
+var errNotWarm = errors.New("not warm")
+
+// Warm returns the value of key that the daemon at $COSIGN_DAEMON_SOCKET keeps
+// for the environment of the process, or the value loaded with load, which
+// the daemon keeps until it expires, at the zero time never. Without a daemon,
+// the value is loaded each time.
+func Warm(ctx context.Context, key string, load func() ([]byte, time.Time, error)) ([]byte, error) {
+	c := client()
+	if c == nil {
+		v, _, err := load()
+		return v, err
+	}
+	key = environmentDigest() + "/" + key
+	v, err := getWarm(ctx, c, key)
+	if err == nil {
+		return v, nil
+	}
+	if !errors.Is(err, errNotWarm) {
+		warnUnavailable(ctx, err)
+		v, _, err := load()
+		return v, err
+	}
+	v, expires, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if err := putWarm(ctx, c, key, v, expires); err != nil {
+		warnUnavailable(ctx, err)
+	}
+	return v, nil
+}
+
+func warmURL(key string) string {
+	return baseURL + "/v1/warm/" + url.PathEscape(key)
+}
+
+func getWarm(ctx context.Context, c *http.Client, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, warmURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(io.LimitReader(resp.Body, maxWarmSize))
+	case http.StatusNotFound:
+		return nil, errNotWarm
+	default:
+		return nil, readError(resp)
+	}
+}
+
+func putWarm(ctx context.Context, c *http.Client, key string, v []byte, expires time.Time) error {
+	if len(v) > maxWarmSize {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, warmURL(key), bytes.NewReader(v))
+	if err != nil {
+		return err
+	}
+	if !expires.IsZero() {
+		req.Header.Set("Expires", expires.UTC().Format(http.TimeFormat))
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return readError(resp)
+	}
+	return nil
+}
+
+// warmValue is a value the daemon keeps, until expires if it isn't zero.
+type warmValue struct {
+	value   []byte
+	expires time.Time
+}
+
+func (s *server) handleWarm(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/v1/warm/")
+	switch r.Method {
+	case http.MethodGet:
+		s.warmMu.Lock()
+		v, ok := s.warm[key]
+		if ok && !v.expires.IsZero() && !time.Now().Before(v.expires) {
+			delete(s.warm, key)
+			ok = false
+		}
+		s.warmMu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(v.value)
+	case http.MethodPut:
+		v := warmValue{}
+		if e := r.Header.Get("Expires"); e != "" {
+			expires, err := http.ParseTime(e)
+			if err != nil {
+				http.Error(w, "invalid Expires header: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			v.expires = expires
+		}
+		b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWarmSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		v.value = b
+		s.warmMu.Lock()
+		s.warm[key] = v
+		s.warmMu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "use GET or PUT", http.StatusMethodNotAllowed)
+	}
+}
+
+// environmentDigest returns the digest of the environment of the process,
+// which the credentials of the values it loads come from.
+func environmentDigest() string {
+	return digest(os.Environ())
+}
+
+// digest returns the hex SHA-256 digest of the sorted strings of ss.
+func digest(ss []string) string {
+	ss = append([]string(nil), ss...)
+	sort.Strings(ss)
+	h := sha256.Sum256([]byte(strings.Join(ss, "\x00")))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,112 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events writes machine-readable progress and result events of
+// long operations, one JSON object per line, to the file descriptor of
+// --events-fd, so that wrapping tools don't have to parse the human output.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Schema identifies the version of the schema of Event. Fields are only
+// added within a version.
+const Schema = "https://sigstore.dev/cosign/events/v1"
+
+// The types of events.
+const (
+	// Start is emitted when work on an item starts.
+	Start = "start"
+	// Skip is emitted for an item completed by a previous run.
+	Skip = "skip"
+	// Done is emitted when an item is completed.
+	Done = "done"
+	// Fail is emitted for an item that was started but not completed when the
+	// operation failed or was interrupted.
+	Fail = "fail"
+	// Finish is emitted once, when the operation ends.
+	Finish = "finish"
+)
+
+// Event is a progress or result event.
+type Event struct {
+	Schema string    `json:"schema"`
+	Type   string    `json:"type"`
+	Time   time.Time `json:"time"`
+	// Item is the image or other item of a Start, Skip, Done or Fail event.
+	Item string `json:"item,omitempty"`
+	// Completed and Skipped count the items of a Finish event.
+	Completed *int `json:"completed,omitempty"`
+	Skipped   *int `json:"skipped,omitempty"`
+	// Error is the error of a Fail or failed Finish event.
+	Error string `json:"error,omitempty"`
+}
+
+var (
+	mu sync.Mutex
+	w  io.Writer
+)
+
+// SetOutput sets the writer that events are written to, or disables events
+// if out is nil.
+func SetOutput(out io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	w = out
+}
+
+// OpenFD returns the file of the open file descriptor fd, for SetOutput.
+func OpenFD(fd int) (*os.File, error) {
+	if fd < 0 {
+		return nil, fmt.Errorf("invalid events file descriptor %d", fd)
+	}
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd%d", fd))
+	if _, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("events file descriptor %d isn't open: %w", fd, err)
+	}
+	return f, nil
+}
+
+// Enabled reports whether events are written.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return w != nil
+}
+
+// Emit writes e as a line of JSON, if events are enabled. Events are for
+// observing an operation, so failing to write them doesn't fail it.
+func Emit(e Event) {
+	mu.Lock()
+	defer mu.Unlock()
+	if w == nil {
+		return
+	}
+	e.Schema = Schema
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_, _ = w.Write(append(b, '\n'))
+}
@@ -0,0 +1,73 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestEmit(t *testing.T) {
+	Emit(Event{Type: Start, Item: "disabled"})
+
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+	if !Enabled() {
+		t.Fatal("Enabled() = false after SetOutput")
+	}
+	completed := 1
+	Emit(Event{Type: Start, Item: "example.com/app"})
+	Emit(Event{Type: Finish, Completed: &completed})
+
+	var got []Event
+	s := bufio.NewScanner(&buf)
+	for s.Scan() {
+		e := Event{}
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", s.Text(), err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Emit() wrote %d events, want 2", len(got))
+	}
+	if got[0].Schema != Schema || got[0].Type != Start || got[0].Item != "example.com/app" || got[0].Time.IsZero() {
+		t.Errorf("start event = %+v", got[0])
+	}
+	if got[1].Type != Finish || got[1].Completed == nil || *got[1].Completed != 1 {
+		t.Errorf("finish event = %+v", got[1])
+	}
+}
+
+func TestOpenFD(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := OpenFD(int(f.Fd())); err != nil {
+		t.Errorf("OpenFD() of an open file = %v", err)
+	}
+	for _, fd := range []int{-1, 1 << 20} {
+		if _, err := OpenFD(fd); err == nil {
+			t.Errorf("OpenFD(%d): expected an error", fd)
+		}
+	}
+}
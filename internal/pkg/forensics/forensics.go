@@ -0,0 +1,393 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package forensics records the evidence that cosign fetches while it
+// verifies, the manifests and blobs of registries and the responses of
+// transparency logs, and writes it with the inputs of the verification to a
+// failure report (see Write), so that failures seen only in CI can be
+// reproduced and debugged elsewhere.
+package forensics
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/telemetry"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/spf13/pflag"
+)
+
+// ReportSchema identifies the index of failure reports.
+const ReportSchema = "https://sigstore.dev/cosign/failure-report/v1"
+
+const (
+	// maxBodySize is the most of a response body that is recorded.
+	maxBodySize = 16 << 20
+	// maxInputSize is the size of the largest input file copied to a report.
+	maxInputSize = 16 << 20
+)
+
+var (
+	mu        sync.Mutex
+	recording bool
+	responses []*response
+)
+
+// Enable starts recording the responses sent through the transports of
+// Transport, which pass them on unrecorded otherwise.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	recording = true
+}
+
+// Reset stops recording and forgets the responses recorded so far.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	recording = false
+	responses = nil
+}
+
+// response is a recorded response.
+type response struct {
+	kind, method, url string
+	status            int
+	mediaType, digest string
+	body              bytes.Buffer
+	truncated         bool
+}
+
+// Transport returns a transport that records the responses sent through
+// next, by a service of kind, once Enable is called. Of registries, only the
+// responses for manifests, blobs and referrers are recorded, and never
+// those of token endpoints, which hold credentials.
+func Transport(next http.RoundTripper, kind string) http.RoundTripper {
+	return &transport{next: next, kind: kind}
+}
+
+type transport struct {
+	next http.RoundTripper
+	kind string
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !evidence(t.kind, req.URL) {
+		return resp, err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !recording {
+		return resp, err
+	}
+	r := &response{
+		kind:      t.kind,
+		method:    req.Method,
+		url:       req.URL.Redacted(),
+		status:    resp.StatusCode,
+		mediaType: resp.Header.Get("Content-Type"),
+		digest:    resp.Header.Get("Docker-Content-Digest"),
+	}
+	responses = append(responses, r)
+	if resp.Body != nil {
+		resp.Body = &recordingBody{ReadCloser: resp.Body, r: r}
+	}
+	return resp, err
+}
+
+// evidence returns whether the responses for u, of a service of kind, are
+// evidence of a verification.
+func evidence(kind string, u *url.URL) bool {
+	if kind != telemetry.Registry {
+		return true
+	}
+	for _, part := range []string{"/manifests/", "/blobs/", "/referrers/"} {
+		if strings.HasPrefix(u.Path, "/v2/") && strings.Contains(u.Path, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordingBody records what is read of a response body.
+type recordingBody struct {
+	io.ReadCloser
+	r *response
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		mu.Lock()
+		if room := maxBodySize - b.r.body.Len(); room < n {
+			b.r.body.Write(p[:room])
+			b.r.truncated = true
+		} else {
+			b.r.body.Write(p[:n])
+		}
+		mu.Unlock()
+	}
+	return n, err
+}
+
+// Index is the index.json of a failure report.
+type Index struct {
+	Schema  string    `json:"schema"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args"`
+	Error   string    `json:"error"`
+	Time    time.Time `json:"time"`
+	// Flags are the flags given to the command, with the values of those
+	// that may hold credentials redacted.
+	Flags map[string]string `json:"flags"`
+	// Files are the files of the report.
+	Files []File `json:"files"`
+}
+
+// File is a file of a failure report.
+type File struct {
+	// Path is the path of the file in the report directory.
+	Path string `json:"path"`
+	// Kind is what the file is: "response", "payload", "signature",
+	// "certificate", "chain", "rekor-bundle", "rfc3161-timestamp" or "input".
+	Kind string `json:"kind"`
+	// Source is where the file comes from: the URL of a response, the
+	// manifest digest of a signature, or the flag or argument of an input.
+	Source string `json:"source"`
+	// The service, request method, status code, media type and digest
+	// header of responses, and whether their body was truncated.
+	Service   string `json:"service,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Status    int    `json:"status,omitempty"`
+	MediaType string `json:"mediaType,omitempty"`
+	Digest    string `json:"digest,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	// Size and SHA256 describe input files, which have no Path if they
+	// aren't copied: private keys and files larger than 16 MiB.
+	Size   int64  `json:"size,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Write writes the report of the verification of command with args, which
+// failed with verifyErr, to the directory dir: the responses recorded since
+// Enable, the payloads, signatures, certificates and bundles of the
+// signatures of the recorded manifests, copies of the files that flags and
+// args name, and an index.json (see Index) of all of them.
+func Write(dir, command string, args []string, flags *pflag.FlagSet, verifyErr error) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	w := &writer{dir: dir}
+	idx := Index{
+		Schema:  ReportSchema,
+		Command: command,
+		Args:    args,
+		Error:   verifyErr.Error(),
+		Time:    time.Now().UTC(),
+		Flags:   map[string]string{},
+	}
+
+	mu.Lock()
+	recorded := append([]*response(nil), responses...)
+	mu.Unlock()
+	blobs := map[string][]byte{}
+	for i, r := range recorded {
+		mu.Lock()
+		body, truncated := append([]byte(nil), r.body.Bytes()...), r.truncated
+		mu.Unlock()
+		f := File{Kind: "response", Source: r.url, Service: r.kind, Method: r.method, Status: r.status,
+			MediaType: r.mediaType, Digest: r.digest, Truncated: truncated}
+		if len(body) > 0 {
+			ext := ".bin"
+			if strings.Contains(r.mediaType, "json") {
+				ext = ".json"
+			}
+			if err := w.write(&f, fmt.Sprintf("responses/%03d-%s%s", i+1, r.kind, ext), body); err != nil {
+				return err
+			}
+			if !truncated {
+				sum := sha256.Sum256(body)
+				blobs["sha256:"+hex.EncodeToString(sum[:])] = body
+			}
+		}
+		idx.Files = append(idx.Files, f)
+	}
+	digests := make([]string, 0, len(blobs))
+	for d := range blobs {
+		digests = append(digests, d)
+	}
+	sort.Strings(digests)
+	for _, d := range digests {
+		if err := w.signatures(&idx, d, blobs[d], blobs); err != nil {
+			return err
+		}
+	}
+
+	var inputErr error
+	flags.Visit(func(fl *pflag.Flag) {
+		if redacted(fl.Name) {
+			idx.Flags[fl.Name] = "REDACTED"
+			return
+		}
+		idx.Flags[fl.Name] = fl.Value.String()
+		values := []string{fl.Value.String()}
+		if s, ok := fl.Value.(pflag.SliceValue); ok {
+			values = s.GetSlice()
+		}
+		for _, v := range values {
+			if err := w.input(&idx, "--"+fl.Name, v); err != nil && inputErr == nil {
+				inputErr = err
+			}
+		}
+	})
+	if inputErr != nil {
+		return inputErr
+	}
+	for _, a := range args {
+		if err := w.input(&idx, "argument", a); err != nil {
+			return err
+		}
+	}
+
+	b, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "index.json"), append(b, '\n'), 0o600)
+}
+
+// redacted returns whether the values of the flag name may hold
+// credentials.
+func redacted(name string) bool {
+	for _, s := range []string{"password", "token", "secret"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+type writer struct {
+	dir   string
+	files int
+}
+
+// write writes b to path in the report, as the file f.
+func (w *writer) write(f *File, path string, b []byte) error {
+	p := filepath.Join(w.dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p, b, 0o600); err != nil {
+		return err
+	}
+	f.Path = path
+	return nil
+}
+
+// signatures writes the payloads, signatures, certificates and bundles of
+// the layers of body, if it is a manifest of signatures or attestations,
+// with the layer blobs of blobs.
+func (w *writer) signatures(idx *Index, digest string, body []byte, blobs map[string][]byte) error {
+	m := struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}{}
+	if json.Unmarshal(body, &m) != nil {
+		return nil
+	}
+	for i, l := range m.Layers {
+		if _, ok := l.Annotations[static.SignatureAnnotationKey]; !ok {
+			continue
+		}
+		base := fmt.Sprintf("signatures/%s-%d/", strings.TrimPrefix(digest, "sha256:")[:12], i)
+		source := fmt.Sprintf("%s layer %d", digest, i)
+		for _, c := range []struct {
+			kind, name string
+			b          []byte
+		}{
+			{"payload", "payload", blobs[l.Digest]},
+			{"signature", "signature.b64", []byte(l.Annotations[static.SignatureAnnotationKey])},
+			{"certificate", "certificate.pem", []byte(l.Annotations[static.CertificateAnnotationKey])},
+			{"chain", "chain.pem", []byte(l.Annotations[static.ChainAnnotationKey])},
+			{"rekor-bundle", "rekor-bundle.json", []byte(l.Annotations[static.BundleAnnotationKey])},
+			{"rfc3161-timestamp", "rfc3161-timestamp.json", []byte(l.Annotations[static.RFC3161TimestampAnnotationKey])},
+		} {
+			if len(c.b) == 0 {
+				continue
+			}
+			f := File{Kind: c.kind, Source: source}
+			if err := w.write(&f, base+c.name, c.b); err != nil {
+				return err
+			}
+			idx.Files = append(idx.Files, f)
+		}
+	}
+	return nil
+}
+
+// input adds the file at path, the input given with source, to the report,
+// if it is a regular file.
+func (w *writer) input(idx *Index, source, path string) error {
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() {
+		return nil //nolint: nilerr // Flag values and arguments are mostly not files.
+	}
+	f := File{Kind: "input", Source: source + " " + path, Size: fi.Size()}
+	if fi.Size() <= maxInputSize {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(b)
+		f.SHA256 = hex.EncodeToString(sum[:])
+		// Private keys, e.g. the signing key of cosign countersign, are
+		// never copied.
+		if !bytes.Contains(b, []byte("PRIVATE KEY")) {
+			w.files++
+			if err := w.write(&f, fmt.Sprintf("inputs/%03d-%s", w.files, filepath.Base(path)), b); err != nil {
+				return err
+			}
+		}
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, file); err != nil {
+			return err
+		}
+		f.SHA256 = hex.EncodeToString(h.Sum(nil))
+	}
+	idx.Files = append(idx.Files, f)
+	return nil
+}
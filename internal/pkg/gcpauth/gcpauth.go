@@ -0,0 +1,157 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gcpauth impersonates the service account of
+// --impersonate-service-account to sign with GCP KMS keys and to
+// authenticate to Google Container Registry and Artifact Registry, so that
+// CI identities sign with the permissions delegated to them rather than
+// with the credentials of the service accounts holding the keys.
+package gcpauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+const (
+	// KMSScope is the scope of the tokens GCP KMS keys are used with.
+	KMSScope = "https://www.googleapis.com/auth/cloudkms"
+	// RegistryScope is the scope of the tokens Google registries are
+	// accessed with, which Artifact Registry requires.
+	RegistryScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// newTokenSource is a variable to replace the IAM credentials API in tests.
+var newTokenSource = impersonate.CredentialsTokenSource
+
+var (
+	mu           sync.Mutex
+	target       string
+	delegates    []string
+	tokenSources = map[string]oauth2.TokenSource{}
+)
+
+// SetServiceAccount sets the service account to impersonate, from the
+// application default credentials, for GCP KMS and Google registries.
+// accounts is the email of the service account, or a comma separated
+// delegation chain whose last account is impersonated through the others in
+// order. Empty, nothing is impersonated.
+func SetServiceAccount(accounts string) error {
+	var chain []string
+	for _, a := range strings.Split(accounts, ",") {
+		if a = strings.TrimSpace(a); a == "" {
+			continue
+		}
+		if !strings.Contains(a, "@") {
+			return fmt.Errorf("%q isn't the email of a service account", a)
+		}
+		chain = append(chain, a)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	target, delegates = "", nil
+	if len(chain) > 0 {
+		target, delegates = chain[len(chain)-1], chain[:len(chain)-1]
+	}
+	tokenSources = map[string]oauth2.TokenSource{}
+	return nil
+}
+
+// ServiceAccount returns the delegation chain of the impersonated service
+// account, separated by commas, or "" if none is.
+func ServiceAccount() string {
+	mu.Lock()
+	defer mu.Unlock()
+	if target == "" {
+		return ""
+	}
+	return strings.Join(append(append([]string{}, delegates...), target), ",")
+}
+
+// TokenSource returns the source of the tokens of the impersonated service
+// account with scope, which is shared by all the clients of the scope, or nil
+// if no service account is impersonated.
+func TokenSource(scope string) (oauth2.TokenSource, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if target == "" {
+		return nil, nil
+	}
+	if ts, ok := tokenSources[scope]; ok {
+		return ts, nil
+	}
+	// The tokens are refreshed after the command creating them ended, in
+	// the clients the cosign daemon keeps warm.
+	ts, err := newTokenSource(context.Background(), impersonate.CredentialsConfig{
+		TargetPrincipal: target,
+		Delegates:       delegates,
+		Scopes:          []string{scope},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("impersonating %s: %w", target, err)
+	}
+	tokenSources[scope] = ts
+	return ts, nil
+}
+
+// ClientOptions returns the options of Google API clients with scope that
+// authenticate as the impersonated service account, or none if no service
+// account is impersonated.
+func ClientOptions(scope string) ([]option.ClientOption, error) {
+	ts, err := TokenSource(scope)
+	if err != nil || ts == nil {
+		return nil, err
+	}
+	return []option.ClientOption{option.WithTokenSource(ts)}, nil
+}
+
+// Keychain authenticates to Google Container Registry and Artifact Registry
+// as the impersonated service account, and to other registries anonymously,
+// so that it can be the first keychain of an authn.MultiKeychain.
+var Keychain authn.Keychain = keychain{}
+
+type keychain struct{}
+
+// Resolve implements authn.Keychain
+func (keychain) Resolve(r authn.Resource) (authn.Authenticator, error) {
+	if !isGoogle(r.RegistryStr()) {
+		return authn.Anonymous, nil
+	}
+	ts, err := TokenSource(RegistryScope)
+	if err != nil {
+		return nil, err
+	}
+	if ts == nil {
+		return authn.Anonymous, nil
+	}
+	return google.NewTokenSourceAuthenticator(ts), nil
+}
+
+// isGoogle reports whether host is a registry of Google, as
+// github.com/google/go-containerregistry/pkg/v1/google does.
+func isGoogle(host string) bool {
+	return host == "gcr.io" ||
+		strings.HasSuffix(host, ".gcr.io") ||
+		strings.HasSuffix(host, ".pkg.dev") ||
+		strings.HasSuffix(host, ".google.com")
+}
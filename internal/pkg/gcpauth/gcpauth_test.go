@@ -0,0 +1,107 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpauth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+func TestServiceAccount(t *testing.T) {
+	var configs []impersonate.CredentialsConfig
+	newTokenSource = func(_ context.Context, c impersonate.CredentialsConfig, _ ...option.ClientOption) (oauth2.TokenSource, error) {
+		configs = append(configs, c)
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token-of-" + c.TargetPrincipal}), nil
+	}
+	t.Cleanup(func() {
+		newTokenSource = impersonate.CredentialsTokenSource
+		_ = SetServiceAccount("")
+	})
+
+	if err := SetServiceAccount("ci-signer"); err == nil {
+		t.Error("SetServiceAccount() of a name: expected an error")
+	}
+	if err := SetServiceAccount(""); err != nil {
+		t.Fatal(err)
+	}
+	if ts, err := TokenSource(KMSScope); ts != nil || err != nil {
+		t.Errorf("TokenSource() without a service account = %v, %v, want none", ts, err)
+	}
+	if opts, err := ClientOptions(KMSScope); opts != nil || err != nil {
+		t.Errorf("ClientOptions() without a service account = %v, %v, want none", opts, err)
+	}
+
+	if err := SetServiceAccount("ci@proj.iam.gserviceaccount.com, signer@keys.iam.gserviceaccount.com"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ServiceAccount(), "ci@proj.iam.gserviceaccount.com,signer@keys.iam.gserviceaccount.com"; got != want {
+		t.Errorf("ServiceAccount() = %s, want %s", got, want)
+	}
+	for i := 0; i < 2; i++ {
+		if opts, err := ClientOptions(KMSScope); len(opts) != 1 || err != nil {
+			t.Fatalf("ClientOptions() = %v, %v, want the option of the token source", opts, err)
+		}
+	}
+	want := []impersonate.CredentialsConfig{{
+		TargetPrincipal: "signer@keys.iam.gserviceaccount.com",
+		Delegates:       []string{"ci@proj.iam.gserviceaccount.com"},
+		Scopes:          []string{KMSScope},
+	}}
+	if !reflect.DeepEqual(configs, want) {
+		t.Errorf("impersonated %+v, want %+v once", configs, want)
+	}
+
+	for _, tc := range []struct {
+		repo string
+		auth string
+	}{
+		{"us-docker.pkg.dev/proj/repo/app", "Bearer token-of-signer@keys.iam.gserviceaccount.com"},
+		{"gcr.io/proj/app", "Bearer token-of-signer@keys.iam.gserviceaccount.com"},
+		{"ghcr.io/org/app", ""},
+	} {
+		repo, err := name.NewRepository(tc.repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := Keychain.Resolve(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.auth == "" {
+			if a != authn.Anonymous {
+				t.Errorf("Resolve(%s) = %v, want anonymous", tc.repo, a)
+			}
+			continue
+		}
+		cfg, err := a.Authorization()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := "Bearer " + cfg.Password; got != tc.auth {
+			t.Errorf("Resolve(%s) authorizes with %q, want %q", tc.repo, got, tc.auth)
+		}
+	}
+	if got := configs[len(configs)-1].Scopes; !reflect.DeepEqual(got, []string{RegistryScope}) {
+		t.Errorf("registries are accessed with scopes %v, want %s", got, RegistryScope)
+	}
+}
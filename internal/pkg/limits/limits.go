@@ -0,0 +1,171 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package limits bounds the content that verifiers read from registries, so
+// that a malicious image can't exhaust their memory with huge payloads,
+// annotations, numbers of layers or deeply nested JSON, and the content that
+// signers push to the sizes that registries accept.
+package limits
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+const (
+	// DefaultPayloadSize is the default of $COSIGN_MAX_PAYLOAD_SIZE.
+	DefaultPayloadSize = 128 << 20
+	// DefaultAnnotationSize is the default of $COSIGN_MAX_ANNOTATION_SIZE.
+	DefaultAnnotationSize = 1 << 20
+	// DefaultLayers is the default of $COSIGN_MAX_LAYERS.
+	DefaultLayers = 1000
+	// DefaultJSONDepth is the default of $COSIGN_MAX_JSON_DEPTH.
+	DefaultJSONDepth = 128
+)
+
+// ErrExceeded is returned, wrapped, when content is larger than its limit.
+var ErrExceeded = errors.New("limit exceeded")
+
+func limit(v env.Variable, def int64) (int64, error) {
+	s := env.Getenv(v)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q, must be a positive number", v, s)
+	}
+	return n, nil
+}
+
+// PayloadSize returns the largest payload read from a registry.
+func PayloadSize() (int64, error) {
+	return limit(env.VariableMaxPayloadSize, DefaultPayloadSize)
+}
+
+// ReadPayload reads all of r, a payload read from a registry, failing once
+// it is larger than PayloadSize.
+func ReadPayload(r io.Reader) ([]byte, error) {
+	max, err := PayloadSize()
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > max {
+		return nil, fmt.Errorf("payload is larger than %d bytes, set %s to raise the limit: %w", max, env.VariableMaxPayloadSize, ErrExceeded)
+	}
+	return b, nil
+}
+
+// CheckAnnotation returns an error if the value of the annotation key is
+// larger than $COSIGN_MAX_ANNOTATION_SIZE.
+func CheckAnnotation(key, value string) error {
+	max, err := limit(env.VariableMaxAnnotationSize, DefaultAnnotationSize)
+	if err != nil {
+		return err
+	}
+	if int64(len(value)) > max {
+		return fmt.Errorf("annotation %s is larger than %d bytes, set %s to raise the limit: %w", key, max, env.VariableMaxAnnotationSize, ErrExceeded)
+	}
+	return nil
+}
+
+// Layers returns the most signature or attestation layers read from a single
+// manifest.
+func Layers() (int64, error) {
+	return limit(env.VariableMaxLayers, DefaultLayers)
+}
+
+// RegistryManifestSize returns the largest manifest pushed to a registry
+// whose known limit is def, or 0 if there is none.
+func RegistryManifestSize(def int64) (int64, error) {
+	return limit(env.VariableRegistryMaxManifestSize, def)
+}
+
+// RegistryBlobSize returns the largest blob pushed to a registry whose known
+// limit is def, or 0 if there is none.
+func RegistryBlobSize(def int64) (int64, error) {
+	return limit(env.VariableRegistryMaxBlobSize, def)
+}
+
+// CheckLayers returns an error if a manifest with n signature or
+// attestation layers has more than $COSIGN_MAX_LAYERS.
+func CheckLayers(n int) error {
+	max, err := Layers()
+	if err != nil {
+		return err
+	}
+	if int64(n) > max {
+		return fmt.Errorf("manifest has %d layers, more than %d, set %s to raise the limit: %w", n, max, env.VariableMaxLayers, ErrExceeded)
+	}
+	return nil
+}
+
+// CheckJSONDepth returns an error if objects and arrays nest in data deeper
+// than max. It doesn't validate data, which is left to the JSON decoder.
+func CheckJSONDepth(data []byte, max int) error {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > max {
+				return fmt.Errorf("JSON nests deeper than %d levels, set %s to raise the limit: %w", max, env.VariableMaxJSONDepth, ErrExceeded)
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+// JSONDepth returns the deepest nesting of JSON read from a registry.
+func JSONDepth() (int, error) {
+	max, err := limit(env.VariableMaxJSONDepth, DefaultJSONDepth)
+	return int(max), err
+}
+
+// Unmarshal parses data, JSON read from a registry, into v, once it has
+// checked that data nests no deeper than $COSIGN_MAX_JSON_DEPTH.
+func Unmarshal(data []byte, v any) error {
+	max, err := JSONDepth()
+	if err != nil {
+		return err
+	}
+	if err := CheckJSONDepth(data, max); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,178 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestReadPayload(t *testing.T) {
+	t.Setenv(env.VariableMaxPayloadSize.String(), "4")
+
+	b, err := ReadPayload(strings.NewReader("abcd"))
+	if err != nil {
+		t.Fatalf("ReadPayload() = %v", err)
+	}
+	if string(b) != "abcd" {
+		t.Errorf("ReadPayload() = %q, want abcd", b)
+	}
+	if _, err := ReadPayload(strings.NewReader("abcde")); !errors.Is(err, ErrExceeded) {
+		t.Errorf("ReadPayload() of 5 bytes = %v, want ErrExceeded", err)
+	}
+}
+
+func TestInvalidLimit(t *testing.T) {
+	for _, v := range []string{"0", "-1", "1MiB"} {
+		t.Setenv(env.VariableMaxPayloadSize.String(), v)
+		if _, err := ReadPayload(strings.NewReader("")); err == nil || errors.Is(err, ErrExceeded) {
+			t.Errorf("ReadPayload() with %s=%s = %v, want an invalid limit error", env.VariableMaxPayloadSize, v, err)
+		}
+	}
+}
+
+func TestCheckAnnotation(t *testing.T) {
+	t.Setenv(env.VariableMaxAnnotationSize.String(), "3")
+	if err := CheckAnnotation("key", "abc"); err != nil {
+		t.Errorf("CheckAnnotation() = %v", err)
+	}
+	if err := CheckAnnotation("key", "abcd"); !errors.Is(err, ErrExceeded) {
+		t.Errorf("CheckAnnotation() = %v, want ErrExceeded", err)
+	}
+}
+
+func TestCheckLayers(t *testing.T) {
+	if err := CheckLayers(DefaultLayers); err != nil {
+		t.Errorf("CheckLayers(%d) = %v", DefaultLayers, err)
+	}
+	if err := CheckLayers(DefaultLayers + 1); !errors.Is(err, ErrExceeded) {
+		t.Errorf("CheckLayers(%d) = %v, want ErrExceeded", DefaultLayers+1, err)
+	}
+}
+
+func TestCheckJSONDepth(t *testing.T) {
+	tests := []struct {
+		data    string
+		max     int
+		wantErr bool
+	}{
+		{data: `{"a":[1,2]}`, max: 2},
+		{data: `{"a":[1,{}]}`, max: 2, wantErr: true},
+		{data: `{"a":"[[[[\"{{{{"}`, max: 1},
+		{data: `{"a\\":"[["}`, max: 1},
+		{data: `[[[`, max: 2, wantErr: true},
+		{data: `]]]]{}`, max: 1},
+	}
+	for _, tt := range tests {
+		err := CheckJSONDepth([]byte(tt.data), tt.max)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckJSONDepth(%s, %d) = %v, wantErr %v", tt.data, tt.max, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Setenv(env.VariableMaxJSONDepth.String(), "3")
+	var v any
+	if err := Unmarshal([]byte(`{"a":{"b":[]}}`), &v); err != nil {
+		t.Errorf("Unmarshal() = %v", err)
+	}
+	if err := Unmarshal([]byte(`{"a":{"b":[{}]}}`), &v); !errors.Is(err, ErrExceeded) {
+		t.Errorf("Unmarshal() = %v, want ErrExceeded", err)
+	}
+}
+
+// depth returns how deep objects and arrays nest in valid JSON.
+func depth(t *testing.T, data []byte) int {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	d, max := 0, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return max
+		}
+		if err != nil {
+			t.Fatalf("Token() = %v", err)
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			d++
+			if d > max {
+				max = d
+			}
+		case json.Delim('}'), json.Delim(']'):
+			d--
+		}
+	}
+}
+
+func FuzzCheckJSONDepth(f *testing.F) {
+	f.Add([]byte(`{"a":[1,{"b":"c"}]}`), 2)
+	f.Add([]byte(`["\"[", {"\\": [[]]}]`), 3)
+	f.Add([]byte(`{{{{`), 1)
+	f.Fuzz(func(t *testing.T, data []byte, max int) {
+		if max < 0 {
+			return
+		}
+		err := CheckJSONDepth(data, max)
+		if err != nil && !errors.Is(err, ErrExceeded) {
+			t.Fatalf("CheckJSONDepth() = %v, want nil or ErrExceeded", err)
+		}
+		if !json.Valid(data) {
+			return
+		}
+		if want := depth(t, data) > max; (err != nil) != want {
+			t.Fatalf("CheckJSONDepth(%q, %d) = %v, want exceeded %v", data, max, err, want)
+		}
+	})
+}
+
+func FuzzReadPayload(f *testing.F) {
+	f.Add([]byte("payload"), int64(4))
+	f.Fuzz(func(t *testing.T, data []byte, max int64) {
+		if max <= 0 {
+			return
+		}
+		t.Setenv(env.VariableMaxPayloadSize.String(), strconv.FormatInt(max, 10))
+		b, err := ReadPayload(bytes.NewReader(data))
+		switch {
+		case int64(len(data)) > max:
+			if !errors.Is(err, ErrExceeded) {
+				t.Fatalf("ReadPayload() of %d bytes with limit %d = %v, want ErrExceeded", len(data), max, err)
+			}
+		case err != nil || !bytes.Equal(b, data):
+			t.Fatalf("ReadPayload() = %q, %v, want %q", b, err, data)
+		}
+	})
+}
+
+func TestRegistrySizes(t *testing.T) {
+	if got, err := RegistryBlobSize(0); err != nil || got != 0 {
+		t.Errorf("RegistryBlobSize(0) = %d, %v, want no limit", got, err)
+	}
+	t.Setenv(env.VariableRegistryMaxManifestSize.String(), "1024")
+	if got, err := RegistryManifestSize(4 << 20); err != nil || got != 1024 {
+		t.Errorf("RegistryManifestSize() = %d, %v, want the limit of the environment", got, err)
+	}
+}
@@ -0,0 +1,61 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin runs the executables that extend cosign, such as SBOM
+// generators and vulnerability scanners.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Find resolves name to the path of the plugin executable prefix+name on
+// the PATH. Names that contain a path separator are used as they are.
+func Find(prefix, name string) (string, error) {
+	if strings.ContainsRune(name, os.PathSeparator) || strings.ContainsRune(name, '/') {
+		return name, nil
+	}
+	path, err := exec.LookPath(prefix + name)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("plugin %s%s not found on the PATH", prefix, name)
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// Output runs the plugin at path with args and returns what it wrote to
+// standard output. The plugin's standard error is passed through, and a
+// plugin that exits non-zero or writes nothing is an error.
+func Output(ctx context.Context, path string, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...) //nolint:gosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running %s: %w", path, err)
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("%s produced no output", path)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,71 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFindAndOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts require a POSIX shell")
+	}
+	bin := t.TempDir()
+	for name, script := range map[string]string{
+		"cosign-test-echo":   `echo "$@"`,
+		"cosign-test-fail":   "exit 3",
+		"cosign-test-silent": "",
+	} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script+"\n"), 0o700); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	ctx := context.Background()
+
+	path, err := Find("cosign-test-", "echo")
+	if err != nil {
+		t.Fatalf("Find() unexpected error: %v", err)
+	}
+	if want := filepath.Join(bin, "cosign-test-echo"); path != want {
+		t.Errorf("Find() = %s, want %s", path, want)
+	}
+	if path, _ := Find("cosign-test-", "./local"); path != "./local" {
+		t.Errorf("Find() = %s, want the path as it is", path)
+	}
+	if _, err := Find("cosign-test-", "missing"); err == nil || !strings.Contains(err.Error(), "cosign-test-missing not found") {
+		t.Errorf("Find() error = %v, want not found", err)
+	}
+
+	out, err := Output(ctx, path, "a", "b")
+	if err != nil {
+		t.Fatalf("Output() unexpected error: %v", err)
+	}
+	if string(out) != "a b\n" {
+		t.Errorf("Output() = %q, want %q", out, "a b\n")
+	}
+	if _, err := Output(ctx, filepath.Join(bin, "cosign-test-fail")); err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Output() error = %v, want exit status", err)
+	}
+	if _, err := Output(ctx, filepath.Join(bin, "cosign-test-silent")); err == nil || !strings.Contains(err.Error(), "produced no output") {
+		t.Errorf("Output() error = %v, want no output", err)
+	}
+}
@@ -0,0 +1,328 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profile applies the named profiles of the profiles config file,
+// which bundle the keys, endpoints, identity constraints, registries and
+// annotations of a team, to the flags of commands, so that operators signing
+// and verifying on behalf of several teams use the credentials and policies
+// of one team at a time. A flag set on the command line to another value than
+// the profile's is an error rather than an override.
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/pflag"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// ConfigFile is the format of the profiles config file.
+type ConfigFile struct {
+	// Profiles are the profiles by name.
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Profile is the configuration of the commands run with --profile. Each field
+// sets the flag named after it of the commands that have it.
+type Profile struct {
+	// Key is the --key of the commands, such as a KMS URI or the path of a
+	// private key.
+	Key string `json:"key,omitempty"`
+	// PublicKey is the --key of the verify commands instead of Key, for
+	// profiles whose Key is a private key.
+	PublicKey string `json:"publicKey,omitempty"`
+
+	FulcioURL          string `json:"fulcioURL,omitempty"`
+	RekorURL           string `json:"rekorURL,omitempty"`
+	OIDCIssuer         string `json:"oidcIssuer,omitempty"`
+	TimestampServerURL string `json:"timestampServerURL,omitempty"`
+
+	CertificateIdentity         string `json:"certificateIdentity,omitempty"`
+	CertificateIdentityRegexp   string `json:"certificateIdentityRegexp,omitempty"`
+	CertificateOIDCIssuer       string `json:"certificateOIDCIssuer,omitempty"`
+	CertificateOIDCIssuerRegexp string `json:"certificateOIDCIssuerRegexp,omitempty"`
+
+	// Registries are the registries, such as "ghcr.io", or repositories,
+	// such as "ghcr.io/team-a", that the images of the commands must be in.
+	// Any image may be used if empty.
+	Registries []string `json:"registries,omitempty"`
+	// RegistryCredentialHelpers are the --registry-credential-helper of the
+	// commands.
+	RegistryCredentialHelpers []string `json:"registryCredentialHelpers,omitempty"`
+	// Annotations are added to the --annotations of the commands, which sign
+	// commands add to signatures and verify commands require of them.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+var (
+	mu         sync.Mutex
+	active     string
+	registries []string
+)
+
+// ConfigPath returns the path of the profiles config file, which is
+// $COSIGN_PROFILES_FILE or cosign/profiles.json in the user's configuration
+// directory.
+func ConfigPath() string {
+	if path := env.Getenv(env.VariableProfilesFile); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cosign", "profiles.json")
+}
+
+// Load reads the profile named name, or $COSIGN_PROFILE if name is empty,
+// from the profiles config file and applies it to flags, the flags of the
+// command that runs, whose name is command. No profile applies if both are
+// empty.
+func Load(name, command string, flags *pflag.FlagSet) error {
+	if name == "" {
+		name = env.Getenv(env.VariableProfile)
+	}
+	set("", nil)
+	if name == "" {
+		return nil
+	}
+	p, err := loadProfile(name)
+	if err != nil {
+		return err
+	}
+	if err := p.apply(name, command, flags); err != nil {
+		return err
+	}
+	set(name, p.Registries)
+	return nil
+}
+
+func loadProfile(profile string) (*Profile, error) {
+	path := ConfigPath()
+	if path == "" {
+		return nil, fmt.Errorf("profile %q: no profiles config file", profile)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading profiles config: %w", err)
+	}
+	config := &ConfigFile{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(config); err != nil {
+		return nil, fmt.Errorf("parsing profiles config %s: %w", path, err)
+	}
+	p, ok := config.Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("profile %q isn't in the profiles config %s", profile, path)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("profile %q in %s: %w", profile, path, err)
+	}
+	return &p, nil
+}
+
+func (p *Profile) validate() error {
+	if p.CertificateIdentity != "" && p.CertificateIdentityRegexp != "" {
+		return errors.New("certificateIdentity and certificateIdentityRegexp are mutually exclusive")
+	}
+	if p.CertificateOIDCIssuer != "" && p.CertificateOIDCIssuerRegexp != "" {
+		return errors.New("certificateOIDCIssuer and certificateOIDCIssuerRegexp are mutually exclusive")
+	}
+	for _, r := range p.Registries {
+		if _, err := registryName(r); err != nil {
+			return fmt.Errorf("registries: %w", err)
+		}
+	}
+	for k := range p.Annotations {
+		if k == "" || strings.Contains(k, "=") {
+			return fmt.Errorf("invalid annotation key %q", k)
+		}
+	}
+	return nil
+}
+
+// apply sets the flags of p that flags has, which must not be set to other
+// values already.
+func (p *Profile) apply(profile, command string, flags *pflag.FlagSet) error {
+	key := p.Key
+	if p.PublicKey != "" && isVerify(command) {
+		key = p.PublicKey
+	}
+	for _, f := range []struct{ flag, value string }{
+		{"key", key},
+		{"fulcio-url", p.FulcioURL},
+		{"rekor-url", p.RekorURL},
+		{"oidc-issuer", p.OIDCIssuer},
+		{"timestamp-server-url", p.TimestampServerURL},
+		{"certificate-identity", p.CertificateIdentity},
+		{"certificate-identity-regexp", p.CertificateIdentityRegexp},
+		{"certificate-oidc-issuer", p.CertificateOIDCIssuer},
+		{"certificate-oidc-issuer-regexp", p.CertificateOIDCIssuerRegexp},
+	} {
+		flag := flags.Lookup(f.flag)
+		if f.value == "" || flag == nil {
+			continue
+		}
+		if flag.Changed {
+			if flag.Value.String() != f.value {
+				return fmt.Errorf("--%s %s conflicts with %s of profile %q", f.flag, flag.Value, f.value, profile)
+			}
+			continue
+		}
+		if err := flags.Set(f.flag, f.value); err != nil {
+			return fmt.Errorf("profile %q: --%s: %w", profile, f.flag, err)
+		}
+	}
+	if err := applySlice(flags, "registry-credential-helper", p.RegistryCredentialHelpers, profile); err != nil {
+		return err
+	}
+	return p.applyAnnotations(profile, flags)
+}
+
+// applySlice sets the slice flag named flag to values, unless it is set to
+// the same values already.
+func applySlice(flags *pflag.FlagSet, flag string, values []string, profile string) error {
+	f := flags.Lookup(flag)
+	if len(values) == 0 || f == nil {
+		return nil
+	}
+	s, ok := f.Value.(pflag.SliceValue)
+	if !ok {
+		return nil
+	}
+	if f.Changed {
+		if strings.Join(s.GetSlice(), ",") != strings.Join(values, ",") {
+			return fmt.Errorf("--%s %s conflicts with %s of profile %q", flag, strings.Join(s.GetSlice(), ","), strings.Join(values, ","), profile)
+		}
+		return nil
+	}
+	if err := s.Replace(values); err != nil {
+		return fmt.Errorf("profile %q: --%s: %w", profile, flag, err)
+	}
+	f.Changed = true
+	return nil
+}
+
+// applyAnnotations adds the annotations of p to those of --annotations, which
+// must not give them other values.
+func (p *Profile) applyAnnotations(profile string, flags *pflag.FlagSet) error {
+	f := flags.Lookup("annotations")
+	if len(p.Annotations) == 0 || f == nil {
+		return nil
+	}
+	s, ok := f.Value.(pflag.SliceValue)
+	if !ok {
+		return nil
+	}
+	given := map[string]string{}
+	for _, a := range s.GetSlice() {
+		k, v, _ := strings.Cut(a, "=")
+		given[k] = v
+	}
+	keys := make([]string, 0, len(p.Annotations))
+	for k := range p.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, ok := given[k]
+		if !ok {
+			if err := s.Append(k + "=" + p.Annotations[k]); err != nil {
+				return fmt.Errorf("profile %q: --annotations: %w", profile, err)
+			}
+			f.Changed = true
+			continue
+		}
+		if v != p.Annotations[k] {
+			return fmt.Errorf("--annotations %s=%s conflicts with %s=%s of profile %q", k, v, k, p.Annotations[k], profile)
+		}
+	}
+	return nil
+}
+
+// isVerify reports whether command, the path of a command without the
+// "cosign" prefix, verifies signatures, such as "verify-blob" or
+// "dockerfile verify".
+func isVerify(command string) bool {
+	for _, c := range strings.Fields(command) {
+		if strings.HasPrefix(c, "verify") {
+			return true
+		}
+	}
+	return false
+}
+
+// registryName returns the name of the registry or repository r.
+func registryName(r string) (string, error) {
+	if !strings.Contains(r, "/") {
+		reg, err := name.NewRegistry(r)
+		if err != nil {
+			return "", err
+		}
+		return reg.Name(), nil
+	}
+	repo, err := name.NewRepository(r)
+	if err != nil {
+		return "", err
+	}
+	return repo.Name(), nil
+}
+
+func set(profile string, regs []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	active, registries = profile, nil
+	for _, r := range regs {
+		// The registries were validated with the profile.
+		n, _ := registryName(r)
+		registries = append(registries, n)
+	}
+}
+
+// Active returns the name of the profile applied by Load, or "" if none.
+func Active() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return active
+}
+
+// CheckImage returns an error unless ref is in the registries of the profile
+// applied by Load. Any image is allowed without a profile, or if it has no
+// registries.
+func CheckImage(ref name.Reference) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if len(registries) == 0 {
+		return nil
+	}
+	repo := ref.Context()
+	for _, r := range registries {
+		if r == repo.RegistryStr() || r == repo.Name() || strings.HasPrefix(repo.Name(), r+"/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s isn't in the registries of profile %q: %s", ref, active, strings.Join(registries, ", "))
+}
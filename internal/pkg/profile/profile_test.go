@@ -0,0 +1,177 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/pflag"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+const config = `{"profiles": {
+	"team-a": {
+		"key": "awskms:///alias/team-a",
+		"publicKey": "team-a.pub",
+		"rekorURL": "https://rekor.team-a.example.com",
+		"certificateIdentity": "ci@team-a.example.com",
+		"registries": ["ghcr.io/team-a", "registry.team-a.example.com"],
+		"registryCredentialHelpers": ["ghcr.io=gcr"],
+		"annotations": {"team": "a", "env": "prod"}
+	},
+	"invalid": {"certificateIdentity": "ci@example.com", "certificateIdentityRegexp": ".*"}
+}}`
+
+func writeConfig(t *testing.T, config string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(env.VariableProfilesFile.String(), path)
+	t.Setenv(env.VariableProfile.String(), "")
+	t.Cleanup(func() { set("", nil) })
+}
+
+func newFlags() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("key", "", "")
+	flags.String("rekor-url", "https://rekor.sigstore.dev", "")
+	flags.String("certificate-identity", "", "")
+	flags.StringSlice("registry-credential-helper", nil, "")
+	flags.StringSlice("annotations", nil, "")
+	return flags
+}
+
+func TestLoad(t *testing.T) {
+	writeConfig(t, config)
+
+	flags := newFlags()
+	if err := flags.Parse([]string{"--rekor-url=https://rekor.team-a.example.com", "--annotations", "team=a,build=42"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load("team-a", "sign", flags); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	for flag, want := range map[string]string{
+		"key":                        "awskms:///alias/team-a",
+		"rekor-url":                  "https://rekor.team-a.example.com",
+		"certificate-identity":       "ci@team-a.example.com",
+		"registry-credential-helper": "[ghcr.io=gcr]",
+		"annotations":                "[team=a,build=42,env=prod]",
+	} {
+		if got := flags.Lookup(flag).Value.String(); got != want {
+			t.Errorf("--%s = %q, want %q", flag, got, want)
+		}
+	}
+	if Active() != "team-a" {
+		t.Errorf("Active() = %q, want team-a", Active())
+	}
+
+	// The verify commands use the public key.
+	flags = newFlags()
+	if err := Load("team-a", "dockerfile verify", flags); err != nil {
+		t.Fatal(err)
+	}
+	if got := flags.Lookup("key").Value.String(); got != "team-a.pub" {
+		t.Errorf("--key of verify = %q, want the public key", got)
+	}
+
+	// $COSIGN_PROFILE is the default profile.
+	t.Setenv(env.VariableProfile.String(), "team-a")
+	flags = newFlags()
+	if err := Load("", "sign", flags); err != nil || Active() != "team-a" {
+		t.Errorf("Load() with $COSIGN_PROFILE = %v, active %q", err, Active())
+	}
+	t.Setenv(env.VariableProfile.String(), "")
+	if err := Load("", "sign", newFlags()); err != nil || Active() != "" {
+		t.Errorf("Load() without a profile = %v, active %q", err, Active())
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	writeConfig(t, config)
+	for _, tc := range []struct {
+		name, profile string
+		args          []string
+		want          string
+	}{
+		{name: "conflicting key", profile: "team-a", args: []string{"--key", "team-b.key"}, want: "--key team-b.key conflicts with awskms:///alias/team-a"},
+		{name: "conflicting annotation", profile: "team-a", args: []string{"--annotations", "team=b"}, want: "--annotations team=b conflicts with team=a"},
+		{name: "conflicting helpers", profile: "team-a", args: []string{"--registry-credential-helper", "ecr"}, want: "--registry-credential-helper ecr conflicts"},
+		{name: "unknown profile", profile: "team-b", want: `profile "team-b" isn't in the profiles config`},
+		{name: "invalid profile", profile: "invalid", want: "mutually exclusive"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := newFlags()
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			err := Load(tc.profile, "sign", flags)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Load() = %v, want an error containing %q", err, tc.want)
+			}
+			if Active() != "" {
+				t.Errorf("Active() = %q after an error", Active())
+			}
+		})
+	}
+
+	writeConfig(t, `{"profiles": {}, "keys": {}}`)
+	if err := Load("team-a", "sign", newFlags()); err == nil || !strings.Contains(err.Error(), "parsing profiles config") {
+		t.Errorf("Load() with an unknown field = %v", err)
+	}
+	t.Setenv(env.VariableProfilesFile.String(), filepath.Join(t.TempDir(), "missing.json"))
+	if err := Load("team-a", "sign", newFlags()); err == nil {
+		t.Error("Load() with a missing config: expected an error")
+	}
+}
+
+func TestCheckImage(t *testing.T) {
+	writeConfig(t, config)
+	check := func(image string) error {
+		t.Helper()
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return CheckImage(ref)
+	}
+	if err := check("docker.io/team-b/app"); err != nil {
+		t.Errorf("CheckImage() without a profile = %v", err)
+	}
+
+	if err := Load("team-a", "sign", newFlags()); err != nil {
+		t.Fatal(err)
+	}
+	for image, ok := range map[string]bool{
+		"ghcr.io/team-a/app:v1": true,
+		"ghcr.io/team-a/tools/builder@sha256:" + strings.Repeat("0", 64): true,
+		"registry.team-a.example.com/app":                                true,
+		"ghcr.io/team-b/app":                                             false,
+		"ghcr.io/team-abc/app":                                           false,
+		"docker.io/library/alpine":                                       false,
+	} {
+		if err := check(image); (err == nil) != ok {
+			t.Errorf("CheckImage(%s) = %v, want allowed %v", image, err, ok)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package regevents parses the notifications that registries send to
+// webhooks when images are pushed to them.
+package regevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// Push is a manifest pushed to a registry.
+type Push struct {
+	// Repository is the full name of the repository, e.g.
+	// registry.example.com/team/app.
+	Repository string
+	Digest     string
+	// Tag is the tag the manifest was pushed with, if any.
+	Tag string
+}
+
+// Image returns the name of the pushed manifest by digest.
+func (p Push) Image() string {
+	return p.Repository + "@" + p.Digest
+}
+
+// cosignTag matches the tags that cosign stores the signatures, attestations
+// and SBOMs of an image at.
+var cosignTag = regexp.MustCompile(`^(sha256)-([0-9a-f]{64})\.(sig|att|sbom)$`)
+
+// Signs returns the image whose signatures, attestations or SBOM p is, by
+// digest, or an empty string if p isn't pushed at one of their tags.
+func (p Push) Signs() string {
+	m := cosignTag.FindStringSubmatch(p.Tag)
+	if m == nil {
+		return ""
+	}
+	return p.Repository + "@" + m[1] + ":" + m[2]
+}
+
+// distributionEnvelope is a notification of Distribution, the registry of
+// Docker.
+type distributionEnvelope struct {
+	Events []struct {
+		Action string `json:"action"`
+		Target struct {
+			MediaType  string `json:"mediaType"`
+			Digest     string `json:"digest"`
+			Repository string `json:"repository"`
+			URL        string `json:"url"`
+			Tag        string `json:"tag"`
+		} `json:"target"`
+		Request struct {
+			Host string `json:"host"`
+		} `json:"request"`
+	} `json:"events"`
+}
+
+// harborEvent is a notification of a Harbor webhook policy.
+type harborEvent struct {
+	Type      string `json:"type"`
+	EventData struct {
+		Resources []struct {
+			Digest      string `json:"digest"`
+			Tag         string `json:"tag"`
+			ResourceURL string `json:"resource_url"`
+		} `json:"resources"`
+		Repository struct {
+			RepoFullName string `json:"repo_full_name"`
+		} `json:"repository"`
+	} `json:"event_data"`
+}
+
+// Parse returns the manifests pushed according to a notification of
+// Distribution or of Harbor. Pushes of blobs and other events are ignored.
+func Parse(body []byte) ([]Push, error) {
+	var kind struct {
+		Events json.RawMessage `json:"events"`
+		Type   string          `json:"type"`
+	}
+	if err := json.Unmarshal(body, &kind); err != nil {
+		return nil, fmt.Errorf("parsing registry notification: %w", err)
+	}
+	switch {
+	case kind.Events != nil:
+		return parseDistribution(body)
+	case kind.Type != "":
+		return parseHarbor(body)
+	}
+	return nil, errors.New("unrecognized registry notification, expected one of Distribution or Harbor")
+}
+
+func parseDistribution(body []byte) ([]Push, error) {
+	var env distributionEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("parsing Distribution notification: %w", err)
+	}
+	var pushes []Push
+	for _, e := range env.Events {
+		mt := types.MediaType(e.Target.MediaType)
+		if e.Action != "push" || !(mt.IsImage() || mt.IsIndex()) {
+			continue
+		}
+		// The URL of the target names the registry as its clients reach
+		// it, the host of the request as the registry was reached.
+		host := e.Request.Host
+		if u, err := url.Parse(e.Target.URL); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		if host == "" || e.Target.Repository == "" || e.Target.Digest == "" {
+			return nil, fmt.Errorf("push without a registry, repository or digest in Distribution notification: %+v", e.Target)
+		}
+		pushes = append(pushes, Push{Repository: host + "/" + e.Target.Repository, Digest: e.Target.Digest, Tag: e.Target.Tag})
+	}
+	return pushes, nil
+}
+
+func parseHarbor(body []byte) ([]Push, error) {
+	var e harborEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("parsing Harbor notification: %w", err)
+	}
+	if e.Type != "PUSH_ARTIFACT" {
+		return nil, nil
+	}
+	var pushes []Push
+	for _, r := range e.EventData.Resources {
+		// The resource URL is the image by tag or digest, e.g.
+		// harbor.example.com/library/app:v1.
+		host, _, _ := strings.Cut(r.ResourceURL, "/")
+		if host == "" || e.EventData.Repository.RepoFullName == "" || r.Digest == "" {
+			return nil, fmt.Errorf("push without a registry, repository or digest in Harbor notification: %s", r.ResourceURL)
+		}
+		pushes = append(pushes, Push{Repository: host + "/" + e.EventData.Repository.RepoFullName, Digest: r.Digest, Tag: r.Tag})
+	}
+	return pushes, nil
+}
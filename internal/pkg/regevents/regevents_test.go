@@ -0,0 +1,88 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package regevents
+
+import (
+	"reflect"
+	"testing"
+)
+
+const digest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		body    string
+		want    []Push
+		wantErr bool
+	}{{
+		name: "distribution",
+		body: `{"events": [
+			{"action": "push", "target": {"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "digest": "sha256:2222", "repository": "team/app"}, "request": {"host": "registry:5000"}},
+			{"action": "push", "target": {"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "` + digest + `", "repository": "team/app", "url": "https://registry.example.com/v2/team/app/manifests/` + digest + `", "tag": "v1"}, "request": {"host": "registry:5000"}},
+			{"action": "pull", "target": {"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "` + digest + `", "repository": "team/app"}, "request": {"host": "registry:5000"}},
+			{"action": "push", "target": {"mediaType": "application/vnd.oci.image.index.v1+json", "digest": "` + digest + `", "repository": "team/base"}, "request": {"host": "registry:5000"}}
+		]}`,
+		want: []Push{
+			{Repository: "registry.example.com/team/app", Digest: digest, Tag: "v1"},
+			{Repository: "registry:5000/team/base", Digest: digest},
+		},
+	}, {
+		name: "harbor",
+		body: `{"type": "PUSH_ARTIFACT", "event_data": {
+			"resources": [{"digest": "` + digest + `", "tag": "v1", "resource_url": "harbor.example.com/library/app:v1"}],
+			"repository": {"name": "app", "namespace": "library", "repo_full_name": "library/app"}
+		}}`,
+		want: []Push{{Repository: "harbor.example.com/library/app", Digest: digest, Tag: "v1"}},
+	}, {
+		name: "harbor pull",
+		body: `{"type": "PULL_ARTIFACT", "event_data": {"resources": [{"digest": "` + digest + `", "resource_url": "harbor.example.com/library/app@` + digest + `"}]}}`,
+	}, {
+		name:    "distribution without a registry",
+		body:    `{"events": [{"action": "push", "target": {"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "` + digest + `", "repository": "app"}}]}`,
+		wantErr: true,
+	}, {
+		name:    "unrecognized",
+		body:    `{"repository": "app"}`,
+		wantErr: true,
+	}, {
+		name:    "invalid",
+		body:    `{`,
+		wantErr: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse([]byte(tc.body))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPushSigns(t *testing.T) {
+	sig := Push{Repository: "registry.example.com/app", Digest: digest, Tag: "sha256-2222222222222222222222222222222222222222222222222222222222222222.sig"}
+	if got, want := sig.Signs(), "registry.example.com/app@sha256:2222222222222222222222222222222222222222222222222222222222222222"; got != want {
+		t.Errorf("Signs() = %q, want %q", got, want)
+	}
+	for _, tag := range []string{"", "v1", "sha256-2222.sig"} {
+		if got := (Push{Repository: "registry.example.com/app", Digest: digest, Tag: tag}).Signs(); got != "" {
+			t.Errorf("Signs() of tag %q = %q, want none", tag, got)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retention selects the signatures and attestations of a repository
+// that a retention policy no longer keeps, such as those of all but the most
+// recently signed tags or the attestations signed more than a year ago.
+package retention
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy is the format of the retention policy file.
+type Policy struct {
+	// Signatures is what the policy keeps of the signatures.
+	Signatures Rule `json:"signatures,omitempty"`
+	// Attestations is what the policy keeps of the attestations.
+	Attestations Rule `json:"attestations,omitempty"`
+}
+
+// Rule is what a policy keeps of one kind of attachment. An entry is kept
+// only if each of the limits that are set keeps it.
+type Rule struct {
+	// KeepSignedTags keeps only the entries attached to the digests of the
+	// KeepSignedTags most recently signed tags of the repository.
+	KeepSignedTags int `json:"keepSignedTags,omitempty"`
+	// MaxAge keeps only the entries signed within MaxAge. Entries without a
+	// signing time are kept.
+	MaxAge Age `json:"maxAge,omitempty"`
+}
+
+func (r Rule) set() bool {
+	return r.KeepSignedTags > 0 || r.MaxAge > 0
+}
+
+// Age is the maximum age of the entries a rule keeps, as a Go duration such
+// as "720h" or a number of days such as "365d".
+type Age time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Age) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("maxAge must be a string such as \"365d\" or \"720h\": %w", err)
+	}
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return fmt.Errorf("invalid maxAge %q: %w", s, err)
+		}
+		*a = Age(time.Duration(n) * 24 * time.Hour)
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid maxAge %q: %w", s, err)
+	}
+	*a = Age(d)
+	return nil
+}
+
+func (a Age) String() string {
+	d := time.Duration(a)
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// Load reads the retention policy file at path.
+func Load(path string) (*Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading retention policy: %w", err)
+	}
+	p := &Policy{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing retention policy %s: %w", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("retention policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+func (p *Policy) validate() error {
+	for kind, r := range map[string]Rule{"signatures": p.Signatures, "attestations": p.Attestations} {
+		if r.KeepSignedTags < 0 {
+			return fmt.Errorf("%s: keepSignedTags must not be negative, got %d", kind, r.KeepSignedTags)
+		}
+		if r.MaxAge < 0 {
+			return fmt.Errorf("%s: maxAge must not be negative, got %s", kind, r.MaxAge)
+		}
+	}
+	if !p.Signatures.set() && !p.Attestations.set() {
+		return errors.New("no signatures or attestations rule is set, so it keeps everything")
+	}
+	return nil
+}
+
+// KeepsSignedTags reports whether a rule of p keeps the entries of the most
+// recently signed tags, which then need to be resolved to their digests.
+func (p *Policy) KeepsSignedTags() bool {
+	return p.Signatures.KeepSignedTags > 0 || p.Attestations.KeepSignedTags > 0
+}
+
+// Attachment is a signature or attestation tag of a repository.
+type Attachment struct {
+	// Tag is the name of the tag.
+	Tag string
+	// Digest is the digest of the artifact the tag is attached to.
+	Digest string
+	// Attestation is set for attestation tags.
+	Attestation bool
+	// Entries are the signatures or attestations stored in the tag.
+	Entries []Entry
+}
+
+// Entry is a signature or attestation of an attachment.
+type Entry struct {
+	// Digest is the digest of the layer of the entry.
+	Digest string
+	// SignedAt is the time the entry was signed, or nil if it is unknown.
+	SignedAt *time.Time
+}
+
+// Removal is an entry that the policy doesn't keep.
+type Removal struct {
+	// Index is the index of the entry in the entries of its attachment.
+	Index  int
+	Reason string
+}
+
+// Select returns the removals of the entries of each attachment, indexed as
+// attachments, at time now. tags maps the other tags of the repository to
+// the digests they point at.
+//
+// A tag is signed when its digest has a signature tag, and it was signed at
+// the newest signing time of the signatures. Tags without a signing time are
+// the least recently signed.
+func (p *Policy) Select(now time.Time, tags map[string]string, attachments []Attachment) [][]Removal {
+	signed := SignedTags(tags, attachments)
+	removals := make([][]Removal, len(attachments))
+	for i, a := range attachments {
+		r := p.Signatures
+		if a.Attestation {
+			r = p.Attestations
+		}
+		if r.KeepSignedTags > 0 && !keptDigest(a.Digest, signed, tags, r.KeepSignedTags) {
+			reason := fmt.Sprintf("not attached to any of the %d most recently signed tags", r.KeepSignedTags)
+			for j := range a.Entries {
+				removals[i] = append(removals[i], Removal{Index: j, Reason: reason})
+			}
+			continue
+		}
+		if r.MaxAge <= 0 {
+			continue
+		}
+		cutoff := now.Add(-time.Duration(r.MaxAge))
+		for j, e := range a.Entries {
+			if e.SignedAt != nil && e.SignedAt.Before(cutoff) {
+				removals[i] = append(removals[i], Removal{
+					Index:  j,
+					Reason: fmt.Sprintf("signed at %s, more than %s ago", e.SignedAt.UTC().Format(time.RFC3339), r.MaxAge),
+				})
+			}
+		}
+	}
+	return removals
+}
+
+// SignedTags returns the tags whose digests have a signature tag in
+// attachments, most recently signed first.
+func SignedTags(tags map[string]string, attachments []Attachment) []string {
+	signedAt := map[string]*time.Time{}
+	for _, a := range attachments {
+		if a.Attestation {
+			continue
+		}
+		var newest *time.Time
+		for _, e := range a.Entries {
+			if e.SignedAt != nil && (newest == nil || e.SignedAt.After(*newest)) {
+				newest = e.SignedAt
+			}
+		}
+		signedAt[a.Digest] = newest
+	}
+
+	var signed []string
+	for tag, digest := range tags {
+		if _, ok := signedAt[digest]; ok {
+			signed = append(signed, tag)
+		}
+	}
+	sort.Slice(signed, func(i, j int) bool {
+		ti, tj := signedAt[tags[signed[i]]], signedAt[tags[signed[j]]]
+		switch {
+		case ti == nil && tj == nil:
+		case ti == nil:
+			return false
+		case tj == nil:
+			return true
+		case !ti.Equal(*tj):
+			return ti.After(*tj)
+		}
+		return signed[i] < signed[j]
+	})
+	return signed
+}
+
+// keptDigest reports whether digest is the digest of one of the first n
+// signed tags.
+func keptDigest(digest string, signed []string, tags map[string]string, n int) bool {
+	for i, tag := range signed {
+		if i == n {
+			break
+		}
+		if tags[tag] == digest {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	for _, tc := range []struct {
+		policy string
+		want   Policy
+		err    string
+	}{
+		{
+			policy: `{"signatures": {"keepSignedTags": 10}, "attestations": {"maxAge": "365d"}}`,
+			want:   Policy{Signatures: Rule{KeepSignedTags: 10}, Attestations: Rule{MaxAge: Age(365 * 24 * time.Hour)}},
+		},
+		{
+			policy: `{"signatures": {"maxAge": "720h"}}`,
+			want:   Policy{Signatures: Rule{MaxAge: Age(720 * time.Hour)}},
+		},
+		{policy: `{}`, err: "keeps everything"},
+		{policy: `{"signatures": {"keepSignedTags": -1}}`, err: "must not be negative"},
+		{policy: `{"attestations": {"maxAge": "a year"}}`, err: "invalid maxAge"},
+		{policy: `{"attestations": {"maxAge": 365}}`, err: "must be a string"},
+		{policy: `{"images": {"keepSignedTags": 1}}`, err: "unknown field"},
+	} {
+		path := filepath.Join(t.TempDir(), "retention.json")
+		if err := os.WriteFile(path, []byte(tc.policy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		p, err := Load(path)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Load(%s) = %v, want an error containing %q", tc.policy, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Load(%s) = %v", tc.policy, err)
+		}
+		if *p != tc.want {
+			t.Errorf("Load(%s) = %+v, want %+v", tc.policy, *p, tc.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+	// v1 and v2 were signed before v3, latest points at v3, and v0 is
+	// signed without a signing time.
+	tags := map[string]string{"v0": "sha256:0", "v1": "sha256:1", "v2": "sha256:2", "v3": "sha256:3", "latest": "sha256:3", "unsigned": "sha256:4"}
+	attachments := []Attachment{
+		{Tag: "sha256-0.sig", Digest: "sha256:0", Entries: []Entry{{Digest: "sha256:a"}}},
+		{Tag: "sha256-1.sig", Digest: "sha256:1", Entries: []Entry{{Digest: "sha256:b", SignedAt: at(500)}}},
+		{Tag: "sha256-2.sig", Digest: "sha256:2", Entries: []Entry{{Digest: "sha256:c", SignedAt: at(400)}, {Digest: "sha256:d", SignedAt: at(10)}}},
+		{Tag: "sha256-3.sig", Digest: "sha256:3", Entries: []Entry{{Digest: "sha256:e", SignedAt: at(20)}}},
+		{Tag: "sha256-1.att", Digest: "sha256:1", Attestation: true, Entries: []Entry{{Digest: "sha256:f", SignedAt: at(500)}, {Digest: "sha256:g"}}},
+		{Tag: "sha256-3.att", Digest: "sha256:3", Attestation: true, Entries: []Entry{{Digest: "sha256:h", SignedAt: at(400)}, {Digest: "sha256:i", SignedAt: at(20)}}},
+	}
+
+	if got, want := SignedTags(tags, attachments), []string{"v2", "latest", "v3", "v1", "v0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SignedTags() = %v, want %v", got, want)
+	}
+
+	p := Policy{Signatures: Rule{KeepSignedTags: 2}, Attestations: Rule{MaxAge: Age(365 * 24 * time.Hour)}}
+	got := p.Select(now, tags, attachments)
+	notSigned := "not attached to any of the 2 most recently signed tags"
+	want := [][]Removal{
+		{{Index: 0, Reason: notSigned}},
+		{{Index: 0, Reason: notSigned}},
+		nil,
+		nil,
+		{{Index: 0, Reason: "signed at 2025-01-17T00:00:00Z, more than 365d ago"}},
+		{{Index: 0, Reason: "signed at 2025-04-27T00:00:00Z, more than 365d ago"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Select() = %v, want %v", got, want)
+	}
+
+	p = Policy{Signatures: Rule{KeepSignedTags: 3, MaxAge: Age(200 * 24 * time.Hour)}}
+	got = p.Select(now, tags, attachments)
+	want = [][]Removal{
+		{{Index: 0, Reason: "not attached to any of the 3 most recently signed tags"}},
+		{{Index: 0, Reason: "not attached to any of the 3 most recently signed tags"}},
+		{{Index: 0, Reason: "signed at 2025-04-27T00:00:00Z, more than 200d ago"}},
+		nil,
+		nil,
+		nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Select() = %v, want %v", got, want)
+	}
+}
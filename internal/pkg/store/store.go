@@ -0,0 +1,248 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package store archives the signatures and attestations that cosign
+// produces in a local directory, by the digest of their subject, so that
+// they aren't lost when pushing them to a registry or uploading them to the
+// transparency log fails.
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// The kinds of entries.
+const (
+	KindSignature   = "signature"
+	KindAttestation = "attestation"
+)
+
+// Entry is a signature or attestation archived in a store.
+type Entry struct {
+	Kind string `json:"kind"`
+	// Subject is the image, by digest, or the path of the blob that was
+	// signed or attested, and Digest its <algorithm>:<hex> digest.
+	Subject   string    `json:"subject"`
+	Digest    string    `json:"digest"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Payload is the signed payload of an image signature or the DSSE
+	// envelope of an attestation. The payload of a blob signature is the
+	// blob, which isn't archived.
+	Payload          []byte                   `json:"payload,omitempty"`
+	Signature        string                   `json:"signature,omitempty"`
+	Certificate      string                   `json:"certificate,omitempty"`
+	Chain            string                   `json:"chain,omitempty"`
+	Bundle           *bundle.RekorBundle      `json:"bundle,omitempty"`
+	RFC3161Timestamp *bundle.RFC3161Timestamp `json:"rfc3161Timestamp,omitempty"`
+}
+
+// ID returns the hex SHA-256 digest of the payload and signature of e, which
+// its file is named after, so that archiving it again, e.g. once it has a
+// transparency log bundle, replaces it.
+func (e *Entry) ID() string {
+	h := sha256.New()
+	h.Write(e.Payload)
+	h.Write([]byte(e.Signature))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FromSignature returns the entry of kind of sig, made for subject with the
+// digest.
+func FromSignature(kind, subject, digest string, sig oci.Signature) (*Entry, error) {
+	e := &Entry{Kind: kind, Subject: subject, Digest: digest, CreatedAt: time.Now().UTC()}
+	var err error
+	if e.Payload, err = sig.Payload(); err != nil {
+		return nil, err
+	}
+	if e.Signature, err = sig.Base64Signature(); err != nil {
+		return nil, err
+	}
+	cert, err := sig.Cert()
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		b, err := cryptoutils.MarshalCertificateToPEM(cert)
+		if err != nil {
+			return nil, err
+		}
+		e.Certificate = string(b)
+	}
+	chain, err := sig.Chain()
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) > 0 {
+		b, err := cryptoutils.MarshalCertificatesToPEM(chain)
+		if err != nil {
+			return nil, err
+		}
+		e.Chain = string(b)
+	}
+	if e.Bundle, err = sig.Bundle(); err != nil {
+		return nil, err
+	}
+	if e.RFC3161Timestamp, err = sig.RFC3161Timestamp(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Dir returns the directory of the store that cosign archives to, from
+// $COSIGN_STORE, or an empty string if it doesn't archive. 1 or true is
+// .cosign/store in the user's home directory.
+func Dir() (string, error) {
+	v := env.Getenv(env.VariableStore)
+	if v == "" {
+		return "", nil
+	}
+	if on, err := strconv.ParseBool(v); err == nil {
+		if !on {
+			return "", nil
+		}
+		return DefaultDir()
+	}
+	return v, nil
+}
+
+// DefaultDir returns .cosign/store in the user's home directory.
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding the store in the home directory: %w", err)
+	}
+	return filepath.Join(home, ".cosign", "store"), nil
+}
+
+// Store is a directory of entries, at <algorithm>/<hex>/<id>.json by the
+// digest of their subject.
+type Store struct {
+	dir string
+}
+
+// New returns the store in dir.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Put archives e, and returns the path of its file.
+func (s *Store) Put(e *Entry) (string, error) {
+	alg, hexDigest, ok := strings.Cut(e.Digest, ":")
+	if !ok || alg == "" || hexDigest == "" || strings.ContainsAny(e.Digest, `/\.`) {
+		return "", fmt.Errorf("invalid subject digest %q", e.Digest)
+	}
+	dir := filepath.Join(s.dir, alg, hexDigest)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating store directory: %w", err)
+	}
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	// The entry is written to a temporary file first so that an
+	// interrupted write doesn't corrupt the entry it replaces.
+	p := filepath.Join(dir, e.ID()+".json")
+	tmp, err := os.CreateTemp(dir, ".entry-*")
+	if err != nil {
+		return "", fmt.Errorf("writing store entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("writing store entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("writing store entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", fmt.Errorf("writing store entry: %w", err)
+	}
+	return p, nil
+}
+
+// Query returns the entries of the subject with digest, or of every subject
+// if empty, oldest first.
+func (s *Store) Query(digest string) ([]Entry, error) {
+	pattern := filepath.Join(s.dir, "*", "*", "*.json")
+	if digest != "" {
+		alg, hexDigest, ok := strings.Cut(digest, ":")
+		if !ok || strings.ContainsAny(digest, `/\.*?[`) {
+			return nil, fmt.Errorf("invalid digest %q, expected <algorithm>:<hex>", digest)
+		}
+		pattern = filepath.Join(s.dir, alg, hexDigest, "*.json")
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(files))
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading store entry: %w", err)
+		}
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			return nil, fmt.Errorf("parsing store entry %s: %w", f, err)
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].CreatedAt.Before(entries[j].CreatedAt) })
+	return entries, nil
+}
+
+// Archive archives e in the store of $COSIGN_STORE, if set. Failing to
+// archive only warns, since the store is a copy of what cosign produced.
+func Archive(ctx context.Context, e *Entry) {
+	dir, err := Dir()
+	if err == nil && dir == "" {
+		return
+	}
+	if err == nil {
+		_, err = New(dir).Put(e)
+	}
+	if err != nil {
+		ui.Warnf(ctx, "Archiving the %s of %s in the local store: %v", e.Kind, e.Subject, err)
+	}
+}
+
+// ArchiveSignature archives the entry of sig, as Archive does.
+func ArchiveSignature(ctx context.Context, kind, subject, digest string, sig oci.Signature) {
+	if dir, err := Dir(); err == nil && dir == "" {
+		return
+	}
+	e, err := FromSignature(kind, subject, digest, sig)
+	if err != nil {
+		ui.Warnf(ctx, "Archiving the %s of %s in the local store: %v", kind, subject, err)
+		return
+	}
+	Archive(ctx, e)
+}
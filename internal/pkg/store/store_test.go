@@ -0,0 +1,108 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+const digest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+
+func TestPutQuery(t *testing.T) {
+	s := New(t.TempDir())
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sig := &Entry{Kind: KindSignature, Subject: "example.com/app@" + digest, Digest: digest, CreatedAt: created.Add(time.Minute), Payload: []byte("{}"), Signature: "c2ln"}
+	att := &Entry{Kind: KindAttestation, Subject: "example.com/app@" + digest, Digest: digest, CreatedAt: created, Payload: []byte(`{"payloadType": "application/vnd.in-toto+json"}`)}
+	other := &Entry{Kind: KindSignature, Subject: "app.tar.gz", Digest: "sha512:2222", CreatedAt: created, Signature: "b3RoZXI="}
+	for _, e := range []*Entry{sig, att, other} {
+		p, err := s.Put(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(p) != e.ID()+".json" {
+			t.Errorf("Put() = %s, want a file named after the ID %s", p, e.ID())
+		}
+	}
+	// Archiving an entry again, with its bundle, replaces it.
+	sig.Bundle = &bundle.RekorBundle{Payload: bundle.RekorPayload{LogIndex: 42}}
+	if _, err := s.Put(sig); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := s.Query(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Kind != KindAttestation || entries[1].Bundle == nil || entries[1].Bundle.Payload.LogIndex != 42 {
+		t.Errorf("Query(%s) = %+v, want the attestation then the signature with its bundle", digest, entries)
+	}
+	if entries, err := s.Query(""); err != nil || len(entries) != 3 {
+		t.Errorf("Query() = %d entries, %v, want 3", len(entries), err)
+	}
+	if entries, err := s.Query("sha256:3333"); err != nil || len(entries) != 0 {
+		t.Errorf("Query() of an unknown digest = %+v, %v, want none", entries, err)
+	}
+	for _, d := range []string{"sha256", "../sha256:1111", "sha256:*"} {
+		if _, err := s.Query(d); err == nil {
+			t.Errorf("Query(%q): expected an error", d)
+		}
+	}
+	if _, err := s.Put(&Entry{Kind: KindSignature, Digest: "../../etc:passwd"}); err == nil {
+		t.Error("Put() of an entry with an invalid digest: expected an error")
+	}
+}
+
+func TestDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, tc := range []struct {
+		value, want string
+	}{
+		{value: "", want: ""},
+		{value: "0", want: ""},
+		{value: "1", want: filepath.Join(home, ".cosign", "store")},
+		{value: "true", want: filepath.Join(home, ".cosign", "store")},
+		{value: "/var/lib/cosign-store", want: "/var/lib/cosign-store"},
+	} {
+		t.Setenv(env.VariableStore.String(), tc.value)
+		if got, err := Dir(); err != nil || got != tc.want {
+			t.Errorf("Dir() with $COSIGN_STORE=%q = %q, %v, want %q", tc.value, got, err, tc.want)
+		}
+	}
+}
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	e := &Entry{Kind: KindSignature, Subject: "app.tar.gz", Digest: digest, CreatedAt: time.Now(), Signature: "c2ln"}
+
+	t.Setenv(env.VariableStore.String(), "")
+	Archive(context.Background(), e)
+	if entries, _ := New(dir).Query(""); len(entries) != 0 {
+		t.Errorf("Archive() without $COSIGN_STORE archived %+v", entries)
+	}
+
+	t.Setenv(env.VariableStore.String(), dir)
+	Archive(context.Background(), e)
+	if entries, _ := New(dir).Query(digest); len(entries) != 1 || entries[0].Subject != "app.tar.gz" {
+		t.Errorf("Archive() with $COSIGN_STORE archived %+v", entries)
+	}
+}
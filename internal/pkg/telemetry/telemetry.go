@@ -0,0 +1,80 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry counts the requests cosign makes to registries and
+// transparency logs, for the summary of operations on many images, which
+// helps tune their parallelism and spot failures shared by every image.
+package telemetry
+
+import (
+	"net/http"
+	"sync"
+)
+
+// The kinds of services requests are counted for.
+const (
+	Registry = "registry"
+	Rekor    = "rekor"
+)
+
+var (
+	mu       sync.Mutex
+	requests = map[string]map[string]int{}
+)
+
+// Transport returns a transport that counts the requests sent through next
+// by host, as requests to a service of kind.
+func Transport(next http.RoundTripper, kind string) http.RoundTripper {
+	return &transport{next: next, kind: kind}
+}
+
+type transport struct {
+	next http.RoundTripper
+	kind string
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	count(t.kind, req.URL.Host)
+	return t.next.RoundTrip(req)
+}
+
+// count records a request to host, a service of kind.
+func count(kind, host string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if requests[kind] == nil {
+		requests[kind] = map[string]int{}
+	}
+	requests[kind][host]++
+}
+
+// Requests returns the number of requests made to each host of the services
+// of kind so far.
+func Requests(kind string) map[string]int {
+	mu.Lock()
+	defer mu.Unlock()
+	counts := make(map[string]int, len(requests[kind]))
+	for host, n := range requests[kind] {
+		counts[host] = n
+	}
+	return counts
+}
+
+// Reset forgets the requests counted so far.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	requests = map[string]map[string]int{}
+}
@@ -22,7 +22,8 @@ import (
 
 // An Env is the environment that the CLI exists in.
 //
-// It contains handles to STDERR and STDIN. Eventually, it will contain
+// It contains handles to STDERR and STDIN, and the message catalog used to
+// localize what is written to STDERR. Eventually, it will contain
 // configuration pertaining to the current invocation (e.g., is this a terminal
 // or not).
 //
@@ -32,14 +33,18 @@ import (
 type Env struct {
 	Stderr io.Writer
 	Stdin  io.Reader
+	// Messages translates user-facing messages. A nil Catalog leaves
+	// messages in English.
+	Messages Catalog
 }
 
-// defaultEnv returns the default environment (writing to os.Stderr and
-// reading from os.Stdin).
+// defaultEnv returns the default environment (writing to os.Stderr,
+// reading from os.Stdin and translating messages for COSIGN_LOCALE).
 func defaultEnv() *Env {
 	return &Env{
-		Stderr: os.Stderr,
-		Stdin:  os.Stdin,
+		Stderr:   os.Stderr,
+		Stdin:    os.Stdin,
+		Messages: catalogFromEnv(),
 	}
 }
 
@@ -79,7 +84,7 @@ type callbackFunc func(context.Context, WriteFunc)
 func RunWithTestCtx(callback callbackFunc) string {
 	var stdin bytes.Buffer
 	var stderr bytes.Buffer
-	e := Env{&stderr, &stdin}
+	e := Env{Stderr: &stderr, Stdin: &stdin}
 
 	ctx := WithEnv(context.Background(), &e)
 	write := func(msg string) { stdin.WriteString(msg) }
@@ -0,0 +1,111 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// A Catalog maps the English format string of a user-facing message to its
+// translation. Messages missing from a catalog are printed in English.
+//
+// Only human readable output written through an Env is translated; JSON
+// output, error types and exit codes never depend on the locale.
+type Catalog map[string]string
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	defaultCatalogOnce sync.Once
+	defaultCatalog     Catalog
+)
+
+// translate returns the translation of msg, or msg itself if there is none.
+func (c Catalog) translate(msg string) string {
+	if t, ok := c[msg]; ok && t != "" {
+		return t
+	}
+	return msg
+}
+
+// LoadCatalog returns the built-in catalog for locale.
+//
+// locale may be a POSIX locale name (e.g. "de_DE.UTF-8") or a BCP 47 tag
+// (e.g. "de-DE"); the most specific available catalog is used. It returns
+// nil, which translates nothing, for English or unknown locales.
+func LoadCatalog(locale string) Catalog {
+	for _, name := range candidateLocales(locale) {
+		b, err := locales.ReadFile(path.Join("locales", name+".json"))
+		if err != nil {
+			continue
+		}
+		c := Catalog{}
+		if err := json.Unmarshal(b, &c); err != nil {
+			// The catalogs are embedded at build time and covered by tests.
+			panic(fmt.Sprintf("invalid message catalog %s: %v", name, err))
+		}
+		return c
+	}
+	return nil
+}
+
+// candidateLocales returns the catalog names to look up for locale, from most
+// to least specific.
+func candidateLocales(locale string) []string {
+	// Drop the codeset and modifier, e.g. "de_DE.UTF-8@euro".
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	locale = strings.ReplaceAll(locale, "-", "_")
+	switch strings.ToLower(locale) {
+	case "", "c", "posix":
+		return nil
+	}
+
+	lang, region, hasRegion := strings.Cut(locale, "_")
+	lang = strings.ToLower(lang)
+	if !hasRegion {
+		return []string{lang}
+	}
+	return []string{lang + "_" + strings.ToUpper(region), lang}
+}
+
+// catalogFromEnv returns the catalog selected by the COSIGN_LOCALE
+// environment variable.
+func catalogFromEnv() Catalog {
+	defaultCatalogOnce.Do(func() {
+		defaultCatalog = LoadCatalog(env.Getenv(env.VariableLocale))
+	})
+	return defaultCatalog
+}
+
+func (w *Env) sprintf(msg string, a ...any) string {
+	return fmt.Sprintf(w.Messages.translate(msg), a...)
+}
+
+// Sprintf works like fmt.Sprintf, except that the format string is first
+// translated using the catalog of the environment in ctx. Use it for
+// user-facing messages that are not written through Infof or Warnf.
+func Sprintf(ctx context.Context, msg string, a ...any) string {
+	return getEnv(ctx).sprintf(msg, a...)
+}
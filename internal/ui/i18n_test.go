@@ -0,0 +1,81 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui_test
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	cases := []struct {
+		locale string
+		found  bool
+	}{
+		{"", false},
+		{"C", false},
+		{"POSIX", false},
+		{"en_US.UTF-8", false},
+		{"xx", false},
+		{"de", true},
+		{"de_DE.UTF-8", true},
+		{"de-AT", true},
+		{"DE_de@euro", true},
+		{"es", true},
+		{"es_MX", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.locale, func(t *testing.T) {
+			c := ui.LoadCatalog(tc.locale)
+			assert.Equal(t, tc.found, len(c) > 0)
+		})
+	}
+}
+
+var verbRe = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsKeepFormatVerbs(t *testing.T) {
+	for _, locale := range []string{"de", "es"} {
+		for msg, translation := range ui.LoadCatalog(locale) {
+			assert.Equal(t, verbRe.FindAllString(msg, -1), verbRe.FindAllString(translation, -1),
+				"%s translation of %q changes the format verbs", locale, msg)
+		}
+	}
+}
+
+func TestLocalizedOutput(t *testing.T) {
+	var stderr bytes.Buffer
+	stdin := bytes.NewBufferString("n\n")
+	e := &ui.Env{Stderr: &stderr, Stdin: stdin, Messages: ui.Catalog{
+		"foo: %v": "le foo: %v",
+		"Are you sure you would like to continue? [y/N] ": "Continuer ? [y/N] ",
+	}}
+	ctx := ui.WithEnv(context.Background(), e)
+
+	ui.Infof(ctx, "foo: %v", "bar")
+	ui.Warnf(ctx, "foo: %v", "baz")
+	ui.Infof(ctx, "untranslated: %v", 1)
+	assert.Equal(t, "le foo: bar\nWARNING: le foo: baz\nuntranslated: 1\n", stderr.String())
+	assert.Equal(t, "le foo: qux", ui.Sprintf(ctx, "foo: %v", "qux"))
+
+	stderr.Reset()
+	assert.EqualValues(t, &ui.ErrPromptDeclined{}, ui.ConfirmContinue(ctx))
+	assert.Equal(t, "Continuer ? [y/N] ", stderr.String())
+}
@@ -19,18 +19,20 @@ import (
 )
 
 func (w *Env) infof(msg string, a ...any) {
-	msg = fmt.Sprintf(msg, a...)
+	msg = w.sprintf(msg, a...)
 	fmt.Fprintln(w.Stderr, msg)
 }
 
 // Infof logs an informational message. It works like fmt.Printf, except that it
-// always has a trailing newline.
+// always has a trailing newline and msg is translated (see Sprintf).
 func Infof(ctx context.Context, msg string, a ...any) {
 	getEnv(ctx).infof(msg, a...)
 }
 
 func (w *Env) warnf(msg string, a ...any) {
-	msg = fmt.Sprintf(msg, a...)
+	msg = w.sprintf(msg, a...)
+	// The prefix is intentionally not translated so that warnings can be
+	// found in logs regardless of the locale.
 	fmt.Fprintf(w.Stderr, "WARNING: %s\n", msg)
 }
 
@@ -42,7 +42,7 @@ func newInvalidYesOrNoInput(got string) error {
 }
 
 func (w *Env) prompt() error {
-	fmt.Fprint(w.Stderr, "Are you sure you would like to continue? [y/N] ")
+	fmt.Fprint(w.Stderr, w.Messages.translate("Are you sure you would like to continue? [y/N] "))
 
 	// TODO: what if it's not a terminal?
 	r, err := bufio.NewReader(w.Stdin).ReadString('\n')
@@ -67,6 +67,6 @@ func (b *BadReader) Read(p []byte) (n int, err error) { //nolint: revive
 func TestConfirmError(t *testing.T) {
 	var stderr bytes.Buffer
 	stdin := BadReader{}
-	ctx := ui.WithEnv(context.Background(), &ui.Env{&stderr, &stdin})
+	ctx := ui.WithEnv(context.Background(), &ui.Env{Stderr: &stderr, Stdin: &stdin})
 	assert.ErrorContains(t, ui.ConfirmContinue(ctx), "my error")
 }
@@ -0,0 +1,168 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/blake3"
+)
+
+// DefaultDigestAlgorithm is the name of the digest algorithm blobs are signed
+// and attested with by default.
+const DefaultDigestAlgorithm = "sha256"
+
+// DigestAlgorithm is a hash function blobs can be signed and attested with.
+//
+// With the default algorithm, blobs are signed as before, by any key and
+// with the transparency log. With any other algorithm, the digest of the blob
+// is signed directly, which only ECDSA keys support, and the transparency log
+// only takes the signature if Tlog is set.
+type DigestAlgorithm struct {
+	// Name is the name of the algorithm on the command line, e.g. sha3-256.
+	Name string
+	// InTotoName is the name of the algorithm in the digests of the
+	// subjects of in-toto statements, e.g. sha3_256.
+	InTotoName string
+	// New returns a hash computing the digests.
+	New func() hash.Hash
+	// Tlog reports whether the transparency log accepts signatures over
+	// digests of the algorithm.
+	Tlog bool
+}
+
+var (
+	digestAlgorithmsMu sync.RWMutex
+	digestAlgorithms   = map[string]DigestAlgorithm{}
+)
+
+func init() {
+	for _, a := range []DigestAlgorithm{
+		{Name: DefaultDigestAlgorithm, InTotoName: "sha256", New: sha256.New, Tlog: true},
+		{Name: "sha3-256", InTotoName: "sha3_256", New: sha3.New256},
+		{Name: "sha3-384", InTotoName: "sha3_384", New: sha3.New384},
+		{Name: "sha3-512", InTotoName: "sha3_512", New: sha3.New512},
+		{Name: "blake3", InTotoName: "blake3", New: blake3.New},
+	} {
+		RegisterDigestAlgorithm(a)
+	}
+}
+
+// RegisterDigestAlgorithm makes a available to sign and attest blobs with,
+// replacing any algorithm of the same name.
+func RegisterDigestAlgorithm(a DigestAlgorithm) {
+	digestAlgorithmsMu.Lock()
+	defer digestAlgorithmsMu.Unlock()
+	digestAlgorithms[a.Name] = a
+}
+
+// LookupDigestAlgorithm returns the registered digest algorithm of the given
+// name, or the default algorithm if name is empty.
+func LookupDigestAlgorithm(name string) (DigestAlgorithm, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultDigestAlgorithm
+	}
+	digestAlgorithmsMu.RLock()
+	defer digestAlgorithmsMu.RUnlock()
+	a, ok := digestAlgorithms[name]
+	if !ok {
+		return DigestAlgorithm{}, fmt.Errorf("unknown blob digest algorithm %q, expected one of %s", name, strings.Join(digestAlgorithmNames(), ", "))
+	}
+	return a, nil
+}
+
+// DigestAlgorithmNames returns the sorted names of the registered digest
+// algorithms.
+func DigestAlgorithmNames() []string {
+	digestAlgorithmsMu.RLock()
+	defer digestAlgorithmsMu.RUnlock()
+	return digestAlgorithmNames()
+}
+
+func digestAlgorithmNames() []string {
+	names := make([]string, 0, len(digestAlgorithms))
+	for name := range digestAlgorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsDefault reports whether a is the default algorithm, which signs blobs
+// rather than their digests.
+func (a DigestAlgorithm) IsDefault() bool {
+	return a.Name == DefaultDigestAlgorithm
+}
+
+// Digest returns the digest of the contents of r.
+func (a DigestAlgorithm) Digest(r io.Reader) ([]byte, error) {
+	h := a.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// CheckKey returns an error unless digests of a can be signed and verified
+// with the public key pub.
+func (a DigestAlgorithm) CheckKey(pub crypto.PublicKey) error {
+	if a.IsDefault() {
+		return nil
+	}
+	if _, ok := pub.(*ecdsa.PublicKey); !ok {
+		return fmt.Errorf("%s blob digests can only be signed with ECDSA keys, not %T", a.Name, pub)
+	}
+	return nil
+}
+
+// SignOptions returns the options that sign digest, a digest of a, with an
+// ECDSA signer.
+func (a DigestAlgorithm) SignOptions(digest []byte) []signature.SignOption {
+	return []signature.SignOption{options.WithDigest(digest), options.WithCryptoSignerOpts(sha2OfSize(len(digest)))}
+}
+
+// VerifyOptions returns the options that verify a signature over digest, a
+// digest of a, with an ECDSA verifier.
+func (a DigestAlgorithm) VerifyOptions(digest []byte) []signature.VerifyOption {
+	return []signature.VerifyOption{options.WithDigest(digest), options.WithCryptoSignerOpts(sha2OfSize(len(digest)))}
+}
+
+// sha2OfSize returns the SHA-2 function with digests of size bytes. ECDSA
+// signs digests as they are, so it only passes the digest length check of
+// signers and verifiers.
+func sha2OfSize(size int) crypto.Hash {
+	switch size {
+	case crypto.SHA384.Size():
+		return crypto.SHA384
+	case crypto.SHA512.Size():
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
@@ -0,0 +1,110 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestLookupDigestAlgorithm(t *testing.T) {
+	a, err := LookupDigestAlgorithm("")
+	if err != nil || !a.IsDefault() || !a.Tlog {
+		t.Fatalf("LookupDigestAlgorithm(\"\") = %+v, %v, want the default algorithm", a, err)
+	}
+	if _, err := LookupDigestAlgorithm("md5"); err == nil || !strings.Contains(err.Error(), "blake3") {
+		t.Errorf("LookupDigestAlgorithm(\"md5\") = %v, want an error listing the algorithms", err)
+	}
+	if got, want := strings.Join(DigestAlgorithmNames(), ","), "blake3,sha256,sha3-256,sha3-384,sha3-512"; got != want {
+		t.Errorf("DigestAlgorithmNames() = %s, want %s", got, want)
+	}
+
+	for _, tc := range []struct {
+		name, inToto, empty string
+	}{
+		{"sha256", "sha256", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"SHA3-256", "sha3_256", "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a"},
+		{"blake3", "blake3", "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+	} {
+		a, err := LookupDigestAlgorithm(tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.InTotoName != tc.inToto {
+			t.Errorf("%s: InTotoName = %s, want %s", tc.name, a.InTotoName, tc.inToto)
+		}
+		digest, err := a.Digest(bytes.NewReader(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(digest); got != tc.empty {
+			t.Errorf("%s: Digest(\"\") = %s, want %s", tc.name, got, tc.empty)
+		}
+	}
+}
+
+func TestDigestAlgorithmSignature(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"sha3-256", "sha3-384", "sha3-512", "blake3"} {
+		a, err := LookupDigestAlgorithm(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.CheckKey(edPub); err == nil {
+			t.Errorf("%s: CheckKey(ed25519) = nil, want an error", name)
+		}
+		if err := a.CheckKey(priv.Public()); err != nil {
+			t.Errorf("%s: CheckKey(ecdsa) = %v", name, err)
+		}
+
+		digest, err := a.Digest(strings.NewReader("release artifact"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := sv.SignMessage(bytes.NewReader(nil), a.SignOptions(digest)...)
+		if err != nil {
+			t.Fatalf("%s: SignMessage() = %v", name, err)
+		}
+		if err := sv.VerifySignature(bytes.NewReader(sig), bytes.NewReader(nil), a.VerifyOptions(digest)...); err != nil {
+			t.Errorf("%s: VerifySignature() = %v", name, err)
+		}
+		other, _ := a.Digest(strings.NewReader("another artifact"))
+		if err := sv.VerifySignature(bytes.NewReader(sig), bytes.NewReader(nil), a.VerifyOptions(other)...); err == nil {
+			t.Errorf("%s: VerifySignature() of another digest = nil, want an error", name)
+		}
+	}
+}
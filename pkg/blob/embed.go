@@ -0,0 +1,467 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"archive/tar"
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// EmbeddedBundleELFSection is the ELF section reserved for the bundle of
+	// an executable, e.g. added with objcopy --add-section.
+	EmbeddedBundleELFSection = ".sigstore"
+	// EmbeddedBundlePESection is the PE section reserved for the bundle of an
+	// executable, .sigstore truncated to the 8 bytes of the names of the
+	// sections of executable images.
+	EmbeddedBundlePESection = ".sigstor"
+	// EmbeddedBundleMachOSegment and EmbeddedBundleMachOSection are the
+	// Mach-O segment and section reserved for the bundle of an executable,
+	// e.g. added with the -sectcreate linker flag.
+	EmbeddedBundleMachOSegment = "__SIGSTORE"
+	EmbeddedBundleMachOSection = "__bundle"
+	// EmbeddedBundleTarMember is the last member of a tar archive with an
+	// embedded bundle, the bundle.
+	EmbeddedBundleTarMember = ".sigstore.bundle.json"
+
+	// embeddedBundleMagic prefixes the comment of a zip archive with an
+	// embedded bundle, and ends the trailer of a bundle appended to a file of
+	// another format, after its little-endian 64-bit length.
+	embeddedBundleMagic = "COSIGN-BUNDLE-V1"
+	trailerSize         = 8 + len(embeddedBundleMagic)
+
+	// gzipSubfieldID identifies the subfield of the extra field of the
+	// header of a gzip file with an embedded bundle, the bundle.
+	gzipSubfieldID = "SG"
+)
+
+// ErrNoEmbeddedBundle is returned by ExtractBundle for files without an
+// embedded bundle.
+var ErrNoEmbeddedBundle = errors.New("no embedded bundle")
+
+// EmbedBundle returns the file with the bundle embedded in it, at the location
+// of its format:
+//
+//   - the reserved section of an ELF, PE or Mach-O executable, which must have
+//     been zero-filled when the executable was signed
+//   - the comment of a zip archive, which must have been empty
+//   - a subfield of the extra field of the header of a gzip file, e.g. a
+//     compressed tar archive
+//   - the last member of a tar archive, before the end of the archive
+//
+// The bundle is appended to files of other formats, and executables without a
+// reserved section. ExtractBundle restores the original contents of the file.
+func EmbedBundle(file, bundle []byte) ([]byte, error) {
+	if len(bundle) == 0 || bundle[len(bundle)-1] == 0 {
+		return nil, errors.New("the bundle must be non-empty and can't end with a NUL byte")
+	}
+	name, off, size, err := reservedSection(file)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case name != "":
+		if int64(len(bundle)) > size {
+			return nil, fmt.Errorf("the bundle has %d bytes, more than the %d bytes of the %s section", len(bundle), size, name)
+		}
+		if len(bytes.TrimRight(file[off:off+size], "\x00")) != 0 {
+			return nil, fmt.Errorf("the %s section of the file already has an embedded bundle", name)
+		}
+		out := append([]byte{}, file...)
+		copy(out[off:], bundle)
+		return out, nil
+	case isZip(file):
+		return embedZip(file, bundle)
+	case isGzip(file):
+		return embedGzip(file, bundle)
+	case isTar(file):
+		return embedTar(file, bundle)
+	}
+	if _, ok := trailer(file); ok {
+		return nil, errors.New("the file already has an embedded bundle")
+	}
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], uint64(len(bundle)))
+	out := make([]byte, 0, len(file)+len(bundle)+trailerSize)
+	out = append(out, file...)
+	out = append(out, bundle...)
+	out = append(out, length[:]...)
+	return append(out, embeddedBundleMagic...), nil
+}
+
+// ExtractBundle returns the contents of the file that were signed, as they
+// were before EmbedBundle, and the bundle embedded in it.
+func ExtractBundle(file []byte) (signed, bundle []byte, err error) {
+	name, off, size, err := reservedSection(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case name != "":
+		bundle = bytes.TrimRight(file[off:off+size], "\x00")
+		if len(bundle) == 0 {
+			return nil, nil, fmt.Errorf("%w in the %s section", ErrNoEmbeddedBundle, name)
+		}
+		bundle = append([]byte{}, bundle...)
+		signed = append([]byte{}, file...)
+		copy(signed[off:off+size], make([]byte, size))
+		return signed, bundle, nil
+	case isZip(file):
+		return extractZip(file)
+	case isGzip(file):
+		return extractGzip(file)
+	case isTar(file):
+		return extractTar(file)
+	}
+	start, ok := trailer(file)
+	if !ok {
+		return nil, nil, ErrNoEmbeddedBundle
+	}
+	return file[:start], file[start : len(file)-trailerSize], nil
+}
+
+// reservedSection returns the name, offset and size of the section reserved
+// for the bundle of the executable, or an empty name if it has none.
+func reservedSection(executable []byte) (string, int64, int64, error) {
+	var name string
+	var off, size uint64
+	if f, err := elf.NewFile(bytes.NewReader(executable)); err == nil {
+		s := f.Section(EmbeddedBundleELFSection)
+		if s == nil {
+			return "", 0, 0, nil
+		}
+		if s.Type == elf.SHT_NOBITS {
+			return "", 0, 0, fmt.Errorf("the %s section of the executable has no contents", s.Name)
+		}
+		name, off, size = s.Name, s.Offset, s.Size
+	} else if f, err := macho.NewFile(bytes.NewReader(executable)); err == nil {
+		var s *macho.Section
+		for _, sec := range f.Sections {
+			if sec.Seg == EmbeddedBundleMachOSegment && sec.Name == EmbeddedBundleMachOSection {
+				s = sec
+				break
+			}
+		}
+		if s == nil {
+			return "", 0, 0, nil
+		}
+		if s.Offset == 0 {
+			return "", 0, 0, fmt.Errorf("the %s,%s section of the executable has no contents", s.Seg, s.Name)
+		}
+		name, off, size = s.Seg+","+s.Name, uint64(s.Offset), s.Size
+	} else if f, err := pe.NewFile(bytes.NewReader(executable)); err == nil {
+		s := f.Section(EmbeddedBundlePESection)
+		if s == nil {
+			return "", 0, 0, nil
+		}
+		if s.Offset == 0 {
+			return "", 0, 0, fmt.Errorf("the %s section of the executable has no contents", s.Name)
+		}
+		name, off, size = s.Name, uint64(s.Offset), uint64(s.Size)
+	} else {
+		return "", 0, 0, nil
+	}
+	if size == 0 || off > uint64(len(executable)) || size > uint64(len(executable))-off {
+		return "", 0, 0, fmt.Errorf("the %s section of the executable is outside of it", name)
+	}
+	return name, int64(off), int64(size), nil
+}
+
+// trailer returns the offset of the bundle appended to the file, if any.
+func trailer(file []byte) (int, bool) {
+	if len(file) < trailerSize || string(file[len(file)-len(embeddedBundleMagic):]) != embeddedBundleMagic {
+		return 0, false
+	}
+	length := binary.LittleEndian.Uint64(file[len(file)-trailerSize:])
+	if length == 0 || length > uint64(len(file)-trailerSize) {
+		return 0, false
+	}
+	return len(file) - trailerSize - int(length), true
+}
+
+// zipEOCDSize is the size of the end of central directory record of a zip
+// archive, without its comment.
+const zipEOCDSize = 22
+
+func isZip(file []byte) bool {
+	_, ok := zipEOCD(file)
+	return ok && bytes.HasPrefix(file, []byte("PK\x03\x04"))
+}
+
+// zipEOCD returns the offset of the end of central directory record of the
+// zip archive, whose comment ends the archive.
+func zipEOCD(file []byte) (int, bool) {
+	for i := len(file) - zipEOCDSize; i >= 0 && i >= len(file)-zipEOCDSize-0xffff; i-- {
+		if string(file[i:i+4]) == "PK\x05\x06" && i+zipEOCDSize+int(binary.LittleEndian.Uint16(file[i+20:])) == len(file) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func embedZip(file, bundle []byte) ([]byte, error) {
+	eocd, _ := zipEOCD(file)
+	if len(file) != eocd+zipEOCDSize {
+		if bytes.HasPrefix(file[eocd+zipEOCDSize:], []byte(embeddedBundleMagic)) {
+			return nil, errors.New("the zip archive already has an embedded bundle")
+		}
+		return nil, errors.New("the zip archive has a comment, the bundle is embedded as its comment")
+	}
+	comment := len(embeddedBundleMagic) + len(bundle)
+	if comment > 0xffff {
+		return nil, fmt.Errorf("the bundle has %d bytes, more than the %d bytes of a zip comment", len(bundle), 0xffff-len(embeddedBundleMagic))
+	}
+	out := make([]byte, 0, len(file)+comment)
+	out = append(out, file...)
+	binary.LittleEndian.PutUint16(out[eocd+20:], uint16(comment))
+	out = append(out, embeddedBundleMagic...)
+	return append(out, bundle...), nil
+}
+
+func extractZip(file []byte) ([]byte, []byte, error) {
+	eocd, _ := zipEOCD(file)
+	comment := file[eocd+zipEOCDSize:]
+	if !bytes.HasPrefix(comment, []byte(embeddedBundleMagic)) || len(comment) == len(embeddedBundleMagic) {
+		return nil, nil, fmt.Errorf("%w in the comment of the zip archive", ErrNoEmbeddedBundle)
+	}
+	signed := append([]byte{}, file[:eocd+zipEOCDSize]...)
+	binary.LittleEndian.PutUint16(signed[eocd+20:], 0)
+	return signed, comment[len(embeddedBundleMagic):], nil
+}
+
+// The flags of the header of a gzip file.
+const (
+	gzipFlagHeaderCRC = 1 << 1
+	gzipFlagExtra     = 1 << 2
+)
+
+func isGzip(file []byte) bool {
+	return len(file) >= 10 && file[0] == 0x1f && file[1] == 0x8b && file[2] == 8
+}
+
+// gzipExtra returns the extra field of the header of the gzip file, if its
+// header has one.
+func gzipExtra(file []byte) ([]byte, bool, error) {
+	if file[3]&gzipFlagHeaderCRC != 0 {
+		return nil, false, errors.New("gzip files with a header CRC aren't supported")
+	}
+	if file[3]&gzipFlagExtra == 0 {
+		return nil, false, nil
+	}
+	if len(file) < 12 || len(file) < 12+int(binary.LittleEndian.Uint16(file[10:])) {
+		return nil, false, errors.New("the extra field of the gzip header is truncated")
+	}
+	return file[12 : 12+int(binary.LittleEndian.Uint16(file[10:]))], true, nil
+}
+
+// gzipSubfields returns the offsets of the subfields of the extra field of a
+// gzip header.
+func gzipSubfields(extra []byte) ([]int, error) {
+	var offsets []int
+	for i := 0; i < len(extra); {
+		if len(extra)-i < 4 || len(extra)-i-4 < int(binary.LittleEndian.Uint16(extra[i+2:])) {
+			return nil, errors.New("the extra field of the gzip header is malformed")
+		}
+		offsets = append(offsets, i)
+		i += 4 + int(binary.LittleEndian.Uint16(extra[i+2:]))
+	}
+	return offsets, nil
+}
+
+func embedGzip(file, bundle []byte) ([]byte, error) {
+	extra, ok, err := gzipExtra(file)
+	if err != nil {
+		return nil, err
+	}
+	if ok && len(extra) == 0 {
+		return nil, errors.New("the gzip header has an empty extra field")
+	}
+	offsets, err := gzipSubfields(extra)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range offsets {
+		if string(extra[i:i+2]) == gzipSubfieldID {
+			return nil, errors.New("the gzip file already has an embedded bundle")
+		}
+	}
+	xlen := len(extra) + 4 + len(bundle)
+	if xlen > 0xffff {
+		return nil, fmt.Errorf("the bundle has %d bytes, more than the %d bytes left in the extra field of the gzip header", len(bundle), 0xffff-len(extra)-4)
+	}
+	out := make([]byte, 0, len(file)+len(bundle)+6)
+	out = append(out, file[:10]...)
+	out[3] |= gzipFlagExtra
+	out = binary.LittleEndian.AppendUint16(out, uint16(xlen))
+	out = append(out, extra...)
+	out = append(out, gzipSubfieldID...)
+	out = binary.LittleEndian.AppendUint16(out, uint16(len(bundle)))
+	out = append(out, bundle...)
+	rest := file[10:]
+	if ok {
+		rest = file[12+len(extra):]
+	}
+	return append(out, rest...), nil
+}
+
+func extractGzip(file []byte) ([]byte, []byte, error) {
+	extra, ok, err := gzipExtra(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	offsets, err := gzipSubfields(extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The bundle is the last subfield.
+	if !ok || len(offsets) == 0 || string(extra[offsets[len(offsets)-1]:][:2]) != gzipSubfieldID {
+		return nil, nil, fmt.Errorf("%w in the gzip header", ErrNoEmbeddedBundle)
+	}
+	last := offsets[len(offsets)-1]
+	bundle := extra[last+4:]
+	signed := make([]byte, 0, len(file))
+	signed = append(signed, file[:10]...)
+	if last == 0 {
+		signed[3] &^= gzipFlagExtra
+	} else {
+		signed = binary.LittleEndian.AppendUint16(signed, uint16(last))
+		signed = append(signed, extra[:last]...)
+	}
+	return append(signed, file[12+len(extra):]...), bundle, nil
+}
+
+// tarBlockSize is the size of the blocks of a tar archive.
+const tarBlockSize = 512
+
+func isTar(file []byte) bool {
+	return len(file) >= tarBlockSize && bytes.HasPrefix(file[257:], []byte("ustar"))
+}
+
+// tarMembers returns the offsets of the headers of the members of the tar
+// archive, and the offset of its end.
+func tarMembers(file []byte) ([]int, int, error) {
+	var offsets []int
+	for off := 0; ; {
+		if len(file)-off < tarBlockSize {
+			return nil, 0, errors.New("the tar archive is truncated")
+		}
+		header := file[off : off+tarBlockSize]
+		if len(bytes.Trim(header, "\x00")) == 0 {
+			return offsets, off, nil
+		}
+		size, err := tarSize(header[124:136])
+		if err != nil {
+			return nil, 0, err
+		}
+		offsets = append(offsets, off)
+		blocks := (size + tarBlockSize - 1) / tarBlockSize
+		if blocks > int64(len(file)-off)/tarBlockSize {
+			return nil, 0, errors.New("the tar archive is truncated")
+		}
+		off += tarBlockSize + int(blocks)*tarBlockSize
+	}
+}
+
+// tarSize parses the size field of a tar header, octal or base-256.
+func tarSize(field []byte) (int64, error) {
+	if field[0]&0x80 != 0 {
+		var size int64
+		for i, b := range field {
+			if i == 0 {
+				b &^= 0x80
+			}
+			if size > (1<<55)-1 {
+				return 0, errors.New("the size of a tar member is too large")
+			}
+			size = size<<8 | int64(b)
+		}
+		return size, nil
+	}
+	s := string(bytes.Trim(field, " \x00"))
+	if s == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(s, 8, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("the size of a tar member is invalid: %q", field)
+	}
+	return size, nil
+}
+
+func embedTar(file, bundle []byte) ([]byte, error) {
+	offsets, end, err := tarMembers(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(offsets) > 0 && tarName(file[offsets[len(offsets)-1]:]) == EmbeddedBundleTarMember {
+		return nil, errors.New("the tar archive already has an embedded bundle")
+	}
+	var member bytes.Buffer
+	w := tar.NewWriter(&member)
+	if err := w.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     EmbeddedBundleTarMember,
+		Mode:     0o644,
+		Size:     int64(len(bundle)),
+		ModTime:  time.Unix(0, 0),
+		Format:   tar.FormatUSTAR,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(bundle); err != nil {
+		return nil, err
+	}
+	// Flush pads the member, without writing the end of the archive.
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	// The padding after the end of the archive is kept, so that the archive
+	// is restored as it was signed.
+	out := make([]byte, 0, len(file)+member.Len())
+	out = append(out, file[:end]...)
+	out = append(out, member.Bytes()...)
+	return append(out, file[end:]...), nil
+}
+
+func extractTar(file []byte) ([]byte, []byte, error) {
+	offsets, end, err := tarMembers(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(offsets) == 0 || tarName(file[offsets[len(offsets)-1]:]) != EmbeddedBundleTarMember {
+		return nil, nil, fmt.Errorf("%w in the last member of the tar archive", ErrNoEmbeddedBundle)
+	}
+	last := offsets[len(offsets)-1]
+	size, _ := tarSize(file[last+124 : last+136])
+	bundle := file[last+tarBlockSize : last+tarBlockSize+int(size)]
+	signed := make([]byte, 0, len(file)-(end-last))
+	signed = append(signed, file[:last]...)
+	return append(signed, file[end:]...), bundle, nil
+}
+
+// tarName returns the name in the tar header, without its USTAR prefix.
+func tarName(header []byte) string {
+	name, _, _ := bytes.Cut(header[:100], []byte{0})
+	return string(name)
+}
@@ -0,0 +1,193 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// elfWithSection returns a minimal ELF file with the section name of size
+// zero bytes.
+func elfWithSection(t *testing.T, name string, size int) []byte {
+	t.Helper()
+	shstrtab := "\x00" + name + "\x00.shstrtab\x00"
+	dataOff := 64
+	strOff := dataOff + size
+	shOff := strOff + len(shstrtab)
+	var b bytes.Buffer
+	hdr := elf.Header64{
+		Type:      uint16(elf.ET_EXEC),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Shoff:     uint64(shOff),
+		Ehsize:    64,
+		Shentsize: 64,
+		Shnum:     3,
+		Shstrndx:  2,
+	}
+	copy(hdr.Ident[:], elf.ELFMAG)
+	hdr.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	hdr.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	hdr.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	sections := []elf.Section64{
+		{},
+		{Name: 1, Type: uint32(elf.SHT_PROGBITS), Off: uint64(dataOff), Size: uint64(size), Addralign: 1},
+		{Name: uint32(len(name) + 2), Type: uint32(elf.SHT_STRTAB), Off: uint64(strOff), Size: uint64(len(shstrtab)), Addralign: 1},
+	}
+	for _, v := range []interface{}{hdr, make([]byte, size), []byte(shstrtab), sections} {
+		if err := binary.Write(&b, binary.LittleEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b.Bytes()
+}
+
+func TestEmbedBundle(t *testing.T) {
+	bundle := []byte(`{"base64Signature":"c2ln"}`)
+	tarball := tarArchive(t)
+	for name, executable := range map[string][]byte{
+		"reserved section":   elfWithSection(t, EmbeddedBundleELFSection, 256),
+		"other section":      elfWithSection(t, ".data", 256),
+		"not an ELF file":    []byte("#!/bin/sh\necho hello\n"),
+		"zip archive":        zipArchive(t),
+		"tar archive":        tarball,
+		"gzip file":          gzipFile(t, tarball, nil),
+		"gzip file extra":    gzipFile(t, tarball, []byte("AP\x02\x00hi")),
+		"padded tar archive": append(append([]byte{}, tarball...), make([]byte, 8192)...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			embedded, err := EmbedBundle(executable, bundle)
+			if err != nil {
+				t.Fatalf("EmbedBundle() = %v", err)
+			}
+			if reserved := name == "reserved section"; reserved != (len(embedded) == len(executable)) {
+				t.Errorf("EmbedBundle() returned %d bytes, of an executable of %d", len(embedded), len(executable))
+			}
+			checkReadable(t, name, embedded)
+			signed, got, err := ExtractBundle(embedded)
+			if err != nil {
+				t.Fatalf("ExtractBundle() = %v", err)
+			}
+			if !bytes.Equal(signed, executable) || !bytes.Equal(got, bundle) {
+				t.Errorf("ExtractBundle() = %q, %q, want the executable and the bundle", signed, got)
+			}
+			if _, err := EmbedBundle(embedded, bundle); err == nil || !strings.Contains(err.Error(), "already has an embedded bundle") {
+				t.Errorf("EmbedBundle() of the embedded executable = %v, want an error", err)
+			}
+			if _, _, err := ExtractBundle(executable); !errors.Is(err, ErrNoEmbeddedBundle) {
+				t.Errorf("ExtractBundle() of the executable = %v, want ErrNoEmbeddedBundle", err)
+			}
+		})
+	}
+
+	if _, err := EmbedBundle(elfWithSection(t, EmbeddedBundleELFSection, 8), bundle); err == nil || !strings.Contains(err.Error(), "more than the 8 bytes") {
+		t.Errorf("EmbedBundle() into a small section = %v, want an error", err)
+	}
+}
+
+func zipArchive(t *testing.T) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	f, err := w.Create("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func tarArchive(t *testing.T) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := tar.NewWriter(&b)
+	if err := w.WriteHeader(&tar.Header{Name: "app", Mode: 0o755, Size: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func gzipFile(t *testing.T, contents, extra []byte) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	w.Extra = extra
+	if _, err := w.Write(contents); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// checkReadable checks that the archive with an embedded bundle is still
+// read as it was.
+func checkReadable(t *testing.T, name string, embedded []byte) {
+	t.Helper()
+	switch {
+	case strings.HasPrefix(name, "zip"):
+		r, err := zip.NewReader(bytes.NewReader(embedded), int64(len(embedded)))
+		if err != nil || len(r.File) != 1 || r.File[0].Name != "app" {
+			t.Errorf("reading the zip archive: %v", err)
+		}
+	case strings.HasPrefix(name, "gzip"):
+		r, err := gzip.NewReader(bytes.NewReader(embedded))
+		if err != nil {
+			t.Fatalf("reading the gzip file: %v", err)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(b, tarArchive(t)) {
+			t.Errorf("reading the gzip file: %v", err)
+		}
+	case strings.Contains(name, "tar"):
+		r := tar.NewReader(bytes.NewReader(embedded))
+		var names []string
+		for {
+			h, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("reading the tar archive: %v", err)
+			}
+			names = append(names, h.Name)
+		}
+		if strings.Join(names, ",") != "app,"+EmbeddedBundleTarMember {
+			t.Errorf("tar archive members = %v, want the bundle last", names)
+		}
+	}
+}
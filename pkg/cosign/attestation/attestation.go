@@ -26,6 +26,7 @@ import (
 	slsa "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
 
 	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 )
 
 const (
@@ -34,6 +35,12 @@ const (
 
 	// CosignVulnProvenanceV01 specifies the type of VulnerabilityScan Predicate
 	CosignVulnProvenanceV01 = "https://cosign.sigstore.dev/attestation/vuln/v1"
+
+	// CosignBaseImageV01 specifies the type of the BaseImage Predicate
+	CosignBaseImageV01 = "https://cosign.sigstore.dev/attestation/base-image/v1"
+
+	// CosignBuildMetadataV01 specifies the type of the BuildMetadata Predicate
+	CosignBuildMetadataV01 = "https://cosign.sigstore.dev/attestation/build-metadata/v1"
 )
 
 // CosignPredicate specifies the format of the Custom Predicate.
@@ -42,6 +49,43 @@ type CosignPredicate struct {
 	Timestamp string
 }
 
+// BaseImagePredicate specifies the format of the Base Image Predicate. Image
+// is the reference, by digest, of the image the attested image was built on.
+type BaseImagePredicate struct {
+	Image string `json:"image"`
+}
+
+// BuildMetadataPredicate specifies the format of the Build Metadata
+// Predicate, the minimal provenance recorded in the config and history of an
+// image by the tool that built it.
+type BuildMetadataPredicate struct {
+	// BaseImage is the image the attested image was built on, if it is
+	// annotated or labeled with it.
+	BaseImage    *BuildMetadataBaseImage `json:"baseImage,omitempty"`
+	Created      string                  `json:"created,omitempty"`
+	Architecture string                  `json:"architecture,omitempty"`
+	OS           string                  `json:"os,omitempty"`
+	Labels       map[string]string       `json:"labels,omitempty"`
+	// Layers are the steps of the history of the image, with the digest of
+	// the layer that each step created, if any.
+	Layers []BuildMetadataLayer `json:"layers"`
+}
+
+// BuildMetadataBaseImage is the base image of a BuildMetadataPredicate.
+type BuildMetadataBaseImage struct {
+	Name   string `json:"name,omitempty"`
+	Digest string `json:"digest"`
+}
+
+// BuildMetadataLayer is a step of the history of the image of a
+// BuildMetadataPredicate. Digest is empty for steps that created no layer.
+type BuildMetadataLayer struct {
+	Digest  string `json:"digest,omitempty"`
+	Command string `json:"command,omitempty"`
+	Created string `json:"created,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
 // VulnPredicate specifies the format of the Vulnerability Scan Predicate
 type CosignVulnPredicate struct {
 	Invocation Invocation `json:"invocation"`
@@ -89,8 +133,11 @@ type GenerateOpts struct {
 	// Type is the pre-defined enums (provenance|link|spdx).
 	// default: custom
 	Type string
-	// Digest of the Image reference.
+	// Digest of the Image reference, as a hex string.
 	Digest string
+	// DigestAlgorithm names the algorithm of Digest, e.g. sha512.
+	// default: sha256
+	DigestAlgorithm string
 	// Repo context of the reference.
 	Repo string
 
@@ -99,34 +146,47 @@ type GenerateOpts struct {
 }
 
 // GenerateStatement returns an in-toto statement based on the provided
-// predicate type (custom|slsaprovenance|spdx|spdxjson|cyclonedx|link).
+// predicate type (custom|slsaprovenance|spdx|spdxjson|cyclonedx|link|vuln|baseimage|buildmetadata).
 func GenerateStatement(opts GenerateOpts) (interface{}, error) {
 	predicate, err := io.ReadAll(opts.Predicate)
 	if err != nil {
 		return nil, err
 	}
 
+	alg := opts.DigestAlgorithm
+	if alg == "" {
+		alg = "sha256"
+	}
+	subject := in_toto.Subject{
+		Name:   opts.Repo,
+		Digest: map[string]string{alg: opts.Digest},
+	}
+
 	switch opts.Type {
 	case "slsaprovenance":
-		return generateSLSAProvenanceStatement(predicate, opts.Digest, opts.Repo)
+		return generateSLSAProvenanceStatement(predicate, subject)
 	case "spdx":
-		return generateSPDXStatement(predicate, opts.Digest, opts.Repo, false)
+		return generateSPDXStatement(predicate, subject, false)
 	case "spdxjson":
-		return generateSPDXStatement(predicate, opts.Digest, opts.Repo, true)
+		return generateSPDXStatement(predicate, subject, true)
 	case "cyclonedx":
-		return generateCycloneDXStatement(predicate, opts.Digest, opts.Repo)
+		return generateCycloneDXStatement(predicate, subject)
 	case "link":
-		return generateLinkStatement(predicate, opts.Digest, opts.Repo)
+		return generateLinkStatement(predicate, subject)
 	case "vuln":
-		return generateVulnStatement(predicate, opts.Digest, opts.Repo)
+		return generateVulnStatement(predicate, subject)
+	case "baseimage":
+		return generateBaseImageStatement(predicate, subject)
+	case "buildmetadata":
+		return generateBuildMetadataStatement(predicate, subject)
 	default:
 		stamp := timestamp(opts)
 		predicateType := customType(opts)
-		return generateCustomStatement(predicate, predicateType, opts.Digest, opts.Repo, stamp)
+		return generateCustomStatement(predicate, predicateType, subject, stamp)
 	}
 }
 
-func generateVulnStatement(predicate []byte, digest string, repo string) (interface{}, error) {
+func generateVulnStatement(predicate []byte, subject in_toto.Subject) (interface{}, error) {
 	var vuln CosignVulnPredicate
 
 	err := json.Unmarshal(predicate, &vuln)
@@ -135,11 +195,43 @@ func generateVulnStatement(predicate []byte, digest string, repo string) (interf
 	}
 
 	return in_toto.Statement{
-		StatementHeader: generateStatementHeader(digest, repo, CosignVulnProvenanceV01),
+		StatementHeader: generateStatementHeader(subject, CosignVulnProvenanceV01),
 		Predicate:       vuln,
 	}, nil
 }
 
+func generateBaseImageStatement(predicate []byte, subject in_toto.Subject) (interface{}, error) {
+	var base BaseImagePredicate
+	if err := json.Unmarshal(predicate, &base); err != nil {
+		return nil, err
+	}
+	if _, err := oci.ParseDigestReference(base.Image); err != nil {
+		return nil, fmt.Errorf("base image predicate: image must be a reference by digest: %w", err)
+	}
+
+	return in_toto.Statement{
+		StatementHeader: generateStatementHeader(subject, CosignBaseImageV01),
+		Predicate:       base,
+	}, nil
+}
+
+func generateBuildMetadataStatement(predicate []byte, subject in_toto.Subject) (interface{}, error) {
+	var metadata BuildMetadataPredicate
+	if err := json.Unmarshal(predicate, &metadata); err != nil {
+		return nil, err
+	}
+	if metadata.BaseImage != nil {
+		if _, err := oci.ParseDigest(metadata.BaseImage.Digest); err != nil {
+			return nil, fmt.Errorf("build metadata predicate: base image digest: %w", err)
+		}
+	}
+
+	return in_toto.Statement{
+		StatementHeader: generateStatementHeader(subject, CosignBuildMetadataV01),
+		Predicate:       metadata,
+	}, nil
+}
+
 func timestamp(opts GenerateOpts) string {
 	if opts.Time == nil {
 		opts.Time = time.Now
@@ -155,29 +247,22 @@ func customType(opts GenerateOpts) string {
 	return CosignCustomProvenanceV01
 }
 
-func generateStatementHeader(digest, repo, predicateType string) in_toto.StatementHeader {
+func generateStatementHeader(subject in_toto.Subject, predicateType string) in_toto.StatementHeader {
 	return in_toto.StatementHeader{
 		Type:          in_toto.StatementInTotoV01,
 		PredicateType: predicateType,
-		Subject: []in_toto.Subject{
-			{
-				Name: repo,
-				Digest: map[string]string{
-					"sha256": digest,
-				},
-			},
-		},
+		Subject:       []in_toto.Subject{subject},
 	}
 }
 
-func generateCustomStatement(rawPayload []byte, customType, digest, repo, timestamp string) (interface{}, error) {
+func generateCustomStatement(rawPayload []byte, customType string, subject in_toto.Subject, timestamp string) (interface{}, error) {
 	payload, err := generateCustomPredicate(rawPayload, customType, timestamp)
 	if err != nil {
 		return nil, err
 	}
 
 	return in_toto.Statement{
-		StatementHeader: generateStatementHeader(digest, repo, customType),
+		StatementHeader: generateStatementHeader(subject, customType),
 		Predicate:       payload,
 	}, nil
 }
@@ -198,7 +283,7 @@ func generateCustomPredicate(rawPayload []byte, customType, timestamp string) (i
 	return result, nil
 }
 
-func generateSLSAProvenanceStatement(rawPayload []byte, digest string, repo string) (interface{}, error) {
+func generateSLSAProvenanceStatement(rawPayload []byte, subject in_toto.Subject) (interface{}, error) {
 	var predicate slsa.ProvenancePredicate
 	err := checkRequiredJSONFields(rawPayload, reflect.TypeOf(predicate))
 	if err != nil {
@@ -209,12 +294,12 @@ func generateSLSAProvenanceStatement(rawPayload []byte, digest string, repo stri
 		return "", fmt.Errorf("unmarshal Provenance predicate: %w", err)
 	}
 	return in_toto.ProvenanceStatement{
-		StatementHeader: generateStatementHeader(digest, repo, slsa.PredicateSLSAProvenance),
+		StatementHeader: generateStatementHeader(subject, slsa.PredicateSLSAProvenance),
 		Predicate:       predicate,
 	}, nil
 }
 
-func generateLinkStatement(rawPayload []byte, digest string, repo string) (interface{}, error) {
+func generateLinkStatement(rawPayload []byte, subject in_toto.Subject) (interface{}, error) {
 	var link in_toto.Link
 	err := checkRequiredJSONFields(rawPayload, reflect.TypeOf(link))
 	if err != nil {
@@ -225,12 +310,12 @@ func generateLinkStatement(rawPayload []byte, digest string, repo string) (inter
 		return "", fmt.Errorf("unmarshal Link statement: %w", err)
 	}
 	return in_toto.LinkStatement{
-		StatementHeader: generateStatementHeader(digest, repo, in_toto.PredicateLinkV1),
+		StatementHeader: generateStatementHeader(subject, in_toto.PredicateLinkV1),
 		Predicate:       link,
 	}, nil
 }
 
-func generateSPDXStatement(rawPayload []byte, digest string, repo string, parseJSON bool) (interface{}, error) {
+func generateSPDXStatement(rawPayload []byte, subject in_toto.Subject, parseJSON bool) (interface{}, error) {
 	var data interface{}
 	if parseJSON {
 		if err := json.Unmarshal(rawPayload, &data); err != nil {
@@ -240,18 +325,18 @@ func generateSPDXStatement(rawPayload []byte, digest string, repo string, parseJ
 		data = string(rawPayload)
 	}
 	return in_toto.SPDXStatement{
-		StatementHeader: generateStatementHeader(digest, repo, in_toto.PredicateSPDX),
+		StatementHeader: generateStatementHeader(subject, in_toto.PredicateSPDX),
 		Predicate:       data,
 	}, nil
 }
 
-func generateCycloneDXStatement(rawPayload []byte, digest string, repo string) (interface{}, error) {
+func generateCycloneDXStatement(rawPayload []byte, subject in_toto.Subject) (interface{}, error) {
 	var data interface{}
 	if err := json.Unmarshal(rawPayload, &data); err != nil {
 		return nil, err
 	}
 	return in_toto.SPDXStatement{
-		StatementHeader: generateStatementHeader(digest, repo, in_toto.PredicateCycloneDX),
+		StatementHeader: generateStatementHeader(subject, in_toto.PredicateCycloneDX),
 		Predicate:       data,
 	}, nil
 }
@@ -0,0 +1,155 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	cuejson "cuelang.org/go/encoding/json"
+	"cuelang.org/go/encoding/jsonschema"
+)
+
+// SchemaRegistry is the format of a predicate schema registry file. It maps
+// custom predicate types to the location of their JSON schemas, e.g.
+//
+//	{"predicateTypes": {"https://example.com/build/v1": "schemas/build.json"}}
+type SchemaRegistry struct {
+	PredicateTypes map[string]string `json:"predicateTypes"`
+}
+
+// PredicateSchemas are the JSON schemas the predicates of registered
+// predicate types must conform to. A nil *PredicateSchemas has no schemas.
+type PredicateSchemas struct {
+	schemas map[string]cue.Value
+}
+
+// NewPredicateSchemas compiles the JSON schema of each predicate type in
+// schemas.
+func NewPredicateSchemas(schemas map[string][]byte) (*PredicateSchemas, error) {
+	ctx := cuecontext.New()
+	s := &PredicateSchemas{schemas: map[string]cue.Value{}}
+	for predicateType, schema := range schemas {
+		v, err := compileJSONSchema(ctx, schema)
+		if err != nil {
+			return nil, fmt.Errorf("compiling the schema of %s: %w", predicateType, err)
+		}
+		s.schemas[predicateType] = v
+	}
+	return s, nil
+}
+
+// Add returns a copy of s with the JSON schema of predicateType, replacing
+// any schema registered for it.
+func (s *PredicateSchemas) Add(predicateType string, schema []byte) (*PredicateSchemas, error) {
+	ctx := cuecontext.New()
+	if s != nil {
+		for _, v := range s.schemas {
+			ctx = v.Context()
+			break
+		}
+	}
+	v, err := compileJSONSchema(ctx, schema)
+	if err != nil {
+		return nil, fmt.Errorf("compiling the schema of %s: %w", predicateType, err)
+	}
+	added := &PredicateSchemas{schemas: map[string]cue.Value{predicateType: v}}
+	if s != nil {
+		for t, v := range s.schemas {
+			if t != predicateType {
+				added.schemas[t] = v
+			}
+		}
+	}
+	return added, nil
+}
+
+// compileJSONSchema converts a JSON schema to a CUE definition, so that
+// objects without additional properties are closed.
+func compileJSONSchema(ctx *cue.Context, schema []byte) (cue.Value, error) {
+	expr, err := cuejson.Extract("schema.json", schema)
+	if err != nil {
+		return cue.Value{}, err
+	}
+	f, err := jsonschema.Extract(ctx.BuildExpr(expr), &jsonschema.Config{})
+	if err != nil {
+		return cue.Value{}, err
+	}
+
+	def := &ast.StructLit{}
+	wrapped := &ast.File{}
+	for _, d := range f.Decls {
+		switch d.(type) {
+		case *ast.Package, *ast.ImportDecl, *ast.Attribute:
+			wrapped.Decls = append(wrapped.Decls, d)
+		default:
+			def.Elts = append(def.Elts, d)
+		}
+	}
+	wrapped.Decls = append(wrapped.Decls, &ast.Field{Label: ast.NewIdent("#Predicate"), Value: def})
+
+	v := ctx.BuildFile(wrapped).LookupPath(cue.ParsePath("#Predicate"))
+	return v, v.Err()
+}
+
+// Has reports whether a schema is registered for predicateType.
+func (s *PredicateSchemas) Has(predicateType string) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.schemas[predicateType]
+	return ok
+}
+
+// Validate returns an error if predicate doesn't conform to the schema
+// registered for predicateType. Predicates of other types are valid.
+func (s *PredicateSchemas) Validate(predicateType string, predicate []byte) error {
+	if !s.Has(predicateType) {
+		return nil
+	}
+	schema := s.schemas[predicateType]
+	expr, err := cuejson.Extract("predicate.json", predicate)
+	if err != nil {
+		return fmt.Errorf("parsing %s predicate: %w", predicateType, err)
+	}
+	v := schema.Unify(schema.Context().BuildExpr(expr))
+	if err := v.Validate(cue.Concrete(true)); err != nil {
+		return fmt.Errorf("%s predicate does not match its schema: %w", predicateType, err)
+	}
+	return nil
+}
+
+// ValidateStatement validates the predicate of an in-toto statement against
+// the schema registered for its predicate type.
+func (s *PredicateSchemas) ValidateStatement(statement []byte) error {
+	if s == nil {
+		return nil
+	}
+	st := struct {
+		PredicateType string          `json:"predicateType"`
+		Predicate     json.RawMessage `json:"predicate"`
+	}{}
+	if err := json.Unmarshal(statement, &st); err != nil {
+		return fmt.Errorf("parsing statement: %w", err)
+	}
+	if st.Predicate == nil {
+		st.Predicate = json.RawMessage("null")
+	}
+	return s.Validate(st.PredicateType, st.Predicate)
+}
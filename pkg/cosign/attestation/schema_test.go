@@ -0,0 +1,108 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"strings"
+	"testing"
+)
+
+const buildSchema = `{
+  "type": "object",
+  "properties": {
+    "builder": {"type": "string"},
+    "steps": {"type": "integer", "minimum": 1}
+  },
+  "required": ["builder"],
+  "additionalProperties": false
+}`
+
+func TestPredicateSchemasValidate(t *testing.T) {
+	schemas, err := NewPredicateSchemas(map[string][]byte{"https://example.com/build/v1": []byte(buildSchema)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		predicateType string
+		predicate     string
+		wantErr       string
+	}{{
+		name:          "valid",
+		predicateType: "https://example.com/build/v1",
+		predicate:     `{"builder": "ci", "steps": 3}`,
+	}, {
+		name:          "missing required property",
+		predicateType: "https://example.com/build/v1",
+		predicate:     `{"steps": 3}`,
+		wantErr:       "does not match its schema",
+	}, {
+		name:          "additional property",
+		predicateType: "https://example.com/build/v1",
+		predicate:     `{"builder": "ci", "extra": true}`,
+		wantErr:       "does not match its schema",
+	}, {
+		name:          "wrong type",
+		predicateType: "https://example.com/build/v1",
+		predicate:     `{"builder": "ci", "steps": 0}`,
+		wantErr:       "does not match its schema",
+	}, {
+		name:          "unregistered predicate type",
+		predicateType: "https://example.com/other/v1",
+		predicate:     `{"anything": "goes"}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schemas.Validate(tt.predicateType, []byte(tt.predicate))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPredicateSchemasValidateStatement(t *testing.T) {
+	schemas, err := NewPredicateSchemas(map[string][]byte{"https://example.com/build/v1": []byte(buildSchema)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := `{"_type": "https://in-toto.io/Statement/v0.1", "predicateType": "https://example.com/build/v1", "predicate": {"builder": "ci"}}`
+	if err := schemas.ValidateStatement([]byte(valid)); err != nil {
+		t.Errorf("ValidateStatement() unexpected error: %v", err)
+	}
+	invalid := `{"_type": "https://in-toto.io/Statement/v0.1", "predicateType": "https://example.com/build/v1", "predicate": {"Data": "ci"}}`
+	if err := schemas.ValidateStatement([]byte(invalid)); err == nil {
+		t.Error("ValidateStatement() succeeded for a predicate that doesn't match its schema")
+	}
+
+	var none *PredicateSchemas
+	if err := none.ValidateStatement([]byte(invalid)); err != nil {
+		t.Errorf("nil PredicateSchemas ValidateStatement() unexpected error: %v", err)
+	}
+}
+
+func TestNewPredicateSchemasInvalid(t *testing.T) {
+	if _, err := NewPredicateSchemas(map[string][]byte{"https://example.com/build/v1": []byte(`{"type": `)}); err == nil {
+		t.Error("NewPredicateSchemas() succeeded for an invalid schema")
+	}
+}
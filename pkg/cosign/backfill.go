@@ -0,0 +1,27 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+const (
+	// BackfilledAnnotation is the signed payload annotation holding the RFC
+	// 3339 time at which `cosign backfill` signed an image that existed
+	// before it was signed, so that verifiers can tell such signatures from
+	// those made when the image was built.
+	BackfilledAnnotation = "dev.sigstore.cosign/backfilled"
+	// BackfillNoteAnnotation is the signed payload annotation holding the
+	// note given to `cosign backfill`, such as why the images were signed.
+	BackfillNoteAnnotation = "dev.sigstore.cosign/backfill-note"
+)
@@ -0,0 +1,160 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CheckChainUsage returns the chains, each from a signing certificate to a
+// trusted root, whose certificates are fit for their use, or the error of
+// the first chain if none is.
+//
+// The chains were built by x509.Certificate.Verify, which enforces the path
+// length and name constraints of the CAs, and the code signing extended key
+// usage where it is set. It treats a certificate without an extended key
+// usage extension as fit for any use, though, and ignores key usages and the
+// basic constraints of roots, which many organizations' policies for their
+// CAs require. Unless co relaxes them, CheckChainUsage requires that
+//   - the signing certificate isn't a CA, may be used for digital signatures,
+//     and has the code signing extended key usage, and not the any extended
+//     key usage,
+//   - the CAs have basic constraints and may be used for signing
+//     certificates,
+//   - with co.RequireNameConstraints, a CA has name constraints,
+//   - with co.IssuerSPKIHashes, the issuer of the signing certificate has
+//     one of the pinned keys,
+//   - with co.MaxChainDepth, the chain has at most that many CAs.
+func CheckChainUsage(chains [][]*x509.Certificate, co *CheckOpts) ([][]*x509.Certificate, error) {
+	var fit [][]*x509.Certificate
+	var firstErr error
+	for _, chain := range chains {
+		if err := checkChainUsage(chain, co); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		fit = append(fit, chain)
+	}
+	if len(fit) == 0 {
+		if firstErr == nil {
+			firstErr = errors.New("no certificate chain")
+		}
+		return nil, firstErr
+	}
+	return fit, nil
+}
+
+func checkChainUsage(chain []*x509.Certificate, co *CheckOpts) error {
+	if len(chain) == 0 {
+		return errors.New("empty certificate chain")
+	}
+	leaf, cas := chain[0], chain[1:]
+	if !co.IgnoreKeyUsage {
+		if leaf.IsCA {
+			return errors.New("signing certificate is a CA certificate, relax with --insecure-ignore-key-usage")
+		}
+		if leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+			return errors.New("signing certificate doesn't have the digital signature key usage, relax with --insecure-ignore-key-usage")
+		}
+		for _, ca := range cas {
+			if !ca.BasicConstraintsValid || !ca.IsCA {
+				return fmt.Errorf("CA certificate %q doesn't have the CA basic constraint, relax with --insecure-ignore-key-usage", ca.Subject)
+			}
+			if ca.KeyUsage&x509.KeyUsageCertSign == 0 {
+				return fmt.Errorf("CA certificate %q doesn't have the certificate signing key usage, relax with --insecure-ignore-key-usage", ca.Subject)
+			}
+		}
+	}
+	if !co.AllowAnyEKU && !codeSigningOnly(leaf) {
+		return errors.New("signing certificate doesn't have the code signing extended key usage, or also has the any extended key usage, relax with --insecure-allow-any-eku")
+	}
+	if co.RequireNameConstraints && !nameConstrained(cas) {
+		return errors.New("no CA certificate of the chain has name constraints, required by --certificate-require-name-constraints")
+	}
+	if co.MaxChainDepth > 0 && len(cas) > co.MaxChainDepth {
+		return fmt.Errorf("certificate chain has %d CA certificates, more than the %d allowed by --certificate-chain-max-depth", len(cas), co.MaxChainDepth)
+	}
+	if len(co.IssuerSPKIHashes) > 0 {
+		if len(cas) == 0 {
+			return errors.New("certificate chain has no issuer to check against --certificate-issuer-spki-hash")
+		}
+		fp, err := KeyFingerprint(cas[0].PublicKey)
+		if err != nil {
+			return fmt.Errorf("hashing the key of the issuer %q: %w", cas[0].Subject, err)
+		}
+		pinned := false
+		for _, h := range co.IssuerSPKIHashes {
+			pinned = pinned || h == fp
+		}
+		if !pinned {
+			return fmt.Errorf("issuer %q of the signing certificate has the key %s, which isn't pinned by --certificate-issuer-spki-hash", cas[0].Subject, fp)
+		}
+	}
+	return nil
+}
+
+// ParseSPKIHash parses the SHA-256 hash of the subject public key info of a
+// certificate, as sha256:<hex> or base64 as in an HTTP public key pin, into
+// its KeyFingerprint form.
+func ParseSPKIHash(s string) (string, error) {
+	var sum []byte
+	if lower := strings.ToLower(s); strings.HasPrefix(lower, "sha256:") {
+		sum, _ = hex.DecodeString(strings.TrimPrefix(lower, "sha256:"))
+	} else {
+		sum, _ = base64.StdEncoding.DecodeString(s)
+	}
+	if len(sum) != sha256.Size {
+		return "", fmt.Errorf("%q isn't a sha256:<hex> or base64 SHA-256 hash", s)
+	}
+	return "sha256:" + hex.EncodeToString(sum), nil
+}
+
+// codeSigningOnly reports whether the extended key usages of cert include
+// code signing, without the any extended key usage.
+func codeSigningOnly(cert *x509.Certificate) bool {
+	codeSigning := false
+	for _, eku := range cert.ExtKeyUsage {
+		switch eku {
+		case x509.ExtKeyUsageAny:
+			return false
+		case x509.ExtKeyUsageCodeSigning:
+			codeSigning = true
+		}
+	}
+	return codeSigning
+}
+
+// nameConstrained reports whether one of cas constrains the names of the
+// certificates it issues.
+func nameConstrained(cas []*x509.Certificate) bool {
+	for _, ca := range cas {
+		if len(ca.PermittedDNSDomains) > 0 || len(ca.ExcludedDNSDomains) > 0 ||
+			len(ca.PermittedEmailAddresses) > 0 || len(ca.ExcludedEmailAddresses) > 0 ||
+			len(ca.PermittedIPRanges) > 0 || len(ca.ExcludedIPRanges) > 0 ||
+			len(ca.PermittedURIDomains) > 0 || len(ca.ExcludedURIDomains) > 0 {
+			return true
+		}
+	}
+	return false
+}
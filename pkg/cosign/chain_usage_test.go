@@ -0,0 +1,233 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/test"
+)
+
+func TestCheckChainUsage(t *testing.T) {
+	leaf := func(mut func(*x509.Certificate)) *x509.Certificate {
+		c := &x509.Certificate{
+			KeyUsage:    x509.KeyUsageDigitalSignature,
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		}
+		if mut != nil {
+			mut(c)
+		}
+		return c
+	}
+	ca := func(mut func(*x509.Certificate)) *x509.Certificate {
+		c := &x509.Certificate{
+			Subject:               pkix.Name{CommonName: "ca"},
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+		if mut != nil {
+			mut(c)
+		}
+		return c
+	}
+
+	for name, tc := range map[string]struct {
+		chain []*x509.Certificate
+		co    CheckOpts
+		want  string
+	}{
+		"fit": {
+			chain: []*x509.Certificate{leaf(nil), ca(nil)},
+		},
+		"CA signing certificate": {
+			chain: []*x509.Certificate{leaf(func(c *x509.Certificate) { c.IsCA = true }), ca(nil)},
+			want:  "is a CA certificate",
+		},
+		"no digital signature key usage": {
+			chain: []*x509.Certificate{leaf(func(c *x509.Certificate) { c.KeyUsage = x509.KeyUsageKeyEncipherment }), ca(nil)},
+			want:  "digital signature key usage",
+		},
+		"legacy key usage": {
+			chain: []*x509.Certificate{leaf(func(c *x509.Certificate) { c.KeyUsage = 0 }), ca(func(c *x509.Certificate) { c.KeyUsage = 0 })},
+			co:    CheckOpts{IgnoreKeyUsage: true},
+		},
+		"root without basic constraints": {
+			chain: []*x509.Certificate{leaf(nil), ca(func(c *x509.Certificate) { c.BasicConstraintsValid = false })},
+			want:  "CA basic constraint",
+		},
+		"CA without certificate signing": {
+			chain: []*x509.Certificate{leaf(nil), ca(func(c *x509.Certificate) { c.KeyUsage = x509.KeyUsageCRLSign })},
+			want:  "certificate signing key usage",
+		},
+		"no extended key usage": {
+			chain: []*x509.Certificate{leaf(func(c *x509.Certificate) { c.ExtKeyUsage = nil }), ca(nil)},
+			want:  "code signing extended key usage",
+		},
+		"any extended key usage": {
+			chain: []*x509.Certificate{leaf(func(c *x509.Certificate) { c.ExtKeyUsage = append(c.ExtKeyUsage, x509.ExtKeyUsageAny) }), ca(nil)},
+			want:  "code signing extended key usage",
+		},
+		"legacy extended key usage": {
+			chain: []*x509.Certificate{leaf(func(c *x509.Certificate) { c.ExtKeyUsage = nil }), ca(nil)},
+			co:    CheckOpts{AllowAnyEKU: true},
+		},
+		"no name constraints": {
+			chain: []*x509.Certificate{leaf(nil), ca(nil)},
+			co:    CheckOpts{RequireNameConstraints: true},
+			want:  "name constraints",
+		},
+		"name constraints": {
+			chain: []*x509.Certificate{leaf(nil), ca(func(c *x509.Certificate) { c.PermittedEmailAddresses = []string{"example.com"} }), ca(nil)},
+			co:    CheckOpts{RequireNameConstraints: true},
+		},
+		"within the chain depth": {
+			chain: []*x509.Certificate{leaf(nil), ca(nil), ca(nil)},
+			co:    CheckOpts{MaxChainDepth: 2},
+		},
+		"deeper than the chain depth": {
+			chain: []*x509.Certificate{leaf(nil), ca(nil), ca(nil)},
+			co:    CheckOpts{MaxChainDepth: 1},
+			want:  "more than the 1 allowed",
+		},
+		"pinned issuer without a chain": {
+			chain: []*x509.Certificate{leaf(nil)},
+			co:    CheckOpts{IssuerSPKIHashes: []string{"sha256:abc"}},
+			want:  "no issuer",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fit, err := CheckChainUsage([][]*x509.Certificate{tc.chain}, &tc.co)
+			if tc.want == "" {
+				if err != nil || len(fit) != 1 {
+					t.Errorf("CheckChainUsage() = %d chains, %v, want the chain", len(fit), err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("CheckChainUsage() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	// Only the fit chains are returned.
+	fit, err := CheckChainUsage([][]*x509.Certificate{{leaf(nil), ca(func(c *x509.Certificate) { c.KeyUsage = 0 })}, {leaf(nil), ca(nil)}}, &CheckOpts{})
+	if err != nil || len(fit) != 1 || fit[0][1].KeyUsage != x509.KeyUsageCertSign {
+		t.Errorf("CheckChainUsage() of a fit and an unfit chain = %v, %v, want the fit chain", fit, err)
+	}
+}
+
+func TestValidateAndUnpackCertWithoutEKU(t *testing.T) {
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(2),
+		EmailAddresses: []string{"subject@mail.com"},
+		NotBefore:      time.Now().Add(-time.Minute),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, rootCert, priv.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(rootCert)
+
+	co := &CheckOpts{RootCerts: pool, IgnoreSCT: true, Identities: []Identity{{Subject: "subject@mail.com", IssuerRegExp: ".*"}}}
+	if _, err := ValidateAndUnpackCert(cert, co); err == nil || !strings.Contains(err.Error(), "extended key usage") {
+		t.Errorf("ValidateAndUnpackCert() of a certificate without an EKU = %v, want an error", err)
+	}
+	co.AllowAnyEKU = true
+	if _, err := ValidateAndUnpackCert(cert, co); err != nil {
+		t.Errorf("ValidateAndUnpackCert() with AllowAnyEKU = %v", err)
+	}
+}
+
+func TestIssuerSPKIHashes(t *testing.T) {
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	subCert, subKey, _ := test.GenerateSubordinateCa(rootCert, rootKey)
+	otherCert, _, _ := test.GenerateSubordinateCa(rootCert, rootKey)
+	leafCert, _, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", subCert, subKey)
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(subCert)
+
+	pin := func(c *x509.Certificate) string {
+		h := sha256.Sum256(c.RawSubjectPublicKeyInfo)
+		return base64.StdEncoding.EncodeToString(h[:])
+	}
+	parse := func(s string) string {
+		h, err := ParseSPKIHash(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	for name, tc := range map[string]struct {
+		pins []string
+		want string
+	}{
+		"issuer pinned":         {pins: []string{parse(pin(otherCert)), parse(pin(subCert))}},
+		"other intermediate":    {pins: []string{parse(pin(otherCert))}, want: "isn't pinned"},
+		"root isn't the issuer": {pins: []string{parse(pin(rootCert))}, want: "isn't pinned"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			co := &CheckOpts{RootCerts: roots, IntermediateCerts: intermediates, IgnoreSCT: true, IssuerSPKIHashes: tc.pins,
+				Identities: []Identity{{Subject: "subject@mail.com", Issuer: "oidc-issuer"}}}
+			_, err := ValidateAndUnpackCert(leafCert, co)
+			if tc.want == "" {
+				if err != nil {
+					t.Errorf("ValidateAndUnpackCert() = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ValidateAndUnpackCert() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseSPKIHash(t *testing.T) {
+	sum := sha256.Sum256([]byte("spki"))
+	want := "sha256:" + hex.EncodeToString(sum[:])
+	for _, s := range []string{want, strings.ToUpper(want), base64.StdEncoding.EncodeToString(sum[:])} {
+		if got, err := ParseSPKIHash(s); err != nil || got != want {
+			t.Errorf("ParseSPKIHash(%q) = %q, %v, want %q", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "sha256:abc", "sha512:" + hex.EncodeToString(sum[:]), base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := ParseSPKIHash(s); err == nil {
+			t.Errorf("ParseSPKIHash(%q): expected an error", s)
+		}
+	}
+}
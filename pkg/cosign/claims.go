@@ -0,0 +1,197 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// certClaim is the Fulcio certificate extension of an OIDC token claim. The
+// values of the extensions of OIDs 1.3.6.1.4.1.57264.1.8 and up are DER
+// UTF8Strings, those of legacy extensions raw strings.
+type certClaim struct {
+	oid    string
+	legacy string
+}
+
+// certClaims are the claims ClaimMatchers constrain by name, documented in
+// https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md.
+var certClaims = map[string]certClaim{
+	"issuer":                              {oid: "1.3.6.1.4.1.57264.1.8", legacy: CertExtensionOIDCIssuer},
+	"buildSignerURI":                      {oid: "1.3.6.1.4.1.57264.1.9"},
+	"buildSignerDigest":                   {oid: "1.3.6.1.4.1.57264.1.10"},
+	"runnerEnvironment":                   {oid: "1.3.6.1.4.1.57264.1.11"},
+	"sourceRepositoryURI":                 {oid: "1.3.6.1.4.1.57264.1.12"},
+	"sourceRepositoryDigest":              {oid: "1.3.6.1.4.1.57264.1.13", legacy: CertExtensionGithubWorkflowSha},
+	"sourceRepositoryRef":                 {oid: "1.3.6.1.4.1.57264.1.14", legacy: CertExtensionGithubWorkflowRef},
+	"sourceRepositoryIdentifier":          {oid: "1.3.6.1.4.1.57264.1.15"},
+	"sourceRepositoryOwnerURI":            {oid: "1.3.6.1.4.1.57264.1.16"},
+	"sourceRepositoryOwnerIdentifier":     {oid: "1.3.6.1.4.1.57264.1.17"},
+	"buildConfigURI":                      {oid: "1.3.6.1.4.1.57264.1.18"},
+	"buildConfigDigest":                   {oid: "1.3.6.1.4.1.57264.1.19"},
+	"buildTrigger":                        {oid: "1.3.6.1.4.1.57264.1.20", legacy: CertExtensionGithubWorkflowTrigger},
+	"runInvocationURI":                    {oid: "1.3.6.1.4.1.57264.1.21"},
+	"sourceRepositoryVisibilityAtSigning": {oid: "1.3.6.1.4.1.57264.1.22"},
+	"githubWorkflowName":                  {legacy: CertExtensionGithubWorkflowName},
+	"githubWorkflowRepository":            {legacy: CertExtensionGithubWorkflowRepository},
+}
+
+var oidPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`)
+
+// ClaimOp is how a ClaimMatcher compares the value of a claim.
+type ClaimOp string
+
+const (
+	// ClaimEquals matches the claims with the value.
+	ClaimEquals ClaimOp = "="
+	// ClaimNotEquals matches the claims without the value, or missing.
+	ClaimNotEquals ClaimOp = "!="
+	// ClaimPrefix matches the claims starting with the value.
+	ClaimPrefix ClaimOp = "^="
+	// ClaimRegexp matches the claims matching the value, a Go regular
+	// expression, which isn't anchored.
+	ClaimRegexp ClaimOp = "~="
+)
+
+// ClaimMatcher constrains a claim of the OIDC token a Fulcio certificate was
+// issued for, embedded in one of its extensions.
+type ClaimMatcher struct {
+	// Claim is the name of the claim, e.g. sourceRepositoryOwnerURI, or the
+	// OID of the extension it is embedded in.
+	Claim string
+	Op    ClaimOp
+	Value string
+
+	re *regexp.Regexp
+}
+
+// ParseClaimMatcher parses claim<op>value, e.g. runnerEnvironment=github-hosted
+// or sourceRepositoryRef^=refs/tags/, with the ops of ClaimOp.
+func ParseClaimMatcher(s string) (ClaimMatcher, error) {
+	i := strings.IndexByte(s, '=')
+	if i <= 0 {
+		return ClaimMatcher{}, fmt.Errorf("certificate claim %q isn't claim=value, claim!=value, claim^=prefix or claim~=regexp", s)
+	}
+	m := ClaimMatcher{Claim: s[:i], Op: ClaimEquals, Value: s[i+1:]}
+	for _, op := range []ClaimOp{ClaimNotEquals, ClaimPrefix, ClaimRegexp} {
+		if strings.HasSuffix(m.Claim, string(op[0])) {
+			m.Claim, m.Op = m.Claim[:len(m.Claim)-1], op
+		}
+	}
+	if _, ok := certClaims[m.Claim]; !ok && !oidPattern.MatchString(m.Claim) {
+		return ClaimMatcher{}, fmt.Errorf("unknown certificate claim %q, expected an OID or one of %s", m.Claim, strings.Join(claimNames(), ", "))
+	}
+	if m.Op == ClaimRegexp {
+		re, err := regexp.Compile(m.Value)
+		if err != nil {
+			return ClaimMatcher{}, fmt.Errorf("certificate claim %s: %w", m.Claim, err)
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+// ParseClaimMatchers parses each of claims with ParseClaimMatcher.
+func ParseClaimMatchers(claims []string) ([]ClaimMatcher, error) {
+	var matchers []ClaimMatcher
+	for _, c := range claims {
+		m, err := ParseClaimMatcher(c)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+func (m ClaimMatcher) String() string {
+	return m.Claim + string(m.Op) + m.Value
+}
+
+// Match reports whether the claim of cert matches m.
+func (m ClaimMatcher) Match(cert *x509.Certificate) bool {
+	v, ok := CertClaim(cert, m.Claim)
+	switch m.Op {
+	case ClaimNotEquals:
+		return !ok || v != m.Value
+	case ClaimPrefix:
+		return ok && strings.HasPrefix(v, m.Value)
+	case ClaimRegexp:
+		re := m.re
+		if re == nil {
+			var err error
+			if re, err = regexp.Compile(m.Value); err != nil {
+				return false
+			}
+		}
+		return ok && re.MatchString(v)
+	default:
+		return ok && v == m.Value
+	}
+}
+
+// CertClaim returns the value of the claim of cert, named as in a
+// ClaimMatcher, and whether cert has it.
+func CertClaim(cert *x509.Certificate, claim string) (string, bool) {
+	c, ok := certClaims[claim]
+	if !ok {
+		c = certClaim{oid: claim}
+	}
+	for _, ext := range cert.Extensions {
+		if c.oid != "" && ext.Id.String() == c.oid {
+			var s string
+			if rest, err := asn1.UnmarshalWithParams(ext.Value, &s, "utf8"); err == nil && len(rest) == 0 {
+				return s, true
+			}
+			if !ok {
+				// An unknown extension may be a raw string.
+				return string(ext.Value), true
+			}
+		}
+	}
+	for _, ext := range cert.Extensions {
+		if c.legacy != "" && ext.Id.String() == c.legacy {
+			return string(ext.Value), true
+		}
+	}
+	return "", false
+}
+
+func claimNames() []string {
+	names := make([]string, 0, len(certClaims))
+	for n := range certClaims {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateCertClaims returns an error if a claim of cert doesn't match its
+// matcher in co.
+func validateCertClaims(cert *x509.Certificate, co *CheckOpts) error {
+	for _, m := range co.CertClaims {
+		if !m.Match(cert) {
+			actual, _ := CertClaim(cert, m.Claim)
+			return newCertExtensionMismatchError("claim "+m.Claim, m.String(), actual)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/test"
+)
+
+func TestParseClaimMatcher(t *testing.T) {
+	for s, want := range map[string]ClaimMatcher{
+		"runnerEnvironment=github-hosted":       {Claim: "runnerEnvironment", Op: ClaimEquals, Value: "github-hosted"},
+		"sourceRepositoryRef^=refs/tags/":       {Claim: "sourceRepositoryRef", Op: ClaimPrefix, Value: "refs/tags/"},
+		"buildTrigger!=pull_request":            {Claim: "buildTrigger", Op: ClaimNotEquals, Value: "pull_request"},
+		"1.3.6.1.4.1.57264.1.16~=^https://a=b$": {Claim: "1.3.6.1.4.1.57264.1.16", Op: ClaimRegexp, Value: "^https://a=b$"},
+	} {
+		got, err := ParseClaimMatcher(s)
+		if err != nil {
+			t.Errorf("ParseClaimMatcher(%q) = %v", s, err)
+			continue
+		}
+		if got.Claim != want.Claim || got.Op != want.Op || got.Value != want.Value || got.String() != s {
+			t.Errorf("ParseClaimMatcher(%q) = %+v, want %+v", s, got, want)
+		}
+	}
+	for s, want := range map[string]string{
+		"runnerEnvironment":    "isn't claim=value",
+		"=github-hosted":       "isn't claim=value",
+		"runner=github-hosted": "unknown certificate claim",
+		"buildTrigger~=(":      "error parsing regexp",
+	} {
+		if _, err := ParseClaimMatcher(s); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseClaimMatcher(%q) = %v, want %q", s, err, want)
+		}
+	}
+}
+
+func TestCheckCertificatePolicyClaims(t *testing.T) {
+	utf8 := func(s string) []byte {
+		b, err := asn1.MarshalWithParams(s, "utf8")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	oid := func(n int) asn1.ObjectIdentifier {
+		return asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, n}
+	}
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	cert, _, err := test.GenerateLeafCert("subject@example.com", "oidc-issuer", rootCert, rootKey,
+		pkix.Extension{Id: oid(11), Value: utf8("github-hosted")},
+		pkix.Extension{Id: oid(14), Value: utf8("refs/tags/v1.2.3")},
+		pkix.Extension{Id: oid(16), Value: utf8("https://github.com/example")},
+		pkix.Extension{Id: oid(2), Value: []byte("push")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := CertClaim(cert, "issuer"); !ok || v != "oidc-issuer" {
+		t.Errorf("CertClaim(issuer) = %q, %v, want the legacy issuer extension", v, ok)
+	}
+	for name, tc := range map[string]struct {
+		claims []string
+		ok     bool
+	}{
+		"equal":                 {claims: []string{"runnerEnvironment=github-hosted", "sourceRepositoryOwnerURI=https://github.com/example"}, ok: true},
+		"prefix":                {claims: []string{"sourceRepositoryRef^=refs/tags/"}, ok: true},
+		"regexp":                {claims: []string{`sourceRepositoryRef~=^refs/tags/v\d+\.\d+\.\d+$`}, ok: true},
+		"legacy extension":      {claims: []string{"buildTrigger=push"}, ok: true},
+		"oid":                   {claims: []string{"1.3.6.1.4.1.57264.1.11=github-hosted"}, ok: true},
+		"not equal":             {claims: []string{"buildTrigger!=pull_request"}, ok: true},
+		"not equal missing":     {claims: []string{"buildSignerURI!=https://example.com"}, ok: true},
+		"another value":         {claims: []string{"runnerEnvironment=self-hosted"}},
+		"another prefix":        {claims: []string{"sourceRepositoryRef^=refs/heads/"}},
+		"missing claim":         {claims: []string{"buildSignerURI=https://example.com"}},
+		"missing claim regexp":  {claims: []string{"buildSignerURI~=.*"}},
+		"one of claims":         {claims: []string{"runnerEnvironment=github-hosted", "buildTrigger!=push"}},
+		"another legacy string": {claims: []string{"githubWorkflowName=release"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			matchers, err := ParseClaimMatchers(tc.claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = CheckCertificatePolicy(cert, &CheckOpts{CertClaims: matchers})
+			if tc.ok && err != nil {
+				t.Errorf("CheckCertificatePolicy() = %v", err)
+			}
+			var mismatch *CertExtensionMismatchError
+			if !tc.ok && !errors.As(err, &mismatch) {
+				t.Errorf("CheckCertificatePolicy() = %v, want a certificate extension mismatch", err)
+			}
+		})
+	}
+}
@@ -16,10 +16,13 @@ package cosign
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tufcache"
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 	"github.com/sigstore/sigstore/pkg/tuf"
 )
@@ -45,11 +48,7 @@ func GetCTLogPubs(ctx context.Context) (*TrustedTransparencyLogPubKeys, error) {
 			return nil, fmt.Errorf("AddCTLogPubKey: %w", err)
 		}
 	} else {
-		tufClient, err := tuf.NewFromEnv(ctx)
-		if err != nil {
-			return nil, err
-		}
-		targets, err := tufClient.GetTargetsByMeta(tuf.CTFE, []string{ctPublicKeyStr})
+		targets, err := tufcache.GetTargetsByMeta(ctx, tuf.CTFE, []string{ctPublicKeyStr})
 		if err != nil {
 			return nil, err
 		}
@@ -58,6 +57,17 @@ func GetCTLogPubs(ctx context.Context) (*TrustedTransparencyLogPubKeys, error) {
 				return nil, fmt.Errorf("AddCTLogPubKey: %w", err)
 			}
 		}
+		// The trusted root, if the TUF root has one, has the URLs of the logs
+		// that inclusion proofs are fetched from.
+		if raw, err := tufcache.GetTarget(ctx, trustedRootTargetStr); err == nil {
+			root := trustedRootTlogs{}
+			if err := json.Unmarshal(raw, &root); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", trustedRootTargetStr, err)
+			}
+			if err := publicKeys.addTrustedRootLogs(root.Ctlogs, time.Now()); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", trustedRootTargetStr, err)
+			}
+		}
 	}
 
 	if len(publicKeys.Keys) == 0 {
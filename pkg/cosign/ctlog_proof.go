@@ -0,0 +1,266 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	ctclient "github.com/google/certificate-transparency-go/client"
+	"github.com/google/certificate-transparency-go/jsonclient"
+	cttls "github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"github.com/google/certificate-transparency-go/x509util"
+	"github.com/sigstore/cosign/v2/pkg/cosign/fulcioverifier/ctutil"
+	"github.com/transparency-dev/merkle/proof"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+// CTInclusionProof is the proof that a certificate is in the certificate
+// transparency log of its SCT, to a tree head signed by the log.
+type CTInclusionProof struct {
+	// LogID is the hex ID of the log, as in the SCT.
+	LogID string `json:"logID"`
+	// LeafHash is the RFC 6962 hash of the log entry of the certificate.
+	LeafHash  []byte   `json:"leafHash"`
+	LeafIndex int64    `json:"leafIndex"`
+	Hashes    [][]byte `json:"hashes"`
+	// TreeSize, Timestamp, RootHash and TreeHeadSignature are the signed tree
+	// head the proof is to. TreeHeadSignature is TLS encoded, as in RFC 6962.
+	TreeSize          uint64 `json:"treeSize"`
+	Timestamp         uint64 `json:"timestamp"`
+	RootHash          []byte `json:"rootHash"`
+	TreeHeadSignature []byte `json:"treeHeadSignature"`
+}
+
+// CTLogClient fetches the signed tree heads and inclusion proofs of a
+// certificate transparency log, as the client of
+// github.com/google/certificate-transparency-go/client does.
+type CTLogClient interface {
+	GetSTH(ctx context.Context) (*ct.SignedTreeHead, error)
+	GetProofByHash(ctx context.Context, hash []byte, treeSize uint64) (*ct.GetProofByHashResponse, error)
+}
+
+// NewCTLogClient returns the client of the certificate transparency log at
+// url.
+func NewCTLogClient(url string) (CTLogClient, error) {
+	return ctclient.New(url, &http.Client{Timeout: 30 * time.Second}, jsonclient.Options{})
+}
+
+// CTLogClientsOfKeys returns the clients of the certificate transparency logs
+// by log ID, at the base URLs of their keys in pubKeys, which only keys of a
+// Sigstore trusted root have.
+func CTLogClientsOfKeys(pubKeys *TrustedTransparencyLogPubKeys) func(logID string) (CTLogClient, error) {
+	return func(logID string) (CTLogClient, error) {
+		if pubKeys != nil {
+			if k, ok := pubKeys.Keys[logID]; ok && k.BaseURL != "" {
+				return NewCTLogClient(k.BaseURL)
+			}
+		}
+		return nil, fmt.Errorf("the URL of the certificate transparency log %s is unknown", logID)
+	}
+}
+
+// ctLeaf is the entry of a certificate in the log of one of its SCTs.
+type ctLeaf struct {
+	logID string
+	hash  [sha256.Size]byte
+}
+
+// ctLeaves returns the log entries of chain[0], a certificate followed by its
+// issuer, for each of its embedded SCTs, or for the detached rawSCT if it has
+// none.
+func ctLeaves(chain []*x509.Certificate, rawSCT []byte) ([]ctLeaf, error) {
+	if len(chain) < 2 {
+		return nil, errors.New("certificate chain must contain at least a certificate and its issuer")
+	}
+	ctChain := make([]*ctx509.Certificate, 0, 2)
+	for _, c := range chain[:2] {
+		cert, err := ctx509.ParseCertificate(c.Raw)
+		if ctx509.IsFatal(err) {
+			return nil, err
+		}
+		ctChain = append(ctChain, cert)
+	}
+	scts, err := x509util.ParseSCTsFromCertificate(chain[0].Raw)
+	if err != nil {
+		return nil, err
+	}
+	embedded := len(scts) > 0
+	if !embedded {
+		if len(rawSCT) == 0 {
+			return nil, &VerificationError{ErrMissingSCTType, ErrMissingSCTMessage}
+		}
+		var resp ct.AddChainResponse
+		if err := json.Unmarshal(rawSCT, &resp); err != nil {
+			return nil, fmt.Errorf("parsing detached SCT: %w", err)
+		}
+		sct, err := resp.ToSignedCertificateTimestamp()
+		if err != nil {
+			return nil, err
+		}
+		scts = []*ct.SignedCertificateTimestamp{sct}
+		ctChain = ctChain[:1]
+	}
+
+	leaves := make([]ctLeaf, 0, len(scts))
+	for _, sct := range scts {
+		h, err := ctutil.LeafHash(ctChain, sct, embedded)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, ctLeaf{logID: hex.EncodeToString(sct.LogID.KeyID[:]), hash: h})
+	}
+	return leaves, nil
+}
+
+// FetchCTInclusionProof fetches the inclusion proof of chain[0], a
+// certificate followed by its issuer, in the log of one of its embedded SCTs,
+// or of the detached rawSCT if it has none, from the client of the log.
+func FetchCTInclusionProof(ctx context.Context, chain []*x509.Certificate, rawSCT []byte, clients func(logID string) (CTLogClient, error)) (*CTInclusionProof, error) {
+	leaves, err := ctLeaves(chain, rawSCT)
+	if err != nil {
+		return nil, err
+	}
+	var errs []string
+	for _, l := range leaves {
+		p, err := fetchCTInclusionProof(ctx, l, clients)
+		if err == nil {
+			return p, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return nil, errors.New(strings.Join(errs, "; "))
+}
+
+func fetchCTInclusionProof(ctx context.Context, l ctLeaf, clients func(logID string) (CTLogClient, error)) (*CTInclusionProof, error) {
+	lc, err := clients(l.logID)
+	if err != nil {
+		return nil, err
+	}
+	sth, err := lc.GetSTH(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the tree head of the certificate transparency log %s: %w", l.logID, err)
+	}
+	resp, err := lc.GetProofByHash(ctx, l.hash[:], sth.TreeSize)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the inclusion proof in the certificate transparency log %s: %w", l.logID, err)
+	}
+	sig, err := cttls.Marshal(sth.TreeHeadSignature)
+	if err != nil {
+		return nil, err
+	}
+	return &CTInclusionProof{
+		LogID:             l.logID,
+		LeafHash:          l.hash[:],
+		LeafIndex:         resp.LeafIndex,
+		Hashes:            resp.AuditPath,
+		TreeSize:          sth.TreeSize,
+		Timestamp:         sth.Timestamp,
+		RootHash:          sth.SHA256RootHash[:],
+		TreeHeadSignature: sig,
+	}, nil
+}
+
+// verify verifies that the tree head of p is signed by the key of its log in
+// pubKeys, and that the proof is of the entry l to the root of the tree.
+func (p *CTInclusionProof) verify(l ctLeaf, pubKeys *TrustedTransparencyLogPubKeys) error {
+	if p.LogID != l.logID || !bytes.Equal(p.LeafHash, l.hash[:]) {
+		return errors.New("the inclusion proof isn't of the certificate")
+	}
+	if pubKeys == nil {
+		return errors.New("none of the CTFE keys have been found")
+	}
+	k, ok := pubKeys.Keys[p.LogID]
+	if !ok {
+		return fmt.Errorf("the key of the certificate transparency log %s wasn't found", p.LogID)
+	}
+	sth := ct.SignedTreeHead{Version: ct.V1, TreeSize: p.TreeSize, Timestamp: p.Timestamp}
+	if len(p.RootHash) != len(sth.SHA256RootHash) {
+		return errors.New("the tree head has an invalid root hash")
+	}
+	copy(sth.SHA256RootHash[:], p.RootHash)
+	if rest, err := cttls.Unmarshal(p.TreeHeadSignature, &sth.TreeHeadSignature); err != nil || len(rest) > 0 {
+		return errors.New("the tree head has an invalid signature")
+	}
+	sv, err := ct.NewSignatureVerifier(k.PubKey)
+	if err != nil {
+		return err
+	}
+	if err := sv.VerifySTHSignature(sth); err != nil {
+		return fmt.Errorf("verifying the tree head of the certificate transparency log %s: %w", p.LogID, err)
+	}
+	if p.LeafIndex < 0 {
+		return errors.New("the inclusion proof has a negative leaf index")
+	}
+	if err := proof.VerifyInclusion(rfc6962.DefaultHasher, uint64(p.LeafIndex), p.TreeSize, p.LeafHash, p.Hashes, p.RootHash); err != nil {
+		return fmt.Errorf("verifying the inclusion proof in the certificate transparency log %s: %w", p.LogID, err)
+	}
+	return nil
+}
+
+// verifyCTInclusion verifies that the certificate of chain is in the log of
+// one of its SCTs, with its proof in co.CTInclusionProofs or, unless
+// co.Offline is set, a proof fetched from the log with co.CTLogClients.
+func verifyCTInclusion(ctx context.Context, chain []*x509.Certificate, co *CheckOpts) error {
+	leaves, err := ctLeaves(chain, co.SCT)
+	if err != nil {
+		return err
+	}
+	clients := co.CTLogClients
+	if clients == nil {
+		clients = CTLogClientsOfKeys(co.CTLogPubKeys)
+	}
+	var errs []string
+	for _, l := range leaves {
+		p := findCTInclusionProof(co.CTInclusionProofs, l)
+		if p == nil {
+			if co.Offline {
+				errs = append(errs, fmt.Sprintf("no inclusion proof in the certificate transparency log %s", l.logID))
+				continue
+			}
+			if p, err = fetchCTInclusionProof(ctx, l, clients); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+		}
+		if err := p.verify(l, co.CTLogPubKeys); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("certificate transparency log inclusion: %s", strings.Join(errs, "; "))
+}
+
+func findCTInclusionProof(proofs []*CTInclusionProof, l ctLeaf) *CTInclusionProof {
+	for _, p := range proofs {
+		if p != nil && p.LogID == l.logID && bytes.Equal(p.LeafHash, l.hash[:]) {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	cttls "github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/tuf"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+// fakeCTLog is a certificate transparency log of three entries, the second
+// of which is that of a certificate.
+type fakeCTLog struct {
+	sth    ct.SignedTreeHead
+	leaf   []byte
+	hashes [][]byte
+}
+
+func (l *fakeCTLog) GetSTH(ctx context.Context) (*ct.SignedTreeHead, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sth := l.sth
+	return &sth, nil
+}
+
+func (l *fakeCTLog) GetProofByHash(_ context.Context, hash []byte, treeSize uint64) (*ct.GetProofByHashResponse, error) {
+	if !bytes.Equal(hash, l.leaf) || treeSize != l.sth.TreeSize {
+		return nil, errors.New("not found")
+	}
+	return &ct.GetProofByHashResponse{LeafIndex: 1, AuditPath: l.hashes}, nil
+}
+
+// newCTLogCert returns the chain of a certificate with an SCT embedded by a
+// log of logKey, and the log.
+func newCTLogCert(t *testing.T, logKey *ecdsa.PrivateKey) ([]*x509.Certificate, *fakeCTLog) {
+	t.Helper()
+	rootCert, rootKey, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(2),
+		EmailAddresses: []string{"subject@example.com"},
+		NotBefore:      time.Now().Add(-time.Minute),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	create := func() *x509.Certificate {
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, rootCert, certKey.Public(), rootKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+
+	// The SCT is of the certificate without it, as a precertificate.
+	logID, err := GetTransparencyLogID(logKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sct := ct.SignedCertificateTimestamp{SCTVersion: ct.V1, Timestamp: uint64(time.Now().UnixMilli())}
+	keyID, err := hex.DecodeString(logID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(sct.LogID.KeyID[:], keyID)
+	leaf := ct.MerkleTreeLeaf{
+		Version:  ct.V1,
+		LeafType: ct.TimestampedEntryLeafType,
+		TimestampedEntry: &ct.TimestampedEntry{
+			EntryType: ct.PrecertLogEntryType,
+			Timestamp: sct.Timestamp,
+			PrecertEntry: &ct.PreCert{
+				IssuerKeyHash:  sha256.Sum256(rootCert.RawSubjectPublicKeyInfo),
+				TBSCertificate: create().RawTBSCertificate,
+			},
+		},
+	}
+	input, err := ct.SerializeSCTSignatureInput(sct, ct.LogEntry{Leaf: leaf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := cttls.CreateSignature(*logKey, cttls.SHA256, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sct.Signature = ct.DigitallySigned(sig)
+	rawSCT, err := cttls.Marshal(sct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := cttls.Marshal(ctx509.SignedCertificateTimestampList{SCTList: []ctx509.SerializedSCT{{Val: rawSCT}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext, err := asn1.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl.ExtraExtensions = []pkix.Extension{{Id: asn1.ObjectIdentifier(ctx509.OIDExtensionCTSCT), Value: ext}}
+	cert := create()
+
+	leafHash, err := ct.LeafHashForLeaf(&leaf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, third := rfc6962.DefaultHasher.HashLeaf([]byte("first")), rfc6962.DefaultHasher.HashLeaf([]byte("third"))
+	root := rfc6962.DefaultHasher.HashChildren(rfc6962.DefaultHasher.HashChildren(first, leafHash[:]), third)
+	log := &fakeCTLog{
+		sth:    ct.SignedTreeHead{Version: ct.V1, TreeSize: 3, Timestamp: sct.Timestamp + 1000},
+		leaf:   leafHash[:],
+		hashes: [][]byte{first, third},
+	}
+	copy(log.sth.SHA256RootHash[:], root)
+	sthInput, err := ct.SerializeSTHSignatureInput(log.sth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err = cttls.CreateSignature(*logKey, cttls.SHA256, sthInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.sth.TreeHeadSignature = ct.DigitallySigned(sig)
+	return []*x509.Certificate{cert, rootCert}, log
+}
+
+func TestVerifyCTInclusion(t *testing.T) {
+	logKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logPub, err := cryptoutils.MarshalPublicKeyToPEM(logKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeys := NewTrustedTransparencyLogPubKeys()
+	if err := pubKeys.AddTransparencyLogPubKey(logPub, tuf.Active); err != nil {
+		t.Fatal(err)
+	}
+	chain, log := newCTLogCert(t, logKey)
+	clients := func(string) (CTLogClient, error) { return log, nil }
+	roots := x509.NewCertPool()
+	roots.AddCert(chain[1])
+
+	co := &CheckOpts{RootCerts: roots, CTLogPubKeys: &pubKeys, RequireCTInclusion: true, CTLogClients: clients}
+	if _, err := ValidateAndUnpackCert(chain[0], co); err != nil {
+		t.Fatalf("ValidateAndUnpackCert() = %v", err)
+	}
+	// The proof is fetched with the context of the caller.
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ValidateAndUnpackCertWithContext(canceled, chain[0], co); err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("ValidateAndUnpackCertWithContext() with a canceled context = %v, want %v", err, context.Canceled)
+	}
+
+	p, err := FetchCTInclusionProof(context.Background(), chain, nil, clients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.LeafIndex != 1 || p.TreeSize != 3 || !bytes.Equal(p.LeafHash, log.leaf) {
+		t.Errorf("FetchCTInclusionProof() = %+v", p)
+	}
+	offline := &CheckOpts{CTLogPubKeys: &pubKeys, Offline: true, CTInclusionProofs: []*CTInclusionProof{p}}
+	if err := verifyCTInclusion(context.Background(), chain, offline); err != nil {
+		t.Errorf("verifyCTInclusion() with the proof = %v", err)
+	}
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherChain, otherLog := newCTLogCert(t, otherKey)
+	for name, tc := range map[string]struct {
+		chain []*x509.Certificate
+		co    CheckOpts
+		want  string
+	}{
+		"offline without proof": {
+			chain: chain,
+			co:    CheckOpts{Offline: true},
+			want:  "no inclusion proof",
+		},
+		"proof of another certificate": {
+			chain: otherChain,
+			co:    CheckOpts{Offline: true, CTInclusionProofs: []*CTInclusionProof{p}},
+			want:  "no inclusion proof",
+		},
+		"invalid audit path": {
+			chain: chain,
+			co: CheckOpts{CTLogClients: func(string) (CTLogClient, error) {
+				return &fakeCTLog{sth: log.sth, leaf: log.leaf, hashes: [][]byte{log.hashes[1], log.hashes[0]}}, nil
+			}},
+			want: "verifying the inclusion proof",
+		},
+		"tree head of another log": {
+			chain: chain,
+			co: CheckOpts{CTLogClients: func(string) (CTLogClient, error) {
+				return &fakeCTLog{sth: ct.SignedTreeHead{TreeSize: 3, SHA256RootHash: log.sth.SHA256RootHash, TreeHeadSignature: otherLog.sth.TreeHeadSignature}, leaf: log.leaf, hashes: log.hashes}, nil
+			}},
+			want: "verifying the tree head",
+		},
+		"unknown log URL": {
+			chain: chain,
+			want:  "is unknown",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tc.co.CTLogPubKeys = &pubKeys
+			if err := verifyCTInclusion(context.Background(), tc.chain, &tc.co); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("verifyCTInclusion() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestOfflineBundleCTInclusionProofs(t *testing.T) {
+	logKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, log := newCTLogCert(t, logKey)
+	certPEM, err := cryptoutils.MarshalCertificateToPEM(chain[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainPEM, err := cryptoutils.MarshalCertificatesToPEM(chain[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &OfflineBundle{
+		Signatures:   []OfflineSignature{{Certificate: certPEM, Chain: chainPEM}, {}},
+		Attestations: []OfflineSignature{{Certificate: certPEM}},
+	}
+	if err := b.AddCTInclusionProofs(context.Background(), func(string) (CTLogClient, error) { return log, nil }); err != nil {
+		t.Fatalf("AddCTInclusionProofs() = %v", err)
+	}
+	if p := b.Signatures[0].CTInclusionProof; p == nil || !bytes.Equal(p.LeafHash, log.leaf) {
+		t.Errorf("AddCTInclusionProofs() added %+v, want the proof of the certificate", p)
+	}
+	if b.Signatures[1].CTInclusionProof != nil || b.Attestations[0].CTInclusionProof != nil {
+		t.Error("AddCTInclusionProofs() added proofs of signatures without a certificate chain")
+	}
+
+	co := &CheckOpts{}
+	if got := withCTInclusionProofs(co, b.Signatures); len(got.CTInclusionProofs) != 1 || len(co.CTInclusionProofs) != 0 {
+		t.Errorf("withCTInclusionProofs() = %d proofs, and %d of co, want 1 and 0", len(got.CTInclusionProofs), len(co.CTInclusionProofs))
+	}
+}
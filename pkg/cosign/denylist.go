@@ -0,0 +1,208 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// Denylist lists revoked signing keys, certificate identities and artifact
+// digests. Signatures that match any entry fail verification, so that
+// compromised signers are blocked before their signatures are cleaned up.
+type Denylist struct {
+	// Keys are the sha256:<hex> fingerprints of the DER encoding of revoked
+	// public keys. They match both key and certificate based signatures.
+	Keys []string `json:"keys,omitempty"`
+	// Identities are revoked certificate identities.
+	Identities []DeniedIdentity `json:"identities,omitempty"`
+	// Digests are the digests of revoked artifacts, e.g. sha256:<hex>.
+	Digests []string `json:"digests,omitempty"`
+}
+
+// DeniedIdentity is a revoked certificate identity. An empty Issuer matches
+// certificates from any issuer.
+type DeniedIdentity struct {
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer,omitempty"`
+}
+
+// ParseDenylist verifies the base64 encoded signature of raw with verifier
+// and parses raw as a Denylist.
+func ParseDenylist(raw []byte, b64sig []byte, verifier signature.Verifier) (*Denylist, error) {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b64sig)))
+	if err != nil {
+		return nil, fmt.Errorf("decoding denylist signature: %w", err)
+	}
+	if err := verifier.VerifySignature(bytes.NewReader(sig), bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("verifying denylist signature: %w", err)
+	}
+	return parseDenylist(raw)
+}
+
+// ParseTUFDenylist parses a Denylist distributed as a TUF target, which the
+// TUF metadata already authenticates.
+func ParseTUFDenylist(raw []byte) (*Denylist, error) {
+	return parseDenylist(raw)
+}
+
+// FetchDenylist fetches a Denylist published as a single layer OCI artifact
+// at ref, after verifying the artifact's signatures with co.
+func FetchDenylist(ctx context.Context, ref name.Reference, co *CheckOpts) (*Denylist, error) {
+	digest, err := ociremote.ResolveDigest(ref, co.RegistryClientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := VerifyImageSignatures(ctx, digest, co); err != nil {
+		return nil, fmt.Errorf("verifying denylist %s: %w", ref, err)
+	}
+	// Read the verified digest, so the denylist can't change in between.
+	raw, err := readSingleLayer(digest, co.RegistryClientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("denylist %s: %w", ref, err)
+	}
+	return parseDenylist(raw)
+}
+
+// readSingleLayer returns the contents of the only layer of the artifact at
+// ref.
+func readSingleLayer(ref name.Reference, opts ...ociremote.Option) ([]byte, error) {
+	se, err := ociremote.SignedEntity(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	img, ok := se.(oci.SignedImage)
+	if !ok {
+		return nil, errors.New("not an image")
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+	if len(layers) != 1 {
+		return nil, fmt.Errorf("%d layers, expected 1", len(layers))
+	}
+	rc, err := layers[0].Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func parseDenylist(raw []byte) (*Denylist, error) {
+	d := &Denylist{}
+	if err := json.Unmarshal(raw, d); err != nil {
+		return nil, fmt.Errorf("parsing denylist: %w", err)
+	}
+	for i, k := range d.Keys {
+		d.Keys[i] = strings.ToLower(k)
+	}
+	for i, h := range d.Digests {
+		d.Digests[i] = strings.ToLower(h)
+	}
+	for _, id := range d.Identities {
+		if id.Subject == "" {
+			return nil, errors.New("denylist identities must have a subject")
+		}
+	}
+	return d, nil
+}
+
+// CheckDigest returns an error if h is a denied artifact digest. Digests are
+// compared case-insensitively.
+func (d *Denylist) CheckDigest(h v1.Hash) error {
+	if d == nil || h.Hex == "" {
+		return nil
+	}
+	digest := strings.ToLower(h.String())
+	for _, denied := range d.Digests {
+		if strings.ToLower(denied) == digest {
+			return newTypedVerificationError(ErrDeniedType, "%s: artifact %s is denied", ErrDeniedMessage, h)
+		}
+	}
+	return nil
+}
+
+// check returns an error if the artifact digest h, the key of verifier or
+// the identity in the certificate of sig is denied.
+func (d *Denylist) check(sig oci.Signature, h v1.Hash, verifier signature.Verifier) error {
+	if d == nil {
+		return nil
+	}
+	if err := d.CheckDigest(h); err != nil {
+		return err
+	}
+
+	if len(d.Keys) > 0 {
+		pub, err := verifier.PublicKey()
+		if err != nil {
+			return err
+		}
+		fp, err := KeyFingerprint(pub)
+		if err != nil {
+			return err
+		}
+		for _, denied := range d.Keys {
+			if denied == fp {
+				return newTypedVerificationError(ErrDeniedType, "%s: key %s is denied", ErrDeniedMessage, fp)
+			}
+		}
+	}
+
+	if len(d.Identities) > 0 {
+		cert, err := sig.Cert()
+		if err != nil {
+			return err
+		}
+		if cert == nil {
+			return nil
+		}
+		for _, denied := range d.Identities {
+			co := &CheckOpts{Identities: []Identity{{Subject: denied.Subject, Issuer: denied.Issuer}}}
+			if CheckCertificatePolicy(cert, co) == nil {
+				return newTypedVerificationError(ErrDeniedType, "%s: identity %s is denied", ErrDeniedMessage, denied.Subject)
+			}
+		}
+	}
+	return nil
+}
+
+// KeyFingerprint returns the sha256:<hex> fingerprint of the DER encoding of
+// pub, as used in denylists.
+func KeyFingerprint(pub crypto.PublicKey) (string, error) {
+	der, err := cryptoutils.MarshalPublicKeyToDER(pub)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(der)
+	return "sha256:" + hex.EncodeToString(h[:]), nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func newDenylistTestSigner(t *testing.T) signature.SignerVerifier {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sv
+}
+
+func TestParseDenylist(t *testing.T) {
+	sv := newDenylistTestSigner(t)
+	raw := []byte(`{"keys":["SHA256:ABC"],"identities":[{"subject":"user@example.com"}],"digests":["SHA256:DEF"]}`)
+	sig, err := sv.SignMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64sig := []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+
+	d, err := ParseDenylist(raw, b64sig, sv)
+	if err != nil {
+		t.Fatalf("ParseDenylist() = %v", err)
+	}
+	if len(d.Keys) != 1 || d.Keys[0] != "sha256:abc" || len(d.Identities) != 1 || len(d.Digests) != 1 || d.Digests[0] != "sha256:def" {
+		t.Errorf("unexpected denylist: %+v", d)
+	}
+
+	if _, err := ParseDenylist(append(raw, ' '), b64sig, sv); err == nil {
+		t.Error("expected a modified denylist to fail verification")
+	}
+	if _, err := ParseDenylist(raw, b64sig, newDenylistTestSigner(t)); err == nil {
+		t.Error("expected a denylist signed by another key to fail verification")
+	}
+	bad := []byte(`{"identities":[{"issuer":"https://issuer.example.com"}]}`)
+	sig, _ = sv.SignMessage(bytes.NewReader(bad))
+	if _, err := ParseDenylist(bad, []byte(base64.StdEncoding.EncodeToString(sig)), sv); err == nil {
+		t.Error("expected an identity without a subject to be rejected")
+	}
+}
+
+func TestDenylistCheck(t *testing.T) {
+	sv := newDenylistTestSigner(t)
+	pub, _ := sv.PublicKey()
+	fp, err := KeyFingerprint(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("a", 64)}
+
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	leafCert, _, _ := test.GenerateLeafCert("user@example.com", "https://issuer.example.com", rootCert, rootKey)
+	leafPEM, _ := cryptoutils.MarshalCertificateToPEM(leafCert)
+	keyless, err := static.NewSignature([]byte("payload"), "", static.WithCertChain(leafPEM, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		d      *Denylist
+		denied bool
+	}{
+		{name: "no denylist"},
+		{name: "other entries", d: &Denylist{Keys: []string{"sha256:abc"}, Digests: []string{"sha256:" + strings.Repeat("b", 64)}}},
+		{name: "denied key", d: &Denylist{Keys: []string{fp}}, denied: true},
+		{name: "denied digest", d: &Denylist{Digests: []string{digest.String()}}, denied: true},
+		{name: "denied mixed-case digest", d: &Denylist{Digests: []string{"sha256:" + strings.Repeat("A", 32) + strings.Repeat("a", 32)}}, denied: true},
+		{name: "denied identity", d: &Denylist{Identities: []DeniedIdentity{{Subject: "user@example.com"}}}, denied: true},
+		{name: "identity from another issuer", d: &Denylist{Identities: []DeniedIdentity{{Subject: "user@example.com", Issuer: "https://other.example.com"}}}},
+	}
+	for _, tt := range tests {
+		err := tt.d.check(keyless, digest, sv)
+		if got := errors.Is(err, ErrDenied); got != tt.denied {
+			t.Errorf("%s: check() = %v, want denied %t", tt.name, err, tt.denied)
+		}
+	}
+
+	upper := v1.Hash{Algorithm: "sha256", Hex: strings.ToUpper(digest.Hex)}
+	if err := (&Denylist{Digests: []string{digest.String()}}).CheckDigest(upper); !errors.Is(err, ErrDenied) {
+		t.Errorf("CheckDigest() of an upper case digest = %v, want denied", err)
+	}
+}
+
+func TestVerifyBlobSignatureDenied(t *testing.T) {
+	sv := newDenylistTestSigner(t)
+	pub, _ := sv.PublicKey()
+	fp, _ := KeyFingerprint(pub)
+	payload := []byte("payload")
+	raw, err := sv.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(payload, base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	co := &CheckOpts{SigVerifier: sv, IgnoreTlog: true}
+	if _, err := VerifyBlobSignature(context.Background(), sig, co); err != nil {
+		t.Fatalf("VerifyBlobSignature() = %v", err)
+	}
+	co.Denylist = &Denylist{Keys: []string{fp}}
+	if _, err := VerifyBlobSignature(context.Background(), sig, co); !errors.Is(err, ErrDenied) {
+		t.Errorf("VerifyBlobSignature() = %v, want %v", err, ErrDenied)
+	}
+}
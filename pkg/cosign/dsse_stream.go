@@ -0,0 +1,442 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	ssldsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+// maxEnvelopeField is the largest field of a DSSE envelope, other than its
+// payload, that VerifyBlobAttestationStream reads into memory.
+const maxEnvelopeField = 1 << 20
+
+// StreamOpts are the checks of VerifyBlobAttestationStream on the in-toto
+// statement of the envelope, which is parsed as it is hashed.
+type StreamOpts struct {
+	// CheckClaims checks that the blob is a subject of the statement.
+	CheckClaims bool
+	// PredicateType, if set, is the predicate type URI that the statement
+	// must have.
+	PredicateType string
+}
+
+// VerifyBlobAttestationStream verifies the DSSE envelope read from r, an
+// attestation of the blob with digest h, with co.SigVerifier. Unlike
+// VerifyBlobAttestation it never holds the payload of the envelope in memory:
+// the envelope is read twice, first to find its signatures and the extent of
+// its payload, then to hash the payload while its statement is checked.
+//
+// The signature is verified against the SHA-256 digest of the envelope, so
+// the key can't be an Ed25519 key. Verification against certificates or the
+// transparency log, which need the whole envelope, is not supported.
+func VerifyBlobAttestationStream(ctx context.Context, r io.ReadSeeker, h v1.Hash, so StreamOpts, co *CheckOpts) error {
+	if co.SigVerifier == nil {
+		return errors.New("streaming attestation verification needs a public key")
+	}
+	if !co.IgnoreTlog {
+		return errors.New("streaming attestation verification doesn't support the transparency log")
+	}
+	pub, err := co.SigVerifier.PublicKey(options.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if _, ok := pub.(ed25519.PublicKey); ok {
+		return errors.New("streaming attestation verification doesn't support Ed25519 keys, which sign the whole envelope")
+	}
+	if co.Denylist != nil {
+		// The envelope has no certificate, only the blob and key are checked.
+		sig, err := static.NewSignature(nil, "")
+		if err != nil {
+			return err
+		}
+		if err := co.Denylist.check(sig, h, co.SigVerifier); err != nil {
+			return err
+		}
+	}
+
+	env, err := scanEnvelope(r)
+	if err != nil {
+		return fmt.Errorf("reading DSSE envelope: %w", err)
+	}
+	if err := checkPayloadType(env.payloadType, co.PayloadTypes); err != nil {
+		return err
+	}
+	if len(env.signatures) == 0 {
+		return errors.New("DSSE envelope has no signatures")
+	}
+
+	if _, err := r.Seek(env.payloadOffset, io.SeekStart); err != nil {
+		return err
+	}
+	digest, st, err := hashPayload(io.LimitReader(r, env.payloadLen), env)
+	if err != nil {
+		return fmt.Errorf("reading DSSE payload: %w", err)
+	}
+
+	var verifyErr error
+	for _, s := range env.signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			verifyErr = err
+			continue
+		}
+		if verifyErr = co.SigVerifier.VerifySignature(bytes.NewReader(sig), nil, options.WithDigest(digest), options.WithContext(ctx)); verifyErr == nil {
+			break
+		}
+	}
+	if verifyErr != nil {
+		return fmt.Errorf("verifying DSSE envelope: %w", verifyErr)
+	}
+
+	if err := co.KeyUsage.checkUsage(co.SigVerifier, nil, KeyUsageAttest, st.PredicateType); err != nil {
+		return err
+	}
+	if so.PredicateType != "" && st.PredicateType != so.PredicateType {
+		return fmt.Errorf("invalid predicate type, expected %s got %s", so.PredicateType, st.PredicateType)
+	}
+	if so.CheckClaims {
+		for _, subj := range st.Subject {
+			if dgst, ok := subj.Digest[h.Algorithm]; ok && dgst == h.Hex {
+				return nil
+			}
+		}
+		return errors.New("no matching subject digest found")
+	}
+	return nil
+}
+
+// scannedEnvelope is a DSSE envelope without its payload, which is
+// payloadLen bytes of base64 at payloadOffset.
+type scannedEnvelope struct {
+	payloadType   string
+	signatures    []ssldsse.Signature
+	payloadOffset int64
+	payloadLen    int64
+	payloadPad    int
+}
+
+// envelopeScanner reads a JSON object, keeping track of its offset.
+type envelopeScanner struct {
+	r   *bufio.Reader
+	off int64
+}
+
+func (s *envelopeScanner) readByte() (byte, error) {
+	c, err := s.r.ReadByte()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil {
+		s.off++
+	}
+	return c, err
+}
+
+// next returns the next byte that isn't whitespace.
+func (s *envelopeScanner) next() (byte, error) {
+	for {
+		c, err := s.readByte()
+		if err != nil {
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+		default:
+			return c, nil
+		}
+	}
+}
+
+func (s *envelopeScanner) expect(want byte) error {
+	c, err := s.next()
+	if err != nil {
+		return err
+	}
+	if c != want {
+		return fmt.Errorf("unexpected %q at offset %d, expected %q", c, s.off-1, want)
+	}
+	return nil
+}
+
+// value reads the JSON value that starts with c, of at most
+// maxEnvelopeField bytes.
+func (s *envelopeScanner) value(c byte) (json.RawMessage, error) {
+	raw := []byte{c}
+	depth := 0
+	inString := c == '"'
+	escaped := false
+	switch c {
+	case '{', '[':
+		depth = 1
+	case '"':
+	default:
+		// A number, true, false or null runs until the next delimiter.
+		for {
+			b, err := s.r.Peek(1)
+			if err != nil {
+				return nil, io.ErrUnexpectedEOF
+			}
+			if bytes.ContainsAny(b, ",}] \t\r\n") {
+				return raw, nil
+			}
+			c, _ := s.readByte()
+			raw = append(raw, c)
+			if len(raw) > maxEnvelopeField {
+				return nil, fmt.Errorf("envelope field is larger than %d bytes", maxEnvelopeField)
+			}
+		}
+	}
+	for inString || depth > 0 {
+		c, err := s.readByte()
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, c)
+		if len(raw) > maxEnvelopeField {
+			return nil, fmt.Errorf("envelope field is larger than %d bytes", maxEnvelopeField)
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return raw, nil
+}
+
+// payload skips the base64 payload string, which has been opened, returning
+// its length and padding.
+func (s *envelopeScanner) payload(env *scannedEnvelope) error {
+	env.payloadOffset = s.off
+	for {
+		c, err := s.readByte()
+		if err != nil {
+			return err
+		}
+		switch c {
+		case '"':
+			env.payloadLen = s.off - 1 - env.payloadOffset
+			return nil
+		case '=':
+			env.payloadPad++
+		case '\\':
+			return errors.New("payload has an escaped character, which base64 never needs")
+		}
+	}
+}
+
+// scanEnvelope reads the DSSE envelope in r without its payload.
+func scanEnvelope(r io.Reader) (*scannedEnvelope, error) {
+	s := &envelopeScanner{r: bufio.NewReader(r)}
+	env := &scannedEnvelope{payloadOffset: -1}
+	if err := s.expect('{'); err != nil {
+		return nil, err
+	}
+	for {
+		c, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+		if c == '}' {
+			break
+		}
+		if c != '"' {
+			return nil, fmt.Errorf("unexpected %q at offset %d, expected a field name", c, s.off-1)
+		}
+		rawKey, err := s.value(c)
+		if err != nil {
+			return nil, err
+		}
+		var key string
+		if err := json.Unmarshal(rawKey, &key); err != nil {
+			return nil, err
+		}
+		if err := s.expect(':'); err != nil {
+			return nil, err
+		}
+		if key == "payload" {
+			if err := s.expect('"'); err != nil {
+				return nil, err
+			}
+			if err := s.payload(env); err != nil {
+				return nil, err
+			}
+		} else {
+			c, err := s.next()
+			if err != nil {
+				return nil, err
+			}
+			raw, err := s.value(c)
+			if err != nil {
+				return nil, err
+			}
+			switch key {
+			case "payloadType":
+				err = json.Unmarshal(raw, &env.payloadType)
+			case "signatures":
+				err = limits.Unmarshal(raw, &env.signatures)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", key, err)
+			}
+		}
+		c, err = s.next()
+		if err != nil {
+			return nil, err
+		}
+		if c == '}' {
+			break
+		}
+		if c != ',' {
+			return nil, fmt.Errorf("unexpected %q at offset %d, expected ',' or '}'", c, s.off-1)
+		}
+	}
+	if env.payloadOffset < 0 {
+		return nil, errors.New("envelope has no payload")
+	}
+	if env.payloadLen%4 != 0 || env.payloadPad > 2 {
+		return nil, errors.New("payload is not padded standard base64")
+	}
+	return env, nil
+}
+
+// hashPayload returns the SHA-256 digest of the DSSE pre-authentication
+// encoding of the base64 payload read from r, and its in-toto statement
+// without the predicate.
+func hashPayload(r io.Reader, env *scannedEnvelope) ([]byte, *in_toto.StatementHeader, error) {
+	n := env.payloadLen/4*3 - int64(env.payloadPad)
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "DSSEv1 %d %s %d ", len(env.payloadType), env.payloadType, n)
+
+	counted := &countingWriter{w: hasher}
+	payload := io.TeeReader(base64.NewDecoder(base64.StdEncoding, r), counted)
+	st, err := scanStatement(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing in-toto statement: %w", err)
+	}
+	// Hash what is left after the statement.
+	if _, err := io.Copy(io.Discard, payload); err != nil {
+		return nil, nil, err
+	}
+	if counted.n != n {
+		return nil, nil, fmt.Errorf("payload is %d bytes, expected %d", counted.n, n)
+	}
+	return hasher.Sum(nil), st, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// scanStatement reads the in-toto statement from r, keeping its subject and
+// predicate type and skipping the rest, such as the predicate, token by
+// token.
+func scanStatement(r io.Reader) (*in_toto.StatementHeader, error) {
+	maxDepth, err := limits.JSONDepth()
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("statement is not a JSON object")
+	}
+	st := &in_toto.StatementHeader{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		switch key {
+		case "subject":
+			err = dec.Decode(&st.Subject)
+		case "predicateType":
+			err = dec.Decode(&st.PredicateType)
+		case "_type":
+			err = dec.Decode(&st.Type)
+		default:
+			err = skipValue(dec, maxDepth)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", key, err)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// skipValue reads the next JSON value from dec, which may nest up to
+// maxDepth levels.
+func skipValue(dec *json.Decoder, maxDepth int) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > maxDepth {
+				return fmt.Errorf("JSON nests deeper than %d levels: %w", maxDepth, limits.ErrExceeded)
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
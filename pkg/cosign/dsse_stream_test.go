@@ -0,0 +1,130 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	ssldsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/sigstore/sigstore/pkg/signature"
+
+	"github.com/sigstore/cosign/v2/pkg/types"
+)
+
+func TestVerifyBlobAttestationStream(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("ab", 32)}
+	statement := fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://cosign.sigstore.dev/attestation/v1",`+
+		`"subject":[{"name":"blob","digest":{"sha256":%q}}],"predicate":{"data":[1,{"two":[3]}],"n":1e400}}`, h.Hex)
+	sig, err := sv.SignMessage(bytes.NewReader(ssldsse.PAE(types.IntotoPayloadType, []byte(statement))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := base64.StdEncoding.EncodeToString([]byte(statement))
+	signatures := fmt.Sprintf(`[{"keyid":"","sig":%q}]`, base64.StdEncoding.EncodeToString(sig))
+
+	tests := []struct {
+		name     string
+		envelope string
+		h        v1.Hash
+		so       StreamOpts
+		wantErr  bool
+	}{{
+		name:     "verified",
+		envelope: fmt.Sprintf(`{"payloadType":%q,"payload":%q,"signatures":%s}`, types.IntotoPayloadType, payload, signatures),
+		h:        h,
+		so:       StreamOpts{CheckClaims: true, PredicateType: "https://cosign.sigstore.dev/attestation/v1"},
+	}, {
+		name:     "payload type after the payload",
+		envelope: fmt.Sprintf("{\n  \"payload\": %q,\n  \"signatures\": %s,\n  \"payloadType\": %q\n}\n", payload, signatures, types.IntotoPayloadType),
+		h:        h,
+		so:       StreamOpts{CheckClaims: true},
+	}, {
+		name:     "tampered payload",
+		envelope: fmt.Sprintf(`{"payloadType":%q,"payload":%q,"signatures":%s}`, types.IntotoPayloadType, base64.StdEncoding.EncodeToString([]byte(statement+" ")), signatures),
+		h:        h,
+		wantErr:  true,
+	}, {
+		name:     "other subject",
+		envelope: fmt.Sprintf(`{"payloadType":%q,"payload":%q,"signatures":%s}`, types.IntotoPayloadType, payload, signatures),
+		h:        v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("cd", 32)},
+		so:       StreamOpts{CheckClaims: true},
+		wantErr:  true,
+	}, {
+		name:     "other predicate type",
+		envelope: fmt.Sprintf(`{"payloadType":%q,"payload":%q,"signatures":%s}`, types.IntotoPayloadType, payload, signatures),
+		h:        h,
+		so:       StreamOpts{PredicateType: "https://slsa.dev/provenance/v0.2"},
+		wantErr:  true,
+	}, {
+		name:     "other payload type",
+		envelope: fmt.Sprintf(`{"payloadType":"text/plain","payload":%q,"signatures":%s}`, payload, signatures),
+		h:        h,
+		wantErr:  true,
+	}, {
+		name:     "no payload",
+		envelope: fmt.Sprintf(`{"payloadType":%q,"signatures":%s}`, types.IntotoPayloadType, signatures),
+		h:        h,
+		wantErr:  true,
+	}, {
+		name:     "truncated",
+		envelope: fmt.Sprintf(`{"payloadType":%q,"payload":%q`, types.IntotoPayloadType, payload),
+		h:        h,
+		wantErr:  true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			co := &CheckOpts{SigVerifier: sv, IgnoreTlog: true}
+			err := VerifyBlobAttestationStream(context.Background(), strings.NewReader(tt.envelope), tt.h, tt.so, co)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyBlobAttestationStream() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyBlobAttestationStreamEd25519(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadED25519SignerVerifier(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	co := &CheckOpts{SigVerifier: sv, IgnoreTlog: true}
+	if err := VerifyBlobAttestationStream(context.Background(), strings.NewReader("{}"), v1.Hash{}, StreamOpts{}, co); err == nil || !strings.Contains(err.Error(), "Ed25519") {
+		t.Errorf("VerifyBlobAttestationStream() with an Ed25519 key = %v, want an error", err)
+	}
+}
@@ -0,0 +1,156 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+// EncryptionRecipientsAnnotation is the signed payload annotation listing, as
+// comma-separated KeyFingerprint values, the public keys that the signer
+// attests can decrypt the layers of an image encrypted with OCIcrypt.
+const EncryptionRecipientsAnnotation = "dev.sigstore.cosign/encryption-recipients"
+
+const (
+	// The media types of OCIcrypt layers are those of the plain layers with
+	// this suffix, and the wrapped layer keys are in annotations with this
+	// prefix and the name of the key wrapping scheme, e.g. jwe or pkcs7.
+	encryptedMediaTypeSuffix       = "+encrypted"
+	encryptionKeysAnnotationPrefix = "org.opencontainers.image.enc.keys."
+)
+
+// CheckEncryptedManifest returns an error unless every layer of the image
+// manifest m is encrypted with OCIcrypt, with its key wrapped for at least
+// one recipient. Nothing is decrypted.
+func CheckEncryptedManifest(m *v1.Manifest) error {
+	if len(m.Layers) == 0 {
+		return newTypedVerificationError(ErrImageNotEncryptedType, "%s: the image has no layers", ErrImageNotEncryptedMessage)
+	}
+	for _, l := range m.Layers {
+		if !strings.HasSuffix(string(l.MediaType), encryptedMediaTypeSuffix) {
+			return newTypedVerificationError(ErrImageNotEncryptedType, "%s: layer %s has media type %s", ErrImageNotEncryptedMessage, l.Digest, l.MediaType)
+		}
+		wrapped := false
+		for k := range l.Annotations {
+			wrapped = wrapped || strings.HasPrefix(k, encryptionKeysAnnotationPrefix)
+		}
+		if !wrapped {
+			return newTypedVerificationError(ErrImageNotEncryptedType, "%s: layer %s has no wrapped keys", ErrImageNotEncryptedMessage, l.Digest)
+		}
+	}
+	return nil
+}
+
+// CheckEncryptedImage checks the manifest of se, or of each image of se if it
+// is an image index, with CheckEncryptedManifest.
+func CheckEncryptedImage(se oci.SignedEntity) error {
+	switch e := se.(type) {
+	case oci.SignedImage:
+		m, err := e.Manifest()
+		if err != nil {
+			return err
+		}
+		return CheckEncryptedManifest(m)
+	case oci.SignedImageIndex:
+		im, err := e.IndexManifest()
+		if err != nil {
+			return err
+		}
+		for _, desc := range im.Manifests {
+			var child oci.SignedEntity
+			if desc.MediaType.IsIndex() {
+				child, err = e.SignedImageIndex(desc.Digest)
+			} else {
+				child, err = e.SignedImage(desc.Digest)
+			}
+			if err != nil {
+				return err
+			}
+			if err := CheckEncryptedImage(child); err != nil {
+				return fmt.Errorf("%s: %w", desc.Digest, err)
+			}
+		}
+		return nil
+	default:
+		return newTypedVerificationError(ErrImageNotEncryptedType, "%s: not an image", ErrImageNotEncryptedMessage)
+	}
+}
+
+// EncryptionRecipient returns the KeyFingerprint of the recipient of an
+// encrypted image, a fingerprint or a PEM-encoded public key or certificate
+// file.
+func EncryptionRecipient(recipient string) (string, error) {
+	if fp := strings.ToLower(recipient); strings.HasPrefix(fp, "sha256:") {
+		return fp, nil
+	}
+	b, err := os.ReadFile(filepath.Clean(recipient))
+	if err != nil {
+		return "", fmt.Errorf("reading encryption recipient: %w", err)
+	}
+	if block, _ := pem.Decode(b); block != nil && block.Type == string(cryptoutils.CertificatePEMType) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("parsing encryption recipient %s: %w", recipient, err)
+		}
+		return KeyFingerprint(cert.PublicKey)
+	}
+	pub, err := cryptoutils.UnmarshalPEMToPublicKey(b)
+	if err != nil {
+		return "", fmt.Errorf("parsing encryption recipient %s: %w", recipient, err)
+	}
+	return KeyFingerprint(pub)
+}
+
+// checkEncryptionRecipients returns an error unless the
+// EncryptionRecipientsAnnotation of the signed payload of sig lists each of
+// recipients.
+func checkEncryptionRecipients(sig oci.Signature, recipients []string) error {
+	p, err := sig.Payload()
+	if err != nil {
+		return err
+	}
+	ss := &payload.SimpleContainerImage{}
+	if err := limits.Unmarshal(p, ss); err != nil {
+		return errors.New("only simple signing payloads can list encryption recipients")
+	}
+	v := ss.Optional[EncryptionRecipientsAnnotation]
+	s, ok := v.(string)
+	if v != nil && !ok {
+		return fmt.Errorf("invalid %s annotation: %v", EncryptionRecipientsAnnotation, v)
+	}
+	listed := map[string]bool{}
+	for _, fp := range strings.Split(s, ",") {
+		listed[strings.ToLower(strings.TrimSpace(fp))] = true
+	}
+	for _, r := range recipients {
+		if !listed[r] {
+			return newTypedVerificationError(ErrRecipientMismatchType, "%s: %s", ErrRecipientMismatchMessage, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sigstore/cosign/v2/pkg/oci/signed"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+func TestCheckEncryptedManifest(t *testing.T) {
+	encrypted := v1.Descriptor{
+		MediaType:   types.MediaType("application/vnd.oci.image.layer.v1.tar+gzip+encrypted"),
+		Annotations: map[string]string{"org.opencontainers.image.enc.keys.jwe": "ZXlK"},
+	}
+	tests := map[string]struct {
+		layers  []v1.Descriptor
+		wantErr bool
+	}{
+		"encrypted":          {layers: []v1.Descriptor{encrypted, encrypted}},
+		"no layers":          {wantErr: true},
+		"plain layer":        {layers: []v1.Descriptor{encrypted, {MediaType: types.OCILayer}}, wantErr: true},
+		"without a key":      {layers: []v1.Descriptor{{MediaType: encrypted.MediaType}}, wantErr: true},
+		"without a key list": {layers: []v1.Descriptor{{MediaType: encrypted.MediaType, Annotations: map[string]string{"org.opencontainers.image.title": "a"}}}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckEncryptedManifest(&v1.Manifest{Layers: tt.layers})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckEncryptedManifest() = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrImageNotEncrypted) {
+				t.Errorf("CheckEncryptedManifest() = %v, want %v", err, ErrImageNotEncrypted)
+			}
+		})
+	}
+
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckEncryptedImage(signed.Image(img)); !errors.Is(err, ErrImageNotEncrypted) {
+		t.Errorf("CheckEncryptedImage() of a plain image = %v, want %v", err, ErrImageNotEncrypted)
+	}
+	idx, err := random.Index(100, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckEncryptedImage(signed.ImageIndex(idx)); !errors.Is(err, ErrImageNotEncrypted) {
+		t.Errorf("CheckEncryptedImage() of a plain index = %v, want %v", err, ErrImageNotEncrypted)
+	}
+}
+
+func TestEncryptionRecipient(t *testing.T) {
+	td := t.TempDir()
+	cert, key, _ := test.GenerateRootCa()
+	want, err := KeyFingerprint(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, err := cryptoutils.MarshalCertificateToPEM(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPEM, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(name string, b []byte) string {
+		p := filepath.Join(td, name)
+		if err := os.WriteFile(p, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	for _, recipient := range []string{write("cert.pem", certPEM), write("key.pub", pubPEM), strings.ToUpper(want)} {
+		if got, err := EncryptionRecipient(recipient); err != nil || got != want {
+			t.Errorf("EncryptionRecipient(%s) = %q, %v, want %q", recipient, got, err, want)
+		}
+	}
+	if _, err := EncryptionRecipient(write("garbage", []byte("not a key"))); err == nil {
+		t.Error("EncryptionRecipient() of a file without a key: expected an error")
+	}
+}
+
+func TestEncryptionRecipients(t *testing.T) {
+	sv := newDenylistTestSigner(t)
+	h := v1.Hash{Algorithm: "sha256", Hex: "2f534bfedaf0bc95c6e0d3e4ac4b2473f27af72d4cbe2d23bd20186f5cd5ca8b"}
+	d, err := name.NewDigest("example.com/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, bob := "sha256:"+strings.Repeat("a", 64), "sha256:"+strings.Repeat("b", 64)
+
+	verify := func(annotations map[string]interface{}, recipients ...string) error {
+		t.Helper()
+		p, err := payload.Cosign{Image: d, Annotations: annotations}.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := sv.SignMessage(bytes.NewReader(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ociSig, err := static.NewSignature(p, base64.StdEncoding.EncodeToString(sig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = VerifyImageSignature(context.Background(), ociSig, h, &CheckOpts{SigVerifier: sv, IgnoreTlog: true, EncryptionRecipients: recipients})
+		return err
+	}
+
+	both := map[string]interface{}{EncryptionRecipientsAnnotation: alice + "," + bob}
+	if err := verify(both, alice, bob); err != nil {
+		t.Errorf("signature listing both recipients: unexpected error %v", err)
+	}
+	if err := verify(map[string]interface{}{EncryptionRecipientsAnnotation: alice}, alice, bob); !errors.Is(err, ErrRecipientMismatch) {
+		t.Errorf("signature listing another recipient: error = %v, want %v", err, ErrRecipientMismatch)
+	}
+	if err := verify(nil, alice); !errors.Is(err, ErrRecipientMismatch) {
+		t.Errorf("signature without recipients: error = %v, want %v", err, ErrRecipientMismatch)
+	}
+	if err := verify(map[string]interface{}{EncryptionRecipientsAnnotation: 42}, alice); err == nil {
+		t.Error("invalid recipients: expected an error")
+	}
+	if err := verify(nil); err != nil {
+		t.Errorf("no recipients required: unexpected error %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxTypoDistance is the largest edit distance between an unknown variable
+// and the known one it is suggested for.
+const maxTypoDistance = 2
+
+// Validate returns why val isn't a value of the type of o. Empty values are
+// valid, as they are read as if the variable weren't set.
+func (o VariableOpts) Validate(val string) error {
+	if val == "" {
+		return nil
+	}
+	switch o.ValueType() {
+	case TypeBool:
+		if _, err := strconv.ParseBool(val); err != nil {
+			return errors.New("must be a boolean, such as 1, 0, true or false")
+		}
+	case TypeInt:
+		if n, err := strconv.ParseInt(val, 10, 64); err != nil || n < 0 {
+			return errors.New("must be a non-negative integer")
+		}
+	case TypeURL:
+		if u, err := url.Parse(val); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("must be an absolute URL")
+		}
+	case TypeList:
+		for _, e := range strings.Split(val, ",") {
+			if strings.TrimSpace(e) == "" {
+				return errors.New("must be a comma-separated list without empty elements")
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns warnings, sorted by variable, about the variables of
+// environ, KEY=value pairs as os.Environ returns them: the registered ones
+// that are deprecated or whose values aren't of their type, and the COSIGN_
+// ones that are neither registered nor in known, such as those that set
+// flags. Those are most likely misspelled, and leave a setting, which may be
+// a security check, at its default.
+func Check(environ []string, known map[string]bool) []string {
+	candidates := []string{}
+	for v := range environmentVariables {
+		if strings.HasPrefix(v.String(), "COSIGN_") {
+			candidates = append(candidates, v.String())
+		}
+	}
+	for k := range known {
+		candidates = append(candidates, k)
+	}
+	sort.Strings(candidates)
+
+	names := map[string]string{}
+	for _, kv := range environ {
+		k, v, _ := strings.Cut(kv, "=")
+		names[k] = v
+	}
+	keys := make([]string, 0, len(names))
+	for k := range names {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var warnings []string
+	for _, k := range keys {
+		if opts, ok := environmentVariables[Variable(k)]; ok {
+			if opts.Deprecated != "" {
+				warnings = append(warnings, fmt.Sprintf("%s is deprecated: %s", k, opts.Deprecated))
+			}
+			if err := opts.Validate(names[k]); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s is invalid: it %v", k, err))
+			}
+			continue
+		}
+		if !strings.HasPrefix(k, "COSIGN_") || known[k] {
+			continue
+		}
+		w := fmt.Sprintf("unknown environment variable %s, which cosign doesn't read", k)
+		if s := closest(k, candidates); s != "" {
+			w += fmt.Sprintf(", did you mean %s?", s)
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// closest returns the candidate nearest to name, if within maxTypoDistance.
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", maxTypoDistance+1
+	for _, c := range candidates {
+		if d := distance(name, c); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < cur[j] {
+				cur[j] = d
+			}
+			if d := cur[j-1] + 1; d < cur[j] {
+				cur[j] = d
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,85 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		typ     Type
+		val     string
+		wantErr bool
+	}{
+		{typ: TypeBool, val: "1"},
+		{typ: TypeBool, val: "false"},
+		{typ: TypeBool, val: "yes", wantErr: true},
+		{typ: TypeInt, val: "1024"},
+		{typ: TypeInt, val: "-1", wantErr: true},
+		{typ: TypeInt, val: "1MB", wantErr: true},
+		{typ: TypeURL, val: "https://github.example.com"},
+		{typ: TypeURL, val: "github.example.com", wantErr: true},
+		{typ: TypeList, val: "a=true,b=false"},
+		{typ: TypeList, val: "a=true,", wantErr: true},
+		{typ: TypePath, val: "/tmp"},
+		{typ: "", val: "anything"},
+		{typ: TypeInt, val: ""},
+	}
+	for _, tt := range tests {
+		err := VariableOpts{Type: tt.typ}.Validate(tt.val)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%s %q) = %v, wantErr %t", tt.typ, tt.val, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	saveEnvs := environmentVariables
+	t.Cleanup(func() { environmentVariables = saveEnvs })
+	environmentVariables = map[Variable]VariableOpts{
+		"COSIGN_EXPERIMENTAL": {Type: TypeBool},
+		"COSIGN_OLD":          {Deprecated: "use COSIGN_EXPERIMENTAL"},
+	}
+	got := Check([]string{
+		"COSIGN_EXPERIMENTAL=maybe",
+		"COSIGN_OLD=1",
+		"COSIGN_EXPERIMENTL=1",
+		"COSIGN_TLOG_UPLOAD=false",
+		"COSIGN_TLOG_UPLAOD=false",
+		"COSIGN_SOMETHING_ELSE=1",
+		"HOME=/root",
+	}, map[string]bool{"COSIGN_TLOG_UPLOAD": true})
+	want := []string{
+		"COSIGN_EXPERIMENTAL is invalid: it must be a boolean, such as 1, 0, true or false",
+		"unknown environment variable COSIGN_EXPERIMENTL, which cosign doesn't read, did you mean COSIGN_EXPERIMENTAL?",
+		"COSIGN_OLD is deprecated: use COSIGN_EXPERIMENTAL",
+		"unknown environment variable COSIGN_SOMETHING_ELSE, which cosign doesn't read",
+		"unknown environment variable COSIGN_TLOG_UPLAOD, which cosign doesn't read, did you mean COSIGN_TLOG_UPLOAD?",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() = %q, want %q", got, want)
+	}
+}
+
+func TestRegisteredTypes(t *testing.T) {
+	for v, opts := range EnvironmentVariables() {
+		if err := opts.Validate(opts.Default); err != nil {
+			t.Errorf("the default of %s %v", v, err)
+		}
+	}
+}
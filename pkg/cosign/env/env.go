@@ -24,12 +24,38 @@ import (
 // Variable is a type representing an environment variable
 type Variable string
 
+// Type is the type of the value of a Variable, which Check validates.
+type Type string
+
+const (
+	// TypeString is any string, the type of variables registered without
+	// one.
+	TypeString Type = "string"
+	// TypeBool is a boolean, as parsed by strconv.ParseBool.
+	TypeBool Type = "bool"
+	// TypeInt is a non-negative integer.
+	TypeInt Type = "int"
+	// TypePath is a path to a file or directory.
+	TypePath Type = "path"
+	// TypeURL is an absolute URL.
+	TypeURL Type = "url"
+	// TypeList is a comma-separated list.
+	TypeList Type = "list"
+)
+
 // VariableOpts closely describes a Variable
 type VariableOpts struct {
 	// Description contains description for the environment variable
 	Description string
 	// Expects describes what value is expected by the environment variable
 	Expects string
+	// Type is the type of the value, TypeString if empty
+	Type Type
+	// Default is the value used when the variable isn't set, if any
+	Default string
+	// Deprecated, if set, says what replaces the variable, which is still
+	// read but warned about
+	Deprecated string
 	// Sensitive is used for environment variables with sensitive values
 	// (e.g. passwords, credentials, etc.)
 	Sensitive bool
@@ -44,12 +70,40 @@ func (v Variable) String() string {
 
 const (
 	// Cosign environment variables
-	VariableExperimental     Variable = "COSIGN_EXPERIMENTAL"
-	VariableDockerMediaTypes Variable = "COSIGN_DOCKER_MEDIA_TYPES"
-	VariablePassword         Variable = "COSIGN_PASSWORD"
-	VariablePKCS11Pin        Variable = "COSIGN_PKCS11_PIN"
-	VariablePKCS11ModulePath Variable = "COSIGN_PKCS11_MODULE_PATH"
-	VariableRepository       Variable = "COSIGN_REPOSITORY"
+	VariableExperimental      Variable = "COSIGN_EXPERIMENTAL"
+	VariableFeatureGates      Variable = "COSIGN_FEATURE_GATES"
+	VariableFeatureGatesFile  Variable = "COSIGN_FEATURE_GATES_FILE"
+	VariableDockerMediaTypes  Variable = "COSIGN_DOCKER_MEDIA_TYPES"
+	VariablePassword          Variable = "COSIGN_PASSWORD"
+	VariablePKCS11Pin         Variable = "COSIGN_PKCS11_PIN"
+	VariablePKCS11ModulePath  Variable = "COSIGN_PKCS11_MODULE_PATH"
+	VariablePKCS12Password    Variable = "COSIGN_PKCS12_PASSWORD"
+	VariableSigningToken      Variable = "COSIGN_SIGNING_SERVER_TOKEN" //nolint:gosec
+	VariableRepository        Variable = "COSIGN_REPOSITORY"
+	VariableCredentialHelpers Variable = "COSIGN_REGISTRY_CREDENTIAL_HELPERS"
+	VariableLocale            Variable = "COSIGN_LOCALE"
+	VariableDenylist          Variable = "COSIGN_DENYLIST"
+	VariableDenylistKey       Variable = "COSIGN_DENYLIST_KEY"
+	VariableSBOMGenerator     Variable = "COSIGN_SBOM_GENERATOR"
+	VariableScanner           Variable = "COSIGN_SCANNER"
+	VariablePredicateSchemas  Variable = "COSIGN_PREDICATE_SCHEMAS"
+	VariablePredicatesDir     Variable = "COSIGN_PREDICATES_DIR"
+	VariableMaxPayloadSize    Variable = "COSIGN_MAX_PAYLOAD_SIZE"
+	VariableMaxAnnotationSize Variable = "COSIGN_MAX_ANNOTATION_SIZE"
+	VariableMaxLayers         Variable = "COSIGN_MAX_LAYERS"
+	VariableMaxJSONDepth      Variable = "COSIGN_MAX_JSON_DEPTH"
+
+	VariableRegistryMaxManifestSize Variable = "COSIGN_REGISTRY_MAX_MANIFEST_SIZE"
+	VariableRegistryMaxBlobSize     Variable = "COSIGN_REGISTRY_MAX_BLOB_SIZE"
+	VariableBudgetFile              Variable = "COSIGN_BUDGET_FILE"
+	VariableProfile                 Variable = "COSIGN_PROFILE"
+	VariableProfilesFile            Variable = "COSIGN_PROFILES_FILE"
+	VariableDaemonSocket            Variable = "COSIGN_DAEMON_SOCKET"
+	VariableStore                   Variable = "COSIGN_STORE"
+	VariableTUFSystemRoot           Variable = "COSIGN_TUF_SYSTEM_ROOT"
+	VariableDevPublicKey            Variable = "COSIGN_DEV_PUBLIC_KEY"
+	VariablePrivateKey              Variable = "COSIGN_PRIVATE_KEY" //nolint:gosec
+	VariablePublicKey               Variable = "COSIGN_PUBLIC_KEY"
 
 	// Sigstore environment variables
 	VariableSigstoreCTLogPublicKeyFile Variable = "SIGSTORE_CT_LOG_PUBLIC_KEY_FILE"
@@ -59,9 +113,11 @@ const (
 
 	// Other external environment variables
 	VariableGitHubHost                Variable = "GITHUB_HOST"
+	VariableGitHubServerURL           Variable = "GITHUB_SERVER_URL"
 	VariableGitHubToken               Variable = "GITHUB_TOKEN" //nolint:gosec
 	VariableGitHubRequestToken        Variable = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
 	VariableGitHubRequestURL          Variable = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	VariableGitHubStepSummary         Variable = "GITHUB_STEP_SUMMARY"
 	VariableSPIFFEEndpointSocket      Variable = "SPIFFE_ENDPOINT_SOCKET"
 	VariableGoogleServiceAccountName  Variable = "GOOGLE_SERVICE_ACCOUNT_NAME"
 	VariableGitLabHost                Variable = "GITLAB_HOST"
@@ -71,6 +127,7 @@ const (
 	VariableBuildkiteJobID            Variable = "BUILDKITE_JOB_ID"
 	VariableBuildkiteAgentLogLevel    Variable = "BUILDKITE_AGENT_LOG_LEVEL"
 	VariableSourceDateEpoch           Variable = "SOURCE_DATE_EPOCH"
+	VariableDockerConfig              Variable = "DOCKER_CONFIG"
 )
 
 var (
@@ -80,11 +137,27 @@ var (
 		VariableExperimental: {
 			Description: "enables experimental cosign features",
 			Expects:     "1 if experimental features should be enabled (0 by default)",
+			Type:        TypeBool,
+			Default:     "0",
+			Sensitive:   false,
+		},
+		VariableFeatureGates: {
+			Description: "enables or disables gated cosign features, overriding the feature gates config file",
+			Expects:     "comma separated list of <feature>=<bool> pairs, listed by cosign env",
+			Type:        TypeList,
+			Sensitive:   false,
+		},
+		VariableFeatureGatesFile: {
+			Description: "is the feature gates config file, of the form {\"featureGates\": {\"<feature>\": <bool>}}",
+			Expects:     "path to a JSON file (cosign/feature-gates.json in the user configuration directory by default)",
+			Type:        TypePath,
 			Sensitive:   false,
 		},
 		VariableDockerMediaTypes: {
 			Description: "to be used with registries that do not support OCI media types",
 			Expects:     "1 to fallback to legacy OCI media types equivalents (0 by default)",
+			Type:        TypeBool,
+			Default:     "0",
 			Sensitive:   false,
 		},
 		VariablePassword: {
@@ -97,9 +170,20 @@ var (
 			Expects:     "string with a PIN",
 			Sensitive:   true,
 		},
+		VariablePKCS12Password: {
+			Description: "is the password of the PKCS #12 bundle imported by cosign import-key-pair",
+			Expects:     "string with a password",
+			Sensitive:   true,
+		},
+		VariableSigningToken: {
+			Description: "is the bearer token that authenticates cosign sign --signing-server to the signing service, and the callers of cosign server sign without --policy",
+			Expects:     "string with a token",
+			Sensitive:   true,
+		},
 		VariablePKCS11ModulePath: {
 			Description: "is PKCS11 module-path",
 			Expects:     "string with a module-path",
+			Type:        TypePath,
 			Sensitive:   false,
 		},
 		VariableRepository: {
@@ -107,22 +191,158 @@ var (
 			Expects:     "string with a repository",
 			Sensitive:   false,
 		},
+		VariableCredentialHelpers: {
+			Description: "are the credential helpers asked for registry credentials before the docker config, when --registry-credential-helper isn't set",
+			Expects:     "comma-separated [REGISTRY=]HELPER of built-in keychains or docker-credential-HELPER programs",
+			Type:        TypeList,
+			Sensitive:   false,
+		},
+		VariableLocale: {
+			Description: "selects the language of human readable messages; machine readable output is never localized",
+			Expects:     "locale such as de or es_ES.UTF-8 (English by default)",
+			Sensitive:   false,
+		},
+		VariableDenylist: {
+			Description: "is the signed denylist of revoked keys, identities and digests consulted by every verify command",
+			Expects:     "path to a denylist file or OCI reference of a denylist artifact",
+			Sensitive:   false,
+		},
+		VariableDenylistKey: {
+			Description: "is the public key that signed the denylist",
+			Expects:     "path to the public key file, KMS URI or Kubernetes Secret",
+			Sensitive:   false,
+		},
+		VariableSBOMGenerator: {
+			Description: "is the SBOM generator plugin used by cosign attest --sbom-from-image",
+			Expects:     "name of a cosign-sbom-<name> executable on the PATH, or path to an executable",
+			Sensitive:   false,
+		},
+		VariableScanner: {
+			Description: "is the vulnerability scanner plugin used by cosign scan attach",
+			Expects:     "name of a cosign-scan-<name> executable on the PATH, or path to an executable",
+			Sensitive:   false,
+		},
+		VariablePredicateSchemas: {
+			Description: "is the registry of JSON schemas that predicates of custom types are validated against when attesting and verifying attestations",
+			Expects:     "path to a predicate schema registry file",
+			Type:        TypePath,
+			Sensitive:   false,
+		},
+		VariablePredicatesDir: {
+			Description: "is the directory of predicate plug-ins, which register custom predicate types and their JSON schemas, used in place of ~/.cosign/predicates",
+			Expects:     "path to a directory of predicate plug-in files",
+			Type:        TypePath,
+			Sensitive:   false,
+		},
+		VariableMaxPayloadSize: {
+			Description: "is the largest signature, attestation or attachment payload read from a registry",
+			Expects:     "number of bytes (134217728 by default)",
+			Type:        TypeInt,
+			Default:     "134217728",
+			Sensitive:   false,
+		},
+		VariableMaxAnnotationSize: {
+			Description: "is the largest certificate, chain, bundle or timestamp annotation of a signature read from a registry",
+			Expects:     "number of bytes (1048576 by default)",
+			Type:        TypeInt,
+			Default:     "1048576",
+			Sensitive:   false,
+		},
+		VariableMaxLayers: {
+			Description: "is the most signatures or attestations read from a single registry manifest",
+			Expects:     "number of layers (1000 by default)",
+			Type:        TypeInt,
+			Default:     "1000",
+			Sensitive:   false,
+		},
+		VariableMaxJSONDepth: {
+			Description: "is the deepest nesting of objects and arrays in JSON read from a registry",
+			Expects:     "number of levels (128 by default)",
+			Type:        TypeInt,
+			Default:     "128",
+			Sensitive:   false,
+		},
+		VariableRegistryMaxManifestSize: {
+			Description: "is the largest manifest pushed to a registry, in place of the limit known for the registry",
+			Expects:     "number of bytes (4194304 for most registries)",
+			Type:        TypeInt,
+			Sensitive:   false,
+		},
+		VariableRegistryMaxBlobSize: {
+			Description: "is the largest signature, attestation or attachment blob pushed to a registry, in place of the limit known for the registry",
+			Expects:     "number of bytes (unlimited for most registries)",
+			Type:        TypeInt,
+			Sensitive:   false,
+		},
+		VariableBudgetFile: {
+			Description: "is the budget config file, which caps the parallel workers of commands and the rate of the requests to registries and Rekor",
+			Expects:     "path to a JSON file (cosign/budget.json in the user configuration directory by default)",
+			Type:        TypePath,
+			Sensitive:   false,
+		},
+		VariableProfile: {
+			Description: "is the profile of the profiles config file whose keys, endpoints, identity constraints, registries and annotations commands use, if --profile isn't set",
+			Expects:     "name of a profile",
+			Sensitive:   false,
+		},
+		VariableProfilesFile: {
+			Description: "is the profiles config file, of the form {\"profiles\": {\"<name>\": {...}}}",
+			Expects:     "path to a JSON file (cosign/profiles.json in the user configuration directory by default)",
+			Type:        TypePath,
+			Sensitive:   false,
+		},
+		VariableDaemonSocket: {
+			Description: "is the Unix socket of the cosign daemon, which keeps KMS clients, OIDC tokens and registry credentials warm for cosign if it is listening",
+			Expects:     "path to a Unix socket",
+			Type:        TypePath,
+			Sensitive:   false,
+		},
+		VariableStore: {
+			Description: "is the local store that the signatures and attestations cosign produces are archived in by the digest of their subject, queried with cosign store query",
+			Expects:     "path to a directory, or 1 for ~/.cosign/store",
+			Sensitive:   false,
+		},
+		VariableTUFSystemRoot: {
+			Description: "is the shared TUF cache that --tuf-cache system reads the trusted keys and certificates from, without writing to it",
+			Expects:     "path to a TUF cache directory, written with TUF_ROOT=<dir> cosign initialize (/var/lib/sigstore/root by default)",
+			Type:        TypePath,
+			Default:     "/var/lib/sigstore/root",
+			Sensitive:   false,
+		},
+		VariableDevPublicKey: {
+			Description: "is the public key of the ephemeral key pair of cosign dev sign, as it prints it to be verified with --key env://COSIGN_DEV_PUBLIC_KEY",
+			Expects:     "PEM-encoded public key",
+			Sensitive:   false,
+		},
+		VariablePrivateKey: {
+			Description: "is the private key that cosign generate-key-pair stores as a GitHub or GitLab secret, read with --key env://COSIGN_PRIVATE_KEY",
+			Expects:     "PEM-encoded encrypted private key",
+			Sensitive:   true,
+		},
+		VariablePublicKey: {
+			Description: "is the public key that cosign generate-key-pair stores as a GitHub or GitLab secret, read with --key env://COSIGN_PUBLIC_KEY",
+			Expects:     "PEM-encoded public key",
+			Sensitive:   false,
+		},
 
 		VariableSigstoreCTLogPublicKeyFile: {
 			Description: "overrides what is used to validate the SCT coming back from Fulcio",
 			Expects:     "path to the public key file",
+			Type:        TypePath,
 			Sensitive:   false,
 			External:    true,
 		},
 		VariableSigstoreRootFile: {
 			Description: "overrides the public good instance root CA",
 			Expects:     "path to the root CA",
+			Type:        TypePath,
 			Sensitive:   false,
 			External:    true,
 		},
 		VariableSigstoreRekorPublicKey: {
 			Description: "if specified, you can specify an oob Public Key that Rekor uses",
 			Expects:     "path to the public key",
+			Type:        TypePath,
 			Sensitive:   false,
 			External:    true,
 		},
@@ -133,6 +353,20 @@ var (
 			Sensitive:   false,
 			External:    true,
 		},
+		VariableGitHubStepSummary: {
+			Description: "is the job summary file of the step of a GitHub Actions workflow run, set by the runner, that --github-summary appends to",
+			Expects:     "path to a Markdown file",
+			Type:        TypePath,
+			Sensitive:   false,
+			External:    true,
+		},
+		VariableGitHubServerURL: {
+			Description: "is the URL of the GitHub server of a GitHub Actions workflow run, set by the runner",
+			Expects:     "string with the URL of the GitHub server",
+			Type:        TypeURL,
+			Sensitive:   false,
+			External:    true,
+		},
 		VariableGitHubToken: {
 			Description: "is a token used to authenticate with GitHub",
 			Expects:     "token generated on GitHub",
@@ -148,6 +382,7 @@ var (
 		VariableGitHubRequestURL: {
 			Description: "is the URL for GitHub's OIDC provider",
 			Expects:     "string with the URL for the OIDC provider",
+			Type:        TypeURL,
 			Sensitive:   false,
 			External:    true,
 		},
@@ -208,6 +443,15 @@ var (
 		VariableSourceDateEpoch: {
 			Description: "overrides current time for reproducible builds, see https://reproducible-builds.org/docs/source-date-epoch/",
 			Expects:     "number of seconds since unix epoch",
+			Type:        TypeInt,
+			Sensitive:   false,
+			External:    true,
+		},
+		VariableDockerConfig: {
+			Description: "is the directory of the Docker client configuration that registry credentials are stored in",
+			Expects:     "path to a directory (~/.docker by default)",
+			Type:        TypePath,
+			Default:     "~/.docker",
 			Sensitive:   false,
 			External:    true,
 		},
@@ -218,6 +462,14 @@ func EnvironmentVariables() map[Variable]VariableOpts {
 	return environmentVariables
 }
 
+// ValueType returns the type of the value of the variable.
+func (o VariableOpts) ValueType() Type {
+	if o.Type == "" {
+		return TypeString
+	}
+	return o.Type
+}
+
 func mustRegisterEnv(name Variable) {
 	opts, ok := environmentVariables[name]
 	if !ok {
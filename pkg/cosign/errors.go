@@ -14,7 +14,11 @@
 
 package cosign
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 var (
 	// NoMatchingAttestations
@@ -32,6 +36,102 @@ var (
 	// NoSignaturesFound
 	ErrNoSignaturesFoundType    = "NoSignaturesFound"
 	ErrNoSignaturesFoundMessage = "no signatures found for image"
+
+	// IdentityMismatch
+	ErrIdentityMismatchType    = "IdentityMismatch"
+	ErrIdentityMismatchMessage = "none of the expected identities matched what was in the certificate"
+
+	// CertificateExtensionMismatch
+	ErrCertExtensionMismatchType    = "CertificateExtensionMismatch"
+	ErrCertExtensionMismatchMessage = "expected certificate extension not found in certificate"
+
+	// CertificateExpired
+	ErrCertificateExpiredType    = "CertificateExpired"
+	ErrCertificateExpiredMessage = "certificate was not valid at signing time"
+
+	// NoCertificateFound
+	ErrNoCertificateFoundType    = "NoCertificateFound"
+	ErrNoCertificateFoundMessage = "no certificate found on signature"
+
+	// MissingSCT
+	ErrMissingSCTType    = "MissingSCT"
+	ErrMissingSCTMessage = "certificate does not include required embedded SCT and no detached SCT was set"
+
+	// TlogMissing
+	ErrTlogMissingType    = "TlogEntryMissing"
+	ErrTlogMissingMessage = "no valid transparency log entry found"
+
+	// TlogKeyNotFound
+	ErrTlogKeyNotFoundType    = "TlogPublicKeyNotFound"
+	ErrTlogKeyNotFoundMessage = "rekor log public key not found for payload"
+
+	// TlogKeyNotValid
+	ErrTlogKeyNotValidType    = "TlogPublicKeyNotValid"
+	ErrTlogKeyNotValidMessage = "transparency log entry was integrated outside the validity period of the log public key"
+
+	// BundleMismatch
+	ErrBundleMismatchType    = "BundleMismatch"
+	ErrBundleMismatchMessage = "signature in bundle does not match signature being verified"
+
+	// InvalidSET
+	ErrInvalidSETType    = "InvalidSignedEntryTimestamp"
+	ErrInvalidSETMessage = "unable to verify SET"
+
+	// MissingTimestamp
+	ErrMissingTimestampType    = "MissingSignedTimestamp"
+	ErrMissingTimestampMessage = "expected a signed timestamp to verify an expired certificate"
+
+	// InvalidPayloadType
+	ErrInvalidPayloadTypeType    = "InvalidPayloadType"
+	ErrInvalidPayloadTypeMessage = "invalid payloadType on envelope"
+
+	// Denied
+	ErrDeniedType    = "Denied"
+	ErrDeniedMessage = "signature matches an entry in the denylist"
+
+	// KeyUsage
+	ErrKeyUsageType    = "KeyUsage"
+	ErrKeyUsageMessage = "signer isn't allowed to make the signature by the key usage policy"
+
+	// SignatureExpired
+	ErrSignatureExpiredType    = "SignatureExpired"
+	ErrSignatureExpiredMessage = "signature has expired"
+
+	// ImageNotEncrypted
+	ErrImageNotEncryptedType    = "ImageNotEncrypted"
+	ErrImageNotEncryptedMessage = "image is not encrypted"
+
+	// RecipientMismatch
+	ErrRecipientMismatchType    = "RecipientMismatch"
+	ErrRecipientMismatchMessage = "signature doesn't list the encryption recipient"
+)
+
+// Sentinel errors for use with errors.Is. Any *VerificationError (or typed
+// error wrapping one) with the same error type matches its sentinel, e.g.
+//
+//	if errors.Is(err, cosign.ErrNoMatchingSignatures) { ... }
+var (
+	ErrNoMatchingAttestations error = &VerificationError{ErrNoMatchingAttestationsType, ErrNoMatchingAttestationsMessage}
+	ErrNoMatchingSignatures   error = &VerificationError{ErrNoMatchingSignaturesType, ErrNoMatchingSignaturesMessage}
+	ErrImageTagNotFound       error = &VerificationError{ErrImageTagNotFoundType, ErrImageTagNotFoundMessage}
+	ErrNoSignaturesFound      error = &VerificationError{ErrNoSignaturesFoundType, ErrNoSignaturesFoundMessage}
+	ErrIdentityMismatch       error = &VerificationError{ErrIdentityMismatchType, ErrIdentityMismatchMessage}
+	ErrCertExtensionMismatch  error = &VerificationError{ErrCertExtensionMismatchType, ErrCertExtensionMismatchMessage}
+	ErrCertificateExpired     error = &VerificationError{ErrCertificateExpiredType, ErrCertificateExpiredMessage}
+	ErrNoCertificateFound     error = &VerificationError{ErrNoCertificateFoundType, ErrNoCertificateFoundMessage}
+	ErrMissingSCT             error = &VerificationError{ErrMissingSCTType, ErrMissingSCTMessage}
+	ErrTlogMissing            error = &VerificationError{ErrTlogMissingType, ErrTlogMissingMessage}
+	ErrTlogKeyNotFound        error = &VerificationError{ErrTlogKeyNotFoundType, ErrTlogKeyNotFoundMessage}
+	ErrTlogKeyNotValid        error = &VerificationError{ErrTlogKeyNotValidType, ErrTlogKeyNotValidMessage}
+	ErrBundleMismatch         error = &VerificationError{ErrBundleMismatchType, ErrBundleMismatchMessage}
+	ErrInvalidSET             error = &VerificationError{ErrInvalidSETType, ErrInvalidSETMessage}
+	ErrMissingTimestamp       error = &VerificationError{ErrMissingTimestampType, ErrMissingTimestampMessage}
+	ErrInvalidPayloadType     error = &VerificationError{ErrInvalidPayloadTypeType, ErrInvalidPayloadTypeMessage}
+	ErrDenied                 error = &VerificationError{ErrDeniedType, ErrDeniedMessage}
+	ErrKeyUsage               error = &VerificationError{ErrKeyUsageType, ErrKeyUsageMessage}
+	ErrSignatureExpired       error = &VerificationError{ErrSignatureExpiredType, ErrSignatureExpiredMessage}
+	ErrImageNotEncrypted      error = &VerificationError{ErrImageNotEncryptedType, ErrImageNotEncryptedMessage}
+	ErrRecipientMismatch      error = &VerificationError{ErrRecipientMismatchType, ErrRecipientMismatchMessage}
 )
 
 // VerificationError is the type of Go error that is used by cosign to surface
@@ -50,6 +150,15 @@ func NewVerificationError(msg string, args ...interface{}) error {
 	}
 }
 
+// newTypedVerificationError constructs a new VerificationError of the given
+// type in a manner similar to fmt.Errorf
+func newTypedVerificationError(errorType, msg string, args ...interface{}) *VerificationError {
+	return &VerificationError{
+		errorType: errorType,
+		message:   fmt.Sprintf(msg, args...),
+	}
+}
+
 // Assert that we implement error at build time.
 var _ error = (*VerificationError)(nil)
 
@@ -66,3 +175,106 @@ func (ve *VerificationError) ErrorType() string {
 func (ve *VerificationError) SetErrorType(errorType string) {
 	ve.errorType = errorType
 }
+
+// Is reports whether target is a VerificationError of the same (non-empty)
+// error type, which makes the sentinel errors above usable with errors.Is.
+func (ve *VerificationError) Is(target error) bool {
+	t, ok := target.(*VerificationError)
+	if !ok || t.errorType == "" {
+		return false
+	}
+	return ve.errorType == t.errorType
+}
+
+// IdentityMismatchError is returned when a certificate matches none of the
+// identities in CheckOpts.Identities.
+type IdentityMismatchError struct {
+	*VerificationError
+	// Expected are the identities that were accepted.
+	Expected []Identity
+	// Subjects are the subject alternative names found in the certificate.
+	Subjects []string
+	// Issuer is the OIDC issuer found in the certificate.
+	Issuer string
+}
+
+func newIdentityMismatchError(expected []Identity, subjects []string, issuer string) *IdentityMismatchError {
+	return &IdentityMismatchError{
+		VerificationError: newTypedVerificationError(ErrIdentityMismatchType, "%s, got subjects [%s] with issuer %s",
+			ErrIdentityMismatchMessage, strings.Join(subjects, ", "), issuer),
+		Expected: expected,
+		Subjects: subjects,
+		Issuer:   issuer,
+	}
+}
+
+// Unwrap allows errors.As to find the underlying *VerificationError.
+func (e *IdentityMismatchError) Unwrap() error {
+	return e.VerificationError
+}
+
+// CertExtensionMismatchError is returned when a certificate extension does
+// not hold the expected value.
+type CertExtensionMismatchError struct {
+	*VerificationError
+	// Extension is the human readable name of the extension, e.g. "GitHub Workflow SHA".
+	Extension string
+	// Expected is the value that was required.
+	Expected string
+	// Actual is the value found in the certificate, empty if absent.
+	Actual string
+}
+
+func newCertExtensionMismatchError(extension, expected, actual string) *CertExtensionMismatchError {
+	return &CertExtensionMismatchError{
+		VerificationError: newTypedVerificationError(ErrCertExtensionMismatchType, "expected %s not found in certificate", extension),
+		Extension:         extension,
+		Expected:          expected,
+		Actual:            actual,
+	}
+}
+
+// Unwrap allows errors.As to find the underlying *VerificationError.
+func (e *CertExtensionMismatchError) Unwrap() error {
+	return e.VerificationError
+}
+
+// CertificateExpiredError is returned when a certificate was not valid at
+// the time a signature was (provably) created.
+type CertificateExpiredError struct {
+	*VerificationError
+	NotBefore time.Time
+	NotAfter  time.Time
+	// At is the time the certificate was checked against.
+	At time.Time
+}
+
+// Unwrap allows errors.As to find the underlying *VerificationError.
+func (e *CertificateExpiredError) Unwrap() error {
+	return e.VerificationError
+}
+
+// NoMatchingSignaturesError is returned when none of the signatures, or
+// attestations, found for an artifact passed verification. It carries the
+// reason each candidate was rejected.
+type NoMatchingSignaturesError struct {
+	*VerificationError
+	// Errors holds the reason each candidate failed verification.
+	Errors []error
+}
+
+func newNoMatchingSignaturesError(errorType, message string, errs []error) *NoMatchingSignaturesError {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return &NoMatchingSignaturesError{
+		VerificationError: newTypedVerificationError(errorType, "%s:\n%s", message, strings.Join(msgs, "\n ")),
+		Errors:            errs,
+	}
+}
+
+// Unwrap allows errors.As to find the underlying *VerificationError.
+func (e *NoMatchingSignaturesError) Unwrap() error {
+	return e.VerificationError
+}
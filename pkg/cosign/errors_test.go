@@ -43,3 +43,47 @@ func TestErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestSentinelErrors(t *testing.T) {
+	identityErr := newIdentityMismatchError([]Identity{{Subject: "foo@example.com"}}, []string{"bar@example.com"}, "https://issuer.example.com")
+	noMatchErr := newNoMatchingSignaturesError(ErrNoMatchingSignaturesType, ErrNoMatchingSignaturesMessage, []error{identityErr})
+
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+		is       bool
+	}{
+		{name: "identity mismatch", err: identityErr, sentinel: ErrIdentityMismatch, is: true},
+		{name: "identity mismatch is not tlog missing", err: identityErr, sentinel: ErrTlogMissing, is: false},
+		{name: "no matching signatures", err: noMatchErr, sentinel: ErrNoMatchingSignatures, is: true},
+		{name: "no matching signatures is not no matching attestations", err: noMatchErr, sentinel: ErrNoMatchingAttestations, is: false},
+		{name: "untyped error", err: NewVerificationError("untyped"), sentinel: ErrIdentityMismatch, is: false},
+		{name: "plain error", err: errors.New("no signatures found for image"), sentinel: ErrNoSignaturesFound, is: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("wrapper: %w", tc.err)
+			if got := errors.Is(wrapped, tc.sentinel); got != tc.is {
+				t.Errorf("errors.Is(%v, %v) = %t, want %t", wrapped, tc.sentinel, got, tc.is)
+			}
+		})
+	}
+
+	var ime *IdentityMismatchError
+	if !errors.As(fmt.Errorf("wrapper: %w", identityErr), &ime) {
+		t.Fatalf("%v is not a %T", identityErr, ime)
+	}
+	if ime.Issuer != "https://issuer.example.com" || len(ime.Subjects) != 1 || ime.Subjects[0] != "bar@example.com" {
+		t.Errorf("unexpected context on %T: %+v", ime, ime)
+	}
+
+	var ve *VerificationError
+	if !errors.As(noMatchErr, &ve) || ve.ErrorType() != ErrNoMatchingSignaturesType {
+		t.Errorf("%v does not unwrap to a %T of type %s", noMatchErr, ve, ErrNoMatchingSignaturesType)
+	}
+	var nms *NoMatchingSignaturesError
+	if !errors.As(noMatchErr, &nms) || len(nms.Errors) != 1 || !errors.Is(nms.Errors[0], ErrIdentityMismatch) {
+		t.Errorf("%v does not carry the per-signature errors", noMatchErr)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+// ExpiresAnnotation is the signed payload annotation holding the RFC 3339
+// time after which a signature should no longer be accepted.
+const ExpiresAnnotation = "dev.sigstore.cosign/expires"
+
+// ParseExpiryDuration parses a signature lifetime such as 90d, 12h or 1h30m.
+// In addition to the units of time.ParseDuration, it accepts a whole number
+// of days.
+func ParseExpiryDuration(s string) (time.Duration, error) {
+	var d time.Duration
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid expiry duration %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid expiry duration %q", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("expiry duration %q must be positive", s)
+	}
+	return d, nil
+}
+
+// checkExpiresAnnotation returns an error if the signed payload of sig has an
+// ExpiresAnnotation before now. Signatures without one never expire.
+func checkExpiresAnnotation(sig oci.Signature, now time.Time) error {
+	p, err := sig.Payload()
+	if err != nil {
+		return err
+	}
+	ss := &payload.SimpleContainerImage{}
+	if err := limits.Unmarshal(p, ss); err != nil {
+		// Not a simple signing payload, e.g. an attestation.
+		return nil
+	}
+	v, ok := ss.Optional[ExpiresAnnotation]
+	if !ok {
+		return nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("invalid %s annotation: %v", ExpiresAnnotation, v)
+	}
+	expires, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", ExpiresAnnotation, err)
+	}
+	if now.After(expires) {
+		return newTypedVerificationError(ErrSignatureExpiredType, "%s: signature expired at %s", ErrSignatureExpiredMessage, expires.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+func TestParseExpiryDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "90d", want: 90 * 24 * time.Hour},
+		{in: "12h", want: 12 * time.Hour},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "0d", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "1.5d", wantErr: true},
+		{in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseExpiryDuration(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseExpiryDuration(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseExpiryDuration(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEnforceExpiry(t *testing.T) {
+	sv := newDenylistTestSigner(t)
+	h := v1.Hash{Algorithm: "sha256", Hex: "2f534bfedaf0bc95c6e0d3e4ac4b2473f27af72d4cbe2d23bd20186f5cd5ca8b"}
+	d, err := name.NewDigest("example.com/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func(annotations map[string]interface{}) error {
+		t.Helper()
+		p, err := payload.Cosign{Image: d, Annotations: annotations}.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := sv.SignMessage(bytes.NewReader(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ociSig, err := static.NewSignature(p, base64.StdEncoding.EncodeToString(sig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = VerifyImageSignature(context.Background(), ociSig, h, &CheckOpts{SigVerifier: sv, IgnoreTlog: true, EnforceExpiry: true})
+		return err
+	}
+
+	if err := verify(nil); err != nil {
+		t.Errorf("signature without expiry: unexpected error %v", err)
+	}
+	if err := verify(map[string]interface{}{ExpiresAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339)}); err != nil {
+		t.Errorf("unexpired signature: unexpected error %v", err)
+	}
+	if err := verify(map[string]interface{}{ExpiresAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339)}); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("expired signature: error = %v, want %v", err, ErrSignatureExpired)
+	}
+	if err := verify(map[string]interface{}{ExpiresAnnotation: "tomorrow"}); err == nil {
+		t.Error("invalid expiry: expected an error")
+	}
+}
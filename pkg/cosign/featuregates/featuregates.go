@@ -0,0 +1,290 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package featuregates gates features that are still being rolled out. A
+// gate is set, in order of precedence, with the --feature-gates flag,
+// $COSIGN_FEATURE_GATES, the feature gates config file or, for alpha and beta
+// gates, COSIGN_EXPERIMENTAL=1, and is otherwise at its default.
+package featuregates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// Feature is the name of a feature gate.
+type Feature string
+
+// Stage is the maturity of a gated feature.
+type Stage string
+
+const (
+	// Alpha features are disabled by default and may change or go away.
+	Alpha Stage = "alpha"
+	// Beta features are complete but may still change.
+	Beta Stage = "beta"
+	// GA features are stable, and their gates are kept until they're removed
+	// so that setting them isn't an error.
+	GA Stage = "ga"
+)
+
+// Spec describes a feature gate.
+type Spec struct {
+	Description string
+	Stage       Stage
+	Default     bool
+}
+
+const (
+	// OCI11Referrers allows storing signatures, attestations and SBOMs as OCI
+	// 1.1 referrers with --registry-referrers-mode=oci-1-1, and signing OCI
+	// artifacts with sign-blob --oci.
+	OCI11Referrers Feature = "OCI11Referrers"
+	// RekorV2 allows uploading to Rekor v2 transparency logs selected by a
+	// signing config.
+	RekorV2 Feature = "RekorV2"
+	// LayerSignatures allows signing the layers and config of images with
+	// sign --layers, and requiring their signatures with verify
+	// --layer-policy.
+	LayerSignatures Feature = "LayerSignatures"
+)
+
+var specs = map[Feature]Spec{
+	OCI11Referrers: {
+		Description: "allows storing signatures, attestations and SBOMs as OCI 1.1 referrers with --registry-referrers-mode=oci-1-1, and signing OCI artifacts with sign-blob --oci",
+		Stage:       Alpha,
+	},
+	RekorV2: {
+		Description: "allows uploading to Rekor v2 transparency logs selected with --signing-config",
+		Stage:       Alpha,
+	},
+	LayerSignatures: {
+		Description: "allows signing the layers and config of images with sign --layers, and requiring their signatures with verify --layer-policy",
+		Stage:       Alpha,
+	},
+}
+
+// Source is where the state of a feature gate was set.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceConfig  Source = "config"
+	SourceFlag    Source = "--feature-gates"
+
+	SourceExperimental = Source(env.VariableExperimental)
+	SourceEnv          = Source(env.VariableFeatureGates)
+)
+
+// Gate is the state of a feature gate.
+type Gate struct {
+	Feature Feature
+	Spec
+	Enabled bool
+	Source  Source
+}
+
+// ConfigFile is the format of the feature gates config file.
+type ConfigFile struct {
+	FeatureGates map[Feature]bool `json:"featureGates"`
+}
+
+var (
+	flagMu    sync.Mutex
+	flagGates map[Feature]bool
+)
+
+// Specs returns the known feature gates.
+func Specs() map[Feature]Spec {
+	out := make(map[Feature]Spec, len(specs))
+	for f, s := range specs {
+		out[f] = s
+	}
+	return out
+}
+
+// Parse parses a comma separated list of <feature>=<bool> pairs.
+func Parse(s string) (map[Feature]bool, error) {
+	gates := map[Feature]bool{}
+	for _, kv := range strings.Split(s, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("feature gate %q is not of the form <feature>=<bool>", kv)
+		}
+		f := Feature(strings.TrimSpace(k))
+		if _, ok := specs[f]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %q, must be one of %s", f, strings.Join(names(), ", "))
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("feature gate %s: %w", f, err)
+		}
+		gates[f] = b
+	}
+	return gates, nil
+}
+
+// SetFromFlag sets the feature gates given with the --feature-gates flag,
+// which override all other sources.
+func SetFromFlag(s string) error {
+	gates, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	flagMu.Lock()
+	defer flagMu.Unlock()
+	if flagGates == nil {
+		flagGates = map[Feature]bool{}
+	}
+	for f, b := range gates {
+		flagGates[f] = b
+	}
+	return nil
+}
+
+// ResetFlag clears the feature gates set with SetFromFlag, so that a process
+// running several commands, like cosign daemon, starts each without them.
+func ResetFlag() {
+	flagMu.Lock()
+	defer flagMu.Unlock()
+	flagGates = nil
+}
+
+// Gates returns the state of all feature gates, sorted by name.
+func Gates() ([]Gate, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	fromEnv, err := Parse(env.Getenv(env.VariableFeatureGates))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", env.VariableFeatureGates, err)
+	}
+	experimental, _ := strconv.ParseBool(env.Getenv(env.VariableExperimental))
+
+	flagMu.Lock()
+	defer flagMu.Unlock()
+	gates := make([]Gate, 0, len(specs))
+	for _, n := range names() {
+		f := Feature(n)
+		g := Gate{Feature: f, Spec: specs[f], Enabled: specs[f].Default, Source: SourceDefault}
+		if experimental && g.Stage != GA {
+			g.Enabled, g.Source = true, SourceExperimental
+		}
+		if b, ok := config[f]; ok {
+			g.Enabled, g.Source = b, SourceConfig
+		}
+		if b, ok := fromEnv[f]; ok {
+			g.Enabled, g.Source = b, SourceEnv
+		}
+		if b, ok := flagGates[f]; ok {
+			g.Enabled, g.Source = b, SourceFlag
+		}
+		gates = append(gates, g)
+	}
+	return gates, nil
+}
+
+// Enabled reports whether feature f is enabled. Feature gates that can't be
+// read, e.g. because of a malformed $COSIGN_FEATURE_GATES, leave f at its
+// default; Gates returns the error.
+func Enabled(f Feature) bool {
+	gates, err := Gates()
+	if err != nil {
+		return specs[f].Default
+	}
+	for _, g := range gates {
+		if g.Feature == f {
+			return g.Enabled
+		}
+	}
+	return false
+}
+
+// Require returns an error if feature f is disabled.
+func Require(f Feature) error {
+	if _, err := Gates(); err != nil {
+		return err
+	}
+	if Enabled(f) {
+		return nil
+	}
+	return fmt.Errorf("the %s feature gate is disabled, enable it with --feature-gates=%s=true, %s or %s=1", f, f, env.VariableFeatureGates, env.VariableExperimental)
+}
+
+// ConfigPath returns the path of the feature gates config file, which is
+// $COSIGN_FEATURE_GATES_FILE or cosign/feature-gates.json in the user's
+// configuration directory.
+func ConfigPath() string {
+	if path := env.Getenv(env.VariableFeatureGatesFile); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cosign", "feature-gates.json")
+}
+
+// loadConfig reads the feature gates config file. A missing config file at
+// the default path sets no gates.
+func loadConfig() (map[Feature]bool, error) {
+	path := ConfigPath()
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && env.Getenv(env.VariableFeatureGatesFile) == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading feature gates config: %w", err)
+	}
+	config := ConfigFile{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&config); err != nil {
+		return nil, fmt.Errorf("parsing feature gates config %s: %w", path, err)
+	}
+	for f := range config.FeatureGates {
+		if _, ok := specs[f]; !ok {
+			return nil, fmt.Errorf("feature gates config %s: unknown feature gate %q", path, f)
+		}
+	}
+	return config.FeatureGates, nil
+}
+
+func names() []string {
+	out := make([]string, 0, len(specs))
+	for f := range specs {
+		out = append(out, string(f))
+	}
+	sort.Strings(out)
+	return out
+}
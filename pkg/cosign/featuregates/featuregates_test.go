@@ -0,0 +1,131 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featuregates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse(" OCI11Referrers = true ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got[OCI11Referrers] {
+		t.Errorf("Parse() = %v, want OCI11Referrers enabled", got)
+	}
+	for _, s := range []string{"OCI11Referrers", "OCI11Referrers=maybe", "Unknown=true"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestGatesPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "feature-gates.json")
+	if err := os.WriteFile(config, []byte(`{"featureGates": {"OCI11Referrers": false}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { flagGates = nil })
+
+	for _, tt := range []struct {
+		name         string
+		experimental string
+		configFile   string
+		envGates     string
+		flag         string
+		want         bool
+		wantSource   Source
+	}{{
+		name:       "default",
+		wantSource: SourceDefault,
+	}, {
+		name:         "experimental",
+		experimental: "1",
+		want:         true,
+		wantSource:   SourceExperimental,
+	}, {
+		name:         "config overrides experimental",
+		experimental: "1",
+		configFile:   config,
+		wantSource:   SourceConfig,
+	}, {
+		name:       "env overrides config",
+		configFile: config,
+		envGates:   "OCI11Referrers=true",
+		want:       true,
+		wantSource: SourceEnv,
+	}, {
+		name:       "flag overrides env",
+		envGates:   "OCI11Referrers=true",
+		flag:       "OCI11Referrers=false",
+		wantSource: SourceFlag,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			flagGates = nil
+			t.Setenv(env.VariableExperimental.String(), tt.experimental)
+			t.Setenv(env.VariableFeatureGates.String(), tt.envGates)
+			t.Setenv(env.VariableFeatureGatesFile.String(), tt.configFile)
+			// Without a config file, the default one in the empty user
+			// configuration directory is missing.
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			t.Setenv("HOME", dir)
+			if tt.flag != "" {
+				if err := SetFromFlag(tt.flag); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			gates, err := Gates()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var g Gate
+			for _, g = range gates {
+				if g.Feature == OCI11Referrers {
+					break
+				}
+			}
+			if g.Feature != OCI11Referrers || g.Enabled != tt.want || g.Source != tt.wantSource {
+				t.Errorf("Gates() = %+v, want OCI11Referrers enabled %t from %s", gates, tt.want, tt.wantSource)
+			}
+			if got := Enabled(OCI11Referrers); got != tt.want {
+				t.Errorf("Enabled() = %t, want %t", got, tt.want)
+			}
+			if err := Require(OCI11Referrers); (err == nil) != tt.want {
+				t.Errorf("Require() = %v, want enabled %t", err, tt.want)
+			} else if err != nil && !strings.Contains(err.Error(), "--feature-gates=OCI11Referrers=true") {
+				t.Errorf("Require() = %v, want a hint to the flag", err)
+			}
+		})
+	}
+}
+
+func TestGatesMissingConfig(t *testing.T) {
+	t.Setenv(env.VariableFeatureGatesFile.String(), filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := Gates(); err == nil {
+		t.Fatal("Gates() succeeded with a missing config file")
+	}
+	if Enabled(OCI11Referrers) {
+		t.Error("Enabled() = true with a missing config file, want the default")
+	}
+}
@@ -26,7 +26,10 @@ import (
 	"sync"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
 	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"golang.org/x/sync/errgroup"
@@ -45,6 +48,9 @@ type LocalSignedPayload struct {
 	Base64Signature string              `json:"base64Signature"`
 	Cert            string              `json:"cert,omitempty"`
 	Bundle          *bundle.RekorBundle `json:"rekorBundle,omitempty"`
+	// TransparencyLogEntry is the entry of a Rekor v2 log, which has no
+	// Bundle.
+	TransparencyLogEntry *rekorv2.Entry `json:"transparencyLogEntry,omitempty"`
 }
 
 type Signatures struct {
@@ -84,7 +90,7 @@ func FetchSignaturesForReference(_ context.Context, ref name.Reference, opts ...
 
 	signatures := make([]SignedPayload, len(l))
 	var g errgroup.Group
-	g.SetLimit(runtime.NumCPU())
+	g.SetLimit(budget.Workers(0, runtime.NumCPU()))
 	for i, sig := range l {
 		i, sig := i, sig
 		g.Go(func() error {
@@ -131,6 +137,13 @@ func FetchAttestationsForReference(_ context.Context, ref name.Reference, predic
 }
 
 func FetchAttestations(se oci.SignedEntity, predicateType string) ([]AttestationPayload, error) {
+	return FetchAttestationsMatching(se, predicateType, nil)
+}
+
+// FetchAttestationsMatching is FetchAttestations of only the attestations for
+// which match, if not nil, returns true. It is up to the caller to report
+// that none matched.
+func FetchAttestationsMatching(se oci.SignedEntity, predicateType string, match func(oci.Signature) (bool, error)) ([]AttestationPayload, error) {
 	atts, err := se.Attestations()
 	if err != nil {
 		return nil, fmt.Errorf("remote image: %w", err)
@@ -147,7 +160,7 @@ func FetchAttestations(se oci.SignedEntity, predicateType string) ([]Attestation
 	var attMu sync.Mutex
 
 	var g errgroup.Group
-	g.SetLimit(runtime.NumCPU())
+	g.SetLimit(budget.Workers(0, runtime.NumCPU()))
 
 	for _, att := range l {
 		if predicateType != "" {
@@ -161,11 +174,20 @@ func FetchAttestations(se oci.SignedEntity, predicateType string) ([]Attestation
 				continue
 			}
 		}
+		if match != nil {
+			ok, err := match(att)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
 		att := att
 		var a AttestationPayload
 		g.Go(func() error {
 			attestPayload, _ := att.Payload()
-			err := json.Unmarshal(attestPayload, &a)
+			err := limits.Unmarshal(attestPayload, &a)
 			if err != nil {
 				return err
 			}
@@ -179,7 +201,7 @@ func FetchAttestations(se oci.SignedEntity, predicateType string) ([]Attestation
 		return nil, err
 	}
 
-	if len(attestations) == 0 && predicateType != "" {
+	if len(attestations) == 0 && predicateType != "" && match == nil {
 		return nil, fmt.Errorf("no attestations with predicate type '%s' found", predicateType)
 	}
 
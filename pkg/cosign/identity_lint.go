@@ -0,0 +1,160 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// broadIdentityProbes are unrelated identities and issuers. A regular
+// expression that matches all of them constrains nothing.
+var broadIdentityProbes = []string{
+	"x",
+	"attacker@evil.example",
+	"https://evil.example/attacker/.github/workflows/release.yml@refs/heads/main",
+}
+
+// foreignIdentityProbes are identities and issuers of the shapes of those
+// that are trusted, an email address, a workflow URI and an issuer URL, but
+// of another domain, host or organization. A regular expression that matches
+// one of them has a wildcard where the domain, host or organization should
+// be, e.g. ^.*@.*$ or ^https://.*$.
+var foreignIdentityProbes = []string{
+	"attacker@evil.example",
+	"https://evil.example/attacker/app/.github/workflows/release.yml@refs/heads/main",
+	"https://github.com/attacker/app/.github/workflows/release.yml@refs/heads/main",
+	"https://evil.example",
+}
+
+// IdentityRegexpProblems returns what makes the certificate identity or issuer
+// regular expression re dangerous: accepting any value, or the values of
+// other domains, hosts or organizations, not being anchored
+// with ^ and $, so that it also matches values that only contain a match, or
+// an unescaped . in a literal, which matches any character, e.g. in
+// ^https://github\.com/example/ that matches https://githubXcom/example/.
+func IdentityRegexpProblems(re string) ([]string, error) {
+	compiled, err := regexp.Compile(re)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", re, err)
+	}
+	parsed, err := syntax.Parse(re, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", re, err)
+	}
+
+	broad := true
+	for _, p := range broadIdentityProbes {
+		if !compiled.MatchString(p) {
+			broad = false
+			break
+		}
+	}
+	// The other problems don't matter if any value is accepted.
+	if broad {
+		return []string{"accepts any value, so it doesn't constrain who signed"}, nil
+	}
+	var problems []string
+	for _, p := range foreignIdentityProbes {
+		if compiled.MatchString(p) {
+			problems = append(problems, fmt.Sprintf("accepts identities of any domain or organization, e.g. %s, so it barely constrains who signed; match the domain, host and organization literally", p))
+			break
+		}
+	}
+	if !anchored(parsed) {
+		problems = append(problems, "isn't anchored with ^ and $, so it also matches any value that contains a match")
+	}
+	if hasLiteralDot(parsed, false) {
+		problems = append(problems, `has an unescaped . that matches any character, e.g. in a domain name; escape it as \.`)
+	}
+	return problems, nil
+}
+
+// anchored reports whether every alternative of re starts at the beginning
+// and ends at the end of the text.
+func anchored(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpCapture:
+		return anchored(re.Sub[0])
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !anchored(sub) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpConcat:
+		return len(re.Sub) > 1 && startsAnchored(re.Sub[0]) && endsAnchored(re.Sub[len(re.Sub)-1])
+	default:
+		return false
+	}
+}
+
+func startsAnchored(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpCapture {
+		return startsAnchored(re.Sub[0])
+	}
+	return re.Op == syntax.OpBeginText || re.Op == syntax.OpBeginLine
+}
+
+func endsAnchored(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpCapture {
+		return endsAnchored(re.Sub[0])
+	}
+	return re.Op == syntax.OpEndText || re.Op == syntax.OpEndLine
+}
+
+// hasLiteralDot reports whether re has a . that isn't repeated, the usual
+// mistake for \. in a literal. Repeated dots, as in .* or .+, are
+// intentional wildcards.
+func hasLiteralDot(re *syntax.Regexp, repeated bool) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return !repeated
+	case syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+		repeated = true
+	}
+	for _, sub := range re.Sub {
+		if hasLiteralDot(sub, repeated) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckIdentityStrict returns an error unless the regular expressions of id,
+// if any, are free of the problems of IdentityRegexpProblems, for verifying
+// with exact or narrowly anchored identities only.
+func CheckIdentityStrict(id Identity) error {
+	for _, c := range []struct{ name, re string }{
+		{"certificate identity", id.SubjectRegExp},
+		{"certificate OIDC issuer", id.IssuerRegExp},
+	} {
+		if c.re == "" {
+			continue
+		}
+		problems, err := IdentityRegexpProblems(c.re)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("the %s regular expression %q %s", c.name, c.re, strings.Join(problems, ", and "))
+		}
+	}
+	return nil
+}
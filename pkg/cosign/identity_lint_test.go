@@ -0,0 +1,83 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIdentityRegexpProblems(t *testing.T) {
+	for _, tt := range []struct {
+		re   string
+		want []string
+	}{
+		{re: `^https://github\.com/example/app/\.github/workflows/release\.yml@refs/heads/main$`},
+		{re: `^https://github\.com/example/.*$`},
+		{re: `^(user|admin)@example\.com$`},
+		{re: `^user@example\.com$|^admin@example\.com$`},
+		{re: `.*`, want: []string{"accepts any value"}},
+		{re: `^.+$`, want: []string{"accepts any value"}},
+		{re: ``, want: []string{"accepts any value"}},
+		{re: `^.*@.*$`, want: []string{"any domain or organization"}},
+		{re: `^https://.*$`, want: []string{"any domain or organization"}},
+		{re: `^https://github\.com/.+$`, want: []string{"any domain or organization"}},
+		{re: `^.*@example\.com$`},
+		{re: `@example\.com$`, want: []string{"isn't anchored"}},
+		{re: `^https://github\.com/example/`, want: []string{"isn't anchored"}},
+		{re: `^user@example\.com$|admin@example\.com`, want: []string{"isn't anchored"}},
+		{re: `^https://github.com/example/.*$`, want: []string{"unescaped ."}},
+		{re: `github.com/example`, want: []string{"isn't anchored", "unescaped ."}},
+	} {
+		got, err := IdentityRegexpProblems(tt.re)
+		if err != nil {
+			t.Fatalf("IdentityRegexpProblems(%q) = %v", tt.re, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("IdentityRegexpProblems(%q) = %q, want %q", tt.re, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !strings.Contains(got[i], tt.want[i]) {
+				t.Errorf("IdentityRegexpProblems(%q)[%d] = %q, want %q", tt.re, i, got[i], tt.want[i])
+			}
+		}
+	}
+	if _, err := IdentityRegexpProblems(`(`); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
+
+func TestCheckIdentityStrict(t *testing.T) {
+	for _, id := range []Identity{
+		{Subject: "user@example.com", Issuer: "https://accounts.google.com"},
+		{SubjectRegExp: `^https://github\.com/example/.*$`, Issuer: "https://token.actions.githubusercontent.com"},
+	} {
+		if err := CheckIdentityStrict(id); err != nil {
+			t.Errorf("CheckIdentityStrict(%+v) = %v", id, err)
+		}
+	}
+	for _, id := range []Identity{
+		{SubjectRegExp: ".*", Issuer: "https://accounts.google.com"},
+		{SubjectRegExp: `^.*@.*$`, Issuer: "https://accounts.google.com"},
+		{SubjectRegExp: `^https://.*$`, Issuer: "https://token.actions.githubusercontent.com"},
+		{Subject: "user@example.com", IssuerRegExp: `accounts\.google\.com`},
+	} {
+		if err := CheckIdentityStrict(id); err == nil {
+			t.Errorf("CheckIdentityStrict(%+v): expected an error", id)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/tink/go/kwp/subtle"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"golang.org/x/crypto/pkcs12"
+)
+
+// Formats of the private keys read by ImportPrivateKey.
+const (
+	// ImportFormatPEM is a PEM encoded PKCS #1, SEC 1 or PKCS #8 private key.
+	ImportFormatPEM = "pem"
+	// ImportFormatPKCS12 is a PKCS #12 bundle, such as a .p12 or .pfx file,
+	// with a single private key.
+	ImportFormatPKCS12 = "pkcs12"
+	// ImportFormatAWSDataKeyPair is the JSON output of
+	// `aws kms generate-data-key-pair`, with the plaintext private key.
+	ImportFormatAWSDataKeyPair = "aws-data-key-pair"
+	// ImportFormatRSAAESKeyWrap is a PKCS #8 private key wrapped with the
+	// RSA AES key wrap scheme (CKM_RSA_AES_KEY_WRAP) of the key imports of
+	// AWS and GCP KMS: an AES key encrypted with RSA-OAEP, followed by the
+	// private key wrapped with that AES key (RFC 5649), raw or base64 encoded.
+	ImportFormatRSAAESKeyWrap = "rsa-aes-key-wrap"
+)
+
+// ImportFormats are the formats read by ImportPrivateKey.
+var ImportFormats = []string{ImportFormatPEM, ImportFormatPKCS12, ImportFormatAWSDataKeyPair, ImportFormatRSAAESKeyWrap}
+
+// ImportOpts are the options of ImportPrivateKey.
+type ImportOpts struct {
+	// Format is one of ImportFormats, detected from the key if empty.
+	Format string
+	// PKCS12Password returns the password of a PKCS #12 bundle, which is
+	// only asked for if the bundle isn't encrypted with an empty password.
+	PKCS12Password PassFunc
+	// WrappingKey is the RSA private key that unwraps rsa-aes-key-wrap key
+	// material.
+	WrappingKey *rsa.PrivateKey
+	// WrappingHash is the hash of the RSA-OAEP encryption of rsa-aes-key-wrap
+	// key material, crypto.SHA256 if zero.
+	WrappingHash crypto.Hash
+}
+
+// awsDataKeyPair is the output of `aws kms generate-data-key-pair`.
+type awsDataKeyPair struct {
+	KeyID                    string `json:"KeyId"`
+	KeyPairSpec              string `json:"KeyPairSpec"`
+	PrivateKeyPlaintext      []byte `json:"PrivateKeyPlaintext"`
+	PrivateKeyCiphertextBlob []byte `json:"PrivateKeyCiphertextBlob"`
+	PublicKey                []byte `json:"PublicKey"`
+}
+
+// ImportPrivateKey reads the private key at keyPath, in the format of opts,
+// to be encrypted into a cosign key pair by EncryptImportedKey.
+func ImportPrivateKey(keyPath string, opts ImportOpts) (crypto.Signer, error) {
+	raw, err := os.ReadFile(filepath.Clean(keyPath))
+	if err != nil {
+		return nil, err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = detectImportFormat(raw, opts)
+	}
+	switch format {
+	case ImportFormatPEM:
+		return parsePEMPrivateKey(raw)
+	case ImportFormatPKCS12:
+		return parsePKCS12PrivateKey(raw, opts.PKCS12Password)
+	case ImportFormatAWSDataKeyPair:
+		return parseAWSDataKeyPair(raw)
+	case ImportFormatRSAAESKeyWrap:
+		return unwrapRSAAESKeyWrap(raw, opts)
+	}
+	return nil, fmt.Errorf("unsupported key format %q, expected one of %s", format, strings.Join(ImportFormats, ", "))
+}
+
+// EncryptImportedKey encrypts pk, with the password returned by pf, into a
+// cosign key pair.
+func EncryptImportedKey(pk crypto.Signer, pf PassFunc) (*KeysBytes, error) {
+	return marshalKeyPair(SigstorePrivateKeyPemType, Keys{pk, pk.Public()}, pf)
+}
+
+// KeyDescription describes the type of pub, e.g. "ECDSA P-256" or "RSA 4096".
+func KeyDescription(pub crypto.PublicKey) string {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		return "ECDSA " + k.Curve.Params().Name
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", k.N.BitLen())
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return fmt.Sprintf("%T", pub)
+}
+
+func detectImportFormat(raw []byte, opts ImportOpts) string {
+	trimmed := bytes.TrimSpace(raw)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("-----BEGIN")):
+		return ImportFormatPEM
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return ImportFormatAWSDataKeyPair
+	case opts.WrappingKey != nil:
+		return ImportFormatRSAAESKeyWrap
+	}
+	return ImportFormatPKCS12
+}
+
+// validateImportedKey returns pk, a private key parsed from a format other
+// than PEM, if it is a key cosign signs with.
+func validateImportedKey(pk interface{}) (crypto.Signer, error) {
+	switch k := pk.(type) {
+	case *rsa.PrivateKey:
+		if err := cryptoutils.ValidatePubKey(k.Public()); err != nil {
+			return nil, fmt.Errorf("error validating rsa key: %w", err)
+		}
+		return k, nil
+	case *ecdsa.PrivateKey:
+		if err := cryptoutils.ValidatePubKey(k.Public()); err != nil {
+			return nil, fmt.Errorf("error validating ecdsa key: %w", err)
+		}
+		return k, nil
+	case ed25519.PrivateKey:
+		if err := cryptoutils.ValidatePubKey(k.Public()); err != nil {
+			return nil, fmt.Errorf("error validating ed25519 key: %w", err)
+		}
+		return k, nil
+	}
+	return nil, fmt.Errorf("unexpected private key %T", pk)
+}
+
+func parsePKCS12PrivateKey(raw []byte, pf PassFunc) (crypto.Signer, error) {
+	blocks, err := pkcs12.ToPEM(raw, "")
+	if errors.Is(err, pkcs12.ErrIncorrectPassword) && pf != nil {
+		var password []byte
+		if password, err = pf(false); err != nil {
+			return nil, err
+		}
+		blocks, err = pkcs12.ToPEM(raw, string(password))
+	}
+	if err != nil {
+		var notImplemented pkcs12.NotImplementedError
+		if errors.As(err, &notImplemented) {
+			return nil, fmt.Errorf("reading PKCS #12 bundle: %w, re-encrypt bundles encrypted with AES, the default of OpenSSL 3, with openssl pkcs12 -legacy", err)
+		}
+		return nil, fmt.Errorf("reading PKCS #12 bundle: %w", err)
+	}
+
+	var keys []*pem.Block
+	for _, b := range blocks {
+		if b.Type == "PRIVATE KEY" {
+			keys = append(keys, b)
+		}
+	}
+	if len(keys) != 1 {
+		return nil, fmt.Errorf("PKCS #12 bundle has %d private keys, expected 1", len(keys))
+	}
+	// The private keys of ToPEM are PKCS #1 or SEC 1 encoded, despite their
+	// PEM type.
+	der := keys[0].Bytes
+	if pk, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return validateImportedKey(pk)
+	}
+	if pk, err := x509.ParseECPrivateKey(der); err == nil {
+		return validateImportedKey(pk)
+	}
+	pk, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the private key of the PKCS #12 bundle: %w", err)
+	}
+	return validateImportedKey(pk)
+}
+
+func parseAWSDataKeyPair(raw []byte) (crypto.Signer, error) {
+	kp := awsDataKeyPair{}
+	if err := json.Unmarshal(raw, &kp); err != nil {
+		return nil, fmt.Errorf("parsing AWS KMS data key pair: %w", err)
+	}
+	if len(kp.PrivateKeyPlaintext) == 0 {
+		if len(kp.PrivateKeyCiphertextBlob) != 0 {
+			return nil, errors.New("the AWS KMS data key pair has no plaintext private key, decrypt its PrivateKeyCiphertextBlob with aws kms decrypt and import the PEM encoded result")
+		}
+		return nil, errors.New("the AWS KMS data key pair has no PrivateKeyPlaintext")
+	}
+	pk, err := x509.ParsePKCS8PrivateKey(kp.PrivateKeyPlaintext)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the private key of the AWS KMS data key pair %s: %w", kp.KeyID, err)
+	}
+	signer, err := validateImportedKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	if len(kp.PublicKey) != 0 {
+		pub, err := x509.ParsePKIXPublicKey(kp.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the public key of the AWS KMS data key pair %s: %w", kp.KeyID, err)
+		}
+		if err := cryptoutils.EqualKeys(pub, signer.Public()); err != nil {
+			return nil, fmt.Errorf("the public key of the AWS KMS data key pair %s doesn't match its private key", kp.KeyID)
+		}
+	}
+	return signer, nil
+}
+
+func unwrapRSAAESKeyWrap(raw []byte, opts ImportOpts) (crypto.Signer, error) {
+	if opts.WrappingKey == nil {
+		return nil, errors.New("unwrapping the key material needs the RSA wrapping private key")
+	}
+	wrapped := raw
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(raw))); err == nil {
+		wrapped = decoded
+	}
+	hash := opts.WrappingHash
+	if hash == 0 {
+		hash = crypto.SHA256
+	}
+	if !hash.Available() {
+		return nil, fmt.Errorf("unsupported wrapping hash %v", hash)
+	}
+
+	// The AES key is encrypted to the size of the RSA wrapping key.
+	size := opts.WrappingKey.Size()
+	if len(wrapped) <= size {
+		return nil, fmt.Errorf("wrapped key material of %d bytes is too short for the %d bit wrapping key", len(wrapped), size*8)
+	}
+	aesKey, err := rsa.DecryptOAEP(hash.New(), nil, opts.WrappingKey, wrapped[:size], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting the wrapped AES key, check the wrapping key and hash: %w", err)
+	}
+	kwp, err := subtle.NewKWP(aesKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrapped AES key: %w", err)
+	}
+	der, err := kwp.Unwrap(wrapped[size:])
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping the private key: %w", err)
+	}
+	pk, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the unwrapped private key: %w", err)
+	}
+	return validateImportedKey(pk)
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/kwp/subtle"
+)
+
+func writeImportFile(t *testing.T, b []byte) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(p, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestImportPrivateKeyPKCS12(t *testing.T) {
+	// The bundles have the same P-256 key, encrypted with 3DES with the
+	// password "test", with AES and with an empty password.
+	password := func(bool) ([]byte, error) { return []byte("test"), nil }
+
+	pk, err := ImportPrivateKey("testdata/pkcs12-legacy.p12", ImportOpts{PKCS12Password: password})
+	if err != nil {
+		t.Fatalf("ImportPrivateKey() = %v", err)
+	}
+	if got := KeyDescription(pk.Public()); got != "ECDSA P-256" {
+		t.Errorf("KeyDescription() = %s, want ECDSA P-256", got)
+	}
+	noPassword, err := ImportPrivateKey("testdata/pkcs12-no-password.p12", ImportOpts{Format: ImportFormatPKCS12})
+	if err != nil {
+		t.Fatalf("ImportPrivateKey() without a password = %v", err)
+	}
+	if !noPassword.Public().(*ecdsa.PublicKey).Equal(pk.Public()) {
+		t.Error("the bundles have different keys")
+	}
+
+	wrong := func(bool) ([]byte, error) { return []byte("wrong"), nil }
+	if _, err := ImportPrivateKey("testdata/pkcs12-legacy.p12", ImportOpts{PKCS12Password: wrong}); err == nil {
+		t.Error("ImportPrivateKey() with the wrong password succeeded")
+	}
+	if _, err := ImportPrivateKey("testdata/pkcs12-aes.p12", ImportOpts{PKCS12Password: password}); err == nil || !strings.Contains(err.Error(), "-legacy") {
+		t.Errorf("ImportPrivateKey() of an AES bundle = %v, want the hint to re-encrypt it", err)
+	}
+}
+
+func TestImportPrivateKeyAWSDataKeyPair(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, err := x509.MarshalPKIXPublicKey(other.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := func(kp awsDataKeyPair) string {
+		b, err := json.Marshal(kp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return writeImportFile(t, b)
+	}
+
+	pk, err := ImportPrivateKey(output(awsDataKeyPair{KeyID: "arn:aws:kms:us-east-1:1:key/1", KeyPairSpec: "ECC_NIST_P256", PrivateKeyPlaintext: der, PublicKey: pub}), ImportOpts{})
+	if err != nil {
+		t.Fatalf("ImportPrivateKey() = %v", err)
+	}
+	if !priv.Equal(pk) {
+		t.Error("ImportPrivateKey() returned another key")
+	}
+	if _, err := ImportPrivateKey(output(awsDataKeyPair{PrivateKeyPlaintext: der, PublicKey: otherPub}), ImportOpts{}); err == nil || !strings.Contains(err.Error(), "doesn't match") {
+		t.Errorf("ImportPrivateKey() with another public key = %v", err)
+	}
+	if _, err := ImportPrivateKey(output(awsDataKeyPair{PrivateKeyCiphertextBlob: []byte("blob")}), ImportOpts{}); err == nil || !strings.Contains(err.Error(), "aws kms decrypt") {
+		t.Errorf("ImportPrivateKey() without the plaintext = %v", err)
+	}
+}
+
+func TestImportPrivateKeyRSAAESKeyWrap(t *testing.T) {
+	wrappingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aesKey := make([]byte, 32)
+	if _, err := rand.Read(aesKey); err != nil {
+		t.Fatal(err)
+	}
+	kwp, err := subtle.NewKWP(aesKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrappedKey, err := kwp.Wrap(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrap := func(hash crypto.Hash) []byte {
+		encrypted, err := rsa.EncryptOAEP(hash.New(), rand.Reader, &wrappingKey.PublicKey, aesKey, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(encrypted, wrappedKey...)
+	}
+
+	for name, tc := range map[string]struct {
+		material []byte
+		opts     ImportOpts
+	}{
+		"sha256": {material: wrap(crypto.SHA256), opts: ImportOpts{WrappingKey: wrappingKey}},
+		"sha1":   {material: wrap(crypto.SHA1), opts: ImportOpts{WrappingKey: wrappingKey, WrappingHash: crypto.SHA1}},
+		"base64": {material: []byte(base64.StdEncoding.EncodeToString(wrap(crypto.SHA256)) + "\n"), opts: ImportOpts{Format: ImportFormatRSAAESKeyWrap, WrappingKey: wrappingKey}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pk, err := ImportPrivateKey(writeImportFile(t, tc.material), tc.opts)
+			if err != nil {
+				t.Fatalf("ImportPrivateKey() = %v", err)
+			}
+			if !priv.Equal(pk) {
+				t.Error("ImportPrivateKey() returned another key")
+			}
+		})
+	}
+
+	path := writeImportFile(t, wrap(crypto.SHA1))
+	if _, err := ImportPrivateKey(path, ImportOpts{WrappingKey: wrappingKey}); err == nil {
+		t.Error("ImportPrivateKey() with the wrong hash succeeded")
+	}
+	if _, err := ImportPrivateKey(path, ImportOpts{Format: ImportFormatRSAAESKeyWrap}); err == nil {
+		t.Error("ImportPrivateKey() without the wrapping key succeeded")
+	}
+}
+
+func TestImportPrivateKeyFormat(t *testing.T) {
+	if _, err := ImportPrivateKey(writeImportFile(t, []byte("key")), ImportOpts{Format: "jwk"}); err == nil || !strings.Contains(err.Error(), "pkcs12") {
+		t.Errorf("ImportPrivateKey() of an unknown format = %v, want the formats", err)
+	}
+
+	// A cosign key pair of the imported key.
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := EncryptImportedKey(priv, func(bool) ([]byte, error) { return []byte("pass"), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := LoadPrivateKey(keys.PrivateBytes, []byte("pass"))
+	if err != nil {
+		t.Fatalf("LoadPrivateKey() of the imported key = %v", err)
+	}
+	if pub, _ := sv.PublicKey(); !priv.PublicKey.Equal(pub) {
+		t.Error("the key pair has another key")
+	}
+}
@@ -0,0 +1,253 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// KeyUsage is what a signer of a KeyUsagePolicy may sign.
+type KeyUsage string
+
+const (
+	// KeyUsageSign allows signatures of images and blobs.
+	KeyUsageSign KeyUsage = "sign"
+	// KeyUsageAttest allows attestations, of the PredicateTypes of the rule
+	// if it has any.
+	KeyUsageAttest KeyUsage = "attest"
+)
+
+// KeyUsagePolicy declares the purposes of signers, e.g. that a release key
+// only signs images and that the identity of a CI workflow only attests
+// provenance, so that verification enforces the separation of duties between
+// them. A signature whose signer matches a rule verifies only if one of the
+// rules that match its signer allows it. Signers that match no rule are not
+// restricted.
+type KeyUsagePolicy struct {
+	Signers []KeyUsageRule `json:"signers"`
+}
+
+// KeyUsageRule restricts the signatures of the key with the sha256:<hex>
+// KeyFingerprint Key, or of the certificates with the identity and issuer
+// of the rule, to Usages. Like those of the denylist, keys match both key
+// and certificate based signatures.
+type KeyUsageRule struct {
+	Key                         string     `json:"key,omitempty"`
+	CertificateIdentity         string     `json:"certificateIdentity,omitempty"`
+	CertificateIdentityRegexp   string     `json:"certificateIdentityRegexp,omitempty"`
+	CertificateOIDCIssuer       string     `json:"certificateOidcIssuer,omitempty"`
+	CertificateOIDCIssuerRegexp string     `json:"certificateOidcIssuerRegexp,omitempty"`
+	Usages                      []KeyUsage `json:"usages"`
+	// PredicateTypes, if set, are the predicate type URIs of the
+	// attestations that the signer may make.
+	PredicateTypes []string `json:"predicateTypes,omitempty"`
+}
+
+// ParseKeyUsagePolicy parses and validates raw as a KeyUsagePolicy.
+func ParseKeyUsagePolicy(raw []byte) (*KeyUsagePolicy, error) {
+	p := &KeyUsagePolicy{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing key usage policy: %w", err)
+	}
+	if len(p.Signers) == 0 {
+		return nil, errors.New("key usage policy has no signers")
+	}
+	for i := range p.Signers {
+		r := &p.Signers[i]
+		r.Key = strings.ToLower(r.Key)
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("key usage policy: signer %d: %w", i, err)
+		}
+	}
+	return p, nil
+}
+
+func (r *KeyUsageRule) validate() error {
+	keyless := r.CertificateIdentity != "" || r.CertificateIdentityRegexp != ""
+	switch {
+	case r.Key == "" && !keyless:
+		return errors.New("must set a key or a certificate identity")
+	case r.Key != "" && keyless:
+		return errors.New("can't set both a key and a certificate identity")
+	case r.Key != "" && !strings.HasPrefix(r.Key, "sha256:"):
+		return fmt.Errorf("key %q isn't a sha256:<hex> key fingerprint", r.Key)
+	case r.Key == "" && r.CertificateOIDCIssuer == "" && r.CertificateOIDCIssuerRegexp == "":
+		return errors.New("must set the certificate OIDC issuer of the certificate identity")
+	}
+	for _, expr := range []string{r.CertificateIdentityRegexp, r.CertificateOIDCIssuerRegexp} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid regular expression %q: %w", expr, err)
+		}
+	}
+	if len(r.Usages) == 0 {
+		return errors.New("must list its usages")
+	}
+	for _, u := range r.Usages {
+		if u != KeyUsageSign && u != KeyUsageAttest {
+			return fmt.Errorf("unknown usage %q, want %q or %q", u, KeyUsageSign, KeyUsageAttest)
+		}
+	}
+	if len(r.PredicateTypes) > 0 && !r.hasUsage(KeyUsageAttest) {
+		return errors.New("predicate types are only allowed with the attest usage")
+	}
+	return nil
+}
+
+func (r *KeyUsageRule) hasUsage(usage KeyUsage) bool {
+	for _, u := range r.Usages {
+		if u == usage {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether the rule allows a signature of usage, which is an
+// attestation of predicateType for KeyUsageAttest.
+func (r *KeyUsageRule) allows(usage KeyUsage, predicateType string) bool {
+	if !r.hasUsage(usage) {
+		return false
+	}
+	if usage != KeyUsageAttest || len(r.PredicateTypes) == 0 {
+		return true
+	}
+	for _, pt := range r.PredicateTypes {
+		if pt == predicateType {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the rule is about the signer with the key
+// fingerprint fp, and the certificate cert if not nil.
+func (r *KeyUsageRule) matches(fp string, cert *x509.Certificate) bool {
+	if r.Key != "" {
+		return r.Key == fp
+	}
+	if cert == nil {
+		return false
+	}
+	co := &CheckOpts{Identities: []Identity{{
+		Subject:       r.CertificateIdentity,
+		SubjectRegExp: r.CertificateIdentityRegexp,
+		Issuer:        r.CertificateOIDCIssuer,
+		IssuerRegExp:  r.CertificateOIDCIssuerRegexp,
+	}}}
+	return CheckCertificatePolicy(cert, co) == nil
+}
+
+// checkUsage returns an error if the signer with the key of verifier, and
+// the certificate cert if not nil, matches rules of the policy but none of
+// them allows a signature of usage and predicateType.
+func (p *KeyUsagePolicy) checkUsage(verifier signature.Verifier, cert *x509.Certificate, usage KeyUsage, predicateType string) error {
+	if p == nil {
+		return nil
+	}
+	pub, err := verifier.PublicKey()
+	if err != nil {
+		return err
+	}
+	fp, err := KeyFingerprint(pub)
+	if err != nil {
+		return err
+	}
+	matched := false
+	for i := range p.Signers {
+		r := &p.Signers[i]
+		if !r.matches(fp, cert) {
+			continue
+		}
+		if r.allows(usage, predicateType) {
+			return nil
+		}
+		matched = true
+	}
+	if !matched {
+		return nil
+	}
+	what := "signatures"
+	if usage == KeyUsageAttest {
+		what = "attestations"
+		if predicateType != "" {
+			what = fmt.Sprintf("attestations of type %s", predicateType)
+		}
+	}
+	signer := "key " + fp
+	if cert != nil {
+		if sans := getSubjectAlternateNames(cert); len(sans) > 0 {
+			signer = sans[0]
+		}
+	}
+	return newTypedVerificationError(ErrKeyUsageType, "%s: %s may not make %s", ErrKeyUsageMessage, signer, what)
+}
+
+// check returns an error if the policy doesn't allow the signer of sig, with
+// the key of verifier, to make sig: a signature, or an attestation of the
+// predicate type of its statement.
+func (p *KeyUsagePolicy) check(sig oci.Signature, verifier signature.Verifier) error {
+	if p == nil {
+		return nil
+	}
+	cert, err := sig.Cert()
+	if err != nil {
+		return err
+	}
+	payload, err := sig.Payload()
+	if err != nil {
+		return err
+	}
+	usage, predicateType := signatureUsage(payload)
+	return p.checkUsage(verifier, cert, usage, predicateType)
+}
+
+// signatureUsage returns the usage of a signature of payload, which is
+// KeyUsageAttest for a DSSE envelope, with the predicate type of the
+// statement for an in-toto statement.
+func signatureUsage(payload []byte) (KeyUsage, string) {
+	var envelope struct {
+		PayloadType string `json:"payloadType"`
+		Payload     string `json:"payload"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil || envelope.PayloadType == "" {
+		return KeyUsageSign, ""
+	}
+	if envelope.PayloadType != types.IntotoPayloadType {
+		return KeyUsageAttest, ""
+	}
+	raw, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return KeyUsageAttest, ""
+	}
+	var statement struct {
+		PredicateType string `json:"predicateType"`
+	}
+	_ = json.Unmarshal(raw, &statement)
+	return KeyUsageAttest, statement.PredicateType
+}
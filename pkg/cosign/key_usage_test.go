@@ -0,0 +1,152 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+func TestParseKeyUsagePolicy(t *testing.T) {
+	p, err := ParseKeyUsagePolicy([]byte(`{"signers": [
+		{"key": "SHA256:ABC", "usages": ["sign"]},
+		{"certificateIdentity": "ci@example.com", "certificateOidcIssuer": "https://issuer.example.com", "usages": ["attest"], "predicateTypes": ["https://slsa.dev/provenance/v0.2"]}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseKeyUsagePolicy() = %v", err)
+	}
+	if len(p.Signers) != 2 || p.Signers[0].Key != "sha256:abc" {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+
+	for raw, want := range map[string]string{
+		`{"signers": []}`:                                   "has no signers",
+		`{"signers": [{"usages": ["sign"]}]}`:               "must set a key or a certificate identity",
+		`{"signers": [{"key": "abc", "usages": ["sign"]}]}`: "isn't a sha256:<hex> key fingerprint",
+		`{"signers": [{"certificateIdentity": "ci@example.com", "usages": ["sign"]}]}`:                        "must set the certificate OIDC issuer",
+		`{"signers": [{"key": "sha256:abc", "usages": []}]}`:                                                  "must list its usages",
+		`{"signers": [{"key": "sha256:abc", "usages": ["verify"]}]}`:                                          `unknown usage "verify"`,
+		`{"signers": [{"key": "sha256:abc", "usages": ["sign"], "predicateTypes": ["x"]}]}`:                   "only allowed with the attest usage",
+		`{"signers": [{"certificateIdentityRegexp": "(", "certificateOidcIssuer": "i", "usages": ["sign"]}]}`: "invalid regular expression",
+		`{"signers": [{"key": "sha256:abc", "usage": ["sign"]}]}`:                                             "unknown field",
+	} {
+		if _, err := ParseKeyUsagePolicy([]byte(raw)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseKeyUsagePolicy(%s) = %v, want %q", raw, err, want)
+		}
+	}
+}
+
+func TestKeyUsagePolicyCheck(t *testing.T) {
+	sv := newDenylistTestSigner(t)
+	pub, _ := sv.PublicKey()
+	fp, err := KeyFingerprint(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := static.NewSignature([]byte("payload"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	attest := func(predicateType string) oci.Signature {
+		statement := `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"` + predicateType + `","subject":[],"predicate":{}}`
+		envelope, err := dsse.WrapSigner(sv, types.IntotoPayloadType).SignMessage(strings.NewReader(statement))
+		if err != nil {
+			t.Fatal(err)
+		}
+		att, err := static.NewAttestation(envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return att
+	}
+	provenance, sbom := attest("https://slsa.dev/provenance/v0.2"), attest("https://spdx.dev/Document")
+
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	leafCert, _, _ := test.GenerateLeafCert("ci@example.com", "https://issuer.example.com", rootCert, rootKey)
+	leafPEM, _ := cryptoutils.MarshalCertificateToPEM(leafCert)
+	keyless, err := static.NewSignature([]byte("payload"), "", static.WithCertChain(leafPEM, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signOnly := KeyUsageRule{Key: fp, Usages: []KeyUsage{KeyUsageSign}}
+	provenanceOnly := KeyUsageRule{Key: fp, Usages: []KeyUsage{KeyUsageAttest}, PredicateTypes: []string{"https://slsa.dev/provenance/v0.2"}}
+	ciAttests := KeyUsageRule{CertificateIdentity: "ci@example.com", CertificateOIDCIssuer: "https://issuer.example.com", Usages: []KeyUsage{KeyUsageAttest}}
+	otherKey := KeyUsageRule{Key: "sha256:abc", Usages: []KeyUsage{KeyUsageAttest}}
+
+	tests := []struct {
+		name    string
+		p       *KeyUsagePolicy
+		sig     oci.Signature
+		allowed bool
+	}{
+		{name: "no policy", sig: sig, allowed: true},
+		{name: "unrestricted signer", p: &KeyUsagePolicy{Signers: []KeyUsageRule{otherKey}}, sig: sig, allowed: true},
+		{name: "signing key signs", p: &KeyUsagePolicy{Signers: []KeyUsageRule{signOnly}}, sig: sig, allowed: true},
+		{name: "signing key attests", p: &KeyUsagePolicy{Signers: []KeyUsageRule{signOnly}}, sig: provenance},
+		{name: "attestation key signs", p: &KeyUsagePolicy{Signers: []KeyUsageRule{provenanceOnly}}, sig: sig},
+		{name: "attestation key attests provenance", p: &KeyUsagePolicy{Signers: []KeyUsageRule{provenanceOnly}}, sig: provenance, allowed: true},
+		{name: "attestation key attests an SBOM", p: &KeyUsagePolicy{Signers: []KeyUsageRule{provenanceOnly}}, sig: sbom},
+		{name: "any rule of the signer", p: &KeyUsagePolicy{Signers: []KeyUsageRule{signOnly, provenanceOnly}}, sig: provenance, allowed: true},
+		{name: "identity signs", p: &KeyUsagePolicy{Signers: []KeyUsageRule{ciAttests}}, sig: keyless},
+		{name: "identity of another signer", p: &KeyUsagePolicy{Signers: []KeyUsageRule{ciAttests}}, sig: sig, allowed: true},
+	}
+	for _, tt := range tests {
+		err := tt.p.check(tt.sig, sv)
+		if err != nil && !errors.Is(err, ErrKeyUsage) {
+			t.Errorf("%s: check() = %v, want %v", tt.name, err, ErrKeyUsage)
+		}
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("%s: check() = %v, want allowed %t", tt.name, err, tt.allowed)
+		}
+	}
+}
+
+func TestVerifyBlobSignatureKeyUsage(t *testing.T) {
+	sv := newDenylistTestSigner(t)
+	pub, _ := sv.PublicKey()
+	fp, _ := KeyFingerprint(pub)
+	payload := []byte("payload")
+	raw, err := sv.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(payload, base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	co := &CheckOpts{SigVerifier: sv, IgnoreTlog: true, KeyUsage: &KeyUsagePolicy{Signers: []KeyUsageRule{{Key: fp, Usages: []KeyUsage{KeyUsageAttest}}}}}
+	if _, err := VerifyBlobSignature(context.Background(), sig, co); !errors.Is(err, ErrKeyUsage) {
+		t.Errorf("VerifyBlobSignature() = %v, want %v", err, ErrKeyUsage)
+	}
+	co.KeyUsage.Signers[0].Usages = []KeyUsage{KeyUsageSign}
+	if _, err := VerifyBlobSignature(context.Background(), sig, co); err != nil {
+		t.Errorf("VerifyBlobSignature() = %v", err)
+	}
+}
@@ -27,8 +27,6 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/theupdateframework/go-tuf/encrypted"
 
@@ -77,11 +75,15 @@ func GeneratePrivateKey() (*ecdsa.PrivateKey, error) {
 
 // TODO(jason): Move this to the only place it's used in cmd/cosign/cli/importkeypair, and unexport it.
 func ImportKeyPair(keyPath string, pf PassFunc) (*KeysBytes, error) {
-	kb, err := os.ReadFile(filepath.Clean(keyPath))
+	pk, err := ImportPrivateKey(keyPath, ImportOpts{Format: ImportFormatPEM})
 	if err != nil {
 		return nil, err
 	}
+	return EncryptImportedKey(pk, pf)
+}
 
+// parsePEMPrivateKey parses the PEM encoded RSA, EC or PKCS #8 private key kb.
+func parsePEMPrivateKey(kb []byte) (crypto.Signer, error) {
 	p, _ := pem.Decode(kb)
 	if p == nil {
 		return nil, fmt.Errorf("invalid pem block")
@@ -135,7 +137,7 @@ func ImportKeyPair(keyPath string, pf PassFunc) (*KeysBytes, error) {
 	default:
 		return nil, fmt.Errorf("unsupported private key")
 	}
-	return marshalKeyPair(p.Type, Keys{pk, pk.Public()}, pf)
+	return pk, nil
 }
 
 func marshalKeyPair(ptype string, keypair Keys, pf PassFunc) (key *KeysBytes, err error) {
@@ -20,8 +20,6 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	utilversion "k8s.io/apimachinery/pkg/util/version"
-	// Initialize all known client auth plugins
-	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
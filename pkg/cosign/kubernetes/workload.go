@@ -0,0 +1,248 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+const (
+	// WorkloadReference is the scheme of the references to the images that
+	// the pods of a Kubernetes workload run, such as
+	// k8s-workload://<namespace>/deployment/<name>.
+	WorkloadReference = "k8s-workload://"
+)
+
+// workloadKinds maps the kinds of workloads, and their short names, to their
+// canonical names.
+var workloadKinds = map[string]string{
+	"deployment":  "deployment",
+	"deploy":      "deployment",
+	"statefulset": "statefulset",
+	"sts":         "statefulset",
+	"daemonset":   "daemonset",
+	"ds":          "daemonset",
+	"replicaset":  "replicaset",
+	"rs":          "replicaset",
+	"job":         "job",
+	"pod":         "pod",
+	"po":          "pod",
+}
+
+// WorkloadImages returns the images that the pods of the workload of
+// k8sRef, a k8s-workload://<namespace>/<kind>/<name> reference, currently
+// run, by the digests their container runtimes report, in their
+// repositories. The cluster is the current context of the kubeconfig, or
+// the cluster cosign runs in.
+func WorkloadImages(ctx context.Context, k8sRef string, opts ...name.Option) ([]string, error) {
+	namespace, kind, workload, err := parseWorkloadRef(k8sRef)
+	if err != nil {
+		return nil, err
+	}
+	client, err := client()
+	if err != nil {
+		return nil, fmt.Errorf("new for config: %w", err)
+	}
+	pods, err := workloadPods(ctx, client, namespace, kind, workload)
+	if err != nil {
+		return nil, fmt.Errorf("getting the pods of %s: %w", k8sRef, err)
+	}
+	images, err := podImages(pods, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", k8sRef, err)
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("%s has no running containers", k8sRef)
+	}
+	return images, nil
+}
+
+func parseWorkloadRef(k8sRef string) (string, string, string, error) {
+	s := strings.Split(strings.TrimPrefix(k8sRef, WorkloadReference), "/")
+	if len(s) != 3 || s[0] == "" || s[2] == "" {
+		return "", "", "", errors.New("kubernetes workload specification should be in the format k8s-workload://<namespace>/<kind>/<name>")
+	}
+	kind, ok := workloadKinds[strings.ToLower(s[1])]
+	if !ok {
+		return "", "", "", fmt.Errorf("unsupported kubernetes workload kind %q, expected deployment, statefulset, daemonset, replicaset, job or pod", s[1])
+	}
+	return s[0], kind, s[2], nil
+}
+
+// workloadPods returns the pods of the workload, selected by the label
+// selector of its spec.
+func workloadPods(ctx context.Context, client kubernetes.Interface, namespace, kind, workload string) ([]v1.Pod, error) {
+	var selector *metav1.LabelSelector
+	switch kind {
+	case "pod":
+		pod, err := client.CoreV1().Pods(namespace).Get(ctx, workload, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []v1.Pod{*pod}, nil
+	case "deployment":
+		d, err := client.AppsV1().Deployments(namespace).Get(ctx, workload, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = d.Spec.Selector
+	case "statefulset":
+		s, err := client.AppsV1().StatefulSets(namespace).Get(ctx, workload, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = s.Spec.Selector
+	case "daemonset":
+		d, err := client.AppsV1().DaemonSets(namespace).Get(ctx, workload, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = d.Spec.Selector
+	case "replicaset":
+		r, err := client.AppsV1().ReplicaSets(namespace).Get(ctx, workload, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = r.Spec.Selector
+	case "job":
+		j, err := client.BatchV1().Jobs(namespace).Get(ctx, workload, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = j.Spec.Selector
+	}
+	if selector == nil {
+		return nil, fmt.Errorf("%s %s/%s has no pod selector", kind, namespace, workload)
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: s.String()})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// RunningImage is an image that containers of a cluster run.
+type RunningImage struct {
+	// Image is the repository of the specs of the containers at the digest
+	// of their statuses, or the image of their specs if their container
+	// runtime doesn't report its digest, in which case Digest is empty.
+	Image  string `json:"image"`
+	Digest string `json:"digest,omitempty"`
+	// Pods are the pods running it, as <namespace>/<name>.
+	Pods []string `json:"pods"`
+}
+
+// ClusterImages returns the images that the pods of namespaces, or of all
+// namespaces if empty, currently run, sorted. The cluster is the current
+// context of the kubeconfig, or the cluster cosign runs in.
+func ClusterImages(ctx context.Context, namespaces []string, opts ...name.Option) ([]RunningImage, error) {
+	client, err := client()
+	if err != nil {
+		return nil, fmt.Errorf("new for config: %w", err)
+	}
+	return clusterImages(ctx, client, namespaces, opts...)
+}
+
+func clusterImages(ctx context.Context, client kubernetes.Interface, namespaces []string, opts ...name.Option) ([]RunningImage, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	var pods []v1.Pod
+	for _, ns := range namespaces {
+		list, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("listing pods: %w", err)
+		}
+		pods = append(pods, list.Items...)
+	}
+	return runningImages(pods, opts...)
+}
+
+// podImages returns the distinct images the containers of pods run, sorted,
+// by digest.
+func podImages(pods []v1.Pod, opts ...name.Option) ([]string, error) {
+	running, err := runningImages(pods, opts...)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]string, 0, len(running))
+	for _, r := range running {
+		if r.Digest == "" {
+			return nil, fmt.Errorf("the container runtime of pod %s doesn't report the digest of %s", r.Pods[0], r.Image)
+		}
+		images = append(images, r.Image)
+	}
+	return images, nil
+}
+
+// runningImages returns the distinct images the containers of pods run,
+// sorted, as the repositories of their specs at the digests of their
+// statuses. Containers that haven't started yet are skipped.
+func runningImages(pods []v1.Pod, opts ...name.Option) ([]RunningImage, error) {
+	found := map[string]*RunningImage{}
+	for _, pod := range pods {
+		podName := pod.Namespace + "/" + pod.Name
+		specImages := map[string]string{}
+		for _, c := range append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+			specImages[c.Name] = c.Image
+		}
+		statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.ImageID == "" {
+				continue
+			}
+			spec, ok := specImages[status.Name]
+			if !ok {
+				spec = status.Image
+			}
+			ref, err := oci.ParseReference(spec, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("parsing the image of the container %s of pod %s: %w", status.Name, podName, err)
+			}
+			r := RunningImage{Image: ref.Name()}
+			if _, digest, ok := strings.Cut(status.ImageID, "@"); ok {
+				r = RunningImage{Image: ref.Context().Digest(digest).String(), Digest: digest}
+			}
+			if found[r.Image] == nil {
+				found[r.Image] = &r
+			}
+			if pods := found[r.Image].Pods; len(pods) == 0 || pods[len(pods)-1] != podName {
+				found[r.Image].Pods = append(pods, podName)
+			}
+		}
+	}
+	images := make([]RunningImage, 0, len(found))
+	for _, r := range found {
+		sort.Strings(r.Pods)
+		images = append(images, *r)
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Image < images[j].Image })
+	return images, nil
+}
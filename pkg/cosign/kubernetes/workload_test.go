@@ -0,0 +1,145 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseWorkloadRef(t *testing.T) {
+	for ref, want := range map[string][3]string{
+		"k8s-workload://prod/deploy/api":      {"prod", "deployment", "api"},
+		"k8s-workload://prod/StatefulSet/db":  {"prod", "statefulset", "db"},
+		"k8s-workload://kube-system/ds/proxy": {"kube-system", "daemonset", "proxy"},
+	} {
+		namespace, kind, name, err := parseWorkloadRef(ref)
+		if err != nil {
+			t.Errorf("parseWorkloadRef(%q) = %v", ref, err)
+			continue
+		}
+		if got := [3]string{namespace, kind, name}; got != want {
+			t.Errorf("parseWorkloadRef(%q) = %v, want %v", ref, got, want)
+		}
+	}
+	for ref, want := range map[string]string{
+		"k8s-workload://prod/api":             "should be in the format",
+		"k8s-workload://prod/deploy/":         "should be in the format",
+		"k8s-workload://prod/cronjob/nightly": "unsupported kubernetes workload kind",
+	} {
+		if _, _, _, err := parseWorkloadRef(ref); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseWorkloadRef(%q) = %v, want an error containing %q", ref, err, want)
+		}
+	}
+}
+
+func TestWorkloadPodImages(t *testing.T) {
+	const (
+		apiDigest   = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		proxyDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	labels := map[string]string{"app": "api"}
+	pod := func(name string, labels map[string]string, statuses ...v1.ContainerStatus) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod", Labels: labels},
+			Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: "api", Image: "example.com/api:v1"},
+				{Name: "proxy", Image: "example.com/proxy"},
+			}},
+			Status: v1.PodStatus{ContainerStatuses: statuses},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+		pod("api-1", labels,
+			v1.ContainerStatus{Name: "api", Image: "example.com/api:v1", ImageID: "example.com/api@" + apiDigest},
+			// Docker reports the image IDs with its own scheme, and the
+			// resolved image rather than that of the spec.
+			v1.ContainerStatus{Name: "proxy", Image: "sha256:abcd", ImageID: "docker-pullable://mirror.example.com/proxy@" + proxyDigest}),
+		// Another replica still pulling its images.
+		pod("api-2", labels,
+			v1.ContainerStatus{Name: "api", Image: "example.com/api:v1", ImageID: "example.com/api@" + apiDigest},
+			v1.ContainerStatus{Name: "proxy", Image: "example.com/proxy"}),
+		pod("other", map[string]string{"app": "other"},
+			v1.ContainerStatus{Name: "api", Image: "example.com/api:v2", ImageID: "example.com/api@sha256:3333333333333333333333333333333333333333333333333333333333333333"}),
+	)
+
+	pods, err := workloadPods(context.Background(), client, "prod", "deployment", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	images, err := podImages(pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"example.com/api@" + apiDigest, "example.com/proxy@" + proxyDigest}
+	if !reflect.DeepEqual(images, want) {
+		t.Errorf("podImages() = %v, want %v", images, want)
+	}
+
+	if _, err := workloadPods(context.Background(), client, "prod", "deployment", "missing"); err == nil {
+		t.Error("workloadPods() of a missing deployment: expected an error")
+	}
+	if _, err := podImages([]v1.Pod{*pod("local", nil, v1.ContainerStatus{Name: "api", ImageID: "sha256:abcd"})}); err == nil {
+		t.Error("podImages() of an image ID without a digest: expected an error")
+	}
+}
+
+func TestClusterImages(t *testing.T) {
+	const digest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	pod := func(namespace, name, image, imageID string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: image}}},
+			Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "app", Image: image, ImageID: imageID}}},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		pod("prod", "api-1", "example.com/api:v1", "example.com/api@"+digest),
+		pod("staging", "api-1", "example.com/api:v1", "example.com/api@"+digest),
+		// A locally built image, whose runtime reports its config digest only.
+		pod("dev", "local", "local/tool:dev", "sha256:abcd"),
+	)
+
+	images, err := clusterImages(context.Background(), client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RunningImage{
+		{Image: "example.com/api@" + digest, Digest: digest, Pods: []string{"prod/api-1", "staging/api-1"}},
+		{Image: "index.docker.io/local/tool:dev", Pods: []string{"dev/local"}},
+	}
+	if !reflect.DeepEqual(images, want) {
+		t.Errorf("clusterImages() = %+v, want %+v", images, want)
+	}
+
+	images, err = clusterImages(context.Background(), client, []string{"staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 1 || !reflect.DeepEqual(images[0].Pods, []string{"staging/api-1"}) {
+		t.Errorf("clusterImages(staging) = %+v, want the staging pod only", images)
+	}
+}
@@ -0,0 +1,87 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// LayerSelectorConfig selects the config blob of an image.
+const LayerSelectorConfig = "config"
+
+// SelectBlobs returns the digests of the blobs of img chosen by selectors,
+// in order and without duplicates. A selector is LayerSelectorConfig, the
+// index of a layer, from the base layer at 0 or, if negative, from the top
+// layer at -1, or the digest of a layer.
+func SelectBlobs(img v1.Image, selectors []string) ([]v1.Hash, error) {
+	if len(selectors) == 0 {
+		return nil, errors.New("no layers selected")
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	var hashes []v1.Hash
+	seen := map[v1.Hash]bool{}
+	for _, s := range selectors {
+		h, err := selectBlob(m, strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		if !seen[h] {
+			seen[h] = true
+			hashes = append(hashes, h)
+		}
+	}
+	return hashes, nil
+}
+
+func selectBlob(m *v1.Manifest, selector string) (v1.Hash, error) {
+	if selector == LayerSelectorConfig {
+		return m.Config.Digest, nil
+	}
+	if strings.Contains(selector, ":") {
+		h, err := oci.ParseDigest(selector)
+		if err != nil {
+			return v1.Hash{}, fmt.Errorf("invalid layer digest %q: %w", selector, err)
+		}
+		for _, l := range m.Layers {
+			if l.Digest == h {
+				return h, nil
+			}
+		}
+		return v1.Hash{}, fmt.Errorf("the image has no layer %s", h)
+	}
+	i, err := strconv.Atoi(selector)
+	if err != nil {
+		return v1.Hash{}, fmt.Errorf("invalid layer selector %q, must be %s, the index of a layer or its digest", selector, LayerSelectorConfig)
+	}
+	n := len(m.Layers)
+	if i < 0 {
+		i += n
+	}
+	if i < 0 || i >= n {
+		return v1.Hash{}, fmt.Errorf("layer %s is out of range, the image has %d layers", selector, n)
+	}
+	return m.Layers[i].Digest, nil
+}
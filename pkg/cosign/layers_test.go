@@ -0,0 +1,68 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+)
+
+func TestSelectBlobs(t *testing.T) {
+	img, err := random.Image(100, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := func(i int) v1.Hash { return m.Layers[i].Digest }
+
+	tests := []struct {
+		selectors []string
+		want      []v1.Hash
+		wantErr   bool
+	}{
+		{selectors: []string{"0", "1"}, want: []v1.Hash{layer(0), layer(1)}},
+		{selectors: []string{"-1", "config"}, want: []v1.Hash{layer(2), m.Config.Digest}},
+		{selectors: []string{layer(1).String(), "1", "-2"}, want: []v1.Hash{layer(1)}},
+		{selectors: nil, wantErr: true},
+		{selectors: []string{"3"}, wantErr: true},
+		{selectors: []string{"-4"}, wantErr: true},
+		{selectors: []string{"base"}, wantErr: true},
+		{selectors: []string{m.Config.Digest.String()}, wantErr: true},
+		{selectors: []string{"sha256:nope"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := SelectBlobs(img, tt.selectors)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SelectBlobs(%v) error = %v, wantErr %t", tt.selectors, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("SelectBlobs(%v) = %v, want %v", tt.selectors, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SelectBlobs(%v) = %v, want %v", tt.selectors, got, tt.want)
+				break
+			}
+		}
+	}
+}
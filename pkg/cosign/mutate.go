@@ -0,0 +1,21 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+// MutatedFromAnnotation is the signed payload annotation holding the digest
+// of the image that `cosign mutate` changed into the signed image, so that
+// verifiers can trace a mutated image back to the one it was made from.
+const MutatedFromAnnotation = "dev.sigstore.cosign/mutated-from"
@@ -0,0 +1,421 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/entries"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// OfflineBundleMediaType is the media type of an OfflineBundle.
+const OfflineBundleMediaType = "application/vnd.dev.sigstore.cosign.offline-bundle.v1+json"
+
+// OfflineBundle holds the signatures and attestations of an image, with their
+// certificate chains, Rekor bundles, timestamps and Rekor inclusion proofs,
+// so that the image can be verified without network access. The SCTs of the
+// certificates are embedded in the certificates, and their certificate
+// transparency log inclusion proofs may be added with AddCTInclusionProofs.
+type OfflineBundle struct {
+	MediaType string `json:"mediaType"`
+	// Image is the image, as repository@digest.
+	Image        string             `json:"image"`
+	Signatures   []OfflineSignature `json:"signatures,omitempty"`
+	Attestations []OfflineSignature `json:"attestations,omitempty"`
+}
+
+// OfflineSignature is a signature, or an attestation, of an OfflineBundle.
+type OfflineSignature struct {
+	MediaType types.MediaType `json:"mediaType"`
+	Payload   []byte          `json:"payload"`
+	// Signature is the base64 signature, empty for attestations.
+	Signature string `json:"signature,omitempty"`
+	// Annotations are the annotations of the signature other than those of
+	// the fields below.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Certificate and Chain are PEM encoded.
+	Certificate      []byte                   `json:"certificate,omitempty"`
+	Chain            []byte                   `json:"chain,omitempty"`
+	RekorBundle      *bundle.RekorBundle      `json:"rekorBundle,omitempty"`
+	RFC3161Timestamp *bundle.RFC3161Timestamp `json:"rfc3161Timestamp,omitempty"`
+	// TlogEntry is the Rekor entry of RekorBundle, with its inclusion proof.
+	TlogEntry *models.LogEntryAnon `json:"tlogEntry,omitempty"`
+	// CTInclusionProof is the proof that Certificate is in the certificate
+	// transparency log of its SCT.
+	CTInclusionProof *CTInclusionProof `json:"ctInclusionProof,omitempty"`
+}
+
+// NewOfflineSignature returns the OfflineSignature of sig.
+func NewOfflineSignature(sig oci.Signature) (OfflineSignature, error) {
+	var s OfflineSignature
+	var err error
+	if s.MediaType, err = sig.MediaType(); err != nil {
+		return s, err
+	}
+	if s.Payload, err = sig.Payload(); err != nil {
+		return s, err
+	}
+	if s.Signature, err = sig.Base64Signature(); err != nil {
+		return s, err
+	}
+	ann, err := sig.Annotations()
+	if err != nil {
+		return s, err
+	}
+	for k, v := range ann {
+		switch k {
+		case static.SignatureAnnotationKey, static.CertificateAnnotationKey, static.ChainAnnotationKey,
+			static.BundleAnnotationKey, static.RFC3161TimestampAnnotationKey:
+			continue
+		}
+		if s.Annotations == nil {
+			s.Annotations = map[string]string{}
+		}
+		s.Annotations[k] = v
+	}
+	cert, err := sig.Cert()
+	if err != nil {
+		return s, err
+	}
+	if cert != nil {
+		if s.Certificate, err = cryptoutils.MarshalCertificateToPEM(cert); err != nil {
+			return s, err
+		}
+		chain, err := sig.Chain()
+		if err != nil {
+			return s, err
+		}
+		if len(chain) > 0 {
+			if s.Chain, err = cryptoutils.MarshalCertificatesToPEM(chain); err != nil {
+				return s, err
+			}
+		}
+	}
+	if s.RekorBundle, err = sig.Bundle(); err != nil {
+		return s, err
+	}
+	if s.RFC3161Timestamp, err = sig.RFC3161Timestamp(); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// signature returns the oci.Signature of s. Unless co.IgnoreTlog is set, the
+// inclusion proof of TlogEntry is verified with co.RekorPubKeys, and must be
+// of the entry of RekorBundle; a TlogEntry without a RekorBundle is used as
+// the Rekor bundle of the signature.
+func (s OfflineSignature) signature(ctx context.Context, co *CheckOpts) (oci.Signature, error) {
+	rekorBundle := s.RekorBundle
+	if s.TlogEntry != nil && !co.IgnoreTlog {
+		e := s.TlogEntry
+		body, ok := e.Body.(string)
+		if !ok || e.IntegratedTime == nil || e.LogIndex == nil || e.LogID == nil {
+			return nil, errors.New("transparency log entry is incomplete")
+		}
+		if err := VerifyTLogEntryOffline(ctx, e, co.RekorPubKeys); err != nil {
+			return nil, fmt.Errorf("verifying transparency log entry: %w", err)
+		}
+		if rekorBundle == nil {
+			rekorBundle = bundle.EntryToBundle(e)
+		} else if b, ok := rekorBundle.Payload.Body.(string); !ok || b != body || rekorBundle.Payload.LogIndex != *e.LogIndex {
+			return nil, errors.New("transparency log entry doesn't match the Rekor bundle")
+		}
+	}
+
+	ann := make(map[string]string, len(s.Annotations))
+	for k, v := range s.Annotations {
+		ann[k] = v
+	}
+	opts := []static.Option{
+		static.WithLayerMediaType(s.MediaType),
+		static.WithAnnotations(ann),
+		static.WithBundle(rekorBundle),
+		static.WithRFC3161Timestamp(s.RFC3161Timestamp),
+	}
+	if len(s.Certificate) > 0 {
+		opts = append(opts, static.WithCertChain(s.Certificate, s.Chain))
+	}
+	return static.NewSignature(s.Payload, s.Signature, opts...)
+}
+
+// NewOfflineBundle returns the OfflineBundle of the signatures and
+// attestations of the image digest.
+func NewOfflineBundle(digest name.Digest, sigs, atts []oci.Signature) (*OfflineBundle, error) {
+	b := &OfflineBundle{MediaType: OfflineBundleMediaType, Image: digest.String()}
+	for _, sig := range sigs {
+		s, err := NewOfflineSignature(sig)
+		if err != nil {
+			return nil, err
+		}
+		b.Signatures = append(b.Signatures, s)
+	}
+	for _, att := range atts {
+		s, err := NewOfflineSignature(att)
+		if err != nil {
+			return nil, err
+		}
+		b.Attestations = append(b.Attestations, s)
+	}
+	return b, nil
+}
+
+// LoadOfflineBundle reads the OfflineBundle at path.
+func LoadOfflineBundle(path string) (*OfflineBundle, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading offline bundle: %w", err)
+	}
+	b := &OfflineBundle{}
+	if err := json.Unmarshal(raw, b); err != nil {
+		return nil, fmt.Errorf("parsing offline bundle %s: %w", path, err)
+	}
+	if b.MediaType != OfflineBundleMediaType {
+		return nil, fmt.Errorf("offline bundle %s has media type %q, want %s", path, b.MediaType, OfflineBundleMediaType)
+	}
+	if _, err := b.Digest(); err != nil {
+		return nil, fmt.Errorf("offline bundle %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Digest returns the image of the bundle.
+func (b *OfflineBundle) Digest() (name.Digest, error) {
+	d, err := oci.ParseDigestReference(b.Image)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("image %q isn't a digest: %w", b.Image, err)
+	}
+	return d, nil
+}
+
+// signatures returns the oci.Signatures of sl, and the digest of the image.
+func (b *OfflineBundle) signatures(ctx context.Context, sl []OfflineSignature, co *CheckOpts) (oci.Signatures, v1.Hash, error) {
+	d, err := b.Digest()
+	if err != nil {
+		return nil, v1.Hash{}, err
+	}
+	h, err := oci.ParseDigest(d.DigestStr())
+	if err != nil {
+		return nil, v1.Hash{}, err
+	}
+	sigs := &fakeOCISignatures{}
+	for i, s := range sl {
+		sig, err := s.signature(ctx, co)
+		if err != nil {
+			return nil, v1.Hash{}, fmt.Errorf("signature %d of the offline bundle: %w", i, err)
+		}
+		sigs.signatures = append(sigs.signatures, sig)
+	}
+	return sigs, h, nil
+}
+
+// VerifyOfflineBundleSignatures verifies the signatures of b, as
+// VerifyImageSignatures does for the image of b, without network access.
+func VerifyOfflineBundleSignatures(ctx context.Context, b *OfflineBundle, co *CheckOpts) (checkedSignatures []oci.Signature, bundleVerified bool, err error) {
+	// Enforce this up front.
+	if co.RootCerts == nil && co.SigVerifier == nil {
+		return nil, false, errors.New("one of verifier or root certs is required")
+	}
+	co = withCTInclusionProofs(co, b.Signatures)
+	sigs, h, err := b.signatures(ctx, b.Signatures, co)
+	if err != nil {
+		return nil, false, err
+	}
+	return verifySignatures(ctx, sigs, h, co)
+}
+
+// VerifyOfflineBundleAttestations verifies the attestations of b, as
+// VerifyImageAttestations does for the image of b, without network access.
+func VerifyOfflineBundleAttestations(ctx context.Context, b *OfflineBundle, co *CheckOpts) (checkedAttestations []oci.Signature, bundleVerified bool, err error) {
+	// Enforce this up front.
+	if co.RootCerts == nil && co.SigVerifier == nil {
+		return nil, false, errors.New("one of verifier or root certs is required")
+	}
+	co = withCTInclusionProofs(co, b.Attestations)
+	atts, h, err := b.signatures(ctx, b.Attestations, co)
+	if err != nil {
+		return nil, false, err
+	}
+	return verifyImageAttestations(ctx, atts, h, co)
+}
+
+// withCTInclusionProofs returns a copy of co with the certificate
+// transparency log inclusion proofs of sl.
+func withCTInclusionProofs(co *CheckOpts, sl []OfflineSignature) *CheckOpts {
+	c := *co
+	c.CTInclusionProofs = append([]*CTInclusionProof{}, co.CTInclusionProofs...)
+	for _, s := range sl {
+		if s.CTInclusionProof != nil {
+			c.CTInclusionProofs = append(c.CTInclusionProofs, s.CTInclusionProof)
+		}
+	}
+	return &c
+}
+
+// AddCTInclusionProofs fetches, with the clients of the logs, the
+// certificate transparency log inclusion proof of the certificate of each
+// signature and attestation of b that has a certificate chain and an
+// embedded SCT.
+func (b *OfflineBundle) AddCTInclusionProofs(ctx context.Context, clients func(logID string) (CTLogClient, error)) error {
+	for _, sl := range [][]OfflineSignature{b.Signatures, b.Attestations} {
+		for i := range sl {
+			s := &sl[i]
+			if len(s.Certificate) == 0 || len(s.Chain) == 0 {
+				continue
+			}
+			if ok, err := ContainsSCT(s.Certificate); err != nil || !ok {
+				continue
+			}
+			chain, err := cryptoutils.UnmarshalCertificatesFromPEM(append(append([]byte{}, s.Certificate...), s.Chain...))
+			if err != nil {
+				return fmt.Errorf("parsing certificate chain: %w", err)
+			}
+			if s.CTInclusionProof, err = FetchCTInclusionProof(ctx, chain, nil, clients); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExportOfflineBundle returns the OfflineBundle of the image digest, with the
+// signatures and attestations attached to it both with the tag schema and as
+// OCI 1.1 referrers. If rekorClient isn't nil, the inclusion proof of the
+// Rekor entry of each signature with a Rekor bundle is fetched with it.
+func ExportOfflineBundle(ctx context.Context, digest name.Digest, rekorClient *client.Rekor, opts ...ociremote.Option) (*OfflineBundle, error) {
+	se, err := ociremote.SignedEntity(digest, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var sigs, atts []oci.Signature
+	sigSeen, attSeen := map[string]bool{}, map[string]bool{}
+	collect := func(se oci.SignedEntity) error {
+		s, err := se.Signatures()
+		if err != nil {
+			return fmt.Errorf("fetching signatures: %w", err)
+		}
+		if sigs, err = appendUniqueSignatures(sigs, sigSeen, s); err != nil {
+			return fmt.Errorf("fetching signatures: %w", err)
+		}
+		a, err := se.Attestations()
+		if err != nil {
+			return fmt.Errorf("fetching attestations: %w", err)
+		}
+		if atts, err = appendUniqueSignatures(atts, attSeen, a); err != nil {
+			return fmt.Errorf("fetching attestations: %w", err)
+		}
+		return nil
+	}
+	if err := collect(se); err != nil {
+		return nil, err
+	}
+	// Registries that don't support referrers have no signatures to add.
+	art, err := ociremote.SignedArtifact(digest, opts...)
+	if err == nil {
+		err = collect(art)
+	}
+	if err != nil {
+		ui.Warnf(ctx, "skipping the OCI 1.1 referrers of %s: %v", digest, err)
+	}
+	if len(sigs) == 0 && len(atts) == 0 {
+		return nil, fmt.Errorf("no signatures or attestations found for %s", digest)
+	}
+
+	b, err := NewOfflineBundle(digest, sigs, atts)
+	if err != nil {
+		return nil, err
+	}
+	if rekorClient == nil {
+		return b, nil
+	}
+	for _, sl := range [][]OfflineSignature{b.Signatures, b.Attestations} {
+		for i := range sl {
+			if sl[i].RekorBundle == nil {
+				continue
+			}
+			if sl[i].TlogEntry, err = tlogEntryOfBundle(ctx, rekorClient, sl[i].RekorBundle); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b, nil
+}
+
+// appendUniqueSignatures appends the signatures of s to sl, skipping those
+// in seen.
+func appendUniqueSignatures(sl []oci.Signature, seen map[string]bool, s oci.Signatures) ([]oci.Signature, error) {
+	got, err := s.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, sig := range got {
+		payload, err := sig.Payload()
+		if err != nil {
+			return nil, err
+		}
+		b64sig, err := sig.Base64Signature()
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.Sum256(payload)
+		key := b64sig + "/" + hex.EncodeToString(h[:])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		sl = append(sl, sig)
+	}
+	return sl, nil
+}
+
+// tlogEntryOfBundle fetches the Rekor entry of b, with its inclusion proof.
+func tlogEntryOfBundle(ctx context.Context, rekorClient *client.Rekor, b *bundle.RekorBundle) (*models.LogEntryAnon, error) {
+	params := entries.NewGetLogEntryByIndexParamsWithContext(ctx)
+	params.SetLogIndex(b.Payload.LogIndex)
+	resp, err := rekorClient.Entries.GetLogEntryByIndex(params)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the Rekor entry %d: %w", b.Payload.LogIndex, err)
+	}
+	for _, e := range resp.Payload {
+		e := e
+		body, _ := e.Body.(string)
+		if want, ok := b.Payload.Body.(string); !ok || body != want {
+			return nil, fmt.Errorf("the Rekor entry %d doesn't match the Rekor bundle", b.Payload.LogIndex)
+		}
+		if e.Verification == nil || e.Verification.InclusionProof == nil {
+			return nil, fmt.Errorf("the Rekor entry %d has no inclusion proof", b.Payload.LogIndex)
+		}
+		return &e, nil
+	}
+	return nil, fmt.Errorf("the Rekor entry %d wasn't found", b.Payload.LogIndex)
+}
@@ -0,0 +1,205 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	rtypes "github.com/sigstore/rekor/pkg/types"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+	"github.com/sigstore/sigstore/pkg/tuf"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+func TestOfflineBundle(t *testing.T) {
+	ctx := context.Background()
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	leafCert, privKey, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", rootCert, rootKey)
+	pemLeaf := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafCert.Raw})
+	rootPool := x509.NewCertPool()
+	rootPool.AddCert(rootCert)
+
+	digest, err := name.NewDigest("registry.example.com/app@sha256:" + strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pld, err := payload.Cosign{Image: digest}.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.Sum256(pld)
+	sig, _ := privKey.Sign(rand.Reader, h[:], crypto.SHA256)
+	b64sig := base64.StdEncoding.EncodeToString(sig)
+
+	// A Rekor bundle and entry of a log with only this entry.
+	rekor, _, err := signature.NewECDSASignerVerifier(elliptic.P256(), rand.Reader, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pe, _ := proposedEntry(b64sig, pld, pemLeaf)
+	entry, _ := rtypes.UnmarshalEntry(pe[0])
+	leaf, _ := entry.Canonicalize(ctx)
+	rekorBundle := CreateTestBundle(ctx, t, rekor, leaf)
+	rootHash := hex.EncodeToString(rfc6962.DefaultHasher.HashLeaf(leaf))
+	zero, one := int64(0), int64(1)
+	tlogEntry := &models.LogEntryAnon{
+		Body:           rekorBundle.Payload.Body,
+		IntegratedTime: &rekorBundle.Payload.IntegratedTime,
+		LogIndex:       &rekorBundle.Payload.LogIndex,
+		LogID:          &rekorBundle.Payload.LogID,
+		Verification: &models.LogEntryAnonVerification{
+			SignedEntryTimestamp: strfmt.Base64(rekorBundle.SignedEntryTimestamp),
+			InclusionProof: &models.InclusionProof{
+				LogIndex: &zero,
+				TreeSize: &one,
+				RootHash: &rootHash,
+				Hashes:   []string{},
+			},
+		},
+	}
+	pemBytes, _ := cryptoutils.MarshalPublicKeyToPEM(rekor.Public())
+	rekorPubKeys := NewTrustedTransparencyLogPubKeys()
+	if err := rekorPubKeys.AddTransparencyLogPubKey(pemBytes, tuf.Active); err != nil {
+		t.Fatal(err)
+	}
+
+	ociSig, err := static.NewSignature(pld, b64sig, static.WithCertChain(pemLeaf, nil), static.WithBundle(rekorBundle),
+		static.WithAnnotations(map[string]string{"team": "release"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewOfflineBundle(digest, []oci.Signature{ociSig}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Signatures[0].TlogEntry = tlogEntry
+	if got := b.Signatures[0].Annotations; len(got) != 1 || got["team"] != "release" {
+		t.Errorf("Annotations = %v, want only the team annotation", got)
+	}
+
+	// Round trip the bundle through a file.
+	raw, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	load := func(t *testing.T) *OfflineBundle {
+		t.Helper()
+		b, err := LoadOfflineBundle(path)
+		if err != nil {
+			t.Fatalf("LoadOfflineBundle() = %v", err)
+		}
+		return b
+	}
+	co := func() *CheckOpts {
+		return &CheckOpts{
+			RootCerts:     rootPool,
+			IgnoreSCT:     true,
+			Offline:       true,
+			Identities:    []Identity{{Subject: "subject@mail.com", Issuer: "oidc-issuer"}},
+			RekorPubKeys:  &rekorPubKeys,
+			ClaimVerifier: SimpleClaimVerifier,
+		}
+	}
+
+	t.Run("verifies", func(t *testing.T) {
+		verified, bundleVerified, err := VerifyOfflineBundleSignatures(ctx, load(t), co())
+		if err != nil {
+			t.Fatalf("VerifyOfflineBundleSignatures() = %v", err)
+		}
+		if len(verified) != 1 || !bundleVerified {
+			t.Errorf("VerifyOfflineBundleSignatures() = %d signatures, bundle verified %v, want 1 and true", len(verified), bundleVerified)
+		}
+	})
+
+	t.Run("entry without a Rekor bundle", func(t *testing.T) {
+		b := load(t)
+		b.Signatures[0].RekorBundle = nil
+		if _, bundleVerified, err := VerifyOfflineBundleSignatures(ctx, b, co()); err != nil || !bundleVerified {
+			t.Errorf("VerifyOfflineBundleSignatures() = %v, %v, want the entry to be used as the bundle", bundleVerified, err)
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		tamper func(*OfflineBundle)
+		want   string
+	}{
+		"invalid inclusion proof": {
+			tamper: func(b *OfflineBundle) {
+				other := hex.EncodeToString(make([]byte, 32))
+				b.Signatures[0].TlogEntry.Verification.InclusionProof.RootHash = &other
+			},
+			want: "verifying inclusion proof",
+		},
+		"entry of another signature": {
+			tamper: func(b *OfflineBundle) {
+				b.Signatures[0].RekorBundle.Payload.Body = base64.StdEncoding.EncodeToString([]byte("other"))
+			},
+			want: "doesn't match the Rekor bundle",
+		},
+		"another image": {
+			tamper: func(b *OfflineBundle) {
+				b.Image = "registry.example.com/app@sha256:" + strings.Repeat("b", 64)
+			},
+			want: "invalid or missing digest",
+		},
+		"no signatures": {
+			tamper: func(b *OfflineBundle) {
+				b.Signatures = nil
+			},
+			want: ErrNoSignaturesFoundMessage,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b := load(t)
+			tc.tamper(b)
+			if _, _, err := VerifyOfflineBundleSignatures(ctx, b, co()); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("VerifyOfflineBundleSignatures() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	if err := os.WriteFile(path, []byte(`{"mediaType": "application/json", "image": "`+digest.String()+`"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOfflineBundle(path); err == nil || !strings.Contains(err.Error(), "has media type") {
+		t.Errorf("LoadOfflineBundle() of another media type = %v", err)
+	}
+}
@@ -37,6 +37,17 @@ func GetKeyWithURIConfig(config *Pkcs11UriConfig, askForPinIfNeeded bool) (*Key,
 	return nil, errors.New("unimplemented")
 }
 
+func GetCertificateWithURIConfig(config *Pkcs11UriConfig, askForPinIfNeeded bool) (*x509.Certificate, error) { //nolint: revive
+	return nil, errors.New("unimplemented")
+}
+
+func ListKeys(config *Pkcs11UriConfig, askForPinIfNeeded bool) ([]KeyPair, error) { //nolint: revive
+	return nil, errors.New("unimplemented")
+}
+
+func CloseSessions() {
+}
+
 func (k *Key) Certificate() (*x509.Certificate, error) {
 	return nil, errors.New("unimplemented")
 }
@@ -30,6 +30,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
 	"syscall"
 
 	"github.com/ThalesIgnite/crypto11"
@@ -51,16 +52,193 @@ type Key struct {
 	cert   *x509.Certificate
 }
 
+// sessions are the logged-in contexts of the tokens opened by this process,
+// so that loading another key of a token, or the same key again, doesn't
+// log in and ask for the PIN again. They stay open until CloseSessions.
+var (
+	sessionsMu sync.Mutex
+	sessions   = map[string]*crypto11.Context{}
+)
+
+func sessionKey(config *Pkcs11UriConfig) string {
+	if config.SlotID != nil {
+		return fmt.Sprintf("%s\x00slot-id=%d", config.ModulePath, *config.SlotID)
+	}
+	return config.ModulePath + "\x00token=" + config.TokenLabel
+}
+
+// session returns the context of the token of config, opening it if this
+// process hasn't yet.
+func session(config *Pkcs11UriConfig, askForPinIfNeeded bool) (*crypto11.Context, error) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	key := sessionKey(config)
+	if ctx, ok := sessions[key]; ok {
+		return ctx, nil
+	}
+	ctx, err := configure(config, askForPinIfNeeded)
+	if err != nil {
+		return nil, err
+	}
+	sessions[key] = ctx
+	return ctx, nil
+}
+
+// CloseSessions logs out of and closes the tokens opened by this process.
+func CloseSessions() {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	for key, ctx := range sessions {
+		_ = ctx.Close()
+		delete(sessions, key)
+	}
+}
+
+// nonEmpty returns b, or nil if it is empty, as crypto11 only ignores the
+// attributes of a search that are nil.
+func nonEmpty(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+func describeKey(config *Pkcs11UriConfig) string {
+	switch {
+	case len(config.KeyID) != 0 && len(config.KeyLabel) != 0:
+		return fmt.Sprintf("with id %x and label '%s'", config.KeyID, config.KeyLabel)
+	case len(config.KeyID) != 0:
+		return fmt.Sprintf("with id %x", config.KeyID)
+	default:
+		return fmt.Sprintf("with label '%s'", config.KeyLabel)
+	}
+}
+
+// selectKeyPair returns the key pair of signers, the key pairs of a token
+// matching config. The key must match both the id and the label if both are
+// set, where cosign v2.0 ignored the label of URIs with an id, and only one
+// key may match, where v2.0 took the first one the token found.
+func selectKeyPair(signers []crypto11.Signer, config *Pkcs11UriConfig) (crypto11.Signer, error) {
+	switch len(signers) {
+	case 0:
+		return nil, fmt.Errorf("no key %s in PKCS11 token", describeKey(config))
+	case 1:
+		return signers[0], nil
+	default:
+		return nil, fmt.Errorf("%d keys %s in PKCS11 token, select one with its id", len(signers), describeKey(config))
+	}
+}
+
+// GetKeyWithURIConfig returns the key of the token of config selected by its
+// id and label, see selectKeyPair. The session of the token is reused by the
+// other keys this process loads from it, until CloseSessions.
 func GetKeyWithURIConfig(config *Pkcs11UriConfig, askForPinIfNeeded bool) (*Key, error) {
-	conf := &crypto11.Config{
-		Path: config.ModulePath,
-		Pin:  config.Pin,
+	// At least one of object and id must be specified.
+	if len(config.KeyLabel) == 0 && len(config.KeyID) == 0 {
+		return nil, errors.New("one of keyLabel and keyID must be set")
+	}
+	ctx, err := session(config, askForPinIfNeeded)
+	if err != nil {
+		return nil, err
 	}
 
+	signers, err := ctx.FindKeyPairs(nonEmpty(config.KeyID), nonEmpty(config.KeyLabel))
+	if err != nil {
+		return nil, err
+	}
+	signer, err := selectKeyPair(signers, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Key's corresponding cert might not exist,
+	// therefore, we do not fail if it is the case.
+	return &Key{ctx: ctx, signer: signer, cert: findKeyCertificate(ctx, signer, config.KeyLabel)}, nil
+}
+
+// findKeyCertificate returns the certificate with the id of signer, or else
+// with label, or nil if there is none.
+func findKeyCertificate(ctx *crypto11.Context, signer crypto11.Signer, label []byte) *x509.Certificate {
+	if id, err := ctx.GetAttribute(signer, crypto11.CkaId); err == nil && id != nil && len(id.Value) != 0 {
+		if cert, _ := ctx.FindCertificate(id.Value, nil, nil); cert != nil {
+			return cert
+		}
+	}
+	if len(label) == 0 {
+		return nil
+	}
+	cert, _ := ctx.FindCertificate(nil, label, nil)
+	return cert
+}
+
+// GetCertificateWithURIConfig returns the certificate object of the token,
+// such as a CA certificate that is a trust anchor, which unlike
+// GetKeyWithURIConfig doesn't need a private key.
+func GetCertificateWithURIConfig(config *Pkcs11UriConfig, askForPinIfNeeded bool) (*x509.Certificate, error) {
 	// At least one of object and id must be specified.
 	if len(config.KeyLabel) == 0 && len(config.KeyID) == 0 {
 		return nil, errors.New("one of keyLabel and keyID must be set")
 	}
+	ctx, err := session(config, askForPinIfNeeded)
+	if err != nil {
+		return nil, err
+	}
+
+	// If both keyID and keyLabel are set, keyID has priority.
+	var cert *x509.Certificate
+	if len(config.KeyID) != 0 {
+		cert, err = ctx.FindCertificate(config.KeyID, nil, nil)
+	} else {
+		cert, err = ctx.FindCertificate(nil, config.KeyLabel, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		return nil, errors.New("certificate not found in PKCS11 token")
+	}
+	return cert, nil
+}
+
+// ListKeys returns the key pairs of the token of config, whose key id and
+// label are ignored.
+func ListKeys(config *Pkcs11UriConfig, askForPinIfNeeded bool) ([]KeyPair, error) {
+	ctx, err := session(config, askForPinIfNeeded)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := ctx.FindAllKeyPairs()
+	if err != nil {
+		return nil, fmt.Errorf("find key pairs: %w", err)
+	}
+
+	keys := make([]KeyPair, 0, len(signers))
+	for _, signer := range signers {
+		attributes, err := ctx.GetAttributes(signer, []crypto11.AttributeType{crypto11.CkaId, crypto11.CkaLabel})
+		if err != nil {
+			return nil, fmt.Errorf("get attributes: %w", err)
+		}
+		key := KeyPair{PublicKey: signer.Public()}
+		if a := attributes[crypto11.CkaId]; a != nil {
+			key.ID = a.Value
+		}
+		if a := attributes[crypto11.CkaLabel]; a != nil {
+			key.Label = a.Value
+		}
+		key.Certificate = findKeyCertificate(ctx, signer, key.Label)
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// configure opens the token of config.
+func configure(config *Pkcs11UriConfig, askForPinIfNeeded bool) (*crypto11.Context, error) {
+	conf := &crypto11.Config{
+		Path: config.ModulePath,
+		Pin:  config.Pin,
+	}
 
 	// At least one of token and slot-id must be specified.
 	if config.TokenLabel == "" && config.SlotID == nil {
@@ -130,7 +308,11 @@ func GetKeyWithURIConfig(config *Pkcs11UriConfig, askForPinIfNeeded bool) (*Key,
 				}
 
 				if tokenInfo.Flags&pkcs11.CKF_LOGIN_REQUIRED == pkcs11.CKF_LOGIN_REQUIRED {
-					fmt.Fprintf(os.Stderr, "Enter PIN for key '%s' in PKCS11 token '%s': ", config.KeyLabel, config.TokenLabel)
+					if len(config.KeyLabel) != 0 {
+						fmt.Fprintf(os.Stderr, "Enter PIN for key '%s' in PKCS11 token '%s': ", config.KeyLabel, tokenInfo.Label)
+					} else {
+						fmt.Fprintf(os.Stderr, "Enter PIN for PKCS11 token '%s': ", tokenInfo.Label)
+					}
 					// Unnecessary convert of syscall.Stdin on *nix, but Windows is a uintptr
 					// nolint:unconvert
 					b, err := term.ReadPassword(int(syscall.Stdin))
@@ -158,32 +340,7 @@ func GetKeyWithURIConfig(config *Pkcs11UriConfig, askForPinIfNeeded bool) (*Key,
 		conf.TokenLabel = config.TokenLabel
 	}
 
-	ctx, err := crypto11.Configure(conf)
-	if err != nil {
-		return nil, err
-	}
-
-	// If both keyID and keyLabel are set, keyID has priority.
-	var signer crypto11.Signer
-	if len(config.KeyID) != 0 {
-		signer, err = ctx.FindKeyPair(config.KeyID, nil)
-	} else if len(config.KeyLabel) != 0 {
-		signer, err = ctx.FindKeyPair(nil, config.KeyLabel)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	// Key's corresponding cert might not exist,
-	// therefore, we do not fail if it is the case.
-	var cert *x509.Certificate
-	if len(config.KeyID) != 0 {
-		cert, _ = ctx.FindCertificate(config.KeyID, nil, nil)
-	} else if len(config.KeyLabel) != 0 {
-		cert, _ = ctx.FindCertificate(nil, config.KeyLabel, nil)
-	}
-
-	return &Key{ctx: ctx, signer: signer, cert: cert}, nil
+	return crypto11.Configure(conf)
 }
 
 func (k *Key) Certificate() (*x509.Certificate, error) {
@@ -259,9 +416,9 @@ func (k *Key) SignerVerifier() (signature.SignerVerifier, error) {
 	return k, nil
 }
 
+// Close releases the key. The session of its token stays open for its other
+// keys until CloseSessions.
 func (k *Key) Close() {
-	k.ctx.Close()
-
 	k.signer = nil
 	k.cert = nil
 	k.ctx = nil
@@ -0,0 +1,95 @@
+//go:build pkcs11key
+// +build pkcs11key
+
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11key
+
+import (
+	"crypto"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ThalesIgnite/crypto11"
+)
+
+// fakeSigner is a key pair of a token.
+type fakeSigner struct {
+	crypto.Signer
+	name string
+}
+
+func (fakeSigner) Delete() error {
+	return errors.New("unimplemented")
+}
+
+func TestSessionKey(t *testing.T) {
+	slot, otherSlot := 1, 2
+	keys := map[string]string{}
+	for name, config := range map[string]*Pkcs11UriConfig{
+		"slot":         {ModulePath: "/usr/lib/softhsm.so", SlotID: &slot, TokenLabel: "token"},
+		"other slot":   {ModulePath: "/usr/lib/softhsm.so", SlotID: &otherSlot, TokenLabel: "token"},
+		"token":        {ModulePath: "/usr/lib/softhsm.so", TokenLabel: "token"},
+		"other token":  {ModulePath: "/usr/lib/softhsm.so", TokenLabel: "other"},
+		"other module": {ModulePath: "/usr/lib/yubihsm.so", TokenLabel: "token"},
+	} {
+		key := sessionKey(config)
+		if other, ok := keys[key]; ok {
+			t.Errorf("%s and %s share the session %q", name, other, key)
+		}
+		keys[key] = name
+	}
+
+	// The keys of a token share its session, whatever selects them.
+	a := &Pkcs11UriConfig{ModulePath: "/usr/lib/softhsm.so", SlotID: &slot, KeyLabel: []byte("a"), Pin: "1234"}
+	b := &Pkcs11UriConfig{ModulePath: "/usr/lib/softhsm.so", SlotID: &slot, TokenLabel: "token", KeyID: []byte{1}}
+	if sessionKey(a) != sessionKey(b) {
+		t.Errorf("the keys of slot %d don't share its session", slot)
+	}
+}
+
+func TestDescribeKey(t *testing.T) {
+	for _, tc := range []struct {
+		config *Pkcs11UriConfig
+		want   string
+	}{
+		{config: &Pkcs11UriConfig{KeyID: []byte{0xab, 0x01}}, want: "with id ab01"},
+		{config: &Pkcs11UriConfig{KeyLabel: []byte("release")}, want: "with label 'release'"},
+		{config: &Pkcs11UriConfig{KeyID: []byte{0xab, 0x01}, KeyLabel: []byte("release")}, want: "with id ab01 and label 'release'"},
+	} {
+		if got := describeKey(tc.config); got != tc.want {
+			t.Errorf("describeKey(%+v) = %q, want %q", tc.config, got, tc.want)
+		}
+	}
+}
+
+func TestSelectKeyPair(t *testing.T) {
+	config := &Pkcs11UriConfig{KeyLabel: []byte("release")}
+	a, b := fakeSigner{name: "a"}, fakeSigner{name: "b"}
+
+	if _, err := selectKeyPair(nil, config); err == nil || !strings.Contains(err.Error(), "no key with label 'release'") {
+		t.Errorf("selectKeyPair() of no key = %v", err)
+	}
+	got, err := selectKeyPair([]crypto11.Signer{a}, config)
+	if err != nil || got.(fakeSigner).name != "a" {
+		t.Errorf("selectKeyPair() = %v, %v, want the key", got, err)
+	}
+	// Keys sharing a label aren't told apart by the order the token lists
+	// them in.
+	if _, err := selectKeyPair([]crypto11.Signer{a, b}, config); err == nil || !strings.Contains(err.Error(), "2 keys with label 'release'") {
+		t.Errorf("selectKeyPair() of two keys = %v, want an error", err)
+	}
+}
@@ -15,6 +15,8 @@
 package pkcs11key
 
 import (
+	"crypto"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/url"
@@ -91,6 +93,15 @@ type Pkcs11UriConfig struct {
 	Pin        string
 }
 
+// KeyPair is a key pair of a PKCS11 token, as listed by ListKeys.
+type KeyPair struct {
+	Label     []byte
+	ID        []byte
+	PublicKey crypto.PublicKey
+	// Certificate is the certificate of the key found in the token, if any.
+	Certificate *x509.Certificate
+}
+
 func NewPkcs11UriConfig() *Pkcs11UriConfig {
 	return &Pkcs11UriConfig{
 		uriPathAttributes:  make(url.Values),
@@ -0,0 +1,191 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// PredicatePlugin registers a custom predicate type, with the JSON schema its
+// predicates must conform to, from a file in the predicate plug-in directory,
+// e.g.
+//
+//	{"name": "build", "predicateType": "https://example.com/build/v1", "schema": {"type": "object"}}
+//
+// The name, if set, can be passed to --type in place of the predicate type.
+type PredicatePlugin struct {
+	Name          string          `json:"name,omitempty"`
+	PredicateType string          `json:"predicateType"`
+	Schema        json.RawMessage `json:"schema"`
+}
+
+// PredicatePluginsDir returns the predicate plug-in directory, which is
+// $COSIGN_PREDICATES_DIR or .cosign/predicates in the user's home directory.
+func PredicatePluginsDir() string {
+	if dir := env.Getenv(env.VariablePredicatesDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cosign", "predicates")
+}
+
+// LoadPredicatePlugins reads the *.json predicate plug-ins in dir, in the
+// order of their file names. A missing directory has no plug-ins.
+func LoadPredicatePlugins(dir string) ([]PredicatePlugin, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var plugins []PredicatePlugin
+	names, types := map[string]string{}, map[string]string{}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading predicate plug-in: %w", err)
+		}
+		plugin := PredicatePlugin{}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&plugin); err != nil {
+			return nil, fmt.Errorf("parsing predicate plug-in %s: %w", file, err)
+		}
+		if _, err := url.ParseRequestURI(plugin.PredicateType); err != nil {
+			return nil, fmt.Errorf("predicate plug-in %s: predicateType %q is not a URI", file, plugin.PredicateType)
+		}
+		if len(plugin.Schema) == 0 {
+			return nil, fmt.Errorf("predicate plug-in %s has no schema", file)
+		}
+		if other, ok := types[plugin.PredicateType]; ok {
+			return nil, fmt.Errorf("predicate plug-ins %s and %s both register %s", other, file, plugin.PredicateType)
+		}
+		types[plugin.PredicateType] = file
+		if plugin.Name != "" {
+			if other, ok := names[plugin.Name]; ok {
+				return nil, fmt.Errorf("predicate plug-ins %s and %s are both named %s", other, file, plugin.Name)
+			}
+			names[plugin.Name] = file
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins, nil
+}
+
+// FindPredicatePlugin returns the plug-in named name in the predicate plug-in
+// directory, or nil if there is none.
+func FindPredicatePlugin(name string) (*PredicatePlugin, error) {
+	plugins, err := LoadPredicatePlugins(PredicatePluginsDir())
+	if err != nil {
+		return nil, err
+	}
+	for i := range plugins {
+		if plugins[i].Name == name {
+			return &plugins[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// LoadPredicateSchemas reads the predicate plug-ins, the predicate schema
+// registry at path, falling back to $COSIGN_PREDICATE_SCHEMAS, and the JSON
+// schemas it lists. The registry overrides the schemas of the plug-ins. It
+// returns nil if neither a plug-in nor a registry is configured.
+//
+// Schemas are files, relative to the registry file, or OCI references of
+// single layer artifacts, which should be pinned by digest.
+func LoadPredicateSchemas(path string, nameOpts []name.Option, regOpts ...ociremote.Option) (*attestation.PredicateSchemas, error) {
+	plugins, err := LoadPredicatePlugins(PredicatePluginsDir())
+	if err != nil {
+		return nil, err
+	}
+	schemas := map[string][]byte{}
+	for _, plugin := range plugins {
+		schemas[plugin.PredicateType] = plugin.Schema
+	}
+
+	if path == "" {
+		path = env.Getenv(env.VariablePredicateSchemas)
+	}
+	if path == "" {
+		if len(schemas) == 0 {
+			return nil, nil
+		}
+		return attestation.NewPredicateSchemas(schemas)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading predicate schema registry: %w", err)
+	}
+	registry := attestation.SchemaRegistry{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&registry); err != nil {
+		return nil, fmt.Errorf("parsing predicate schema registry %s: %w", path, err)
+	}
+
+	for predicateType, location := range registry.PredicateTypes {
+		file := location
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+		if _, err := os.Stat(file); err == nil {
+			if schemas[predicateType], err = os.ReadFile(file); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		ref, err := oci.ParseReference(location, nameOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s of %s is neither a file nor an image reference: %w", location, predicateType, err)
+		}
+		if schemas[predicateType], err = readSingleLayer(ref, regOpts...); err != nil {
+			return nil, fmt.Errorf("fetching schema %s of %s: %w", location, predicateType, err)
+		}
+	}
+	return attestation.NewPredicateSchemas(schemas)
+}
+
+// AddPredicateSchema compiles the JSON schema file at path for predicateType
+// into schemas, replacing the registered schema of predicateType if any.
+func AddPredicateSchema(schemas *attestation.PredicateSchemas, predicateType, path string) (*attestation.PredicateSchemas, error) {
+	if predicateType == "" {
+		return nil, errors.New("a predicate schema needs a predicate type")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading predicate schema: %w", err)
+	}
+	return schemas.Add(predicateType, raw)
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestLoadPredicateSchemas(t *testing.T) {
+	t.Setenv(env.VariablePredicatesDir.String(), t.TempDir())
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	u := strings.TrimPrefix(s.URL, "http://")
+
+	// Publish the deploy schema as a single layer artifact.
+	f, err := static.NewFile([]byte(`{"type": "object", "required": ["env"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.ParseReference(u + "/schemas/deploy:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, f); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "schemas"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schemas", "build.json"), []byte(`{"type": "object", "required": ["builder"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "registry.json")
+	registryJSON := fmt.Sprintf(`{"predicateTypes": {"https://example.com/build/v1": "schemas/build.json", "https://example.com/deploy/v1": %q}}`, ref)
+	if err := os.WriteFile(path, []byte(registryJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, err := LoadPredicateSchemas(path, nil)
+	if err != nil {
+		t.Fatalf("LoadPredicateSchemas() unexpected error: %v", err)
+	}
+	if err := schemas.Validate("https://example.com/build/v1", []byte(`{}`)); err == nil {
+		t.Error("build predicate without builder was accepted")
+	}
+	if err := schemas.Validate("https://example.com/deploy/v1", []byte(`{}`)); err == nil {
+		t.Error("deploy predicate without env was accepted")
+	}
+	if err := schemas.Validate("https://example.com/deploy/v1", []byte(`{"env": "prod"}`)); err != nil {
+		t.Errorf("valid deploy predicate was rejected: %v", err)
+	}
+
+	t.Setenv(env.VariablePredicateSchemas.String(), path)
+	if schemas, err := LoadPredicateSchemas("", nil); err != nil || !schemas.Has("https://example.com/build/v1") {
+		t.Errorf("LoadPredicateSchemas() from the environment = %v, %v", schemas, err)
+	}
+}
+
+func TestLoadPredicateSchemasNone(t *testing.T) {
+	t.Setenv(env.VariablePredicateSchemas.String(), "")
+	t.Setenv(env.VariablePredicatesDir.String(), t.TempDir())
+	schemas, err := LoadPredicateSchemas("", nil)
+	if err != nil || schemas != nil {
+		t.Errorf("LoadPredicateSchemas() = %v, %v, want nil, nil", schemas, err)
+	}
+}
+
+func TestLoadPredicateSchemasUnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	if err := os.WriteFile(path, []byte(`{"predicates": {}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPredicateSchemas(path, nil); err == nil {
+		t.Error("LoadPredicateSchemas() succeeded for a registry with unknown fields")
+	}
+}
+
+func TestLoadPredicatePlugins(t *testing.T) {
+	dir := t.TempDir()
+	write := func(dir, name, content string) string {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	write(dir, "build.json", `{"name": "build", "predicateType": "https://example.com/build/v1", "schema": {"type": "object", "required": ["builder"]}}`)
+	write(dir, "deploy.json", `{"predicateType": "https://example.com/deploy/v1", "schema": {"type": "object", "required": ["env"]}}`)
+	write(dir, "README.md", "not a plug-in")
+	t.Setenv(env.VariablePredicatesDir.String(), dir)
+	t.Setenv(env.VariablePredicateSchemas.String(), "")
+
+	plugins, err := LoadPredicatePlugins(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 2 || plugins[0].Name != "build" || plugins[1].PredicateType != "https://example.com/deploy/v1" {
+		t.Fatalf("LoadPredicatePlugins() = %+v", plugins)
+	}
+	if plugin, err := FindPredicatePlugin("build"); err != nil || plugin == nil || plugin.PredicateType != "https://example.com/build/v1" {
+		t.Errorf("FindPredicatePlugin(build) = %+v, %v", plugin, err)
+	}
+	if plugin, err := FindPredicatePlugin("other"); err != nil || plugin != nil {
+		t.Errorf("FindPredicatePlugin(other) = %+v, %v, want nil", plugin, err)
+	}
+
+	schemas, err := LoadPredicateSchemas("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.Validate("https://example.com/build/v1", []byte(`{}`)); err == nil {
+		t.Error("build predicate without builder was accepted")
+	}
+
+	// The registry overrides the schemas of the plug-ins.
+	registryDir := t.TempDir()
+	write(registryDir, "build.json", `{"type": "object"}`)
+	registry := write(registryDir, "registry.json", `{"predicateTypes": {"https://example.com/build/v1": "build.json"}}`)
+	schemas, err = LoadPredicateSchemas(registry, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.Validate("https://example.com/build/v1", []byte(`{}`)); err != nil {
+		t.Errorf("build predicate was validated against the plug-in schema: %v", err)
+	}
+	if err := schemas.Validate("https://example.com/deploy/v1", []byte(`{}`)); err == nil {
+		t.Error("deploy predicate without env was accepted")
+	}
+
+	// --predicate-schema overrides both.
+	schema := write(t.TempDir(), "schema.json", `{"type": "object", "required": ["steps"]}`)
+	if schemas, err = AddPredicateSchema(schemas, "https://example.com/build/v1", schema); err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.Validate("https://example.com/build/v1", []byte(`{}`)); err == nil {
+		t.Error("build predicate without steps was accepted")
+	}
+	if err := schemas.Validate("https://example.com/deploy/v1", []byte(`{}`)); err == nil {
+		t.Error("AddPredicateSchema() dropped the deploy schema")
+	}
+
+	for name, content := range map[string]string{
+		"no schema":      `{"predicateType": "https://example.com/other/v1"}`,
+		"not a URI":      `{"predicateType": "other", "schema": {}}`,
+		"same type":      `{"predicateType": "https://example.com/build/v1", "schema": {}}`,
+		"same name":      `{"name": "build", "predicateType": "https://example.com/other/v1", "schema": {}}`,
+		"unknown fields": `{"type": "https://example.com/other/v1", "schema": {}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			bad := t.TempDir()
+			write(bad, "build.json", `{"name": "build", "predicateType": "https://example.com/build/v1", "schema": {}}`)
+			write(bad, "other.json", content)
+			if _, err := LoadPredicatePlugins(bad); err == nil {
+				t.Errorf("LoadPredicatePlugins() accepted %s", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	"github.com/sigstore/rekor/pkg/generated/models"
+)
+
+// RekorBundleDir is a directory of Rekor log entries fetched ahead of
+// verification, e.g. by a mirror job with network access, so that signatures
+// without a Rekor bundle can be checked against the transparency log
+// offline. The entries of a signature are in the file RekorBundleFileName of
+// its payload, as Rekor's API returns them: a JSON object of the log entries,
+// with their inclusion proofs, keyed by their UUID.
+type RekorBundleDir string
+
+// RekorBundleFileName returns the name of the file of a RekorBundleDir with the
+// entries of the signature of payload: sha256-<hex of its SHA-256>.json.
+func RekorBundleFileName(payload []byte) string {
+	h := sha256.Sum256(payload)
+	return "sha256-" + hex.EncodeToString(h[:]) + ".json"
+}
+
+// Entries returns the log entries of sig in the directory, keyed by UUID, or
+// nil if it has none.
+func (d RekorBundleDir) Entries(sig oci.Signature) (map[string]models.LogEntryAnon, error) {
+	payload, err := sig.Payload()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(string(d), RekorBundleFileName(payload))
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	entries := models.LogEntry{}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("parsing Rekor entries %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// verifyRekorBundleDirEntry verifies that sig is in the transparency log
+// with an entry of co.RekorBundleDir, checking its inclusion proof and
+// signed entry timestamp with co.RekorPubKeys, and returns the earliest
+// entry of sig. It returns nil if the directory has no entries of sig.
+func verifyRekorBundleDirEntry(ctx context.Context, sig oci.Signature, co *CheckOpts) (*models.LogEntryAnon, error) {
+	entries, err := co.RekorBundleDir.Entries(sig)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	uuids := make([]string, 0, len(entries))
+	for uuid := range entries {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+
+	var earliest *models.LogEntryAnon
+	var errs []string
+	for _, uuid := range uuids {
+		entry := entries[uuid]
+		if err := verifyUUID(uuid, entry); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if err := VerifyTLogEntryOffline(ctx, &entry, co.RekorPubKeys); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		// The entry must be that of sig, as the online search checks.
+		bundled, err := mutate.Signature(sig, mutate.WithBundle(bundle.EntryToBundle(&entry)))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := VerifyBundle(bundled, co); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if earliest == nil || time.Unix(*entry.IntegratedTime, 0).Before(time.Unix(*earliest.IntegratedTime, 0)) {
+			earliest = &entry
+		}
+	}
+	if earliest == nil {
+		return nil, newTypedVerificationError(ErrTlogMissingType, "no valid tlog entries found in %s: %s", co.RekorBundleDir, strings.Join(errs, ", "))
+	}
+	return earliest, nil
+}
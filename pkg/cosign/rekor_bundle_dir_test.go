@@ -0,0 +1,169 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	rtypes "github.com/sigstore/rekor/pkg/types"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+	"github.com/sigstore/sigstore/pkg/tuf"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+func TestRekorBundleDir(t *testing.T) {
+	ctx := context.Background()
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	leafCert, privKey, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", rootCert, rootKey)
+	pemLeaf := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafCert.Raw})
+	rootPool := x509.NewCertPool()
+	rootPool.AddCert(rootCert)
+
+	digest, err := name.NewDigest("registry.example.com/app@sha256:" + strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := v1.NewHash(digest.DigestStr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pld, err := payload.Cosign{Image: digest}.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(pld)
+	raw, _ := privKey.Sign(rand.Reader, sum[:], crypto.SHA256)
+	b64sig := base64.StdEncoding.EncodeToString(raw)
+	// The signature has no Rekor bundle, as if it was signed without one.
+	sig, err := static.NewSignature(pld, b64sig, static.WithCertChain(pemLeaf, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The pre-fetched entry of the signature, in a log with only this entry.
+	rekor, _, err := signature.NewECDSASignerVerifier(elliptic.P256(), rand.Reader, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pe, _ := proposedEntry(b64sig, pld, pemLeaf)
+	entry, _ := rtypes.UnmarshalEntry(pe[0])
+	leaf, _ := entry.Canonicalize(ctx)
+	rekorBundle := CreateTestBundle(ctx, t, rekor, leaf)
+	uuid := hex.EncodeToString(rfc6962.DefaultHasher.HashLeaf(leaf))
+	zero, one := int64(0), int64(1)
+	tlogEntry := func() models.LogEntryAnon {
+		rootHash := uuid
+		return models.LogEntryAnon{
+			Body:           rekorBundle.Payload.Body,
+			IntegratedTime: &rekorBundle.Payload.IntegratedTime,
+			LogIndex:       &rekorBundle.Payload.LogIndex,
+			LogID:          &rekorBundle.Payload.LogID,
+			Verification: &models.LogEntryAnonVerification{
+				SignedEntryTimestamp: strfmt.Base64(rekorBundle.SignedEntryTimestamp),
+				InclusionProof: &models.InclusionProof{
+					LogIndex: &zero,
+					TreeSize: &one,
+					RootHash: &rootHash,
+					Hashes:   []string{},
+				},
+			},
+		}
+	}
+	pemBytes, _ := cryptoutils.MarshalPublicKeyToPEM(rekor.Public())
+	rekorPubKeys := NewTrustedTransparencyLogPubKeys()
+	if err := rekorPubKeys.AddTransparencyLogPubKey(pemBytes, tuf.Active); err != nil {
+		t.Fatal(err)
+	}
+
+	// dir returns a RekorBundleDir with entries for the signature.
+	dir := func(t *testing.T, entries models.LogEntry) RekorBundleDir {
+		t.Helper()
+		d := t.TempDir()
+		if entries != nil {
+			b, err := json.Marshal(entries)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(d, RekorBundleFileName(pld)), b, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return RekorBundleDir(d)
+	}
+	co := func(d RekorBundleDir) *CheckOpts {
+		return &CheckOpts{
+			RootCerts:      rootPool,
+			IgnoreSCT:      true,
+			Offline:        true,
+			Identities:     []Identity{{Subject: "subject@mail.com", Issuer: "oidc-issuer"}},
+			RekorPubKeys:   &rekorPubKeys,
+			ClaimVerifier:  SimpleClaimVerifier,
+			RekorBundleDir: d,
+		}
+	}
+
+	bundleVerified, err := VerifyImageSignature(ctx, sig, h, co(dir(t, models.LogEntry{uuid: tlogEntry()})))
+	if err != nil || !bundleVerified {
+		t.Fatalf("VerifyImageSignature() = %v, %v, want the pre-fetched entry to be verified", bundleVerified, err)
+	}
+
+	tampered := tlogEntry()
+	other := hex.EncodeToString(make([]byte, 32))
+	tampered.Verification.InclusionProof.RootHash = &other
+	for name, tc := range map[string]struct {
+		entries models.LogEntry
+		want    string
+	}{
+		"no entries": {
+			want: "offline verification failed",
+		},
+		"invalid inclusion proof": {
+			entries: models.LogEntry{uuid: tampered},
+			want:    "verifying inclusion proof",
+		},
+		"another UUID": {
+			entries: models.LogEntry{other: tlogEntry()},
+			want:    "no valid tlog entries found",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := VerifyImageSignature(ctx, sig, h, co(dir(t, tc.entries))); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("VerifyImageSignature() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekorv2
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+// Checkpoint is the signed tree head of a transparency log, in the checkpoint
+// format of https://c2sp.org/tlog-checkpoint.
+type Checkpoint struct {
+	// Origin identifies the log, e.g. log2025-1.rekor.sigstore.dev.
+	Origin   string
+	Size     uint64
+	RootHash []byte
+	// Extensions are the optional lines after the root hash.
+	Extensions []string
+}
+
+// NoteSignature is a signature line of a signed note, see
+// https://c2sp.org/signed-note.
+type NoteSignature struct {
+	// Name is the name of the key, which is the origin of the log for the
+	// signature of the log itself.
+	Name string
+	// KeyHint is the key ID the signer claims, which is only a hint to pick
+	// the key and is never trusted.
+	KeyHint   [4]byte
+	Signature []byte
+}
+
+// SignedCheckpoint is a checkpoint with the signatures of the log and of
+// any witnesses.
+type SignedCheckpoint struct {
+	Checkpoint
+	Signatures []NoteSignature
+	// text is the signed part of the note.
+	text []byte
+}
+
+// ParseSignedCheckpoint parses a checkpoint signed note.
+func ParseSignedCheckpoint(note string) (*SignedCheckpoint, error) {
+	text, sigs, ok := strings.Cut(note, "\n\n")
+	if !ok {
+		return nil, errors.New("checkpoint is missing its signatures")
+	}
+	text += "\n"
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if len(lines) < 3 {
+		return nil, errors.New("checkpoint must have an origin, tree size and root hash")
+	}
+	c := &SignedCheckpoint{text: []byte(text)}
+	c.Origin = lines[0]
+	if c.Origin == "" {
+		return nil, errors.New("checkpoint has an empty origin")
+	}
+	size, err := strconv.ParseUint(lines[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint tree size: %w", err)
+	}
+	c.Size = size
+	if c.RootHash, err = base64.StdEncoding.DecodeString(lines[2]); err != nil {
+		return nil, fmt.Errorf("checkpoint root hash: %w", err)
+	}
+	c.Extensions = lines[3:]
+
+	for _, line := range strings.Split(strings.TrimSuffix(sigs, "\n"), "\n") {
+		name, b64, ok := strings.Cut(strings.TrimPrefix(line, "— "), " ")
+		if !ok || !strings.HasPrefix(line, "— ") {
+			return nil, fmt.Errorf("malformed checkpoint signature line %q", line)
+		}
+		raw, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil || len(raw) < 5 {
+			return nil, fmt.Errorf("malformed checkpoint signature of %s", name)
+		}
+		s := NoteSignature{Name: name, Signature: raw[4:]}
+		copy(s.KeyHint[:], raw[:4])
+		c.Signatures = append(c.Signatures, s)
+	}
+	return c, nil
+}
+
+// VerifySignature verifies that the key called name, with public key pub,
+// signed the checkpoint. An empty name matches signatures by any name, as
+// Rekor v1 logs sign with their host name rather than their origin. ECDSA and
+// RSA signatures are over the SHA-256 digest of the note, Ed25519 signatures
+// over the note itself.
+func (c *SignedCheckpoint) VerifySignature(name string, pub crypto.PublicKey) error {
+	v, err := signature.LoadVerifier(pub, crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("loading checkpoint verifier: %w", err)
+	}
+	found := false
+	for _, s := range c.Signatures {
+		if name != "" && s.Name != name {
+			continue
+		}
+		found = true
+		if err := v.VerifySignature(bytes.NewReader(s.Signature), bytes.NewReader(c.text), options.WithCryptoSignerOpts(crypto.SHA256)); err == nil {
+			return nil
+		}
+	}
+	if name == "" {
+		name = "the log"
+	}
+	if !found {
+		return fmt.Errorf("checkpoint has no signature by %s", name)
+	}
+	return fmt.Errorf("checkpoint signature by %s doesn't verify", name)
+}
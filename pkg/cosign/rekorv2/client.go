@@ -0,0 +1,209 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rekorv2 is a client for Rekor v2, the tile-backed Sigstore
+// transparency log. Entries are added with a single write API and proven to
+// be in the log with an inclusion proof to a signed checkpoint, which can also
+// be computed from the tiles of the log, see https://c2sp.org/tlog-tiles.
+package rekorv2
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// APIVersion is the major version of the Rekor API this client speaks.
+const APIVersion = 2
+
+// Client adds entries to a Rekor v2 log and reads it as tiles.
+type Client struct {
+	url        *url.URL
+	httpClient *http.Client
+	userAgent  string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests.
+func WithUserAgent(ua string) Option {
+	return func(cl *Client) {
+		cl.userAgent = ua
+	}
+}
+
+// NewClient returns a client of the Rekor v2 log at logURL, whose scheme may
+// have the TileScheme prefix.
+func NewClient(logURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(logURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Rekor v2 URL: %w", err)
+	}
+	u.Scheme = strings.TrimPrefix(u.Scheme, TileScheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("URL %s of the Rekor v2 log must be http or https", logURL)
+	}
+	c := &Client{url: u, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+type hashedRekordRequest struct {
+	Digest    []byte    `json:"digest"`
+	Signature Signature `json:"signature"`
+}
+
+type dsseRequest struct {
+	Envelope  json.RawMessage `json:"envelope"`
+	Verifiers []Verifier      `json:"verifiers"`
+}
+
+// AddHashedRekord adds a hashedrekord entry of the signature sig over the
+// SHA-256 digest of an artifact, verified by v.
+func (c *Client) AddHashedRekord(ctx context.Context, digest, sig []byte, v Verifier) (*Entry, error) {
+	return c.add(ctx, map[string]interface{}{
+		"hashedRekordRequestV002": hashedRekordRequest{
+			Digest:    digest,
+			Signature: Signature{Content: sig, Verifier: v},
+		},
+	})
+}
+
+// AddDSSE adds a dsse entry of the DSSE envelope, whose signatures are
+// verified by verifiers.
+func (c *Client) AddDSSE(ctx context.Context, envelope []byte, verifiers []Verifier) (*Entry, error) {
+	return c.add(ctx, map[string]interface{}{
+		"dsseRequestV002": dsseRequest{Envelope: envelope, Verifiers: verifiers},
+	})
+}
+
+func (c *Client) add(ctx context.Context, request interface{}) (*Entry, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	u := c.url.JoinPath("api", "v2", "log", "entries")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("adding entry to %s: %w", c.url.Host, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("adding entry to %s: %s: %s", c.url.Host, resp.Status, strings.TrimSpace(string(raw)))
+	}
+	e := &Entry{}
+	if err := json.Unmarshal(raw, e); err != nil {
+		return nil, fmt.Errorf("parsing entry returned by %s: %w", c.url.Host, err)
+	}
+	return e, nil
+}
+
+// VerifierFromPEM returns the verifier of a PEM encoded certificate or public
+// key, as sign commands have them.
+func VerifierFromPEM(pemBytes []byte) (Verifier, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return Verifier{}, errors.New("no PEM encoded certificate or public key found")
+	}
+	var v Verifier
+	var pub crypto.PublicKey
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return Verifier{}, err
+		}
+		v.X509Certificate = &RawBytes{RawBytes: cert.Raw}
+		pub = cert.PublicKey
+	} else {
+		key, err := cryptoutils.UnmarshalPEMToPublicKey(pemBytes)
+		if err != nil {
+			return Verifier{}, err
+		}
+		der, err := cryptoutils.MarshalPublicKeyToDER(key)
+		if err != nil {
+			return Verifier{}, err
+		}
+		v.PublicKey = &RawBytes{RawBytes: der}
+		pub = key
+	}
+	details, err := keyDetails(pub)
+	if err != nil {
+		return Verifier{}, err
+	}
+	v.KeyDetails = details
+	return v, nil
+}
+
+// keyDetails returns the PublicKeyDetails name of the algorithm cosign signs
+// with for a key.
+func keyDetails(pub crypto.PublicKey) (string, error) {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return "PKIX_ECDSA_P256_SHA_256", nil
+		case elliptic.P384():
+			return "PKIX_ECDSA_P384_SHA_384", nil
+		case elliptic.P521():
+			return "PKIX_ECDSA_P521_SHA_512", nil
+		}
+	case *rsa.PublicKey:
+		switch k.Size() * 8 {
+		case 2048, 3072, 4096:
+			return fmt.Sprintf("PKIX_RSA_PKCS1V15_%d_SHA256", k.Size()*8), nil
+		}
+	case ed25519.PublicKey:
+		return "PKIX_ED25519", nil
+	}
+	return "", fmt.Errorf("unsupported key type %T for Rekor v2", pub)
+}
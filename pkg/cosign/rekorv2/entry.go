@@ -0,0 +1,187 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekorv2
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/transparency-dev/merkle/proof"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+// Entry is an entry of a Rekor v2 log with its proof of inclusion, in the
+// JSON encoding of the TransparencyLogEntry message of
+// https://github.com/sigstore/protobuf-specs. Unlike Rekor v1 entries it has
+// no signed entry timestamp: the inclusion proof and the signed checkpoint it
+// leads to prove the entry is in the log.
+type Entry struct {
+	LogIndex          int64          `json:"logIndex,string"`
+	LogID             LogID          `json:"logId"`
+	KindVersion       KindVersion    `json:"kindVersion"`
+	IntegratedTime    int64          `json:"integratedTime,string,omitempty"`
+	InclusionProof    InclusionProof `json:"inclusionProof"`
+	CanonicalizedBody []byte         `json:"canonicalizedBody"`
+}
+
+// LogID identifies a log by the SHA-256 digest of its DER encoded public key.
+type LogID struct {
+	KeyID []byte `json:"keyId"`
+}
+
+// KindVersion is the type of an entry.
+type KindVersion struct {
+	Kind    string `json:"kind"`
+	Version string `json:"version"`
+}
+
+// InclusionProof proves that an entry is in the tree of the checkpoint.
+type InclusionProof struct {
+	LogIndex   int64              `json:"logIndex,string"`
+	RootHash   []byte             `json:"rootHash"`
+	TreeSize   int64              `json:"treeSize,string"`
+	Hashes     [][]byte           `json:"hashes"`
+	Checkpoint CheckpointEnvelope `json:"checkpoint"`
+}
+
+// CheckpointEnvelope is the signed note of the tree head an inclusion proof
+// leads to.
+type CheckpointEnvelope struct {
+	Envelope string `json:"envelope"`
+}
+
+// Verify verifies that the entry is in the log whose key is pub: that the
+// checkpoint is signed by the log, and that the inclusion proof leads from
+// the entry to the root hash of the checkpoint. It returns the checkpoint.
+func (e *Entry) Verify(pub crypto.PublicKey) (*SignedCheckpoint, error) {
+	p := e.InclusionProof
+	if p.LogIndex < 0 || p.TreeSize <= p.LogIndex {
+		return nil, fmt.Errorf("entry index %d is outside the tree of size %d", p.LogIndex, p.TreeSize)
+	}
+	cp, err := VerifyCheckpoint(p.Checkpoint.Envelope, p.RootHash, uint64(p.TreeSize), pub)
+	if err != nil {
+		return nil, err
+	}
+	leaf := rfc6962.DefaultHasher.HashLeaf(e.CanonicalizedBody)
+	if err := proof.VerifyInclusion(rfc6962.DefaultHasher, uint64(p.LogIndex), uint64(p.TreeSize), leaf, p.Hashes, cp.RootHash); err != nil {
+		return nil, fmt.Errorf("verifying inclusion proof: %w", err)
+	}
+	return cp, nil
+}
+
+// VerifyCheckpoint parses the checkpoint note of an inclusion proof for the
+// tree of treeSize with rootHash and verifies that it's signed by the log
+// whose key is pub. Rekor v1 inclusion proofs have checkpoints too.
+func VerifyCheckpoint(note string, rootHash []byte, treeSize uint64, pub crypto.PublicKey) (*SignedCheckpoint, error) {
+	if note == "" {
+		return nil, errors.New("inclusion proof has no checkpoint")
+	}
+	cp, err := ParseSignedCheckpoint(note)
+	if err != nil {
+		return nil, err
+	}
+	if err := cp.VerifySignature("", pub); err != nil {
+		return nil, err
+	}
+	if cp.Size != treeSize || !bytes.Equal(cp.RootHash, rootHash) {
+		return nil, fmt.Errorf("inclusion proof for tree size %d doesn't match the checkpoint of tree size %d", treeSize, cp.Size)
+	}
+	return cp, nil
+}
+
+// HashedRekord is the body of a hashedrekord v0.0.2 entry.
+type HashedRekord struct {
+	Data      HashOutput `json:"data"`
+	Signature Signature  `json:"signature"`
+}
+
+// HashOutput is a digest and its algorithm, e.g. SHA2_256.
+type HashOutput struct {
+	Algorithm string `json:"algorithm"`
+	Digest    []byte `json:"digest"`
+}
+
+// Signature is a signature with the key or certificate that verifies it.
+type Signature struct {
+	Content  []byte   `json:"content"`
+	Verifier Verifier `json:"verifier"`
+}
+
+// Verifier is a public key or certificate, with the algorithm it verifies
+// signatures with, e.g. PKIX_ECDSA_P256_SHA_256.
+type Verifier struct {
+	PublicKey       *RawBytes `json:"publicKey,omitempty"`
+	X509Certificate *RawBytes `json:"x509Certificate,omitempty"`
+	KeyDetails      string    `json:"keyDetails"`
+}
+
+// RawBytes is the DER encoding of a public key or certificate.
+type RawBytes struct {
+	RawBytes []byte `json:"rawBytes"`
+}
+
+// raw returns the DER encoded key or certificate.
+func (v Verifier) raw() []byte {
+	switch {
+	case v.X509Certificate != nil:
+		return v.X509Certificate.RawBytes
+	case v.PublicKey != nil:
+		return v.PublicKey.RawBytes
+	}
+	return nil
+}
+
+type entryBody struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Spec       struct {
+		HashedRekordV002 *HashedRekord `json:"hashedRekordV002"`
+	} `json:"spec"`
+}
+
+// HashedRekord returns the body of a hashedrekord entry.
+func (e *Entry) HashedRekord() (*HashedRekord, error) {
+	body := entryBody{}
+	if err := json.Unmarshal(e.CanonicalizedBody, &body); err != nil {
+		return nil, fmt.Errorf("parsing entry body: %w", err)
+	}
+	if body.Kind != "hashedrekord" || body.Spec.HashedRekordV002 == nil {
+		return nil, fmt.Errorf("entry is a %s %s entry, not a hashedrekord 0.0.2 entry", body.Kind, body.APIVersion)
+	}
+	return body.Spec.HashedRekordV002, nil
+}
+
+// MatchHashedRekord returns an error unless the entry is a hashedrekord of
+// the SHA-256 digest and signature. The verifier, the DER encoded public key
+// or certificate, is only compared if it's not nil.
+func (e *Entry) MatchHashedRekord(digest, sig, verifier []byte) error {
+	h, err := e.HashedRekord()
+	if err != nil {
+		return err
+	}
+	switch {
+	case h.Data.Algorithm != "SHA2_256" || !bytes.Equal(h.Data.Digest, digest):
+		return errors.New("the transparency log entry is for a different digest")
+	case !bytes.Equal(h.Signature.Content, sig):
+		return errors.New("the transparency log entry is for a different signature")
+	case verifier != nil && !bytes.Equal(h.Signature.Verifier.raw(), verifier):
+		return errors.New("the transparency log entry is for a different key or certificate")
+	}
+	return nil
+}
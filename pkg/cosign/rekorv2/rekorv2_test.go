@@ -0,0 +1,219 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekorv2
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/transparency-dev/merkle/rfc6962"
+	"github.com/transparency-dev/merkle/testonly"
+)
+
+const testOrigin = "log.example.com"
+
+// signNote signs the checkpoint text with key, as the log named name.
+func signNote(t *testing.T, text, name string, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(text))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("— %s %s\n", name, base64.StdEncoding.EncodeToString(append([]byte{0, 0, 0, 0}, sig...)))
+}
+
+// fakeLog serves the Rekor v2 write API of a log signing checkpoints with
+// key, which starts with the entries in prefill.
+func fakeLog(t *testing.T, key *ecdsa.PrivateKey, prefill int) *httptest.Server {
+	t.Helper()
+	tree := testonly.New(rfc6962.DefaultHasher)
+	for i := 0; i < prefill; i++ {
+		tree.AppendData([]byte(fmt.Sprintf("entry %d", i)))
+	}
+	der, err := cryptoutils.MarshalPublicKeyToDER(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	logID := sha256.Sum256(der)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v2/log/entries" {
+			http.NotFound(w, r)
+			return
+		}
+		req := struct {
+			HashedRekordRequestV002 *hashedRekordRequest `json:"hashedRekordRequestV002"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.HashedRekordRequestV002 == nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		body := entryBody{APIVersion: "0.0.2", Kind: "hashedrekord"}
+		body.Spec.HashedRekordV002 = &HashedRekord{
+			Data:      HashOutput{Algorithm: "SHA2_256", Digest: req.HashedRekordRequestV002.Digest},
+			Signature: req.HashedRekordRequestV002.Signature,
+		}
+		canonical, _ := json.Marshal(body)
+		tree.AppendData(canonical)
+		index, size := tree.Size()-1, tree.Size()
+		hashes, err := tree.InclusionProof(index, size)
+		if err != nil {
+			t.Error(err)
+		}
+		text := fmt.Sprintf("%s\n%d\n%s\n", testOrigin, size, base64.StdEncoding.EncodeToString(tree.Hash()))
+		_ = json.NewEncoder(w).Encode(Entry{
+			LogIndex:    int64(index),
+			LogID:       LogID{KeyID: logID[:]},
+			KindVersion: KindVersion{Kind: "hashedrekord", Version: "0.0.2"},
+			InclusionProof: InclusionProof{
+				LogIndex:   int64(index),
+				RootHash:   tree.Hash(),
+				TreeSize:   int64(size),
+				Hashes:     hashes,
+				Checkpoint: CheckpointEnvelope{Envelope: text + "\n" + signNote(t, text, testOrigin, key)},
+			},
+			CanonicalizedBody: canonical,
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestAddHashedRekord(t *testing.T) {
+	logKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemBytes, err := cryptoutils.MarshalPublicKeyToPEM(signer.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := VerifierFromPEM(pemBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifier.KeyDetails != "PKIX_ECDSA_P256_SHA_256" || verifier.PublicKey == nil {
+		t.Fatalf("VerifierFromPEM() = %+v", verifier)
+	}
+	digest := sha256.Sum256([]byte("artifact"))
+	sig, err := ecdsa.SignASN1(rand.Reader, signer, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, prefill := range []int{0, 6} {
+		s := fakeLog(t, logKey, prefill)
+		c, err := NewClient(s.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := c.AddHashedRekord(context.Background(), digest[:], sig, verifier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.LogIndex != int64(prefill) {
+			t.Errorf("LogIndex = %d, want %d", e.LogIndex, prefill)
+		}
+		cp, err := e.Verify(logKey.Public())
+		if err != nil {
+			t.Fatalf("Verify() = %v", err)
+		}
+		if cp.Origin != testOrigin || cp.Size != uint64(prefill+1) {
+			t.Errorf("checkpoint = %s %d", cp.Origin, cp.Size)
+		}
+		if err := e.MatchHashedRekord(digest[:], sig, verifier.PublicKey.RawBytes); err != nil {
+			t.Errorf("MatchHashedRekord() = %v", err)
+		}
+		other := sha256.Sum256([]byte("other"))
+		if err := e.MatchHashedRekord(other[:], sig, nil); err == nil {
+			t.Error("MatchHashedRekord() matched another digest")
+		}
+
+		// Entries survive a round trip through a bundle.
+		raw, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := &Entry{}
+		if err := json.Unmarshal(raw, decoded); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := decoded.Verify(logKey.Public()); err != nil {
+			t.Errorf("Verify() after a round trip = %v", err)
+		}
+
+		wrongKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.Verify(wrongKey.Public()); err == nil {
+			t.Error("Verify() succeeded with another log key")
+		}
+		tampered := *e
+		tampered.CanonicalizedBody = append([]byte(" "), e.CanonicalizedBody...)
+		if _, err := tampered.Verify(logKey.Public()); err == nil {
+			t.Error("Verify() succeeded with a tampered body")
+		}
+	}
+}
+
+func TestParseSignedCheckpoint(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := testOrigin + "\n3\n" + base64.StdEncoding.EncodeToString(make([]byte, 32)) + "\nextension\n"
+	note := text + "\n" + signNote(t, text, testOrigin, key) + signNote(t, text, "witness.example.com", key)
+	cp, err := ParseSignedCheckpoint(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Size != 3 || len(cp.RootHash) != 32 || len(cp.Extensions) != 1 || len(cp.Signatures) != 2 {
+		t.Errorf("ParseSignedCheckpoint() = %+v", cp)
+	}
+	if err := cp.VerifySignature("witness.example.com", key.Public()); err != nil {
+		t.Errorf("VerifySignature() = %v", err)
+	}
+	if err := cp.VerifySignature("unknown.example.com", key.Public()); err == nil {
+		t.Error("VerifySignature() succeeded without a signature by the key")
+	}
+
+	for _, bad := range []string{
+		text,
+		"\n3\nAAAA\n\n— a AAAAAAA=\n",
+		testOrigin + "\nthree\nAAAA\n\n— a AAAAAAA=\n",
+		strings.Replace(note, "— ", "- ", 1),
+	} {
+		if _, err := ParseSignedCheckpoint(bad); err == nil {
+			t.Errorf("ParseSignedCheckpoint(%q) succeeded", bad)
+		}
+	}
+}
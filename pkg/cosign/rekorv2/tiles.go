@@ -0,0 +1,204 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekorv2
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/transparency-dev/merkle/compact"
+	"github.com/transparency-dev/merkle/proof"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+const (
+	// TileScheme prefixes the scheme of a log URL, as in
+	// tiles+https://log.example.com, to read the log as tiles rather than
+	// through the Rekor v1 API.
+	TileScheme = "tiles+"
+
+	// tileHeight is the number of tree levels a tile spans, and tileWidth the
+	// number of hashes of a full tile, see https://c2sp.org/tlog-tiles.
+	tileHeight = 8
+	tileWidth  = 1 << tileHeight
+)
+
+// LogReader reads the latest checkpoint of a log and the hashes of its tree.
+type LogReader interface {
+	// Checkpoint returns the latest checkpoint of the log, whose signatures
+	// aren't verified.
+	Checkpoint(ctx context.Context) (*SignedCheckpoint, error)
+	// InclusionProof returns the proof that the entry at index is in the tree
+	// of size treeSize.
+	InclusionProof(ctx context.Context, index, treeSize uint64) ([][]byte, error)
+}
+
+var _ LogReader = (*Client)(nil)
+
+// Checkpoint reads the checkpoint of the log, as a tile-based log serves it
+// at <log URL>/checkpoint.
+func (c *Client) Checkpoint(ctx context.Context) (*SignedCheckpoint, error) {
+	raw, err := c.get(ctx, "checkpoint")
+	if err != nil {
+		return nil, err
+	}
+	cp, err := ParseSignedCheckpoint(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parsing checkpoint of %s: %w", c.url.Host, err)
+	}
+	return cp, nil
+}
+
+// InclusionProof computes the inclusion proof of the entry at index from the
+// tiles of the tree of size treeSize.
+func (c *Client) InclusionProof(ctx context.Context, index, treeSize uint64) ([][]byte, error) {
+	nodes, err := proof.Inclusion(index, treeSize)
+	if err != nil {
+		return nil, err
+	}
+	tiles := map[string][][]byte{}
+	hashes := make([][]byte, 0, len(nodes.IDs))
+	for _, id := range nodes.IDs {
+		h, err := c.nodeHash(ctx, id, treeSize, tiles)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, h)
+	}
+	return nodes.Rehash(hashes, rfc6962.DefaultHasher.HashChildren)
+}
+
+// nodeHash returns the hash of the complete subtree id of the tree of size
+// treeSize, from the tile holding its leftmost hash at the bottom level of
+// the tile. Tiles are cached in tiles by path.
+func (c *Client) nodeHash(ctx context.Context, id compact.NodeID, treeSize uint64, tiles map[string][][]byte) ([]byte, error) {
+	level := id.Level / tileHeight
+	// The subtree is the hashes [begin, end) of the bottom level of the tile.
+	sub := id.Level % tileHeight
+	first := id.Index << sub
+	n := first / tileWidth
+	begin, end := first%tileWidth, first%tileWidth+1<<sub
+
+	width := (treeSize >> (level * tileHeight)) - n*tileWidth
+	if width > tileWidth {
+		width = tileWidth
+	}
+	path := tilePath(level, n, width)
+	tile, ok := tiles[path]
+	if !ok {
+		raw, err := c.get(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		size := rfc6962.DefaultHasher.Size()
+		if uint64(len(raw)) != width*uint64(size) {
+			return nil, fmt.Errorf("tile %s of %s has %d bytes, want %d hashes", path, c.url.Host, len(raw), width)
+		}
+		for i := 0; i < len(raw); i += size {
+			tile = append(tile, raw[i:i+size])
+		}
+		tiles[path] = tile
+	}
+	if end > uint64(len(tile)) {
+		return nil, fmt.Errorf("tile %s of %s doesn't cover node %d at level %d", path, c.url.Host, id.Index, id.Level)
+	}
+	return subtreeHash(tile[begin:end]), nil
+}
+
+// subtreeHash returns the root hash of the perfect subtree of the hashes,
+// whose number is a power of two.
+func subtreeHash(hashes [][]byte) []byte {
+	for len(hashes) > 1 {
+		parents := make([][]byte, 0, len(hashes)/2)
+		for i := 0; i < len(hashes); i += 2 {
+			parents = append(parents, rfc6962.DefaultHasher.HashChildren(hashes[i], hashes[i+1]))
+		}
+		hashes = parents
+	}
+	return hashes[0]
+}
+
+// tilePath returns the path of the tile n at level, of width hashes, e.g.
+// tile/0/x001/234 or tile/1/067.p/12 for a partial tile.
+func tilePath(level uint, n, width uint64) string {
+	index := fmt.Sprintf("%03d", n%1000)
+	for n >= 1000 {
+		n /= 1000
+		index = fmt.Sprintf("x%03d/%s", n%1000, index)
+	}
+	path := fmt.Sprintf("tile/%d/%s", level, index)
+	if width < tileWidth {
+		path += fmt.Sprintf(".p/%d", width)
+	}
+	return path
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url.JoinPath(path).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s of %s: %w", path, c.url.Host, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading %s of %s: %s: %s", path, c.url.Host, resp.Status, strings.TrimSpace(string(raw)))
+	}
+	return raw, nil
+}
+
+// VerifyWithLog verifies that the entry is in the log whose key is pub
+// against the latest checkpoint r reads, rather than the checkpoint of its
+// inclusion proof, which may be missing or not cosigned by witnesses yet. It
+// returns the latest checkpoint.
+func (e *Entry) VerifyWithLog(ctx context.Context, r LogReader, pub crypto.PublicKey) (*SignedCheckpoint, error) {
+	if e.LogIndex < 0 {
+		return nil, fmt.Errorf("entry has the negative index %d", e.LogIndex)
+	}
+	cp, err := r.Checkpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := cp.VerifySignature("", pub); err != nil {
+		return nil, err
+	}
+	index := uint64(e.LogIndex)
+	if cp.Size <= index {
+		return nil, fmt.Errorf("entry index %d is outside the tree of size %d of the latest checkpoint", index, cp.Size)
+	}
+	hashes, err := r.InclusionProof(ctx, index, cp.Size)
+	if err != nil {
+		return nil, fmt.Errorf("reading inclusion proof: %w", err)
+	}
+	leaf := rfc6962.DefaultHasher.HashLeaf(e.CanonicalizedBody)
+	if err := proof.VerifyInclusion(rfc6962.DefaultHasher, index, cp.Size, leaf, hashes, cp.RootHash); err != nil {
+		return nil, fmt.Errorf("verifying inclusion proof: %w", err)
+	}
+	return cp, nil
+}
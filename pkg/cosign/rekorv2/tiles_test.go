@@ -0,0 +1,194 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekorv2
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/transparency-dev/merkle/rfc6962"
+	"github.com/transparency-dev/merkle/testonly"
+)
+
+// fakeTileLog serves the checkpoint and tiles of a log of size entries,
+// signing the checkpoint with key. Only the tiles of the current tree size
+// are served, as the spec requires clients to ask for.
+func fakeTileLog(t *testing.T, key *ecdsa.PrivateKey, size int) (*httptest.Server, *testonly.Tree) {
+	t.Helper()
+	tree := testonly.New(rfc6962.DefaultHasher)
+	for i := 0; i < size; i++ {
+		tree.AppendData([]byte(fmt.Sprintf("entry %d", i)))
+	}
+	nodes := map[[2]uint64][]byte{}
+	var node func(level, index uint64) []byte
+	node = func(level, index uint64) []byte {
+		if level == 0 {
+			return tree.LeafHash(index)
+		}
+		k := [2]uint64{level, index}
+		if h, ok := nodes[k]; ok {
+			return h
+		}
+		h := rfc6962.DefaultHasher.HashChildren(node(level-1, 2*index), node(level-1, 2*index+1))
+		nodes[k] = h
+		return h
+	}
+	files := map[string][]byte{}
+	for level := uint(0); uint64(size)>>(level*tileHeight) > 0; level++ {
+		count := uint64(size) >> (level * tileHeight)
+		for n := uint64(0); n*tileWidth < count; n++ {
+			width := count - n*tileWidth
+			if width > tileWidth {
+				width = tileWidth
+			}
+			var tile []byte
+			for i := uint64(0); i < width; i++ {
+				tile = append(tile, node(uint64(level)*tileHeight, n*tileWidth+i)...)
+			}
+			files["/"+tilePath(level, n, width)] = tile
+		}
+	}
+	text := fmt.Sprintf("%s\n%d\n%s\n", testOrigin, size, base64.StdEncoding.EncodeToString(tree.Hash()))
+	files["/checkpoint"] = []byte(text + "\n" + signNote(t, text, testOrigin, key))
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, ok := files[r.URL.Path]
+		if r.Method != http.MethodGet || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(b)
+	}))
+	t.Cleanup(s.Close)
+	return s, tree
+}
+
+func TestTilePath(t *testing.T) {
+	for _, tc := range []struct {
+		level    uint
+		n, width uint64
+		want     string
+	}{
+		{0, 0, tileWidth, "tile/0/000"},
+		{1, 67, 12, "tile/1/067.p/12"},
+		{0, 1234, tileWidth, "tile/0/x001/234"},
+		{2, 1234067, 1, "tile/2/x001/x234/067.p/1"},
+	} {
+		if got := tilePath(tc.level, tc.n, tc.width); got != tc.want {
+			t.Errorf("tilePath(%d, %d, %d) = %q, want %q", tc.level, tc.n, tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestInclusionProofFromTiles(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	// 1000 entries have a partial tile at both levels, and 256 entries a
+	// single full tile.
+	for _, size := range []int{1, 5, 256, 1000} {
+		s, tree := fakeTileLog(t, key, size)
+		c, err := NewClient(TileScheme + s.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cp, err := c.Checkpoint(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cp.Size != uint64(size) || !bytes.Equal(cp.RootHash, tree.Hash()) {
+			t.Fatalf("Checkpoint() = %d %x, want the tree of size %d", cp.Size, cp.RootHash, size)
+		}
+		for _, index := range []uint64{0, 1, 255, 256, 511, 767, 998, 999} {
+			if index >= uint64(size) {
+				continue
+			}
+			got, err := c.InclusionProof(ctx, index, uint64(size))
+			if err != nil {
+				t.Fatalf("InclusionProof(%d, %d) = %v", index, size, err)
+			}
+			want, err := tree.InclusionProof(index, uint64(size))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("InclusionProof(%d, %d) has %d hashes, want %d", index, size, len(got), len(want))
+			}
+			for i := range want {
+				if !bytes.Equal(got[i], want[i]) {
+					t.Errorf("InclusionProof(%d, %d) hash %d = %x, want %x", index, size, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestEntryVerifyWithLog(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	s, _ := fakeTileLog(t, key, 300)
+	c, err := NewClient(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The entry has no inclusion proof of its own.
+	e := &Entry{LogIndex: 260, CanonicalizedBody: []byte("entry 260")}
+	if _, err := e.Verify(key.Public()); err == nil {
+		t.Fatal("Verify() succeeded without an inclusion proof")
+	}
+	cp, err := e.VerifyWithLog(ctx, c, key.Public())
+	if err != nil {
+		t.Fatalf("VerifyWithLog() = %v", err)
+	}
+	if cp.Size != 300 {
+		t.Errorf("VerifyWithLog() checkpoint size = %d, want 300", cp.Size)
+	}
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		e    *Entry
+		key  *ecdsa.PrivateKey
+		want string
+	}{
+		"another log key":  {e: e, key: other, want: "doesn't verify"},
+		"another body":     {e: &Entry{LogIndex: 260, CanonicalizedBody: []byte("entry 261")}, key: key, want: "verifying inclusion proof"},
+		"outside the tree": {e: &Entry{LogIndex: 300, CanonicalizedBody: []byte("entry 300")}, key: key, want: "outside the tree"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tc.e.VerifyWithLog(ctx, c, tc.key.Public()); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("VerifyWithLog() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekorv2
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Signature types of note verifier keys, see https://c2sp.org/signed-note.
+const (
+	sigTypeEd25519     = 0x01
+	sigTypeCosignature = 0x04
+)
+
+// Witness is a third party that cosigns the checkpoints of a log it has
+// checked to be consistent with the checkpoints it cosigned before.
+type Witness struct {
+	Name      string
+	KeyID     [4]byte
+	PublicKey ed25519.PublicKey
+	// cosignature is set for keys that make timestamped
+	// https://c2sp.org/tlog-cosignature signatures, and unset for keys that
+	// make plain Ed25519 note signatures.
+	cosignature bool
+}
+
+// ParseWitnessKey parses a note verifier key of the form
+// <name>+<key ID>+<base64 signature type and Ed25519 public key>.
+func ParseWitnessKey(vkey string) (Witness, error) {
+	parts := strings.SplitN(strings.TrimSpace(vkey), "+", 3)
+	if len(parts) != 3 || parts[0] == "" {
+		return Witness{}, fmt.Errorf("witness key %q is not of the form <name>+<key ID>+<key>", vkey)
+	}
+	name := parts[0]
+	hint, err := hex.DecodeString(parts[1])
+	if err != nil || len(hint) != 4 {
+		return Witness{}, fmt.Errorf("witness key %s has a malformed key ID", name)
+	}
+	key, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil || len(key) != 1+ed25519.PublicKeySize {
+		return Witness{}, fmt.Errorf("witness key %s has a malformed Ed25519 public key", name)
+	}
+	if key[0] != sigTypeEd25519 && key[0] != sigTypeCosignature {
+		return Witness{}, fmt.Errorf("witness key %s has the unsupported signature type %d", name, key[0])
+	}
+	w := Witness{Name: name, PublicKey: ed25519.PublicKey(key[1:]), cosignature: key[0] == sigTypeCosignature}
+	copy(w.KeyID[:], hint)
+	if w.KeyID != keyID(name, key) {
+		return Witness{}, fmt.Errorf("witness key %s has a key ID that doesn't match its key", name)
+	}
+	return w, nil
+}
+
+// ParseWitnessKeys parses one witness key per line of r, skipping empty
+// lines and lines starting with #.
+func ParseWitnessKeys(r io.Reader) ([]Witness, error) {
+	var witnesses []Witness
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		w, err := ParseWitnessKey(line)
+		if err != nil {
+			return nil, err
+		}
+		witnesses = append(witnesses, w)
+	}
+	return witnesses, s.Err()
+}
+
+// keyID returns the ID of the key made of a signature type and public key.
+func keyID(name string, key []byte) [4]byte {
+	h := sha256.New()
+	h.Write([]byte(name))
+	h.Write([]byte("\n"))
+	h.Write(key)
+	var id [4]byte
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+// verify reports whether s is a valid signature of the checkpoint by w.
+func (w Witness) verify(c *SignedCheckpoint, s NoteSignature) bool {
+	if s.Name != w.Name || s.KeyHint != w.KeyID {
+		return false
+	}
+	if !w.cosignature {
+		return ed25519.Verify(w.PublicKey, c.text, s.Signature)
+	}
+	// A cosignature is the big-endian timestamp it was made at followed by
+	// the signature over the timestamp and the checkpoint.
+	if len(s.Signature) != 8+ed25519.SignatureSize {
+		return false
+	}
+	ts := binary.BigEndian.Uint64(s.Signature[:8])
+	msg := bytes.NewBufferString("cosignature/v1\ntime " + strconv.FormatUint(ts, 10) + "\n")
+	msg.Write(c.text)
+	return ed25519.Verify(w.PublicKey, msg.Bytes(), s.Signature[8:])
+}
+
+// WitnessPolicy requires checkpoints to be cosigned by at least Min of the
+// Witnesses.
+type WitnessPolicy struct {
+	Witnesses []Witness
+	Min       int
+}
+
+// Verify returns an error unless the checkpoint is cosigned by enough
+// witnesses of the policy.
+func (p *WitnessPolicy) Verify(c *SignedCheckpoint) error {
+	if p.Min < 1 || p.Min > len(p.Witnesses) {
+		return fmt.Errorf("can't require %d of %d witnesses", p.Min, len(p.Witnesses))
+	}
+	cosigned := 0
+	seen := map[string]bool{}
+	for _, w := range p.Witnesses {
+		id := w.Name + "+" + hex.EncodeToString(w.KeyID[:])
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		for _, s := range c.Signatures {
+			if w.verify(c, s) {
+				cosigned++
+				break
+			}
+		}
+	}
+	if cosigned < p.Min {
+		return fmt.Errorf("checkpoint %d of %s is cosigned by %d of the %d required witnesses", c.Size, c.Origin, cosigned, p.Min)
+	}
+	return nil
+}
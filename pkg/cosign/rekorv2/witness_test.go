@@ -0,0 +1,124 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekorv2
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testWitness is a witness with its signing key.
+type testWitness struct {
+	vkey string
+	name string
+	id   [4]byte
+	priv ed25519.PrivateKey
+	typ  byte
+}
+
+func newTestWitness(t *testing.T, name string, typ byte) testWitness {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := append([]byte{typ}, pub...)
+	id := keyID(name, key)
+	return testWitness{
+		vkey: fmt.Sprintf("%s+%s+%s", name, hex.EncodeToString(id[:]), base64.StdEncoding.EncodeToString(key)),
+		name: name,
+		id:   id,
+		priv: priv,
+		typ:  typ,
+	}
+}
+
+// sign returns the note signature line of w for text.
+func (w testWitness) sign(text string) string {
+	sig := ed25519.Sign(w.priv, []byte(text))
+	if w.typ == sigTypeCosignature {
+		var ts [8]byte
+		binary.BigEndian.PutUint64(ts[:], 1700000000)
+		sig = append(ts[:], ed25519.Sign(w.priv, []byte("cosignature/v1\ntime 1700000000\n"+text))...)
+	}
+	return fmt.Sprintf("— %s %s\n", w.name, base64.StdEncoding.EncodeToString(append(w.id[:], sig...)))
+}
+
+func TestParseWitnessKey(t *testing.T) {
+	w := newTestWitness(t, "witness.example.com", sigTypeEd25519)
+	got, err := ParseWitnessKey(w.vkey)
+	if err != nil {
+		t.Fatalf("ParseWitnessKey() = %v", err)
+	}
+	if got.Name != w.name || got.KeyID != w.id || got.cosignature {
+		t.Errorf("ParseWitnessKey() = %+v", got)
+	}
+
+	parts := strings.SplitN(w.vkey, "+", 3)
+	for name, vkey := range map[string]string{
+		"missing key":      parts[0] + "+" + parts[1],
+		"key ID mismatch":  parts[0] + "+00000000+" + parts[2],
+		"name mismatch":    "other.example.com+" + parts[1] + "+" + parts[2],
+		"unsupported type": parts[0] + "+" + parts[1] + "+" + base64.StdEncoding.EncodeToString(append([]byte{0x02}, make([]byte, 32)...)),
+	} {
+		if _, err := ParseWitnessKey(vkey); err == nil {
+			t.Errorf("%s: ParseWitnessKey() succeeded", name)
+		}
+	}
+}
+
+func TestWitnessPolicyVerify(t *testing.T) {
+	a := newTestWitness(t, "a.example.com", sigTypeEd25519)
+	b := newTestWitness(t, "b.example.com", sigTypeCosignature)
+	c := newTestWitness(t, "c.example.com", sigTypeCosignature)
+
+	keys := "# witnesses\n" + a.vkey + "\n\n" + b.vkey + "\n" + c.vkey + "\n"
+	witnesses, err := ParseWitnessKeys(strings.NewReader(keys))
+	if err != nil {
+		t.Fatalf("ParseWitnessKeys() = %v", err)
+	}
+	if len(witnesses) != 3 {
+		t.Fatalf("ParseWitnessKeys() returned %d witnesses, want 3", len(witnesses))
+	}
+
+	text := testOrigin + "\n3\n" + base64.StdEncoding.EncodeToString(make([]byte, 32)) + "\n"
+	forged := c.sign(strings.Replace(text, "\n3\n", "\n4\n", 1))
+	cp, err := ParseSignedCheckpoint(text + "\n" + a.sign(text) + b.sign(text) + forged)
+	if err != nil {
+		t.Fatalf("ParseSignedCheckpoint() = %v", err)
+	}
+	for min, wantErr := range map[int]bool{1: false, 2: false, 3: true, 4: true} {
+		t.Run(strconv.Itoa(min), func(t *testing.T) {
+			err := (&WitnessPolicy{Witnesses: witnesses, Min: min}).Verify(cp)
+			if (err != nil) != wantErr {
+				t.Errorf("Verify() = %v, want error %t", err, wantErr)
+			}
+		})
+	}
+
+	// A witness listed twice only counts once.
+	dup := &WitnessPolicy{Witnesses: []Witness{witnesses[0], witnesses[0]}, Min: 2}
+	if err := dup.Verify(cp); err == nil {
+		t.Error("Verify() counted a duplicate witness twice")
+	}
+}
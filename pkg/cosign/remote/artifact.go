@@ -0,0 +1,152 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+const (
+	// EmptyJSONMediaType is the media type of the empty config of OCI 1.1
+	// artifacts.
+	EmptyJSONMediaType types.MediaType = "application/vnd.oci.empty.v1+json"
+	// TitleAnnotation names the file a layer was read from.
+	TitleAnnotation = "org.opencontainers.image.title"
+)
+
+// ArtifactFile is a file uploaded as one layer of an OCI artifact. An empty
+// MediaType is detected from the contents.
+type ArtifactFile struct {
+	Path        string
+	MediaType   types.MediaType
+	Annotations map[string]string
+}
+
+// UploadArtifact uploads files as the layers of a single OCI 1.1 artifact
+// with the given artifactType and manifest annotations. Each layer is titled
+// with the base name of its file, so the files can be extracted again.
+func UploadArtifact(ref name.Reference, artifactType string, files []ArtifactFile, annotations map[string]string, getMt MediaTypeGetter, remoteOpts ...remote.Option) (name.Digest, error) {
+	config := static.NewLayer([]byte("{}"), EmptyJSONMediaType)
+	configDesc, err := descriptor(config)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	if err := remote.WriteLayer(ref.Context(), config, remoteOpts...); err != nil {
+		return name.Digest{}, err
+	}
+
+	m := artifactManifest{
+		Manifest: v1.Manifest{
+			SchemaVersion: 2,
+			MediaType:     types.OCIManifestSchema1,
+			Config:        configDesc,
+			Annotations:   annotations,
+		},
+		ArtifactType: artifactType,
+	}
+	titles := map[string]string{}
+	for _, f := range files {
+		title := filepath.Base(f.Path)
+		if other, dup := titles[title]; dup {
+			return name.Digest{}, fmt.Errorf("%s and %s have the same file name", other, f.Path)
+		}
+		titles[title] = f.Path
+
+		b, err := os.ReadFile(f.Path)
+		if err != nil {
+			return name.Digest{}, err
+		}
+		mt := f.MediaType
+		if mt == "" {
+			mt = getMt(b)
+		}
+		fmt.Fprintf(os.Stderr, "Uploading file from [%s] to [%s] with media type [%s]\n", f.Path, ref.Name(), mt)
+
+		layer := static.NewLayer(b, mt)
+		desc, err := descriptor(layer)
+		if err != nil {
+			return name.Digest{}, err
+		}
+		desc.Annotations = map[string]string{TitleAnnotation: title}
+		for k, v := range f.Annotations {
+			desc.Annotations[k] = v
+		}
+		if err := remote.WriteLayer(ref.Context(), layer, remoteOpts...); err != nil {
+			return name.Digest{}, err
+		}
+		m.Layers = append(m.Layers, desc)
+	}
+
+	raw, err := json.Marshal(&m)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	h, _, err := v1.SHA256(bytes.NewReader(raw))
+	if err != nil {
+		return name.Digest{}, err
+	}
+	if err := remote.Put(ref, &rawManifest{raw: raw, mediaType: m.MediaType}, remoteOpts...); err != nil {
+		return name.Digest{}, err
+	}
+	return ref.Context().Digest(h.String()), nil
+}
+
+// artifactManifest is an OCI image manifest with the artifactType field added
+// in OCI 1.1.
+type artifactManifest struct {
+	v1.Manifest
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+func descriptor(l v1.Layer) (v1.Descriptor, error) {
+	h, err := l.Digest()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	size, err := l.Size()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	mt, err := l.MediaType()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	return v1.Descriptor{MediaType: mt, Size: size, Digest: h}, nil
+}
+
+// rawManifest is a manifest that is written as is.
+type rawManifest struct {
+	raw       []byte
+	mediaType types.MediaType
+}
+
+func (r *rawManifest) RawManifest() ([]byte, error) {
+	return r.raw, nil
+}
+
+func (r *rawManifest) MediaType() (types.MediaType, error) {
+	return r.mediaType, nil
+}
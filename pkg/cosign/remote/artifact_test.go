@@ -0,0 +1,94 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+func TestUploadArtifact(t *testing.T) {
+	nopLog := log.New(io.Discard, "", 0)
+	s := httptest.NewServer(registry.New(registry.Logger(nopLog)))
+	defer s.Close()
+	ref, err := name.ParseReference(strings.TrimPrefix(s.URL, "http://") + "/foo/bundle:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := []ArtifactFile{
+		{Path: "testdata/foo", Annotations: map[string]string{"org.example.os": "linux"}},
+		{Path: "testdata/bar", MediaType: "application/vnd.example.bar"},
+	}
+	d, err := UploadArtifact(ref, "application/vnd.example.bundle.v1", files, map[string]string{"version": "1"}, DefaultMediaTypeGetter)
+	if err != nil {
+		t.Fatalf("UploadArtifact() = %v", err)
+	}
+
+	got, err := remote.Get(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Digest.String() != d.DigestStr() {
+		t.Errorf("UploadArtifact() = %s, but the tag points at %s", d.DigestStr(), got.Digest)
+	}
+	m := artifactManifest{}
+	if err := json.Unmarshal(got.Manifest, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.ArtifactType != "application/vnd.example.bundle.v1" {
+		t.Errorf("artifactType = %q", m.ArtifactType)
+	}
+	if m.Config.MediaType != EmptyJSONMediaType || m.Config.Size != 2 {
+		t.Errorf("config = %+v, want the empty JSON descriptor", m.Config)
+	}
+	if m.Annotations["version"] != "1" {
+		t.Errorf("annotations = %v", m.Annotations)
+	}
+	if len(m.Layers) != 2 {
+		t.Fatalf("got %d layers, want 2", len(m.Layers))
+	}
+	if l := m.Layers[0]; l.MediaType != "text/plain" || l.Annotations[TitleAnnotation] != "foo" || l.Annotations["org.example.os"] != "linux" {
+		t.Errorf("layer 0 = %+v", l)
+	}
+	if l := m.Layers[1]; l.MediaType != "application/vnd.example.bar" || l.Annotations[TitleAnnotation] != "bar" {
+		t.Errorf("layer 1 = %+v", l)
+	}
+	for _, l := range append(m.Layers, m.Config) {
+		blob, err := remote.Layer(ref.Context().Digest(l.Digest.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc, err := blob.Compressed()
+		if err != nil {
+			t.Errorf("blob %s was not uploaded: %v", l.Digest, err)
+			continue
+		}
+		rc.Close()
+	}
+
+	if _, err := UploadArtifact(ref, "application/vnd.example", []ArtifactFile{{Path: "testdata/foo"}, {Path: "other/foo"}}, nil, DefaultMediaTypeGetter); err == nil {
+		t.Error("UploadArtifact() with two files named foo: expected an error")
+	}
+}
@@ -17,14 +17,20 @@ package remote
 
 import (
 	"bytes"
+	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 
+	ssldsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/cosign/v2/pkg/verify"
 	"github.com/sigstore/sigstore/pkg/signature"
 )
 
@@ -110,6 +116,61 @@ LayerLoop:
 	return nil, nil
 }
 
+// FindStatement returns the attestation of atts whose DSSE envelope has the
+// in-toto statement as its payload and was signed by v or, if cert is set,
+// by the identity and OIDC issuer of cert. It returns nil if there is none,
+// so that attesting identical content again, e.g. with each new keyless
+// certificate of the same CI identity, reuses the existing attestation.
+func FindStatement(atts oci.Signatures, statement []byte, v signature.Verifier, cert *x509.Certificate) (oci.Signature, error) {
+	sigs, err := atts.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, att := range sigs {
+		p, err := att.Payload()
+		if err != nil {
+			continue
+		}
+		env := ssldsse.Envelope{}
+		if err := json.Unmarshal(p, &env); err != nil || env.PayloadType != types.IntotoPayloadType {
+			continue
+		}
+		if existing, err := base64.StdEncoding.DecodeString(env.Payload); err != nil || !bytes.Equal(existing, statement) {
+			continue
+		}
+		if err := verify.VerifyEnvelope(context.Background(), v, &env); err == nil {
+			return att, nil
+		}
+		if cert == nil {
+			continue
+		}
+		if existingCert, err := att.Cert(); err == nil && existingCert != nil && sameIdentity(existingCert, cert) {
+			return att, nil
+		}
+	}
+	return nil, nil
+}
+
+// sameIdentity returns whether the certificates a and b were issued to the
+// same subject alternative names by the same OIDC issuer.
+func sameIdentity(a, b *x509.Certificate) bool {
+	if verify.OIDCIssuer(a) != verify.OIDCIssuer(b) {
+		return false
+	}
+	sansA, sansB := verify.SubjectAlternativeNames(a), verify.SubjectAlternativeNames(b)
+	if len(sansA) == 0 || len(sansA) != len(sansB) {
+		return false
+	}
+	sort.Strings(sansA)
+	sort.Strings(sansB)
+	for i := range sansA {
+		if sansA[i] != sansB[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *ro) Replace(signatures oci.Signatures, o oci.Signature) (oci.Signatures, error) {
 	sigs, err := signatures.Get()
 	if err != nil {
@@ -0,0 +1,151 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+func TestFindStatement(t *testing.T) {
+	newSigner := func(t *testing.T) (signature.SignerVerifier, *ecdsa.PrivateKey) {
+		t.Helper()
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sv, priv
+	}
+	newCert := func(t *testing.T, priv *ecdsa.PrivateKey, email string) *x509.Certificate {
+		t.Helper()
+		tmpl := &x509.Certificate{
+			SerialNumber:   big.NewInt(1),
+			Subject:        pkix.Name{CommonName: "sigstore"},
+			NotBefore:      time.Now().Add(-time.Minute),
+			NotAfter:       time.Now().Add(10 * time.Minute),
+			EmailAddresses: []string{email},
+			ExtraExtensions: []pkix.Extension{{
+				Id:    []int{1, 3, 6, 1, 4, 1, 57264, 1, 1},
+				Value: []byte("https://accounts.example.com"),
+			}},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, priv.Public(), priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+	attest := func(t *testing.T, sv signature.SignerVerifier, statement []byte, opts ...static.Option) oci.Signatures {
+		t.Helper()
+		env, err := dsse.WrapSigner(sv, types.IntotoPayloadType).SignMessage(bytes.NewReader(statement))
+		if err != nil {
+			t.Fatal(err)
+		}
+		att, err := static.NewAttestation(env, append(opts, static.WithLayerMediaType(types.DssePayloadType))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		atts, err := mutate.AppendSignatures(empty.Signatures(), att)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return atts
+	}
+
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://spdx.dev/Document","subject":[{"name":"example.com/app","digest":{"sha256":"ab"}}],"predicate":{}}`)
+	signer, priv := newSigner(t)
+	other, otherPriv := newSigner(t)
+	keyed := attest(t, signer, statement)
+	keyless := attest(t, signer, statement, static.WithCertChain(cryptoutils.PEMEncode(cryptoutils.CertificatePEMType, newCert(t, priv, "ci@example.com").Raw), nil))
+
+	for _, tc := range []struct {
+		name      string
+		atts      oci.Signatures
+		statement []byte
+		verifier  signature.Verifier
+		cert      *x509.Certificate
+		want      bool
+	}{{
+		name:      "same statement and key",
+		atts:      keyed,
+		statement: statement,
+		verifier:  signer,
+		want:      true,
+	}, {
+		name:      "other statement",
+		atts:      keyed,
+		statement: []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`),
+		verifier:  signer,
+	}, {
+		name:      "other key",
+		atts:      keyed,
+		statement: statement,
+		verifier:  other,
+	}, {
+		name:      "same identity",
+		atts:      keyless,
+		statement: statement,
+		verifier:  other,
+		cert:      newCert(t, otherPriv, "ci@example.com"),
+		want:      true,
+	}, {
+		name:      "other identity",
+		atts:      keyless,
+		statement: statement,
+		verifier:  other,
+		cert:      newCert(t, otherPriv, "someone@example.com"),
+	}, {
+		name:      "no attestations",
+		atts:      empty.Signatures(),
+		statement: statement,
+		verifier:  signer,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FindStatement(tc.atts, tc.statement, tc.verifier, tc.cert)
+			if err != nil {
+				t.Fatalf("FindStatement() = %v", err)
+			}
+			if (got != nil) != tc.want {
+				t.Errorf("FindStatement() = %v, want found %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SigningConfig is a Sigstore signing config, the services a client should
+// sign with, see the SigningConfig message of
+// https://github.com/sigstore/protobuf-specs.
+type SigningConfig struct {
+	MediaType     string    `json:"mediaType"`
+	CAURLs        []Service `json:"caUrls,omitempty"`
+	OIDCURLs      []Service `json:"oidcUrls,omitempty"`
+	RekorTlogURLs []Service `json:"rekorTlogUrls,omitempty"`
+	TSAURLs       []Service `json:"tsaUrls,omitempty"`
+}
+
+// Service is a Sigstore service of a signing config.
+type Service struct {
+	URL             string         `json:"url"`
+	MajorAPIVersion uint32         `json:"majorApiVersion"`
+	ValidFor        ValidityPeriod `json:"validFor"`
+	Operator        string         `json:"operator,omitempty"`
+}
+
+// supportedRekorAPIVersions are the major versions of the Rekor API that
+// cosign can upload entries with.
+var supportedRekorAPIVersions = map[uint32]bool{1: true, 2: true}
+
+// LoadSigningConfig reads the signing config at path.
+func LoadSigningConfig(path string) (*SigningConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing config: %w", err)
+	}
+	c := &SigningConfig{}
+	if err := json.Unmarshal(raw, c); err != nil {
+		return nil, fmt.Errorf("parsing signing config %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// RekorLog returns the transparency log to upload entries to at now. Of the
+// logs valid at now with an API version cosign supports, it's the one with
+// the highest API version, and of those the one that became valid last.
+func (c *SigningConfig) RekorLog(now time.Time) (Service, error) {
+	var selected *Service
+	for i, s := range c.RekorTlogURLs {
+		if !supportedRekorAPIVersions[s.MajorAPIVersion] || !s.ValidFor.Contains(now) {
+			continue
+		}
+		if selected == nil || s.MajorAPIVersion > selected.MajorAPIVersion ||
+			(s.MajorAPIVersion == selected.MajorAPIVersion && s.ValidFor.Start.After(selected.ValidFor.Start)) {
+			selected = &c.RekorTlogURLs[i]
+		}
+	}
+	if selected == nil {
+		return Service{}, errors.New("the signing config has no transparency log valid now with a Rekor API version cosign supports")
+	}
+	return *selected, nil
+}
@@ -0,0 +1,61 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSigningConfigRekorLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing_config.json")
+	if err := os.WriteFile(path, []byte(`{
+  "mediaType": "application/vnd.dev.sigstore.signingconfig.v0.2+json",
+  "rekorTlogUrls": [
+    {"url": "https://rekor.example.com", "majorApiVersion": 1, "validFor": {"start": "2021-01-01T00:00:00Z"}},
+    {"url": "https://log2025.example.com", "majorApiVersion": 2, "validFor": {"start": "2025-01-01T00:00:00Z", "end": "2026-01-01T00:00:00Z"}},
+    {"url": "https://log2026.example.com", "majorApiVersion": 2, "validFor": {"start": "2025-12-01T00:00:00Z"}},
+    {"url": "https://log.example.com/v3", "majorApiVersion": 3, "validFor": {"start": "2021-01-01T00:00:00Z"}}
+  ],
+  "rekorTlogConfig": {"selector": "ANY"}
+}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadSigningConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for now, want := range map[string]string{
+		"2024-06-01T00:00:00Z": "https://rekor.example.com",
+		"2025-06-01T00:00:00Z": "https://log2025.example.com",
+		"2025-12-15T00:00:00Z": "https://log2026.example.com",
+		"2026-06-01T00:00:00Z": "https://log2026.example.com",
+	} {
+		ts, _ := time.Parse(time.RFC3339, now)
+		got, err := c.RekorLog(ts)
+		if err != nil {
+			t.Fatalf("RekorLog(%s) = %v", now, err)
+		}
+		if got.URL != want {
+			t.Errorf("RekorLog(%s) = %s, want %s", now, got.URL, want)
+		}
+	}
+	if _, err := c.RekorLog(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("RekorLog() succeeded before any log was valid")
+	}
+}
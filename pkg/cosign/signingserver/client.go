@@ -0,0 +1,188 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signingserver
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+// Client signs with the key of a signing service. It implements
+// signature.SignerVerifier, verifying signatures locally with the public key
+// of the service.
+type Client struct {
+	url        *url.URL
+	token      string
+	httpClient *http.Client
+	signer     Signer
+	publicKey  crypto.PublicKey
+	verifier   signature.Verifier
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+var _ signature.SignerVerifier = (*Client)(nil)
+
+// NewClient returns a client of the signing service at baseURL, whose
+// requests are authenticated with token if set, after fetching the Signer of
+// the service.
+func NewClient(ctx context.Context, baseURL, token string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing server URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("URL %s of the signing server must be http or https", baseURL)
+	}
+	c := &Client{url: u, token: token, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err := c.do(ctx, http.MethodGet, SignerPath, nil, &c.signer); err != nil {
+		return nil, err
+	}
+	c.publicKey, err = cryptoutils.UnmarshalPEMToPublicKey([]byte(c.signer.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("parsing the public key of the signing server: %w", err)
+	}
+	c.verifier, err = signature.LoadVerifier(c.publicKey, crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("loading the public key of the signing server: %w", err)
+	}
+	return c, nil
+}
+
+// Signer returns the key of the service.
+func (c *Client) Signer() Signer {
+	return c.signer
+}
+
+// PublicKey implements signature.PublicKeyProvider.
+func (c *Client) PublicKey(...signature.PublicKeyOption) (crypto.PublicKey, error) {
+	return c.publicKey, nil
+}
+
+// SignMessage implements signature.Signer. The digest of message is sent to
+// the service with message itself, unless message is larger than
+// MaxPayloadSize. Only the digest given with options.WithDigest is sent.
+func (c *Client) SignMessage(message io.Reader, opts ...signature.SignOption) ([]byte, error) {
+	ctx := context.Background()
+	var digest []byte
+	var signerOpts crypto.SignerOpts = crypto.SHA256
+	for _, opt := range opts {
+		opt.ApplyContext(&ctx)
+		opt.ApplyDigest(&digest)
+		opt.ApplyCryptoSignerOpts(&signerOpts)
+	}
+	hash := signerOpts.HashFunc()
+	name, err := hashAlgorithmName(hash)
+	if err != nil {
+		return nil, err
+	}
+	req := SignRequest{HashAlgorithm: name, Digest: digest}
+	if len(digest) == 0 {
+		h := hash.New()
+		var payload bytes.Buffer
+		n, err := io.Copy(io.MultiWriter(h, &payload), io.LimitReader(message, MaxPayloadSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if n <= MaxPayloadSize {
+			req.Payload = payload.Bytes()
+		} else if _, err := io.Copy(h, message); err != nil {
+			return nil, err
+		}
+		req.Digest = h.Sum(nil)
+	}
+
+	resp := SignResponse{}
+	if err := c.do(ctx, http.MethodPost, SignPath, req, &resp); err != nil {
+		return nil, err
+	}
+	// Check the signature before it is uploaded anywhere.
+	if err := c.verifier.VerifySignature(bytes.NewReader(resp.Signature), nil, options.WithDigest(req.Digest), options.WithCryptoSignerOpts(hash)); err != nil {
+		return nil, fmt.Errorf("the signing server returned an invalid signature: %w", err)
+	}
+	return resp.Signature, nil
+}
+
+// VerifySignature implements signature.Verifier.
+func (c *Client) VerifySignature(sig, message io.Reader, opts ...signature.VerifyOption) error {
+	return c.verifier.VerifySignature(sig, message, opts...)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, request, response interface{}) error {
+	var body io.Reader
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url.JoinPath(path).String(), body)
+	if err != nil {
+		return err
+	}
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("signing server %s: %w", c.url.Host, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg := strings.TrimSpace(string(raw))
+		e := Error{}
+		if err := json.Unmarshal(raw, &e); err == nil && e.Message != "" {
+			msg = e.Message
+		}
+		return fmt.Errorf("signing server %s: %s: %s", c.url.Host, resp.Status, msg)
+	}
+	if err := json.Unmarshal(raw, response); err != nil {
+		return fmt.Errorf("parsing the response of signing server %s: %w", c.url.Host, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signingserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+// Handler serves the protocol for a signer, e.g. to build a signing service
+// around a key in an HSM or KMS. It doesn't rate limit requests.
+type Handler struct {
+	sv     signature.Signer
+	token  string
+	signer Signer
+	mux    *http.ServeMux
+	policy *Policy
+
+	auditMu sync.Mutex
+	audit   *json.Encoder
+}
+
+// HandlerOption configures a Handler.
+type HandlerOption func(*Handler)
+
+// WithPolicy authenticates and authorizes the requests with the callers of p,
+// instead of the token of the Handler.
+func WithPolicy(p *Policy) HandlerOption {
+	return func(h *Handler) {
+		h.policy = p
+	}
+}
+
+// WithAuditLog writes an AuditRecord to w, as a line of JSON, for each sign
+// request and each request that fails authentication.
+func WithAuditLog(w io.Writer) HandlerOption {
+	return func(h *Handler) {
+		h.audit = json.NewEncoder(w)
+	}
+}
+
+// AuditRecord is a line of the audit log of a Handler.
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	Caller     string    `json:"caller,omitempty"`
+	RemoteAddr string    `json:"remoteAddr"`
+	Path       string    `json:"path"`
+	// Decision is signed, denied or failed, and Reason why the request
+	// wasn't signed.
+	Decision      string   `json:"decision"`
+	Reason        string   `json:"reason,omitempty"`
+	HashAlgorithm string   `json:"hashAlgorithm,omitempty"`
+	Digest        string   `json:"digest,omitempty"`
+	Repositories  []string `json:"repositories,omitempty"`
+}
+
+// Decisions of an AuditRecord.
+const (
+	AuditSigned = "signed"
+	AuditDenied = "denied"
+	AuditFailed = "failed"
+)
+
+// NewHandler returns a Handler that signs digests with sv, described to
+// clients by signer, for the requests authenticated with token if set.
+func NewHandler(sv signature.Signer, signer Signer, token string, opts ...HandlerOption) *Handler {
+	h := &Handler{sv: sv, token: token, signer: signer, mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc("/"+SignerPath, h.handleSigner)
+	h.mux.HandleFunc("/"+SignPath, h.handleSign)
+	return h
+}
+
+type callerKey struct{}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	switch {
+	case h.policy != nil:
+		c := h.policy.caller(token)
+		if c == nil {
+			h.reject(w, r, AuditRecord{Decision: AuditDenied}, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), callerKey{}, c))
+	case h.token != "":
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			h.reject(w, r, AuditRecord{Decision: AuditDenied}, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) handleSigner(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, Error{Message: "use GET"})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.signer)
+}
+
+func (h *Handler) handleSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, Error{Message: "use POST"})
+		return
+	}
+	c, _ := r.Context().Value(callerKey{}).(*Caller)
+	rec := AuditRecord{Decision: AuditFailed}
+	if c != nil {
+		rec.Caller = c.Name
+	}
+	req := SignRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		h.reject(w, r, rec, http.StatusBadRequest, "invalid sign request: "+err.Error())
+		return
+	}
+	rec.HashAlgorithm = req.HashAlgorithm
+	rec.Digest = hex.EncodeToString(req.Digest)
+	hash, err := hashAlgorithm(req.HashAlgorithm)
+	if err != nil {
+		h.reject(w, r, rec, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Digest) != hash.Size() {
+		h.reject(w, r, rec, http.StatusBadRequest, "the digest isn't a "+req.HashAlgorithm+" digest")
+		return
+	}
+	if req.Payload != nil {
+		d := hash.New()
+		d.Write(req.Payload)
+		if !bytes.Equal(d.Sum(nil), req.Digest) {
+			h.reject(w, r, rec, http.StatusBadRequest, "the digest isn't the digest of the payload")
+			return
+		}
+		rec.Repositories, _ = payloadRepositories(req.Payload)
+	}
+	if c != nil {
+		if err := c.authorize(req); err != nil {
+			rec.Decision = AuditDenied
+			h.reject(w, r, rec, http.StatusForbidden, err.Error())
+			return
+		}
+	}
+	sig, err := h.sv.SignMessage(bytes.NewReader(nil), options.WithContext(r.Context()), options.WithDigest(req.Digest), options.WithCryptoSignerOpts(hash))
+	if err != nil {
+		h.reject(w, r, rec, http.StatusInternalServerError, "signing: "+err.Error())
+		return
+	}
+	rec.Decision = AuditSigned
+	h.log(r, rec)
+	writeJSON(w, http.StatusOK, SignResponse{Signature: sig})
+}
+
+// reject answers a request that isn't signed with reason, recording it in the
+// audit log as rec.
+func (h *Handler) reject(w http.ResponseWriter, r *http.Request, rec AuditRecord, status int, reason string) {
+	rec.Reason = reason
+	h.log(r, rec)
+	writeJSON(w, status, Error{Message: reason})
+}
+
+func (h *Handler) log(r *http.Request, rec AuditRecord) {
+	if h.audit == nil {
+		return
+	}
+	rec.Time = time.Now().UTC()
+	rec.RemoteAddr = r.RemoteAddr
+	rec.Path = r.URL.Path
+	h.auditMu.Lock()
+	defer h.auditMu.Unlock()
+	_ = h.audit.Encode(rec)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
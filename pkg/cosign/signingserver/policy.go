@@ -0,0 +1,218 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signingserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+// Policy authorizes the callers of a Handler, e.g.
+//
+//	{"callers": [
+//	  {"name": "release", "tokenSha256": "9f86d0...", "repositories": ["ghcr.io/example/*"]},
+//	  {"name": "audit", "tokenSha256": "60303a...", "hashAlgorithms": ["sha256"]}
+//	]}
+type Policy struct {
+	Callers []Caller `json:"callers"`
+}
+
+// Caller is a caller of the service and what it may sign.
+type Caller struct {
+	// Name identifies the caller in the audit log.
+	Name string `json:"name"`
+	// TokenSHA256 is the hex-encoded SHA-256 digest of the bearer token of
+	// the caller, so that the policy doesn't hold the tokens.
+	TokenSHA256 string `json:"tokenSha256"`
+	// Repositories, if set, are the path.Match patterns of the repositories
+	// of the images the caller may sign, matched against the docker-reference
+	// of simple signing payloads and the subjects of in-toto statements. The
+	// caller must then send the payloads it signs.
+	Repositories []string `json:"repositories,omitempty"`
+	// HashAlgorithms, if set, are the hash algorithms the caller may sign
+	// the digests of.
+	HashAlgorithms []string `json:"hashAlgorithms,omitempty"`
+}
+
+// ReadPolicy reads and validates the policy in file.
+func ReadPolicy(file string) (*Policy, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policy{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing signing server policy %s: %w", file, err)
+	}
+	if len(p.Callers) == 0 {
+		return nil, fmt.Errorf("signing server policy %s has no callers", file)
+	}
+	names := map[string]bool{}
+	for i, c := range p.Callers {
+		if c.Name == "" || names[c.Name] {
+			return nil, fmt.Errorf("signing server policy %s: caller %d must have a unique name", file, i)
+		}
+		names[c.Name] = true
+		if d, err := hex.DecodeString(c.TokenSHA256); err != nil || len(d) != sha256.Size {
+			return nil, fmt.Errorf("signing server policy %s: tokenSha256 of caller %s isn't a hex SHA-256 digest", file, c.Name)
+		}
+		for _, r := range c.Repositories {
+			if _, err := path.Match(r, ""); err != nil {
+				return nil, fmt.Errorf("signing server policy %s: caller %s: invalid repository pattern %q: %w", file, c.Name, r, err)
+			}
+		}
+		for _, h := range c.HashAlgorithms {
+			if _, err := hashAlgorithm(h); err != nil {
+				return nil, fmt.Errorf("signing server policy %s: caller %s: %w", file, c.Name, err)
+			}
+		}
+	}
+	return p, nil
+}
+
+// caller returns the caller authenticated by token, or nil if none is.
+func (p *Policy) caller(token string) *Caller {
+	if token == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(token))
+	digest := []byte(hex.EncodeToString(sum[:]))
+	var found *Caller
+	// Compare with every caller, so that the time taken doesn't tell which
+	// one matched.
+	for i := range p.Callers {
+		if subtle.ConstantTimeCompare(digest, []byte(strings.ToLower(p.Callers[i].TokenSHA256))) == 1 {
+			found = &p.Callers[i]
+		}
+	}
+	return found
+}
+
+// authorize returns an error if c may not sign req, whose digest is that of
+// its payload if it has one.
+func (c *Caller) authorize(req SignRequest) error {
+	if len(c.HashAlgorithms) > 0 && !contains(c.HashAlgorithms, req.HashAlgorithm) {
+		return fmt.Errorf("%s digests may not be signed", req.HashAlgorithm)
+	}
+	if len(c.Repositories) == 0 {
+		return nil
+	}
+	if req.Payload == nil {
+		return errors.New("the payload is required to authorize the repository")
+	}
+	repos, err := payloadRepositories(req.Payload)
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if !matchesAny(c.Repositories, repo) {
+			return fmt.Errorf("images of %s may not be signed", repo)
+		}
+	}
+	return nil
+}
+
+// payloadRepositories returns the repositories of the images signed by p, a
+// simple signing payload or the DSSE pre-authentication encoding of an
+// in-toto statement, as cosign attest signs them.
+func payloadRepositories(p []byte) ([]string, error) {
+	if bytes.HasPrefix(p, []byte("DSSEv1 ")) {
+		payloadType, body, err := parsePAE(p)
+		if err != nil {
+			return nil, err
+		}
+		if payloadType != "application/vnd.in-toto+json" {
+			return nil, fmt.Errorf("unsupported DSSE payload type %q", payloadType)
+		}
+		st := in_toto.Statement{}
+		if err := json.Unmarshal(body, &st); err != nil {
+			return nil, fmt.Errorf("parsing the in-toto statement: %w", err)
+		}
+		if len(st.Subject) == 0 {
+			return nil, errors.New("the in-toto statement has no subjects")
+		}
+		repos := make([]string, 0, len(st.Subject))
+		for _, s := range st.Subject {
+			repos = append(repos, s.Name)
+		}
+		return repos, nil
+	}
+	sci := payload.SimpleContainerImage{}
+	if err := json.Unmarshal(p, &sci); err != nil || sci.Critical.Type != payload.CosignSignatureType {
+		return nil, errors.New("the payload is neither a simple signing payload nor an in-toto statement")
+	}
+	return []string{sci.Critical.Identity.DockerReference}, nil
+}
+
+// parsePAE parses "DSSEv1 <len> <type> <len> <body>".
+func parsePAE(p []byte) (string, []byte, error) {
+	rest := p[len("DSSEv1 "):]
+	var fields [2][]byte
+	for i := range fields {
+		sp := bytes.IndexByte(rest, ' ')
+		if sp < 0 {
+			return "", nil, errors.New("invalid DSSE pre-authentication encoding")
+		}
+		n, err := strconv.Atoi(string(rest[:sp]))
+		if err != nil || n < 0 || n > len(rest)-sp-1 {
+			return "", nil, errors.New("invalid DSSE pre-authentication encoding")
+		}
+		fields[i] = rest[sp+1 : sp+1+n]
+		rest = rest[sp+1+n:]
+		if i == 0 {
+			if len(rest) == 0 || rest[0] != ' ' {
+				return "", nil, errors.New("invalid DSSE pre-authentication encoding")
+			}
+			rest = rest[1:]
+		}
+	}
+	if len(rest) != 0 {
+		return "", nil, errors.New("invalid DSSE pre-authentication encoding")
+	}
+	return string(fields[0]), fields[1], nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, v string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, v); ok {
+			return true
+		}
+	}
+	return false
+}
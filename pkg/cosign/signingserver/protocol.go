@@ -0,0 +1,102 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signingserver defines the protocol of a delegated signing service,
+// which keeps custody of a signing key so that clients, such as
+// `cosign sign --signing-server`, only send it what to sign.
+//
+// The service exchanges JSON at two endpoints relative to its base URL, and
+// authenticates requests with a bearer token:
+//
+//	GET  /v1/signer  returns the Signer, the public key and certificates
+//	POST /v1/sign    signs the digest of a SignRequest, returning a SignResponse
+//
+// Failed requests have a non-2xx status and an Error body. `cosign server sign`
+// serves the protocol with a Handler.
+package signingserver
+
+import (
+	"crypto"
+	"fmt"
+)
+
+// Paths of the endpoints, relative to the base URL of the service.
+const (
+	SignerPath = "v1/signer"
+	SignPath   = "v1/sign"
+)
+
+// MaxPayloadSize is the size of the largest payload a SignRequest carries,
+// well within the size of the requests services accept.
+const MaxPayloadSize = 256 << 10
+
+// Signer describes the key of the service.
+type Signer struct {
+	// PublicKey is the PEM-encoded public key that verifies the signatures.
+	PublicKey string `json:"publicKey"`
+	// Certificate, if set, is the PEM-encoded certificate of PublicKey, and
+	// CertificateChain the PEM-encoded intermediate and root certificates
+	// that issued it.
+	Certificate      string `json:"certificate,omitempty"`
+	CertificateChain string `json:"certificateChain,omitempty"`
+}
+
+// SignRequest asks the service to sign a digest.
+type SignRequest struct {
+	// HashAlgorithm is the hash function that computed Digest, sha256,
+	// sha384 or sha512.
+	HashAlgorithm string `json:"hashAlgorithm"`
+	// Digest is the digest to sign, base64 encoded in JSON.
+	Digest []byte `json:"digest"`
+	// Payload, if set, is the message whose digest is Digest, base64 encoded
+	// in JSON, so that the service can authorize what it signs, e.g. the
+	// repositories of the images.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// SignResponse is the signature of the digest of a SignRequest.
+type SignResponse struct {
+	// Signature is the signature, base64 encoded in JSON, in the format of
+	// cosign signatures, e.g. ASN.1 for ECDSA keys.
+	Signature []byte `json:"signature"`
+}
+
+// Error is the body of a failed request.
+type Error struct {
+	Message string `json:"error"`
+}
+
+var hashAlgorithms = map[string]crypto.Hash{
+	"sha256": crypto.SHA256,
+	"sha384": crypto.SHA384,
+	"sha512": crypto.SHA512,
+}
+
+func hashAlgorithm(name string) (crypto.Hash, error) {
+	h, ok := hashAlgorithms[name]
+	if !ok {
+		return 0, fmt.Errorf("unsupported hash algorithm %q", name)
+	}
+	return h, nil
+}
+
+func hashAlgorithmName(h crypto.Hash) (string, error) {
+	for name, hash := range hashAlgorithms {
+		if hash == h {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported hash algorithm %s", h)
+}
@@ -0,0 +1,277 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signingserver
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+func newSigner(t *testing.T) (signature.SignerVerifier, Signer) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sv, Signer{PublicKey: string(pub)}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	sv, signer := newSigner(t)
+	s := httptest.NewServer(NewHandler(sv, signer, "s3cr3t"))
+	t.Cleanup(s.Close)
+
+	c, err := NewClient(ctx, s.URL, "s3cr3t")
+	if err != nil {
+		t.Fatalf("NewClient() = %v", err)
+	}
+	if c.Signer() != signer {
+		t.Errorf("Signer() = %+v, want %+v", c.Signer(), signer)
+	}
+
+	msg := []byte("payload")
+	sig, err := c.SignMessage(bytes.NewReader(msg))
+	if err != nil {
+		t.Fatalf("SignMessage() = %v", err)
+	}
+	if err := sv.VerifySignature(bytes.NewReader(sig), bytes.NewReader(msg)); err != nil {
+		t.Errorf("the signature doesn't verify with the key of the server: %v", err)
+	}
+	if err := c.VerifySignature(bytes.NewReader(sig), bytes.NewReader(msg)); err != nil {
+		t.Errorf("VerifySignature() = %v", err)
+	}
+
+	digest := sha256.Sum256(msg)
+	sig, err = c.SignMessage(nil, options.WithDigest(digest[:]))
+	if err != nil {
+		t.Fatalf("SignMessage() of a digest = %v", err)
+	}
+	if err := sv.VerifySignature(bytes.NewReader(sig), bytes.NewReader(msg)); err != nil {
+		t.Errorf("the signature of the digest doesn't verify: %v", err)
+	}
+	if _, err := c.SignMessage(bytes.NewReader(msg), options.WithCryptoSignerOpts(crypto.SHA1)); err == nil {
+		t.Error("SignMessage() with SHA-1: expected an error")
+	}
+
+	for name, token := range map[string]string{"without a token": "", "with another token": "guess"} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewClient(ctx, s.URL, token); err == nil || !strings.Contains(err.Error(), "bearer token") {
+				t.Errorf("NewClient() = %v, want an authentication error", err)
+			}
+		})
+	}
+	if _, err := NewClient(ctx, "ftp://signer.example.com", ""); err == nil {
+		t.Error("NewClient() of an ftp URL: expected an error")
+	}
+}
+
+// TestClientInvalidSignature verifies that the signatures of another key than
+// the one the server describes are rejected.
+func TestClientInvalidSignature(t *testing.T) {
+	sv, _ := newSigner(t)
+	_, other := newSigner(t)
+	s := httptest.NewServer(NewHandler(sv, other, ""))
+	t.Cleanup(s.Close)
+
+	c, err := NewClient(context.Background(), s.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SignMessage(bytes.NewReader([]byte("payload"))); err == nil || !strings.Contains(err.Error(), "invalid signature") {
+		t.Errorf("SignMessage() = %v, want an invalid signature error", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	sv, signer := newSigner(t)
+	s := httptest.NewServer(NewHandler(sv, signer, ""))
+	t.Cleanup(s.Close)
+	c, err := NewClient(context.Background(), s.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, req := range map[string]SignRequest{
+		"unknown hash":  {HashAlgorithm: "md5", Digest: make([]byte, 16)},
+		"short digest":  {HashAlgorithm: "sha256", Digest: []byte("short")},
+		"no algorithm":  {Digest: make([]byte, 32)},
+		"sha512 as 256": {HashAlgorithm: "sha512", Digest: make([]byte, 32)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := c.do(context.Background(), http.MethodPost, SignPath, req, &SignResponse{}); err == nil || !strings.Contains(err.Error(), "400") {
+				t.Errorf("sign request = %v, want a bad request", err)
+			}
+		})
+	}
+	if err := c.do(context.Background(), http.MethodGet, SignPath, nil, &SignResponse{}); err == nil || !strings.Contains(err.Error(), "use POST") {
+		t.Errorf("GET of the sign endpoint = %v", err)
+	}
+}
+
+func writePolicy(t *testing.T, policy string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(p, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func tokenSHA256(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestReadPolicy(t *testing.T) {
+	digest := tokenSHA256("token")
+	for name, tc := range map[string]struct {
+		policy string
+		want   string
+	}{
+		"no callers":      {policy: `{"callers": []}`, want: "has no callers"},
+		"unknown field":   {policy: `{"callers": [{"name": "ci", "tokenSha256": "` + digest + `", "repos": []}]}`, want: "unknown field"},
+		"no name":         {policy: `{"callers": [{"tokenSha256": "` + digest + `"}]}`, want: "unique name"},
+		"duplicate name":  {policy: `{"callers": [{"name": "ci", "tokenSha256": "` + digest + `"}, {"name": "ci", "tokenSha256": "` + digest + `"}]}`, want: "unique name"},
+		"plain token":     {policy: `{"callers": [{"name": "ci", "tokenSha256": "token"}]}`, want: "isn't a hex SHA-256 digest"},
+		"invalid pattern": {policy: `{"callers": [{"name": "ci", "tokenSha256": "` + digest + `", "repositories": ["["]}]}`, want: "invalid repository pattern"},
+		"unknown hash":    {policy: `{"callers": [{"name": "ci", "tokenSha256": "` + digest + `", "hashAlgorithms": ["md5"]}]}`, want: "unsupported hash algorithm"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadPolicy(writePolicy(t, tc.policy)); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ReadPolicy() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestHandlerPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy, err := ReadPolicy(writePolicy(t, `{"callers": [
+		{"name": "release", "tokenSha256": "`+tokenSHA256("release-token")+`", "repositories": ["registry.example.com/team/*"]},
+		{"name": "nightly", "tokenSha256": "`+strings.ToUpper(tokenSHA256("nightly-token"))+`", "hashAlgorithms": ["sha384"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, signer := newSigner(t)
+	var audit bytes.Buffer
+	s := httptest.NewServer(NewHandler(sv, signer, "ignored", WithPolicy(policy), WithAuditLog(&audit)))
+	t.Cleanup(s.Close)
+
+	if _, err := NewClient(ctx, s.URL, "ignored"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("NewClient() with the token of the handler = %v, want an authentication error", err)
+	}
+	release, err := NewClient(ctx, s.URL, "release-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nightly, err := NewClient(ctx, s.URL, "nightly-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	image := func(repo string) []byte {
+		return []byte(`{"critical":{"identity":{"docker-reference":"` + repo + `"},"image":{"docker-manifest-digest":"sha256:abcd"},"type":"cosign container image signature"},"optional":null}`)
+	}
+	allowed := image("registry.example.com/team/app")
+	if _, err := release.SignMessage(bytes.NewReader(allowed)); err != nil {
+		t.Errorf("SignMessage() of an allowed repository = %v", err)
+	}
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"custom","subject":[{"name":"registry.example.com/team/app","digest":{"sha256":"abcd"}}],"predicate":{}}`)
+	if _, err := dsse.WrapSigner(release, "application/vnd.in-toto+json").SignMessage(bytes.NewReader(statement)); err != nil {
+		t.Errorf("SignMessage() of an attestation of an allowed repository = %v", err)
+	}
+	digest := sha256.Sum256(allowed)
+	for name, sign := range map[string]func() error{
+		"another repository": func() error {
+			_, err := release.SignMessage(bytes.NewReader(image("registry.example.com/other/app")))
+			return err
+		},
+		"only a digest": func() error {
+			_, err := release.SignMessage(nil, options.WithDigest(digest[:]))
+			return err
+		},
+		"not an image payload": func() error {
+			_, err := release.SignMessage(bytes.NewReader([]byte("blob")))
+			return err
+		},
+		"another hash algorithm": func() error {
+			_, err := nightly.SignMessage(bytes.NewReader([]byte("blob")))
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := sign(); err == nil || !strings.Contains(err.Error(), "403") {
+				t.Errorf("SignMessage() = %v, want a forbidden error", err)
+			}
+		})
+	}
+	if _, err := nightly.SignMessage(bytes.NewReader([]byte("blob")), options.WithCryptoSignerOpts(crypto.SHA384)); err != nil {
+		t.Errorf("SignMessage() with an allowed hash algorithm = %v", err)
+	}
+	if err := release.do(ctx, http.MethodPost, SignPath, SignRequest{HashAlgorithm: "sha256", Digest: digest[:], Payload: image("registry.example.com/team/other")}, &SignResponse{}); err == nil || !strings.Contains(err.Error(), "isn't the digest of the payload") {
+		t.Errorf("sign request of another payload than the digest = %v", err)
+	}
+
+	var records []AuditRecord
+	dec := json.NewDecoder(&audit)
+	for dec.More() {
+		rec := AuditRecord{}
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+	decisions := map[string]int{}
+	for _, rec := range records {
+		decisions[rec.Decision]++
+	}
+	if decisions[AuditSigned] != 3 || decisions[AuditDenied] != 5 || decisions[AuditFailed] != 1 {
+		t.Errorf("audit log decisions = %v, want 3 signed, 5 denied and 1 failed", decisions)
+	}
+	first := records[1]
+	if first.Caller != "release" || first.Decision != AuditSigned || first.Digest != hex.EncodeToString(digest[:]) ||
+		len(first.Repositories) != 1 || first.Repositories[0] != "registry.example.com/team/app" || first.Time.IsZero() {
+		t.Errorf("audit record of the first signature = %+v", first)
+	}
+}
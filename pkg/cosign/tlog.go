@@ -23,21 +23,29 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"hash"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 	"github.com/transparency-dev/merkle/proof"
 	"github.com/transparency-dev/merkle/rfc6962"
 
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tufcache"
 	"github.com/sigstore/cosign/v2/internal/ui"
 	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/verify"
 	"github.com/sigstore/rekor/pkg/generated/client"
 	"github.com/sigstore/rekor/pkg/generated/client/entries"
 	"github.com/sigstore/rekor/pkg/generated/models"
@@ -52,11 +60,62 @@ import (
 // This is the rekor transparency log public key target name
 var rekorTargetStr = `rekor.pub`
 
+// This is the TUF target of the Sigstore trusted root, which records the
+// period each transparency log key was used in.
+var trustedRootTargetStr = `trusted_root.json`
+
 // TransparencyLogPubKey contains the ECDSA verification key and the current status
 // of the key according to TUF metadata, whether it's active or expired.
 type TransparencyLogPubKey struct {
 	PubKey crypto.PublicKey
 	Status tuf.StatusKind
+	// ValidFor is the period the key was used to sign log entries in, if known.
+	ValidFor ValidityPeriod
+	// BaseURL is the URL of the log from the trusted root, if known.
+	BaseURL string
+}
+
+// ValidityPeriod is a time range. A zero Start or End leaves that side of the
+// range unbounded.
+type ValidityPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains returns whether t is within the period.
+func (v ValidityPeriod) Contains(t time.Time) bool {
+	if !v.Start.IsZero() && t.Before(v.Start) {
+		return false
+	}
+	if !v.End.IsZero() && t.After(v.End) {
+		return false
+	}
+	return true
+}
+
+func (v ValidityPeriod) String() string {
+	start, end := "-", "-"
+	if !v.Start.IsZero() {
+		start = v.Start.UTC().Format(time.RFC3339)
+	}
+	if !v.End.IsZero() {
+		end = v.End.UTC().Format(time.RFC3339)
+	}
+	return start + " to " + end
+}
+
+// checkIntegratedTime returns an error if an entry integrated at
+// integratedTime (in seconds since the epoch) can't have been signed with k,
+// e.g. an entry claiming to predate a key rotation that is signed with the new key.
+func (k TransparencyLogPubKey) checkIntegratedTime(integratedTime int64) error {
+	it := time.Unix(integratedTime, 0)
+	if !k.ValidFor.Contains(it) {
+		return &VerificationError{
+			errorType: ErrTlogKeyNotValidType,
+			message:   fmt.Sprintf("%s: integrated at %s, key valid from %s", ErrTlogKeyNotValidMessage, it.UTC().Format(time.RFC3339), k.ValidFor),
+		}
+	}
+	return nil
 }
 
 // This is a map of TransparencyLog public keys indexed by log ID that's used
@@ -74,12 +133,7 @@ const entryIDHexStringLen = treeIDHexStringLen + uuidHexStringLen
 // (see RFC 6962 S3.2)
 // In CT V1 the log id is a hash of the public key.
 func GetTransparencyLogID(pub crypto.PublicKey) (string, error) {
-	pubBytes, err := x509.MarshalPKIXPublicKey(pub)
-	if err != nil {
-		return "", err
-	}
-	digest := sha256.Sum256(pubBytes)
-	return hex.EncodeToString(digest[:]), nil
+	return verify.LogID(pub)
 }
 
 func intotoEntry(ctx context.Context, signature, pubKey []byte) (models.ProposedEntry, error) {
@@ -101,7 +155,8 @@ func intotoEntry(ctx context.Context, signature, pubKey []byte) (models.Proposed
 // TUF root. If expired, makes a network call to retrieve the updated targets.
 // There are two Env variable that can be used to override this behaviour:
 // SIGSTORE_REKOR_PUBLIC_KEY - If specified, location of the file that contains
-// the Rekor Public Key on local filesystem
+// the Rekor Public Key(s) on local filesystem
+// Keys read from TUF are cached for the lifetime of the process.
 func GetRekorPubs(ctx context.Context) (*TrustedTransparencyLogPubKeys, error) {
 	publicKeys := NewTrustedTransparencyLogPubKeys()
 	altRekorPub := env.Getenv(env.VariableSigstoreRekorPublicKey)
@@ -111,22 +166,18 @@ func GetRekorPubs(ctx context.Context) (*TrustedTransparencyLogPubKeys, error) {
 		if err != nil {
 			return nil, fmt.Errorf("error reading alternate Rekor public key file: %w", err)
 		}
-		if err := publicKeys.AddTransparencyLogPubKey(raw, tuf.Active); err != nil {
+		// The file may hold several keys, e.g. the old and the new key
+		// while a key rotation is in progress.
+		if err := publicKeys.addTransparencyLogPubKeysPEM(raw); err != nil {
 			return nil, fmt.Errorf("AddRekorPubKey: %w", err)
 		}
 	} else {
-		tufClient, err := tuf.NewFromEnv(ctx)
-		if err != nil {
-			return nil, err
-		}
-		targets, err := tufClient.GetTargetsByMeta(tuf.Rekor, []string{rekorTargetStr})
+		cached, err := rekorPubsFromTUF(ctx)
 		if err != nil {
 			return nil, err
 		}
-		for _, t := range targets {
-			if err := publicKeys.AddTransparencyLogPubKey(t.Target, t.Status); err != nil {
-				return nil, fmt.Errorf("AddRekorPubKey: %w", err)
-			}
+		for id, k := range cached.Keys {
+			publicKeys.Keys[id] = k
 		}
 	}
 
@@ -137,6 +188,129 @@ func GetRekorPubs(ctx context.Context) (*TrustedTransparencyLogPubKeys, error) {
 	return &publicKeys, nil
 }
 
+var (
+	tufRekorPubsMu sync.Mutex
+	tufRekorPubs   *TrustedTransparencyLogPubKeys
+)
+
+// rekorPubsFromTUF returns the Rekor public keys of every log shard in the TUF
+// root, with the periods they were valid in if the root has a trusted root
+// target. The keys are only read from TUF once per process.
+func rekorPubsFromTUF(ctx context.Context) (*TrustedTransparencyLogPubKeys, error) {
+	tufRekorPubsMu.Lock()
+	defer tufRekorPubsMu.Unlock()
+	if tufRekorPubs != nil {
+		return tufRekorPubs, nil
+	}
+
+	publicKeys := NewTrustedTransparencyLogPubKeys()
+	targets, err := tufcache.GetTargetsByMeta(ctx, tuf.Rekor, []string{rekorTargetStr})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range targets {
+		if err := publicKeys.AddTransparencyLogPubKey(t.Target, t.Status); err != nil {
+			return nil, fmt.Errorf("AddRekorPubKey: %w", err)
+		}
+	}
+	// Older TUF roots don't have a trusted root, in which case the keys are
+	// used without validity periods.
+	if raw, err := tufcache.GetTarget(ctx, trustedRootTargetStr); err == nil {
+		if err := publicKeys.addTrustedRootTlogs(raw, time.Now()); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", trustedRootTargetStr, err)
+		}
+	}
+
+	tufRekorPubs = &publicKeys
+	return tufRekorPubs, nil
+}
+
+// trustedRootTlogs is the subset of a Sigstore trusted root describing the
+// transparency logs and certificate transparency logs.
+type trustedRootTlogs struct {
+	Tlogs  []trustedRootLog `json:"tlogs"`
+	Ctlogs []trustedRootLog `json:"ctlogs"`
+}
+
+// trustedRootLog is a transparency log, or a certificate transparency log, of
+// a Sigstore trusted root.
+type trustedRootLog struct {
+	BaseURL   string `json:"baseUrl"`
+	PublicKey struct {
+		RawBytes []byte `json:"rawBytes"`
+		ValidFor struct {
+			Start *time.Time `json:"start"`
+			End   *time.Time `json:"end"`
+		} `json:"validFor"`
+	} `json:"publicKey"`
+}
+
+// addTrustedRootTlogs records the validity periods of the transparency log
+// keys in a Sigstore trusted root, and adds the keys that are missing, such
+// as those of historical shards. Keys whose period has ended before now are
+// marked as expired.
+func (t *TrustedTransparencyLogPubKeys) addTrustedRootTlogs(raw []byte, now time.Time) error {
+	root := trustedRootTlogs{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return err
+	}
+	return t.addTrustedRootLogs(root.Tlogs, now)
+}
+
+// addTrustedRootLogs adds the keys of logs, as addTrustedRootTlogs does.
+func (t *TrustedTransparencyLogPubKeys) addTrustedRootLogs(logs []trustedRootLog, now time.Time) error {
+	for _, tlog := range logs {
+		pubKey, err := x509.ParsePKIXPublicKey(tlog.PublicKey.RawBytes)
+		if err != nil {
+			return fmt.Errorf("parsing transparency log public key: %w", err)
+		}
+		keyID, err := GetTransparencyLogID(pubKey)
+		if err != nil {
+			return err
+		}
+		var validFor ValidityPeriod
+		if tlog.PublicKey.ValidFor.Start != nil {
+			validFor.Start = *tlog.PublicKey.ValidFor.Start
+		}
+		if tlog.PublicKey.ValidFor.End != nil {
+			validFor.End = *tlog.PublicKey.ValidFor.End
+		}
+
+		k, ok := t.Keys[keyID]
+		if !ok {
+			k = TransparencyLogPubKey{PubKey: pubKey, Status: tuf.Active}
+			if !validFor.End.IsZero() && validFor.End.Before(now) {
+				k.Status = tuf.Expired
+			}
+		}
+		k.ValidFor = validFor
+		k.BaseURL = tlog.BaseURL
+		t.Keys[keyID] = k
+	}
+	return nil
+}
+
+// addTransparencyLogPubKeysPEM adds every PEM-encoded key in pemBytes as an
+// active key.
+func (t *TrustedTransparencyLogPubKeys) addTransparencyLogPubKeysPEM(pemBytes []byte) error {
+	var found bool
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			break
+		}
+		if err := t.AddTransparencyLogPubKey(pem.EncodeToMemory(block), tuf.Active); err != nil {
+			return err
+		}
+		found = true
+	}
+	if !found {
+		return errors.New("no PEM encoded public key found")
+	}
+	return nil
+}
+
 // rekorPubsFromClient returns a RekorPubKey keyed by the log ID from the Rekor client.
 // NOTE: This **must not** be used in the verification path, but may be used in the
 // sign path to validate return responses are consistent from Rekor.
@@ -370,7 +544,12 @@ func proposedEntry(b64Sig string, payload, pubKey []byte) ([]models.ProposedEntr
 		}
 		proposedEntry = []models.ProposedEntry{entry}
 	}
-	return proposedEntry, nil
+	// Also search for the entries of the registered types.
+	registered, err := registeredProposedEntries(context.Background(), signature, payload, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(proposedEntry, registered...), nil
 }
 
 func FindTlogEntry(ctx context.Context, rekorClient *client.Rekor,
@@ -456,6 +635,9 @@ func VerifyTLogEntryOffline(ctx context.Context, e *models.LogEntryAnon, rekorPu
 	if !ok {
 		return errors.New("rekor log public key not found for payload. Check your TUF root (see cosign initialize) or set a custom key with env var SIGSTORE_REKOR_PUBLIC_KEY")
 	}
+	if err := pubKey.checkIntegratedTime(payload.IntegratedTime); err != nil {
+		return err
+	}
 	err = VerifySET(payload, []byte(e.Verification.SignedEntryTimestamp), pubKey.PubKey.(*ecdsa.PublicKey))
 	if err != nil {
 		return fmt.Errorf("verifying signedEntryTimestamp: %w", err)
@@ -466,6 +648,99 @@ func VerifyTLogEntryOffline(ctx context.Context, e *models.LogEntryAnon, rekorPu
 	return nil
 }
 
+// VerifyRekorV2Entry verifies that a Rekor v2 entry is in a log trusted by
+// the Rekor public keys of co, with an inclusion proof to a checkpoint signed
+// by the log and, if co has witnesses, cosigned by the witnesses. Unless co is
+// offline, an entry whose own proof or checkpoint doesn't verify is verified
+// against the latest checkpoint of the log, read from co.RekorLog or else from
+// the tiles at the base URL of the log in the trusted root.
+func VerifyRekorV2Entry(ctx context.Context, e *rekorv2.Entry, co *CheckOpts) error {
+	if co.RekorPubKeys == nil || co.RekorPubKeys.Keys == nil {
+		return errors.New("no trusted rekor public keys provided")
+	}
+	logID := hex.EncodeToString(e.LogID.KeyID)
+	pubKey, ok := co.RekorPubKeys.Keys[logID]
+	if !ok {
+		return errors.New("rekor log public key not found for entry. Check your TUF root (see cosign initialize) or set a custom key with env var SIGSTORE_REKOR_PUBLIC_KEY")
+	}
+	cp, err := e.Verify(pubKey.PubKey)
+	if err != nil {
+		err = fmt.Errorf("verifying Rekor v2 entry: %w", err)
+	} else if co.Witnesses != nil {
+		err = co.Witnesses.Verify(cp)
+	}
+	if err != nil {
+		log, lerr := rekorV2Log(co, pubKey)
+		if lerr != nil {
+			return lerr
+		}
+		if log == nil {
+			return err
+		}
+		if cp, err = e.VerifyWithLog(ctx, log, pubKey.PubKey); err != nil {
+			return fmt.Errorf("verifying Rekor v2 entry against the latest checkpoint: %w", err)
+		}
+		if co.Witnesses != nil {
+			if err := co.Witnesses.Verify(cp); err != nil {
+				return err
+			}
+		}
+	}
+	if pubKey.Status != tuf.Active {
+		ui.Infof(ctx, "Successfully verified Rekor v2 entry of %s using an expired verification key", cp.Origin)
+	}
+	return nil
+}
+
+// rekorV2Log returns the reader of the tiles of the log of pubKey, or nil if
+// co is offline or the log has no known URL.
+func rekorV2Log(co *CheckOpts, pubKey TransparencyLogPubKey) (rekorv2.LogReader, error) {
+	switch {
+	case co.Offline:
+		return nil, nil
+	case co.RekorLog != nil:
+		return co.RekorLog, nil
+	case pubKey.BaseURL == "":
+		return nil, nil
+	}
+	return rekorv2.NewClient(pubKey.BaseURL)
+}
+
+// verifyTlogWitnesses verifies that the checkpoint of the Rekor v1 entry of
+// sig is cosigned by the witnesses of co. Rekor bundles have no checkpoint, so
+// unless the entry was already looked up online it's looked up now.
+func verifyTlogWitnesses(ctx context.Context, sig oci.Signature, e *models.LogEntryAnon, co *CheckOpts) error {
+	if e == nil {
+		if co.Offline || co.RekorClient == nil {
+			return errors.New("witness cosignatures can only be verified online, as Rekor bundles have no checkpoint")
+		}
+		pemBytes, err := keyBytes(sig, co)
+		if err != nil {
+			return err
+		}
+		if e, err = tlogValidateEntry(ctx, co.RekorClient, co.RekorPubKeys, sig, pemBytes); err != nil {
+			return err
+		}
+	}
+	p := e.Verification.InclusionProof
+	if p.Checkpoint == nil || p.RootHash == nil || p.TreeSize == nil {
+		return errors.New("the transparency log entry has no checkpoint to verify witness cosignatures of")
+	}
+	pubKey, ok := co.RekorPubKeys.Keys[*e.LogID]
+	if !ok {
+		return errors.New("rekor log public key not found for payload")
+	}
+	rootHash, err := hex.DecodeString(*p.RootHash)
+	if err != nil {
+		return fmt.Errorf("decoding root hash: %w", err)
+	}
+	cp, err := rekorv2.VerifyCheckpoint(*p.Checkpoint, rootHash, uint64(*p.TreeSize), pubKey.PubKey)
+	if err != nil {
+		return fmt.Errorf("verifying checkpoint: %w", err)
+	}
+	return co.Witnesses.Verify(cp)
+}
+
 func NewTrustedTransparencyLogPubKeys() TrustedTransparencyLogPubKeys {
 	return TrustedTransparencyLogPubKeys{Keys: make(map[string]TransparencyLogPubKey, 0)}
 }
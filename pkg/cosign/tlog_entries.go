@@ -0,0 +1,151 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/types/hashedrekord"
+	"github.com/sigstore/rekor/pkg/types/intoto"
+	"github.com/sigstore/rekor/pkg/types/rekord"
+
+	"github.com/sigstore/cosign/v2/pkg/verify"
+)
+
+// TlogEntryType is a kind of Rekor entry, besides the hashedrekord, rekord
+// and intoto entries that cosign creates, that signatures are searched for in
+// the transparency log and matched against in bundles. Library consumers
+// register their entry types with RegisterTlogEntryType.
+type TlogEntryType interface {
+	// Kind is the kind of the entries, as in their body.
+	Kind() string
+
+	// ProposedEntry returns the entry that records signature, or the DSSE
+	// envelope payload of an attestation if signature is empty, made by
+	// pubKey, a PEM public key or certificate. It is used to search for and
+	// upload the entry, and may be nil if this type doesn't record it.
+	ProposedEntry(ctx context.Context, signature, payload, pubKey []byte) (models.ProposedEntry, error)
+
+	// Contents returns the signature, key and payload digest recorded in
+	// body, the JSON body of an entry of this kind.
+	Contents(body []byte) (*TlogEntryContents, error)
+}
+
+// TlogEntryContents is what a log entry records of a signature, as a bundle
+// holds it.
+type TlogEntryContents = verify.EntryContents
+
+var (
+	tlogEntryTypesMu sync.Mutex
+	tlogEntryTypes   = map[string]TlogEntryType{}
+)
+
+// RegisterTlogEntryType adds t to the kinds of entries that signatures are
+// searched for and matched against. It panics if the kind is built in or
+// already registered.
+func RegisterTlogEntryType(t TlogEntryType) {
+	tlogEntryTypesMu.Lock()
+	defer tlogEntryTypesMu.Unlock()
+
+	kind := t.Kind()
+	switch kind {
+	case hashedrekord.KIND, rekord.KIND, intoto.KIND:
+		panic(fmt.Sprintf("the %s entry type is built in", kind))
+	}
+	if prev, ok := tlogEntryTypes[kind]; ok {
+		panic(fmt.Sprintf("duplicate entry type for kind %q, %T and %T", kind, prev, t))
+	}
+	tlogEntryTypes[kind] = t
+}
+
+// registeredTlogEntryTypes returns the registered entry types, sorted by
+// kind.
+func registeredTlogEntryTypes() []TlogEntryType {
+	tlogEntryTypesMu.Lock()
+	defer tlogEntryTypesMu.Unlock()
+
+	types := make([]TlogEntryType, 0, len(tlogEntryTypes))
+	for _, t := range tlogEntryTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Kind() < types[j].Kind() })
+	return types
+}
+
+// registeredEntryContents returns the contents of body, the decoded body of
+// a log entry, if it is an entry of a registered type, and nil otherwise.
+func registeredEntryContents(body []byte) (*TlogEntryContents, error) {
+	var entry struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(body, &entry); err != nil {
+		// Left to the built in entry types to report.
+		return nil, nil
+	}
+	tlogEntryTypesMu.Lock()
+	t, ok := tlogEntryTypes[entry.Kind]
+	tlogEntryTypesMu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	c, err := t.Contents(body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s entry: %w", entry.Kind, err)
+	}
+	return c, nil
+}
+
+// registeredProposedEntries returns the entries of the registered types that
+// record the signature.
+func registeredProposedEntries(ctx context.Context, signature, payload, pubKey []byte) ([]models.ProposedEntry, error) {
+	var proposed []models.ProposedEntry
+	for _, t := range registeredTlogEntryTypes() {
+		pe, err := t.ProposedEntry(ctx, signature, payload, pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("proposing %s entry: %w", t.Kind(), err)
+		}
+		if pe != nil {
+			proposed = append(proposed, pe)
+		}
+	}
+	return proposed, nil
+}
+
+// TLogUploadEntryType uploads the entry of the registered type kind that
+// records signature, or the DSSE envelope payload if signature is empty, and
+// pemBytes to the transparency log.
+func TLogUploadEntryType(ctx context.Context, rekorClient *client.Rekor, kind string, signature, payload, pemBytes []byte) (*models.LogEntryAnon, error) {
+	tlogEntryTypesMu.Lock()
+	t, ok := tlogEntryTypes[kind]
+	tlogEntryTypesMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no entry type is registered for kind %q", kind)
+	}
+	pe, err := t.ProposedEntry(ctx, signature, payload, pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	if pe == nil {
+		return nil, fmt.Errorf("the %s entry type doesn't record the signature", kind)
+	}
+	return doUpload(ctx, rekorClient, pe)
+}
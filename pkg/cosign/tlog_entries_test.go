@@ -0,0 +1,140 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/sigstore/rekor/pkg/generated/models"
+)
+
+// dsseEntryType records attestations as Rekor dsse entries.
+type dsseEntryType struct{}
+
+func (dsseEntryType) Kind() string { return "dsse" }
+
+func (dsseEntryType) ProposedEntry(_ context.Context, signature, payload, pubKey []byte) (models.ProposedEntry, error) {
+	if len(signature) != 0 {
+		return nil, nil
+	}
+	return &models.DSSE{
+		APIVersion: swag.String("0.0.1"),
+		Spec: models.DSSEV001Schema{
+			ProposedContent: &models.DSSEV001SchemaProposedContent{
+				Envelope:  swag.String(string(payload)),
+				Verifiers: []strfmt.Base64{pubKey},
+			},
+		},
+	}, nil
+}
+
+func (dsseEntryType) Contents(body []byte) (*TlogEntryContents, error) {
+	var entry struct {
+		Spec models.DSSEV001Schema `json:"spec"`
+	}
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, err
+	}
+	if entry.Spec.PayloadHash == nil || len(entry.Spec.Signatures) != 1 {
+		return nil, errors.New("expected a payload hash and one signature")
+	}
+	return &TlogEntryContents{
+		PublicKey:     entry.Spec.Signatures[0].Verifier.String(),
+		HashAlgorithm: *entry.Spec.PayloadHash.Algorithm,
+		HashValue:     *entry.Spec.PayloadHash.Value,
+	}, nil
+}
+
+func TestRegisterTlogEntryType(t *testing.T) {
+	RegisterTlogEntryType(dsseEntryType{})
+	t.Cleanup(func() {
+		tlogEntryTypesMu.Lock()
+		delete(tlogEntryTypes, "dsse")
+		tlogEntryTypesMu.Unlock()
+	})
+
+	pubKey := []byte("-----BEGIN PUBLIC KEY-----\nMFkw\n-----END PUBLIC KEY-----\n")
+	body, err := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "dsse",
+		"spec": models.DSSEV001Schema{
+			PayloadHash: &models.DSSEV001SchemaPayloadHash{Algorithm: swag.String("sha256"), Value: swag.String("abcd")},
+			Signatures:  []*models.DSSEV001SchemaSignaturesItems0{{Signature: swag.String("c2ln"), Verifier: (*strfmt.Base64)(&pubKey)}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundleBody := base64.StdEncoding.EncodeToString(body)
+
+	if alg, value, err := bundleHash(bundleBody, ""); err != nil || alg != "sha256" || value != "abcd" {
+		t.Errorf("bundleHash() = %s, %s, %v, want sha256, abcd", alg, value, err)
+	}
+	if key, err := bundleKey(bundleBody); err != nil || key != base64.StdEncoding.EncodeToString(pubKey) {
+		t.Errorf("bundleKey() = %s, %v, want the base64 PEM key", key, err)
+	}
+	if sig, err := bundleSig(bundleBody); err != nil || sig != "" {
+		t.Errorf("bundleSig() = %s, %v, want no signature", sig, err)
+	}
+	if _, err := bundleKey(base64.StdEncoding.EncodeToString([]byte(`{"kind":"dsse","spec":{}}`))); err == nil {
+		t.Error("expected an error for a dsse entry without a signature")
+	}
+
+	proposed, err := proposedEntry("", []byte(`{"payloadType":"application/vnd.in-toto+json"}`), pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposed) != 2 || proposed[0].Kind() != "intoto" || proposed[1].Kind() != "dsse" {
+		t.Errorf("proposedEntry() = %v, want an intoto and a dsse entry", proposed)
+	}
+	proposed, err = proposedEntry(base64.StdEncoding.EncodeToString([]byte("sig")), []byte("payload"), pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposed) != 1 || proposed[0].Kind() != "hashedrekord" {
+		t.Errorf("proposedEntry() = %v, want only a hashedrekord entry for a signature", proposed)
+	}
+}
+
+func TestRegisterTlogEntryTypePanics(t *testing.T) {
+	for _, kind := range []string{"hashedrekord", "intoto"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("registering the built in %s entry type didn't panic", kind)
+				}
+			}()
+			RegisterTlogEntryType(kindEntryType(kind))
+		}()
+	}
+}
+
+// kindEntryType is an entry type that only has a kind.
+type kindEntryType string
+
+func (k kindEntryType) Kind() string { return string(k) }
+
+func (kindEntryType) ProposedEntry(context.Context, []byte, []byte, []byte) (models.ProposedEntry, error) {
+	return nil, nil
+}
+
+func (kindEntryType) Contents([]byte) (*TlogEntryContents, error) { return nil, nil }
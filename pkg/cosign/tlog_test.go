@@ -17,18 +17,30 @@ package cosign
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	ttestdata "github.com/google/certificate-transparency-go/trillian/testdata"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/tuf"
+	"github.com/transparency-dev/merkle/rfc6962"
+	"github.com/transparency-dev/merkle/testonly"
 )
 
 var (
@@ -191,3 +203,195 @@ func TestVerifyTLogEntryOfflineFailsWithInvalidPublicKey(t *testing.T) {
 		t.Fatalf("Did not get expected error message, wanted 'is not type ecdsa.PublicKey' got: %v", err)
 	}
 }
+
+func TestValidityPeriod(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		period ValidityPeriod
+		at     time.Time
+		want   bool
+	}{
+		{"unbounded", ValidityPeriod{}, start, true},
+		{"before start", ValidityPeriod{Start: start}, start.Add(-time.Second), false},
+		{"at start", ValidityPeriod{Start: start, End: end}, start, true},
+		{"within", ValidityPeriod{Start: start, End: end}, start.Add(time.Hour), true},
+		{"after end", ValidityPeriod{Start: start, End: end}, end.Add(time.Second), false},
+		{"open ended", ValidityPeriod{Start: start}, end.Add(time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.period.Contains(tt.at); got != tt.want {
+				t.Errorf("Contains(%v) = %t, want %t", tt.at, got, tt.want)
+			}
+		})
+	}
+
+	k := TransparencyLogPubKey{ValidFor: ValidityPeriod{Start: start, End: end}}
+	if err := k.checkIntegratedTime(start.Add(time.Hour).Unix()); err != nil {
+		t.Errorf("checkIntegratedTime() unexpected error: %v", err)
+	}
+	if err := k.checkIntegratedTime(end.Add(time.Hour).Unix()); !errors.Is(err, ErrTlogKeyNotValid) {
+		t.Errorf("checkIntegratedTime() = %v, want %v", err, ErrTlogKeyNotValid)
+	}
+}
+
+func TestAddTrustedRootTlogs(t *testing.T) {
+	newKey := func() crypto.PublicKey {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return priv.Public()
+	}
+	current, old := newKey(), newKey()
+	currentPEM, _ := cryptoutils.MarshalPublicKeyToPEM(current)
+	currentDER, _ := x509.MarshalPKIXPublicKey(current)
+	oldDER, _ := x509.MarshalPKIXPublicKey(old)
+
+	// The current key is known from the rekor.pub target, the old shard's key
+	// is only in the trusted root.
+	keys := NewTrustedTransparencyLogPubKeys()
+	if err := keys.AddTransparencyLogPubKey(currentPEM, tuf.Active); err != nil {
+		t.Fatal(err)
+	}
+	trustedRoot := fmt.Sprintf(`{
+  "mediaType": "application/vnd.dev.sigstore.trustedroot+json;version=0.1",
+  "tlogs": [{
+    "baseUrl": "https://rekor.example.com",
+    "hashAlgorithm": "SHA2_256",
+    "publicKey": {"rawBytes": %q, "keyDetails": "PKIX_ECDSA_P256_SHA_256", "validFor": {"start": "2021-01-12T11:53:27.000Z", "end": "2022-04-01T00:00:00.000Z"}}
+  }, {
+    "baseUrl": "https://rekor.example.com",
+    "hashAlgorithm": "SHA2_256",
+    "publicKey": {"rawBytes": %q, "keyDetails": "PKIX_ECDSA_P256_SHA_256", "validFor": {"start": "2022-03-15T00:00:00.000Z"}}
+  }]
+}`, base64.StdEncoding.EncodeToString(oldDER), base64.StdEncoding.EncodeToString(currentDER))
+
+	if err := keys.addTrustedRootTlogs([]byte(trustedRoot), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("addTrustedRootTlogs() unexpected error: %v", err)
+	}
+	if len(keys.Keys) != 2 {
+		t.Fatalf("got %d keys, want 2", len(keys.Keys))
+	}
+
+	oldID, _ := GetTransparencyLogID(old)
+	currentID, _ := GetTransparencyLogID(current)
+	oldKey, currentKey := keys.Keys[oldID], keys.Keys[currentID]
+	if oldKey.Status != tuf.Expired || currentKey.Status != tuf.Active {
+		t.Errorf("statuses = %v, %v; want expired old key and active current key", oldKey.Status, currentKey.Status)
+	}
+	if oldKey.BaseURL != "https://rekor.example.com" || currentKey.BaseURL != "https://rekor.example.com" {
+		t.Errorf("base URLs = %q, %q; want the URL of the log", oldKey.BaseURL, currentKey.BaseURL)
+	}
+
+	// During the overlap in the rotation, entries signed with either key verify.
+	overlap := time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC).Unix()
+	if oldKey.checkIntegratedTime(overlap) != nil || currentKey.checkIntegratedTime(overlap) != nil {
+		t.Error("expected both keys to be valid during the rotation")
+	}
+	// Entries can't claim to be signed with the old key after it was rotated out,
+	// or with the new key before it was introduced.
+	if oldKey.checkIntegratedTime(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC).Unix()) == nil {
+		t.Error("expected the old key to be invalid after the rotation")
+	}
+	if currentKey.checkIntegratedTime(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC).Unix()) == nil {
+		t.Error("expected the current key to be invalid before the rotation")
+	}
+
+	if err := keys.addTrustedRootTlogs([]byte(`{"tlogs": [{"publicKey": {"rawBytes": "Zm9v"}}]}`), time.Now()); err == nil {
+		t.Error("expected an error for an invalid public key")
+	}
+}
+
+func TestGetRekorPubsMultipleKeys(t *testing.T) {
+	var pems []byte
+	for i := 0; i < 2; i++ {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pemBytes, _ := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+		pems = append(pems, pemBytes...)
+	}
+	keyFile := filepath.Join(t.TempDir(), "rekor.pub")
+	if err := os.WriteFile(keyFile, pems, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SIGSTORE_REKOR_PUBLIC_KEY", keyFile)
+
+	keys, err := GetRekorPubs(context.Background())
+	if err != nil {
+		t.Fatalf("GetRekorPubs() unexpected error: %v", err)
+	}
+	if len(keys.Keys) != 2 {
+		t.Errorf("got %d keys, want 2", len(keys.Keys))
+	}
+
+	if err := os.WriteFile(keyFile, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetRekorPubs(context.Background()); err == nil {
+		t.Error("expected an error for a file without keys")
+	}
+}
+
+// fakeLogReader reads the checkpoint and inclusion proofs of tree, signed
+// by key.
+type fakeLogReader struct {
+	t    *testing.T
+	tree *testonly.Tree
+	key  *ecdsa.PrivateKey
+}
+
+func (r fakeLogReader) Checkpoint(_ context.Context) (*rekorv2.SignedCheckpoint, error) {
+	text := fmt.Sprintf("log.example.com\n%d\n%s\n", r.tree.Size(), base64.StdEncoding.EncodeToString(r.tree.Hash()))
+	digest := sha256.Sum256([]byte(text))
+	sig, err := ecdsa.SignASN1(rand.Reader, r.key, digest[:])
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return rekorv2.ParseSignedCheckpoint(text + "\n— log.example.com " + base64.StdEncoding.EncodeToString(append([]byte{0, 0, 0, 0}, sig...)) + "\n")
+}
+
+func (r fakeLogReader) InclusionProof(_ context.Context, index, treeSize uint64) ([][]byte, error) {
+	return r.tree.InclusionProof(index, treeSize)
+}
+
+func TestVerifyRekorV2EntryWithLog(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	logID := sha256.Sum256(der)
+	keys := NewTrustedTransparencyLogPubKeys()
+	keys.Keys[hex.EncodeToString(logID[:])] = TransparencyLogPubKey{PubKey: key.Public(), Status: tuf.Active}
+
+	tree := testonly.New(rfc6962.DefaultHasher)
+	for i := 0; i < 10; i++ {
+		tree.AppendData([]byte(fmt.Sprintf("entry %d", i)))
+	}
+	// The entry has no inclusion proof, as if its bundle had been stripped.
+	e := &rekorv2.Entry{LogIndex: 7, LogID: rekorv2.LogID{KeyID: logID[:]}, CanonicalizedBody: []byte("entry 7")}
+	log := fakeLogReader{t: t, tree: tree, key: key}
+
+	if err := VerifyRekorV2Entry(ctx, e, &CheckOpts{RekorPubKeys: &keys}); err == nil {
+		t.Error("VerifyRekorV2Entry() without a log succeeded")
+	}
+	if err := VerifyRekorV2Entry(ctx, e, &CheckOpts{RekorPubKeys: &keys, RekorLog: log, Offline: true}); err == nil {
+		t.Error("VerifyRekorV2Entry() offline succeeded")
+	}
+	if err := VerifyRekorV2Entry(ctx, e, &CheckOpts{RekorPubKeys: &keys, RekorLog: log}); err != nil {
+		t.Errorf("VerifyRekorV2Entry() with the log = %v", err)
+	}
+	other := &rekorv2.Entry{LogIndex: 7, LogID: e.LogID, CanonicalizedBody: []byte("entry 8")}
+	if err := VerifyRekorV2Entry(ctx, other, &CheckOpts{RekorPubKeys: &keys, RekorLog: log}); err == nil {
+		t.Error("VerifyRekorV2Entry() of an entry that isn't in the log succeeded")
+	}
+}
@@ -0,0 +1,100 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// maxTrustAnchorDepth bounds the length of the chains to trust anchor keys.
+const maxTrustAnchorDepth = 10
+
+// TrustedCertWithAnchorKeys verifies cert like TrustedCert, but also trusts
+// the certificates issued with the trust anchor keys, such as CA keys held in
+// a KMS whose root certificates aren't at hand. The anchors stand in for root
+// certificates with the subject of the issuers found while building the
+// chain.
+func TrustedCertWithAnchorKeys(cert *x509.Certificate, roots *x509.CertPool, intermediates *x509.CertPool, keys []crypto.PublicKey) ([][]*x509.Certificate, error) {
+	if roots == nil {
+		roots = x509.NewCertPool()
+	} else {
+		roots = roots.Clone()
+	}
+	anchored := map[string]bool{}
+	issuer := cert.RawIssuer
+	for i := 0; ; i++ {
+		anchored[string(issuer)] = true
+		for _, k := range keys {
+			anchor, err := trustAnchor(issuer, k)
+			if err != nil {
+				return nil, err
+			}
+			roots.AddCert(anchor)
+		}
+		chains, err := TrustedCert(cert, roots, intermediates)
+		// The top of the chain that couldn't be completed names the next
+		// issuer to add the anchors for.
+		var uae x509.UnknownAuthorityError
+		if err == nil || i == maxTrustAnchorDepth || !errors.As(err, &uae) || uae.Cert == nil || anchored[string(uae.Cert.RawIssuer)] {
+			return chains, err
+		}
+		issuer = uae.Cert.RawIssuer
+	}
+}
+
+// trustAnchor returns a root certificate with the raw subject and public key,
+// valid forever, that's only used to check the signatures of the
+// certificates it issued.
+func trustAnchor(subject []byte, pub crypto.PublicKey) (*x509.Certificate, error) {
+	der, err := cryptoutils.MarshalPublicKeyToDER(pub)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling trust anchor key: %w", err)
+	}
+	var alg x509.PublicKeyAlgorithm
+	switch pub.(type) {
+	case *ecdsa.PublicKey:
+		alg = x509.ECDSA
+	case *rsa.PublicKey:
+		alg = x509.RSA
+	case ed25519.PublicKey:
+		alg = x509.Ed25519
+	default:
+		return nil, fmt.Errorf("unsupported trust anchor key type %T", pub)
+	}
+	return &x509.Certificate{
+		// The pool tells certificates apart by their raw bytes.
+		Raw:                     append(append([]byte{}, subject...), der...),
+		RawSubject:              subject,
+		RawSubjectPublicKeyInfo: der,
+		PublicKey:               pub,
+		PublicKeyAlgorithm:      alg,
+		NotAfter:                time.Unix(math.MaxInt32, 0),
+		BasicConstraintsValid:   true,
+		IsCA:                    true,
+		MaxPathLen:              -1,
+		KeyUsage:                x509.KeyUsageCertSign,
+	}, nil
+}
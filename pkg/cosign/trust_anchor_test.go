@@ -0,0 +1,114 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/test"
+)
+
+func TestTrustedCertWithAnchorKeys(t *testing.T) {
+	// The root certificate of the CA key, as held in a KMS, isn't given to
+	// the verifier.
+	rootCert, rootKey, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subCert, subKey, err := test.GenerateSubordinateCa(rootCert, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafCert, _, err := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", subCert, subKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	directCert, _, err := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", rootCert, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(subCert)
+
+	for _, tt := range []struct {
+		name          string
+		cert          *x509.Certificate
+		intermediates *x509.CertPool
+		key           crypto.PublicKey
+		wantErr       bool
+	}{{
+		name:          "issued by an intermediate",
+		cert:          leafCert,
+		intermediates: intermediates,
+		key:           rootKey.Public(),
+	}, {
+		name: "issued by the anchor",
+		cert: directCert,
+		key:  rootKey.Public(),
+	}, {
+		name:          "intermediate is the anchor",
+		cert:          leafCert,
+		intermediates: intermediates,
+		key:           subKey.Public(),
+	}, {
+		name:    "missing intermediate",
+		cert:    leafCert,
+		key:     rootKey.Public(),
+		wantErr: true,
+	}, {
+		name:          "other key",
+		cert:          leafCert,
+		intermediates: intermediates,
+		key:           otherKey.Public(),
+		wantErr:       true,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			chains, err := TrustedCertWithAnchorKeys(tt.cert, nil, tt.intermediates, []crypto.PublicKey{tt.key})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("TrustedCertWithAnchorKeys() succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TrustedCertWithAnchorKeys() = %v", err)
+			}
+			if len(chains) != 1 || chains[0][0] != tt.cert {
+				t.Errorf("TrustedCertWithAnchorKeys() = %v", chains)
+			}
+		})
+	}
+
+	co := &CheckOpts{
+		RootCerts:         x509.NewCertPool(),
+		IntermediateCerts: intermediates,
+		TrustAnchorKeys:   []crypto.PublicKey{rootKey.Public()},
+		IgnoreSCT:         true,
+		Identities:        []Identity{{Subject: "subject@mail.com", Issuer: "oidc-issuer"}},
+	}
+	if _, err := ValidateAndUnpackCert(leafCert, co); err != nil {
+		t.Errorf("ValidateAndUnpackCert() = %v", err)
+	}
+}
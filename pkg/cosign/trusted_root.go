@@ -0,0 +1,166 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TrustedRoot is the trust material of a Sigstore trusted root, the
+// trusted_root.json target of the Sigstore TUF repository, for verifying
+// without fetching it from TUF. Verification only reads it, so one
+// TrustedRoot may be loaded once and shared by concurrent verifications.
+type TrustedRoot struct {
+	// FulcioRoots and FulcioIntermediates are the certificates of the
+	// certificate authorities, FulcioRootCertificates and
+	// FulcioIntermediateCertificates in pools.
+	FulcioRoots                    *x509.CertPool
+	FulcioIntermediates            *x509.CertPool
+	FulcioRootCertificates         []*x509.Certificate
+	FulcioIntermediateCertificates []*x509.Certificate
+	// RekorPubKeys are the keys of the transparency logs.
+	RekorPubKeys *TrustedTransparencyLogPubKeys
+	// CTLogPubKeys are the keys of the certificate transparency logs.
+	CTLogPubKeys *TrustedTransparencyLogPubKeys
+	// TSACertificate is the certificate of the timestamp authority if the
+	// trusted root has exactly one, and is otherwise taken from the timestamp.
+	TSACertificate              *x509.Certificate
+	TSAIntermediateCertificates []*x509.Certificate
+	TSARootCertificates         []*x509.Certificate
+	// TSACertificateChains are the chains of each of the timestamp
+	// authorities, such as those of the intermediates a TSA rotated.
+	TSACertificateChains []TSACertificateChain
+}
+
+// trustedRoot is the subset of a Sigstore trusted root that cosign uses.
+type trustedRoot struct {
+	Tlogs                  []trustedRootLog `json:"tlogs"`
+	Ctlogs                 []trustedRootLog `json:"ctlogs"`
+	CertificateAuthorities []trustedRootCA  `json:"certificateAuthorities"`
+	TimestampAuthorities   []trustedRootCA  `json:"timestampAuthorities"`
+}
+
+// trustedRootCA is a certificate authority or a timestamp authority of a
+// Sigstore trusted root.
+type trustedRootCA struct {
+	CertChain struct {
+		Certificates []struct {
+			RawBytes []byte `json:"rawBytes"`
+		} `json:"certificates"`
+	} `json:"certChain"`
+}
+
+func (ca trustedRootCA) certificates() ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for _, c := range ca.CertChain.Certificates {
+		cert, err := x509.ParseCertificate(c.RawBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// LoadTrustedRoot reads the Sigstore trusted root at path.
+func LoadTrustedRoot(path string) (*TrustedRoot, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading trusted root: %w", err)
+	}
+	tr, err := ParseTrustedRoot(raw, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("parsing trusted root %s: %w", path, err)
+	}
+	return tr, nil
+}
+
+// ParseTrustedRoot parses the JSON of a Sigstore trusted root. Log keys whose
+// validity period has ended before now are marked as expired.
+func ParseTrustedRoot(raw []byte, now time.Time) (*TrustedRoot, error) {
+	root := trustedRoot{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return nil, err
+	}
+
+	rekorPubKeys := NewTrustedTransparencyLogPubKeys()
+	if err := rekorPubKeys.addTrustedRootLogs(root.Tlogs, now); err != nil {
+		return nil, err
+	}
+	ctLogPubKeys := NewTrustedTransparencyLogPubKeys()
+	if err := ctLogPubKeys.addTrustedRootLogs(root.Ctlogs, now); err != nil {
+		return nil, err
+	}
+	tr := &TrustedRoot{RekorPubKeys: &rekorPubKeys, CTLogPubKeys: &ctLogPubKeys}
+
+	// The chains are ordered from the leaf, or the intermediate for a
+	// certificate authority, to the root.
+	for _, ca := range root.CertificateAuthorities {
+		certs, err := ca.certificates()
+		if err != nil {
+			return nil, fmt.Errorf("certificate authority: %w", err)
+		}
+		if len(certs) == 0 {
+			return nil, errors.New("certificate authority has no certificates")
+		}
+		if tr.FulcioRoots == nil {
+			tr.FulcioRoots = x509.NewCertPool()
+		}
+		tr.FulcioRoots.AddCert(certs[len(certs)-1])
+		tr.FulcioRootCertificates = append(tr.FulcioRootCertificates, certs[len(certs)-1])
+		for _, cert := range certs[:len(certs)-1] {
+			if tr.FulcioIntermediates == nil {
+				tr.FulcioIntermediates = x509.NewCertPool()
+			}
+			tr.FulcioIntermediates.AddCert(cert)
+			tr.FulcioIntermediateCertificates = append(tr.FulcioIntermediateCertificates, cert)
+		}
+	}
+
+	var leaves []*x509.Certificate
+	for _, tsa := range root.TimestampAuthorities {
+		certs, err := tsa.certificates()
+		if err != nil {
+			return nil, fmt.Errorf("timestamp authority: %w", err)
+		}
+		chain := TSACertificateChain{}
+		for _, cert := range certs {
+			switch {
+			case !cert.IsCA:
+				leaves = append(leaves, cert)
+				chain.Certificate = cert
+			case bytes.Equal(cert.RawSubject, cert.RawIssuer):
+				tr.TSARootCertificates = append(tr.TSARootCertificates, cert)
+				chain.Roots = append(chain.Roots, cert)
+			default:
+				tr.TSAIntermediateCertificates = append(tr.TSAIntermediateCertificates, cert)
+				chain.Intermediates = append(chain.Intermediates, cert)
+			}
+		}
+		tr.TSACertificateChains = append(tr.TSACertificateChains, chain)
+	}
+	if len(leaves) == 1 {
+		tr.TSACertificate = leaves[0]
+	}
+	return tr, nil
+}
@@ -0,0 +1,87 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+func TestParseTrustedRoot(t *testing.T) {
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	subCert, subKey, _ := test.GenerateSubordinateCa(rootCert, rootKey)
+	leafCert, _, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", subCert, subKey)
+	rekorKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ctKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rekorDER, _ := x509.MarshalPKIXPublicKey(rekorKey.Public())
+	ctDER, _ := x509.MarshalPKIXPublicKey(ctKey.Public())
+	b64 := func(b []byte) string { return base64.StdEncoding.EncodeToString(b) }
+
+	raw := fmt.Sprintf(`{
+  "mediaType": "application/vnd.dev.sigstore.trustedroot+json;version=0.1",
+  "tlogs": [{"publicKey": {"rawBytes": %q, "validFor": {"start": "2021-01-12T11:53:27.000Z"}}}],
+  "certificateAuthorities": [{"certChain": {"certificates": [{"rawBytes": %q}, {"rawBytes": %q}]}}],
+  "ctlogs": [{"publicKey": {"rawBytes": %q, "validFor": {"start": "2021-01-12T11:53:27.000Z", "end": "2022-01-01T00:00:00.000Z"}}}],
+  "timestampAuthorities": [{"certChain": {"certificates": [{"rawBytes": %q}, {"rawBytes": %q}, {"rawBytes": %q}]}}]
+}`, b64(rekorDER), b64(subCert.Raw), b64(rootCert.Raw), b64(ctDER), b64(leafCert.Raw), b64(subCert.Raw), b64(rootCert.Raw))
+
+	tr, err := ParseTrustedRoot([]byte(raw), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ParseTrustedRoot() = %v", err)
+	}
+
+	rekorID, _ := GetTransparencyLogID(rekorKey.Public())
+	if k, ok := tr.RekorPubKeys.Keys[rekorID]; !ok || k.Status != tuf.Active {
+		t.Errorf("RekorPubKeys = %v, want the active Rekor key", tr.RekorPubKeys.Keys)
+	}
+	ctID, _ := GetTransparencyLogID(ctKey.Public())
+	if k, ok := tr.CTLogPubKeys.Keys[ctID]; !ok || k.Status != tuf.Expired {
+		t.Errorf("CTLogPubKeys = %v, want the expired CT log key", tr.CTLogPubKeys.Keys)
+	}
+	if _, err := TrustedCert(leafCert, tr.FulcioRoots, tr.FulcioIntermediates); err != nil {
+		t.Errorf("TrustedCert() with the certificate authorities = %v", err)
+	}
+	if len(tr.FulcioRootCertificates) != 1 || !tr.FulcioRootCertificates[0].Equal(rootCert) ||
+		len(tr.FulcioIntermediateCertificates) != 1 || !tr.FulcioIntermediateCertificates[0].Equal(subCert) {
+		t.Errorf("Fulcio roots and intermediates = %d, %d, want the root and the intermediate", len(tr.FulcioRootCertificates), len(tr.FulcioIntermediateCertificates))
+	}
+	if tr.TSACertificate == nil || !tr.TSACertificate.Equal(leafCert) {
+		t.Errorf("TSACertificate = %v, want the leaf", tr.TSACertificate)
+	}
+	if len(tr.TSAIntermediateCertificates) != 1 || len(tr.TSARootCertificates) != 1 || !tr.TSARootCertificates[0].Equal(rootCert) {
+		t.Errorf("TSA intermediates and roots = %d, %d, want 1 and the root", len(tr.TSAIntermediateCertificates), len(tr.TSARootCertificates))
+	}
+
+	for _, raw := range []string{
+		`{"certificateAuthorities": [{"certChain": {"certificates": []}}]}`,
+		`{"certificateAuthorities": [{"certChain": {"certificates": [{"rawBytes": "Zm9v"}]}}]}`,
+		`{"ctlogs": [{"publicKey": {"rawBytes": "Zm9v"}}]}`,
+		`[]`,
+	} {
+		if _, err := ParseTrustedRoot([]byte(raw), time.Now()); err == nil {
+			t.Errorf("ParseTrustedRoot(%s) = nil, want an error", raw)
+		}
+	}
+}
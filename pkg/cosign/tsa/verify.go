@@ -0,0 +1,79 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tsa verifies RFC3161 timestamps with the certificate chains of
+// timestamp authorities.
+package tsa
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/digitorus/timestamp"
+	"github.com/sigstore/timestamp-authority/pkg/verification"
+)
+
+// VerifyTimestampResponse verifies the RFC3161 timestamp response tsr of
+// artifact with the chain of chains that issued it, so that the timestamps
+// of a TSA that rotated its intermediate or leaf certificate verify with the
+// chains from before and after the rotation. The chains of the certificate
+// embedded in the timestamp are tried first, then the others.
+func VerifyTimestampResponse(tsr, artifact []byte, chains []verification.VerifyOpts) (*timestamp.Timestamp, error) {
+	if len(chains) == 0 {
+		return nil, errors.New("no timestamp authority certificate chains")
+	}
+	if len(chains) == 1 {
+		return verification.VerifyTimestampResponse(tsr, bytes.NewReader(artifact), chains[0])
+	}
+	ts, err := timestamp.ParseResponse(tsr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response into Timestamp: %w", err)
+	}
+	var issuers, others []verification.VerifyOpts
+	for _, chain := range chains {
+		if len(ts.Certificates) > 0 && issued(chain, ts.Certificates[0]) {
+			issuers = append(issuers, chain)
+		} else {
+			others = append(others, chain)
+		}
+	}
+	var firstErr error
+	for _, chain := range append(issuers, others...) {
+		verified, err := verification.VerifyTimestampResponse(tsr, bytes.NewReader(artifact), chain)
+		if err == nil {
+			return verified, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, fmt.Errorf("the timestamp doesn't verify with any of the %d timestamp authority certificate chains: %w", len(chains), firstErr)
+}
+
+// issued reports whether chain has the TSA certificate cert, or, if it has
+// none, a CA certificate that signed cert.
+func issued(chain verification.VerifyOpts, cert *x509.Certificate) bool {
+	if chain.TSACertificate != nil {
+		return chain.TSACertificate.Equal(cert)
+	}
+	for _, ca := range append(append([]*x509.Certificate{}, chain.Intermediates...), chain.Roots...) {
+		if cert.CheckSignatureFrom(ca) == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsa
+
+import (
+	"bytes"
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa/mock"
+	"github.com/sigstore/timestamp-authority/pkg/verification"
+)
+
+func TestVerifyTimestampResponse(t *testing.T) {
+	artifact := []byte("signature")
+	// The chains of a TSA before and after it rotated its certificates.
+	var chains, withLeaves []verification.VerifyOpts
+	var responses [][]byte
+	for i := 0; i < 2; i++ {
+		c, err := mock.NewTSAClient(mock.TSAClientOptions{Message: artifact})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tsr, err := c.GetTimestampResponse(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := len(c.CertChain) - 1
+		chain := verification.VerifyOpts{
+			Intermediates: c.CertChain[1:last],
+			Roots:         []*x509.Certificate{c.CertChain[last]},
+		}
+		chains = append(chains, chain)
+		chain.TSACertificate = c.CertChain[0]
+		withLeaves = append(withLeaves, chain)
+		responses = append(responses, tsr)
+	}
+
+	for i, tsr := range responses {
+		if _, err := VerifyTimestampResponse(tsr, artifact, chains); err != nil {
+			t.Errorf("VerifyTimestampResponse() of timestamp %d = %v", i, err)
+		}
+		if _, err := VerifyTimestampResponse(tsr, artifact, withLeaves); err != nil {
+			t.Errorf("VerifyTimestampResponse() of timestamp %d with the TSA certificates = %v", i, err)
+		}
+	}
+
+	_, err := VerifyTimestampResponse(responses[1], artifact, chains[:1])
+	if err == nil {
+		t.Error("VerifyTimestampResponse() with the chain of another TSA succeeded")
+	}
+	otherChain := []verification.VerifyOpts{chains[0], chains[0]}
+	if _, err := VerifyTimestampResponse(responses[1], artifact, otherChain); err == nil || !strings.Contains(err.Error(), "any of the 2") {
+		t.Errorf("VerifyTimestampResponse() with the chains of another TSA = %v, want an error", err)
+	}
+	if _, err := VerifyTimestampResponse(responses[0], bytes.ToUpper(artifact), chains); err == nil {
+		t.Error("VerifyTimestampResponse() of another artifact succeeded")
+	}
+	if _, err := VerifyTimestampResponse(responses[0], artifact, nil); err == nil {
+		t.Error("VerifyTimestampResponse() without chains succeeded")
+	}
+}
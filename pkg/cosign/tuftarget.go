@@ -0,0 +1,49 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tufcache"
+)
+
+// TUFTargetPrefix marks a policy or denylist path as the name of a target in
+// the TUF repository configured with cosign initialize, e.g.
+// tuf://policies/release.rego.
+const TUFTargetPrefix = "tuf://"
+
+// TUFTargetName returns the target name of a tuf://<target> path, and
+// whether path is one.
+func TUFTargetName(path string) (string, bool) {
+	if !strings.HasPrefix(path, TUFTargetPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(path, TUFTargetPrefix), true
+}
+
+// GetTUFTarget returns the target called name from the TUF repository
+// configured with cosign initialize. The target is checked against the
+// signed TUF metadata, which is refreshed from the repository as --tuf-refresh
+// sets, so updated targets are picked up without any client changes.
+func GetTUFTarget(ctx context.Context, name string) ([]byte, error) {
+	b, err := tufcache.GetTarget(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("fetching TUF target %s: %w", name, err)
+	}
+	return b, nil
+}
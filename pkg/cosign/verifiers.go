@@ -17,7 +17,6 @@ package cosign
 
 import (
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -25,6 +24,7 @@ import (
 	"github.com/in-toto/in-toto-golang/in_toto"
 	"github.com/secure-systems-lab/go-securesystemslib/dsse"
 
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/sigstore/pkg/signature/payload"
 )
@@ -37,7 +37,7 @@ func SimpleClaimVerifier(sig oci.Signature, imageDigest v1.Hash, annotations map
 	}
 
 	ss := &payload.SimpleContainerImage{}
-	if err := json.Unmarshal(p, ss); err != nil {
+	if err := limits.Unmarshal(p, ss); err != nil {
 		return err
 	}
 
@@ -64,7 +64,7 @@ func IntotoSubjectClaimVerifier(sig oci.Signature, imageDigest v1.Hash, _ map[st
 
 	// The payload here is an envelope. We already verified the signature earlier.
 	e := dsse.Envelope{}
-	if err := json.Unmarshal(p, &e); err != nil {
+	if err := limits.Unmarshal(p, &e); err != nil {
 		return err
 	}
 	stBytes, err := base64.StdEncoding.DecodeString(e.Payload)
@@ -73,16 +73,11 @@ func IntotoSubjectClaimVerifier(sig oci.Signature, imageDigest v1.Hash, _ map[st
 	}
 
 	st := in_toto.Statement{}
-	if err := json.Unmarshal(stBytes, &st); err != nil {
+	if err := limits.Unmarshal(stBytes, &st); err != nil {
 		return err
 	}
 	for _, subj := range st.StatementHeader.Subject {
-		dgst, ok := subj.Digest["sha256"]
-		if !ok {
-			continue
-		}
-		subjDigest := "sha256:" + dgst
-		if subjDigest == imageDigest.String() {
+		if dgst, ok := subj.Digest[imageDigest.Algorithm]; ok && dgst == imageDigest.Hex {
 			return nil
 		}
 	}
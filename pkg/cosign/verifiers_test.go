@@ -15,9 +15,13 @@
 package cosign
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
 )
 
@@ -78,3 +82,34 @@ func Test_IntotoSubjectClaimVerifier(t *testing.T) {
 		}
 	}
 }
+
+func Test_IntotoSubjectClaimVerifierSHA512(t *testing.T) {
+	digest := v1.Hash{Algorithm: "sha512", Hex: strings.Repeat("ab", 64)}
+	st, err := attestation.GenerateStatement(attestation.GenerateOpts{
+		Predicate:       strings.NewReader(`{"foo":"bar"}`),
+		Type:            "https://example.com/predicate",
+		Digest:          digest.Hex,
+		DigestAlgorithm: digest.Algorithm,
+		Repo:            "example.com/app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := `{"payloadType":"application/vnd.in-toto+json","payload":"` + base64.StdEncoding.EncodeToString(b) + `","signatures":[]}`
+	ociSig, err := static.NewSignature([]byte(envelope), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := IntotoSubjectClaimVerifier(ociSig, digest, nil); err != nil {
+		t.Errorf("expected sha512 subject to match: %v", err)
+	}
+	// The same hex under a different algorithm must not match.
+	if err := IntotoSubjectClaimVerifier(ociSig, v1.Hash{Algorithm: "sha256", Hex: digest.Hex}, nil); err == nil {
+		t.Error("expected a sha256 digest not to match a sha512 subject")
+	}
+}
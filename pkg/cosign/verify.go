@@ -24,38 +24,38 @@ import (
 	"encoding/asn1"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/digitorus/timestamp"
 	cbundle "github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/cosign/rekorv2"
 	"github.com/sigstore/sigstore/pkg/tuf"
 
 	"github.com/sigstore/cosign/v2/pkg/blob"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
 	"github.com/sigstore/cosign/v2/pkg/types"
 
-	"github.com/cyberphone/json-canonicalization/go/src/webpki.org/jsoncanonicalizer"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 
 	ssldsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
 	ociexperimental "github.com/sigstore/cosign/v2/internal/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/internal/ui"
+	"github.com/sigstore/cosign/v2/pkg/cosign/tsa"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/layout"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/verify"
 	"github.com/sigstore/rekor/pkg/generated/client"
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature"
-	"github.com/sigstore/sigstore/pkg/signature/dsse"
 	"github.com/sigstore/sigstore/pkg/signature/options"
 	tsaverification "github.com/sigstore/timestamp-authority/pkg/verification"
 )
@@ -63,14 +63,15 @@ import (
 // Identity specifies an issuer/subject to verify a signature against.
 // Both IssuerRegExp/SubjectRegExp support regexp while Issuer/Subject are for
 // strict matching.
-type Identity struct {
-	Issuer        string
-	Subject       string
-	IssuerRegExp  string
-	SubjectRegExp string
-}
+type Identity = verify.Identity
 
 // CheckOpts are the options for checking signatures.
+//
+// The verification functions only read a CheckOpts, so one CheckOpts, with
+// the trust material it points to such as the certificate pools, the keys
+// and the Rekor client, may be shared by any number of concurrent
+// verifications, provided none of it is modified meanwhile. WarningHandler
+// and ClaimVerifier may then be called from several goroutines at once.
 type CheckOpts struct {
 	// RegistryClientOpts are the options for interacting with the container registry.
 	RegistryClientOpts []ociremote.Option
@@ -99,6 +100,14 @@ type CheckOpts struct {
 	RootCerts *x509.CertPool
 	// IntermediateCerts are the optional intermediate CA certs used to verify a certificate chain.
 	IntermediateCerts *x509.CertPool
+	// RootCertificates and IntermediateCertificates are the certificates of
+	// RootCerts and IntermediateCerts, see SetCertificates. A
+	// VerificationCache doesn't cache verifications with pools whose
+	// certificates aren't set, as the pools alone can't tell them apart.
+	RootCertificates         []*x509.Certificate
+	IntermediateCertificates []*x509.Certificate
+	// TrustAnchorKeys are the optional public keys of CAs, such as keys held in a KMS, that are trusted like RootCerts.
+	TrustAnchorKeys []crypto.PublicKey
 
 	// CertGithubWorkflowTrigger is the GitHub Workflow Trigger name expected for a certificate to be valid. The empty string means any certificate can be valid.
 	CertGithubWorkflowTrigger string
@@ -110,6 +119,9 @@ type CheckOpts struct {
 	CertGithubWorkflowRepository string
 	// CertGithubWorkflowRef is the GitHub Workflow Ref expected for a certificate to be valid. The empty string means any certificate can be valid.
 	CertGithubWorkflowRef string
+	// CertClaims constrain the OIDC token claims embedded in the extensions
+	// of a certificate, which must all match for it to be valid.
+	CertClaims []ClaimMatcher
 
 	// IgnoreSCT requires that a certificate contain an embedded SCT during verification. An SCT is proof of inclusion in a
 	// certificate transparency log.
@@ -119,6 +131,18 @@ type CheckOpts struct {
 	// CTLogPubKeys, if set, is used to validate SCTs against those keys.
 	// It is a map from log id to LogIDMetadata. It is a map from LogID to crypto.PublicKey. LogID is derived from the PublicKey (see RFC 6962 S3.2).
 	CTLogPubKeys *TrustedTransparencyLogPubKeys
+	// RequireCTInclusion requires, beyond the signature of its SCT, an
+	// inclusion proof of the certificate in the certificate transparency log
+	// of the SCT, to a tree head signed by the log key in CTLogPubKeys.
+	RequireCTInclusion bool
+	// CTInclusionProofs are inclusion proofs of certificates, such as those of
+	// an offline bundle. The proofs of other certificates are fetched from
+	// their log, unless Offline is set.
+	CTInclusionProofs []*CTInclusionProof
+	// CTLogClients returns the client of the certificate transparency log
+	// with the hex log ID. Defaults to the clients of the base URLs of the
+	// keys in CTLogPubKeys.
+	CTLogClients func(logID string) (CTLogClient, error)
 
 	// SignatureRef is the reference to the signature file. PayloadRef should always be specified as well (though it’s possible for a _some_ signatures to be verified without it, with a warning).
 	SignatureRef string
@@ -132,6 +156,11 @@ type CheckOpts struct {
 	// Force offline verification of the signature
 	Offline bool
 
+	// RekorBundleDir, if set, is a directory of pre-fetched Rekor entries
+	// that signatures without a Rekor bundle are checked against, instead of
+	// looking them up online.
+	RekorBundleDir RekorBundleDir
+
 	// Set of flags to verify an RFC3161 timestamp used for trusted timestamping
 	// TSACertificate is the certificate used to sign the timestamp. Optional, if provided in the timestamp
 	TSACertificate *x509.Certificate
@@ -139,9 +168,93 @@ type CheckOpts struct {
 	TSARootCertificates []*x509.Certificate
 	// TSAIntermediateCertificates are the set of intermediates for chain building
 	TSAIntermediateCertificates []*x509.Certificate
+	// TSACertificateChains are further timestamp authority chains, such as
+	// those of the intermediates a TSA rotated away from. Each RFC3161
+	// timestamp is verified with the chain, of these and of the certificates
+	// above, that issued it.
+	TSACertificateChains []TSACertificateChain
 
 	// IgnoreTlog skip tlog verification
 	IgnoreTlog bool
+	// Witnesses, if set, requires the checkpoint of the transparency log
+	// entry to be cosigned by witnesses. Rekor bundles have no checkpoint,
+	// so the entry is then looked up online.
+	Witnesses *rekorv2.WitnessPolicy
+	// RekorLog, if set, reads the tiles of the log of Rekor v2 entries whose
+	// own inclusion proof doesn't verify, or isn't to a checkpoint cosigned by
+	// the witnesses. Otherwise they're read from the base URL of the log in
+	// the trusted root.
+	RekorLog rekorv2.LogReader
+
+	// WarningHandler, if set, is called with each soft policy signal (see
+	// VerificationWarning) raised for a signature that passed verification.
+	WarningHandler func(sig oci.Signature, warning VerificationWarning)
+	// CertExpiryWarningWindow is how close to expiry a long-lived signing
+	// certificate may be before a warning is raised. Defaults to DefaultCertExpiryWarningWindow.
+	CertExpiryWarningWindow time.Duration
+
+	// Denylist, if set, rejects signatures made with revoked keys or
+	// identities, and signatures for revoked artifact digests.
+	Denylist *Denylist
+	// KeyUsage, if set, rejects signatures and attestations that their
+	// signers aren't allowed to make.
+	KeyUsage *KeyUsagePolicy
+
+	// EnforceExpiry rejects signatures whose signed payload has an
+	// ExpiresAnnotation in the past.
+	EnforceExpiry bool
+
+	// RequireEncrypted rejects images whose layers aren't all encrypted with
+	// OCIcrypt, see CheckEncryptedImage.
+	RequireEncrypted bool
+	// EncryptionRecipients, if set, are the KeyFingerprint values that the
+	// EncryptionRecipientsAnnotation of a signature must list.
+	EncryptionRecipients []string
+
+	// CertClockSkew extends the validity period of signing certificates on
+	// both ends when it is checked against the transparency log, timestamp
+	// or current time, to tolerate small clock differences between the
+	// signer, Fulcio and the log or timestamp authority.
+	CertClockSkew time.Duration
+
+	// IgnoreKeyUsage skips the key usage and basic constraints checks of the
+	// certificate chain (see CheckChainUsage), for legacy CAs that don't set
+	// them.
+	IgnoreKeyUsage bool
+	// AllowAnyEKU accepts signing certificates without an extended key usage
+	// extension, or with the any extended key usage, instead of requiring
+	// code signing.
+	AllowAnyEKU bool
+	// RequireNameConstraints requires a CA of the certificate chain to
+	// constrain the names it may issue certificates for.
+	RequireNameConstraints bool
+	// IssuerSPKIHashes, if set, pins the CA that issues signing certificates:
+	// the sha256:<hex> hash of the subject public key info of the issuer of
+	// the signing certificate must be one of them, so that a compromised
+	// intermediate of the same root can't issue them.
+	IssuerSPKIHashes []string
+	// MaxChainDepth, if positive, is the most CA certificates, intermediates
+	// and root, that the certificate chain may have above the signing
+	// certificate. 1 requires that it is issued by the root.
+	MaxChainDepth int
+
+	// PayloadTypes are the payloadType values that the DSSE envelopes of
+	// attestations may have, exactly, so that a payload signed as something
+	// else can't be passed off as an attestation. Only in-toto statements, of
+	// types.IntotoPayloadType, are accepted if it is empty.
+	PayloadTypes []string
+
+	// SignatureWorkers is the number of signatures or attestations of an
+	// image verified at once, one if it is not positive, but at most the
+	// workers of the budget config file. The calls to WarningHandler and
+	// ClaimVerifier may then come from several goroutines, one at a time for
+	// WarningHandler.
+	SignatureWorkers int
+	// StopAfterVerified, if positive, stops the verification of the
+	// signatures of an image once that many of them have verified, so that
+	// the others, e.g. stale signatures of earlier keys, aren't checked.
+	// Signatures verified concurrently by then are also returned.
+	StopAfterVerified int
 }
 
 // This is a substitutable signature verification function that can be used for verifying
@@ -157,25 +270,46 @@ type payloader interface {
 }
 
 func verifyOCIAttestation(ctx context.Context, verifier signature.Verifier, att payloader) error {
+	return verifyAttestationEnvelope(ctx, verifier, att, nil)
+}
+
+// attestationVerificationFn verifies attestations whose envelopes have one of
+// the payloadTypes of co.
+func attestationVerificationFn(co *CheckOpts) signatureVerificationFn {
+	return func(ctx context.Context, verifier signature.Verifier, att payloader) error {
+		return verifyAttestationEnvelope(ctx, verifier, att, co.PayloadTypes)
+	}
+}
+
+func verifyAttestationEnvelope(ctx context.Context, verifier signature.Verifier, att payloader, payloadTypes []string) error {
 	payload, err := att.Payload()
 	if err != nil {
 		return err
 	}
 
 	env := ssldsse.Envelope{}
-	if err := json.Unmarshal(payload, &env); err != nil {
+	if err := limits.Unmarshal(payload, &env); err != nil {
 		return err
 	}
 
-	if env.PayloadType != types.IntotoPayloadType {
-		return NewVerificationError("invalid payloadType %s on envelope. Expected %s", env.PayloadType, types.IntotoPayloadType)
-	}
-	dssev, err := ssldsse.NewEnvelopeVerifier(&dsse.VerifierAdapter{SignatureVerifier: verifier})
-	if err != nil {
+	if err := checkPayloadType(env.PayloadType, payloadTypes); err != nil {
 		return err
 	}
-	_, err = dssev.Verify(ctx, &env)
-	return err
+	return verify.VerifyEnvelope(ctx, verifier, &env)
+}
+
+// checkPayloadType returns an error if payloadType isn't one of
+// payloadTypes, or the in-toto payload type if there are none.
+func checkPayloadType(payloadType string, payloadTypes []string) error {
+	if len(payloadTypes) == 0 {
+		payloadTypes = []string{types.IntotoPayloadType}
+	}
+	for _, t := range payloadTypes {
+		if payloadType == t {
+			return nil
+		}
+	}
+	return newTypedVerificationError(ErrInvalidPayloadTypeType, "invalid payloadType %s on envelope. Expected %s", payloadType, strings.Join(payloadTypes, " or "))
 }
 
 func verifyOCISignature(ctx context.Context, verifier signature.Verifier, sig payloader) error {
@@ -183,20 +317,23 @@ func verifyOCISignature(ctx context.Context, verifier signature.Verifier, sig pa
 	if err != nil {
 		return err
 	}
-	signature, err := base64.StdEncoding.DecodeString(b64sig)
-	if err != nil {
-		return err
-	}
 	payload, err := sig.Payload()
 	if err != nil {
 		return err
 	}
-	return verifier.VerifySignature(bytes.NewReader(signature), bytes.NewReader(payload), options.WithContext(ctx))
+	return verify.VerifySignature(ctx, verifier, b64sig, payload)
 }
 
 // ValidateAndUnpackCert creates a Verifier from a certificate. Veries that the certificate
 // chains up to a trusted root. Optionally verifies the subject and issuer of the certificate.
 func ValidateAndUnpackCert(cert *x509.Certificate, co *CheckOpts) (signature.Verifier, error) {
+	return ValidateAndUnpackCertWithContext(context.Background(), cert, co)
+}
+
+// ValidateAndUnpackCertWithContext is like ValidateAndUnpackCert, but uses
+// ctx to verify the SCT and fetch the certificate transparency log inclusion
+// proof of the certificate.
+func ValidateAndUnpackCertWithContext(ctx context.Context, cert *x509.Certificate, co *CheckOpts) (signature.Verifier, error) {
 	verifier, err := signature.LoadVerifier(cert.PublicKey, crypto.SHA256)
 	if err != nil {
 		return nil, fmt.Errorf("invalid certificate found on signature: %w", err)
@@ -217,7 +354,16 @@ func ValidateAndUnpackCert(cert *x509.Certificate, co *CheckOpts) (signature.Ver
 	}
 
 	// Now verify the cert, then the signature.
-	chains, err := TrustedCert(cert, co.RootCerts, co.IntermediateCerts)
+	var chains [][]*x509.Certificate
+	if len(co.TrustAnchorKeys) > 0 {
+		chains, err = TrustedCertWithAnchorKeys(cert, co.RootCerts, co.IntermediateCerts, co.TrustAnchorKeys)
+	} else {
+		chains, err = TrustedCert(cert, co.RootCerts, co.IntermediateCerts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	chains, err = CheckChainUsage(chains, co)
 	if err != nil {
 		return nil, err
 	}
@@ -236,15 +382,14 @@ func ValidateAndUnpackCert(cert *x509.Certificate, co *CheckOpts) (signature.Ver
 		return nil, err
 	}
 	if !contains && len(co.SCT) == 0 {
-		// TODO: add error type instead of blank string
-		return nil, &VerificationError{"", "certificate does not include required embedded SCT and no detached SCT was set"}
+		return nil, &VerificationError{ErrMissingSCTType, ErrMissingSCTMessage}
 	}
 	// handle if chains has more than one chain - grab first and print message
 	if len(chains) > 1 {
 		fmt.Fprintf(os.Stderr, "**Info** Multiple valid certificate chains found. Selecting the first to verify the SCT.\n")
 	}
 	if contains {
-		if err := VerifyEmbeddedSCT(context.Background(), chains[0], co.CTLogPubKeys); err != nil {
+		if err := VerifyEmbeddedSCT(ctx, chains[0], co.CTLogPubKeys); err != nil {
 			return nil, err
 		}
 	} else {
@@ -260,7 +405,12 @@ func ValidateAndUnpackCert(cert *x509.Certificate, co *CheckOpts) (signature.Ver
 		if err != nil {
 			return nil, err
 		}
-		if err := VerifySCT(context.Background(), certPEM, chainPEM, co.SCT, co.CTLogPubKeys); err != nil {
+		if err := VerifySCT(ctx, certPEM, chainPEM, co.SCT, co.CTLogPubKeys); err != nil {
+			return nil, err
+		}
+	}
+	if co.RequireCTInclusion {
+		if err := verifyCTInclusion(ctx, chains[0], co); err != nil {
 			return nil, err
 		}
 	}
@@ -276,146 +426,90 @@ func CheckCertificatePolicy(cert *x509.Certificate, co *CheckOpts) error {
 	if err := validateCertExtensions(ce, co); err != nil {
 		return err
 	}
-	oidcIssuer := ce.GetIssuer()
-	sans := getSubjectAlternateNames(cert)
-	// If there are identities given, go through them and if one of them
-	// matches, call that good, otherwise, return an error.
-	if len(co.Identities) > 0 {
-		for _, identity := range co.Identities {
-			issuerMatches := false
-			switch {
-			// Check the issuer first
-			case identity.IssuerRegExp != "":
-				if regex, err := regexp.Compile(identity.IssuerRegExp); err != nil {
-					return fmt.Errorf("malformed issuer in identity: %s : %w", identity.IssuerRegExp, err)
-				} else if regex.MatchString(oidcIssuer) {
-					issuerMatches = true
-				}
-			case identity.Issuer != "":
-				if identity.Issuer == oidcIssuer {
-					issuerMatches = true
-				}
-			default:
-				// No issuer constraint on this identity, so checks out
-				issuerMatches = true
-			}
+	err := verify.CheckIdentities(cert, co.Identities)
+	var mismatch *verify.IdentityMismatchError
+	if errors.As(err, &mismatch) {
+		return newIdentityMismatchError(co.Identities, mismatch.Subjects, mismatch.Issuer)
+	}
+	return err
+}
 
-			// Then the subject
-			subjectMatches := false
-			switch {
-			case identity.SubjectRegExp != "":
-				regex, err := regexp.Compile(identity.SubjectRegExp)
-				if err != nil {
-					return fmt.Errorf("malformed subject in identity: %s : %w", identity.SubjectRegExp, err)
-				}
-				for _, san := range sans {
-					if regex.MatchString(san) {
-						subjectMatches = true
-						break
-					}
-				}
-			case identity.Subject != "":
-				for _, san := range sans {
-					if san == identity.Subject {
-						subjectMatches = true
-						break
-					}
-				}
-			default:
-				// No subject constraint on this identity, so checks out
-				subjectMatches = true
-			}
-			if subjectMatches && issuerMatches {
-				// If both issuer / subject match, return verified
-				return nil
-			}
-		}
-		return &VerificationError{"",
-			fmt.Sprintf("none of the expected identities matched what was in the certificate, got subjects [%s] with issuer %s",
-				strings.Join(sans, ", "), oidcIssuer)}
+// MatchedIdentity returns the index of the first of identities that the
+// subject and issuer of cert match, e.g. to report which of several
+// identities a signature was accepted for, or -1 if it matches none.
+func MatchedIdentity(cert *x509.Certificate, identities []Identity) int {
+	i, err := verify.MatchIdentity(cert, identities)
+	if err != nil {
+		return -1
 	}
-	return nil
+	return i
 }
 
 func validateCertExtensions(ce CertExtensions, co *CheckOpts) error {
 	if co.CertGithubWorkflowTrigger != "" {
-		if ce.GetCertExtensionGithubWorkflowTrigger() != co.CertGithubWorkflowTrigger {
-			// TODO: add error type instead of blank string
-			return &VerificationError{"", "expected GitHub Workflow Trigger not found in certificate"}
+		if actual := ce.GetCertExtensionGithubWorkflowTrigger(); actual != co.CertGithubWorkflowTrigger {
+			return newCertExtensionMismatchError("GitHub Workflow Trigger", co.CertGithubWorkflowTrigger, actual)
 		}
 	}
 
 	if co.CertGithubWorkflowSha != "" {
-		if ce.GetExtensionGithubWorkflowSha() != co.CertGithubWorkflowSha {
-			// TODO: add error type instead of blank string
-			return &VerificationError{"", "expected GitHub Workflow SHA not found in certificate"}
+		if actual := ce.GetExtensionGithubWorkflowSha(); actual != co.CertGithubWorkflowSha {
+			return newCertExtensionMismatchError("GitHub Workflow SHA", co.CertGithubWorkflowSha, actual)
 		}
 	}
 
 	if co.CertGithubWorkflowName != "" {
-		if ce.GetCertExtensionGithubWorkflowName() != co.CertGithubWorkflowName {
-			// TODO: add error type instead of blank string
-			return &VerificationError{"", "expected GitHub Workflow Name not found in certificate"}
+		if actual := ce.GetCertExtensionGithubWorkflowName(); actual != co.CertGithubWorkflowName {
+			return newCertExtensionMismatchError("GitHub Workflow Name", co.CertGithubWorkflowName, actual)
 		}
 	}
 
 	if co.CertGithubWorkflowRepository != "" {
-		if ce.GetCertExtensionGithubWorkflowRepository() != co.CertGithubWorkflowRepository {
-			// TODO: add error type instead of blank string
-			return &VerificationError{"", "expected GitHub Workflow Repository not found in certificate"}
+		if actual := ce.GetCertExtensionGithubWorkflowRepository(); actual != co.CertGithubWorkflowRepository {
+			return newCertExtensionMismatchError("GitHub Workflow Repository", co.CertGithubWorkflowRepository, actual)
 		}
 	}
 
 	if co.CertGithubWorkflowRef != "" {
-		if ce.GetCertExtensionGithubWorkflowRef() != co.CertGithubWorkflowRef {
-			// TODO: add error type instead of blank string
-			return &VerificationError{"", "expected GitHub Workflow Ref not found in certificate"}
+		if actual := ce.GetCertExtensionGithubWorkflowRef(); actual != co.CertGithubWorkflowRef {
+			return newCertExtensionMismatchError("GitHub Workflow Ref", co.CertGithubWorkflowRef, actual)
 		}
 	}
-	return nil
+	return validateCertClaims(ce.Cert, co)
 }
 
-// getSubjectAlternateNames returns all of the following for a Certificate.
-// DNSNames
-// EmailAddresses
-// IPAddresses
-// URIs
+// getSubjectAlternateNames returns the DNS names, email addresses, IP
+// addresses, URIs and OtherName of a Certificate.
 func getSubjectAlternateNames(cert *x509.Certificate) []string {
-	sans := []string{}
-	sans = append(sans, cert.DNSNames...)
-	sans = append(sans, cert.EmailAddresses...)
-	for _, ip := range cert.IPAddresses {
-		sans = append(sans, ip.String())
-	}
-	for _, uri := range cert.URIs {
-		sans = append(sans, uri.String())
-	}
-	// ignore error if there's no OtherName SAN
-	otherName, _ := cryptoutils.UnmarshalOtherNameSAN(cert.Extensions)
-	if len(otherName) > 0 {
-		sans = append(sans, otherName)
-	}
-	return sans
+	return verify.SubjectAlternativeNames(cert)
 }
 
 // ValidateAndUnpackCertWithChain creates a Verifier from a certificate. Verifies that the certificate
 // chains up to the provided root. Chain should start with the parent of the certificate and end with the root.
-// Optionally verifies the subject and issuer of the certificate.
+// Optionally verifies the subject and issuer of the certificate. The pools of
+// co are replaced by those of the chain in a copy, and co isn't modified.
 func ValidateAndUnpackCertWithChain(cert *x509.Certificate, chain []*x509.Certificate, co *CheckOpts) (signature.Verifier, error) {
+	return ValidateAndUnpackCertWithChainContext(context.Background(), cert, chain, co)
+}
+
+// ValidateAndUnpackCertWithChainContext is like ValidateAndUnpackCertWithChain,
+// but uses ctx as ValidateAndUnpackCertWithContext does.
+func ValidateAndUnpackCertWithChainContext(ctx context.Context, cert *x509.Certificate, chain []*x509.Certificate, co *CheckOpts) (signature.Verifier, error) {
 	if len(chain) == 0 {
 		return nil, errors.New("no chain provided to validate certificate")
 	}
+	chainCo := *co
 	rootPool := x509.NewCertPool()
 	rootPool.AddCert(chain[len(chain)-1])
-	co.RootCerts = rootPool
+	chainCo.RootCerts = rootPool
 
 	subPool := x509.NewCertPool()
 	for _, c := range chain[:len(chain)-1] {
 		subPool.AddCert(c)
 	}
-	co.IntermediateCerts = subPool
+	chainCo.IntermediateCerts = subPool
 
-	return ValidateAndUnpackCert(cert, co)
+	return ValidateAndUnpackCertWithContext(ctx, cert, &chainCo)
 }
 
 func tlogValidateEntry(ctx context.Context, client *client.Rekor, rekorPubKeys *TrustedTransparencyLogPubKeys,
@@ -433,7 +527,7 @@ func tlogValidateEntry(ctx context.Context, client *client.Rekor, rekorPubKeys *
 		return nil, err
 	}
 	if len(tlogEntries) == 0 {
-		return nil, fmt.Errorf("no valid tlog entries found with proposed entry")
+		return nil, newTypedVerificationError(ErrTlogMissingType, "no valid tlog entries found with proposed entry")
 	}
 	// Always return the earliest integrated entry. That
 	// always suffices for verification of signature time.
@@ -453,7 +547,7 @@ func tlogValidateEntry(ctx context.Context, client *client.Rekor, rekorPubKeys *
 		}
 	}
 	if earliestLogEntryTime == nil {
-		return nil, fmt.Errorf("no valid tlog entries found %s", strings.Join(entryVerificationErrs, ", "))
+		return nil, newTypedVerificationError(ErrTlogMissingType, "no valid tlog entries found %s", strings.Join(entryVerificationErrs, ", "))
 	}
 	return &earliestLogEntry, nil
 }
@@ -470,6 +564,16 @@ func (fos *fakeOCISignatures) Get() ([]oci.Signature, error) {
 // VerifyImageSignatures does all the main cosign checks in a loop, returning the verified signatures.
 // If there were no valid signatures, we return an error.
 func VerifyImageSignatures(ctx context.Context, signedImgRef name.Reference, co *CheckOpts) (checkedSignatures []oci.Signature, bundleVerified bool, err error) {
+	if co.RequireEncrypted {
+		se, err := ociremote.SignedEntity(signedImgRef, co.RegistryClientOpts...)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := CheckEncryptedImage(se); err != nil {
+			return nil, false, err
+		}
+	}
+
 	// Try first using OCI 1.1 behavior
 	verified, bundleVerified, err := verifyImageSignaturesExperimentalOCI(ctx, signedImgRef, co)
 	if err == nil {
@@ -493,7 +597,7 @@ func VerifyImageSignatures(ctx context.Context, signedImgRef name.Reference, co
 		}
 		return nil, false, err
 	}
-	h, err := v1.NewHash(digest.Identifier())
+	h, err := oci.ParseDigest(digest.Identifier())
 	if err != nil {
 		return nil, false, err
 	}
@@ -527,47 +631,44 @@ func VerifyLocalImageSignatures(ctx context.Context, path string, co *CheckOpts)
 		return nil, false, errors.New("one of verifier or root certs is required")
 	}
 
-	se, err := layout.SignedImageIndex(path)
-	if err != nil {
-		return nil, false, err
-	}
-
-	var h v1.Hash
-	// Verify either an image index or image.
-	ii, err := se.SignedImageIndex(v1.Hash{})
+	se, h, err := localImage(path)
 	if err != nil {
 		return nil, false, err
 	}
-	i, err := se.SignedImage(v1.Hash{})
-	if err != nil {
-		return nil, false, err
-	}
-	switch {
-	case ii != nil:
-		h, err = ii.Digest()
-		if err != nil {
+	if co.RequireEncrypted {
+		if err := CheckEncryptedImage(se); err != nil {
 			return nil, false, err
 		}
-	case i != nil:
-		h, err = i.Digest()
-		if err != nil {
-			return nil, false, err
-		}
-	default:
-		return nil, false, errors.New("must verify either an image index or image")
 	}
 
 	sigs, err := se.Signatures()
 	if err != nil {
 		return nil, false, err
 	}
-	if sigs == nil {
+	if sl, err := sigs.Get(); err == nil && len(sl) == 0 {
 		return nil, false, fmt.Errorf("no signatures associated with the image saved in %s", path)
 	}
 
 	return verifySignatures(ctx, sigs, h, co)
 }
 
+// localImage returns the image or image index saved in the OCI layout at
+// path, with the signatures and attestations stored with it, and its digest.
+func localImage(path string) (oci.SignedEntity, v1.Hash, error) {
+	l, err := layout.LoadLocal(path)
+	if err != nil {
+		return nil, v1.Hash{}, err
+	}
+	if l.Tarball {
+		return nil, v1.Hash{}, fmt.Errorf("%s is a docker tarball, which can't hold signatures; verify the OCI layout it was signed to with --local-image-output", path)
+	}
+	h, err := l.Entity.Digest()
+	if err != nil {
+		return nil, v1.Hash{}, err
+	}
+	return l.Entity, h, nil
+}
+
 func verifySignatures(ctx context.Context, sigs oci.Signatures, h v1.Hash, co *CheckOpts) (checkedSignatures []oci.Signature, bundleVerified bool, err error) {
 	sl, err := sigs.Get()
 	if err != nil {
@@ -581,29 +682,11 @@ func verifySignatures(ctx context.Context, sigs oci.Signatures, h v1.Hash, co *C
 		}
 	}
 
-	validationErrs := []string{}
-
-	for _, sig := range sl {
-		sig, err := static.Copy(sig)
-		if err != nil {
-			validationErrs = append(validationErrs, err.Error())
-			continue
-		}
-		verified, err := VerifyImageSignature(ctx, sig, h, co)
-		bundleVerified = bundleVerified || verified
-		if err != nil {
-			validationErrs = append(validationErrs, err.Error())
-			continue
-		}
-
-		// Phew, we made it.
-		checkedSignatures = append(checkedSignatures, sig)
-	}
+	checkedSignatures, bundleVerified, validationErrs := verifyCandidates(ctx, sl, co, co.StopAfterVerified, func(ctx context.Context, sig oci.Signature, co *CheckOpts) (bool, error) {
+		return VerifyImageSignature(ctx, sig, h, co)
+	})
 	if len(checkedSignatures) == 0 {
-		return nil, false, &VerificationError{
-			errorType: ErrNoMatchingSignaturesType,
-			message:   fmt.Sprintf("%s:\n%s", ErrNoMatchingSignaturesMessage, strings.Join(validationErrs, "\n ")),
-		}
+		return nil, false, newNoMatchingSignaturesError(ErrNoMatchingSignaturesType, ErrNoMatchingSignaturesMessage, validationErrs)
 	}
 	return checkedSignatures, bundleVerified, nil
 }
@@ -612,15 +695,18 @@ func verifySignatures(ctx context.Context, sigs oci.Signatures, h v1.Hash, co *C
 //  1. Verifies the signature using the provided verifier.
 //  2. Checks for transparency log entry presence:
 //     a. Verifies the Rekor entry in the bundle, if provided. This works offline OR
-//     b. If we don't have a Rekor entry retrieved via cert, do an online lookup (assuming
+//     b. Verifies the pre-fetched Rekor entry in co.RekorBundleDir, if any, offline OR
+//     c. If we don't have a Rekor entry retrieved via cert, do an online lookup (assuming
 //     we are in experimental mode).
 //  3. If a certificate is provided, check it's expiration using the transparency log timestamp.
 func verifyInternal(ctx context.Context, sig oci.Signature, h v1.Hash,
 	verifyFn signatureVerificationFn, co *CheckOpts) (
 	bundleVerified bool, err error) {
 	var acceptableRFC3161Time, acceptableRekorBundleTime *time.Time // Timestamps for the signature we accept, or nil if not applicable.
+	var logID string                                                // The transparency log that attested to the signature, if any.
+	var tlogEntry *models.LogEntryAnon                              // The transparency log entry looked up online or pre-fetched, if any.
 
-	if co.TSARootCertificates != nil {
+	if co.TSARootCertificates != nil || len(co.TSACertificateChains) > 0 {
 		acceptableRFC3161Timestamp, err := VerifyRFC3161Timestamp(sig, co)
 		if err != nil {
 			return false, fmt.Errorf("unable to verify RFC3161 timestamp bundle: %w", err)
@@ -643,11 +729,21 @@ func verifyInternal(ctx context.Context, sig oci.Signature, h v1.Hash,
 				return false, fmt.Errorf("error getting bundle integrated time: %w", err)
 			}
 			acceptableRekorBundleTime = &t
+			if b, err := sig.Bundle(); err == nil && b != nil {
+				logID = b.Payload.LogID
+			}
+		} else if tlogEntry, err = verifyRekorBundleDirEntry(ctx, sig, co); err != nil {
+			return false, err
+		} else if tlogEntry != nil {
+			bundleVerified = true
+			t := time.Unix(*tlogEntry.IntegratedTime, 0)
+			acceptableRekorBundleTime = &t
+			logID = *tlogEntry.LogID
 		} else {
 			// If the --offline flag was specified, fail here. bundleVerified returns false with
 			// no error when there was no bundle provided.
 			if co.Offline {
-				return false, fmt.Errorf("offline verification failed")
+				return false, newTypedVerificationError(ErrTlogMissingType, "offline verification failed")
 			}
 
 			// no Rekor client provided for an online lookup
@@ -660,12 +756,21 @@ func verifyInternal(ctx context.Context, sig oci.Signature, h v1.Hash,
 				return false, err
 			}
 
-			e, err := tlogValidateEntry(ctx, co.RekorClient, co.RekorPubKeys, sig, pemBytes)
+			tlogEntry, err = tlogValidateEntry(ctx, co.RekorClient, co.RekorPubKeys, sig, pemBytes)
 			if err != nil {
 				return false, err
 			}
-			t := time.Unix(*e.IntegratedTime, 0)
+			t := time.Unix(*tlogEntry.IntegratedTime, 0)
 			acceptableRekorBundleTime = &t
+			if tlogEntry.LogID != nil {
+				logID = *tlogEntry.LogID
+			}
+		}
+
+		if co.Witnesses != nil {
+			if err := verifyTlogWitnesses(ctx, sig, tlogEntry, co); err != nil {
+				return false, err
+			}
 		}
 	}
 
@@ -677,8 +782,7 @@ func verifyInternal(ctx context.Context, sig oci.Signature, h v1.Hash,
 			return false, err
 		}
 		if cert == nil {
-			// TODO: add error type instead of blank string
-			return false, &VerificationError{"", "no certificate found on signature"}
+			return false, &VerificationError{ErrNoCertificateFoundType, ErrNoCertificateFoundMessage}
 		}
 		// Create a certificate pool for intermediate CA certificates, excluding the root
 		chain, err := sig.Chain()
@@ -686,29 +790,44 @@ func verifyInternal(ctx context.Context, sig oci.Signature, h v1.Hash,
 			return false, err
 		}
 		// If there is no chain annotation present, we preserve the pools set in the CheckOpts.
+		// The pools of the chain are set on a copy, as co may be shared by
+		// concurrent verifications.
+		certCo := co
 		if len(chain) > 0 {
 			if len(chain) == 1 {
-				co.IntermediateCerts = nil
+				c := *co
+				c.IntermediateCerts = nil
+				certCo = &c
 			} else if co.IntermediateCerts == nil {
 				// If the intermediate certs have not been loaded in by TUF
 				pool := x509.NewCertPool()
 				for _, cert := range chain[:len(chain)-1] {
 					pool.AddCert(cert)
 				}
-				co.IntermediateCerts = pool
+				c := *co
+				c.IntermediateCerts = pool
+				certCo = &c
 			}
 		}
-		verifier, err = ValidateAndUnpackCert(cert, co)
+		verifier, err = ValidateAndUnpackCertWithContext(ctx, cert, certCo)
 		if err != nil {
 			return false, err
 		}
 	}
 
+	if err := co.Denylist.check(sig, h, verifier); err != nil {
+		return false, err
+	}
+
 	// 1. Perform cryptographic verification of the signature using the certificate's public key.
 	if err := verifyFn(ctx, verifier, sig); err != nil {
 		return false, err
 	}
 
+	if err := co.KeyUsage.check(sig, verifier); err != nil {
+		return false, err
+	}
+
 	// We can't check annotations without claims, both require unmarshalling the payload.
 	if co.ClaimVerifier != nil {
 		if err := co.ClaimVerifier(sig, h, co.Annotations); err != nil {
@@ -716,6 +835,18 @@ func verifyInternal(ctx context.Context, sig oci.Signature, h v1.Hash,
 		}
 	}
 
+	if co.EnforceExpiry {
+		if err := checkExpiresAnnotation(sig, time.Now()); err != nil {
+			return false, err
+		}
+	}
+
+	if len(co.EncryptionRecipients) > 0 {
+		if err := checkEncryptionRecipients(sig, co.EncryptionRecipients); err != nil {
+			return false, err
+		}
+	}
+
 	// 2. if a certificate was used, verify the certificate expiration against a time
 	cert, err := sig.Cert()
 	if err != nil {
@@ -727,14 +858,14 @@ func verifyInternal(ctx context.Context, sig oci.Signature, h v1.Hash,
 
 		if acceptableRFC3161Time != nil {
 			// Verify the cert against the timestamp time.
-			if err := CheckExpiry(cert, *acceptableRFC3161Time); err != nil {
+			if err := CheckExpiryWithSkew(cert, *acceptableRFC3161Time, co.CertClockSkew); err != nil {
 				return false, fmt.Errorf("checking expiry on certificate with timestamp: %w", err)
 			}
 			expirationChecked = true
 		}
 
 		if acceptableRekorBundleTime != nil {
-			if err := CheckExpiry(cert, *acceptableRekorBundleTime); err != nil {
+			if err := CheckExpiryWithSkew(cert, *acceptableRekorBundleTime, co.CertClockSkew); err != nil {
 				return false, fmt.Errorf("checking expiry on certificate with bundle: %w", err)
 			}
 			expirationChecked = true
@@ -742,17 +873,18 @@ func verifyInternal(ctx context.Context, sig oci.Signature, h v1.Hash,
 
 		// if no timestamp has been provided, use the current time
 		if !expirationChecked {
-			if err := CheckExpiry(cert, time.Now()); err != nil {
+			if err := CheckExpiryWithSkew(cert, time.Now(), co.CertClockSkew); err != nil {
 				// If certificate is expired and not signed timestamp was provided then error the following message. Otherwise throw an expiration error.
 				if co.IgnoreTlog && acceptableRFC3161Time == nil {
-					// TODO: add error type instead of blank string
-					return false, &VerificationError{"", "expected a signed timestamp to verify an expired certificate"}
+					return false, &VerificationError{ErrMissingTimestampType, ErrMissingTimestampMessage}
 				}
 				return false, fmt.Errorf("checking expiry on certificate with bundle: %w", err)
 			}
 		}
 	}
 
+	checkWarnings(sig, logID, co)
+
 	return bundleVerified, nil
 }
 
@@ -778,6 +910,34 @@ func VerifyBlobSignature(ctx context.Context, sig oci.Signature, co *CheckOpts)
 	return verifyInternal(ctx, sig, v1.Hash{}, verifyOCISignature, co)
 }
 
+// VerifyBlobDigestSignature verifies a signature over digest, the digest of
+// a blob with alg, which was signed instead of the blob itself. Unless the
+// transparency log accepts digests of alg, co must ignore the tlog.
+func VerifyBlobDigestSignature(ctx context.Context, sig oci.Signature, alg blob.DigestAlgorithm, digest []byte, co *CheckOpts) (bundleVerified bool, err error) {
+	if !alg.Tlog && !co.IgnoreTlog {
+		return false, fmt.Errorf("the transparency log doesn't accept signatures over %s blob digests", alg.Name)
+	}
+	return verifyInternal(ctx, sig, v1.Hash{}, func(ctx context.Context, verifier signature.Verifier, sig payloader) error {
+		pub, err := verifier.PublicKey()
+		if err != nil {
+			return err
+		}
+		if err := alg.CheckKey(pub); err != nil {
+			return err
+		}
+		b64sig, err := sig.Base64Signature()
+		if err != nil {
+			return err
+		}
+		raw, err := base64.StdEncoding.DecodeString(b64sig)
+		if err != nil {
+			return err
+		}
+		opts := append(alg.VerifyOptions(digest), options.WithContext(ctx))
+		return verifier.VerifySignature(bytes.NewReader(raw), nil, opts...)
+	}, co)
+}
+
 // VerifyImageSignature verifies a signature
 func VerifyImageSignature(ctx context.Context, sig oci.Signature, h v1.Hash, co *CheckOpts) (bundleVerified bool, err error) {
 	return verifyInternal(ctx, sig, h, verifyOCISignature, co)
@@ -835,6 +995,12 @@ func VerifyImageAttestations(ctx context.Context, signedImgRef name.Reference, c
 		return nil, false, errors.New("one of verifier or root certs is required")
 	}
 
+	// Try first using OCI 1.1 behavior
+	verified, bundleVerified, err := verifyImageAttestationsExperimentalOCI(ctx, signedImgRef, co)
+	if err == nil {
+		return verified, bundleVerified, nil
+	}
+
 	// This is a carefully optimized sequence for fetching the attestations of
 	// the entity that minimizes registry requests when supplied with a digest
 	// input.
@@ -842,7 +1008,7 @@ func VerifyImageAttestations(ctx context.Context, signedImgRef name.Reference, c
 	if err != nil {
 		return nil, false, err
 	}
-	h, err := v1.NewHash(digest.Identifier())
+	h, err := oci.ParseDigest(digest.Identifier())
 	if err != nil {
 		return nil, false, err
 	}
@@ -867,35 +1033,10 @@ func VerifyLocalImageAttestations(ctx context.Context, path string, co *CheckOpt
 		return nil, false, errors.New("one of verifier or root certs is required")
 	}
 
-	se, err := layout.SignedImageIndex(path)
-	if err != nil {
-		return nil, false, err
-	}
-
-	var h v1.Hash
-	// Verify either an image index or image.
-	ii, err := se.SignedImageIndex(v1.Hash{})
-	if err != nil {
-		return nil, false, err
-	}
-	i, err := se.SignedImage(v1.Hash{})
+	se, h, err := localImage(path)
 	if err != nil {
 		return nil, false, err
 	}
-	switch {
-	case ii != nil:
-		h, err = ii.Digest()
-		if err != nil {
-			return nil, false, err
-		}
-	case i != nil:
-		h, err = i.Digest()
-		if err != nil {
-			return nil, false, err
-		}
-	default:
-		return nil, false, errors.New("must verify either an image index or image")
-	}
 
 	atts, err := se.Attestations()
 	if err != nil {
@@ -906,7 +1047,7 @@ func VerifyLocalImageAttestations(ctx context.Context, path string, co *CheckOpt
 
 func VerifyBlobAttestation(ctx context.Context, att oci.Signature, h v1.Hash, co *CheckOpts) (
 	bool, error) {
-	return verifyInternal(ctx, att, h, verifyOCIAttestation, co)
+	return verifyInternal(ctx, att, h, attestationVerificationFn(co), co)
 }
 
 func verifyImageAttestations(ctx context.Context, atts oci.Signatures, h v1.Hash, co *CheckOpts) (checkedAttestations []oci.Signature, bundleVerified bool, err error) {
@@ -915,46 +1056,50 @@ func verifyImageAttestations(ctx context.Context, atts oci.Signatures, h v1.Hash
 		return nil, false, err
 	}
 
-	validationErrs := []string{}
-	for _, att := range sl {
-		att, err := static.Copy(att)
-		if err != nil {
-			validationErrs = append(validationErrs, err.Error())
-			continue
-		}
-		if err := func(att oci.Signature) error {
-			verified, err := verifyInternal(ctx, att, h, verifyOCIAttestation, co)
-			bundleVerified = bundleVerified || verified
-			return err
-		}(att); err != nil {
-			validationErrs = append(validationErrs, err.Error())
-			continue
-		}
-
-		// Phew, we made it.
-		checkedAttestations = append(checkedAttestations, att)
-	}
+	checkedAttestations, bundleVerified, validationErrs := verifyCandidates(ctx, sl, co, 0, func(ctx context.Context, att oci.Signature, co *CheckOpts) (bool, error) {
+		return verifyInternal(ctx, att, h, attestationVerificationFn(co), co)
+	})
 	if len(checkedAttestations) == 0 {
-		return nil, false, &VerificationError{
-			errorType: ErrNoMatchingAttestationsType,
-			message:   fmt.Sprintf("%s:\n%s", ErrNoMatchingAttestationsMessage, strings.Join(validationErrs, "\n ")),
-		}
+		return nil, false, newNoMatchingSignaturesError(ErrNoMatchingAttestationsType, ErrNoMatchingAttestationsMessage, validationErrs)
 	}
 	return checkedAttestations, bundleVerified, nil
 }
 
 // CheckExpiry confirms the time provided is within the valid period of the cert
 func CheckExpiry(cert *x509.Certificate, it time.Time) error {
+	return CheckExpiryWithSkew(cert, it, 0)
+}
+
+// CheckExpiryWithSkew is like CheckExpiry, but accepts times up to skew
+// before or after the valid period of the cert. A negative skew is treated
+// as zero.
+func CheckExpiryWithSkew(cert *x509.Certificate, it time.Time, skew time.Duration) error {
 	ft := func(t time.Time) string {
 		return t.Format(time.RFC3339)
 	}
-	if cert.NotAfter.Before(it) {
-		return NewVerificationError("certificate expired before signatures were entered in log: %s is before %s",
-			ft(cert.NotAfter), ft(it))
+	allowed := ""
+	if skew > 0 {
+		allowed = fmt.Sprintf(", allowing %s of clock skew", skew)
+	} else {
+		skew = 0
 	}
-	if cert.NotBefore.After(it) {
-		return NewVerificationError("certificate was issued after signatures were entered in log: %s is after %s",
-			ft(cert.NotAfter), ft(it))
+	if cert.NotAfter.Add(skew).Before(it) {
+		return &CertificateExpiredError{
+			VerificationError: newTypedVerificationError(ErrCertificateExpiredType, "certificate expired before signatures were entered in log: %s is before %s%s",
+				ft(cert.NotAfter), ft(it), allowed),
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			At:        it,
+		}
+	}
+	if cert.NotBefore.Add(-skew).After(it) {
+		return &CertificateExpiredError{
+			VerificationError: newTypedVerificationError(ErrCertificateExpiredType, "certificate was issued after signatures were entered in log: %s is after %s%s",
+				ft(cert.NotAfter), ft(it), allowed),
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			At:        it,
+		}
 	}
 	return nil
 }
@@ -999,8 +1144,10 @@ func VerifyBundle(sig oci.Signature, co *CheckOpts) (bool, error) {
 
 	pubKey, ok := co.RekorPubKeys.Keys[bundle.Payload.LogID]
 	if !ok {
-		// TODO: add error type instead of blank string
-		return false, &VerificationError{"", "verifying bundle: rekor log public key not found for payload"}
+		return false, newTypedVerificationError(ErrTlogKeyNotFoundType, "verifying bundle: %s", ErrTlogKeyNotFoundMessage)
+	}
+	if err := pubKey.checkIntegratedTime(bundle.Payload.IntegratedTime); err != nil {
+		return false, err
 	}
 	err = VerifySET(bundle.Payload, bundle.SignedEntryTimestamp, pubKey.PubKey.(*ecdsa.PublicKey))
 	if err != nil {
@@ -1062,12 +1209,55 @@ func VerifyRFC3161Timestamp(sig oci.Signature, co *CheckOpts) (*timestamp.Timest
 		tsBytes = rawSig
 	}
 
-	return tsaverification.VerifyTimestampResponse(ts.SignedRFC3161Timestamp, bytes.NewReader(tsBytes),
-		tsaverification.VerifyOpts{
+	return tsa.VerifyTimestampResponse(ts.SignedRFC3161Timestamp, tsBytes, co.tsaCertificateChains())
+}
+
+// TSACertificateChain is the certificate chain of a timestamp authority.
+type TSACertificateChain struct {
+	// Certificate is the certificate that signs the timestamps. Optional, if
+	// provided in the timestamps.
+	Certificate   *x509.Certificate
+	Intermediates []*x509.Certificate
+	Roots         []*x509.Certificate
+}
+
+// SetCertificates sets RootCerts and IntermediateCerts to pools of roots and
+// intermediates, and keeps the certificates in RootCertificates and
+// IntermediateCertificates. A pool is nil without certificates.
+func (co *CheckOpts) SetCertificates(roots, intermediates []*x509.Certificate) {
+	co.RootCerts, co.RootCertificates = certPool(roots), roots
+	co.IntermediateCerts, co.IntermediateCertificates = certPool(intermediates), intermediates
+}
+
+func certPool(certs []*x509.Certificate) *x509.CertPool {
+	if len(certs) == 0 {
+		return nil
+	}
+	pool := x509.NewCertPool()
+	for _, c := range certs {
+		pool.AddCert(c)
+	}
+	return pool
+}
+
+// tsaCertificateChains returns the timestamp authority chains of co.
+func (co *CheckOpts) tsaCertificateChains() []tsaverification.VerifyOpts {
+	var chains []tsaverification.VerifyOpts
+	if co.TSARootCertificates != nil {
+		chains = append(chains, tsaverification.VerifyOpts{
 			TSACertificate: co.TSACertificate,
 			Intermediates:  co.TSAIntermediateCertificates,
 			Roots:          co.TSARootCertificates,
 		})
+	}
+	for _, c := range co.TSACertificateChains {
+		chains = append(chains, tsaverification.VerifyOpts{
+			TSACertificate: c.Certificate,
+			Intermediates:  c.Intermediates,
+			Roots:          c.Roots,
+		})
+	}
+	return chains
 }
 
 // compare bundle signature to the signature we are verifying
@@ -1090,8 +1280,7 @@ func compareSigs(bundleBody string, sig oci.Signature) error {
 		return nil
 	}
 	if bundleSignature != actualSig {
-		// TODO: add error type instead of blank string
-		return &VerificationError{"", "signature in bundle does not match signature being verified"}
+		return &VerificationError{ErrBundleMismatchType, ErrBundleMismatchMessage}
 	}
 	return nil
 }
@@ -1130,190 +1319,62 @@ func comparePublicKey(bundleBody string, sig oci.Signature, co *CheckOpts) error
 	return nil
 }
 
-func bundleHash(bundleBody, signature string) (string, string, error) {
-	var toto models.Intoto
-	var rekord models.Rekord
-	var hrekord models.Hashedrekord
-	var intotoObj models.IntotoV001Schema
-	var rekordObj models.RekordV001Schema
-	var hrekordObj models.HashedrekordV001Schema
-
+// bundleContents returns the contents of the entry of a bundle, of which
+// bundleBody is the base64 body.
+func bundleContents(bundleBody string) (*TlogEntryContents, error) {
 	bodyDecoded, err := base64.StdEncoding.DecodeString(bundleBody)
 	if err != nil {
-		return "", "", err
-	}
-
-	// The fact that there's no signature (or empty rather), implies
-	// that this is an Attestation that we're verifying.
-	if len(signature) == 0 {
-		err = json.Unmarshal(bodyDecoded, &toto)
-		if err != nil {
-			return "", "", err
-		}
-
-		specMarshal, err := json.Marshal(toto.Spec)
-		if err != nil {
-			return "", "", err
-		}
-		err = json.Unmarshal(specMarshal, &intotoObj)
-		if err != nil {
-			return "", "", err
-		}
-
-		return *intotoObj.Content.Hash.Algorithm, *intotoObj.Content.Hash.Value, nil
+		return nil, fmt.Errorf("decoding bundleBody: %w", err)
 	}
-
-	if err := json.Unmarshal(bodyDecoded, &rekord); err == nil {
-		specMarshal, err := json.Marshal(rekord.Spec)
-		if err != nil {
-			return "", "", err
-		}
-		err = json.Unmarshal(specMarshal, &rekordObj)
-		if err != nil {
-			return "", "", err
-		}
-		return *rekordObj.Data.Hash.Algorithm, *rekordObj.Data.Hash.Value, nil
+	if c, err := registeredEntryContents(bodyDecoded); c != nil || err != nil {
+		return c, err
 	}
+	return verify.ParseEntryBody(bodyDecoded)
+}
 
-	// Try hashedRekordObj
-	err = json.Unmarshal(bodyDecoded, &hrekord)
-	if err != nil {
-		return "", "", err
-	}
-	specMarshal, err := json.Marshal(hrekord.Spec)
+func bundleHash(bundleBody, signature string) (string, string, error) {
+	c, err := bundleContents(bundleBody)
 	if err != nil {
 		return "", "", err
 	}
-	err = json.Unmarshal(specMarshal, &hrekordObj)
-	if err != nil {
-		return "", "", err
+	// The fact that there's no signature (or empty rather), implies
+	// that this is an Attestation that we're verifying.
+	if len(signature) == 0 && c.Base64Signature != "" {
+		return "", "", errors.New("the entry of an attestation records a signature")
 	}
-	return *hrekordObj.Data.Hash.Algorithm, *hrekordObj.Data.Hash.Value, nil
+	return c.HashAlgorithm, c.HashValue, nil
 }
 
 // bundleSig extracts the signature from the rekor bundle body
 func bundleSig(bundleBody string) (string, error) {
-	var rekord models.Rekord
-	var hrekord models.Hashedrekord
-	var rekordObj models.RekordV001Schema
-	var hrekordObj models.HashedrekordV001Schema
-
-	bodyDecoded, err := base64.StdEncoding.DecodeString(bundleBody)
+	c, err := bundleContents(bundleBody)
 	if err != nil {
-		return "", fmt.Errorf("decoding bundleBody: %w", err)
-	}
-
-	// Try Rekord
-	if err := json.Unmarshal(bodyDecoded, &rekord); err == nil {
-		specMarshal, err := json.Marshal(rekord.Spec)
-		if err != nil {
-			return "", err
-		}
-		if err := json.Unmarshal(specMarshal, &rekordObj); err != nil {
-			return "", err
-		}
-		return rekordObj.Signature.Content.String(), nil
-	}
-
-	// Try hashedRekordObj
-	if err := json.Unmarshal(bodyDecoded, &hrekord); err != nil {
-		return "", err
-	}
-	specMarshal, err := json.Marshal(hrekord.Spec)
-	if err != nil {
-		return "", err
-	}
-	if err := json.Unmarshal(specMarshal, &hrekordObj); err != nil {
 		return "", err
 	}
-	return hrekordObj.Signature.Content.String(), nil
+	return c.Base64Signature, nil
 }
 
 // bundleKey extracts the key from the rekor bundle body
 func bundleKey(bundleBody string) (string, error) {
-	var rekord models.Rekord
-	var hrekord models.Hashedrekord
-	var intotod models.Intoto
-	var rekordObj models.RekordV001Schema
-	var hrekordObj models.HashedrekordV001Schema
-	var intotodObj models.IntotoV001Schema
-
-	bodyDecoded, err := base64.StdEncoding.DecodeString(bundleBody)
-	if err != nil {
-		return "", fmt.Errorf("decoding bundleBody: %w", err)
-	}
-
-	// Try Rekord
-	if err := json.Unmarshal(bodyDecoded, &rekord); err == nil {
-		specMarshal, err := json.Marshal(rekord.Spec)
-		if err != nil {
-			return "", err
-		}
-		if err := json.Unmarshal(specMarshal, &rekordObj); err != nil {
-			return "", err
-		}
-		return rekordObj.Signature.PublicKey.Content.String(), nil
-	}
-
-	// Try hashedRekordObj
-	if err := json.Unmarshal(bodyDecoded, &hrekord); err == nil {
-		specMarshal, err := json.Marshal(hrekord.Spec)
-		if err != nil {
-			return "", err
-		}
-		if err := json.Unmarshal(specMarshal, &hrekordObj); err != nil {
-			return "", err
-		}
-		return hrekordObj.Signature.PublicKey.Content.String(), nil
-	}
-
-	// Try Intoto
-	if err := json.Unmarshal(bodyDecoded, &intotod); err != nil {
-		return "", err
-	}
-	specMarshal, err := json.Marshal(intotod.Spec)
+	c, err := bundleContents(bundleBody)
 	if err != nil {
 		return "", err
 	}
-	if err := json.Unmarshal(specMarshal, &intotodObj); err != nil {
-		return "", err
-	}
-	return intotodObj.PublicKey.String(), nil
+	return c.PublicKey, nil
 }
 
 func VerifySET(bundlePayload cbundle.RekorPayload, signature []byte, pub *ecdsa.PublicKey) error {
-	contents, err := json.Marshal(bundlePayload)
-	if err != nil {
-		return fmt.Errorf("marshaling: %w", err)
-	}
-	canonicalized, err := jsoncanonicalizer.Transform(contents)
-	if err != nil {
-		return fmt.Errorf("canonicalizing: %w", err)
+	err := verify.VerifySET(bundlePayload, signature, pub)
+	if errors.Is(err, verify.ErrInvalidSET) {
+		return &VerificationError{ErrInvalidSETType, ErrInvalidSETMessage}
 	}
-
-	// verify the SET against the public key
-	hash := sha256.Sum256(canonicalized)
-	if !ecdsa.VerifyASN1(pub, hash[:], signature) {
-		// TODO: add error type instead of blank string
-		return &VerificationError{"", "unable to verify SET"}
-	}
-	return nil
+	return err
 }
 
 func TrustedCert(cert *x509.Certificate, roots *x509.CertPool, intermediates *x509.CertPool) ([][]*x509.Certificate, error) {
-	chains, err := cert.Verify(x509.VerifyOptions{
-		// THIS IS IMPORTANT: WE DO NOT CHECK TIMES HERE
-		// THE CERTIFICATE IS TREATED AS TRUSTED FOREVER
-		// WE CHECK THAT THE SIGNATURES WERE CREATED DURING THIS WINDOW
-		CurrentTime:   cert.NotBefore,
-		Roots:         roots,
-		Intermediates: intermediates,
-		KeyUsages: []x509.ExtKeyUsage{
-			x509.ExtKeyUsageCodeSigning,
-		},
-	})
+	chains, err := verify.TrustedCert(cert, roots, intermediates)
 	if err != nil {
-		return nil, fmt.Errorf("cert verification failed: %w. Check your TUF root (see cosign initialize) or set a custom root with env var SIGSTORE_ROOT_FILE", err)
+		return nil, fmt.Errorf("%w. Check your TUF root (see cosign initialize) or set a custom root with env var SIGSTORE_ROOT_FILE", err)
 	}
 	return chains, nil
 }
@@ -1327,6 +1388,40 @@ func correctAnnotations(wanted, have map[string]interface{}) bool {
 	return true
 }
 
+// verifyImageAttestationsExperimentalOCI verifies the attestations of the
+// latest attestation referrer of the image, using OCI 1.1+ behavior.
+func verifyImageAttestationsExperimentalOCI(ctx context.Context, signedImgRef name.Reference, co *CheckOpts) (checkedAttestations []oci.Signature, bundleVerified bool, err error) {
+	digest, err := ociremote.ResolveDigest(signedImgRef, co.RegistryClientOpts...)
+	if err != nil {
+		return nil, false, err
+	}
+	h, err := oci.ParseDigest(digest.Identifier())
+	if err != nil {
+		return nil, false, err
+	}
+
+	artifactType := ociexperimental.ArtifactType("att")
+	index, err := ociremote.ReferrersWithContext(ctx, digest, artifactType, co.RegistryClientOpts...)
+	if err != nil {
+		return nil, false, err
+	}
+	results := index.Manifests
+	if len(results) == 0 {
+		return nil, false, fmt.Errorf("unable to locate reference with artifactType %s", artifactType)
+	}
+	// TODO: do this smarter using "created" annotations
+	st, err := oci.ParseReference(fmt.Sprintf("%s@%s", digest.Repository, results[len(results)-1].Digest.String()))
+	if err != nil {
+		return nil, false, err
+	}
+	atts, err := ociremote.Signatures(st, co.RegistryClientOpts...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return verifyImageAttestations(ctx, atts, h, co)
+}
+
 // verifyImageSignaturesExperimentalOCI does all the main cosign checks in a loop, returning the verified signatures.
 // If there were no valid signatures, we return an error, using OCI 1.1+ behavior.
 func verifyImageSignaturesExperimentalOCI(ctx context.Context, signedImgRef name.Reference, co *CheckOpts) (checkedSignatures []oci.Signature, bundleVerified bool, err error) {
@@ -1341,7 +1436,7 @@ func verifyImageSignaturesExperimentalOCI(ctx context.Context, signedImgRef name
 	if err != nil {
 		return nil, false, err
 	}
-	h, err := v1.NewHash(digest.Identifier())
+	h, err := oci.ParseDigest(digest.Identifier())
 	if err != nil {
 		return nil, false, err
 	}
@@ -1350,7 +1445,7 @@ func verifyImageSignaturesExperimentalOCI(ctx context.Context, signedImgRef name
 	sigRef := co.SignatureRef
 	if sigRef == "" {
 		artifactType := ociexperimental.ArtifactType("sig")
-		index, err := ociremote.Referrers(digest, artifactType, co.RegistryClientOpts...)
+		index, err := ociremote.ReferrersWithContext(ctx, digest, artifactType, co.RegistryClientOpts...)
 		if err != nil {
 			return nil, false, err
 		}
@@ -1364,7 +1459,7 @@ func verifyImageSignaturesExperimentalOCI(ctx context.Context, signedImgRef name
 		}
 		// TODO: do this smarter using "created" annotations
 		lastResult := results[numResults-1]
-		st, err := name.ParseReference(fmt.Sprintf("%s@%s", digest.Repository, lastResult.Digest.String()))
+		st, err := oci.ParseReference(fmt.Sprintf("%s@%s", digest.Repository, lastResult.Digest.String()))
 		if err != nil {
 			return nil, false, err
 		}
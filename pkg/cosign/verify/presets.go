@@ -0,0 +1,156 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// preset is what a preset option requires of the other options.
+type preset struct {
+	name string
+	// keyless presets verify certificates issued to identities, keyed
+	// presets a key only.
+	keyless bool
+	// tlog and sct require transparency log entries and SCTs.
+	tlog, sct bool
+}
+
+// PublicSigstoreKeyless verifies signatures made with the public Sigstore
+// instance: certificates issued by Fulcio to one of identities, with an SCT,
+// and entries in the Rekor log, all trusted by root, e.g. the trusted root of
+// the Sigstore TUF repository. The identities may not be broad regular
+// expressions (see cosign.CheckIdentityStrict), and NewVerifier rejects the
+// options that would weaken the verification, such as WithoutTlog and
+// WithoutSCT.
+func PublicSigstoreKeyless(root *cosign.TrustedRoot, identities ...cosign.Identity) Option {
+	return keylessPreset(&preset{name: "PublicSigstoreKeyless", keyless: true, tlog: true, sct: true}, root, identities, func(c *config, root *cosign.TrustedRoot) error {
+		if !hasKeys(root.RekorPubKeys) || !hasKeys(root.CTLogPubKeys) {
+			return errors.New("PublicSigstoreKeyless requires a trusted root with transparency logs and certificate transparency logs")
+		}
+		return nil
+	})
+}
+
+// PrivateDeployment verifies signatures made with a private Fulcio and Rekor:
+// certificates issued by the certificate authorities of root to one of
+// identities, and entries in the transparency logs of root. The certificates
+// need an SCT if root has certificate transparency logs, and signatures with
+// RFC3161 timestamps are checked against the timestamp authorities of root.
+// As with PublicSigstoreKeyless, the identities must be narrow and the
+// verification can't be weakened with other options.
+func PrivateDeployment(root *cosign.TrustedRoot, identities ...cosign.Identity) Option {
+	p := &preset{name: "PrivateDeployment", keyless: true, tlog: true}
+	return keylessPreset(p, root, identities, func(c *config, root *cosign.TrustedRoot) error {
+		if !hasKeys(root.RekorPubKeys) {
+			return errors.New("PrivateDeployment requires a trusted root with a transparency log")
+		}
+		p.sct = hasKeys(root.CTLogPubKeys)
+		if !p.sct {
+			c.co.IgnoreSCT = true
+		}
+		return nil
+	})
+}
+
+// KeyPairOnly verifies signatures made with the private key of pub, as
+// cosign sign --key --tlog-upload=false makes them: with the key only,
+// without certificates or transparency log entries. NewVerifier rejects
+// options that would trust anything else, such as WithTrustedRoot and
+// WithIdentityPolicy.
+func KeyPairOnly(pub crypto.PublicKey) Option {
+	p := &preset{name: "KeyPairOnly"}
+	return func(c *config) error {
+		if err := setPreset(c, p); err != nil {
+			return err
+		}
+		if err := WithPublicKey(pub)(c); err != nil {
+			return err
+		}
+		c.co.IgnoreTlog = true
+		c.co.IgnoreSCT = true
+		return nil
+	}
+}
+
+// keylessPreset returns the option of the keyless preset p, which checks
+// root, and configures c for it, with checkRoot.
+func keylessPreset(p *preset, root *cosign.TrustedRoot, identities []cosign.Identity, checkRoot func(*config, *cosign.TrustedRoot) error) Option {
+	return func(c *config) error {
+		if err := setPreset(c, p); err != nil {
+			return err
+		}
+		if root == nil {
+			return errors.New("nil trusted root")
+		}
+		if root.FulcioRoots == nil {
+			return fmt.Errorf("%s requires a trusted root with certificate authorities", p.name)
+		}
+		if err := checkRoot(c, root); err != nil {
+			return err
+		}
+		if len(identities) == 0 {
+			return fmt.Errorf("%s requires identities", p.name)
+		}
+		for _, id := range identities {
+			if (id.Issuer == "" && id.IssuerRegExp == "") || (id.Subject == "" && id.SubjectRegExp == "") {
+				return fmt.Errorf("each identity of %s needs an issuer and a subject", p.name)
+			}
+			if err := cosign.CheckIdentityStrict(id); err != nil {
+				return fmt.Errorf("%s: %w", p.name, err)
+			}
+		}
+		c.root = root
+		c.co.Identities = append(c.co.Identities, identities...)
+		return nil
+	}
+}
+
+// setPreset records p as the preset of c, of which there may be only one.
+func setPreset(c *config, p *preset) error {
+	if c.preset != nil {
+		return fmt.Errorf("%s can't be combined with %s", p.name, c.preset.name)
+	}
+	c.preset = p
+	return nil
+}
+
+// check returns an error if the options of c weaken the verification of p,
+// or trust more than it does.
+func (p *preset) check(c *config) error {
+	if p.tlog && c.co.IgnoreTlog {
+		return fmt.Errorf("%s requires transparency log entries, it can't be used with WithoutTlog", p.name)
+	}
+	if p.sct && c.co.IgnoreSCT {
+		return fmt.Errorf("%s requires SCTs, it can't be used with WithoutSCT", p.name)
+	}
+	if p.keyless && c.co.SigVerifier != nil {
+		return fmt.Errorf("%s verifies certificates, it can't be used with WithPublicKey or WithSignatureVerifier", p.name)
+	}
+	if !p.keyless && (c.root != nil || len(c.co.Identities) > 0 || c.co.RekorClient != nil) {
+		return fmt.Errorf("%s verifies with the key only, it can't be used with WithTrustedRoot, WithIdentityPolicy or WithRekorClient", p.name)
+	}
+	return nil
+}
+
+// hasKeys returns whether keys has any key.
+func hasKeys(keys *cosign.TrustedTransparencyLogPubKeys) bool {
+	return keys != nil && len(keys.Keys) > 0
+}
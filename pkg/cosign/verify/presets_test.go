@@ -0,0 +1,101 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+func TestKeyPairOnly(t *testing.T) {
+	priv, err := cosign.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := signedImage(t, priv, nil)
+
+	v, err := NewVerifier(KeyPairOnly(priv.Public()))
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	if sigs, err := v.VerifyImageSignatures(context.Background(), ref); err != nil || len(sigs) != 1 {
+		t.Errorf("VerifyImageSignatures() = %d signatures, %v, want 1", len(sigs), err)
+	}
+}
+
+func TestPrivateDeployment(t *testing.T) {
+	logs := &cosign.TrustedTransparencyLogPubKeys{Keys: map[string]cosign.TransparencyLogPubKey{"log": {}}}
+	id := cosign.Identity{Issuer: "https://oidc.example.com", Subject: "ci@example.com"}
+	root := &cosign.TrustedRoot{FulcioRoots: x509.NewCertPool(), RekorPubKeys: logs}
+
+	// Without certificate transparency logs, certificates need no SCT.
+	v, err := NewVerifier(PrivateDeployment(root, id))
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	if co := v.images; co.IgnoreTlog || !co.IgnoreSCT || co.RekorPubKeys != logs || len(co.Identities) != 1 {
+		t.Errorf("NewVerifier() checks %+v, want tlog entries of the root and no SCTs", co)
+	}
+
+	root.CTLogPubKeys = logs
+	v, err = NewVerifier(PrivateDeployment(root, id))
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	if co := v.images; co.IgnoreSCT || co.CTLogPubKeys != logs {
+		t.Errorf("NewVerifier() checks %+v, want SCTs of the certificate transparency logs of the root", co)
+	}
+}
+
+func TestPresetErrors(t *testing.T) {
+	priv, err := cosign.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs := &cosign.TrustedTransparencyLogPubKeys{Keys: map[string]cosign.TransparencyLogPubKey{"log": {}}}
+	public := &cosign.TrustedRoot{FulcioRoots: x509.NewCertPool(), RekorPubKeys: logs, CTLogPubKeys: logs}
+	noCTLogs := &cosign.TrustedRoot{FulcioRoots: x509.NewCertPool(), RekorPubKeys: logs}
+	id := cosign.Identity{Issuer: "https://oidc.example.com", Subject: "ci@example.com"}
+	for name, tt := range map[string]struct {
+		opts []Option
+		want string
+	}{
+		"without tlog":         {opts: []Option{PublicSigstoreKeyless(public, id), WithoutTlog()}, want: "can't be used with WithoutTlog"},
+		"without SCT":          {opts: []Option{WithoutSCT(), PublicSigstoreKeyless(public, id)}, want: "can't be used with WithoutSCT"},
+		"no CT logs":           {opts: []Option{PublicSigstoreKeyless(noCTLogs, id)}, want: "certificate transparency logs"},
+		"no tlog":              {opts: []Option{PrivateDeployment(&cosign.TrustedRoot{FulcioRoots: x509.NewCertPool()}, id)}, want: "transparency log"},
+		"no CAs":               {opts: []Option{PrivateDeployment(&cosign.TrustedRoot{RekorPubKeys: logs}, id)}, want: "certificate authorities"},
+		"nil trusted root":     {opts: []Option{PrivateDeployment(nil, id)}, want: "nil trusted root"},
+		"no identities":        {opts: []Option{PublicSigstoreKeyless(public)}, want: "requires identities"},
+		"no issuer":            {opts: []Option{PublicSigstoreKeyless(public, cosign.Identity{Subject: "ci@example.com"})}, want: "needs an issuer and a subject"},
+		"broad identity":       {opts: []Option{PublicSigstoreKeyless(public, cosign.Identity{Issuer: id.Issuer, SubjectRegExp: ".*"})}, want: "regular expression"},
+		"with a key":           {opts: []Option{PublicSigstoreKeyless(public, id), WithPublicKey(priv.Public())}, want: "can't be used with WithPublicKey"},
+		"key with a root":      {opts: []Option{KeyPairOnly(priv.Public()), WithTrustedRoot(public)}, want: "can't be used with WithTrustedRoot"},
+		"key with identities":  {opts: []Option{WithIdentityPolicy(id), KeyPairOnly(priv.Public())}, want: "WithIdentityPolicy"},
+		"two presets":          {opts: []Option{KeyPairOnly(priv.Public()), PrivateDeployment(public, id)}, want: "PrivateDeployment can't be combined with KeyPairOnly"},
+		"key pair invalid key": {opts: []Option{KeyPairOnly("not a key")}, want: "loading public key"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewVerifier(tt.opts...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewVerifier() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package verify builds verifiers of cosign signatures and attestations for
+// programs that embed cosign, configured with options rather than the flags
+// of the cosign CLI:
+//
+//	v, err := verify.NewVerifier(
+//		verify.WithTrustedRoot(root),
+//		verify.WithIdentityPolicy(cosign.Identity{Issuer: issuer, Subject: subject}),
+//		verify.WithOfflineTlog(),
+//	)
+//	...
+//	sigs, err := v.VerifyImageSignatures(ctx, ref)
+//
+// The preset options PublicSigstoreKeyless, PrivateDeployment and
+// KeyPairOnly configure the checks that signatures of these deployments
+// need, and make NewVerifier reject the options that would weaken them:
+//
+//	v, err := verify.NewVerifier(verify.PublicSigstoreKeyless(root, identity))
+//
+// A Verifier is immutable, and may be used by any number of goroutines at
+// once: the trust material it is built with, such as the trusted root, is
+// shared by all of its verifications without being copied or modified, and
+// must not be modified afterwards.
+package verify
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// Verifier verifies the signatures and attestations of images and blobs
+// with a fixed policy.
+type Verifier struct {
+	// The options of each kind of verification, only read once built.
+	images, attestations, blobs cosign.CheckOpts
+}
+
+// config is what the options of NewVerifier set.
+type config struct {
+	co          cosign.CheckOpts
+	root        *cosign.TrustedRoot
+	offlineTlog bool
+	// preset is the preset option given, if any, whose requirements the
+	// other options must meet.
+	preset *preset
+}
+
+// Option configures a Verifier.
+type Option func(*config) error
+
+// WithTrustedRoot trusts the certificate authorities, transparency logs,
+// certificate transparency logs and timestamp authorities of root, e.g. as
+// loaded with cosign.LoadTrustedRoot. It is required to verify certificates
+// or transparency log entries.
+func WithTrustedRoot(root *cosign.TrustedRoot) Option {
+	return func(c *config) error {
+		if root == nil {
+			return errors.New("nil trusted root")
+		}
+		c.root = root
+		return nil
+	}
+}
+
+// WithPublicKey verifies signatures with pub instead of certificates.
+func WithPublicKey(pub crypto.PublicKey) Option {
+	return func(c *config) error {
+		v, err := signature.LoadVerifier(pub, crypto.SHA256)
+		if err != nil {
+			return fmt.Errorf("loading public key: %w", err)
+		}
+		c.co.SigVerifier = v
+		return nil
+	}
+}
+
+// WithSignatureVerifier verifies signatures with v instead of certificates,
+// e.g. for a key in a KMS.
+func WithSignatureVerifier(v signature.Verifier) Option {
+	return func(c *config) error {
+		c.co.SigVerifier = v
+		return nil
+	}
+}
+
+// WithIdentityPolicy accepts the certificates of any of identities. It is
+// required unless signatures are verified with a key.
+func WithIdentityPolicy(identities ...cosign.Identity) Option {
+	return func(c *config) error {
+		c.co.Identities = append(c.co.Identities, identities...)
+		return nil
+	}
+}
+
+// WithOfflineTlog verifies transparency log entries with the bundles stored
+// with the signatures only, without looking them up in the log. It is the
+// default without WithRekorClient.
+func WithOfflineTlog() Option {
+	return func(c *config) error {
+		c.offlineTlog = true
+		return nil
+	}
+}
+
+// WithRekorClient looks up the transparency log entries of signatures
+// without a bundle with rc.
+func WithRekorClient(rc *client.Rekor) Option {
+	return func(c *config) error {
+		c.co.RekorClient = rc
+		return nil
+	}
+}
+
+// WithoutTlog accepts signatures that aren't in a transparency log.
+func WithoutTlog() Option {
+	return func(c *config) error {
+		c.co.IgnoreTlog = true
+		return nil
+	}
+}
+
+// WithoutSCT accepts certificates without a proof of inclusion in a
+// certificate transparency log.
+func WithoutSCT() Option {
+	return func(c *config) error {
+		c.co.IgnoreSCT = true
+		return nil
+	}
+}
+
+// WithAnnotations requires the signed payloads of image signatures to have
+// annotations.
+func WithAnnotations(annotations map[string]interface{}) Option {
+	return func(c *config) error {
+		c.co.Annotations = annotations
+		return nil
+	}
+}
+
+// WithDenylist rejects the signatures revoked by d.
+func WithDenylist(d *cosign.Denylist) Option {
+	return func(c *config) error {
+		c.co.Denylist = d
+		return nil
+	}
+}
+
+// WithEnforceExpiry rejects signatures whose cosign.ExpiresAnnotation is in
+// the past.
+func WithEnforceExpiry() Option {
+	return func(c *config) error {
+		c.co.EnforceExpiry = true
+		return nil
+	}
+}
+
+// WithRegistryOptions sets the options used to read images and signatures
+// from registries, e.g. their credentials.
+func WithRegistryOptions(opts ...ociremote.Option) Option {
+	return func(c *config) error {
+		c.co.RegistryClientOpts = append(c.co.RegistryClientOpts, opts...)
+		return nil
+	}
+}
+
+// NewVerifier returns a Verifier configured by opts. Signatures are verified
+// with a key, or with certificates issued by the certificate authorities of
+// the trusted root to one of the identities of the policy, and must be in the
+// transparency log unless WithoutTlog is given.
+func NewVerifier(opts ...Option) (*Verifier, error) {
+	c := &config{}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.preset != nil {
+		if err := c.preset.check(c); err != nil {
+			return nil, err
+		}
+	}
+	co := c.co
+
+	keyless := co.SigVerifier == nil
+	if keyless {
+		if c.root == nil {
+			return nil, errors.New("one of WithPublicKey, WithSignatureVerifier or WithTrustedRoot is required")
+		}
+		if len(co.Identities) == 0 {
+			return nil, errors.New("verifying certificates requires WithIdentityPolicy")
+		}
+		co.RootCerts = c.root.FulcioRoots
+		co.IntermediateCerts = c.root.FulcioIntermediates
+		if !co.IgnoreSCT {
+			co.CTLogPubKeys = c.root.CTLogPubKeys
+		}
+	}
+	if !co.IgnoreTlog {
+		if c.root == nil {
+			return nil, errors.New("verifying transparency log entries requires WithTrustedRoot, or use WithoutTlog")
+		}
+		co.RekorPubKeys = c.root.RekorPubKeys
+		co.Offline = c.offlineTlog || co.RekorClient == nil
+	}
+	if c.root != nil && len(c.root.TSARootCertificates) > 0 {
+		if len(c.root.TSACertificateChains) > 1 {
+			co.TSACertificateChains = c.root.TSACertificateChains
+		} else {
+			co.TSACertificate = c.root.TSACertificate
+			co.TSAIntermediateCertificates = c.root.TSAIntermediateCertificates
+			co.TSARootCertificates = c.root.TSARootCertificates
+		}
+	}
+	v := &Verifier{images: co, attestations: co, blobs: co}
+	v.images.ClaimVerifier = cosign.SimpleClaimVerifier
+	v.attestations.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
+	return v, nil
+}
+
+// VerifyImageSignatures returns the signatures of the image ref that verify,
+// whose payloads must name the digest of ref.
+func (v *Verifier) VerifyImageSignatures(ctx context.Context, ref name.Reference) ([]oci.Signature, error) {
+	sigs, _, err := cosign.VerifyImageSignatures(ctx, ref, &v.images)
+	return sigs, err
+}
+
+// VerifyImageAttestations returns the attestations of the image ref that
+// verify, whose in-toto statements must have the digest of ref as subject.
+func (v *Verifier) VerifyImageAttestations(ctx context.Context, ref name.Reference) ([]oci.Signature, error) {
+	atts, _, err := cosign.VerifyImageAttestations(ctx, ref, &v.attestations)
+	return atts, err
+}
+
+// VerifyBlobSignature verifies sig, whose payload is the signed blob.
+func (v *Verifier) VerifyBlobSignature(ctx context.Context, sig oci.Signature) error {
+	_, err := cosign.VerifyBlobSignature(ctx, sig, &v.blobs)
+	return err
+}
@@ -0,0 +1,268 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+// signedImage pushes an image to a test registry with a signature made by
+// priv, with the certificate cert if set.
+func signedImage(t *testing.T, priv *ecdsa.PrivateKey, cert *x509.Certificate) name.Digest {
+	t.Helper()
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.NewDigest(strings.TrimPrefix(s.URL, "http://") + "/app@" + h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := payload.Cosign{Image: ref}.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := sv.SignMessage(bytes.NewReader(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var opts []static.Option
+	if cert != nil {
+		certPEM, err := cryptoutils.MarshalCertificateToPEM(cert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts = append(opts, static.WithCertChain(certPEM, nil))
+	}
+	sig, err := static.NewSignature(p, base64.StdEncoding.EncodeToString(raw), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := mutate.AppendSignatures(empty.Signatures(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := ociremote.SignatureTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(tag, sigs); err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+func TestVerifierPublicKey(t *testing.T) {
+	ctx := context.Background()
+	priv, err := cosign.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := signedImage(t, priv, nil)
+
+	v, err := NewVerifier(WithPublicKey(priv.Public()), WithoutTlog())
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	if sigs, err := v.VerifyImageSignatures(ctx, ref); err != nil || len(sigs) != 1 {
+		t.Errorf("VerifyImageSignatures() = %d signatures, %v, want 1", len(sigs), err)
+	}
+
+	other, err := cosign.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = NewVerifier(WithPublicKey(other.Public()), WithoutTlog())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.VerifyImageSignatures(ctx, ref); err == nil {
+		t.Error("VerifyImageSignatures() with another key: expected an error")
+	}
+	v, err = NewVerifier(WithPublicKey(priv.Public()), WithoutTlog(), WithAnnotations(map[string]interface{}{"env": "prod"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.VerifyImageSignatures(ctx, ref); err == nil {
+		t.Error("VerifyImageSignatures() with missing annotations: expected an error")
+	}
+}
+
+func TestVerifierIdentityPolicy(t *testing.T) {
+	ctx := context.Background()
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	subCert, subKey, _ := test.GenerateSubordinateCa(rootCert, rootKey)
+	leafCert, leafKey, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", subCert, subKey)
+	ref := signedImage(t, leafKey, leafCert)
+
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	roots.AddCert(rootCert)
+	intermediates.AddCert(subCert)
+	root := &cosign.TrustedRoot{FulcioRoots: roots, FulcioIntermediates: intermediates}
+
+	v, err := NewVerifier(WithTrustedRoot(root), WithIdentityPolicy(cosign.Identity{Issuer: "oidc-issuer", Subject: "subject@mail.com"}),
+		WithoutTlog(), WithoutSCT())
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	// The verifier is shared by concurrent verifications.
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var sigs []oci.Signature
+			sigs, errs[i] = v.VerifyImageSignatures(ctx, ref)
+			if errs[i] == nil && len(sigs) != 1 {
+				t.Errorf("VerifyImageSignatures() = %d signatures, want 1", len(sigs))
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Errorf("VerifyImageSignatures() = %v", err)
+		}
+	}
+
+	v, err = NewVerifier(WithTrustedRoot(root), WithIdentityPolicy(cosign.Identity{Issuer: "oidc-issuer", Subject: "other@mail.com"}),
+		WithoutTlog(), WithoutSCT())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.VerifyImageSignatures(ctx, ref); err == nil {
+		t.Error("VerifyImageSignatures() of another identity: expected an error")
+	}
+}
+
+func TestNewVerifierErrors(t *testing.T) {
+	priv, err := cosign.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := &cosign.TrustedRoot{}
+	for name, tt := range map[string]struct {
+		opts []Option
+		want string
+	}{
+		"nothing to verify with": {want: "is required"},
+		"no identity policy":     {opts: []Option{WithTrustedRoot(root), WithoutTlog()}, want: "WithIdentityPolicy"},
+		"no tlog keys":           {opts: []Option{WithPublicKey(priv.Public())}, want: "WithoutTlog"},
+		"nil trusted root":       {opts: []Option{WithTrustedRoot(nil)}, want: "nil trusted root"},
+		"invalid key":            {opts: []Option{WithPublicKey("not a key")}, want: "loading public key"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewVerifier(tt.opts...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewVerifier() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkVerifierParallel measures the verifications a single Verifier
+// makes at once from GOMAXPROCS goroutines, as in an admission controller.
+func BenchmarkVerifierParallel(b *testing.B) {
+	ctx := context.Background()
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	subCert, subKey, _ := test.GenerateSubordinateCa(rootCert, rootKey)
+	leafCert, leafKey, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", subCert, subKey)
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	roots.AddCert(rootCert)
+	intermediates.AddCert(subCert)
+	root := &cosign.TrustedRoot{FulcioRoots: roots, FulcioIntermediates: intermediates}
+
+	blob := []byte("release artifact")
+	sv, err := signature.LoadECDSASignerVerifier(leafKey, crypto.SHA256)
+	if err != nil {
+		b.Fatal(err)
+	}
+	raw, err := sv.SignMessage(bytes.NewReader(blob))
+	if err != nil {
+		b.Fatal(err)
+	}
+	certPEM, err := cryptoutils.MarshalCertificateToPEM(leafCert)
+	if err != nil {
+		b.Fatal(err)
+	}
+	sig, err := static.NewSignature(blob, base64.StdEncoding.EncodeToString(raw), static.WithCertChain(certPEM, nil))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for name, opts := range map[string][]Option{
+		"public key": {WithPublicKey(leafKey.Public()), WithoutTlog()},
+		"identity policy": {WithTrustedRoot(root), WithIdentityPolicy(cosign.Identity{Issuer: "oidc-issuer", Subject: "subject@mail.com"}),
+			WithoutTlog(), WithoutSCT()},
+	} {
+		b.Run(name, func(b *testing.B) {
+			v, err := NewVerifier(opts...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := v.VerifyBlobSignature(ctx, sig); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,101 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// VerifyResult is the verification of one of the images of
+// VerifyImagesParallel.
+type VerifyResult struct {
+	// Index is the index of Ref in the references that were verified.
+	Index          int
+	Ref            name.Reference
+	Signatures     []oci.Signature
+	BundleVerified bool
+	Err            error
+	// Duration is how long verifying the image took.
+	Duration time.Duration
+}
+
+// BatchOpts are the options of VerifyImagesParallel.
+type BatchOpts struct {
+	// Workers is the number of images verified at once, GOMAXPROCS if it is
+	// not positive, but at most the workers of the budget config file.
+	Workers int
+	// Verify verifies each image, VerifyImageSignatures if it is nil.
+	Verify func(context.Context, name.Reference, *CheckOpts) ([]oci.Signature, bool, error)
+}
+
+// VerifyImagesParallel verifies the signatures of refs with co, up to
+// bo.Workers images at a time, and sends the result of each to the returned
+// channel as it completes, so not in the order of refs. The channel is closed
+// once every image has a result; it must be read until then.
+//
+// Each image is verified with a shallow copy of co, so that the Rekor client,
+// the Fulcio roots and the other clients and keys of co are shared by all of
+// them. Once ctx is done, the images left are not verified, and their result
+// is the error of ctx.
+func VerifyImagesParallel(ctx context.Context, refs []name.Reference, co *CheckOpts, bo BatchOpts) <-chan VerifyResult {
+	workers := budget.Workers(bo.Workers, runtime.GOMAXPROCS(0))
+	if workers > len(refs) {
+		workers = len(refs)
+	}
+	verify := bo.Verify
+	if verify == nil {
+		verify = VerifyImageSignatures
+	}
+
+	results := make(chan VerifyResult)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				r := VerifyResult{Index: i, Ref: refs[i]}
+				if r.Err = ctx.Err(); r.Err == nil {
+					// The verify function of BatchOpts may set options for
+					// the image.
+					imageCo := *co
+					start := time.Now()
+					r.Signatures, r.BundleVerified, r.Err = verify(ctx, refs[i], &imageCo)
+					r.Duration = time.Since(start)
+				}
+				results <- r
+			}
+		}()
+	}
+	go func() {
+		for i := range refs {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
@@ -0,0 +1,112 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+func batchRefs(t *testing.T, n int) []name.Reference {
+	refs := make([]name.Reference, 0, n)
+	for i := 0; i < n; i++ {
+		ref, err := name.ParseReference(fmt.Sprintf("registry.example.com/app:%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func TestVerifyImagesParallel(t *testing.T) {
+	refs := batchRefs(t, 20)
+	co := &CheckOpts{}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	verify := func(_ context.Context, ref name.Reference, imageCo *CheckOpts) ([]oci.Signature, bool, error) {
+		if imageCo == co {
+			t.Error("the CheckOpts of the batch were passed to verify instead of a copy")
+		}
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if ref == refs[3] {
+			return nil, false, ErrNoSignaturesFound
+		}
+		return nil, true, nil
+	}
+
+	seen := map[int]bool{}
+	for r := range VerifyImagesParallel(context.Background(), refs, co, BatchOpts{Workers: 4, Verify: verify}) {
+		if seen[r.Index] {
+			t.Errorf("image %d has more than one result", r.Index)
+		}
+		seen[r.Index] = true
+		if r.Ref != refs[r.Index] {
+			t.Errorf("result %d is for %s, want %s", r.Index, r.Ref, refs[r.Index])
+		}
+		if wantErr := r.Index == 3; (r.Err != nil) != wantErr || r.BundleVerified == wantErr {
+			t.Errorf("result %d = %v, %v, want error %v", r.Index, r.BundleVerified, r.Err, wantErr)
+		}
+	}
+	if len(seen) != len(refs) {
+		t.Errorf("got %d results, want %d", len(seen), len(refs))
+	}
+	if maxRunning > 4 {
+		t.Errorf("verified %d images at once, want at most 4", maxRunning)
+	}
+}
+
+func TestVerifyImagesParallelCanceled(t *testing.T) {
+	refs := batchRefs(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	verify := func(context.Context, name.Reference, *CheckOpts) ([]oci.Signature, bool, error) {
+		cancel()
+		return nil, false, nil
+	}
+
+	verified, canceled := 0, 0
+	for r := range VerifyImagesParallel(ctx, refs, &CheckOpts{}, BatchOpts{Workers: 1, Verify: verify}) {
+		switch {
+		case r.Err == nil:
+			verified++
+		case errors.Is(r.Err, context.Canceled):
+			canceled++
+		default:
+			t.Errorf("result %d = %v", r.Index, r.Err)
+		}
+	}
+	if verified != 1 || canceled != len(refs)-1 {
+		t.Errorf("got %d verified and %d canceled images, want 1 and %d", verified, canceled, len(refs)-1)
+	}
+}
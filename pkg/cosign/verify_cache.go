@@ -0,0 +1,464 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// DefaultVerificationCacheTTL is how long cached verifications are used by
+// default.
+const DefaultVerificationCacheTTL = time.Hour
+
+// VerificationCache stores the signatures that verified image digests, so
+// that verifying a digest again with the same policy needs no registry or
+// Rekor calls. Entries older than the TTL of the cache are not returned.
+type VerificationCache interface {
+	// Get returns the cached verification of key, or nil if there is none.
+	Get(key VerificationCacheKey) (*CachedVerification, error)
+	// Put caches the verification of key.
+	Put(key VerificationCacheKey, v *CachedVerification) error
+	// Invalidate removes the cached verifications of digest with every
+	// policy, e.g. once one of its signatures is revoked.
+	Invalidate(digest v1.Hash) error
+}
+
+// VerificationCacheKey identifies the verification of an image digest with a
+// policy.
+type VerificationCacheKey struct {
+	Digest v1.Hash
+	// Policy is the hex SHA-256 of the verification policy, see
+	// NewVerificationCacheKey.
+	Policy string
+}
+
+// CachedVerification is the result of a verification in a VerificationCache.
+type CachedVerification struct {
+	VerifiedAt     time.Time          `json:"verifiedAt"`
+	Signatures     []OfflineSignature `json:"signatures"`
+	BundleVerified bool               `json:"bundleVerified"`
+	// Warnings are the VerificationWarnings raised for the signatures, by
+	// their base64 signature, which are raised again when the verification
+	// is read from the cache.
+	Warnings map[string][]VerificationWarning `json:"warnings,omitempty"`
+}
+
+// verificationPolicy is what is hashed into the Policy of a
+// VerificationCacheKey: the parts of CheckOpts that decide whether a
+// signature verifies, or the warnings it raises, with the keys and
+// certificates that are trusted. Every field of CheckOpts must be here or
+// in verificationPolicyExcluded, which TestVerificationPolicyFields checks.
+type verificationPolicy struct {
+	Scope                  string                 `json:"scope,omitempty"`
+	Annotations            map[string]interface{} `json:"annotations,omitempty"`
+	CheckClaims            bool                   `json:"checkClaims"`
+	PublicKey              []byte                 `json:"publicKey,omitempty"`
+	TrustAnchorKeys        [][]byte               `json:"trustAnchorKeys,omitempty"`
+	Roots                  [][]byte               `json:"roots,omitempty"`
+	Intermediates          [][]byte               `json:"intermediates,omitempty"`
+	GithubWorkflow         [5]string              `json:"githubWorkflow"`
+	CertClaims             []string               `json:"certClaims,omitempty"`
+	Identities             []Identity             `json:"identities,omitempty"`
+	IgnoreSCT              bool                   `json:"ignoreSCT"`
+	SCT                    []byte                 `json:"sct,omitempty"`
+	CTLogIDs               []string               `json:"ctLogIDs,omitempty"`
+	RequireCTInclusion     bool                   `json:"requireCTInclusion,omitempty"`
+	IgnoreTlog             bool                   `json:"ignoreTlog"`
+	RekorLogIDs            []string               `json:"rekorLogIDs,omitempty"`
+	Witnesses              []string               `json:"witnesses,omitempty"`
+	MinWitnesses           int                    `json:"minWitnesses,omitempty"`
+	SignatureRef           string                 `json:"signatureRef,omitempty"`
+	PayloadRef             string                 `json:"payloadRef,omitempty"`
+	TSACertificates        [][]byte               `json:"tsaCertificates,omitempty"`
+	Denylist               *Denylist              `json:"denylist,omitempty"`
+	KeyUsage               *KeyUsagePolicy        `json:"keyUsage,omitempty"`
+	EnforceExpiry          bool                   `json:"enforceExpiry"`
+	CertClockSkew          time.Duration          `json:"certClockSkew"`
+	IgnoreKeyUsage         bool                   `json:"ignoreKeyUsage"`
+	AllowAnyEKU            bool                   `json:"allowAnyEKU"`
+	RequireNameConstraints bool                   `json:"requireNameConstraints"`
+	IssuerSPKIHashes       []string               `json:"issuerSPKIHashes,omitempty"`
+	MaxChainDepth          int                    `json:"maxChainDepth,omitempty"`
+	PayloadTypes           []string               `json:"payloadTypes,omitempty"`
+	StopAfterVerified      int                    `json:"stopAfterVerified,omitempty"`
+	RequireEncrypted       bool                   `json:"requireEncrypted,omitempty"`
+	EncryptionRecipients   []string               `json:"encryptionRecipients,omitempty"`
+	Offline                bool                   `json:"offline,omitempty"`
+	RekorBundleDir         RekorBundleDir         `json:"rekorBundleDir,omitempty"`
+	CTInclusionProofs      []*CTInclusionProof    `json:"ctInclusionProofs,omitempty"`
+	CertExpiryWarning      time.Duration          `json:"certExpiryWarning,omitempty"`
+}
+
+// verificationPolicyExcluded are the fields of CheckOpts that aren't in
+// verificationPolicy, because they only change how or where from what is
+// verified is fetched, not the result.
+var verificationPolicyExcluded = map[string]bool{
+	"RegistryClientOpts": true,
+	"RekorClient":        true,
+	"PKOpts":             true,
+	"CTLogClients":       true,
+	"RekorLog":           true,
+	// The warnings are cached with the verification and raised again.
+	"WarningHandler":   true,
+	"SignatureWorkers": true,
+}
+
+// NewVerificationCacheKey returns the key of the verification of digest with
+// co. scope describes what else the caller checks, e.g. the repository the
+// signatures are read from or that the platforms of an index are signed too,
+// so that verifications that differ in it don't share cached results.
+func NewVerificationCacheKey(digest v1.Hash, co *CheckOpts, scope string) (VerificationCacheKey, error) {
+	p := verificationPolicy{
+		Scope:       scope,
+		Annotations: co.Annotations,
+		CheckClaims: co.ClaimVerifier != nil,
+		GithubWorkflow: [5]string{co.CertGithubWorkflowTrigger, co.CertGithubWorkflowSha, co.CertGithubWorkflowName,
+			co.CertGithubWorkflowRepository, co.CertGithubWorkflowRef},
+		Identities:             co.Identities,
+		IgnoreSCT:              co.IgnoreSCT,
+		SCT:                    co.SCT,
+		RequireCTInclusion:     co.RequireCTInclusion,
+		IgnoreTlog:             co.IgnoreTlog,
+		SignatureRef:           co.SignatureRef,
+		PayloadRef:             co.PayloadRef,
+		Denylist:               co.Denylist,
+		KeyUsage:               co.KeyUsage,
+		EnforceExpiry:          co.EnforceExpiry,
+		CertClockSkew:          co.CertClockSkew,
+		IgnoreKeyUsage:         co.IgnoreKeyUsage,
+		AllowAnyEKU:            co.AllowAnyEKU,
+		RequireNameConstraints: co.RequireNameConstraints,
+		IssuerSPKIHashes:       co.IssuerSPKIHashes,
+		MaxChainDepth:          co.MaxChainDepth,
+		PayloadTypes:           co.PayloadTypes,
+		StopAfterVerified:      co.StopAfterVerified,
+		RequireEncrypted:       co.RequireEncrypted,
+		EncryptionRecipients:   co.EncryptionRecipients,
+		Offline:                co.Offline,
+		RekorBundleDir:         co.RekorBundleDir,
+		CTInclusionProofs:      co.CTInclusionProofs,
+		CertExpiryWarning:      co.CertExpiryWarningWindow,
+	}
+	for _, m := range co.CertClaims {
+		p.CertClaims = append(p.CertClaims, m.String())
+	}
+	if co.SigVerifier != nil {
+		pub, err := co.SigVerifier.PublicKey(co.PKOpts...)
+		if err != nil {
+			return VerificationCacheKey{}, fmt.Errorf("getting the public key: %w", err)
+		}
+		if p.PublicKey, err = cryptoutils.MarshalPublicKeyToDER(pub); err != nil {
+			return VerificationCacheKey{}, err
+		}
+	}
+	for _, k := range co.TrustAnchorKeys {
+		der, err := cryptoutils.MarshalPublicKeyToDER(k)
+		if err != nil {
+			return VerificationCacheKey{}, err
+		}
+		p.TrustAnchorKeys = append(p.TrustAnchorKeys, der)
+	}
+	var err error
+	if p.Roots, err = poolCertificates(co.RootCerts, co.RootCertificates); err != nil {
+		return VerificationCacheKey{}, err
+	}
+	if p.Intermediates, err = poolCertificates(co.IntermediateCerts, co.IntermediateCertificates); err != nil {
+		return VerificationCacheKey{}, err
+	}
+	p.CTLogIDs = logIDs(co.CTLogPubKeys)
+	p.RekorLogIDs = logIDs(co.RekorPubKeys)
+	if co.Witnesses != nil {
+		for _, w := range co.Witnesses.Witnesses {
+			p.Witnesses = append(p.Witnesses, w.Name+"+"+hex.EncodeToString(w.KeyID[:]))
+		}
+		p.MinWitnesses = co.Witnesses.Min
+	}
+	tsaCerts := [][]*x509.Certificate{{co.TSACertificate}, co.TSAIntermediateCertificates, co.TSARootCertificates}
+	for _, chain := range co.TSACertificateChains {
+		tsaCerts = append(tsaCerts, []*x509.Certificate{chain.Certificate}, chain.Intermediates, chain.Roots)
+	}
+	for _, certs := range tsaCerts {
+		for _, c := range certs {
+			if c != nil {
+				p.TSACertificates = append(p.TSACertificates, c.Raw)
+			}
+		}
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return VerificationCacheKey{}, fmt.Errorf("marshaling the verification policy: %w", err)
+	}
+	h := sha256.Sum256(b)
+	return VerificationCacheKey{Digest: digest, Policy: hex.EncodeToString(h[:])}, nil
+}
+
+// errUnknownPoolCertificates is returned by NewVerificationCacheKey when
+// the certificates of a certificate pool of the CheckOpts aren't set.
+var errUnknownPoolCertificates = errors.New("the certificates of the certificate pools are unknown")
+
+// poolCertificates returns the sorted DER encodings of certs, the
+// certificates of pool. Certificates are told apart by their DER encoding,
+// rather than by their subject, which a rotated root may keep.
+func poolCertificates(pool *x509.CertPool, certs []*x509.Certificate) ([][]byte, error) {
+	if pool == nil {
+		return nil, nil
+	}
+	certsPool := x509.NewCertPool()
+	for _, c := range certs {
+		certsPool.AddCert(c)
+	}
+	if !pool.Equal(certsPool) {
+		return nil, errUnknownPoolCertificates
+	}
+	der := make([][]byte, 0, len(certs))
+	for _, c := range certs {
+		der = append(der, c.Raw)
+	}
+	sort.Slice(der, func(i, j int) bool { return bytes.Compare(der[i], der[j]) < 0 })
+	return der, nil
+}
+
+func logIDs(keys *TrustedTransparencyLogPubKeys) []string {
+	if keys == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(keys.Keys))
+	for id := range keys.Keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// CacheVerification returns verify with its results cached in cache: an
+// image whose digest was verified in the same repository, with the same
+// CheckOpts and scope, is not verified again. The VerificationWarnings of a
+// cached verification are raised again, with the WarningHandler of co.
+func CacheVerification(cache VerificationCache, scope string, verify func(context.Context, name.Reference, *CheckOpts) ([]oci.Signature, bool, error)) func(context.Context, name.Reference, *CheckOpts) ([]oci.Signature, bool, error) {
+	return func(ctx context.Context, ref name.Reference, co *CheckOpts) ([]oci.Signature, bool, error) {
+		digest, ok := ref.(name.Digest)
+		if !ok {
+			var err error
+			if digest, err = ociremote.ResolveDigest(ref, co.RegistryClientOpts...); err != nil {
+				return nil, false, err
+			}
+		}
+		h, err := oci.ParseDigest(digest.DigestStr())
+		if err != nil {
+			return nil, false, err
+		}
+		key, err := NewVerificationCacheKey(h, co, digest.Context().Name()+"\n"+scope)
+		if errors.Is(err, errUnknownPoolCertificates) {
+			return verify(ctx, ref, co)
+		} else if err != nil {
+			return nil, false, err
+		}
+		if cached, err := cache.Get(key); err != nil {
+			return nil, false, fmt.Errorf("reading the verification cache: %w", err)
+		} else if cached != nil {
+			sigs := make([]oci.Signature, 0, len(cached.Signatures))
+			for _, s := range cached.Signatures {
+				sig, err := s.signature(ctx, &CheckOpts{IgnoreTlog: true})
+				if err != nil {
+					return nil, false, fmt.Errorf("reading the verification cache: %w", err)
+				}
+				sigs = append(sigs, sig)
+				if co.WarningHandler == nil {
+					continue
+				}
+				b64sig, err := sig.Base64Signature()
+				if err != nil {
+					return nil, false, err
+				}
+				for _, w := range cached.Warnings[b64sig] {
+					co.WarningHandler(sig, w)
+				}
+			}
+			return sigs, cached.BundleVerified, nil
+		}
+
+		// The warnings are collected whether or not co has a WarningHandler,
+		// for the verifications that read the cached result with one.
+		warnings := map[string][]VerificationWarning{}
+		var mu sync.Mutex
+		wco := *co
+		wco.WarningHandler = func(sig oci.Signature, w VerificationWarning) {
+			if b64sig, err := sig.Base64Signature(); err == nil {
+				mu.Lock()
+				warnings[b64sig] = append(warnings[b64sig], w)
+				mu.Unlock()
+			}
+			if co.WarningHandler != nil {
+				co.WarningHandler(sig, w)
+			}
+		}
+		// The digest is verified, rather than ref, so that the cached result
+		// is that of the digest even if the tag is moved meanwhile.
+		verified, bundleVerified, err := verify(ctx, digest, &wco)
+		if err != nil {
+			return nil, false, err
+		}
+		v := &CachedVerification{VerifiedAt: time.Now().UTC(), BundleVerified: bundleVerified}
+		for _, sig := range verified {
+			s, err := NewOfflineSignature(sig)
+			if err != nil {
+				return nil, false, err
+			}
+			v.Signatures = append(v.Signatures, s)
+			b64sig, err := sig.Base64Signature()
+			if err != nil {
+				return nil, false, err
+			}
+			if ws := warnings[b64sig]; len(ws) > 0 {
+				if v.Warnings == nil {
+					v.Warnings = map[string][]VerificationWarning{}
+				}
+				v.Warnings[b64sig] = ws
+			}
+		}
+		if err := cache.Put(key, v); err != nil {
+			return nil, false, fmt.Errorf("writing the verification cache: %w", err)
+		}
+		return verified, bundleVerified, nil
+	}
+}
+
+// memoryVerificationCache is a VerificationCache in memory.
+type memoryVerificationCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[v1.Hash]map[string]*CachedVerification
+}
+
+// NewMemoryVerificationCache returns a VerificationCache in memory, whose
+// entries are used for ttl.
+func NewMemoryVerificationCache(ttl time.Duration) VerificationCache {
+	return &memoryVerificationCache{ttl: ttl, entries: map[v1.Hash]map[string]*CachedVerification{}}
+}
+
+func (c *memoryVerificationCache) Get(key VerificationCacheKey) (*CachedVerification, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v := c.entries[key.Digest][key.Policy]
+	if v == nil || time.Since(v.VerifiedAt) > c.ttl {
+		return nil, nil
+	}
+	return v, nil
+}
+
+func (c *memoryVerificationCache) Put(key VerificationCacheKey, v *CachedVerification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key.Digest] == nil {
+		c.entries[key.Digest] = map[string]*CachedVerification{}
+	}
+	c.entries[key.Digest][key.Policy] = v
+	return nil
+}
+
+func (c *memoryVerificationCache) Invalidate(digest v1.Hash) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, digest)
+	return nil
+}
+
+// fileVerificationCache is a VerificationCache in a directory, with a file
+// for each policy in a directory for each digest:
+// <dir>/<algorithm>/<hex>/<policy>.json.
+type fileVerificationCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewFileVerificationCache returns a VerificationCache in dir, which is
+// created if needed, whose entries are used for ttl.
+func NewFileVerificationCache(dir string, ttl time.Duration) (VerificationCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating the verification cache %s: %w", dir, err)
+	}
+	return &fileVerificationCache{dir: dir, ttl: ttl}, nil
+}
+
+func (c *fileVerificationCache) digestDir(digest v1.Hash) string {
+	return filepath.Join(c.dir, digest.Algorithm, digest.Hex)
+}
+
+func (c *fileVerificationCache) Get(key VerificationCacheKey) (*CachedVerification, error) {
+	b, err := os.ReadFile(filepath.Join(c.digestDir(key.Digest), key.Policy+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v := &CachedVerification{}
+	// A corrupt entry is verified again, and replaced.
+	if err := json.Unmarshal(b, v); err != nil || time.Since(v.VerifiedAt) > c.ttl {
+		return nil, nil
+	}
+	return v, nil
+}
+
+func (c *fileVerificationCache) Put(key VerificationCacheKey, v *CachedVerification) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dir := c.digestDir(key.Digest)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	// Entries are renamed into place, so that concurrent verifications never
+	// read a partial entry.
+	f, err := os.CreateTemp(dir, key.Policy+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(dir, key.Policy+".json"))
+}
+
+func (c *fileVerificationCache) Invalidate(digest v1.Hash) error {
+	return os.RemoveAll(c.digestDir(digest))
+}
@@ -0,0 +1,296 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestNewVerificationCacheKey(t *testing.T) {
+	digest := v1.Hash{Algorithm: "sha256", Hex: "abcd"}
+	newVerifier := func() signature.Verifier {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := signature.LoadECDSAVerifier(&priv.PublicKey, crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	verifier := newVerifier()
+	key := func(co *CheckOpts, scope string) VerificationCacheKey {
+		t.Helper()
+		k, err := NewVerificationCacheKey(digest, co, scope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	base := key(&CheckOpts{SigVerifier: verifier}, "")
+	if base.Digest != digest || len(base.Policy) != 64 {
+		t.Fatalf("key = %+v, want the digest and a SHA-256 policy", base)
+	}
+	if k := key(&CheckOpts{SigVerifier: verifier}, ""); k != base {
+		t.Errorf("key of the same policy = %+v, want %+v", k, base)
+	}
+	for name, k := range map[string]VerificationCacheKey{
+		"another key":   key(&CheckOpts{SigVerifier: newVerifier()}, ""),
+		"another scope": key(&CheckOpts{SigVerifier: verifier}, "recursive"),
+		"ignore tlog":   key(&CheckOpts{SigVerifier: verifier, IgnoreTlog: true}, ""),
+		"identities":    key(&CheckOpts{Identities: []Identity{{Subject: "user@example.com"}}}, ""),
+		"denylist":      key(&CheckOpts{SigVerifier: verifier, Denylist: &Denylist{Digests: []string{"sha256:ef01"}}}, ""),
+		"offline":       key(&CheckOpts{SigVerifier: verifier, Offline: true}, ""),
+		"expiry window": key(&CheckOpts{SigVerifier: verifier, CertExpiryWarningWindow: time.Hour}, ""),
+	} {
+		if k.Policy == base.Policy {
+			t.Errorf("%s: key has the same policy as the base key", name)
+		}
+	}
+}
+
+func TestVerificationCacheKeyRoots(t *testing.T) {
+	digest := v1.Hash{Algorithm: "sha256", Hex: "abcd"}
+	// The roots have the same subject, but different keys, as a rotated
+	// root may.
+	root, _, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, _, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root.RawSubject, rotated.RawSubject) {
+		t.Fatal("the roots have different subjects")
+	}
+	key := func(roots, intermediates []*x509.Certificate) VerificationCacheKey {
+		t.Helper()
+		co := &CheckOpts{}
+		co.SetCertificates(roots, intermediates)
+		k, err := NewVerificationCacheKey(digest, co, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+	base := key([]*x509.Certificate{root}, nil)
+	if k := key([]*x509.Certificate{root}, nil); k != base {
+		t.Errorf("key of the same root = %+v, want %+v", k, base)
+	}
+	if k := key([]*x509.Certificate{rotated}, nil); k.Policy == base.Policy {
+		t.Error("key of a root with the same subject has the same policy")
+	}
+	if k := key([]*x509.Certificate{rotated}, []*x509.Certificate{root}); k.Policy == key([]*x509.Certificate{rotated}, []*x509.Certificate{rotated}).Policy {
+		t.Error("key of an intermediate with the same subject has the same policy")
+	}
+
+	// Without the certificates of the pools, the roots can't be told apart.
+	co := &CheckOpts{RootCerts: x509.NewCertPool()}
+	co.RootCerts.AddCert(root)
+	if _, err := NewVerificationCacheKey(digest, co, ""); !errors.Is(err, errUnknownPoolCertificates) {
+		t.Errorf("NewVerificationCacheKey() of a pool without its certificates = %v, want %v", err, errUnknownPoolCertificates)
+	}
+	co.RootCertificates = []*x509.Certificate{rotated}
+	if _, err := NewVerificationCacheKey(digest, co, ""); !errors.Is(err, errUnknownPoolCertificates) {
+		t.Errorf("NewVerificationCacheKey() of a pool with other certificates = %v, want %v", err, errUnknownPoolCertificates)
+	}
+}
+
+func TestVerificationCaches(t *testing.T) {
+	file, err := NewFileVerificationCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, cache := range map[string]VerificationCache{
+		"memory": NewMemoryVerificationCache(time.Hour),
+		"file":   file,
+	} {
+		t.Run(name, func(t *testing.T) {
+			key := VerificationCacheKey{Digest: v1.Hash{Algorithm: "sha256", Hex: "abcd"}, Policy: "policy"}
+			other := VerificationCacheKey{Digest: key.Digest, Policy: "other"}
+			if v, err := cache.Get(key); err != nil || v != nil {
+				t.Fatalf("Get() of an empty cache = %+v, %v", v, err)
+			}
+			fresh := &CachedVerification{VerifiedAt: time.Now(), Signatures: []OfflineSignature{{Payload: []byte("payload")}}}
+			if err := cache.Put(key, fresh); err != nil {
+				t.Fatal(err)
+			}
+			if v, err := cache.Get(key); err != nil || v == nil || string(v.Signatures[0].Payload) != "payload" {
+				t.Errorf("Get() = %+v, %v, want the cached verification", v, err)
+			}
+			if err := cache.Put(other, &CachedVerification{VerifiedAt: time.Now().Add(-2 * time.Hour)}); err != nil {
+				t.Fatal(err)
+			}
+			if v, err := cache.Get(other); err != nil || v != nil {
+				t.Errorf("Get() of an expired verification = %+v, %v, want none", v, err)
+			}
+			if err := cache.Invalidate(key.Digest); err != nil {
+				t.Fatal(err)
+			}
+			if v, err := cache.Get(key); err != nil || v != nil {
+				t.Errorf("Get() after Invalidate() = %+v, %v, want none", v, err)
+			}
+		})
+	}
+}
+
+func TestCacheVerification(t *testing.T) {
+	ctx := context.Background()
+	ref, err := name.NewDigest("registry.example.com/app@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature([]byte("payload"), "c2lnbmF0dXJl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	verify := CacheVerification(NewMemoryVerificationCache(time.Hour), "", func(_ context.Context, got name.Reference, _ *CheckOpts) ([]oci.Signature, bool, error) {
+		calls++
+		if d, ok := got.(name.Digest); !ok || d.DigestStr() != ref.DigestStr() {
+			t.Errorf("verified %s, want the digest %s", got, ref.DigestStr())
+		}
+		return []oci.Signature{sig}, true, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		verified, bundleVerified, err := verify(ctx, ref, &CheckOpts{IgnoreTlog: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(verified) != 1 || !bundleVerified {
+			t.Fatalf("verify() = %d signatures, %t, want the signature and a verified bundle", len(verified), bundleVerified)
+		}
+		if p, err := verified[0].Payload(); err != nil || string(p) != "payload" {
+			t.Errorf("payload = %q, %v", p, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("verified %d times, want the cached verification to be used", calls)
+	}
+	if _, _, err := verify(ctx, ref, &CheckOpts{}); err != nil || calls != 2 {
+		t.Errorf("verify() with another policy = %v after %d verifications, want a second verification", err, calls)
+	}
+	mirror, _ := name.NewDigest("mirror.example.com/app@" + ref.DigestStr())
+	if _, _, err := verify(ctx, mirror, &CheckOpts{}); err != nil || calls != 3 {
+		t.Errorf("verify() in another repository = %v after %d verifications, want a third verification", err, calls)
+	}
+
+	// Verifications with pools whose certificates are unknown aren't cached.
+	root, _, err := test.GenerateRootCa()
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	for i := 4; i < 6; i++ {
+		if _, _, err := verify(ctx, ref, &CheckOpts{RootCerts: roots}); err != nil || calls != i {
+			t.Errorf("verify() with unknown roots = %v after %d verifications, want %d", err, calls, i)
+		}
+	}
+}
+
+func TestCacheVerificationWarnings(t *testing.T) {
+	ctx := context.Background()
+	ref, err := name.NewDigest("registry.example.com/app@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature([]byte("payload"), "c2lnbmF0dXJl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify := CacheVerification(NewMemoryVerificationCache(time.Hour), "", func(_ context.Context, _ name.Reference, co *CheckOpts) ([]oci.Signature, bool, error) {
+		co.warn(sig, WarningDeprecatedAlgorithm, "weak")
+		return []oci.Signature{sig}, false, nil
+	})
+
+	// The warnings of the verification are raised again when it is read
+	// from the cache, so that --warnings-as-errors fails every time.
+	for i := 0; i < 2; i++ {
+		var got []VerificationWarning
+		co := &CheckOpts{WarningHandler: func(s oci.Signature, w VerificationWarning) {
+			if b64, _ := s.Base64Signature(); b64 != "c2lnbmF0dXJl" {
+				t.Errorf("warning raised for signature %s", b64)
+			}
+			got = append(got, w)
+		}}
+		if _, _, err := verify(ctx, ref, co); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Type != WarningDeprecatedAlgorithm {
+			t.Errorf("verification %d raised %v, want the cached warning", i, got)
+		}
+	}
+}
+
+func TestVerificationPolicyFields(t *testing.T) {
+	policy := map[string]bool{}
+	for i, pt := 0, reflect.TypeOf(verificationPolicy{}); i < pt.NumField(); i++ {
+		policy[pt.Field(i).Name] = true
+	}
+	// The fields of CheckOpts that are in the policy under another name, or
+	// together with others.
+	renamed := map[string]string{
+		"ClaimVerifier":                "CheckClaims",
+		"SigVerifier":                  "PublicKey",
+		"RootCerts":                    "Roots",
+		"IntermediateCerts":            "Intermediates",
+		"RootCertificates":             "Roots",
+		"IntermediateCertificates":     "Intermediates",
+		"CertGithubWorkflowTrigger":    "GithubWorkflow",
+		"CertGithubWorkflowSha":        "GithubWorkflow",
+		"CertGithubWorkflowName":       "GithubWorkflow",
+		"CertGithubWorkflowRepository": "GithubWorkflow",
+		"CertGithubWorkflowRef":        "GithubWorkflow",
+		"CTLogPubKeys":                 "CTLogIDs",
+		"RekorPubKeys":                 "RekorLogIDs",
+		"TSACertificate":               "TSACertificates",
+		"TSARootCertificates":          "TSACertificates",
+		"TSAIntermediateCertificates":  "TSACertificates",
+		"TSACertificateChains":         "TSACertificates",
+		"CertExpiryWarningWindow":      "CertExpiryWarning",
+	}
+	for i, ct := 0, reflect.TypeOf(CheckOpts{}); i < ct.NumField(); i++ {
+		f := ct.Field(i).Name
+		if name, ok := renamed[f]; ok {
+			f = name
+		}
+		if !policy[f] && !verificationPolicyExcluded[ct.Field(i).Name] {
+			t.Errorf("CheckOpts.%s is neither in the verification cache key nor excluded from it", ct.Field(i).Name)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/budget"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+// candidateVerificationFn verifies one of the signatures or attestations of
+// an image with co, and returns whether its transparency log entry verified.
+type candidateVerificationFn func(ctx context.Context, sig oci.Signature, co *CheckOpts) (bool, error)
+
+// verifyCandidates verifies copies of the signatures or attestations sl with
+// verify, co.SignatureWorkers of them at once, and returns those that
+// verified, in the order of sl, and the errors of the others. If stopAfter
+// is positive, it returns once that many have verified, without the errors
+// of those cut short.
+func verifyCandidates(ctx context.Context, sl []oci.Signature, co *CheckOpts, stopAfter int, verify candidateVerificationFn) (checked []oci.Signature, bundleVerified bool, errs []error) {
+	workers := budget.Workers(co.SignatureWorkers, 1)
+	if workers > len(sl) {
+		workers = len(sl)
+	}
+	if workers <= 1 {
+		errs = []error{}
+		for _, sig := range sl {
+			sig, err := static.Copy(sig)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			verified, err := verify(ctx, sig, co)
+			bundleVerified = bundleVerified || verified
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			// Phew, we made it.
+			checked = append(checked, sig)
+			if stopAfter > 0 && len(checked) >= stopAfter {
+				break
+			}
+		}
+		return checked, bundleVerified, errs
+	}
+
+	type result struct {
+		sig            oci.Signature
+		bundleVerified bool
+		err            error
+	}
+	results := make([]result, len(sl))
+	candidateCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	verifiedCount := 0
+	warningHandler := co.WarningHandler
+	if warningHandler != nil {
+		var warningMu sync.Mutex
+		warningHandler = func(sig oci.Signature, w VerificationWarning) {
+			warningMu.Lock()
+			defer warningMu.Unlock()
+			co.WarningHandler(sig, w)
+		}
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				r := &results[i]
+				sig, err := static.Copy(sl[i])
+				if err != nil {
+					r.err = err
+					continue
+				}
+				// The warnings of concurrent signatures are handled one at a
+				// time.
+				sigCo := *co
+				sigCo.WarningHandler = warningHandler
+				r.bundleVerified, r.err = verify(candidateCtx, sig, &sigCo)
+				if r.err != nil {
+					continue
+				}
+				r.sig = sig
+				mu.Lock()
+				verifiedCount++
+				if stopAfter > 0 && verifiedCount >= stopAfter {
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := range sl {
+		select {
+		case indexes <- i:
+		case <-candidateCtx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	stopped := stopAfter > 0 && verifiedCount >= stopAfter
+	errs = []error{}
+	for _, r := range results {
+		bundleVerified = bundleVerified || r.bundleVerified
+		switch {
+		case r.sig != nil:
+			checked = append(checked, r.sig)
+		case r.err != nil && !stopped:
+			errs = append(errs, r.err)
+		}
+	}
+	if len(checked) == 0 && ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+	return checked, bundleVerified, errs
+}
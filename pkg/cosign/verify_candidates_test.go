@@ -0,0 +1,88 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestVerifyCandidates(t *testing.T) {
+	// Signatures 0, 3, 6 and 9 verify.
+	sl := make([]oci.Signature, 10)
+	for i := range sl {
+		sig, err := static.NewSignature([]byte(fmt.Sprint(i)), "c2lnbmF0dXJl")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sl[i] = sig
+	}
+	var calls int32
+	verify := func(ctx context.Context, sig oci.Signature, co *CheckOpts) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		p, err := sig.Payload()
+		if err != nil {
+			return false, err
+		}
+		co.warn(sig, WarningDeprecatedAlgorithm, "checked %s", p)
+		if p[0]%3 != 0 {
+			return false, errors.New("stale signature")
+		}
+		return true, nil
+	}
+
+	for _, workers := range []int{0, 1, 4, 20} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			calls = 0
+			warnings := 0
+			co := &CheckOpts{SignatureWorkers: workers, WarningHandler: func(oci.Signature, VerificationWarning) { warnings++ }}
+			checked, bundleVerified, errs := verifyCandidates(context.Background(), sl, co, 0, verify)
+			if len(checked) != 4 || !bundleVerified || len(errs) != 6 || calls != 10 || warnings != 10 {
+				t.Fatalf("verifyCandidates() = %d signatures, %t, %d errors with %d calls and %d warnings, want 4, true, 6, 10 and 10",
+					len(checked), bundleVerified, len(errs), calls, warnings)
+			}
+			for i, sig := range checked {
+				if p, _ := sig.Payload(); string(p) != fmt.Sprint(3*i) {
+					t.Errorf("verified signature %d has payload %s, want the signatures in order", i, p)
+				}
+			}
+
+			calls = 0
+			checked, _, errs = verifyCandidates(context.Background(), sl, co, 2, verify)
+			if len(checked) < 2 {
+				t.Errorf("verifyCandidates() stopping after 2 = %d signatures", len(checked))
+			}
+			if workers <= 1 && (len(checked) != 2 || len(errs) != 2 || calls != 4) {
+				t.Errorf("verifyCandidates() stopping after 2 = %d signatures and %d errors with %d calls, want 2, 2 and 4", len(checked), len(errs), calls)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checked, _, errs := verifyCandidates(ctx, sl, &CheckOpts{SignatureWorkers: 4}, 0, func(ctx context.Context, sig oci.Signature, co *CheckOpts) (bool, error) {
+		return false, ctx.Err()
+	})
+	if len(checked) != 0 || len(errs) == 0 || !errors.Is(errs[len(errs)-1], context.Canceled) {
+		t.Errorf("verifyCandidates() with a canceled context = %d signatures and %v", len(checked), errs)
+	}
+}
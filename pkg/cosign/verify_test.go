@@ -33,6 +33,7 @@ import (
 	"net"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,6 +47,7 @@ import (
 	"github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa"
 	tsaMock "github.com/sigstore/cosign/v2/internal/pkg/cosign/tsa/mock"
 	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
 	"github.com/sigstore/cosign/v2/pkg/types"
 	"github.com/sigstore/cosign/v2/test"
@@ -135,6 +137,34 @@ func Test_verifyOCIAttestation(t *testing.T) {
 	}
 }
 
+func Test_attestationVerificationFn(t *testing.T) {
+	stmt, err := json.Marshal(in_toto.ProvenanceStatement{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := func(payloadType string) *mockAttestation {
+		return &mockAttestation{payload: map[string]interface{}{
+			"payloadType": payloadType,
+			"payload":     stmt,
+			"signatures":  []dsse.Signature{{Sig: base64.StdEncoding.EncodeToString([]byte("foobar"))}},
+		}}
+	}
+	custom := "application/vnd.example.attestation+json"
+	verify := attestationVerificationFn(&CheckOpts{PayloadTypes: []string{custom}})
+	if err := verify(context.TODO(), &mockVerifier{}, envelope(custom)); err != nil {
+		t.Errorf("attestationVerificationFn() with an allowed payload type = %v", err)
+	}
+	err = verify(context.TODO(), &mockVerifier{}, envelope(types.IntotoPayloadType))
+	var verr *VerificationError
+	if !errors.As(err, &verr) || verr.ErrorType() != ErrInvalidPayloadTypeType {
+		t.Errorf("attestationVerificationFn() with the in-toto payload type = %v, want an %s error", err, ErrInvalidPayloadTypeType)
+	}
+	// Payload types are matched exactly.
+	if err := verify(context.TODO(), &mockVerifier{}, envelope(custom+"; charset=utf-8")); err == nil {
+		t.Error("attestationVerificationFn() with a payload type with parameters: expected an error")
+	}
+}
+
 func TestVerifyImageSignature(t *testing.T) {
 	rootCert, rootKey, _ := test.GenerateRootCa()
 	subCert, subKey, _ := test.GenerateSubordinateCa(rootCert, rootKey)
@@ -376,6 +406,66 @@ func TestVerifyImageSignatureWithOnlyRoot(t *testing.T) {
 	}
 }
 
+func TestVerifyImageSignatureSharedCheckOpts(t *testing.T) {
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	pemRoot := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootCert.Raw})
+	rootPool := x509.NewCertPool()
+	rootPool.AddCert(rootCert)
+
+	// Signatures whose chains have different intermediates, or none.
+	payload := []byte{1, 2, 3, 4}
+	h := sha256.Sum256(payload)
+	var sigs []oci.Signature
+	for i := 0; i < 3; i++ {
+		issuerCert, issuerKey, chain := rootCert, rootKey, pemRoot
+		if i > 0 {
+			issuerCert, issuerKey, _ = test.GenerateSubordinateCa(rootCert, rootKey)
+			chain = appendSlices([][]byte{pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuerCert.Raw}), pemRoot})
+		}
+		leafCert, privKey, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", issuerCert, issuerKey)
+		pemLeaf := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafCert.Raw})
+		signature, _ := privKey.Sign(rand.Reader, h[:], crypto.SHA256)
+		sig, err := static.NewSignature(payload, base64.StdEncoding.EncodeToString(signature), static.WithCertChain(pemLeaf, chain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+	}
+
+	co := &CheckOpts{
+		RootCerts:  rootPool,
+		IgnoreSCT:  true,
+		IgnoreTlog: true,
+		Identities: []Identity{{Subject: "subject@mail.com", Issuer: "oidc-issuer"}},
+	}
+	// The intermediates of one signature aren't used for the next.
+	for i, sig := range append(sigs[1:], sigs[0]) {
+		if _, err := VerifyImageSignature(context.TODO(), sig, v1.Hash{}, co); err != nil {
+			t.Errorf("verifying signature %d: %v", i, err)
+		}
+	}
+
+	// co is shared by concurrent verifications.
+	var wg sync.WaitGroup
+	errs := make([]error, 8*len(sigs))
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = VerifyImageSignature(context.TODO(), sigs[i%len(sigs)], v1.Hash{}, co)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("verifying signature %d concurrently: %v", i%len(sigs), err)
+		}
+	}
+	if co.IntermediateCerts != nil || co.RootCerts != rootPool {
+		t.Error("verification modified the certificate pools of the CheckOpts")
+	}
+}
+
 func TestVerifyImageSignatureWithMissingSub(t *testing.T) {
 	rootCert, rootKey, _ := test.GenerateRootCa()
 	subCert, subKey, _ := test.GenerateSubordinateCa(rootCert, rootKey)
@@ -1461,3 +1551,33 @@ func TestVerifyRFC3161Timestamp(t *testing.T) {
 		t.Fatalf("expected error verifying mismatched signatures, got: %v", err)
 	}
 }
+
+func TestCheckExpiryWithSkew(t *testing.T) {
+	notBefore := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notBefore.Add(10 * time.Minute)}
+	for _, tt := range []struct {
+		name    string
+		at      time.Time
+		skew    time.Duration
+		wantErr bool
+	}{
+		{name: "valid", at: notBefore.Add(5 * time.Minute)},
+		{name: "before NotBefore", at: notBefore.Add(-10 * time.Second), wantErr: true},
+		{name: "before NotBefore within skew", at: notBefore.Add(-10 * time.Second), skew: 30 * time.Second},
+		{name: "after NotAfter", at: cert.NotAfter.Add(10 * time.Second), wantErr: true},
+		{name: "after NotAfter within skew", at: cert.NotAfter.Add(10 * time.Second), skew: 30 * time.Second},
+		{name: "after NotAfter beyond skew", at: cert.NotAfter.Add(time.Minute), skew: 30 * time.Second, wantErr: true},
+		{name: "negative skew", at: cert.NotAfter.Add(10 * time.Second), skew: -time.Minute, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckExpiryWithSkew(cert, tt.at, tt.skew)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckExpiryWithSkew() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			var expiredErr *CertificateExpiredError
+			if err != nil && !errors.As(err, &expiredErr) {
+				t.Errorf("CheckExpiryWithSkew() error = %T, want *CertificateExpiredError", err)
+			}
+		})
+	}
+}
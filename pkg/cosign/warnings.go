@@ -0,0 +1,101 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+const (
+	// WarningCertificateNearExpiry is raised when a long-lived signing
+	// certificate is valid now but will expire soon.
+	WarningCertificateNearExpiry = "CertificateNearExpiry"
+	// WarningInactiveTlogKey is raised when a transparency log entry was
+	// verified with a log key that is no longer active (e.g. an old shard).
+	WarningInactiveTlogKey = "InactiveTransparencyLogKey"
+	// WarningDeprecatedAlgorithm is raised when the signing certificate uses
+	// a signature algorithm or key size that is considered weak.
+	WarningDeprecatedAlgorithm = "DeprecatedAlgorithm"
+
+	// DefaultCertExpiryWarningWindow is used when CheckOpts.CertExpiryWarningWindow is unset.
+	DefaultCertExpiryWarningWindow = 30 * 24 * time.Hour
+)
+
+// VerificationWarning is a soft policy signal observed while verifying a
+// signature. Unlike a VerificationError it does not cause verification to
+// fail on its own.
+type VerificationWarning struct {
+	// Type is a stable identifier for the kind of warning, e.g. WarningCertificateNearExpiry.
+	Type string `json:"type"`
+	// Message is a human readable description of the warning.
+	Message string `json:"message"`
+}
+
+// String implements fmt.Stringer
+func (w VerificationWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Type, w.Message)
+}
+
+func (co *CheckOpts) warn(sig oci.Signature, warningType, msg string, args ...interface{}) {
+	if co.WarningHandler == nil {
+		return
+	}
+	co.WarningHandler(sig, VerificationWarning{Type: warningType, Message: fmt.Sprintf(msg, args...)})
+}
+
+// checkWarnings reports soft policy signals for a signature that has
+// otherwise passed verification. logID is the ID of the transparency log
+// whose key verified the signature's log entry, or empty if none.
+func checkWarnings(sig oci.Signature, logID string, co *CheckOpts) {
+	if co.WarningHandler == nil {
+		return
+	}
+
+	if logID != "" && co.RekorPubKeys != nil {
+		if pubKey, ok := co.RekorPubKeys.Keys[logID]; ok && pubKey.Status != tuf.Active {
+			co.warn(sig, WarningInactiveTlogKey, "transparency log entry was verified using the inactive log key %s", logID)
+		}
+	}
+
+	cert, err := sig.Cert()
+	if err != nil || cert == nil {
+		return
+	}
+
+	window := co.CertExpiryWarningWindow
+	if window == 0 {
+		window = DefaultCertExpiryWarningWindow
+	}
+	// Short-lived certificates (e.g. from Fulcio) are expected to expire
+	// almost immediately, so only warn for certificates that outlive the window.
+	now := time.Now()
+	if cert.NotAfter.Sub(cert.NotBefore) > window && now.Before(cert.NotAfter) && cert.NotAfter.Sub(now) < window {
+		co.warn(sig, WarningCertificateNearExpiry, "signing certificate expires at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+
+	switch cert.SignatureAlgorithm {
+	case x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		co.warn(sig, WarningDeprecatedAlgorithm, "signing certificate is signed with deprecated algorithm %s", cert.SignatureAlgorithm)
+	}
+	if pub, ok := cert.PublicKey.(*rsa.PublicKey); ok && pub.N.BitLen() < 2048 {
+		co.warn(sig, WarningDeprecatedAlgorithm, "signing certificate uses a %d-bit RSA key", pub.N.BitLen())
+	}
+}
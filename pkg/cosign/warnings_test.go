@@ -0,0 +1,114 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+func signatureWithValidity(t *testing.T, notBefore, notAfter time.Time) oci.Signature {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemCert, err := cryptoutils.MarshalCertificateToPEM(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature([]byte("payload"), "c2lnbmF0dXJl", static.WithCertChain(pemCert, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestCheckWarnings(t *testing.T) {
+	now := time.Now()
+	rekorPubKeys := NewTrustedTransparencyLogPubKeys()
+	rekorPubKeys.Keys["active"] = TransparencyLogPubKey{Status: tuf.Active}
+	rekorPubKeys.Keys["expired"] = TransparencyLogPubKey{Status: tuf.Expired}
+
+	tests := []struct {
+		name     string
+		sig      oci.Signature
+		logID    string
+		expected []string
+	}{{
+		name:     "long-lived certificate close to expiry",
+		sig:      signatureWithValidity(t, now.Add(-365*24*time.Hour), now.Add(7*24*time.Hour)),
+		expected: []string{WarningCertificateNearExpiry},
+	}, {
+		name: "long-lived certificate far from expiry",
+		sig:  signatureWithValidity(t, now.Add(-24*time.Hour), now.Add(365*24*time.Hour)),
+	}, {
+		name: "short-lived certificate",
+		sig:  signatureWithValidity(t, now.Add(-5*time.Minute), now.Add(5*time.Minute)),
+	}, {
+		name:     "inactive log key",
+		sig:      signatureWithValidity(t, now.Add(-5*time.Minute), now.Add(5*time.Minute)),
+		logID:    "expired",
+		expected: []string{WarningInactiveTlogKey},
+	}, {
+		name:  "active log key",
+		sig:   signatureWithValidity(t, now.Add(-5*time.Minute), now.Add(5*time.Minute)),
+		logID: "active",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			co := &CheckOpts{
+				RekorPubKeys: &rekorPubKeys,
+				WarningHandler: func(_ oci.Signature, w VerificationWarning) {
+					got = append(got, w.Type)
+				},
+			}
+			checkWarnings(tc.sig, tc.logID, co)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("checkWarnings() = %v, want %v", got, tc.expected)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("checkWarnings()[%d] = %s, want %s", i, got[i], tc.expected[i])
+				}
+			}
+		})
+	}
+}
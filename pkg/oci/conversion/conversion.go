@@ -0,0 +1,239 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conversion recognizes images whose layers were converted to a
+// lazy-pulling format, like eStargz, zstd:chunked and Nydus, and checks that
+// such an image has the same contents as the image it was converted from.
+//
+// Converting the layers changes the digest of the manifest, so the
+// signatures of the original image don't apply to the converted one.
+// Converters like nydusify --with-referrer and the Harbor acceleration
+// service record the original image in the subject of the converted
+// manifest, which is the conversion mapping used here.
+package conversion
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// Format is the format of the layers of an image.
+type Format string
+
+const (
+	// None is the format of images whose layers were not converted.
+	None Format = ""
+	// EStargz layers are gzip compressed tarballs with a table of contents.
+	EStargz Format = "eStargz"
+	// ZstdChunked layers are zstd compressed tarballs with a table of
+	// contents in a skippable frame.
+	ZstdChunked Format = "zstd:chunked"
+	// Nydus layers are RAFS blobs and a bootstrap, not tarballs.
+	Nydus Format = "Nydus"
+)
+
+// Annotations and media types that mark the layer formats.
+const (
+	estargzTOCAnnotation     = "containerd.io/snapshot/stargz/toc.digest"
+	zstdChunkedTOCAnnotation = "io.github.containers.zstd-chunked.manifest-checksum"
+	nydusBlobAnnotation      = "containerd.io/snapshot/nydus-blob"
+	nydusBootstrapAnnotation = "containerd.io/snapshot/nydus-bootstrap"
+	nydusBlobMediaType       = types.MediaType("application/vnd.oci.image.layer.nydus.blob.v1")
+)
+
+// Detect returns the format the layers of m were converted to.
+func Detect(m *v1.Manifest) Format {
+	for _, l := range m.Layers {
+		switch {
+		case l.MediaType == nydusBlobMediaType, l.Annotations[nydusBlobAnnotation] != "", l.Annotations[nydusBootstrapAnnotation] != "":
+			return Nydus
+		case l.Annotations[zstdChunkedTOCAnnotation] != "":
+			return ZstdChunked
+		case l.Annotations[estargzTOCAnnotation] != "":
+			return EStargz
+		}
+	}
+	return None
+}
+
+// Comparable reports whether images in format f can be checked against the
+// image they were converted from, because their layers are still tarballs.
+func (f Format) Comparable() bool {
+	return f == EStargz || f == ZstdChunked
+}
+
+// Source returns the digest of the image m was converted from, recorded in
+// its subject.
+func Source(m *v1.Manifest) (v1.Hash, bool) {
+	if Detect(m) == None || m.Subject == nil {
+		return v1.Hash{}, false
+	}
+	return m.Subject.Digest, true
+}
+
+// Equivalent returns an error unless converted has the same configuration
+// and the same files in each layer as source, which it was converted from.
+// Only the root filesystem diff IDs of the configurations, and the
+// compression and order of the files in the layers, may differ.
+func Equivalent(converted, source v1.Image) error {
+	cc, err := comparableConfig(converted)
+	if err != nil {
+		return err
+	}
+	sc, err := comparableConfig(source)
+	if err != nil {
+		return err
+	}
+	if cc != sc {
+		return errors.New("the image configurations differ")
+	}
+
+	cl, err := converted.Layers()
+	if err != nil {
+		return err
+	}
+	sl, err := source.Layers()
+	if err != nil {
+		return err
+	}
+	if len(cl) != len(sl) {
+		return fmt.Errorf("the images have %d and %d layers", len(cl), len(sl))
+	}
+	for i := range cl {
+		cf, err := layerFiles(cl[i])
+		if err != nil {
+			return fmt.Errorf("reading layer %d: %w", i, err)
+		}
+		sf, err := layerFiles(sl[i])
+		if err != nil {
+			return fmt.Errorf("reading layer %d of the source: %w", i, err)
+		}
+		if err := compareFiles(cf, sf); err != nil {
+			return fmt.Errorf("layer %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func comparableConfig(img v1.Image) (string, error) {
+	cf, err := img.ConfigFile()
+	if err != nil {
+		return "", err
+	}
+	c := *cf
+	c.RootFS.DiffIDs = nil
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// estargzEntries are the files eStargz adds to each layer.
+var estargzEntries = map[string]bool{
+	"stargz.index.json":     true,
+	".prefetch.landmark":    true,
+	".no.prefetch.landmark": true,
+}
+
+// file is what is compared of each file in a layer.
+type file struct {
+	Typeflag byte
+	Mode     int64
+	UID, GID int
+	Linkname string
+	Devmajor int64
+	Devminor int64
+	Size     int64
+	Digest   string
+}
+
+// layerFiles reads the files in the uncompressed tarball of l by name. Like
+// when the layer is extracted, later files replace earlier ones.
+func layerFiles(l v1.Layer) (map[string]file, error) {
+	rc, err := l.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	files := map[string]file{}
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		if estargzEntries[name] {
+			continue
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, tr); err != nil { //nolint:gosec // the layer is hashed, not extracted
+			return nil, err
+		}
+		files[name] = file{
+			Typeflag: hdr.Typeflag,
+			Mode:     hdr.Mode,
+			UID:      hdr.Uid,
+			GID:      hdr.Gid,
+			Linkname: hdr.Linkname,
+			Devmajor: hdr.Devmajor,
+			Devminor: hdr.Devminor,
+			Size:     hdr.Size,
+			Digest:   hex.EncodeToString(h.Sum(nil)),
+		}
+	}
+}
+
+func compareFiles(converted, source map[string]file) error {
+	var names []string
+	for name := range converted {
+		names = append(names, name)
+	}
+	for name := range source {
+		if _, ok := converted[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c, inConverted := converted[name]
+		s, inSource := source[name]
+		switch {
+		case !inSource:
+			return fmt.Errorf("%s was added", name)
+		case !inConverted:
+			return fmt.Errorf("%s was removed", name)
+		case c != s:
+			return fmt.Errorf("%s was changed", name)
+		}
+	}
+	return nil
+}
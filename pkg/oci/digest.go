@@ -0,0 +1,111 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto"
+	// Register the hash functions behind the supported digest algorithms.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// placeholderDigest stands in for digests go-containerregistry can't parse
+// while it parses the rest of a reference.
+var placeholderDigest = "sha256:" + strings.Repeat("0", 64)
+
+// digestAlgorithms are the manifest digest algorithms registered with the
+// OCI image specification.
+var digestAlgorithms = map[string]crypto.Hash{
+	"sha256": crypto.SHA256,
+	"sha384": crypto.SHA384,
+	"sha512": crypto.SHA512,
+}
+
+// ParseDigest parses a digest of the form "<algorithm>:<hex>".
+// Unlike v1.NewHash, it accepts every algorithm registered with the OCI
+// image specification, not only sha256.
+func ParseDigest(s string) (v1.Hash, error) {
+	alg, hex, ok := strings.Cut(s, ":")
+	if !ok {
+		return v1.Hash{}, fmt.Errorf("cannot parse digest: %q", s)
+	}
+	h, err := DigestHash(alg)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+	if strings.TrimLeft(hex, "0123456789abcdef") != "" {
+		return v1.Hash{}, fmt.Errorf("found non-hex character in digest: %q", s)
+	}
+	if len(hex) != h.Size()*2 {
+		return v1.Hash{}, fmt.Errorf("wrong number of hex digits for %s: %s", alg, hex)
+	}
+	return v1.Hash{Algorithm: alg, Hex: hex}, nil
+}
+
+// DigestHash returns the hash function for the named digest algorithm
+// (e.g. "sha512").
+func DigestHash(alg string) (crypto.Hash, error) {
+	h, ok := digestAlgorithms[alg]
+	if !ok {
+		return 0, fmt.Errorf("unsupported digest algorithm: %q", alg)
+	}
+	return h, nil
+}
+
+// ComputeDigest returns the digest of b under the named digest algorithm.
+func ComputeDigest(alg string, b []byte) (v1.Hash, error) {
+	h, err := DigestHash(alg)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+	hasher := h.New()
+	hasher.Write(b)
+	return v1.Hash{Algorithm: alg, Hex: hex.EncodeToString(hasher.Sum(nil))}, nil
+}
+
+// ParseDigestReference parses a reference by digest, like name.NewDigest.
+// Unlike name.NewDigest, it accepts every digest ParseDigest accepts.
+func ParseDigestReference(s string, opts ...name.Option) (name.Digest, error) {
+	base, dig, ok := strings.Cut(s, "@")
+	if !ok || strings.HasPrefix(dig, "sha256:") {
+		return name.NewDigest(s, opts...)
+	}
+	h, err := ParseDigest(dig)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	d, err := name.NewDigest(base+"@"+placeholderDigest, opts...)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	return d.Context().Digest(h.String()), nil
+}
+
+// ParseReference parses a reference by tag or digest, like
+// name.ParseReference. Unlike name.ParseReference, it accepts every digest
+// ParseDigest accepts.
+func ParseReference(s string, opts ...name.Option) (name.Reference, error) {
+	if strings.Contains(s, "@") {
+		return ParseDigestReference(s, opts...)
+	}
+	return name.ParseReference(s, opts...)
+}
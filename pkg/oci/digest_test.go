@@ -0,0 +1,87 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDigest(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{in: "sha256:" + strings.Repeat("a", 64)},
+		{in: "sha384:" + strings.Repeat("b", 96)},
+		{in: "sha512:" + strings.Repeat("c", 128)},
+		{in: "sha512:" + strings.Repeat("c", 64), wantErr: true},
+		{in: "sha256:" + strings.Repeat("g", 64), wantErr: true},
+		{in: "md5:" + strings.Repeat("d", 32), wantErr: true},
+		{in: strings.Repeat("e", 64), wantErr: true},
+	}
+	for _, tt := range tests {
+		h, err := ParseDigest(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDigest(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && h.String() != tt.in {
+			t.Errorf("ParseDigest(%q) = %s", tt.in, h)
+		}
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	sha256Digest := "sha256:" + strings.Repeat("a", 64)
+	sha512Digest := "sha512:" + strings.Repeat("c", 128)
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "example.com/foo:v1", want: "example.com/foo:v1"},
+		{in: "example.com/foo@" + sha256Digest, want: "example.com/foo@" + sha256Digest},
+		{in: "example.com/foo@" + sha512Digest, want: "example.com/foo@" + sha512Digest},
+		{in: "example.com/foo:v1@" + sha512Digest, want: "example.com/foo@" + sha512Digest},
+		{in: "example.com/foo@sha512:" + strings.Repeat("c", 64), wantErr: true},
+		{in: "example.com/FOO@" + sha512Digest, wantErr: true},
+	}
+	for _, tt := range tests {
+		ref, err := ParseReference(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseReference(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && ref.Name() != tt.want {
+			t.Errorf("ParseReference(%q) = %s, want %s", tt.in, ref.Name(), tt.want)
+		}
+	}
+}
+
+func TestComputeDigest(t *testing.T) {
+	h, err := ComputeDigest("sha512", []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "sha512:9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"
+	if h.String() != want {
+		t.Errorf("ComputeDigest() = %s, want %s", h, want)
+	}
+	if _, err := ComputeDigest("md5", []byte("hello")); err == nil {
+		t.Error("ComputeDigest(md5) succeeded")
+	}
+}
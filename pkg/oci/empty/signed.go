@@ -56,7 +56,7 @@ func SignedImage(ref name.Reference) (oci.SignedImage, error) {
 	d := v1.Hash{}
 	base := empty.Image
 	if digest, ok := ref.(name.Digest); ok {
-		d, err = v1.NewHash(digest.DigestStr())
+		d, err = oci.ParseDigest(digest.DigestStr())
 		if err != nil {
 			return nil, err
 		}
@@ -31,3 +31,14 @@ type SignedImageIndex interface {
 	// the nested image index's signed metadata.
 	SignedImageIndex(v1.Hash) (SignedImageIndex, error)
 }
+
+// SignedArtifactIndex is implemented by the SignedImageIndexes whose
+// manifests may be OCI artifacts that are neither images nor indexes, such
+// as the platforms of a multi-platform Helm plugin.
+type SignedArtifactIndex interface {
+	SignedImageIndex
+
+	// SignedArtifact provides accessors for the signed metadata of the nested
+	// artifact, which is never parsed as an image.
+	SignedArtifact(v1.Hash) (SignedEntity, error)
+}
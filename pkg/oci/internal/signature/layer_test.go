@@ -20,13 +20,16 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
 	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 )
 
 func mustDecode(s string) []byte {
@@ -453,3 +456,55 @@ func TestSignatureWithTSAAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestSignatureLimits(t *testing.T) {
+	t.Setenv(env.VariableMaxPayloadSize.String(), "100")
+	t.Setenv(env.VariableMaxAnnotationSize.String(), "10")
+
+	layer, err := random.Layer(300 /* byteSize */, types.DockerLayer)
+	if err != nil {
+		t.Fatalf("random.Layer() = %v", err)
+	}
+	l := &sigLayer{
+		Layer: layer,
+		desc: v1.Descriptor{
+			Annotations: map[string]string{
+				certkey:   strings.Repeat("c", 11),
+				BundleKey: strings.Repeat("b", 11),
+			},
+		},
+	}
+	if _, err := l.Payload(); !errors.Is(err, limits.ErrExceeded) {
+		t.Errorf("Payload() = %v, wanted %v", err, limits.ErrExceeded)
+	}
+	if _, err := l.Cert(); !errors.Is(err, limits.ErrExceeded) {
+		t.Errorf("Cert() = %v, wanted %v", err, limits.ErrExceeded)
+	}
+	if _, err := l.Bundle(); !errors.Is(err, limits.ErrExceeded) {
+		t.Errorf("Bundle() = %v, wanted %v", err, limits.ErrExceeded)
+	}
+}
+
+func FuzzSignatureAnnotations(f *testing.F) {
+	f.Add("YmxhaA==", "", "", `{"SignedEntryTimestamp":"","Payload":{"body":"","integratedTime":1,"logIndex":1,"logID":""}}`, `{"SignedRFC3161Timestamp":""}`)
+	f.Add("", "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----", "", `[[[[`, `{"a":`)
+	f.Fuzz(func(t *testing.T, sig, cert, chain, bndl, ts string) {
+		l := &sigLayer{
+			desc: v1.Descriptor{
+				Annotations: map[string]string{
+					sigkey:              sig,
+					certkey:             cert,
+					chainkey:            chain,
+					BundleKey:           bndl,
+					RFC3161TimestampKey: ts,
+				},
+			},
+		}
+		// Malformed annotations must fail with errors, never panic.
+		_, _ = l.Signature()
+		_, _ = l.Cert()
+		_, _ = l.Chain()
+		_, _ = l.Bundle()
+		_, _ = l.RFC3161Timestamp()
+	})
+}
@@ -0,0 +1,250 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociempty "github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/signed"
+)
+
+const (
+	// The annotations that name the image of a manifest of an OCI layout,
+	// as written by build tools.
+	refNameAnnotation        = "org.opencontainers.image.ref.name"
+	containerdNameAnnotation = "io.containerd.image.name"
+)
+
+// Local is an image or image index on disk, in an OCI layout or a docker
+// tarball, that is signed and verified without a registry.
+type Local struct {
+	// Entity is the image or image index, with the signatures and
+	// attestations stored with it.
+	Entity oci.SignedEntity
+	// Name is the reference the image is named by on disk, or "" if it has
+	// none.
+	Name string
+	// Tarball is whether the image is a docker tarball, which can't hold
+	// signatures.
+	Tarball bool
+
+	annotations map[string]string
+}
+
+// LoadLocal reads the image or image index at path. That is either an OCI
+// layout, written by WriteSignedImage, WriteSignedImageIndex or Local.Write,
+// or holding a single image or image index, e.g. the OCI output of a build,
+// or a docker tarball of a single image.
+func LoadLocal(path string) (*Local, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return loadTarball(path)
+	}
+
+	p, err := layout.FromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	ii, err := p.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	m, err := ii.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	var desc *v1.Descriptor
+	for i, d := range m.Manifests {
+		if kind := d.Annotations[kindAnnotation]; kind == imageAnnotation || kind == imageIndexAnnotation {
+			desc = &m.Manifests[i]
+			break
+		}
+	}
+	if desc == nil {
+		// The layout wasn't written by cosign, so it holds no signatures.
+		if len(m.Manifests) != 1 {
+			return nil, fmt.Errorf("%s holds %d manifests, rather than a single image or image index", path, len(m.Manifests))
+		}
+		desc = &m.Manifests[0]
+	}
+
+	l := &Local{annotations: map[string]string{}}
+	for k, v := range desc.Annotations {
+		if k != kindAnnotation {
+			l.annotations[k] = v
+		}
+	}
+	l.Name = imageName(desc.Annotations)
+	stored := &index{v1Index: ii}
+	switch {
+	case desc.MediaType.IsIndex():
+		child, err := ii.ImageIndex(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		l.Entity = &localIndex{SignedImageIndex: signed.ImageIndex(child), stored: stored}
+	case desc.MediaType.IsImage():
+		img, err := ii.Image(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		l.Entity = &localImage{SignedImage: signed.Image(img), stored: stored}
+	default:
+		return nil, fmt.Errorf("%s holds a manifest of media type %s, rather than an image or image index", path, desc.MediaType)
+	}
+	return l, nil
+}
+
+func loadTarball(path string) (*Local, error) {
+	opener := func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}
+	m, err := tarball.LoadManifest(opener)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s as a docker tarball: %w", path, err)
+	}
+	if len(m) != 1 {
+		return nil, fmt.Errorf("the docker tarball %s holds %d images, rather than a single image", path, len(m))
+	}
+	img, err := tarball.Image(opener, nil)
+	if err != nil {
+		return nil, err
+	}
+	l := &Local{Entity: &localImage{SignedImage: signed.Image(img)}, Tarball: true}
+	if len(m[0].RepoTags) > 0 {
+		l.Name = m[0].RepoTags[0]
+	}
+	return l, nil
+}
+
+// imageName returns the reference that the annotations of a manifest name
+// it by, or "" if they don't. A ref name may be only a tag, which isn't a
+// name of the image.
+func imageName(annotations map[string]string) string {
+	if n := annotations[containerdNameAnnotation]; n != "" {
+		return n
+	}
+	if n := annotations[refNameAnnotation]; strings.ContainsAny(n, "/:@") {
+		return n
+	}
+	return ""
+}
+
+// Write writes se, the Entity of l with signatures or attestations attached
+// to it, to the OCI layout at path, with the annotations of the image, e.g.
+// its name. The layout is created if it doesn't exist.
+func (l *Local) Write(path string, se oci.SignedEntity) error {
+	p, err := layout.Write(path, empty.Index)
+	if err != nil {
+		return err
+	}
+	annotations := map[string]string{}
+	for k, v := range l.annotations {
+		annotations[k] = v
+	}
+	if l.Tarball && l.Name != "" {
+		annotations[refNameAnnotation] = l.Name
+	}
+	switch e := se.(type) {
+	case oci.SignedImageIndex:
+		annotations[kindAnnotation] = imageIndexAnnotation
+		if err := p.AppendIndex(e, layout.WithAnnotations(annotations)); err != nil {
+			return fmt.Errorf("appending signed image index: %w", err)
+		}
+	case oci.SignedImage:
+		annotations[kindAnnotation] = imageAnnotation
+		if err := p.AppendImage(e, layout.WithAnnotations(annotations)); err != nil {
+			return fmt.Errorf("appending signed image: %w", err)
+		}
+	default:
+		return errors.New("only images and image indexes can be written to an OCI layout")
+	}
+	return writeSignedEntity(p, se)
+}
+
+// localImage and localIndex are an image and an image index with the
+// signatures and attestations stored in their OCI layout, if any.
+type localImage struct {
+	oci.SignedImage
+	stored *index
+}
+
+func (i *localImage) Signatures() (oci.Signatures, error) {
+	return storedSignatures(i.stored, sigsAnnotation)
+}
+
+func (i *localImage) Attestations() (oci.Signatures, error) {
+	return storedSignatures(i.stored, attsAnnotation)
+}
+
+type localIndex struct {
+	oci.SignedImageIndex
+	stored *index
+}
+
+func (i *localIndex) Signatures() (oci.Signatures, error) {
+	return storedSignatures(i.stored, sigsAnnotation)
+}
+
+func (i *localIndex) Attestations() (oci.Signatures, error) {
+	return storedSignatures(i.stored, attsAnnotation)
+}
+
+func storedSignatures(stored *index, annotation string) (oci.Signatures, error) {
+	if stored == nil {
+		return ociempty.Signatures(), nil
+	}
+	img, err := stored.imageByAnnotation(annotation)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return ociempty.Signatures(), nil
+	}
+	return &sigs{img}, nil
+}
+
+// Reference returns the digest of the image, in the repository of its Name,
+// which signature payloads and attestation subjects name the image by.
+func (l *Local) Reference() (name.Digest, error) {
+	if l.Name == "" {
+		return name.Digest{}, fmt.Errorf("the local image has no name, which is needed for the signature payload; "+
+			"name it with the %s annotation, or tag it in the docker tarball", containerdNameAnnotation)
+	}
+	ref, err := oci.ParseReference(l.Name)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("parsing the name of the local image: %w", err)
+	}
+	h, err := l.Entity.Digest()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	return ref.Context().Digest(h.String()), nil
+}
@@ -0,0 +1,157 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func count(t *testing.T, s oci.Signatures) int {
+	t.Helper()
+	l, err := s.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(l)
+}
+
+func TestLocal(t *testing.T) {
+	img, err := random.Image(300, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The OCI output of a build, which names the image.
+	path := filepath.Join(t.TempDir(), "oci")
+	p, err := layout.Write(path, empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AppendImage(img, layout.WithAnnotations(map[string]string{containerdNameAnnotation: "example.com/app:1.0", refNameAnnotation: "1.0"})); err != nil {
+		t.Fatal(err)
+	}
+	l, err := LoadLocal(path)
+	if err != nil {
+		t.Fatalf("LoadLocal() = %v", err)
+	}
+	if l.Tarball || l.Name != "example.com/app:1.0" {
+		t.Errorf("LoadLocal() = %+v, want the named OCI layout", l)
+	}
+	if got := count(t, mustSignatures(t, l.Entity)); got != 0 {
+		t.Errorf("the build output has %d signatures", got)
+	}
+	digest, err := l.Reference()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "example.com/app@" + h.String(); digest.String() != want {
+		t.Errorf("Reference() = %s, want %s", digest, want)
+	}
+
+	// The signatures are stored in the layout, which keeps the name.
+	sig, err := static.NewSignature([]byte("payload"), "c2ln")
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, err := mutate.AttachSignatureToEntity(l.Entity, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Write(path, se); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	l, err = LoadLocal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := count(t, mustSignatures(t, l.Entity)); got != 1 || l.Name != "example.com/app:1.0" {
+		t.Errorf("reloaded layout %q has %d signatures, want the name and 1", l.Name, got)
+	}
+	if got, err := l.Entity.Digest(); err != nil || got != h {
+		t.Errorf("reloaded digest = %v, %v, want %s", got, err, h)
+	}
+	// The layout can still be read as one written by cosign save.
+	sii, err := SignedImageIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if si, err := sii.SignedImage(h); err != nil || si == nil {
+		t.Errorf("SignedImage() = %v, %v", si, err)
+	}
+
+	// A docker tarball of a tagged image, which is written to a layout.
+	tarPath := filepath.Join(t.TempDir(), "image.tar")
+	tag, err := name.NewTag("example.com/other:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tarball.WriteToFile(tarPath, tag, img); err != nil {
+		t.Fatal(err)
+	}
+	tl, err := LoadLocal(tarPath)
+	if err != nil {
+		t.Fatalf("LoadLocal() of a tarball = %v", err)
+	}
+	if !tl.Tarball || tl.Name != tag.String() {
+		t.Errorf("LoadLocal() = %+v, want the tagged tarball", tl)
+	}
+	out := filepath.Join(t.TempDir(), "signed")
+	if err := tl.Write(out, tl.Entity); err != nil {
+		t.Fatal(err)
+	}
+	if ol, err := LoadLocal(out); err != nil || ol.Tarball || ol.Name != tag.String() {
+		t.Errorf("LoadLocal() of the written tarball = %+v, %v, want a layout named %s", ol, err, tag)
+	}
+
+	// A layout of several images doesn't say which one is signed.
+	several := filepath.Join(t.TempDir(), "several")
+	sp, err := layout.Write(several, empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := sp.AppendImage(img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := LoadLocal(several); err == nil || !strings.Contains(err.Error(), "2 manifests") {
+		t.Errorf("LoadLocal() of 2 images = %v", err)
+	}
+}
+
+func mustSignatures(t *testing.T, se oci.SignedEntity) oci.Signatures {
+	t.Helper()
+	s, err := se.Signatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
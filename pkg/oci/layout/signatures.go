@@ -17,6 +17,7 @@ package layout
 
 import (
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/internal/signature"
 )
@@ -33,6 +34,9 @@ func (s *sigs) Get() ([]oci.Signature, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := limits.CheckLayers(len(manifest.Layers)); err != nil {
+		return nil, err
+	}
 	signatures := make([]oci.Signature, 0, len(manifest.Layers))
 	for _, desc := range manifest.Layers {
 		l, err := s.Image.LayerByDigest(desc.Digest)
@@ -0,0 +1,125 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+)
+
+// VerifyDigests checks that every blob reachable from the index of the
+// layout at path is present and matches the digest and size it is referenced
+// with, so that a tampered layout is rejected before any of it is used.
+func VerifyDigests(path string) error {
+	p, err := layout.FromPath(path)
+	if err != nil {
+		return err
+	}
+	ii, err := p.ImageIndex()
+	if err != nil {
+		return err
+	}
+	raw, err := ii.RawManifest()
+	if err != nil {
+		return err
+	}
+	v := &digestVerifier{path: p, verified: map[v1.Hash]struct{}{}}
+	return v.index(raw)
+}
+
+type digestVerifier struct {
+	path     layout.Path
+	verified map[v1.Hash]struct{}
+}
+
+func (v *digestVerifier) index(raw []byte) error {
+	m, err := v1.ParseIndexManifest(bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	for _, desc := range m.Manifests {
+		if err := v.descriptor(desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// descriptor verifies the blob desc points at and, for manifests, the blobs
+// they reference.
+func (v *digestVerifier) descriptor(desc v1.Descriptor) error {
+	if _, ok := v.verified[desc.Digest]; ok {
+		return nil
+	}
+	b, err := v.blob(desc)
+	if err != nil {
+		return err
+	}
+	v.verified[desc.Digest] = struct{}{}
+
+	switch {
+	case desc.MediaType.IsIndex():
+		return v.index(b)
+	case desc.MediaType.IsImage():
+		m, err := v1.ParseManifest(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		if err := v.descriptor(m.Config); err != nil {
+			return err
+		}
+		for _, l := range m.Layers {
+			if err := v.descriptor(l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// blob verifies the digest and size of the blob desc points at, and returns
+// it if it is a manifest.
+func (v *digestVerifier) blob(desc v1.Descriptor) ([]byte, error) {
+	if desc.Digest.Algorithm != "sha256" {
+		return nil, fmt.Errorf("blob %s: unsupported digest algorithm %s", desc.Digest, desc.Digest.Algorithm)
+	}
+	rc, err := v.path.Blob(desc.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("blob %s: %w", desc.Digest, err)
+	}
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	var w io.Writer = io.Discard
+	if desc.MediaType.IsIndex() || desc.MediaType.IsImage() {
+		w = &buf
+	}
+	h, n, err := v1.SHA256(io.TeeReader(rc, w))
+	if err != nil {
+		return nil, fmt.Errorf("blob %s: %w", desc.Digest, err)
+	}
+	if h != desc.Digest {
+		return nil, fmt.Errorf("blob %s has digest %s", desc.Digest, h)
+	}
+	if n != desc.Size {
+		return nil, fmt.Errorf("blob %s has size %d, expected %d", desc.Digest, n, desc.Size)
+	}
+	return buf.Bytes(), nil
+}
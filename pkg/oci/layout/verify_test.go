@@ -0,0 +1,111 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+)
+
+func TestVerifyDigests(t *testing.T) {
+	si := randomSignedImage(t)
+	layers, err := si.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer, err := layers[0].Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blobPath := func(dir string, h v1.Hash) string {
+		return filepath.Join(dir, "blobs", h.Algorithm, h.Hex)
+	}
+	// sigsManifest returns the digest of the signatures manifest written to dir.
+	sigsManifest := func(dir string) v1.Hash {
+		p, err := layout.FromPath(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ii, err := p.ImageIndex()
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := ii.IndexManifest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, desc := range m.Manifests {
+			if desc.Annotations[kindAnnotation] == sigsAnnotation {
+				return desc.Digest
+			}
+		}
+		t.Fatal("no signatures were written")
+		return v1.Hash{}
+	}
+	tests := []struct {
+		name    string
+		tamper  func(dir string) error
+		wantErr string
+	}{{
+		name:   "untouched",
+		tamper: func(string) error { return nil },
+	}, {
+		name: "modified layer",
+		tamper: func(dir string) error {
+			return os.WriteFile(blobPath(dir, layer), []byte("tampered"), 0o600)
+		},
+		wantErr: "has digest",
+	}, {
+		name: "missing layer",
+		tamper: func(dir string) error {
+			return os.Remove(blobPath(dir, layer))
+		},
+		wantErr: layer.String(),
+	}, {
+		name: "modified signature manifest",
+		tamper: func(dir string) error {
+			return os.WriteFile(blobPath(dir, sigsManifest(dir)), []byte("{}"), 0o600)
+		},
+		wantErr: "has digest",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := WriteSignedImage(dir, si); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.tamper(dir); err != nil {
+				t.Fatal(err)
+			}
+			err := VerifyDigests(dir)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("VerifyDigests() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("VerifyDigests() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
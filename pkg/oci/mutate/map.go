@@ -28,7 +28,8 @@ import (
 )
 
 // Fn is the signature of the callback supplied to Map.
-// The oci.SignedEntity is either an oci.SignedImageIndex or an oci.SignedImage.
+// The oci.SignedEntity is either an oci.SignedImageIndex or an oci.SignedImage,
+// or, for the artifacts of an oci.SignedArtifactIndex, neither.
 // This callback is called on oci.SignedImageIndex *before* its children are
 // processed with a context that returns IsBeforeChildren(ctx) == true.
 // If the images within the SignedImageIndex change after the Before pass, then
@@ -124,7 +125,41 @@ func Map(ctx context.Context, parent oci.SignedEntity, fn Fn) (oci.SignedEntity,
 			})
 
 		default:
-			return nil, fmt.Errorf("unknown mime type: %v", desc.MediaType)
+			// Neither an image nor an index, such as the artifact of a platform
+			// of a Helm plugin, which only some indexes can access.
+			ai, ok := sii.(oci.SignedArtifactIndex)
+			if !ok {
+				return nil, fmt.Errorf("unknown mime type: %v", desc.MediaType)
+			}
+			x, err := ai.SignedArtifact(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			se, err := fn(ctx, x)
+			if err != nil {
+				return nil, err
+			} else if se == nil {
+				// If the function returns nil, it filters it.
+				changed = true
+				continue
+			}
+
+			add, ok := se.(Appendable)
+			if !ok {
+				return nil, fmt.Errorf("artifact %v can't be added to an index", desc.Digest)
+			}
+			changed = changed || (x != se)
+			adds = append(adds, IndexAddendum{
+				Add: add,
+				Descriptor: v1.Descriptor{
+					URLs:         desc.URLs,
+					MediaType:    desc.MediaType,
+					Annotations:  desc.Annotations,
+					Platform:     desc.Platform,
+					ArtifactType: desc.ArtifactType,
+				},
+			})
 		}
 	}
 
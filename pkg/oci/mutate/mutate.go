@@ -66,7 +66,7 @@ type indexWrapper struct {
 	addendum []IndexAddendum
 }
 
-var _ oci.SignedImageIndex = (*indexWrapper)(nil)
+var _ oci.SignedArtifactIndex = (*indexWrapper)(nil)
 
 // Signatures implements oci.SignedImageIndex
 func (i *indexWrapper) Signatures() (oci.Signatures, error) {
@@ -127,6 +127,25 @@ func (i *indexWrapper) SignedImageIndex(h v1.Hash) (oci.SignedImageIndex, error)
 	}
 }
 
+// SignedArtifact implements oci.SignedArtifactIndex
+func (i *indexWrapper) SignedArtifact(h v1.Hash) (oci.SignedEntity, error) {
+	for _, add := range i.addendum {
+		switch add.Add.(type) {
+		case oci.SignedImage, oci.SignedImageIndex:
+			continue
+		}
+		if d, err := add.Add.Digest(); err != nil {
+			return nil, err
+		} else if d == h {
+			return add.Add, nil
+		}
+	}
+	if sb, ok := i.ogbase.(oci.SignedArtifactIndex); ok {
+		return sb.SignedArtifact(h)
+	}
+	return nil, fmt.Errorf("no artifact with digest %v in index", h)
+}
+
 // AttachSignatureToEntity attaches the provided signature to the provided entity.
 func AttachSignatureToEntity(se oci.SignedEntity, sig oci.Signature, opts ...SignOption) (oci.SignedEntity, error) {
 	switch obj := se.(type) {
@@ -135,7 +154,12 @@ func AttachSignatureToEntity(se oci.SignedEntity, sig oci.Signature, opts ...Sig
 	case oci.SignedImageIndex:
 		return AttachSignatureToImageIndex(obj, sig, opts...)
 	default:
-		return nil, fmt.Errorf("unsupported type: %T", se)
+		return &signedEntity{
+			SignedEntity: se,
+			sig:          sig,
+			attachments:  make(map[string]oci.File),
+			so:           makeSignOpts(opts...),
+		}, nil
 	}
 }
 
@@ -147,7 +171,12 @@ func AttachAttestationToEntity(se oci.SignedEntity, att oci.Signature, opts ...S
 	case oci.SignedImageIndex:
 		return AttachAttestationToImageIndex(obj, att, opts...)
 	default:
-		return nil, fmt.Errorf("unsupported type: %T", se)
+		return &signedEntity{
+			SignedEntity: se,
+			att:          att,
+			attachments:  make(map[string]oci.File),
+			so:           makeSignOpts(opts...),
+		}, nil
 	}
 }
 
@@ -159,7 +188,11 @@ func AttachFileToEntity(se oci.SignedEntity, name string, f oci.File, opts ...Si
 	case oci.SignedImageIndex:
 		return AttachFileToImageIndex(obj, name, f, opts...)
 	default:
-		return nil, fmt.Errorf("unsupported type: %T", se)
+		return &signedEntity{
+			SignedEntity: se,
+			attachments:  map[string]oci.File{name: f},
+			so:           makeSignOpts(opts...),
+		}, nil
 	}
 }
 
@@ -348,3 +381,66 @@ func (sii *signedImageIndex) Attachment(attName string) (oci.File, error) {
 	}
 	return nil, fmt.Errorf("attachment %q not found", attName)
 }
+
+// signedEntity attaches to entities that are neither images nor indexes, such
+// as the OCI artifacts of ociremote.SignedArtifact.
+type signedEntity struct {
+	oci.SignedEntity
+	sig         oci.Signature
+	att         oci.Signature
+	so          *signOpts
+	attachments map[string]oci.File
+}
+
+// Signatures implements oci.SignedEntity
+func (se *signedEntity) Signatures() (oci.Signatures, error) {
+	base, err := se.SignedEntity.Signatures()
+	if err != nil {
+		return nil, err
+	} else if se.sig == nil {
+		return base, nil
+	}
+	if se.so.dd != nil {
+		if existing, err := se.so.dd.Find(base, se.sig); err != nil {
+			return nil, err
+		} else if existing != nil {
+			// Just return base if the signature is redundant
+			return base, nil
+		}
+	}
+	return AppendSignatures(base, se.sig)
+}
+
+// Attestations implements oci.SignedEntity
+func (se *signedEntity) Attestations() (oci.Signatures, error) {
+	base, err := se.SignedEntity.Attestations()
+	if err != nil {
+		return nil, err
+	} else if se.att == nil {
+		return base, nil
+	}
+	if se.so.dd != nil {
+		if existing, err := se.so.dd.Find(base, se.att); err != nil {
+			return nil, err
+		} else if existing != nil {
+			// Just return base if the signature is redundant
+			return base, nil
+		}
+	}
+	if se.so.ro != nil {
+		replace, err := se.so.ro.Replace(base, se.att)
+		if err != nil {
+			return nil, err
+		}
+		return ReplaceSignatures(replace)
+	}
+	return AppendSignatures(base, se.att)
+}
+
+// Attachment implements oci.SignedEntity
+func (se *signedEntity) Attachment(attName string) (oci.File, error) {
+	if f, ok := se.attachments[attName]; ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("attachment %q not found", attName)
+}
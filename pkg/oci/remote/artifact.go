@@ -0,0 +1,89 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	ociexperimental "github.com/sigstore/cosign/v2/internal/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+)
+
+// SignedArtifact provides access to a remote OCI artifact, such as a Helm
+// chart or a WASM module, and the signatures attached to it as OCI 1.1
+// referrers. Unlike SignedEntity, the manifest may have any media type, and
+// is never parsed as an image.
+func SignedArtifact(ref name.Reference, options ...Option) (oci.SignedEntity, error) {
+	o := makeOptions(ref.Context(), options...)
+	got, err := descriptor(ref, o)
+	var te *transport.Error
+	if errors.As(err, &te) && te.StatusCode == http.StatusNotFound {
+		return nil, errors.New("artifact not found in registry")
+	} else if err != nil {
+		return nil, err
+	}
+	return &artifact{
+		ref: ref.Context().Digest(got.Digest.String()),
+		opt: o,
+	}, nil
+}
+
+type artifact struct {
+	ref name.Digest
+	opt *options
+}
+
+var _ oci.SignedEntity = (*artifact)(nil)
+
+// Digest implements oci.SignedEntity
+func (a *artifact) Digest() (v1.Hash, error) {
+	return oci.ParseDigest(a.ref.DigestStr())
+}
+
+// Signatures implements oci.SignedEntity
+func (a *artifact) Signatures() (oci.Signatures, error) {
+	return a.referrer(ociexperimental.ArtifactType("sig"))
+}
+
+// Attestations implements oci.SignedEntity
+func (a *artifact) Attestations() (oci.Signatures, error) {
+	return a.referrer(ociexperimental.ArtifactType("att"))
+}
+
+// Attachment implements oci.SignedEntity
+func (a *artifact) Attachment(name string) (oci.File, error) {
+	return attachmentExperimentalOCI(a, name, a.opt)
+}
+
+// referrer returns the signatures of the latest referrer of the artifact
+// with artifactType, or empty signatures if there are none.
+func (a *artifact) referrer(artifactType string) (oci.Signatures, error) {
+	index, err := Referrers(a.ref, artifactType, a.opt.OriginalOptions...)
+	if err != nil {
+		return nil, err
+	}
+	if len(index.Manifests) == 0 {
+		return empty.Signatures(), nil
+	}
+	// TODO: do this smarter using "created" annotations
+	last := index.Manifests[len(index.Manifests)-1]
+	return Signatures(a.ref.Context().Digest(last.Digest.String()), a.opt.OriginalOptions...)
+}
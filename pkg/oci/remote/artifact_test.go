@@ -0,0 +1,89 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+func TestSignedArtifact(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true), registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An artifact manifest, which isn't an image.
+	raw := []byte(`{"mediaType":"application/vnd.oci.artifact.manifest.v1+json","artifactType":"application/vnd.wasm.config.v0+json","blobs":[]}`)
+	h, _, err := v1.SHA256(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := repo.Digest(h.String())
+	if err := remote.Put(digest, &taggableManifest{raw: raw, mediaType: "application/vnd.oci.artifact.manifest.v1+json"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SignedEntity(digest); err == nil {
+		t.Fatal("SignedEntity() of an artifact manifest = nil, want an unknown mime type")
+	}
+
+	for i := 0; i < 2; i++ {
+		se, err := SignedArtifact(digest)
+		if err != nil {
+			t.Fatalf("SignedArtifact() = %v", err)
+		}
+		if got, err := se.Digest(); err != nil || got != h {
+			t.Fatalf("Digest() = %v, %v, want %v", got, err, h)
+		}
+		sigs, err := se.Signatures()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := sigs.Get(); err != nil || len(got) != i {
+			t.Fatalf("Signatures() has %d signatures, %v, want %d", len(got), err, i)
+		}
+
+		sig, err := static.NewSignature([]byte(fmt.Sprintf("payload %d", i)), fmt.Sprintf("sig %d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		se, err = mutate.AttachSignatureToEntity(se, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteSignaturesExperimentalOCI(digest, se); err != nil {
+			t.Fatalf("WriteSignaturesExperimentalOCI() = %v", err)
+		}
+	}
+
+	if _, err := SignedArtifact(repo.Digest("sha256:" + strings.Repeat("0", 64))); err == nil {
+		t.Error("SignedArtifact() of a missing artifact = nil, want an error")
+	}
+}
@@ -0,0 +1,59 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// SignedBlob provides access to the signatures of a blob of a repository,
+// such as a layer or the config of an image, which are stored as those of an
+// image are, under the tag of its digest. The blob itself is never fetched.
+func SignedBlob(ref name.Digest, options ...Option) oci.SignedEntity {
+	return &blob{
+		ref: ref,
+		opt: makeOptions(ref.Context(), options...),
+	}
+}
+
+type blob struct {
+	ref name.Digest
+	opt *options
+}
+
+var _ oci.SignedEntity = (*blob)(nil)
+
+// Digest implements oci.SignedEntity
+func (b *blob) Digest() (v1.Hash, error) {
+	return oci.ParseDigest(b.ref.DigestStr())
+}
+
+// Signatures implements oci.SignedEntity
+func (b *blob) Signatures() (oci.Signatures, error) {
+	return signatures(b, b.opt)
+}
+
+// Attestations implements oci.SignedEntity
+func (b *blob) Attestations() (oci.Signatures, error) {
+	return attestations(b, b.opt)
+}
+
+// Attachment implements oci.SignedEntity
+func (b *blob) Attachment(name string) (oci.File, error) {
+	return attachment(b, name, b.opt)
+}
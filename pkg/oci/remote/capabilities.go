@@ -0,0 +1,178 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// Capabilities are the optional registry features that cosign works around
+// when a registry lacks them, as Quay and Artifactory versions differ in
+// which they support. Each is probed the first time it's used on a registry
+// and cached per registry host for the life of the process. Until probed,
+// a capability is assumed to be supported.
+type Capabilities struct {
+	// Referrers is unset if the registry doesn't serve the OCI 1.1
+	// referrers API. Referrers are then listed with the referrers tag
+	// schema without asking the registry first.
+	Referrers bool
+	// DeleteByDigest is unset if the registry rejects deleting manifests by
+	// digest. Manifests are then deleted by tag.
+	DeleteByDigest bool
+	// Tags is unset if the registry rejected a cosign signature, attestation
+	// or SBOM tag, e.g. because of a tag naming policy or immutable tags.
+	// Those can only be stored as OCI 1.1 referrers.
+	Tags bool
+}
+
+// capability is the probed state of one capability of a registry.
+type capability int
+
+const (
+	unprobed capability = iota
+	supported
+	unsupported
+)
+
+type registryCapabilities struct {
+	referrers, deleteByDigest, tags capability
+	// The smallest manifest and blob sizes that the registry rejected as too
+	// large, or 0.
+	rejectedManifestSize, rejectedBlobSize int64
+}
+
+var (
+	capabilitiesMu sync.Mutex
+	capabilities   = map[string]*registryCapabilities{}
+)
+
+// RegistryCapabilities returns what is known of the capabilities of the
+// registry at host.
+func RegistryCapabilities(host string) Capabilities {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	c := capabilities[host]
+	if c == nil {
+		return Capabilities{Referrers: true, DeleteByDigest: true, Tags: true}
+	}
+	return Capabilities{
+		Referrers:      c.referrers != unsupported,
+		DeleteByDigest: c.deleteByDigest != unsupported,
+		Tags:           c.tags != unsupported,
+	}
+}
+
+// probed returns the capability state for a probe that found it supported
+// or not.
+func probed(ok bool) capability {
+	if ok {
+		return supported
+	}
+	return unsupported
+}
+
+// updateCapabilities records the outcome of a probe of host.
+func updateCapabilities(host string, update func(*registryCapabilities)) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	c := capabilities[host]
+	if c == nil {
+		c = &registryCapabilities{}
+		capabilities[host] = c
+	}
+	update(c)
+}
+
+// CapabilitiesTransport wraps inner to probe the referrers API support of
+// each registry from its first answer to a referrers request. Registries
+// that answer with an error other than 404 Not Found, such as 405 Method Not
+// Allowed, get the 404 that makes clients fall back to the referrers tag
+// schema, and later referrers requests to registries without the referrers
+// API are answered with a 404 without being sent.
+func CapabilitiesTransport(inner http.RoundTripper) http.RoundTripper {
+	return &capabilitiesTransport{inner: inner}
+}
+
+type capabilitiesTransport struct {
+	inner http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *capabilitiesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !isReferrersPath(req.URL.Path) {
+		return t.inner.RoundTrip(req)
+	}
+	host := req.URL.Host
+	if !RegistryCapabilities(host).Referrers {
+		return indexResponse(req, http.StatusNotFound, nil), nil
+	}
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	setReferrers := func(ok bool) {
+		updateCapabilities(host, func(c *registryCapabilities) { c.referrers = probed(ok) })
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		setReferrers(true)
+	case http.StatusNotFound, http.StatusBadRequest:
+		setReferrers(false)
+	case http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusUnsupportedMediaType, http.StatusNotImplemented:
+		setReferrers(false)
+		drain(resp)
+		return indexResponse(req, http.StatusNotFound, nil), nil
+	}
+	return resp, nil
+}
+
+// writeCosignTag writes img to the cosign tag, recording a rejection of the tag
+// in the capabilities of its registry.
+func writeCosignTag(tag name.Tag, img v1.Image, opts ...remote.Option) error {
+	host := tag.RegistryStr()
+	if !RegistryCapabilities(host).Tags {
+		return fmt.Errorf("registry %s rejected cosign tags before, not writing %s", host, tag)
+	}
+	err := WriteImage(tag, img, opts...)
+	if isTagRejected(err) {
+		updateCapabilities(host, func(c *registryCapabilities) { c.tags = unsupported })
+		return fmt.Errorf("registry %s rejected the tag %s, signatures and attestations can instead be stored as OCI 1.1 referrers with --registry-referrers-mode=oci-1-1: %w", host, tag, err)
+	}
+	return err
+}
+
+// isTagRejected reports whether the registry rejected the tag of a manifest
+// write.
+func isTagRejected(err error) bool {
+	var terr *transport.Error
+	if !errors.As(err, &terr) {
+		return false
+	}
+	for _, e := range terr.Errors {
+		if e.Code == transport.TagInvalidErrorCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	"github.com/sigstore/cosign/v2/pkg/oci/signed"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+// quirkyRegistry serves an in-memory registry that answers referrers
+// requests with referrersStatus and rejects cosign tags if rejectTags is
+// set, counting the requests of each kind.
+func quirkyRegistry(t *testing.T, referrersStatus int, rejectTags bool) (name.Repository, *int32, *int32) {
+	t.Helper()
+	var referrers, tagWrites int32
+	reg := registry.New(registry.WithReferrersSupport(true), registry.Logger(log.New(io.Discard, "", 0)))
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case isReferrersPath(r.URL.Path):
+			atomic.AddInt32(&referrers, 1)
+			if referrersStatus != http.StatusOK {
+				w.WriteHeader(referrersStatus)
+				return
+			}
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, ".sig"):
+			atomic.AddInt32(&tagWrites, 1)
+			if rejectTags {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":[{"code":"TAG_INVALID","message":"tag is immutable"}]}`))
+				return
+			}
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, &referrers, &tagWrites
+}
+
+func TestCapabilitiesTransportReferrers(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		status        int
+		wantReferrers bool
+		wantRequests  int32
+	}{{
+		name:          "referrers API",
+		status:        http.StatusOK,
+		wantReferrers: true,
+		wantRequests:  2,
+	}, {
+		name:         "not found",
+		status:       http.StatusNotFound,
+		wantRequests: 1,
+	}, {
+		name:         "method not allowed",
+		status:       http.StatusMethodNotAllowed,
+		wantRequests: 1,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, referrers, _ := quirkyRegistry(t, tt.status, false)
+			img, err := random.Image(10, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h, err := img.Digest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := remote.Write(repo.Digest(h.String()), img); err != nil {
+				t.Fatal(err)
+			}
+			atomic.StoreInt32(referrers, 0)
+
+			opts := []Option{WithRemoteOptions(remote.WithTransport(CapabilitiesTransport(http.DefaultTransport)))}
+			for i := 0; i < 2; i++ {
+				if _, err := Referrers(repo.Digest(h.String()), "application/vnd.example.sbom", opts...); err != nil {
+					t.Fatalf("Referrers() = %v", err)
+				}
+			}
+			if got := RegistryCapabilities(repo.RegistryStr()).Referrers; got != tt.wantReferrers {
+				t.Errorf("Referrers capability = %t, want %t", got, tt.wantReferrers)
+			}
+			if got := atomic.LoadInt32(referrers); got != tt.wantRequests {
+				t.Errorf("sent %d referrers requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestWriteSignaturesRejectedTag(t *testing.T) {
+	repo, _, tagWrites := quirkyRegistry(t, http.StatusOK, true)
+	img, err := random.Image(10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := static.NewSignature(nil, "sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	si, err := mutate.AttachSignatureToImage(signed.Image(img), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WriteSignatures(repo, si)
+	if err == nil || !strings.Contains(err.Error(), "--registry-referrers-mode=oci-1-1") {
+		t.Fatalf("WriteSignatures() = %v, want the tag rejection", err)
+	}
+	if RegistryCapabilities(repo.RegistryStr()).Tags {
+		t.Error("Tags capability is still set after the tag was rejected")
+	}
+	if err := WriteSignatures(repo, si); err == nil {
+		t.Fatal("WriteSignatures() succeeded after the tag was rejected")
+	}
+	if got := atomic.LoadInt32(tagWrites); got != 1 {
+		t.Errorf("sent %d tag writes, want 1", got)
+	}
+}
@@ -0,0 +1,277 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+)
+
+// maxTagWriteAttempts bounds the writes of a signature or attestation tag
+// that other writers keep changing.
+const maxTagWriteAttempts = 5
+
+// tagWriteBackoff is how long to wait before the attempt-th write of a tag
+// that another writer changed, mocked by the tests.
+var tagWriteBackoff = func(attempt int) time.Duration {
+	d := time.Duration(attempt) * 200 * time.Millisecond
+	return d + time.Duration(rand.Int63n(int64(d))) // #nosec G404
+}
+
+var (
+	preconditionsMu sync.Mutex
+	// preconditions are the If-Match or If-None-Match headers of the tag
+	// writes in progress, by URL host and path of the tag manifest.
+	preconditions = map[string]string{}
+)
+
+func manifestKey(tag name.Tag) string {
+	return fmt.Sprintf("%s/v2/%s/manifests/%s", tag.RegistryStr(), tag.RepositoryStr(), tag.TagStr())
+}
+
+// expectTag makes the writes of tag through a ConditionalTransport
+// conditional on the tag pointing to the manifest with digest h, or on the
+// tag not existing if h is nil, until the returned func is called.
+func expectTag(tag name.Tag, h *v1.Hash) func() {
+	condition := "*"
+	if h != nil {
+		condition = fmt.Sprintf("%q", h.String())
+	}
+	key := manifestKey(tag)
+	preconditionsMu.Lock()
+	defer preconditionsMu.Unlock()
+	preconditions[key] = condition
+	return func() {
+		preconditionsMu.Lock()
+		defer preconditionsMu.Unlock()
+		delete(preconditions, key)
+	}
+}
+
+// ConditionalTransport wraps inner to send the writes of signature and
+// attestation tags as conditional requests, with If-Match naming the digest
+// of the manifest the signatures were merged with, or If-None-Match if the
+// tag didn't exist. Registries that support conditional requests reject the
+// write with 412 Precondition Failed if another signer wrote the tag
+// meanwhile, and the signatures are merged again. Others ignore the headers.
+func ConditionalTransport(inner http.RoundTripper) http.RoundTripper {
+	return &conditionalTransport{inner: inner}
+}
+
+type conditionalTransport struct {
+	inner http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut {
+		return t.inner.RoundTrip(req)
+	}
+	preconditionsMu.Lock()
+	condition, ok := preconditions[req.URL.Host+req.URL.Path]
+	preconditionsMu.Unlock()
+	if !ok {
+		return t.inner.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	if condition == "*" {
+		req.Header.Set("If-None-Match", condition)
+	} else {
+		req.Header.Set("If-Match", condition)
+	}
+	return t.inner.RoundTrip(req)
+}
+
+// isPreconditionFailed reports whether the registry rejected a conditional
+// write because the tag changed.
+func isPreconditionFailed(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusPreconditionFailed
+}
+
+// currentTag returns the signatures of tag and the digest of their manifest,
+// or empty signatures and a nil digest if the tag doesn't exist.
+func currentTag(tag name.Tag, o *options) (oci.Signatures, *v1.Hash, error) {
+	img, err := remoteImage(tag, o.ROpt...)
+	var te *transport.Error
+	if errors.As(err, &te) && te.StatusCode == http.StatusNotFound {
+		return empty.Signatures(), nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	h, err := img.Digest()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &sigs{Image: img}, &h, nil
+}
+
+// writeSignaturesTag writes sigs to tag without losing the signatures that
+// concurrent signers, such as parallel CI jobs signing the same digest, add
+// to it. sigs are taken to be the signatures the tag had when it was first
+// read here plus those being added: those that other writers add after that
+// are merged into sigs, and those missing from sigs stay removed, as with
+// cosign attest --replace. The write is conditional, with a
+// ConditionalTransport, and otherwise the tag is read back to find if
+// another writer replaced it, in which case the signatures are merged again.
+func writeSignaturesTag(tag name.Tag, sigs oci.Signatures, o *options) error {
+	base, h, err := currentTag(tag, o)
+	if err != nil {
+		// Reading the tag isn't required to write it.
+		return writeCosignTag(tag, sigs, o.ROpt...)
+	}
+	baseSigs, err := signatureKeys(base)
+	if err != nil {
+		return err
+	}
+	ourSigs, err := signatureKeys(sigs)
+	if err != nil {
+		return err
+	}
+	current, img := base, v1.Image(sigs)
+	for attempt := 1; ; attempt++ {
+		if current != base {
+			if img, err = mergeSignatures(sigs, current, baseSigs); err != nil {
+				return err
+			}
+		}
+		release := expectTag(tag, h)
+		err := writeCosignTag(tag, img, o.ROpt...)
+		release()
+		if err != nil && !isPreconditionFailed(err) {
+			return err
+		}
+		if err == nil {
+			want, err := img.Digest()
+			if err != nil {
+				return err
+			}
+			got, err := remoteHead(tag, o.ROpt...)
+			if err != nil || got.Digest == want {
+				// Unless it can't be read back, the tag has our write.
+				return nil
+			}
+		}
+		if attempt == maxTagWriteAttempts {
+			return fmt.Errorf("%s kept changing while writing it, after %d attempts", tag, attempt)
+		}
+		time.Sleep(tagWriteBackoff(attempt))
+		if current, h, err = currentTag(tag, o); err != nil {
+			return err
+		}
+		// Another writer may have merged our signatures already.
+		if merged, err := hasSignatures(current, ourSigs, baseSigs); err != nil || merged {
+			return err
+		}
+	}
+}
+
+// mergeSignatures returns sigs, followed by the signatures of current that
+// other writers added since the tag had the signatures base.
+func mergeSignatures(sigs, current oci.Signatures, base map[signatureKey]bool) (v1.Image, error) {
+	ours, err := sigs.Get()
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := current.Get()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[signatureKey]bool, len(ours))
+	adds := make([]mutate.Addendum, 0, len(ours)+len(theirs))
+	for i, sig := range append(ours, theirs...) {
+		k, err := keyOf(sig)
+		if err != nil {
+			return nil, err
+		}
+		if seen[k] || (i >= len(ours) && base[k]) {
+			continue
+		}
+		seen[k] = true
+		ann, err := sig.Annotations()
+		if err != nil {
+			return nil, err
+		}
+		adds = append(adds, mutate.Addendum{Layer: sig, Annotations: ann})
+	}
+	return mutate.Append(empty.Signatures(), adds...)
+}
+
+func signatureKeys(sigs oci.Signatures) (map[signatureKey]bool, error) {
+	sl, err := sigs.Get()
+	if err != nil {
+		return nil, err
+	}
+	digests := make(map[signatureKey]bool, len(sl))
+	for _, sig := range sl {
+		k, err := keyOf(sig)
+		if err != nil {
+			return nil, err
+		}
+		digests[k] = true
+	}
+	return digests, nil
+}
+
+// signatureKey tells the signatures of a tag apart: signatures of the same
+// payload only differ by their annotations.
+type signatureKey struct {
+	digest    v1.Hash
+	signature string
+}
+
+func keyOf(sig oci.Signature) (signatureKey, error) {
+	h, err := sig.Digest()
+	if err != nil {
+		return signatureKey{}, err
+	}
+	b64, err := sig.Base64Signature()
+	if err != nil {
+		return signatureKey{}, err
+	}
+	return signatureKey{digest: h, signature: b64}, nil
+}
+
+// hasSignatures reports whether sigs has all the signatures of want, and
+// none of the signatures of base missing from want.
+func hasSignatures(sigs oci.Signatures, want, base map[signatureKey]bool) (bool, error) {
+	have, err := signatureKeys(sigs)
+	if err != nil {
+		return false, err
+	}
+	for h := range want {
+		if !have[h] {
+			return false, nil
+		}
+	}
+	for h := range base {
+		if have[h] && !want[h] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,206 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+// racyRegistry serves an in-memory registry where another signer writes the
+// signature tag while the first write of it is in flight: before it if
+// conditional is set, and the registry then honors If-Match and
+// If-None-Match on the signature tag, or after it otherwise.
+func racyRegistry(t *testing.T, conditional bool, race func()) name.Repository {
+	t.Helper()
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	var (
+		mu      sync.Mutex
+		digests = map[string]string{}
+		raced   bool
+	)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || !strings.HasSuffix(r.URL.Path, ".sig") {
+			reg.ServeHTTP(w, r)
+			return
+		}
+		mu.Lock()
+		first := !raced && r.Header.Get("If-Match")+r.Header.Get("If-None-Match") != ""
+		raced = raced || first
+		mu.Unlock()
+		if first && conditional {
+			race()
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		sum := sha256.Sum256(b)
+		mu.Lock()
+		current, ok := digests[r.URL.Path]
+		if conditional && ((r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != `"`+current+`"`) || (r.Header.Get("If-None-Match") == "*" && ok)) {
+			mu.Unlock()
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		digests[r.URL.Path] = "sha256:" + hex.EncodeToString(sum[:])
+		mu.Unlock()
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		reg.ServeHTTP(w, r)
+		if first && !conditional {
+			race()
+		}
+	}))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func signatureImage(t *testing.T, sigs ...string) oci.Signatures {
+	t.Helper()
+	var adds []oci.Signature
+	for _, s := range sigs {
+		sig, err := static.NewSignature(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		adds = append(adds, sig)
+	}
+	img, err := mutate.AppendSignatures(empty.Signatures(), adds...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func tagSignatures(t *testing.T, tag name.Tag) []string {
+	t.Helper()
+	sigs, err := Signatures(tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl, err := sigs.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, sig := range sl {
+		b64, err := sig.Base64Signature()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, b64)
+	}
+	sort.Strings(got)
+	return got
+}
+
+func TestWriteSignaturesConcurrentSigner(t *testing.T) {
+	tb := tagWriteBackoff
+	t.Cleanup(func() { tagWriteBackoff = tb })
+	tagWriteBackoff = func(int) time.Duration { return 0 }
+
+	for _, tt := range []struct {
+		name        string
+		conditional bool
+		// ours and theirs are written by two signers that read the tag
+		// with base.
+		base, ours, theirs []string
+		want               []string
+	}{{
+		name:        "conditional requests",
+		conditional: true,
+		base:        []string{"a"},
+		ours:        []string{"a", "b"},
+		theirs:      []string{"a", "c"},
+		want:        []string{"a", "b", "c"},
+	}, {
+		name:   "merge and retry",
+		base:   []string{"a"},
+		ours:   []string{"a", "b"},
+		theirs: []string{"a", "c"},
+		want:   []string{"a", "b", "c"},
+	}, {
+		name:        "new tag",
+		conditional: true,
+		ours:        []string{"b"},
+		theirs:      []string{"c"},
+		want:        []string{"b", "c"},
+	}, {
+		name:   "replace",
+		base:   []string{"a", "old"},
+		ours:   []string{"a", "new"},
+		theirs: []string{"a", "old", "c"},
+		want:   []string{"a", "c", "new"},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			var tag name.Tag
+			repo := racyRegistry(t, tt.conditional, func() {
+				if err := remote.Write(tag, signatureImage(t, tt.theirs...)); err != nil {
+					t.Error(err)
+				}
+			})
+			img, err := random.Image(10, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h, err := img.Digest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tag, err = SignatureTag(repo.Digest(h.String())); err != nil {
+				t.Fatal(err)
+			}
+			if len(tt.base) > 0 {
+				if err := remote.Write(tag, signatureImage(t, tt.base...)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			opts := WithRemoteOptions(remote.WithTransport(ConditionalTransport(http.DefaultTransport)))
+			if err := writeSignaturesTag(tag, signatureImage(t, tt.ours...), makeOptions(repo, opts)); err != nil {
+				t.Fatalf("writeSignaturesTag() = %v", err)
+			}
+			if got := tagSignatures(t, tag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("signatures = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// Deleter deletes manifests with the method each registry supports. The
+// distribution spec requires manifests to be deleted by digest, which also
+// removes their tags, but some registries only support deleting tags. The
+// first rejected deletion by digest on a registry clears its DeleteByDigest
+// capability, which switches to deleting tags on that registry. Manifests
+// that other tags point to are never deleted by digest, only their tag.
+type Deleter struct {
+	ropt []remote.Option
+}
+
+// NewDeleter returns a Deleter that uses the registry client options in
+// opts.
+func NewDeleter(opts ...Option) *Deleter {
+	o := makeOptions(name.Repository{}, opts...)
+	return &Deleter{ropt: o.ROpt}
+}
+
+// DeleteTag deletes the manifest tag points to, and tag itself. If other tags
+// of the repository point to the same manifest, only tag is deleted, and
+// DeleteTag fails on registries that can't delete tags. It returns false if
+// tag doesn't exist.
+func (d *Deleter) DeleteTag(tag name.Tag) (bool, error) {
+	desc, err := remote.Head(tag, d.ropt...)
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	registry := tag.RegistryStr()
+	var aliases []string
+	if RegistryCapabilities(registry).DeleteByDigest {
+		aliases, err = d.aliases(tag, desc.Digest)
+		if err != nil {
+			return false, fmt.Errorf("listing the tags of %s: %w", tag.Context(), err)
+		}
+	}
+	if RegistryCapabilities(registry).DeleteByDigest && len(aliases) == 0 {
+		err := remote.Delete(tag.Context().Digest(desc.Digest.String()), d.ropt...)
+		switch {
+		case err == nil:
+			updateCapabilities(registry, func(c *registryCapabilities) { c.deleteByDigest = supported })
+			// Registries that keep tags after their manifest is deleted
+			// still need the tag deleted.
+			if _, err := remote.Head(tag, d.ropt...); isNotFound(err) {
+				return true, nil
+			}
+		case isUnsupported(err):
+			updateCapabilities(registry, func(c *registryCapabilities) { c.deleteByDigest = unsupported })
+		default:
+			return false, err
+		}
+	}
+
+	if err := remote.Delete(tag, d.ropt...); err != nil && !isNotFound(err) {
+		if len(aliases) > 0 && isUnsupported(err) {
+			return false, fmt.Errorf("the registry can't delete tag %s, and deleting its manifest by digest would delete the tags %s too", tag, strings.Join(aliases, ", "))
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// aliases returns the other tags of the repository of tag that point to
+// digest.
+func (d *Deleter) aliases(tag name.Tag, digest v1.Hash) ([]string, error) {
+	tags, err := remote.List(tag.Context(), d.ropt...)
+	if err != nil {
+		return nil, err
+	}
+	var aliases []string
+	for _, t := range tags {
+		if t == tag.TagStr() {
+			continue
+		}
+		desc, err := remote.Head(tag.Context().Tag(t), d.ropt...)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if desc.Digest == digest {
+			aliases = append(aliases, t)
+		}
+	}
+	return aliases, nil
+}
+
+// TagExists reports whether tag exists, so that DeleteTag would delete it.
+func (d *Deleter) TagExists(tag name.Tag) (bool, error) {
+	_, err := remote.Head(tag, d.ropt...)
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Referrers returns the digests of the referrers of digest with
+// artifactType, which DeleteReferrers deletes.
+func (d *Deleter) Referrers(digest name.Digest, artifactType string) ([]v1.Hash, error) {
+	idx, err := remote.Referrers(digest, append(d.ropt, remote.WithFilter("artifactType", artifactType))...)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	im, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	var referrers []v1.Hash
+	for _, m := range im.Manifests {
+		if m.ArtifactType == artifactType {
+			referrers = append(referrers, m.Digest)
+		}
+	}
+	return referrers, nil
+}
+
+// DeleteReferrers deletes the referrers of digest with artifactType, as
+// written in the OCI 1.1 referrers mode, and returns how many were deleted.
+// On registries without the referrers API, the referrers tag schema that
+// clients maintain instead is updated too.
+func (d *Deleter) DeleteReferrers(digest name.Digest, artifactType string) (int, error) {
+	referrers, err := d.Referrers(digest, artifactType)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted []v1.Hash
+	for _, h := range referrers {
+		if err := remote.Delete(digest.Context().Digest(h.String()), d.ropt...); err != nil && !isNotFound(err) {
+			return len(deleted), fmt.Errorf("deleting referrer %s: %w", h, err)
+		}
+		deleted = append(deleted, h)
+	}
+	if len(deleted) == 0 {
+		return 0, nil
+	}
+	return len(deleted), d.pruneReferrersTag(digest, deleted)
+}
+
+// pruneReferrersTag removes the deleted referrers from the index at the
+// referrers tag schema tag of digest, if there is one.
+func (d *Deleter) pruneReferrersTag(digest name.Digest, deleted []v1.Hash) error {
+	tag := digest.Context().Tag(strings.Replace(digest.DigestStr(), ":", "-", 1))
+	idx, err := remote.Index(tag, d.ropt...)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	pruned := mutate.RemoveManifests(idx, match.Digests(deleted...))
+	im, err := pruned.IndexManifest()
+	if err != nil {
+		return err
+	}
+	if len(im.Manifests) == 0 {
+		_, err := d.DeleteTag(tag)
+		return err
+	}
+	return remote.WriteIndex(tag, pruned, d.ropt...)
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
+
+// isUnsupported reports whether the registry rejected a request as an
+// operation it doesn't support.
+func isUnsupported(err error) bool {
+	var terr *transport.Error
+	if !errors.As(err, &terr) {
+		return false
+	}
+	switch terr.StatusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	for _, e := range terr.Errors {
+		if e.Code == transport.UnsupportedErrorCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// deleteRegistry serves an in-memory registry and records the references
+// of the DELETE requests. If digestsUnsupported is set, deletions by digest
+// are rejected like registries that only delete tags do.
+func deleteRegistry(t *testing.T, digestsUnsupported bool, opts ...registry.Option) (name.Repository, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var deletes []string
+	reg := registry.New(append(opts, registry.Logger(log.New(io.Discard, "", 0)))...)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			target := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			mu.Lock()
+			deletes = append(deletes, target)
+			mu.Unlock()
+			if digestsUnsupported && strings.HasPrefix(target, "sha256:") {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				_, _ = w.Write([]byte(`{"errors":[{"code":"UNSUPPORTED","message":"The operation is unsupported."}]}`))
+				return
+			}
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, deletes...)
+	}
+}
+
+func writeTag(t *testing.T, tag name.Tag) v1.Hash {
+	t.Helper()
+	img, err := random.Image(10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(tag, img); err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestDeleterDeleteTag(t *testing.T) {
+	for _, tt := range []struct {
+		name               string
+		digestsUnsupported bool
+		wantDeletes        func(sig, att v1.Hash) []string
+	}{{
+		name: "by digest",
+		// The in-memory registry keeps tags when their manifest is
+		// deleted, so they are deleted too.
+		wantDeletes: func(sig, att v1.Hash) []string {
+			return []string{sig.String(), "sha256-abc.sig", att.String(), "sha256-abc.att"}
+		},
+	}, {
+		name:               "tags only",
+		digestsUnsupported: true,
+		// Deletion by digest is only tried once.
+		wantDeletes: func(sig, _ v1.Hash) []string {
+			return []string{sig.String(), "sha256-abc.sig", "sha256-abc.att"}
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, deletes := deleteRegistry(t, tt.digestsUnsupported)
+			sig := writeTag(t, repo.Tag("sha256-abc.sig"))
+			att := writeTag(t, repo.Tag("sha256-abc.att"))
+
+			d := NewDeleter()
+			for _, tag := range []name.Tag{repo.Tag("sha256-abc.sig"), repo.Tag("sha256-abc.att")} {
+				if exists, err := d.TagExists(tag); err != nil || !exists {
+					t.Fatalf("TagExists(%s) = %t, %v", tag, exists, err)
+				}
+				deleted, err := d.DeleteTag(tag)
+				if err != nil || !deleted {
+					t.Fatalf("DeleteTag(%s) = %t, %v", tag, deleted, err)
+				}
+				if _, err := remote.Head(tag); !isNotFound(err) {
+					t.Errorf("%s still exists: %v", tag, err)
+				}
+			}
+			if got, want := deletes(), tt.wantDeletes(sig, att); strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("deletes = %v, want %v", got, want)
+			}
+			if got := RegistryCapabilities(repo.RegistryStr()).DeleteByDigest; got == tt.digestsUnsupported {
+				t.Errorf("DeleteByDigest capability = %t, want %t", got, !tt.digestsUnsupported)
+			}
+
+			if deleted, err := d.DeleteTag(repo.Tag("sha256-abc.sbom")); err != nil || deleted {
+				t.Errorf("DeleteTag() of a missing tag = %t, %v", deleted, err)
+			}
+		})
+	}
+}
+
+func TestDeleterDeleteTagAliases(t *testing.T) {
+	for _, digestsUnsupported := range []bool{false, true} {
+		repo, deletes := deleteRegistry(t, digestsUnsupported)
+		img, err := random.Image(10, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		latest, v1Tag := repo.Tag("latest"), repo.Tag("v1")
+		for _, tag := range []name.Tag{latest, v1Tag} {
+			if err := remote.Write(tag, img); err != nil {
+				t.Fatal(err)
+			}
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := NewDeleter()
+		if deleted, err := d.DeleteTag(latest); err != nil || !deleted {
+			t.Fatalf("digests unsupported %t: DeleteTag(%s) = %t, %v", digestsUnsupported, latest, deleted, err)
+		}
+		if _, err := remote.Head(latest); !isNotFound(err) {
+			t.Errorf("digests unsupported %t: %s still exists: %v", digestsUnsupported, latest, err)
+		}
+		// The manifest of another tag isn't deleted with the tag.
+		if desc, err := remote.Head(v1Tag); err != nil || desc.Digest != h {
+			t.Errorf("digests unsupported %t: %s = %v, %v, want %s", digestsUnsupported, v1Tag, desc, err, h)
+		}
+		if got := deletes(); strings.Join(got, " ") != "latest" {
+			t.Errorf("digests unsupported %t: deletes = %v, want only the tag", digestsUnsupported, got)
+		}
+
+		// The last tag of the manifest deletes it.
+		if deleted, err := d.DeleteTag(v1Tag); err != nil || !deleted {
+			t.Fatalf("digests unsupported %t: DeleteTag(%s) = %t, %v", digestsUnsupported, v1Tag, deleted, err)
+		}
+		if _, err := remote.Head(repo.Digest(h.String())); digestsUnsupported == isNotFound(err) {
+			t.Errorf("digests unsupported %t: manifest %s: %v", digestsUnsupported, h, err)
+		}
+	}
+}
+
+func TestDeleterDeleteReferrers(t *testing.T) {
+	const (
+		sigType  = "application/vnd.dev.cosign.artifact.sig.v1+json"
+		sbomType = "application/vnd.dev.cosign.artifact.sbom.v1+json"
+	)
+	for _, referrersAPI := range []bool{true, false} {
+		repo, _ := deleteRegistry(t, false, registry.WithReferrersSupport(referrersAPI))
+		img, err := random.Image(100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		subject := repo.Digest(h.String())
+		if err := remote.Write(subject, img); err != nil {
+			t.Fatal(err)
+		}
+		desc, err := remote.Head(subject)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, artifactType := range []string{sigType, sbomType} {
+			ref := mutate.Subject(mutate.ConfigMediaType(empty.Image, types.MediaType(artifactType)), *desc).(v1.Image)
+			rh, err := ref.Digest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := remote.Write(repo.Digest(rh.String()), ref); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if hs, err := NewDeleter().Referrers(subject, sbomType); err != nil || len(hs) != 1 {
+			t.Fatalf("referrers API %t: Referrers() = %v, %v, want the SBOM", referrersAPI, hs, err)
+		}
+		n, err := NewDeleter().DeleteReferrers(subject, sigType)
+		if err != nil || n != 1 {
+			t.Fatalf("referrers API %t: DeleteReferrers() = %d, %v, want 1", referrersAPI, n, err)
+		}
+		for artifactType, want := range map[string]int{sigType: 0, sbomType: 1} {
+			idx, err := Referrers(subject, artifactType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(idx.Manifests) != want {
+				t.Errorf("referrers API %t: %d %s referrers, want %d", referrersAPI, len(idx.Manifests), artifactType, want)
+			}
+		}
+	}
+}
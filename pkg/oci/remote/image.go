@@ -30,13 +30,27 @@ var ErrImageNotFound = errors.New("image not found in registry")
 // SignedImage provides access to a remote image reference, and its signatures.
 func SignedImage(ref name.Reference, options ...Option) (oci.SignedImage, error) {
 	o := makeOptions(ref.Context(), options...)
-	ri, err := remoteImage(ref, o.ROpt...)
+	fetchRef, h, err := manifestRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	ri, err := remoteImage(fetchRef, o.ROpt...)
 	var te *transport.Error
 	if errors.As(err, &te) && te.StatusCode == http.StatusNotFound {
 		return nil, ErrImageNotFound
 	} else if err != nil {
 		return nil, err
 	}
+	if h != nil {
+		m, err := ri.RawManifest()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkManifest(m, *h); err != nil {
+			return nil, err
+		}
+		ri = &rehashedImage{Image: ri, digest: *h}
+	}
 	return &image{
 		Image: ri,
 		opt:   o,
@@ -17,24 +17,40 @@ package remote
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 )
 
 // SignedImageIndex provides access to a remote index reference, and its signatures.
 func SignedImageIndex(ref name.Reference, options ...Option) (oci.SignedImageIndex, error) {
 	o := makeOptions(ref.Context(), options...)
-	ri, err := remoteIndex(ref, o.ROpt...)
+	fetchRef, h, err := manifestRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	ri, err := remoteIndex(fetchRef, o.ROpt...)
 	var te *transport.Error
 	if errors.As(err, &te) && te.StatusCode == http.StatusNotFound {
 		return nil, errors.New("index not found in registry")
 	} else if err != nil {
 		return nil, err
 	}
+	if h != nil {
+		m, err := ri.RawManifest()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkManifest(m, *h); err != nil {
+			return nil, err
+		}
+		ri = &rehashedIndex{v1Index: ri, digest: *h}
+	}
 	return &index{
 		v1Index: ri,
 		ref:     ref,
@@ -52,7 +68,7 @@ type index struct {
 	opt *options
 }
 
-var _ oci.SignedImageIndex = (*index)(nil)
+var _ oci.SignedArtifactIndex = (*index)(nil)
 
 // Signatures implements oci.SignedImageIndex
 func (i *index) Signatures() (oci.Signatures, error) {
@@ -92,3 +108,61 @@ func (i *index) SignedImageIndex(h v1.Hash) (oci.SignedImageIndex, error) {
 		opt:     i.opt,
 	}, nil
 }
+
+// SignedArtifact implements oci.SignedArtifactIndex
+func (i *index) SignedArtifact(h v1.Hash) (oci.SignedEntity, error) {
+	im, err := i.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, desc := range im.Manifests {
+		if desc.Digest == h {
+			return &indexArtifact{
+				desc: desc,
+				opt:  i.opt,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("no child with digest %v in index", h)
+}
+
+// indexArtifact is a manifest of an index that is neither an image nor an
+// index. Like the images of the index, its signatures are found by the tags
+// of its digest. It implements mutate.Appendable, so that it is kept when the
+// index is rebuilt.
+type indexArtifact struct {
+	desc v1.Descriptor
+	opt  *options
+}
+
+var _ oci.SignedEntity = (*indexArtifact)(nil)
+
+// Digest implements oci.SignedEntity
+func (a *indexArtifact) Digest() (v1.Hash, error) {
+	return a.desc.Digest, nil
+}
+
+// MediaType implements mutate.Appendable
+func (a *indexArtifact) MediaType() (types.MediaType, error) {
+	return a.desc.MediaType, nil
+}
+
+// Size implements mutate.Appendable
+func (a *indexArtifact) Size() (int64, error) {
+	return a.desc.Size, nil
+}
+
+// Signatures implements oci.SignedEntity
+func (a *indexArtifact) Signatures() (oci.Signatures, error) {
+	return signatures(a, a.opt)
+}
+
+// Attestations implements oci.SignedEntity
+func (a *indexArtifact) Attestations() (oci.Signatures, error) {
+	return attestations(a, a.opt)
+}
+
+// Attachment implements oci.SignedEntity
+func (a *indexArtifact) Attachment(name string) (oci.File, error) {
+	return attachment(a, name, a.opt)
+}
@@ -0,0 +1,123 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
+// namedKeychain is a keychain registered with RegisterKeychain.
+type namedKeychain struct {
+	name string
+	kc   authn.Keychain
+}
+
+var keychains []namedKeychain
+
+// RegisterKeychain adds kc, named name, to the keychains that credential
+// helpers can name, e.g. the keychains of cloud registries, which read the
+// ambient credentials of their platform.
+func RegisterKeychain(name string, kc authn.Keychain) {
+	keychains = append(keychains, namedKeychain{name: name, kc: kc})
+}
+
+// RegisteredKeychains returns the names of the keychains added with
+// RegisterKeychain, in the order they were added.
+func RegisteredKeychains() []string {
+	names := make([]string, 0, len(keychains))
+	for _, k := range keychains {
+		names = append(names, k.name)
+	}
+	return names
+}
+
+// CredentialHelperKeychain returns the keychain of the credential helper
+// helper, of the form [REGISTRY=]HELPER: the keychain registered as HELPER,
+// or else the docker credential helper program docker-credential-HELPER on
+// the PATH. With REGISTRY, e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com
+// or index.docker.io, the helper is only asked for the credentials of that
+// registry.
+func CredentialHelperKeychain(helper string) (authn.Keychain, error) {
+	registry := ""
+	if i := strings.Index(helper, "="); i >= 0 {
+		registry, helper = helper[:i], helper[i+1:]
+		if registry == "" {
+			return nil, fmt.Errorf("invalid credential helper %q: empty registry", "="+helper)
+		}
+	}
+	if helper == "" {
+		return nil, errors.New("invalid credential helper: empty name")
+	}
+	kc, err := credentialHelperKeychain(helper)
+	if err != nil {
+		return nil, err
+	}
+	if registry != "" {
+		return registryKeychain{registry: registry, kc: kc}, nil
+	}
+	return kc, nil
+}
+
+func credentialHelperKeychain(helper string) (authn.Keychain, error) {
+	for _, k := range keychains {
+		if k.name == helper {
+			return k.kc, nil
+		}
+	}
+	program := "docker-credential-" + helper
+	if _, err := exec.LookPath(program); err != nil {
+		return nil, fmt.Errorf("credential helper %s is neither a registered keychain (%s) nor a program on the PATH: %w",
+			helper, strings.Join(RegisteredKeychains(), ", "), err)
+	}
+	return authn.NewKeychainFromHelper(execHelper{program: program}), nil
+}
+
+// execHelper is an authn.Helper that runs a docker credential helper
+// program, as `docker login` would, for the credentials of a registry.
+type execHelper struct {
+	program string
+}
+
+// Get implements authn.Helper. Registries the helper has no credentials
+// for, or that it fails for, are accessed anonymously.
+func (h execHelper) Get(serverURL string) (string, string, error) {
+	creds, err := client.Get(client.NewShellProgramFunc(h.program), serverURL)
+	if err != nil {
+		return "", "", err
+	}
+	return creds.Username, creds.Secret, nil
+}
+
+// registryKeychain is a keychain that only resolves the credentials of one
+// registry.
+type registryKeychain struct {
+	registry string
+	kc       authn.Keychain
+}
+
+// Resolve implements authn.Keychain
+func (k registryKeychain) Resolve(r authn.Resource) (authn.Authenticator, error) {
+	if r.RegistryStr() != k.registry {
+		return authn.Anonymous, nil
+	}
+	return k.kc.Resolve(r)
+}
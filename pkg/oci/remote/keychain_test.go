@@ -0,0 +1,93 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+func TestCredentialHelperKeychain(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper script requires a POSIX shell")
+	}
+	// A fake helper with the credentials of every registry.
+	bin := t.TempDir()
+	script := "#!/bin/sh\nread url\necho '{\"ServerURL\":\"'$url'\",\"Username\":\"user\",\"Secret\":\"token\"}'\n"
+	if err := os.WriteFile(filepath.Join(bin, "docker-credential-fake"), []byte(script), 0o700); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	registered := keychains
+	t.Cleanup(func() { keychains = registered })
+	RegisterKeychain("static", authn.NewKeychainFromHelper(staticHelper{}))
+
+	resolve := func(t *testing.T, helper, registry string) *authn.AuthConfig {
+		t.Helper()
+		kc, err := CredentialHelperKeychain(helper)
+		if err != nil {
+			t.Fatalf("CredentialHelperKeychain(%q) = %v", helper, err)
+		}
+		reg, err := name.NewRegistry(registry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		auth, err := kc.Resolve(reg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := auth.Authorization()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+	for helper, want := range map[string]string{
+		"fake":                      "token",
+		"static":                    "secret",
+		"registry.example.com=fake": "token",
+	} {
+		if cfg := resolve(t, helper, "registry.example.com"); cfg.Username != "user" || cfg.Password != want {
+			t.Errorf("credentials of %s = %+v, want user and %s", helper, cfg, want)
+		}
+	}
+	if cfg := resolve(t, "other.example.com=fake", "registry.example.com"); *cfg != (authn.AuthConfig{}) {
+		t.Errorf("credentials of another registry's helper = %+v, want anonymous", cfg)
+	}
+
+	for helper, want := range map[string]string{
+		"missing":  "neither a registered keychain (static) nor a program",
+		"=fake":    "empty registry",
+		"example=": "empty name",
+	} {
+		if _, err := CredentialHelperKeychain(helper); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("CredentialHelperKeychain(%q) = %v, want %q", helper, err, want)
+		}
+	}
+}
+
+type staticHelper struct{}
+
+func (staticHelper) Get(string) (string, string, error) {
+	return "user", "secret", nil
+}
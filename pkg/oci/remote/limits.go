@@ -0,0 +1,261 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// DefaultManifestSize is the largest manifest pushed to registries without a
+// known limit: the 4 MiB that the OCI distribution spec asks registries to
+// accept at least, and that the distribution registry accepts at most.
+const DefaultManifestSize = 4 << 20
+
+// The parts of an upload that an UploadLimitError is about.
+const (
+	UploadManifest = "manifest"
+	UploadBlob     = "blob"
+	UploadLayers   = "layers"
+)
+
+// uploadLimits are the largest manifest and blob, and the most layers of a
+// manifest, pushed to a registry. Zero is no limit.
+type uploadLimits struct {
+	manifestSize, blobSize, layers int64
+}
+
+// knownUploadLimits returns the documented upload limits of the registry at
+// host.
+func knownUploadLimits(host string) uploadLimits {
+	l := uploadLimits{manifestSize: DefaultManifestSize}
+	switch {
+	case strings.Contains(host, ".dkr.ecr.") && strings.HasSuffix(host, ".amazonaws.com"):
+		l.blobSize, l.layers = 52000<<20, 4200
+	case host == "ghcr.io":
+		l.blobSize = 10 << 30
+	}
+	return l
+}
+
+// registryUploadLimits returns the upload limits of the registry at host:
+// its known limits, unless overridden with $COSIGN_REGISTRY_MAX_MANIFEST_SIZE
+// or $COSIGN_REGISTRY_MAX_BLOB_SIZE, lowered below the uploads it rejected
+// as too large. Manifests have at most the $COSIGN_MAX_LAYERS layers that
+// verifiers read.
+func registryUploadLimits(host string) (uploadLimits, error) {
+	l := knownUploadLimits(host)
+	var err error
+	if l.manifestSize, err = limits.RegistryManifestSize(l.manifestSize); err != nil {
+		return l, err
+	}
+	if l.blobSize, err = limits.RegistryBlobSize(l.blobSize); err != nil {
+		return l, err
+	}
+	read, err := limits.Layers()
+	if err != nil {
+		return l, err
+	}
+	if l.layers == 0 || read < l.layers {
+		l.layers = read
+	}
+
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	if c := capabilities[host]; c != nil {
+		lowerLimit(&l.manifestSize, c.rejectedManifestSize)
+		lowerLimit(&l.blobSize, c.rejectedBlobSize)
+	}
+	return l, nil
+}
+
+// lowerLimit lowers limit below rejected, a size that was rejected, if set.
+func lowerLimit(limit *int64, rejected int64) {
+	if rejected > 0 && (*limit == 0 || rejected <= *limit) {
+		*limit = rejected - 1
+	}
+}
+
+// UploadLimitError is returned when a manifest or blob is larger, or a
+// manifest has more layers, than can be pushed to a registry, before it is
+// pushed or once the registry rejected it.
+type UploadLimitError struct {
+	// Ref is the reference the upload was pushed to.
+	Ref name.Reference
+	// What is UploadManifest, UploadBlob or UploadLayers.
+	What string
+	// Size is the size of the manifest or blob, or the number of layers.
+	Size int64
+	// Limit is the most that can be pushed, or 0 if the registry rejected
+	// the upload without telling.
+	Limit int64
+	// Err is the error of the registry, if it rejected the upload.
+	Err error
+}
+
+func (e *UploadLimitError) Error() string {
+	host := e.Ref.Context().RegistryStr()
+	if e.Limit == 0 {
+		return fmt.Sprintf("%s rejected the %s of %s as too large, at %d bytes: %v", host, e.What, e.Ref, e.Size, e.Err)
+	}
+	if e.What == UploadLayers {
+		return fmt.Sprintf("the manifest of %s has %d layers, more than the %d that can be pushed to %s", e.Ref, e.Size, e.Limit, host)
+	}
+	return fmt.Sprintf("the %s of %s is %d bytes, larger than the %d bytes that can be pushed to %s", e.What, e.Ref, e.Size, e.Limit, host)
+}
+
+// Unwrap returns the error of the registry, or limits.ErrExceeded if the
+// upload wasn't pushed.
+func (e *UploadLimitError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+	return limits.ErrExceeded
+}
+
+// Guidance returns suggestions to make the upload fit.
+func (e *UploadLimitError) Guidance() []string {
+	host := e.Ref.Context().RegistryStr()
+	referrers := "Store signatures and attestations as OCI 1.1 referrers with --registry-referrers-mode=oci-1-1, which pushes a manifest for each."
+	switch e.What {
+	case UploadBlob:
+		return []string{
+			"Minify JSON payloads, e.g. with `jq -c`, which shrinks indented SBOMs and predicates severalfold.",
+			"Split the payload, e.g. attest an SBOM for each platform or component of the image rather than a single one.",
+			"Store the payload elsewhere, and attest its digest and location with a custom predicate.",
+			fmt.Sprintf("If %s accepts larger blobs, set %s to its limit.", host, env.VariableRegistryMaxBlobSize),
+		}
+	case UploadLayers:
+		return []string{
+			"Each signature or attestation adds a layer to the manifest of its tag: remove those no longer needed with `cosign clean`, or replace attestations with `cosign attest --replace`.",
+			referrers,
+			fmt.Sprintf("If the verifiers of the image read more layers, set %s to their limit.", env.VariableMaxLayers),
+		}
+	default:
+		return []string{
+			"Each signature or attestation adds a layer, annotated with its certificate, chain and transparency log bundle, to the manifest of its tag: remove those no longer needed with `cosign clean`.",
+			referrers,
+			"Sign with fewer or smaller --annotations.",
+			fmt.Sprintf("If %s accepts larger manifests, set %s to its limit.", host, env.VariableRegistryMaxManifestSize),
+		}
+	}
+}
+
+// WriteGuidance writes the guidance of the error to w, with a write for each
+// suggestion, so that a term.NewResponsiveWriter wraps them.
+func (e *UploadLimitError) WriteGuidance(w io.Writer) {
+	fmt.Fprintln(w, "To make the upload fit:")
+	for _, g := range e.Guidance() {
+		fmt.Fprintf(w, "  - %s\n", g)
+	}
+}
+
+// checkUploadLimits returns an UploadLimitError if a manifest of
+// manifestSize bytes, with layers of layerSizes, can't be pushed to ref.
+func checkUploadLimits(ref name.Reference, manifestSize int64, layerSizes []int64) error {
+	l, err := registryUploadLimits(ref.Context().RegistryStr())
+	if err != nil {
+		return err
+	}
+	if n := int64(len(layerSizes)); l.layers > 0 && n > l.layers {
+		return &UploadLimitError{Ref: ref, What: UploadLayers, Size: n, Limit: l.layers}
+	}
+	for _, size := range layerSizes {
+		if l.blobSize > 0 && size > l.blobSize {
+			return &UploadLimitError{Ref: ref, What: UploadBlob, Size: size, Limit: l.blobSize}
+		}
+	}
+	if l.manifestSize > 0 && manifestSize > l.manifestSize {
+		return &UploadLimitError{Ref: ref, What: UploadManifest, Size: manifestSize, Limit: l.manifestSize}
+	}
+	return nil
+}
+
+// uploadRejected returns an UploadLimitError if err is the registry of ref
+// rejecting an upload as too large, recording its size so that later uploads
+// fail before being pushed, and err otherwise. The size of a rejected blob is
+// taken to be the largest of layerSizes.
+func uploadRejected(ref name.Reference, manifestSize int64, layerSizes []int64, err error) error {
+	var terr *transport.Error
+	if !errors.As(err, &terr) || terr.StatusCode != http.StatusRequestEntityTooLarge {
+		return err
+	}
+	e := &UploadLimitError{Ref: ref, What: UploadManifest, Size: manifestSize, Err: err}
+	if terr.Request != nil && strings.Contains(terr.Request.URL.Path, "/blobs/") {
+		e.What, e.Size = UploadBlob, 0
+		for _, size := range layerSizes {
+			if size > e.Size {
+				e.Size = size
+			}
+		}
+	}
+	updateCapabilities(ref.Context().RegistryStr(), func(c *registryCapabilities) {
+		rejected := &c.rejectedManifestSize
+		if e.What == UploadBlob {
+			rejected = &c.rejectedBlobSize
+		}
+		if *rejected == 0 || e.Size < *rejected {
+			*rejected = e.Size
+		}
+	})
+	return e
+}
+
+// imageSizes returns the size of the manifest of img and of its layers.
+func imageSizes(img v1.Image) (int64, []int64, error) {
+	raw, err := img.RawManifest()
+	if err != nil {
+		return 0, nil, err
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return 0, nil, err
+	}
+	sizes := make([]int64, 0, len(layers))
+	for _, l := range layers {
+		size, err := l.Size()
+		if err != nil {
+			return 0, nil, err
+		}
+		sizes = append(sizes, size)
+	}
+	return int64(len(raw)), sizes, nil
+}
+
+// WriteImage writes img to ref as remote.Write does, once it has checked
+// that the registry accepts its manifest and layers. It returns an
+// UploadLimitError if they are too large, before or once pushed.
+func WriteImage(ref name.Reference, img v1.Image, opts ...remote.Option) error {
+	manifestSize, layerSizes, err := imageSizes(img)
+	if err != nil {
+		return err
+	}
+	if err := checkUploadLimits(ref, manifestSize, layerSizes); err != nil {
+		return err
+	}
+	return uploadRejected(ref, manifestSize, layerSizes, remoteWrite(ref, img, opts...))
+}
@@ -0,0 +1,119 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+func TestWriteImageLimits(t *testing.T) {
+	var manifestPuts int32
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+			atomic.AddInt32(&manifestPuts, 1)
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	tag, err := name.NewTag(strings.TrimPrefix(s.URL, "http://") + "/app:sha256-abcd.sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(t *testing.T, err error, what string, limit int64) *UploadLimitError {
+		t.Helper()
+		var lerr *UploadLimitError
+		if !errors.As(err, &lerr) || lerr.What != what || lerr.Limit != limit {
+			t.Fatalf("WriteImage() = %v, want an UploadLimitError of the %s with limit %d", err, what, limit)
+		}
+		return lerr
+	}
+
+	t.Run("blob", func(t *testing.T) {
+		t.Setenv(env.VariableRegistryMaxBlobSize.String(), "50")
+		err := WriteImage(tag, img)
+		check(t, err, UploadBlob, 50)
+		if !errors.Is(err, limits.ErrExceeded) {
+			t.Errorf("WriteImage() = %v, want ErrExceeded", err)
+		}
+	})
+	t.Run("layers", func(t *testing.T) {
+		t.Setenv(env.VariableMaxLayers.String(), "1")
+		check(t, WriteImage(tag, img), UploadLayers, 1)
+	})
+	if n := atomic.LoadInt32(&manifestPuts); n != 0 {
+		t.Fatalf("%d manifests were pushed past the limits", n)
+	}
+
+	// The registry rejects the manifest, and later uploads of it fail before
+	// being pushed.
+	lerr := check(t, WriteImage(tag, img), UploadManifest, 0)
+	if lerr.Err == nil {
+		t.Error("UploadLimitError has no error of the registry")
+	}
+	var b bytes.Buffer
+	lerr.WriteGuidance(&b)
+	if !strings.Contains(b.String(), "--registry-referrers-mode=oci-1-1") || !strings.Contains(b.String(), env.VariableRegistryMaxManifestSize.String()) {
+		t.Errorf("WriteGuidance() = %q", b.String())
+	}
+	check(t, WriteImage(tag, img), UploadManifest, lerr.Size-1)
+	if n := atomic.LoadInt32(&manifestPuts); n != 1 {
+		t.Errorf("%d manifests were pushed, want only the rejected one", n)
+	}
+}
+
+func TestKnownUploadLimits(t *testing.T) {
+	for host, want := range map[string]uploadLimits{
+		"index.docker.io": {manifestSize: DefaultManifestSize},
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com": {manifestSize: DefaultManifestSize, blobSize: 52000 << 20, layers: 4200},
+		"ghcr.io": {manifestSize: DefaultManifestSize, blobSize: 10 << 30},
+	} {
+		if got := knownUploadLimits(host); got != want {
+			t.Errorf("knownUploadLimits(%s) = %+v, want %+v", host, got, want)
+		}
+	}
+
+	t.Setenv(env.VariableRegistryMaxManifestSize.String(), "1000")
+	l, err := registryUploadLimits("123456789012.dkr.ecr.us-east-1.amazonaws.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.manifestSize != 1000 || l.layers != limits.DefaultLayers {
+		t.Errorf("registryUploadLimits() = %+v, want the manifest size of the environment and the layers read by verifiers", l)
+	}
+}
@@ -16,6 +16,10 @@
 package remote
 
 import (
+	"context"
+	"net/http"
+	"strings"
+
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
@@ -32,3 +36,53 @@ func Referrers(d name.Digest, artifactType string, opts ...Option) (*v1.IndexMan
 	}
 	return idx.IndexManifest()
 }
+
+// ReferrersWithContext is like Referrers, but also asks the registry to only
+// return the referrers with artifactType when the registry transport is
+// wrapped with ReferrersFilterTransport. Registries that don't support the
+// filter return all referrers, which are then filtered client-side.
+func ReferrersWithContext(ctx context.Context, d name.Digest, artifactType string, opts ...Option) (*v1.IndexManifest, error) {
+	o := makeOptions(name.Repository{}, opts...)
+	rOpt := o.ROpt
+	rOpt = append(rOpt,
+		remote.WithContext(context.WithValue(ctx, artifactTypeFilterKey{}, artifactType)),
+		remote.WithFilter("artifactType", artifactType))
+	idx, err := remote.Referrers(d, rOpt...)
+	if err != nil {
+		return nil, err
+	}
+	return idx.IndexManifest()
+}
+
+type artifactTypeFilterKey struct{}
+
+// ReferrersFilterTransport wraps inner to pass the artifactType filter of
+// ReferrersWithContext to the registry's referrers API, so that it fetches
+// only the signature or attestation referrers instead of listing every
+// referrer of the image.
+func ReferrersFilterTransport(inner http.RoundTripper) http.RoundTripper {
+	return &referrersFilterTransport{inner: inner}
+}
+
+type referrersFilterTransport struct {
+	inner http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *referrersFilterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	artifactType, ok := req.Context().Value(artifactTypeFilterKey{}).(string)
+	if !ok || artifactType == "" || req.Method != http.MethodGet || !isReferrersPath(req.URL.Path) {
+		return t.inner.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	q := req.URL.Query()
+	q.Set("artifactType", artifactType)
+	req.URL.RawQuery = q.Encode()
+	return t.inner.RoundTrip(req)
+}
+
+// isReferrersPath reports whether path is /v2/<name>/referrers/<digest>.
+func isReferrersPath(path string) bool {
+	elems := strings.Split(strings.Trim(path, "/"), "/")
+	return len(elems) >= 4 && elems[0] == "v2" && elems[len(elems)-2] == "referrers"
+}
@@ -0,0 +1,246 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// ecrPattern matches the private Amazon ECR registries, as the ECR credential
+// helper does.
+var ecrPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-_]*\.dkr\.ecr(-fips)?\.[a-zA-Z0-9][a-zA-Z0-9-_]*\.amazonaws\.com(\.cn)?$`)
+
+// isECR reports whether host is an Amazon ECR registry.
+func isECR(host string) bool {
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	return host == "public.ecr.aws" || ecrPattern.MatchString(host)
+}
+
+// ECRReferrersTransport wraps inner to work around the way Amazon ECR serves
+// the referrers API, which otherwise makes cosign miss signatures and
+// attestations stored as referrers:
+//   - a referrers list split into pages with Link headers is fetched page
+//     by page and returned as one index.
+//   - referrers pushed with the referrers tag schema, before the repository
+//     served the referrers API, are merged in from the sha256-<hex> tag.
+//   - a 405 or 501 answer to the referrers API is turned into a 404, so that
+//     the tag schema is used instead.
+//   - descriptors without an artifactType get the one of their manifest, so
+//     that filtering on the artifact type keeps them.
+//
+// Requests to other registries are passed to inner unchanged.
+func ECRReferrersTransport(inner http.RoundTripper) http.RoundTripper {
+	return &ecrReferrersTransport{inner: inner, match: isECR}
+}
+
+type ecrReferrersTransport struct {
+	inner http.RoundTripper
+	match func(host string) bool
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ecrReferrersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !isReferrersPath(req.URL.Path) || !t.match(req.URL.Host) {
+		return t.inner.RoundTrip(req)
+	}
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		drain(resp)
+		return indexResponse(req, http.StatusNotFound, nil), nil
+	default:
+		return resp, nil
+	}
+
+	idx, err := t.referrers(req, resp)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(idx)
+	if err != nil {
+		return nil, err
+	}
+	return indexResponse(req, http.StatusOK, b), nil
+}
+
+// referrers collects every page of the referrers list starting with resp,
+// and merges in the referrers of the tag schema.
+func (t *ecrReferrersTransport) referrers(req *http.Request, resp *http.Response) (*v1.IndexManifest, error) {
+	idx := &v1.IndexManifest{}
+	seen := map[string]bool{req.URL.String(): true}
+	for {
+		page := &v1.IndexManifest{}
+		err := json.NewDecoder(resp.Body).Decode(page)
+		next := nextLink(resp)
+		drain(resp)
+		if err != nil {
+			return nil, fmt.Errorf("decoding referrers of %s: %w", req.URL, err)
+		}
+		if idx.SchemaVersion == 0 {
+			idx.SchemaVersion, idx.MediaType = page.SchemaVersion, page.MediaType
+		}
+		idx.Manifests = append(idx.Manifests, page.Manifests...)
+		if next == "" {
+			break
+		}
+		u, err := req.URL.Parse(next)
+		if err != nil {
+			return nil, fmt.Errorf("parsing referrers link %q: %w", next, err)
+		}
+		if seen[u.String()] {
+			return nil, fmt.Errorf("referrers pages of %s link back to %s", req.URL, u)
+		}
+		seen[u.String()] = true
+		resp, err = t.get(req, u, string(types.OCIImageIndex))
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			drain(resp)
+			return nil, fmt.Errorf("fetching referrers page %s: %s", u, resp.Status)
+		}
+	}
+
+	tagged, err := t.taggedReferrers(req)
+	if err != nil {
+		return nil, err
+	}
+	found := map[v1.Hash]bool{}
+	for _, desc := range idx.Manifests {
+		found[desc.Digest] = true
+	}
+	for _, desc := range tagged {
+		if !found[desc.Digest] {
+			found[desc.Digest] = true
+			idx.Manifests = append(idx.Manifests, desc)
+		}
+	}
+
+	for i, desc := range idx.Manifests {
+		if desc.ArtifactType == "" {
+			idx.Manifests[i].ArtifactType, err = t.artifactType(req, desc)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return idx, nil
+}
+
+// taggedReferrers returns the referrers in the index of the referrers tag
+// schema, if there is one.
+func (t *ecrReferrersTransport) taggedReferrers(req *http.Request) ([]v1.Descriptor, error) {
+	repo, digest, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v2/"), "/referrers/")
+	u := &url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: "/v2/" + repo + "/manifests/" + strings.Replace(digest, ":", "-", 1)}
+	resp, err := t.get(req, u, string(types.OCIImageIndex))
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusOK {
+		// Not found just means nothing was pushed with the tag schema.
+		return nil, nil
+	}
+	idx := &v1.IndexManifest{}
+	if err := json.NewDecoder(resp.Body).Decode(idx); err != nil {
+		return nil, fmt.Errorf("decoding referrers tag %s: %w", u, err)
+	}
+	return idx.Manifests, nil
+}
+
+// artifactType returns the artifact type of the manifest of desc: its
+// artifactType, or else its config media type as the referrers API would.
+func (t *ecrReferrersTransport) artifactType(req *http.Request, desc v1.Descriptor) (string, error) {
+	repo, _, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v2/"), "/referrers/")
+	u := &url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: "/v2/" + repo + "/manifests/" + desc.Digest.String()}
+	resp, err := t.get(req, u, string(desc.MediaType))
+	if err != nil {
+		return "", err
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusOK {
+		return "", nil
+	}
+	m := struct {
+		ArtifactType string        `json:"artifactType"`
+		Config       v1.Descriptor `json:"config"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return "", fmt.Errorf("decoding manifest %s: %w", u, err)
+	}
+	if m.ArtifactType != "" {
+		return m.ArtifactType, nil
+	}
+	return string(m.Config.MediaType), nil
+}
+
+// get fetches u with the headers, and so the credentials, of req.
+func (t *ecrReferrersTransport) get(req *http.Request, u *url.URL, accept string) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL = u
+	r.Host = u.Host
+	r.Header.Set("Accept", accept)
+	return t.inner.RoundTrip(r)
+}
+
+// nextLink returns the target of the rel="next" Link header of resp.
+func nextLink(resp *http.Response) string {
+	for _, h := range resp.Header.Values("Link") {
+		for _, link := range strings.Split(h, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+				continue
+			}
+			return strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+	return ""
+}
+
+func indexResponse(req *http.Request, status int, b []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{string(types.OCIImageIndex)}},
+		Body:          io.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       req,
+	}
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,192 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+func TestIsECR(t *testing.T) {
+	for host, want := range map[string]bool{
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com":          true,
+		"123456789012.dkr.ecr-fips.us-gov-west-1.amazonaws.com": true,
+		"123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn":      true,
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com:443":      true,
+		"public.ecr.aws":              true,
+		"ecr.us-east-1.amazonaws.com": false,
+		"gcr.io":                      false,
+		"localhost:5000":              false,
+	} {
+		if got := isECR(host); got != want {
+			t.Errorf("isECR(%q) = %t, want %t", host, got, want)
+		}
+	}
+}
+
+func TestECRReferrersTransport(t *testing.T) {
+	const sigType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	// referrers is the list that the fake referrers API serves on two pages,
+	// and unsupported makes it answer 405 instead.
+	var referrers []v1.Descriptor
+	unsupported := false
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/referrers/") {
+			reg.ServeHTTP(w, r)
+			return
+		}
+		if unsupported {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		page := referrers[:1]
+		if r.URL.Query().Get("page") == "2" {
+			page = referrers[1:]
+		} else {
+			w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+		}
+		w.Header().Set("Content-Type", string(types.OCIImageIndex))
+		_ = json.NewEncoder(w).Encode(v1.IndexManifest{SchemaVersion: 2, MediaType: types.OCIImageIndex, Manifests: page})
+	}))
+	t.Cleanup(s.Close)
+
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject := repo.Digest(h.String())
+	if err := remote.Write(subject, img); err != nil {
+		t.Fatal(err)
+	}
+	desc, err := remote.Head(subject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	referrer := func(annotation string) v1.Descriptor {
+		t.Helper()
+		ref := mutate.Annotations(mutate.Subject(mutate.ConfigMediaType(empty.Image, sigType), *desc), map[string]string{"n": annotation}).(v1.Image)
+		rh, err := ref.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(repo.Digest(rh.String()), ref); err != nil {
+			t.Fatal(err)
+		}
+		d, err := remote.Head(repo.Digest(rh.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.ArtifactType = sigType
+		return *d
+	}
+	paged, untyped, tagged := referrer("paged"), referrer("untyped"), referrer("tagged")
+	// The second page omits the artifactType.
+	untyped.ArtifactType = ""
+	referrers = []v1.Descriptor{paged, untyped}
+	// tagged was pushed with the referrers tag schema.
+	tagIdx := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{Add: mustImage(t, repo.Digest(tagged.Digest.String())), Descriptor: tagged})
+	if err := remote.WriteIndex(repo.Tag(strings.Replace(h.String(), ":", "-", 1)), tagIdx); err != nil {
+		t.Fatal(err)
+	}
+
+	compat := []Option{WithRemoteOptions(remote.WithTransport(&ecrReferrersTransport{inner: http.DefaultTransport, match: func(string) bool { return true }}))}
+	plain := []Option{WithRemoteOptions(remote.WithTransport(ECRReferrersTransport(http.DefaultTransport)))}
+	for _, tt := range []struct {
+		name        string
+		opts        []Option
+		unsupported bool
+		want        []v1.Hash
+		wantErr     bool
+	}{{
+		name: "paged, untyped and tagged referrers",
+		opts: compat,
+		want: []v1.Hash{paged.Digest, untyped.Digest, tagged.Digest},
+	}, {
+		name: "first page only for other registries",
+		opts: plain,
+		want: []v1.Hash{paged.Digest},
+	}, {
+		name:        "tag schema when unsupported",
+		opts:        compat,
+		unsupported: true,
+		want:        []v1.Hash{tagged.Digest},
+	}, {
+		name:        "error for other registries when unsupported",
+		opts:        plain,
+		unsupported: true,
+		wantErr:     true,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			unsupported = tt.unsupported
+			idx, err := Referrers(subject, sigType, tt.opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Referrers() = %+v, want an error", idx.Manifests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Referrers() = %v", err)
+			}
+			var got []string
+			for _, m := range idx.Manifests {
+				got = append(got, m.Digest.String())
+			}
+			var want []string
+			for _, h := range tt.want {
+				want = append(want, h.String())
+			}
+			sort.Strings(got)
+			sort.Strings(want)
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("Referrers() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func mustImage(t *testing.T, d name.Digest) v1.Image {
+	t.Helper()
+	img, err := remote.Image(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
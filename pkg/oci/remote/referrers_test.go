@@ -0,0 +1,108 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+func TestReferrersWithContext(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	reg := registry.New(registry.WithReferrersSupport(true), registry.Logger(log.New(io.Discard, "", 0)))
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/referrers/") {
+			mu.Lock()
+			queries = append(queries, r.URL.RawQuery)
+			mu.Unlock()
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject := repo.Digest(h.String())
+	if err := remote.Write(subject, img); err != nil {
+		t.Fatal(err)
+	}
+	desc, err := remote.Head(subject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, artifactType := range []string{"application/vnd.dev.cosign.artifact.sig.v1+json", "application/vnd.example.sbom"} {
+		ref := mutate.Subject(mutate.ConfigMediaType(empty.Image, types.MediaType(artifactType)), *desc).(v1.Image)
+		rh, err := ref.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(repo.Digest(rh.String()), ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Writing the referrers probes the referrers API too.
+	mu.Lock()
+	queries = nil
+	mu.Unlock()
+	opts := []Option{WithRemoteOptions(remote.WithTransport(ReferrersFilterTransport(http.DefaultTransport)))}
+	idx, err := ReferrersWithContext(context.Background(), subject, "application/vnd.dev.cosign.artifact.sig.v1+json", opts...)
+	if err != nil {
+		t.Fatalf("ReferrersWithContext() = %v", err)
+	}
+	if len(idx.Manifests) != 1 || idx.Manifests[0].ArtifactType != "application/vnd.dev.cosign.artifact.sig.v1+json" {
+		t.Errorf("ReferrersWithContext() = %+v, want only the signature referrer", idx.Manifests)
+	}
+	if _, err := Referrers(subject, "application/vnd.example.sbom", opts...); err != nil {
+		t.Fatalf("Referrers() = %v", err)
+	}
+
+	want := []string{"artifactType=application%2Fvnd.dev.cosign.artifact.sig.v1%2Bjson", ""}
+	if len(queries) != len(want) {
+		t.Fatalf("referrers queries = %q, want %q", queries, want)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("referrers query %d = %q, want %q", i, queries[i], want[i])
+		}
+	}
+}
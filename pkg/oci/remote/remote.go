@@ -18,7 +18,6 @@ package remote
 import (
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/google/go-containerregistry/pkg/name"
@@ -26,6 +25,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
 	ociexperimental "github.com/sigstore/cosign/v2/internal/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 )
@@ -35,6 +35,7 @@ var (
 	remoteImage = remote.Image
 	remoteIndex = remote.Index
 	remoteGet   = remote.Get
+	remoteHead  = remote.Head
 	remoteWrite = remote.Write
 )
 
@@ -43,13 +44,14 @@ var (
 func SignedEntity(ref name.Reference, options ...Option) (oci.SignedEntity, error) {
 	o := makeOptions(ref.Context(), options...)
 
-	got, err := remoteGet(ref, o.ROpt...)
+	got, err := descriptor(ref, o)
 	var te *transport.Error
 	if errors.As(err, &te) && te.StatusCode == http.StatusNotFound {
 		return nil, errors.New("entity not found in registry")
 	} else if err != nil {
 		return nil, err
 	}
+	rehashed := got.Digest.Algorithm != "sha256"
 
 	switch got.MediaType {
 	case types.OCIImageIndex, types.DockerManifestList:
@@ -57,6 +59,9 @@ func SignedEntity(ref name.Reference, options ...Option) (oci.SignedEntity, erro
 		if err != nil {
 			return nil, err
 		}
+		if rehashed {
+			ii = &rehashedIndex{v1Index: ii, digest: got.Digest}
+		}
 		return &index{
 			v1Index: ii,
 			ref:     ref.Context().Digest(got.Digest.String()),
@@ -68,6 +73,9 @@ func SignedEntity(ref name.Reference, options ...Option) (oci.SignedEntity, erro
 		if err != nil {
 			return nil, err
 		}
+		if rehashed {
+			i = &rehashedImage{Image: i, digest: got.Digest}
+		}
 		return &image{
 			Image: i,
 			opt:   o,
@@ -78,6 +86,94 @@ func SignedEntity(ref name.Reference, options ...Option) (oci.SignedEntity, erro
 	}
 }
 
+// Descriptor fetches the manifest of ref, like remote.Get. Unlike remote.Get,
+// it also fetches references by a digest other than sha256, and reports that
+// digest as the digest of the manifest.
+func Descriptor(ref name.Reference, options ...Option) (*remote.Descriptor, error) {
+	return descriptor(ref, makeOptions(ref.Context(), options...))
+}
+
+func descriptor(ref name.Reference, o *options) (*remote.Descriptor, error) {
+	fetchRef, h, err := manifestRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	got, err := remoteGet(fetchRef, o.ROpt...)
+	if err != nil {
+		return nil, err
+	}
+	if h != nil {
+		if err := checkManifest(got.Manifest, *h); err != nil {
+			return nil, err
+		}
+		got.Digest = *h
+	}
+	return got, nil
+}
+
+// manifestRef returns the reference to fetch the manifest of ref by. When ref
+// is by a digest other than sha256, it also returns that digest, which the
+// caller must check the manifest against with checkManifest:
+// go-containerregistry only computes the sha256 digest of a manifest, and
+// reports any other requested digest as a mismatch, so such references are
+// fetched as though they were tags.
+func manifestRef(ref name.Reference) (name.Reference, *v1.Hash, error) {
+	d, ok := ref.(name.Digest)
+	if !ok {
+		return ref, nil, nil
+	}
+	h, err := oci.ParseDigest(d.DigestStr())
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.Algorithm == "sha256" {
+		return ref, nil, nil
+	}
+	return opaqueDigest{Digest: d}, &h, nil
+}
+
+// opaqueDigest hides a name.Digest from the digest check of
+// go-containerregistry.
+type opaqueDigest struct {
+	name.Digest
+}
+
+// checkManifest checks that the manifest has the requested digest.
+func checkManifest(manifest []byte, want v1.Hash) error {
+	got, err := oci.ComputeDigest(want.Algorithm, manifest)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("manifest digest: %q does not match requested digest: %q", got, want)
+	}
+	return nil
+}
+
+// rehashedImage is an image fetched by a digest other than sha256, which it
+// reports in place of its sha256 digest.
+type rehashedImage struct {
+	v1.Image
+	digest v1.Hash
+}
+
+// Digest implements v1.Image
+func (i *rehashedImage) Digest() (v1.Hash, error) {
+	return i.digest, nil
+}
+
+// rehashedIndex is an index fetched by a digest other than sha256, which it
+// reports in place of its sha256 digest.
+type rehashedIndex struct {
+	v1Index
+	digest v1.Hash
+}
+
+// Digest implements v1.ImageIndex
+func (i *rehashedIndex) Digest() (v1.Hash, error) {
+	return i.digest, nil
+}
+
 // normalize turns image digests into tags with optional prefix & suffix:
 // sha256:d34db33f -> [prefix]sha256-d34db33f[.suffix]
 func normalize(h v1.Hash, prefix string, suffix string) string {
@@ -109,7 +205,7 @@ func suffixTag(ref name.Reference, suffix string, o *options) (name.Tag, error)
 	var h v1.Hash
 	if digest, ok := ref.(name.Digest); ok {
 		var err error
-		h, err = v1.NewHash(digest.DigestStr())
+		h, err = oci.ParseDigest(digest.DigestStr())
 		if err != nil { // This is effectively impossible.
 			return name.Tag{}, err
 		}
@@ -193,7 +289,7 @@ func (f *attached) Payload() ([]byte, error) {
 		return nil, err
 	}
 	defer rc.Close()
-	return io.ReadAll(rc)
+	return limits.ReadPayload(rc)
 }
 
 // attachmentExperimentalOCI is a shared implementation of the oci.Signed* Attachment method (for OCI 1.1+ behavior).
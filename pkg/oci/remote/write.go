@@ -71,7 +71,7 @@ func WriteSignedImageIndexImages(ref name.Reference, sii oci.SignedImageIndex, o
 		if err != nil {
 			return fmt.Errorf("sigs tag: %w", err)
 		}
-		if err := remoteWrite(sigsTag, sigs, o.ROpt...); err != nil {
+		if err := writeCosignTag(sigsTag, sigs, o.ROpt...); err != nil {
 			return err
 		}
 	}
@@ -86,7 +86,7 @@ func WriteSignedImageIndexImages(ref name.Reference, sii oci.SignedImageIndex, o
 		if err != nil {
 			return fmt.Errorf("sigs tag: %w", err)
 		}
-		return remoteWrite(attsTag, atts, o.ROpt...)
+		return writeCosignTag(attsTag, atts, o.ROpt...)
 	}
 	return nil
 }
@@ -109,8 +109,9 @@ func WriteSignatures(repo name.Repository, se oci.SignedEntity, opts ...Option)
 	}
 	tag := o.TargetRepository.Tag(normalize(h, o.TagPrefix, o.SignatureSuffix))
 
-	// Write the Signatures image to the tag, with the provided remote.Options
-	return remoteWrite(tag, sigs, o.ROpt...)
+	// Write the Signatures image to the tag, with the provided remote.Options,
+	// keeping the signatures that concurrent signers add to it.
+	return writeSignaturesTag(tag, sigs, o)
 }
 
 // WriteAttestations publishes the attestations attached to the given entity
@@ -131,54 +132,46 @@ func WriteAttestations(repo name.Repository, se oci.SignedEntity, opts ...Option
 	}
 	tag := o.TargetRepository.Tag(normalize(h, o.TagPrefix, o.AttestationSuffix))
 
-	// Write the Signatures image to the tag, with the provided remote.Options
-	return remoteWrite(tag, atts, o.ROpt...)
+	// Write the Signatures image to the tag, with the provided remote.Options,
+	// keeping the attestations that concurrent signers add to it.
+	return writeSignaturesTag(tag, atts, o)
 }
 
 // WriteSignaturesExperimentalOCI publishes the signatures attached to the given entity
 // into the provided repository (using OCI 1.1 methods).
 func WriteSignaturesExperimentalOCI(d name.Digest, se oci.SignedEntity, opts ...Option) error {
-	o := makeOptions(d.Repository, opts...)
-	signTarget := d.String()
-	ref, err := name.ParseReference(signTarget, o.NameOpts...)
-	if err != nil {
-		return err
-	}
-	desc, err := remote.Head(ref, o.ROpt...)
-	if err != nil {
-		return err
-	}
 	sigs, err := se.Signatures()
 	if err != nil {
 		return err
 	}
+	return writeReferrerExperimentalOCI(d, sigs, "sig", "signature", ctypes.SimpleSigningMediaType, opts...)
+}
 
-	// Write the signature blobs
-	s, err := sigs.Get()
+// WriteAttestationsExperimentalOCI publishes the attestations attached to the
+// given entity into the provided repository (using OCI 1.1 methods).
+func WriteAttestationsExperimentalOCI(d name.Digest, se oci.SignedEntity, opts ...Option) error {
+	atts, err := se.Attestations()
 	if err != nil {
 		return err
 	}
-	for _, v := range s {
-		if err := remote.WriteLayer(d.Repository, v, o.ROpt...); err != nil {
-			return err
-		}
-	}
+	return writeReferrerExperimentalOCI(d, atts, "att", "attestation", ctypes.DssePayloadType, opts...)
+}
 
-	// Write the config
-	configBytes, err := sigs.RawConfigFile()
+// writeReferrerExperimentalOCI writes sigs as a referrer of d with the
+// artifact type of attName. kind and layerMediaType are only printed.
+func writeReferrerExperimentalOCI(d name.Digest, sigs oci.Signatures, attName, kind, layerMediaType string, opts ...Option) error {
+	o := makeOptions(d.Repository, opts...)
+	signTarget := d.String()
+	ref, err := oci.ParseReference(signTarget, o.NameOpts...)
 	if err != nil {
 		return err
 	}
-	var configDesc v1.Descriptor
-	if err := json.Unmarshal(configBytes, &configDesc); err != nil {
-		return err
-	}
-	configLayer := static.NewLayer(configBytes, configDesc.MediaType)
-	if err := remote.WriteLayer(d.Repository, configLayer, o.ROpt...); err != nil {
+	desc, err := remote.Head(ref, o.ROpt...)
+	if err != nil {
 		return err
 	}
 
-	// Write the manifest containing a subject
+	// Build the manifest containing a subject
 	b, err := sigs.RawManifest()
 	if err != nil {
 		return err
@@ -188,7 +181,7 @@ func WriteSignaturesExperimentalOCI(d name.Digest, se oci.SignedEntity, opts ...
 		return err
 	}
 
-	artifactType := ociexperimental.ArtifactType("sig")
+	artifactType := ociexperimental.ArtifactType(attName)
 	m.Config.MediaType = types.MediaType(artifactType)
 	m.Subject = desc
 	b, err = json.Marshal(&m)
@@ -199,14 +192,55 @@ func WriteSignaturesExperimentalOCI(d name.Digest, se oci.SignedEntity, opts ...
 	if err != nil {
 		return err
 	}
-	targetRef, err := name.ParseReference(fmt.Sprintf("%s/%s@%s", d.RegistryStr(), d.RepositoryStr(), digest.String()))
+	targetRef, err := oci.ParseReference(fmt.Sprintf("%s/%s@%s", d.RegistryStr(), d.RepositoryStr(), digest.String()))
+	if err != nil {
+		return err
+	}
+
+	// Check that the registry accepts the referrer before pushing any of it.
+	s, err := sigs.Get()
+	if err != nil {
+		return err
+	}
+	layerSizes := make([]int64, 0, len(s))
+	for _, v := range s {
+		size, err := v.Size()
+		if err != nil {
+			return err
+		}
+		layerSizes = append(layerSizes, size)
+	}
+	if err := checkUploadLimits(targetRef, int64(len(b)), layerSizes); err != nil {
+		return err
+	}
+
+	// Write the signature blobs
+	for _, v := range s {
+		if err := remote.WriteLayer(d.Repository, v, o.ROpt...); err != nil {
+			return uploadRejected(targetRef, int64(len(b)), layerSizes, err)
+		}
+	}
+
+	// Write the config
+	configBytes, err := sigs.RawConfigFile()
 	if err != nil {
 		return err
 	}
+	var configDesc v1.Descriptor
+	if err := json.Unmarshal(configBytes, &configDesc); err != nil {
+		return err
+	}
+	configLayer := static.NewLayer(configBytes, configDesc.MediaType)
+	if err := remote.WriteLayer(d.Repository, configLayer, o.ROpt...); err != nil {
+		return err
+	}
+
+	// Write the manifest
 	// TODO: use ui.Infof
-	fmt.Fprintf(os.Stderr, "Uploading signature for [%s] to [%s] with config.mediaType [%s] layers[0].mediaType [%s].\n",
-		d.String(), targetRef.String(), artifactType, ctypes.SimpleSigningMediaType)
-	return remote.Put(targetRef, &taggableManifest{raw: b, mediaType: m.MediaType}, o.ROpt...)
+	fmt.Fprintf(os.Stderr, "Uploading %s for [%s] to [%s] with config.mediaType [%s] layers[0].mediaType [%s].\n",
+		kind, d.String(), targetRef.String(), artifactType, layerMediaType)
+	err = remote.Put(targetRef, &taggableManifest{raw: b, mediaType: m.MediaType}, o.ROpt...)
+	return uploadRejected(targetRef, int64(len(b)), layerSizes, err)
 }
 
 type taggableManifest struct {
@@ -18,12 +18,11 @@ package signature
 import (
 	"crypto/x509"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io"
 	"strings"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sigstore/cosign/v2/internal/pkg/limits"
 	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
 	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
@@ -63,9 +62,10 @@ func (s *sigLayer) Payload() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	payload, err := io.ReadAll(r)
+	defer r.Close()
+	payload, err := limits.ReadPayload(r)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("reading signature layer %s: %w", s.desc.Digest, err)
 	}
 	return payload, nil
 }
@@ -94,10 +94,16 @@ func (s *sigLayer) Cert() (*x509.Certificate, error) {
 	if certPEM == "" {
 		return nil, nil
 	}
+	if err := limits.CheckAnnotation(certkey, certPEM); err != nil {
+		return nil, err
+	}
 	certs, err := cryptoutils.LoadCertificatesFromPEM(strings.NewReader(certPEM))
 	if err != nil {
 		return nil, err
 	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("signature layer %s has no certificate in %q annotation", s.desc.Digest, certkey)
+	}
 	return certs[0], nil
 }
 
@@ -107,6 +113,9 @@ func (s *sigLayer) Chain() ([]*x509.Certificate, error) {
 	if chainPEM == "" {
 		return nil, nil
 	}
+	if err := limits.CheckAnnotation(chainkey, chainPEM); err != nil {
+		return nil, err
+	}
 	certs, err := cryptoutils.LoadCertificatesFromPEM(strings.NewReader(chainPEM))
 	if err != nil {
 		return nil, err
@@ -120,8 +129,11 @@ func (s *sigLayer) Bundle() (*bundle.RekorBundle, error) {
 	if val == "" {
 		return nil, nil
 	}
+	if err := limits.CheckAnnotation(BundleKey, val); err != nil {
+		return nil, err
+	}
 	var b bundle.RekorBundle
-	if err := json.Unmarshal([]byte(val), &b); err != nil {
+	if err := limits.Unmarshal([]byte(val), &b); err != nil {
 		return nil, fmt.Errorf("unmarshaling bundle: %w", err)
 	}
 	return &b, nil
@@ -133,8 +145,11 @@ func (s *sigLayer) RFC3161Timestamp() (*bundle.RFC3161Timestamp, error) {
 	if val == "" {
 		return nil, nil
 	}
+	if err := limits.CheckAnnotation(RFC3161TimestampKey, val); err != nil {
+		return nil, err
+	}
 	var b bundle.RFC3161Timestamp
-	if err := json.Unmarshal([]byte(val), &b); err != nil {
+	if err := limits.Unmarshal([]byte(val), &b); err != nil {
 		return nil, fmt.Errorf("unmarshaling RFC3161 timestamp bundle: %w", err)
 	}
 	return &b, nil
@@ -23,7 +23,8 @@ import (
 )
 
 // Fn is the signature of the callback supplied to SignedEntity.
-// The oci.SignedEntity is either an oci.SignedImageIndex or an oci.SignedImage.
+// The oci.SignedEntity is either an oci.SignedImageIndex or an oci.SignedImage,
+// or, for the artifacts of an oci.SignedArtifactIndex, neither.
 // This callback is called on oci.SignedImageIndex *before* its children.
 type Fn func(context.Context, oci.SignedEntity) error
 
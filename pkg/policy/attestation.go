@@ -49,9 +49,9 @@ func AttestationToPayloadJSON(_ context.Context, predicateType string, verifiedA
 	if predicateType == "" {
 		return nil, "", errors.New("missing predicate type")
 	}
-	predicateURI, ok := options.PredicateTypeMap[predicateType]
-	if !ok {
-		// Not a custom one, use it as is.
+	predicateURI, err := options.ParsePredicateType(predicateType)
+	if err != nil {
+		// Neither a predicate cosign knows nor a predicate plug-in, use it as is.
 		predicateURI = predicateType
 	}
 	var payloadData map[string]interface{}
@@ -0,0 +1,32 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exec defines a providers.Interface for helper executables, named
+// exec:<path> with --oidc-provider, that furnish the OIDC tokens of systems
+// without a provider of their own, e.g. site-specific SSO systems.
+//
+// The helper is run without arguments, with the environment of cosign and its
+// standard error, so that it may prompt for a login. It reads a request from
+// its standard input,
+//
+//	{"apiVersion": "cosign.sigstore.dev/oidc-provider/v1", "audience": "sigstore"}
+//
+// and writes a response with the token scoped to the audience to its standard
+// output, exiting with status 0,
+//
+//	{"apiVersion": "cosign.sigstore.dev/oidc-provider/v1", "token": "eyJ..."}
+//
+// A helper that exits with another status fails the signing.
+package exec
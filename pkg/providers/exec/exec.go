@@ -0,0 +1,102 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+
+	"github.com/sigstore/cosign/v2/pkg/providers"
+)
+
+// APIVersion is the version of the requests and responses of the helpers.
+const APIVersion = "cosign.sigstore.dev/oidc-provider/v1"
+
+func init() {
+	providers.RegisterFactory("exec", func(path string) (providers.Interface, error) {
+		return New(path)
+	})
+}
+
+// Request is written to the standard input of a helper.
+type Request struct {
+	APIVersion string `json:"apiVersion"`
+	Audience   string `json:"audience"`
+}
+
+// Response is read from the standard output of a helper.
+type Response struct {
+	APIVersion string `json:"apiVersion"`
+	Token      string `json:"token"`
+}
+
+type helper struct {
+	path string
+}
+
+var _ providers.Interface = (*helper)(nil)
+
+// New returns the provider of the helper executable at path, which is looked
+// up in PATH if it has no separator.
+func New(path string) (providers.Interface, error) {
+	if path == "" {
+		return nil, errors.New("exec provider requires the path of a helper, as exec:<path>")
+	}
+	p, err := osexec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("finding OIDC provider helper: %w", err)
+	}
+	return &helper{path: p}, nil
+}
+
+// Enabled implements providers.Interface
+func (h *helper) Enabled(context.Context) bool {
+	fi, err := os.Stat(h.path)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// Provide implements providers.Interface
+func (h *helper) Provide(ctx context.Context, audience string) (string, error) {
+	req, err := json.Marshal(Request{APIVersion: APIVersion, Audience: audience})
+	if err != nil {
+		return "", err
+	}
+	var stdout bytes.Buffer
+	cmd := osexec.CommandContext(ctx, h.path)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("running OIDC provider helper %s: %w", h.path, err)
+	}
+
+	resp := Response{}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return "", fmt.Errorf("decoding the response of OIDC provider helper %s: %w", h.path, err)
+	}
+	if resp.APIVersion != APIVersion {
+		return "", fmt.Errorf("OIDC provider helper %s responded with apiVersion %q, want %q", h.path, resp.APIVersion, APIVersion)
+	}
+	if resp.Token == "" {
+		return "", fmt.Errorf("OIDC provider helper %s responded without a token", h.path)
+	}
+	return resp.Token, nil
+}
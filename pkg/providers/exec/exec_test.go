@@ -0,0 +1,90 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/cosign/v2/pkg/providers"
+)
+
+func writeHelper(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "helper")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProvide(t *testing.T) {
+	ctx := context.Background()
+	// The helper echoes the audience of the request as the token.
+	path := writeHelper(t, `sed -e 's/"audience":"\([^"]*\)"/"token":"token-for-\1"/'`)
+	p, err := providers.ProvideFrom(ctx, "exec:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Enabled(ctx) {
+		t.Error("Enabled() = false, want true")
+	}
+	if got, err := p.Provide(ctx, "sigstore"); err != nil || got != "token-for-sigstore" {
+		t.Errorf("Provide() = %q, %v, want token-for-sigstore", got, err)
+	}
+
+	for name, tc := range map[string]struct {
+		script string
+		want   string
+	}{
+		"failed": {
+			script: "echo denied >&2; exit 3",
+			want:   "exit status 3",
+		},
+		"not json": {
+			script: "echo token",
+			want:   "decoding the response",
+		},
+		"another version": {
+			script: `echo '{"apiVersion":"v0","token":"t"}'`,
+			want:   "apiVersion",
+		},
+		"no token": {
+			script: `echo '{"apiVersion":"` + APIVersion + `"}'`,
+			want:   "without a token",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, err := New(writeHelper(t, tc.script))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := p.Provide(ctx, "sigstore"); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Provide() = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	if _, err := providers.ProvideFrom(ctx, "exec:"); err == nil {
+		t.Error("ProvideFrom() without a path: expected an error")
+	}
+	if _, err := providers.ProvideFrom(ctx, "exec:"+filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ProvideFrom() with a missing helper: expected an error")
+	}
+}
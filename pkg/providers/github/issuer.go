@@ -0,0 +1,61 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+)
+
+// PublicIssuer is the OIDC issuer of the tokens of GitHub Actions on
+// github.com.
+const PublicIssuer = "https://token.actions.githubusercontent.com"
+
+// Issuer returns the OIDC issuer of the tokens of GitHub Actions on the
+// GitHub server, given as a host or URL: PublicIssuer for github.com, or the
+// token service of a GitHub Enterprise Server instance.
+func Issuer(server string) (string, error) {
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", fmt.Errorf("parsing GitHub server URL: %w", err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("GitHub server URL %q has no host", server)
+	}
+	switch strings.ToLower(u.Hostname()) {
+	case "github.com", "www.github.com":
+		return PublicIssuer, nil
+	}
+	return u.Scheme + "://" + u.Host + strings.TrimSuffix(u.Path, "/") + "/_services/token", nil
+}
+
+// AmbientIssuer returns the OIDC issuer of the tokens of GitHub Actions on
+// the GitHub server of the environment, that of the workflow run or of
+// GITHUB_HOST, or PublicIssuer if there is none.
+func AmbientIssuer() (string, error) {
+	for _, v := range []env.Variable{env.VariableGitHubServerURL, env.VariableGitHubHost} {
+		if server := env.Getenv(v); server != "" {
+			return Issuer(server)
+		}
+	}
+	return PublicIssuer, nil
+}
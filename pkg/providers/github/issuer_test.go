@@ -0,0 +1,53 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import "testing"
+
+func TestIssuer(t *testing.T) {
+	for server, want := range map[string]string{
+		"github.com":                       PublicIssuer,
+		"https://github.com":               PublicIssuer,
+		"ghes.example.com":                 "https://ghes.example.com/_services/token",
+		"https://ghes.example.com/":        "https://ghes.example.com/_services/token",
+		"https://example.com:8443/github":  "https://example.com:8443/github/_services/token",
+		"http://ghes.internal.example.com": "http://ghes.internal.example.com/_services/token",
+	} {
+		got, err := Issuer(server)
+		if err != nil || got != want {
+			t.Errorf("Issuer(%q) = %q, %v, want %q", server, got, err, want)
+		}
+	}
+	if _, err := Issuer("https://"); err == nil {
+		t.Error("Issuer() without a host: expected an error")
+	}
+}
+
+func TestAmbientIssuer(t *testing.T) {
+	t.Setenv("GITHUB_SERVER_URL", "")
+	t.Setenv("GITHUB_HOST", "")
+	if got, err := AmbientIssuer(); err != nil || got != PublicIssuer {
+		t.Errorf("AmbientIssuer() = %q, %v, want %q", got, err, PublicIssuer)
+	}
+	t.Setenv("GITHUB_HOST", "https://ghes.example.com")
+	if got, err := AmbientIssuer(); err != nil || got != "https://ghes.example.com/_services/token" {
+		t.Errorf("AmbientIssuer() with GITHUB_HOST = %q, %v", got, err)
+	}
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	if got, err := AmbientIssuer(); err != nil || got != PublicIssuer {
+		t.Errorf("AmbientIssuer() with GITHUB_SERVER_URL = %q, %v, want %q", got, err, PublicIssuer)
+	}
+}
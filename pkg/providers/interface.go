@@ -19,12 +19,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 )
 
 var (
 	m         sync.Mutex
 	providers = make(map[string]Interface)
+	factories = make(map[string]Factory)
 )
 
 // Interface is what providers need to implement to participate in furnishing OIDC tokens.
@@ -47,6 +50,36 @@ func Register(name string, p Interface) {
 	providers[name] = p
 }
 
+// Factory returns the provider named <prefix>:<arg>, for the arg of a
+// prefix registered with RegisterFactory.
+type Factory func(arg string) (Interface, error)
+
+// RegisterFactory is used by providers that are configured by their name,
+// e.g. exec:<path>, to participate in furnishing OIDC tokens. They are only
+// used when named with ProvideFrom.
+func RegisterFactory(prefix string, f Factory) {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, ok := factories[prefix]; ok {
+		panic(fmt.Sprintf("duplicate provider factory for prefix %q", prefix))
+	}
+	factories[prefix] = f
+}
+
+// Names returns the names of the registered providers, sorted.
+func Names() []string {
+	m.Lock()
+	defer m.Unlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Enabled checks whether any of the registered providers are enabled in this execution context.
 func Enabled(ctx context.Context) bool {
 	m.Lock()
@@ -89,9 +122,13 @@ func ProvideFrom(_ context.Context, provider string) (Interface, error) {
 	m.Lock()
 	defer m.Unlock()
 
-	p, ok := providers[provider]
-	if !ok {
-		return nil, fmt.Errorf("%s is not a valid provider", provider)
+	if p, ok := providers[provider]; ok {
+		return p, nil
+	}
+	if prefix, arg, ok := strings.Cut(provider, ":"); ok {
+		if f, ok := factories[prefix]; ok {
+			return f(arg)
+		}
 	}
-	return p, nil
+	return nil, fmt.Errorf("%s is not a valid provider", provider)
 }
@@ -34,6 +34,22 @@ import (
 	"github.com/sigstore/sigstore/pkg/signature/kms"
 )
 
+// kmsSchemes are the key reference schemes of the KMS providers that the
+// cosign binary registers, unless it is built with the nokms tag.
+var kmsSchemes = []string{"awskms://", "azurekms://", "gcpkms://", "hashivault://"}
+
+// kmsProviderNotBuilt returns the error of keyRef, which no registered KMS
+// provider matched, if it is a reference to one of kmsSchemes, so that it
+// isn't read as a file or URL.
+func kmsProviderNotBuilt(keyRef string) error {
+	for _, scheme := range kmsSchemes {
+		if strings.HasPrefix(keyRef, scheme) {
+			return fmt.Errorf("the %s KMS provider isn't registered, this cosign was built without KMS providers (the nokms build tag)", scheme)
+		}
+	}
+	return nil
+}
+
 // LoadPublicKey is a wrapper for VerifierForKeyRef, hardcoding SHA256 as the hash algorithm
 func LoadPublicKey(ctx context.Context, keyRef string) (verifier signature.Verifier, err error) {
 	return VerifierForKeyRef(ctx, keyRef, crypto.SHA256)
@@ -52,6 +68,9 @@ func VerifierForKeyRef(ctx context.Context, keyRef string, hashAlgorithm crypto.
 	case errors.As(err, &perr):
 		// We can ignore ProviderNotFoundError; that just means the keyRef
 		// didn't match any of the KMS schemes.
+		if err := kmsProviderNotBuilt(keyRef); err != nil {
+			return nil, err
+		}
 	default:
 		// But other errors indicate something more insidious; pass those
 		// through.
@@ -164,6 +183,9 @@ func SignerVerifierFromKeyRef(ctx context.Context, keyRef string, pf cosign.Pass
 			return nil, fmt.Errorf("kms get: %w", err)
 		}
 		// ProviderNotFoundError is okay; loadKey handles other URL schemes
+		if err := kmsProviderNotBuilt(keyRef); err != nil {
+			return nil, err
+		}
 	}
 
 	return loadKey(keyRef, pf)
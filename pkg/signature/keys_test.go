@@ -22,6 +22,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/sigstore/cosign/v2/pkg/blob"
@@ -167,6 +168,18 @@ func TestVerifierForKeyRefError(t *testing.T) {
 	}
 }
 
+func TestKMSProviderNotBuilt(t *testing.T) {
+	ctx := context.Background()
+	// The KMS providers aren't registered in this package's tests, as in a
+	// cosign built with the nokms tag.
+	if _, err := PublicKeyFromKeyRef(ctx, "awskms:///arn:aws:kms:us-east-1:1234567890:key/abc"); err == nil || !strings.Contains(err.Error(), "nokms") {
+		t.Errorf("PublicKeyFromKeyRef() = %v, want an error naming the nokms build tag", err)
+	}
+	if _, err := SignerVerifierFromKeyRef(ctx, "gcpkms://projects/p/locations/l/keyRings/r/cryptoKeys/k", pass("")); err == nil || !strings.Contains(err.Error(), "nokms") {
+		t.Errorf("SignerVerifierFromKeyRef() = %v, want an error naming the nokms build tag", err)
+	}
+}
+
 func pass(s string) cosign.PassFunc {
 	return func(_ bool) ([]byte, error) {
 		return []byte(s), nil
@@ -0,0 +1,187 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kmsplugin is a KMS provider for keys held by out-of-tree KMS
+// integrations. A key reference kms-plugin://<name>/<key id> runs the
+// cosign-kms-<name> executable from $PATH, like a credential helper, and
+// speaks the versioned JSON-over-stdio protocol of specs/KMS_PLUGIN_SPEC.md
+// with it. The request and response types are exported for plugins written
+// in Go.
+package kmsplugin
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+)
+
+// ReferenceScheme is the scheme of the key references of plugins.
+const ReferenceScheme = "kms-plugin://"
+
+// ExecutablePrefix is the prefix of the names of plugin executables.
+const ExecutablePrefix = "cosign-kms-"
+
+// ProtocolVersion is the version of the protocol, sent in every request and
+// required in every response.
+const ProtocolVersion = "v1"
+
+// The methods of the protocol, passed to the plugin as its only argument.
+const (
+	// MethodPublicKey returns the PEM public key of the key.
+	MethodPublicKey = "public-key"
+	// MethodSign signs a digest.
+	MethodSign = "sign"
+	// MethodVerify verifies the signature of a digest.
+	MethodVerify = "verify"
+	// MethodCreateKey creates the key, or returns the public key of the key
+	// if it exists.
+	MethodCreateKey = "create-key"
+)
+
+// Request is the JSON request written to the standard input of a plugin.
+type Request struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	Method          string `json:"method"`
+	// KeyID is the key reference without the scheme and plugin name.
+	KeyID string `json:"keyId"`
+	// HashAlgorithm is the hash of Digest, one of sha224, sha256, sha384 and
+	// sha512, for sign and verify.
+	HashAlgorithm string `json:"hashAlgorithm,omitempty"`
+	// Digest is the digest to sign or verify.
+	Digest []byte `json:"digest,omitempty"`
+	// Signature is the signature to verify.
+	Signature []byte `json:"signature,omitempty"`
+	// Algorithm is the algorithm of the key to create, or empty for the
+	// default of the plugin.
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// Response is the JSON response a plugin writes to its standard output.
+type Response struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	// Error fails the request; plugins may also exit with a non-zero status.
+	Error string `json:"error,omitempty"`
+	// PublicKey is the PEM public key, for public-key and create-key.
+	PublicKey string `json:"publicKey,omitempty"`
+	// Signature is the signature, for sign.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+var nameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func init() {
+	kms.AddProvider(ReferenceScheme, func(ctx context.Context, keyResourceID string, hashFunc crypto.Hash, _ ...signature.RPCOption) (kms.SignerVerifier, error) {
+		return LoadSignerVerifier(ctx, keyResourceID, hashFunc)
+	})
+}
+
+// ParseReference returns the plugin name and key ID of a
+// kms-plugin://<name>/<key id> reference.
+func ParseReference(ref string) (name, keyID string, err error) {
+	if !strings.HasPrefix(ref, ReferenceScheme) {
+		return "", "", fmt.Errorf("kms plugin reference %q doesn't start with %s", ref, ReferenceScheme)
+	}
+	name, keyID, _ = strings.Cut(strings.TrimPrefix(ref, ReferenceScheme), "/")
+	if !nameRegexp.MatchString(name) {
+		return "", "", fmt.Errorf("invalid kms plugin name %q in %q, expected lowercase letters, digits, - and _", name, ref)
+	}
+	if keyID == "" {
+		return "", "", fmt.Errorf("kms plugin reference %q has no key ID, expected %s<name>/<key id>", ref, ReferenceScheme)
+	}
+	return name, keyID, nil
+}
+
+// plugin runs the executable of a plugin.
+type plugin struct {
+	name  string
+	path  string
+	keyID string
+}
+
+func newPlugin(ref string) (*plugin, error) {
+	name, keyID, err := ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	path, err := exec.LookPath(ExecutablePrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("finding the %s kms plugin: %w", name, err)
+	}
+	return &plugin{name: name, path: path, keyID: keyID}, nil
+}
+
+// call sends req to the plugin and returns its response.
+func (p *plugin) call(ctx context.Context, req Request) (*Response, error) {
+	req.ProtocolVersion = ProtocolVersion
+	req.KeyID = p.keyID
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	// #nosec G204 -- the executable is named by the key reference, as a
+	// credential helper is by the docker config.
+	cmd := exec.CommandContext(ctx, p.path, req.Method)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+
+	var resp Response
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("%s kms plugin %s: %w", p.name, req.Method, runErr)
+		}
+		return nil, fmt.Errorf("%s kms plugin %s: reading response: %w", p.name, req.Method, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%s kms plugin %s: %s", p.name, req.Method, resp.Error)
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("%s kms plugin %s: %w", p.name, req.Method, runErr)
+	}
+	if resp.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("%s kms plugin %s: unsupported protocol version %q, want %s", p.name, req.Method, resp.ProtocolVersion, ProtocolVersion)
+	}
+	return &resp, nil
+}
+
+var hashNames = map[crypto.Hash]string{
+	crypto.SHA224: "sha224",
+	crypto.SHA256: "sha256",
+	crypto.SHA384: "sha384",
+	crypto.SHA512: "sha512",
+}
+
+var supportedHashFuncs = []crypto.Hash{crypto.SHA224, crypto.SHA256, crypto.SHA384, crypto.SHA512}
+
+func hashName(h crypto.Hash) (string, error) {
+	name, ok := hashNames[h]
+	if !ok {
+		return "", errors.New("unsupported hash function for a kms plugin")
+	}
+	return name, nil
+}
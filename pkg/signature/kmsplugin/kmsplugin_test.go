@@ -0,0 +1,209 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmsplugin
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+)
+
+// testKeyEnv names the PEM private key file of the plugin that the test
+// binary runs as when it is set.
+const testKeyEnv = "COSIGN_KMS_PLUGIN_TEST_KEY"
+
+func TestMain(m *testing.M) {
+	if keyFile := os.Getenv(testKeyEnv); keyFile != "" {
+		os.Exit(runTestPlugin(keyFile))
+	}
+	os.Exit(m.Run())
+}
+
+// runTestPlugin serves one request for the key in keyFile. The key ID
+// "v2" answers with another protocol version, and "crash" exits without a
+// response.
+func runTestPlugin(keyFile string) int {
+	var req Request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		return 2
+	}
+	if req.KeyID == "crash" {
+		return 3
+	}
+	resp := Response{ProtocolVersion: ProtocolVersion}
+	if req.KeyID == "v2" {
+		resp.ProtocolVersion = "v2"
+	}
+	if err := servePlugin(keyFile, os.Args[1], req, &resp); err != nil {
+		resp.Error = err.Error()
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		return 2
+	}
+	return 0
+}
+
+func servePlugin(keyFile, method string, req Request, resp *Response) error {
+	if method != req.Method || req.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("bad request %s %+v", method, req)
+	}
+	raw, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	priv, err := cryptoutils.UnmarshalPEMToPrivateKey(raw, cryptoutils.SkipPassword)
+	if err != nil {
+		return err
+	}
+	key := priv.(*ecdsa.PrivateKey)
+	switch method {
+	case MethodPublicKey, MethodCreateKey:
+		pem, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+		resp.PublicKey = string(pem)
+		return err
+	case MethodSign:
+		if req.HashAlgorithm != "sha256" {
+			return fmt.Errorf("unexpected hash %s", req.HashAlgorithm)
+		}
+		resp.Signature, err = ecdsa.SignASN1(rand.Reader, key, req.Digest)
+		return err
+	case MethodVerify:
+		if !ecdsa.VerifyASN1(&key.PublicKey, req.Digest, req.Signature) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown method %s", method)
+}
+
+// installTestPlugin puts the test binary on $PATH as the cosign-kms-test
+// plugin.
+func installTestPlugin(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem, err := cryptoutils.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(keyFile, pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	bin, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := fmt.Sprintf("#!/bin/sh\nexec %q \"$@\"\n", bin)
+	if err := os.WriteFile(filepath.Join(dir, ExecutablePrefix+"test"), []byte(script), 0o700); err != nil { // #nosec G306
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(testKeyEnv, keyFile)
+	return key
+}
+
+func TestSignerVerifier(t *testing.T) {
+	key := installTestPlugin(t)
+	ctx := context.Background()
+
+	sv, err := kms.Get(ctx, "kms-plugin://test/projects/p/keys/k", crypto.SHA256)
+	if err != nil {
+		t.Fatalf("kms.Get() = %v", err)
+	}
+	pub, err := sv.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey() = %v", err)
+	}
+	if !key.PublicKey.Equal(pub) {
+		t.Errorf("PublicKey() = %v, want the key of the plugin", pub)
+	}
+	if pub, err := sv.CreateKey(ctx, sv.DefaultAlgorithm()); err != nil || !key.PublicKey.Equal(pub) {
+		t.Errorf("CreateKey() = %v, %v, want the key of the plugin", pub, err)
+	}
+
+	message := []byte("payload")
+	sig, err := sv.SignMessage(bytes.NewReader(message))
+	if err != nil {
+		t.Fatalf("SignMessage() = %v", err)
+	}
+	digest := sha256.Sum256(message)
+	if !ecdsa.VerifyASN1(&key.PublicKey, digest[:], sig) {
+		t.Error("SignMessage() returned an invalid signature")
+	}
+	if err := sv.VerifySignature(bytes.NewReader(sig), bytes.NewReader(message)); err != nil {
+		t.Errorf("VerifySignature() = %v", err)
+	}
+	if err := sv.VerifySignature(bytes.NewReader(sig), strings.NewReader("other")); err == nil || !strings.Contains(err.Error(), "invalid signature") {
+		t.Errorf("VerifySignature() of another message = %v, want the plugin's error", err)
+	}
+
+	signer, opts, err := sv.CryptoSigner(ctx, func(err error) { t.Error(err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err = signer.Sign(rand.Reader, digest[:], opts)
+	if err != nil || !ecdsa.VerifyASN1(&key.PublicKey, digest[:], sig) {
+		t.Errorf("CryptoSigner().Sign() = %v, want a valid signature", err)
+	}
+}
+
+func TestSignerVerifierErrors(t *testing.T) {
+	installTestPlugin(t)
+	ctx := context.Background()
+
+	for ref, want := range map[string]string{
+		"kms-plugin://missing/key": "finding the missing kms plugin",
+		"kms-plugin://Test/key":    "invalid kms plugin name",
+		"kms-plugin://test":        "has no key ID",
+	} {
+		if _, err := LoadSignerVerifier(ctx, ref, crypto.SHA256); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadSignerVerifier(%s) = %v, want %q", ref, err, want)
+		}
+	}
+	for keyID, want := range map[string]string{
+		"v2":    `unsupported protocol version "v2"`,
+		"crash": "exit status 3",
+	} {
+		sv, err := LoadSignerVerifier(ctx, "kms-plugin://test/"+keyID, crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sv.PublicKey(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("PublicKey() of %s = %v, want %q", keyID, err, want)
+		}
+	}
+}
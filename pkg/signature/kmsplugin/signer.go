@@ -0,0 +1,172 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmsplugin
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"io"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+// SignerVerifier signs and verifies with the key of a plugin.
+type SignerVerifier struct {
+	plugin   *plugin
+	hashFunc crypto.Hash
+}
+
+var _ kms.SignerVerifier = (*SignerVerifier)(nil)
+
+// LoadSignerVerifier returns a SignerVerifier for keyResourceID, a
+// kms-plugin://<name>/<key id> reference, that hashes messages with
+// hashFunc. The plugin is found on $PATH but not run until it is used.
+func LoadSignerVerifier(_ context.Context, keyResourceID string, hashFunc crypto.Hash) (*SignerVerifier, error) {
+	if _, err := hashName(hashFunc); err != nil {
+		return nil, err
+	}
+	p, err := newPlugin(keyResourceID)
+	if err != nil {
+		return nil, err
+	}
+	return &SignerVerifier{plugin: p, hashFunc: hashFunc}, nil
+}
+
+// SignMessage signs message, or the digest of the WithDigest option, with
+// the key of the plugin.
+//
+// SignMessage recognizes the WithContext, WithDigest and
+// WithCryptoSignerOpts options; all other options are ignored.
+func (s *SignerVerifier) SignMessage(message io.Reader, opts ...signature.SignOption) ([]byte, error) {
+	ctx := context.Background()
+	var signerOpts crypto.SignerOpts = s.hashFunc
+	for _, opt := range opts {
+		opt.ApplyContext(&ctx)
+		opt.ApplyCryptoSignerOpts(&signerOpts)
+	}
+	digest, hf, err := signature.ComputeDigestForSigning(message, signerOpts.HashFunc(), supportedHashFuncs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	hashAlgorithm, err := hashName(hf)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.plugin.call(ctx, Request{Method: MethodSign, HashAlgorithm: hashAlgorithm, Digest: digest})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Signature) == 0 {
+		return nil, errors.New("the kms plugin returned no signature")
+	}
+	return resp.Signature, nil
+}
+
+// PublicKey returns the public key of the plugin's key.
+//
+// PublicKey recognizes the WithContext option; all other options are
+// ignored.
+func (s *SignerVerifier) PublicKey(opts ...signature.PublicKeyOption) (crypto.PublicKey, error) {
+	ctx := context.Background()
+	for _, opt := range opts {
+		opt.ApplyContext(&ctx)
+	}
+	resp, err := s.plugin.call(ctx, Request{Method: MethodPublicKey})
+	if err != nil {
+		return nil, err
+	}
+	return cryptoutils.UnmarshalPEMToPublicKey([]byte(resp.PublicKey))
+}
+
+// VerifySignature verifies sig over message, or the digest of the
+// WithDigest option, with the plugin.
+//
+// VerifySignature recognizes the WithContext, WithDigest and
+// WithCryptoSignerOpts options; all other options are ignored.
+func (s *SignerVerifier) VerifySignature(sig, message io.Reader, opts ...signature.VerifyOption) error {
+	ctx := context.Background()
+	var signerOpts crypto.SignerOpts = s.hashFunc
+	for _, opt := range opts {
+		opt.ApplyContext(&ctx)
+		opt.ApplyCryptoSignerOpts(&signerOpts)
+	}
+	digest, hf, err := signature.ComputeDigestForVerifying(message, signerOpts.HashFunc(), supportedHashFuncs, opts...)
+	if err != nil {
+		return err
+	}
+	hashAlgorithm, err := hashName(hf)
+	if err != nil {
+		return err
+	}
+	sigBytes, err := io.ReadAll(sig)
+	if err != nil {
+		return err
+	}
+	_, err = s.plugin.call(ctx, Request{Method: MethodVerify, HashAlgorithm: hashAlgorithm, Digest: digest, Signature: sigBytes})
+	return err
+}
+
+// CreateKey creates the key with the plugin, or returns its public key if it
+// exists. An empty algorithm selects the default of the plugin.
+func (s *SignerVerifier) CreateKey(ctx context.Context, algorithm string) (crypto.PublicKey, error) {
+	resp, err := s.plugin.call(ctx, Request{Method: MethodCreateKey, Algorithm: algorithm})
+	if err != nil {
+		return nil, err
+	}
+	return cryptoutils.UnmarshalPEMToPublicKey([]byte(resp.PublicKey))
+}
+
+// CryptoSigner returns a crypto.Signer that signs with the plugin.
+func (s *SignerVerifier) CryptoSigner(ctx context.Context, errFunc func(error)) (crypto.Signer, crypto.SignerOpts, error) {
+	return &cryptoSigner{ctx: ctx, sv: s, errFunc: errFunc}, s.hashFunc, nil
+}
+
+// SupportedAlgorithms returns no algorithms, as they are up to the plugin.
+func (s *SignerVerifier) SupportedAlgorithms() []string {
+	return nil
+}
+
+// DefaultAlgorithm returns the empty algorithm, which selects the default of
+// the plugin.
+func (s *SignerVerifier) DefaultAlgorithm() string {
+	return ""
+}
+
+type cryptoSigner struct {
+	ctx     context.Context
+	sv      *SignerVerifier
+	errFunc func(error)
+}
+
+func (c *cryptoSigner) Public() crypto.PublicKey {
+	pk, err := c.sv.PublicKey(options.WithContext(c.ctx))
+	if err != nil && c.errFunc != nil {
+		c.errFunc(err)
+	}
+	return pk
+}
+
+func (c *cryptoSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var hashFunc crypto.SignerOpts = c.sv.hashFunc
+	if opts != nil {
+		hashFunc = opts.HashFunc()
+	}
+	return c.sv.SignMessage(nil, options.WithContext(c.ctx), options.WithDigest(digest), options.WithCryptoSignerOpts(hashFunc))
+}
@@ -0,0 +1,204 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/cyberphone/json-canonicalization/go/src/webpki.org/jsoncanonicalizer"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/rekor/pkg/generated/models"
+)
+
+var (
+	// ErrNoBundle is returned by Verify when the signature has no Rekor
+	// bundle to verify.
+	ErrNoBundle = errors.New("signature has no Rekor bundle")
+	// ErrInvalidSET is returned when the signed entry timestamp of a bundle
+	// doesn't verify.
+	ErrInvalidSET = errors.New("unable to verify SET")
+	// ErrBundleMismatch is returned when a bundle records another signature.
+	ErrBundleMismatch = errors.New("signature in bundle does not match signature being verified")
+	// ErrTlogKeyNotFound is returned when the log of a bundle isn't trusted.
+	ErrTlogKeyNotFound = errors.New("rekor log public key not found for payload")
+)
+
+// EntryContents is what a Rekor entry records of a signature, as a bundle
+// holds it.
+type EntryContents struct {
+	// Base64Signature is the base64 signature, empty for the entries of
+	// attestations.
+	Base64Signature string
+	// PublicKey is the base64 PEM public key or certificate.
+	PublicKey string
+	// HashAlgorithm and HashValue are the algorithm and hex digest of the
+	// payload, which must be sha256.
+	HashAlgorithm string
+	HashValue     string
+}
+
+// ParseEntryBody returns the contents of body, the decoded body of a
+// hashedrekord, rekord or intoto entry.
+func ParseEntryBody(body []byte) (*EntryContents, error) {
+	var entry struct {
+		Kind string          `json:"kind"`
+		Spec json.RawMessage `json:"spec"`
+	}
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, fmt.Errorf("parsing entry: %w", err)
+	}
+	switch entry.Kind {
+	case (&models.Hashedrekord{}).Kind():
+		spec := models.HashedrekordV001Schema{}
+		if err := json.Unmarshal(entry.Spec, &spec); err != nil {
+			return nil, err
+		}
+		if spec.Signature == nil || spec.Signature.PublicKey == nil || spec.Data == nil || spec.Data.Hash == nil ||
+			spec.Data.Hash.Algorithm == nil || spec.Data.Hash.Value == nil {
+			return nil, fmt.Errorf("incomplete %s entry", entry.Kind)
+		}
+		return &EntryContents{
+			Base64Signature: spec.Signature.Content.String(),
+			PublicKey:       spec.Signature.PublicKey.Content.String(),
+			HashAlgorithm:   *spec.Data.Hash.Algorithm,
+			HashValue:       *spec.Data.Hash.Value,
+		}, nil
+	case (&models.Rekord{}).Kind():
+		spec := models.RekordV001Schema{}
+		if err := json.Unmarshal(entry.Spec, &spec); err != nil {
+			return nil, err
+		}
+		if spec.Signature == nil || spec.Signature.Content == nil || spec.Signature.PublicKey == nil || spec.Signature.PublicKey.Content == nil ||
+			spec.Data == nil || spec.Data.Hash == nil || spec.Data.Hash.Algorithm == nil || spec.Data.Hash.Value == nil {
+			return nil, fmt.Errorf("incomplete %s entry", entry.Kind)
+		}
+		return &EntryContents{
+			Base64Signature: spec.Signature.Content.String(),
+			PublicKey:       spec.Signature.PublicKey.Content.String(),
+			HashAlgorithm:   *spec.Data.Hash.Algorithm,
+			HashValue:       *spec.Data.Hash.Value,
+		}, nil
+	case (&models.Intoto{}).Kind():
+		spec := models.IntotoV001Schema{}
+		if err := json.Unmarshal(entry.Spec, &spec); err != nil {
+			return nil, err
+		}
+		if spec.PublicKey == nil || spec.Content == nil || spec.Content.Hash == nil ||
+			spec.Content.Hash.Algorithm == nil || spec.Content.Hash.Value == nil {
+			return nil, fmt.Errorf("incomplete %s entry", entry.Kind)
+		}
+		return &EntryContents{
+			PublicKey:     spec.PublicKey.String(),
+			HashAlgorithm: *spec.Content.Hash.Algorithm,
+			HashValue:     *spec.Content.Hash.Value,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported entry kind %q", entry.Kind)
+	}
+}
+
+// VerifyBundle verifies that b is a Rekor entry, signed by one of
+// rekorPubKeys, of the base64Signature of payload, or of the DSSE envelope
+// payload if base64Signature is empty, made by pubKeyPEM, the PEM public key
+// or certificate of the signature.
+func VerifyBundle(b *bundle.RekorBundle, base64Signature string, payload, pubKeyPEM []byte, rekorPubKeys map[string]*ecdsa.PublicKey) error {
+	body, ok := b.Payload.Body.(string)
+	if !ok {
+		return fmt.Errorf("bundle body is a %T, not a string", b.Payload.Body)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return fmt.Errorf("decoding bundle body: %w", err)
+	}
+	contents, err := ParseEntryBody(decoded)
+	if err != nil {
+		return err
+	}
+
+	if base64Signature != "" && contents.Base64Signature != "" && contents.Base64Signature != base64Signature {
+		return ErrBundleMismatch
+	}
+	entryKey, err := base64.StdEncoding.DecodeString(contents.PublicKey)
+	if err != nil {
+		return fmt.Errorf("decoding base64 string %s", contents.PublicKey)
+	}
+	if !samePEM(pubKeyPEM, entryKey) {
+		return fmt.Errorf("comparing public key PEMs, expected %s, got %s", pubKeyPEM, entryKey)
+	}
+
+	pub, ok := rekorPubKeys[b.Payload.LogID]
+	if !ok {
+		return ErrTlogKeyNotFound
+	}
+	if err := VerifySET(b.Payload, b.SignedEntryTimestamp, pub); err != nil {
+		return err
+	}
+
+	h := sha256.Sum256(payload)
+	if contents.HashAlgorithm != "sha256" || contents.HashValue != hex.EncodeToString(h[:]) {
+		return errors.New("matching bundle to payload: the entry records another payload")
+	}
+	return nil
+}
+
+// samePEM reports whether a and b are the same PEM block, ignoring spurious
+// newlines.
+func samePEM(a, b []byte) bool {
+	first, _ := pem.Decode(a)
+	second, _ := pem.Decode(b)
+	return first != nil && second != nil && bytes.Equal(first.Bytes, second.Bytes)
+}
+
+// VerifySET verifies signature, the signed entry timestamp of the Rekor
+// entry bundlePayload, with pub.
+func VerifySET(bundlePayload bundle.RekorPayload, signature []byte, pub *ecdsa.PublicKey) error {
+	contents, err := json.Marshal(bundlePayload)
+	if err != nil {
+		return fmt.Errorf("marshaling: %w", err)
+	}
+	canonicalized, err := jsoncanonicalizer.Transform(contents)
+	if err != nil {
+		return fmt.Errorf("canonicalizing: %w", err)
+	}
+
+	// verify the SET against the public key
+	hash := sha256.Sum256(canonicalized)
+	if !ecdsa.VerifyASN1(pub, hash[:], signature) {
+		return ErrInvalidSET
+	}
+	return nil
+}
+
+// LogID returns the ID of the transparency log with the public key pub, the
+// SHA256 hash of its DER encoding (see RFC 6962 S3.2).
+func LogID(pub crypto.PublicKey) (string, error) {
+	pubBytes, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(pubBytes)
+	return hex.EncodeToString(digest[:]), nil
+}
@@ -0,0 +1,175 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// oidcIssuerOID is the Fulcio extension of the OIDC issuer of a certificate,
+// documented in https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md
+var oidcIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+
+// Identity specifies an issuer/subject to verify a signature against.
+// Both IssuerRegExp/SubjectRegExp support regexp while Issuer/Subject are for
+// strict matching.
+type Identity struct {
+	Issuer        string
+	Subject       string
+	IssuerRegExp  string
+	SubjectRegExp string
+}
+
+// IdentityMismatchError is returned by CheckIdentities when the certificate
+// matches none of the identities.
+type IdentityMismatchError struct {
+	Expected []Identity
+	Subjects []string
+	Issuer   string
+}
+
+func (e *IdentityMismatchError) Error() string {
+	return fmt.Sprintf("none of the expected identities matched what was in the certificate, got subjects [%s] with issuer %s",
+		strings.Join(e.Subjects, ", "), e.Issuer)
+}
+
+// CheckIdentities checks that the subject and issuer of cert match one of
+// identities, if there are any.
+func CheckIdentities(cert *x509.Certificate, identities []Identity) error {
+	_, err := MatchIdentity(cert, identities)
+	return err
+}
+
+// MatchIdentity returns the index of the first of identities that the
+// subject and issuer of cert match, or -1 if there are no identities.
+func MatchIdentity(cert *x509.Certificate, identities []Identity) (int, error) {
+	if len(identities) == 0 {
+		return -1, nil
+	}
+	oidcIssuer := OIDCIssuer(cert)
+	sans := SubjectAlternativeNames(cert)
+	for i, identity := range identities {
+		issuerMatches := false
+		switch {
+		// Check the issuer first
+		case identity.IssuerRegExp != "":
+			if regex, err := regexp.Compile(identity.IssuerRegExp); err != nil {
+				return -1, fmt.Errorf("malformed issuer in identity: %s : %w", identity.IssuerRegExp, err)
+			} else if regex.MatchString(oidcIssuer) {
+				issuerMatches = true
+			}
+		case identity.Issuer != "":
+			if identity.Issuer == oidcIssuer {
+				issuerMatches = true
+			}
+		default:
+			// No issuer constraint on this identity, so checks out
+			issuerMatches = true
+		}
+
+		// Then the subject
+		subjectMatches := false
+		switch {
+		case identity.SubjectRegExp != "":
+			regex, err := regexp.Compile(identity.SubjectRegExp)
+			if err != nil {
+				return -1, fmt.Errorf("malformed subject in identity: %s : %w", identity.SubjectRegExp, err)
+			}
+			for _, san := range sans {
+				if regex.MatchString(san) {
+					subjectMatches = true
+					break
+				}
+			}
+		case identity.Subject != "":
+			for _, san := range sans {
+				if san == identity.Subject {
+					subjectMatches = true
+					break
+				}
+			}
+		default:
+			// No subject constraint on this identity, so checks out
+			subjectMatches = true
+		}
+		if subjectMatches && issuerMatches {
+			// If both issuer / subject match, return verified
+			return i, nil
+		}
+	}
+	return -1, &IdentityMismatchError{Expected: identities, Subjects: sans, Issuer: oidcIssuer}
+}
+
+// SubjectAlternativeNames returns all of the following for a Certificate.
+// DNSNames
+// EmailAddresses
+// IPAddresses
+// URIs
+// OtherName
+func SubjectAlternativeNames(cert *x509.Certificate) []string {
+	sans := []string{}
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	// ignore error if there's no OtherName SAN
+	otherName, _ := cryptoutils.UnmarshalOtherNameSAN(cert.Extensions)
+	if len(otherName) > 0 {
+		sans = append(sans, otherName)
+	}
+	return sans
+}
+
+// OIDCIssuer returns the OIDC issuer of a Fulcio certificate, or "" if it
+// has none.
+func OIDCIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidcIssuerOID) {
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+// TrustedCert verifies that cert chains up to roots for code signing, and
+// returns its chains.
+func TrustedCert(cert *x509.Certificate, roots *x509.CertPool, intermediates *x509.CertPool) ([][]*x509.Certificate, error) {
+	chains, err := cert.Verify(x509.VerifyOptions{
+		// THIS IS IMPORTANT: WE DO NOT CHECK TIMES HERE
+		// THE CERTIFICATE IS TREATED AS TRUSTED FOREVER
+		// WE CHECK THAT THE SIGNATURES WERE CREATED DURING THIS WINDOW
+		CurrentTime:   cert.NotBefore,
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageCodeSigning,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cert verification failed: %w", err)
+	}
+	return chains, nil
+}
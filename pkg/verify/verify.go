@@ -0,0 +1,218 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package verify is the core of cosign's signature verification, checking
+// signatures, attestations, certificates and Rekor bundles that the caller
+// has already fetched. It has no registry, transparency log or TUF clients
+// and no KMS providers, so it also builds for GOOS=js and GOOS=wasip1 with
+// GOARCH=wasm, to verify in browsers, registries and other WASM sandboxes.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	ssldsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+// Signature is a signature or an attestation to verify, as stored by cosign.
+type Signature struct {
+	// Payload is the signed payload, or the DSSE envelope of an attestation.
+	Payload []byte
+	// Base64Signature is the base64 signature of Payload, empty for
+	// attestations.
+	Base64Signature string
+	// Certificate is the PEM signing certificate, if not signed with a key.
+	Certificate []byte
+	// Chain is the PEM chain of Certificate, up to and including the root.
+	Chain []byte
+	// Bundle is the Rekor bundle of the signature.
+	Bundle *bundle.RekorBundle
+}
+
+// Options are the trusted keys and certificates to verify a Signature with.
+type Options struct {
+	// PublicKey is the key of the signature. Without it, the signature must
+	// have a certificate issued by Roots.
+	PublicKey crypto.PublicKey
+	// Roots and Intermediates are the trusted certificate authorities, such
+	// as those of Fulcio.
+	Roots         *x509.CertPool
+	Intermediates *x509.CertPool
+	// Identities are the identities that the certificate must match one of.
+	Identities []Identity
+
+	// RekorPubKeys are the trusted Rekor public keys, by log ID.
+	RekorPubKeys map[string]*ecdsa.PublicKey
+	// IgnoreTlog skips verifying the Rekor bundle, which the signature need
+	// not have.
+	IgnoreTlog bool
+}
+
+// Result is what Verify verified of a Signature.
+type Result struct {
+	// Payload is the signed payload, the in-toto statement of an attestation.
+	Payload []byte
+	// Subjects and Issuer are the identity of the certificate, if any.
+	Subjects []string
+	Issuer   string
+	// LogIndex and IntegratedTime are those of the Rekor entry, if verified.
+	LogIndex       int64
+	IntegratedTime time.Time
+}
+
+// Verify verifies sig with o.
+func Verify(ctx context.Context, sig Signature, o Options) (*Result, error) {
+	verifier, cert, keyPEM, err := o.verifier(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Payload: sig.Payload}
+	if sig.Base64Signature == "" {
+		env := ssldsse.Envelope{}
+		if err := json.Unmarshal(sig.Payload, &env); err != nil {
+			return nil, fmt.Errorf("parsing DSSE envelope: %w", err)
+		}
+		if env.PayloadType != types.IntotoPayloadType {
+			return nil, fmt.Errorf("invalid payloadType %s on envelope, expected %s", env.PayloadType, types.IntotoPayloadType)
+		}
+		if err := VerifyEnvelope(ctx, verifier, &env); err != nil {
+			return nil, err
+		}
+		if res.Payload, err = base64.StdEncoding.DecodeString(env.Payload); err != nil {
+			return nil, fmt.Errorf("decoding DSSE payload: %w", err)
+		}
+	} else if err := VerifySignature(ctx, verifier, sig.Base64Signature, sig.Payload); err != nil {
+		return nil, err
+	}
+
+	// Without a Rekor entry, a certificate must still be valid.
+	signedAt := time.Now()
+	if !o.IgnoreTlog {
+		if sig.Bundle == nil {
+			return nil, ErrNoBundle
+		}
+		if err := VerifyBundle(sig.Bundle, sig.Base64Signature, sig.Payload, keyPEM, o.RekorPubKeys); err != nil {
+			return nil, err
+		}
+		res.LogIndex = sig.Bundle.Payload.LogIndex
+		res.IntegratedTime = time.Unix(sig.Bundle.Payload.IntegratedTime, 0).UTC()
+		signedAt = res.IntegratedTime
+	}
+	if cert != nil {
+		if signedAt.Before(cert.NotBefore) || signedAt.After(cert.NotAfter) {
+			return nil, fmt.Errorf("certificate was not valid at %s, it is valid from %s to %s",
+				signedAt.Format(time.RFC3339), cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+		}
+		res.Subjects = SubjectAlternativeNames(cert)
+		res.Issuer = OIDCIssuer(cert)
+	}
+	return res, nil
+}
+
+// verifier returns the verifier of sig, its certificate if it has one, and
+// the PEM key or certificate that its Rekor entry records.
+func (o Options) verifier(sig Signature) (signature.Verifier, *x509.Certificate, []byte, error) {
+	if o.PublicKey != nil {
+		keyPEM, err := cryptoutils.MarshalPublicKeyToPEM(o.PublicKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		verifier, err := signature.LoadVerifier(o.PublicKey, crypto.SHA256)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("loading verifier: %w", err)
+		}
+		return verifier, nil, keyPEM, nil
+	}
+
+	if len(sig.Certificate) == 0 {
+		return nil, nil, nil, errors.New("a public key or the certificate of the signature is required")
+	}
+	if o.Roots == nil {
+		return nil, nil, nil, errors.New("trusted roots are required to verify a certificate")
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(sig.Certificate)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parsing certificate: %w", err)
+	}
+	if len(certs) != 1 {
+		return nil, nil, nil, fmt.Errorf("expected a certificate, got %d", len(certs))
+	}
+	cert := certs[0]
+	intermediates := o.Intermediates
+	if len(sig.Chain) > 0 {
+		chain, err := cryptoutils.UnmarshalCertificatesFromPEM(sig.Chain)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("parsing certificate chain: %w", err)
+		}
+		// Only the intermediates of the chain are used, its root must be
+		// one of Roots.
+		if intermediates == nil {
+			intermediates = x509.NewCertPool()
+		} else {
+			intermediates = intermediates.Clone()
+		}
+		for _, c := range chain {
+			if !bytes.Equal(c.RawIssuer, c.RawSubject) {
+				intermediates.AddCert(c)
+			}
+		}
+	}
+	if _, err := TrustedCert(cert, o.Roots, intermediates); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := CheckIdentities(cert, o.Identities); err != nil {
+		return nil, nil, nil, err
+	}
+	verifier, err := signature.LoadVerifier(cert.PublicKey, crypto.SHA256)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("loading verifier: %w", err)
+	}
+	return verifier, cert, sig.Certificate, nil
+}
+
+// VerifySignature verifies base64Signature, the signature of payload.
+func VerifySignature(ctx context.Context, verifier signature.Verifier, base64Signature string, payload []byte) error {
+	raw, err := base64.StdEncoding.DecodeString(base64Signature)
+	if err != nil {
+		return err
+	}
+	return verifier.VerifySignature(bytes.NewReader(raw), bytes.NewReader(payload), options.WithContext(ctx))
+}
+
+// VerifyEnvelope verifies the signatures of the DSSE envelope env.
+func VerifyEnvelope(ctx context.Context, verifier signature.Verifier, env *ssldsse.Envelope) error {
+	dssev, err := ssldsse.NewEnvelopeVerifier(&dsse.VerifierAdapter{SignatureVerifier: verifier})
+	if err != nil {
+		return err
+	}
+	_, err = dssev.Verify(ctx, env)
+	return err
+}
@@ -0,0 +1,259 @@
+//
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cyberphone/json-canonicalization/go/src/webpki.org/jsoncanonicalizer"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+)
+
+// rekorBundle returns a bundle of a hashedrekord entry of the signature of
+// payload by pubPEM, signed by rekorKey.
+func rekorBundle(t *testing.T, rekorKey *ecdsa.PrivateKey, b64sig string, payload, pubPEM []byte) *bundle.RekorBundle {
+	t.Helper()
+	h := sha256.Sum256(payload)
+	body := fmt.Sprintf(`{"apiVersion":"0.0.1","kind":"hashedrekord","spec":{"data":{"hash":{"algorithm":"sha256","value":%q}},"signature":{"content":%q,"publicKey":{"content":%q}}}}`,
+		hex.EncodeToString(h[:]), b64sig, base64.StdEncoding.EncodeToString(pubPEM))
+	logID, err := LogID(rekorKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &bundle.RekorBundle{Payload: bundle.RekorPayload{
+		Body:           base64.StdEncoding.EncodeToString([]byte(body)),
+		IntegratedTime: time.Now().Unix(),
+		LogIndex:       7,
+		LogID:          logID,
+	}}
+	raw, err := json.Marshal(b.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonicalized, err := jsoncanonicalizer.Transform(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(canonicalized)
+	if b.SignedEntryTimestamp, err = ecdsa.SignASN1(rand.Reader, rekorKey, digest[:]); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPEM, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rekorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logID, err := LogID(rekorKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rekorPubKeys := map[string]*ecdsa.PublicKey{logID: &rekorKey.PublicKey}
+
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"example.com/app"},"image":{"docker-manifest-digest":"sha256:abcd"},"type":"cosign container image signature"},"optional":null}`)
+	raw, err := sv.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64sig := base64.StdEncoding.EncodeToString(raw)
+	sig := Signature{Payload: payload, Base64Signature: b64sig, Bundle: rekorBundle(t, rekorKey, b64sig, payload, pubPEM)}
+
+	res, err := Verify(ctx, sig, Options{PublicKey: priv.Public(), RekorPubKeys: rekorPubKeys})
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if !bytes.Equal(res.Payload, payload) || res.LogIndex != 7 || res.IntegratedTime.IsZero() {
+		t.Errorf("Verify() = %+v, want the payload and the log entry", res)
+	}
+
+	tampered := *sig.Bundle
+	tampered.Payload.LogIndex = 8
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		sig  Signature
+		o    Options
+		want error
+	}{
+		"no bundle": {
+			sig:  Signature{Payload: payload, Base64Signature: b64sig},
+			o:    Options{PublicKey: priv.Public(), RekorPubKeys: rekorPubKeys},
+			want: ErrNoBundle,
+		},
+		"untrusted log": {
+			sig:  sig,
+			o:    Options{PublicKey: priv.Public()},
+			want: ErrTlogKeyNotFound,
+		},
+		"tampered bundle": {
+			sig:  Signature{Payload: payload, Base64Signature: b64sig, Bundle: &tampered},
+			o:    Options{PublicKey: priv.Public(), RekorPubKeys: rekorPubKeys},
+			want: ErrInvalidSET,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Verify(ctx, tc.sig, tc.o); !errors.Is(err, tc.want) {
+				t.Errorf("Verify() = %v, want %v", err, tc.want)
+			}
+		})
+	}
+	if _, err := Verify(ctx, Signature{Payload: payload, Base64Signature: b64sig}, Options{PublicKey: priv.Public(), IgnoreTlog: true}); err != nil {
+		t.Errorf("Verify() ignoring the tlog = %v", err)
+	}
+	if _, err := Verify(ctx, sig, Options{PublicKey: other.Public(), IgnoreTlog: true}); err == nil {
+		t.Error("Verify() with another key succeeded")
+	}
+
+	// An attestation verifies the DSSE envelope, and returns its statement.
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://example.com/predicate","subject":[{"name":"example.com/app","digest":{"sha256":"abcd"}}],"predicate":{}}`)
+	envelope, err := dsse.WrapSigner(sv, types.IntotoPayloadType).SignMessage(bytes.NewReader(statement))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = Verify(ctx, Signature{Payload: envelope}, Options{PublicKey: priv.Public(), IgnoreTlog: true})
+	if err != nil {
+		t.Fatalf("Verify() of an attestation = %v", err)
+	}
+	if !bytes.Equal(res.Payload, statement) {
+		t.Errorf("Verify() payload = %s, want the statement", res.Payload)
+	}
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	ctx := context.Background()
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	subCert, subKey, _ := test.GenerateSubordinateCa(rootCert, rootKey)
+	leafCert, leafKey, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", subCert, subKey)
+	sv, err := signature.LoadECDSASignerVerifier(leafKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, err := cryptoutils.MarshalCertificateToPEM(leafCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainPEM, err := cryptoutils.MarshalCertificatesToPEM([]*x509.Certificate{subCert, rootCert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCert)
+
+	payload := []byte("payload")
+	raw, err := sv.SignMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := Signature{Payload: payload, Base64Signature: base64.StdEncoding.EncodeToString(raw), Certificate: certPEM, Chain: chainPEM}
+
+	res, err := Verify(ctx, sig, Options{Roots: roots, Identities: []Identity{{Issuer: "oidc-issuer", SubjectRegExp: "@mail.com$"}}, IgnoreTlog: true})
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if len(res.Subjects) != 1 || res.Subjects[0] != "subject@mail.com" || res.Issuer != "oidc-issuer" {
+		t.Errorf("Verify() identity = %v, %s, want subject@mail.com and oidc-issuer", res.Subjects, res.Issuer)
+	}
+
+	var mismatch *IdentityMismatchError
+	if _, err := Verify(ctx, sig, Options{Roots: roots, Identities: []Identity{{Subject: "other@mail.com"}}, IgnoreTlog: true}); !errors.As(err, &mismatch) {
+		t.Errorf("Verify() with another identity = %v, want an IdentityMismatchError", err)
+	}
+	otherRoot, _, _ := test.GenerateRootCa()
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherRoot)
+	if _, err := Verify(ctx, sig, Options{Roots: otherRoots, IgnoreTlog: true}); err == nil {
+		t.Error("Verify() with another root succeeded")
+	}
+	if _, err := Verify(ctx, sig, Options{IgnoreTlog: true}); err == nil {
+		t.Error("Verify() of a certificate without roots succeeded")
+	}
+}
+
+func TestParseEntryBody(t *testing.T) {
+	for _, body := range []string{
+		`{"kind":"dsse","spec":{}}`,
+		`{"kind":"hashedrekord","spec":{"signature":{}}}`,
+		`[]`,
+	} {
+		if _, err := ParseEntryBody([]byte(body)); err == nil {
+			t.Errorf("ParseEntryBody(%s) = nil, want an error", body)
+		}
+	}
+	c, err := ParseEntryBody([]byte(`{"kind":"intoto","spec":{"content":{"hash":{"algorithm":"sha256","value":"abcd"}},"publicKey":"a2V5"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Base64Signature != "" || c.PublicKey != "a2V5" || c.HashValue != "abcd" {
+		t.Errorf("ParseEntryBody() = %+v", c)
+	}
+}
+
+func TestMatchIdentity(t *testing.T) {
+	rootCert, rootKey, _ := test.GenerateRootCa()
+	leafCert, _, _ := test.GenerateLeafCert("subject@mail.com", "oidc-issuer", rootCert, rootKey)
+
+	identities := []Identity{
+		{Subject: "subject@mail.com", Issuer: "other-issuer"},
+		{SubjectRegExp: "@mail.com$", IssuerRegExp: "^oidc-"},
+		{Subject: "subject@mail.com", Issuer: "oidc-issuer"},
+	}
+	if i, err := MatchIdentity(leafCert, identities); i != 1 || err != nil {
+		t.Errorf("MatchIdentity() = %d, %v, want the first matching identity, 1", i, err)
+	}
+	if i, err := MatchIdentity(leafCert, nil); i != -1 || err != nil {
+		t.Errorf("MatchIdentity() without identities = %d, %v, want -1", i, err)
+	}
+	var mismatch *IdentityMismatchError
+	if i, err := MatchIdentity(leafCert, identities[:1]); i != -1 || !errors.As(err, &mismatch) {
+		t.Errorf("MatchIdentity() with another issuer = %d, %v, want an IdentityMismatchError", i, err)
+	}
+}
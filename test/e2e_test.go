@@ -147,18 +147,18 @@ func TestSignVerify(t *testing.T) {
 	// Verify should fail at first
 	mustErr(verify(pubKeyPath, imgName, true, nil, ""), t)
 	// So should download
-	mustErr(download.SignatureCmd(ctx, options.RegistryOptions{}, imgName), t)
+	mustErr(download.SignatureCmd(ctx, options.RegistryOptions{}, options.SignatureDownloadOptions{}, imgName), t)
 
 	// Now sign the image
 	ko := options.KeyOpts{KeyRef: privKeyPath, PassFunc: passFunc}
 	so := options.SignOptions{
 		Upload: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	// Now verify and download should work!
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
-	must(download.SignatureCmd(ctx, options.RegistryOptions{}, imgName), t)
+	must(download.SignatureCmd(ctx, options.RegistryOptions{}, options.SignatureDownloadOptions{}, imgName), t)
 
 	// Look for a specific annotation
 	mustErr(verify(pubKeyPath, imgName, true, map[string]interface{}{"foo": "bar"}, ""), t)
@@ -167,7 +167,7 @@ func TestSignVerify(t *testing.T) {
 		Annotations: []string{"foo=bar"},
 	}
 	// Sign the image with an annotation
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	// It should match this time.
 	must(verify(pubKeyPath, imgName, true, map[string]interface{}{"foo": "bar"}, ""), t)
@@ -194,11 +194,11 @@ func TestSignVerifyClean(t *testing.T) {
 	so := options.SignOptions{
 		Upload: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	// Now verify and download should work!
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
-	must(download.SignatureCmd(ctx, options.RegistryOptions{}, imgName), t)
+	must(download.SignatureCmd(ctx, options.RegistryOptions{}, options.SignatureDownloadOptions{}, imgName), t)
 
 	// Now clean signature from the given image
 	must(cli.CleanCmd(ctx, options.RegistryOptions{}, "all", imgName, true), t)
@@ -226,11 +226,11 @@ func TestImportSignVerifyClean(t *testing.T) {
 	so := options.SignOptions{
 		Upload: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	// Now verify and download should work!
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
-	must(download.SignatureCmd(ctx, options.RegistryOptions{}, imgName), t)
+	must(download.SignatureCmd(ctx, options.RegistryOptions{}, options.SignatureDownloadOptions{}, imgName), t)
 
 	// Now clean signature from the given image
 	must(cli.CleanCmd(ctx, options.RegistryOptions{}, "all", imgName, true), t)
@@ -810,6 +810,7 @@ func TestAttestationRFC3161Timestamp(t *testing.T) {
 }
 
 func TestRekorBundle(t *testing.T) {
+	ctx := context.Background()
 	// turn on the tlog
 	defer setenv(t, env.VariableExperimental.String(), "1")()
 
@@ -834,7 +835,7 @@ func TestRekorBundle(t *testing.T) {
 	}
 
 	// Sign the image
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 	// Make sure verify works
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
 
@@ -845,6 +846,7 @@ func TestRekorBundle(t *testing.T) {
 }
 
 func TestFulcioBundle(t *testing.T) {
+	ctx := context.Background()
 	repo, stop := reg(t)
 	defer stop()
 	td := t.TempDir()
@@ -868,7 +870,7 @@ func TestFulcioBundle(t *testing.T) {
 	}
 
 	// Sign the image
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 	// Make sure verify works
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
 
@@ -879,6 +881,7 @@ func TestFulcioBundle(t *testing.T) {
 }
 
 func TestRFC3161Timestamp(t *testing.T) {
+	ctx := context.Background()
 	// TSA server needed to create timestamp
 	viper.Set("timestamp-signer", "memory")
 	apiServer := server.NewRestAPIServer("localhost", 0, []string{"http"}, 10*time.Second, 10*time.Second)
@@ -927,12 +930,13 @@ func TestRFC3161Timestamp(t *testing.T) {
 	}
 
 	// Sign the image
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 	// Make sure verify works against the TSA server
 	must(verifyTSA(pubKeyPath, imgName, true, nil, "", file.Name(), true), t)
 }
 
 func TestRekorBundleAndRFC3161Timestamp(t *testing.T) {
+	ctx := context.Background()
 	// TSA server needed to create timestamp
 	viper.Set("timestamp-signer", "memory")
 	apiServer := server.NewRestAPIServer("localhost", 0, []string{"http"}, 10*time.Second, 10*time.Second)
@@ -983,7 +987,7 @@ func TestRekorBundleAndRFC3161Timestamp(t *testing.T) {
 	}
 
 	// Sign the image
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 	// Make sure verify works against the Rekor and TSA clients
 	must(verifyTSA(pubKeyPath, imgName, true, nil, "", file.Name(), false), t)
 }
@@ -1004,21 +1008,21 @@ func TestDuplicateSign(t *testing.T) {
 	// Verify should fail at first
 	mustErr(verify(pubKeyPath, imgName, true, nil, ""), t)
 	// So should download
-	mustErr(download.SignatureCmd(ctx, options.RegistryOptions{}, imgName), t)
+	mustErr(download.SignatureCmd(ctx, options.RegistryOptions{}, options.SignatureDownloadOptions{}, imgName), t)
 
 	// Now sign the image
 	ko := options.KeyOpts{KeyRef: privKeyPath, PassFunc: passFunc}
 	so := options.SignOptions{
 		Upload: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	// Now verify and download should work!
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
-	must(download.SignatureCmd(ctx, options.RegistryOptions{}, imgName), t)
+	must(download.SignatureCmd(ctx, options.RegistryOptions{}, options.SignatureDownloadOptions{}, imgName), t)
 
 	// Signing again should work just fine...
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	se, err := ociremote.SignedEntity(ref, ociremote.WithRemoteOptions(registryClientOpts(ctx)...))
 	must(err, t)
@@ -1094,6 +1098,7 @@ func TestGenerateKeyPairK8s(t *testing.T) {
 }
 
 func TestMultipleSignatures(t *testing.T) {
+	ctx := context.Background()
 	repo, stop := reg(t)
 	defer stop()
 
@@ -1117,14 +1122,14 @@ func TestMultipleSignatures(t *testing.T) {
 	so := options.SignOptions{
 		Upload: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 	// Now verify should work with that one, but not the other
 	must(verify(pub1, imgName, true, nil, ""), t)
 	mustErr(verify(pub2, imgName, true, nil, ""), t)
 
 	// Now sign with the other key too
 	ko.KeyRef = priv2
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	// Now verify should work with both
 	must(verify(pub1, imgName, true, nil, ""), t)
@@ -1175,7 +1180,7 @@ func TestSignBlob(t *testing.T) {
 		KeyRef:   privKeyPath1,
 		PassFunc: passFunc,
 	}
-	sig, err := sign.SignBlobCmd(ro, ko, bp, true, "", "", false)
+	sig, err := sign.SignBlobCmd(ctx, ro, ko, bp, true, "", "", false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1222,14 +1227,14 @@ func TestSignBlobBundle(t *testing.T) {
 		RekorURL:         rekorURL,
 		SkipConfirmation: true,
 	}
-	if _, err := sign.SignBlobCmd(ro, ko, bp, true, "", "", false); err != nil {
+	if _, err := sign.SignBlobCmd(ctx, ro, ko, bp, true, "", "", false); err != nil {
 		t.Fatal(err)
 	}
 	// Now verify should work
 	must(verifyBlobCmd.Exec(ctx, bp), t)
 
 	// Now we turn on the tlog and sign again
-	if _, err := sign.SignBlobCmd(ro, ko, bp, true, "", "", true); err != nil {
+	if _, err := sign.SignBlobCmd(ctx, ro, ko, bp, true, "", "", true); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1306,14 +1311,14 @@ func TestSignBlobRFC3161TimestampBundle(t *testing.T) {
 		RekorURL:             rekorURL,
 		SkipConfirmation:     true,
 	}
-	if _, err := sign.SignBlobCmd(ro, ko, bp, true, "", "", false); err != nil {
+	if _, err := sign.SignBlobCmd(ctx, ro, ko, bp, true, "", "", false); err != nil {
 		t.Fatal(err)
 	}
 	// Now verify should work
 	must(verifyBlobCmd.Exec(ctx, bp), t)
 
 	// Now we turn on the tlog and sign again
-	if _, err := sign.SignBlobCmd(ro, ko, bp, true, "", "", true); err != nil {
+	if _, err := sign.SignBlobCmd(ctx, ro, ko, bp, true, "", "", true); err != nil {
 		t.Fatal(err)
 	}
 	// Point to a fake rekor server to make sure offline verification of the tlog entry works
@@ -1331,7 +1336,7 @@ func TestGenerate(t *testing.T) {
 
 	// Generate the payload for the image, and check the digest.
 	b := bytes.Buffer{}
-	must(generate.GenerateCmd(context.Background(), options.RegistryOptions{}, imgName, nil, &b), t)
+	must(generate.GenerateCmd(context.Background(), options.GenerateOptions{}, imgName, nil, &b), t)
 	ss := payload.SimpleContainerImage{}
 	must(json.Unmarshal(b.Bytes(), &ss), t)
 
@@ -1340,7 +1345,7 @@ func TestGenerate(t *testing.T) {
 	// Now try with some annotations.
 	b.Reset()
 	a := map[string]interface{}{"foo": "bar"}
-	must(generate.GenerateCmd(context.Background(), options.RegistryOptions{}, imgName, a, &b), t)
+	must(generate.GenerateCmd(context.Background(), options.GenerateOptions{}, imgName, a, &b), t)
 	must(json.Unmarshal(b.Bytes(), &ss), t)
 
 	equals(desc.Digest.String(), ss.Critical.Image.DockerManifestDigest, t)
@@ -1569,7 +1574,7 @@ func TestSaveLoad(t *testing.T) {
 			so := options.SignOptions{
 				Upload: true,
 			}
-			must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+			must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 			must(verify(pubKeyPath, imgName, true, nil, ""), t)
 
 			// save the image to a temp dir
@@ -1605,7 +1610,7 @@ func TestSaveLoadAttestation(t *testing.T) {
 	so := options.SignOptions{
 		Upload: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
 
 	// now, append an attestation to the image
@@ -1709,7 +1714,7 @@ func TestAttachSBOM(t *testing.T) {
 		Upload:     true,
 		Attachment: "sbom",
 	}
-	must(sign.SignCmd(ro, ko1, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko1, so, []string{imgName}), t)
 
 	// Now verify should work with that one, but not the other
 	must(verify(pubKeyPath1, imgName, true, nil, "sbom"), t)
@@ -1817,6 +1822,7 @@ func setenv(t *testing.T, k, v string) func() {
 }
 
 func TestTlog(t *testing.T) {
+	ctx := context.Background()
 	repo, stop := reg(t)
 	defer stop()
 	td := t.TempDir()
@@ -1840,7 +1846,7 @@ func TestTlog(t *testing.T) {
 	so := options.SignOptions{
 		Upload: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	// Now verify should work!
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
@@ -1852,12 +1858,13 @@ func TestTlog(t *testing.T) {
 	// mustErr(verify(pubKeyPath, imgName, true, nil, ""), t)
 
 	// // Sign again with the tlog env var on
-	// must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	// must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 	// // And now verify works!
 	// must(verify(pubKeyPath, imgName, true, nil, ""), t)
 }
 
 func TestNoTlog(t *testing.T) {
+	ctx := context.Background()
 	repo, stop := reg(t)
 	defer stop()
 	td := t.TempDir()
@@ -1881,7 +1888,7 @@ func TestNoTlog(t *testing.T) {
 	so := options.SignOptions{
 		Upload: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 
 	// Now verify should work!
 	must(verify(pubKeyPath, imgName, true, nil, ""), t)
@@ -1893,7 +1900,7 @@ func TestNoTlog(t *testing.T) {
 	// so = options.SignOptions{
 	// 	TlogUpload: false,
 	// }
-	// must(sign.SignCmd(ro, ko, so, []string{imgName}), t)
+	// must(sign.SignCmd(ctx, ro, ko, so, []string{imgName}), t)
 	// // And verify it still fails.
 	// mustErr(verify(pubKeyPath, imgName, true, nil, ""), t)
 }
@@ -2072,7 +2079,7 @@ func TestInvalidBundle(t *testing.T) {
 		TlogUpload:       true,
 		SkipConfirmation: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{img1}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{img1}), t)
 	// verify image1
 	must(verify(pubKeyPath, img1, true, nil, ""), t)
 	// extract the bundle from image1
@@ -2101,7 +2108,7 @@ func TestInvalidBundle(t *testing.T) {
 		Upload:     true,
 		TlogUpload: false,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{img2}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{img2}), t)
 	must(verify(pubKeyPath, img2, true, nil, ""), t)
 
 	si2, err := ociremote.SignedEntity(imgRef2, remoteOpts)
@@ -2236,7 +2243,7 @@ func TestOffline(t *testing.T) {
 		TlogUpload:       true,
 		SkipConfirmation: true,
 	}
-	must(sign.SignCmd(ro, ko, so, []string{img1}), t)
+	must(sign.SignCmd(ctx, ro, ko, so, []string{img1}), t)
 	// verify image1 online and offline
 	must(verify(pubKeyPath, img1, true, nil, ""), t)
 	verifyCmd := &cliverify.VerifyCommand{
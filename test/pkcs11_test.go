@@ -45,6 +45,7 @@ import (
 	"encoding/hex"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 	"strings"
@@ -274,6 +275,78 @@ func TestSignAndVerify(t *testing.T) {
 	}
 }
 
+func TestKeySelection(t *testing.T) {
+	ctx := context.Background()
+
+	tokens, err := GetTokens(ctx, modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slotID uint
+	bTokenFound := false
+	for _, token := range tokens {
+		if token.TokenInfo.Label == tokenLabel {
+			bTokenFound = true
+			slotID = token.Slot
+			break
+		}
+	}
+	if !bTokenFound {
+		t.Fatalf("token with label '%s' not found", tokenLabel)
+	}
+
+	err = importKey(slotID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteKey(slotID)
+	defer pkcs11key.CloseSessions()
+
+	keyIDBytes, _ := hex.DecodeString(keyID)
+	for _, tc := range []struct {
+		name    string
+		label   []byte
+		id      []byte
+		wantErr string
+	}{
+		{name: "label", label: []byte(keyLabel)},
+		{name: "id", id: keyIDBytes},
+		{name: "id and label", label: []byte(keyLabel), id: keyIDBytes},
+		// The label isn't ignored when the id is set.
+		{name: "id and other label", label: []byte("Other Key"), id: keyIDBytes, wantErr: "no key with id"},
+		{name: "other id", id: []byte{0x01}, wantErr: "no key with id 01"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := pkcs11key.NewPkcs11UriConfigFromInput(modulePath, nil, tokenLabel, tc.label, tc.id, pin)
+			sk, err := pkcs11key.GetKeyWithURIConfig(config, false)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("GetKeyWithURIConfig() = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			sk.Close()
+		})
+	}
+
+	keys, err := pkcs11key.ListKeys(pkcs11key.NewPkcs11UriConfigFromInput(modulePath, nil, tokenLabel, nil, nil, pin), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bKeyFound := false
+	for _, key := range keys {
+		if hex.EncodeToString(key.ID) == keyID && string(key.Label) == keyLabel {
+			bKeyFound = true
+		}
+	}
+	if !bKeyFound {
+		t.Fatalf("ListKeys() = %v, want the key", keys)
+	}
+}
+
 var newPublicKeyAttrs = []*pkcs11.Attribute{
 	pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
 	pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
@@ -350,7 +423,7 @@ func importKey(slotID uint) error {
 	keyLabelBytes := []byte(keyLabel)
 
 	r := strings.NewReader(rsaPrivKey)
-	pemBytes, err = os.ReadAll(r)
+	pemBytes, err = io.ReadAll(r)
 	if err != nil {
 		return fmt.Errorf("unable to read pem")
 	}